@@ -364,6 +364,7 @@ func (app *BaseApp) CheckTx(req *abci.CheckTxRequest) (*abci.CheckTxResponse, er
 	}
 
 	gInfo, result, anteEvents, err := app.runTx(mode, req.Tx)
+	app.streamSimulation(mode, req.Tx, gInfo, result, err)
 	if err != nil {
 		return responseCheckTxWithEvents(err, gInfo.GasWanted, gInfo.GasUsed, anteEvents, app.trace), nil
 	}
@@ -871,7 +872,8 @@ func (app *BaseApp) internalFinalizeBlock(ctx context.Context, req *abci.Finaliz
 // where they adhere to the sdk.Tx interface.
 func (app *BaseApp) FinalizeBlock(req *abci.FinalizeBlockRequest) (res *abci.FinalizeBlockResponse, err error) {
 	defer func() {
-		// call the streaming service hooks with the FinalizeBlock messages
+		// call the streaming service hooks with the FinalizeBlock messages, this is only done for the block
+		// which is finalized and not for blocks which are executed optimistically
 		for _, streamingListener := range app.streamingManager.ABCIListeners {
 			if err := streamingListener.ListenFinalizeBlock(app.finalizeBlockState.Context(), *req, *res); err != nil {
 				app.logger.Error("ListenFinalizeBlock listening hook failed", "height", req.Height, "err", err)
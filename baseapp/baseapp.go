@@ -21,6 +21,7 @@ import (
 	"cosmossdk.io/core/log"
 	errorsmod "cosmossdk.io/errors"
 	sdklog "cosmossdk.io/log"
+	"cosmossdk.io/schema/indexer"
 	"cosmossdk.io/store"
 	storemetrics "cosmossdk.io/store/metrics"
 	"cosmossdk.io/store/snapshots"
@@ -178,6 +179,9 @@ type BaseApp struct {
 	// streamingManager for managing instances and configuration of ABCIListener services
 	streamingManager storetypes.StreamingManager
 
+	// indexerManager manages the built-in indexer targets if they are enabled with EnableIndexer
+	indexerManager *indexer.Manager
+
 	chainID string
 
 	cdc codec.Codec
@@ -1151,6 +1155,14 @@ func (app *BaseApp) Close() error {
 		}
 	}
 
+	// Stop the indexer targets so that they can flush their data
+	if app.indexerManager != nil {
+		app.logger.Info("Stopping indexer targets")
+		if err := app.indexerManager.Stop(context.Background()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	// Close app.snapshotManager
 	// - opened when app chains use cosmos-sdk/server/util.go/DefaultBaseappOptions (boilerplate)
 	// - which calls cosmos-sdk/server/util.go/GetSnapshotStore
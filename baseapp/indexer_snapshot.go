@@ -0,0 +1,94 @@
+package baseapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"cosmossdk.io/schema/decoding"
+	"cosmossdk.io/schema/indexer"
+	snapshot "cosmossdk.io/store/snapshots/types"
+)
+
+const (
+	// IndexerCheckpointSnapshotFormat defines the snapshot format of the indexer checkpoint extension,
+	// a single JSON encoded indexer.Checkpoint payload.
+	IndexerCheckpointSnapshotFormat = 1
+
+	// IndexerCheckpointSnapshotName defines the snapshot name of the indexer checkpoint extension.
+	IndexerCheckpointSnapshotName = "indexer_checkpoint"
+)
+
+var _ snapshot.ExtensionSnapshotter = &IndexerCheckpointSnapshotter{}
+
+// IndexerCheckpointSnapshotter is a snapshot extension which embeds the indexer checkpoint of the snapshot
+// height, that is the height and the schema fingerprints of the app modules, in state sync snapshots. When
+// a node is restored from a snapshot, the checkpoint is passed to the built-in indexer targets, if they are
+// enabled, so that they know which height and schema versions the restored state corresponds to.
+//
+// Since restoring a snapshot with an unknown extension fails, the snapshotter should be registered whether
+// or not the indexer is enabled.
+type IndexerCheckpointSnapshotter struct {
+	app      *BaseApp
+	resolver decoding.DecoderResolver
+}
+
+// NewIndexerCheckpointSnapshotter returns an IndexerCheckpointSnapshotter for the app and its modules.
+func NewIndexerCheckpointSnapshotter(app *BaseApp, appModules map[string]any) *IndexerCheckpointSnapshotter {
+	return &IndexerCheckpointSnapshotter{app: app, resolver: decoding.ModuleSetDecoderResolver(appModules)}
+}
+
+func (s *IndexerCheckpointSnapshotter) SnapshotName() string {
+	return IndexerCheckpointSnapshotName
+}
+
+func (s *IndexerCheckpointSnapshotter) SnapshotFormat() uint32 {
+	return IndexerCheckpointSnapshotFormat
+}
+
+func (s *IndexerCheckpointSnapshotter) SupportedFormats() []uint32 {
+	return []uint32{IndexerCheckpointSnapshotFormat}
+}
+
+func (s *IndexerCheckpointSnapshotter) SnapshotExtension(height uint64, payloadWriter snapshot.ExtensionPayloadWriter) error {
+	checkpoint, err := indexer.NewCheckpoint(int64(height), s.resolver)
+	if err != nil {
+		return err
+	}
+
+	bz, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	return payloadWriter(bz)
+}
+
+func (s *IndexerCheckpointSnapshotter) RestoreExtension(height uint64, format uint32, payloadReader snapshot.ExtensionPayloadReader) error {
+	if format != IndexerCheckpointSnapshotFormat {
+		return snapshot.ErrUnknownFormat
+	}
+
+	payload, err := payloadReader()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+
+		return err
+	}
+
+	var checkpoint indexer.Checkpoint
+	if err := json.Unmarshal(payload, &checkpoint); err != nil {
+		return fmt.Errorf("invalid indexer checkpoint payload: %w", err)
+	}
+
+	if checkpoint.Height != int64(height) {
+		return fmt.Errorf("indexer checkpoint height %d does not match snapshot height %d", checkpoint.Height, height)
+	}
+
+	if s.app.indexerManager == nil {
+		return nil
+	}
+	return s.app.indexerManager.Restore(checkpoint)
+}
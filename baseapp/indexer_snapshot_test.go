@@ -0,0 +1,77 @@
+package baseapp
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/indexer"
+	snapshot "cosmossdk.io/store/snapshots/types"
+)
+
+type indexerSnapshotTestModule struct{}
+
+func (indexerSnapshotTestModule) ModuleCodec() (schema.ModuleCodec, error) {
+	moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:      "balances",
+		KeyFields: []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+	}})
+	return schema.ModuleCodec{Schema: moduleSchema}, err
+}
+
+func TestIndexerCheckpointSnapshotter(t *testing.T) {
+	var restored []indexer.Checkpoint
+	indexer.Register("indexer_snapshot_test", func(indexer.InitParams) (indexer.InitResult, error) {
+		return indexer.InitResult{
+			OnRestore: func(checkpoint indexer.Checkpoint) error {
+				restored = append(restored, checkpoint)
+				return nil
+			},
+		}, nil
+	})
+
+	modules := map[string]any{"bank": indexerSnapshotTestModule{}}
+	source := NewIndexerCheckpointSnapshotter(&BaseApp{}, modules)
+
+	var payloads [][]byte
+	require.NoError(t, source.SnapshotExtension(5, func(payload []byte) error {
+		payloads = append(payloads, payload)
+		return nil
+	}))
+	require.Len(t, payloads, 1)
+
+	reader := func() snapshot.ExtensionPayloadReader {
+		remaining := payloads
+		return func() ([]byte, error) {
+			if len(remaining) == 0 {
+				return nil, io.EOF
+			}
+			payload := remaining[0]
+			remaining = remaining[1:]
+			return payload, nil
+		}
+	}
+
+	// restoring without the indexer enabled is a no-op
+	require.NoError(t, source.RestoreExtension(5, IndexerCheckpointSnapshotFormat, reader()))
+
+	manager, err := indexer.StartManager(indexer.ManagerOptions{
+		Config: indexer.ManagerConfig{Target: map[string]indexer.Config{"test": {Type: "indexer_snapshot_test"}}},
+	})
+	require.NoError(t, err)
+	target := NewIndexerCheckpointSnapshotter(&BaseApp{indexerManager: manager}, modules)
+
+	require.NoError(t, target.RestoreExtension(5, IndexerCheckpointSnapshotFormat, reader()))
+	require.Len(t, restored, 1)
+	require.Equal(t, int64(5), restored[0].Height)
+	bankCodec, err := indexerSnapshotTestModule{}.ModuleCodec()
+	require.NoError(t, err)
+	require.True(t, restored[0].SchemaMatches("bank", bankCodec.Schema))
+
+	require.ErrorContains(t, target.RestoreExtension(6, IndexerCheckpointSnapshotFormat, reader()), "indexer checkpoint height 5 does not match snapshot height 6")
+	require.ErrorIs(t, target.RestoreExtension(5, 2, reader()), snapshot.ErrUnknownFormat)
+	payloads = nil
+	require.ErrorIs(t, target.RestoreExtension(5, IndexerCheckpointSnapshotFormat, reader()), io.ErrUnexpectedEOF)
+}
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 
 	abci "github.com/cometbft/cometbft/api/cometbft/abci/v1"
+	cmtproto "github.com/cometbft/cometbft/api/cometbft/types/v1"
 	"github.com/spf13/cast"
 
+	errorsmod "cosmossdk.io/errors"
 	"cosmossdk.io/schema"
 	"cosmossdk.io/schema/appdata"
 	"cosmossdk.io/schema/decoding"
@@ -18,6 +21,8 @@ import (
 
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
 const (
@@ -31,28 +36,47 @@ const (
 // EnableIndexer enables the built-in indexer with the provided options (usually from the app.toml indexer key),
 // kv-store keys, and app modules. Using the built-in indexer framework is mutually exclusive from using other
 // types of streaming listeners.
+//
+// Once enabled, the state changes of every block, including those made in begin and end blockers, are
+// streamed to the indexer targets as KVPairData and, for modules which implement schema.HasModuleCodec,
+// decoded into ObjectUpdateData without any extra code in the modules. Modules can be enabled or disabled
+// for all targets with the include_modules and exclude_modules options, in which case the state changes of
+// disabled modules are not collected at all.
 func (app *BaseApp) EnableIndexer(indexerOpts interface{}, keys map[string]*storetypes.KVStoreKey, appModules map[string]any) error {
-	listener, err := indexer.StartManager(indexer.ManagerOptions{
+	manager, err := indexer.StartManager(indexer.ManagerOptions{
 		Config:     indexerOpts,
 		Resolver:   decoding.ModuleSetDecoderResolver(appModules),
 		SyncSource: nil,
+		Metrics:    telemetry.IndexerMetrics(),
 		Logger:     app.logger.With("module", "indexer"),
 	})
 	if err != nil {
 		return err
 	}
+	app.indexerManager = manager
 
-	exposedKeys := exposeStoreKeysSorted([]string{"*"}, keys)
+	var exposedKeys []storetypes.StoreKey
+	for _, key := range exposeStoreKeysSorted([]string{"*"}, keys) {
+		if manager.IncludesModule(key.Name()) {
+			exposedKeys = append(exposedKeys, key)
+		}
+	}
 	app.cms.AddListeners(exposedKeys)
 
 	app.streamingManager = storetypes.StreamingManager{
-		ABCIListeners: []storetypes.ABCIListener{listenerWrapper{listener}},
+		ABCIListeners: []storetypes.ABCIListener{&listenerWrapper{listener: manager.Listener(), txDecoder: app.txDecoder}},
 		StopNodeOnErr: true,
 	}
 
 	return nil
 }
 
+// IndexerManager returns the manager of the built-in indexer targets, which reports their health, or nil if
+// EnableIndexer has not been called.
+func (app *BaseApp) IndexerManager() *indexer.Manager {
+	return app.indexerManager
+}
+
 // RegisterStreamingServices registers streaming services with the BaseApp.
 func (app *BaseApp) RegisterStreamingServices(appOpts servertypes.AppOptions, keys map[string]*storetypes.KVStoreKey) error {
 	// register streaming services
@@ -142,26 +166,231 @@ func exposeStoreKeysSorted(keysStr []string, keys map[string]*storetypes.KVStore
 	return exposeStoreKeys
 }
 
+// listenerWrapper adapts an app data listener to the ABCIListener interface. The packets of a finalized block
+// are buffered by block hash and only delivered once the block is committed, so that listeners never receive
+// data of blocks which are executed but not committed, for instance when a speculatively executed proposal is
+// replaced by another one in a later round.
 type listenerWrapper struct {
-	listener appdata.Listener
+	listener  appdata.Listener
+	txDecoder sdk.TxDecoder
+
+	mu sync.Mutex
+	// pending maps the hashes of finalized blocks which are not committed yet to their packets
+	pending map[string][]appdata.Packet
+
+	// deliverMu is held while the packets of a committed block or a simulation are delivered, so that
+	// simulations, which run concurrently with blocks, are never delivered in the middle of a block
+	deliverMu sync.Mutex
 }
 
-func (p listenerWrapper) ListenFinalizeBlock(_ context.Context, req abci.FinalizeBlockRequest, res abci.FinalizeBlockResponse) error {
+func (p *listenerWrapper) ListenFinalizeBlock(ctx context.Context, req abci.FinalizeBlockRequest, res abci.FinalizeBlockResponse) error {
+	var packets []appdata.Packet
 	if p.listener.StartBlock != nil {
-		err := p.listener.StartBlock(appdata.StartBlockData{
-			Height: uint64(req.Height),
+		header := appdata.BlockHeader{
+			Height:             uint64(req.Height),
+			Time:               req.Time,
+			Hash:               req.Hash,
+			NextValidatorsHash: req.NextValidatorsHash,
+			ProposerAddress:    req.ProposerAddress,
+		}
+		if sdkCtx, ok := ctx.(sdk.Context); ok {
+			header.ChainID = sdkCtx.ChainID()
+			header.AppHash = sdkCtx.BlockHeader().AppHash
+		}
+		lastCommit, validatorUpdates := req.DecidedLastCommit, res.ValidatorUpdates
+		packets = append(packets, appdata.StartBlockData{
+			Height:           uint64(req.Height),
+			Header:           func() (appdata.BlockHeader, error) { return header, nil },
+			LastCommit:       func() (appdata.LastCommit, error) { return lastCommitToAppData(lastCommit), nil },
+			ValidatorUpdates: func() ([]appdata.ValidatorUpdate, error) { return validatorUpdatesToAppData(validatorUpdates), nil },
 		})
-		if err != nil {
-			return err
+	}
+
+	if p.listener.OnTx != nil {
+		for i, txBytes := range req.Txs {
+			txBytes := txBytes
+			data := appdata.TxData{
+				TxIndex: int32(i),
+				Bytes:   func() ([]byte, error) { return txBytes, nil },
+			}
+			if p.txDecoder != nil {
+				data.Decoded = func() (appdata.DecodedTx, error) { return decodeTx(p.txDecoder, txBytes) }
+			}
+			if i < len(res.TxResults) {
+				txResult := res.TxResults[i]
+				data.Result = &appdata.TxResult{
+					Code:      txResult.Code,
+					Codespace: txResult.Codespace,
+					GasWanted: txResult.GasWanted,
+					GasUsed:   txResult.GasUsed,
+					Log:       txResult.Log,
+				}
+			}
+			packets = append(packets, data)
 		}
 	}
 
-	//// TODO txs, events
+	//// TODO events
 
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pending == nil {
+		p.pending = map[string][]appdata.Packet{}
+	}
+	p.pending[string(req.Hash)] = packets
 	return nil
 }
 
-func (p listenerWrapper) ListenCommit(ctx context.Context, res abci.CommitResponse, changeSet []*storetypes.StoreKVPair) error {
+// lastCommitToAppData converts the votes for the previous block. Validators which voted nil count as having
+// signed the block, like in x/slashing.
+func lastCommitToAppData(commit abci.CommitInfo) appdata.LastCommit {
+	res := appdata.LastCommit{Round: commit.Round, Votes: make([]appdata.Vote, len(commit.Votes))}
+	for i, vote := range commit.Votes {
+		res.Votes[i] = appdata.Vote{
+			ValidatorAddress: vote.Validator.Address,
+			Power:            vote.Validator.Power,
+			Signed:           vote.BlockIdFlag == cmtproto.BlockIDFlagCommit || vote.BlockIdFlag == cmtproto.BlockIDFlagNil,
+		}
+	}
+	return res
+}
+
+func validatorUpdatesToAppData(updates []abci.ValidatorUpdate) []appdata.ValidatorUpdate {
+	res := make([]appdata.ValidatorUpdate, len(updates))
+	for i, update := range updates {
+		res[i] = appdata.ValidatorUpdate{PubKeyType: update.PubKeyType, PubKey: update.PubKeyBytes, Power: update.Power}
+	}
+	return res
+}
+
+// simulationListener is implemented by streaming listeners which receive the outcomes of checked and simulated
+// transactions.
+type simulationListener interface {
+	listenSimulation(data appdata.SimulationData) error
+}
+
+// streamSimulation delivers the outcome of checking or simulating a transaction to the streaming listeners
+// which receive simulations, such as the indexer. Failures are logged since they must not affect the outcome
+// of the transaction.
+func (app *BaseApp) streamSimulation(mode execMode, txBytes []byte, gInfo sdk.GasInfo, result *sdk.Result, err error) {
+	var listeners []simulationListener
+	for _, listener := range app.streamingManager.ABCIListeners {
+		if l, ok := listener.(simulationListener); ok {
+			listeners = append(listeners, l)
+		}
+	}
+	if len(listeners) == 0 {
+		return
+	}
+
+	data := appdata.SimulationData{
+		Height: uint64(app.LastBlockHeight()),
+		Bytes:  func() ([]byte, error) { return txBytes, nil },
+		Result: appdata.TxResult{GasWanted: int64(gInfo.GasWanted), GasUsed: int64(gInfo.GasUsed)},
+	}
+	switch mode {
+	case execModeCheck:
+		data.Mode = appdata.CheckTxMode
+	case execModeReCheck:
+		data.Mode = appdata.ReCheckTxMode
+	default:
+		data.Mode = appdata.SimulateMode
+	}
+	if err != nil {
+		data.Result.Codespace, data.Result.Code, data.Result.Log = errorsmod.ABCIInfo(err, app.trace)
+	} else if result != nil {
+		data.Result.Log = result.Log
+	}
+
+	for _, listener := range listeners {
+		if err := listener.listenSimulation(data); err != nil {
+			app.logger.Error("failed to stream transaction simulation", "mode", data.Mode, "err", err)
+		}
+	}
+}
+
+func (p *listenerWrapper) listenSimulation(data appdata.SimulationData) error {
+	if p.listener.OnSimulation == nil {
+		return nil
+	}
+
+	if p.txDecoder != nil {
+		toBytes := data.Bytes
+		data.Decoded = func() (appdata.DecodedTx, error) {
+			txBytes, err := toBytes()
+			if err != nil {
+				return appdata.DecodedTx{}, err
+			}
+			return decodeTx(p.txDecoder, txBytes)
+		}
+	}
+
+	p.deliverMu.Lock()
+	defer p.deliverMu.Unlock()
+	return p.listener.OnSimulation(data)
+}
+
+// decodeTx decodes a transaction into the structured fields which are passed to indexers.
+func decodeTx(txDecoder sdk.TxDecoder, txBytes []byte) (appdata.DecodedTx, error) {
+	tx, err := txDecoder(txBytes)
+	if err != nil {
+		return appdata.DecodedTx{}, err
+	}
+
+	var res appdata.DecodedTx
+	for _, msg := range tx.GetMsgs() {
+		res.MsgTypeURLs = append(res.MsgTypeURLs, sdk.MsgTypeURL(msg))
+	}
+
+	res.Signers, err = tx.GetSenders()
+	if err != nil {
+		return appdata.DecodedTx{}, err
+	}
+
+	res.GasLimit, err = tx.GetGasLimit()
+	if err != nil {
+		return appdata.DecodedTx{}, err
+	}
+
+	if feeTx, ok := tx.(sdk.FeeTx); ok {
+		for _, coin := range feeTx.GetFee() {
+			res.Fee = append(res.Fee, appdata.Coin{Denom: coin.Denom, Amount: coin.Amount.String()})
+		}
+		res.FeePayer = feeTx.FeePayer()
+	}
+
+	if memoTx, ok := tx.(sdk.TxWithMemo); ok {
+		res.Memo = memoTx.GetMemo()
+	}
+
+	return res, nil
+}
+
+func (p *listenerWrapper) ListenCommit(ctx context.Context, res abci.CommitResponse, changeSet []*storetypes.StoreKVPair) error {
+	sdkCtx, ok := ctx.(sdk.Context)
+	if !ok {
+		return fmt.Errorf("cannot identify the committed block from a context of type %T", ctx)
+	}
+	hash := sdkCtx.HeaderHash()
+
+	p.deliverMu.Lock()
+	defer p.deliverMu.Unlock()
+
+	// deliver the packets of the committed block and drop those of any other block, which won't be committed
+	p.mu.Lock()
+	packets, found := p.pending[string(hash)]
+	p.pending = nil
+	p.mu.Unlock()
+	if !found {
+		return fmt.Errorf("block %X is committed but was not finalized", hash)
+	}
+
+	for _, packet := range packets {
+		if err := p.listener.SendPacket(packet); err != nil {
+			return err
+		}
+	}
+
 	if cb := p.listener.OnKVPair; cb != nil {
 		updates := make([]appdata.ModuleKVPairUpdate, len(changeSet))
 		for i, pair := range changeSet {
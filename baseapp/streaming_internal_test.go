@@ -0,0 +1,274 @@
+package baseapp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/api/cometbft/abci/v1"
+	cmtproto "github.com/cometbft/cometbft/api/cometbft/types/v1"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"cosmossdk.io/core/transaction"
+	"cosmossdk.io/log"
+	"cosmossdk.io/schema/appdata"
+	storetypes "cosmossdk.io/store/types"
+
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+type streamingTestTx struct {
+	msgs []sdk.Msg
+}
+
+var _ sdk.FeeTx = streamingTestTx{}
+
+func (streamingTestTx) Hash() [32]byte                                      { return [32]byte{} }
+func (streamingTestTx) GetMessages() ([]transaction.Msg, error)             { return nil, nil }
+func (streamingTestTx) GetSenders() ([][]byte, error)                       { return [][]byte{{0xaa}}, nil }
+func (streamingTestTx) GetGasLimit() (uint64, error)                        { return 200000, nil }
+func (streamingTestTx) Bytes() []byte                                       { return nil }
+func (tx streamingTestTx) GetMsgs() []sdk.Msg                               { return tx.msgs }
+func (streamingTestTx) GetReflectMessages() ([]protoreflect.Message, error) { return nil, nil }
+func (streamingTestTx) GetGas() uint64                                      { return 200000 }
+func (streamingTestTx) GetFee() sdk.Coins                                   { return sdk.NewCoins(sdk.NewInt64Coin("uatom", 10)) }
+func (streamingTestTx) FeePayer() []byte                                    { return []byte{0xaa} }
+func (streamingTestTx) FeeGranter() []byte                                  { return nil }
+func (streamingTestTx) GetMemo() string                                     { return "memo" }
+
+func TestListenerWrapper_Txs(t *testing.T) {
+	decodeErr := errors.New("decode error")
+	txDecoder := func(txBytes []byte) (sdk.Tx, error) {
+		if len(txBytes) == 0 {
+			return nil, decodeErr
+		}
+		return streamingTestTx{msgs: []sdk.Msg{testdata.NewTestMsg()}}, nil
+	}
+
+	var txs []appdata.TxData
+	wrapper := &listenerWrapper{
+		listener: appdata.Listener{
+			OnTx: func(data appdata.TxData) error {
+				txs = append(txs, data)
+				return nil
+			},
+		},
+		txDecoder: txDecoder,
+	}
+
+	err := wrapper.ListenFinalizeBlock(context.Background(), abci.FinalizeBlockRequest{
+		Height: 1,
+		Txs:    [][]byte{{1, 2, 3}, {}},
+	}, abci.FinalizeBlockResponse{
+		TxResults: []*abci.ExecTxResult{
+			{GasWanted: 200000, GasUsed: 50000},
+			{Code: 2, Codespace: "sdk"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, wrapper.ListenCommit(sdk.Context{}, abci.CommitResponse{}, nil))
+	require.Len(t, txs, 2)
+
+	bz, err := txs[0].Bytes()
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, bz)
+	require.Equal(t, &appdata.TxResult{GasWanted: 200000, GasUsed: 50000}, txs[0].Result)
+
+	decoded, err := txs[0].Decoded()
+	require.NoError(t, err)
+	require.Equal(t, appdata.DecodedTx{
+		MsgTypeURLs: []string{"/testpb.TestMsg"},
+		Signers:     [][]byte{{0xaa}},
+		Fee:         []appdata.Coin{{Denom: "uatom", Amount: "10"}},
+		FeePayer:    []byte{0xaa},
+		GasLimit:    200000,
+		Memo:        "memo",
+	}, decoded)
+
+	require.Equal(t, int32(1), txs[1].TxIndex)
+	require.Equal(t, &appdata.TxResult{Code: 2, Codespace: "sdk"}, txs[1].Result)
+	_, err = txs[1].Decoded()
+	require.ErrorIs(t, err, decodeErr)
+
+	// without a tx decoder, listeners still receive the raw bytes
+	wrapper.txDecoder = nil
+	txs = nil
+	err = wrapper.ListenFinalizeBlock(context.Background(), abci.FinalizeBlockRequest{Txs: [][]byte{{1}}}, abci.FinalizeBlockResponse{})
+	require.NoError(t, err)
+	require.NoError(t, wrapper.ListenCommit(sdk.Context{}, abci.CommitResponse{}, nil))
+	require.Len(t, txs, 1)
+	require.Nil(t, txs[0].Decoded)
+	require.Nil(t, txs[0].Result)
+}
+
+func TestListenerWrapper_OnlyCommittedBlocks(t *testing.T) {
+	var packets []appdata.Packet
+	record := func(p appdata.Packet) error {
+		packets = append(packets, p)
+		return nil
+	}
+	wrapper := &listenerWrapper{
+		listener: appdata.Listener{
+			StartBlock: func(data appdata.StartBlockData) error { return record(data) },
+			OnTx:       func(data appdata.TxData) error { return record(data) },
+			OnKVPair:   func(data appdata.KVPairData) error { return record(data) },
+			Commit:     func(data appdata.CommitData) error { return record(data) },
+		},
+	}
+
+	// a proposal which is executed but replaced by another one in a later round
+	require.NoError(t, wrapper.ListenFinalizeBlock(context.Background(), abci.FinalizeBlockRequest{
+		Height: 1,
+		Hash:   []byte("phantom"),
+		Txs:    [][]byte{{1}, {2}},
+	}, abci.FinalizeBlockResponse{}))
+	require.NoError(t, wrapper.ListenFinalizeBlock(context.Background(), abci.FinalizeBlockRequest{
+		Height: 1,
+		Hash:   []byte("committed"),
+		Txs:    [][]byte{{3}},
+	}, abci.FinalizeBlockResponse{}))
+	require.Empty(t, packets, "packets must be buffered until the block is committed")
+
+	ctx := sdk.Context{}.WithHeaderHash([]byte("committed"))
+	changeSet := []*storetypes.StoreKVPair{{StoreKey: "bank", Key: []byte{1}, Value: []byte{2}}}
+	require.NoError(t, wrapper.ListenCommit(ctx, abci.CommitResponse{}, changeSet))
+	require.Len(t, packets, 4)
+	require.Equal(t, uint64(1), packets[0].(appdata.StartBlockData).Height)
+	bz, err := packets[1].(appdata.TxData).Bytes()
+	require.NoError(t, err)
+	require.Equal(t, []byte{3}, bz)
+	require.Equal(t, "bank", packets[2].(appdata.KVPairData).Updates[0].ModuleName)
+	require.Equal(t, appdata.CommitData{}, packets[3])
+
+	// the packets of the phantom block were dropped, so it can't be committed anymore
+	packets = nil
+	err = wrapper.ListenCommit(sdk.Context{}.WithHeaderHash([]byte("phantom")), abci.CommitResponse{}, nil)
+	require.ErrorContains(t, err, "is committed but was not finalized")
+	require.Empty(t, packets)
+}
+
+func TestListenerWrapper_UnknownCommittedBlock(t *testing.T) {
+	var packets []appdata.Packet
+	wrapper := &listenerWrapper{
+		listener: appdata.Listener{
+			OnKVPair: func(data appdata.KVPairData) error {
+				packets = append(packets, data)
+				return nil
+			},
+			Commit: func(data appdata.CommitData) error {
+				packets = append(packets, data)
+				return nil
+			},
+		},
+	}
+	finalize := func(hash string) {
+		require.NoError(t, wrapper.ListenFinalizeBlock(context.Background(), abci.FinalizeBlockRequest{
+			Height: 1,
+			Hash:   []byte(hash),
+		}, abci.FinalizeBlockResponse{}))
+	}
+	changeSet := []*storetypes.StoreKVPair{{StoreKey: "bank", Key: []byte{1}, Value: []byte{2}}}
+
+	// the committed block must be identified by the header hash of an sdk.Context
+	finalize("block")
+	err := wrapper.ListenCommit(context.Background(), abci.CommitResponse{}, changeSet)
+	require.ErrorContains(t, err, "cannot identify the committed block")
+
+	// a block which was not finalized is not delivered, and the buffered blocks are dropped
+	finalize("block")
+	err = wrapper.ListenCommit(sdk.Context{}.WithHeaderHash([]byte("other")), abci.CommitResponse{}, changeSet)
+	require.ErrorContains(t, err, "block 6F74686572 is committed but was not finalized")
+	require.Empty(t, packets)
+	require.Empty(t, wrapper.pending)
+}
+
+func TestListenerWrapper_BlockMetadata(t *testing.T) {
+	var blocks []appdata.StartBlockData
+	wrapper := &listenerWrapper{
+		listener: appdata.Listener{
+			StartBlock: func(data appdata.StartBlockData) error {
+				blocks = append(blocks, data)
+				return nil
+			},
+		},
+	}
+
+	blockTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	ctx := sdk.Context{}.WithBlockHeader(cmtproto.Header{AppHash: []byte("apphash")}).WithChainID("test-chain")
+	require.NoError(t, wrapper.ListenFinalizeBlock(ctx, abci.FinalizeBlockRequest{
+		Height:          5,
+		Hash:            []byte("hash"),
+		Time:            blockTime,
+		ProposerAddress: []byte("proposer"),
+		DecidedLastCommit: abci.CommitInfo{Round: 1, Votes: []abci.VoteInfo{
+			{Validator: abci.Validator{Address: []byte("val1"), Power: 10}, BlockIdFlag: cmtproto.BlockIDFlagCommit},
+			{Validator: abci.Validator{Address: []byte("val2"), Power: 5}, BlockIdFlag: cmtproto.BlockIDFlagAbsent},
+		}},
+	}, abci.FinalizeBlockResponse{
+		ValidatorUpdates: []abci.ValidatorUpdate{{PubKeyType: "ed25519", PubKeyBytes: []byte("pk"), Power: 0}},
+	}))
+	require.NoError(t, wrapper.ListenCommit(sdk.Context{}.WithHeaderHash([]byte("hash")), abci.CommitResponse{}, nil))
+	require.Len(t, blocks, 1)
+
+	header, err := blocks[0].Header()
+	require.NoError(t, err)
+	require.Equal(t, appdata.BlockHeader{
+		ChainID:         "test-chain",
+		Height:          5,
+		Time:            blockTime,
+		Hash:            []byte("hash"),
+		AppHash:         []byte("apphash"),
+		ProposerAddress: []byte("proposer"),
+	}, header)
+
+	lastCommit, err := blocks[0].LastCommit()
+	require.NoError(t, err)
+	require.Equal(t, appdata.LastCommit{Round: 1, Votes: []appdata.Vote{
+		{ValidatorAddress: []byte("val1"), Power: 10, Signed: true},
+		{ValidatorAddress: []byte("val2"), Power: 5, Signed: false},
+	}}, lastCommit)
+
+	updates, err := blocks[0].ValidatorUpdates()
+	require.NoError(t, err)
+	require.Equal(t, []appdata.ValidatorUpdate{{PubKeyType: "ed25519", PubKey: []byte("pk"), Power: 0}}, updates)
+}
+
+func TestStreamSimulation(t *testing.T) {
+	var simulations []appdata.SimulationData
+	wrapper := &listenerWrapper{
+		listener: appdata.Listener{
+			OnSimulation: func(data appdata.SimulationData) error {
+				simulations = append(simulations, data)
+				return nil
+			},
+		},
+		txDecoder: func([]byte) (sdk.Tx, error) {
+			return streamingTestTx{msgs: []sdk.Msg{testdata.NewTestMsg()}}, nil
+		},
+	}
+
+	app := NewBaseApp("test", log.NewNopLogger(), dbm.NewMemDB(), nil)
+	app.streamingManager = storetypes.StreamingManager{ABCIListeners: []storetypes.ABCIListener{wrapper}}
+
+	app.streamSimulation(execModeSimulate, []byte{1}, sdk.GasInfo{GasWanted: 10, GasUsed: 5}, &sdk.Result{Log: "ok"}, nil)
+	app.streamSimulation(execModeCheck, []byte{2}, sdk.GasInfo{GasWanted: 10, GasUsed: 20}, nil, sdkerrors.ErrOutOfGas)
+	require.Len(t, simulations, 2)
+
+	require.Equal(t, appdata.SimulateMode, simulations[0].Mode)
+	require.Equal(t, appdata.TxResult{GasWanted: 10, GasUsed: 5, Log: "ok"}, simulations[0].Result)
+	decoded, err := simulations[0].Decoded()
+	require.NoError(t, err)
+	require.Equal(t, "memo", decoded.Memo)
+
+	require.Equal(t, appdata.CheckTxMode, simulations[1].Mode)
+	require.Equal(t, sdkerrors.ErrOutOfGas.ABCICode(), simulations[1].Result.Code)
+	require.Equal(t, sdkerrors.ErrOutOfGas.Codespace(), simulations[1].Result.Codespace)
+	bz, err := simulations[1].Bytes()
+	require.NoError(t, err)
+	require.Equal(t, []byte{2}, bz)
+}
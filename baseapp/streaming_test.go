@@ -9,10 +9,13 @@ import (
 	tmproto "github.com/cometbft/cometbft/api/cometbft/types/v1"
 	"github.com/stretchr/testify/require"
 
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
 	storetypes "cosmossdk.io/store/types"
 
 	"github.com/cosmos/cosmos-sdk/baseapp"
 	baseapptestutil "github.com/cosmos/cosmos-sdk/baseapp/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
 var _ storetypes.ABCIListener = (*MockABCIListener)(nil)
@@ -146,3 +149,57 @@ func Test_Ctx_with_StreamingManager(t *testing.T) {
 		require.NoError(t, err)
 	}
 }
+
+func TestEnableIndexer_ModuleConfig(t *testing.T) {
+	var kvPairModules []string
+	commits := 0
+	indexer.Register("baseapp_streaming_test", func(indexer.InitParams) (indexer.InitResult, error) {
+		return indexer.InitResult{
+			Listener: appdata.Listener{
+				OnKVPair: func(data appdata.KVPairData) error {
+					for _, update := range data.Updates {
+						kvPairModules = append(kvPairModules, update.ModuleName)
+					}
+					return nil
+				},
+				Commit: func(appdata.CommitData) error {
+					commits++
+					return nil
+				},
+			},
+			LastBlockPersisted: -1,
+		}, nil
+	})
+
+	distOpt := func(bapp *baseapp.BaseApp) { bapp.MountStores(distKey1) }
+	beginBlockOpt := func(bapp *baseapp.BaseApp) {
+		bapp.SetBeginBlocker(func(ctx sdk.Context) (sdk.BeginBlock, error) {
+			ctx.KVStore(distKey1).Set([]byte("foo"), []byte("bar"))
+			ctx.KVStore(capKey1).Set([]byte("foo"), []byte("bar"))
+			return sdk.BeginBlock{}, nil
+		})
+	}
+	suite := NewBaseAppSuite(t, distOpt, beginBlockOpt)
+
+	err := suite.baseApp.EnableIndexer(map[string]interface{}{
+		"exclude_modules": []interface{}{capKey1.Name()},
+		"target": map[string]interface{}{
+			"test": map[string]interface{}{"type": "baseapp_streaming_test"},
+		},
+	}, map[string]*storetypes.KVStoreKey{capKey1.Name(): capKey1, distKey1.Name(): distKey1}, nil)
+	require.NoError(t, err)
+	require.True(t, suite.baseApp.CommitMultiStore().ListeningEnabled(distKey1))
+	require.False(t, suite.baseApp.CommitMultiStore().ListeningEnabled(capKey1))
+
+	_, err = suite.baseApp.InitChain(&abci.InitChainRequest{ConsensusParams: &tmproto.ConsensusParams{}})
+	require.NoError(t, err)
+
+	// the state changes of the begin blocker are streamed without the excluded module
+	_, err = suite.baseApp.FinalizeBlock(&abci.FinalizeBlockRequest{Height: 1})
+	require.NoError(t, err)
+	_, err = suite.baseApp.Commit()
+	require.NoError(t, err)
+
+	require.Equal(t, 1, commits)
+	require.Equal(t, []string{distKey1.Name()}, kvPairModules)
+}
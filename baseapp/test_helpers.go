@@ -26,6 +26,7 @@ func (app *BaseApp) SimCheck(txEncoder sdk.TxEncoder, tx sdk.Tx) (sdk.GasInfo, *
 // Simulate executes a tx in simulate mode to get result and gas info.
 func (app *BaseApp) Simulate(txBytes []byte) (sdk.GasInfo, *sdk.Result, error) {
 	gasInfo, result, _, err := app.runTx(execModeSimulate, txBytes)
+	app.streamSimulation(execModeSimulate, txBytes, gasInfo, result, err)
 	return gasInfo, result, err
 }
 
@@ -0,0 +1,586 @@
+package schemaproto
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	indexerv1 "cosmossdk.io/api/cosmos/indexer/v1"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+)
+
+// PacketToProto converts an app data packet to its protobuf representation, evaluating the lazy fields of the
+// packet.
+func PacketToProto(packet appdata.Packet) (*indexerv1.Packet, error) {
+	switch p := packet.(type) {
+	case appdata.ModuleInitializationData:
+		return &indexerv1.Packet{Packet: &indexerv1.Packet_ModuleInitialization{
+			ModuleInitialization: &indexerv1.ModuleInitialization{
+				ModuleName:    p.ModuleName,
+				Schema:        ModuleSchemaToProto(p.Schema),
+				SchemaChanged: p.SchemaChanged,
+			},
+		}}, nil
+
+	case appdata.StartBlockData:
+		res := &indexerv1.StartBlock{Height: p.Height, Backfill: p.Backfill}
+		var err error
+		if p.HeaderBytes != nil {
+			if res.HeaderBytes, err = p.HeaderBytes(); err != nil {
+				return nil, err
+			}
+		}
+		if p.HeaderJSON != nil {
+			if res.HeaderJson, err = p.HeaderJSON(); err != nil {
+				return nil, err
+			}
+		}
+		if err := blockMetadataToProto(p, res); err != nil {
+			return nil, err
+		}
+		return &indexerv1.Packet{Packet: &indexerv1.Packet_StartBlock{StartBlock: res}}, nil
+
+	case appdata.TxData:
+		res, err := txToProto(p)
+		if err != nil {
+			return nil, err
+		}
+		return &indexerv1.Packet{Packet: &indexerv1.Packet_Tx{Tx: res}}, nil
+
+	case appdata.EventData:
+		res, err := eventToProto(p)
+		if err != nil {
+			return nil, err
+		}
+		return &indexerv1.Packet{Packet: &indexerv1.Packet_Event{Event: res}}, nil
+
+	case appdata.KVPairData:
+		res := &indexerv1.KVPairs{Updates: make([]*indexerv1.ModuleKVPairUpdate, len(p.Updates))}
+		for i, update := range p.Updates {
+			res.Updates[i] = &indexerv1.ModuleKVPairUpdate{
+				ModuleName: update.ModuleName,
+				Key:        update.Update.Key,
+				Value:      update.Update.Value,
+				Delete:     update.Update.Delete,
+			}
+		}
+		return &indexerv1.Packet{Packet: &indexerv1.Packet_KvPairs{KvPairs: res}}, nil
+
+	case appdata.ObjectUpdateData:
+		res := &indexerv1.ObjectUpdates{ModuleName: p.ModuleName, Updates: make([]*indexerv1.ObjectUpdate, len(p.Updates))}
+		for i, update := range p.Updates {
+			var err error
+			if res.Updates[i], err = objectUpdateToProto(update); err != nil {
+				return nil, err
+			}
+		}
+		return &indexerv1.Packet{Packet: &indexerv1.Packet_ObjectUpdates{ObjectUpdates: res}}, nil
+
+	case appdata.BytesChunkData:
+		return &indexerv1.Packet{Packet: &indexerv1.Packet_BytesChunk{BytesChunk: &indexerv1.BytesChunk{
+			ModuleName:  p.ModuleName,
+			TypeName:    p.TypeName,
+			UpdateIndex: uint32(p.UpdateIndex),
+			FieldName:   p.FieldName,
+			Size:        uint64(p.Size),
+			Offset:      uint64(p.Offset),
+			Chunk:       p.Chunk,
+		}}}, nil
+
+	case appdata.CommitData:
+		return &indexerv1.Packet{Packet: &indexerv1.Packet_Commit{Commit: &indexerv1.Commit{}}}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown packet type %T", packet)
+	}
+}
+
+func txToProto(tx appdata.TxData) (*indexerv1.Tx, error) {
+	res := &indexerv1.Tx{TxIndex: tx.TxIndex}
+	var err error
+	if tx.Bytes != nil {
+		if res.TxBytes, err = tx.Bytes(); err != nil {
+			return nil, err
+		}
+	}
+	if tx.JSON != nil {
+		if res.TxJson, err = tx.JSON(); err != nil {
+			return nil, err
+		}
+	}
+	if tx.Decoded != nil {
+		decoded, err := tx.Decoded()
+		if err != nil {
+			return nil, err
+		}
+		res.Decoded = &indexerv1.DecodedTx{
+			MsgTypeUrls: decoded.MsgTypeURLs,
+			Signers:     decoded.Signers,
+			FeePayer:    decoded.FeePayer,
+			GasLimit:    decoded.GasLimit,
+			Memo:        decoded.Memo,
+		}
+		for _, coin := range decoded.Fee {
+			res.Decoded.Fee = append(res.Decoded.Fee, &indexerv1.Coin{Denom: coin.Denom, Amount: coin.Amount})
+		}
+	}
+	if tx.Result != nil {
+		res.Result = &indexerv1.TxResult{
+			Code:      tx.Result.Code,
+			Codespace: tx.Result.Codespace,
+			GasWanted: tx.Result.GasWanted,
+			GasUsed:   tx.Result.GasUsed,
+			Log:       tx.Result.Log,
+		}
+	}
+	return res, nil
+}
+
+// blockMetadataToProto sets the structured header, last commit and validator updates of the block on res.
+func blockMetadataToProto(data appdata.StartBlockData, res *indexerv1.StartBlock) error {
+	if data.Header != nil {
+		header, err := data.Header()
+		if err != nil {
+			return err
+		}
+		res.Header = &indexerv1.BlockHeader{
+			ChainId:            header.ChainID,
+			Height:             header.Height,
+			Time:               timestamppb.New(header.Time),
+			Hash:               header.Hash,
+			AppHash:            header.AppHash,
+			NextValidatorsHash: header.NextValidatorsHash,
+			ProposerAddress:    header.ProposerAddress,
+		}
+	}
+
+	if data.LastCommit != nil {
+		lastCommit, err := data.LastCommit()
+		if err != nil {
+			return err
+		}
+		res.LastCommit = &indexerv1.LastCommit{Round: lastCommit.Round, Votes: make([]*indexerv1.Vote, len(lastCommit.Votes))}
+		for i, vote := range lastCommit.Votes {
+			res.LastCommit.Votes[i] = &indexerv1.Vote{ValidatorAddress: vote.ValidatorAddress, Power: vote.Power, Signed: vote.Signed}
+		}
+	}
+
+	if data.ValidatorUpdates != nil {
+		updates, err := data.ValidatorUpdates()
+		if err != nil {
+			return err
+		}
+		res.ValidatorUpdates = &indexerv1.ValidatorUpdates{Updates: make([]*indexerv1.ValidatorUpdate, len(updates))}
+		for i, update := range updates {
+			res.ValidatorUpdates.Updates[i] = &indexerv1.ValidatorUpdate{PubKeyType: update.PubKeyType, PubKey: update.PubKey, Power: update.Power}
+		}
+	}
+	return nil
+}
+
+func eventToProto(event appdata.EventData) (*indexerv1.Event, error) {
+	res := &indexerv1.Event{
+		TxIndex:    event.TxIndex,
+		MsgIndex:   event.MsgIndex,
+		EventIndex: event.EventIndex,
+		EventType:  event.Type,
+		ModuleName: event.ModuleName,
+	}
+	var err error
+	if event.Data != nil {
+		if res.DataJson, err = event.Data(); err != nil {
+			return nil, err
+		}
+	}
+	if event.Attributes != nil {
+		attrs, err := event.Attributes()
+		if err != nil {
+			return nil, err
+		}
+		for _, attr := range attrs {
+			value, err := ValueToProto(attr.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of event attribute %q: %w", attr.Key, err)
+			}
+			res.Attributes = append(res.Attributes, &indexerv1.EventAttribute{Key: attr.Key, Value: value})
+		}
+	}
+	return res, nil
+}
+
+func objectUpdateToProto(update schema.ObjectUpdate) (*indexerv1.ObjectUpdate, error) {
+	key, err := ValueToProto(update.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key of %q update: %w", update.TypeName, err)
+	}
+
+	res := &indexerv1.ObjectUpdate{TypeName: update.TypeName, Key: key, Delete: update.Delete}
+	if update.Delete {
+		return res, nil
+	}
+
+	if valueUpdates, ok := update.Value.(schema.ValueUpdates); ok {
+		fieldUpdates := &indexerv1.FieldUpdates{}
+		var valueErr error
+		err := valueUpdates.Iterate(func(col string, value interface{}) bool {
+			var v *indexerv1.Value
+			v, valueErr = ValueToProto(value)
+			if valueErr != nil {
+				valueErr = fmt.Errorf("invalid value of field %q of %q update: %w", col, update.TypeName, valueErr)
+				return false
+			}
+			fieldUpdates.Updates = append(fieldUpdates.Updates, &indexerv1.FieldUpdate{FieldName: col, Value: v})
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		if valueErr != nil {
+			return nil, valueErr
+		}
+		res.Value = &indexerv1.ObjectUpdate_FieldUpdates{FieldUpdates: fieldUpdates}
+		return res, nil
+	}
+
+	value, err := ValueToProto(update.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value of %q update: %w", update.TypeName, err)
+	}
+	res.Value = &indexerv1.ObjectUpdate_ObjectValue{ObjectValue: value}
+	return res, nil
+}
+
+// ValueToProto converts a schema value, which must have one of the go types which schema.Kind.ValidateValueType
+// accepts, or a slice of them for multi-field keys and values, to its protobuf representation.
+func ValueToProto(value interface{}) (*indexerv1.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return &indexerv1.Value{Kind: &indexerv1.Value_NullValue{NullValue: true}}, nil
+	case string:
+		return &indexerv1.Value{Kind: &indexerv1.Value_StringValue{StringValue: v}}, nil
+	case []byte:
+		return &indexerv1.Value{Kind: &indexerv1.Value_BytesValue{BytesValue: v}}, nil
+	case int8:
+		return &indexerv1.Value{Kind: &indexerv1.Value_Int8Value{Int8Value: int32(v)}}, nil
+	case uint8:
+		return &indexerv1.Value{Kind: &indexerv1.Value_Uint8Value{Uint8Value: uint32(v)}}, nil
+	case int16:
+		return &indexerv1.Value{Kind: &indexerv1.Value_Int16Value{Int16Value: int32(v)}}, nil
+	case uint16:
+		return &indexerv1.Value{Kind: &indexerv1.Value_Uint16Value{Uint16Value: uint32(v)}}, nil
+	case int32:
+		return &indexerv1.Value{Kind: &indexerv1.Value_Int32Value{Int32Value: v}}, nil
+	case uint32:
+		return &indexerv1.Value{Kind: &indexerv1.Value_Uint32Value{Uint32Value: v}}, nil
+	case int64:
+		return &indexerv1.Value{Kind: &indexerv1.Value_Int64Value{Int64Value: v}}, nil
+	case uint64:
+		return &indexerv1.Value{Kind: &indexerv1.Value_Uint64Value{Uint64Value: v}}, nil
+	case bool:
+		return &indexerv1.Value{Kind: &indexerv1.Value_BoolValue{BoolValue: v}}, nil
+	case float32:
+		return &indexerv1.Value{Kind: &indexerv1.Value_Float32Value{Float32Value: v}}, nil
+	case float64:
+		return &indexerv1.Value{Kind: &indexerv1.Value_Float64Value{Float64Value: v}}, nil
+	case time.Time:
+		return &indexerv1.Value{Kind: &indexerv1.Value_TimeValue{TimeValue: timestamppb.New(v)}}, nil
+	case time.Duration:
+		return &indexerv1.Value{Kind: &indexerv1.Value_DurationValue{DurationValue: durationpb.New(v)}}, nil
+	case json.RawMessage:
+		return &indexerv1.Value{Kind: &indexerv1.Value_JsonValue{JsonValue: v}}, nil
+	case []interface{}:
+		list := &indexerv1.ValueList{Values: make([]*indexerv1.Value, len(v))}
+		for i, elem := range v {
+			var err error
+			if list.Values[i], err = ValueToProto(elem); err != nil {
+				return nil, err
+			}
+		}
+		return &indexerv1.Value{Kind: &indexerv1.Value_ListValue{ListValue: list}}, nil
+	case map[interface{}]interface{}:
+		m := &indexerv1.ValueMap{}
+		for key, elem := range v {
+			k, err := ValueToProto(key)
+			if err != nil {
+				return nil, err
+			}
+			e, err := ValueToProto(elem)
+			if err != nil {
+				return nil, err
+			}
+			m.Entries = append(m.Entries, &indexerv1.ValueMapEntry{Key: k, Value: e})
+		}
+		return &indexerv1.Value{Kind: &indexerv1.Value_MapValue{MapValue: m}}, nil
+	case schema.OneOfValue:
+		// oneof values have no typed case and are sent in the self-describing encoding of the schema package
+		bz, err := schema.EncodeValue(v)
+		if err != nil {
+			return nil, err
+		}
+		return &indexerv1.Value{Kind: &indexerv1.Value_EncodedValue{EncodedValue: bz}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+// PacketFromProto converts the protobuf representation of an app data packet back to the packet.
+func PacketFromProto(packet *indexerv1.Packet) (appdata.Packet, error) {
+	switch p := packet.GetPacket().(type) {
+	case *indexerv1.Packet_ModuleInitialization:
+		moduleSchema, err := ModuleSchemaFromProto(p.ModuleInitialization.GetSchema())
+		if err != nil {
+			return nil, err
+		}
+		return appdata.ModuleInitializationData{
+			ModuleName:    p.ModuleInitialization.GetModuleName(),
+			Schema:        moduleSchema,
+			SchemaChanged: p.ModuleInitialization.GetSchemaChanged(),
+		}, nil
+
+	case *indexerv1.Packet_StartBlock:
+		res := appdata.StartBlockData{Height: p.StartBlock.GetHeight(), Backfill: p.StartBlock.GetBackfill()}
+		if headerBytes := p.StartBlock.GetHeaderBytes(); headerBytes != nil {
+			res.HeaderBytes = func() ([]byte, error) { return headerBytes, nil }
+		}
+		if headerJSON := p.StartBlock.GetHeaderJson(); headerJSON != nil {
+			res.HeaderJSON = func() (json.RawMessage, error) { return headerJSON, nil }
+		}
+		blockMetadataFromProto(p.StartBlock, &res)
+		return res, nil
+
+	case *indexerv1.Packet_Tx:
+		return txFromProto(p.Tx), nil
+
+	case *indexerv1.Packet_Event:
+		return eventFromProto(p.Event)
+
+	case *indexerv1.Packet_KvPairs:
+		res := appdata.KVPairData{Updates: make([]appdata.ModuleKVPairUpdate, len(p.KvPairs.GetUpdates()))}
+		for i, update := range p.KvPairs.GetUpdates() {
+			res.Updates[i] = appdata.ModuleKVPairUpdate{
+				ModuleName: update.GetModuleName(),
+				Update: schema.KVPairUpdate{
+					Key:    update.GetKey(),
+					Value:  update.GetValue(),
+					Delete: update.GetDelete(),
+				},
+			}
+		}
+		return res, nil
+
+	case *indexerv1.Packet_ObjectUpdates:
+		res := appdata.ObjectUpdateData{
+			ModuleName: p.ObjectUpdates.GetModuleName(),
+			Updates:    make([]schema.ObjectUpdate, len(p.ObjectUpdates.GetUpdates())),
+		}
+		for i, update := range p.ObjectUpdates.GetUpdates() {
+			var err error
+			if res.Updates[i], err = objectUpdateFromProto(update); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+
+	case *indexerv1.Packet_BytesChunk:
+		return appdata.BytesChunkData{
+			ModuleName:  p.BytesChunk.GetModuleName(),
+			TypeName:    p.BytesChunk.GetTypeName(),
+			UpdateIndex: int(p.BytesChunk.GetUpdateIndex()),
+			FieldName:   p.BytesChunk.GetFieldName(),
+			Size:        int(p.BytesChunk.GetSize()),
+			Offset:      int(p.BytesChunk.GetOffset()),
+			Chunk:       p.BytesChunk.GetChunk(),
+		}, nil
+
+	case *indexerv1.Packet_Commit:
+		return appdata.CommitData{}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown packet type %T", p)
+	}
+}
+
+func txFromProto(tx *indexerv1.Tx) appdata.TxData {
+	res := appdata.TxData{TxIndex: tx.GetTxIndex()}
+	if txBytes := tx.GetTxBytes(); txBytes != nil {
+		res.Bytes = func() ([]byte, error) { return txBytes, nil }
+	}
+	if txJSON := tx.GetTxJson(); txJSON != nil {
+		res.JSON = func() (json.RawMessage, error) { return txJSON, nil }
+	}
+	if decoded := tx.GetDecoded(); decoded != nil {
+		decodedTx := appdata.DecodedTx{
+			MsgTypeURLs: decoded.GetMsgTypeUrls(),
+			Signers:     decoded.GetSigners(),
+			FeePayer:    decoded.GetFeePayer(),
+			GasLimit:    decoded.GetGasLimit(),
+			Memo:        decoded.GetMemo(),
+		}
+		for _, coin := range decoded.GetFee() {
+			decodedTx.Fee = append(decodedTx.Fee, appdata.Coin{Denom: coin.GetDenom(), Amount: coin.GetAmount()})
+		}
+		res.Decoded = func() (appdata.DecodedTx, error) { return decodedTx, nil }
+	}
+	if result := tx.GetResult(); result != nil {
+		res.Result = &appdata.TxResult{
+			Code:      result.GetCode(),
+			Codespace: result.GetCodespace(),
+			GasWanted: result.GetGasWanted(),
+			GasUsed:   result.GetGasUsed(),
+			Log:       result.GetLog(),
+		}
+	}
+	return res
+}
+
+// blockMetadataFromProto sets the structured header, last commit and validator updates of the block on res.
+func blockMetadataFromProto(block *indexerv1.StartBlock, res *appdata.StartBlockData) {
+	if h := block.GetHeader(); h != nil {
+		header := appdata.BlockHeader{
+			ChainID:            h.GetChainId(),
+			Height:             h.GetHeight(),
+			Hash:               h.GetHash(),
+			AppHash:            h.GetAppHash(),
+			NextValidatorsHash: h.GetNextValidatorsHash(),
+			ProposerAddress:    h.GetProposerAddress(),
+		}
+		if h.GetTime() != nil {
+			header.Time = h.GetTime().AsTime()
+		}
+		res.Header = func() (appdata.BlockHeader, error) { return header, nil }
+	}
+
+	if c := block.GetLastCommit(); c != nil {
+		lastCommit := appdata.LastCommit{Round: c.GetRound(), Votes: make([]appdata.Vote, len(c.GetVotes()))}
+		for i, vote := range c.GetVotes() {
+			lastCommit.Votes[i] = appdata.Vote{ValidatorAddress: vote.GetValidatorAddress(), Power: vote.GetPower(), Signed: vote.GetSigned()}
+		}
+		res.LastCommit = func() (appdata.LastCommit, error) { return lastCommit, nil }
+	}
+
+	if u := block.GetValidatorUpdates(); u != nil {
+		updates := make([]appdata.ValidatorUpdate, len(u.GetUpdates()))
+		for i, update := range u.GetUpdates() {
+			updates[i] = appdata.ValidatorUpdate{PubKeyType: update.GetPubKeyType(), PubKey: update.GetPubKey(), Power: update.GetPower()}
+		}
+		res.ValidatorUpdates = func() ([]appdata.ValidatorUpdate, error) { return updates, nil }
+	}
+}
+
+func eventFromProto(event *indexerv1.Event) (appdata.EventData, error) {
+	res := appdata.EventData{
+		TxIndex:    event.GetTxIndex(),
+		MsgIndex:   event.GetMsgIndex(),
+		EventIndex: event.GetEventIndex(),
+		Type:       event.GetEventType(),
+		ModuleName: event.GetModuleName(),
+	}
+	if data := event.GetDataJson(); data != nil {
+		res.Data = func() (json.RawMessage, error) { return data, nil }
+	}
+	if len(event.GetAttributes()) != 0 {
+		attrs := make([]appdata.EventAttribute, len(event.GetAttributes()))
+		for i, attr := range event.GetAttributes() {
+			value, err := ValueFromProto(attr.GetValue())
+			if err != nil {
+				return appdata.EventData{}, fmt.Errorf("invalid value of event attribute %q: %w", attr.GetKey(), err)
+			}
+			attrs[i] = appdata.EventAttribute{Key: attr.GetKey(), Value: value}
+		}
+		res.Attributes = func() ([]appdata.EventAttribute, error) { return attrs, nil }
+	}
+	return res, nil
+}
+
+func objectUpdateFromProto(update *indexerv1.ObjectUpdate) (schema.ObjectUpdate, error) {
+	key, err := ValueFromProto(update.GetKey())
+	if err != nil {
+		return schema.ObjectUpdate{}, fmt.Errorf("invalid key of %q update: %w", update.GetTypeName(), err)
+	}
+
+	res := schema.ObjectUpdate{TypeName: update.GetTypeName(), Key: key, Delete: update.GetDelete()}
+	switch value := update.GetValue().(type) {
+	case *indexerv1.ObjectUpdate_ObjectValue:
+		if res.Value, err = ValueFromProto(value.ObjectValue); err != nil {
+			return schema.ObjectUpdate{}, fmt.Errorf("invalid value of %q update: %w", update.GetTypeName(), err)
+		}
+	case *indexerv1.ObjectUpdate_FieldUpdates:
+		valueUpdates := schema.MapValueUpdates{}
+		for _, fieldUpdate := range value.FieldUpdates.GetUpdates() {
+			if valueUpdates[fieldUpdate.GetFieldName()], err = ValueFromProto(fieldUpdate.GetValue()); err != nil {
+				return schema.ObjectUpdate{}, fmt.Errorf("invalid value of field %q of %q update: %w", fieldUpdate.GetFieldName(), update.GetTypeName(), err)
+			}
+		}
+		res.Value = valueUpdates
+	}
+	return res, nil
+}
+
+// ValueFromProto converts the protobuf representation of a schema value back to the value.
+func ValueFromProto(value *indexerv1.Value) (interface{}, error) {
+	switch v := value.GetKind().(type) {
+	case nil, *indexerv1.Value_NullValue:
+		return nil, nil
+	case *indexerv1.Value_StringValue:
+		return v.StringValue, nil
+	case *indexerv1.Value_BytesValue:
+		return v.BytesValue, nil
+	case *indexerv1.Value_Int8Value:
+		return int8(v.Int8Value), nil
+	case *indexerv1.Value_Uint8Value:
+		return uint8(v.Uint8Value), nil
+	case *indexerv1.Value_Int16Value:
+		return int16(v.Int16Value), nil
+	case *indexerv1.Value_Uint16Value:
+		return uint16(v.Uint16Value), nil
+	case *indexerv1.Value_Int32Value:
+		return v.Int32Value, nil
+	case *indexerv1.Value_Uint32Value:
+		return v.Uint32Value, nil
+	case *indexerv1.Value_Int64Value:
+		return v.Int64Value, nil
+	case *indexerv1.Value_Uint64Value:
+		return v.Uint64Value, nil
+	case *indexerv1.Value_BoolValue:
+		return v.BoolValue, nil
+	case *indexerv1.Value_Float32Value:
+		return v.Float32Value, nil
+	case *indexerv1.Value_Float64Value:
+		return v.Float64Value, nil
+	case *indexerv1.Value_TimeValue:
+		return v.TimeValue.AsTime(), nil
+	case *indexerv1.Value_DurationValue:
+		return v.DurationValue.AsDuration(), nil
+	case *indexerv1.Value_JsonValue:
+		return json.RawMessage(v.JsonValue), nil
+	case *indexerv1.Value_ListValue:
+		res := make([]interface{}, len(v.ListValue.GetValues()))
+		for i, elem := range v.ListValue.GetValues() {
+			var err error
+			if res[i], err = ValueFromProto(elem); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	case *indexerv1.Value_MapValue:
+		res := make(map[interface{}]interface{}, len(v.MapValue.GetEntries()))
+		for _, entry := range v.MapValue.GetEntries() {
+			key, err := ValueFromProto(entry.GetKey())
+			if err != nil {
+				return nil, err
+			}
+			if res[key], err = ValueFromProto(entry.GetValue()); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	case *indexerv1.Value_EncodedValue:
+		return schema.DecodeValue(v.EncodedValue)
+	default:
+		return nil, fmt.Errorf("unknown value kind %T", v)
+	}
+}
@@ -0,0 +1,90 @@
+package schemaproto_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+
+	"github.com/cosmos/cosmos-sdk/codec/schemaproto"
+)
+
+func TestValueRoundTrip(t *testing.T) {
+	values := []interface{}{
+		nil,
+		"foo",
+		[]byte{1, 2},
+		int8(-1),
+		uint8(1),
+		int16(-300),
+		uint16(300),
+		int32(-70000),
+		uint32(70000),
+		int64(-1) << 40,
+		uint64(1) << 40,
+		true,
+		float32(1.5),
+		2.5,
+		time.Unix(1700000000, 5).UTC(),
+		3 * time.Second,
+		json.RawMessage(`{"a":1}`),
+		[]interface{}{"a", int32(1), []interface{}{true}},
+		map[interface{}]interface{}{"a": uint64(1)},
+		schema.OneOfValue{Case: "point", Value: []interface{}{int32(1), int32(2)}},
+	}
+	for _, value := range values {
+		p, err := schemaproto.ValueToProto(value)
+		require.NoError(t, err)
+		res, err := schemaproto.ValueFromProto(p)
+		require.NoError(t, err)
+		require.Equal(t, value, res)
+	}
+
+	_, err := schemaproto.ValueToProto(struct{}{})
+	require.ErrorContains(t, err, "unsupported value type")
+}
+
+func TestStartBlockRoundTrip(t *testing.T) {
+	header := appdata.BlockHeader{
+		ChainID:         "test",
+		Height:          5,
+		Time:            time.Unix(1700000000, 0).UTC(),
+		Hash:            []byte{1},
+		AppHash:         []byte{2},
+		ProposerAddress: []byte{3},
+	}
+	lastCommit := appdata.LastCommit{Round: 1, Votes: []appdata.Vote{{ValidatorAddress: []byte{3}, Power: 10, Signed: true}}}
+	updates := []appdata.ValidatorUpdate{{PubKeyType: "ed25519", PubKey: []byte{4}, Power: 5}}
+
+	p, err := schemaproto.PacketToProto(appdata.StartBlockData{
+		Height:           5,
+		Header:           func() (appdata.BlockHeader, error) { return header, nil },
+		LastCommit:       func() (appdata.LastCommit, error) { return lastCommit, nil },
+		ValidatorUpdates: func() ([]appdata.ValidatorUpdate, error) { return updates, nil },
+	})
+	require.NoError(t, err)
+	packet, err := schemaproto.PacketFromProto(p)
+	require.NoError(t, err)
+
+	data := packet.(appdata.StartBlockData)
+	resHeader, err := data.Header()
+	require.NoError(t, err)
+	require.Equal(t, header, resHeader)
+	resLastCommit, err := data.LastCommit()
+	require.NoError(t, err)
+	require.Equal(t, lastCommit, resLastCommit)
+	resUpdates, err := data.ValidatorUpdates()
+	require.NoError(t, err)
+	require.Equal(t, updates, resUpdates)
+
+	// metadata which the source does not provide stays nil
+	p, err = schemaproto.PacketToProto(appdata.StartBlockData{Height: 6})
+	require.NoError(t, err)
+	packet, err = schemaproto.PacketFromProto(p)
+	require.NoError(t, err)
+	require.Equal(t, appdata.StartBlockData{Height: 6}, packet)
+}
@@ -0,0 +1,246 @@
+// Package protoderive derives cosmossdk.io/schema object types from protobuf message descriptors so
+// that modules whose state is defined in protobuf do not need to maintain a separate schema definition.
+package protoderive
+
+import (
+	"fmt"
+
+	cosmos_proto "github.com/cosmos/cosmos-proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"cosmossdk.io/schema"
+)
+
+var (
+	timestampFullName = (&timestamppb.Timestamp{}).ProtoReflect().Descriptor().FullName()
+	durationFullName  = (&durationpb.Duration{}).ProtoReflect().Descriptor().FullName()
+	anyFullName       = (&anypb.Any{}).ProtoReflect().Descriptor().FullName()
+	structFullName    = (&structpb.Struct{}).ProtoReflect().Descriptor().FullName()
+	valueFullName     = (&structpb.Value{}).ProtoReflect().Descriptor().FullName()
+)
+
+// scalarKinds maps cosmos_proto.scalar annotations to the kinds of the fields they annotate.
+var scalarKinds = map[string]schema.Kind{
+	"cosmos.AddressString":          schema.AddressKind,
+	"cosmos.ValidatorAddressString": schema.AddressKind,
+	"cosmos.ConsensusAddressString": schema.AddressKind,
+	"cosmos.AddressBytes":           schema.AddressKind,
+	"cosmos.ValidatorAddressBytes":  schema.AddressKind,
+	"cosmos.ConsensusAddressBytes":  schema.AddressKind,
+	"cosmos.Int":                    schema.IntegerStringKind,
+	"cosmos.Dec":                    schema.DecimalStringKind,
+}
+
+// DeriveObjectType derives an object type named after the message from the fields of the message
+// descriptor. The fields named in keyFields become the key fields of the object type in the order
+// given and all other fields become value fields in declaration order. See DeriveField for how
+// fields are mapped. The derived object type is validated before it is returned.
+func DeriveObjectType(desc protoreflect.MessageDescriptor, keyFields ...string) (schema.ObjectType, error) {
+	res := schema.ObjectType{Name: string(desc.Name())}
+
+	isKey := map[protoreflect.Name]bool{}
+	for _, name := range keyFields {
+		fd := desc.Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			return schema.ObjectType{}, fmt.Errorf("key field %q not found in message %s", name, desc.FullName())
+		}
+
+		field, err := DeriveField(fd)
+		if err != nil {
+			return schema.ObjectType{}, err
+		}
+
+		// key fields are always present
+		field.Nullable = false
+		res.KeyFields = append(res.KeyFields, field)
+		isKey[fd.Name()] = true
+	}
+
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if isKey[fd.Name()] {
+			continue
+		}
+
+		field, err := DeriveField(fd)
+		if err != nil {
+			return schema.ObjectType{}, err
+		}
+		res.ValueFields = append(res.ValueFields, field)
+	}
+
+	if err := res.Validate(); err != nil {
+		return schema.ObjectType{}, fmt.Errorf("invalid object type derived from %s: %w", desc.FullName(), err)
+	}
+
+	return res, nil
+}
+
+// DeriveField derives a schema field from a protobuf field descriptor. Fields are mapped as follows:
+//   - scalar types map to the kind with the same go type, ex. sint64 and sfixed64 map to Int64Kind
+//   - string and bytes fields annotated with a cosmos_proto.scalar address type map to AddressKind and
+//     those annotated with cosmos.Int and cosmos.Dec map to IntegerStringKind and DecimalStringKind
+//   - enums map to EnumKind with an enum type named after the protobuf enum whose numeric values are
+//     the protobuf enum numbers
+//   - google.protobuf.Timestamp and google.protobuf.Duration map to TimeKind and DurationKind
+//   - google.protobuf.Any, google.protobuf.Struct and google.protobuf.Value map to JSONKind
+//   - other messages map to StructKind with a struct type named after the message
+//   - repeated fields map to ListKind and map fields map to MapKind
+//
+// Fields with presence, such as message fields and proto3 optional fields, are nullable.
+func DeriveField(fd protoreflect.FieldDescriptor) (schema.Field, error) {
+	return deriveField(fd, map[protoreflect.FullName]bool{})
+}
+
+// deriveField derives a schema field, using visiting to detect recursive message types.
+func deriveField(fd protoreflect.FieldDescriptor, visiting map[protoreflect.FullName]bool) (schema.Field, error) {
+	field := schema.Field{Name: string(fd.Name())}
+
+	switch {
+	case fd.IsMap():
+		keyKind, err := deriveKind(&field, fd.MapKey(), visiting)
+		if err != nil {
+			return schema.Field{}, err
+		}
+
+		valueKind, err := deriveKind(&field, fd.MapValue(), visiting)
+		if err != nil {
+			return schema.Field{}, err
+		}
+
+		field.Kind = schema.MapKind
+		field.KeyKind = keyKind
+		field.ValueKind = valueKind
+	case fd.IsList():
+		elementKind, err := deriveKind(&field, fd, visiting)
+		if err != nil {
+			return schema.Field{}, err
+		}
+
+		field.Kind = schema.ListKind
+		field.ElementKind = elementKind
+	default:
+		kind, err := deriveKind(&field, fd, visiting)
+		if err != nil {
+			return schema.Field{}, err
+		}
+
+		field.Kind = kind
+		field.Nullable = fd.HasPresence()
+	}
+
+	return field, nil
+}
+
+// deriveKind returns the kind of the values of the field descriptor ignoring whether it is repeated and
+// sets the enum or struct type on field if they are referenced.
+func deriveKind(field *schema.Field, fd protoreflect.FieldDescriptor, visiting map[protoreflect.FullName]bool) (schema.Kind, error) {
+	if scalar, ok := proto.GetExtension(fd.Options(), cosmos_proto.E_Scalar).(string); ok && scalar != "" {
+		if kind, ok := scalarKinds[scalar]; ok &&
+			(fd.Kind() == protoreflect.StringKind || fd.Kind() == protoreflect.BytesKind) {
+			return kind, nil
+		}
+	}
+
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return schema.StringKind, nil
+	case protoreflect.BytesKind:
+		return schema.BytesKind, nil
+	case protoreflect.BoolKind:
+		return schema.BoolKind, nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return schema.Int32Kind, nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return schema.Uint32Kind, nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return schema.Int64Kind, nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return schema.Uint64Kind, nil
+	case protoreflect.FloatKind:
+		return schema.Float32Kind, nil
+	case protoreflect.DoubleKind:
+		return schema.Float64Kind, nil
+	case protoreflect.EnumKind:
+		field.EnumType = EnumTypeFromProtoEnum(fd.Enum())
+		return schema.EnumKind, nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return deriveMessageKind(field, fd.Message(), visiting)
+	default:
+		return schema.InvalidKind, fmt.Errorf("unsupported protobuf kind %s for field %s", fd.Kind(), fd.FullName())
+	}
+}
+
+func deriveMessageKind(field *schema.Field, desc protoreflect.MessageDescriptor, visiting map[protoreflect.FullName]bool) (schema.Kind, error) {
+	switch desc.FullName() {
+	case timestampFullName:
+		return schema.TimeKind, nil
+	case durationFullName:
+		return schema.DurationKind, nil
+	case anyFullName, structFullName, valueFullName:
+		return schema.JSONKind, nil
+	}
+
+	if visiting[desc.FullName()] {
+		return schema.InvalidKind, fmt.Errorf("recursive message %s is not supported", desc.FullName())
+	}
+	visiting[desc.FullName()] = true
+	defer delete(visiting, desc.FullName())
+
+	structType := schema.StructType{Name: string(desc.Name())}
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		nested, err := deriveField(fields.Get(i), visiting)
+		if err != nil {
+			return schema.InvalidKind, err
+		}
+		structType.Fields = append(structType.Fields, nested)
+	}
+
+	field.StructType = structType
+	return schema.StructKind, nil
+}
+
+// EnumTypeFromProtoEnum returns the enum type of a protobuf enum descriptor, named after the enum.
+// The values of the enum type are the names of the protobuf enum values in declaration order and its
+// numeric values are their numbers. Aliases, which share the number of a previous value, are skipped
+// so that every number maps to a single name.
+func EnumTypeFromProtoEnum(desc protoreflect.EnumDescriptor) schema.EnumType {
+	res := schema.EnumType{Name: string(desc.Name())}
+	values := desc.Values()
+	seen := make(map[protoreflect.EnumNumber]bool, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		value := values.Get(i)
+		if seen[value.Number()] {
+			continue
+		}
+		seen[value.Number()] = true
+		res.Values = append(res.Values, string(value.Name()))
+		res.NumericValues = append(res.NumericValues, int32(value.Number()))
+	}
+	return res
+}
+
+// EnumValueFromProto returns the value of the enum type for a protobuf enum number.
+func EnumValueFromProto(enumType schema.EnumType, number protoreflect.EnumNumber) (string, error) {
+	value, ok := enumType.ValueForNumber(int32(number))
+	if !ok {
+		return "", fmt.Errorf("%d is not a number of enum %s", number, enumType.Name)
+	}
+	return value, nil
+}
+
+// EnumValueToProto returns the protobuf enum number of a value of the enum type.
+func EnumValueToProto(enumType schema.EnumType, value string) (protoreflect.EnumNumber, error) {
+	number, ok := enumType.NumericValue(value)
+	if !ok {
+		return 0, fmt.Errorf("value %q is not a valid enum value for %s", value, enumType.Name)
+	}
+	return protoreflect.EnumNumber(number), nil
+}
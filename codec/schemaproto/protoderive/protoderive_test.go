@@ -0,0 +1,109 @@
+package protoderive_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	stakingv1beta1 "cosmossdk.io/api/cosmos/staking/v1beta1"
+	"cosmossdk.io/schema"
+
+	"github.com/cosmos/cosmos-sdk/codec/schemaproto/protoderive"
+)
+
+func TestDeriveObjectType(t *testing.T) {
+	desc := (&stakingv1beta1.Validator{}).ProtoReflect().Descriptor()
+	objectType, err := protoderive.DeriveObjectType(desc, "operator_address")
+	require.NoError(t, err)
+
+	require.Equal(t, "Validator", objectType.Name)
+	require.Equal(t, []schema.Field{{Name: "operator_address", Kind: schema.AddressKind}}, objectType.KeyFields)
+
+	fields := map[string]schema.Field{}
+	var names []string
+	for _, field := range objectType.ValueFields {
+		fields[field.Name] = field
+		names = append(names, field.Name)
+	}
+	require.Equal(t, []string{
+		"consensus_pubkey", "jailed", "status", "tokens", "delegator_shares", "description", "unbonding_height",
+		"unbonding_time", "commission", "min_self_delegation", "unbonding_on_hold_ref_count", "unbonding_ids",
+	}, names)
+
+	require.Equal(t, schema.Field{Name: "consensus_pubkey", Kind: schema.JSONKind, Nullable: true}, fields["consensus_pubkey"])
+	require.Equal(t, schema.Field{Name: "jailed", Kind: schema.BoolKind}, fields["jailed"])
+	require.Equal(t, schema.Field{
+		Name: "status",
+		Kind: schema.EnumKind,
+		EnumType: schema.EnumType{
+			Name:          "BondStatus",
+			Values:        []string{"BOND_STATUS_UNSPECIFIED", "BOND_STATUS_UNBONDED", "BOND_STATUS_UNBONDING", "BOND_STATUS_BONDED"},
+			NumericValues: []int32{0, 1, 2, 3},
+		},
+	}, fields["status"])
+	require.Equal(t, schema.IntegerStringKind, fields["tokens"].Kind)
+	require.Equal(t, schema.DecimalStringKind, fields["delegator_shares"].Kind)
+	require.Equal(t, schema.Int64Kind, fields["unbonding_height"].Kind)
+	require.Equal(t, schema.Field{Name: "unbonding_time", Kind: schema.TimeKind, Nullable: true}, fields["unbonding_time"])
+	require.Equal(t, schema.Field{Name: "unbonding_ids", Kind: schema.ListKind, ElementKind: schema.Uint64Kind}, fields["unbonding_ids"])
+
+	commission := fields["commission"]
+	require.Equal(t, schema.StructKind, commission.Kind)
+	require.True(t, commission.Nullable)
+	require.Equal(t, "Commission", commission.StructType.Name)
+	require.Equal(t, "commission_rates", commission.StructType.Fields[0].Name)
+	rates := commission.StructType.Fields[0].StructType
+	require.Equal(t, "CommissionRates", rates.Name)
+	require.Equal(t, schema.DecimalStringKind, rates.Fields[0].Kind)
+	require.Equal(t, schema.TimeKind, commission.StructType.Fields[1].Kind)
+}
+
+func TestDeriveField(t *testing.T) {
+	desc := (&structpb.Struct{}).ProtoReflect().Descriptor()
+	field, err := protoderive.DeriveField(desc.Fields().ByName("fields"))
+	require.NoError(t, err)
+	require.Equal(t, schema.Field{
+		Name:      "fields",
+		Kind:      schema.MapKind,
+		KeyKind:   schema.StringKind,
+		ValueKind: schema.JSONKind,
+	}, field)
+
+	// google.protobuf.ListValue contains google.protobuf.Value which is mapped to JSON, so it is not recursive
+	listDesc := (&structpb.ListValue{}).ProtoReflect().Descriptor()
+	field, err = protoderive.DeriveField(listDesc.Fields().ByName("values"))
+	require.NoError(t, err)
+	require.Equal(t, schema.Field{Name: "values", Kind: schema.ListKind, ElementKind: schema.JSONKind}, field)
+}
+
+func TestDeriveObjectType_Errors(t *testing.T) {
+	desc := (&stakingv1beta1.Validator{}).ProtoReflect().Descriptor()
+	_, err := protoderive.DeriveObjectType(desc, "foo")
+	require.ErrorContains(t, err, "key field \"foo\" not found in message cosmos.staking.v1beta1.Validator")
+
+	_, err = protoderive.DeriveObjectType(desc, "unbonding_ids")
+	require.ErrorContains(t, err, "invalid object type derived from cosmos.staking.v1beta1.Validator")
+}
+
+func TestEnumTypeFromProtoEnum(t *testing.T) {
+	enumType := protoderive.EnumTypeFromProtoEnum(stakingv1beta1.BondStatus(0).Descriptor())
+	require.NoError(t, enumType.Validate())
+	require.Equal(t, schema.EnumType{
+		Name:          "BondStatus",
+		Values:        []string{"BOND_STATUS_UNSPECIFIED", "BOND_STATUS_UNBONDED", "BOND_STATUS_UNBONDING", "BOND_STATUS_BONDED"},
+		NumericValues: []int32{0, 1, 2, 3},
+	}, enumType)
+
+	value, err := protoderive.EnumValueFromProto(enumType, stakingv1beta1.BondStatus_BOND_STATUS_BONDED.Number())
+	require.NoError(t, err)
+	require.Equal(t, "BOND_STATUS_BONDED", value)
+	_, err = protoderive.EnumValueFromProto(enumType, 7)
+	require.ErrorContains(t, err, "7 is not a number of enum BondStatus")
+
+	number, err := protoderive.EnumValueToProto(enumType, "BOND_STATUS_UNBONDING")
+	require.NoError(t, err)
+	require.Equal(t, stakingv1beta1.BondStatus_BOND_STATUS_UNBONDING.Number(), number)
+	_, err = protoderive.EnumValueToProto(enumType, "BOND_STATUS_JAILED")
+	require.Error(t, err)
+}
@@ -0,0 +1,369 @@
+// Package schemaproto converts between cosmossdk.io/schema module schemas and their
+// protobuf representation defined in cosmos/schema/v1/schema.proto, and between app data
+// packets and the packets of cosmos/indexer/v1 which are sent to remote indexers.
+package schemaproto
+
+import (
+	"fmt"
+
+	schemav1 "cosmossdk.io/api/cosmos/schema/v1"
+	"cosmossdk.io/schema"
+)
+
+// ModuleSchemaToProto converts a module schema to its protobuf representation.
+// Object types are sorted by name so that the output is deterministic.
+func ModuleSchemaToProto(moduleSchema schema.ModuleSchema) *schemav1.ModuleSchema {
+	res := &schemav1.ModuleSchema{}
+	moduleSchema.ObjectTypes(func(objectType schema.ObjectType) bool {
+		res.ObjectTypes = append(res.ObjectTypes, ObjectTypeToProto(objectType))
+		return true
+	})
+	moduleSchema.EventTypes(func(eventType schema.EventType) bool {
+		res.EventTypes = append(res.EventTypes, EventTypeToProto(eventType))
+		return true
+	})
+	res.ReservedTypeNames = moduleSchema.ReservedTypeNames()
+	res.Version = moduleSchema.Version()
+	return res
+}
+
+// ModuleSchemaFromProto converts a protobuf module schema to a schema.ModuleSchema
+// and validates it.
+func ModuleSchemaFromProto(moduleSchema *schemav1.ModuleSchema) (schema.ModuleSchema, error) {
+	objectTypes := make([]schema.ObjectType, len(moduleSchema.GetObjectTypes()))
+	for i, objectType := range moduleSchema.GetObjectTypes() {
+		objectTypes[i] = ObjectTypeFromProto(objectType)
+	}
+
+	res, err := schema.NewModuleSchema(objectTypes)
+	if err != nil {
+		return schema.ModuleSchema{}, fmt.Errorf("invalid module schema: %w", err)
+	}
+
+	if len(moduleSchema.GetEventTypes()) != 0 {
+		eventTypes := make([]schema.EventType, len(moduleSchema.GetEventTypes()))
+		for i, eventType := range moduleSchema.GetEventTypes() {
+			eventTypes[i] = EventTypeFromProto(eventType)
+		}
+
+		res, err = res.WithEventTypes(eventTypes...)
+		if err != nil {
+			return schema.ModuleSchema{}, fmt.Errorf("invalid module schema: %w", err)
+		}
+	}
+
+	if len(moduleSchema.GetReservedTypeNames()) != 0 {
+		res, err = res.WithReservedTypeNames(moduleSchema.GetReservedTypeNames()...)
+		if err != nil {
+			return schema.ModuleSchema{}, fmt.Errorf("invalid module schema: %w", err)
+		}
+	}
+
+	return res.WithVersion(moduleSchema.GetVersion()), nil
+}
+
+// ObjectTypeToProto converts an object type to its protobuf representation.
+func ObjectTypeToProto(objectType schema.ObjectType) *schemav1.ObjectType {
+	res := &schemav1.ObjectType{
+		Name:            objectType.Name,
+		KeyFields:       fieldsToProto(objectType.KeyFields),
+		ValueFields:     fieldsToProto(objectType.ValueFields),
+		RetainDeletions: objectType.RetainDeletions,
+		Description:     objectType.Description,
+		Metadata:        copyMetadata(objectType.Metadata),
+	}
+
+	if len(objectType.ReservedFieldNames) != 0 {
+		res.ReservedFieldNames = append([]string(nil), objectType.ReservedFieldNames...)
+	}
+
+	for _, constraint := range objectType.UniqueConstraints {
+		res.UniqueConstraints = append(res.UniqueConstraints, &schemav1.UniqueConstraint{
+			FieldNames: append([]string(nil), constraint...),
+		})
+	}
+
+	for _, index := range objectType.Indexes {
+		res.Indexes = append(res.Indexes, IndexDescriptorToProto(index))
+	}
+
+	if objectType.Retention != nil {
+		res.Retention = &schemav1.RetentionPolicy{
+			Mode:   schemav1.RetentionMode(objectType.Retention.Mode),
+			Blocks: objectType.Retention.Blocks,
+		}
+	}
+
+	return res
+}
+
+// ObjectTypeFromProto converts a protobuf object type to a schema.ObjectType.
+// The result is not validated.
+func ObjectTypeFromProto(objectType *schemav1.ObjectType) schema.ObjectType {
+	res := schema.ObjectType{
+		Name:            objectType.GetName(),
+		KeyFields:       fieldsFromProto(objectType.GetKeyFields()),
+		ValueFields:     fieldsFromProto(objectType.GetValueFields()),
+		RetainDeletions: objectType.GetRetainDeletions(),
+		Description:     objectType.GetDescription(),
+		Metadata:        copyMetadata(objectType.GetMetadata()),
+	}
+
+	if len(objectType.GetReservedFieldNames()) != 0 {
+		res.ReservedFieldNames = append([]string(nil), objectType.GetReservedFieldNames()...)
+	}
+
+	for _, constraint := range objectType.GetUniqueConstraints() {
+		res.UniqueConstraints = append(res.UniqueConstraints, append([]string(nil), constraint.GetFieldNames()...))
+	}
+
+	for _, index := range objectType.GetIndexes() {
+		res.Indexes = append(res.Indexes, IndexDescriptorFromProto(index))
+	}
+
+	if retention := objectType.GetRetention(); retention != nil {
+		res.Retention = &schema.RetentionPolicy{
+			Mode:   schema.RetentionMode(retention.GetMode()),
+			Blocks: retention.GetBlocks(),
+		}
+	}
+
+	return res
+}
+
+// IndexDescriptorToProto converts an index descriptor to its protobuf representation.
+func IndexDescriptorToProto(index schema.IndexDescriptor) *schemav1.IndexDescriptor {
+	res := &schemav1.IndexDescriptor{Name: index.Name}
+	for _, field := range index.Fields {
+		res.Fields = append(res.Fields, &schemav1.IndexField{Name: field.Name, Descending: field.Descending})
+	}
+	return res
+}
+
+// IndexDescriptorFromProto converts a protobuf index descriptor to a schema.IndexDescriptor.
+// The result is not validated.
+func IndexDescriptorFromProto(index *schemav1.IndexDescriptor) schema.IndexDescriptor {
+	res := schema.IndexDescriptor{Name: index.GetName()}
+	for _, field := range index.GetFields() {
+		res.Fields = append(res.Fields, schema.IndexField{Name: field.GetName(), Descending: field.GetDescending()})
+	}
+	return res
+}
+
+// FieldToProto converts a field to its protobuf representation. The enum, struct or oneof
+// definition is only included if the field, its list elements or its map values
+// reference an enum, struct or oneof type. Default values have no protobuf representation
+// and are not included.
+func FieldToProto(field schema.Field) *schemav1.Field {
+	res := &schemav1.Field{
+		Name:             field.Name,
+		Kind:             schemav1.Kind(field.Kind),
+		Nullable:         field.Nullable,
+		NullableElements: field.NullableElements,
+		ElementKind:      schemav1.Kind(field.ElementKind),
+		KeyKind:          schemav1.Kind(field.KeyKind),
+		ValueKind:        schemav1.Kind(field.ValueKind),
+		Precision:        field.Precision,
+		Scale:            field.Scale,
+		TimeResolution:   schemav1.TimeResolution(field.TimeResolution),
+		AddressPrefix:    field.AddressPrefix,
+		Description:      field.Description,
+		Metadata:         copyMetadata(field.Metadata),
+	}
+
+	if !field.Constraints.IsZero() {
+		res.Constraints = FieldConstraintsToProto(field.Constraints)
+	}
+
+	switch referencedKind(field) {
+	case schema.EnumKind:
+		res.EnumType = EnumTypeToProto(field.EnumType)
+	case schema.StructKind:
+		res.StructType = StructTypeToProto(field.StructType)
+	case schema.OneOfKind:
+		res.OneofType = OneOfTypeToProto(field.OneOfType)
+	}
+
+	return res
+}
+
+// FieldFromProto converts a protobuf field to a schema.Field. The result is not validated.
+func FieldFromProto(field *schemav1.Field) schema.Field {
+	res := schema.Field{
+		Name:             field.GetName(),
+		Kind:             schema.Kind(field.GetKind()),
+		Nullable:         field.GetNullable(),
+		NullableElements: field.GetNullableElements(),
+		ElementKind:      schema.Kind(field.GetElementKind()),
+		KeyKind:          schema.Kind(field.GetKeyKind()),
+		ValueKind:        schema.Kind(field.GetValueKind()),
+		Precision:        field.GetPrecision(),
+		Scale:            field.GetScale(),
+		TimeResolution:   schema.TimeResolution(field.GetTimeResolution()),
+		AddressPrefix:    field.GetAddressPrefix(),
+		Description:      field.GetDescription(),
+		Metadata:         copyMetadata(field.GetMetadata()),
+	}
+
+	if field.GetEnumType() != nil {
+		res.EnumType = EnumTypeFromProto(field.GetEnumType())
+	}
+
+	if field.GetStructType() != nil {
+		res.StructType = StructTypeFromProto(field.GetStructType())
+	}
+
+	if field.GetOneofType() != nil {
+		res.OneOfType = OneOfTypeFromProto(field.GetOneofType())
+	}
+
+	if field.GetConstraints() != nil {
+		res.Constraints = FieldConstraintsFromProto(field.GetConstraints())
+	}
+
+	return res
+}
+
+// FieldConstraintsToProto converts field constraints to their protobuf representation.
+func FieldConstraintsToProto(constraints schema.FieldConstraints) *schemav1.FieldConstraints {
+	return &schemav1.FieldConstraints{
+		Min:       constraints.Min,
+		Max:       constraints.Max,
+		MaxLength: constraints.MaxLength,
+		Pattern:   constraints.Pattern,
+		Size:      constraints.Size,
+	}
+}
+
+// FieldConstraintsFromProto converts protobuf field constraints to a schema.FieldConstraints. The result is
+// not validated.
+func FieldConstraintsFromProto(constraints *schemav1.FieldConstraints) schema.FieldConstraints {
+	return schema.FieldConstraints{
+		Min:       constraints.GetMin(),
+		Max:       constraints.GetMax(),
+		MaxLength: constraints.GetMaxLength(),
+		Pattern:   constraints.GetPattern(),
+		Size:      constraints.GetSize(),
+	}
+}
+
+// EnumTypeToProto converts an enum type to its protobuf representation.
+func EnumTypeToProto(enumType schema.EnumType) *schemav1.EnumType {
+	return &schemav1.EnumType{
+		Name:          enumType.Name,
+		Values:        append([]string(nil), enumType.Values...),
+		NumericValues: append([]int32(nil), enumType.NumericValues...),
+		Description:   enumType.Description,
+		Metadata:      copyMetadata(enumType.Metadata),
+	}
+}
+
+// EnumTypeFromProto converts a protobuf enum type to a schema.EnumType. The result is not validated.
+func EnumTypeFromProto(enumType *schemav1.EnumType) schema.EnumType {
+	return schema.EnumType{
+		Name:          enumType.GetName(),
+		Values:        append([]string(nil), enumType.GetValues()...),
+		NumericValues: append([]int32(nil), enumType.GetNumericValues()...),
+		Description:   enumType.GetDescription(),
+		Metadata:      copyMetadata(enumType.GetMetadata()),
+	}
+}
+
+// StructTypeToProto converts a struct type to its protobuf representation.
+func StructTypeToProto(structType schema.StructType) *schemav1.StructType {
+	return &schemav1.StructType{
+		Name:   structType.Name,
+		Fields: fieldsToProto(structType.Fields),
+	}
+}
+
+// StructTypeFromProto converts a protobuf struct type to a schema.StructType. The result is not validated.
+func StructTypeFromProto(structType *schemav1.StructType) schema.StructType {
+	return schema.StructType{
+		Name:   structType.GetName(),
+		Fields: fieldsFromProto(structType.GetFields()),
+	}
+}
+
+// OneOfTypeToProto converts a oneof type to its protobuf representation.
+func OneOfTypeToProto(oneOfType schema.OneOfType) *schemav1.OneOfType {
+	return &schemav1.OneOfType{
+		Name:  oneOfType.Name,
+		Cases: fieldsToProto(oneOfType.Cases),
+	}
+}
+
+// OneOfTypeFromProto converts a protobuf oneof type to a schema.OneOfType. The result is not validated.
+func OneOfTypeFromProto(oneOfType *schemav1.OneOfType) schema.OneOfType {
+	return schema.OneOfType{
+		Name:  oneOfType.GetName(),
+		Cases: fieldsFromProto(oneOfType.GetCases()),
+	}
+}
+
+// EventTypeToProto converts an event type to its protobuf representation.
+func EventTypeToProto(eventType schema.EventType) *schemav1.EventType {
+	return &schemav1.EventType{
+		Name:        eventType.Name,
+		Fields:      fieldsToProto(eventType.Fields),
+		Description: eventType.Description,
+	}
+}
+
+// EventTypeFromProto converts a protobuf event type to a schema.EventType. The result is not validated.
+func EventTypeFromProto(eventType *schemav1.EventType) schema.EventType {
+	return schema.EventType{
+		Name:        eventType.GetName(),
+		Fields:      fieldsFromProto(eventType.GetFields()),
+		Description: eventType.GetDescription(),
+	}
+}
+
+func fieldsToProto(fields []schema.Field) []*schemav1.Field {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	res := make([]*schemav1.Field, len(fields))
+	for i, field := range fields {
+		res[i] = FieldToProto(field)
+	}
+	return res
+}
+
+func fieldsFromProto(fields []*schemav1.Field) []schema.Field {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	res := make([]schema.Field, len(fields))
+	for i, field := range fields {
+		res[i] = FieldFromProto(field)
+	}
+	return res
+}
+
+// referencedKind returns the kind which determines whether the field references an
+// enum, struct or oneof type, taking list elements and map values into account.
+func referencedKind(field schema.Field) schema.Kind {
+	switch field.Kind {
+	case schema.ListKind:
+		return field.ElementKind
+	case schema.MapKind:
+		return field.ValueKind
+	default:
+		return field.Kind
+	}
+}
+
+func copyMetadata(metadata map[string]string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	res := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		res[k] = v
+	}
+	return res
+}
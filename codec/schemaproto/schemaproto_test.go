@@ -0,0 +1,152 @@
+package schemaproto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	schemav1 "cosmossdk.io/api/cosmos/schema/v1"
+	"cosmossdk.io/schema"
+
+	"github.com/cosmos/cosmos-sdk/codec/schemaproto"
+)
+
+func TestModuleSchemaRoundTrip(t *testing.T) {
+	statusEnum := schema.EnumType{
+		Name:          "status",
+		Values:        []string{"active", "inactive"},
+		NumericValues: []int32{0, 2},
+		Description:   "the status of an account",
+	}
+	pointStruct := schema.StructType{
+		Name: "point",
+		Fields: []schema.Field{
+			{Name: "x", Kind: schema.Int32Kind},
+			{Name: "y", Kind: schema.Int32Kind},
+		},
+	}
+
+	moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{
+		{
+			Name: "shapes",
+			KeyFields: []schema.Field{
+				{Name: "id", Kind: schema.Uint64Kind},
+			},
+			ValueFields: []schema.Field{
+				{Name: "origin", Kind: schema.StructKind, StructType: pointStruct},
+				{Name: "vertices", Kind: schema.ListKind, ElementKind: schema.StructKind, StructType: pointStruct},
+				{Name: "labels", Kind: schema.MapKind, KeyKind: schema.StringKind, ValueKind: schema.StringKind, Nullable: true, NullableElements: true},
+				{Name: "anchor", Kind: schema.OneOfKind, Nullable: true, OneOfType: schema.OneOfType{
+					Name: "anchor",
+					Cases: []schema.Field{
+						{Name: "point", Kind: schema.StructKind, StructType: pointStruct},
+						{Name: "label", Kind: schema.StringKind},
+					},
+				}},
+			},
+			RetainDeletions: true,
+		},
+		{
+			Name: "accounts",
+			KeyFields: []schema.Field{
+				{Name: "address", Kind: schema.AddressKind, Description: "the account address", Constraints: schema.FieldConstraints{Size: 20}, AddressPrefix: "cosmos"},
+			},
+			ValueFields: []schema.Field{
+				{Name: "status", Kind: schema.EnumKind, EnumType: statusEnum},
+				{Name: "balance", Kind: schema.IntegerStringKind, Nullable: true, Constraints: schema.FieldConstraints{Min: "0", Max: "1000000"}},
+				{Name: "nickname", Kind: schema.StringKind, Constraints: schema.FieldConstraints{MaxLength: 32, Pattern: "^[a-z]+$"}},
+				{Name: "rate", Kind: schema.DecimalStringKind, Precision: 36, Scale: 18},
+				{Name: "created", Kind: schema.TimeKind, TimeResolution: schema.TimeResolutionMillis},
+			},
+			UniqueConstraints: [][]string{{"status", "balance"}},
+			Retention:         &schema.RetentionPolicy{Mode: schema.RetentionKeepBlocks, Blocks: 1000},
+			Description:       "accounts by address",
+			Metadata:          map[string]string{"source": "auth"},
+			Indexes: []schema.IndexDescriptor{
+				{Name: "by_status_balance", Fields: []schema.IndexField{{Name: "status"}, {Name: "balance", Descending: true}}},
+			},
+			ReservedFieldNames: []string{"sequence"},
+		},
+	})
+	require.NoError(t, err)
+	moduleSchema, err = moduleSchema.WithEventTypes(schema.EventType{
+		Name: "transfer",
+		Fields: []schema.Field{
+			{Name: "recipient", Kind: schema.AddressKind, AddressPrefix: "cosmos"},
+			{Name: "status", Kind: schema.EnumKind, EnumType: statusEnum},
+			{Name: "memo", Kind: schema.StringKind, Nullable: true},
+		},
+		Description: "a coin transfer",
+	})
+	require.NoError(t, err)
+	moduleSchema, err = moduleSchema.WithReservedTypeNames("params")
+	require.NoError(t, err)
+	moduleSchema = moduleSchema.WithVersion(3)
+
+	protoSchema := schemaproto.ModuleSchemaToProto(moduleSchema)
+	require.Equal(t, uint64(3), protoSchema.Version)
+	require.Len(t, protoSchema.ObjectTypes, 2)
+	require.Equal(t, "accounts", protoSchema.ObjectTypes[0].Name)
+	require.Equal(t, "shapes", protoSchema.ObjectTypes[1].Name)
+	require.Equal(t, schemav1.Kind_KIND_ENUM, protoSchema.ObjectTypes[0].ValueFields[0].Kind)
+	require.Nil(t, protoSchema.ObjectTypes[0].ValueFields[1].EnumType)
+	require.Equal(t, "point", protoSchema.ObjectTypes[1].ValueFields[1].StructType.Name)
+	require.Equal(t, "anchor", protoSchema.ObjectTypes[1].ValueFields[3].OneofType.Name)
+	require.Equal(t, "1000000", protoSchema.ObjectTypes[0].ValueFields[1].Constraints.Max)
+	require.Nil(t, protoSchema.ObjectTypes[0].ValueFields[0].Constraints)
+	require.Equal(t, "cosmos", protoSchema.ObjectTypes[0].KeyFields[0].AddressPrefix)
+
+	bz, err := proto.Marshal(protoSchema)
+	require.NoError(t, err)
+	var decoded schemav1.ModuleSchema
+	require.NoError(t, proto.Unmarshal(bz, &decoded))
+
+	res, err := schemaproto.ModuleSchemaFromProto(&decoded)
+	require.NoError(t, err)
+
+	expectedJSON, err := moduleSchema.MarshalJSON()
+	require.NoError(t, err)
+	actualJSON, err := res.MarshalJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedJSON), string(actualJSON))
+	require.Equal(t, moduleSchema.Fingerprint(), res.Fingerprint())
+}
+
+func TestModuleSchemaFromProto_Invalid(t *testing.T) {
+	_, err := schemaproto.ModuleSchemaFromProto(&schemav1.ModuleSchema{
+		ObjectTypes: []*schemav1.ObjectType{
+			{
+				Name: "object1",
+				KeyFields: []*schemav1.Field{
+					{Name: "k", Kind: schemav1.Kind_KIND_LIST, ElementKind: schemav1.Kind_KIND_STRING},
+				},
+			},
+		},
+	})
+	require.ErrorContains(t, err, "invalid module schema")
+}
+
+func TestKindValues(t *testing.T) {
+	for kind := schema.InvalidKind; kind <= schema.MAX_VALID_KIND; kind++ {
+		_, ok := schemav1.Kind_name[int32(kind)]
+		require.True(t, ok, "missing protobuf kind for %s", kind)
+	}
+	require.Len(t, schemav1.Kind_name, int(schema.MAX_VALID_KIND)+1)
+}
+
+func TestTimeResolutionValues(t *testing.T) {
+	for res := schema.TimeResolutionNanos; res <= schema.TimeResolutionSeconds; res++ {
+		_, ok := schemav1.TimeResolution_name[int32(res)]
+		require.True(t, ok, "missing protobuf time resolution for %s", res)
+	}
+	require.Len(t, schemav1.TimeResolution_name, int(schema.TimeResolutionSeconds)+1)
+}
+
+func TestRetentionModeValues(t *testing.T) {
+	for mode := schema.RetentionKeepForever; mode <= schema.RetentionKeepBlocks; mode++ {
+		_, ok := schemav1.RetentionMode_name[int32(mode)]
+		require.True(t, ok, "missing protobuf retention mode for %s", mode)
+	}
+	require.Len(t, schemav1.RetentionMode_name, int(schema.RetentionKeepBlocks)+1)
+}
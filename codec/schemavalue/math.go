@@ -0,0 +1,126 @@
+package schemavalue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	collcodec "cosmossdk.io/collections/codec"
+	"cosmossdk.io/math"
+	"cosmossdk.io/schema"
+)
+
+// IntString renders an integer as the value of an IntegerStringKind field. Nil integers, which are
+// encoded like zero, are rendered as zero.
+func IntString(i math.Int) string {
+	if i.IsNil() {
+		return "0"
+	}
+	return i.String()
+}
+
+// DecString renders a decimal as the value of a DecimalStringKind field. Nil decimals, which are
+// encoded like zero, are rendered as zero. Decimals with more than 50 digits before the decimal point
+// are rendered as invalid values, see FormatDec.
+func DecString(d math.LegacyDec) string {
+	if d.IsNil() {
+		return math.LegacyZeroDec().String()
+	}
+	return d.String()
+}
+
+// FormatInt renders an integer like IntString and checks that the result is a valid IntegerStringKind
+// value.
+func FormatInt(i math.Int) (string, error) {
+	s := IntString(i)
+	if err := schema.IntegerStringKind.ValidateValue(s); err != nil {
+		return "", fmt.Errorf("%w: integer %s can't be represented as an integer string: %w", collcodec.ErrEncoding, s, err)
+	}
+	return s, nil
+}
+
+// FormatDec renders a decimal like DecString and checks that the result is a valid DecimalStringKind
+// value, which isn't the case for decimals with more than 50 digits before the decimal point.
+func FormatDec(d math.LegacyDec) (string, error) {
+	s := DecString(d)
+	if err := schema.DecimalStringKind.ValidateValue(s); err != nil {
+		return "", fmt.Errorf("%w: decimal %s can't be represented as a decimal string: %w", collcodec.ErrEncoding, s, err)
+	}
+	return s, nil
+}
+
+// ParseInt parses the value of an IntegerStringKind field. It fails if the value isn't a valid
+// integer string or doesn't fit in the 256 bits of math.Int.
+func ParseInt(s string) (math.Int, error) {
+	if err := schema.IntegerStringKind.ValidateValue(s); err != nil {
+		return math.Int{}, fmt.Errorf("%w: invalid integer %q: %w", collcodec.ErrEncoding, s, err)
+	}
+	i, ok := math.NewIntFromString(s)
+	if !ok {
+		return math.Int{}, fmt.Errorf("%w: invalid integer %q: out of range", collcodec.ErrEncoding, s)
+	}
+	return i, nil
+}
+
+// ParseDec parses the value of a DecimalStringKind field, including values with an exponent. It fails if
+// the value isn't a valid decimal string, has non-zero digits beyond the 18 decimal places of
+// math.LegacyDec, which would be lost, or is out of the range of math.LegacyDec.
+func ParseDec(s string) (math.LegacyDec, error) {
+	if err := schema.DecimalStringKind.ValidateValue(s); err != nil {
+		return math.LegacyDec{}, fmt.Errorf("%w: invalid decimal %q: %w", collcodec.ErrEncoding, s, err)
+	}
+
+	plain := trimFractionZeros(expandExponent(s))
+	if i := strings.IndexByte(plain, '.'); i >= 0 && len(plain)-i-1 > math.LegacyPrecision {
+		return math.LegacyDec{}, fmt.Errorf("%w: invalid decimal %q: more than %d decimal places", collcodec.ErrEncoding, s, math.LegacyPrecision)
+	}
+
+	d, err := math.LegacyNewDecFromStr(plain)
+	if err != nil {
+		return math.LegacyDec{}, fmt.Errorf("%w: invalid decimal %q: %w", collcodec.ErrEncoding, s, err)
+	}
+	return d, nil
+}
+
+// expandExponent rewrites a decimal string which matches schema.DecimalFormat without its exponent, which
+// has at most 2 digits, by moving the decimal point.
+func expandExponent(s string) string {
+	i := strings.IndexAny(s, "eE")
+	if i < 0 {
+		return s
+	}
+	exp, _ := strconv.Atoi(s[i+1:])
+
+	mantissa := s[:i]
+	sign := ""
+	if strings.HasPrefix(mantissa, "-") {
+		sign, mantissa = "-", mantissa[1:]
+	}
+	intPart, fracPart := mantissa, ""
+	if j := strings.IndexByte(mantissa, '.'); j >= 0 {
+		intPart, fracPart = mantissa[:j], mantissa[j+1:]
+	}
+
+	digits := intPart + fracPart
+	point := len(intPart) + exp
+	if point < 1 {
+		digits = strings.Repeat("0", 1-point) + digits
+		point = 1
+	}
+	if point > len(digits) {
+		digits += strings.Repeat("0", point-len(digits))
+	}
+	if point == len(digits) {
+		return sign + digits
+	}
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// trimFractionZeros removes the trailing zeros after the decimal point of a decimal string without an
+// exponent.
+func trimFractionZeros(s string) string {
+	if !strings.Contains(s, ".") {
+		return s
+	}
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
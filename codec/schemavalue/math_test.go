@@ -0,0 +1,91 @@
+package schemavalue_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	collcodec "cosmossdk.io/collections/codec"
+	"cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/codec/schemavalue"
+)
+
+func TestParseInt(t *testing.T) {
+	i, err := schemavalue.ParseInt("-12345678901234567890")
+	require.NoError(t, err)
+	require.Equal(t, "-12345678901234567890", i.String())
+
+	s, err := schemavalue.FormatInt(i)
+	require.NoError(t, err)
+	require.Equal(t, "-12345678901234567890", s)
+
+	s, err = schemavalue.FormatInt(math.Int{})
+	require.NoError(t, err)
+	require.Equal(t, "0", s)
+
+	_, err = schemavalue.ParseInt("1.5")
+	require.ErrorIs(t, err, collcodec.ErrEncoding)
+	require.ErrorContains(t, err, `invalid integer "1.5"`)
+
+	// integer strings have up to 100 digits but math.Int only has 256 bits
+	_, err = schemavalue.ParseInt(strings.Repeat("9", 78))
+	require.ErrorContains(t, err, "out of range")
+	_, err = schemavalue.ParseInt("115792089237316195423570985008687907853269984665640564039457584007913129639935")
+	require.NoError(t, err)
+}
+
+func TestParseDec(t *testing.T) {
+	tests := []struct {
+		value       string
+		expected    math.LegacyDec
+		errContains string
+	}{
+		{value: "1.5", expected: math.LegacyNewDecWithPrec(15, 1)},
+		{value: "-0.000000000000000001", expected: math.LegacyNewDecWithPrec(-1, 18)},
+		{value: "2.000000000000000000000", expected: math.LegacyNewDec(2)},
+		{value: "1.25e3", expected: math.LegacyNewDec(1250)},
+		{value: "-125E-5", expected: math.LegacyNewDecWithPrec(-125, 5)},
+		{value: "0.5e1", expected: math.LegacyNewDec(5)},
+		{value: "12e-1", expected: math.LegacyNewDecWithPrec(12, 1)},
+		{value: "1e-19", errContains: "more than 18 decimal places"},
+		{value: "0.0000000000000000001", errContains: "more than 18 decimal places"},
+		{value: "1e99", errContains: "out of range"},
+		{value: "1.", errContains: `invalid decimal "1."`},
+		{value: "abc", errContains: `invalid decimal "abc"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			d, err := schemavalue.ParseDec(tt.value)
+			if tt.errContains != "" {
+				require.ErrorIs(t, err, collcodec.ErrEncoding)
+				require.ErrorContains(t, err, tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, tt.expected.Equal(d), "expected %s, got %s", tt.expected, d)
+		})
+	}
+}
+
+func TestFormatDec(t *testing.T) {
+	s, err := schemavalue.FormatDec(math.LegacyNewDecWithPrec(-15, 1))
+	require.NoError(t, err)
+	require.Equal(t, "-1.500000000000000000", s)
+
+	d, err := schemavalue.ParseDec(s)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyNewDecWithPrec(-15, 1), d)
+
+	s, err = schemavalue.FormatDec(math.LegacyDec{})
+	require.NoError(t, err)
+	require.Equal(t, "0.000000000000000000", s)
+
+	// decimal strings have at most 50 digits before the decimal point
+	big, err := math.LegacyNewDecFromStr("1" + strings.Repeat("0", 50))
+	require.NoError(t, err)
+	_, err = schemavalue.FormatDec(big)
+	require.ErrorIs(t, err, collcodec.ErrEncoding)
+	require.ErrorContains(t, err, "can't be represented as a decimal string")
+}
@@ -0,0 +1,274 @@
+package schemavalue
+
+import (
+	"fmt"
+	"time"
+
+	collcodec "cosmossdk.io/collections/codec"
+	"cosmossdk.io/math"
+	"cosmossdk.io/schema"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Reader reads the values of the fields of an object or struct value, or the elements of a list
+// value, in order. Reading a value of an unexpected type records an error, which is reported by Err
+// of the reader and of the readers of enclosing values, and returns the zero value, so that
+// conversions can read all values before checking for errors once.
+type Reader struct {
+	values []interface{}
+	parent *Reader
+	err    error
+}
+
+// NewReader returns a reader of a value of n fields, such as the value of an object type or of a struct.
+func NewReader(value interface{}, n int) (*Reader, error) {
+	values, ok := value.([]interface{})
+	if !ok || len(values) != n {
+		return nil, fmt.Errorf("%w: expected %d values, got %v", collcodec.ErrEncoding, n, value)
+	}
+	return &Reader{values: values}, nil
+}
+
+// Err returns the first error encountered by the reader or the readers of its nested values.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// More reports whether there are values left to read.
+func (r *Reader) More() bool {
+	return len(r.values) != 0
+}
+
+// Next reads the next value as is.
+func (r *Reader) Next() interface{} {
+	if len(r.values) == 0 {
+		r.fail(fmt.Errorf("%w: missing value", collcodec.ErrEncoding))
+		return nil
+	}
+	v := r.values[0]
+	r.values = r.values[1:]
+	return v
+}
+
+func (r *Reader) fail(err error) {
+	for ; r != nil; r = r.parent {
+		if r.err == nil {
+			r.err = err
+		}
+	}
+}
+
+func (r *Reader) typeError(expected string, value interface{}) {
+	r.fail(fmt.Errorf("%w: expected %s, got %T", collcodec.ErrEncoding, expected, value))
+}
+
+// ReadString reads a StringKind value.
+func (r *Reader) ReadString() string {
+	v := r.Next()
+	s, ok := v.(string)
+	if !ok {
+		r.typeError("string", v)
+	}
+	return s
+}
+
+// ReadBytes reads a BytesKind or AddressKind value.
+func (r *Reader) ReadBytes() []byte {
+	v := r.Next()
+	bz, ok := v.([]byte)
+	if !ok {
+		r.typeError("[]byte", v)
+	}
+	return bz
+}
+
+// ReadBool reads a BoolKind value.
+func (r *Reader) ReadBool() bool {
+	v := r.Next()
+	b, ok := v.(bool)
+	if !ok {
+		r.typeError("bool", v)
+	}
+	return b
+}
+
+// ReadInt32 reads an Int32Kind value.
+func (r *Reader) ReadInt32() int32 {
+	v := r.Next()
+	i, ok := v.(int32)
+	if !ok {
+		r.typeError("int32", v)
+	}
+	return i
+}
+
+// ReadInt64 reads an Int64Kind value.
+func (r *Reader) ReadInt64() int64 {
+	v := r.Next()
+	i, ok := v.(int64)
+	if !ok {
+		r.typeError("int64", v)
+	}
+	return i
+}
+
+// ReadUint32 reads a Uint32Kind value.
+func (r *Reader) ReadUint32() uint32 {
+	v := r.Next()
+	u, ok := v.(uint32)
+	if !ok {
+		r.typeError("uint32", v)
+	}
+	return u
+}
+
+// ReadUint64 reads a Uint64Kind value.
+func (r *Reader) ReadUint64() uint64 {
+	v := r.Next()
+	u, ok := v.(uint64)
+	if !ok {
+		r.typeError("uint64", v)
+	}
+	return u
+}
+
+// ReadTime reads a TimeKind value.
+func (r *Reader) ReadTime() time.Time {
+	v := r.Next()
+	t, ok := v.(time.Time)
+	if !ok {
+		r.typeError("time.Time", v)
+	}
+	return t
+}
+
+// ReadNullableTime reads a nullable TimeKind value, see TimeValue.
+func (r *Reader) ReadNullableTime() *time.Time {
+	v := r.Next()
+	if v == nil {
+		return nil
+	}
+	t, ok := v.(time.Time)
+	if !ok {
+		r.typeError("time.Time", v)
+		return nil
+	}
+	return &t
+}
+
+// ReadDuration reads a DurationKind value.
+func (r *Reader) ReadDuration() time.Duration {
+	v := r.Next()
+	d, ok := v.(time.Duration)
+	if !ok {
+		r.typeError("time.Duration", v)
+	}
+	return d
+}
+
+// ReadInt reads an IntegerStringKind value, see ParseInt.
+func (r *Reader) ReadInt() math.Int {
+	i, err := ParseInt(r.ReadString())
+	if err != nil {
+		r.fail(err)
+		return math.ZeroInt()
+	}
+	return i
+}
+
+// ReadDec reads a DecimalStringKind value, see ParseDec.
+func (r *Reader) ReadDec() math.LegacyDec {
+	d, err := ParseDec(r.ReadString())
+	if err != nil {
+		r.fail(err)
+		return math.LegacyZeroDec()
+	}
+	return d
+}
+
+// ReadEnum reads an EnumKind value of the enum type and returns its numeric value.
+func (r *Reader) ReadEnum(enum schema.EnumType) int32 {
+	s := r.ReadString()
+	n, ok := enum.NumericValue(s)
+	if !ok {
+		r.fail(fmt.Errorf("%w: invalid %s value %q", collcodec.ErrEncoding, enum.Name, s))
+	}
+	return n
+}
+
+// ReadStruct reads a StructKind value of n fields and returns its reader.
+func (r *Reader) ReadStruct(n int) *Reader {
+	v := r.Next()
+	res, err := NewReader(v, n)
+	if err != nil {
+		r.fail(err)
+		return &Reader{values: make([]interface{}, n), err: err}
+	}
+	res.parent = r
+	return res
+}
+
+// ReadNullableStruct reads a nullable StructKind value of n fields and returns its reader, or nil if
+// the value is null.
+func (r *Reader) ReadNullableStruct(n int) *Reader {
+	if len(r.values) != 0 && r.values[0] == nil {
+		r.Next()
+		return nil
+	}
+	return r.ReadStruct(n)
+}
+
+// ReadList reads a ListKind value and returns the reader of its elements.
+func (r *Reader) ReadList() *Reader {
+	v := r.Next()
+	values, ok := v.([]interface{})
+	if !ok {
+		r.typeError("[]interface{}", v)
+	}
+	return &Reader{values: values, parent: r}
+}
+
+// ReadAny reads a nullable AnyStruct value, see AnyValue.
+func (r *Reader) ReadAny() *codectypes.Any {
+	if len(r.values) != 0 && r.values[0] == nil {
+		r.Next()
+		return nil
+	}
+	return r.anyStruct()
+}
+
+// ReadAnys reads a list of AnyStruct values, see AnysValue.
+func (r *Reader) ReadAnys() []*codectypes.Any {
+	var res []*codectypes.Any
+	for l := r.ReadList(); l.More(); {
+		res = append(res, l.anyStruct())
+	}
+	return res
+}
+
+func (r *Reader) anyStruct() *codectypes.Any {
+	s := r.ReadStruct(len(AnyStruct.Fields))
+	return &codectypes.Any{TypeUrl: s.ReadString(), Value: s.ReadBytes()}
+}
+
+// ReadCoins reads a list of CoinStruct values, see CoinsValue.
+func (r *Reader) ReadCoins() []sdk.Coin {
+	var res []sdk.Coin
+	for l := r.ReadList(); l.More(); {
+		s := l.ReadStruct(len(CoinStruct.Fields))
+		res = append(res, sdk.Coin{Denom: s.ReadString(), Amount: s.ReadInt()})
+	}
+	return res
+}
+
+// ReadDecCoins reads a list of DecCoinStruct values, see DecCoinsValue.
+func (r *Reader) ReadDecCoins() []sdk.DecCoin {
+	var res []sdk.DecCoin
+	for l := r.ReadList(); l.More(); {
+		s := l.ReadStruct(len(DecCoinStruct.Fields))
+		res = append(res, sdk.DecCoin{Denom: s.ReadString(), Amount: s.ReadDec()})
+	}
+	return res
+}
@@ -0,0 +1,121 @@
+// Package schemavalue helps modules describe the protobuf values of their collections with structured
+// cosmossdk.io/schema fields, so that indexers receive queryable fields rather than JSON. Modules
+// declare the fields of a value with collections/codec.ValueCodecWithSchema, convert values to schema
+// values with the helpers of this package and read them back with a Reader.
+package schemavalue
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	collcodec "cosmossdk.io/collections/codec"
+	"cosmossdk.io/schema"
+
+	"github.com/cosmos/cosmos-sdk/codec/schemaproto/protoderive"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	// AnyStruct is the struct type of google.protobuf.Any values, see AnyValue.
+	AnyStruct = schema.StructType{
+		Name: "any",
+		Fields: []schema.Field{
+			{Name: "type_url", Kind: schema.StringKind},
+			{Name: "value", Kind: schema.BytesKind},
+		},
+	}
+
+	// CoinStruct is the struct type of sdk.Coin values, see CoinsValue.
+	CoinStruct = schema.StructType{
+		Name: "coin",
+		Fields: []schema.Field{
+			{Name: "denom", Kind: schema.StringKind},
+			{Name: "amount", Kind: schema.IntegerStringKind},
+		},
+	}
+
+	// DecCoinStruct is the struct type of sdk.DecCoin values, see DecCoinsValue.
+	DecCoinStruct = schema.StructType{
+		Name: "dec_coin",
+		Fields: []schema.Field{
+			{Name: "denom", Kind: schema.StringKind},
+			{Name: "amount", Kind: schema.DecimalStringKind},
+		},
+	}
+)
+
+// EnumType returns the enum type of a protobuf enum descriptor, such as the descriptor of
+// BondStatus of cosmossdk.io/api, with the name. The values and numeric values of the enum type are
+// derived from the descriptor with protoderive.EnumTypeFromProtoEnum, so that they can't drift from
+// the protobuf definition of the enum.
+func EnumType(name string, desc protoreflect.EnumDescriptor) schema.EnumType {
+	res := protoderive.EnumTypeFromProtoEnum(desc)
+	res.Name = name
+	return res
+}
+
+// AddressBytesKey describes a key codec of raw address bytes as a single address field with the name.
+func AddressBytesKey(keyCodec collcodec.KeyCodec[[]byte], name string) collcodec.KeyCodec[[]byte] {
+	return collcodec.KeyCodecWithSchema(keyCodec, collcodec.SchemaCodec[[]byte]{
+		Fields: []schema.Field{{Name: name, Kind: schema.AddressKind}},
+	})
+}
+
+// AnyValue renders an Any as the value of a nullable AnyStruct field.
+func AnyValue(a *codectypes.Any) interface{} {
+	if a == nil {
+		return nil
+	}
+	return []interface{}{a.TypeUrl, a.Value}
+}
+
+// AnysValue renders Anys as the value of a list field of AnyStruct elements.
+func AnysValue(anys []*codectypes.Any) []interface{} {
+	res := make([]interface{}, len(anys))
+	for i, a := range anys {
+		res[i] = []interface{}{a.GetTypeUrl(), a.GetValue()}
+	}
+	return res
+}
+
+// CoinsValue renders coins as the value of a list field of CoinStruct elements.
+func CoinsValue(coins []sdk.Coin) []interface{} {
+	res := make([]interface{}, len(coins))
+	for i, coin := range coins {
+		res[i] = []interface{}{coin.Denom, IntString(coin.Amount)}
+	}
+	return res
+}
+
+// DecCoinsValue renders decimal coins as the value of a list field of DecCoinStruct elements.
+func DecCoinsValue(coins []sdk.DecCoin) []interface{} {
+	res := make([]interface{}, len(coins))
+	for i, coin := range coins {
+		res[i] = []interface{}{coin.Denom, DecString(coin.Amount)}
+	}
+	return res
+}
+
+// FormatDecCoins renders decimal coins like DecCoinsValue and checks that each amount is a valid
+// DecimalStringKind value, see FormatDec.
+func FormatDecCoins(coins []sdk.DecCoin) ([]interface{}, error) {
+	res := make([]interface{}, len(coins))
+	for i, coin := range coins {
+		amount, err := FormatDec(coin.Amount)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = []interface{}{coin.Denom, amount}
+	}
+	return res, nil
+}
+
+// TimeValue renders an optional time as the value of a nullable TimeKind field.
+func TimeValue(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
@@ -0,0 +1,90 @@
+package schemavalue_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	stakingv1beta1 "cosmossdk.io/api/cosmos/staking/v1beta1"
+	collcodec "cosmossdk.io/collections/codec"
+	"cosmossdk.io/math"
+	"cosmossdk.io/schema"
+
+	"github.com/cosmos/cosmos-sdk/codec/schemavalue"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestEnumType(t *testing.T) {
+	enum := schemavalue.EnumType("bond_status", stakingv1beta1.BondStatus(0).Descriptor())
+	require.NoError(t, enum.Validate())
+	require.Equal(t, "bond_status", enum.Name)
+	require.Equal(t, []string{"BOND_STATUS_UNSPECIFIED", "BOND_STATUS_UNBONDED", "BOND_STATUS_UNBONDING", "BOND_STATUS_BONDED"}, enum.Values)
+	require.Equal(t, []int32{0, 1, 2, 3}, enum.NumericValues)
+}
+
+func TestReader(t *testing.T) {
+	now := time.Unix(10, 0).UTC()
+	coins := []sdk.Coin{sdk.NewInt64Coin("atom", 5)}
+	decCoins := []sdk.DecCoin{sdk.NewDecCoinFromDec("atom", math.LegacyNewDecWithPrec(15, 1))}
+	anys := []*codectypes.Any{{TypeUrl: "/a", Value: []byte{1}}}
+	value := []interface{}{
+		"a", []byte{1}, true, int32(-1), int64(-2), uint32(4), uint64(3), now, nil, time.Second,
+		schemavalue.IntString(math.Int{}), schemavalue.DecString(math.LegacyNewDec(2)), "STATUS_A",
+		schemavalue.AnyValue(nil), schemavalue.AnysValue(anys),
+		schemavalue.CoinsValue(coins), schemavalue.DecCoinsValue(decCoins), nil, []interface{}{"b"},
+	}
+
+	r, err := schemavalue.NewReader(value, len(value))
+	require.NoError(t, err)
+	require.Equal(t, "a", r.ReadString())
+	require.Equal(t, []byte{1}, r.ReadBytes())
+	require.True(t, r.ReadBool())
+	require.Equal(t, int32(-1), r.ReadInt32())
+	require.Equal(t, int64(-2), r.ReadInt64())
+	require.Equal(t, uint32(4), r.ReadUint32())
+	require.Equal(t, uint64(3), r.ReadUint64())
+	require.Equal(t, now, r.ReadTime())
+	require.Nil(t, r.ReadNullableTime())
+	require.Equal(t, time.Second, r.ReadDuration())
+	require.Equal(t, math.ZeroInt(), r.ReadInt())
+	require.Equal(t, math.LegacyNewDec(2), r.ReadDec())
+	require.Equal(t, int32(1), r.ReadEnum(schema.EnumType{Name: "status", Values: []string{"STATUS_UNSPECIFIED", "STATUS_A"}}))
+	require.Nil(t, r.ReadAny())
+	require.Equal(t, anys, r.ReadAnys())
+	require.Equal(t, coins, r.ReadCoins())
+	require.Equal(t, decCoins, r.ReadDecCoins())
+	require.Nil(t, r.ReadNullableStruct(1))
+	require.Equal(t, "b", r.ReadNullableStruct(1).ReadString())
+	require.False(t, r.More())
+	require.NoError(t, r.Err())
+
+	_, err = schemavalue.NewReader([]interface{}{"a"}, 2)
+	require.ErrorIs(t, err, collcodec.ErrEncoding)
+}
+
+func TestReader_nestedErrors(t *testing.T) {
+	r, err := schemavalue.NewReader([]interface{}{[]interface{}{[]interface{}{"atom", "not a number"}}, "b"}, 2)
+	require.NoError(t, err)
+	require.Equal(t, []sdk.Coin{{Denom: "atom", Amount: math.ZeroInt()}}, r.ReadCoins())
+	require.Equal(t, "b", r.ReadString())
+	require.ErrorContains(t, r.Err(), `invalid integer "not a number"`)
+	require.ErrorIs(t, r.Err(), collcodec.ErrEncoding)
+
+	r.ReadString()
+	require.ErrorContains(t, r.Err(), "invalid integer", "the first error is kept")
+}
+
+func TestFormatDecCoins(t *testing.T) {
+	coins := []sdk.DecCoin{sdk.NewDecCoinFromDec("atom", math.LegacyNewDecWithPrec(15, 1))}
+	value, err := schemavalue.FormatDecCoins(coins)
+	require.NoError(t, err)
+	require.Equal(t, schemavalue.DecCoinsValue(coins), value)
+
+	big, err := math.LegacyNewDecFromStr("1" + strings.Repeat("0", 50))
+	require.NoError(t, err)
+	_, err = schemavalue.FormatDecCoins([]sdk.DecCoin{{Denom: "atom", Amount: big}})
+	require.ErrorIs(t, err, collcodec.ErrEncoding)
+}
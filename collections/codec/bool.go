@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+
+	"cosmossdk.io/schema"
 )
 
 func NewBoolKey[T ~bool]() KeyCodec[T] { return boolKey[T]{} }
@@ -53,6 +55,11 @@ func (b boolKey[T]) KeyType() string {
 	return "bool"
 }
 
+// SchemaCodec implements the HasSchemaCodec interface.
+func (boolKey[T]) SchemaCodec() (SchemaCodec[T], error) {
+	return primitiveSchemaCodec(schema.BoolKind, func(key T) bool { return (bool)(key) }, func(v bool) T { return (T)(v) }), nil
+}
+
 func (b boolKey[T]) EncodeNonTerminal(buffer []byte, key T) (int, error) {
 	return b.Encode(buffer, key)
 }
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+
+	"cosmossdk.io/schema"
 )
 
 // MaxBytesKeyNonTerminalSize defines the maximum length of a bytes key encoded
@@ -45,6 +47,11 @@ func (b bytesKey[T]) KeyType() string {
 	return "bytes"
 }
 
+// SchemaCodec implements the HasSchemaCodec interface.
+func (bytesKey[T]) SchemaCodec() (SchemaCodec[T], error) {
+	return primitiveSchemaCodec(schema.BytesKind, func(key T) []byte { return ([]byte)(key) }, func(v []byte) T { return (T)(v) }), nil
+}
+
 func (b bytesKey[T]) EncodeNonTerminal(buffer []byte, key T) (int, error) {
 	if len(key) > MaxBytesKeyNonTerminalSize {
 		return 0, fmt.Errorf(
@@ -167,3 +167,8 @@ func (k keyToValueCodec[K]) Stringify(value K) string {
 func (k keyToValueCodec[K]) ValueType() string {
 	return k.kc.KeyType()
 }
+
+// SchemaCodec implements the HasSchemaCodec interface by describing values like the wrapped key codec.
+func (k keyToValueCodec[K]) SchemaCodec() (SchemaCodec[K], error) {
+	return KeySchemaCodec(k.kc)
+}
@@ -0,0 +1,196 @@
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cosmossdk.io/schema"
+)
+
+// HasSchemaCodec is implemented by key and value codecs which describe their values with
+// cosmossdk.io/schema fields so that collections can be exported to indexers. Codecs which
+// don't implement it are described by FallbackSchemaCodec.
+type HasSchemaCodec[T any] interface {
+	// SchemaCodec returns the schema codec of the codec.
+	SchemaCodec() (SchemaCodec[T], error)
+}
+
+// SchemaCodec converts between the values of a collections codec and the values of
+// the schema fields which describe them.
+type SchemaCodec[T any] struct {
+	// Fields are the schema fields which the codec's values are made of. Field names may be
+	// left empty, in which case the collection names the fields based on their position.
+	// A codec without fields represents no value, like the key of an Item or the value of a KeySet.
+	Fields []schema.Field
+
+	// ToSchemaType converts a value of the codec to a schema value for Fields: a single value
+	// if there is one field and a []interface{} with a value per field otherwise. If it is nil,
+	// T is already a valid schema value.
+	ToSchemaType func(T) (interface{}, error)
+
+	// FromSchemaType converts a schema value for Fields back to a value of the codec. If it is
+	// nil, T is already a valid schema value.
+	FromSchemaType func(interface{}) (T, error)
+
+	// FromKeyedSchemaType converts a schema value for Fields back to a value of the codec given the
+	// schema value of the key of the collection entry. Value codecs which leave out the data which
+	// their values repeat from their keys, such as ids and addresses, set it so that the values can be
+	// restored from the key and the value fields. If it is set, collections use it instead of
+	// FromSchemaType to encode values.
+	FromKeyedSchemaType func(key, value interface{}) (T, error)
+}
+
+// KeySchemaCodec returns the schema codec of a key codec, falling back to FallbackSchemaCodec
+// if the key codec doesn't implement HasSchemaCodec.
+func KeySchemaCodec[T any](keyCodec KeyCodec[T]) (SchemaCodec[T], error) {
+	if cdc, ok := keyCodec.(HasSchemaCodec[T]); ok {
+		return cdc.SchemaCodec()
+	}
+	return FallbackSchemaCodec(keyCodec.EncodeJSON, keyCodec.DecodeJSON), nil
+}
+
+// ValueSchemaCodec returns the schema codec of a value codec, falling back to FallbackSchemaCodec
+// if the value codec doesn't implement HasSchemaCodec.
+func ValueSchemaCodec[T any](valueCodec ValueCodec[T]) (SchemaCodec[T], error) {
+	if cdc, ok := valueCodec.(HasSchemaCodec[T]); ok {
+		return cdc.SchemaCodec()
+	}
+	return FallbackSchemaCodec(valueCodec.EncodeJSON, valueCodec.DecodeJSON), nil
+}
+
+// FallbackSchemaCodec returns a schema codec for T when its codec doesn't provide one. Go types
+// which map directly to a schema kind, as reported by schema.KindForGoValue, are represented by
+// a field of that kind and everything else by a JSON field using the codec's JSON encoding.
+func FallbackSchemaCodec[T any](encodeJSON func(T) ([]byte, error), decodeJSON func([]byte) (T, error)) SchemaCodec[T] {
+	var zero T
+	if kind := schema.KindForGoValue(zero); kind.Validate() == nil {
+		return SchemaCodec[T]{Fields: []schema.Field{{Kind: kind}}}
+	}
+
+	return SchemaCodec[T]{
+		Fields: []schema.Field{{Kind: schema.JSONKind}},
+		ToSchemaType: func(value T) (interface{}, error) {
+			bz, err := encodeJSON(value)
+			if err != nil {
+				return nil, err
+			}
+			return json.RawMessage(bz), nil
+		},
+		FromSchemaType: func(value interface{}) (T, error) {
+			bz, ok := value.(json.RawMessage)
+			if !ok {
+				var t T
+				return t, fmt.Errorf("%w: expected json.RawMessage, got %T", ErrEncoding, value)
+			}
+			return decodeJSON(bz)
+		},
+	}
+}
+
+// primitiveSchemaCodec returns the schema codec of a codec whose values of type T convert to
+// values of type S of a single field of the kind.
+func primitiveSchemaCodec[T, S any](kind schema.Kind, to func(T) S, from func(S) T) SchemaCodec[T] {
+	return SchemaCodec[T]{
+		Fields: []schema.Field{{Kind: kind}},
+		ToSchemaType: func(value T) (interface{}, error) {
+			return to(value), nil
+		},
+		FromSchemaType: func(value interface{}) (T, error) {
+			s, ok := value.(S)
+			if !ok {
+				var t T
+				return t, fmt.Errorf("%w: expected %T, got %T", ErrEncoding, s, value)
+			}
+			return from(s), nil
+		},
+	}
+}
+
+// NamedKeyCodec returns a key codec which encodes keys like keyCodec and whose schema fields are
+// named after names, in order, so that the key fields of the collections using it are exported
+// with meaningful names rather than after their position.
+func NamedKeyCodec[T any](keyCodec KeyCodec[T], names ...string) KeyCodec[T] {
+	return namedKeyCodec[T]{KeyCodec: keyCodec, names: names}
+}
+
+type namedKeyCodec[T any] struct {
+	KeyCodec[T]
+	names []string
+}
+
+// SchemaCodec implements the HasSchemaCodec interface.
+func (n namedKeyCodec[T]) SchemaCodec() (SchemaCodec[T], error) {
+	cdc, err := KeySchemaCodec(n.KeyCodec)
+	if err != nil {
+		return SchemaCodec[T]{}, err
+	}
+	return nameFields(cdc, n.names)
+}
+
+// NamedValueCodec returns a value codec which encodes values like valueCodec and whose schema fields
+// are named after names, in order, so that the value fields of the collections using it are exported
+// with meaningful names rather than after their position.
+func NamedValueCodec[T any](valueCodec ValueCodec[T], names ...string) ValueCodec[T] {
+	return namedValueCodec[T]{ValueCodec: valueCodec, names: names}
+}
+
+type namedValueCodec[T any] struct {
+	ValueCodec[T]
+	names []string
+}
+
+// SchemaCodec implements the HasSchemaCodec interface.
+func (n namedValueCodec[T]) SchemaCodec() (SchemaCodec[T], error) {
+	cdc, err := ValueSchemaCodec(n.ValueCodec)
+	if err != nil {
+		return SchemaCodec[T]{}, err
+	}
+	return nameFields(cdc, n.names)
+}
+
+func nameFields[T any](cdc SchemaCodec[T], names []string) (SchemaCodec[T], error) {
+	if len(names) != len(cdc.Fields) {
+		return SchemaCodec[T]{}, fmt.Errorf("expected %d field names, got %d", len(cdc.Fields), len(names))
+	}
+	fields := make([]schema.Field, len(cdc.Fields))
+	copy(fields, cdc.Fields)
+	for i, name := range names {
+		fields[i].Name = name
+	}
+	cdc.Fields = fields
+	return cdc, nil
+}
+
+// KeyCodecWithSchema returns a key codec which encodes keys like keyCodec and is described by
+// schemaCodec, for key codecs whose schema codec doesn't describe the meaning of their keys, such as
+// bytes keys storing addresses.
+func KeyCodecWithSchema[T any](keyCodec KeyCodec[T], schemaCodec SchemaCodec[T]) KeyCodec[T] {
+	return keyCodecWithSchema[T]{KeyCodec: keyCodec, schemaCodec: schemaCodec}
+}
+
+type keyCodecWithSchema[T any] struct {
+	KeyCodec[T]
+	schemaCodec SchemaCodec[T]
+}
+
+// SchemaCodec implements the HasSchemaCodec interface.
+func (k keyCodecWithSchema[T]) SchemaCodec() (SchemaCodec[T], error) {
+	return k.schemaCodec, nil
+}
+
+// ValueCodecWithSchema returns a value codec which encodes values like valueCodec and is described
+// by schemaCodec, so that values such as protobuf messages can be exported with structured fields
+// rather than as JSON.
+func ValueCodecWithSchema[T any](valueCodec ValueCodec[T], schemaCodec SchemaCodec[T]) ValueCodec[T] {
+	return valueCodecWithSchema[T]{ValueCodec: valueCodec, schemaCodec: schemaCodec}
+}
+
+type valueCodecWithSchema[T any] struct {
+	ValueCodec[T]
+	schemaCodec SchemaCodec[T]
+}
+
+// SchemaCodec implements the HasSchemaCodec interface.
+func (v valueCodecWithSchema[T]) SchemaCodec() (SchemaCodec[T], error) {
+	return v.schemaCodec, nil
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+
+	"cosmossdk.io/schema"
 )
 
 func NewInt64Key[T ~int64]() KeyCodec[T] { return int64Key[T]{} }
@@ -52,6 +54,11 @@ func (i int64Key[T]) KeyType() string {
 	return "int64"
 }
 
+// SchemaCodec implements the HasSchemaCodec interface.
+func (int64Key[T]) SchemaCodec() (SchemaCodec[T], error) {
+	return primitiveSchemaCodec(schema.Int64Kind, func(key T) int64 { return (int64)(key) }, func(v int64) T { return (T)(v) }), nil
+}
+
 func (i int64Key[T]) EncodeNonTerminal(buffer []byte, key T) (int, error) {
 	return i.Encode(buffer, key)
 }
@@ -110,6 +117,11 @@ func (i int32Key[T]) KeyType() string {
 	return "int32"
 }
 
+// SchemaCodec implements the HasSchemaCodec interface.
+func (int32Key[T]) SchemaCodec() (SchemaCodec[T], error) {
+	return primitiveSchemaCodec(schema.Int32Kind, func(key T) int32 { return (int32)(key) }, func(v int32) T { return (T)(v) }), nil
+}
+
 func (i int32Key[T]) EncodeNonTerminal(buffer []byte, key T) (int, error) {
 	return i.Encode(buffer, key)
 }
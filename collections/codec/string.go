@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+
+	"cosmossdk.io/schema"
 )
 
 func NewStringKeyCodec[T ~string]() KeyCodec[T] { return stringKey[T]{} }
@@ -66,3 +68,8 @@ func (stringKey[T]) Stringify(key T) string {
 func (stringKey[T]) KeyType() string {
 	return "string"
 }
+
+// SchemaCodec implements the HasSchemaCodec interface.
+func (stringKey[T]) SchemaCodec() (SchemaCodec[T], error) {
+	return primitiveSchemaCodec(schema.StringKind, func(key T) string { return (string)(key) }, func(v string) T { return (T)(v) }), nil
+}
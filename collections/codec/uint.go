@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+
+	"cosmossdk.io/schema"
 )
 
 func NewUint64Key[T ~uint64]() KeyCodec[T] { return uint64Key[T]{} }
@@ -55,6 +57,11 @@ func (uint64Key[T]) KeyType() string {
 	return "uint64"
 }
 
+// SchemaCodec implements the HasSchemaCodec interface.
+func (uint64Key[T]) SchemaCodec() (SchemaCodec[T], error) {
+	return primitiveSchemaCodec(schema.Uint64Kind, func(key T) uint64 { return (uint64)(key) }, func(v uint64) T { return (T)(v) }), nil
+}
+
 func NewUint32Key[T ~uint32]() KeyCodec[T] { return uint32Key[T]{} }
 
 type uint32Key[T ~uint32] struct{}
@@ -87,6 +94,11 @@ func (uint32Key[T]) Stringify(key T) string { return strconv.FormatUint(uint64(k
 
 func (uint32Key[T]) KeyType() string { return "uint32" }
 
+// SchemaCodec implements the HasSchemaCodec interface.
+func (uint32Key[T]) SchemaCodec() (SchemaCodec[T], error) {
+	return primitiveSchemaCodec(schema.Uint32Kind, func(key T) uint32 { return (uint32)(key) }, func(v uint32) T { return (T)(v) }), nil
+}
+
 func (u uint32Key[T]) EncodeNonTerminal(buffer []byte, key T) (int, error) {
 	return u.Encode(buffer, key)
 }
@@ -127,6 +139,11 @@ func (uint16Key[T]) Stringify(key T) string { return strconv.FormatUint((uint64)
 
 func (uint16Key[T]) KeyType() string { return "uint16" }
 
+// SchemaCodec implements the HasSchemaCodec interface.
+func (uint16Key[T]) SchemaCodec() (SchemaCodec[T], error) {
+	return primitiveSchemaCodec(schema.Uint16Kind, func(key T) uint16 { return (uint16)(key) }, func(v uint16) T { return (T)(v) }), nil
+}
+
 func (u uint16Key[T]) EncodeNonTerminal(buffer []byte, key T) (int, error) {
 	return u.Encode(buffer, key)
 }
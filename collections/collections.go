@@ -90,6 +90,9 @@ type Collection interface {
 	ValueCodec() codec.UntypedValueCodec
 
 	genesisHandler
+
+	// schemaCodec returns the codec which describes the collection as a schema object type.
+	schemaCodec() (*collectionSchemaCodec, error)
 }
 
 // Prefix defines a segregation bytes namespace for specific collections objects.
@@ -30,6 +30,29 @@ type Index[PrimaryKey, Value any] interface {
 	Unreference(ctx context.Context, pk PrimaryKey, lazyOldValue func() (Value, error)) error
 }
 
+// HasIndexSchema is implemented by indexes which describe themselves in the module schema exported
+// by Schema.ModuleCodec.
+type HasIndexSchema interface {
+	// IndexSchema returns the schema of the index.
+	IndexSchema() IndexSchema
+}
+
+// IndexSchema describes an index of an IndexedMap in the module schema exported by Schema.ModuleCodec.
+type IndexSchema struct {
+	// CollectionName is the name of the collection in which the index stores its references. Its
+	// fields are named after Fields and the key fields of the IndexedMap when their number matches.
+	CollectionName string
+
+	// Fields are the value fields of the IndexedMap from which the reference key of the index is
+	// derived, in order. If set, the index is exported as a schema.IndexDescriptor of the IndexedMap's
+	// object type named after CollectionName.
+	Fields []string
+
+	// Unique indicates that the index enforces the uniqueness of its reference keys, in which case
+	// Fields are also exported as a unique constraint of the IndexedMap's object type.
+	Unique bool
+}
+
 // IndexedMap works like a Map but creates references between fields of Value and its PrimaryKey.
 // These relationships are expressed and maintained using the Indexes type.
 // Internally IndexedMap can be seen as a partitioned collection, one partition
@@ -62,10 +85,17 @@ func NewIndexedMapSafe[K, V, I any](
 		}
 	}
 
+	m := NewMap(schema, prefix, name, pkCodec, valueCodec)
+	for _, index := range indexesList {
+		if indexSchema, ok := index.(HasIndexSchema); ok {
+			schema.schema.indexSchemas[name] = append(schema.schema.indexSchemas[name], indexSchema.IndexSchema())
+		}
+	}
+
 	return &IndexedMap[K, V, I]{
 		computedIndexes: indexesList,
 		Indexes:         indexes,
-		m:               NewMap(schema, prefix, name, pkCodec, valueCodec),
+		m:               m,
 	}, nil
 }
 
@@ -0,0 +1,114 @@
+package indexes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/collections/codec"
+	"cosmossdk.io/schema"
+)
+
+// companyValueCodec describes companies with a schema field per struct field.
+type companyValueCodec struct{}
+
+func (companyValueCodec) Encode(value company) ([]byte, error) { return json.Marshal(value) }
+func (companyValueCodec) Decode(b []byte) (company, error) {
+	var c company
+	err := json.Unmarshal(b, &c)
+	return c, err
+}
+func (c companyValueCodec) EncodeJSON(value company) ([]byte, error) { return c.Encode(value) }
+func (c companyValueCodec) DecodeJSON(b []byte) (company, error)     { return c.Decode(b) }
+func (companyValueCodec) Stringify(value company) string             { return value.City }
+func (companyValueCodec) ValueType() string                          { return "company" }
+
+func (companyValueCodec) SchemaCodec() (codec.SchemaCodec[company], error) {
+	return codec.SchemaCodec[company]{
+		Fields: []schema.Field{{Name: "city", Kind: schema.StringKind}, {Name: "vat", Kind: schema.Uint64Kind}},
+		ToSchemaType: func(c company) (interface{}, error) {
+			return []interface{}{c.City, c.Vat}, nil
+		},
+	}, nil
+}
+
+type companyIndexes struct {
+	City *Multi[string, string, company]
+	Vat  *Unique[uint64, string, company]
+}
+
+func (i companyIndexes) IndexesList() []collections.Index[string, company] {
+	return []collections.Index[string, company]{i.City, i.Vat}
+}
+
+func TestIndexSchema(t *testing.T) {
+	sk, ctx := deps()
+	sb := collections.NewSchemaBuilder(sk)
+	companies := collections.NewIndexedMap(sb, collections.NewPrefix(1), "companies", collections.StringKey, companyValueCodec{}, companyIndexes{
+		City: NewMulti(sb, collections.NewPrefix(2), "companies_by_city", collections.StringKey, collections.StringKey,
+			func(_ string, c company) (string, error) { return c.City, nil }, WithMultiSchemaFields("city")),
+		Vat: NewUnique(sb, collections.NewPrefix(3), "companies_by_vat", collections.Uint64Key, collections.StringKey,
+			func(_ string, c company) (uint64, error) { return c.Vat, nil }, WithUniqueSchemaFields("vat")),
+	})
+	s, err := sb.Build()
+	require.NoError(t, err)
+
+	cdc, err := s.ModuleCodec(collections.IndexingOptions{})
+	require.NoError(t, err)
+
+	typ, ok := cdc.Schema.LookupType("companies")
+	require.True(t, ok)
+	companiesType := typ.(schema.ObjectType)
+	require.Equal(t, []schema.IndexDescriptor{
+		{Name: "companies_by_city", Fields: []schema.IndexField{{Name: "city"}}},
+		{Name: "companies_by_vat", Fields: []schema.IndexField{{Name: "vat"}}},
+	}, companiesType.Indexes)
+	require.Equal(t, [][]string{{"vat"}}, companiesType.UniqueConstraints)
+
+	typ, ok = cdc.Schema.LookupType("companies_by_city")
+	require.True(t, ok)
+	require.Equal(t, []schema.Field{{Name: "city", Kind: schema.StringKind}, {Name: "key", Kind: schema.StringKind}}, typ.(schema.ObjectType).KeyFields)
+
+	typ, ok = cdc.Schema.LookupType("companies_by_vat")
+	require.True(t, ok)
+	require.Equal(t, []schema.Field{{Name: "vat", Kind: schema.Uint64Kind}}, typ.(schema.ObjectType).KeyFields)
+	require.Equal(t, []schema.Field{{Name: "key", Kind: schema.StringKind}}, typ.(schema.ObjectType).ValueFields)
+
+	require.NoError(t, companies.Set(ctx, "acme", company{City: "milan", Vat: 7}))
+
+	var updates []schema.ObjectUpdate
+	it, err := sk.OpenKVStore(ctx).Iterator(nil, nil)
+	require.NoError(t, err)
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		res, err := cdc.KVDecoder(schema.KVPairUpdate{Key: it.Key(), Value: it.Value()})
+		require.NoError(t, err)
+		for _, update := range res {
+			require.NoError(t, cdc.Schema.ValidateObjectUpdate(update))
+		}
+		updates = append(updates, res...)
+	}
+	require.Equal(t, []schema.ObjectUpdate{
+		{TypeName: "companies", Key: "acme", Value: []interface{}{"milan", uint64(7)}},
+		{TypeName: "companies_by_city", Key: []interface{}{"milan", "acme"}},
+		{TypeName: "companies_by_vat", Key: uint64(7), Value: "acme"},
+	}, updates)
+}
+
+func TestIndexSchema_unknownField(t *testing.T) {
+	sk, _ := deps()
+	sb := collections.NewSchemaBuilder(sk)
+	collections.NewIndexedMap(sb, collections.NewPrefix(1), "companies", collections.StringKey, companyValueCodec{}, companyIndexes{
+		City: NewMulti(sb, collections.NewPrefix(2), "companies_by_city", collections.StringKey, collections.StringKey,
+			func(_ string, c company) (string, error) { return c.City, nil }, WithMultiSchemaFields("town")),
+		Vat: NewUnique(sb, collections.NewPrefix(3), "companies_by_vat", collections.Uint64Key, collections.StringKey,
+			func(_ string, c company) (uint64, error) { return c.Vat, nil }),
+	})
+	s, err := sb.Build()
+	require.NoError(t, err)
+
+	_, err = s.ModuleCodec(collections.IndexingOptions{})
+	require.ErrorContains(t, err, "unknown value field \"town\"")
+}
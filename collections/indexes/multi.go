@@ -10,6 +10,7 @@ import (
 
 type multiOptions struct {
 	uncheckedValue bool
+	schemaFields   []string
 }
 
 // WithMultiUncheckedValue is an option that can be passed to NewMulti to
@@ -23,12 +24,22 @@ func WithMultiUncheckedValue() func(*multiOptions) {
 	}
 }
 
+// WithMultiSchemaFields is an option that can be passed to NewMulti to declare the value fields of the
+// indexed object from which the reference key is derived, so that the index is exported as an index of
+// the IndexedMap's object type by collections.Schema.ModuleCodec. See collections.IndexSchema.
+func WithMultiSchemaFields(fields ...string) func(*multiOptions) {
+	return func(o *multiOptions) {
+		o.schemaFields = fields
+	}
+}
+
 // Multi defines the most common index. It can be used to create a reference between
 // a field of value and its primary key. Multiple primary keys can be mapped to the same
 // reference key as the index does not enforce uniqueness constraints.
 type Multi[ReferenceKey, PrimaryKey, Value any] struct {
-	getRefKey func(pk PrimaryKey, value Value) (ReferenceKey, error)
-	refKeys   collections.KeySet[collections.Pair[ReferenceKey, PrimaryKey]]
+	getRefKey    func(pk PrimaryKey, value Value) (ReferenceKey, error)
+	refKeys      collections.KeySet[collections.Pair[ReferenceKey, PrimaryKey]]
+	schemaFields []string
 }
 
 // NewMulti instantiates a new Multi instance given a schema,
@@ -50,14 +61,24 @@ func NewMulti[ReferenceKey, PrimaryKey, Value any](
 	}
 	if o.uncheckedValue {
 		return &Multi[ReferenceKey, PrimaryKey, Value]{
-			getRefKey: getRefKeyFunc,
-			refKeys:   collections.NewKeySet(schema, prefix, name, collections.PairKeyCodec(refCodec, pkCodec), collections.WithKeySetUncheckedValue()),
+			getRefKey:    getRefKeyFunc,
+			refKeys:      collections.NewKeySet(schema, prefix, name, collections.PairKeyCodec(refCodec, pkCodec), collections.WithKeySetUncheckedValue()),
+			schemaFields: o.schemaFields,
 		}
 	}
 
 	return &Multi[ReferenceKey, PrimaryKey, Value]{
-		getRefKey: getRefKeyFunc,
-		refKeys:   collections.NewKeySet(schema, prefix, name, collections.PairKeyCodec(refCodec, pkCodec)),
+		getRefKey:    getRefKeyFunc,
+		refKeys:      collections.NewKeySet(schema, prefix, name, collections.PairKeyCodec(refCodec, pkCodec)),
+		schemaFields: o.schemaFields,
+	}
+}
+
+// IndexSchema implements the collections.HasIndexSchema interface.
+func (m *Multi[ReferenceKey, PrimaryKey, Value]) IndexSchema() collections.IndexSchema {
+	return collections.IndexSchema{
+		CollectionName: (collections.Map[collections.Pair[ReferenceKey, PrimaryKey], collections.NoValue])(m.refKeys).GetName(),
+		Fields:         m.schemaFields,
 	}
 }
 
@@ -66,6 +66,14 @@ func NewReversePair[Value, K1, K2 any](
 	return mi
 }
 
+// IndexSchema implements the collections.HasIndexSchema interface. ReversePair indexes the primary key
+// rather than value fields, so only the collection storing its references is exported.
+func (i *ReversePair[K1, K2, Value]) IndexSchema() collections.IndexSchema {
+	return collections.IndexSchema{
+		CollectionName: (collections.Map[collections.Pair[K2, K1], collections.NoValue])(i.refKeys).GetName(),
+	}
+}
+
 // Iterate exposes the raw iterator API.
 func (i *ReversePair[K1, K2, Value]) Iterate(ctx context.Context, ranger collections.Ranger[collections.Pair[K2, K1]]) (iter ReversePairIterator[K2, K1], err error) {
 	sIter, err := i.refKeys.Iterate(ctx, ranger)
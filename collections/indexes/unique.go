@@ -12,8 +12,22 @@ import (
 // Unique identifies an index that imposes uniqueness constraints on the reference key.
 // It creates relationships between reference and primary key of the value.
 type Unique[ReferenceKey, PrimaryKey, Value any] struct {
-	getRefKey func(PrimaryKey, Value) (ReferenceKey, error)
-	refKeys   collections.Map[ReferenceKey, PrimaryKey]
+	getRefKey    func(PrimaryKey, Value) (ReferenceKey, error)
+	refKeys      collections.Map[ReferenceKey, PrimaryKey]
+	schemaFields []string
+}
+
+type uniqueOptions struct {
+	schemaFields []string
+}
+
+// WithUniqueSchemaFields is an option that can be passed to NewUnique to declare the value fields of the
+// indexed object from which the reference key is derived, so that the index is exported as a unique index
+// of the IndexedMap's object type by collections.Schema.ModuleCodec. See collections.IndexSchema.
+func WithUniqueSchemaFields(fields ...string) func(*uniqueOptions) {
+	return func(o *uniqueOptions) {
+		o.schemaFields = fields
+	}
 }
 
 // NewUnique instantiates a new Unique index.
@@ -24,10 +38,25 @@ func NewUnique[ReferenceKey, PrimaryKey, Value any](
 	refCodec codec.KeyCodec[ReferenceKey],
 	pkCodec codec.KeyCodec[PrimaryKey],
 	getRefKeyFunc func(pk PrimaryKey, v Value) (ReferenceKey, error),
+	options ...func(*uniqueOptions),
 ) *Unique[ReferenceKey, PrimaryKey, Value] {
+	o := new(uniqueOptions)
+	for _, opt := range options {
+		opt(o)
+	}
 	return &Unique[ReferenceKey, PrimaryKey, Value]{
-		getRefKey: getRefKeyFunc,
-		refKeys:   collections.NewMap(schema, prefix, name, refCodec, codec.KeyToValueCodec(pkCodec)),
+		getRefKey:    getRefKeyFunc,
+		refKeys:      collections.NewMap(schema, prefix, name, refCodec, codec.KeyToValueCodec(pkCodec)),
+		schemaFields: o.schemaFields,
+	}
+}
+
+// IndexSchema implements the collections.HasIndexSchema interface.
+func (i *Unique[ReferenceKey, PrimaryKey, Value]) IndexSchema() collections.IndexSchema {
+	return collections.IndexSchema{
+		CollectionName: i.refKeys.GetName(),
+		Fields:         i.schemaFields,
+		Unique:         true,
 	}
 }
 
@@ -0,0 +1,389 @@
+package collections
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"cosmossdk.io/collections/codec"
+	"cosmossdk.io/schema"
+)
+
+// IndexingOptions are the options for exporting the collections of a schema as a
+// cosmossdk.io/schema module schema.
+type IndexingOptions struct {
+	// RetainDeletionsFor are the names of the collections whose deleted objects indexers
+	// should retain, as described by schema.ObjectType.RetainDeletions.
+	RetainDeletionsFor []string
+}
+
+// ModuleCodec returns a schema.ModuleCodec for the collections of the schema. Every collection
+// is exported as an object type with the name of the collection whose key and value fields are
+// described by the codec.HasSchemaCodec implementations of its key and value codecs, and its
+// KVDecoder decodes the kv-pairs of the collections to object updates and its KVEncoder encodes
+// object updates back to kv-pairs. The collections of the
+// indexes of IndexedMaps are exported as well, so that indexers receive the same references as
+// on-chain code, and indexes implementing HasIndexSchema are declared on the IndexedMap's object
+// type. Modules built on collections can use it to implement schema.HasModuleCodec without a
+// hand-written decoder.
+func (s Schema) ModuleCodec(opts IndexingOptions) (schema.ModuleCodec, error) {
+	retainDeletions := make(map[string]bool, len(opts.RetainDeletionsFor))
+	for _, name := range opts.RetainDeletionsFor {
+		if _, ok := s.collectionsByName[name]; !ok {
+			return schema.ModuleCodec{}, fmt.Errorf("unknown collection %s in RetainDeletionsFor", name)
+		}
+		retainDeletions[name] = true
+	}
+
+	decoder := moduleDecoder{}
+	codecs := make(map[string]*collectionSchemaCodec, len(s.collectionsOrdered))
+	for _, name := range s.collectionsOrdered {
+		cdc, err := s.collectionsByName[name].schemaCodec()
+		if err != nil {
+			return schema.ModuleCodec{}, fmt.Errorf("collection %s: %w", name, err)
+		}
+		cdc.objectType.RetainDeletions = retainDeletions[name]
+		codecs[name] = cdc
+		decoder.collections = append(decoder.collections, cdc)
+	}
+
+	for _, name := range s.collectionsOrdered {
+		for _, index := range s.indexSchemas[name] {
+			if err := addIndexSchema(codecs[name], codecs[index.CollectionName], index); err != nil {
+				return schema.ModuleCodec{}, fmt.Errorf("collection %s: %w", name, err)
+			}
+		}
+	}
+
+	objectTypes := make([]schema.ObjectType, 0, len(s.collectionsOrdered))
+	for _, name := range s.collectionsOrdered {
+		objectTypes = append(objectTypes, codecs[name].objectType)
+	}
+	sort.Slice(decoder.collections, func(i, j int) bool {
+		return bytes.Compare(decoder.collections[i].prefix, decoder.collections[j].prefix) < 0
+	})
+
+	moduleSchema, err := schema.NewModuleSchema(objectTypes)
+	if err != nil {
+		return schema.ModuleCodec{}, err
+	}
+
+	return schema.ModuleCodec{
+		Schema:    moduleSchema,
+		KVDecoder: decoder.decodeKV,
+		KVEncoder: func(update schema.ObjectUpdate) ([]schema.KVPairUpdate, error) {
+			cdc, ok := codecs[update.TypeName]
+			if !ok {
+				return nil, fmt.Errorf("unknown collection %s", update.TypeName)
+			}
+			return cdc.encodeObjectUpdate(update)
+		},
+	}, nil
+}
+
+// addIndexSchema describes an index of the indexed collection in its object type and names the
+// fields of the index's collection after the fields of the indexed collection which they store.
+func addIndexSchema(indexed, indexCollection *collectionSchemaCodec, index IndexSchema) error {
+	if indexCollection == nil {
+		return fmt.Errorf("unknown index collection %s", index.CollectionName)
+	}
+
+	if len(index.Fields) != 0 {
+		fields := make([]schema.IndexField, len(index.Fields))
+		for i, name := range index.Fields {
+			fields[i] = schema.IndexField{Name: name}
+		}
+		indexed.objectType.Indexes = append(indexed.objectType.Indexes, schema.IndexDescriptor{
+			Name:   index.CollectionName,
+			Fields: fields,
+		})
+		if index.Unique {
+			indexed.objectType.UniqueConstraints = append(indexed.objectType.UniqueConstraints, index.Fields)
+		}
+	}
+
+	// the index's collection stores the reference key followed by the primary key, which may be split
+	// between its key and value fields
+	names := append([]string(nil), index.Fields...)
+	for _, field := range indexed.objectType.KeyFields {
+		names = append(names, field.Name)
+	}
+	keyFields, valueFields := indexCollection.objectType.KeyFields, indexCollection.objectType.ValueFields
+	if len(index.Fields) == 0 || len(keyFields)+len(valueFields) != len(names) || hasDuplicates(names) {
+		return nil
+	}
+	for i := range keyFields {
+		keyFields[i].Name = names[i]
+	}
+	for i := range valueFields {
+		valueFields[i].Name = names[len(keyFields)+i]
+	}
+	return nil
+}
+
+func hasDuplicates(names []string) bool {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			return true
+		}
+		seen[name] = true
+	}
+	return false
+}
+
+// moduleDecoder decodes the kv-pairs of the collections of a schema.
+type moduleDecoder struct {
+	// collections are sorted by prefix
+	collections []*collectionSchemaCodec
+}
+
+func (m moduleDecoder) decodeKV(update schema.KVPairUpdate) ([]schema.ObjectUpdate, error) {
+	// prefixes within a schema don't overlap, so the only collection whose prefix can be a prefix
+	// of the key is the one with the greatest prefix which is not greater than the key
+	i := sort.Search(len(m.collections), func(i int) bool {
+		return bytes.Compare(m.collections[i].prefix, update.Key) > 0
+	})
+	if i == 0 || !bytes.HasPrefix(update.Key, m.collections[i-1].prefix) {
+		return nil, nil
+	}
+	return m.collections[i-1].decodeKVPair(update)
+}
+
+// collectionSchemaCodec describes a collection as an object type and decodes and encodes its kv-pairs.
+type collectionSchemaCodec struct {
+	objectType   schema.ObjectType
+	prefix       []byte
+	keyDecoder   func([]byte) (interface{}, error)
+	valueDecoder func([]byte) (interface{}, error)
+	keyEncoder   func(interface{}) ([]byte, error)
+	valueEncoder func(key, value interface{}) ([]byte, error)
+}
+
+func (c collectionSchemaCodec) decodeKVPair(update schema.KVPairUpdate) ([]schema.ObjectUpdate, error) {
+	key, err := c.keyDecoder(update.Key[len(c.prefix):])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key of collection %s: %w", c.objectType.Name, err)
+	}
+
+	if update.Delete {
+		return []schema.ObjectUpdate{{TypeName: c.objectType.Name, Key: key, Delete: true}}, nil
+	}
+
+	value, err := c.valueDecoder(update.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode value of collection %s: %w", c.objectType.Name, err)
+	}
+	return []schema.ObjectUpdate{{TypeName: c.objectType.Name, Key: key, Value: value}}, nil
+}
+
+func (c collectionSchemaCodec) encodeObjectUpdate(update schema.ObjectUpdate) ([]schema.KVPairUpdate, error) {
+	key, err := c.keyEncoder(update.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode key of collection %s: %w", c.objectType.Name, err)
+	}
+
+	if update.Delete {
+		return []schema.KVPairUpdate{{Key: key, Delete: true}}, nil
+	}
+
+	if _, ok := update.Value.(schema.MapValueUpdates); ok {
+		return nil, fmt.Errorf("cannot encode partial value updates of collection %s", c.objectType.Name)
+	}
+	value, err := c.valueEncoder(update.Key, update.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value of collection %s: %w", c.objectType.Name, err)
+	}
+	return []schema.KVPairUpdate{{Key: key, Value: value}}, nil
+}
+
+func (c collectionImpl[K, V]) schemaCodec() (*collectionSchemaCodec, error) {
+	keyCodec, err := codec.KeySchemaCodec(c.m.kc)
+	if err != nil {
+		return nil, err
+	}
+	valueCodec, err := codec.ValueSchemaCodec(c.m.vc)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &collectionSchemaCodec{
+		objectType: schema.ObjectType{
+			Name:        c.GetName(),
+			KeyFields:   append([]schema.Field(nil), keyCodec.Fields...),
+			ValueFields: append([]schema.Field(nil), valueCodec.Fields...),
+		},
+		prefix: c.GetPrefix(),
+		keyDecoder: func(bz []byte) (interface{}, error) {
+			_, key, err := c.m.kc.Decode(bz)
+			if err != nil {
+				return nil, err
+			}
+			return toSchemaType(keyCodec, key)
+		},
+		valueDecoder: func(bz []byte) (interface{}, error) {
+			value, err := c.m.vc.Decode(bz)
+			if err != nil {
+				return nil, err
+			}
+			return toSchemaType(valueCodec, value)
+		},
+		keyEncoder: func(value interface{}) ([]byte, error) {
+			key, err := fromSchemaType(keyCodec, value)
+			if err != nil {
+				return nil, err
+			}
+			return EncodeKeyWithPrefix(c.GetPrefix(), c.m.kc, key)
+		},
+		valueEncoder: func(key, value interface{}) ([]byte, error) {
+			var v V
+			var err error
+			if valueCodec.FromKeyedSchemaType != nil {
+				v, err = valueCodec.FromKeyedSchemaType(key, value)
+			} else {
+				v, err = fromSchemaType(valueCodec, value)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return c.m.vc.Encode(v)
+		},
+	}
+	ensureFieldNames(res.objectType.KeyFields, res.objectType.ValueFields)
+	return res, nil
+}
+
+// ensureFieldNames names the unnamed key and value fields of an object type after their position
+// as key, value or key1, key2, etc. if there are several, and renames fields whose names clash.
+func ensureFieldNames(keyFields, valueFields []schema.Field) {
+	names := map[string]bool{}
+	name := func(fields []schema.Field, prefix string) {
+		for i := range fields {
+			if fields[i].Name == "" {
+				fields[i].Name = prefix
+				if len(fields) > 1 {
+					fields[i].Name += strconv.Itoa(i + 1)
+				}
+			}
+			if names[fields[i].Name] {
+				fields[i].Name += "_" + strconv.Itoa(i+1)
+			}
+			names[fields[i].Name] = true
+		}
+	}
+	name(keyFields, "key")
+	name(valueFields, "value")
+}
+
+func toSchemaType[T any](cdc codec.SchemaCodec[T], value T) (interface{}, error) {
+	if cdc.ToSchemaType == nil {
+		return value, nil
+	}
+	return cdc.ToSchemaType(value)
+}
+
+func fromSchemaType[T any](cdc codec.SchemaCodec[T], value interface{}) (T, error) {
+	if cdc.FromSchemaType != nil {
+		return cdc.FromSchemaType(value)
+	}
+	t, ok := value.(T)
+	if !ok {
+		return t, fmt.Errorf("%w: expected %T, got %T", ErrEncoding, t, value)
+	}
+	return t, nil
+}
+
+// schemaCodecPart is the type erased schema codec of a part of a multipart key.
+type schemaCodecPart struct {
+	fields         []schema.Field
+	toSchemaType   func(interface{}) (interface{}, error)
+	fromSchemaType func(interface{}) (interface{}, error)
+}
+
+func keySchemaCodecPart[T any](keyCodec codec.KeyCodec[T]) (schemaCodecPart, error) {
+	cdc, err := codec.KeySchemaCodec(keyCodec)
+	if err != nil {
+		return schemaCodecPart{}, err
+	}
+	return schemaCodecPart{
+		fields:       cdc.Fields,
+		toSchemaType: func(value interface{}) (interface{}, error) { return toSchemaType(cdc, value.(T)) },
+		fromSchemaType: func(value interface{}) (interface{}, error) {
+			return fromSchemaType(cdc, value)
+		},
+	}, nil
+}
+
+// multipartSchemaCodec returns the schema codec of a multipart key whose parts are described by
+// parts and which is split into and joined from its parts by split and join.
+func multipartSchemaCodec[T any](
+	parts []schemaCodecPart,
+	split func(T) []interface{},
+	join func([]interface{}) T,
+) codec.SchemaCodec[T] {
+	var fields []schema.Field
+	for _, part := range parts {
+		fields = append(fields, part.fields...)
+	}
+
+	return codec.SchemaCodec[T]{
+		Fields: fields,
+		ToSchemaType: func(key T) (interface{}, error) {
+			var values []interface{}
+			for i, partValue := range split(key) {
+				value, err := parts[i].toSchemaType(partValue)
+				if err != nil {
+					return nil, err
+				}
+				switch len(parts[i].fields) {
+				case 0:
+				case 1:
+					values = append(values, value)
+				default:
+					partValues, ok := value.([]interface{})
+					if !ok || len(partValues) != len(parts[i].fields) {
+						return nil, fmt.Errorf("%w: expected %d values, got %v", ErrEncoding, len(parts[i].fields), value)
+					}
+					values = append(values, partValues...)
+				}
+			}
+			if len(fields) == 1 {
+				return values[0], nil
+			}
+			return values, nil
+		},
+		FromSchemaType: func(value interface{}) (T, error) {
+			values := []interface{}{value}
+			if len(fields) != 1 {
+				var ok bool
+				values, ok = value.([]interface{})
+				if !ok || len(values) != len(fields) {
+					var t T
+					return t, fmt.Errorf("%w: expected %d values, got %v", ErrEncoding, len(fields), value)
+				}
+			}
+
+			partValues := make([]interface{}, len(parts))
+			for i, part := range parts {
+				var schemaValue interface{}
+				switch n := len(part.fields); n {
+				case 0:
+				case 1:
+					schemaValue = values[0]
+				default:
+					schemaValue = values[:n]
+				}
+				values = values[len(part.fields):]
+
+				partValue, err := part.fromSchemaType(schemaValue)
+				if err != nil {
+					var t T
+					return t, err
+				}
+				partValues[i] = partValue
+			}
+			return join(partValues), nil
+		},
+	}
+}
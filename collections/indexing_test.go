@@ -0,0 +1,228 @@
+package collections
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/collections/codec"
+	"cosmossdk.io/schema"
+)
+
+type testParams struct {
+	MaxSupply uint64 `json:"max_supply"`
+}
+
+// testParamsValueCodec is a value codec which doesn't implement codec.HasSchemaCodec.
+type testParamsValueCodec struct{}
+
+func (testParamsValueCodec) Encode(value testParams) ([]byte, error) { return json.Marshal(value) }
+func (testParamsValueCodec) Decode(b []byte) (testParams, error) {
+	var p testParams
+	err := json.Unmarshal(b, &p)
+	return p, err
+}
+func (c testParamsValueCodec) EncodeJSON(value testParams) ([]byte, error) { return c.Encode(value) }
+func (c testParamsValueCodec) DecodeJSON(b []byte) (testParams, error)     { return c.Decode(b) }
+func (testParamsValueCodec) Stringify(value testParams) string             { return "params" }
+func (testParamsValueCodec) ValueType() string                             { return "testParams" }
+
+func TestSchema_ModuleCodec(t *testing.T) {
+	sk, ctx := deps()
+	sb := NewSchemaBuilder(sk)
+	balances := NewMap(sb, NewPrefix(1), "balances", PairKeyCodec(StringKey, StringKey), Uint64Value)
+	params := NewItem(sb, NewPrefix(2), "params", testParamsValueCodec{})
+	allowed := NewKeySet(sb, NewPrefix(3), "allowed", BytesKey)
+	heights := NewMap(sb, NewPrefix(4), "heights", TripleKeyCodec(StringKey, Int64Key, BoolKey), StringValue)
+	s, err := sb.Build()
+	require.NoError(t, err)
+
+	cdc, err := s.ModuleCodec(IndexingOptions{RetainDeletionsFor: []string{"balances"}})
+	require.NoError(t, err)
+
+	balancesType := lookupObjectType(t, cdc.Schema, "balances")
+	require.Equal(t, schema.ObjectType{
+		Name: "balances",
+		KeyFields: []schema.Field{
+			{Name: "key1", Kind: schema.StringKind},
+			{Name: "key2", Kind: schema.StringKind},
+		},
+		ValueFields:     []schema.Field{{Name: "value", Kind: schema.Uint64Kind}},
+		RetainDeletions: true,
+	}, balancesType)
+
+	paramsType := lookupObjectType(t, cdc.Schema, "params")
+	require.Empty(t, paramsType.KeyFields)
+	require.Equal(t, []schema.Field{{Name: "value", Kind: schema.JSONKind}}, paramsType.ValueFields)
+
+	allowedType := lookupObjectType(t, cdc.Schema, "allowed")
+	require.Equal(t, []schema.Field{{Name: "key", Kind: schema.BytesKind}}, allowedType.KeyFields)
+	require.Empty(t, allowedType.ValueFields)
+
+	require.NoError(t, balances.Set(ctx, Join("alice", "atom"), 10))
+	require.NoError(t, params.Set(ctx, testParams{MaxSupply: 100}))
+	require.NoError(t, allowed.Set(ctx, []byte{1, 2}))
+	require.NoError(t, heights.Set(ctx, Join3("bob", int64(-5), true), "foo"))
+
+	var updates []schema.ObjectUpdate
+	it, err := sk.OpenKVStore(ctx).Iterator(nil, nil)
+	require.NoError(t, err)
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		res, err := cdc.KVDecoder(schema.KVPairUpdate{Key: it.Key(), Value: it.Value()})
+		require.NoError(t, err)
+		for _, update := range res {
+			require.NoError(t, cdc.Schema.ValidateObjectUpdate(update))
+
+			// the encoder is the inverse of the decoder
+			pairs, err := cdc.KVEncoder(update)
+			require.NoError(t, err)
+			require.Equal(t, []schema.KVPairUpdate{{Key: it.Key(), Value: it.Value()}}, pairs)
+		}
+		updates = append(updates, res...)
+	}
+
+	require.Equal(t, []schema.ObjectUpdate{
+		{TypeName: "balances", Key: []interface{}{"alice", "atom"}, Value: uint64(10)},
+		{TypeName: "params", Value: json.RawMessage(`{"max_supply":100}`)},
+		{TypeName: "allowed", Key: []byte{1, 2}},
+		{TypeName: "heights", Key: []interface{}{"bob", int64(-5), true}, Value: "foo"},
+	}, updates)
+
+	res, err := cdc.KVDecoder(schema.KVPairUpdate{Key: []byte{1, 'b', 'o', 'b', 0, 'a'}, Delete: true})
+	require.NoError(t, err)
+	require.Equal(t, []schema.ObjectUpdate{{TypeName: "balances", Key: []interface{}{"bob", "a"}, Delete: true}}, res)
+
+	pairs, err := cdc.KVEncoder(schema.ObjectUpdate{TypeName: "balances", Key: []interface{}{"bob", "a"}, Delete: true})
+	require.NoError(t, err)
+	require.Equal(t, []schema.KVPairUpdate{{Key: []byte{1, 'b', 'o', 'b', 0, 'a'}, Delete: true}}, pairs)
+
+	_, err = cdc.KVEncoder(schema.ObjectUpdate{TypeName: "supply", Key: "atom"})
+	require.ErrorContains(t, err, "unknown collection supply")
+
+	_, err = cdc.KVEncoder(schema.ObjectUpdate{TypeName: "balances", Key: "bob", Value: uint64(1)})
+	require.ErrorIs(t, err, ErrEncoding)
+
+	// kv-pairs outside of the collections are ignored
+	res, err = cdc.KVDecoder(schema.KVPairUpdate{Key: []byte{5, 1}, Value: []byte{1}})
+	require.NoError(t, err)
+	require.Nil(t, res)
+}
+
+func lookupObjectType(t *testing.T, moduleSchema schema.ModuleSchema, name string) schema.ObjectType {
+	t.Helper()
+	typ, ok := moduleSchema.LookupType(name)
+	require.True(t, ok)
+	objectType, ok := typ.(schema.ObjectType)
+	require.True(t, ok)
+	return objectType
+}
+
+func TestSchema_ModuleCodec_unknownRetainDeletions(t *testing.T) {
+	sk, _ := deps()
+	sb := NewSchemaBuilder(sk)
+	NewMap(sb, NewPrefix(1), "balances", StringKey, Uint64Value)
+	s, err := sb.Build()
+	require.NoError(t, err)
+
+	_, err = s.ModuleCodec(IndexingOptions{RetainDeletionsFor: []string{"supply"}})
+	require.ErrorContains(t, err, "unknown collection supply")
+}
+
+func TestPairKeyCodec_SchemaCodec(t *testing.T) {
+	cdc, err := codec.KeySchemaCodec(PairKeyCodec(StringKey, PairKeyCodec(Uint64Key, BytesKey)))
+	require.NoError(t, err)
+	require.Equal(t, []schema.Field{{Kind: schema.StringKind}, {Kind: schema.Uint64Kind}, {Kind: schema.BytesKind}}, cdc.Fields)
+
+	key := Join("a", Join(uint64(1), []byte{2}))
+	value, err := cdc.ToSchemaType(key)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"a", uint64(1), []byte{2}}, value)
+
+	decoded, err := cdc.FromSchemaType(value)
+	require.NoError(t, err)
+	require.Equal(t, key, decoded)
+
+	_, err = cdc.FromSchemaType([]interface{}{"a"})
+	require.ErrorIs(t, err, ErrEncoding)
+}
+
+func TestNamedCodecs(t *testing.T) {
+	sk, _ := deps()
+	sb := NewSchemaBuilder(sk)
+	NewMap(sb, NewPrefix(1), "balances",
+		PairKeyCodec(codec.NamedKeyCodec(StringKey, "address"), codec.NamedKeyCodec(StringKey, "denom")),
+		codec.NamedValueCodec(Uint64Value, "amount"),
+	)
+	s, err := sb.Build()
+	require.NoError(t, err)
+
+	cdc, err := s.ModuleCodec(IndexingOptions{})
+	require.NoError(t, err)
+	typ, ok := cdc.Schema.LookupType("balances")
+	require.True(t, ok)
+	objectType := typ.(schema.ObjectType)
+	require.Equal(t, []schema.Field{{Name: "address", Kind: schema.StringKind}, {Name: "denom", Kind: schema.StringKind}}, objectType.KeyFields)
+	require.Equal(t, []schema.Field{{Name: "amount", Kind: schema.Uint64Kind}}, objectType.ValueFields)
+
+	_, err = codec.KeySchemaCodec(codec.NamedKeyCodec(PairKeyCodec(StringKey, StringKey), "address"))
+	require.ErrorContains(t, err, "expected 2 field names, got 1")
+}
+
+func TestCodecsWithSchema(t *testing.T) {
+	keyCodec := codec.KeyCodecWithSchema(BytesKey, codec.SchemaCodec[[]byte]{Fields: []schema.Field{{Name: "address", Kind: schema.AddressKind}}})
+	cdc, err := codec.KeySchemaCodec(keyCodec)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Field{{Name: "address", Kind: schema.AddressKind}}, cdc.Fields)
+	require.Equal(t, BytesKey.KeyType(), keyCodec.KeyType())
+
+	valueCodec := codec.ValueCodecWithSchema(testParamsValueCodec{}, codec.SchemaCodec[testParams]{
+		Fields:       []schema.Field{{Name: "max_supply", Kind: schema.Uint64Kind}},
+		ToSchemaType: func(p testParams) (interface{}, error) { return p.MaxSupply, nil },
+	})
+	vcdc, err := codec.ValueSchemaCodec(valueCodec)
+	require.NoError(t, err)
+	value, err := vcdc.ToSchemaType(testParams{MaxSupply: 5})
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), value)
+}
+
+func TestSchema_ModuleCodec_keyedValues(t *testing.T) {
+	type account struct {
+		ID      uint64 `json:"id"`
+		Balance uint64 `json:"balance"`
+	}
+	valueCodec := codec.ValueCodecWithSchema(NewJSONValueCodec[account](), codec.SchemaCodec[account]{
+		Fields:       []schema.Field{{Name: "balance", Kind: schema.Uint64Kind}},
+		ToSchemaType: func(a account) (interface{}, error) { return a.Balance, nil },
+		FromKeyedSchemaType: func(key, value interface{}) (account, error) {
+			return account{ID: key.(uint64), Balance: value.(uint64)}, nil
+		},
+	})
+
+	sk, ctx := deps()
+	sb := NewSchemaBuilder(sk)
+	accounts := NewMap(sb, NewPrefix(1), "accounts", codec.NamedKeyCodec(Uint64Key, "id"), valueCodec)
+	s, err := sb.Build()
+	require.NoError(t, err)
+	require.NoError(t, accounts.Set(ctx, 7, account{ID: 7, Balance: 100}))
+
+	cdc, err := s.ModuleCodec(IndexingOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []schema.Field{{Name: "balance", Kind: schema.Uint64Kind}}, lookupObjectType(t, cdc.Schema, "accounts").ValueFields)
+
+	// the id which the value leaves out is restored from the key
+	update := schema.ObjectUpdate{TypeName: "accounts", Key: uint64(7), Value: uint64(100)}
+	pairs, err := cdc.KVEncoder(update)
+	require.NoError(t, err)
+	require.Len(t, pairs, 1)
+	store := sk.OpenKVStore(ctx)
+	bz, err := store.Get(pairs[0].Key)
+	require.NoError(t, err)
+	require.Equal(t, bz, pairs[0].Value)
+
+	decoded, err := cdc.KVDecoder(pairs[0])
+	require.NoError(t, err)
+	require.Equal(t, []schema.ObjectUpdate{update}, decoded)
+}
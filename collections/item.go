@@ -65,3 +65,12 @@ func (noKey) DecodeJSON(b []byte) (noKey, error) {
 func (k noKey) EncodeNonTerminal(_ []byte, _ noKey) (int, error) { panic("must not be called") }
 func (k noKey) DecodeNonTerminal(_ []byte) (int, noKey, error)   { panic("must not be called") }
 func (k noKey) SizeNonTerminal(_ noKey) int                      { panic("must not be called") }
+
+// SchemaCodec implements the codec.HasSchemaCodec interface. Items have no key fields and are
+// exported as singleton object types.
+func (noKey) SchemaCodec() (codec.SchemaCodec[noKey], error) {
+	return codec.SchemaCodec[noKey]{
+		ToSchemaType:   func(noKey) (interface{}, error) { return nil, nil },
+		FromSchemaType: func(interface{}) (noKey, error) { return noKey{}, nil },
+	}, nil
+}
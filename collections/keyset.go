@@ -140,3 +140,11 @@ func (NoValue) Stringify(_ NoValue) string {
 func (n NoValue) ValueType() string {
 	return noValueValueType
 }
+
+// SchemaCodec implements the codec.HasSchemaCodec interface. Key sets have no value fields.
+func (NoValue) SchemaCodec() (codec.SchemaCodec[NoValue], error) {
+	return codec.SchemaCodec[NoValue]{
+		ToSchemaType:   func(NoValue) (interface{}, error) { return nil, nil },
+		FromSchemaType: func(interface{}) (NoValue, error) { return NoValue{}, nil },
+	}, nil
+}
@@ -181,6 +181,25 @@ func (p pairKeyCodec[K1, K2]) SizeNonTerminal(key Pair[K1, K2]) int {
 	return size
 }
 
+// SchemaCodec implements the codec.HasSchemaCodec interface. The fields of a pair are the fields
+// of its first part followed by the fields of its second part.
+func (p pairKeyCodec[K1, K2]) SchemaCodec() (codec.SchemaCodec[Pair[K1, K2]], error) {
+	part1, err := keySchemaCodecPart(p.keyCodec1)
+	if err != nil {
+		return codec.SchemaCodec[Pair[K1, K2]]{}, err
+	}
+	part2, err := keySchemaCodecPart(p.keyCodec2)
+	if err != nil {
+		return codec.SchemaCodec[Pair[K1, K2]]{}, err
+	}
+
+	return multipartSchemaCodec(
+		[]schemaCodecPart{part1, part2},
+		func(key Pair[K1, K2]) []interface{} { return []interface{}{key.K1(), key.K2()} },
+		func(parts []interface{}) Pair[K1, K2] { return Join(parts[0].(K1), parts[1].(K2)) },
+	), nil
+}
+
 // GENESIS
 
 type jsonPairKey [2]json.RawMessage
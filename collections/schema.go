@@ -26,6 +26,7 @@ func NewSchemaBuilderFromAccessor(accessorFunc func(ctx context.Context) store.K
 			storeAccessor:       accessorFunc,
 			collectionsByName:   map[string]Collection{},
 			collectionsByPrefix: map[string]Collection{},
+			indexSchemas:        map[string][]IndexSchema{},
 		},
 	}
 }
@@ -129,6 +130,8 @@ type Schema struct {
 	collectionsOrdered  []string
 	collectionsByPrefix map[string]Collection
 	collectionsByName   map[string]Collection
+	// indexSchemas are the schemas of the indexes of IndexedMaps by collection name
+	indexSchemas map[string][]IndexSchema
 }
 
 // NewSchema creates a new schema for the provided KVStoreService.
@@ -70,6 +70,31 @@ type tripleKeyCodec[K1, K2, K3 any] struct {
 	keyCodec3 codec.KeyCodec[K3]
 }
 
+// SchemaCodec implements the codec.HasSchemaCodec interface. The fields of a triple are the fields
+// of its parts in order.
+func (t tripleKeyCodec[K1, K2, K3]) SchemaCodec() (codec.SchemaCodec[Triple[K1, K2, K3]], error) {
+	part1, err := keySchemaCodecPart(t.keyCodec1)
+	if err != nil {
+		return codec.SchemaCodec[Triple[K1, K2, K3]]{}, err
+	}
+	part2, err := keySchemaCodecPart(t.keyCodec2)
+	if err != nil {
+		return codec.SchemaCodec[Triple[K1, K2, K3]]{}, err
+	}
+	part3, err := keySchemaCodecPart(t.keyCodec3)
+	if err != nil {
+		return codec.SchemaCodec[Triple[K1, K2, K3]]{}, err
+	}
+
+	return multipartSchemaCodec(
+		[]schemaCodecPart{part1, part2, part3},
+		func(key Triple[K1, K2, K3]) []interface{} { return []interface{}{key.K1(), key.K2(), key.K3()} },
+		func(parts []interface{}) Triple[K1, K2, K3] {
+			return Join3(parts[0].(K1), parts[1].(K2), parts[2].(K3))
+		},
+	), nil
+}
+
 type jsonTripleKey [3]json.RawMessage
 
 func (t tripleKeyCodec[K1, K2, K3]) EncodeJSON(value Triple[K1, K2, K3]) ([]byte, error) {
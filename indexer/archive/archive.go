@@ -0,0 +1,192 @@
+// Package archive provides an indexer target which writes blocks, transactions, events and object updates as
+// partitioned CSV files to a local directory or an S3-compatible store for data-lake ingestion.
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/schema/logutil"
+)
+
+// IndexerType is the type of the archive indexer target in the indexer config.
+const IndexerType = "archive"
+
+func init() {
+	indexer.Register(IndexerType, Init)
+}
+
+// Config is the indexer specific config of an archive indexer target.
+type Config struct {
+	// Path is the local directory in which the files are written. Either Path or S3 must be set.
+	Path string `json:"path"`
+
+	// S3 is the S3-compatible store to which the files are uploaded.
+	S3 *S3Config `json:"s3"`
+
+	// Format is the format of the files. Only "csv" is supported, which is the default.
+	Format string `json:"format"`
+
+	// Gzip compresses the files with gzip.
+	Gzip bool `json:"gzip"`
+
+	// BlocksPerFile is the number of blocks after which the files are written and new files are started. It
+	// defaults to DefaultBlocksPerFile.
+	BlocksPerFile uint64 `json:"blocks_per_file"`
+
+	// StagingDir is the local directory in which the files are written until they are complete. It defaults to
+	// the temporary directory of the system.
+	StagingDir string `json:"staging_dir"`
+}
+
+// S3Config is the config of an S3-compatible store. Requests are signed with AWS signature version 4 and use
+// path-style URLs, which are supported by AWS S3 and other S3-compatible stores such as MinIO.
+type S3Config struct {
+	// Endpoint is the URL of the store, such as "https://s3.us-east-1.amazonaws.com".
+	Endpoint string `json:"endpoint"`
+
+	// Region is the region of the bucket. It defaults to "us-east-1".
+	Region string `json:"region"`
+
+	// Bucket is the name of the bucket.
+	Bucket string `json:"bucket"`
+
+	// Prefix is prepended to the names of the files.
+	Prefix string `json:"prefix"`
+
+	// AccessKeyID and SecretAccessKey are the credentials with which requests are signed. If they are empty,
+	// requests are sent anonymously.
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+}
+
+const (
+	// FormatCSV is the CSV file format.
+	FormatCSV = "csv"
+
+	// DefaultBlocksPerFile is the default value of Config.BlocksPerFile.
+	DefaultBlocksPerFile = 1000
+
+	// LastBlockFileName is the name of the file which contains the last block whose data has been written.
+	LastBlockFileName = "_last_block"
+)
+
+// Init initializes an archive indexer target from the indexer config. The files of the blocks which are
+// buffered are written when the context of params is done.
+func Init(params indexer.InitParams) (indexer.InitResult, error) {
+	cfg, err := decodeConfig(params.Config.Config)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	ctx := params.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = logutil.NoopLogger{}
+	}
+
+	var store Store
+	if cfg.S3 != nil {
+		store = NewS3Store(*cfg.S3, nil)
+	} else {
+		store = DirStore(cfg.Path)
+	}
+
+	lastBlock, err := readLastBlock(ctx, store)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	stagingDir, err := ioutil.TempDir(cfg.StagingDir, "archive-indexer-")
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	w := &writer{
+		ctx:           ctx,
+		store:         store,
+		logger:        logger,
+		stagingDir:    stagingDir,
+		gzip:          cfg.Gzip,
+		blocksPerFile: cfg.BlocksPerFile,
+		files:         map[string]*stagedFile{},
+		schemas:       map[string]schema.ModuleSchema{},
+	}
+
+	if params.DoneWaitGroup != nil {
+		params.DoneWaitGroup.Add(1)
+	}
+	go func() {
+		<-ctx.Done()
+		w.mu.Lock()
+		if err := w.flush(context.Background()); err != nil {
+			logger.Error("failed to write buffered archive files on shutdown", "err", err)
+		}
+		w.closed = true
+		w.mu.Unlock()
+		_ = os.RemoveAll(stagingDir)
+		if params.DoneWaitGroup != nil {
+			params.DoneWaitGroup.Done()
+		}
+	}()
+
+	return indexer.InitResult{
+		Listener:           w.listener(),
+		LastBlockPersisted: int64(lastBlock),
+	}, nil
+}
+
+// readLastBlock returns the height in the last block file of a store, or 0 if it doesn't exist.
+func readLastBlock(ctx context.Context, store Store) (uint64, error) {
+	bz, found, err := store.Get(ctx, LastBlockFileName)
+	if err != nil || !found {
+		return 0, err
+	}
+	height, err := strconv.ParseUint(strings.TrimSpace(string(bz)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s file: %v", LastBlockFileName, err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+	return height, nil
+}
+
+func decodeConfig(rawConfig map[string]interface{}) (Config, error) {
+	bz, err := json.Marshal(rawConfig)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid archive indexer config: %v", err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(bz, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid archive indexer config: %v", err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+
+	switch {
+	case cfg.Path == "" && cfg.S3 == nil:
+		return Config{}, fmt.Errorf("either path or s3 must be set")
+	case cfg.Path != "" && cfg.S3 != nil:
+		return Config{}, fmt.Errorf("only one of path and s3 can be set")
+	case cfg.S3 != nil && (cfg.S3.Endpoint == "" || cfg.S3.Bucket == ""):
+		return Config{}, fmt.Errorf("s3.endpoint and s3.bucket are required")
+	}
+	if cfg.Format == "" {
+		cfg.Format = FormatCSV
+	}
+	if cfg.Format != FormatCSV {
+		return Config{}, fmt.Errorf("unsupported format %q, only %q is supported", cfg.Format, FormatCSV)
+	}
+	if cfg.BlocksPerFile == 0 {
+		cfg.BlocksPerFile = DefaultBlocksPerFile
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,11 @@
+module cosmossdk.io/indexer/archive
+
+// NOTE: we are staying on an earlier version of golang to avoid problems building
+// with older codebases.
+go 1.12
+
+// NOTE: cosmossdk.io/schema should be the only dependency here. This module only
+// uses the golang standard library to write files and to talk to S3-compatible stores.
+require cosmossdk.io/schema v0.1.1
+
+replace cosmossdk.io/schema => ../../schema
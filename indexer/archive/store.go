@@ -0,0 +1,227 @@
+package archive
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Store is the destination of the archive files. Names are slash-separated paths relative to the root of
+// the store.
+type Store interface {
+	// Put writes a file, replacing it if it exists.
+	Put(ctx context.Context, name string, data io.ReadSeeker) error
+
+	// Get reads a file. It returns false if the file doesn't exist.
+	Get(ctx context.Context, name string) ([]byte, bool, error)
+}
+
+// DirStore is a Store which writes the files to a local directory.
+type DirStore string
+
+// Put implements Store. The file is written next to its destination and renamed, so that it is either
+// complete or missing.
+func (d DirStore) Put(_ context.Context, name string, data io.ReadSeeker) error {
+	path := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get implements Store.
+func (d DirStore) Get(_ context.Context, name string) ([]byte, bool, error) {
+	bz, err := ioutil.ReadFile(filepath.Join(string(d), filepath.FromSlash(name)))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return bz, true, nil
+}
+
+// S3Store is a Store which uploads the files to a bucket of an S3-compatible store.
+type S3Store struct {
+	cfg        S3Config
+	httpClient *http.Client
+
+	// now returns the time at which requests are signed, it is replaced in tests
+	now func() time.Time
+}
+
+// NewS3Store returns a store for a bucket of an S3-compatible store. If httpClient is nil,
+// http.DefaultClient is used.
+func NewS3Store(cfg S3Config, httpClient *http.Client) *S3Store {
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &S3Store{cfg: cfg, httpClient: httpClient, now: time.Now}
+}
+
+// Put implements Store.
+func (s *S3Store) Put(ctx context.Context, name string, data io.ReadSeeker) error {
+	hash := sha256.New()
+	size, err := io.Copy(hash, data)
+	if err != nil {
+		return err
+	}
+	if _, err := data.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// the body isn't closed by the client since it is owned by the caller
+	req, err := s.newRequest(ctx, http.MethodPut, name, ioutil.NopCloser(data), hex.EncodeToString(hash.Sum(nil)))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+
+	status, body, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("PUT %s returned status %d: %s", name, status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// Get implements Store.
+func (s *S3Store) Get(ctx context.Context, name string) ([]byte, bool, error) {
+	emptyHash := sha256.Sum256(nil)
+	req, err := s.newRequest(ctx, http.MethodGet, name, nil, hex.EncodeToString(emptyHash[:]))
+	if err != nil {
+		return nil, false, err
+	}
+
+	status, body, err := s.do(req)
+	switch {
+	case err != nil:
+		return nil, false, err
+	case status == http.StatusNotFound:
+		return nil, false, nil
+	case status < 200 || status >= 300:
+		return nil, false, fmt.Errorf("GET %s returned status %d: %s", name, status, strings.TrimSpace(string(body)))
+	}
+	return body, true, nil
+}
+
+// newRequest returns a signed request for an object. payloadHash is the hex encoded SHA-256 hash of the body.
+func (s *S3Store) newRequest(ctx context.Context, method, name string, body io.Reader, payloadHash string) (*http.Request, error) {
+	key := strings.TrimPrefix(s.cfg.Prefix+name, "/")
+	req, err := http.NewRequest(method, s.cfg.Endpoint+"/"+uriEscape(s.cfg.Bucket)+"/"+uriEscapePath(key), body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	if s.cfg.AccessKeyID != "" {
+		s.sign(req, payloadHash, s.now().UTC())
+	}
+	return req, nil
+}
+
+func (s *S3Store) do(req *http.Request) (int, []byte, error) {
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
+// sign adds the AWS signature version 4 of a request, which signs the host, x-amz-content-sha256 and x-amz-date
+// headers, to its Authorization header.
+func (s *S3Store) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("x-amz-date", amzDate)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	signingKey := []byte("AWS4" + s.cfg.SecretAccessKey)
+	for _, part := range []string{date, s.cfg.Region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// uriEscapePath escapes each segment of a slash-separated path with uriEscape.
+func uriEscapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = uriEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// uriEscape escapes all bytes except the unreserved characters as required by AWS signature version 4.
+func uriEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
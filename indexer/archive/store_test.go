@@ -0,0 +1,103 @@
+package archive
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDirStore(t *testing.T) {
+	store := DirStore(t.TempDir())
+	ctx := context.Background()
+
+	if _, found, err := store.Get(ctx, LastBlockFileName); err != nil || found {
+		t.Fatalf("expected missing file, got %v, %v", found, err)
+	}
+	if err := store.Put(ctx, "txs/start_height=1/data.csv", strings.NewReader("height\n")); err != nil {
+		t.Fatal(err)
+	}
+	bz, found, err := store.Get(ctx, "txs/start_height=1/data.csv")
+	if err != nil || !found || string(bz) != "height\n" {
+		t.Fatalf("unexpected file %q, %v, %v", bz, found, err)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	objects := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=key/20240102/eu-west-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") {
+			t.Errorf("unexpected authorization %q", auth)
+		}
+		if r.Header.Get("x-amz-date") != "20240102T030405Z" {
+			t.Errorf("unexpected date %q", r.Header.Get("x-amz-date"))
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			bz, _ := ioutil.ReadAll(r.Body)
+			objects[r.URL.EscapedPath()] = string(bz)
+		case http.MethodGet:
+			obj, ok := objects[r.URL.EscapedPath()]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(obj))
+		}
+	}))
+	defer server.Close()
+
+	store := NewS3Store(S3Config{
+		Endpoint:        server.URL + "/",
+		Region:          "eu-west-1",
+		Bucket:          "archive",
+		Prefix:          "chain/",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+	}, server.Client())
+	store.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+	ctx := context.Background()
+
+	if _, found, err := store.Get(ctx, LastBlockFileName); err != nil || found {
+		t.Fatalf("expected missing file, got %v, %v", found, err)
+	}
+	if err := store.Put(ctx, "object_updates/module=bank/start_height=1/data.csv", strings.NewReader("height\n")); err != nil {
+		t.Fatal(err)
+	}
+	if objects["/archive/chain/object_updates/module%3Dbank/start_height%3D1/data.csv"] != "height\n" {
+		t.Fatalf("unexpected objects %v", objects)
+	}
+	bz, found, err := store.Get(ctx, "object_updates/module=bank/start_height=1/data.csv")
+	if err != nil || !found || string(bz) != "height\n" {
+		t.Fatalf("unexpected file %q, %v, %v", bz, found, err)
+	}
+}
+
+func TestDecodeConfig(t *testing.T) {
+	cfg, err := decodeConfig(map[string]interface{}{"path": "archive"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Format != FormatCSV || cfg.BlocksPerFile != DefaultBlocksPerFile {
+		t.Fatalf("expected defaults, got %+v", cfg)
+	}
+
+	for _, tc := range []struct {
+		config map[string]interface{}
+		err    string
+	}{
+		{map[string]interface{}{}, "either path or s3 must be set"},
+		{map[string]interface{}{"path": "archive", "s3": map[string]interface{}{"bucket": "b"}}, "only one of path and s3"},
+		{map[string]interface{}{"s3": map[string]interface{}{"bucket": "b"}}, "s3.endpoint and s3.bucket are required"},
+		{map[string]interface{}{"path": "archive", "format": "parquet"}, `unsupported format "parquet"`},
+	} {
+		if _, err := decodeConfig(tc.config); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Fatalf("expected error %q for %v, got %v", tc.err, tc.config, err)
+		}
+	}
+}
@@ -0,0 +1,368 @@
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/logutil"
+)
+
+const (
+	// BlocksTableName is the name of the directory in which blocks are written.
+	BlocksTableName = "blocks"
+
+	// TxsTableName is the name of the directory in which transactions are written.
+	TxsTableName = "txs"
+
+	// EventsTableName is the name of the directory in which events are written.
+	EventsTableName = "events"
+
+	// ObjectUpdatesTableName is the name of the directory in which object updates are written, with a
+	// module=<module name> partition for each module.
+	ObjectUpdatesTableName = "object_updates"
+)
+
+// tableColumns are the columns of the files of each table, which are written as the header of the files.
+var tableColumns = map[string][]string{
+	BlocksTableName:        {"height", "header"},
+	TxsTableName:           {"height", "tx_index", "data"},
+	EventsTableName:        {"height", "tx_index", "msg_index", "event_index", "type", "module_name", "data", "attributes"},
+	ObjectUpdatesTableName: {"height", "update_index", "type_name", "key", "value", "delete"},
+}
+
+// writer writes the rows of the indexed data to staged files, one for each partition, which are written to the
+// store every blocksPerFile blocks. All fields are guarded by mu.
+type writer struct {
+	mu            sync.Mutex
+	ctx           context.Context
+	store         Store
+	logger        logutil.Logger
+	stagingDir    string
+	gzip          bool
+	blocksPerFile uint64
+	closed        bool
+
+	height  uint64
+	schemas map[string]schema.ModuleSchema
+
+	// pending are the rows of the block which isn't committed yet
+	pending []row
+
+	// files are the staged files by partition, which contain the rows of the committed blocks from startHeight
+	// to lastHeight
+	files       map[string]*stagedFile
+	startHeight uint64
+	lastHeight  uint64
+	numBlocks   uint64
+}
+
+// row is a row of a partition, which is the directory of a table or the module partition of the object updates.
+type row struct {
+	partition string
+	table     string
+	values    []string
+}
+
+// listener returns the listener of the writer. Object updates are collected with appdata.BatchingListener
+// and the rows of a block are added to the staged files when it is committed.
+func (w *writer) listener() appdata.Listener {
+	return appdata.BatchingListener(appdata.Listener{
+		InitializeModuleData: w.initializeModuleData,
+		StartBlock:           w.startBlock,
+		OnTx:                 w.onTx,
+		OnEvent:              w.onEvent,
+		Commit:               w.commit,
+	}, appdata.CommitBatchFunc(w.commitBatch))
+}
+
+func (w *writer) initializeModuleData(data appdata.ModuleInitializationData) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.schemas[data.ModuleName] = data.Schema
+	return nil
+}
+
+func (w *writer) startBlock(data appdata.StartBlockData) error {
+	header, err := jsonString(data.HeaderJSON)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.height = data.Height
+	w.pending = w.pending[:0]
+	w.add(BlocksTableName, BlocksTableName, formatUint(data.Height), header)
+	return nil
+}
+
+func (w *writer) onTx(data appdata.TxData) error {
+	txJSON, err := jsonString(data.JSON)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.add(TxsTableName, TxsTableName, formatUint(w.height), strconv.Itoa(int(data.TxIndex)), txJSON)
+	return nil
+}
+
+func (w *writer) onEvent(data appdata.EventData) error {
+	eventJSON, err := jsonString(data.Data)
+	if err != nil {
+		return err
+	}
+
+	var attributes string
+	if data.Attributes != nil {
+		attrs, err := data.Attributes()
+		if err != nil {
+			return err
+		}
+		obj := make(map[string]interface{}, len(attrs))
+		for _, attr := range attrs {
+			obj[attr.Key] = attr.Value
+		}
+		bz, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		attributes = string(bz)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.add(EventsTableName, EventsTableName, formatUint(w.height), strconv.Itoa(int(data.TxIndex)),
+		formatUint(uint64(data.MsgIndex)), formatUint(uint64(data.EventIndex)), data.Type, data.ModuleName, eventJSON, attributes)
+	return nil
+}
+
+func (w *writer) commitBatch(batch appdata.BlockBatch) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var updateIndex uint64
+	for _, data := range batch.Updates {
+		modSchema, ok := w.schemas[data.ModuleName]
+		if !ok {
+			return fmt.Errorf("module %s not initialized", data.ModuleName)
+		}
+
+		for _, update := range data.Updates {
+			typ, ok := lookupObjectType(modSchema, update.TypeName)
+			if !ok {
+				return fmt.Errorf("unknown object type %q in module %s", update.TypeName, data.ModuleName)
+			}
+
+			key, err := fieldsJSON(typ.KeyFields, update.Key, schema.HexAddressCodec{})
+			if err != nil {
+				return fmt.Errorf("invalid key of %s update in module %s: %v", update.TypeName, data.ModuleName, err) //nolint:errorlint // using %v for go 1.12 compat
+			}
+
+			var value string
+			if !update.Delete {
+				value, err = fieldsJSON(typ.ValueFields, update.Value, schema.HexAddressCodec{})
+				if err != nil {
+					return fmt.Errorf("invalid value of %s update in module %s: %v", update.TypeName, data.ModuleName, err) //nolint:errorlint // using %v for go 1.12 compat
+				}
+			}
+
+			w.add(ObjectUpdatesTableName+"/module="+data.ModuleName, ObjectUpdatesTableName, formatUint(batch.Height),
+				formatUint(updateIndex), update.TypeName, key, value, strconv.FormatBool(update.Delete))
+			updateIndex++
+		}
+	}
+	return nil
+}
+
+func (w *writer) commit(appdata.CommitData) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return fmt.Errorf("archive indexer is shut down")
+	}
+
+	if w.numBlocks == 0 {
+		w.startHeight = w.height
+	}
+	for _, r := range w.pending {
+		if err := w.write(r); err != nil {
+			return err
+		}
+	}
+	w.pending = w.pending[:0]
+	w.lastHeight = w.height
+	w.numBlocks++
+
+	if w.numBlocks < w.blocksPerFile {
+		return nil
+	}
+	return w.flush(w.ctx)
+}
+
+// add adds a row to the pending rows of the block.
+func (w *writer) add(partition, table string, values ...string) {
+	w.pending = append(w.pending, row{partition: partition, table: table, values: values})
+}
+
+// write writes a row to the staged file of its partition, which is created if it doesn't exist.
+func (w *writer) write(r row) error {
+	f, ok := w.files[r.partition]
+	if !ok {
+		var err error
+		f, err = newStagedFile(w.stagingDir, w.gzip, tableColumns[r.table])
+		if err != nil {
+			return err
+		}
+		w.files[r.partition] = f
+	}
+	return f.csv.Write(r.values)
+}
+
+// flush writes the staged files of the committed blocks to the store and then the last block file. The blocks
+// file is written after the other files so that a block is only visible in it once all of its data has been
+// written.
+func (w *writer) flush(ctx context.Context) error {
+	if w.numBlocks == 0 {
+		return nil
+	}
+
+	partitions := make([]string, 0, len(w.files))
+	for partition := range w.files {
+		if partition != BlocksTableName {
+			partitions = append(partitions, partition)
+		}
+	}
+	sort.Strings(partitions)
+	if _, ok := w.files[BlocksTableName]; ok {
+		partitions = append(partitions, BlocksTableName)
+	}
+
+	// uploaded files are removed so that only the remaining files are written if flush is retried after an error
+	for _, partition := range partitions {
+		name := w.fileName(partition)
+		if err := w.files[partition].upload(ctx, w.store, name); err != nil {
+			return fmt.Errorf("failed to write %s: %v", name, err) //nolint:errorlint // using %v for go 1.12 compat
+		}
+		w.logger.Debug("wrote archive file", "file", name)
+		delete(w.files, partition)
+	}
+
+	if err := w.store.Put(ctx, LastBlockFileName, strings.NewReader(formatUint(w.lastHeight)+"\n")); err != nil {
+		return fmt.Errorf("failed to write %s: %v", LastBlockFileName, err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+	w.numBlocks = 0
+	return nil
+}
+
+// fileName returns the name of the file of a partition for the staged blocks. Files are partitioned by the
+// height of their first block, so a file which is written again after a restart replaces the previous one.
+func (w *writer) fileName(partition string) string {
+	name := fmt.Sprintf("%s/start_height=%d/data.csv", partition, w.startHeight)
+	if w.gzip {
+		name += ".gz"
+	}
+	return name
+}
+
+// stagedFile is a CSV file in the staging directory.
+type stagedFile struct {
+	file     *os.File
+	gzip     *gzip.Writer
+	csv      *csv.Writer
+	finished bool
+}
+
+func newStagedFile(dir string, compress bool, columns []string) (*stagedFile, error) {
+	file, err := ioutil.TempFile(dir, "*.csv")
+	if err != nil {
+		return nil, err
+	}
+
+	f := &stagedFile{file: file}
+	var out io.Writer = file
+	if compress {
+		f.gzip = gzip.NewWriter(file)
+		out = f.gzip
+	}
+	f.csv = csv.NewWriter(out)
+	if err := f.csv.Write(columns); err != nil {
+		_ = f.discard()
+		return nil, err
+	}
+	return f, nil
+}
+
+// upload finishes the file, writes it to the store and removes it from the staging directory.
+func (f *stagedFile) upload(ctx context.Context, store Store, name string) error {
+	if !f.finished {
+		f.csv.Flush()
+		if err := f.csv.Error(); err != nil {
+			return err
+		}
+		if f.gzip != nil {
+			if err := f.gzip.Close(); err != nil {
+				return err
+			}
+		}
+		f.finished = true
+	}
+
+	if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := store.Put(ctx, name, f.file); err != nil {
+		return err
+	}
+	return f.discard()
+}
+
+func (f *stagedFile) discard() error {
+	_ = f.file.Close()
+	return os.Remove(f.file.Name())
+}
+
+// lookupObjectType returns the object type with the name in the module schema.
+func lookupObjectType(modSchema schema.ModuleSchema, name string) (schema.ObjectType, bool) {
+	typ, ok := modSchema.LookupType(name)
+	if !ok {
+		return schema.ObjectType{}, false
+	}
+	objType, ok := typ.(schema.ObjectType)
+	return objType, ok
+}
+
+// jsonString returns the JSON of a lazy JSON value as a string, or an empty string if it isn't provided.
+func jsonString(toJSON appdata.ToJSON) (string, error) {
+	if toJSON == nil {
+		return "", nil
+	}
+
+	bz, err := toJSON()
+	if err != nil {
+		return "", err
+	}
+	return string(bz), nil
+}
+
+func formatUint(x uint64) string {
+	return strconv.FormatUint(x, 10)
+}
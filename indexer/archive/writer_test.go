@@ -0,0 +1,185 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/logutil"
+)
+
+var testModuleSchema = func() schema.ModuleSchema {
+	s, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:        "balances",
+		KeyFields:   []schema.Field{{Name: "address", Kind: schema.AddressKind}, {Name: "denom", Kind: schema.StringKind}},
+		ValueFields: []schema.Field{{Name: "amount", Kind: schema.IntegerStringKind}},
+	}})
+	if err != nil {
+		panic(err)
+	}
+	return s
+}()
+
+// memStore is a Store which keeps the files in memory and records the order in which they are written.
+type memStore struct {
+	files map[string][]byte
+	order []string
+}
+
+func (m *memStore) Put(_ context.Context, name string, data io.ReadSeeker) error {
+	bz, err := ioutil.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	m.files[name] = bz
+	m.order = append(m.order, name)
+	return nil
+}
+
+func (m *memStore) Get(_ context.Context, name string) ([]byte, bool, error) {
+	bz, ok := m.files[name]
+	return bz, ok, nil
+}
+
+func TestWriter(t *testing.T) {
+	store := &memStore{files: map[string][]byte{}}
+	w := &writer{
+		ctx:           context.Background(),
+		store:         store,
+		logger:        logutil.NoopLogger{},
+		stagingDir:    t.TempDir(),
+		blocksPerFile: 2,
+		files:         map[string]*stagedFile{},
+		schemas:       map[string]schema.ModuleSchema{},
+	}
+	listener := w.listener()
+
+	check := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check(listener.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "bank", Schema: testModuleSchema}))
+
+	// the first block is staged until the second block is committed
+	check(listener.StartBlock(appdata.StartBlockData{Height: 1}))
+	check(listener.OnObjectUpdate(appdata.ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{
+		{TypeName: "balances", Key: []interface{}{[]byte{0x01}, "stake"}, Value: "100"},
+	}}))
+	check(listener.Commit(appdata.CommitData{}))
+	if len(store.files) != 0 {
+		t.Fatalf("expected no files, got %v", store.order)
+	}
+
+	check(listener.StartBlock(appdata.StartBlockData{Height: 2, HeaderJSON: func() (json.RawMessage, error) {
+		return json.RawMessage(`{"time":"2024-01-01T00:00:00Z"}`), nil
+	}}))
+	check(listener.OnTx(appdata.TxData{TxIndex: 0, JSON: func() (json.RawMessage, error) {
+		return json.RawMessage(`{"memo":"test"}`), nil
+	}}))
+	check(listener.OnEvent(appdata.EventData{TxIndex: 0, MsgIndex: 1, EventIndex: 2, Type: "transfer", ModuleName: "bank",
+		Attributes: func() ([]appdata.EventAttribute, error) {
+			return []appdata.EventAttribute{{Key: "amount", Value: "100stake"}}, nil
+		},
+	}))
+	check(listener.OnObjectUpdate(appdata.ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{
+		{TypeName: "balances", Key: []interface{}{[]byte{0x01}, "stake"}, Delete: true},
+		{TypeName: "balances", Key: []interface{}{[]byte{0x02}, "stake"}, Value: "200"},
+	}}))
+	check(listener.Commit(appdata.CommitData{}))
+
+	expectedOrder := []string{
+		"events/start_height=1/data.csv",
+		"object_updates/module=bank/start_height=1/data.csv",
+		"txs/start_height=1/data.csv",
+		"blocks/start_height=1/data.csv",
+		LastBlockFileName,
+	}
+	if !reflect.DeepEqual(store.order, expectedOrder) {
+		t.Fatalf("expected files to be written in order %v, got %v", expectedOrder, store.order)
+	}
+
+	expected := map[string]string{
+		"blocks/start_height=1/data.csv": "height,header\n1,\n2,\"{\"\"time\"\":\"\"2024-01-01T00:00:00Z\"\"}\"\n",
+		"txs/start_height=1/data.csv":    "height,tx_index,data\n2,0,\"{\"\"memo\"\":\"\"test\"\"}\"\n",
+		"events/start_height=1/data.csv": "height,tx_index,msg_index,event_index,type,module_name,data,attributes\n" +
+			"2,0,1,2,transfer,bank,,\"{\"\"amount\"\":\"\"100stake\"\"}\"\n",
+		"object_updates/module=bank/start_height=1/data.csv": "height,update_index,type_name,key,value,delete\n" +
+			"1,0,balances,\"{\"\"address\"\":\"\"0x01\"\",\"\"denom\"\":\"\"stake\"\"}\",\"{\"\"amount\"\":\"\"100\"\"}\",false\n" +
+			"2,0,balances,\"{\"\"address\"\":\"\"0x01\"\",\"\"denom\"\":\"\"stake\"\"}\",,true\n" +
+			"2,1,balances,\"{\"\"address\"\":\"\"0x02\"\",\"\"denom\"\":\"\"stake\"\"}\",\"{\"\"amount\"\":\"\"200\"\"}\",false\n",
+		LastBlockFileName: "2\n",
+	}
+	for name, content := range expected {
+		if string(store.files[name]) != content {
+			t.Fatalf("expected %s to contain %q, got %q", name, content, store.files[name])
+		}
+	}
+
+	// the rows of a block which isn't committed are discarded and the next files start after the last block
+	check(listener.StartBlock(appdata.StartBlockData{Height: 3}))
+	check(listener.OnTx(appdata.TxData{TxIndex: 0}))
+	check(listener.StartBlock(appdata.StartBlockData{Height: 3}))
+	check(listener.Commit(appdata.CommitData{}))
+	check(w.flush(context.Background()))
+	if got := string(store.files["blocks/start_height=3/data.csv"]); got != "height,header\n3,\n" {
+		t.Fatalf("unexpected blocks file %q", got)
+	}
+	if _, ok := store.files["txs/start_height=3/data.csv"]; ok {
+		t.Fatal("expected no transactions file for the uncommitted block")
+	}
+	if lastBlock, err := readLastBlock(context.Background(), store); err != nil || lastBlock != 3 {
+		t.Fatalf("expected last block 3, got %d, %v", lastBlock, err)
+	}
+}
+
+func TestWriterGzip(t *testing.T) {
+	store := &memStore{files: map[string][]byte{}}
+	w := &writer{
+		ctx:           context.Background(),
+		store:         store,
+		logger:        logutil.NoopLogger{},
+		stagingDir:    t.TempDir(),
+		gzip:          true,
+		blocksPerFile: 1,
+		files:         map[string]*stagedFile{},
+		schemas:       map[string]schema.ModuleSchema{},
+	}
+	listener := w.listener()
+	if err := listener.StartBlock(appdata.StartBlockData{Height: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if err := listener.Commit(appdata.CommitData{}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(store.files["blocks/start_height=7/data.csv.gz"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bz, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bz) != "height,header\n7,\n" {
+		t.Fatalf("unexpected blocks file %q", bz)
+	}
+}
+
+func TestWriterClosed(t *testing.T) {
+	w := &writer{files: map[string]*stagedFile{}, closed: true}
+	err := w.listener().Commit(appdata.CommitData{})
+	if err == nil || !strings.Contains(err.Error(), "shut down") {
+		t.Fatalf("expected error after shutdown, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+// Package clickhouse provides an indexer target which writes the append-only history of blocks, transactions,
+// events and object updates to ClickHouse for analytics workloads.
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/schema/logutil"
+)
+
+// IndexerType is the type of the ClickHouse indexer target in the indexer config.
+const IndexerType = "clickhouse"
+
+func init() {
+	indexer.Register(IndexerType, Init)
+}
+
+// Config is the indexer specific config of a ClickHouse indexer target.
+type Config struct {
+	// DatabaseURL is the ClickHouse data source name, such as "clickhouse://localhost:9000/default".
+	DatabaseURL string `json:"database_url"`
+
+	// DatabaseDriver is the ClickHouse database/sql driver to use. This defaults to "clickhouse", the name of
+	// the github.com/ClickHouse/clickhouse-go/v2 driver, which needs to be imported by the app.
+	DatabaseDriver string `json:"database_driver"`
+
+	// BatchSize is the number of buffered rows after which they are inserted when a block is committed. It
+	// defaults to DefaultBatchSize.
+	BatchSize int `json:"batch_size"`
+
+	// FlushInterval is the maximum time for which rows are buffered before they are inserted when a block is
+	// committed, such as "5s". It defaults to DefaultFlushInterval.
+	FlushInterval string `json:"flush_interval"`
+
+	// RetentionDays is the number of days after which rows are deleted by ClickHouse with a TTL. If it is
+	// zero, rows are kept forever.
+	RetentionDays uint32 `json:"retention_days"`
+}
+
+const (
+	// DefaultDatabaseDriver is the default value of Config.DatabaseDriver.
+	DefaultDatabaseDriver = "clickhouse"
+
+	// DefaultBatchSize is the default value of Config.BatchSize.
+	DefaultBatchSize = 100000
+
+	// DefaultFlushInterval is the default value of Config.FlushInterval.
+	DefaultFlushInterval = 5 * time.Second
+)
+
+// Init initializes a ClickHouse indexer target from the indexer config. Buffered rows are inserted when the
+// context of params is done.
+func Init(params indexer.InitParams) (indexer.InitResult, error) {
+	cfg, err := decodeConfig(params.Config.Config)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	flushInterval := DefaultFlushInterval
+	if cfg.FlushInterval != "" {
+		flushInterval, err = time.ParseDuration(cfg.FlushInterval)
+		if err != nil {
+			return indexer.InitResult{}, fmt.Errorf("invalid flush_interval: %v", err) //nolint:errorlint // using %v for go 1.12 compat
+		}
+	}
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	ctx := params.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = logutil.NoopLogger{}
+	}
+
+	db, err := sql.Open(cfg.DatabaseDriver, cfg.DatabaseURL)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	for _, t := range tables {
+		buf := new(strings.Builder)
+		if err := createTableSql(buf, t, cfg.RetentionDays); err != nil {
+			return indexer.InitResult{}, err
+		}
+		if _, err := db.ExecContext(ctx, buf.String()); err != nil {
+			return indexer.InitResult{}, fmt.Errorf("failed to create table %s: %v", t.name, err) //nolint:errorlint // using %v for go 1.12 compat
+		}
+	}
+
+	var lastBlock uint64
+	err = db.QueryRowContext(ctx, fmt.Sprintf("SELECT max(height) FROM %s", BlocksTableName)).Scan(&lastBlock)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	w := &writer{
+		ctx:           ctx,
+		db:            db,
+		logger:        logger,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		lastFlush:     time.Now(),
+		rows:          map[string][][]interface{}{},
+		schemas:       map[string]schema.ModuleSchema{},
+	}
+
+	if params.DoneWaitGroup != nil {
+		params.DoneWaitGroup.Add(1)
+	}
+	go func() {
+		<-ctx.Done()
+		w.mu.Lock()
+		if err := w.flush(context.Background()); err != nil {
+			logger.Error("failed to insert buffered rows into clickhouse on shutdown", "err", err)
+		}
+		w.closed = true
+		w.mu.Unlock()
+		_ = db.Close()
+		if params.DoneWaitGroup != nil {
+			params.DoneWaitGroup.Done()
+		}
+	}()
+
+	return indexer.InitResult{
+		Listener:           w.listener(),
+		LastBlockPersisted: int64(lastBlock),
+	}, nil
+}
+
+func decodeConfig(rawConfig map[string]interface{}) (Config, error) {
+	bz, err := json.Marshal(rawConfig)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid clickhouse indexer config: %v", err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(bz, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid clickhouse indexer config: %v", err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+
+	if cfg.DatabaseURL == "" {
+		return Config{}, fmt.Errorf("missing database URL")
+	}
+	if cfg.DatabaseDriver == "" {
+		cfg.DatabaseDriver = DefaultDatabaseDriver
+	}
+
+	return cfg, nil
+}
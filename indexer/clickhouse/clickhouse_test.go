@@ -0,0 +1,46 @@
+package clickhouse
+
+import (
+	"strings"
+	"testing"
+
+	"cosmossdk.io/schema/indexer"
+)
+
+func TestInit(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]interface{}
+		errContains string
+	}{
+		{
+			name:        "missing database URL",
+			config:      map[string]interface{}{},
+			errContains: "missing database URL",
+		},
+		{
+			name:        "invalid config",
+			config:      map[string]interface{}{"database_url": 1},
+			errContains: "invalid clickhouse indexer config",
+		},
+		{
+			name:        "invalid flush interval",
+			config:      map[string]interface{}{"database_url": "clickhouse://localhost:9000", "flush_interval": "soon"},
+			errContains: "invalid flush_interval",
+		},
+		{
+			name:        "driver not registered",
+			config:      map[string]interface{}{"database_url": "clickhouse://localhost:9000"},
+			errContains: `unknown driver "clickhouse"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Init(indexer.InitParams{Config: indexer.Config{Type: IndexerType, Config: tt.config}})
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error containing %q, got: %v", tt.errContains, err)
+			}
+		})
+	}
+}
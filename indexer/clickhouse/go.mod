@@ -0,0 +1,12 @@
+module cosmossdk.io/indexer/clickhouse
+
+// NOTE: we are staying on an earlier version of golang to avoid problems building
+// with older codebases.
+go 1.12
+
+// NOTE: cosmossdk.io/schema should be the only dependency here. This module only
+// uses the golang standard library (database/sql) and the ClickHouse database/sql
+// driver, github.com/ClickHouse/clickhouse-go/v2, is registered by the app.
+require cosmossdk.io/schema v0.1.1
+
+replace cosmossdk.io/schema => ../../schema
@@ -0,0 +1,127 @@
+package clickhouse
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cosmossdk.io/schema"
+)
+
+// fieldsJSON encodes the key or value of an object update as a JSON object with the field names as keys.
+// If value is a schema.ValueUpdates, only the updated fields are included.
+func fieldsJSON(fields []schema.Field, value interface{}, addressCodec schema.AddressCodec) (string, error) {
+	obj := make(map[string]interface{}, len(fields))
+
+	if valueUpdates, ok := value.(schema.ValueUpdates); ok {
+		byName := make(map[string]schema.Field, len(fields))
+		for _, field := range fields {
+			byName[field.Name] = field
+		}
+
+		var fieldErr error
+		err := valueUpdates.Iterate(func(name string, v interface{}) bool {
+			field, ok := byName[name]
+			if !ok {
+				fieldErr = fmt.Errorf("unknown field %q", name)
+				return false
+			}
+			obj[name], fieldErr = jsonValue(field, v, addressCodec)
+			return fieldErr == nil
+		})
+		if err != nil {
+			return "", err
+		}
+		if fieldErr != nil {
+			return "", fieldErr
+		}
+	} else {
+		var values []interface{}
+		switch len(fields) {
+		case 0:
+		case 1:
+			values = []interface{}{value}
+		default:
+			var ok bool
+			values, ok = value.([]interface{})
+			if !ok || len(values) != len(fields) {
+				return "", fmt.Errorf("expected slice of %d values, got %T", len(fields), value)
+			}
+		}
+
+		for i, field := range fields {
+			v, err := jsonValue(field, values[i], addressCodec)
+			if err != nil {
+				return "", err
+			}
+			obj[field.Name] = v
+		}
+	}
+
+	bz, err := json.Marshal(obj)
+	return string(bz), err
+}
+
+// jsonValue converts the value of a field to a value which can be marshaled as JSON. Addresses are
+// formatted with the address codec, durations as nanoseconds, structs as objects and map keys as strings.
+func jsonValue(field schema.Field, value interface{}, addressCodec schema.AddressCodec) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch field.Kind {
+	case schema.AddressKind:
+		bz, ok := value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("expected []byte for field %q, got %T", field.Name, value)
+		}
+		return addressCodec.BytesToString(bz)
+	case schema.StructKind:
+		values, ok := value.([]interface{})
+		if !ok || len(values) != len(field.StructType.Fields) {
+			return nil, fmt.Errorf("expected %d values for field %q, got %T", len(field.StructType.Fields), field.Name, value)
+		}
+		obj := make(map[string]interface{}, len(values))
+		for i, structField := range field.StructType.Fields {
+			v, err := jsonValue(structField, values[i], addressCodec)
+			if err != nil {
+				return nil, err
+			}
+			obj[structField.Name] = v
+		}
+		return obj, nil
+	case schema.ListKind:
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected []interface{} for field %q, got %T", field.Name, value)
+		}
+		elemField := schema.Field{Name: field.Name, Kind: field.ElementKind, StructType: field.StructType}
+		list := make([]interface{}, len(values))
+		for i, v := range values {
+			jv, err := jsonValue(elemField, v, addressCodec)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = jv
+		}
+		return list, nil
+	case schema.MapKind:
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected map[interface{}]interface{} for field %q, got %T", field.Name, value)
+		}
+		valueField := schema.Field{Name: field.Name, Kind: field.ValueKind, StructType: field.StructType}
+		obj := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			jv, err := jsonValue(valueField, v, addressCodec)
+			if err != nil {
+				return nil, err
+			}
+			obj[fmt.Sprint(k)] = jv
+		}
+		return obj, nil
+	default:
+		// durations are marshaled as nanoseconds, times in RFC 3339 format, bytes in base64 and
+		// JSON values as is
+		return value, nil
+	}
+}
@@ -0,0 +1,122 @@
+package clickhouse
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	// BlocksTableName is the name of the table in which blocks are indexed.
+	BlocksTableName = "blocks"
+
+	// TxsTableName is the name of the table in which transactions are indexed.
+	TxsTableName = "txs"
+
+	// EventsTableName is the name of the table in which events are indexed.
+	EventsTableName = "events"
+
+	// ObjectUpdatesTableName is the name of the table in which object updates are indexed.
+	ObjectUpdatesTableName = "object_updates"
+)
+
+// table describes an append-only table.
+type table struct {
+	name    string
+	columns []column
+	orderBy string
+}
+
+type column struct {
+	name string
+	typ  string
+}
+
+// tables are the tables of the indexer. The blocks table is last so that a block is only visible in it once
+// the data of the block has been written to the other tables.
+var tables = []table{
+	{
+		name: TxsTableName,
+		columns: []column{
+			{"height", "UInt64"},
+			{"tx_index", "Int32"},
+			{"data", "Nullable(String)"},
+		},
+		orderBy: "height, tx_index",
+	},
+	{
+		name: EventsTableName,
+		columns: []column{
+			{"height", "UInt64"},
+			{"tx_index", "Int32"},
+			{"msg_index", "UInt32"},
+			{"event_index", "UInt32"},
+			{"type", "LowCardinality(String)"},
+			{"module_name", "LowCardinality(String)"},
+			{"data", "Nullable(String)"},
+			{"attributes", "Nullable(String)"},
+		},
+		orderBy: "type, height, tx_index, msg_index, event_index",
+	},
+	{
+		name: ObjectUpdatesTableName,
+		columns: []column{
+			{"height", "UInt64"},
+			{"update_index", "UInt32"},
+			{"module_name", "LowCardinality(String)"},
+			{"type_name", "LowCardinality(String)"},
+			{"key", "String"},
+			{"value", "Nullable(String)"},
+			{"delete", "Bool"},
+		},
+		orderBy: "module_name, type_name, height, update_index",
+	},
+	{
+		name: BlocksTableName,
+		columns: []column{
+			{"height", "UInt64"},
+			{"header", "Nullable(String)"},
+		},
+		orderBy: "height",
+	},
+}
+
+// CreateTablesSql generates the CREATE TABLE statements for the tables of the indexer, separated by empty
+// lines. The tables use the ReplacingMergeTree engine so that rows which are written again when a batch is
+// retried are deduplicated when parts are merged. If retentionDays is not zero, rows are deleted that many
+// days after they were inserted with a TTL.
+func CreateTablesSql(writer io.Writer, retentionDays uint32) error {
+	for i, t := range tables {
+		if i > 0 {
+			if _, err := fmt.Fprintf(writer, "\n\n"); err != nil {
+				return err
+			}
+		}
+
+		if err := createTableSql(writer, t, retentionDays); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createTableSql generates the CREATE TABLE statement for a table, which has to be executed on its own.
+func createTableSql(writer io.Writer, t table, retentionDays uint32) error {
+	if _, err := fmt.Fprintf(writer, "CREATE TABLE IF NOT EXISTS %s (\n", t.name); err != nil {
+		return err
+	}
+	for _, col := range t.columns {
+		if _, err := fmt.Fprintf(writer, "\t%s %s,\n", col.name, col.typ); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(writer, "\tinserted_at DateTime DEFAULT now()\n) ENGINE = ReplacingMergeTree\nORDER BY (%s)", t.orderBy); err != nil {
+		return err
+	}
+	if retentionDays != 0 {
+		if _, err := fmt.Fprintf(writer, "\nTTL inserted_at + INTERVAL %d DAY", retentionDays); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(writer, ";")
+	return err
+}
@@ -0,0 +1,57 @@
+package clickhouse
+
+import (
+	"fmt"
+	"os"
+)
+
+func ExampleCreateTablesSql() {
+	err := CreateTablesSql(os.Stdout, 30)
+	if err != nil {
+		fmt.Println(err)
+	}
+	// Output:
+	// CREATE TABLE IF NOT EXISTS txs (
+	// 	height UInt64,
+	// 	tx_index Int32,
+	// 	data Nullable(String),
+	// 	inserted_at DateTime DEFAULT now()
+	// ) ENGINE = ReplacingMergeTree
+	// ORDER BY (height, tx_index)
+	// TTL inserted_at + INTERVAL 30 DAY;
+	//
+	// CREATE TABLE IF NOT EXISTS events (
+	// 	height UInt64,
+	// 	tx_index Int32,
+	// 	msg_index UInt32,
+	// 	event_index UInt32,
+	// 	type LowCardinality(String),
+	// 	module_name LowCardinality(String),
+	// 	data Nullable(String),
+	// 	attributes Nullable(String),
+	// 	inserted_at DateTime DEFAULT now()
+	// ) ENGINE = ReplacingMergeTree
+	// ORDER BY (type, height, tx_index, msg_index, event_index)
+	// TTL inserted_at + INTERVAL 30 DAY;
+	//
+	// CREATE TABLE IF NOT EXISTS object_updates (
+	// 	height UInt64,
+	// 	update_index UInt32,
+	// 	module_name LowCardinality(String),
+	// 	type_name LowCardinality(String),
+	// 	key String,
+	// 	value Nullable(String),
+	// 	delete Bool,
+	// 	inserted_at DateTime DEFAULT now()
+	// ) ENGINE = ReplacingMergeTree
+	// ORDER BY (module_name, type_name, height, update_index)
+	// TTL inserted_at + INTERVAL 30 DAY;
+	//
+	// CREATE TABLE IF NOT EXISTS blocks (
+	// 	height UInt64,
+	// 	header Nullable(String),
+	// 	inserted_at DateTime DEFAULT now()
+	// ) ENGINE = ReplacingMergeTree
+	// ORDER BY (height)
+	// TTL inserted_at + INTERVAL 30 DAY;
+}
@@ -0,0 +1,252 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/logutil"
+)
+
+// writer buffers the rows of the indexed data and inserts them in batches. All fields are guarded by mu.
+type writer struct {
+	mu            sync.Mutex
+	ctx           context.Context
+	db            *sql.DB
+	logger        logutil.Logger
+	batchSize     int
+	flushInterval time.Duration
+	lastFlush     time.Time
+	closed        bool
+
+	height  uint64
+	rows    map[string][][]interface{}
+	numRows int
+	schemas map[string]schema.ModuleSchema
+
+	// execFunc replaces the insertion of rows into the database in tests
+	execFunc func(ctx context.Context, t table, rows [][]interface{}) error
+}
+
+// listener returns the listener of the writer. Object updates are collected with appdata.BatchingListener
+// and the buffered rows are inserted when a block is committed and either the batch size or the flush
+// interval has been reached.
+func (w *writer) listener() appdata.Listener {
+	return appdata.BatchingListener(appdata.Listener{
+		InitializeModuleData: w.initializeModuleData,
+		StartBlock:           w.startBlock,
+		OnTx:                 w.onTx,
+		OnEvent:              w.onEvent,
+		Commit:               w.commit,
+	}, appdata.CommitBatchFunc(w.commitBatch))
+}
+
+func (w *writer) initializeModuleData(data appdata.ModuleInitializationData) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.schemas[data.ModuleName] = data.Schema
+	return nil
+}
+
+func (w *writer) startBlock(data appdata.StartBlockData) error {
+	header, err := jsonString(data.HeaderJSON)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.height = data.Height
+	w.add(BlocksTableName, data.Height, header)
+	return nil
+}
+
+func (w *writer) onTx(data appdata.TxData) error {
+	txJSON, err := jsonString(data.JSON)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.add(TxsTableName, w.height, data.TxIndex, txJSON)
+	return nil
+}
+
+func (w *writer) onEvent(data appdata.EventData) error {
+	eventJSON, err := jsonString(data.Data)
+	if err != nil {
+		return err
+	}
+
+	var attributes interface{}
+	if data.Attributes != nil {
+		attrs, err := data.Attributes()
+		if err != nil {
+			return err
+		}
+		obj := make(map[string]interface{}, len(attrs))
+		for _, attr := range attrs {
+			obj[attr.Key] = attr.Value
+		}
+		bz, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		attributes = string(bz)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.add(EventsTableName, w.height, data.TxIndex, data.MsgIndex, data.EventIndex, data.Type, data.ModuleName, eventJSON, attributes)
+	return nil
+}
+
+func (w *writer) commitBatch(batch appdata.BlockBatch) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var updateIndex uint32
+	for _, data := range batch.Updates {
+		modSchema, ok := w.schemas[data.ModuleName]
+		if !ok {
+			return fmt.Errorf("module %s not initialized", data.ModuleName)
+		}
+
+		for _, update := range data.Updates {
+			typ, ok := lookupObjectType(modSchema, update.TypeName)
+			if !ok {
+				return fmt.Errorf("unknown object type %q in module %s", update.TypeName, data.ModuleName)
+			}
+
+			key, err := fieldsJSON(typ.KeyFields, update.Key, schema.HexAddressCodec{})
+			if err != nil {
+				return fmt.Errorf("invalid key of %s update in module %s: %v", update.TypeName, data.ModuleName, err) //nolint:errorlint // using %v for go 1.12 compat
+			}
+
+			var value interface{}
+			if !update.Delete {
+				value, err = fieldsJSON(typ.ValueFields, update.Value, schema.HexAddressCodec{})
+				if err != nil {
+					return fmt.Errorf("invalid value of %s update in module %s: %v", update.TypeName, data.ModuleName, err) //nolint:errorlint // using %v for go 1.12 compat
+				}
+			}
+
+			w.add(ObjectUpdatesTableName, batch.Height, updateIndex, data.ModuleName, update.TypeName, key, value, update.Delete)
+			updateIndex++
+		}
+	}
+	return nil
+}
+
+func (w *writer) commit(appdata.CommitData) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return fmt.Errorf("clickhouse indexer is shut down")
+	}
+
+	if w.numRows < w.batchSize && time.Now().Sub(w.lastFlush) < w.flushInterval {
+		return nil
+	}
+	return w.flush(w.ctx)
+}
+
+// add buffers a row of a table.
+func (w *writer) add(tableName string, row ...interface{}) {
+	w.rows[tableName] = append(w.rows[tableName], row)
+	w.numRows++
+}
+
+// flush inserts all buffered rows. The blocks table is written last so that the last persisted block is only
+// advanced once all data of the block has been inserted.
+func (w *writer) flush(ctx context.Context) error {
+	if w.numRows != 0 {
+		for _, t := range tables {
+			rows := w.rows[t.name]
+			if len(rows) == 0 {
+				continue
+			}
+
+			if err := w.exec(ctx, t, rows); err != nil {
+				return fmt.Errorf("failed to insert %d rows into %s: %v", len(rows), t.name, err) //nolint:errorlint // using %v for go 1.12 compat
+			}
+			w.logger.Debug("inserted rows into clickhouse", "table", t.name, "rows", len(rows))
+			delete(w.rows, t.name)
+		}
+		w.numRows = 0
+	}
+
+	w.lastFlush = time.Now()
+	return nil
+}
+
+// exec inserts rows into a table in one batch.
+func (w *writer) exec(ctx context.Context, t table, rows [][]interface{}) error {
+	if w.execFunc != nil {
+		return w.execFunc(ctx, t, rows)
+	}
+
+	tx, err := w.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	cols := make([]string, len(t.columns))
+	for i, col := range t.columns {
+		cols[i] = col.name
+	}
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s (%s)", t.name, strings.Join(cols, ", ")))
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			_ = stmt.Close()
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	if err := stmt.Close(); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// lookupObjectType returns the object type with the name in the module schema.
+func lookupObjectType(modSchema schema.ModuleSchema, name string) (schema.ObjectType, bool) {
+	typ, ok := modSchema.LookupType(name)
+	if !ok {
+		return schema.ObjectType{}, false
+	}
+	objType, ok := typ.(schema.ObjectType)
+	return objType, ok
+}
+
+// jsonString returns the JSON of a lazy JSON value as a string, or nil if it isn't provided.
+func jsonString(toJSON appdata.ToJSON) (interface{}, error) {
+	if toJSON == nil {
+		return nil, nil
+	}
+
+	bz, err := toJSON()
+	if err != nil || bz == nil {
+		return nil, err
+	}
+	return string(bz), nil
+}
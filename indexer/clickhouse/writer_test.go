@@ -0,0 +1,118 @@
+package clickhouse
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/logutil"
+)
+
+var testModuleSchema = func() schema.ModuleSchema {
+	s, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:        "balances",
+		KeyFields:   []schema.Field{{Name: "address", Kind: schema.AddressKind}, {Name: "denom", Kind: schema.StringKind}},
+		ValueFields: []schema.Field{{Name: "amount", Kind: schema.IntegerStringKind}},
+	}})
+	if err != nil {
+		panic(err)
+	}
+	return s
+}()
+
+func TestWriter(t *testing.T) {
+	inserted := map[string][][]interface{}{}
+	var tableOrder []string
+	w := &writer{
+		ctx:           context.Background(),
+		logger:        logutil.NoopLogger{},
+		batchSize:     6,
+		flushInterval: time.Hour,
+		lastFlush:     time.Now(),
+		rows:          map[string][][]interface{}{},
+		schemas:       map[string]schema.ModuleSchema{},
+		execFunc: func(_ context.Context, t table, rows [][]interface{}) error {
+			tableOrder = append(tableOrder, t.name)
+			inserted[t.name] = append(inserted[t.name], rows...)
+			return nil
+		},
+	}
+	listener := w.listener()
+
+	check := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check(listener.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "bank", Schema: testModuleSchema}))
+
+	// the first block has fewer rows than the batch size, so nothing is inserted
+	check(listener.StartBlock(appdata.StartBlockData{Height: 1}))
+	check(listener.OnObjectUpdate(appdata.ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{
+		{TypeName: "balances", Key: []interface{}{[]byte{0x01}, "stake"}, Value: "100"},
+	}}))
+	check(listener.Commit(appdata.CommitData{}))
+	if len(inserted) != 0 {
+		t.Fatalf("expected no inserted rows, got %v", inserted)
+	}
+
+	check(listener.StartBlock(appdata.StartBlockData{Height: 2, HeaderJSON: func() (json.RawMessage, error) {
+		return json.RawMessage(`{"time":"2024-01-01T00:00:00Z"}`), nil
+	}}))
+	check(listener.OnTx(appdata.TxData{TxIndex: 0, JSON: func() (json.RawMessage, error) {
+		return json.RawMessage(`{"memo":"test"}`), nil
+	}}))
+	check(listener.OnEvent(appdata.EventData{TxIndex: 0, MsgIndex: 1, EventIndex: 2, Type: "transfer", ModuleName: "bank",
+		Attributes: func() ([]appdata.EventAttribute, error) {
+			return []appdata.EventAttribute{{Key: "amount", Value: "100stake"}}, nil
+		},
+	}))
+	check(listener.OnObjectUpdate(appdata.ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{
+		{TypeName: "balances", Key: []interface{}{[]byte{0x01}, "stake"}, Delete: true},
+		{TypeName: "balances", Key: []interface{}{[]byte{0x02}, "stake"}, Value: "200"},
+	}}))
+	check(listener.Commit(appdata.CommitData{}))
+
+	expectedOrder := []string{TxsTableName, EventsTableName, ObjectUpdatesTableName, BlocksTableName}
+	if !reflect.DeepEqual(tableOrder, expectedOrder) {
+		t.Fatalf("expected tables to be inserted in order %v, got %v", expectedOrder, tableOrder)
+	}
+
+	expected := map[string][][]interface{}{
+		BlocksTableName: {
+			{uint64(1), nil},
+			{uint64(2), `{"time":"2024-01-01T00:00:00Z"}`},
+		},
+		TxsTableName: {
+			{uint64(2), int32(0), `{"memo":"test"}`},
+		},
+		EventsTableName: {
+			{uint64(2), int32(0), uint32(1), uint32(2), "transfer", "bank", nil, `{"amount":"100stake"}`},
+		},
+		ObjectUpdatesTableName: {
+			{uint64(1), uint32(0), "bank", "balances", `{"address":"0x01","denom":"stake"}`, `{"amount":"100"}`, false},
+			{uint64(2), uint32(0), "bank", "balances", `{"address":"0x01","denom":"stake"}`, nil, true},
+			{uint64(2), uint32(1), "bank", "balances", `{"address":"0x02","denom":"stake"}`, `{"amount":"200"}`, false},
+		},
+	}
+	if !reflect.DeepEqual(inserted, expected) {
+		t.Fatalf("expected inserted rows %v, got %v", expected, inserted)
+	}
+	if w.numRows != 0 || len(w.rows) != 0 {
+		t.Fatalf("expected buffers to be reset, got %d rows", w.numRows)
+	}
+}
+
+func TestWriterClosed(t *testing.T) {
+	w := &writer{rows: map[string][][]interface{}{}, closed: true}
+	err := w.listener().Commit(appdata.CommitData{})
+	if err == nil {
+		t.Fatal("expected error after shutdown")
+	}
+}
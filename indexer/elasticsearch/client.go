@@ -0,0 +1,159 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// client is a minimal client of the Elasticsearch REST API, which is also implemented by OpenSearch.
+type client struct {
+	baseURL    string
+	httpClient *http.Client
+	username   string
+	password   string
+	apiKey     string
+}
+
+// do sends a request with a JSON or NDJSON body and returns the status code and body of the response.
+func (c *client) do(ctx context.Context, method, path string, body []byte, contentType string) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	switch {
+	case c.apiKey != "":
+		req.Header.Set("Authorization", "ApiKey "+c.apiKey)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, respBody, nil
+}
+
+// doJSON sends a request with a JSON body and returns an error if the response status is not 2xx.
+func (c *client) doJSON(ctx context.Context, method, path string, body interface{}) ([]byte, error) {
+	var bz []byte
+	if body != nil {
+		var err error
+		bz, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	status, respBody, err := c.do(ctx, method, path, bz, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	if status < 200 || status >= 300 {
+		return nil, fmt.Errorf("%s %s returned status %d: %s", method, path, status, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, nil
+}
+
+// createIndex creates an index with mappings unless it exists.
+func (c *client) createIndex(ctx context.Context, index string, mappings map[string]interface{}) error {
+	status, _, err := c.do(ctx, http.MethodHead, "/"+index, nil, "")
+	if err != nil {
+		return err
+	}
+	if status == http.StatusOK {
+		return nil
+	}
+
+	_, err = c.doJSON(ctx, http.MethodPut, "/"+index, map[string]interface{}{"mappings": mappings})
+	return err
+}
+
+// putMapping adds fields to the mappings of an index.
+func (c *client) putMapping(ctx context.Context, index string, mappings map[string]interface{}) error {
+	_, err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/%s/_mapping", index), mappings)
+	return err
+}
+
+// maxHeight returns the highest height of the documents in an index, or 0 if it is empty.
+func (c *client) maxHeight(ctx context.Context, index string) (int64, error) {
+	respBody, err := c.doJSON(ctx, http.MethodPost, fmt.Sprintf("/%s/_search", index), map[string]interface{}{
+		"size": 0,
+		"aggs": map[string]interface{}{"max_height": map[string]interface{}{"max": map[string]interface{}{"field": "height"}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var res struct {
+		Aggregations struct {
+			MaxHeight struct {
+				Value *float64 `json:"value"`
+			} `json:"max_height"`
+		} `json:"aggregations"`
+	}
+	if err := json.Unmarshal(respBody, &res); err != nil {
+		return 0, fmt.Errorf("invalid search response: %v", err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+	if res.Aggregations.MaxHeight.Value == nil {
+		return 0, nil
+	}
+	return int64(*res.Aggregations.MaxHeight.Value), nil
+}
+
+// bulk sends a bulk request and returns an error describing the first failed item if any item failed.
+func (c *client) bulk(ctx context.Context, body []byte) error {
+	status, respBody, err := c.do(ctx, http.MethodPost, "/_bulk", body, "application/x-ndjson")
+	if err != nil {
+		return err
+	}
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("bulk request returned status %d: %s", status, strings.TrimSpace(string(respBody)))
+	}
+
+	var res struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Index  string          `json:"_index"`
+			ID     string          `json:"_id"`
+			Status int             `json:"status"`
+			Error  json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(respBody, &res); err != nil {
+		return fmt.Errorf("invalid bulk response: %v", err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+	if !res.Errors {
+		return nil
+	}
+
+	for _, item := range res.Items {
+		for action, result := range item {
+			if result.Error != nil {
+				return fmt.Errorf("failed to %s document %s in %s: %s", action, result.ID, result.Index, result.Error)
+			}
+		}
+	}
+	return fmt.Errorf("bulk request failed")
+}
@@ -0,0 +1,228 @@
+package elasticsearch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+)
+
+// blockDocument returns the document of a block. The block time is taken from the time field of the header
+// JSON if it has one.
+func blockDocument(height uint64, blockTime, header json.RawMessage) map[string]interface{} {
+	doc := map[string]interface{}{"height": height}
+	if blockTime != nil {
+		doc["block_time"] = blockTime
+	}
+	if header != nil {
+		doc["header"] = header
+	}
+	return doc
+}
+
+// headerTime returns the time field of the JSON of a block header, or nil if it doesn't have one.
+func headerTime(header json.RawMessage) json.RawMessage {
+	var fields struct {
+		Time json.RawMessage `json:"time"`
+	}
+	if err := json.Unmarshal(header, &fields); err != nil {
+		return nil
+	}
+	return fields.Time
+}
+
+// txDocument returns the document of a transaction with its metadata.
+func txDocument(height uint64, blockTime json.RawMessage, tx appdata.TxData) (map[string]interface{}, error) {
+	doc := map[string]interface{}{
+		"height":   height,
+		"tx_index": tx.TxIndex,
+	}
+	if blockTime != nil {
+		doc["block_time"] = blockTime
+	}
+
+	if tx.Bytes != nil {
+		bz, err := tx.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		hash := sha256.Sum256(bz)
+		doc["hash"] = strings.ToUpper(hex.EncodeToString(hash[:]))
+	}
+
+	if tx.JSON != nil {
+		bz, err := tx.JSON()
+		if err != nil {
+			return nil, err
+		}
+		if bz != nil {
+			doc["data"] = string(bz)
+		}
+	}
+
+	if tx.Decoded != nil {
+		decoded, err := tx.Decoded()
+		if err != nil {
+			return nil, err
+		}
+
+		addressCodec := schema.HexAddressCodec{}
+		signers := make([]string, len(decoded.Signers))
+		for i, signer := range decoded.Signers {
+			if signers[i], err = addressCodec.BytesToString(signer); err != nil {
+				return nil, err
+			}
+		}
+		fee := make([]map[string]interface{}, len(decoded.Fee))
+		for i, coin := range decoded.Fee {
+			fee[i] = map[string]interface{}{"denom": coin.Denom, "amount": coin.Amount}
+		}
+
+		doc["msg_type_urls"] = decoded.MsgTypeURLs
+		doc["signers"] = signers
+		doc["fee"] = fee
+		doc["gas_limit"] = decoded.GasLimit
+		doc["memo"] = decoded.Memo
+		if decoded.FeePayer != nil {
+			if doc["fee_payer"], err = addressCodec.BytesToString(decoded.FeePayer); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if tx.Result != nil {
+		doc["code"] = tx.Result.Code
+		doc["codespace"] = tx.Result.Codespace
+		doc["gas_wanted"] = tx.Result.GasWanted
+		doc["gas_used"] = tx.Result.GasUsed
+	}
+
+	return doc, nil
+}
+
+// eventDocument returns the document of an event. If eventType is not nil, the attributes are converted to
+// JSON according to its fields, otherwise they are indexed as is.
+func eventDocument(height uint64, blockTime json.RawMessage, event appdata.EventData, eventType *schema.EventType) (map[string]interface{}, error) {
+	doc := map[string]interface{}{
+		"height":      height,
+		"tx_index":    event.TxIndex,
+		"msg_index":   event.MsgIndex,
+		"event_index": event.EventIndex,
+		"type":        event.Type,
+	}
+	if blockTime != nil {
+		doc["block_time"] = blockTime
+	}
+	if event.ModuleName != "" {
+		doc["module_name"] = event.ModuleName
+	}
+
+	if event.Data != nil {
+		bz, err := event.Data()
+		if err != nil {
+			return nil, err
+		}
+		if bz != nil {
+			doc["data"] = string(bz)
+		}
+	}
+
+	if event.Attributes != nil {
+		attrs, err := event.Attributes()
+		if err != nil {
+			return nil, err
+		}
+
+		var fields map[string]schema.Field
+		if eventType != nil {
+			fields = make(map[string]schema.Field, len(eventType.Fields))
+			for _, field := range eventType.Fields {
+				fields[field.Name] = field
+			}
+		}
+
+		values := make(map[string]interface{}, len(attrs))
+		for _, attr := range attrs {
+			field, ok := fields[attr.Key]
+			if !ok {
+				values[attr.Key] = attr.Value
+				continue
+			}
+			if values[attr.Key], err = jsonValue(field, attr.Value); err != nil {
+				return nil, fmt.Errorf("invalid attribute %q of event %s: %v", attr.Key, event.Type, err) //nolint:errorlint // using %v for go 1.12 compat
+			}
+		}
+		doc["attributes"] = map[string]interface{}{event.Type: values}
+	}
+
+	return doc, nil
+}
+
+// jsonValue converts the value of a field to a value which can be marshaled as JSON in the format of its
+// mapping. Addresses are formatted as hex strings, durations as nanoseconds and structs as objects.
+func jsonValue(field schema.Field, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch field.Kind {
+	case schema.AddressKind:
+		bz, ok := value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("expected []byte for field %q, got %T", field.Name, value)
+		}
+		return schema.HexAddressCodec{}.BytesToString(bz)
+	case schema.StructKind:
+		values, ok := value.([]interface{})
+		if !ok || len(values) != len(field.StructType.Fields) {
+			return nil, fmt.Errorf("expected %d values for field %q, got %T", len(field.StructType.Fields), field.Name, value)
+		}
+		obj := make(map[string]interface{}, len(values))
+		for i, structField := range field.StructType.Fields {
+			v, err := jsonValue(structField, values[i])
+			if err != nil {
+				return nil, err
+			}
+			obj[structField.Name] = v
+		}
+		return obj, nil
+	case schema.ListKind:
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected []interface{} for field %q, got %T", field.Name, value)
+		}
+		elemField := schema.Field{Name: field.Name, Kind: field.ElementKind, StructType: field.StructType}
+		list := make([]interface{}, len(values))
+		for i, v := range values {
+			jv, err := jsonValue(elemField, v)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = jv
+		}
+		return list, nil
+	case schema.MapKind:
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected map[interface{}]interface{} for field %q, got %T", field.Name, value)
+		}
+		valueField := schema.Field{Name: field.Name, Kind: field.ValueKind, StructType: field.StructType}
+		obj := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			jv, err := jsonValue(valueField, v)
+			if err != nil {
+				return nil, err
+			}
+			obj[fmt.Sprint(k)] = jv
+		}
+		return obj, nil
+	default:
+		// durations are marshaled as nanoseconds, times in RFC 3339 format, bytes in base64 and
+		// JSON values as is
+		return value, nil
+	}
+}
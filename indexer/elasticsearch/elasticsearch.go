@@ -0,0 +1,146 @@
+// Package elasticsearch provides an indexer target which writes blocks, transactions and events into
+// Elasticsearch or OpenSearch with mappings derived from the schema, enabling full-text and aggregation
+// queries over chain activity.
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/schema/logutil"
+)
+
+// IndexerType is the type of the Elasticsearch indexer target in the indexer config.
+const IndexerType = "elasticsearch"
+
+func init() {
+	indexer.Register(IndexerType, Init)
+}
+
+// Config is the indexer specific config of an Elasticsearch indexer target.
+type Config struct {
+	// URL is the URL of the Elasticsearch or OpenSearch cluster, such as "http://localhost:9200".
+	URL string `json:"url"`
+
+	// Username and Password are the credentials for basic authentication, if required.
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// APIKey is the encoded Elasticsearch API key, which is used instead of basic authentication if set.
+	APIKey string `json:"api_key"`
+
+	// IndexPrefix is the prefix of the names of the indexes. It defaults to DefaultIndexPrefix.
+	IndexPrefix string `json:"index_prefix"`
+
+	// BatchSize is the maximum number of documents written with one bulk request. It defaults to
+	// DefaultBatchSize.
+	BatchSize int `json:"batch_size"`
+}
+
+const (
+	// DefaultIndexPrefix is the default value of Config.IndexPrefix.
+	DefaultIndexPrefix = "cosmos"
+
+	// DefaultBatchSize is the default value of Config.BatchSize.
+	DefaultBatchSize = 1000
+)
+
+// Init initializes an Elasticsearch indexer target from the indexer config. It creates the indexes if they
+// don't exist.
+func Init(params indexer.InitParams) (indexer.InitResult, error) {
+	cfg, err := decodeConfig(params.Config.Config)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	ctx := params.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = logutil.NoopLogger{}
+	}
+
+	c := &client{
+		baseURL:    strings.TrimSuffix(cfg.URL, "/"),
+		httpClient: http.DefaultClient,
+		username:   cfg.Username,
+		password:   cfg.Password,
+		apiKey:     cfg.APIKey,
+	}
+
+	mappings := baseMappings()
+	suffixes := make([]string, 0, len(mappings))
+	for suffix := range mappings {
+		suffixes = append(suffixes, suffix)
+	}
+	sort.Strings(suffixes)
+	for _, suffix := range suffixes {
+		index := indexName(cfg.IndexPrefix, suffix)
+		if err := c.createIndex(ctx, index, mappings[suffix]); err != nil {
+			return indexer.InitResult{}, fmt.Errorf("failed to create index %s: %v", index, err) //nolint:errorlint // using %v for go 1.12 compat
+		}
+	}
+
+	lastBlock, err := c.maxHeight(ctx, indexName(cfg.IndexPrefix, BlocksIndexSuffix))
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	w := &writer{
+		ctx:        ctx,
+		client:     c,
+		logger:     logger,
+		prefix:     cfg.IndexPrefix,
+		batchSize:  cfg.BatchSize,
+		eventTypes: map[string]map[string]schema.EventType{},
+	}
+
+	go func() {
+		<-ctx.Done()
+		// documents are written when blocks are committed, so any buffered documents belong to a block
+		// which wasn't committed and are discarded
+		w.mu.Lock()
+		w.closed = true
+		w.mu.Unlock()
+	}()
+
+	return indexer.InitResult{
+		Listener:           w.listener(),
+		LastBlockPersisted: lastBlock,
+	}, nil
+}
+
+func decodeConfig(rawConfig map[string]interface{}) (Config, error) {
+	bz, err := json.Marshal(rawConfig)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid elasticsearch indexer config: %v", err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(bz, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid elasticsearch indexer config: %v", err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+
+	if cfg.URL == "" {
+		return Config{}, fmt.Errorf("missing elasticsearch URL")
+	}
+	if cfg.IndexPrefix == "" {
+		cfg.IndexPrefix = DefaultIndexPrefix
+	}
+	if cfg.IndexPrefix != strings.ToLower(cfg.IndexPrefix) {
+		return Config{}, fmt.Errorf("index prefix %q must be lowercase", cfg.IndexPrefix)
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+
+	return cfg, nil
+}
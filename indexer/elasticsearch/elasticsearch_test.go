@@ -0,0 +1,268 @@
+package elasticsearch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+)
+
+// testServer implements the parts of the Elasticsearch REST API used by the indexer.
+type testServer struct {
+	mu        sync.Mutex
+	indexes   map[string]bool
+	mappings  map[string][]string
+	docs      map[string]map[string]interface{}
+	maxHeight string
+	bulkError string
+}
+
+func newTestServer() *testServer {
+	return &testServer{
+		indexes:   map[string]bool{},
+		mappings:  map[string][]string{},
+		docs:      map[string]map[string]interface{}{},
+		maxHeight: "null",
+	}
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	switch {
+	case r.Method == http.MethodHead:
+		if !s.indexes[path] {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case r.Method == http.MethodPut && strings.HasSuffix(path, "/_mapping"):
+		index := strings.TrimSuffix(path, "/_mapping")
+		s.mappings[index] = append(s.mappings[index], string(body))
+		_, _ = w.Write([]byte(`{"acknowledged":true}`))
+	case r.Method == http.MethodPut:
+		s.indexes[path] = true
+		s.mappings[path] = append(s.mappings[path], string(body))
+		_, _ = w.Write([]byte(`{"acknowledged":true}`))
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/_search"):
+		_, _ = w.Write([]byte(`{"aggregations":{"max_height":{"value":` + s.maxHeight + `}}}`))
+	case r.Method == http.MethodPost && path == "_bulk":
+		if s.bulkError != "" {
+			_, _ = w.Write([]byte(`{"errors":true,"items":[{"index":{"_index":"cosmos-txs","_id":"1-0","status":400,"error":` + s.bulkError + `}}]}`))
+			return
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			var action struct {
+				Index struct {
+					Index string `json:"_index"`
+					ID    string `json:"_id"`
+				} `json:"index"`
+			}
+			if err := json.Unmarshal(scanner.Bytes(), &action); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			scanner.Scan()
+			var doc map[string]interface{}
+			if err := json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			s.docs[action.Index.Index+"/"+action.Index.ID] = doc
+		}
+		_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func TestIndexer(t *testing.T) {
+	srv := newTestServer()
+	httpSrv := httptest.NewServer(srv)
+	defer httpSrv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	res, err := Init(indexer.InitParams{
+		Context: ctx,
+		Config: indexer.Config{Type: IndexerType, Config: map[string]interface{}{
+			"url":        httpSrv.URL,
+			"batch_size": 2,
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.LastBlockPersisted != 0 {
+		t.Fatalf("expected last block persisted 0, got %d", res.LastBlockPersisted)
+	}
+	for _, index := range []string{"cosmos-blocks", "cosmos-events", "cosmos-txs"} {
+		if !srv.indexes[index] {
+			t.Fatalf("expected index %s to be created", index)
+		}
+	}
+
+	moduleSchema, err := schema.NewModuleSchema(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moduleSchema, err = moduleSchema.WithEventTypes(schema.EventType{
+		Name:   "transfer",
+		Fields: []schema.Field{{Name: "sender", Kind: schema.AddressKind}, {Name: "amount", Kind: schema.IntegerStringKind}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener := res.Listener
+	check := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	check(listener.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "bank", Schema: moduleSchema}))
+	if len(srv.mappings["cosmos-events"]) != 2 {
+		t.Fatalf("expected event types to be added to the events mapping, got %v", srv.mappings["cosmos-events"])
+	}
+
+	check(listener.StartBlock(appdata.StartBlockData{Height: 3, HeaderJSON: func() (json.RawMessage, error) {
+		return json.RawMessage(`{"height":"3","time":"2024-05-01T10:00:00.123456789Z"}`), nil
+	}}))
+	check(listener.OnTx(appdata.TxData{
+		TxIndex: 0,
+		Bytes:   func() ([]byte, error) { return []byte("tx"), nil },
+		Decoded: func() (appdata.DecodedTx, error) {
+			return appdata.DecodedTx{
+				MsgTypeURLs: []string{"/cosmos.bank.v1beta1.MsgSend"},
+				Signers:     [][]byte{{0xab}},
+				Fee:         []appdata.Coin{{Denom: "stake", Amount: "10"}},
+				GasLimit:    200000,
+				Memo:        "hello world",
+			}, nil
+		},
+		Result: &appdata.TxResult{GasWanted: 200000, GasUsed: 50000},
+	}))
+	check(listener.OnEvent(appdata.EventData{TxIndex: 0, MsgIndex: 0, EventIndex: 1, Type: "transfer", ModuleName: "bank",
+		Attributes: func() ([]appdata.EventAttribute, error) {
+			return []appdata.EventAttribute{{Key: "sender", Value: []byte{0xab}}, {Key: "amount", Value: "10"}}, nil
+		},
+	}))
+	check(listener.OnEvent(appdata.EventData{TxIndex: -2, EventIndex: 0, Type: "rewards",
+		Attributes: func() ([]appdata.EventAttribute, error) {
+			return []appdata.EventAttribute{{Key: "validator", Value: "val1"}}, nil
+		},
+	}))
+	check(listener.Commit(appdata.CommitData{}))
+
+	expected := map[string]map[string]interface{}{
+		"cosmos-blocks/3": {
+			"height":     float64(3),
+			"block_time": "2024-05-01T10:00:00.123456789Z",
+			"header":     map[string]interface{}{"height": "3", "time": "2024-05-01T10:00:00.123456789Z"},
+		},
+		"cosmos-txs/3-0": {
+			"height":        float64(3),
+			"block_time":    "2024-05-01T10:00:00.123456789Z",
+			"tx_index":      float64(0),
+			"hash":          "1B5B9CCB3E8D006A5230DE9BDA23FF91EDC794D4F56410560830B418528E446C",
+			"msg_type_urls": []interface{}{"/cosmos.bank.v1beta1.MsgSend"},
+			"signers":       []interface{}{"0xab"},
+			"fee":           []interface{}{map[string]interface{}{"denom": "stake", "amount": "10"}},
+			"gas_limit":     float64(200000),
+			"memo":          "hello world",
+			"code":          float64(0),
+			"codespace":     "",
+			"gas_wanted":    float64(200000),
+			"gas_used":      float64(50000),
+		},
+		"cosmos-events/3-0-0-1": {
+			"height":      float64(3),
+			"block_time":  "2024-05-01T10:00:00.123456789Z",
+			"tx_index":    float64(0),
+			"msg_index":   float64(0),
+			"event_index": float64(1),
+			"type":        "transfer",
+			"module_name": "bank",
+			"attributes":  map[string]interface{}{"transfer": map[string]interface{}{"sender": "0xab", "amount": "10"}},
+		},
+		"cosmos-events/3--2-0-0": {
+			"height":      float64(3),
+			"block_time":  "2024-05-01T10:00:00.123456789Z",
+			"tx_index":    float64(-2),
+			"msg_index":   float64(0),
+			"event_index": float64(0),
+			"type":        "rewards",
+			"attributes":  map[string]interface{}{"rewards": map[string]interface{}{"validator": "val1"}},
+		},
+	}
+	if !reflect.DeepEqual(srv.docs, expected) {
+		t.Fatalf("expected documents %v, got %v", expected, srv.docs)
+	}
+
+	srv.bulkError = `{"type":"mapper_parsing_exception","reason":"failed to parse"}`
+	check(listener.StartBlock(appdata.StartBlockData{Height: 4}))
+	err = listener.Commit(appdata.CommitData{})
+	if err == nil || !strings.Contains(err.Error(), "mapper_parsing_exception") {
+		t.Fatalf("expected bulk error, got: %v", err)
+	}
+}
+
+func TestInit(t *testing.T) {
+	srv := newTestServer()
+	srv.maxHeight = "42.0"
+	httpSrv := httptest.NewServer(srv)
+	defer httpSrv.Close()
+
+	res, err := Init(indexer.InitParams{Config: indexer.Config{Type: IndexerType, Config: map[string]interface{}{"url": httpSrv.URL}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.LastBlockPersisted != 42 {
+		t.Fatalf("expected last block persisted 42, got %d", res.LastBlockPersisted)
+	}
+
+	tests := []struct {
+		name        string
+		config      map[string]interface{}
+		errContains string
+	}{
+		{
+			name:        "missing url",
+			config:      map[string]interface{}{},
+			errContains: "missing elasticsearch URL",
+		},
+		{
+			name:        "invalid config",
+			config:      map[string]interface{}{"url": 1},
+			errContains: "invalid elasticsearch indexer config",
+		},
+		{
+			name:        "uppercase index prefix",
+			config:      map[string]interface{}{"url": httpSrv.URL, "index_prefix": "Cosmos"},
+			errContains: `index prefix "Cosmos" must be lowercase`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Init(indexer.InitParams{Config: indexer.Config{Type: IndexerType, Config: tt.config}})
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error containing %q, got: %v", tt.errContains, err)
+			}
+		})
+	}
+}
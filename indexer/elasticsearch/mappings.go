@@ -0,0 +1,160 @@
+package elasticsearch
+
+import (
+	"fmt"
+
+	"cosmossdk.io/schema"
+)
+
+const (
+	// BlocksIndexSuffix is the suffix of the name of the index in which blocks are indexed.
+	BlocksIndexSuffix = "blocks"
+
+	// TxsIndexSuffix is the suffix of the name of the index in which transactions are indexed.
+	TxsIndexSuffix = "txs"
+
+	// EventsIndexSuffix is the suffix of the name of the index in which events are indexed.
+	EventsIndexSuffix = "events"
+)
+
+// indexName returns the name of an index with the index prefix.
+func indexName(prefix, suffix string) string {
+	return fmt.Sprintf("%s-%s", prefix, suffix)
+}
+
+// textWithKeyword is the mapping of strings which are searched by their words and aggregated by their value.
+var textWithKeyword = map[string]interface{}{
+	"type": "text",
+	"fields": map[string]interface{}{
+		"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 256},
+	},
+}
+
+// baseMappings returns the mappings of the indexes by index suffix.
+func baseMappings() map[string]map[string]interface{} {
+	mapping := func(properties map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"properties": properties}
+	}
+
+	return map[string]map[string]interface{}{
+		BlocksIndexSuffix: mapping(map[string]interface{}{
+			"height":     map[string]interface{}{"type": "long"},
+			"block_time": map[string]interface{}{"type": "date_nanos"},
+			"header":     map[string]interface{}{"type": "object", "enabled": false},
+		}),
+		TxsIndexSuffix: mapping(map[string]interface{}{
+			"height":        map[string]interface{}{"type": "long"},
+			"block_time":    map[string]interface{}{"type": "date_nanos"},
+			"tx_index":      map[string]interface{}{"type": "integer"},
+			"hash":          map[string]interface{}{"type": "keyword"},
+			"msg_type_urls": map[string]interface{}{"type": "keyword"},
+			"signers":       map[string]interface{}{"type": "keyword"},
+			"fee_payer":     map[string]interface{}{"type": "keyword"},
+			"fee": map[string]interface{}{
+				"type": "nested",
+				"properties": map[string]interface{}{
+					"denom":  map[string]interface{}{"type": "keyword"},
+					"amount": map[string]interface{}{"type": "keyword"},
+				},
+			},
+			"gas_limit":  map[string]interface{}{"type": "unsigned_long"},
+			"memo":       textWithKeyword,
+			"code":       map[string]interface{}{"type": "long"},
+			"codespace":  map[string]interface{}{"type": "keyword"},
+			"gas_wanted": map[string]interface{}{"type": "long"},
+			"gas_used":   map[string]interface{}{"type": "long"},
+			"data":       map[string]interface{}{"type": "text"},
+		}),
+		EventsIndexSuffix: mapping(map[string]interface{}{
+			"height":      map[string]interface{}{"type": "long"},
+			"block_time":  map[string]interface{}{"type": "date_nanos"},
+			"tx_index":    map[string]interface{}{"type": "integer"},
+			"msg_index":   map[string]interface{}{"type": "long"},
+			"event_index": map[string]interface{}{"type": "long"},
+			"type":        map[string]interface{}{"type": "keyword"},
+			"module_name": map[string]interface{}{"type": "keyword"},
+			"data":        map[string]interface{}{"type": "text"},
+			// attributes are indexed as attributes.<event type>.<key> with the mappings of the event types of
+			// the modules, or dynamically mapped as text with a keyword sub-field for unknown event types
+			"attributes": map[string]interface{}{"type": "object"},
+		}),
+	}
+}
+
+// eventTypeMappings returns the mappings of the attributes of the event types of a module schema, which are
+// added to the events index when the module is initialized. It returns nil if the module has no event types.
+func eventTypeMappings(moduleSchema schema.ModuleSchema) map[string]interface{} {
+	eventTypes := map[string]interface{}{}
+	moduleSchema.EventTypes(func(eventType schema.EventType) bool {
+		properties := make(map[string]interface{}, len(eventType.Fields))
+		for _, field := range eventType.Fields {
+			properties[field.Name] = fieldMapping(field)
+		}
+		eventTypes[eventType.Name] = map[string]interface{}{"properties": properties}
+		return true
+	})
+	if len(eventTypes) == 0 {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"properties": map[string]interface{}{
+			"attributes": map[string]interface{}{"properties": eventTypes},
+		},
+	}
+}
+
+// fieldMapping returns the Elasticsearch mapping of a field.
+func fieldMapping(field schema.Field) map[string]interface{} {
+	return kindMapping(field, field.Kind)
+}
+
+func kindMapping(field schema.Field, kind schema.Kind) map[string]interface{} {
+	typ := func(t string) map[string]interface{} {
+		return map[string]interface{}{"type": t}
+	}
+
+	switch kind {
+	case schema.StringKind:
+		return textWithKeyword
+	case schema.BytesKind:
+		return typ("binary")
+	case schema.Int8Kind:
+		return typ("byte")
+	case schema.Uint8Kind, schema.Int16Kind:
+		return typ("short")
+	case schema.Uint16Kind, schema.Int32Kind:
+		return typ("integer")
+	case schema.Uint32Kind, schema.Int64Kind, schema.DurationKind:
+		return typ("long")
+	case schema.Uint64Kind:
+		return typ("unsigned_long")
+	case schema.BoolKind:
+		return typ("boolean")
+	case schema.Float32Kind:
+		return typ("float")
+	case schema.Float64Kind:
+		return typ("double")
+	case schema.TimeKind:
+		if field.TimeResolution == schema.TimeResolutionNanos {
+			return typ("date_nanos")
+		}
+		return typ("date")
+	case schema.StructKind:
+		properties := make(map[string]interface{}, len(field.StructType.Fields))
+		for _, structField := range field.StructType.Fields {
+			properties[structField.Name] = fieldMapping(structField)
+		}
+		return map[string]interface{}{"properties": properties}
+	case schema.ListKind:
+		// lists are indexed as arrays of their elements
+		return kindMapping(field, field.ElementKind)
+	case schema.JSONKind, schema.MapKind:
+		// arbitrary JSON and maps are stored but not indexed to avoid a mapping explosion
+		return map[string]interface{}{"type": "object", "enabled": false}
+	default:
+		// integer and decimal strings exceed the range of numeric types and are indexed as keywords like
+		// addresses and enum values
+		return typ("keyword")
+	}
+}
@@ -0,0 +1,78 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cosmossdk.io/schema"
+)
+
+func Example_eventTypeMappings() {
+	moduleSchema, err := schema.NewModuleSchema(nil)
+	if err != nil {
+		panic(err)
+	}
+	moduleSchema, err = moduleSchema.WithEventTypes(schema.EventType{
+		Name: "transfer",
+		Fields: []schema.Field{
+			{Name: "sender", Kind: schema.AddressKind},
+			{Name: "amount", Kind: schema.IntegerStringKind},
+			{Name: "height", Kind: schema.Uint64Kind},
+			{Name: "memo", Kind: schema.StringKind, Nullable: true},
+			{Name: "at", Kind: schema.TimeKind, TimeResolution: schema.TimeResolutionSeconds},
+			{Name: "tags", Kind: schema.ListKind, ElementKind: schema.StringKind},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	bz, err := json.MarshalIndent(eventTypeMappings(moduleSchema), "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(bz))
+	// Output:
+	// {
+	//   "properties": {
+	//     "attributes": {
+	//       "properties": {
+	//         "transfer": {
+	//           "properties": {
+	//             "amount": {
+	//               "type": "keyword"
+	//             },
+	//             "at": {
+	//               "type": "date"
+	//             },
+	//             "height": {
+	//               "type": "unsigned_long"
+	//             },
+	//             "memo": {
+	//               "fields": {
+	//                 "keyword": {
+	//                   "ignore_above": 256,
+	//                   "type": "keyword"
+	//                 }
+	//               },
+	//               "type": "text"
+	//             },
+	//             "sender": {
+	//               "type": "keyword"
+	//             },
+	//             "tags": {
+	//               "fields": {
+	//                 "keyword": {
+	//                   "ignore_above": 256,
+	//                   "type": "keyword"
+	//                 }
+	//               },
+	//               "type": "text"
+	//             }
+	//           }
+	//         }
+	//       }
+	//     }
+	//   }
+	// }
+}
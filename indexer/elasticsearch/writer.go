@@ -0,0 +1,173 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/logutil"
+)
+
+// writer buffers the documents of the indexed data and writes them with bulk requests. All fields are guarded
+// by mu.
+type writer struct {
+	mu        sync.Mutex
+	ctx       context.Context
+	client    *client
+	logger    logutil.Logger
+	prefix    string
+	batchSize int
+	closed    bool
+
+	// eventTypes are the event types of the initialized modules by module and event type name
+	eventTypes map[string]map[string]schema.EventType
+
+	height    uint64
+	blockTime json.RawMessage
+	header    json.RawMessage
+
+	// buf contains the actions and documents of the next bulk request as NDJSON
+	buf     bytes.Buffer
+	numDocs int
+}
+
+func (w *writer) listener() appdata.Listener {
+	return appdata.Listener{
+		InitializeModuleData: w.initializeModuleData,
+		StartBlock:           w.startBlock,
+		OnTx:                 w.onTx,
+		OnEvent:              w.onEvent,
+		Commit:               w.commit,
+	}
+}
+
+func (w *writer) initializeModuleData(data appdata.ModuleInitializationData) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	eventTypes := map[string]schema.EventType{}
+	data.Schema.EventTypes(func(eventType schema.EventType) bool {
+		eventTypes[eventType.Name] = eventType
+		return true
+	})
+	w.eventTypes[data.ModuleName] = eventTypes
+
+	mappings := eventTypeMappings(data.Schema)
+	if mappings == nil {
+		return nil
+	}
+	if err := w.client.putMapping(w.ctx, indexName(w.prefix, EventsIndexSuffix), mappings); err != nil {
+		return fmt.Errorf("failed to add event types of module %s to the events mapping: %v", data.ModuleName, err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+	return nil
+}
+
+func (w *writer) startBlock(data appdata.StartBlockData) error {
+	var header json.RawMessage
+	if data.HeaderJSON != nil {
+		var err error
+		if header, err = data.HeaderJSON(); err != nil {
+			return err
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.height = data.Height
+	w.header = header
+	w.blockTime = nil
+	if header != nil {
+		w.blockTime = headerTime(header)
+	}
+	return nil
+}
+
+func (w *writer) onTx(data appdata.TxData) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	doc, err := txDocument(w.height, w.blockTime, data)
+	if err != nil {
+		return err
+	}
+	return w.add(TxsIndexSuffix, fmt.Sprintf("%d-%d", w.height, data.TxIndex), doc)
+}
+
+func (w *writer) onEvent(data appdata.EventData) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var eventType *schema.EventType
+	if typ, ok := w.eventTypes[data.ModuleName][data.Type]; ok {
+		eventType = &typ
+	}
+
+	doc, err := eventDocument(w.height, w.blockTime, data, eventType)
+	if err != nil {
+		return err
+	}
+	return w.add(EventsIndexSuffix, fmt.Sprintf("%d-%d-%d-%d", w.height, data.TxIndex, data.MsgIndex, data.EventIndex), doc)
+}
+
+// commit writes the block document and all buffered documents. The block document is written last so that
+// the last persisted block is only advanced once all documents of the block have been written.
+func (w *writer) commit(appdata.CommitData) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.add(BlocksIndexSuffix, fmt.Sprintf("%d", w.height), blockDocument(w.height, w.blockTime, w.header)); err != nil {
+		return err
+	}
+	return w.flush(w.ctx)
+}
+
+// add buffers the index action of a document, which is written when the block is committed or once batchSize
+// documents are buffered. Documents have deterministic IDs so that writing a block again replaces its
+// documents.
+func (w *writer) add(indexSuffix, id string, doc map[string]interface{}) error {
+	if w.closed {
+		return fmt.Errorf("elasticsearch indexer is shut down")
+	}
+
+	action, err := json.Marshal(map[string]interface{}{
+		"index": map[string]interface{}{"_index": indexName(w.prefix, indexSuffix), "_id": id},
+	})
+	if err != nil {
+		return err
+	}
+	bz, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	w.buf.Write(action)
+	w.buf.WriteByte('\n')
+	w.buf.Write(bz)
+	w.buf.WriteByte('\n')
+	w.numDocs++
+
+	if w.numDocs >= w.batchSize {
+		return w.flush(w.ctx)
+	}
+	return nil
+}
+
+// flush writes the buffered documents with a bulk request.
+func (w *writer) flush(ctx context.Context) error {
+	if w.numDocs == 0 {
+		return nil
+	}
+
+	if err := w.client.bulk(ctx, w.buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write %d documents: %v", w.numDocs, err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+	w.logger.Debug("wrote documents to elasticsearch", "documents", w.numDocs)
+	w.buf.Reset()
+	w.numDocs = 0
+	return nil
+}
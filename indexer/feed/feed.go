@@ -0,0 +1,369 @@
+// Package feed implements the "feed" indexer type which serves a WebSocket endpoint on which clients, such as
+// wallets and exchanges, subscribe to the object updates of committed blocks which involve a set of addresses.
+//
+// An object update involves an address if the address appears in any AddressKind field of the update, including
+// list elements, map entries and struct fields. The matching updates of each block are delivered in a single
+// message once the block is committed.
+package feed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/schema/logutil"
+
+	"github.com/cosmos/cosmos-sdk/codec/schemaproto"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+// IndexerType is the indexer type of feed indexer targets.
+const IndexerType = "feed"
+
+func init() {
+	indexer.Register(IndexerType, Init)
+}
+
+// Config is the indexer specific config of a feed indexer target.
+type Config struct {
+	// ListenAddress is the address on which the WebSocket endpoint is served. It defaults to
+	// DefaultListenAddress.
+	ListenAddress string `json:"listen_address"`
+
+	// Path is the HTTP path of the WebSocket endpoint. It defaults to DefaultPath.
+	Path string `json:"path"`
+
+	// MaxAddresses is the maximum number of addresses a connection can subscribe to. It defaults to
+	// DefaultMaxAddresses.
+	MaxAddresses int `json:"max_addresses"`
+
+	// BufferSize is the number of messages which are buffered for each connection. Connections which fall
+	// further behind are closed so that slow clients don't hold back the node. It defaults to
+	// DefaultBufferSize.
+	BufferSize int `json:"buffer_size"`
+
+	// AllowedOrigins are the origins from which browsers may connect. "*" allows all origins. If it is empty,
+	// only requests without an Origin header or from the same host are accepted.
+	AllowedOrigins []string `json:"allowed_origins"`
+}
+
+const (
+	// DefaultListenAddress is the default value of Config.ListenAddress.
+	DefaultListenAddress = "localhost:9393"
+
+	// DefaultPath is the default value of Config.Path.
+	DefaultPath = "/address_feed"
+
+	// DefaultMaxAddresses is the default value of Config.MaxAddresses.
+	DefaultMaxAddresses = 100
+
+	// DefaultBufferSize is the default value of Config.BufferSize.
+	DefaultBufferSize = 64
+)
+
+// Init initializes a feed indexer target. It is registered as the indexer type "feed".
+func Init(params indexer.InitParams) (indexer.InitResult, error) {
+	cfg, err := decodeConfig(params.Config.Config)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	ctx := params.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = logutil.NoopLogger{}
+	}
+
+	ln, err := net.Listen("tcp", cfg.ListenAddress)
+	if err != nil {
+		return indexer.InitResult{}, fmt.Errorf("failed to listen on %s: %w", cfg.ListenAddress, err)
+	}
+
+	f := newFeed(cfg, logger)
+	mux := http.NewServeMux()
+	mux.Handle(cfg.Path, f)
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("address feed server failed", "error", err)
+		}
+	}()
+	logger.Info("serving address feed", "address", ln.Addr().String(), "path", cfg.Path)
+
+	if params.DoneWaitGroup != nil {
+		params.DoneWaitGroup.Add(1)
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error("failed to shut down address feed server", "error", err)
+		}
+		// hijacked WebSocket connections are not closed by Shutdown
+		f.close()
+		if params.DoneWaitGroup != nil {
+			params.DoneWaitGroup.Done()
+		}
+	}()
+
+	return indexer.InitResult{
+		Listener:           f.listener(),
+		LastBlockPersisted: -1,
+	}, nil
+}
+
+func decodeConfig(rawConfig map[string]interface{}) (Config, error) {
+	bz, err := json.Marshal(rawConfig)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid feed indexer config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(bz, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid feed indexer config: %w", err)
+	}
+
+	if cfg.ListenAddress == "" {
+		cfg.ListenAddress = DefaultListenAddress
+	}
+	if cfg.Path == "" {
+		cfg.Path = DefaultPath
+	}
+	if cfg.MaxAddresses == 0 {
+		cfg.MaxAddresses = DefaultMaxAddresses
+	}
+	if cfg.BufferSize == 0 {
+		cfg.BufferSize = DefaultBufferSize
+	}
+	if cfg.MaxAddresses < 0 || cfg.BufferSize < 0 {
+		return Config{}, fmt.Errorf("feed indexer max_addresses and buffer_size must not be negative")
+	}
+
+	return cfg, nil
+}
+
+// ParseAddress parses an address which is rendered either as bech32, with any human-readable prefix, or
+// as hex with a 0x prefix, into its canonical bytes.
+func ParseAddress(text string) ([]byte, error) {
+	if strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X") {
+		return schema.HexAddressCodec{}.StringToBytes(text)
+	}
+
+	_, bz, err := bech32.DecodeAndConvert(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", text, err)
+	}
+	return bz, nil
+}
+
+// Message is a message sent to subscribers with the matching object updates of a committed block.
+type Message struct {
+	// Height is the height of the block.
+	Height uint64 `json:"height"`
+
+	// Updates are the object updates of the block which involve the subscribed addresses, in order.
+	Updates []Update `json:"updates"`
+}
+
+// Update is an object update which involves subscribed addresses.
+type Update struct {
+	// Module is the name of the module of the object.
+	Module string `json:"module"`
+
+	// Addresses are the subscribed addresses which appear in the update, as they were subscribed.
+	Addresses []string `json:"addresses"`
+
+	// Update is the object update as a cosmos.indexer.v1.ObjectUpdate message in its protobuf JSON encoding.
+	Update json.RawMessage `json:"update"`
+}
+
+// blockUpdate is an object update of the current block which involves at least one address.
+type blockUpdate struct {
+	module    string
+	update    json.RawMessage
+	addresses map[string]bool
+}
+
+// feed tracks the subscribers and the object updates of the current block. Packets are delivered to the
+// listener sequentially, so only the subscribers are guarded by mu.
+type feed struct {
+	cfg      Config
+	logger   logutil.Logger
+	upgrader websocket.Upgrader
+
+	schemas map[string]schema.ModuleSchema
+	height  uint64
+	pending []blockUpdate
+
+	mu          sync.Mutex
+	closed      bool
+	subscribers map[*subscriber]bool
+}
+
+func newFeed(cfg Config, logger logutil.Logger) *feed {
+	f := &feed{
+		cfg:         cfg,
+		logger:      logger,
+		schemas:     map[string]schema.ModuleSchema{},
+		subscribers: map[*subscriber]bool{},
+	}
+	f.upgrader.CheckOrigin = f.checkOrigin
+	return f
+}
+
+func (f *feed) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range f.cfg.AllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(origin, "https://"), "http://") == r.Host
+}
+
+func (f *feed) listener() appdata.Listener {
+	return appdata.Listener{
+		InitializeModuleData: func(data appdata.ModuleInitializationData) error {
+			f.schemas[data.ModuleName] = data.Schema
+			return nil
+		},
+		StartBlock: func(data appdata.StartBlockData) error {
+			f.height = data.Height
+			f.pending = nil
+			return nil
+		},
+		OnObjectUpdate: f.onObjectUpdate,
+		Commit: func(appdata.CommitData) error {
+			f.publish()
+			f.pending = nil
+			return nil
+		},
+	}
+}
+
+func (f *feed) onObjectUpdate(data appdata.ObjectUpdateData) error {
+	if !f.hasSubscribers() {
+		return nil
+	}
+
+	moduleSchema, ok := f.schemas[data.ModuleName]
+	if !ok {
+		return nil
+	}
+
+	for _, update := range data.Updates {
+		typ, ok := moduleSchema.LookupType(update.TypeName)
+		if !ok {
+			return fmt.Errorf("unknown object type %q in module %q", update.TypeName, data.ModuleName)
+		}
+		objectType, ok := typ.(schema.ObjectType)
+		if !ok {
+			return fmt.Errorf("type %q in module %q is not an object type", update.TypeName, data.ModuleName)
+		}
+
+		addresses := map[string]bool{}
+		err := update.Addresses(objectType, func(_ string, address []byte) bool {
+			addresses[string(address)] = true
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		if len(addresses) == 0 {
+			continue
+		}
+
+		packet, err := schemaproto.PacketToProto(appdata.ObjectUpdateData{ModuleName: data.ModuleName, Updates: []schema.ObjectUpdate{update}})
+		if err != nil {
+			return err
+		}
+		bz, err := protojson.Marshal(packet.GetObjectUpdates().Updates[0])
+		if err != nil {
+			return err
+		}
+		f.pending = append(f.pending, blockUpdate{module: data.ModuleName, update: bz, addresses: addresses})
+	}
+	return nil
+}
+
+// publish sends the updates of the current block to each subscriber which has subscribed to any of their
+// addresses.
+func (f *feed) publish() {
+	if len(f.pending) == 0 {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for sub := range f.subscribers {
+		msg := Message{Height: f.height}
+		for _, update := range f.pending {
+			var matched []string
+			for address, text := range sub.addresses {
+				if update.addresses[address] {
+					matched = append(matched, text)
+				}
+			}
+			if len(matched) != 0 {
+				msg.Updates = append(msg.Updates, Update{Module: update.module, Addresses: sortedStrings(matched), Update: update.update})
+			}
+		}
+		if len(msg.Updates) == 0 {
+			continue
+		}
+
+		bz, err := json.Marshal(msg)
+		if err != nil {
+			f.logger.Error("failed to encode address feed message", "error", err)
+			continue
+		}
+		select {
+		case sub.send <- bz:
+		default:
+			f.logger.Warn("closing slow address feed subscriber", "remote", sub.remote)
+			f.removeLocked(sub)
+		}
+	}
+}
+
+func (f *feed) hasSubscribers() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subscribers) != 0
+}
+
+func (f *feed) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	for sub := range f.subscribers {
+		f.removeLocked(sub)
+	}
+}
+
+func (f *feed) removeLocked(sub *subscriber) {
+	if !f.subscribers[sub] {
+		return
+	}
+	delete(f.subscribers, sub)
+	close(sub.send)
+}
@@ -0,0 +1,171 @@
+package feed
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/logutil"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+func testModuleSchema(t *testing.T) schema.ModuleSchema {
+	t.Helper()
+	moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{
+		{
+			Name:        "balances",
+			KeyFields:   []schema.Field{{Name: "address", Kind: schema.AddressKind}, {Name: "denom", Kind: schema.StringKind}},
+			ValueFields: []schema.Field{{Name: "amount", Kind: schema.IntegerStringKind}},
+		},
+		{
+			Name:        "supply",
+			KeyFields:   []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+			ValueFields: []schema.Field{{Name: "amount", Kind: schema.IntegerStringKind}},
+		},
+	})
+	require.NoError(t, err)
+	return moduleSchema
+}
+
+func testFeed(t *testing.T, cfg map[string]interface{}) (*feed, *httptest.Server) {
+	t.Helper()
+	c, err := decodeConfig(cfg)
+	require.NoError(t, err)
+	f := newFeed(c, logutil.NoopLogger{})
+	srv := httptest.NewServer(f)
+	t.Cleanup(func() {
+		f.close()
+		srv.Close()
+	})
+	return f, srv
+}
+
+func dial(t *testing.T, srv *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/?"+query, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func readJSON(t *testing.T, conn *websocket.Conn, v interface{}) {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, bz, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(bz, v))
+}
+
+func sendBlock(t *testing.T, listener appdata.Listener, height uint64, updates ...schema.ObjectUpdate) {
+	t.Helper()
+	require.NoError(t, listener.StartBlock(appdata.StartBlockData{Height: height}))
+	require.NoError(t, listener.OnObjectUpdate(appdata.ObjectUpdateData{ModuleName: "bank", Updates: updates}))
+	require.NoError(t, listener.Commit(appdata.CommitData{}))
+}
+
+func TestFeed(t *testing.T) {
+	alice, bob := []byte{0xa1, 0xa1}, []byte{0xb0, 0xb0}
+	aliceBech32, err := bech32.ConvertAndEncode("cosmos", alice)
+	require.NoError(t, err)
+
+	f, srv := testFeed(t, map[string]interface{}{})
+	listener := f.listener()
+	require.NoError(t, listener.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "bank", Schema: testModuleSchema(t)}))
+
+	conn := dial(t, srv, QueryAddress+"="+aliceBech32)
+	var res ControlResponse
+	readJSON(t, conn, &res)
+	require.Equal(t, []string{aliceBech32}, res.Addresses)
+
+	sendBlock(t, listener, 5,
+		schema.ObjectUpdate{TypeName: "balances", Key: []interface{}{alice, "uatom"}, Value: "10"},
+		schema.ObjectUpdate{TypeName: "balances", Key: []interface{}{bob, "uatom"}, Value: "20"},
+		schema.ObjectUpdate{TypeName: "supply", Key: "uatom", Value: "30"},
+	)
+
+	var msg Message
+	readJSON(t, conn, &msg)
+	require.Equal(t, uint64(5), msg.Height)
+	require.Len(t, msg.Updates, 1)
+	require.Equal(t, "bank", msg.Updates[0].Module)
+	require.Equal(t, []string{aliceBech32}, msg.Updates[0].Addresses)
+	require.Contains(t, string(msg.Updates[0].Update), `"typeName":"balances"`)
+
+	// subscribe to bob with a control message, blocks without matching updates are not sent
+	require.NoError(t, conn.WriteJSON(ControlMessage{Subscribe: []string{"0xb0b0"}, Unsubscribe: []string{aliceBech32}}))
+	readJSON(t, conn, &res)
+	require.Equal(t, []string{"0xb0b0"}, res.Addresses)
+
+	sendBlock(t, listener, 6, schema.ObjectUpdate{TypeName: "balances", Key: []interface{}{alice, "uatom"}, Delete: true})
+	sendBlock(t, listener, 7, schema.ObjectUpdate{TypeName: "balances", Key: []interface{}{bob, "uatom"}, Delete: true})
+	readJSON(t, conn, &msg)
+	require.Equal(t, uint64(7), msg.Height)
+	require.Equal(t, []string{"0xb0b0"}, msg.Updates[0].Addresses)
+
+	// invalid control messages are rejected without changing the subscription
+	require.NoError(t, conn.WriteJSON(ControlMessage{Subscribe: []string{"not an address"}}))
+	res = ControlResponse{}
+	readJSON(t, conn, &res)
+	require.NotEmpty(t, res.Error)
+	require.Equal(t, []string{"0xb0b0"}, res.Addresses)
+}
+
+func TestFeed_maxAddresses(t *testing.T) {
+	_, srv := testFeed(t, map[string]interface{}{"max_addresses": 1})
+
+	_, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/?"+QueryAddress+"=0x01,0x02", nil)
+	require.Error(t, err)
+	require.Equal(t, 400, resp.StatusCode)
+
+	conn := dial(t, srv, QueryAddress+"=0x01")
+	var res ControlResponse
+	readJSON(t, conn, &res)
+	require.NoError(t, conn.WriteJSON(ControlMessage{Subscribe: []string{"0x02"}}))
+	readJSON(t, conn, &res)
+	require.Contains(t, res.Error, "more than 1 addresses")
+}
+
+func TestFeed_slowSubscriber(t *testing.T) {
+	f, _ := testFeed(t, map[string]interface{}{})
+	listener := f.listener()
+	require.NoError(t, listener.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "bank", Schema: testModuleSchema(t)}))
+
+	// a subscriber whose messages are not written, so it is dropped once its buffer is full instead of
+	// blocking the node
+	sub := &subscriber{send: make(chan []byte, 1), addresses: map[string]string{"\xa1": "0xa1"}}
+	f.subscribers[sub] = true
+
+	update := schema.ObjectUpdate{TypeName: "balances", Key: []interface{}{[]byte{0xa1}, "uatom"}, Value: "1"}
+	sendBlock(t, listener, 1, update)
+	require.True(t, f.hasSubscribers())
+	sendBlock(t, listener, 2, update)
+	require.False(t, f.hasSubscribers())
+
+	_, ok := <-sub.send
+	require.True(t, ok)
+	_, ok = <-sub.send
+	require.False(t, ok, "expected the channel of the dropped subscriber to be closed")
+}
+
+func TestParseAddress(t *testing.T) {
+	addr, err := bech32.ConvertAndEncode("cosmosvaloper", []byte{1, 2, 3})
+	require.NoError(t, err)
+	bz, err := ParseAddress(addr)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, bz)
+
+	bz, err = ParseAddress("0x010203")
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, bz)
+
+	_, err = ParseAddress("cosmos1invalid")
+	require.Error(t, err)
+}
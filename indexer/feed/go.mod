@@ -0,0 +1,55 @@
+module cosmossdk.io/indexer/feed
+
+go 1.22.2
+
+// NOTE: apart from cosmossdk.io/schema, this module should only depend on the WebSocket server and on
+// the packet encoding and bech32 helpers of github.com/cosmos/cosmos-sdk.
+require (
+	cosmossdk.io/schema v0.1.1
+	github.com/cosmos/cosmos-sdk v0.53.0
+	github.com/gorilla/websocket v1.5.3
+	github.com/stretchr/testify v1.9.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	cosmossdk.io/api v0.7.5 // indirect
+	github.com/cosmos/btcutil v1.0.5 // indirect
+	github.com/cosmos/cosmos-proto v1.0.0-beta.5 // indirect
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+	golang.org/x/net v0.27.0 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240709173604-40e1e62336c5 // indirect
+	google.golang.org/grpc v1.64.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/cosmos/cosmos-sdk => ../..
+
+// TODO remove post spinning out all modules
+replace (
+	cosmossdk.io/api => ../../api
+	cosmossdk.io/collections => ../../collections
+	cosmossdk.io/core => ../../core
+	cosmossdk.io/core/testing => ../../core/testing
+	cosmossdk.io/log => ../../log
+	cosmossdk.io/schema => ../../schema
+	cosmossdk.io/store => ../../store
+	cosmossdk.io/x/accounts => ../../x/accounts
+	cosmossdk.io/x/auth => ../../x/auth
+	cosmossdk.io/x/bank => ../../x/bank
+	cosmossdk.io/x/consensus => ../../x/consensus
+	cosmossdk.io/x/staking => ../../x/staking
+	cosmossdk.io/x/tx => ../../x/tx
+)
+
+// Below are the long-lived replace of the Cosmos SDK
+replace (
+	// use cosmos fork of keyring
+	github.com/99designs/keyring => github.com/cosmos/keyring v1.2.0
+
+	// replace broken goleveldb
+	github.com/syndtr/goleveldb => github.com/syndtr/goleveldb v1.0.1-0.20210819022825-2ae1ddf74ef7
+)
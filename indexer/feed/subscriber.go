@@ -0,0 +1,200 @@
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// QueryAddress is the query parameter with the addresses which a connection subscribes to when it is opened.
+// It can be repeated or contain comma separated addresses.
+const QueryAddress = "address"
+
+// writeTimeout is the time after which writing a message to a subscriber fails.
+const writeTimeout = 10 * time.Second
+
+// ControlMessage is a message which subscribers send to change their subscription.
+type ControlMessage struct {
+	// Subscribe are addresses to subscribe to.
+	Subscribe []string `json:"subscribe,omitempty"`
+
+	// Unsubscribe are addresses to unsubscribe from.
+	Unsubscribe []string `json:"unsubscribe,omitempty"`
+}
+
+// ControlResponse is the response to a ControlMessage, and to the subscription of the query parameters
+// when a connection is opened.
+type ControlResponse struct {
+	// Addresses are the addresses which the connection is subscribed to.
+	Addresses []string `json:"addresses"`
+
+	// Error is the reason why the control message was rejected, in which case the subscription is unchanged.
+	Error string `json:"error,omitempty"`
+}
+
+// subscriber is a WebSocket connection. Its addresses map canonical address bytes to the address as it was
+// subscribed and are guarded by the mutex of the feed.
+type subscriber struct {
+	conn      *websocket.Conn
+	remote    string
+	send      chan []byte
+	addresses map[string]string
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection and serves the subscription.
+func (f *feed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var initial []string
+	for _, value := range r.URL.Query()[QueryAddress] {
+		for _, address := range strings.Split(value, ",") {
+			if address = strings.TrimSpace(address); address != "" {
+				initial = append(initial, address)
+			}
+		}
+	}
+
+	sub := &subscriber{remote: r.RemoteAddr, send: make(chan []byte, f.cfg.BufferSize), addresses: map[string]string{}}
+	if err := f.update(sub, ControlMessage{Subscribe: initial}); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conn, err := f.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has already replied with an error
+		return
+	}
+	sub.conn = conn
+
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		_ = conn.Close()
+		return
+	}
+	f.subscribers[sub] = true
+	f.mu.Unlock()
+
+	f.respond(sub, nil)
+	go f.write(sub)
+	f.read(sub)
+}
+
+// read handles the control messages of the subscriber until the connection is closed.
+func (f *feed) read(sub *subscriber) {
+	defer f.remove(sub)
+
+	for {
+		_, bz, err := sub.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg ControlMessage
+		if err := json.Unmarshal(bz, &msg); err != nil {
+			f.respond(sub, fmt.Errorf("invalid control message: %w", err))
+			continue
+		}
+		f.respond(sub, f.update(sub, msg))
+	}
+}
+
+// write sends the messages of the subscriber until its channel is closed.
+func (f *feed) write(sub *subscriber) {
+	defer sub.conn.Close()
+
+	for bz := range sub.send {
+		_ = sub.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := sub.conn.WriteMessage(websocket.TextMessage, bz); err != nil {
+			f.remove(sub)
+			return
+		}
+	}
+	_ = sub.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(writeTimeout))
+}
+
+// update applies a control message to the subscription of the subscriber. Either all addresses are applied
+// or none.
+func (f *feed) update(sub *subscriber, msg ControlMessage) error {
+	subscribe := make(map[string]string, len(msg.Subscribe))
+	for _, text := range msg.Subscribe {
+		bz, err := ParseAddress(text)
+		if err != nil {
+			return err
+		}
+		subscribe[string(bz)] = text
+	}
+	unsubscribe := make([]string, 0, len(msg.Unsubscribe))
+	for _, text := range msg.Unsubscribe {
+		bz, err := ParseAddress(text)
+		if err != nil {
+			return err
+		}
+		unsubscribe = append(unsubscribe, string(bz))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	count := len(sub.addresses)
+	for address := range subscribe {
+		if _, ok := sub.addresses[address]; !ok {
+			count++
+		}
+	}
+	if count > f.cfg.MaxAddresses {
+		return fmt.Errorf("cannot subscribe to more than %d addresses", f.cfg.MaxAddresses)
+	}
+
+	for address, text := range subscribe {
+		sub.addresses[address] = text
+	}
+	for _, address := range unsubscribe {
+		delete(sub.addresses, address)
+	}
+	return nil
+}
+
+// respond queues the response to a control message for the subscriber.
+func (f *feed) respond(sub *subscriber, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.subscribers[sub] {
+		return
+	}
+
+	res := ControlResponse{Addresses: make([]string, 0, len(sub.addresses))}
+	for _, text := range sub.addresses {
+		res.Addresses = append(res.Addresses, text)
+	}
+	res.Addresses = sortedStrings(res.Addresses)
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	bz, err := json.Marshal(res)
+	if err != nil {
+		return
+	}
+	select {
+	case sub.send <- bz:
+	default:
+		f.removeLocked(sub)
+	}
+}
+
+func (f *feed) remove(sub *subscriber) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.removeLocked(sub)
+}
+
+func sortedStrings(s []string) []string {
+	sort.Strings(s)
+	return s
+}
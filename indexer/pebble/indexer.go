@@ -0,0 +1,351 @@
+package pebble
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	pebbledb "github.com/cockroachdb/pebble"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+)
+
+// Indexer stores the state of the objects of modules in a Pebble database. The updates of each block are
+// written in a single batch when the block is committed, so the database always contains the state of a
+// committed block. It implements view.AppData.
+type Indexer struct {
+	db  *pebbledb.DB
+	cfg Config
+
+	// indexes maps module and object type names to their indexed fields
+	indexes map[string]map[string][]string
+
+	mu      sync.RWMutex
+	modules map[string]schema.ModuleSchema
+
+	// batch holds the writes of the current block, height is its height if it was started with StartBlock
+	batch  *pebbledb.Batch
+	height uint64
+}
+
+func newIndexer(db *pebbledb.DB, cfg Config) (*Indexer, error) {
+	idx := &Indexer{
+		db:      db,
+		cfg:     cfg,
+		indexes: map[string]map[string][]string{},
+		modules: map[string]schema.ModuleSchema{},
+	}
+	for _, index := range cfg.Indexes {
+		types, ok := idx.indexes[index.Module]
+		if !ok {
+			types = map[string][]string{}
+			idx.indexes[index.Module] = types
+		}
+		types[index.Type] = append(types[index.Type], index.Field)
+	}
+
+	// the schemas of the modules initialized before a restart are loaded so that the data can be read before
+	// the modules are initialized again
+	iter, err := db.NewIter(&pebbledb.IterOptions{LowerBound: []byte{schemaPrefix}, UpperBound: []byte{schemaPrefix + 1}})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+	for iter.First(); iter.Valid(); iter.Next() {
+		var modSchema schema.ModuleSchema
+		if err := json.Unmarshal(iter.Value(), &modSchema); err != nil {
+			return nil, fmt.Errorf("invalid stored schema of module %s: %w", iter.Key()[1:], err)
+		}
+		idx.modules[string(iter.Key()[1:])] = modSchema
+	}
+	return idx, iter.Error()
+}
+
+// Close closes the database. Writes of a block which wasn't committed are discarded.
+func (i *Indexer) Close() error {
+	if i.batch != nil {
+		_ = i.batch.Close()
+		i.batch = nil
+	}
+	return i.db.Close()
+}
+
+// Listener returns the listener which writes module initialization, object updates and commits to the
+// database. Other data is ignored.
+func (i *Indexer) Listener() appdata.Listener {
+	return appdata.Listener{
+		InitializeModuleData: i.initializeModule,
+		StartBlock:           i.startBlock,
+		OnObjectUpdate:       i.onObjectUpdate,
+		Commit:               i.commit,
+	}
+}
+
+func (i *Indexer) initializeModule(data appdata.ModuleInitializationData) error {
+	for typeName, fields := range i.indexes[data.ModuleName] {
+		typ, ok := data.Schema.LookupType(typeName)
+		objectType, isObject := typ.(schema.ObjectType)
+		if !ok || !isObject {
+			return fmt.Errorf("can't index object type %s which is not in the schema of module %s", typeName, data.ModuleName)
+		}
+		for _, fieldName := range fields {
+			if _, ok := lookupField(objectType, fieldName); !ok {
+				return fmt.Errorf("can't index unknown field %s of object type %s in module %s", fieldName, typeName, data.ModuleName)
+			}
+		}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if stored, ok := i.modules[data.ModuleName]; ok && stored.Fingerprint() != data.Schema.Fingerprint() {
+		if err := data.Schema.CompatibleWith(stored); err != nil {
+			return fmt.Errorf("schema of module %s is incompatible with the stored data: %w", data.ModuleName, err)
+		}
+	}
+
+	bz, err := json.Marshal(data.Schema)
+	if err != nil {
+		return err
+	}
+	if err := i.db.Set(schemaKey(data.ModuleName), bz, pebbledb.Sync); err != nil {
+		return err
+	}
+	i.modules[data.ModuleName] = data.Schema
+	return nil
+}
+
+func (i *Indexer) startBlock(data appdata.StartBlockData) error {
+	if i.batch != nil {
+		// the block which was being written wasn't committed, for instance because it is delivered again
+		_ = i.batch.Close()
+	}
+	i.batch = i.db.NewIndexedBatch()
+	i.height = data.Height
+	return nil
+}
+
+func (i *Indexer) onObjectUpdate(data appdata.ObjectUpdateData) error {
+	i.mu.RLock()
+	modSchema, ok := i.modules[data.ModuleName]
+	i.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("module %s isn't initialized", data.ModuleName)
+	}
+	if err := i.startImplicitBlock(); err != nil {
+		return err
+	}
+
+	for _, update := range data.Updates {
+		typ, ok := modSchema.LookupType(update.TypeName)
+		objectType, isObject := typ.(schema.ObjectType)
+		if !ok || !isObject {
+			return fmt.Errorf("unknown object type %s in module %s", update.TypeName, data.ModuleName)
+		}
+		if err := objectType.ValidateObjectUpdate(update); err != nil {
+			return fmt.Errorf("invalid update of object type %s in module %s: %w", update.TypeName, data.ModuleName, err)
+		}
+		if err := i.apply(data.ModuleName, objectType, update); err != nil {
+			return fmt.Errorf("can't apply update of object type %s in module %s: %w", update.TypeName, data.ModuleName, err)
+		}
+	}
+	return nil
+}
+
+func (i *Indexer) commit(appdata.CommitData) error {
+	if err := i.startImplicitBlock(); err != nil {
+		return err
+	}
+
+	if err := i.batch.Set(lastBlockKey, binary.AppendUvarint(nil, i.height), nil); err != nil {
+		return err
+	}
+	err := i.batch.Commit(pebbledb.Sync)
+	_ = i.batch.Close()
+	i.batch = nil
+	return err
+}
+
+// startImplicitBlock starts the block after the last committed block if StartBlock wasn't called.
+func (i *Indexer) startImplicitBlock() error {
+	if i.batch != nil {
+		return nil
+	}
+	lastBlock, err := i.BlockNum()
+	if err != nil {
+		return err
+	}
+	i.batch = i.db.NewIndexedBatch()
+	i.height = lastBlock + 1
+	return nil
+}
+
+// apply writes an object update and the secondary index entries of the object to the batch of the block.
+func (i *Indexer) apply(moduleName string, objectType schema.ObjectType, update schema.ObjectUpdate) error {
+	key, err := objectKey(moduleName, objectType.Name, update.Key)
+	if err != nil {
+		return err
+	}
+	existing, found, err := getRecord(i.batch, key)
+	if err != nil {
+		return err
+	}
+	live := found && !existing.deleted
+
+	if update.Delete {
+		if !live {
+			return nil
+		}
+		if objectType.RetainDeletions && !i.cfg.DisableRetainDeletions {
+			existing.deleted = true
+			return i.setRecord(key, existing)
+		}
+		if err := i.updateIndexes(moduleName, objectType, update.Key, existing.value, true); err != nil {
+			return err
+		}
+		return i.batch.Delete(key, nil)
+	}
+
+	value := update.Value
+	if valueUpdates, ok := value.(schema.ValueUpdates); ok {
+		if !live {
+			return fmt.Errorf("can't apply partial update to object %v which doesn't exist", update.Key)
+		}
+		if value, err = mergeValueUpdates(objectType, existing.value, valueUpdates); err != nil {
+			return err
+		}
+	}
+
+	if found {
+		if err := i.updateIndexes(moduleName, objectType, update.Key, existing.value, true); err != nil {
+			return err
+		}
+	}
+	if err := i.updateIndexes(moduleName, objectType, update.Key, value, false); err != nil {
+		return err
+	}
+	return i.setRecord(key, record{value: value})
+}
+
+func (i *Indexer) setRecord(key []byte, r record) error {
+	bz, err := r.encode()
+	if err != nil {
+		return err
+	}
+	return i.batch.Set(key, bz, nil)
+}
+
+// updateIndexes adds the secondary index entries of an object to the batch, or deletes them if remove is true.
+func (i *Indexer) updateIndexes(moduleName string, objectType schema.ObjectType, key, value interface{}, remove bool) error {
+	fields := i.indexes[moduleName][objectType.Name]
+	if len(fields) == 0 {
+		return nil
+	}
+
+	encodedKey, err := schema.EncodeValue(key)
+	if err != nil {
+		return err
+	}
+	for _, fieldName := range fields {
+		fieldValue, err := objectFieldValue(objectType, key, value, fieldName)
+		if err != nil {
+			return err
+		}
+		prefix, err := indexValuePrefix(moduleName, objectType.Name, fieldName, fieldValue)
+		if err != nil {
+			return err
+		}
+		entry := append(prefix, encodedKey...)
+		if remove {
+			err = i.batch.Delete(entry, nil)
+		} else {
+			err = i.batch.Set(entry, nil, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reader is implemented by the database, its snapshots and indexed batches.
+type reader interface {
+	Get(key []byte) ([]byte, io.Closer, error)
+}
+
+func getRecord(r reader, key []byte) (record, bool, error) {
+	bz, closer, err := r.Get(key)
+	if errors.Is(err, pebbledb.ErrNotFound) {
+		return record{}, false, nil
+	}
+	if err != nil {
+		return record{}, false, err
+	}
+	defer closer.Close()
+
+	res, err := decodeRecord(bz)
+	if err != nil {
+		return record{}, false, err
+	}
+	return res, true, nil
+}
+
+// objectFieldValue returns the value of a key or value field of an object.
+func objectFieldValue(objectType schema.ObjectType, key, value interface{}, fieldName string) (interface{}, error) {
+	update := schema.ObjectUpdate{TypeName: objectType.Name, Key: key, Value: value}
+	for _, field := range objectType.KeyFields {
+		if field.Name == fieldName {
+			return update.KeyFieldValue(objectType, fieldName)
+		}
+	}
+	return update.ValueFieldValue(objectType, fieldName)
+}
+
+// mergeValueUpdates returns the value of the value fields of an object after applying value updates to them.
+func mergeValueUpdates(objectType schema.ObjectType, value interface{}, valueUpdates schema.ValueUpdates) (interface{}, error) {
+	var values []interface{}
+	if len(objectType.ValueFields) == 1 {
+		values = []interface{}{value}
+	} else {
+		existing, ok := value.([]interface{})
+		if !ok || len(existing) != len(objectType.ValueFields) {
+			return nil, fmt.Errorf("invalid stored value of object type %s", objectType.Name)
+		}
+		values = append([]interface{}{}, existing...)
+	}
+
+	err := valueUpdates.Iterate(func(name string, fieldValue interface{}) bool {
+		for j, field := range objectType.ValueFields {
+			if field.Name == name {
+				values[j] = fieldValue
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) == 1 {
+		return values[0], nil
+	}
+	return values, nil
+}
+
+func lookupField(objectType schema.ObjectType, name string) (schema.Field, bool) {
+	for _, field := range objectType.KeyFields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	for _, field := range objectType.ValueFields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return schema.Field{}, false
+}
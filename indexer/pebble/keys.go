@@ -0,0 +1,105 @@
+package pebble
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"cosmossdk.io/schema"
+)
+
+// The database contains these keys, in which module, type and field names are terminated by a zero byte, which
+// names never contain, and values are encoded with schema.EncodeValue:
+//   - 'm' "last_block": the uvarint of the last committed block
+//   - 's' module: the JSON of the module schema
+//   - 'o' module type key: the record of the object, see record
+//   - 'i' module type field len(value) value key: an empty value for each object in a secondary index, with the
+//     uvarint length of the encoded field value before it so that entries can be looked up by prefix
+const (
+	metaPrefix   = 'm'
+	schemaPrefix = 's'
+	objectPrefix = 'o'
+	indexPrefix  = 'i'
+)
+
+var lastBlockKey = []byte{metaPrefix, 'l', 'a', 's', 't', '_', 'b', 'l', 'o', 'c', 'k'}
+
+func schemaKey(moduleName string) []byte {
+	return append([]byte{schemaPrefix}, moduleName...)
+}
+
+// objectTypePrefix returns the prefix of the keys of the objects of an object type.
+func objectTypePrefix(moduleName, typeName string) []byte {
+	return appendNames([]byte{objectPrefix}, moduleName, typeName)
+}
+
+func objectKey(moduleName, typeName string, key interface{}) ([]byte, error) {
+	encodedKey, err := schema.EncodeValue(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+	return append(objectTypePrefix(moduleName, typeName), encodedKey...), nil
+}
+
+// indexFieldPrefix returns the prefix of the entries of a secondary index.
+func indexFieldPrefix(moduleName, typeName, fieldName string) []byte {
+	return appendNames([]byte{indexPrefix}, moduleName, typeName, fieldName)
+}
+
+// indexValuePrefix returns the prefix of the entries of a secondary index for the objects whose field has the
+// value.
+func indexValuePrefix(moduleName, typeName, fieldName string, value interface{}) ([]byte, error) {
+	encodedValue, err := schema.EncodeValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value of indexed field %s: %w", fieldName, err)
+	}
+	res := binary.AppendUvarint(indexFieldPrefix(moduleName, typeName, fieldName), uint64(len(encodedValue)))
+	return append(res, encodedValue...), nil
+}
+
+func appendNames(bz []byte, names ...string) []byte {
+	for _, name := range names {
+		bz = append(append(bz, name...), 0)
+	}
+	return bz
+}
+
+// prefixEnd returns the smallest key which is greater than all the keys with the prefix.
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// record is the stored state of an object.
+type record struct {
+	// value is the value of the value fields of the object as described by schema.ObjectUpdate.Value.
+	value interface{}
+
+	// deleted is true if the object was deleted and its deletion is retained.
+	deleted bool
+}
+
+func (r record) encode() ([]byte, error) {
+	return schema.EncodeValue([]interface{}{r.value, r.deleted})
+}
+
+func decodeRecord(bz []byte) (record, error) {
+	value, err := schema.DecodeValue(bz)
+	if err != nil {
+		return record{}, err
+	}
+	fields, ok := value.([]interface{})
+	if !ok || len(fields) != 2 {
+		return record{}, fmt.Errorf("invalid object record")
+	}
+	deleted, ok := fields[1].(bool)
+	if !ok {
+		return record{}, fmt.Errorf("invalid object record")
+	}
+	return record{value: fields[0], deleted: deleted}, nil
+}
@@ -0,0 +1,128 @@
+// Package pebble provides an indexer target which stores the state of objects in an embedded Pebble key-value
+// database, with secondary indexes on the fields which are queried, for operators who want indexed reads
+// without running a database server.
+package pebble
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	pebbledb "github.com/cockroachdb/pebble"
+
+	"cosmossdk.io/schema/indexer"
+)
+
+// IndexerType is the type of the Pebble indexer target in the indexer config.
+const IndexerType = "pebble"
+
+func init() {
+	indexer.Register(IndexerType, Init)
+}
+
+// Config is the indexer specific config of a Pebble indexer target.
+type Config struct {
+	// Path is the directory of the Pebble database, which is created if it doesn't exist.
+	Path string `json:"path"`
+
+	// Indexes are the secondary indexes which are maintained on fields of object types.
+	Indexes []IndexConfig `json:"indexes"`
+
+	// DisableRetainDeletions disables the retain deletions functionality even if it is set in an object type schema.
+	DisableRetainDeletions bool `json:"disable_retain_deletions"`
+}
+
+// IndexConfig declares a secondary index on a key or value field of an object type, which is used to look up
+// objects with Indexer.Lookup and to list objects filtered by the field with an equality condition.
+type IndexConfig struct {
+	// Module is the name of the module.
+	Module string `json:"module"`
+
+	// Type is the name of the object type.
+	Type string `json:"type"`
+
+	// Field is the name of the field.
+	Field string `json:"field"`
+}
+
+// Init initializes a Pebble indexer target from the indexer config. The database is closed when the context of
+// params is done.
+func Init(params indexer.InitParams) (indexer.InitResult, error) {
+	cfg, err := decodeConfig(params.Config.Config)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	idx, err := Open(cfg, nil)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	lastBlock, err := idx.BlockNum()
+	if err != nil {
+		_ = idx.Close()
+		return indexer.InitResult{}, err
+	}
+
+	ctx := params.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if params.DoneWaitGroup != nil {
+		params.DoneWaitGroup.Add(1)
+	}
+	go func() {
+		<-ctx.Done()
+		if err := idx.Close(); err != nil && params.Logger != nil {
+			params.Logger.Error("failed to close pebble indexer database", "err", err)
+		}
+		if params.DoneWaitGroup != nil {
+			params.DoneWaitGroup.Done()
+		}
+	}()
+
+	return indexer.InitResult{
+		Listener:           idx.Listener(),
+		LastBlockPersisted: int64(lastBlock),
+		View:               idx,
+	}, nil
+}
+
+// Open opens the Pebble database of an indexer. opts are the options of the database, which may be nil.
+func Open(cfg Config, opts *pebbledb.Options) (*Indexer, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("missing database path")
+	}
+
+	db, err := pebbledb.Open(cfg.Path, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pebble database: %w", err)
+	}
+
+	idx, err := newIndexer(db, cfg)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return idx, nil
+}
+
+func decodeConfig(rawConfig map[string]interface{}) (Config, error) {
+	bz, err := json.Marshal(rawConfig)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid pebble indexer config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(bz, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid pebble indexer config: %w", err)
+	}
+
+	for i, index := range cfg.Indexes {
+		if index.Module == "" || index.Type == "" || index.Field == "" {
+			return Config{}, fmt.Errorf("invalid pebble indexer config: indexes[%d]: module, type and field are required", i)
+		}
+	}
+
+	return cfg, nil
+}
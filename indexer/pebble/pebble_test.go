@@ -0,0 +1,195 @@
+package pebble
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	pebbledb "github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/schema/view"
+)
+
+var testSchema = func() schema.ModuleSchema {
+	modSchema, err := schema.NewModuleSchema([]schema.ObjectType{
+		{
+			Name:      "balances",
+			KeyFields: []schema.Field{{Name: "address", Kind: schema.StringKind}, {Name: "denom", Kind: schema.StringKind}},
+			ValueFields: []schema.Field{
+				{Name: "amount", Kind: schema.Uint64Kind},
+				{Name: "locked", Kind: schema.Uint64Kind},
+			},
+		},
+		{
+			Name:            "proposals",
+			KeyFields:       []schema.Field{{Name: "id", Kind: schema.Uint64Kind}},
+			ValueFields:     []schema.Field{{Name: "status", Kind: schema.StringKind}},
+			RetainDeletions: true,
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return modSchema
+}()
+
+func sendPackets(t *testing.T, listener appdata.Listener, packets ...appdata.Packet) {
+	t.Helper()
+	for _, p := range packets {
+		if err := listener.SendPacket(p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestIndexer(t *testing.T) {
+	fs := vfs.NewMem()
+	cfg := Config{
+		Path: "db",
+		Indexes: []IndexConfig{
+			{Module: "bank", Type: "balances", Field: "denom"},
+			{Module: "gov", Type: "proposals", Field: "status"},
+		},
+	}
+	idx, err := Open(cfg, &pebbledb.Options{FS: fs})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener := idx.Listener()
+	sendPackets(t, listener,
+		appdata.ModuleInitializationData{ModuleName: "bank", Schema: testSchema},
+		appdata.ModuleInitializationData{ModuleName: "gov", Schema: testSchema},
+		appdata.StartBlockData{Height: 1},
+		appdata.ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{
+			{TypeName: "balances", Key: []interface{}{"alice", "atom"}, Value: []interface{}{uint64(10), uint64(0)}},
+			{TypeName: "balances", Key: []interface{}{"alice", "osmo"}, Value: []interface{}{uint64(3), uint64(0)}},
+			{TypeName: "balances", Key: []interface{}{"bob", "atom"}, Value: []interface{}{uint64(5), uint64(0)}},
+		}},
+		appdata.ObjectUpdateData{ModuleName: "gov", Updates: []schema.ObjectUpdate{
+			{TypeName: "proposals", Key: uint64(1), Value: "voting"},
+			{TypeName: "proposals", Key: uint64(2), Value: "voting"},
+		}},
+		appdata.CommitData{},
+		appdata.StartBlockData{Height: 2},
+		appdata.ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{
+			{TypeName: "balances", Key: []interface{}{"alice", "atom"}, Value: schema.MapValueUpdates{"locked": uint64(4)}},
+			{TypeName: "balances", Key: []interface{}{"bob", "atom"}, Delete: true},
+		}},
+		appdata.ObjectUpdateData{ModuleName: "gov", Updates: []schema.ObjectUpdate{
+			{TypeName: "proposals", Key: uint64(1), Value: "passed"},
+			{TypeName: "proposals", Key: uint64(2), Delete: true},
+		}},
+		appdata.CommitData{},
+	)
+
+	// the updates of a block which isn't committed are not visible
+	sendPackets(t, listener,
+		appdata.StartBlockData{Height: 3},
+		appdata.ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{
+			{TypeName: "balances", Key: []interface{}{"carol", "atom"}, Value: []interface{}{uint64(1), uint64(0)}},
+		}},
+	)
+
+	if blockNum, err := idx.BlockNum(); err != nil || blockNum != 2 {
+		t.Fatalf("expected block 2, got %d, %v", blockNum, err)
+	}
+
+	atoms, err := idx.Lookup("bank", "balances", "denom", "atom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []schema.ObjectUpdate{{TypeName: "balances", Key: []interface{}{"alice", "atom"}, Value: []interface{}{uint64(10), uint64(4)}}}
+	if !reflect.DeepEqual(atoms, expected) {
+		t.Fatalf("expected %v, got %v", expected, atoms)
+	}
+
+	// the index entries of retained deletions are kept and updated values are indexed again
+	voting, err := idx.Lookup("gov", "proposals", "status", "voting")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(voting) != 1 || voting[0].Key != uint64(2) || !voting[0].Delete {
+		t.Fatalf("unexpected voting proposals %v", voting)
+	}
+	if _, err := idx.Lookup("bank", "balances", "amount", uint64(10)); err == nil || !strings.Contains(err.Error(), "isn't indexed") {
+		t.Fatalf("expected error for a field which isn't indexed, got %v", err)
+	}
+
+	// listing with an equality condition on an indexed field uses the index
+	coll := getCollection(t, idx, "bank", "balances")
+	res, err := coll.List(view.FieldFilter{{Field: "denom", Op: view.FilterEq, Value: "osmo"}}, nil, view.Pagination{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Objects) != 1 || !reflect.DeepEqual(res.Objects[0].Key, []interface{}{"alice", "osmo"}) {
+		t.Fatalf("unexpected objects %v", res.Objects)
+	}
+	if n, err := coll.Len(); err != nil || n != 2 {
+		t.Fatalf("expected 2 balances, got %d, %v", n, err)
+	}
+	obj, found, err := coll.GetObject([]interface{}{"alice", "osmo"})
+	if err != nil || !found || !reflect.DeepEqual(obj.Value, []interface{}{uint64(3), uint64(0)}) {
+		t.Fatalf("unexpected object %v, %v, %v", obj, found, err)
+	}
+
+	// the data and the schemas are persisted across restarts
+	if err := idx.Close(); err != nil {
+		t.Fatal(err)
+	}
+	idx, err = Open(cfg, &pebbledb.Options{FS: fs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	if blockNum, err := idx.BlockNum(); err != nil || blockNum != 2 {
+		t.Fatalf("expected block 2, got %d, %v", blockNum, err)
+	}
+	if n, err := getCollection(t, idx, "gov", "proposals").Len(); err != nil || n != 2 {
+		t.Fatalf("expected 2 proposals, got %d, %v", n, err)
+	}
+	sendPackets(t, idx.Listener(), appdata.ModuleInitializationData{ModuleName: "bank", Schema: testSchema})
+}
+
+func getCollection(t *testing.T, idx *Indexer, moduleName, typeName string) view.ObjectCollection {
+	t.Helper()
+	mod, err := idx.AppState().GetModule(moduleName)
+	if err != nil || mod == nil {
+		t.Fatalf("expected module %s, got %v", moduleName, err)
+	}
+	coll, err := mod.GetObjectCollection(typeName)
+	if err != nil || coll == nil {
+		t.Fatalf("expected collection %s, got %v", typeName, err)
+	}
+	return coll
+}
+
+func TestIndexer_invalidIndex(t *testing.T) {
+	idx, err := Open(Config{Path: "db", Indexes: []IndexConfig{{Module: "bank", Type: "balances", Field: "owner"}}}, &pebbledb.Options{FS: vfs.NewMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	err = idx.Listener().InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "bank", Schema: testSchema})
+	if err == nil || !strings.Contains(err.Error(), "can't index unknown field owner") {
+		t.Fatalf("expected invalid index error, got %v", err)
+	}
+}
+
+func TestDecodeConfig(t *testing.T) {
+	if _, err := decodeConfig(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := decodeConfig(map[string]interface{}{"path": "db", "indexes": []interface{}{map[string]interface{}{"module": "bank"}}})
+	if err == nil || !strings.Contains(err.Error(), "module, type and field are required") {
+		t.Fatalf("expected invalid index error, got %v", err)
+	}
+	if _, err := Init(indexer.InitParams{Config: indexer.Config{Type: IndexerType}}); err == nil {
+		t.Fatalf("expected missing path error")
+	}
+}
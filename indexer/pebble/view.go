@@ -0,0 +1,281 @@
+package pebble
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	pebbledb "github.com/cockroachdb/pebble"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/view"
+)
+
+// BlockNum implements view.AppData and returns the last committed block.
+func (i *Indexer) BlockNum() (uint64, error) {
+	bz, closer, err := i.db.Get(lastBlockKey)
+	if errors.Is(err, pebbledb.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer closer.Close()
+
+	height, n := binary.Uvarint(bz)
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid stored last block")
+	}
+	return height, nil
+}
+
+// AppState implements view.AppData.
+func (i *Indexer) AppState() view.AppState {
+	return appState{i}
+}
+
+// Lookup returns the objects of an object type whose field has the value using the secondary index of the field,
+// which must be declared in Config.Indexes.
+func (i *Indexer) Lookup(moduleName, typeName, fieldName string, value interface{}) ([]schema.ObjectUpdate, error) {
+	if !i.indexed(moduleName, typeName, fieldName) {
+		return nil, fmt.Errorf("field %s of object type %s in module %s isn't indexed", fieldName, typeName, moduleName)
+	}
+
+	var res []schema.ObjectUpdate
+	var iterErr error
+	i.lookup(moduleName, typeName, fieldName, value, func(update schema.ObjectUpdate, err error) bool {
+		if err != nil {
+			iterErr = err
+			return false
+		}
+		res = append(res, update)
+		return true
+	})
+	return res, iterErr
+}
+
+func (i *Indexer) indexed(moduleName, typeName, fieldName string) bool {
+	for _, field := range i.indexes[moduleName][typeName] {
+		if field == fieldName {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup iterates over the objects whose indexed field has the value in the order of their keys.
+func (i *Indexer) lookup(moduleName, typeName, fieldName string, value interface{}, f func(schema.ObjectUpdate, error) bool) {
+	prefix, err := indexValuePrefix(moduleName, typeName, fieldName, value)
+	if err != nil {
+		f(schema.ObjectUpdate{}, err)
+		return
+	}
+
+	// the entries and the objects are read from the same snapshot so that they are consistent
+	snapshot := i.db.NewSnapshot()
+	defer snapshot.Close()
+	iter, err := snapshot.NewIter(&pebbledb.IterOptions{LowerBound: prefix, UpperBound: prefixEnd(prefix)})
+	if err != nil {
+		f(schema.ObjectUpdate{}, err)
+		return
+	}
+	defer iter.Close()
+
+	objPrefix := objectTypePrefix(moduleName, typeName)
+	for iter.First(); iter.Valid(); iter.Next() {
+		encodedKey := iter.Key()[len(prefix):]
+		r, found, err := getRecord(snapshot, append(objPrefix[:len(objPrefix):len(objPrefix)], encodedKey...))
+		if err == nil && !found {
+			err = fmt.Errorf("index entry of field %s of object type %s in module %s refers to a missing object", fieldName, typeName, moduleName)
+		}
+		var update schema.ObjectUpdate
+		if err == nil {
+			update, err = recordUpdate(typeName, encodedKey, r)
+		}
+		if err != nil {
+			f(schema.ObjectUpdate{}, err)
+			return
+		}
+		if !f(update, nil) {
+			return
+		}
+	}
+	if err := iter.Error(); err != nil {
+		f(schema.ObjectUpdate{}, err)
+	}
+}
+
+func recordUpdate(typeName string, encodedKey []byte, r record) (schema.ObjectUpdate, error) {
+	key, err := schema.DecodeValue(encodedKey)
+	if err != nil {
+		return schema.ObjectUpdate{}, err
+	}
+	return schema.ObjectUpdate{TypeName: typeName, Key: key, Value: r.value, Delete: r.deleted}, nil
+}
+
+type appState struct {
+	idx *Indexer
+}
+
+func (s appState) moduleNames() []string {
+	s.idx.mu.RLock()
+	defer s.idx.mu.RUnlock()
+
+	res := make([]string, 0, len(s.idx.modules))
+	for name := range s.idx.modules {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func (s appState) GetModule(moduleName string) (view.ModuleState, error) {
+	s.idx.mu.RLock()
+	defer s.idx.mu.RUnlock()
+
+	modSchema, ok := s.idx.modules[moduleName]
+	if !ok {
+		return nil, nil
+	}
+	return moduleState{idx: s.idx, name: moduleName, schema: modSchema}, nil
+}
+
+func (s appState) Modules(f func(view.ModuleState, error) bool) {
+	for _, name := range s.moduleNames() {
+		mod, err := s.GetModule(name)
+		if !f(mod, err) {
+			return
+		}
+	}
+}
+
+func (s appState) NumModules() (int, error) {
+	return len(s.moduleNames()), nil
+}
+
+type moduleState struct {
+	idx    *Indexer
+	name   string
+	schema schema.ModuleSchema
+}
+
+func (m moduleState) ModuleName() string {
+	return m.name
+}
+
+func (m moduleState) ModuleSchema() schema.ModuleSchema {
+	return m.schema
+}
+
+func (m moduleState) GetObjectCollection(typeName string) (view.ObjectCollection, error) {
+	typ, ok := m.schema.LookupType(typeName)
+	objectType, isObject := typ.(schema.ObjectType)
+	if !ok || !isObject {
+		return nil, nil
+	}
+	return objectCollection{idx: m.idx, moduleName: m.name, objectType: objectType}, nil
+}
+
+func (m moduleState) ObjectCollections(f func(view.ObjectCollection, error) bool) {
+	m.schema.ObjectTypes(func(objectType schema.ObjectType) bool {
+		return f(objectCollection{idx: m.idx, moduleName: m.name, objectType: objectType}, nil)
+	})
+}
+
+func (m moduleState) NumObjectCollections() (int, error) {
+	n := 0
+	m.schema.ObjectTypes(func(schema.ObjectType) bool {
+		n++
+		return true
+	})
+	return n, nil
+}
+
+type objectCollection struct {
+	idx        *Indexer
+	moduleName string
+	objectType schema.ObjectType
+}
+
+func (c objectCollection) ObjectType() schema.ObjectType {
+	return c.objectType
+}
+
+func (c objectCollection) GetObject(key interface{}) (update schema.ObjectUpdate, found bool, err error) {
+	objKey, err := objectKey(c.moduleName, c.objectType.Name, key)
+	if err != nil {
+		return schema.ObjectUpdate{}, false, err
+	}
+	r, found, err := getRecord(c.idx.db, objKey)
+	if err != nil || !found {
+		return schema.ObjectUpdate{}, false, err
+	}
+	update, err = recordUpdate(c.objectType.Name, objKey[len(objectTypePrefix(c.moduleName, c.objectType.Name)):], r)
+	return update, err == nil, err
+}
+
+// AllState iterates over the objects in the order of their encoded keys.
+func (c objectCollection) AllState(f func(schema.ObjectUpdate, error) bool) {
+	prefix := objectTypePrefix(c.moduleName, c.objectType.Name)
+	iter, err := c.idx.db.NewIter(&pebbledb.IterOptions{LowerBound: prefix, UpperBound: prefixEnd(prefix)})
+	if err != nil {
+		f(schema.ObjectUpdate{}, err)
+		return
+	}
+	defer iter.Close()
+
+	for iter.First(); iter.Valid(); iter.Next() {
+		r, err := decodeRecord(iter.Value())
+		var update schema.ObjectUpdate
+		if err == nil {
+			update, err = recordUpdate(c.objectType.Name, iter.Key()[len(prefix):], r)
+		}
+		if err != nil {
+			f(schema.ObjectUpdate{}, err)
+			return
+		}
+		if !f(update, nil) {
+			return
+		}
+	}
+	if err := iter.Error(); err != nil {
+		f(schema.ObjectUpdate{}, err)
+	}
+}
+
+func (c objectCollection) Len() (int, error) {
+	n := 0
+	var iterErr error
+	c.AllState(func(_ schema.ObjectUpdate, err error) bool {
+		if err != nil {
+			iterErr = err
+			return false
+		}
+		n++
+		return true
+	})
+	return n, iterErr
+}
+
+// List implements view.ObjectCollection. If the filter has an equality condition on an indexed field, only the
+// objects in the secondary index of the field are read.
+func (c objectCollection) List(filter view.FieldFilter, order view.OrderBy, page view.Pagination) (view.ListResult, error) {
+	for _, cond := range filter {
+		if cond.Op == view.FilterEq && cond.Value != nil && c.idx.indexed(c.moduleName, c.objectType.Name, cond.Field) {
+			return view.ListObjects(indexedCollection{objectCollection: c, field: cond.Field, value: cond.Value}, filter, order, page)
+		}
+	}
+	return view.ListObjects(c, filter, order, page)
+}
+
+// indexedCollection is a collection restricted to the objects whose indexed field has a value.
+type indexedCollection struct {
+	objectCollection
+	field string
+	value interface{}
+}
+
+func (c indexedCollection) AllState(f func(schema.ObjectUpdate, error) bool) {
+	c.idx.lookup(c.moduleName, c.objectType.Name, c.field, c.value, f)
+}
@@ -5,4 +5,12 @@ const BaseSQL = `
 CREATE OR REPLACE FUNCTION nanos_to_timestamptz(nanos bigint) RETURNS timestamptz AS $$
     SELECT to_timestamp(nanos / 1000000000) + (nanos / 1000000000) * INTERVAL '1 microsecond'
 $$ LANGUAGE SQL IMMUTABLE;
+
+CREATE TABLE IF NOT EXISTS "indexer_schema_migrations" (
+    "id" SERIAL PRIMARY KEY,
+    "module_name" TEXT NOT NULL,
+    "table_name" TEXT NOT NULL,
+    "sql" TEXT NOT NULL,
+    "applied_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
 `
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"cosmossdk.io/schema/appdata"
+)
+
+const (
+	// BlockTableName is the name of the table in which blocks are indexed.
+	BlockTableName = "block"
+
+	// TxTableName is the name of the table in which transactions are indexed.
+	TxTableName = "tx"
+
+	// EventTableName is the name of the table in which events are indexed.
+	EventTableName = "event"
+)
+
+// BlockTableNames are the names of the tables in which block, transaction and event data is indexed.
+var BlockTableNames = []string{BlockTableName, TxTableName, EventTableName}
+
+// CreateBlockTables creates the block, transaction and event tables.
+func CreateBlockTables(ctx context.Context, conn DBConn, options Options) error {
+	buf := new(strings.Builder)
+	err := CreateBlockTablesSql(buf, options)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.ExecContext(ctx, buf.String())
+	return err
+}
+
+// CreateBlockTablesSql generates the CREATE TABLE statements for the block, transaction and event tables. If
+// partitioning is enabled, the tables are partitioned by ranges of block heights.
+func CreateBlockTablesSql(writer io.Writer, options Options) error {
+	jsonType := options.Dialect.jsonColumnType()
+	grant := func(tableName string) string {
+		if sqlStr := options.Dialect.grantSelectSql(tableName); sqlStr != "" {
+			return sqlStr + "\n"
+		}
+		return ""
+	}
+	partitionBy := func(col string) string {
+		if options.PartitionSize == 0 {
+			return ""
+		}
+		return fmt.Sprintf(" PARTITION BY RANGE (%q)", col)
+	}
+
+	_, err := fmt.Fprintf(writer, `CREATE TABLE IF NOT EXISTS %q (
+	"number" BIGINT NOT NULL,
+	"header" %s NULL,
+	PRIMARY KEY ("number")
+)%s;
+%s`, BlockTableName, jsonType, partitionBy("number"), grant(BlockTableName))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, `CREATE TABLE IF NOT EXISTS %q (
+	"block_number" BIGINT NOT NULL,
+	"index_in_block" INTEGER NOT NULL,
+	"data" %s NULL,
+	PRIMARY KEY ("block_number", "index_in_block")
+)%s;
+%s`, TxTableName, jsonType, partitionBy("block_number"), grant(TxTableName))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, `CREATE TABLE IF NOT EXISTS %q (
+	"block_number" BIGINT NOT NULL,
+	"tx_index" INTEGER NOT NULL,
+	"msg_index" BIGINT NOT NULL,
+	"event_index" BIGINT NOT NULL,
+	"type" TEXT NOT NULL,
+	"module_name" TEXT NULL,
+	"data" %s NULL,
+	PRIMARY KEY ("block_number", "tx_index", "msg_index", "event_index")
+)%s;
+%sCREATE INDEX IF NOT EXISTS %q ON %q ("type");`, EventTableName, jsonType, partitionBy("block_number"), grant(EventTableName),
+		fmt.Sprintf("%s_type", EventTableName), EventTableName)
+	return err
+}
+
+// insertBlock inserts a block into the block table.
+func insertBlock(ctx context.Context, conn DBConn, options Options, data appdata.StartBlockData) error {
+	header, err := jsonParam(data.HeaderJSON)
+	if err != nil {
+		return err
+	}
+
+	return execInsert(ctx, conn, options, BlockTableName,
+		fmt.Sprintf(`INSERT INTO %q ("number", "header") VALUES ($1, $2) ON CONFLICT DO NOTHING;`, BlockTableName),
+		int64(data.Height), header)
+}
+
+// insertTx inserts a transaction of the block at height into the transaction table.
+func insertTx(ctx context.Context, conn DBConn, options Options, height uint64, data appdata.TxData) error {
+	txJSON, err := jsonParam(data.JSON)
+	if err != nil {
+		return err
+	}
+
+	return execInsert(ctx, conn, options, TxTableName,
+		fmt.Sprintf(`INSERT INTO %q ("block_number", "index_in_block", "data") VALUES ($1, $2, $3) ON CONFLICT DO NOTHING;`, TxTableName),
+		int64(height), data.TxIndex, txJSON)
+}
+
+// insertEvent inserts an event of the block at height into the event table.
+func insertEvent(ctx context.Context, conn DBConn, options Options, height uint64, data appdata.EventData) error {
+	eventJSON, err := jsonParam(data.Data)
+	if err != nil {
+		return err
+	}
+
+	var moduleName interface{}
+	if data.ModuleName != "" {
+		moduleName = data.ModuleName
+	}
+
+	return execInsert(ctx, conn, options, EventTableName,
+		fmt.Sprintf(`INSERT INTO %q ("block_number", "tx_index", "msg_index", "event_index", "type", "module_name", "data") VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT DO NOTHING;`, EventTableName),
+		int64(height), data.TxIndex, int64(data.MsgIndex), int64(data.EventIndex), data.Type, moduleName, eventJSON)
+}
+
+func execInsert(ctx context.Context, conn DBConn, options Options, tableName, sqlStr string, params ...interface{}) error {
+	if options.Logger != nil {
+		options.Logger(fmt.Sprintf("Inserting into %s", tableName), sqlStr, params...)
+	}
+	_, err := conn.ExecContext(ctx, sqlStr, params...)
+	return err
+}
+
+// jsonParam returns the JSON of a lazy JSON value as a string parameter, or nil if it isn't provided.
+func jsonParam(toJSON appdata.ToJSON) (interface{}, error) {
+	if toJSON == nil {
+		return nil, nil
+	}
+
+	bz, err := toJSON()
+	if err != nil || bz == nil {
+		return nil, err
+	}
+	return string(bz), nil
+}
@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"cosmossdk.io/schema"
+)
+
+// DefaultBulkBatchSize is the default number of object updates which are buffered for each object type in bulk
+// mode before they are written.
+const DefaultBulkBatchSize = 10000
+
+// CopyFromFunc writes rows to the columns of a table using the PostgreSQL COPY protocol.
+type CopyFromFunc = func(ctx context.Context, conn DBConn, tableName string, columns []string, rows [][]interface{}) error
+
+// CopyIn is the default CopyFromFunc. It prepares a COPY ... FROM STDIN statement which is executed once for
+// each row and once without arguments to flush the data, as supported by the github.com/lib/pq driver. Other
+// drivers, such as pgx, need a CopyFromFunc which calls their native COPY API.
+func CopyIn(ctx context.Context, conn DBConn, tableName string, columns []string, rows [][]interface{}) error {
+	stmt, err := conn.PrepareContext(ctx, fmt.Sprintf("COPY %q (%s) FROM STDIN", tableName, strings.Join(columns, ", ")))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return err
+		}
+	}
+
+	_, err = stmt.ExecContext(ctx)
+	return err
+}
+
+// BulkUpdate buffers an object update to be written in bulk by FlushBulk, which is called automatically once
+// the batch size is reached. Updates of individual value fields and updates of object types with history tables
+// can't be written in bulk, so the buffered updates are flushed and they are applied with Update instead.
+func (tm *ObjectIndexer) BulkUpdate(ctx context.Context, conn DBConn, update schema.ObjectUpdate, height uint64) error {
+	if _, ok := update.Value.(schema.ValueUpdates); (ok && !update.Delete) || tm.HasHistory() {
+		if err := tm.FlushBulk(ctx, conn); err != nil {
+			return err
+		}
+		return tm.Update(ctx, conn, update, height)
+	}
+
+	keyParams, err := tm.bindKeyParams(update.Key)
+	if err != nil {
+		return fmt.Errorf("invalid key of %s update: %v", tm.typ.Name, err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+	if len(tm.typ.KeyFields) == 0 {
+		keyParams = []interface{}{1}
+	}
+
+	valueParams := make([]interface{}, len(tm.typ.ValueFields))
+	if !update.Delete {
+		valueParams, err = tm.bindValueParams(update.Value)
+		if err != nil {
+			return fmt.Errorf("invalid %s update: %v", tm.typ.Name, err) //nolint:errorlint // using %v for go 1.12 compat
+		}
+	}
+
+	tm.bulkSeq++
+	row := append(append(keyParams, valueParams...), tm.bulkSeq, update.Delete)
+	tm.bulkRows = append(tm.bulkRows, row)
+
+	if len(tm.bulkRows) >= tm.bulkBatchSize() {
+		return tm.FlushBulk(ctx, conn)
+	}
+	return nil
+}
+
+// FlushBulk writes the buffered object updates. They are copied into a temporary staging table with the COPY
+// protocol and then merged into the object type's table, keeping only the last update of each object.
+func (tm *ObjectIndexer) FlushBulk(ctx context.Context, conn DBConn) error {
+	if len(tm.bulkRows) == 0 {
+		return nil
+	}
+	rows := tm.bulkRows
+	tm.bulkRows = nil
+
+	cols, err := tm.keyAndValueColumns()
+	if err != nil {
+		return err
+	}
+
+	stagingTable := tm.stagingTableName()
+	createSql := fmt.Sprintf("CREATE TEMP TABLE %q AS SELECT %s FROM %q WITH NO DATA;\nALTER TABLE %q ADD COLUMN _seq BIGINT NOT NULL, ADD COLUMN _delete BOOLEAN NOT NULL;",
+		stagingTable, strings.Join(cols, ", "), tm.TableName(), stagingTable)
+	if tm.options.Logger != nil {
+		tm.options.Logger(fmt.Sprintf("Creating staging table %s", stagingTable), createSql)
+	}
+	if _, err := conn.ExecContext(ctx, createSql); err != nil {
+		return err
+	}
+
+	copyFrom := tm.options.CopyFrom
+	if copyFrom == nil {
+		copyFrom = CopyIn
+	}
+	if err := copyFrom(ctx, conn, stagingTable, append(cols, "_seq", "_delete"), rows); err != nil {
+		return fmt.Errorf("failed to copy %d %s updates: %v", len(rows), tm.typ.Name, err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+
+	buf := new(strings.Builder)
+	if err := tm.BulkMergeSql(buf); err != nil {
+		return err
+	}
+	mergeSql := buf.String()
+	if tm.options.Logger != nil {
+		tm.options.Logger(fmt.Sprintf("Merging %d updates into %s", len(rows), tm.TableName()), mergeSql)
+	}
+	_, err = conn.ExecContext(ctx, mergeSql)
+	return err
+}
+
+// BulkMergeSql generates the statements which merge the updates copied into the staging table into the object
+// type's table and drop the staging table afterwards.
+func (tm *ObjectIndexer) BulkMergeSql(writer io.Writer) error {
+	cols, err := tm.keyAndValueColumns()
+	if err != nil {
+		return err
+	}
+
+	var keyConds, stagingKeyConds []string
+	for _, col := range tm.primaryKeyColumns() {
+		keyConds = append(keyConds, fmt.Sprintf("t.%s = s.%s", col, col))
+		stagingKeyConds = append(stagingKeyConds, fmt.Sprintf("s.%s = n.%s", col, col))
+	}
+
+	stagingTable := tm.stagingTableName()
+	_, err = fmt.Fprintf(writer, "DELETE FROM %q AS s USING %q AS n WHERE %s AND s._seq < n._seq;\n",
+		stagingTable, stagingTable, strings.Join(stagingKeyConds, " AND "))
+	if err != nil {
+		return err
+	}
+
+	conflictSql, err := tm.onConflictSql()
+	if err != nil {
+		return err
+	}
+	colList := strings.Join(cols, ", ")
+	_, err = fmt.Fprintf(writer, "INSERT INTO %q (%s) SELECT %s FROM %q WHERE NOT _delete ON CONFLICT (%s) %s;\n",
+		tm.TableName(), colList, colList, stagingTable, strings.Join(tm.primaryKeyColumns(), ", "), conflictSql)
+	if err != nil {
+		return err
+	}
+
+	if tm.retainDeletions() {
+		_, err = fmt.Fprintf(writer, "UPDATE %q AS t SET _deleted = TRUE FROM %q AS s WHERE %s AND s._delete;\n",
+			tm.TableName(), stagingTable, strings.Join(keyConds, " AND "))
+	} else {
+		_, err = fmt.Fprintf(writer, "DELETE FROM %q AS t USING %q AS s WHERE %s AND s._delete;\n",
+			tm.TableName(), stagingTable, strings.Join(keyConds, " AND "))
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "DROP TABLE %q;", stagingTable)
+	return err
+}
+
+// keyAndValueColumns returns the primary key columns followed by the updatable columns of the value fields.
+func (tm *ObjectIndexer) keyAndValueColumns() ([]string, error) {
+	cols := tm.primaryKeyColumns()
+	for _, field := range tm.typ.ValueFields {
+		col, err := tm.updatableColumnName(field)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+func (tm *ObjectIndexer) stagingTableName() string {
+	return fmt.Sprintf("%s_staging", tm.TableName())
+}
+
+func (tm *ObjectIndexer) bulkBatchSize() int {
+	if tm.options.BulkBatchSize > 0 {
+		return tm.options.BulkBatchSize
+	}
+	return DefaultBulkBatchSize
+}
+
+// BulkUpdateObjects buffers object updates written at height to be written in bulk like
+// ObjectIndexer.BulkUpdate.
+func (m *ModuleIndexer) BulkUpdateObjects(ctx context.Context, conn DBConn, updates []schema.ObjectUpdate, height uint64) error {
+	for _, update := range updates {
+		tm, ok := m.tables[update.TypeName]
+		if !ok {
+			return fmt.Errorf("unknown object type %q in module %s", update.TypeName, m.moduleName)
+		}
+
+		if err := tm.BulkUpdate(ctx, conn, update, height); err != nil {
+			return fmt.Errorf("failed to update %s in module %s: %v", update.TypeName, m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+		}
+	}
+	return nil
+}
+
+// FlushBulk writes the buffered object updates of all tables of the module.
+func (m *ModuleIndexer) FlushBulk(ctx context.Context, conn DBConn) error {
+	for _, tm := range m.tables {
+		if err := tm.FlushBulk(ctx, conn); err != nil {
+			return fmt.Errorf("failed to write %s updates in module %s: %v", tm.typ.Name, m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+		}
+	}
+	return nil
+}
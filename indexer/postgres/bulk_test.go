@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"os"
+
+	"cosmossdk.io/indexer/postgres/internal/testdata"
+	"cosmossdk.io/schema"
+)
+
+func ExampleObjectIndexer_BulkMergeSql_vote() {
+	exampleBulkMerge(testdata.VoteObject, Options{})
+	// Output:
+	// DELETE FROM "test_vote_staging" AS s USING "test_vote_staging" AS n WHERE s."proposal" = n."proposal" AND s."address" = n."address" AND s._seq < n._seq;
+	// INSERT INTO "test_vote" ("proposal", "address", "vote") SELECT "proposal", "address", "vote" FROM "test_vote_staging" WHERE NOT _delete ON CONFLICT ("proposal", "address") DO UPDATE SET "vote" = EXCLUDED."vote", _deleted = FALSE;
+	// UPDATE "test_vote" AS t SET _deleted = TRUE FROM "test_vote_staging" AS s WHERE t."proposal" = s."proposal" AND t."address" = s."address" AND s._delete;
+	// DROP TABLE "test_vote_staging";
+}
+
+func ExampleObjectIndexer_BulkMergeSql_retainDeletionsDisabled() {
+	exampleBulkMerge(testdata.VoteObject, Options{DisableRetainDeletions: true})
+	// Output:
+	// DELETE FROM "test_vote_staging" AS s USING "test_vote_staging" AS n WHERE s."proposal" = n."proposal" AND s."address" = n."address" AND s._seq < n._seq;
+	// INSERT INTO "test_vote" ("proposal", "address", "vote") SELECT "proposal", "address", "vote" FROM "test_vote_staging" WHERE NOT _delete ON CONFLICT ("proposal", "address") DO UPDATE SET "vote" = EXCLUDED."vote";
+	// DELETE FROM "test_vote" AS t USING "test_vote_staging" AS s WHERE t."proposal" = s."proposal" AND t."address" = s."address" AND s._delete;
+	// DROP TABLE "test_vote_staging";
+}
+
+func ExampleObjectIndexer_BulkMergeSql_singleton() {
+	exampleBulkMerge(testdata.SingletonObject, Options{})
+	// Output:
+	// DELETE FROM "test_singleton_staging" AS s USING "test_singleton_staging" AS n WHERE s._id = n._id AND s._seq < n._seq;
+	// INSERT INTO "test_singleton" (_id, "foo", "bar", "an_enum") SELECT _id, "foo", "bar", "an_enum" FROM "test_singleton_staging" WHERE NOT _delete ON CONFLICT (_id) DO UPDATE SET "foo" = EXCLUDED."foo", "bar" = EXCLUDED."bar", "an_enum" = EXCLUDED."an_enum";
+	// DELETE FROM "test_singleton" AS t USING "test_singleton_staging" AS s WHERE t._id = s._id AND s._delete;
+	// DROP TABLE "test_singleton_staging";
+}
+
+func exampleBulkMerge(objectType schema.ObjectType, options Options) {
+	tm := NewObjectIndexer("test", objectType, options)
+	err := tm.BulkMergeSql(os.Stdout)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -9,6 +9,10 @@ import (
 
 // createColumnDefinition writes a column definition within a CREATE TABLE statement for the field.
 func (tm *ObjectIndexer) createColumnDefinition(writer io.Writer, field schema.Field) error {
+	if tm.options.Dialect == DialectSQLite {
+		return tm.createSQLiteColumnDefinition(writer, field)
+	}
+
 	_, err := fmt.Fprintf(writer, "%q ", field.Name)
 	if err != nil {
 		return err
@@ -25,12 +29,21 @@ func (tm *ObjectIndexer) createColumnDefinition(writer io.Writer, field schema.F
 	} else {
 		switch field.Kind {
 		case schema.EnumKind:
-			_, err = fmt.Fprintf(writer, "%q", enumTypeName(tm.moduleName, field.EnumDefinition))
+			_, err = fmt.Fprintf(writer, "%q", enumTypeName(tm.moduleName, field.EnumType))
 			if err != nil {
 				return err
 			}
 		case schema.TimeKind:
-			// for time fields, we generate two columns:
+			if field.TimeResolution != schema.TimeResolutionNanos {
+				// timestamptz has microsecond precision so coarser resolutions are stored losslessly
+				_, err = fmt.Fprintf(writer, "TIMESTAMPTZ")
+				if err != nil {
+					return err
+				}
+				break
+			}
+
+			// for time fields with nanosecond resolution, we generate two columns:
 			// - one with nanoseconds precision for lossless storage, suffixed with _nanos
 			// - one as a timestamptz (microsecond precision) for ease of use, that is GENERATED
 			nanosColName := fmt.Sprintf("%s_nanos", field.Name)
@@ -100,19 +113,21 @@ func simpleColumnType(kind schema.Kind) string {
 		return "JSONB"
 	case schema.DurationKind:
 		return "BIGINT"
-	case schema.Bech32AddressKind:
+	case schema.AddressKind:
 		return "TEXT"
+	case schema.StructKind, schema.ListKind, schema.MapKind, schema.OneOfKind:
+		return "JSONB"
 	default:
 		return ""
 	}
 }
 
 // updatableColumnName is the name of the insertable/updatable column name for the field.
-// This is the field name in most cases, except for time columns which are stored as nanos
-// and then converted to timestamp generated columns.
+// This is the field name in most cases, except for time columns with nanosecond resolution which
+// are stored as nanos and then converted to timestamp generated columns.
 func (tm *ObjectIndexer) updatableColumnName(field schema.Field) (name string, err error) {
 	name = field.Name
-	if field.Kind == schema.TimeKind {
+	if field.Kind == schema.TimeKind && field.TimeResolution == schema.TimeResolutionNanos {
 		name = fmt.Sprintf("%s_nanos", name)
 	}
 	name = fmt.Sprintf("%q", name)
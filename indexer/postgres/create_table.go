@@ -84,13 +84,55 @@ func (tm *ObjectIndexer) CreateTableSql(writer io.Writer) error {
 		return err
 	}
 
-	// we GRANT SELECT on the table to PUBLIC so that the table is automatically available
-	// for querying using off-the-shelf tools like pg_graphql, Postgrest, Postgraphile, etc.
-	// without any login permissions
-	_, err = fmt.Fprintf(writer, "GRANT SELECT ON TABLE %q TO PUBLIC;", tm.TableName())
+	_, err = fmt.Fprintf(writer, "%s", tm.options.Dialect.grantSelectSql(tm.TableName()))
 	if err != nil {
 		return err
 	}
 
+	return tm.createIndexesSql(writer)
+}
+
+// createIndexesSql generates CREATE UNIQUE INDEX statements for the unique constraints and
+// CREATE INDEX statements for the secondary indexes of the object type.
+func (tm *ObjectIndexer) createIndexesSql(writer io.Writer) error {
+	for _, constraint := range tm.typ.UniqueConstraints {
+		cols := make([]string, len(constraint))
+		for i, fieldName := range constraint {
+			col, err := tm.updatableColumnName(tm.valueFields[fieldName])
+			if err != nil {
+				return err
+			}
+			cols[i] = col
+		}
+
+		indexName := fmt.Sprintf("%s_%s_key", tm.TableName(), strings.Join(constraint, "_"))
+		_, err := fmt.Fprintf(writer, "\nCREATE UNIQUE INDEX IF NOT EXISTS %q ON %q (%s);",
+			indexName, tm.TableName(), strings.Join(cols, ", "))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, index := range tm.typ.Indexes {
+		cols := make([]string, len(index.Fields))
+		for i, indexField := range index.Fields {
+			col, err := tm.updatableColumnName(tm.valueFields[indexField.Name])
+			if err != nil {
+				return err
+			}
+			if indexField.Descending {
+				col += " DESC"
+			}
+			cols[i] = col
+		}
+
+		indexName := fmt.Sprintf("%s_%s", tm.TableName(), index.Name)
+		_, err := fmt.Fprintf(writer, "\nCREATE INDEX IF NOT EXISTS %q ON %q (%s);",
+			indexName, tm.TableName(), strings.Join(cols, ", "))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -35,6 +35,10 @@ func ExampleObjectIndexer_CreateTableSql_allKinds() {
 	//	"bech32address" TEXT NOT NULL,
 	//	"enum" "test_my_enum" NOT NULL,
 	//	"json" JSONB NOT NULL,
+	//	"struct" JSONB NOT NULL,
+	//	"list" JSONB NOT NULL,
+	//	"map" JSONB NOT NULL,
+	//	"oneof" JSONB NOT NULL,
 	//	PRIMARY KEY ("id", "ts_nanos")
 	// );
 	// GRANT SELECT ON TABLE "test_all_kinds" TO PUBLIC;
@@ -78,6 +82,24 @@ func ExampleObjectIndexer_CreateTableSql_vote_no_retain_delete() {
 	// GRANT SELECT ON TABLE "test_vote" TO PUBLIC;
 }
 
+func ExampleObjectIndexer_CreateTableSql_indexes() {
+	exampleCreateTable(testdata.ValidatorObject)
+	// Output:
+	// CREATE TABLE IF NOT EXISTS "test_validator" (
+	// 	"operator" TEXT NOT NULL,
+	//	"moniker" TEXT NOT NULL,
+	//	"consensus_pubkey" BYTEA NOT NULL,
+	//	"tokens" NUMERIC NOT NULL,
+	//	"jailed" BOOLEAN NOT NULL,
+	//	"jailed_until" TIMESTAMPTZ NOT NULL,
+	//	PRIMARY KEY ("operator")
+	// );
+	// GRANT SELECT ON TABLE "test_validator" TO PUBLIC;
+	// CREATE UNIQUE INDEX IF NOT EXISTS "test_validator_consensus_pubkey_key" ON "test_validator" ("consensus_pubkey");
+	// CREATE INDEX IF NOT EXISTS "test_validator_by_tokens" ON "test_validator" ("tokens" DESC);
+	// CREATE INDEX IF NOT EXISTS "test_validator_by_jailed_until" ON "test_validator" ("jailed", "jailed_until");
+}
+
 func exampleCreateTable(objectType schema.ObjectType) {
 	exampleCreateTableOpt(objectType, false)
 }
@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"cosmossdk.io/schema"
+)
+
+// Dialect is the SQL dialect which the indexer generates SQL for. The SQL generation is shared between
+// PostgreSQL and other databases with a similar dialect, which only differ in column types and schema
+// management.
+type Dialect int
+
+const (
+	// DialectPostgres is the PostgreSQL dialect, which is the default.
+	DialectPostgres Dialect = iota
+
+	// DialectSQLite is the SQLite dialect. Enum fields are stored as TEXT columns with a CHECK constraint and
+	// history tables, partitioning, bulk writes and schema migrations aren't supported.
+	DialectSQLite
+)
+
+// String returns the name of the dialect.
+func (d Dialect) String() string {
+	switch d {
+	case DialectPostgres:
+		return "postgres"
+	case DialectSQLite:
+		return "sqlite"
+	default:
+		return fmt.Sprintf("Dialect(%d)", int(d))
+	}
+}
+
+// validateOptions checks that the options only use features which the dialect supports.
+func (d Dialect) validateOptions(options Options) error {
+	if d != DialectSQLite {
+		return nil
+	}
+
+	var unsupported []string
+	if options.HistoryTables {
+		unsupported = append(unsupported, "history tables")
+	}
+	if options.PartitionSize != 0 {
+		unsupported = append(unsupported, "partitioning")
+	}
+	if len(unsupported) != 0 {
+		return fmt.Errorf("%s are not supported by the %s dialect", strings.Join(unsupported, " and "), d)
+	}
+	return nil
+}
+
+// jsonColumnType returns the type of the columns which store JSON.
+func (d Dialect) jsonColumnType() string {
+	if d == DialectSQLite {
+		return "TEXT"
+	}
+	return "JSONB"
+}
+
+// grantSelectSql returns the statement which grants public read access to a table, which is empty if the
+// database has no access control.
+func (d Dialect) grantSelectSql(tableName string) string {
+	if d == DialectSQLite {
+		return ""
+	}
+
+	// we GRANT SELECT on the table to PUBLIC so that the table is automatically available
+	// for querying using off-the-shelf tools like pg_graphql, Postgrest, Postgraphile, etc.
+	// without any login permissions
+	return fmt.Sprintf("GRANT SELECT ON TABLE %q TO PUBLIC;", tableName)
+}
+
+// createSQLiteColumnDefinition writes a column definition within a CREATE TABLE statement for the field in the
+// SQLite dialect.
+func (tm *ObjectIndexer) createSQLiteColumnDefinition(writer io.Writer, field schema.Field) error {
+	_, err := fmt.Fprintf(writer, "%q ", field.Name)
+	if err != nil {
+		return err
+	}
+
+	switch field.Kind {
+	case schema.EnumKind:
+		values := make([]string, len(field.EnumType.Values))
+		for i, value := range field.EnumType.Values {
+			values[i] = fmt.Sprintf("'%s'", value)
+		}
+		_, err = fmt.Fprintf(writer, "TEXT CHECK (%q IN (%s))", field.Name, strings.Join(values, ", "))
+	case schema.TimeKind:
+		if field.TimeResolution != schema.TimeResolutionNanos {
+			_, err = fmt.Fprintf(writer, "TIMESTAMP")
+			break
+		}
+
+		// like in PostgreSQL, the lossless nanoseconds are stored in the _nanos column and the time is
+		// generated from them for ease of use
+		nanosColName := fmt.Sprintf("%s_nanos", field.Name)
+		_, err = fmt.Fprintf(writer, "TEXT GENERATED ALWAYS AS (strftime('%%Y-%%m-%%dT%%H:%%M:%%fZ', %q / 1000000000.0, 'unixepoch')) VIRTUAL,\n\t%q INTEGER",
+			nanosColName, nanosColName)
+	default:
+		colType := sqliteColumnType(field.Kind)
+		if colType == "" {
+			return fmt.Errorf("unexpected kind: %v, this should have been handled earlier", field.Kind)
+		}
+		_, err = fmt.Fprintf(writer, "%s", colType)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeNullability(writer, field.Nullable)
+}
+
+// sqliteColumnType returns the SQLite column type for the kind for simple types. Integer and decimal strings
+// and uint64 values, which can exceed the range of SQLite's INTEGER, are stored as TEXT.
+func sqliteColumnType(kind schema.Kind) string {
+	//nolint:goconst // adding constants for these sqlite type names would impede readability
+	switch kind {
+	case schema.StringKind, schema.AddressKind, schema.Uint64Kind, schema.IntegerStringKind, schema.DecimalStringKind:
+		return "TEXT"
+	case schema.BoolKind:
+		return "BOOLEAN"
+	case schema.BytesKind:
+		return "BLOB"
+	case schema.Int8Kind, schema.Int16Kind, schema.Int32Kind, schema.Int64Kind,
+		schema.Uint8Kind, schema.Uint16Kind, schema.Uint32Kind, schema.DurationKind:
+		return "INTEGER"
+	case schema.Float32Kind, schema.Float64Kind:
+		return "REAL"
+	case schema.JSONKind, schema.StructKind, schema.ListKind, schema.MapKind, schema.OneOfKind:
+		return "TEXT"
+	default:
+		return ""
+	}
+}
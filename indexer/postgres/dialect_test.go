@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cosmossdk.io/indexer/postgres/internal/testdata"
+	"cosmossdk.io/schema"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// assertGolden compares actual with the golden file testdata/name, or overwrites the golden file if the tests
+// are run with -update.
+func assertGolden(t *testing.T, actual, name string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := ioutil.WriteFile(path, []byte(actual), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual != string(expected) {
+		t.Fatalf("output doesn't match golden file %s, run the tests with -update to update it\nexpected:\n%s\nactual:\n%s", path, expected, actual)
+	}
+}
+
+func TestSQLiteDDL(t *testing.T) {
+	tests := []struct {
+		name   string
+		golden string
+		write  func(writer io.Writer) error
+	}{
+		{
+			name:   "all kinds",
+			golden: "sqlite_create_all_kinds.golden",
+			write:  NewObjectIndexer("test", testdata.AllKindsObject, Options{Dialect: DialectSQLite}).CreateTableSql,
+		},
+		{
+			name:   "singleton",
+			golden: "sqlite_create_singleton.golden",
+			write:  NewObjectIndexer("test", testdata.SingletonObject, Options{Dialect: DialectSQLite}).CreateTableSql,
+		},
+		{
+			name:   "retain deletions",
+			golden: "sqlite_create_vote.golden",
+			write:  NewObjectIndexer("test", testdata.VoteObject, Options{Dialect: DialectSQLite}).CreateTableSql,
+		},
+		{
+			name:   "retain deletions disabled",
+			golden: "sqlite_create_vote_no_retain_delete.golden",
+			write: NewObjectIndexer("test", testdata.VoteObject, Options{
+				Dialect:                DialectSQLite,
+				DisableRetainDeletions: true,
+			}).CreateTableSql,
+		},
+		{
+			name:   "block tables",
+			golden: "sqlite_create_block_tables.golden",
+			write: func(writer io.Writer) error {
+				return CreateBlockTablesSql(writer, Options{Dialect: DialectSQLite})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(strings.Builder)
+			if err := tt.write(buf); err != nil {
+				t.Fatal(err)
+			}
+			assertGolden(t, buf.String(), tt.golden)
+		})
+	}
+}
+
+func TestSQLiteDDL_nanosColumn(t *testing.T) {
+	typ := schema.ObjectType{
+		Name:        "expiry",
+		KeyFields:   []schema.Field{{Name: "id", Kind: schema.Uint64Kind}},
+		ValueFields: []schema.Field{{Name: "expires", Kind: schema.TimeKind, Nullable: true}},
+	}
+	tm := NewObjectIndexer("test", typ, Options{Dialect: DialectSQLite})
+
+	buf := new(strings.Builder)
+	if err := tm.createSQLiteColumnDefinition(buf, typ.ValueFields[0]); err != nil {
+		t.Fatal(err)
+	}
+	// the time is generated from the nanoseconds, which carry the nullability of the field
+	expected := `"expires" TEXT GENERATED ALWAYS AS (strftime('%Y-%m-%dT%H:%M:%fZ', "expires_nanos" / 1000000000.0, 'unixepoch')) VIRTUAL,
+	"expires_nanos" INTEGER NULL,
+	`
+	if buf.String() != expected {
+		t.Fatalf("expected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+
+	typ.ValueFields[0].TimeResolution = schema.TimeResolutionSeconds
+	tm = NewObjectIndexer("test", typ, Options{Dialect: DialectSQLite})
+	buf.Reset()
+	if err := tm.createSQLiteColumnDefinition(buf, typ.ValueFields[0]); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "\"expires\" TIMESTAMP NULL,\n\t"; buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestSQLiteDML(t *testing.T) {
+	vote := NewObjectIndexer("test", testdata.VoteObject, Options{Dialect: DialectSQLite})
+	voteNoRetain := NewObjectIndexer("test", testdata.VoteObject, Options{Dialect: DialectSQLite, DisableRetainDeletions: true})
+	singleton := NewObjectIndexer("test", testdata.SingletonObject, Options{Dialect: DialectSQLite})
+	allKinds := NewObjectIndexer("test", testdata.AllKindsObject, Options{Dialect: DialectSQLite})
+	voteKey := []interface{}{int64(1), []byte{0x01, 0x02}}
+
+	tests := []struct {
+		name  string
+		tm    *ObjectIndexer
+		write func(tm *ObjectIndexer, writer io.Writer, keyParams []interface{}) ([]interface{}, error)
+		key   interface{}
+	}{
+		{
+			name: "upsert",
+			tm:   vote,
+			key:  voteKey,
+			write: func(tm *ObjectIndexer, writer io.Writer, keyParams []interface{}) ([]interface{}, error) {
+				return tm.upsertSql(writer, keyParams, "yes")
+			},
+		},
+		{
+			name: "upsert singleton",
+			tm:   singleton,
+			write: func(tm *ObjectIndexer, writer io.Writer, keyParams []interface{}) ([]interface{}, error) {
+				return tm.upsertSql(writer, keyParams, []interface{}{"foo", nil, "a"})
+			},
+		},
+		{
+			name: "update",
+			tm:   vote,
+			key:  voteKey,
+			write: func(tm *ObjectIndexer, writer io.Writer, keyParams []interface{}) ([]interface{}, error) {
+				return tm.updateSql(writer, keyParams, schema.MapValueUpdates{"vote": "abstain"})
+			},
+		},
+		{
+			name: "update time and nanos",
+			tm:   allKinds,
+			key:  []interface{}{int64(2), time.Unix(0, 5)},
+			write: func(tm *ObjectIndexer, writer io.Writer, keyParams []interface{}) ([]interface{}, error) {
+				return tm.updateSql(writer, keyParams, schema.MapValueUpdates{"time": time.Unix(1, 7)})
+			},
+		},
+		{
+			name: "delete retaining deletions",
+			tm:   vote,
+			key:  voteKey,
+			write: func(tm *ObjectIndexer, writer io.Writer, keyParams []interface{}) ([]interface{}, error) {
+				return tm.deleteSql(writer, keyParams)
+			},
+		},
+		{
+			name: "delete",
+			tm:   voteNoRetain,
+			key:  voteKey,
+			write: func(tm *ObjectIndexer, writer io.Writer, keyParams []interface{}) ([]interface{}, error) {
+				return tm.deleteSql(writer, keyParams)
+			},
+		},
+	}
+
+	out := new(strings.Builder)
+	for _, tt := range tests {
+		keyParams, err := tt.tm.bindKeyParams(tt.key)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		buf := new(strings.Builder)
+		params, err := tt.write(tt.tm, buf, keyParams)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		_, err = io.WriteString(out, "-- "+tt.name+"\n"+buf.String()+"\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, param := range params {
+			if _, err := io.WriteString(out, "--   "+formatParam(param)+"\n"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	assertGolden(t, out.String(), "sqlite_dml.golden")
+}
+
+// formatParam formats a bound parameter with its go type so that the golden file shows how values are bound.
+func formatParam(param interface{}) string {
+	if param == nil {
+		return "<nil>"
+	}
+	return strings.TrimSpace(strings.Replace(fmt.Sprintf("%T(%v)", param, param), "\n", " ", -1))
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"cosmossdk.io/schema"
+)
+
+// HasHistory returns true if history tables are enabled and the retention policy of the object type requires
+// keeping historical versions of its objects.
+func (tm *ObjectIndexer) HasHistory() bool {
+	if !tm.options.HistoryTables {
+		return false
+	}
+	return tm.typ.Retention == nil || tm.typ.Retention.Mode != schema.RetentionKeepLatest
+}
+
+// HistoryTableName returns the name of the history table of the object type.
+func (tm *ObjectIndexer) HistoryTableName() string {
+	return fmt.Sprintf("%s_history", tm.TableName())
+}
+
+// CreateHistoryTable creates the history table for the object type.
+func (tm *ObjectIndexer) CreateHistoryTable(ctx context.Context, conn DBConn) error {
+	buf := new(strings.Builder)
+	err := tm.CreateHistoryTableSql(buf)
+	if err != nil {
+		return err
+	}
+
+	sqlStr := buf.String()
+	if tm.options.Logger != nil {
+		tm.options.Logger(fmt.Sprintf("Creating table %s", tm.HistoryTableName()), sqlStr)
+	}
+	_, err = conn.ExecContext(ctx, sqlStr)
+	return err
+}
+
+// CreateHistoryTableSql generates a CREATE TABLE statement for the history table of the object type. The history
+// table has one row for each version of an object with the same columns as the object type's table, the height
+// at which the version was written in _valid_from and the height at which it was replaced or deleted in _valid_to,
+// which is NULL for the current version.
+func (tm *ObjectIndexer) CreateHistoryTableSql(writer io.Writer) error {
+	_, err := fmt.Fprintf(writer, "CREATE TABLE IF NOT EXISTS %q (\n\t", tm.HistoryTableName())
+	if err != nil {
+		return err
+	}
+
+	if len(tm.typ.KeyFields) == 0 {
+		_, err = fmt.Fprintf(writer, "_id INTEGER NOT NULL CHECK (_id = 1),\n\t")
+		if err != nil {
+			return err
+		}
+	}
+	for _, field := range tm.typ.KeyFields {
+		err = tm.createColumnDefinition(writer, field)
+		if err != nil {
+			return err
+		}
+	}
+	for _, field := range tm.typ.ValueFields {
+		err = tm.createColumnDefinition(writer, field)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(writer, "_valid_from BIGINT NOT NULL,\n\t_valid_to BIGINT NULL,\n\tPRIMARY KEY (%s, _valid_from)\n)",
+		strings.Join(tm.primaryKeyColumns(), ", "))
+	if err != nil {
+		return err
+	}
+
+	// history tables are partitioned by the height at which versions were written
+	if tm.options.PartitionSize != 0 {
+		_, err = fmt.Fprintf(writer, " PARTITION BY RANGE (_valid_from)")
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(writer, ";\n")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "GRANT SELECT ON TABLE %q TO PUBLIC;\n", tm.HistoryTableName())
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "CREATE INDEX IF NOT EXISTS %q ON %q (_valid_to);",
+		fmt.Sprintf("%s_valid_to", tm.HistoryTableName()), tm.HistoryTableName())
+	return err
+}
+
+// MigrateHistoryTable migrates the existing history table of the object type like MigrateTable.
+func (tm *ObjectIndexer) MigrateHistoryTable(ctx context.Context, conn DBConn, existing []ColumnInfo) (string, error) {
+	return tm.execMigration(ctx, conn, tm.HistoryTableName(), existing, tm.MigrateHistoryTableSql)
+}
+
+// MigrateHistoryTableSql generates the ALTER TABLE statements which migrate the history table of the object type
+// like MigrateTableSql.
+func (tm *ObjectIndexer) MigrateHistoryTableSql(writer io.Writer, existing []ColumnInfo) error {
+	statements, err := tm.migrateColumns(tm.HistoryTableName(), existing, true)
+	if err != nil || len(statements) == 0 {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "%s", strings.Join(statements, "\n"))
+	return err
+}
+
+// recordHistory records the version of an object written at height, which must be called after the update
+// was applied to the object type's table. The previous version is closed at height and replaced if it was also
+// written at height, so that there is at most one version of each object per height.
+func (tm *ObjectIndexer) recordHistory(ctx context.Context, conn DBConn, keyParams []interface{}, deleted bool, height uint64) error {
+	where, params := tm.whereKeySql(nil, keyParams)
+	heightParam := fmt.Sprintf("$%d", len(params)+1)
+	params = append(params, int64(height))
+
+	statements := []string{
+		fmt.Sprintf("DELETE FROM %q WHERE %s AND _valid_from = %s;", tm.HistoryTableName(), where, heightParam),
+		fmt.Sprintf("UPDATE %q SET _valid_to = %s WHERE %s AND _valid_to IS NULL;", tm.HistoryTableName(), heightParam, where),
+	}
+
+	if !deleted {
+		cols := tm.primaryKeyColumns()
+		for _, field := range tm.typ.ValueFields {
+			col, err := tm.updatableColumnName(field)
+			if err != nil {
+				return err
+			}
+			cols = append(cols, col)
+		}
+
+		colList := strings.Join(cols, ", ")
+		statements = append(statements, fmt.Sprintf("INSERT INTO %q (%s, _valid_from) SELECT %s, %s::BIGINT FROM %q WHERE %s;",
+			tm.HistoryTableName(), colList, colList, heightParam, tm.TableName(), where))
+	}
+
+	for _, sqlStr := range statements {
+		if tm.options.Logger != nil {
+			tm.options.Logger(fmt.Sprintf("Updating %s", tm.HistoryTableName()), sqlStr, params...)
+		}
+		if _, err := conn.ExecContext(ctx, sqlStr, params...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PruneHistory deletes the versions in the history table which were replaced before the number of blocks which
+// the object type's retention policy keeps, if it has a RetentionKeepBlocks policy.
+func (tm *ObjectIndexer) PruneHistory(ctx context.Context, conn DBConn, height uint64) error {
+	if !tm.HasHistory() || tm.typ.Retention == nil || tm.typ.Retention.Mode != schema.RetentionKeepBlocks {
+		return nil
+	}
+
+	if height <= tm.typ.Retention.Blocks {
+		return nil
+	}
+
+	cutoff := height - tm.typ.Retention.Blocks
+	if tm.options.PartitionSize != 0 {
+		// partitions in which all versions can be pruned are dropped instead of deleting their rows
+		err := dropPartitions(ctx, conn, tm.options, tm.HistoryTableName(), cutoff+1, func(partitionName string) (bool, error) {
+			var keep bool
+			err := conn.QueryRowContext(ctx,
+				fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %q WHERE _valid_to IS NULL OR _valid_to > $1);", partitionName),
+				int64(cutoff)).Scan(&keep)
+			return keep, err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	sqlStr := fmt.Sprintf("DELETE FROM %q WHERE _valid_to IS NOT NULL AND _valid_to <= $1;", tm.HistoryTableName())
+	params := []interface{}{int64(cutoff)}
+	if tm.options.Logger != nil {
+		tm.options.Logger(fmt.Sprintf("Pruning %s", tm.HistoryTableName()), sqlStr, params...)
+	}
+	_, err := conn.ExecContext(ctx, sqlStr, params...)
+	return err
+}
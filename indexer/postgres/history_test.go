@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"fmt"
+	"os"
+
+	"cosmossdk.io/indexer/postgres/internal/testdata"
+	"cosmossdk.io/schema"
+)
+
+func ExampleObjectIndexer_CreateHistoryTableSql_vote() {
+	tm := NewObjectIndexer("test", testdata.VoteObject, Options{HistoryTables: true})
+	err := tm.CreateHistoryTableSql(os.Stdout)
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+	// CREATE TABLE IF NOT EXISTS "test_vote_history" (
+	// 	"proposal" BIGINT NOT NULL,
+	//	"address" TEXT NOT NULL,
+	//	"vote" "test_vote_type" NOT NULL,
+	//	_valid_from BIGINT NOT NULL,
+	//	_valid_to BIGINT NULL,
+	//	PRIMARY KEY ("proposal", "address", _valid_from)
+	// );
+	// GRANT SELECT ON TABLE "test_vote_history" TO PUBLIC;
+	// CREATE INDEX IF NOT EXISTS "test_vote_history_valid_to" ON "test_vote_history" (_valid_to);
+}
+
+func ExampleObjectIndexer_MigrateHistoryTableSql() {
+	voteObject := testdata.VoteObject
+	voteObject.ValueFields = append(voteObject.ValueFields, schema.Field{Name: "memo", Kind: schema.StringKind, Nullable: true})
+	tm := NewObjectIndexer("test", voteObject, Options{HistoryTables: true})
+	err := tm.MigrateHistoryTableSql(os.Stdout, []ColumnInfo{
+		{Name: "proposal", UDTName: "int8"},
+		{Name: "address", UDTName: "text"},
+		{Name: "vote", UDTName: "test_vote_type"},
+		{Name: "_valid_from", UDTName: "int8"},
+		{Name: "_valid_to", UDTName: "int8", Nullable: true},
+	})
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+	// ALTER TABLE "test_vote_history" ADD COLUMN "memo" TEXT NULL;
+}
+
+func ExampleObjectIndexer_HasHistory() {
+	keepLatest := testdata.VoteObject
+	keepLatest.Retention = &schema.RetentionPolicy{Mode: schema.RetentionKeepLatest}
+	fmt.Println(NewObjectIndexer("test", testdata.VoteObject, Options{}).HasHistory())
+	fmt.Println(NewObjectIndexer("test", testdata.VoteObject, Options{HistoryTables: true}).HasHistory())
+	fmt.Println(NewObjectIndexer("test", keepLatest, Options{HistoryTables: true}).HasHistory())
+	// Output:
+	// false
+	// true
+	// false
+}
+
+func ExampleObjectIndexer_upsertSql() {
+	tm := NewObjectIndexer("test", testdata.VoteObject, Options{})
+	params, err := tm.upsertSql(os.Stdout, []interface{}{int64(1), "0102"}, "yes")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println()
+	fmt.Println(params...)
+	// Output:
+	// INSERT INTO "test_vote" ("proposal", "address", "vote") VALUES ($1, $2, $3) ON CONFLICT ("proposal", "address") DO UPDATE SET "vote" = EXCLUDED."vote", _deleted = FALSE;
+	// 1 0102 yes
+}
+
+func ExampleObjectIndexer_deleteSql() {
+	tm := NewObjectIndexer("test", testdata.SingletonObject, Options{})
+	_, err := tm.deleteSql(os.Stdout, nil)
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+	// DELETE FROM "test_singleton" WHERE _id = 1;
+}
@@ -17,13 +17,67 @@ type Config struct {
 
 	// DisableRetainDeletions disables the retain deletions functionality even if it is set in an object type schema.
 	DisableRetainDeletions bool `json:"disable_retain_deletions"`
+
+	// HistoryTables enables history tables which record every version of the objects of object types whose
+	// retention policy keeps history, with the heights between which each version was valid.
+	HistoryTables bool `json:"history_tables"`
+
+	// DisableBulkWrites disables writing object updates in bulk with the COPY protocol while blocks are backfilled.
+	DisableBulkWrites bool `json:"disable_bulk_writes"`
+
+	// BulkBatchSize is the number of object updates which are buffered for each object type while blocks are
+	// backfilled before they are written. It defaults to DefaultBulkBatchSize.
+	BulkBatchSize int `json:"bulk_batch_size"`
+
+	// CopyFrom is used to write object updates with the COPY protocol. It defaults to CopyIn, which only
+	// supports the github.com/lib/pq driver, so other drivers need to provide a function which uses their
+	// native COPY API.
+	CopyFrom CopyFromFunc `json:"-"`
+
+	// PartitionSize enables partitioning the block, transaction, event and history tables by ranges of this
+	// number of block heights if it is not zero. Partitions are created automatically as blocks are indexed.
+	PartitionSize uint64 `json:"partition_size"`
+
+	// RetainBlocks is the number of most recent blocks whose block, transaction and event data is kept when
+	// partitioning is enabled. Partitions which only contain older blocks are dropped. If it is zero, all
+	// blocks are kept.
+	RetainBlocks uint64 `json:"retain_blocks"`
+
+	// Dialect is the SQL dialect of the database. It defaults to DialectPostgres.
+	Dialect Dialect `json:"-"`
 }
 
 type SqlLogger = func(msg, sql string, params ...interface{})
 
+// Indexer indexes app data into a database and provides a view of the indexed data.
+type Indexer struct {
+	listener appdata.Listener
+	view     *View
+}
+
+// Listener returns the app data listener of the indexer.
+func (i *Indexer) Listener() appdata.Listener {
+	return i.listener
+}
+
+// View returns a view of the data which the indexer has committed.
+func (i *Indexer) View() *View {
+	return i.view
+}
+
+// StartIndexer starts an indexer and returns its listener.
 func StartIndexer(ctx context.Context, logger SqlLogger, config Config) (appdata.Listener, error) {
+	idx, err := NewIndexer(ctx, logger, config)
+	if err != nil {
+		return appdata.Listener{}, err
+	}
+	return idx.Listener(), nil
+}
+
+// NewIndexer connects to the database, creates the base schema and returns an indexer.
+func NewIndexer(ctx context.Context, logger SqlLogger, config Config) (*Indexer, error) {
 	if config.DatabaseURL == "" {
-		return appdata.Listener{}, fmt.Errorf("missing database URL")
+		return nil, fmt.Errorf("missing database URL")
 	}
 
 	driver := config.DatabaseDriver
@@ -31,34 +85,69 @@ func StartIndexer(ctx context.Context, logger SqlLogger, config Config) (appdata
 		driver = "pgx"
 	}
 
+	opts := Options{
+		Dialect:                config.Dialect,
+		DisableRetainDeletions: config.DisableRetainDeletions,
+		HistoryTables:          config.HistoryTables,
+		PartitionSize:          config.PartitionSize,
+		BulkBatchSize:          config.BulkBatchSize,
+		CopyFrom:               config.CopyFrom,
+		Logger:                 logger,
+	}
+	if err := opts.Dialect.validateOptions(opts); err != nil {
+		return nil, err
+	}
+	if opts.Dialect != DialectPostgres {
+		// the COPY protocol is specific to PostgreSQL
+		config.DisableBulkWrites = true
+	}
+
 	db, err := sql.Open(driver, config.DatabaseURL)
 	if err != nil {
-		return appdata.Listener{}, err
+		return nil, err
 	}
 
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		return appdata.Listener{}, err
+		return nil, err
 	}
 
-	// commit base schema
-	_, err = tx.Exec(BaseSQL)
+	if opts.Dialect == DialectPostgres {
+		_, err = tx.Exec(BaseSQL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = CreateBlockTables(ctx, tx, opts)
 	if err != nil {
-		return appdata.Listener{}, err
+		return nil, err
 	}
 
-	moduleIndexers := map[string]*ModuleIndexer{}
-	opts := Options{
-		DisableRetainDeletions: config.DisableRetainDeletions,
-		Logger:                 logger,
+	// the base schema is committed right away so that the view can be queried before the first block
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
 	}
 
-	return appdata.Listener{
+	tx, err = db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	moduleIndexers := map[string]*ModuleIndexer{}
+	parts := newPartitions(opts)
+	var height uint64
+	var backfill bool
+
+	listener := appdata.Listener{
 		InitializeModuleData: func(data appdata.ModuleInitializationData) error {
 			moduleName := data.ModuleName
 			modSchema := data.Schema
+			// a module is only initialized again if its schema changed, in which case the tables of new object
+			// types are created and the tables of existing ones are migrated
 			_, ok := moduleIndexers[moduleName]
-			if ok {
+			if ok && !data.SchemaChanged {
 				return fmt.Errorf("module %s already initialized", moduleName)
 			}
 
@@ -67,7 +156,56 @@ func StartIndexer(ctx context.Context, logger SqlLogger, config Config) (appdata
 
 			return mm.InitializeSchema(ctx, tx)
 		},
+		StartBlock: func(data appdata.StartBlockData) error {
+			height = data.Height
+			backfill = data.Backfill
+
+			tableNames := append([]string{}, BlockTableNames...)
+			for _, mm := range moduleIndexers {
+				tableNames = append(tableNames, mm.PartitionedTables()...)
+			}
+			err := parts.ensure(ctx, tx, tableNames, height)
+			if err != nil {
+				return err
+			}
+
+			return insertBlock(ctx, tx, opts, data)
+		},
+		OnTx: func(data appdata.TxData) error {
+			return insertTx(ctx, tx, opts, height, data)
+		},
+		OnEvent: func(data appdata.EventData) error {
+			return insertEvent(ctx, tx, opts, height, data)
+		},
+		OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+			mm, ok := moduleIndexers[data.ModuleName]
+			if !ok {
+				return fmt.Errorf("module %s not initialized", data.ModuleName)
+			}
+
+			if backfill && !config.DisableBulkWrites {
+				return mm.BulkUpdateObjects(ctx, tx, data.Updates, height)
+			}
+			return mm.UpdateObjects(ctx, tx, data.Updates, height)
+		},
 		Commit: func(data appdata.CommitData) error {
+			for _, mm := range moduleIndexers {
+				err = mm.FlushBulk(ctx, tx)
+				if err != nil {
+					return err
+				}
+
+				err = mm.PruneHistory(ctx, tx, height)
+				if err != nil {
+					return err
+				}
+			}
+
+			err = DropBlockPartitions(ctx, tx, opts, height, config.RetainBlocks)
+			if err != nil {
+				return err
+			}
+
 			err = tx.Commit()
 			if err != nil {
 				return err
@@ -76,5 +214,10 @@ func StartIndexer(ctx context.Context, logger SqlLogger, config Config) (appdata
 			tx, err = db.BeginTx(ctx, nil)
 			return err
 		},
+	}
+
+	return &Indexer{
+		listener: listener,
+		view:     NewView(ctx, db, moduleIndexers),
 	}, nil
 }
@@ -29,21 +29,31 @@ func init() {
 
 		switch i {
 		case schema.EnumKind:
-			field.EnumDefinition = MyEnum
-		case schema.Bech32AddressKind:
-			field.AddressPrefix = "foo"
+			field.EnumType = MyEnum
+		case schema.StructKind:
+			field.StructType = MyStruct
+		case schema.ListKind:
+			field.ElementKind = schema.StringKind
+		case schema.MapKind:
+			field.KeyKind = schema.StringKind
+			field.ValueKind = schema.Int64Kind
+		case schema.OneOfKind:
+			field.OneOfType = MyOneOf
 		default:
 		}
 
 		AllKindsObject.ValueFields = append(AllKindsObject.ValueFields, field)
 	}
 
-	ExampleSchema = schema.ModuleSchema{
-		ObjectTypes: []schema.ObjectType{
-			AllKindsObject,
-			SingletonObject,
-			VoteObject,
-		},
+	var err error
+	ExampleSchema, err = schema.NewModuleSchema([]schema.ObjectType{
+		AllKindsObject,
+		SingletonObject,
+		VoteObject,
+		ValidatorObject,
+	})
+	if err != nil {
+		panic(err)
 	}
 }
 
@@ -60,9 +70,9 @@ var SingletonObject = schema.ObjectType{
 			Nullable: true,
 		},
 		{
-			Name:           "an_enum",
-			Kind:           schema.EnumKind,
-			EnumDefinition: MyEnum,
+			Name:     "an_enum",
+			Kind:     schema.EnumKind,
+			EnumType: MyEnum,
 		},
 	},
 }
@@ -76,14 +86,14 @@ var VoteObject = schema.ObjectType{
 		},
 		{
 			Name: "address",
-			Kind: schema.Bech32AddressKind,
+			Kind: schema.AddressKind,
 		},
 	},
 	ValueFields: []schema.Field{
 		{
 			Name: "vote",
 			Kind: schema.EnumKind,
-			EnumDefinition: schema.EnumDefinition{
+			EnumType: schema.EnumType{
 				Name:   "vote_type",
 				Values: []string{"yes", "no", "abstain"},
 			},
@@ -92,7 +102,80 @@ var VoteObject = schema.ObjectType{
 	RetainDeletions: true,
 }
 
-var MyEnum = schema.EnumDefinition{
+var ValidatorObject = schema.ObjectType{
+	Name: "validator",
+	KeyFields: []schema.Field{
+		{
+			Name: "operator",
+			Kind: schema.AddressKind,
+		},
+	},
+	ValueFields: []schema.Field{
+		{
+			Name: "moniker",
+			Kind: schema.StringKind,
+		},
+		{
+			Name: "consensus_pubkey",
+			Kind: schema.BytesKind,
+		},
+		{
+			Name: "tokens",
+			Kind: schema.IntegerStringKind,
+		},
+		{
+			Name: "jailed",
+			Kind: schema.BoolKind,
+		},
+		{
+			Name:           "jailed_until",
+			Kind:           schema.TimeKind,
+			TimeResolution: schema.TimeResolutionSeconds,
+		},
+	},
+	UniqueConstraints: [][]string{{"consensus_pubkey"}},
+	Indexes: []schema.IndexDescriptor{
+		{
+			Name:   "by_tokens",
+			Fields: []schema.IndexField{{Name: "tokens", Descending: true}},
+		},
+		{
+			Name:   "by_jailed_until",
+			Fields: []schema.IndexField{{Name: "jailed"}, {Name: "jailed_until"}},
+		},
+	},
+}
+
+var MyEnum = schema.EnumType{
 	Name:   "my_enum",
 	Values: []string{"a", "b", "c"},
 }
+
+var MyStruct = schema.StructType{
+	Name: "my_struct",
+	Fields: []schema.Field{
+		{
+			Name: "foo",
+			Kind: schema.StringKind,
+		},
+		{
+			Name: "bar",
+			Kind: schema.Int32Kind,
+		},
+	},
+}
+
+var MyOneOf = schema.OneOfType{
+	Name: "my_oneof",
+	Cases: []schema.Field{
+		{
+			Name: "text",
+			Kind: schema.StringKind,
+		},
+		{
+			Name:       "struct",
+			Kind:       schema.StructKind,
+			StructType: MyStruct,
+		},
+	},
+}
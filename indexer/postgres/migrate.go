@@ -0,0 +1,267 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"cosmossdk.io/schema"
+)
+
+// MigrationsTableName is the name of the table in which applied schema migrations are recorded.
+const MigrationsTableName = "indexer_schema_migrations"
+
+// ColumnInfo describes an existing column of a table.
+type ColumnInfo struct {
+	// Name is the name of the column.
+	Name string
+
+	// UDTName is the name of the column's type as reported by information_schema.columns.udt_name, for
+	// instance "int8" for BIGINT columns or the name of the enum type for enum columns.
+	UDTName string
+
+	// Nullable indicates whether the column is nullable.
+	Nullable bool
+}
+
+// ExistingColumns returns the columns of the object type's table in the database, or nil if the table doesn't exist.
+func (tm *ObjectIndexer) ExistingColumns(ctx context.Context, conn DBConn) ([]ColumnInfo, error) {
+	return existingColumns(ctx, conn, tm.TableName())
+}
+
+func existingColumns(ctx context.Context, conn DBConn, tableName string) ([]ColumnInfo, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT column_name, udt_name, is_nullable = 'YES'
+FROM information_schema.columns
+WHERE table_schema = current_schema() AND table_name = $1
+ORDER BY ordinal_position`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cols []ColumnInfo
+	for rows.Next() {
+		var col ColumnInfo
+		if err := rows.Scan(&col.Name, &col.UDTName, &col.Nullable); err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	return cols, rows.Err()
+}
+
+// MigrateTable migrates the existing table of the object type, whose columns are existing, to the current
+// object type and returns the SQL that was applied, which is empty if the table was up-to-date.
+func (tm *ObjectIndexer) MigrateTable(ctx context.Context, conn DBConn, existing []ColumnInfo) (string, error) {
+	return tm.execMigration(ctx, conn, tm.TableName(), existing, tm.MigrateTableSql)
+}
+
+func (tm *ObjectIndexer) execMigration(ctx context.Context, conn DBConn, tableName string, existing []ColumnInfo,
+	migrateSql func(io.Writer, []ColumnInfo) error,
+) (string, error) {
+	buf := new(strings.Builder)
+	err := migrateSql(buf, existing)
+	if err != nil {
+		return "", err
+	}
+
+	sqlStr := buf.String()
+	if sqlStr == "" {
+		return "", nil
+	}
+
+	if tm.options.Logger != nil {
+		tm.options.Logger(fmt.Sprintf("Migrating table %s", tableName), sqlStr)
+	}
+	_, err = conn.ExecContext(ctx, sqlStr)
+	return sqlStr, err
+}
+
+// MigrateTableSql generates the ALTER TABLE statements which migrate a table with the existing columns to the
+// object type. Only compatible changes are migrated: new nullable value fields are added, value fields which
+// became nullable have their NOT NULL constraint dropped and the _deleted column is added if retain deletions
+// was enabled. For any other change, such as removed fields, new non-nullable fields or changed kinds, an error
+// describing all incompatible changes is returned and nothing is written.
+func (tm *ObjectIndexer) MigrateTableSql(writer io.Writer, existing []ColumnInfo) error {
+	statements, err := tm.migrateColumns(tm.TableName(), existing, false)
+	if err != nil || len(statements) == 0 {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "%s", strings.Join(statements, "\n"))
+	if err != nil {
+		return err
+	}
+
+	// indexes are created if they don't exist yet
+	return tm.createIndexesSql(writer)
+}
+
+// migrateColumns returns the statements which migrate the columns of the table with the existing columns, which
+// is either the table of the object type or its history table, to the object type.
+func (tm *ObjectIndexer) migrateColumns(tableName string, existing []ColumnInfo, history bool) ([]string, error) {
+	existingCols := make(map[string]ColumnInfo, len(existing))
+	for _, col := range existing {
+		existingCols[col.Name] = col
+	}
+
+	var statements, incompatible []string
+	expectedCols := map[string]bool{}
+
+	checkField := func(field schema.Field, isKey bool) error {
+		cols := tm.fieldColumns(field)
+		for _, col := range cols {
+			expectedCols[col.Name] = true
+		}
+
+		// the last column is the updatable one, i.e. the _nanos column of nanosecond resolution time fields
+		updatable := cols[len(cols)-1]
+		existingCol, ok := existingCols[updatable.Name]
+		if !ok {
+			switch {
+			case isKey:
+				incompatible = append(incompatible, fmt.Sprintf("key field %q was added", field.Name))
+			case !field.Nullable:
+				incompatible = append(incompatible, fmt.Sprintf("non-nullable value field %q was added", field.Name))
+			default:
+				stmts, err := tm.addColumnSql(tableName, field)
+				if err != nil {
+					return err
+				}
+				statements = append(statements, stmts...)
+			}
+			return nil
+		}
+
+		for _, col := range cols {
+			existingCol, ok := existingCols[col.Name]
+			if ok && existingCol.UDTName != col.UDTName {
+				incompatible = append(incompatible, fmt.Sprintf("column %q has type %s but field %q requires %s",
+					col.Name, existingCol.UDTName, field.Name, col.UDTName))
+			}
+		}
+
+		switch {
+		case existingCol.Nullable && !field.Nullable:
+			incompatible = append(incompatible, fmt.Sprintf("field %q is no longer nullable", field.Name))
+		case !existingCol.Nullable && field.Nullable:
+			if isKey {
+				incompatible = append(incompatible, fmt.Sprintf("key field %q became nullable", field.Name))
+			} else {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %q ALTER COLUMN %q DROP NOT NULL;", tableName, updatable.Name))
+			}
+		}
+		return nil
+	}
+
+	if len(tm.typ.KeyFields) == 0 {
+		expectedCols["_id"] = true
+		if _, ok := existingCols["_id"]; !ok {
+			incompatible = append(incompatible, "the object type became a singleton")
+		}
+	}
+	for _, field := range tm.typ.KeyFields {
+		if err := checkField(field, true); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range tm.typ.ValueFields {
+		if err := checkField(field, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if history {
+		expectedCols["_valid_from"] = true
+		expectedCols["_valid_to"] = true
+	} else {
+		// the _deleted column is kept if retain deletions is disabled because it has a default value
+		expectedCols["_deleted"] = true
+		if tm.retainDeletions() {
+			if _, ok := existingCols["_deleted"]; !ok {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %q ADD COLUMN _deleted BOOLEAN NOT NULL DEFAULT FALSE;", tableName))
+			}
+		}
+	}
+
+	for _, col := range existing {
+		if !expectedCols[col.Name] {
+			incompatible = append(incompatible, fmt.Sprintf("column %q does not correspond to any field", col.Name))
+		}
+	}
+
+	if len(incompatible) != 0 {
+		return nil, fmt.Errorf("incompatible schema change for table %q: %s; these changes can't be migrated automatically, drop the table and re-index the module",
+			tableName, strings.Join(incompatible, ", "))
+	}
+
+	return statements, nil
+}
+
+// fieldColumns returns the columns of a field with the names and the UDT names they have in the database.
+func (tm *ObjectIndexer) fieldColumns(field schema.Field) []ColumnInfo {
+	if field.Kind == schema.TimeKind && field.TimeResolution == schema.TimeResolutionNanos {
+		return []ColumnInfo{
+			{Name: field.Name, UDTName: "timestamptz", Nullable: true},
+			{Name: fmt.Sprintf("%s_nanos", field.Name), UDTName: "int8", Nullable: field.Nullable},
+		}
+	}
+
+	var udtName string
+	switch field.Kind {
+	case schema.EnumKind:
+		udtName = enumTypeName(tm.moduleName, field.EnumType)
+	case schema.TimeKind:
+		udtName = "timestamptz"
+	default:
+		udtName = udtNames[simpleColumnType(field.Kind)]
+	}
+	return []ColumnInfo{{Name: field.Name, UDTName: udtName, Nullable: field.Nullable}}
+}
+
+// udtNames maps the simple column types to the names of the types in information_schema.columns.udt_name.
+var udtNames = map[string]string{
+	"TEXT":             "text",
+	"BOOLEAN":          "bool",
+	"BYTEA":            "bytea",
+	"SMALLINT":         "int2",
+	"INTEGER":          "int4",
+	"BIGINT":           "int8",
+	"NUMERIC":          "numeric",
+	"REAL":             "float4",
+	"DOUBLE PRECISION": "float8",
+	"JSONB":            "jsonb",
+}
+
+// addColumnSql generates the ALTER TABLE statements which add the columns of a nullable value field.
+func (tm *ObjectIndexer) addColumnSql(tableName string, field schema.Field) ([]string, error) {
+	if field.Kind == schema.TimeKind && field.TimeResolution == schema.TimeResolutionNanos {
+		nanosColName := fmt.Sprintf("%s_nanos", field.Name)
+		return []string{
+			fmt.Sprintf("ALTER TABLE %q ADD COLUMN %q BIGINT NULL;", tableName, nanosColName),
+			fmt.Sprintf("ALTER TABLE %q ADD COLUMN %q TIMESTAMPTZ GENERATED ALWAYS AS (nanos_to_timestamptz(%q)) STORED;",
+				tableName, field.Name, nanosColName),
+		}, nil
+	}
+
+	buf := new(strings.Builder)
+	// the column definition is the same as in CREATE TABLE, without the trailing separator
+	if err := tm.createColumnDefinition(buf, field); err != nil {
+		return nil, err
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %q ADD COLUMN %s;", tableName, strings.TrimSuffix(buf.String(), ",\n\t"))}, nil
+}
+
+// retainDeletions returns true if the table has a _deleted column to retain deletions.
+func (tm *ObjectIndexer) retainDeletions() bool {
+	return !tm.options.DisableRetainDeletions && tm.typ.RetainDeletions
+}
+
+// recordMigration records SQL applied to the table of an object type in the migrations table.
+func (m *ModuleIndexer) recordMigration(ctx context.Context, conn DBConn, tableName, sqlStr string) error {
+	_, err := conn.ExecContext(ctx,
+		fmt.Sprintf("INSERT INTO %q (module_name, table_name, sql) VALUES ($1, $2, $3);", MigrationsTableName),
+		m.moduleName, tableName, sqlStr)
+	return err
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"fmt"
+	"os"
+
+	"cosmossdk.io/indexer/postgres/internal/testdata"
+	"cosmossdk.io/schema"
+)
+
+var existingVoteColumns = []ColumnInfo{
+	{Name: "proposal", UDTName: "int8"},
+	{Name: "address", UDTName: "text"},
+	{Name: "vote", UDTName: "test_vote_type"},
+	{Name: "_deleted", UDTName: "bool"},
+}
+
+func ExampleObjectIndexer_MigrateTableSql_addNullableFields() {
+	voteObject := testdata.VoteObject
+	voteObject.ValueFields = append(voteObject.ValueFields,
+		schema.Field{Name: "memo", Kind: schema.StringKind, Nullable: true},
+		schema.Field{Name: "voted_at", Kind: schema.TimeKind, Nullable: true},
+	)
+	exampleMigrateTable(voteObject, existingVoteColumns)
+	// Output:
+	// ALTER TABLE "test_vote" ADD COLUMN "memo" TEXT NULL;
+	// ALTER TABLE "test_vote" ADD COLUMN "voted_at_nanos" BIGINT NULL;
+	// ALTER TABLE "test_vote" ADD COLUMN "voted_at" TIMESTAMPTZ GENERATED ALWAYS AS (nanos_to_timestamptz("voted_at_nanos")) STORED;
+}
+
+func ExampleObjectIndexer_MigrateTableSql_upToDate() {
+	exampleMigrateTable(testdata.VoteObject, existingVoteColumns)
+	// Output:
+}
+
+func ExampleObjectIndexer_MigrateTableSql_dropNotNull() {
+	voteObject := testdata.VoteObject
+	voteObject.ValueFields = []schema.Field{voteObject.ValueFields[0]}
+	voteObject.ValueFields[0].Nullable = true
+	exampleMigrateTable(voteObject, existingVoteColumns)
+	// Output:
+	// ALTER TABLE "test_vote" ALTER COLUMN "vote" DROP NOT NULL;
+}
+
+func ExampleObjectIndexer_MigrateTableSql_incompatible() {
+	voteObject := testdata.VoteObject
+	voteObject.KeyFields = []schema.Field{voteObject.KeyFields[0], {Name: "address", Kind: schema.BytesKind}}
+	voteObject.ValueFields = []schema.Field{{Name: "weight", Kind: schema.StringKind}}
+	exampleMigrateTable(voteObject, existingVoteColumns)
+	// Output:
+	// incompatible schema change for table "test_vote": column "address" has type text but field "address" requires bytea, non-nullable value field "weight" was added, column "vote" does not correspond to any field; these changes can't be migrated automatically, drop the table and re-index the module
+}
+
+func exampleMigrateTable(objectType schema.ObjectType, existing []ColumnInfo) {
+	tm := NewObjectIndexer("test", objectType, Options{})
+	err := tm.MigrateTableSql(os.Stdout, existing)
+	if err != nil {
+		fmt.Println(err)
+	}
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"cosmossdk.io/schema"
 )
@@ -12,7 +13,7 @@ type ModuleIndexer struct {
 	moduleName   string
 	schema       schema.ModuleSchema
 	tables       map[string]*ObjectIndexer
-	definedEnums map[string]schema.EnumDefinition
+	definedEnums map[string]schema.EnumType
 	options      Options
 }
 
@@ -22,36 +23,137 @@ func NewModuleIndexer(moduleName string, modSchema schema.ModuleSchema, options
 		moduleName:   moduleName,
 		schema:       modSchema,
 		tables:       map[string]*ObjectIndexer{},
-		definedEnums: map[string]schema.EnumDefinition{},
+		definedEnums: map[string]schema.EnumType{},
 		options:      options,
 	}
 }
 
 // InitializeSchema creates tables for all object types in the module schema and creates enum types.
 func (m *ModuleIndexer) InitializeSchema(ctx context.Context, conn DBConn) error {
+	var err error
+
+	if m.options.Dialect == DialectSQLite {
+		// enum fields have CHECK constraints instead of types and schema migrations aren't supported
+		m.schema.ObjectTypes(func(typ schema.ObjectType) bool {
+			tm := NewObjectIndexer(m.moduleName, typ, m.options)
+			m.tables[typ.Name] = tm
+			err = tm.CreateTable(ctx, conn)
+			return err == nil
+		})
+		return err
+	}
+
 	// create enum types
-	for _, typ := range m.schema.ObjectTypes {
-		err := m.createEnumTypesForFields(ctx, conn, typ.KeyFields)
+	m.schema.ObjectTypes(func(typ schema.ObjectType) bool {
+		err = m.createEnumTypesForFields(ctx, conn, typ.KeyFields)
 		if err != nil {
-			return err
+			return false
 		}
 
 		err = m.createEnumTypesForFields(ctx, conn, typ.ValueFields)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// create tables for new object types and migrate the tables of existing ones
+	m.schema.ObjectTypes(func(typ schema.ObjectType) bool {
+		tm := NewObjectIndexer(m.moduleName, typ, m.options)
+		m.tables[typ.Name] = tm
+		err = m.initializeTable(ctx, conn, tm)
+		if err == nil && tm.HasHistory() {
+			err = m.initializeHistoryTable(ctx, conn, tm)
+		}
+		return err == nil
+	})
+
+	return err
+}
+
+// initializeTable creates the table of an object type if it doesn't exist yet and otherwise migrates it to the
+// current object type. Applied changes are recorded in the migrations table.
+func (m *ModuleIndexer) initializeTable(ctx context.Context, conn DBConn, tm *ObjectIndexer) error {
+	existing, err := tm.ExistingColumns(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("failed to inspect table for %s in module %s: %v", tm.typ.Name, m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+
+	if len(existing) == 0 {
+		err = tm.CreateTable(ctx, conn)
 		if err != nil {
+			return fmt.Errorf("failed to create table for %s in module %s: %v", tm.typ.Name, m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+		}
+
+		buf := new(strings.Builder)
+		if err := tm.CreateTableSql(buf); err != nil {
 			return err
 		}
+		return m.recordMigration(ctx, conn, tm.TableName(), buf.String())
 	}
 
-	// create tables for all object types
-	for _, typ := range m.schema.ObjectTypes {
-		tm := NewObjectIndexer(m.moduleName, typ, m.options)
-		m.tables[typ.Name] = tm
-		err := tm.CreateTable(ctx, conn)
+	sqlStr, err := tm.MigrateTable(ctx, conn, existing)
+	if err != nil {
+		return fmt.Errorf("failed to migrate table for %s in module %s: %v", tm.typ.Name, m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+	if sqlStr == "" {
+		return nil
+	}
+	return m.recordMigration(ctx, conn, tm.TableName(), sqlStr)
+}
+
+// initializeHistoryTable creates or migrates the history table of an object type like initializeTable.
+func (m *ModuleIndexer) initializeHistoryTable(ctx context.Context, conn DBConn, tm *ObjectIndexer) error {
+	existing, err := existingColumns(ctx, conn, tm.HistoryTableName())
+	if err != nil {
+		return fmt.Errorf("failed to inspect history table for %s in module %s: %v", tm.typ.Name, m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+
+	if len(existing) == 0 {
+		err = tm.CreateHistoryTable(ctx, conn)
 		if err != nil {
-			return fmt.Errorf("failed to create table for %s in module %s: %v", typ.Name, m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+			return fmt.Errorf("failed to create history table for %s in module %s: %v", tm.typ.Name, m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+		}
+
+		buf := new(strings.Builder)
+		if err := tm.CreateHistoryTableSql(buf); err != nil {
+			return err
+		}
+		return m.recordMigration(ctx, conn, tm.HistoryTableName(), buf.String())
+	}
+
+	sqlStr, err := tm.MigrateHistoryTable(ctx, conn, existing)
+	if err != nil {
+		return fmt.Errorf("failed to migrate history table for %s in module %s: %v", tm.typ.Name, m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+	if sqlStr == "" {
+		return nil
+	}
+	return m.recordMigration(ctx, conn, tm.HistoryTableName(), sqlStr)
+}
+
+// UpdateObjects applies object updates written at height to the tables of the module.
+func (m *ModuleIndexer) UpdateObjects(ctx context.Context, conn DBConn, updates []schema.ObjectUpdate, height uint64) error {
+	for _, update := range updates {
+		tm, ok := m.tables[update.TypeName]
+		if !ok {
+			return fmt.Errorf("unknown object type %q in module %s", update.TypeName, m.moduleName)
+		}
+
+		if err := tm.Update(ctx, conn, update, height); err != nil {
+			return fmt.Errorf("failed to update %s in module %s: %v", update.TypeName, m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
 		}
 	}
+	return nil
+}
 
+// PruneHistory prunes the history tables of the module according to the retention policies of its object types.
+func (m *ModuleIndexer) PruneHistory(ctx context.Context, conn DBConn, height uint64) error {
+	for _, tm := range m.tables {
+		if err := tm.PruneHistory(ctx, conn, height); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
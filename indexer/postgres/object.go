@@ -13,6 +13,10 @@ type ObjectIndexer struct {
 	valueFields map[string]schema.Field
 	allFields   map[string]schema.Field
 	options     Options
+
+	// bulkRows are the rows buffered by BulkUpdate and bulkSeq is the sequence number of the last one
+	bulkRows [][]interface{}
+	bulkSeq  int64
 }
 
 // NewObjectIndexer creates a new ObjectIndexer for the given object type.
@@ -1,10 +1,33 @@
 package postgres
 
+import "cosmossdk.io/schema"
+
 // Options are the options for module and object indexers.
 type Options struct {
+	// Dialect is the SQL dialect to generate SQL for. It defaults to DialectPostgres.
+	Dialect Dialect
+
 	// DisableRetainDeletions disables retain deletions functionality even on object types that have it set.
 	DisableRetainDeletions bool
 
+	// HistoryTables enables history tables which record every version of the objects of an object type
+	// alongside its current-state table, unless the object type's retention policy only keeps the latest version.
+	HistoryTables bool
+
+	// AddressCodec is used to convert addresses to strings. It defaults to schema.HexAddressCodec.
+	AddressCodec schema.AddressCodec
+
+	// PartitionSize enables partitioning the block, transaction, event and history tables by ranges of this
+	// number of block heights if it is not zero.
+	PartitionSize uint64
+
+	// BulkBatchSize is the number of object updates which are buffered for each object type in bulk mode before
+	// they are written. It defaults to DefaultBulkBatchSize.
+	BulkBatchSize int
+
+	// CopyFrom is used to write rows with the COPY protocol in bulk mode. It defaults to CopyIn.
+	CopyFrom CopyFromFunc
+
 	// Logger is the logger for the indexer to use.
 	Logger SqlLogger
 }
@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cosmossdk.io/schema"
+)
+
+// bindKeyParams returns the parameters for the key columns of an object update.
+func (tm *ObjectIndexer) bindKeyParams(key interface{}) ([]interface{}, error) {
+	return tm.bindParams(tm.typ.KeyFields, key)
+}
+
+// bindValueParams returns the parameters for the value columns of an object update whose value is not a
+// ValueUpdates.
+func (tm *ObjectIndexer) bindValueParams(value interface{}) ([]interface{}, error) {
+	return tm.bindParams(tm.typ.ValueFields, value)
+}
+
+// bindParams returns the parameters for the columns of fields from a single value or slice of values as
+// described by schema.ObjectUpdate.
+func (tm *ObjectIndexer) bindParams(fields []schema.Field, value interface{}) ([]interface{}, error) {
+	switch len(fields) {
+	case 0:
+		return nil, nil
+	case 1:
+		param, err := tm.bindParam(fields[0], value)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{param}, nil
+	}
+
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected slice of values for %d fields, got %T", len(fields), value)
+	}
+	if len(values) != len(fields) {
+		return nil, fmt.Errorf("expected %d values, got %d", len(fields), len(values))
+	}
+
+	params := make([]interface{}, len(fields))
+	for i, field := range fields {
+		param, err := tm.bindParam(field, values[i])
+		if err != nil {
+			return nil, err
+		}
+		params[i] = param
+	}
+	return params, nil
+}
+
+// bindParam converts the value of a field to the parameter for its updatable column.
+func (tm *ObjectIndexer) bindParam(field schema.Field, value interface{}) (interface{}, error) {
+	if value == nil {
+		if !field.Nullable {
+			return nil, fmt.Errorf("field %q is not nullable", field.Name)
+		}
+		return nil, nil
+	}
+
+	switch field.Kind {
+	case schema.Uint64Kind:
+		v, ok := value.(uint64)
+		if !ok {
+			return nil, fmt.Errorf("expected uint64 for field %q, got %T", field.Name, value)
+		}
+		return strconv.FormatUint(v, 10), nil
+	case schema.TimeKind:
+		t, ok := value.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("expected time.Time for field %q, got %T", field.Name, value)
+		}
+		if field.TimeResolution == schema.TimeResolutionNanos {
+			return t.UnixNano(), nil
+		}
+		return t, nil
+	case schema.DurationKind:
+		d, ok := value.(time.Duration)
+		if !ok {
+			return nil, fmt.Errorf("expected time.Duration for field %q, got %T", field.Name, value)
+		}
+		return int64(d), nil
+	case schema.AddressKind:
+		bz, ok := value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("expected []byte for field %q, got %T", field.Name, value)
+		}
+		return tm.addressCodec().BytesToString(bz)
+	case schema.JSONKind:
+		raw, ok := value.(json.RawMessage)
+		if !ok {
+			return nil, fmt.Errorf("expected json.RawMessage for field %q, got %T", field.Name, value)
+		}
+		return string(raw), nil
+	case schema.StructKind, schema.ListKind, schema.MapKind, schema.OneOfKind:
+		v, err := tm.jsonValue(field, value)
+		if err != nil {
+			return nil, err
+		}
+		bz, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return string(bz), nil
+	default:
+		return value, nil
+	}
+}
+
+// jsonValue converts the value of a struct, list, map or oneof field to a value which can be marshaled as JSON:
+// structs are converted to objects with their field names as keys, map keys are formatted as strings and oneofs
+// are converted to objects with the name of their case as the only key.
+func (tm *ObjectIndexer) jsonValue(field schema.Field, value interface{}) (interface{}, error) {
+	switch field.Kind {
+	case schema.StructKind:
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected []interface{} for field %q, got %T", field.Name, value)
+		}
+		if len(values) != len(field.StructType.Fields) {
+			return nil, fmt.Errorf("expected %d values for field %q, got %d", len(field.StructType.Fields), field.Name, len(values))
+		}
+		obj := make(map[string]interface{}, len(values))
+		for i, structField := range field.StructType.Fields {
+			v, err := tm.jsonValue(structField, values[i])
+			if err != nil {
+				return nil, err
+			}
+			obj[structField.Name] = v
+		}
+		return obj, nil
+	case schema.OneOfKind:
+		oneOf, ok := value.(schema.OneOfValue)
+		if !ok {
+			return nil, fmt.Errorf("expected schema.OneOfValue for field %q, got %T", field.Name, value)
+		}
+		c, ok := field.OneOfType.LookupCase(oneOf.Case)
+		if !ok {
+			return nil, fmt.Errorf("unknown case %q for field %q", oneOf.Case, field.Name)
+		}
+		v, err := tm.jsonValue(c, oneOf.Value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{oneOf.Case: v}, nil
+	case schema.MapKind:
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected map[interface{}]interface{} for field %q, got %T", field.Name, value)
+		}
+		obj := make(map[string]interface{}, len(m))
+		valueField := elementField(field, field.ValueKind)
+		for k, v := range m {
+			jv, err := tm.jsonValue(valueField, v)
+			if err != nil {
+				return nil, err
+			}
+			obj[fmt.Sprint(k)] = jv
+		}
+		return obj, nil
+	case schema.ListKind:
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected []interface{} for field %q, got %T", field.Name, value)
+		}
+		list := make([]interface{}, len(values))
+		elemField := elementField(field, field.ElementKind)
+		for i, v := range values {
+			jv, err := tm.jsonValue(elemField, v)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = jv
+		}
+		return list, nil
+	case schema.AddressKind:
+		if bz, ok := value.([]byte); ok {
+			return tm.addressCodec().BytesToString(bz)
+		}
+		return value, nil
+	case schema.JSONKind:
+		if raw, ok := value.(json.RawMessage); ok {
+			return raw, nil
+		}
+		return value, nil
+	default:
+		return value, nil
+	}
+}
+
+// elementField returns a field which describes the elements of a list field or the values of a map field.
+func elementField(field schema.Field, kind schema.Kind) schema.Field {
+	return schema.Field{
+		Name:           field.Name,
+		Kind:           kind,
+		EnumType:       field.EnumType,
+		StructType:     field.StructType,
+		OneOfType:      field.OneOfType,
+		TimeResolution: field.TimeResolution,
+	}
+}
+
+func (tm *ObjectIndexer) addressCodec() schema.AddressCodec {
+	if tm.options.AddressCodec != nil {
+		return tm.options.AddressCodec
+	}
+	return schema.HexAddressCodec{}
+}
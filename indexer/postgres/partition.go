@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"cosmossdk.io/schema"
+)
+
+// PartitionName returns the name of the partition of a table partitioned by block height ranges which starts
+// at the block height start.
+func PartitionName(tableName string, start uint64) string {
+	return fmt.Sprintf("%s_p%d", tableName, start)
+}
+
+// CreatePartitionSql generates the statement which creates the partition of a table partitioned by ranges of
+// size block heights which contains height.
+func CreatePartitionSql(writer io.Writer, tableName string, height, size uint64) error {
+	start := height - height%size
+	_, err := fmt.Fprintf(writer, "CREATE TABLE IF NOT EXISTS %q PARTITION OF %q FOR VALUES FROM (%d) TO (%d);",
+		PartitionName(tableName, start), tableName, start, start+size)
+	return err
+}
+
+// partitions creates the partitions of tables partitioned by block height ranges as blocks are indexed.
+type partitions struct {
+	options Options
+	created map[string]bool
+}
+
+func newPartitions(options Options) *partitions {
+	return &partitions{options: options, created: map[string]bool{}}
+}
+
+// ensure creates the partitions of the tables which contain height if they don't exist yet.
+func (p *partitions) ensure(ctx context.Context, conn DBConn, tableNames []string, height uint64) error {
+	if p.options.PartitionSize == 0 {
+		return nil
+	}
+
+	for _, tableName := range tableNames {
+		name := PartitionName(tableName, height-height%p.options.PartitionSize)
+		if p.created[name] {
+			continue
+		}
+
+		buf := new(strings.Builder)
+		if err := CreatePartitionSql(buf, tableName, height, p.options.PartitionSize); err != nil {
+			return err
+		}
+		sqlStr := buf.String()
+		if p.options.Logger != nil {
+			p.options.Logger(fmt.Sprintf("Creating partition %s", name), sqlStr)
+		}
+		if _, err := conn.ExecContext(ctx, sqlStr); err != nil {
+			return fmt.Errorf("failed to create partition %s, tables which were created without partitioning must be dropped to enable it: %v", name, err) //nolint:errorlint // using %v for go 1.12 compat
+		}
+		p.created[name] = true
+	}
+	return nil
+}
+
+// dropPartitions drops the partitions of a table partitioned by block height ranges which only contain block
+// heights less than cutoff, unless keep returns true for them.
+func dropPartitions(ctx context.Context, conn DBConn, options Options, tableName string, cutoff uint64, keep func(partitionName string) (bool, error)) error {
+	names, starts, err := existingPartitions(ctx, conn, tableName)
+	if err != nil {
+		return err
+	}
+
+	for i, name := range names {
+		if starts[i]+options.PartitionSize > cutoff {
+			continue
+		}
+
+		if keep != nil {
+			kept, err := keep(name)
+			if err != nil {
+				return err
+			}
+			if kept {
+				continue
+			}
+		}
+
+		sqlStr := fmt.Sprintf("DROP TABLE %q;", name)
+		if options.Logger != nil {
+			options.Logger(fmt.Sprintf("Dropping partition %s", name), sqlStr)
+		}
+		if _, err := conn.ExecContext(ctx, sqlStr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// existingPartitions returns the names and start heights of the partitions of a table.
+func existingPartitions(ctx context.Context, conn DBConn, tableName string) ([]string, []uint64, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT c.relname
+FROM pg_inherits i
+JOIN pg_class c ON c.oid = i.inhrelid
+JOIN pg_class p ON p.oid = i.inhparent
+WHERE p.relname = $1`, tableName)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	var starts []uint64
+	prefix := fmt.Sprintf("%s_p", tableName)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, nil, err
+		}
+
+		// partitions which weren't created by the indexer are ignored
+		start, err := strconv.ParseUint(strings.TrimPrefix(name, prefix), 10, 64)
+		if !strings.HasPrefix(name, prefix) || err != nil {
+			continue
+		}
+		names = append(names, name)
+		starts = append(starts, start)
+	}
+	return names, starts, rows.Err()
+}
+
+// DropBlockPartitions drops the partitions of the block, transaction and event tables which only contain blocks
+// older than the last retainBlocks blocks before height.
+func DropBlockPartitions(ctx context.Context, conn DBConn, options Options, height, retainBlocks uint64) error {
+	if options.PartitionSize == 0 || retainBlocks == 0 || height <= retainBlocks {
+		return nil
+	}
+
+	for _, tableName := range BlockTableNames {
+		if err := dropPartitions(ctx, conn, options, tableName, height-retainBlocks, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PartitionedTables returns the names of the tables of the module which are partitioned by block height ranges.
+func (m *ModuleIndexer) PartitionedTables() []string {
+	if m.options.PartitionSize == 0 {
+		return nil
+	}
+
+	var tableNames []string
+	m.schema.ObjectTypes(func(typ schema.ObjectType) bool {
+		if tm, ok := m.tables[typ.Name]; ok && tm.HasHistory() {
+			tableNames = append(tableNames, tm.HistoryTableName())
+		}
+		return true
+	})
+	return tableNames
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"fmt"
+	"os"
+
+	"cosmossdk.io/indexer/postgres/internal/testdata"
+	"cosmossdk.io/schema"
+)
+
+func ExampleCreateBlockTablesSql_partitioned() {
+	err := CreateBlockTablesSql(os.Stdout, Options{PartitionSize: 100000})
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+	// CREATE TABLE IF NOT EXISTS "block" (
+	// 	"number" BIGINT NOT NULL,
+	// 	"header" JSONB NULL,
+	// 	PRIMARY KEY ("number")
+	// ) PARTITION BY RANGE ("number");
+	// GRANT SELECT ON TABLE "block" TO PUBLIC;
+	// CREATE TABLE IF NOT EXISTS "tx" (
+	// 	"block_number" BIGINT NOT NULL,
+	// 	"index_in_block" INTEGER NOT NULL,
+	// 	"data" JSONB NULL,
+	// 	PRIMARY KEY ("block_number", "index_in_block")
+	// ) PARTITION BY RANGE ("block_number");
+	// GRANT SELECT ON TABLE "tx" TO PUBLIC;
+	// CREATE TABLE IF NOT EXISTS "event" (
+	// 	"block_number" BIGINT NOT NULL,
+	// 	"tx_index" INTEGER NOT NULL,
+	// 	"msg_index" BIGINT NOT NULL,
+	// 	"event_index" BIGINT NOT NULL,
+	// 	"type" TEXT NOT NULL,
+	// 	"module_name" TEXT NULL,
+	// 	"data" JSONB NULL,
+	// 	PRIMARY KEY ("block_number", "tx_index", "msg_index", "event_index")
+	// ) PARTITION BY RANGE ("block_number");
+	// GRANT SELECT ON TABLE "event" TO PUBLIC;
+	// CREATE INDEX IF NOT EXISTS "event_type" ON "event" ("type");
+}
+
+func ExampleCreatePartitionSql() {
+	err := CreatePartitionSql(os.Stdout, TxTableName, 123456, 100000)
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+	// CREATE TABLE IF NOT EXISTS "tx_p100000" PARTITION OF "tx" FOR VALUES FROM (100000) TO (200000);
+}
+
+func ExampleObjectIndexer_CreateHistoryTableSql_partitioned() {
+	tm := NewObjectIndexer("test", testdata.SingletonObject, Options{HistoryTables: true, PartitionSize: 1000})
+	err := tm.CreateHistoryTableSql(os.Stdout)
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+	// CREATE TABLE IF NOT EXISTS "test_singleton_history" (
+	// 	_id INTEGER NOT NULL CHECK (_id = 1),
+	// 	"foo" TEXT NOT NULL,
+	// 	"bar" INTEGER NULL,
+	// 	"an_enum" "test_my_enum" NOT NULL,
+	// 	_valid_from BIGINT NOT NULL,
+	// 	_valid_to BIGINT NULL,
+	// 	PRIMARY KEY (_id, _valid_from)
+	// ) PARTITION BY RANGE (_valid_from);
+	// GRANT SELECT ON TABLE "test_singleton_history" TO PUBLIC;
+	// CREATE INDEX IF NOT EXISTS "test_singleton_history_valid_to" ON "test_singleton_history" (_valid_to);
+}
+
+func ExampleModuleIndexer_PartitionedTables() {
+	mm := NewModuleIndexer("test", testdata.ExampleSchema, Options{HistoryTables: true, PartitionSize: 1000})
+	testdata.ExampleSchema.ObjectTypes(func(typ schema.ObjectType) bool {
+		mm.tables[typ.Name] = NewObjectIndexer("test", typ, mm.options)
+		return true
+	})
+	fmt.Println(mm.PartitionedTables())
+	// Output:
+	// [test_all_kinds_history test_singleton_history test_validator_history test_vote_history]
+}
@@ -28,6 +28,9 @@ require (
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
-replace cosmossdk.io/indexer/postgres => ../.
+replace (
+	cosmossdk.io/indexer/postgres => ../.
+	cosmossdk.io/schema => ../../../schema
+)
 
 go 1.22
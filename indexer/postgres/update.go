@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"cosmossdk.io/schema"
+)
+
+// Update applies an object update to the table of the object type. If the object type has a history table,
+// the version of the object at height is recorded in it.
+func (tm *ObjectIndexer) Update(ctx context.Context, conn DBConn, update schema.ObjectUpdate, height uint64) error {
+	keyParams, err := tm.bindKeyParams(update.Key)
+	if err != nil {
+		return fmt.Errorf("invalid key of %s update: %v", tm.typ.Name, err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+
+	buf := new(strings.Builder)
+	var params []interface{}
+	valueUpdates, isValueUpdates := update.Value.(schema.ValueUpdates)
+	switch {
+	case update.Delete:
+		params, err = tm.deleteSql(buf, keyParams)
+	case isValueUpdates:
+		params, err = tm.updateSql(buf, keyParams, valueUpdates)
+	default:
+		params, err = tm.upsertSql(buf, keyParams, update.Value)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s update: %v", tm.typ.Name, err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+
+	if sqlStr := buf.String(); sqlStr != "" {
+		if tm.options.Logger != nil {
+			tm.options.Logger(fmt.Sprintf("Updating %s", tm.TableName()), sqlStr, params...)
+		}
+		if _, err := conn.ExecContext(ctx, sqlStr, params...); err != nil {
+			return err
+		}
+	}
+
+	if tm.HasHistory() {
+		return tm.recordHistory(ctx, conn, keyParams, update.Delete, height)
+	}
+	return nil
+}
+
+// upsertSql generates an INSERT ... ON CONFLICT statement which sets all columns of the object.
+func (tm *ObjectIndexer) upsertSql(writer io.Writer, keyParams []interface{}, value interface{}) ([]interface{}, error) {
+	valueParams, err := tm.bindValueParams(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var cols, values []string
+	params := append([]interface{}{}, keyParams...)
+	if len(tm.typ.KeyFields) == 0 {
+		cols = append(cols, "_id")
+		values = append(values, "1")
+	}
+	for i, field := range tm.typ.KeyFields {
+		col, err := tm.updatableColumnName(field)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+		values = append(values, fmt.Sprintf("$%d", i+1))
+	}
+	for i, field := range tm.typ.ValueFields {
+		col, err := tm.updatableColumnName(field)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, valueParams[i])
+		cols = append(cols, col)
+		values = append(values, fmt.Sprintf("$%d", len(params)))
+	}
+
+	conflictSql, err := tm.onConflictSql()
+	if err != nil {
+		return nil, err
+	}
+	_, err = fmt.Fprintf(writer, "INSERT INTO %q (%s) VALUES (%s) ON CONFLICT (%s) %s;",
+		tm.TableName(), strings.Join(cols, ", "), strings.Join(values, ", "), strings.Join(tm.primaryKeyColumns(), ", "), conflictSql)
+	return params, err
+}
+
+// onConflictSql returns the ON CONFLICT action of upserts which sets all value columns of the existing row.
+func (tm *ObjectIndexer) onConflictSql() (string, error) {
+	var setCols []string
+	for _, field := range tm.typ.ValueFields {
+		col, err := tm.updatableColumnName(field)
+		if err != nil {
+			return "", err
+		}
+		setCols = append(setCols, fmt.Sprintf("%s = EXCLUDED.%s", col, col))
+	}
+	if tm.retainDeletions() {
+		setCols = append(setCols, "_deleted = FALSE")
+	}
+
+	if len(setCols) == 0 {
+		return "DO NOTHING", nil
+	}
+	return fmt.Sprintf("DO UPDATE SET %s", strings.Join(setCols, ", ")), nil
+}
+
+// updateSql generates an UPDATE statement which sets the columns of the updated value fields.
+func (tm *ObjectIndexer) updateSql(writer io.Writer, keyParams []interface{}, valueUpdates schema.ValueUpdates) ([]interface{}, error) {
+	var setCols []string
+	var params []interface{}
+	var bindErr error
+	err := valueUpdates.Iterate(func(name string, value interface{}) bool {
+		field, ok := tm.valueFields[name]
+		if !ok {
+			bindErr = fmt.Errorf("unknown value field %q", name)
+			return false
+		}
+
+		var param interface{}
+		param, bindErr = tm.bindParam(field, value)
+		if bindErr != nil {
+			return false
+		}
+
+		col, _ := tm.updatableColumnName(field)
+		params = append(params, param)
+		setCols = append(setCols, fmt.Sprintf("%s = $%d", col, len(params)))
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if bindErr != nil {
+		return nil, bindErr
+	}
+	if len(setCols) == 0 {
+		return nil, nil
+	}
+
+	where, params := tm.whereKeySql(params, keyParams)
+	_, err = fmt.Fprintf(writer, "UPDATE %q SET %s WHERE %s;", tm.TableName(), strings.Join(setCols, ", "), where)
+	return params, err
+}
+
+// deleteSql generates a DELETE statement, or an UPDATE statement which marks the object as deleted if
+// deletions are retained.
+func (tm *ObjectIndexer) deleteSql(writer io.Writer, keyParams []interface{}) ([]interface{}, error) {
+	where, params := tm.whereKeySql(nil, keyParams)
+	var err error
+	if tm.retainDeletions() {
+		_, err = fmt.Fprintf(writer, "UPDATE %q SET _deleted = TRUE WHERE %s;", tm.TableName(), where)
+	} else {
+		_, err = fmt.Fprintf(writer, "DELETE FROM %q WHERE %s;", tm.TableName(), where)
+	}
+	return params, err
+}
+
+// whereKeySql returns a condition which matches the primary key columns to the key params, which are
+// appended to params.
+func (tm *ObjectIndexer) whereKeySql(params, keyParams []interface{}) (string, []interface{}) {
+	if len(tm.typ.KeyFields) == 0 {
+		return "_id = 1", params
+	}
+
+	conds := make([]string, len(tm.typ.KeyFields))
+	for i, field := range tm.typ.KeyFields {
+		col, _ := tm.updatableColumnName(field)
+		params = append(params, keyParams[i])
+		conds[i] = fmt.Sprintf("%s = $%d", col, len(params))
+	}
+	return strings.Join(conds, " AND "), params
+}
+
+// primaryKeyColumns returns the primary key columns of the table of the object type.
+func (tm *ObjectIndexer) primaryKeyColumns() []string {
+	if len(tm.typ.KeyFields) == 0 {
+		return []string{"_id"}
+	}
+
+	cols := make([]string, len(tm.typ.KeyFields))
+	for i, field := range tm.typ.KeyFields {
+		cols[i], _ = tm.updatableColumnName(field)
+	}
+	return cols
+}
@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cosmossdk.io/schema"
+)
+
+// readValue converts the value scanned from the updatable column of a field back to the value of the field,
+// reversing bindParam. It accepts the types which database/sql drivers return for the column types of all
+// dialects.
+func (tm *ObjectIndexer) readValue(field schema.Field, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch field.Kind {
+	case schema.StringKind, schema.IntegerStringKind, schema.DecimalStringKind, schema.EnumKind:
+		return readString(value)
+	case schema.BytesKind:
+		switch v := value.(type) {
+		case []byte:
+			return v, nil
+		case string:
+			return []byte(v), nil
+		}
+	case schema.Int8Kind, schema.Int16Kind, schema.Int32Kind, schema.Int64Kind,
+		schema.Uint8Kind, schema.Uint16Kind, schema.Uint32Kind, schema.Uint64Kind, schema.DurationKind:
+		return readInteger(field.Kind, value)
+	case schema.Float32Kind, schema.Float64Kind:
+		f, ok := value.(float64)
+		if !ok {
+			s, err := readString(value)
+			if err != nil {
+				break
+			}
+			if f, err = strconv.ParseFloat(s, 64); err != nil {
+				return nil, err
+			}
+		}
+		if field.Kind == schema.Float32Kind {
+			return float32(f), nil
+		}
+		return f, nil
+	case schema.BoolKind:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case int64:
+			return v != 0, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+	case schema.TimeKind:
+		switch v := value.(type) {
+		case int64:
+			return time.Unix(0, v), nil
+		case time.Time:
+			return v, nil
+		case string:
+			return time.Parse(time.RFC3339Nano, v)
+		}
+	case schema.AddressKind:
+		s, err := readString(value)
+		if err != nil {
+			break
+		}
+		return tm.addressCodec().StringToBytes(s)
+	case schema.JSONKind:
+		s, err := readString(value)
+		if err != nil {
+			break
+		}
+		return json.RawMessage(s), nil
+	case schema.StructKind, schema.ListKind, schema.MapKind, schema.OneOfKind:
+		s, err := readString(value)
+		if err != nil {
+			break
+		}
+		dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+		dec.UseNumber()
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		return tm.fromJSONValue(field, v)
+	}
+
+	return nil, fmt.Errorf("unexpected value %v of type %T for field %q of kind %s", value, value, field.Name, field.Kind)
+}
+
+// fromJSONValue converts a value decoded from JSON with json.Decoder.UseNumber back to the value of a field,
+// reversing jsonValue.
+func (tm *ObjectIndexer) fromJSONValue(field schema.Field, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch field.Kind {
+	case schema.StructKind:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected JSON object for field %q, got %T", field.Name, value)
+		}
+		values := make([]interface{}, len(field.StructType.Fields))
+		for i, structField := range field.StructType.Fields {
+			v, err := tm.fromJSONValue(structField, obj[structField.Name])
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		return values, nil
+	case schema.OneOfKind:
+		obj, ok := value.(map[string]interface{})
+		if !ok || len(obj) != 1 {
+			return nil, fmt.Errorf("expected JSON object with a single case for field %q, got %v", field.Name, value)
+		}
+		for name, v := range obj {
+			c, ok := field.OneOfType.LookupCase(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown case %q for field %q", name, field.Name)
+			}
+			cv, err := tm.fromJSONValue(c, v)
+			if err != nil {
+				return nil, err
+			}
+			return schema.OneOfValue{Case: name, Value: cv}, nil
+		}
+	case schema.ListKind:
+		list, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected JSON array for field %q, got %T", field.Name, value)
+		}
+		elemField := elementField(field, field.ElementKind)
+		values := make([]interface{}, len(list))
+		for i, elem := range list {
+			v, err := tm.fromJSONValue(elemField, elem)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		return values, nil
+	case schema.MapKind:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected JSON object for field %q, got %T", field.Name, value)
+		}
+		keyField := elementField(field, field.KeyKind)
+		valueField := elementField(field, field.ValueKind)
+		m := make(map[interface{}]interface{}, len(obj))
+		for k, v := range obj {
+			key, err := tm.readValue(keyField, k)
+			if err != nil {
+				return nil, err
+			}
+			mv, err := tm.fromJSONValue(valueField, v)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = mv
+		}
+		return m, nil
+	case schema.BytesKind:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected JSON string for field %q, got %T", field.Name, value)
+		}
+		return base64.StdEncoding.DecodeString(s)
+	case schema.JSONKind:
+		return json.Marshal(value)
+	case schema.Float32Kind, schema.Float64Kind, schema.Int8Kind, schema.Int16Kind, schema.Int32Kind, schema.Int64Kind,
+		schema.Uint8Kind, schema.Uint16Kind, schema.Uint32Kind, schema.Uint64Kind, schema.DurationKind:
+		if n, ok := value.(json.Number); ok {
+			return tm.readValue(field, n.String())
+		}
+	}
+
+	return tm.readValue(field, value)
+}
+
+func readString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	default:
+		return "", fmt.Errorf("expected string, got %T", value)
+	}
+}
+
+// readInteger converts an integer value returned as int64 or as a string, as is the case for NUMERIC and TEXT
+// columns, to the go type of the kind.
+func readInteger(kind schema.Kind, value interface{}) (interface{}, error) {
+	var i int64
+	var u uint64
+	switch v := value.(type) {
+	case int64:
+		i, u = v, uint64(v)
+	case float64:
+		i, u = int64(v), uint64(v)
+	default:
+		s, err := readString(value)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected value of type %T for kind %s", value, kind)
+		}
+		if kind == schema.Uint64Kind {
+			u, err = strconv.ParseUint(s, 10, 64)
+		} else {
+			i, err = strconv.ParseInt(s, 10, 64)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch kind {
+	case schema.Int8Kind:
+		return int8(i), nil
+	case schema.Int16Kind:
+		return int16(i), nil
+	case schema.Int32Kind:
+		return int32(i), nil
+	case schema.Int64Kind:
+		return i, nil
+	case schema.Uint8Kind:
+		return uint8(i), nil
+	case schema.Uint16Kind:
+		return uint16(i), nil
+	case schema.Uint32Kind:
+		return uint32(i), nil
+	case schema.Uint64Kind:
+		return u, nil
+	case schema.DurationKind:
+		return time.Duration(i), nil
+	default:
+		return nil, fmt.Errorf("unexpected integer kind %s", kind)
+	}
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"cosmossdk.io/schema"
+)
+
+func ExampleObjectIndexer_readValue() {
+	structType := schema.StructType{Name: "pair", Fields: []schema.Field{
+		{Name: "addr", Kind: schema.AddressKind},
+		{Name: "amount", Kind: schema.Uint64Kind},
+	}}
+	fields := []schema.Field{
+		{Name: "u64", Kind: schema.Uint64Kind},
+		{Name: "time", Kind: schema.TimeKind},
+		{Name: "duration", Kind: schema.DurationKind},
+		{Name: "addr", Kind: schema.AddressKind},
+		{Name: "struct", Kind: schema.StructKind, StructType: structType},
+		{Name: "list", Kind: schema.ListKind, ElementKind: schema.Int32Kind},
+		{Name: "map", Kind: schema.MapKind, KeyKind: schema.Uint8Kind, ValueKind: schema.StringKind},
+		{Name: "oneof", Kind: schema.OneOfKind, OneOfType: schema.OneOfType{Name: "payload", Cases: []schema.Field{
+			{Name: "text", Kind: schema.StringKind},
+			{Name: "pair", Kind: schema.StructKind, StructType: structType},
+		}}},
+	}
+	values := []interface{}{
+		uint64(18446744073709551615),
+		time.Unix(0, 1234567890123456789).UTC(),
+		time.Second,
+		[]byte{0xde, 0xad},
+		[]interface{}{[]byte{0xbe, 0xef}, uint64(18446744073709551615)},
+		[]interface{}{int32(1), int32(-2)},
+		map[interface{}]interface{}{uint8(7): "seven"},
+		schema.OneOfValue{Case: "pair", Value: []interface{}{[]byte{0xbe, 0xef}, uint64(1)}},
+	}
+
+	tm := NewObjectIndexer("test", schema.ObjectType{Name: "test"}, Options{})
+	for i, field := range fields {
+		param, err := tm.bindParam(field, values[i])
+		if err != nil {
+			panic(err)
+		}
+		value, err := tm.readValue(field, param)
+		if err != nil {
+			panic(err)
+		}
+		if t, ok := value.(time.Time); ok {
+			value = t.UTC()
+		}
+		fmt.Printf("%s: %v -> %#v\n", field.Name, param, value)
+	}
+	// Output:
+	// u64: 18446744073709551615 -> 0xffffffffffffffff
+	// time: 1234567890123456789 -> time.Date(2009, time.February, 13, 23, 31, 30, 123456789, time.UTC)
+	// duration: 1000000000 -> 1000000000
+	// addr: 0xdead -> []byte{0xde, 0xad}
+	// struct: {"addr":"0xbeef","amount":18446744073709551615} -> []interface {}{[]uint8{0xbe, 0xef}, 0xffffffffffffffff}
+	// list: [1,-2] -> []interface {}{1, -2}
+	// map: {"7":"seven"} -> map[interface {}]interface {}{0x7:"seven"}
+	// oneof: {"pair":{"addr":"0xbeef","amount":1}} -> schema.OneOfValue{Case:"pair", Value:[]interface {}{[]uint8{0xbe, 0xef}, 0x1}}
+}
@@ -0,0 +1,407 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/view"
+)
+
+// View implements view.AppData by querying the tables of an indexer.
+type View struct {
+	ctx     context.Context
+	conn    DBConn
+	modules map[string]*ModuleIndexer
+}
+
+var _ view.AppData = (*View)(nil)
+
+// NewView returns a view of the tables of the module indexers which are queried with conn. To only see
+// committed data, conn should not be the connection or transaction which the indexer writes to.
+func NewView(ctx context.Context, conn DBConn, modules map[string]*ModuleIndexer) *View {
+	return &View{ctx: ctx, conn: conn, modules: modules}
+}
+
+// BlockNum implements view.AppData.
+func (v *View) BlockNum() (uint64, error) {
+	var num *int64
+	err := v.conn.QueryRowContext(v.ctx, fmt.Sprintf(`SELECT MAX("number") FROM %q;`, BlockTableName)).Scan(&num)
+	if err != nil || num == nil {
+		return 0, err
+	}
+	return uint64(*num), nil
+}
+
+// AppState implements view.AppData.
+func (v *View) AppState() view.AppState {
+	return v
+}
+
+// GetModule implements view.AppState.
+func (v *View) GetModule(moduleName string) (view.ModuleState, error) {
+	mm, ok := v.modules[moduleName]
+	if !ok {
+		return nil, nil
+	}
+	return moduleView{View: v, mm: mm}, nil
+}
+
+// Modules implements view.AppState.
+func (v *View) Modules(f func(modState view.ModuleState, err error) bool) {
+	names := make([]string, 0, len(v.modules))
+	for name := range v.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !f(moduleView{View: v, mm: v.modules[name]}, nil) {
+			return
+		}
+	}
+}
+
+// NumModules implements view.AppState.
+func (v *View) NumModules() (int, error) {
+	return len(v.modules), nil
+}
+
+type moduleView struct {
+	*View
+	mm *ModuleIndexer
+}
+
+func (m moduleView) ModuleName() string {
+	return m.mm.moduleName
+}
+
+func (m moduleView) ModuleSchema() schema.ModuleSchema {
+	return m.mm.schema
+}
+
+func (m moduleView) GetObjectCollection(objectType string) (view.ObjectCollection, error) {
+	tm, ok := m.mm.tables[objectType]
+	if !ok {
+		return nil, nil
+	}
+	return objectView{View: m.View, tm: tm}, nil
+}
+
+func (m moduleView) ObjectCollections(f func(value view.ObjectCollection, err error) bool) {
+	m.mm.schema.ObjectTypes(func(typ schema.ObjectType) bool {
+		tm, ok := m.mm.tables[typ.Name]
+		if !ok {
+			return true
+		}
+		return f(objectView{View: m.View, tm: tm}, nil)
+	})
+}
+
+func (m moduleView) NumObjectCollections() (int, error) {
+	return len(m.mm.tables), nil
+}
+
+type objectView struct {
+	*View
+	tm *ObjectIndexer
+}
+
+func (o objectView) ObjectType() schema.ObjectType {
+	return o.tm.typ
+}
+
+func (o objectView) GetObject(key interface{}) (update schema.ObjectUpdate, found bool, err error) {
+	keyParams, err := o.tm.bindKeyParams(key)
+	if err != nil {
+		return schema.ObjectUpdate{}, false, err
+	}
+
+	where, params := o.tm.whereKeySql(nil, keyParams)
+	rows, err := o.conn.QueryContext(o.ctx, fmt.Sprintf("%s WHERE %s;", o.tm.selectSql(), where), params...)
+	if err != nil {
+		return schema.ObjectUpdate{}, false, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return schema.ObjectUpdate{}, false, rows.Err()
+	}
+	update, err = o.tm.scanObject(rows)
+	return update, err == nil, err
+}
+
+func (o objectView) AllState(f func(schema.ObjectUpdate, error) bool) {
+	rows, err := o.conn.QueryContext(o.ctx, fmt.Sprintf("%s ORDER BY %s;", o.tm.selectSql(), strings.Join(o.tm.primaryKeyColumns(), ", ")))
+	if err != nil {
+		f(schema.ObjectUpdate{}, err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		update, err := o.tm.scanObject(rows)
+		if !f(update, err) || err != nil {
+			return
+		}
+	}
+	if err := rows.Err(); err != nil {
+		f(schema.ObjectUpdate{}, err)
+	}
+}
+
+func (o objectView) List(filter view.FieldFilter, order view.OrderBy, page view.Pagination) (view.ListResult, error) {
+	if o.tm.options.Dialect == DialectSQLite {
+		// numbers which exceed the range of INTEGER are stored as TEXT in SQLite and can't be compared in SQL
+		return view.ListObjects(o, filter, order, page)
+	}
+
+	plan, err := view.PlanList(o.tm.typ, filter, order, page)
+	if err != nil {
+		return view.ListResult{}, err
+	}
+
+	buf := new(strings.Builder)
+	params, err := o.tm.listSql(buf, plan)
+	if err != nil {
+		return view.ListResult{}, err
+	}
+
+	rows, err := o.conn.QueryContext(o.ctx, buf.String(), params...)
+	if err != nil {
+		return view.ListResult{}, err
+	}
+	defer rows.Close()
+
+	var res view.ListResult
+	for rows.Next() {
+		update, err := o.tm.scanObject(rows)
+		if err != nil {
+			return view.ListResult{}, err
+		}
+		if len(res.Objects) == plan.Limit {
+			// one more object than the limit is selected to know if there is a next page
+			sortValues, err := plan.SortValues(o.tm.typ, res.Objects[len(res.Objects)-1])
+			if err != nil {
+				return view.ListResult{}, err
+			}
+			res.NextCursor, err = plan.Cursor(sortValues)
+			if err != nil {
+				return view.ListResult{}, err
+			}
+			break
+		}
+		res.Objects = append(res.Objects, update)
+	}
+	return res, rows.Err()
+}
+
+func (o objectView) Len() (int, error) {
+	var n int
+	err := o.conn.QueryRowContext(o.ctx, fmt.Sprintf("SELECT COUNT(*) FROM %q;", o.tm.TableName())).Scan(&n)
+	return n, err
+}
+
+// selectSql returns a SELECT statement without conditions for the key and value columns of the object type's
+// table and the _deleted column if deletions are retained.
+func (tm *ObjectIndexer) selectSql() string {
+	cols, _ := tm.keyAndValueColumns()
+	if tm.retainDeletions() {
+		cols = append(cols, "_deleted")
+	}
+	return fmt.Sprintf("SELECT %s FROM %q", strings.Join(cols, ", "), tm.TableName())
+}
+
+// scanObject scans a row selected by selectSql into an object update.
+func (tm *ObjectIndexer) scanObject(rows interface{ Scan(...interface{}) error }) (schema.ObjectUpdate, error) {
+	numKeys := len(tm.typ.KeyFields)
+	if numKeys == 0 {
+		// the _id column of singletons
+		numKeys = 1
+	}
+	values := make([]interface{}, numKeys+len(tm.typ.ValueFields)+1)
+	dest := make([]interface{}, len(values))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if !tm.retainDeletions() {
+		dest = dest[:len(dest)-1]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return schema.ObjectUpdate{}, err
+	}
+
+	update := schema.ObjectUpdate{TypeName: tm.typ.Name}
+	var err error
+	if len(tm.typ.KeyFields) != 0 {
+		update.Key, err = tm.readFields(tm.typ.KeyFields, values[:numKeys])
+		if err != nil {
+			return schema.ObjectUpdate{}, err
+		}
+	}
+	update.Value, err = tm.readFields(tm.typ.ValueFields, values[numKeys:numKeys+len(tm.typ.ValueFields)])
+	if err != nil {
+		return schema.ObjectUpdate{}, err
+	}
+	if tm.retainDeletions() {
+		deleted, err := tm.readValue(schema.Field{Name: "_deleted", Kind: schema.BoolKind}, values[len(values)-1])
+		if err != nil {
+			return schema.ObjectUpdate{}, err
+		}
+		update.Delete = deleted == true
+	}
+	return update, nil
+}
+
+// readFields converts the scanned values of fields to a single value or a slice of values as described by
+// schema.ObjectUpdate.
+func (tm *ObjectIndexer) readFields(fields []schema.Field, values []interface{}) (interface{}, error) {
+	res := make([]interface{}, len(fields))
+	for i, field := range fields {
+		v, err := tm.readValue(field, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read field %q: %v", field.Name, err) //nolint:errorlint // using %v for go 1.12 compat
+		}
+		res[i] = v
+	}
+
+	switch len(res) {
+	case 0:
+		return nil, nil
+	case 1:
+		return res[0], nil
+	default:
+		return res, nil
+	}
+}
+
+// listSql generates a SELECT statement which selects the objects of a page of a list. One more object than
+// the limit of the plan is selected to know if there is a next page.
+func (tm *ObjectIndexer) listSql(writer io.Writer, plan view.ListPlan) ([]interface{}, error) {
+	var conds []string
+	var params []interface{}
+	bind := func(field schema.Field, value interface{}) (string, error) {
+		param, err := tm.bindParam(field, value)
+		if err != nil {
+			return "", err
+		}
+		params = append(params, param)
+		return fmt.Sprintf("$%d", len(params)), nil
+	}
+
+	for _, cond := range plan.Conditions {
+		col, err := tm.orderedColumn(cond.Field)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case cond.Op == view.FilterEq && cond.Value == nil:
+			conds = append(conds, fmt.Sprintf("%s IS NULL", col))
+		case cond.Op == view.FilterNotEq && cond.Value == nil:
+			conds = append(conds, fmt.Sprintf("%s IS NOT NULL", col))
+		case cond.Op == view.FilterIn:
+			values := cond.Value.([]interface{})
+			if len(values) == 0 {
+				conds = append(conds, "FALSE")
+				continue
+			}
+			placeholders := make([]string, len(values))
+			for i, v := range values {
+				if placeholders[i], err = bind(cond.Field, v); err != nil {
+					return nil, err
+				}
+			}
+			conds = append(conds, fmt.Sprintf("%s IN (%s)", col, strings.Join(placeholders, ", ")))
+		default:
+			placeholder, err := bind(cond.Field, cond.Value)
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, fmt.Sprintf("%s %s %s", col, filterOperators[cond.Op], placeholder))
+		}
+	}
+
+	sortCols := make([]string, len(plan.Sort))
+	orderBy := make([]string, len(plan.Sort))
+	for i, s := range plan.Sort {
+		col, err := tm.orderedColumn(s.Field)
+		if err != nil {
+			return nil, err
+		}
+		sortCols[i] = col
+		orderBy[i] = col
+		if s.Descending {
+			orderBy[i] += " DESC"
+		}
+	}
+
+	// the objects after the cursor are those which are ordered after it by the first sort field in which
+	// they differ
+	if plan.After != nil {
+		alternatives := make([]string, len(plan.Sort))
+		for i, s := range plan.Sort {
+			var parts []string
+			for j := 0; j <= i; j++ {
+				placeholder, err := bind(plan.Sort[j].Field, plan.After[j])
+				if err != nil {
+					return nil, err
+				}
+				op := "="
+				if j == i {
+					op = ">"
+					if s.Descending {
+						op = "<"
+					}
+				}
+				parts = append(parts, fmt.Sprintf("%s %s %s", sortCols[j], op, placeholder))
+			}
+			alternatives[i] = fmt.Sprintf("(%s)", strings.Join(parts, " AND "))
+		}
+		conds = append(conds, fmt.Sprintf("(%s)", strings.Join(alternatives, " OR ")))
+	}
+
+	_, err := fmt.Fprintf(writer, "%s", tm.selectSql())
+	if err != nil {
+		return nil, err
+	}
+	if len(conds) != 0 {
+		_, err = fmt.Fprintf(writer, " WHERE %s", strings.Join(conds, " AND "))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(orderBy) != 0 {
+		_, err = fmt.Fprintf(writer, " ORDER BY %s", strings.Join(orderBy, ", "))
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = fmt.Fprintf(writer, " LIMIT %d;", plan.Limit+1)
+	return params, err
+}
+
+var filterOperators = map[view.FilterOp]string{
+	view.FilterEq:    "=",
+	view.FilterNotEq: "<>",
+	view.FilterLt:    "<",
+	view.FilterLtEq:  "<=",
+	view.FilterGt:    ">",
+	view.FilterGtEq:  ">=",
+}
+
+// orderedColumn returns the updatable column of a field for conditions and ordering. Strings are compared
+// bytewise with the "C" collation like in view.CompareValues.
+func (tm *ObjectIndexer) orderedColumn(field schema.Field) (string, error) {
+	col, err := tm.updatableColumnName(field)
+	if err != nil {
+		return "", err
+	}
+	if field.Kind == schema.StringKind {
+		col = fmt.Sprintf("%s COLLATE \"C\"", col)
+	}
+	return col, nil
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"fmt"
+	"os"
+
+	"cosmossdk.io/indexer/postgres/internal/testdata"
+	"cosmossdk.io/schema/view"
+)
+
+func ExampleObjectIndexer_listSql() {
+	tm := NewObjectIndexer("test", testdata.ValidatorObject, Options{})
+	filter := view.FieldFilter{
+		{Field: "jailed", Op: view.FilterEq, Value: false},
+		{Field: "tokens", Op: view.FilterGtEq, Value: "1000"},
+	}
+	order := view.OrderBy{{Field: "moniker"}, {Field: "tokens", Descending: true}}
+	plan, err := view.PlanList(tm.typ, filter, order, view.Pagination{Limit: 10})
+	if err != nil {
+		panic(err)
+	}
+	cursor, err := plan.Cursor([]interface{}{"val", "5000", []byte{1}})
+	if err != nil {
+		panic(err)
+	}
+	plan, err = view.PlanList(tm.typ, filter, order, view.Pagination{Limit: 10, Cursor: cursor})
+	if err != nil {
+		panic(err)
+	}
+
+	params, err := tm.listSql(os.Stdout, plan)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println()
+	fmt.Println(params)
+	// Output:
+	// SELECT "operator", "moniker", "consensus_pubkey", "tokens", "jailed", "jailed_until" FROM "test_validator" WHERE "jailed" = $1 AND "tokens" >= $2 AND (("moniker" COLLATE "C" > $3) OR ("moniker" COLLATE "C" = $4 AND "tokens" < $5) OR ("moniker" COLLATE "C" = $6 AND "tokens" = $7 AND "operator" > $8)) ORDER BY "moniker" COLLATE "C", "tokens" DESC, "operator" LIMIT 11;
+	// [false 1000 val val 5000 val 5000 0x01]
+}
+
+func ExampleObjectIndexer_listSql_null() {
+	tm := NewObjectIndexer("test", testdata.SingletonObject, Options{})
+	plan, err := view.PlanList(tm.typ, view.FieldFilter{
+		{Field: "bar", Op: view.FilterNotEq},
+		{Field: "an_enum", Op: view.FilterIn, Value: []interface{}{"a", "c"}},
+	}, nil, view.Pagination{})
+	if err != nil {
+		panic(err)
+	}
+
+	params, err := tm.listSql(os.Stdout, plan)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println()
+	fmt.Println(params)
+	// Output:
+	// SELECT _id, "foo", "bar", "an_enum" FROM "test_singleton" WHERE "bar" IS NOT NULL AND "an_enum" IN ($1, $2) LIMIT 101;
+	// [a c]
+}
@@ -0,0 +1,338 @@
+package prometheus
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+)
+
+// sumPrecision is the precision of the sums of the series, which is high enough that adding and subtracting
+// the values of 256-bit integer amounts is exact.
+const sumPrecision = 512
+
+// gauges computes the gauges of the rules from the object updates of committed blocks. Packets are delivered
+// to the listener sequentially and the gauges are safe for concurrent use by the metrics handler, so the state
+// isn't guarded.
+type gauges struct {
+	// rules are the rules by module and object type name
+	rules map[string]map[string][]*ruleState
+}
+
+// ruleState tracks the objects of the object type of a rule and the series of its gauge.
+type ruleState struct {
+	Rule
+	gauge      *promclient.GaugeVec
+	objectType schema.ObjectType
+
+	// fields are the names of the fields whose values are tracked: the labels, the fields of Match and Field
+	fields []string
+
+	// objects are the values of the tracked fields of each object by key
+	objects map[string][]interface{}
+
+	// series are the series of the gauge by their label values
+	series map[string]*series
+}
+
+type series struct {
+	labels []string
+	sum    *big.Float
+	count  int
+}
+
+func newGauges(cfg Config, registerer promclient.Registerer) (*gauges, error) {
+	g := &gauges{rules: map[string]map[string][]*ruleState{}}
+	for _, rule := range cfg.Rules {
+		help := rule.Help
+		if help == "" {
+			if rule.Field == "" {
+				help = fmt.Sprintf("Number of %s objects of module %s.", rule.Type, rule.Module)
+			} else {
+				help = fmt.Sprintf("Sum of the %s field of %s objects of module %s.", rule.Field, rule.Type, rule.Module)
+			}
+		}
+
+		gauge := promclient.NewGaugeVec(promclient.GaugeOpts{Namespace: cfg.Namespace, Name: rule.Name, Help: help}, rule.Labels)
+		if err := registerer.Register(gauge); err != nil {
+			return nil, fmt.Errorf("invalid prometheus indexer rule %s: %w", rule.Name, err)
+		}
+
+		state := &ruleState{
+			Rule:    rule,
+			gauge:   gauge,
+			fields:  append([]string{}, rule.Labels...),
+			objects: map[string][]interface{}{},
+			series:  map[string]*series{},
+		}
+		for name := range rule.Match {
+			state.fields = append(state.fields, name)
+		}
+		if rule.Field != "" {
+			state.fields = append(state.fields, rule.Field)
+		}
+
+		types, ok := g.rules[rule.Module]
+		if !ok {
+			types = map[string][]*ruleState{}
+			g.rules[rule.Module] = types
+		}
+		types[rule.Type] = append(types[rule.Type], state)
+	}
+	return g, nil
+}
+
+// listener returns the listener of the gauges. Object updates are collected with appdata.BatchingListener so
+// that the gauges only change when a block is committed.
+func (g *gauges) listener() appdata.Listener {
+	return appdata.BatchingListener(appdata.Listener{
+		InitializeModuleData: g.initializeModuleData,
+	}, appdata.CommitBatchFunc(g.commitBatch))
+}
+
+// initializeModuleData checks the rules of a module against its schema.
+func (g *gauges) initializeModuleData(data appdata.ModuleInitializationData) error {
+	for typeName, rules := range g.rules[data.ModuleName] {
+		typ, ok := data.Schema.LookupType(typeName)
+		objectType, isObject := typ.(schema.ObjectType)
+		if !ok || !isObject {
+			return fmt.Errorf("prometheus indexer rules refer to object type %s which is not in the schema of module %s", typeName, data.ModuleName)
+		}
+
+		for _, rule := range rules {
+			for _, name := range rule.fields {
+				field, ok := lookupField(objectType, name)
+				if !ok {
+					return fmt.Errorf("prometheus indexer rule %s refers to unknown field %s of object type %s", rule.Name, name, typeName)
+				}
+				if name == rule.Field && !isNumeric(field.Kind) {
+					return fmt.Errorf("prometheus indexer rule %s can't sum field %s of kind %s", rule.Name, name, field.Kind)
+				}
+			}
+			rule.objectType = objectType
+		}
+	}
+	return nil
+}
+
+func (g *gauges) commitBatch(batch appdata.BlockBatch) error {
+	changed := map[*ruleState]map[string]bool{}
+	for _, data := range batch.Updates {
+		types, ok := g.rules[data.ModuleName]
+		if !ok {
+			continue
+		}
+
+		for _, update := range data.Updates {
+			for _, rule := range types[update.TypeName] {
+				if changed[rule] == nil {
+					changed[rule] = map[string]bool{}
+				}
+				if err := rule.apply(update, changed[rule]); err != nil {
+					return fmt.Errorf("prometheus indexer rule %s: %w", rule.Name, err)
+				}
+			}
+		}
+	}
+
+	for rule, seriesKeys := range changed {
+		for key := range seriesKeys {
+			s := rule.series[key]
+			if s.count == 0 {
+				rule.gauge.DeleteLabelValues(s.labels...)
+				delete(rule.series, key)
+				continue
+			}
+			value, _ := s.sum.Float64()
+			rule.gauge.WithLabelValues(s.labels...).Set(value)
+		}
+	}
+	return nil
+}
+
+// apply updates the tracked values of an object and the series to which it contributes, and records the keys
+// of the changed series.
+func (r *ruleState) apply(update schema.ObjectUpdate, changed map[string]bool) error {
+	key := fmt.Sprintf("%#v", update.Key)
+	existing, found := r.objects[key]
+	if found {
+		if err := r.contribute(existing, -1, changed); err != nil {
+			return err
+		}
+	}
+
+	if update.Delete {
+		delete(r.objects, key)
+		return nil
+	}
+
+	values := make([]interface{}, len(r.fields))
+	for i, name := range r.fields {
+		value, err := fieldValue(r.objectType, update, name)
+		if errors.Is(err, schema.ErrFieldNotUpdated) {
+			if !found {
+				return fmt.Errorf("partial update of object %v which doesn't exist", update.Key)
+			}
+			value, err = existing[i], nil
+		}
+		if err != nil {
+			return err
+		}
+		values[i] = value
+	}
+	r.objects[key] = values
+	return r.contribute(values, 1, changed)
+}
+
+// contribute adds the contribution of an object to its series, or subtracts it if sign is -1.
+func (r *ruleState) contribute(values []interface{}, sign int, changed map[string]bool) error {
+	for name, want := range r.Match {
+		if labelValue(values[r.fieldIndex(name)]) != want {
+			return nil
+		}
+	}
+
+	labels := make([]string, len(r.Labels))
+	for i := range r.Labels {
+		labels[i] = labelValue(values[i])
+	}
+	key := strings.Join(labels, "\x00")
+
+	value := big.NewFloat(1)
+	if r.Field != "" {
+		var err error
+		value, err = numericValue(values[len(values)-1])
+		if err != nil {
+			return fmt.Errorf("invalid value of field %s: %w", r.Field, err)
+		}
+	}
+
+	s, ok := r.series[key]
+	if !ok {
+		s = &series{labels: labels, sum: new(big.Float).SetPrec(sumPrecision)}
+		r.series[key] = s
+	}
+	if sign < 0 {
+		s.sum.Sub(s.sum, value)
+	} else {
+		s.sum.Add(s.sum, value)
+	}
+	s.count += sign
+	changed[key] = true
+	return nil
+}
+
+func (r *ruleState) fieldIndex(name string) int {
+	for i := len(r.Labels); i < len(r.fields); i++ {
+		if r.fields[i] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// fieldValue returns the value of a key or value field of an object update.
+func fieldValue(objectType schema.ObjectType, update schema.ObjectUpdate, name string) (interface{}, error) {
+	for _, field := range objectType.KeyFields {
+		if field.Name == name {
+			return update.KeyFieldValue(objectType, name)
+		}
+	}
+	return update.ValueFieldValue(objectType, name)
+}
+
+func lookupField(objectType schema.ObjectType, name string) (schema.Field, bool) {
+	for _, field := range objectType.KeyFields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	for _, field := range objectType.ValueFields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return schema.Field{}, false
+}
+
+func isNumeric(kind schema.Kind) bool {
+	switch kind {
+	case schema.Int8Kind, schema.Uint8Kind, schema.Int16Kind, schema.Uint16Kind, schema.Int32Kind, schema.Uint32Kind,
+		schema.Int64Kind, schema.Uint64Kind, schema.IntegerStringKind, schema.DecimalStringKind, schema.BoolKind,
+		schema.Float32Kind, schema.Float64Kind:
+		return true
+	default:
+		return false
+	}
+}
+
+// numericValue converts the value of a numeric field to a number. Nil values are zero.
+func numericValue(value interface{}) (*big.Float, error) {
+	res := new(big.Float).SetPrec(sumPrecision)
+	switch v := value.(type) {
+	case nil:
+		return res, nil
+	case int8:
+		return res.SetInt64(int64(v)), nil
+	case int16:
+		return res.SetInt64(int64(v)), nil
+	case int32:
+		return res.SetInt64(int64(v)), nil
+	case int64:
+		return res.SetInt64(v), nil
+	case uint8:
+		return res.SetUint64(uint64(v)), nil
+	case uint16:
+		return res.SetUint64(uint64(v)), nil
+	case uint32:
+		return res.SetUint64(uint64(v)), nil
+	case uint64:
+		return res.SetUint64(v), nil
+	case float32:
+		return floatValue(res, float64(v))
+	case float64:
+		return floatValue(res, v)
+	case bool:
+		if v {
+			return res.SetInt64(1), nil
+		}
+		return res, nil
+	case string:
+		if _, ok := res.SetString(v); !ok {
+			return nil, fmt.Errorf("invalid number %q", v)
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("unexpected numeric value of type %T", value)
+	}
+}
+
+func floatValue(res *big.Float, f float64) (*big.Float, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("can't sum %v", f)
+	}
+	return res.SetFloat64(f), nil
+}
+
+// labelValue renders the value of a field as a label value. Bytes and addresses are rendered as hex strings with a
+// 0x prefix and times in RFC 3339 format.
+func labelValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,166 @@
+// Package prometheus implements the "prometheus" indexer type which maps numeric fields of objects, such as the
+// supply of a denom or the tokens of validators, to Prometheus gauges with configurable rules, so that chain KPIs
+// can be scraped directly without a separate exporter.
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/schema/logutil"
+)
+
+// IndexerType is the indexer type of Prometheus indexer targets.
+const IndexerType = "prometheus"
+
+func init() {
+	indexer.Register(IndexerType, Init)
+}
+
+// Config is the indexer specific config of a Prometheus indexer target.
+type Config struct {
+	// ListenAddress is the address on which the metrics are served. It defaults to DefaultListenAddress.
+	ListenAddress string `json:"listen_address"`
+
+	// Path is the HTTP path of the metrics. It defaults to DefaultPath.
+	Path string `json:"path"`
+
+	// Namespace is prepended to the names of the gauges, separated by an underscore, if it is set.
+	Namespace string `json:"namespace"`
+
+	// Rules are the rules which define the gauges.
+	Rules []Rule `json:"rules"`
+}
+
+// Rule maps the objects of an object type to a gauge. The objects are grouped by the values of the label fields,
+// and the gauge has a series for each group with the sum of the values of Field of its objects, or the number of
+// objects if Field is empty.
+type Rule struct {
+	// Name is the name of the gauge.
+	Name string `json:"name"`
+
+	// Help is the help text of the gauge. It defaults to a description of the rule.
+	Help string `json:"help"`
+
+	// Module is the name of the module.
+	Module string `json:"module"`
+
+	// Type is the name of the object type.
+	Type string `json:"type"`
+
+	// Field is the name of the numeric key or value field whose values are summed. Integer, decimal string,
+	// float and bool fields are supported. If it is empty, objects are counted.
+	Field string `json:"field"`
+
+	// Labels are the names of the key or value fields whose values are the labels of the series. Addresses are
+	// rendered as hex strings.
+	Labels []string `json:"labels"`
+
+	// Match restricts the objects to those whose fields have the given values, rendered like labels.
+	Match map[string]string `json:"match"`
+}
+
+const (
+	// DefaultListenAddress is the default value of Config.ListenAddress.
+	DefaultListenAddress = "localhost:9394"
+
+	// DefaultPath is the default value of Config.Path.
+	DefaultPath = "/metrics"
+)
+
+// Init initializes a Prometheus indexer target. It is registered as the indexer type "prometheus".
+func Init(params indexer.InitParams) (indexer.InitResult, error) {
+	cfg, err := decodeConfig(params.Config.Config)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	ctx := params.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = logutil.NoopLogger{}
+	}
+
+	registry := promclient.NewRegistry()
+	g, err := newGauges(cfg, registry)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	ln, err := net.Listen("tcp", cfg.ListenAddress)
+	if err != nil {
+		return indexer.InitResult{}, fmt.Errorf("failed to listen on %s: %w", cfg.ListenAddress, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(cfg.Path, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("prometheus indexer server failed", "error", err)
+		}
+	}()
+	logger.Info("serving indexer gauges", "address", ln.Addr().String(), "path", cfg.Path)
+
+	if params.DoneWaitGroup != nil {
+		params.DoneWaitGroup.Add(1)
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error("failed to shut down prometheus indexer server", "error", err)
+		}
+		if params.DoneWaitGroup != nil {
+			params.DoneWaitGroup.Done()
+		}
+	}()
+
+	// the gauges are computed from the state of the objects, which isn't persisted, so the target starts
+	// from a catch-up sync of the current state
+	return indexer.InitResult{
+		Listener: g.listener(),
+	}, nil
+}
+
+func decodeConfig(rawConfig map[string]interface{}) (Config, error) {
+	bz, err := json.Marshal(rawConfig)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid prometheus indexer config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(bz, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid prometheus indexer config: %w", err)
+	}
+
+	if cfg.ListenAddress == "" {
+		cfg.ListenAddress = DefaultListenAddress
+	}
+	if cfg.Path == "" {
+		cfg.Path = DefaultPath
+	}
+	if len(cfg.Rules) == 0 {
+		return Config{}, fmt.Errorf("prometheus indexer config has no rules")
+	}
+	for i, rule := range cfg.Rules {
+		if rule.Name == "" || rule.Module == "" || rule.Type == "" {
+			return Config{}, fmt.Errorf("invalid prometheus indexer config: rules[%d]: name, module and type are required", i)
+		}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,133 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+)
+
+func testModuleSchema(t *testing.T) schema.ModuleSchema {
+	t.Helper()
+	moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{
+		{
+			Name:        "supply",
+			KeyFields:   []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+			ValueFields: []schema.Field{{Name: "amount", Kind: schema.IntegerStringKind}},
+		},
+		{
+			Name:      "proposals",
+			KeyFields: []schema.Field{{Name: "id", Kind: schema.Uint64Kind}},
+			ValueFields: []schema.Field{
+				{Name: "status", Kind: schema.StringKind},
+				{Name: "deposit", Kind: schema.DecimalStringKind},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return moduleSchema
+}
+
+func testGauges(t *testing.T, cfg map[string]interface{}) (appdata.Listener, *promclient.Registry) {
+	t.Helper()
+	c, err := decodeConfig(cfg)
+	require.NoError(t, err)
+	registry := promclient.NewRegistry()
+	g, err := newGauges(c, registry)
+	require.NoError(t, err)
+	listener := g.listener()
+	require.NoError(t, listener.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "gov", Schema: testModuleSchema(t)}))
+	return listener, registry
+}
+
+func sendBlock(t *testing.T, listener appdata.Listener, height uint64, updates ...schema.ObjectUpdate) {
+	t.Helper()
+	require.NoError(t, listener.StartBlock(appdata.StartBlockData{Height: height}))
+	require.NoError(t, listener.OnObjectUpdate(appdata.ObjectUpdateData{ModuleName: "gov", Updates: updates}))
+	require.NoError(t, listener.Commit(appdata.CommitData{}))
+}
+
+func TestGauges(t *testing.T) {
+	listener, registry := testGauges(t, map[string]interface{}{
+		"namespace": "chain",
+		"rules": []interface{}{
+			map[string]interface{}{"name": "supply", "module": "gov", "type": "supply", "field": "amount", "labels": []interface{}{"denom"}},
+			map[string]interface{}{"name": "proposals", "module": "gov", "type": "proposals", "labels": []interface{}{"status"}},
+			map[string]interface{}{
+				"name": "voting_deposits", "module": "gov", "type": "proposals", "field": "deposit",
+				"match": map[string]interface{}{"status": "voting"}, "help": "Deposits of proposals in voting.",
+			},
+		},
+	})
+
+	sendBlock(t, listener, 1,
+		schema.ObjectUpdate{TypeName: "supply", Key: "uatom", Value: "1000000000000000000000001"},
+		schema.ObjectUpdate{TypeName: "proposals", Key: uint64(1), Value: []interface{}{"voting", "10.5"}},
+		schema.ObjectUpdate{TypeName: "proposals", Key: uint64(2), Value: []interface{}{"voting", "2"}},
+		schema.ObjectUpdate{TypeName: "proposals", Key: uint64(3), Value: []interface{}{"passed", "7"}},
+	)
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP chain_proposals Number of proposals objects of module gov.
+# TYPE chain_proposals gauge
+chain_proposals{status="passed"} 1
+chain_proposals{status="voting"} 2
+# HELP chain_supply Sum of the amount field of supply objects of module gov.
+# TYPE chain_supply gauge
+chain_supply{denom="uatom"} 1e+24
+# HELP chain_voting_deposits Deposits of proposals in voting.
+# TYPE chain_voting_deposits gauge
+chain_voting_deposits 12.5
+`)))
+
+	// partial updates keep the other fields, series without objects are removed and uncommitted updates are
+	// discarded
+	require.NoError(t, listener.StartBlock(appdata.StartBlockData{Height: 2}))
+	require.NoError(t, listener.OnObjectUpdate(appdata.ObjectUpdateData{ModuleName: "gov", Updates: []schema.ObjectUpdate{
+		{TypeName: "proposals", Key: uint64(2), Delete: true},
+	}}))
+	sendBlock(t, listener, 2,
+		schema.ObjectUpdate{TypeName: "supply", Key: "uatom", Value: "2"},
+		schema.ObjectUpdate{TypeName: "proposals", Key: uint64(1), Value: schema.MapValueUpdates{"status": "rejected"}},
+		schema.ObjectUpdate{TypeName: "proposals", Key: uint64(3), Delete: true},
+	)
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP chain_proposals Number of proposals objects of module gov.
+# TYPE chain_proposals gauge
+chain_proposals{status="rejected"} 1
+chain_proposals{status="voting"} 1
+# HELP chain_supply Sum of the amount field of supply objects of module gov.
+# TYPE chain_supply gauge
+chain_supply{denom="uatom"} 2
+# HELP chain_voting_deposits Deposits of proposals in voting.
+# TYPE chain_voting_deposits gauge
+chain_voting_deposits 2
+`)))
+}
+
+func TestGauges_invalidRules(t *testing.T) {
+	c, err := decodeConfig(map[string]interface{}{"rules": []interface{}{
+		map[string]interface{}{"name": "status", "module": "gov", "type": "proposals", "field": "status"},
+	}})
+	require.NoError(t, err)
+	g, err := newGauges(c, promclient.NewRegistry())
+	require.NoError(t, err)
+	err = g.listener().InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "gov", Schema: testModuleSchema(t)})
+	require.ErrorContains(t, err, "can't sum field status")
+
+	c, err = decodeConfig(map[string]interface{}{"rules": []interface{}{
+		map[string]interface{}{"name": "invalid-name", "module": "gov", "type": "proposals"},
+	}})
+	require.NoError(t, err)
+	_, err = newGauges(c, promclient.NewRegistry())
+	require.ErrorContains(t, err, "invalid prometheus indexer rule invalid-name")
+
+	_, err = decodeConfig(map[string]interface{}{})
+	require.ErrorContains(t, err, "no rules")
+	_, err = decodeConfig(map[string]interface{}{"rules": []interface{}{map[string]interface{}{"name": "x"}}})
+	require.ErrorContains(t, err, "name, module and type are required")
+}
@@ -0,0 +1,133 @@
+// Package proof implements a query service which returns ICS23 Merkle proofs of the objects served by indexers,
+// so that indexer consumers can verify indexed data against the app hash instead of trusting the indexer.
+//
+// The service relies on the store paths recorded by an indexer.StorePathIndex, which must be passed to the indexer
+// manager in ManagerOptions.StorePaths, to find the kv-pair from which an object was decoded and then queries the
+// app for that kv-pair with a proof.
+package proof
+
+import (
+	"context"
+	"fmt"
+
+	abci "github.com/cometbft/cometbft/api/cometbft/abci/v1"
+	cmtcrypto "github.com/cometbft/cometbft/api/cometbft/crypto/v1"
+	"github.com/cometbft/cometbft/crypto/merkle"
+
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/store/rootmulti"
+	storetypes "cosmossdk.io/store/types"
+)
+
+// Querier is the ABCI query interface of the app, which is implemented by *baseapp.BaseApp.
+type Querier interface {
+	Query(context.Context, *abci.QueryRequest) (*abci.QueryResponse, error)
+}
+
+// ObjectProof is the proof of the state of an object at a height.
+type ObjectProof struct {
+	// StoreKey is the name of the store which holds the kv-pair of the object.
+	StoreKey string
+
+	// Key is the key of the kv-pair of the object in the store.
+	Key []byte
+
+	// Value is the value of the kv-pair of the object at Height. It is nil if the object did not exist at
+	// Height, in which case the proof is a proof of absence.
+	Value []byte
+
+	// Height is the height of the state which is proven. The proof must be verified against the app hash of
+	// this state, which is included in the header of the block at Height+1.
+	Height int64
+
+	// AppHash is the app hash of the state at Height if the app keeps the commit info of past heights, and
+	// nil otherwise. Clients must check it against the block header before trusting it.
+	AppHash []byte
+
+	// ProofOps are the ICS23 commitment proofs of the kv-pair in its store and of the store in the app state.
+	ProofOps *cmtcrypto.ProofOps
+}
+
+// Verify verifies the proof against the app hash of the state at p.Height.
+func (p ObjectProof) Verify(appHash []byte) error {
+	keyPath := merkle.KeyPath{}.
+		AppendKey([]byte(p.StoreKey), merkle.KeyEncodingURL).
+		AppendKey(p.Key, merkle.KeyEncodingHex).
+		String()
+
+	prt := rootmulti.DefaultProofRuntime()
+	if p.Value == nil {
+		return prt.VerifyAbsence(p.ProofOps, appHash, keyPath)
+	}
+	return prt.VerifyValue(p.ProofOps, appHash, keyPath, p.Value)
+}
+
+// Service proves the state of indexed objects.
+type Service struct {
+	paths   *indexer.StorePathIndex
+	querier Querier
+
+	// commitInfos returns the app hashes of past heights, it is nil if the app doesn't expose them
+	commitInfos commitInfoStore
+}
+
+// commitInfoStore is implemented by multi-stores which keep the commit info of past heights, such as
+// rootmulti.Store.
+type commitInfoStore interface {
+	GetCommitInfo(ver int64) (*storetypes.CommitInfo, error)
+}
+
+// NewService returns a Service which looks up the store paths of objects in paths and queries the proofs from
+// querier. If querier has a CommitMultiStore method, like *baseapp.BaseApp, which returns a multi-store that
+// keeps the commit info of past heights, proofs also include the app hash.
+func NewService(paths *indexer.StorePathIndex, querier Querier) *Service {
+	s := &Service{paths: paths, querier: querier}
+	if app, ok := querier.(interface {
+		CommitMultiStore() storetypes.CommitMultiStore
+	}); ok {
+		s.commitInfos, _ = app.CommitMultiStore().(commitInfoStore)
+	}
+	return s
+}
+
+// ProveObject returns the proof of the state at height of the object of type typeName in module moduleName with
+// the given key, which must have the same Go types as the keys of the object updates delivered to indexers. If
+// height is zero, the latest height is used. Only objects whose store path was recorded can be proven.
+func (s *Service) ProveObject(ctx context.Context, moduleName, typeName string, key interface{}, height int64) (ObjectProof, error) {
+	path, found, err := s.paths.Lookup(moduleName, typeName, key)
+	if err != nil {
+		return ObjectProof{}, err
+	}
+	if !found {
+		return ObjectProof{}, fmt.Errorf("no store path recorded for %s in module %s with key %v", typeName, moduleName, key)
+	}
+
+	res, err := s.querier.Query(ctx, &abci.QueryRequest{
+		Path:   fmt.Sprintf("/store/%s/key", path.StoreKey),
+		Data:   path.Key,
+		Height: height,
+		Prove:  true,
+	})
+	if err != nil {
+		return ObjectProof{}, err
+	}
+	if res.Code != 0 {
+		return ObjectProof{}, fmt.Errorf("failed to query proof of %s in module %s: %s", typeName, moduleName, res.Log)
+	}
+
+	proof := ObjectProof{
+		StoreKey: path.StoreKey,
+		Key:      path.Key,
+		Value:    res.Value,
+		Height:   res.Height,
+		ProofOps: res.ProofOps,
+	}
+	if s.commitInfos != nil {
+		commitInfo, err := s.commitInfos.GetCommitInfo(res.Height)
+		if err != nil {
+			return ObjectProof{}, fmt.Errorf("failed to get the app hash at height %d: %w", res.Height, err)
+		}
+		proof.AppHash = commitInfo.Hash()
+	}
+	return proof, nil
+}
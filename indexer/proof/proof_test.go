@@ -0,0 +1,168 @@
+package proof
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/api/cometbft/abci/v1"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	coretesting "cosmossdk.io/core/testing"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/decoding"
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/schema/view"
+	"cosmossdk.io/store/metrics"
+	"cosmossdk.io/store/rootmulti"
+	storetypes "cosmossdk.io/store/types"
+)
+
+// storeQuerier serves the "/store" queries of the app from a multi-store.
+type storeQuerier struct {
+	store *rootmulti.Store
+}
+
+func (q storeQuerier) Query(_ context.Context, req *abci.QueryRequest) (*abci.QueryResponse, error) {
+	res, err := q.store.Query(&storetypes.RequestQuery{
+		Path:   strings.TrimPrefix(req.Path, "/store"),
+		Data:   req.Data,
+		Height: req.Height,
+		Prove:  req.Prove,
+	})
+	if err != nil {
+		return nil, err
+	}
+	abciRes := abci.QueryResponse(*res)
+	return &abciRes, nil
+}
+
+func (q storeQuerier) CommitMultiStore() storetypes.CommitMultiStore {
+	return q.store
+}
+
+type bankModule struct{}
+
+func (bankModule) ModuleCodec() (schema.ModuleCodec, error) {
+	modSchema, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:        "balances",
+		KeyFields:   []schema.Field{{Name: "address", Kind: schema.StringKind}},
+		ValueFields: []schema.Field{{Name: "amount", Kind: schema.StringKind}},
+	}})
+	if err != nil {
+		return schema.ModuleCodec{}, err
+	}
+	return schema.ModuleCodec{
+		Schema: modSchema,
+		KVDecoder: func(update schema.KVPairUpdate) ([]schema.ObjectUpdate, error) {
+			return []schema.ObjectUpdate{{
+				TypeName: "balances",
+				Key:      string(update.Key[1:]),
+				Value:    string(update.Value),
+				Delete:   update.Delete,
+			}}, nil
+		},
+	}, nil
+}
+
+func TestProveObject(t *testing.T) {
+	store := rootmulti.NewStore(dbm.NewMemDB(), coretesting.NewNopLogger(), metrics.NewNoOpMetrics())
+	bankKey := storetypes.NewKVStoreKey("bank")
+	store.MountStoreWithDB(bankKey, storetypes.StoreTypeIAVL, nil)
+	require.NoError(t, store.LoadVersion(0))
+
+	paths := indexer.NewStorePathIndex()
+	listener, err := decoding.Middleware(paths.Listener(), decoding.ModuleSetDecoderResolver(map[string]interface{}{"bank": bankModule{}}), decoding.MiddlewareOptions{})
+	require.NoError(t, err)
+
+	// commit a kv-pair at each height and stream it to the store path index
+	var appHashes [][]byte
+	for height, pair := range []schema.KVPairUpdate{
+		{Key: []byte("\x02alice"), Value: []byte("10")},
+		{Key: []byte("\x02bob"), Value: []byte("20")},
+		{Key: []byte("\x02alice"), Delete: true},
+	} {
+		kvStore := store.GetKVStore(bankKey)
+		if pair.Delete {
+			kvStore.Delete(pair.Key)
+		} else {
+			kvStore.Set(pair.Key, pair.Value)
+		}
+		appHashes = append(appHashes, store.Commit().Hash)
+
+		require.NoError(t, listener.SendPacket(appdata.StartBlockData{Height: uint64(height + 1)}))
+		require.NoError(t, listener.SendPacket(appdata.KVPairData{Updates: []appdata.ModuleKVPairUpdate{{ModuleName: "bank", Update: pair}}}))
+	}
+
+	service := NewService(paths, storeQuerier{store: store})
+
+	proof, err := service.ProveObject(context.Background(), "bank", "balances", "alice", 2)
+	require.NoError(t, err)
+	require.Equal(t, "bank", proof.StoreKey)
+	require.Equal(t, []byte("\x02alice"), proof.Key)
+	require.Equal(t, []byte("10"), proof.Value)
+	require.Equal(t, appHashes[1], proof.AppHash)
+	require.NoError(t, proof.Verify(appHashes[1]))
+	require.Error(t, proof.Verify(appHashes[2]))
+
+	proof.Value = []byte("11")
+	require.Error(t, proof.Verify(appHashes[1]))
+
+	// alice was deleted at height 3, which is proven by a proof of absence
+	proof, err = service.ProveObject(context.Background(), "bank", "balances", "alice", 3)
+	require.NoError(t, err)
+	require.Nil(t, proof.Value)
+	require.NoError(t, proof.Verify(appHashes[2]))
+
+	_, err = service.ProveObject(context.Background(), "bank", "balances", "carol", 3)
+	require.ErrorContains(t, err, "no store path recorded")
+
+	// objects of app data are proven at its block number
+	data := ProvableAppData(testAppData{height: 2}, service)
+	modState, err := data.AppState().GetModule("bank")
+	require.NoError(t, err)
+	coll, err := modState.GetObjectCollection("balances")
+	require.NoError(t, err)
+	prover, ok := coll.(view.ObjectProver)
+	require.True(t, ok)
+	viewProof, err := prover.ProveObject("bob")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), viewProof.Height)
+	require.Equal(t, appHashes[1], viewProof.AppHash)
+	require.Equal(t, []byte("20"), viewProof.Value)
+	require.Len(t, viewProof.Ops, 2)
+}
+
+// testAppData is app data with a bank module which has a balances collection. Only the methods used to
+// prove objects are implemented.
+type testAppData struct {
+	height uint64
+}
+
+func (d testAppData) BlockNum() (uint64, error) { return d.height, nil }
+
+func (d testAppData) AppState() view.AppState { return testAppState{} }
+
+type testAppState struct {
+	view.AppState
+}
+
+func (testAppState) GetModule(string) (view.ModuleState, error) { return testModuleState{}, nil }
+
+type testModuleState struct {
+	view.ModuleState
+}
+
+func (testModuleState) ModuleName() string { return "bank" }
+
+func (testModuleState) GetObjectCollection(string) (view.ObjectCollection, error) {
+	return testCollection{}, nil
+}
+
+type testCollection struct {
+	view.ObjectCollection
+}
+
+func (testCollection) ObjectType() schema.ObjectType { return schema.ObjectType{Name: "balances"} }
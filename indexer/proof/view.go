@@ -0,0 +1,108 @@
+package proof
+
+import (
+	"context"
+	"fmt"
+
+	"cosmossdk.io/schema/view"
+)
+
+// ProvableAppData returns app data which serves the data of an indexer target and whose object collections
+// implement view.ObjectProver by proving objects with the service at the block number of the data, so that
+// the REST and GraphQL layers can serve them in verifiable mode.
+func ProvableAppData(data view.AppData, service *Service) view.AppData {
+	return provableAppData{AppData: data, service: service}
+}
+
+type provableAppData struct {
+	view.AppData
+	service *Service
+}
+
+func (d provableAppData) AppState() view.AppState {
+	state := d.AppData.AppState()
+	if state == nil {
+		return nil
+	}
+	return provableAppState{AppState: state, data: d}
+}
+
+type provableAppState struct {
+	view.AppState
+	data provableAppData
+}
+
+func (s provableAppState) GetModule(moduleName string) (view.ModuleState, error) {
+	modState, err := s.AppState.GetModule(moduleName)
+	if err != nil || modState == nil {
+		return modState, err
+	}
+	return provableModuleState{ModuleState: modState, data: s.data}, nil
+}
+
+func (s provableAppState) Modules(f func(view.ModuleState, error) bool) {
+	s.AppState.Modules(func(modState view.ModuleState, err error) bool {
+		if modState != nil {
+			modState = provableModuleState{ModuleState: modState, data: s.data}
+		}
+		return f(modState, err)
+	})
+}
+
+type provableModuleState struct {
+	view.ModuleState
+	data provableAppData
+}
+
+func (m provableModuleState) GetObjectCollection(objectType string) (view.ObjectCollection, error) {
+	coll, err := m.ModuleState.GetObjectCollection(objectType)
+	if err != nil || coll == nil {
+		return coll, err
+	}
+	return provableCollection{ObjectCollection: coll, moduleName: m.ModuleName(), data: m.data}, nil
+}
+
+func (m provableModuleState) ObjectCollections(f func(view.ObjectCollection, error) bool) {
+	m.ModuleState.ObjectCollections(func(coll view.ObjectCollection, err error) bool {
+		if coll != nil {
+			coll = provableCollection{ObjectCollection: coll, moduleName: m.ModuleName(), data: m.data}
+		}
+		return f(coll, err)
+	})
+}
+
+type provableCollection struct {
+	view.ObjectCollection
+	moduleName string
+	data       provableAppData
+}
+
+// ProveObject implements view.ObjectProver.
+func (c provableCollection) ProveObject(key interface{}) (view.ObjectProof, error) {
+	height, err := c.data.BlockNum()
+	if err != nil {
+		return view.ObjectProof{}, err
+	}
+	if height == 0 {
+		return view.ObjectProof{}, fmt.Errorf("no block has been indexed yet")
+	}
+
+	proof, err := c.data.service.ProveObject(context.Background(), c.moduleName, c.ObjectType().Name, key, int64(height))
+	if err != nil {
+		return view.ObjectProof{}, err
+	}
+
+	res := view.ObjectProof{
+		Height:   uint64(proof.Height),
+		AppHash:  proof.AppHash,
+		StoreKey: proof.StoreKey,
+		Key:      proof.Key,
+		Value:    proof.Value,
+	}
+	if proof.ProofOps != nil {
+		for _, op := range proof.ProofOps.Ops {
+			res.Ops = append(res.Ops, view.ProofOp{Type: op.Type, Key: op.Key, Data: op.Data})
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,399 @@
+// Package remote implements the "remote" indexer type which streams app data to an indexer running out-of-process
+// over the cosmos.indexer.v1.RemoteIndexerService gRPC protocol, so that indexers can be written in any language.
+//
+// Packets are delivered at least once: the node keeps the packets which the indexer has not acknowledged yet and
+// sends them again after reconnecting if the stream breaks. At most max_in_flight packets are unacknowledged at
+// any time and each block is only committed once the indexer has acknowledged all its packets.
+package remote
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	indexerv1 "cosmossdk.io/api/cosmos/indexer/v1"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/schema/logutil"
+
+	"github.com/cosmos/cosmos-sdk/codec/schemaproto"
+)
+
+// IndexerType is the indexer type of remote indexer targets.
+const IndexerType = "remote"
+
+func init() {
+	indexer.Register(IndexerType, Init)
+}
+
+// Config is the indexer specific config of a remote indexer target.
+type Config struct {
+	// Address is the gRPC address of the remote indexer.
+	Address string `json:"address"`
+
+	// TLS enables transport security using the system's root certificates.
+	TLS bool `json:"tls"`
+
+	// MaxInFlight is the maximum number of packets which have not been acknowledged by the indexer. It
+	// defaults to DefaultMaxInFlight.
+	MaxInFlight int `json:"max_in_flight"`
+
+	// ReconnectInterval is the interval between reconnection attempts, such as "500ms". It defaults to
+	// DefaultReconnectInterval.
+	ReconnectInterval string `json:"reconnect_interval"`
+
+	// MaxReconnectAttempts is the number of consecutive attempts to reconnect to the indexer after which
+	// delivery fails. It defaults to DefaultMaxReconnectAttempts.
+	MaxReconnectAttempts int `json:"max_reconnect_attempts"`
+
+	// IndexerConfig is passed as JSON to the remote indexer when connecting.
+	IndexerConfig map[string]interface{} `json:"indexer_config"`
+}
+
+const (
+	// DefaultMaxInFlight is the default value of Config.MaxInFlight.
+	DefaultMaxInFlight = 1000
+
+	// DefaultReconnectInterval is the default value of Config.ReconnectInterval.
+	DefaultReconnectInterval = time.Second
+
+	// DefaultMaxReconnectAttempts is the default value of Config.MaxReconnectAttempts.
+	DefaultMaxReconnectAttempts = 10
+)
+
+// Init initializes a remote indexer target. It is registered as the indexer type "remote".
+func Init(params indexer.InitParams) (indexer.InitResult, error) {
+	cfg, err := decodeConfig(params.Config.Config)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	var creds credentials.TransportCredentials
+	if cfg.TLS {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	} else {
+		creds = insecure.NewCredentials()
+	}
+
+	conn, err := grpc.NewClient(cfg.Address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return indexer.InitResult{}, fmt.Errorf("failed to create remote indexer client: %w", err)
+	}
+
+	ctx := params.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = logutil.NoopLogger{}
+	}
+
+	c, err := newClient(ctx, indexerv1.NewRemoteIndexerServiceClient(conn), cfg, logger)
+	if err != nil {
+		_ = conn.Close()
+		return indexer.InitResult{}, err
+	}
+
+	c.mu.Lock()
+	lastBlockPersisted, err := c.connect()
+	c.mu.Unlock()
+	if err != nil {
+		_ = conn.Close()
+		return indexer.InitResult{}, err
+	}
+
+	if params.DoneWaitGroup != nil {
+		params.DoneWaitGroup.Add(1)
+	}
+	go func() {
+		<-ctx.Done()
+		c.close()
+		_ = conn.Close()
+		if params.DoneWaitGroup != nil {
+			params.DoneWaitGroup.Done()
+		}
+	}()
+
+	return indexer.InitResult{
+		Listener:           c.listener(),
+		LastBlockPersisted: lastBlockPersisted,
+	}, nil
+}
+
+func decodeConfig(rawConfig map[string]interface{}) (Config, error) {
+	bz, err := json.Marshal(rawConfig)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid remote indexer config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(bz, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid remote indexer config: %w", err)
+	}
+
+	if cfg.Address == "" {
+		return Config{}, fmt.Errorf("remote indexer address is required")
+	}
+
+	return cfg, nil
+}
+
+// client streams packets to the remote indexer. All fields are guarded by mu, which is held while packets are
+// sent so that the listener callbacks and reconnections are serialized.
+type client struct {
+	ctx       context.Context
+	svc       indexerv1.RemoteIndexerServiceClient
+	logger    logutil.Logger
+	sessionID string
+	config    []byte
+
+	maxInFlight          int
+	reconnectInterval    time.Duration
+	maxReconnectAttempts int
+
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	// stream is the current stream or nil if it broke
+	stream       indexerv1.RemoteIndexerService_StreamClient
+	cancelStream context.CancelFunc
+	// generation is incremented for each stream so that the receivers of old streams can stop
+	generation uint64
+
+	sequence uint64
+	// pending are the packets which were sent but not acknowledged yet in sequence order
+	pending []*indexerv1.StreamRequest
+	// err is the error reported by the indexer, after which no more packets are delivered
+	err error
+}
+
+func newClient(ctx context.Context, svc indexerv1.RemoteIndexerServiceClient, cfg Config, logger logutil.Logger) (*client, error) {
+	sessionID := make([]byte, 16)
+	if _, err := rand.Read(sessionID); err != nil {
+		return nil, err
+	}
+
+	config, err := json.Marshal(cfg.IndexerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote indexer config: %w", err)
+	}
+
+	c := &client{
+		ctx:                  ctx,
+		svc:                  svc,
+		logger:               logger,
+		sessionID:            hex.EncodeToString(sessionID),
+		config:               config,
+		maxInFlight:          cfg.MaxInFlight,
+		reconnectInterval:    DefaultReconnectInterval,
+		maxReconnectAttempts: cfg.MaxReconnectAttempts,
+	}
+	c.cond = sync.NewCond(&c.mu)
+
+	if c.maxInFlight <= 0 {
+		c.maxInFlight = DefaultMaxInFlight
+	}
+	if c.maxReconnectAttempts <= 0 {
+		c.maxReconnectAttempts = DefaultMaxReconnectAttempts
+	}
+	if cfg.ReconnectInterval != "" {
+		c.reconnectInterval, err = time.ParseDuration(cfg.ReconnectInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote indexer reconnect_interval: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+func (c *client) listener() appdata.Listener {
+	send := func(packet appdata.Packet) error {
+		p, err := schemaproto.PacketToProto(packet)
+		if err != nil {
+			return err
+		}
+		return c.send(p)
+	}
+
+	return appdata.Listener{
+		InitializeModuleData: func(data appdata.ModuleInitializationData) error { return send(data) },
+		StartBlock:           func(data appdata.StartBlockData) error { return send(data) },
+		OnTx:                 func(data appdata.TxData) error { return send(data) },
+		OnEvent:              func(data appdata.EventData) error { return send(data) },
+		OnKVPair:             func(data appdata.KVPairData) error { return send(data) },
+		OnObjectUpdate:       func(data appdata.ObjectUpdateData) error { return send(data) },
+		OnBytesChunk:         func(data appdata.BytesChunkData) error { return send(data) },
+		Commit: func(data appdata.CommitData) error {
+			if err := send(data); err != nil {
+				return err
+			}
+
+			// the block is only committed once the indexer has processed all of its packets
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return c.await(func() bool { return len(c.pending) == 0 })
+		},
+	}
+}
+
+// send sends a packet once fewer than maxInFlight packets are unacknowledged. If the stream is broken, the
+// packet is sent after reconnecting.
+func (c *client) send(packet *indexerv1.Packet) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.await(func() bool { return len(c.pending) < c.maxInFlight }); err != nil {
+		return err
+	}
+
+	c.sequence++
+	req := &indexerv1.StreamRequest{Sequence: c.sequence, Packet: packet}
+	c.pending = append(c.pending, req)
+	if err := c.stream.Send(req); err != nil {
+		c.logger.Warn("remote indexer stream broke", "error", err)
+		c.closeStream()
+	}
+	return nil
+}
+
+// await waits until cond is true, reconnecting if the stream breaks in the meantime. c.mu must be held.
+func (c *client) await(cond func() bool) error {
+	for {
+		if c.err != nil {
+			return c.err
+		}
+
+		if c.stream == nil {
+			if _, err := c.connect(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if cond() {
+			return nil
+		}
+
+		c.cond.Wait()
+	}
+}
+
+// connect connects to the indexer, opens a new stream, sends the pending packets again and returns the last
+// block persisted by the indexer. It retries up to maxReconnectAttempts times. c.mu must be held.
+func (c *client) connect() (int64, error) {
+	var lastErr error
+	for attempt := 0; attempt < c.maxReconnectAttempts; attempt++ {
+		if attempt > 0 {
+			c.mu.Unlock()
+			select {
+			case <-c.ctx.Done():
+			case <-time.After(c.reconnectInterval):
+			}
+			c.mu.Lock()
+		}
+
+		if err := c.ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		lastBlockPersisted, err := c.openStream()
+		if err == nil {
+			return lastBlockPersisted, nil
+		}
+
+		lastErr = err
+		c.logger.Warn("failed to connect to remote indexer", "attempt", attempt+1, "error", err)
+	}
+
+	return 0, fmt.Errorf("failed to connect to remote indexer after %d attempts: %w", c.maxReconnectAttempts, lastErr)
+}
+
+func (c *client) openStream() (int64, error) {
+	res, err := c.svc.Connect(c.ctx, &indexerv1.ConnectRequest{SessionId: c.sessionID, ConfigJson: c.config})
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	stream, err := c.svc.Stream(ctx)
+	if err != nil {
+		cancel()
+		return 0, err
+	}
+
+	for _, req := range c.pending {
+		if err := stream.Send(req); err != nil {
+			cancel()
+			return 0, err
+		}
+	}
+
+	c.stream = stream
+	c.cancelStream = cancel
+	c.generation++
+	go c.receive(stream, c.generation)
+
+	return res.LastBlockPersisted, nil
+}
+
+// receive processes the acknowledgements of the indexer until the stream breaks.
+func (c *client) receive(stream indexerv1.RemoteIndexerService_StreamClient, generation uint64) {
+	for {
+		res, err := stream.Recv()
+
+		c.mu.Lock()
+		if generation != c.generation {
+			c.mu.Unlock()
+			return
+		}
+
+		if err != nil {
+			c.logger.Warn("remote indexer stream broke", "error", err)
+			c.closeStream()
+			c.cond.Broadcast()
+			c.mu.Unlock()
+			return
+		}
+
+		i := 0
+		for i < len(c.pending) && c.pending[i].Sequence <= res.AckedSequence {
+			i++
+		}
+		c.pending = c.pending[i:]
+
+		if res.Error != "" {
+			c.err = fmt.Errorf("remote indexer failed to process packet %d: %s", res.AckedSequence+1, res.Error)
+		}
+
+		c.cond.Broadcast()
+		c.mu.Unlock()
+	}
+}
+
+// closeStream closes the current stream. c.mu must be held.
+func (c *client) closeStream() {
+	if c.stream == nil {
+		return
+	}
+
+	c.cancelStream()
+	c.stream = nil
+	c.generation++
+}
+
+func (c *client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closeStream()
+	c.cond.Broadcast()
+}
@@ -0,0 +1,222 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	indexerv1 "cosmossdk.io/api/cosmos/indexer/v1"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/logutil"
+)
+
+type recordingIndexer struct {
+	packets []appdata.Packet
+	failAt  int
+}
+
+func (r *recordingIndexer) listener() appdata.Listener {
+	record := func(p appdata.Packet) error {
+		if r.failAt != 0 && len(r.packets)+1 == r.failAt {
+			return errors.New("indexer failure")
+		}
+		r.packets = append(r.packets, p)
+		return nil
+	}
+	return appdata.Listener{
+		InitializeModuleData: func(data appdata.ModuleInitializationData) error { return record(data) },
+		StartBlock:           func(data appdata.StartBlockData) error { return record(data) },
+		OnKVPair:             func(data appdata.KVPairData) error { return record(data) },
+		OnObjectUpdate:       func(data appdata.ObjectUpdateData) error { return record(data) },
+		Commit:               func(data appdata.CommitData) error { return record(data) },
+	}
+}
+
+// flakyServer breaks the first stream after it has processed breakAfter packets without acknowledging them.
+type flakyServer struct {
+	*server
+	breakAfter int
+	streams    int
+}
+
+func (f *flakyServer) Stream(stream indexerv1.RemoteIndexerService_StreamServer) error {
+	f.streams++
+	if f.streams > 1 {
+		return f.server.Stream(stream)
+	}
+
+	for i := 0; i < f.breakAfter; i++ {
+		req, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if _, err := f.process(req); err != nil {
+			return err
+		}
+	}
+	return errors.New("connection lost")
+}
+
+func startClient(t *testing.T, svc indexerv1.RemoteIndexerServiceServer, cfg Config) (*client, int64) {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	indexerv1.RegisterRemoteIndexerServiceServer(srv, svc)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	c, err := newClient(ctx, indexerv1.NewRemoteIndexerServiceClient(conn), cfg, logutil.NoopLogger{})
+	require.NoError(t, err)
+
+	c.mu.Lock()
+	lastBlockPersisted, err := c.connect()
+	c.mu.Unlock()
+	require.NoError(t, err)
+	return c, lastBlockPersisted
+}
+
+var testModuleSchema = func() schema.ModuleSchema {
+	moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:        "balances",
+		KeyFields:   []schema.Field{{Name: "address", Kind: schema.AddressKind}, {Name: "denom", Kind: schema.StringKind}},
+		ValueFields: []schema.Field{{Name: "amount", Kind: schema.IntegerStringKind}},
+	}})
+	if err != nil {
+		panic(err)
+	}
+	return moduleSchema
+}()
+
+func sendTestBlock(listener appdata.Listener, height uint64) error {
+	packets := []appdata.Packet{
+		appdata.StartBlockData{Height: height},
+		appdata.KVPairData{Updates: []appdata.ModuleKVPairUpdate{
+			{ModuleName: "bank", Update: schema.KVPairUpdate{Key: []byte("key"), Value: []byte("value")}},
+		}},
+		appdata.ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{
+			{TypeName: "balances", Key: []interface{}{[]byte{1}, "atom"}, Value: "10"},
+			{TypeName: "balances", Key: []interface{}{[]byte{2}, "atom"}, Value: schema.MapValueUpdates{"amount": "5"}},
+			{TypeName: "balances", Key: []interface{}{[]byte{3}, "atom"}, Delete: true},
+		}},
+		appdata.CommitData{},
+	}
+	for _, p := range packets {
+		if err := listener.SendPacket(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRemoteIndexer(t *testing.T) {
+	idx := &recordingIndexer{}
+	c, lastBlockPersisted := startClient(t, NewServer(ServerOptions{
+		Listener:           idx.listener(),
+		LastBlockPersisted: func() (int64, error) { return 7, nil },
+	}), Config{MaxInFlight: 2})
+	require.Equal(t, int64(7), lastBlockPersisted)
+
+	listener := c.listener()
+	require.NoError(t, listener.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "bank", Schema: testModuleSchema, SchemaChanged: true}))
+	require.NoError(t, sendTestBlock(listener, 8))
+
+	// commit only returns once all packets were processed
+	require.Len(t, idx.packets, 5)
+	initData := idx.packets[0].(appdata.ModuleInitializationData)
+	require.Equal(t, "bank", initData.ModuleName)
+	require.True(t, initData.SchemaChanged)
+	_, found := initData.Schema.LookupType("balances")
+	require.True(t, found)
+	require.Equal(t, uint64(8), idx.packets[1].(appdata.StartBlockData).Height)
+	require.Equal(t, []byte("value"), idx.packets[2].(appdata.KVPairData).Updates[0].Update.Value)
+	require.Equal(t, []schema.ObjectUpdate{
+		{TypeName: "balances", Key: []interface{}{[]byte{1}, "atom"}, Value: "10"},
+		{TypeName: "balances", Key: []interface{}{[]byte{2}, "atom"}, Value: schema.MapValueUpdates{"amount": "5"}},
+		{TypeName: "balances", Key: []interface{}{[]byte{3}, "atom"}, Delete: true},
+	}, idx.packets[3].(appdata.ObjectUpdateData).Updates)
+	require.Equal(t, appdata.CommitData{}, idx.packets[4])
+}
+
+func TestRemoteIndexer_reconnect(t *testing.T) {
+	idx := &recordingIndexer{}
+	svc := &flakyServer{server: NewServer(ServerOptions{Listener: idx.listener()}).(*server), breakAfter: 2}
+	c, lastBlockPersisted := startClient(t, svc, Config{ReconnectInterval: "1ms"})
+	require.Equal(t, int64(-1), lastBlockPersisted)
+
+	listener := c.listener()
+	require.NoError(t, sendTestBlock(listener, 1))
+	require.NoError(t, sendTestBlock(listener, 2))
+
+	// the packets processed before the stream broke were sent again but only delivered once
+	require.Equal(t, 2, svc.streams)
+	require.Len(t, idx.packets, 8)
+	require.Equal(t, uint64(1), idx.packets[0].(appdata.StartBlockData).Height)
+	require.Equal(t, uint64(2), idx.packets[4].(appdata.StartBlockData).Height)
+}
+
+func TestRemoteIndexer_chunks(t *testing.T) {
+	idx := &recordingIndexer{}
+	c, _ := startClient(t, NewServer(ServerOptions{Listener: idx.listener()}), Config{MaxInFlight: 2})
+
+	moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:        "code",
+		KeyFields:   []schema.Field{{Name: "id", Kind: schema.Uint64Kind}},
+		ValueFields: []schema.Field{{Name: "wasm", Kind: schema.BytesKind}},
+	}})
+	require.NoError(t, err)
+
+	listener, err := appdata.ChunkBytes(c.listener(), appdata.ChunkOptions{Threshold: 4})
+	require.NoError(t, err)
+	wasm := []byte("0123456789")
+	packets := []appdata.Packet{
+		appdata.ModuleInitializationData{ModuleName: "wasm", Schema: moduleSchema},
+		appdata.ObjectUpdateData{ModuleName: "wasm", Updates: []schema.ObjectUpdate{{TypeName: "code", Key: uint64(1), Value: wasm}}},
+		appdata.CommitData{},
+	}
+	for _, p := range packets {
+		require.NoError(t, listener.SendPacket(p))
+	}
+
+	// the value is sent in 3 chunks and reassembled by the server
+	require.Len(t, idx.packets, 3)
+	require.Equal(t, wasm, idx.packets[1].(appdata.ObjectUpdateData).Updates[0].Value)
+}
+
+func TestRemoteIndexer_error(t *testing.T) {
+	idx := &recordingIndexer{failAt: 3}
+	c, _ := startClient(t, NewServer(ServerOptions{Listener: idx.listener()}), Config{})
+
+	err := sendTestBlock(c.listener(), 1)
+	require.ErrorContains(t, err, "remote indexer failed to process packet 3: indexer failure")
+}
+
+func TestDecodeConfig(t *testing.T) {
+	_, err := decodeConfig(map[string]interface{}{})
+	require.ErrorContains(t, err, "address is required")
+
+	cfg, err := decodeConfig(map[string]interface{}{
+		"address":        "localhost:9000",
+		"max_in_flight":  10,
+		"indexer_config": map[string]interface{}{"foo": "bar"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, Config{Address: "localhost:9000", MaxInFlight: 10, IndexerConfig: map[string]interface{}{"foo": "bar"}}, cfg)
+}
@@ -0,0 +1,103 @@
+package remote
+
+import (
+	"context"
+	"sync"
+
+	indexerv1 "cosmossdk.io/api/cosmos/indexer/v1"
+	"cosmossdk.io/schema/appdata"
+
+	"github.com/cosmos/cosmos-sdk/codec/schemaproto"
+)
+
+// ServerOptions are the options of a remote indexer server.
+type ServerOptions struct {
+	// Listener receives the app data streamed by the node.
+	Listener appdata.Listener
+
+	// LastBlockPersisted returns the last block persisted by the indexer as described by
+	// indexer.InitResult.LastBlockPersisted. If it is nil, the indexer is assumed not to persist data.
+	LastBlockPersisted func() (int64, error)
+}
+
+// NewServer returns an implementation of the remote indexer service for indexers written in go which delivers
+// the streamed packets to a listener. Packets which were already delivered are skipped when the node sends
+// them again after reconnecting. Bytes values which the node sends in chunks are reassembled with
+// appdata.ReassembleBytes unless the listener handles OnBytesChunk itself.
+func NewServer(opts ServerOptions) indexerv1.RemoteIndexerServiceServer {
+	if opts.Listener.OnBytesChunk == nil {
+		opts.Listener = appdata.ReassembleBytes(opts.Listener)
+	}
+	return &server{opts: opts}
+}
+
+type server struct {
+	indexerv1.UnimplementedRemoteIndexerServiceServer
+	opts ServerOptions
+
+	// mu serializes the delivery of packets to the listener
+	mu        sync.Mutex
+	sessionID string
+	processed uint64
+}
+
+// Connect implements the RemoteIndexerServiceServer interface.
+func (s *server) Connect(_ context.Context, req *indexerv1.ConnectRequest) (*indexerv1.ConnectResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.SessionId != s.sessionID {
+		s.sessionID = req.SessionId
+		s.processed = 0
+	}
+
+	if s.opts.LastBlockPersisted == nil {
+		return &indexerv1.ConnectResponse{LastBlockPersisted: -1}, nil
+	}
+
+	lastBlockPersisted, err := s.opts.LastBlockPersisted()
+	if err != nil {
+		return nil, err
+	}
+	return &indexerv1.ConnectResponse{LastBlockPersisted: lastBlockPersisted}, nil
+}
+
+// Stream implements the RemoteIndexerServiceServer interface.
+func (s *server) Stream(stream indexerv1.RemoteIndexerService_StreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		res, err := s.process(req)
+		if err != nil {
+			return err
+		}
+
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *server) process(req *indexerv1.StreamRequest) (*indexerv1.StreamResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.Sequence <= s.processed {
+		// already delivered before the node reconnected
+		return &indexerv1.StreamResponse{AckedSequence: s.processed}, nil
+	}
+
+	packet, err := schemaproto.PacketFromProto(req.Packet)
+	if err == nil {
+		err = s.opts.Listener.SendPacket(packet)
+	}
+	if err != nil {
+		return &indexerv1.StreamResponse{AckedSequence: s.processed, Error: err.Error()}, nil
+	}
+
+	s.processed = req.Sequence
+	return &indexerv1.StreamResponse{AckedSequence: s.processed}, nil
+}
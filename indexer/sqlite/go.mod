@@ -0,0 +1,18 @@
+module cosmossdk.io/indexer/sqlite
+
+// NOTE: we are staying on an earlier version of golang to avoid problems building
+// with older codebases.
+go 1.12
+
+// NOTE: like cosmossdk.io/indexer/postgres, this module should only use the golang
+// standard library (database/sql), cosmossdk.io/schema and the SQL generation of
+// cosmossdk.io/indexer/postgres. The SQLite database/sql driver is registered by the app.
+require (
+	cosmossdk.io/indexer/postgres v0.0.0-00010101000000-000000000000
+	cosmossdk.io/schema v0.1.1
+)
+
+replace (
+	cosmossdk.io/indexer/postgres => ../postgres
+	cosmossdk.io/schema => ../../schema
+)
@@ -0,0 +1,107 @@
+// Package sqlite provides an indexer target which indexes app data into a SQLite database. It shares the SQL
+// generation of the PostgreSQL indexer and is suitable for local development, tests and light clients.
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"cosmossdk.io/indexer/postgres"
+	"cosmossdk.io/schema/indexer"
+)
+
+// IndexerType is the type of the SQLite indexer target in the indexer config.
+const IndexerType = "sqlite"
+
+func init() {
+	indexer.Register(IndexerType, Init)
+}
+
+// Config is the indexer specific config of a SQLite indexer target.
+type Config struct {
+	// DatabasePath is the data source name of the SQLite database, usually the path of the database file.
+	// In-memory databases must use a shared cache, i.e. "file::memory:?cache=shared", so that the view
+	// queries the same database.
+	DatabasePath string `json:"database_path"`
+
+	// DatabaseDriver is the SQLite database/sql driver to use. This defaults to "sqlite3", the name of the
+	// github.com/mattn/go-sqlite3 driver, which needs to be imported by the app.
+	DatabaseDriver string `json:"database_driver"`
+
+	// DisableRetainDeletions disables the retain deletions functionality even if it is set in an object type schema.
+	DisableRetainDeletions bool `json:"disable_retain_deletions"`
+}
+
+// DefaultDatabaseDriver is the default value of Config.DatabaseDriver.
+const DefaultDatabaseDriver = "sqlite3"
+
+// StartIndexer starts a SQLite indexer. Its listener and view can be accessed with postgres.Indexer's
+// Listener and View methods.
+func StartIndexer(ctx context.Context, logger postgres.SqlLogger, config Config) (*postgres.Indexer, error) {
+	if config.DatabasePath == "" {
+		return nil, fmt.Errorf("missing database path")
+	}
+
+	driver := config.DatabaseDriver
+	if driver == "" {
+		driver = DefaultDatabaseDriver
+	}
+
+	return postgres.NewIndexer(ctx, logger, postgres.Config{
+		DatabaseURL:            config.DatabasePath,
+		DatabaseDriver:         driver,
+		DisableRetainDeletions: config.DisableRetainDeletions,
+		Dialect:                postgres.DialectSQLite,
+	})
+}
+
+// Init initializes a SQLite indexer target from the indexer config.
+func Init(params indexer.InitParams) (indexer.InitResult, error) {
+	cfg, err := decodeConfig(params.Config.Config)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	ctx := params.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var logger postgres.SqlLogger
+	if params.Logger != nil {
+		logger = func(msg, sql string, args ...interface{}) {
+			params.Logger.Debug(msg, "sql", sql, "params", args)
+		}
+	}
+
+	idx, err := StartIndexer(ctx, logger, cfg)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	lastBlock, err := idx.View().BlockNum()
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	return indexer.InitResult{
+		Listener:           idx.Listener(),
+		LastBlockPersisted: int64(lastBlock),
+		View:               idx.View(),
+	}, nil
+}
+
+func decodeConfig(rawConfig map[string]interface{}) (Config, error) {
+	bz, err := json.Marshal(rawConfig)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid sqlite indexer config: %v", err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(bz, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid sqlite indexer config: %v", err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+
+	return cfg, nil
+}
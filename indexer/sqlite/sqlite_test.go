@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"strings"
+	"testing"
+
+	"cosmossdk.io/schema/indexer"
+)
+
+func TestInit(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]interface{}
+		errContains string
+	}{
+		{
+			name:        "missing database path",
+			config:      map[string]interface{}{},
+			errContains: "missing database path",
+		},
+		{
+			name:        "invalid config",
+			config:      map[string]interface{}{"database_path": 1},
+			errContains: "invalid sqlite indexer config",
+		},
+		{
+			name:        "driver not registered",
+			config:      map[string]interface{}{"database_path": "test.db"},
+			errContains: `unknown driver "sqlite3"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Init(indexer.InitParams{Config: indexer.Config{Type: IndexerType, Config: tt.config}})
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error containing %q, got: %v", tt.errContains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+module cosmossdk.io/indexer/sqlite/testing
+
+require (
+	cosmossdk.io/indexer/sqlite v0.0.0-00010101000000-000000000000
+	cosmossdk.io/schema v0.1.1
+	github.com/mattn/go-sqlite3 v1.14.22
+	github.com/stretchr/testify v1.9.0
+	gotest.tools/v3 v3.5.1
+)
+
+require (
+	cosmossdk.io/indexer/postgres v0.0.0-00010101000000-000000000000 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/google/go-cmp v0.5.9 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	cosmossdk.io/indexer/postgres => ../../postgres
+	cosmossdk.io/indexer/sqlite => ../.
+	cosmossdk.io/schema => ../../../schema
+)
+
+go 1.22
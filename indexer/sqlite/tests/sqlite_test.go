@@ -0,0 +1,250 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // this is where we get our sqlite3 database driver from
+	"github.com/stretchr/testify/require"
+	"gotest.tools/v3/golden"
+
+	"cosmossdk.io/indexer/sqlite"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/schema/view"
+)
+
+var statusEnum = schema.EnumType{
+	Name:   "status",
+	Values: []string{"active", "frozen"},
+}
+
+var balanceType = schema.ObjectType{
+	Name: "balance",
+	KeyFields: []schema.Field{
+		{Name: "address", Kind: schema.AddressKind},
+		{Name: "denom", Kind: schema.StringKind},
+	},
+	ValueFields: []schema.Field{
+		{Name: "amount", Kind: schema.IntegerStringKind},
+		{Name: "updated", Kind: schema.TimeKind},
+		{Name: "status", Kind: schema.EnumKind, EnumType: statusEnum},
+		{Name: "memo", Kind: schema.StringKind, Nullable: true},
+	},
+	RetainDeletions: true,
+}
+
+var paramsType = schema.ObjectType{
+	Name: "params",
+	ValueFields: []schema.Field{
+		{Name: "max_supply", Kind: schema.Uint64Kind},
+		{Name: "enabled", Kind: schema.BoolKind},
+	},
+}
+
+var (
+	addr1 = []byte{0x01}
+	addr2 = []byte{0x02}
+	time1 = time.Unix(1700000000, 123456789)
+	time2 = time.Unix(1700000060, 5)
+)
+
+func balance(addr []byte, denom, amount string, updated time.Time, status string, memo interface{}) schema.ObjectUpdate {
+	return schema.ObjectUpdate{
+		TypeName: "balance",
+		Key:      []interface{}{addr, denom},
+		Value:    []interface{}{amount, updated, status, memo},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		testRoundTrip(t, false, "round_trip.txt")
+	})
+
+	t.Run("retain deletions disabled", func(t *testing.T) {
+		testRoundTrip(t, true, "round_trip_no_retain_delete.txt")
+	})
+}
+
+func testRoundTrip(t *testing.T, disableRetainDeletions bool, goldenFileName string) {
+	t.Helper()
+	modSchema, err := schema.NewModuleSchema([]schema.ObjectType{balanceType, paramsType})
+	require.NoError(t, err)
+
+	buf := &strings.Builder{}
+	logger := func(msg, sql string, params ...interface{}) {
+		_, err := fmt.Fprintln(buf, msg)
+		require.NoError(t, err)
+		_, err = fmt.Fprintln(buf, sql)
+		require.NoError(t, err)
+		if len(params) != 0 {
+			_, err = fmt.Fprintln(buf, "Params:", params)
+			require.NoError(t, err)
+		}
+		_, err = fmt.Fprintln(buf)
+		require.NoError(t, err)
+	}
+	dbPath := "file:" + filepath.Join(t.TempDir(), "index.db")
+	idx, err := sqlite.StartIndexer(context.Background(), logger, sqlite.Config{
+		DatabasePath:           dbPath,
+		DisableRetainDeletions: disableRetainDeletions,
+	})
+	require.NoError(t, err)
+	listener := idx.Listener()
+
+	require.NoError(t, listener.InitializeModuleData(appdata.ModuleInitializationData{
+		ModuleName: "test",
+		Schema:     modSchema,
+	}))
+
+	// block 1 inserts objects
+	require.NoError(t, listener.StartBlock(appdata.StartBlockData{Height: 1}))
+	require.NoError(t, listener.OnObjectUpdate(appdata.ObjectUpdateData{
+		ModuleName: "test",
+		Updates: []schema.ObjectUpdate{
+			balance(addr1, "atom", "1000", time1, "active", nil),
+			balance(addr1, "stake", "18446744073709551616", time1, "active", "big"),
+			balance(addr2, "atom", "9", time1, "frozen", nil),
+			{TypeName: "params", Value: []interface{}{uint64(18446744073709551615), true}},
+		},
+	}))
+	require.NoError(t, listener.Commit(appdata.CommitData{}))
+
+	// block 2 updates some value fields, overwrites and deletes objects
+	require.NoError(t, listener.StartBlock(appdata.StartBlockData{Height: 2}))
+	require.NoError(t, listener.OnObjectUpdate(appdata.ObjectUpdateData{
+		ModuleName: "test",
+		Updates: []schema.ObjectUpdate{
+			{
+				TypeName: "balance",
+				Key:      []interface{}{addr1, "atom"},
+				Value:    schema.MapValueUpdates{"amount": "100", "updated": time2},
+			},
+			balance(addr2, "atom", "9", time2, "active", "restored"),
+			{TypeName: "balance", Key: []interface{}{addr1, "stake"}, Delete: true},
+			{TypeName: "params", Value: schema.MapValueUpdates{"enabled": false}},
+		},
+	}))
+	require.NoError(t, listener.Commit(appdata.CommitData{}))
+
+	golden.Assert(t, buf.String(), goldenFileName)
+
+	// the time column is generated from the nanoseconds column by SQLite
+	db, err := sql.Open("sqlite3", dbPath)
+	require.NoError(t, err)
+	defer db.Close()
+	var updated string
+	err = db.QueryRow(`SELECT "updated" FROM "test_balance" WHERE "address" = '0x01' AND "denom" = 'atom';`).Scan(&updated)
+	require.NoError(t, err)
+	require.Equal(t, "2023-11-14T22:14:20.000Z", updated)
+
+	// the committed state is read back in schema format
+	appData := view.AppData(idx.View())
+	blockNum, err := appData.BlockNum()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), blockNum)
+
+	numModules, err := appData.AppState().NumModules()
+	require.NoError(t, err)
+	require.Equal(t, 1, numModules)
+	modState, err := appData.AppState().GetModule("test")
+	require.NoError(t, err)
+	require.NotNil(t, modState)
+	require.Equal(t, "test", modState.ModuleName())
+
+	balances, err := modState.GetObjectCollection("balance")
+	require.NoError(t, err)
+	require.Equal(t, balanceType, balances.ObjectType())
+
+	obj, found, err := balances.GetObject([]interface{}{addr1, "atom"})
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "100", obj.Value.([]interface{})[0])
+	require.True(t, time2.Equal(obj.Value.([]interface{})[1].(time.Time)))
+	require.Nil(t, obj.Value.([]interface{})[3])
+
+	// deleted objects are only kept with a deleted flag if deletions are retained
+	obj, found, err = balances.GetObject([]interface{}{addr1, "stake"})
+	require.NoError(t, err)
+	require.Equal(t, !disableRetainDeletions, found)
+	if found {
+		require.True(t, obj.Delete)
+		require.Equal(t, "18446744073709551616", obj.Value.([]interface{})[0])
+	}
+
+	expectedLen := 3
+	if disableRetainDeletions {
+		expectedLen = 2
+	}
+	n, err := balances.Len()
+	require.NoError(t, err)
+	require.Equal(t, expectedLen, n)
+
+	var all []schema.ObjectUpdate
+	balances.AllState(func(update schema.ObjectUpdate, err error) bool {
+		require.NoError(t, err)
+		all = append(all, update)
+		return true
+	})
+	require.Len(t, all, expectedLen)
+	require.Equal(t, []interface{}{addr1, "atom"}, all[0].Key)
+
+	// integer strings are ordered numerically even though SQLite stores them as TEXT
+	filter := view.FieldFilter{
+		{Field: "denom", Op: view.FilterEq, Value: "atom"},
+		{Field: "status", Op: view.FilterEq, Value: "active"},
+	}
+	order := view.OrderBy{{Field: "amount", Descending: true}}
+	res, err := balances.List(filter, order, view.Pagination{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, res.Objects, 1)
+	require.Equal(t, []interface{}{addr1, "atom"}, res.Objects[0].Key)
+	require.NotEmpty(t, res.NextCursor)
+
+	res, err = balances.List(filter, order, view.Pagination{Limit: 1, Cursor: res.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, res.Objects, 1)
+	require.Equal(t, []interface{}{addr2, "atom"}, res.Objects[0].Key)
+	require.Equal(t, "restored", res.Objects[0].Value.([]interface{})[3])
+	require.Empty(t, res.NextCursor)
+
+	params, err := modState.GetObjectCollection("params")
+	require.NoError(t, err)
+	obj, found, err = params.GetObject(nil)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, []interface{}{uint64(18446744073709551615), false}, obj.Value)
+}
+
+func TestInit(t *testing.T) {
+	dbPath := "file:" + filepath.Join(t.TempDir(), "index.db")
+	res, err := sqlite.Init(indexerInitParams(dbPath))
+	require.NoError(t, err)
+	require.Equal(t, int64(0), res.LastBlockPersisted)
+	require.NoError(t, res.Listener.StartBlock(appdata.StartBlockData{Height: 7}))
+	require.NoError(t, res.Listener.Commit(appdata.CommitData{}))
+
+	// the last persisted block is read from the existing database when the target is initialized again
+	res, err = sqlite.Init(indexerInitParams(dbPath))
+	require.NoError(t, err)
+	require.Equal(t, int64(7), res.LastBlockPersisted)
+	blockNum, err := res.View.BlockNum()
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), blockNum)
+}
+
+func indexerInitParams(dbPath string) indexer.InitParams {
+	return indexer.InitParams{
+		Config: indexer.Config{
+			Type:   sqlite.IndexerType,
+			Config: map[string]interface{}{"database_path": dbPath},
+		},
+	}
+}
@@ -0,0 +1,65 @@
+module cosmossdk.io/indexer/stream/nats
+
+go 1.22.2
+
+// NOTE: apart from cosmossdk.io/indexer/stream, this module should only depend on the NATS client.
+require (
+	cosmossdk.io/api v0.7.5
+	cosmossdk.io/indexer/stream v0.0.0-00010101000000-000000000000
+	cosmossdk.io/schema v0.1.1
+	github.com/nats-io/nats-server/v2 v2.10.17
+	github.com/nats-io/nats.go v1.36.0
+	github.com/stretchr/testify v1.9.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	github.com/cosmos/cosmos-proto v1.0.0-beta.5 // indirect
+	github.com/cosmos/cosmos-sdk v0.53.0 // indirect
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/klauspost/compress v1.17.9 // indirect
+	github.com/minio/highwayhash v1.0.2 // indirect
+	github.com/nats-io/jwt/v2 v2.5.7 // indirect
+	github.com/nats-io/nkeys v0.4.7 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+	golang.org/x/crypto v0.25.0 // indirect
+	golang.org/x/net v0.27.0 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	golang.org/x/time v0.5.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240709173604-40e1e62336c5 // indirect
+	google.golang.org/grpc v1.64.1 // indirect
+	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace cosmossdk.io/indexer/stream => ..
+
+replace github.com/cosmos/cosmos-sdk => ../../..
+
+// TODO remove post spinning out all modules
+replace (
+	cosmossdk.io/api => ../../../api
+	cosmossdk.io/collections => ../../../collections
+	cosmossdk.io/core => ../../../core
+	cosmossdk.io/core/testing => ../../../core/testing
+	cosmossdk.io/log => ../../../log
+	cosmossdk.io/schema => ../../../schema
+	cosmossdk.io/store => ../../../store
+	cosmossdk.io/x/accounts => ../../../x/accounts
+	cosmossdk.io/x/auth => ../../../x/auth
+	cosmossdk.io/x/bank => ../../../x/bank
+	cosmossdk.io/x/consensus => ../../../x/consensus
+	cosmossdk.io/x/staking => ../../../x/staking
+	cosmossdk.io/x/tx => ../../../x/tx
+)
+
+// Below are the long-lived replace of the Cosmos SDK
+replace (
+	// use cosmos fork of keyring
+	github.com/99designs/keyring => github.com/cosmos/keyring v1.2.0
+
+	// replace broken goleveldb
+	github.com/syndtr/goleveldb => github.com/syndtr/goleveldb v1.0.1-0.20210819022825-2ae1ddf74ef7
+)
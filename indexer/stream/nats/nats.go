@@ -0,0 +1,215 @@
+// Package nats implements the "nats" publisher of the stream indexer, which publishes messages to the NATS
+// subjects named after their topics, either with core NATS or with JetStream so that messages are persisted in
+// streams before the blocks which produced them are committed.
+package nats
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	natsclient "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"cosmossdk.io/indexer/stream"
+)
+
+// PublisherName is the name under which the publisher is registered, which stream indexer targets select with
+// their publisher config option.
+const PublisherName = "nats"
+
+func init() {
+	stream.RegisterPublisher(PublisherName, NewPublisher)
+}
+
+// Config is the publisher specific config of a stream indexer target.
+type Config struct {
+	// URL is the URL of the NATS server, or a comma separated list of the URLs of the servers of a cluster. It
+	// defaults to nats.DefaultURL.
+	URL string `json:"url"`
+
+	// Name is the name of the connection reported to the NATS server. It defaults to DefaultName.
+	Name string `json:"name"`
+
+	// CredentialsFile is the path of a user credentials file with the JWT and seed of the NATS user.
+	CredentialsFile string `json:"credentials_file"`
+
+	// Username and Password authenticate the NATS user if they are set.
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// Token is the authentication token of the NATS user if it is set.
+	Token string `json:"token"`
+
+	// JetStream publishes messages to JetStream and waits for their acknowledgements when flushing, so that
+	// messages are persisted by the stream bound to their subject. The streams must be created beforehand.
+	// Otherwise, messages are published with core NATS and flushing only waits for the server to receive them.
+	JetStream bool `json:"jetstream"`
+}
+
+const (
+	// DefaultName is the default value of Config.Name.
+	DefaultName = "cosmos-sdk-stream-indexer"
+
+	// HeaderKey is the message header with the hex encoded key of the message, because NATS messages don't have
+	// a key. Consumers can use it to process the updates of each kv-pair or object in order.
+	HeaderKey = "key"
+
+	// flushTimeout bounds flushes whose context has no deadline, because the NATS client requires one.
+	flushTimeout = 10 * time.Second
+)
+
+// NewPublisher creates a NATS publisher from the publisher specific config of a stream indexer target. It is
+// registered as the publisher "nats".
+func NewPublisher(ctx context.Context, rawConfig map[string]interface{}) (stream.Publisher, error) {
+	cfg, err := decodeConfig(rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &publisher{}
+	opts := []natsclient.Option{
+		natsclient.Name(cfg.Name),
+		natsclient.ErrorHandler(func(_ *natsclient.Conn, _ *natsclient.Subscription, err error) {
+			p.setErr(err)
+		}),
+	}
+	if cfg.CredentialsFile != "" {
+		opts = append(opts, natsclient.UserCredentials(cfg.CredentialsFile))
+	}
+	if cfg.Username != "" {
+		opts = append(opts, natsclient.UserInfo(cfg.Username, cfg.Password))
+	}
+	if cfg.Token != "" {
+		opts = append(opts, natsclient.Token(cfg.Token))
+	}
+
+	p.conn, err = natsclient.Connect(cfg.URL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS at %s: %w", cfg.URL, err)
+	}
+
+	if cfg.JetStream {
+		p.js, err = jetstream.New(p.conn)
+		if err != nil {
+			p.conn.Close()
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+func decodeConfig(rawConfig map[string]interface{}) (Config, error) {
+	bz, err := json.Marshal(rawConfig)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid NATS publisher config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(bz, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid NATS publisher config: %w", err)
+	}
+
+	if cfg.URL == "" {
+		cfg.URL = natsclient.DefaultURL
+	}
+	if cfg.Name == "" {
+		cfg.Name = DefaultName
+	}
+	if cfg.Password != "" && cfg.Username == "" {
+		return Config{}, errors.New("NATS publisher password requires a username")
+	}
+
+	return cfg, nil
+}
+
+// publisher implements stream.Publisher. With JetStream, the acknowledgements of the messages published since
+// the last flush are tracked in pending, otherwise asynchronous errors reported by the connection are recorded
+// in err.
+type publisher struct {
+	conn *natsclient.Conn
+	js   jetstream.JetStream
+
+	mu      sync.Mutex
+	pending []jetstream.PubAckFuture
+	err     error
+}
+
+// Publish implements stream.Publisher.
+func (p *publisher) Publish(_ context.Context, msg stream.Message) error {
+	m := natsclient.NewMsg(msg.Topic)
+	m.Data = msg.Value
+	for k, v := range msg.Headers {
+		m.Header.Set(k, v)
+	}
+	m.Header.Set(HeaderKey, hex.EncodeToString(msg.Key))
+
+	if p.js == nil {
+		return p.conn.PublishMsg(m)
+	}
+
+	ack, err := p.js.PublishMsgAsync(m)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.pending = append(p.pending, ack)
+	p.mu.Unlock()
+	return nil
+}
+
+// Flush implements stream.Publisher.
+func (p *publisher) Flush(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, flushTimeout)
+		defer cancel()
+	}
+
+	if p.js == nil {
+		if err := p.conn.FlushWithContext(ctx); err != nil {
+			return err
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		err := p.err
+		p.err = nil
+		return err
+	}
+
+	p.mu.Lock()
+	pending := p.pending
+	p.pending = nil
+	p.mu.Unlock()
+
+	for _, ack := range pending {
+		select {
+		case <-ack.Ok():
+		case err := <-ack.Err():
+			return fmt.Errorf("failed to publish message to %s: %w", ack.Msg().Subject, err)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Close implements stream.Publisher.
+func (p *publisher) Close() error {
+	err := p.Flush(context.Background())
+	p.conn.Close()
+	return err
+}
+
+func (p *publisher) setErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err == nil {
+		p.err = err
+	}
+}
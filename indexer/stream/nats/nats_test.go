@@ -0,0 +1,175 @@
+package nats
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	natsclient "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	indexerv1 "cosmossdk.io/api/cosmos/indexer/v1"
+	"cosmossdk.io/indexer/stream"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+)
+
+func startServer(t *testing.T) *server.Server {
+	t.Helper()
+	opts := natsserver.DefaultTestOptions
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	s := natsserver.RunServer(&opts)
+	t.Cleanup(s.Shutdown)
+	return s
+}
+
+func startIndexer(t *testing.T, publisherConfig map[string]interface{}) appdata.Listener {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	res, err := stream.Init(indexer.InitParams{
+		Config: indexer.Config{Type: stream.IndexerType, Config: map[string]interface{}{
+			"publisher":        PublisherName,
+			"publisher_config": publisherConfig,
+			"topic_prefix":     "chain",
+		}},
+		Context: ctx,
+	})
+	require.NoError(t, err)
+	return res.Listener
+}
+
+func sendBlock(listener appdata.Listener) error {
+	if err := listener.StartBlock(appdata.StartBlockData{Height: 3}); err != nil {
+		return err
+	}
+	if err := listener.OnKVPair(appdata.KVPairData{Updates: []appdata.ModuleKVPairUpdate{
+		{ModuleName: "bank", Update: schema.KVPairUpdate{Key: []byte{0x02, 0x01}, Value: []byte{0x05}}},
+	}}); err != nil {
+		return err
+	}
+	if err := listener.OnObjectUpdate(appdata.ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{
+		{TypeName: "supply", Key: "atom", Value: "5"},
+	}}); err != nil {
+		return err
+	}
+	return listener.Commit(appdata.CommitData{})
+}
+
+func TestJetStream(t *testing.T) {
+	s := startServer(t)
+
+	nc, err := natsclient.Connect(s.ClientURL())
+	require.NoError(t, err)
+	defer nc.Close()
+	js, err := jetstream.New(nc)
+	require.NoError(t, err)
+	ctx := context.Background()
+	str, err := js.CreateStream(ctx, jetstream.StreamConfig{Name: "CHAIN", Subjects: []string{"chain.>"}})
+	require.NoError(t, err)
+
+	listener := startIndexer(t, map[string]interface{}{"url": s.ClientURL(), "jetstream": true})
+	require.NoError(t, sendBlock(listener))
+
+	// the block is committed once all of its messages have been persisted by the stream
+	info, err := str.Info(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), info.State.Msgs)
+
+	consumer, err := str.OrderedConsumer(ctx, jetstream.OrderedConsumerConfig{})
+	require.NoError(t, err)
+	batch, err := consumer.Fetch(4, jetstream.FetchMaxWait(time.Second))
+	require.NoError(t, err)
+	var msgs []jetstream.Msg
+	for msg := range batch.Messages() {
+		msgs = append(msgs, msg)
+	}
+	require.NoError(t, batch.Error())
+
+	type expectedMessage struct {
+		subject, key, packetType string
+	}
+	expected := []expectedMessage{
+		{"chain.blocks", hex.EncodeToString([]byte("chain.blocks")), "start_block"},
+		{"chain.bank", "0201", "kv_pairs"},
+		{"chain.bank", "", "object_updates"},
+		{"chain.blocks", hex.EncodeToString([]byte("chain.blocks")), "commit"},
+	}
+	require.Len(t, msgs, len(expected))
+	for i, msg := range msgs {
+		require.Equal(t, expected[i].subject, msg.Subject())
+		require.Equal(t, expected[i].packetType, msg.Headers().Get(stream.HeaderPacketType))
+		require.Equal(t, "3", msg.Headers().Get(stream.HeaderHeight))
+		if expected[i].key != "" {
+			require.Equal(t, expected[i].key, msg.Headers().Get(HeaderKey))
+		}
+	}
+
+	var packet indexerv1.Packet
+	require.NoError(t, proto.Unmarshal(msgs[2].Data(), &packet))
+	updates := packet.GetObjectUpdates()
+	require.Equal(t, "bank", updates.ModuleName)
+	require.Len(t, updates.Updates, 1)
+	require.Equal(t, "supply", updates.Updates[0].TypeName)
+	require.Contains(t, msgs[2].Headers().Get(HeaderKey), hex.EncodeToString([]byte("supply/")))
+}
+
+func TestJetStream_noStream(t *testing.T) {
+	s := startServer(t)
+
+	// with at least once delivery, the block fails if no stream persists its messages
+	listener := startIndexer(t, map[string]interface{}{"url": s.ClientURL(), "jetstream": true})
+	require.ErrorContains(t, sendBlock(listener), "failed to deliver messages of block 3")
+}
+
+func TestCoreNATS(t *testing.T) {
+	s := startServer(t)
+
+	nc, err := natsclient.Connect(s.ClientURL())
+	require.NoError(t, err)
+	defer nc.Close()
+	sub, err := nc.SubscribeSync("chain.>")
+	require.NoError(t, err)
+	require.NoError(t, nc.Flush())
+
+	listener := startIndexer(t, map[string]interface{}{"url": s.ClientURL()})
+	require.NoError(t, sendBlock(listener))
+
+	var packetTypes []string
+	for i := 0; i < 4; i++ {
+		msg, err := sub.NextMsg(time.Second)
+		require.NoError(t, err)
+		packetTypes = append(packetTypes, msg.Header.Get(stream.HeaderPacketType))
+	}
+	require.Equal(t, []string{"start_block", "kv_pairs", "object_updates", "commit"}, packetTypes)
+}
+
+func TestNewPublisher_unreachable(t *testing.T) {
+	_, err := NewPublisher(context.Background(), map[string]interface{}{"url": "nats://127.0.0.1:1"})
+	require.ErrorContains(t, err, "failed to connect to NATS")
+}
+
+func TestDecodeConfig(t *testing.T) {
+	cfg, err := decodeConfig(map[string]interface{}{})
+	require.NoError(t, err)
+	require.Equal(t, Config{URL: natsclient.DefaultURL, Name: DefaultName}, cfg)
+
+	cfg, err = decodeConfig(map[string]interface{}{"url": "nats://nats:4222", "username": "indexer", "password": "secret", "jetstream": true})
+	require.NoError(t, err)
+	require.Equal(t, Config{URL: "nats://nats:4222", Name: DefaultName, Username: "indexer", Password: "secret", JetStream: true}, cfg)
+
+	_, err = decodeConfig(map[string]interface{}{"password": "secret"})
+	require.ErrorContains(t, err, "requires a username")
+
+	_, err = decodeConfig(map[string]interface{}{"jetstream": "yes"})
+	require.ErrorContains(t, err, "invalid NATS publisher config")
+}
@@ -0,0 +1,80 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Message is a message published to a message bus.
+type Message struct {
+	// Topic is the Kafka topic or NATS subject of the message.
+	Topic string
+
+	// Key is the key of the message, which message buses such as Kafka use to assign messages to partitions.
+	// Messages with a kv-pair update have the kv-pair key as key and messages with an object update have the
+	// type name and key of the object as key, so that the updates of each kv-pair and object are delivered in
+	// order. The other messages have the topic as key so that the packets of blocks are delivered in order.
+	Key []byte
+
+	// Value is the serialized app data packet.
+	Value []byte
+
+	// Headers are the headers of the message, see the Header* constants.
+	Headers map[string]string
+}
+
+// Publisher publishes messages to a message bus such as Kafka or NATS. Publishers are implemented with the
+// client library of their message bus in separate modules, such as cosmossdk.io/indexer/stream/nats, or by apps,
+// and registered with RegisterPublisher, so that this package doesn't depend on any client library.
+type Publisher interface {
+	// Publish publishes a message. It may return before the message has been delivered, in which case
+	// delivery errors must be returned by the next call to Flush. Messages must be delivered in the order in
+	// which they were published.
+	Publish(ctx context.Context, msg Message) error
+
+	// Flush blocks until all messages published so far have been delivered and returns the first error
+	// which occurred when delivering them.
+	Flush(ctx context.Context) error
+
+	// Close flushes and closes the publisher.
+	Close() error
+}
+
+// PublisherFactory creates a publisher from the publisher specific config of a stream indexer target.
+type PublisherFactory func(ctx context.Context, config map[string]interface{}) (Publisher, error)
+
+var (
+	publishersMu sync.Mutex
+	publishers   = map[string]PublisherFactory{}
+)
+
+// RegisterPublisher registers a publisher factory under a name, such as "kafka" or "nats", which stream
+// indexer targets select with their publisher config option. It panics if a publisher is registered twice
+// under the same name.
+func RegisterPublisher(name string, factory PublisherFactory) {
+	publishersMu.Lock()
+	defer publishersMu.Unlock()
+
+	if _, ok := publishers[name]; ok {
+		panic(fmt.Sprintf("publisher %q already registered", name))
+	}
+	publishers[name] = factory
+}
+
+func lookupPublisher(name string) (PublisherFactory, error) {
+	publishersMu.Lock()
+	defer publishersMu.Unlock()
+
+	factory, ok := publishers[name]
+	if !ok {
+		names := make([]string, 0, len(publishers))
+		for n := range publishers {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("publisher %q not registered, registered publishers: %v", name, names)
+	}
+	return factory, nil
+}
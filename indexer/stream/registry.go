@@ -0,0 +1,82 @@
+package stream
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"cosmossdk.io/schema"
+)
+
+// SchemaRegistry registers the schemas of modules so that consumers can decode the object updates of the
+// topics of the modules.
+type SchemaRegistry interface {
+	// RegisterModuleSchema registers the schema of a module under a subject and returns the ID of the schema,
+	// which is added to the messages of the module with the HeaderSchemaID header.
+	RegisterModuleSchema(ctx context.Context, subject string, moduleSchema schema.ModuleSchema) (int, error)
+}
+
+// NewSchemaRegistryClient returns a client of a schema registry which implements the Confluent Schema Registry
+// REST API at baseURL. Module schemas are registered in their JSON representation with the JSON schema type.
+func NewSchemaRegistryClient(baseURL string, httpClient *http.Client) SchemaRegistry {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &schemaRegistryClient{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: httpClient}
+}
+
+type schemaRegistryClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// RegisterModuleSchema implements SchemaRegistry. Registering a schema which is already registered under the
+// subject returns the ID of the existing schema.
+func (c *schemaRegistryClient) RegisterModuleSchema(ctx context.Context, subject string, moduleSchema schema.ModuleSchema) (int, error) {
+	schemaJSON, err := json.Marshal(moduleSchema)
+	if err != nil {
+		return 0, err
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"schemaType": "JSON",
+		"schema":     string(schemaJSON),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/subjects/%s/versions", c.baseURL, url.PathEscape(subject)), bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("schema registry returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	var res struct {
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &res); err != nil {
+		return 0, fmt.Errorf("invalid schema registry response: %w", err)
+	}
+	return res.ID, nil
+}
@@ -0,0 +1,405 @@
+// Package stream implements the "stream" indexer type which publishes app data packets to a message bus such as
+// Kafka or NATS, so that downstream systems can consume chain data without connecting to the node.
+//
+// The packets of each module are published to the topic <topic_prefix>.<module> and the packets of blocks,
+// such as transactions, events which don't belong to a module and commits, to <topic_prefix>.blocks. Packets
+// are serialized as cosmos.indexer.v1.Packet messages, in their protobuf or JSON encoding.
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	indexerv1 "cosmossdk.io/api/cosmos/indexer/v1"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/schema/logutil"
+
+	"github.com/cosmos/cosmos-sdk/codec/schemaproto"
+)
+
+// IndexerType is the indexer type of stream indexer targets.
+const IndexerType = "stream"
+
+func init() {
+	indexer.Register(IndexerType, Init)
+}
+
+// Config is the indexer specific config of a stream indexer target.
+type Config struct {
+	// Publisher is the name of the publisher, which must have been registered with RegisterPublisher.
+	Publisher string `json:"publisher"`
+
+	// PublisherConfig is passed to the factory of the publisher.
+	PublisherConfig map[string]interface{} `json:"publisher_config"`
+
+	// TopicPrefix is the prefix of the topics to which packets are published. It defaults to
+	// DefaultTopicPrefix.
+	TopicPrefix string `json:"topic_prefix"`
+
+	// Format is the encoding of the packets, either FormatProto or FormatJSON. It defaults to FormatProto.
+	Format string `json:"format"`
+
+	// Delivery is the delivery guarantee, either DeliveryAtLeastOnce or DeliveryAtMostOnce. It defaults to
+	// DeliveryAtLeastOnce.
+	Delivery string `json:"delivery"`
+
+	// SchemaRegistryURL is the URL of a schema registry implementing the Confluent Schema Registry REST API
+	// in which the schemas of modules are registered. If it is empty, schemas are not registered.
+	SchemaRegistryURL string `json:"schema_registry_url"`
+}
+
+const (
+	// DefaultTopicPrefix is the default value of Config.TopicPrefix.
+	DefaultTopicPrefix = "cosmos"
+
+	// BlocksTopic is the topic suffix of the packets which don't belong to a module.
+	BlocksTopic = "blocks"
+
+	// FormatProto encodes packets as protobuf.
+	FormatProto = "proto"
+
+	// FormatJSON encodes packets as protobuf JSON.
+	FormatJSON = "json"
+
+	// DeliveryAtLeastOnce waits for all messages of a block to be delivered when it is committed and fails
+	// the block if any of them couldn't be delivered.
+	DeliveryAtLeastOnce = "at_least_once"
+
+	// DeliveryAtMostOnce doesn't wait for messages to be delivered and only logs errors when publishing
+	// messages, so that a message bus outage doesn't halt the node.
+	DeliveryAtMostOnce = "at_most_once"
+)
+
+const (
+	// HeaderPacketType is the message header with the type of the packet, which is the name of the field of
+	// the packet in the cosmos.indexer.v1.Packet oneof, such as "start_block" or "object_updates".
+	HeaderPacketType = "packet-type"
+
+	// HeaderHeight is the message header with the height of the block of the packet.
+	HeaderHeight = "height"
+
+	// HeaderContentType is the message header with the content type of the message value.
+	HeaderContentType = "content-type"
+
+	// HeaderSchemaID is the message header with the ID of the module schema in the schema registry.
+	HeaderSchemaID = "schema-id"
+)
+
+// Init initializes a stream indexer target. It is registered as the indexer type "stream".
+func Init(params indexer.InitParams) (indexer.InitResult, error) {
+	cfg, err := decodeConfig(params.Config.Config)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	factory, err := lookupPublisher(cfg.Publisher)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	ctx := params.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = logutil.NoopLogger{}
+	}
+
+	publisher, err := factory(ctx, cfg.PublisherConfig)
+	if err != nil {
+		return indexer.InitResult{}, fmt.Errorf("failed to create %s publisher: %w", cfg.Publisher, err)
+	}
+
+	var registry SchemaRegistry
+	if cfg.SchemaRegistryURL != "" {
+		registry = NewSchemaRegistryClient(cfg.SchemaRegistryURL, nil)
+	}
+
+	s := newSink(ctx, publisher, registry, cfg, logger)
+
+	if params.DoneWaitGroup != nil {
+		params.DoneWaitGroup.Add(1)
+	}
+	go func() {
+		<-ctx.Done()
+		s.close()
+		if params.DoneWaitGroup != nil {
+			params.DoneWaitGroup.Done()
+		}
+	}()
+
+	return indexer.InitResult{
+		Listener:           s.listener(),
+		LastBlockPersisted: -1,
+	}, nil
+}
+
+func decodeConfig(rawConfig map[string]interface{}) (Config, error) {
+	bz, err := json.Marshal(rawConfig)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid stream indexer config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(bz, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid stream indexer config: %w", err)
+	}
+
+	if cfg.Publisher == "" {
+		return Config{}, fmt.Errorf("stream indexer publisher is required")
+	}
+	if cfg.TopicPrefix == "" {
+		cfg.TopicPrefix = DefaultTopicPrefix
+	}
+	switch cfg.Format {
+	case "":
+		cfg.Format = FormatProto
+	case FormatProto, FormatJSON:
+	default:
+		return Config{}, fmt.Errorf("invalid stream indexer format %q, expected %q or %q", cfg.Format, FormatProto, FormatJSON)
+	}
+	switch cfg.Delivery {
+	case "":
+		cfg.Delivery = DeliveryAtLeastOnce
+	case DeliveryAtLeastOnce, DeliveryAtMostOnce:
+	default:
+		return Config{}, fmt.Errorf("invalid stream indexer delivery %q, expected %q or %q", cfg.Delivery, DeliveryAtLeastOnce, DeliveryAtMostOnce)
+	}
+
+	return cfg, nil
+}
+
+// sink publishes packets with a publisher. All fields are guarded by mu so that the publisher isn't closed
+// while packets are published.
+type sink struct {
+	ctx       context.Context
+	publisher Publisher
+	registry  SchemaRegistry
+	logger    logutil.Logger
+	cfg       Config
+
+	mu     sync.Mutex
+	closed bool
+	height uint64
+	// schemaIDs are the IDs of the module schemas in the schema registry
+	schemaIDs map[string]int
+}
+
+func newSink(ctx context.Context, publisher Publisher, registry SchemaRegistry, cfg Config, logger logutil.Logger) *sink {
+	return &sink{
+		ctx:       ctx,
+		publisher: publisher,
+		registry:  registry,
+		logger:    logger,
+		cfg:       cfg,
+		schemaIDs: map[string]int{},
+	}
+}
+
+func (s *sink) listener() appdata.Listener {
+	return appdata.Listener{
+		InitializeModuleData: s.initializeModuleData,
+		StartBlock: func(data appdata.StartBlockData) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			s.height = data.Height
+			return s.publish("", nil, data)
+		},
+		OnTx: func(data appdata.TxData) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return s.publish("", nil, data)
+		},
+		OnEvent: func(data appdata.EventData) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return s.publish(data.ModuleName, nil, data)
+		},
+		OnKVPair: func(data appdata.KVPairData) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			// the updates are split by module so that they are published to the topics of their modules, and
+			// each update is published in its own message keyed by the kv-pair key
+			var order []string
+			byModule := map[string][]appdata.ModuleKVPairUpdate{}
+			for _, update := range data.Updates {
+				if _, ok := byModule[update.ModuleName]; !ok {
+					order = append(order, update.ModuleName)
+				}
+				byModule[update.ModuleName] = append(byModule[update.ModuleName], update)
+			}
+			for _, moduleName := range order {
+				for _, update := range byModule[moduleName] {
+					packet := appdata.KVPairData{Updates: []appdata.ModuleKVPairUpdate{update}}
+					if err := s.publish(moduleName, update.Update.Key, packet); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+		OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			// each update is published in its own message keyed by the object key
+			for _, update := range data.Updates {
+				key, err := objectKey(update)
+				if err != nil {
+					return err
+				}
+				packet := appdata.ObjectUpdateData{
+					ModuleName: data.ModuleName,
+					Updates:    []schema.ObjectUpdate{update},
+					SourceKey:  data.SourceKey,
+				}
+				if err := s.publish(data.ModuleName, key, packet); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Commit: func(data appdata.CommitData) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			if err := s.publish("", nil, data); err != nil {
+				return err
+			}
+			if s.cfg.Delivery != DeliveryAtLeastOnce {
+				return nil
+			}
+			// the block is only committed once all of its messages have been delivered
+			if err := s.publisher.Flush(s.ctx); err != nil {
+				return fmt.Errorf("failed to deliver messages of block %d: %w", s.height, err)
+			}
+			return nil
+		},
+	}
+}
+
+func (s *sink) initializeModuleData(data appdata.ModuleInitializationData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.registry != nil {
+		subject := fmt.Sprintf("%s-value", s.topic(data.ModuleName))
+		id, err := s.registry.RegisterModuleSchema(s.ctx, subject, data.Schema)
+		if err != nil {
+			return fmt.Errorf("failed to register schema of module %s: %w", data.ModuleName, err)
+		}
+		s.schemaIDs[data.ModuleName] = id
+	}
+
+	return s.publish(data.ModuleName, nil, data)
+}
+
+// topic returns the topic of the packets of a module, or of the packets which don't belong to a module if
+// moduleName is empty.
+func (s *sink) topic(moduleName string) string {
+	if moduleName == "" {
+		moduleName = BlocksTopic
+	}
+	return fmt.Sprintf("%s.%s", s.cfg.TopicPrefix, moduleName)
+}
+
+// publish publishes a packet to the topic of a module with the given message key, or with the topic as key
+// if key is nil. With at most once delivery, errors are logged instead of being returned.
+func (s *sink) publish(moduleName string, key []byte, packet appdata.Packet) error {
+	if s.closed {
+		return fmt.Errorf("stream indexer is shut down")
+	}
+
+	msg, err := s.message(moduleName, key, packet)
+	if err != nil {
+		return err
+	}
+
+	err = s.publisher.Publish(s.ctx, msg)
+	if err != nil && s.cfg.Delivery == DeliveryAtMostOnce {
+		s.logger.Warn("failed to publish packet", "topic", msg.Topic, "height", s.height, "error", err)
+		return nil
+	}
+	return err
+}
+
+func (s *sink) message(moduleName string, key []byte, packet appdata.Packet) (Message, error) {
+	p, err := schemaproto.PacketToProto(packet)
+	if err != nil {
+		return Message{}, err
+	}
+
+	var value []byte
+	var contentType string
+	if s.cfg.Format == FormatJSON {
+		value, err = protojson.Marshal(p)
+		contentType = "application/json"
+	} else {
+		value, err = proto.Marshal(p)
+		contentType = "application/protobuf"
+	}
+	if err != nil {
+		return Message{}, err
+	}
+
+	topic := s.topic(moduleName)
+	headers := map[string]string{
+		HeaderPacketType:  packetType(p),
+		HeaderHeight:      strconv.FormatUint(s.height, 10),
+		HeaderContentType: contentType,
+	}
+	if id, ok := s.schemaIDs[moduleName]; ok {
+		headers[HeaderSchemaID] = strconv.Itoa(id)
+	}
+
+	if key == nil {
+		key = []byte(topic)
+	}
+
+	return Message{Topic: topic, Key: key, Value: value, Headers: headers}, nil
+}
+
+// objectKey returns the message key of an object update, which is the type name of the object followed by
+// a slash and the deterministic protobuf encoding of the object key as a cosmos.indexer.v1.Value.
+func objectKey(update schema.ObjectUpdate) ([]byte, error) {
+	key, err := schemaproto.ValueToProto(update.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key of %q update: %w", update.TypeName, err)
+	}
+	bz, err := proto.MarshalOptions{Deterministic: true}.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(update.TypeName+"/"), bz...), nil
+}
+
+// packetType returns the name of the field of the packet in the cosmos.indexer.v1.Packet oneof.
+func packetType(p *indexerv1.Packet) string {
+	msg := p.ProtoReflect()
+	field := msg.WhichOneof(msg.Descriptor().Oneofs().ByName("packet"))
+	if field == nil {
+		return ""
+	}
+	return string(field.Name())
+}
+
+func (s *sink) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	if err := s.publisher.Close(); err != nil {
+		s.logger.Error("failed to close stream indexer publisher", "error", err)
+	}
+}
@@ -0,0 +1,296 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	indexerv1 "cosmossdk.io/api/cosmos/indexer/v1"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/schema/logutil"
+
+	"github.com/cosmos/cosmos-sdk/codec/schemaproto"
+)
+
+type testPublisher struct {
+	messages   []Message
+	publishErr error
+	flushErr   error
+	flushes    int
+	closed     bool
+}
+
+func (p *testPublisher) Publish(_ context.Context, msg Message) error {
+	if p.publishErr != nil {
+		return p.publishErr
+	}
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func (p *testPublisher) Flush(context.Context) error {
+	p.flushes++
+	return p.flushErr
+}
+
+func (p *testPublisher) Close() error {
+	p.closed = true
+	return nil
+}
+
+type testRegistry struct {
+	subjects []string
+}
+
+func (r *testRegistry) RegisterModuleSchema(_ context.Context, subject string, _ schema.ModuleSchema) (int, error) {
+	r.subjects = append(r.subjects, subject)
+	return len(r.subjects), nil
+}
+
+func testSink(t *testing.T, cfg map[string]interface{}, registry SchemaRegistry) (*testPublisher, *sink) {
+	t.Helper()
+	cfg["publisher"] = "test"
+	c, err := decodeConfig(cfg)
+	require.NoError(t, err)
+	p := &testPublisher{}
+	return p, newSink(context.Background(), p, registry, c, logutil.NoopLogger{})
+}
+
+func sendTestBlock(listener appdata.Listener) error {
+	if err := listener.StartBlock(appdata.StartBlockData{Height: 7}); err != nil {
+		return err
+	}
+	if err := listener.OnTx(appdata.TxData{TxIndex: 0, Bytes: func() ([]byte, error) { return []byte("tx"), nil }}); err != nil {
+		return err
+	}
+	if err := listener.OnKVPair(appdata.KVPairData{Updates: []appdata.ModuleKVPairUpdate{
+		{ModuleName: "bank", Update: schema.KVPairUpdate{Key: []byte("a"), Value: []byte("1")}},
+		{ModuleName: "staking", Update: schema.KVPairUpdate{Key: []byte("b"), Value: []byte("2")}},
+		{ModuleName: "bank", Update: schema.KVPairUpdate{Key: []byte("c"), Delete: true}},
+	}}); err != nil {
+		return err
+	}
+	if err := listener.OnObjectUpdate(appdata.ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{
+		{TypeName: "balances", Key: "a", Value: uint64(1)},
+	}}); err != nil {
+		return err
+	}
+	return listener.Commit(appdata.CommitData{})
+}
+
+func TestStream(t *testing.T) {
+	registry := &testRegistry{}
+	p, s := testSink(t, map[string]interface{}{"topic_prefix": "chain"}, registry)
+	listener := s.listener()
+
+	require.NoError(t, listener.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "bank"}))
+	require.NoError(t, sendTestBlock(listener))
+	require.Equal(t, []string{"chain.bank-value"}, registry.subjects)
+	require.Equal(t, 1, p.flushes)
+
+	balanceKey, err := objectKey(schema.ObjectUpdate{TypeName: "balances", Key: "a"})
+	require.NoError(t, err)
+
+	type expectedMessage struct {
+		topic, key, packetType, height, schemaID string
+	}
+	expected := []expectedMessage{
+		{"chain.bank", "chain.bank", "module_initialization", "0", "1"},
+		{"chain.blocks", "chain.blocks", "start_block", "7", ""},
+		{"chain.blocks", "chain.blocks", "tx", "7", ""},
+		{"chain.bank", "a", "kv_pairs", "7", "1"},
+		{"chain.bank", "c", "kv_pairs", "7", "1"},
+		{"chain.staking", "b", "kv_pairs", "7", ""},
+		{"chain.bank", string(balanceKey), "object_updates", "7", "1"},
+		{"chain.blocks", "chain.blocks", "commit", "7", ""},
+	}
+	require.Len(t, p.messages, len(expected))
+	for i, msg := range p.messages {
+		require.Equal(t, expected[i].topic, msg.Topic)
+		require.Equal(t, []byte(expected[i].key), msg.Key)
+		require.Equal(t, expected[i].packetType, msg.Headers[HeaderPacketType])
+		require.Equal(t, expected[i].height, msg.Headers[HeaderHeight])
+		require.Equal(t, expected[i].schemaID, msg.Headers[HeaderSchemaID])
+		require.Equal(t, "application/protobuf", msg.Headers[HeaderContentType])
+	}
+
+	var kvPairs indexerv1.Packet
+	require.NoError(t, proto.Unmarshal(p.messages[4].Value, &kvPairs))
+	packet, err := schemaproto.PacketFromProto(&kvPairs)
+	require.NoError(t, err)
+	require.Equal(t, appdata.KVPairData{Updates: []appdata.ModuleKVPairUpdate{
+		{ModuleName: "bank", Update: schema.KVPairUpdate{Key: []byte("c"), Delete: true}},
+	}}, packet)
+
+	s.close()
+	require.True(t, p.closed)
+	require.ErrorContains(t, sendTestBlock(listener), "shut down")
+}
+
+func TestStream_json(t *testing.T) {
+	p, s := testSink(t, map[string]interface{}{"format": "json"}, nil)
+	require.NoError(t, sendTestBlock(s.listener()))
+
+	msg := p.messages[0]
+	require.Equal(t, "cosmos.blocks", msg.Topic)
+	require.Equal(t, "application/json", msg.Headers[HeaderContentType])
+
+	var startBlock indexerv1.Packet
+	require.NoError(t, protojson.Unmarshal(msg.Value, &startBlock))
+	require.Equal(t, uint64(7), startBlock.GetStartBlock().Height)
+}
+
+func TestStream_keys(t *testing.T) {
+	p, s := testSink(t, map[string]interface{}{}, nil)
+	require.NoError(t, s.listener().OnObjectUpdate(appdata.ObjectUpdateData{
+		ModuleName: "bank",
+		Updates: []schema.ObjectUpdate{
+			{TypeName: "balances", Key: []interface{}{"addr1", "atom"}, Value: uint64(1)},
+			{TypeName: "balances", Key: []interface{}{"addr2", "atom"}, Value: uint64(2)},
+			{TypeName: "supply", Key: "atom", Value: uint64(3)},
+		},
+	}))
+
+	// each object update is published in its own message keyed by its type name and object key
+	require.Len(t, p.messages, 3)
+	keys := map[string]bool{}
+	for i, msg := range p.messages {
+		var packet indexerv1.Packet
+		require.NoError(t, proto.Unmarshal(msg.Value, &packet))
+		require.Len(t, packet.GetObjectUpdates().Updates, 1)
+
+		update, err := schemaproto.PacketFromProto(&packet)
+		require.NoError(t, err)
+		key, err := objectKey(update.(appdata.ObjectUpdateData).Updates[0])
+		require.NoError(t, err)
+		require.Equal(t, key, msg.Key, i)
+		keys[string(msg.Key)] = true
+	}
+	require.Len(t, keys, 3)
+
+	_, err := objectKey(schema.ObjectUpdate{TypeName: "balances", Key: struct{}{}})
+	require.ErrorContains(t, err, "invalid key")
+}
+
+func TestStream_delivery(t *testing.T) {
+	deliveryErr := errors.New("broker unavailable")
+
+	// with at least once delivery, the block fails if its messages can't be delivered
+	p, s := testSink(t, map[string]interface{}{}, nil)
+	p.flushErr = deliveryErr
+	require.ErrorIs(t, sendTestBlock(s.listener()), deliveryErr)
+
+	p, s = testSink(t, map[string]interface{}{}, nil)
+	p.publishErr = deliveryErr
+	require.ErrorIs(t, sendTestBlock(s.listener()), deliveryErr)
+
+	// with at most once delivery, errors are ignored and messages aren't flushed
+	p, s = testSink(t, map[string]interface{}{"delivery": "at_most_once"}, nil)
+	p.publishErr = deliveryErr
+	require.NoError(t, sendTestBlock(s.listener()))
+	require.Equal(t, 0, p.flushes)
+}
+
+func TestSchemaRegistryClient(t *testing.T) {
+	moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:      "balances",
+		KeyFields: []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+	}})
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/subjects/cosmos.bank-value/versions", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var req map[string]string
+		require.NoError(t, json.Unmarshal(body, &req))
+		require.Equal(t, "JSON", req["schemaType"])
+		var registered schema.ModuleSchema
+		require.NoError(t, json.Unmarshal([]byte(req["schema"]), &registered))
+		require.Equal(t, moduleSchema, registered)
+
+		_, _ = w.Write([]byte(`{"id":42}`))
+	}))
+	defer srv.Close()
+
+	id, err := NewSchemaRegistryClient(srv.URL+"/", nil).RegisterModuleSchema(context.Background(), "cosmos.bank-value", moduleSchema)
+	require.NoError(t, err)
+	require.Equal(t, 42, id)
+
+	errSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error_code":409}`, http.StatusConflict)
+	}))
+	defer errSrv.Close()
+
+	_, err = NewSchemaRegistryClient(errSrv.URL, nil).RegisterModuleSchema(context.Background(), "cosmos.bank-value", moduleSchema)
+	require.ErrorContains(t, err, "409 Conflict")
+}
+
+func TestInit(t *testing.T) {
+	p := &testPublisher{}
+	var publisherConfig map[string]interface{}
+	RegisterPublisher("test-init", func(_ context.Context, config map[string]interface{}) (Publisher, error) {
+		publisherConfig = config
+		return p, nil
+	})
+	require.Panics(t, func() {
+		RegisterPublisher("test-init", nil)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	res, err := Init(indexer.InitParams{
+		Context: ctx,
+		Config: indexer.Config{Type: IndexerType, Config: map[string]interface{}{
+			"publisher":        "test-init",
+			"publisher_config": map[string]interface{}{"brokers": "localhost:9092"},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), res.LastBlockPersisted)
+	require.Equal(t, map[string]interface{}{"brokers": "localhost:9092"}, publisherConfig)
+	require.NoError(t, sendTestBlock(res.Listener))
+	require.Len(t, p.messages, 7)
+
+	cancel()
+	require.Eventually(t, func() bool {
+		return res.Listener.Commit(appdata.CommitData{}) != nil
+	}, time.Second, time.Millisecond)
+	require.True(t, p.closed)
+
+	_, err = Init(indexer.InitParams{Config: indexer.Config{Type: IndexerType, Config: map[string]interface{}{
+		"publisher": "kafka",
+	}}})
+	require.ErrorContains(t, err, `publisher "kafka" not registered`)
+}
+
+func TestDecodeConfig(t *testing.T) {
+	cfg, err := decodeConfig(map[string]interface{}{"publisher": "nats"})
+	require.NoError(t, err)
+	require.Equal(t, Config{
+		Publisher:   "nats",
+		TopicPrefix: DefaultTopicPrefix,
+		Format:      FormatProto,
+		Delivery:    DeliveryAtLeastOnce,
+	}, cfg)
+
+	_, err = decodeConfig(map[string]interface{}{})
+	require.ErrorContains(t, err, "publisher is required")
+
+	_, err = decodeConfig(map[string]interface{}{"publisher": "nats", "format": "avro"})
+	require.ErrorContains(t, err, `invalid stream indexer format "avro"`)
+
+	_, err = decodeConfig(map[string]interface{}{"publisher": "nats", "delivery": "exactly_once"})
+	require.ErrorContains(t, err, `invalid stream indexer delivery "exactly_once"`)
+}
@@ -7,6 +7,7 @@ require (
 	cosmossdk.io/core v0.12.1-0.20231114100755-569e3ff6a0d7
 	cosmossdk.io/depinject v1.0.0
 	cosmossdk.io/errors v1.0.1
+	cosmossdk.io/schema v0.1.1
 	github.com/cosmos/cosmos-db v1.0.2
 	github.com/cosmos/cosmos-proto v1.0.0-beta.5
 	github.com/golang/mock v1.6.0
@@ -68,4 +69,7 @@ require (
 	sigs.k8s.io/yaml v1.4.0 // indirect
 )
 
-replace cosmossdk.io/core => ../core
+replace (
+	cosmossdk.io/core => ../core
+	cosmossdk.io/schema => ../schema
+)
@@ -19,6 +19,7 @@ import (
 	"cosmossdk.io/orm/encoding/ormkv"
 	"cosmossdk.io/orm/model/ormtable"
 	"cosmossdk.io/orm/types/ormerrors"
+	"cosmossdk.io/schema"
 )
 
 // ModuleDB defines the ORM database type to be used by modules.
@@ -41,6 +42,14 @@ type ModuleDB interface {
 	//  }
 	GenesisHandler() appmodule.HasGenesisAuto
 
+	// HasModuleCodec describes the tables of the module DB as a module schema and decodes
+	// their kv-store entries so that modules using the ORM can be indexed. Ex:
+	//
+	//  func (am AppModule) ModuleCodec() (schema.ModuleCodec, error) {
+	//    return am.keeper.db.ModuleCodec()
+	//  }
+	schema.HasModuleCodec
+
 	private()
 }
 
@@ -28,6 +28,7 @@ import (
 	"cosmossdk.io/orm/testing/ormmocks"
 	"cosmossdk.io/orm/testing/ormtest"
 	"cosmossdk.io/orm/types/ormerrors"
+	"cosmossdk.io/schema"
 )
 
 // These tests use a simulated bank keeper. Addresses and balances use
@@ -413,3 +414,54 @@ func TestAppConfigModule(t *testing.T) {
 
 	runSimpleBankTests(t, k, context.Background())
 }
+
+func TestModuleCodec(t *testing.T) {
+	db, err := ormdb.NewModuleDB(TestBankSchema, ormdb.ModuleDBOptions{})
+	assert.NilError(t, err)
+	cdc, err := db.ModuleCodec()
+	assert.NilError(t, err)
+
+	typ, ok := cdc.Schema.LookupType("Balance")
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, schema.ObjectType{
+		Name: "Balance",
+		KeyFields: []schema.Field{
+			{Name: "address", Kind: schema.StringKind},
+			{Name: "denom", Kind: schema.StringKind},
+		},
+		ValueFields: []schema.Field{{Name: "amount", Kind: schema.Uint64Kind}},
+	}, typ)
+
+	backend := ormtest.NewMemoryBackend()
+	ctx := ormtable.WrapContextDefault(backend)
+	k, err := NewKeeper(db)
+	assert.NilError(t, err)
+	assert.NilError(t, k.Mint(ctx, "bob", "foo", 10))
+
+	var updates []schema.ObjectUpdate
+	it, err := backend.CommitmentStore().Iterator(nil, nil)
+	assert.NilError(t, err)
+	for ; it.Valid(); it.Next() {
+		res, err := cdc.KVDecoder(schema.KVPairUpdate{Key: it.Key(), Value: it.Value()})
+		assert.NilError(t, err)
+		for _, update := range res {
+			assert.NilError(t, cdc.Schema.ValidateObjectUpdate(update))
+		}
+		updates = append(updates, res...)
+	}
+	assert.NilError(t, it.Close())
+	assert.DeepEqual(t, []schema.ObjectUpdate{
+		{TypeName: "Balance", Key: []interface{}{"bob", "foo"}, Value: uint64(10)},
+		{TypeName: "Supply", Key: "foo", Value: uint64(10)},
+	}, updates)
+
+	// index entries are derived from primary key entries
+	it, err = backend.IndexStore().Iterator(nil, nil)
+	assert.NilError(t, err)
+	for ; it.Valid(); it.Next() {
+		res, err := cdc.KVDecoder(schema.KVPairUpdate{Key: it.Key(), Value: it.Value()})
+		assert.NilError(t, err)
+		assert.Assert(t, res == nil)
+	}
+	assert.NilError(t, it.Close())
+}
@@ -0,0 +1,481 @@
+package ormdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ormv1 "cosmossdk.io/api/cosmos/orm/v1"
+	"cosmossdk.io/orm/encoding/encodeutil"
+	"cosmossdk.io/orm/encoding/ormkv"
+	"cosmossdk.io/orm/internal/fieldnames"
+	"cosmossdk.io/orm/model/ormtable"
+	"cosmossdk.io/orm/types/ormerrors"
+	"cosmossdk.io/schema"
+)
+
+// AutoIncrementMetadataKey is the field metadata key which marks the primary key field of
+// auto-increment tables in the object types returned by ModuleDB.ModuleCodec.
+const AutoIncrementMetadataKey = "cosmos.orm.auto_increment"
+
+var (
+	timestampFullName = (&timestamppb.Timestamp{}).ProtoReflect().Descriptor().FullName()
+	durationFullName  = (&durationpb.Duration{}).ProtoReflect().Descriptor().FullName()
+)
+
+// ModuleCodec implements schema.HasModuleCodec. Every table and singleton is described by an
+// object type named after its message. The primary key fields of tables are the key fields of the
+// object type and all other message fields are value fields. The message's indexes are described
+// by index descriptors on their value fields and unique indexes which only reference value fields
+// also become unique constraints. The KVDecoder decodes primary key entries to object updates and
+// ignores index and sequence entries as they can be derived from the primary key entries.
+func (m moduleDB) ModuleCodec() (schema.ModuleCodec, error) {
+	decoder := moduleSchemaDecoder{db: m, tables: map[protoreflect.FullName]*tableSchemaCodec{}}
+	var objectTypes []schema.ObjectType
+	names := map[string]bool{}
+	for _, file := range m.filesByID {
+		for _, table := range file.tablesByID {
+			cdc, err := newTableSchemaCodec(table)
+			if err != nil {
+				return schema.ModuleCodec{}, err
+			}
+
+			// NewModuleSchema doesn't detect duplicate names, so messages with the same name in
+			// different packages must be caught here
+			fullName := table.MessageType().Descriptor().FullName()
+			if names[cdc.objectType.Name] {
+				return schema.ModuleCodec{}, ormerrors.InvalidTableDefinition.Wrapf("duplicate object type name %s for %s", cdc.objectType.Name, fullName)
+			}
+			names[cdc.objectType.Name] = true
+			decoder.tables[fullName] = cdc
+			objectTypes = append(objectTypes, cdc.objectType)
+		}
+	}
+
+	moduleSchema, err := schema.NewModuleSchema(objectTypes)
+	if err != nil {
+		return schema.ModuleCodec{}, err
+	}
+
+	return schema.ModuleCodec{
+		Schema:    moduleSchema,
+		KVDecoder: decoder.decodeKV,
+	}, nil
+}
+
+// moduleSchemaDecoder decodes the kv-pairs of a module DB to object updates.
+type moduleSchemaDecoder struct {
+	db     moduleDB
+	tables map[protoreflect.FullName]*tableSchemaCodec
+}
+
+func (d moduleSchemaDecoder) decodeKV(update schema.KVPairUpdate) ([]schema.ObjectUpdate, error) {
+	r := bytes.NewReader(update.Key)
+	if err := encodeutil.SkipPrefix(r, d.db.prefix); err != nil {
+		// not an ORM entry
+		return nil, nil
+	}
+
+	fileID, err := binary.ReadUvarint(r)
+	if err != nil || fileID > math.MaxUint32 {
+		return nil, nil
+	}
+	file, ok := d.db.filesByID[uint32(fileID)]
+	if !ok {
+		return nil, nil
+	}
+
+	tableID, err := binary.ReadUvarint(r)
+	if err != nil || tableID > math.MaxUint32 {
+		return nil, nil
+	}
+	table, ok := file.tablesByID[uint32(tableID)]
+	if !ok {
+		return nil, nil
+	}
+	cdc := d.tables[table.MessageType().Descriptor().FullName()]
+
+	// singletons are stored under the table prefix
+	if cdc.singleton {
+		if r.Len() != 0 {
+			return nil, nil
+		}
+		if update.Delete {
+			return []schema.ObjectUpdate{{TypeName: cdc.objectType.Name, Delete: true}}, nil
+		}
+
+		msg := table.MessageType().New().Interface()
+		if err := proto.Unmarshal(update.Value, msg); err != nil {
+			return nil, err
+		}
+		return cdc.objectUpdate(nil, msg)
+	}
+
+	entry, err := table.DecodeEntry(update.Key, update.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	pkEntry, ok := entry.(*ormkv.PrimaryKeyEntry)
+	if !ok {
+		return nil, nil
+	}
+
+	if update.Delete {
+		key, err := cdc.keyValue(pkEntry.Key)
+		if err != nil {
+			return nil, err
+		}
+		return []schema.ObjectUpdate{{TypeName: cdc.objectType.Name, Key: key, Delete: true}}, nil
+	}
+
+	return cdc.objectUpdate(pkEntry.Key, pkEntry.Value)
+}
+
+// tableSchemaCodec describes a table as an object type and converts its entries to object updates.
+type tableSchemaCodec struct {
+	objectType  schema.ObjectType
+	singleton   bool
+	keyFields   []protoreflect.FieldDescriptor
+	valueFields []protoreflect.FieldDescriptor
+}
+
+func newTableSchemaCodec(table ormtable.Table) (*tableSchemaCodec, error) {
+	desc := table.MessageType().Descriptor()
+	res := &tableSchemaCodec{
+		objectType: schema.ObjectType{Name: string(desc.Name())},
+	}
+
+	tableDesc := proto.GetExtension(desc.Options(), ormv1.E_Table).(*ormv1.TableDescriptor)
+	if tableDesc == nil {
+		res.singleton = true
+	}
+
+	isKey := map[protoreflect.Name]bool{}
+	if tableDesc != nil {
+		for _, name := range fieldnames.CommaSeparatedFieldNames(tableDesc.PrimaryKey.Fields).Names() {
+			fd := desc.Fields().ByName(name)
+			if fd == nil {
+				return nil, ormerrors.FieldNotFound.Wrapf("%s on message %s", name, desc.FullName())
+			}
+
+			field, err := schemaField(fd)
+			if err != nil {
+				return nil, err
+			}
+			field.Nullable = false
+			if tableDesc.PrimaryKey.AutoIncrement {
+				field.Metadata = map[string]string{AutoIncrementMetadataKey: "true"}
+			}
+
+			res.objectType.KeyFields = append(res.objectType.KeyFields, field)
+			res.keyFields = append(res.keyFields, fd)
+			isKey[name] = true
+		}
+	}
+
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if isKey[fd.Name()] {
+			continue
+		}
+
+		field, err := schemaField(fd)
+		if err != nil {
+			return nil, err
+		}
+		res.objectType.ValueFields = append(res.objectType.ValueFields, field)
+		res.valueFields = append(res.valueFields, fd)
+	}
+
+	if tableDesc != nil {
+		for _, index := range tableDesc.Index {
+			res.addIndex(fieldnames.CommaSeparatedFieldNames(index.Fields).Names(), index.Unique, isKey)
+		}
+	}
+
+	return res, nil
+}
+
+// addIndex describes an ORM index by an index descriptor on its value fields as schema indexes
+// can't reference key fields. Unique indexes are only described by a unique constraint if they
+// don't reference key fields as the uniqueness of a subset of their fields isn't guaranteed.
+func (t *tableSchemaCodec) addIndex(names []protoreflect.Name, unique bool, isKey map[protoreflect.Name]bool) {
+	var fields []schema.IndexField
+	var valueFields []string
+	for _, name := range names {
+		if isKey[name] {
+			continue
+		}
+		fields = append(fields, schema.IndexField{Name: string(name)})
+		valueFields = append(valueFields, string(name))
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	// several ORM indexes may only differ by their key fields
+	name := strings.Join(valueFields, "_")
+	if !t.hasIndex(name) {
+		t.objectType.Indexes = append(t.objectType.Indexes, schema.IndexDescriptor{
+			Name:   name,
+			Fields: fields,
+		})
+	}
+	if unique && len(valueFields) == len(names) {
+		t.objectType.UniqueConstraints = append(t.objectType.UniqueConstraints, valueFields)
+	}
+}
+
+func (t *tableSchemaCodec) hasIndex(name string) bool {
+	for _, index := range t.objectType.Indexes {
+		if index.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *tableSchemaCodec) keyValue(key []protoreflect.Value) (interface{}, error) {
+	if len(key) != len(t.keyFields) {
+		return nil, ormerrors.UnexpectedDecodePrefix.Wrapf("expected %d key values, got %d", len(t.keyFields), len(key))
+	}
+
+	values := make([]interface{}, len(key))
+	for i, fd := range t.keyFields {
+		if !key[i].IsValid() {
+			return nil, fmt.Errorf("missing value for key field %s", fd.Name())
+		}
+
+		value, err := schemaValue(fd, key[i])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	return singleOrSlice(values), nil
+}
+
+func (t *tableSchemaCodec) objectUpdate(key []protoreflect.Value, msg proto.Message) ([]schema.ObjectUpdate, error) {
+	keyValue, err := t.keyValue(key)
+	if err != nil {
+		return nil, err
+	}
+
+	refl := msg.ProtoReflect()
+	values := make([]interface{}, len(t.valueFields))
+	for i, fd := range t.valueFields {
+		if fd.HasPresence() && !refl.Has(fd) {
+			continue
+		}
+
+		values[i], err = schemaValue(fd, refl.Get(fd))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return []schema.ObjectUpdate{{
+		TypeName: t.objectType.Name,
+		Key:      keyValue,
+		Value:    singleOrSlice(values),
+	}}, nil
+}
+
+func singleOrSlice(values []interface{}) interface{} {
+	switch len(values) {
+	case 0:
+		return nil
+	case 1:
+		return values[0]
+	default:
+		return values
+	}
+}
+
+// schemaField derives the schema field of a message field. Google.protobuf.Timestamp and
+// google.protobuf.Duration fields map to TimeKind and DurationKind, other message fields are
+// encoded as JSON and repeated and map fields map to ListKind and MapKind.
+func schemaField(fd protoreflect.FieldDescriptor) (schema.Field, error) {
+	field := schema.Field{Name: string(fd.Name())}
+
+	switch {
+	case fd.IsMap():
+		keyKind, err := schemaKind(&field, fd.MapKey())
+		if err != nil {
+			return schema.Field{}, err
+		}
+		valueKind, err := schemaKind(&field, fd.MapValue())
+		if err != nil {
+			return schema.Field{}, err
+		}
+
+		field.Kind = schema.MapKind
+		field.KeyKind = keyKind
+		field.ValueKind = valueKind
+	case fd.IsList():
+		elementKind, err := schemaKind(&field, fd)
+		if err != nil {
+			return schema.Field{}, err
+		}
+
+		field.Kind = schema.ListKind
+		field.ElementKind = elementKind
+	default:
+		kind, err := schemaKind(&field, fd)
+		if err != nil {
+			return schema.Field{}, err
+		}
+
+		field.Kind = kind
+		field.Nullable = fd.HasPresence()
+	}
+
+	return field, nil
+}
+
+func schemaKind(field *schema.Field, fd protoreflect.FieldDescriptor) (schema.Kind, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return schema.StringKind, nil
+	case protoreflect.BytesKind:
+		return schema.BytesKind, nil
+	case protoreflect.BoolKind:
+		return schema.BoolKind, nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return schema.Int32Kind, nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return schema.Uint32Kind, nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return schema.Int64Kind, nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return schema.Uint64Kind, nil
+	case protoreflect.FloatKind:
+		return schema.Float32Kind, nil
+	case protoreflect.DoubleKind:
+		return schema.Float64Kind, nil
+	case protoreflect.EnumKind:
+		field.EnumType = schemaEnumType(fd.Enum())
+		return schema.EnumKind, nil
+	case protoreflect.MessageKind:
+		switch fd.Message().FullName() {
+		case timestampFullName:
+			return schema.TimeKind, nil
+		case durationFullName:
+			return schema.DurationKind, nil
+		default:
+			return schema.JSONKind, nil
+		}
+	default:
+		return schema.InvalidKind, fmt.Errorf("unsupported protobuf kind %s for field %s", fd.Kind(), fd.FullName())
+	}
+}
+
+func schemaEnumType(desc protoreflect.EnumDescriptor) schema.EnumType {
+	res := schema.EnumType{Name: string(desc.Name())}
+	values := desc.Values()
+	for i := 0; i < values.Len(); i++ {
+		value := values.Get(i)
+		res.Values = append(res.Values, string(value.Name()))
+		res.NumericValues = append(res.NumericValues, int32(value.Number()))
+	}
+	return res
+}
+
+// schemaValue converts the value of a message field to the value of its schema field.
+func schemaValue(fd protoreflect.FieldDescriptor, value protoreflect.Value) (interface{}, error) {
+	switch {
+	case fd.IsMap():
+		res := map[interface{}]interface{}{}
+		var err error
+		value.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			var key, elem interface{}
+			key, err = scalarValue(fd.MapKey(), k.Value())
+			if err != nil {
+				return false
+			}
+			elem, err = scalarValue(fd.MapValue(), v)
+			if err != nil {
+				return false
+			}
+			res[key] = elem
+			return true
+		})
+		return res, err
+	case fd.IsList():
+		list := value.List()
+		res := make([]interface{}, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			elem, err := scalarValue(fd, list.Get(i))
+			if err != nil {
+				return nil, err
+			}
+			res[i] = elem
+		}
+		return res, nil
+	default:
+		return scalarValue(fd, value)
+	}
+}
+
+// scalarValue converts a single value of a field to a schema value ignoring whether the field
+// is repeated.
+func scalarValue(fd protoreflect.FieldDescriptor, value protoreflect.Value) (interface{}, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return value.String(), nil
+	case protoreflect.BytesKind:
+		return value.Bytes(), nil
+	case protoreflect.BoolKind:
+		return value.Bool(), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return int32(value.Int()), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return uint32(value.Uint()), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return value.Int(), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return value.Uint(), nil
+	case protoreflect.FloatKind:
+		return float32(value.Float()), nil
+	case protoreflect.DoubleKind:
+		return value.Float(), nil
+	case protoreflect.EnumKind:
+		enumValue := fd.Enum().Values().ByNumber(value.Enum())
+		if enumValue == nil {
+			return nil, fmt.Errorf("unknown value %d for enum %s", value.Enum(), fd.Enum().FullName())
+		}
+		return string(enumValue.Name()), nil
+	case protoreflect.MessageKind:
+		msg := value.Message()
+		switch fd.Message().FullName() {
+		case timestampFullName:
+			fields := msg.Descriptor().Fields()
+			return time.Unix(msg.Get(fields.ByName("seconds")).Int(), msg.Get(fields.ByName("nanos")).Int()), nil
+		case durationFullName:
+			fields := msg.Descriptor().Fields()
+			seconds, nanos := msg.Get(fields.ByName("seconds")).Int(), msg.Get(fields.ByName("nanos")).Int()
+			return time.Duration(seconds)*time.Second + time.Duration(nanos), nil
+		default:
+			bz, err := protojson.Marshal(msg.Interface())
+			if err != nil {
+				return nil, err
+			}
+			return json.RawMessage(bz), nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported protobuf kind %s for field %s", fd.Kind(), fd.FullName())
+	}
+}
@@ -0,0 +1,107 @@
+package ormdb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gotest.tools/v3/assert"
+
+	"cosmossdk.io/orm/internal/testpb"
+	"cosmossdk.io/orm/model/ormtable"
+	"cosmossdk.io/schema"
+)
+
+func buildTableSchemaCodec(t *testing.T, message proto.Message) *tableSchemaCodec {
+	t.Helper()
+	table, err := ormtable.Build(ormtable.Options{MessageType: message.ProtoReflect().Type()})
+	assert.NilError(t, err)
+	cdc, err := newTableSchemaCodec(table)
+	assert.NilError(t, err)
+	assert.NilError(t, cdc.objectType.Validate())
+	return cdc
+}
+
+func TestTableSchemaCodec(t *testing.T) {
+	cdc := buildTableSchemaCodec(t, &testpb.ExampleTable{})
+	assert.DeepEqual(t, []schema.Field{
+		{Name: "u32", Kind: schema.Uint32Kind},
+		{Name: "i64", Kind: schema.Int64Kind},
+		{Name: "str", Kind: schema.StringKind},
+	}, cdc.objectType.KeyFields)
+
+	fields := map[string]schema.Field{}
+	for _, field := range cdc.objectType.ValueFields {
+		fields[field.Name] = field
+	}
+	assert.DeepEqual(t, schema.Field{Name: "ts", Kind: schema.TimeKind, Nullable: true}, fields["ts"])
+	assert.DeepEqual(t, schema.Field{Name: "repeated", Kind: schema.ListKind, ElementKind: schema.Uint32Kind}, fields["repeated"])
+	assert.DeepEqual(t, schema.Field{Name: "map", Kind: schema.MapKind, KeyKind: schema.StringKind, ValueKind: schema.Uint32Kind}, fields["map"])
+	assert.DeepEqual(t, schema.Field{Name: "msg", Kind: schema.JSONKind, Nullable: true}, fields["msg"])
+	assert.DeepEqual(t, schema.Field{Name: "oneof", Kind: schema.Uint32Kind, Nullable: true}, fields["oneof"])
+	assert.Equal(t, schema.EnumKind, fields["e"].Kind)
+	assert.DeepEqual(t, []int32{0, 1, 2, 5, -3}, fields["e"].EnumType.NumericValues)
+
+	// indexes are restricted to value fields and unique indexes including key fields aren't constraints
+	assert.DeepEqual(t, []schema.IndexDescriptor{
+		{Name: "u64", Fields: []schema.IndexField{{Name: "u64"}}},
+		{Name: "bz", Fields: []schema.IndexField{{Name: "bz"}}},
+	}, cdc.objectType.Indexes)
+	assert.Assert(t, cdc.objectType.UniqueConstraints == nil)
+
+	ts := time.Unix(10, 5)
+	res, err := cdc.objectUpdate(
+		[]protoreflect.Value{protoreflect.ValueOfUint32(1), protoreflect.ValueOfInt64(-2), protoreflect.ValueOfString("a")},
+		&testpb.ExampleTable{
+			U32: 1, I64: -2, Str: "a", Ts: timestamppb.New(ts), E: testpb.Enum_ENUM_FIVE,
+			Repeated: []uint32{3}, Map: map[string]uint32{"b": 4}, Msg: &testpb.ExampleTable_ExampleMessage{Foo: "c"},
+		},
+	)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(res))
+	assert.DeepEqual(t, []interface{}{uint32(1), int64(-2), "a"}, res[0].Key)
+	values := res[0].Value.([]interface{})
+	for i, field := range cdc.objectType.ValueFields {
+		assert.NilError(t, field.ValidateValue(values[i]))
+		switch field.Name {
+		case "ts":
+			assert.Assert(t, values[i].(time.Time).Equal(ts))
+		case "e":
+			assert.Equal(t, "ENUM_FIVE", values[i])
+		case "repeated":
+			assert.DeepEqual(t, []interface{}{uint32(3)}, values[i])
+		case "map":
+			assert.DeepEqual(t, map[interface{}]interface{}{"b": uint32(4)}, values[i])
+		case "msg":
+			var msg map[string]interface{}
+			assert.NilError(t, json.Unmarshal(values[i].(json.RawMessage), &msg))
+			assert.DeepEqual(t, map[string]interface{}{"foo": "c"}, msg)
+		case "dur", "oneof":
+			assert.Assert(t, values[i] == nil)
+		}
+	}
+}
+
+func TestTableSchemaCodec_AutoIncrement(t *testing.T) {
+	cdc := buildTableSchemaCodec(t, &testpb.ExampleAutoIncrementTable{})
+	assert.DeepEqual(t, []schema.Field{{
+		Name:     "id",
+		Kind:     schema.Uint64Kind,
+		Metadata: map[string]string{AutoIncrementMetadataKey: "true"},
+	}}, cdc.objectType.KeyFields)
+	assert.DeepEqual(t, [][]string{{"x"}}, cdc.objectType.UniqueConstraints)
+	assert.DeepEqual(t, []schema.IndexDescriptor{{Name: "x", Fields: []schema.IndexField{{Name: "x"}}}}, cdc.objectType.Indexes)
+}
+
+func TestTableSchemaCodec_Singleton(t *testing.T) {
+	cdc := buildTableSchemaCodec(t, &testpb.ExampleSingleton{})
+	assert.Assert(t, cdc.singleton)
+	assert.Equal(t, 0, len(cdc.objectType.KeyFields))
+
+	res, err := cdc.objectUpdate(nil, &testpb.ExampleSingleton{Foo: "a", Bar: 3})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []schema.ObjectUpdate{{TypeName: "ExampleSingleton", Value: []interface{}{"a", int32(3)}}}, res)
+}
@@ -13,6 +13,7 @@ import (
 	appv1alpha1 "cosmossdk.io/api/cosmos/app/v1alpha1"
 	autocliv1 "cosmossdk.io/api/cosmos/autocli/v1"
 	reflectionv1 "cosmossdk.io/api/cosmos/reflection/v1"
+	schemav1 "cosmossdk.io/api/cosmos/schema/v1"
 	"cosmossdk.io/core/app"
 	"cosmossdk.io/core/appmodule"
 	"cosmossdk.io/core/comet"
@@ -69,6 +70,21 @@ func (m appModule) AutoCLIOptions() *autocliv1.ModuleOptions {
 						},
 					},
 				},
+				"schema": {
+					Service: schemav1.SchemaRegistryService_ServiceDesc.ServiceName,
+					RpcCommandOptions: []*autocliv1.RpcCommandOptions{
+						{
+							RpcMethod: "ModuleSchemas",
+							Short:     "Query the schemas of all modules",
+						},
+						{
+							RpcMethod:      "ModuleSchema",
+							Use:            "module-schema [module-name]",
+							Short:          "Query the schema of a module",
+							PositionalArgs: []*autocliv1.PositionalArgDescriptor{{ProtoField: "module_name"}},
+						},
+					},
+				},
 			},
 		},
 	}
@@ -3,6 +3,7 @@ package runtime
 import (
 	autocliv1 "cosmossdk.io/api/cosmos/autocli/v1"
 	reflectionv1 "cosmossdk.io/api/cosmos/reflection/v1"
+	schemav1 "cosmossdk.io/api/cosmos/schema/v1"
 
 	"github.com/cosmos/cosmos-sdk/runtime/services"
 	"github.com/cosmos/cosmos-sdk/types/module"
@@ -17,5 +18,11 @@ func (a *App) registerRuntimeServices(cfg module.Configurator) error { // nolint
 	}
 	reflectionv1.RegisterReflectionServiceServer(cfg.QueryServer(), reflectionSvc)
 
+	schemaRegistrySvc, err := services.NewSchemaRegistryService(a.ModuleManager.Modules)
+	if err != nil {
+		return err
+	}
+	schemav1.RegisterSchemaRegistryServiceServer(cfg.QueryServer(), schemaRegistrySvc)
+
 	return nil
 }
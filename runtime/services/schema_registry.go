@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	schemav1 "cosmossdk.io/api/cosmos/schema/v1"
+	"cosmossdk.io/core/appmodule"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/decoding"
+
+	"github.com/cosmos/cosmos-sdk/codec/schemaproto"
+)
+
+// SchemaRegistryService implements the cosmos.schema.v1 SchemaRegistryService.
+type SchemaRegistryService struct {
+	schemav1.UnimplementedSchemaRegistryServiceServer
+
+	modules []*schemav1.ModuleSchemaDescriptor
+	byName  map[string]*schemav1.ModuleSchemaDescriptor
+}
+
+// NewSchemaRegistryService returns a SchemaRegistryService serving the schemas of the provided modules which
+// implement schema.HasModuleCodec. The schemas are resolved once, so modules must be fully initialized.
+func NewSchemaRegistryService(appModules map[string]appmodule.AppModule) (*SchemaRegistryService, error) {
+	moduleSet := make(map[string]interface{}, len(appModules))
+	for name, mod := range appModules {
+		moduleSet[name] = mod
+	}
+
+	svc := &SchemaRegistryService{byName: map[string]*schemav1.ModuleSchemaDescriptor{}}
+	err := decoding.ModuleSetDecoderResolver(moduleSet).IterateAll(func(moduleName string, cdc schema.ModuleCodec) error {
+		fingerprint := cdc.Schema.Fingerprint()
+		desc := &schemav1.ModuleSchemaDescriptor{
+			ModuleName:  moduleName,
+			Schema:      schemaproto.ModuleSchemaToProto(cdc.Schema),
+			Fingerprint: fingerprint[:],
+			Version:     cdc.Schema.Version(),
+		}
+		if mod, ok := appModules[moduleName].(appmodule.HasConsensusVersion); ok {
+			desc.ConsensusVersion = mod.ConsensusVersion()
+		}
+		svc.modules = append(svc.modules, desc)
+		svc.byName[moduleName] = desc
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve module schemas: %w", err)
+	}
+
+	return svc, nil
+}
+
+// ModuleSchemas implements the SchemaRegistryServiceServer interface.
+func (s SchemaRegistryService) ModuleSchemas(_ context.Context, _ *schemav1.ModuleSchemasRequest) (*schemav1.ModuleSchemasResponse, error) {
+	return &schemav1.ModuleSchemasResponse{Modules: s.modules}, nil
+}
+
+// ModuleSchema implements the SchemaRegistryServiceServer interface.
+func (s SchemaRegistryService) ModuleSchema(_ context.Context, req *schemav1.ModuleSchemaRequest) (*schemav1.ModuleSchemaResponse, error) {
+	if req.ModuleName == "" {
+		return nil, status.Error(codes.InvalidArgument, "module name cannot be empty")
+	}
+
+	desc, ok := s.byName[req.ModuleName]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "module %s has no schema", req.ModuleName)
+	}
+	return &schemav1.ModuleSchemaResponse{Module: desc}, nil
+}
+
+var _ schemav1.SchemaRegistryServiceServer = &SchemaRegistryService{}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	schemav1 "cosmossdk.io/api/cosmos/schema/v1"
+	"cosmossdk.io/core/appmodule"
+	"cosmossdk.io/schema"
+)
+
+type schemaModule struct {
+	moduleSchema schema.ModuleSchema
+}
+
+func (schemaModule) IsOnePerModuleType() {}
+func (schemaModule) IsAppModule()        {}
+func (schemaModule) ConsensusVersion() uint64 {
+	return 3
+}
+
+func (m schemaModule) ModuleCodec() (schema.ModuleCodec, error) {
+	return schema.ModuleCodec{Schema: m.moduleSchema}, nil
+}
+
+type noSchemaModule struct{}
+
+func (noSchemaModule) IsOnePerModuleType() {}
+func (noSchemaModule) IsAppModule()        {}
+
+func TestSchemaRegistryService(t *testing.T) {
+	bankSchema, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:        "balances",
+		KeyFields:   []schema.Field{{Name: "address", Kind: schema.AddressKind}},
+		ValueFields: []schema.Field{{Name: "amount", Kind: schema.IntegerStringKind}},
+	}})
+	require.NoError(t, err)
+	// the schema version only changes with the schema, unlike the consensus version of the module
+	bankSchema = bankSchema.WithVersion(2)
+
+	svc, err := NewSchemaRegistryService(map[string]appmodule.AppModule{
+		"bank": schemaModule{moduleSchema: bankSchema},
+		"auth": noSchemaModule{},
+	})
+	require.NoError(t, err)
+
+	res, err := svc.ModuleSchemas(context.Background(), &schemav1.ModuleSchemasRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Modules, 1)
+
+	fingerprint := bankSchema.Fingerprint()
+	bank := res.Modules[0]
+	require.Equal(t, "bank", bank.ModuleName)
+	require.Equal(t, fingerprint[:], bank.Fingerprint)
+	require.Equal(t, uint64(2), bank.Version)
+	require.Equal(t, uint64(2), bank.Schema.Version)
+	require.Equal(t, uint64(3), bank.ConsensusVersion)
+	require.Equal(t, "balances", bank.Schema.ObjectTypes[0].Name)
+
+	modRes, err := svc.ModuleSchema(context.Background(), &schemav1.ModuleSchemaRequest{ModuleName: "bank"})
+	require.NoError(t, err)
+	require.Equal(t, bank, modRes.Module)
+
+	_, err = svc.ModuleSchema(context.Background(), &schemav1.ModuleSchemaRequest{ModuleName: "auth"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = svc.ModuleSchema(context.Background(), &schemav1.ModuleSchemaRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
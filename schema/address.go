@@ -0,0 +1,397 @@
+package schema
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// AddressCodec converts addresses between their canonical []byte representation and a string rendering
+// such as bech32 or hex. It has the same methods as cosmossdk.io/core/address.Codec so that the address
+// codecs of an app can be used directly.
+type AddressCodec interface {
+	// StringToBytes decodes text to bytes.
+	StringToBytes(text string) ([]byte, error)
+
+	// BytesToString encodes bytes to text.
+	BytesToString(bz []byte) (string, error)
+}
+
+// NormalizeValue validates the value and returns it in its canonical go representation for the kind.
+// For AddressKind, string values are decoded into []byte with the address codec and []byte values are
+// verified by encoding them with the address codec, so that indexers always receive canonical []byte
+// addresses. For all other kinds, the value is validated with ValidateValue and returned unchanged.
+// If addressCodec is nil, AddressKind values must already be []byte and are not verified.
+func (t Kind) NormalizeValue(value interface{}, addressCodec AddressCodec) (interface{}, error) {
+	if t != AddressKind || addressCodec == nil {
+		if err := t.ValidateValue(value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+
+	switch value := value.(type) {
+	case []byte:
+		if _, err := addressCodec.BytesToString(value); err != nil {
+			return nil, fmt.Errorf("invalid address %x: %v", value, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		return value, nil
+	case string:
+		bz, err := addressCodec.StringToBytes(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q: %v", value, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		return bz, nil
+	default:
+		return nil, fmt.Errorf("expected []byte or string, got %T", value)
+	}
+}
+
+// HexAddressCodec is an AddressCodec which renders addresses as lowercase hex strings with a 0x prefix.
+// Decoding accepts upper or lower case hex with or without the 0x prefix.
+type HexAddressCodec struct{}
+
+// StringToBytes implements the AddressCodec interface.
+func (HexAddressCodec) StringToBytes(text string) ([]byte, error) {
+	text = strings.TrimPrefix(strings.TrimPrefix(text, "0x"), "0X")
+	if text == "" {
+		return nil, fmt.Errorf("empty address string")
+	}
+	return hex.DecodeString(text)
+}
+
+// BytesToString implements the AddressCodec interface.
+func (HexAddressCodec) BytesToString(bz []byte) (string, error) {
+	if len(bz) == 0 {
+		return "", fmt.Errorf("empty address")
+	}
+	return "0x" + hex.EncodeToString(bz), nil
+}
+
+// Bech32AddressCodec is an AddressCodec which renders addresses as bech32 strings with the human-readable
+// part Prefix, such as "cosmos" or "osmovaloper", like the address codecs of Cosmos SDK apps. Decoding fails
+// for strings with any other prefix, so addresses of the wrong chain or type are rejected.
+type Bech32AddressCodec struct {
+	// Prefix is the human-readable part of the addresses.
+	Prefix string
+}
+
+// StringToBytes implements the AddressCodec interface.
+func (c Bech32AddressCodec) StringToBytes(text string) ([]byte, error) {
+	hrp, data, err := decodeBech32(text)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != c.Prefix {
+		return nil, fmt.Errorf("expected address prefix %q, got %q", c.Prefix, hrp)
+	}
+	bz, err := convertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(bz) == 0 {
+		return nil, fmt.Errorf("empty address")
+	}
+	return bz, nil
+}
+
+// BytesToString implements the AddressCodec interface.
+func (c Bech32AddressCodec) BytesToString(bz []byte) (string, error) {
+	if len(bz) == 0 {
+		return "", fmt.Errorf("empty address")
+	}
+	if err := validateAddressPrefix(c.Prefix); err != nil {
+		return "", err
+	}
+	data, err := convertBits(bz, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return encodeBech32(c.Prefix, data), nil
+}
+
+// AddressCodec returns the codec which renders the addresses of the field: a Bech32AddressCodec if the field
+// declares an AddressPrefix and codec otherwise. This allows indexers which serve the addresses of several
+// chains, or of several address types of one chain, to render each address with the correct prefix.
+func (c Field) AddressCodec(codec AddressCodec) AddressCodec {
+	if c.AddressPrefix != "" {
+		return Bech32AddressCodec{Prefix: c.AddressPrefix}
+	}
+	return codec
+}
+
+// validateAddressPrefix checks that prefix is a valid bech32 human-readable part with only lowercase
+// characters.
+func validateAddressPrefix(prefix string) error {
+	if len(prefix) == 0 || len(prefix) > 83 {
+		return fmt.Errorf("address prefix %q must have between 1 and 83 characters", prefix)
+	}
+	for _, ch := range prefix {
+		if ch < 33 || ch > 126 || (ch >= 'A' && ch <= 'Z') {
+			return fmt.Errorf("address prefix %q must only contain lowercase printable ASCII characters", prefix)
+		}
+	}
+	return nil
+}
+
+// bech32Charset is the alphabet of the data part of bech32 strings as specified in BIP-173.
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// maxBech32Length is the maximum length of the bech32 strings which are decoded. It is larger than the
+// 90 characters of BIP-173 so that 32 byte addresses with long prefixes can be decoded, like in the SDK.
+const maxBech32Length = 1023
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	res := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		res = append(res, hrp[i]>>5)
+	}
+	res = append(res, 0)
+	for i := 0; i < len(hrp); i++ {
+		res = append(res, hrp[i]&31)
+	}
+	return res
+}
+
+func encodeBech32(hrp string, data []byte) string {
+	values := append(bech32HRPExpand(hrp), data...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ 1
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range data {
+		sb.WriteByte(bech32Charset[v])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+	return sb.String()
+}
+
+// decodeBech32 decodes a bech32 string into its human-readable part and its 5 bit data without the checksum.
+func decodeBech32(text string) (string, []byte, error) {
+	if len(text) > maxBech32Length {
+		return "", nil, fmt.Errorf("bech32 string is longer than %d characters", maxBech32Length)
+	}
+	lower := strings.ToLower(text)
+	if lower != text && strings.ToUpper(text) != text {
+		return "", nil, fmt.Errorf("bech32 string %q has mixed case", text)
+	}
+
+	sep := strings.LastIndexByte(lower, '1')
+	if sep < 1 || sep+7 > len(lower) {
+		return "", nil, fmt.Errorf("invalid bech32 string %q", text)
+	}
+	hrp := lower[:sep]
+	if err := validateAddressPrefix(hrp); err != nil {
+		return "", nil, err
+	}
+
+	data := make([]byte, 0, len(lower)-sep-1)
+	for i := sep + 1; i < len(lower); i++ {
+		v := strings.IndexByte(bech32Charset, lower[i])
+		if v < 0 {
+			return "", nil, fmt.Errorf("invalid character %q in bech32 string %q", lower[i], text)
+		}
+		data = append(data, byte(v))
+	}
+
+	if bech32Polymod(append(bech32HRPExpand(hrp), data...)) != 1 {
+		return "", nil, fmt.Errorf("invalid checksum in bech32 string %q", text)
+	}
+	return hrp, data[:len(data)-6], nil
+}
+
+// convertBits regroups data from groups of fromBits bits into groups of toBits bits.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc uint32
+	var bits uint
+	maxValue := uint32(1)<<toBits - 1
+	res := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, fmt.Errorf("invalid data value %d", v)
+		}
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			res = append(res, byte(acc>>bits&maxValue))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			res = append(res, byte(acc<<(toBits-bits)&maxValue))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxValue != 0 {
+		return nil, fmt.Errorf("invalid padding in bech32 data")
+	}
+	return res, nil
+}
+
+// Addresses calls fn with every address in the update together with the name of the key or value field of
+// the object type which contains it. Addresses are the values of AddressKind fields as well as the list
+// elements, map keys and values and struct fields of AddressKind at any depth. The values of deletes are not
+// inspected and if the Value of the update is a ValueUpdates, only the updated fields are. Iteration stops
+// when fn returns false. The object type must be the type of the update and the update must be valid, so
+// addresses must have been normalized to []byte.
+func (u ObjectUpdate) Addresses(objectType ObjectType, fn func(field string, address []byte) bool) error {
+	if objectType.Name != u.TypeName {
+		return fmt.Errorf("object update has type %q, got object type %q", u.TypeName, objectType.Name)
+	}
+
+	cont, err := positionalAddresses(objectType.KeyFields, u.Key, fn)
+	if err != nil || !cont || u.Delete {
+		return err
+	}
+
+	valueUpdates, ok := u.Value.(ValueUpdates)
+	if !ok {
+		_, err = positionalAddresses(objectType.ValueFields, u.Value, fn)
+		return err
+	}
+
+	fields := make(map[string]Field, len(objectType.ValueFields))
+	for _, field := range objectType.ValueFields {
+		fields[field.Name] = field
+	}
+
+	iterErr := valueUpdates.Iterate(func(name string, value interface{}) bool {
+		field, ok := fields[name]
+		if !ok {
+			err = fmt.Errorf("unknown value field %q", name)
+			return false
+		}
+		cont, err = fieldAddresses(field, name, value, fn)
+		return err == nil && cont
+	})
+	if iterErr != nil {
+		return iterErr
+	}
+	return err
+}
+
+// positionalAddresses calls fn with the addresses in a single value or slice of values encoded as described
+// in ObjectUpdate.Key.
+func positionalAddresses(fields []Field, value interface{}, fn func(string, []byte) bool) (bool, error) {
+	if len(fields) == 0 {
+		return true, nil
+	}
+
+	if len(fields) == 1 {
+		return fieldAddresses(fields[0], fields[0].Name, value, fn)
+	}
+
+	values, ok := value.([]interface{})
+	if !ok || len(values) != len(fields) {
+		return false, fmt.Errorf("expected slice of %d values, got %T", len(fields), value)
+	}
+
+	for i, field := range fields {
+		cont, err := fieldAddresses(field, field.Name, values[i], fn)
+		if err != nil || !cont {
+			return cont, err
+		}
+	}
+	return true, nil
+}
+
+// fieldAddresses calls fn with the addresses in the value of field, reporting them as part of the top-level
+// field name.
+func fieldAddresses(field Field, name string, value interface{}, fn func(string, []byte) bool) (bool, error) {
+	if value == nil {
+		return true, nil
+	}
+
+	switch field.Kind {
+	case AddressKind:
+		address, ok := value.([]byte)
+		if !ok {
+			return false, fmt.Errorf("expected []byte address for field %q, got %T", name, value)
+		}
+		return fn(name, address), nil
+	case StructKind:
+		values, ok := value.([]interface{})
+		if !ok || len(values) != len(field.StructType.Fields) {
+			return false, fmt.Errorf("expected slice of %d values for struct field %q, got %T", len(field.StructType.Fields), name, value)
+		}
+		for i, structField := range field.StructType.Fields {
+			cont, err := fieldAddresses(structField, name, values[i], fn)
+			if err != nil || !cont {
+				return cont, err
+			}
+		}
+		return true, nil
+	case OneOfKind:
+		oneOf, ok := value.(OneOfValue)
+		if !ok {
+			return false, fmt.Errorf("expected OneOfValue for oneof field %q, got %T", name, value)
+		}
+		c, ok := field.OneOfType.LookupCase(oneOf.Case)
+		if !ok {
+			return false, fmt.Errorf("unknown case %q for oneof field %q", oneOf.Case, name)
+		}
+		return fieldAddresses(c, name, oneOf.Value, fn)
+	case ListKind:
+		if !kindHasAddresses(field.ElementKind) {
+			return true, nil
+		}
+		values, ok := value.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("expected slice of values for list field %q, got %T", name, value)
+		}
+		element := Field{Kind: field.ElementKind, StructType: field.StructType, OneOfType: field.OneOfType}
+		for _, v := range values {
+			cont, err := fieldAddresses(element, name, v, fn)
+			if err != nil || !cont {
+				return cont, err
+			}
+		}
+		return true, nil
+	case MapKind:
+		if !kindHasAddresses(field.KeyKind) && !kindHasAddresses(field.ValueKind) {
+			return true, nil
+		}
+		entries, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return false, fmt.Errorf("expected map of values for map field %q, got %T", name, value)
+		}
+		key := Field{Kind: field.KeyKind}
+		val := Field{Kind: field.ValueKind, StructType: field.StructType, OneOfType: field.OneOfType}
+		for k, v := range entries {
+			cont, err := fieldAddresses(key, name, k, fn)
+			if err != nil || !cont {
+				return cont, err
+			}
+			cont, err = fieldAddresses(val, name, v, fn)
+			if err != nil || !cont {
+				return cont, err
+			}
+		}
+		return true, nil
+	default:
+		return true, nil
+	}
+}
+
+// kindHasAddresses reports whether values of the kind may contain addresses.
+func kindHasAddresses(kind Kind) bool {
+	return kind == AddressKind || kind == StructKind || kind == OneOfKind
+}
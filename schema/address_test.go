@@ -0,0 +1,334 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testPrefixAddressCodec renders addresses as hex with a fixed prefix and only accepts 4 byte addresses.
+type testPrefixAddressCodec struct{}
+
+func (testPrefixAddressCodec) StringToBytes(text string) ([]byte, error) {
+	if !strings.HasPrefix(text, "test") {
+		return nil, fmt.Errorf("missing prefix")
+	}
+	bz, err := HexAddressCodec{}.StringToBytes(strings.TrimPrefix(text, "test"))
+	if err != nil {
+		return nil, err
+	}
+	if len(bz) != 4 {
+		return nil, fmt.Errorf("expected 4 bytes, got %d", len(bz))
+	}
+	return bz, nil
+}
+
+func (testPrefixAddressCodec) BytesToString(bz []byte) (string, error) {
+	if len(bz) != 4 {
+		return "", fmt.Errorf("expected 4 bytes, got %d", len(bz))
+	}
+	s, err := HexAddressCodec{}.BytesToString(bz)
+	return "test" + strings.TrimPrefix(s, "0x"), err
+}
+
+func TestKind_NormalizeValue(t *testing.T) {
+	tests := []struct {
+		name         string
+		kind         Kind
+		value        interface{}
+		addressCodec AddressCodec
+		expected     interface{}
+		errContains  string
+	}{
+		{
+			name:         "non-address kind",
+			kind:         StringKind,
+			value:        "abc",
+			addressCodec: HexAddressCodec{},
+			expected:     "abc",
+		},
+		{
+			name:         "invalid non-address kind",
+			kind:         IntegerStringKind,
+			value:        "abc",
+			addressCodec: HexAddressCodec{},
+			errContains:  "expected base10 integer",
+		},
+		{
+			name:     "address bytes without codec",
+			kind:     AddressKind,
+			value:    []byte{1, 2},
+			expected: []byte{1, 2},
+		},
+		{
+			name:        "address string without codec",
+			kind:        AddressKind,
+			value:       "0x0102",
+			errContains: "expected []byte, got string",
+		},
+		{
+			name:         "hex address string",
+			kind:         AddressKind,
+			value:        "0x0A0b",
+			addressCodec: HexAddressCodec{},
+			expected:     []byte{10, 11},
+		},
+		{
+			name:         "prefixed address string",
+			kind:         AddressKind,
+			value:        "test01020304",
+			addressCodec: testPrefixAddressCodec{},
+			expected:     []byte{1, 2, 3, 4},
+		},
+		{
+			name:         "valid address bytes",
+			kind:         AddressKind,
+			value:        []byte{1, 2, 3, 4},
+			addressCodec: testPrefixAddressCodec{},
+			expected:     []byte{1, 2, 3, 4},
+		},
+		{
+			name:         "invalid address bytes",
+			kind:         AddressKind,
+			value:        []byte{1, 2},
+			addressCodec: testPrefixAddressCodec{},
+			errContains:  "invalid address 0102: expected 4 bytes, got 2",
+		},
+		{
+			name:         "invalid address string",
+			kind:         AddressKind,
+			value:        "cosmos1abc",
+			addressCodec: testPrefixAddressCodec{},
+			errContains:  "invalid address \"cosmos1abc\": missing prefix",
+		},
+		{
+			name:         "invalid address type",
+			kind:         AddressKind,
+			value:        1,
+			addressCodec: HexAddressCodec{},
+			errContains:  "expected []byte or string, got int",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.kind.NormalizeValue(tt.value, tt.addressCodec)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error to contain %q, got: %v", tt.errContains, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if bz, ok := tt.expected.([]byte); ok {
+				if !bytes.Equal(bz, res.([]byte)) {
+					t.Fatalf("expected %x, got %x", bz, res)
+				}
+			} else if res != tt.expected {
+				t.Fatalf("expected %v, got %v", tt.expected, res)
+			}
+		})
+	}
+}
+
+func TestHexAddressCodec(t *testing.T) {
+	codec := HexAddressCodec{}
+	str, err := codec.BytesToString([]byte{0xab, 0xcd})
+	if err != nil || str != "0xabcd" {
+		t.Fatalf("expected 0xabcd, got %q, %v", str, err)
+	}
+
+	bz, err := codec.StringToBytes("0XABCD")
+	if err != nil || !bytes.Equal(bz, []byte{0xab, 0xcd}) {
+		t.Fatalf("expected abcd, got %x, %v", bz, err)
+	}
+
+	if _, err := codec.StringToBytes("0x"); err == nil {
+		t.Fatalf("expected error for empty address")
+	}
+
+	if _, err := codec.BytesToString(nil); err == nil {
+		t.Fatalf("expected error for empty address")
+	}
+
+	if _, err := codec.StringToBytes("xyz"); err == nil {
+		t.Fatalf("expected error for invalid hex")
+	}
+}
+
+func TestBech32AddressCodec(t *testing.T) {
+	codec := Bech32AddressCodec{Prefix: "osmovaloper"}
+	addr := []byte{0xde, 0xad, 0xbe, 0xef, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	str, err := codec.BytesToString(addr)
+	if err != nil || str != "osmovaloper1m6kmamcpqgpsgpgxquyqjzstpsxsurcsnqu80h" {
+		t.Fatalf("unexpected bech32 address %q, %v", str, err)
+	}
+
+	bz, err := codec.StringToBytes(strings.ToUpper(str))
+	if err != nil || !bytes.Equal(bz, addr) {
+		t.Fatalf("expected %x, got %x, %v", addr, bz, err)
+	}
+
+	str, err = Bech32AddressCodec{Prefix: "cosmos"}.BytesToString(make([]byte, 20))
+	if err != nil || str != "cosmos1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqnrql8a" {
+		t.Fatalf("unexpected bech32 address %q, %v", str, err)
+	}
+
+	tests := []struct {
+		name        string
+		str         string
+		errContains string
+	}{
+		{name: "wrong prefix", str: "cosmos1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqnrql8a", errContains: "expected address prefix \"osmovaloper\", got \"cosmos\""},
+		{name: "invalid checksum", str: "osmovaloper1m6kmamcpqgpsgpgxquyqjzstpsxsurcsnqu80g", errContains: "invalid checksum"},
+		{name: "mixed case", str: "osmovaloper1M6kmamcpqgpsgpgxquyqjzstpsxsurcsnqu80h", errContains: "mixed case"},
+		{name: "invalid character", str: "osmovaloper1b6kmamcpqgpsgpgxquyqjzstpsxsurcsnqu80h", errContains: "invalid character"},
+		{name: "hex", str: "0xdeadbeef", errContains: "invalid bech32 string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := codec.StringToBytes(tt.str)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+
+	if _, err := codec.BytesToString(nil); err == nil {
+		t.Fatalf("expected error for empty address")
+	}
+
+	if _, err := (Bech32AddressCodec{Prefix: "Cosmos"}).BytesToString(addr); err == nil {
+		t.Fatalf("expected error for uppercase prefix")
+	}
+}
+
+func TestField_AddressCodec(t *testing.T) {
+	field := Field{Name: "validator", Kind: AddressKind, AddressPrefix: "cosmosvaloper", Constraints: FieldConstraints{Size: 20}}
+	if err := field.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if codec := field.AddressCodec(HexAddressCodec{}); codec != (Bech32AddressCodec{Prefix: "cosmosvaloper"}) {
+		t.Fatalf("expected bech32 address codec, got %#v", codec)
+	}
+	if codec := (Field{Name: "account", Kind: AddressKind}).AddressCodec(HexAddressCodec{}); codec != (HexAddressCodec{}) {
+		t.Fatalf("expected hex address codec, got %#v", codec)
+	}
+
+	// addresses of the wrong size, e.g. from a decoder which returns hex strings as bytes, are invalid
+	if err := field.ValidateValue([]byte("deadbeef")); err == nil || !strings.Contains(err.Error(), "expected 20 bytes, got 8") {
+		t.Fatalf("expected size error, got %v", err)
+	}
+
+	list := Field{Name: "validators", Kind: ListKind, ElementKind: AddressKind, AddressPrefix: "cosmosvaloper"}
+	if err := list.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := Field{Name: "denom", Kind: StringKind, AddressPrefix: "cosmos"}.Validate()
+	if err == nil || !strings.Contains(err.Error(), "address prefix is only valid for field \"denom\" with type AddressKind") {
+		t.Fatalf("expected address prefix error, got %v", err)
+	}
+
+	err = Field{Name: "account", Kind: AddressKind, AddressPrefix: "cosmos 1"}.Validate()
+	if err == nil || !strings.Contains(err.Error(), "invalid address prefix for field \"account\"") {
+		t.Fatalf("expected invalid address prefix error, got %v", err)
+	}
+}
+
+func TestObjectUpdate_Addresses(t *testing.T) {
+	coin := StructType{Name: "coin", Fields: []Field{{Name: "denom", Kind: StringKind}, {Name: "amount", Kind: IntegerStringKind}}}
+	grant := StructType{Name: "grant", Fields: []Field{{Name: "grantee", Kind: AddressKind}, {Name: "limit", Kind: IntegerStringKind}}}
+	objectType := ObjectType{
+		Name:      "delegations",
+		KeyFields: []Field{{Name: "delegator", Kind: AddressKind}, {Name: "validator", Kind: AddressKind}},
+		ValueFields: []Field{
+			{Name: "shares", Kind: DecimalStringKind},
+			{Name: "balance", Kind: StructKind, StructType: coin},
+			{Name: "grants", Kind: ListKind, ElementKind: StructKind, StructType: grant},
+			{Name: "operator", Kind: AddressKind, Nullable: true},
+			{Name: "content", Kind: OneOfKind, OneOfType: testContentOneOf, Nullable: true},
+		},
+	}
+
+	collect := func(update ObjectUpdate) ([]string, error) {
+		var res []string
+		err := update.Addresses(objectType, func(field string, address []byte) bool {
+			res = append(res, fmt.Sprintf("%s:%x", field, address))
+			return true
+		})
+		return res, err
+	}
+
+	key := []interface{}{[]byte{1}, []byte{2}}
+	tests := []struct {
+		name     string
+		update   ObjectUpdate
+		expected []string
+		errMsg   string
+	}{
+		{
+			name: "positional value",
+			update: ObjectUpdate{TypeName: "delegations", Key: key, Value: []interface{}{
+				"1.5",
+				[]interface{}{"uatom", "10"},
+				[]interface{}{[]interface{}{[]byte{3}, "5"}, []interface{}{[]byte{4}, "6"}},
+				nil,
+				OneOfValue{Case: "recipient", Value: []byte{6}},
+			}},
+			expected: []string{"delegator:01", "validator:02", "grants:03", "grants:04", "content:06"},
+		},
+		{
+			name:     "value updates",
+			update:   ObjectUpdate{TypeName: "delegations", Key: key, Value: MapValueUpdates{"operator": []byte{5}, "shares": "2"}},
+			expected: []string{"delegator:01", "validator:02", "operator:05"},
+		},
+		{
+			name:     "delete",
+			update:   ObjectUpdate{TypeName: "delegations", Key: key, Delete: true},
+			expected: []string{"delegator:01", "validator:02"},
+		},
+		{
+			name:   "wrong type",
+			update: ObjectUpdate{TypeName: "other", Key: key, Delete: true},
+			errMsg: "object update has type",
+		},
+		{
+			name:   "unnormalized address",
+			update: ObjectUpdate{TypeName: "delegations", Key: []interface{}{"cosmos1", []byte{2}}, Delete: true},
+			errMsg: "expected []byte address",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := collect(tt.update)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(res, ",") != strings.Join(tt.expected, ",") {
+				t.Fatalf("expected addresses %v, got %v", tt.expected, res)
+			}
+		})
+	}
+
+	// iteration stops when the callback returns false
+	n := 0
+	err := ObjectUpdate{TypeName: "delegations", Key: key, Delete: true}.Addresses(objectType, func(string, []byte) bool {
+		n++
+		return false
+	})
+	if err != nil || n != 1 {
+		t.Fatalf("expected iteration to stop after one address, got %d, %v", n, err)
+	}
+}
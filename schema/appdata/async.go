@@ -0,0 +1,246 @@
+package appdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// OverflowPolicy specifies what an asynchronous listener does when its queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the caller until there is room in the queue. This applies backpressure to the
+	// data source while guaranteeing that no data is lost.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropOldest drops the oldest packet in the queue to make room for the new one so that the
+	// caller never blocks. Any packet including Commit may be dropped so this is only suitable for
+	// listeners which can tolerate gaps in the data, such as metrics or live notifications.
+	OverflowDropOldest
+
+	// OverflowError returns ErrQueueFull to the caller without enqueuing the packet.
+	OverflowError
+)
+
+// ErrQueueFull is returned by an asynchronous listener using OverflowError when its queue is full.
+var ErrQueueFull = errors.New("async listener queue is full")
+
+// String returns a string representation of the overflow policy.
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDropOldest:
+		return "drop_oldest"
+	case OverflowError:
+		return "error"
+	default:
+		return fmt.Sprintf("invalid(%d)", p)
+	}
+}
+
+// AsyncListenerOptions are options for AsyncListenerWithBackpressure.
+type AsyncListenerOptions struct {
+	// Context is the context whose cancellation stops the listener goroutine and unblocks callers waiting
+	// for room in the queue. If it is nil, context.Background() is used.
+	Context context.Context
+
+	// BufferSize is the number of packets which can be queued before the overflow policy applies.
+	// Values less than one are treated as one.
+	BufferSize int
+
+	// OverflowPolicy specifies what happens when a packet is sent while the queue is full.
+	OverflowPolicy OverflowPolicy
+
+	// DoneWaitGroup, if set, is incremented when the listener goroutine starts and marked done when it exits.
+	DoneWaitGroup *sync.WaitGroup
+
+	// OnQueueDepth, if set, is called with the number of queued packets every time a packet is enqueued or
+	// dequeued so that the queue depth can be reported as a metric. Calls are serialized so that the last
+	// reported depth is always current.
+	OnQueueDepth func(depth int)
+
+	// OnDrop, if set, is called with each packet which is dropped because of OverflowDropOldest.
+	OnDrop func(Packet)
+
+	// DrainOnDone specifies that the packets which are queued when Context is done are still forwarded to the
+	// listener, unless it has returned an error, before the listener goroutine exits, so that a graceful
+	// shutdown doesn't lose them. Callers must not send packets after Context is done.
+	DrainOnDone bool
+}
+
+// AsyncListenerWithBackpressure returns a listener which enqueues packets on a bounded channel and
+// forwards them to listener in order on a separate goroutine, so that a slow listener does not block the
+// data source until the queue is full. What happens then depends on opts.OverflowPolicy.
+//
+// Callbacks which are nil in listener are also nil in the returned listener. If listener returns an error,
+// all remaining packets are discarded and the error is returned by the next call to any callback of the
+// returned listener, usually Commit.
+func AsyncListenerWithBackpressure(opts AsyncListenerOptions, listener Listener) Listener {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	bufferSize := opts.BufferSize
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+
+	q := &asyncQueue{
+		ctx:     ctx,
+		packets: make(chan Packet, bufferSize),
+		opts:    opts,
+	}
+
+	if opts.DoneWaitGroup != nil {
+		opts.DoneWaitGroup.Add(1)
+	}
+
+	go q.run(listener)
+
+	res := Listener{}
+	if listener.InitializeModuleData != nil {
+		res.InitializeModuleData = func(data ModuleInitializationData) error { return q.send(data) }
+	}
+	if listener.StartBlock != nil {
+		res.StartBlock = func(data StartBlockData) error { return q.send(data) }
+	}
+	if listener.OnTx != nil {
+		res.OnTx = func(data TxData) error { return q.send(data) }
+	}
+	if listener.OnEvent != nil {
+		res.OnEvent = func(data EventData) error { return q.send(data) }
+	}
+	if listener.OnKVPair != nil {
+		res.OnKVPair = func(data KVPairData) error { return q.send(data) }
+	}
+	if listener.OnObjectUpdate != nil {
+		res.OnObjectUpdate = func(data ObjectUpdateData) error { return q.send(data) }
+	}
+	if listener.Commit != nil {
+		res.Commit = func(data CommitData) error { return q.send(data) }
+	}
+	if listener.OnSimulation != nil {
+		res.OnSimulation = func(data SimulationData) error { return q.send(data) }
+	}
+	if listener.OnBytesChunk != nil {
+		res.OnBytesChunk = func(data BytesChunkData) error { return q.send(data) }
+	}
+	return res
+}
+
+type asyncQueue struct {
+	ctx     context.Context
+	packets chan Packet
+	opts    AsyncListenerOptions
+
+	mu  sync.Mutex
+	err error
+
+	depthMu sync.Mutex
+}
+
+func (q *asyncQueue) run(listener Listener) {
+	if q.opts.DoneWaitGroup != nil {
+		defer q.opts.DoneWaitGroup.Done()
+	}
+
+	for {
+		select {
+		case packet := <-q.packets:
+			q.forward(listener, packet)
+		case <-q.ctx.Done():
+			if !q.opts.DrainOnDone {
+				return
+			}
+			for {
+				select {
+				case packet := <-q.packets:
+					q.forward(listener, packet)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (q *asyncQueue) forward(listener Listener, packet Packet) {
+	q.reportDepth()
+
+	if q.getErr() != nil {
+		// discard packets after an error
+		return
+	}
+
+	if err := listener.SendPacket(packet); err != nil {
+		q.setErr(err)
+	}
+}
+
+func (q *asyncQueue) send(packet Packet) error {
+	if err := q.getErr(); err != nil {
+		return err
+	}
+
+	switch q.opts.OverflowPolicy {
+	case OverflowBlock:
+		select {
+		case q.packets <- packet:
+		case <-q.ctx.Done():
+			return q.ctx.Err()
+		}
+	case OverflowDropOldest:
+		for sent := false; !sent; {
+			select {
+			case q.packets <- packet:
+				sent = true
+			default:
+				select {
+				case dropped := <-q.packets:
+					if q.opts.OnDrop != nil {
+						q.opts.OnDrop(dropped)
+					}
+				default:
+					// the listener goroutine dequeued a packet in the meantime
+				}
+			}
+		}
+	case OverflowError:
+		select {
+		case q.packets <- packet:
+		default:
+			return ErrQueueFull
+		}
+	default:
+		return fmt.Errorf("invalid overflow policy: %s", q.opts.OverflowPolicy)
+	}
+
+	q.reportDepth()
+	return nil
+}
+
+func (q *asyncQueue) reportDepth() {
+	if q.opts.OnQueueDepth == nil {
+		return
+	}
+
+	q.depthMu.Lock()
+	defer q.depthMu.Unlock()
+	q.opts.OnQueueDepth(len(q.packets))
+}
+
+func (q *asyncQueue) getErr() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.err
+}
+
+func (q *asyncQueue) setErr(err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.err = err
+}
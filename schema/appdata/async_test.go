@@ -0,0 +1,218 @@
+package appdata
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAsyncListenerWithBackpressure(t *testing.T) {
+	t.Run("block", func(t *testing.T) {
+		var heights []uint64
+		committed := make(chan struct{})
+		listener := newTestAsyncListener(OverflowBlock, 1, nil, Listener{
+			StartBlock: func(data StartBlockData) error {
+				heights = append(heights, data.Height)
+				return nil
+			},
+			Commit: func(CommitData) error {
+				committed <- struct{}{}
+				return nil
+			},
+		})
+		defer listener.close()
+
+		if listener.OnTx != nil {
+			t.Fatalf("expected nil callbacks to stay nil")
+		}
+
+		for i := uint64(1); i <= 10; i++ {
+			if err := listener.StartBlock(StartBlockData{Height: i}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if err := listener.Commit(CommitData{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		<-committed
+
+		if len(heights) != 10 {
+			t.Fatalf("expected 10 blocks, got %v", heights)
+		}
+		for i, height := range heights {
+			if height != uint64(i+1) {
+				t.Fatalf("expected blocks in order, got %v", heights)
+			}
+		}
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		gate := make(chan struct{})
+		var mu sync.Mutex
+		var dropped []uint64
+		listener := newTestAsyncListener(OverflowDropOldest, 2, func(p Packet) {
+			mu.Lock()
+			defer mu.Unlock()
+			dropped = append(dropped, p.(StartBlockData).Height)
+		}, Listener{
+			StartBlock: func(StartBlockData) error {
+				<-gate
+				return nil
+			},
+		})
+		defer listener.close()
+
+		// the first packet is dequeued and blocks the listener so wait until it has been
+		sendHeight(t, listener, 1)
+		waitFor(t, func() bool { return listener.queueDepth() == 0 })
+
+		for i := uint64(2); i <= 5; i++ {
+			sendHeight(t, listener, i)
+		}
+
+		close(gate)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(dropped) != 2 || dropped[0] != 2 || dropped[1] != 3 {
+			t.Fatalf("expected blocks 2 and 3 to be dropped, got %v", dropped)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		gate := make(chan struct{})
+		listener := newTestAsyncListener(OverflowError, 1, nil, Listener{
+			StartBlock: func(StartBlockData) error {
+				<-gate
+				return nil
+			},
+		})
+		defer listener.close()
+		defer close(gate)
+
+		sendHeight(t, listener, 1)
+		waitFor(t, func() bool { return listener.queueDepth() == 0 })
+		sendHeight(t, listener, 2)
+
+		err := listener.StartBlock(StartBlockData{Height: 3})
+		if err != ErrQueueFull { //nolint:errorlint // false positive due to using go1.12
+			t.Fatalf("expected ErrQueueFull, got: %v", err)
+		}
+	})
+
+	t.Run("listener error", func(t *testing.T) {
+		listenerErr := errors.New("listener error")
+		listener := newTestAsyncListener(OverflowBlock, 10, nil, Listener{
+			OnTx: func(TxData) error {
+				return listenerErr
+			},
+			Commit: func(CommitData) error {
+				return nil
+			},
+		})
+		defer listener.close()
+
+		if err := listener.OnTx(TxData{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var err error
+		waitFor(t, func() bool {
+			err = listener.Commit(CommitData{})
+			return err != nil
+		})
+		if err != listenerErr { //nolint:errorlint // false positive due to using go1.12
+			t.Fatalf("expected listener error, got: %v", err)
+		}
+	})
+
+	t.Run("drain on done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		gate := make(chan struct{})
+		var heights []uint64
+		wg := &sync.WaitGroup{}
+		listener := AsyncListenerWithBackpressure(AsyncListenerOptions{
+			Context:       ctx,
+			BufferSize:    10,
+			DoneWaitGroup: wg,
+			DrainOnDone:   true,
+		}, Listener{
+			StartBlock: func(data StartBlockData) error {
+				<-gate
+				heights = append(heights, data.Height)
+				return nil
+			},
+		})
+
+		for i := uint64(1); i <= 5; i++ {
+			if err := listener.StartBlock(StartBlockData{Height: i}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		cancel()
+		close(gate)
+		wg.Wait()
+
+		if len(heights) != 5 {
+			t.Fatalf("expected the queued blocks to be drained, got %v", heights)
+		}
+	})
+}
+
+type testAsyncListener struct {
+	Listener
+	cancel context.CancelFunc
+	wg     *sync.WaitGroup
+
+	mu    sync.Mutex
+	depth int
+}
+
+func newTestAsyncListener(policy OverflowPolicy, bufferSize int, onDrop func(Packet), listener Listener) *testAsyncListener {
+	ctx, cancel := context.WithCancel(context.Background())
+	res := &testAsyncListener{cancel: cancel, wg: &sync.WaitGroup{}}
+	res.Listener = AsyncListenerWithBackpressure(AsyncListenerOptions{
+		Context:        ctx,
+		BufferSize:     bufferSize,
+		OverflowPolicy: policy,
+		DoneWaitGroup:  res.wg,
+		OnQueueDepth: func(depth int) {
+			res.mu.Lock()
+			defer res.mu.Unlock()
+			res.depth = depth
+		},
+		OnDrop: onDrop,
+	}, listener)
+	return res
+}
+
+func (l *testAsyncListener) close() {
+	l.cancel()
+	l.wg.Wait()
+}
+
+func (l *testAsyncListener) queueDepth() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.depth
+}
+
+func sendHeight(t *testing.T, listener *testAsyncListener, height uint64) {
+	t.Helper()
+	if err := listener.StartBlock(StartBlockData{Height: height}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for i := 0; i < 1000; i++ {
+		if cond() {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("condition not met")
+}
@@ -0,0 +1,61 @@
+package appdata
+
+// BlockBatch contains all the object updates of a block.
+type BlockBatch struct {
+	// Height is the height of the block as passed to StartBlock. It is zero if StartBlock was not called.
+	Height uint64
+
+	// Updates are the object updates of the block in the order they were received.
+	Updates []ObjectUpdateData
+}
+
+// BatchCommitter is implemented by listeners which apply all the object updates of a block at once,
+// for instance in a single database transaction.
+type BatchCommitter interface {
+	// CommitBatch is called with all the object updates of a block when the block is committed.
+	// It is called even if the block has no object updates. If it returns an error, the block
+	// should be considered not to have been committed.
+	CommitBatch(BlockBatch) error
+}
+
+// CommitBatchFunc is a function which implements BatchCommitter.
+type CommitBatchFunc func(BlockBatch) error
+
+// CommitBatch implements BatchCommitter.
+func (f CommitBatchFunc) CommitBatch(batch BlockBatch) error {
+	return f(batch)
+}
+
+// BatchingListener returns a listener which accumulates the object updates of each block and passes them
+// to committer as one batch when Commit is called, before Commit is forwarded to listener. All other
+// callbacks are forwarded to listener as is and listener.OnObjectUpdate is ignored. The returned listener
+// is not safe for concurrent use.
+func BatchingListener(listener Listener, committer BatchCommitter) Listener {
+	var batch BlockBatch
+
+	res := listener
+	res.StartBlock = func(data StartBlockData) error {
+		batch = BlockBatch{Height: data.Height}
+		if listener.StartBlock == nil {
+			return nil
+		}
+		return listener.StartBlock(data)
+	}
+	res.OnObjectUpdate = func(data ObjectUpdateData) error {
+		batch.Updates = append(batch.Updates, data)
+		return nil
+	}
+	res.Commit = func(data CommitData) error {
+		committed := batch
+		batch = BlockBatch{}
+		if err := committer.CommitBatch(committed); err != nil {
+			return err
+		}
+
+		if listener.Commit == nil {
+			return nil
+		}
+		return listener.Commit(data)
+	}
+	return res
+}
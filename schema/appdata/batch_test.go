@@ -0,0 +1,70 @@
+package appdata
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"cosmossdk.io/schema"
+)
+
+func TestBatchingListener(t *testing.T) {
+	var batches []BlockBatch
+	var commits int
+	commitErr := errors.New("commit error")
+	failCommit := false
+	listener := BatchingListener(Listener{
+		OnObjectUpdate: func(ObjectUpdateData) error {
+			t.Fatalf("object updates should not be forwarded")
+			return nil
+		},
+		Commit: func(CommitData) error {
+			commits++
+			return nil
+		},
+	}, CommitBatchFunc(func(batch BlockBatch) error {
+		if failCommit {
+			return commitErr
+		}
+		batches = append(batches, batch)
+		return nil
+	}))
+
+	update1 := ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{{TypeName: "balances", Key: "a"}}}
+	update2 := ObjectUpdateData{ModuleName: "staking", Updates: []schema.ObjectUpdate{{TypeName: "validators", Key: "b"}}}
+	packets := []Packet{
+		StartBlockData{Height: 1},
+		update1,
+		update2,
+		CommitData{},
+		StartBlockData{Height: 2},
+		CommitData{},
+	}
+	for _, p := range packets {
+		if err := listener.SendPacket(p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := []BlockBatch{
+		{Height: 1, Updates: []ObjectUpdateData{update1, update2}},
+		{Height: 2},
+	}
+	if !reflect.DeepEqual(batches, expected) {
+		t.Fatalf("expected batches %v, got %v", expected, batches)
+	}
+	if commits != 2 {
+		t.Fatalf("expected 2 commits to be forwarded, got %d", commits)
+	}
+
+	failCommit = true
+	if err := listener.SendPacket(update1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := listener.Commit(CommitData{}); err != commitErr { //nolint:errorlint // false positive due to using go1.12
+		t.Fatalf("expected commit error, got: %v", err)
+	}
+	if commits != 2 {
+		t.Fatalf("expected commit not to be forwarded after a failed batch")
+	}
+}
@@ -0,0 +1,344 @@
+package appdata
+
+import (
+	"fmt"
+
+	"cosmossdk.io/schema"
+)
+
+// BytesChunkData is a chunk of a large Bytes value of an object update which is delivered in chunks.
+type BytesChunkData struct {
+	// ModuleName is the name of the module.
+	ModuleName string
+
+	// TypeName is the name of the object type of the update.
+	TypeName string
+
+	// UpdateIndex is the index of the update in the Updates of the ObjectUpdateData packet which follows the
+	// chunks.
+	UpdateIndex int
+
+	// FieldName is the name of the value field.
+	FieldName string
+
+	// Size is the total size in bytes of the value.
+	Size int
+
+	// Offset is the offset of the chunk in the value.
+	Offset int
+
+	// Chunk is the content of the chunk. It must not be modified.
+	Chunk []byte
+}
+
+// ChunkOptions are options for ChunkBytes.
+type ChunkOptions struct {
+	// Threshold is the size in bytes above which Bytes values are delivered in chunks. If it is zero, values are
+	// delivered whole.
+	Threshold int
+
+	// ChunkSize is the maximum size in bytes of each chunk. If it is zero, Threshold is used.
+	ChunkSize int
+}
+
+// ChunkBytes returns a listener which delivers the Bytes values of object updates which are larger than
+// opts.Threshold in chunks to listener.OnBytesChunk, so that a single multi-megabyte value, such as wasm code,
+// doesn't have to be encoded or sent as one huge packet, for instance by a remote listener. The chunks of an
+// ObjectUpdateData packet are sent right before it and their values are nil in the packet. Only value fields
+// are chunked, keys are always delivered whole. The chunks share the memory of the original values.
+//
+// If listener.OnBytesChunk is nil, listener is returned as is and values are delivered whole. Callbacks which
+// are nil in listener are also nil in the returned listener, except for InitializeModuleData which is needed
+// to resolve the Bytes fields of object types.
+func ChunkBytes(listener Listener, opts ChunkOptions) (Listener, error) {
+	if opts.Threshold < 0 || opts.ChunkSize < 0 {
+		return Listener{}, fmt.Errorf("chunk threshold and size must not be negative")
+	}
+	if opts.Threshold == 0 || listener.OnBytesChunk == nil || listener.OnObjectUpdate == nil {
+		return listener, nil
+	}
+	chunkSize := opts.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = opts.Threshold
+	}
+
+	// objectTypes holds the object types with Bytes value fields of the initialized modules
+	objectTypes := map[string]map[string]schema.ObjectType{}
+
+	res := listener
+	res.InitializeModuleData = func(data ModuleInitializationData) error {
+		objectTypes[data.ModuleName] = bytesObjectTypes(data.Schema)
+		if listener.InitializeModuleData == nil {
+			return nil
+		}
+		return listener.InitializeModuleData(data)
+	}
+
+	res.OnObjectUpdate = func(data ObjectUpdateData) error {
+		types := objectTypes[data.ModuleName]
+		if len(types) == 0 {
+			return listener.OnObjectUpdate(data)
+		}
+
+		var updates []schema.ObjectUpdate
+		for i, update := range data.Updates {
+			objectType, ok := types[update.TypeName]
+			if !ok || update.Delete {
+				continue
+			}
+
+			value, err := mapBytesValues(objectType, update.Value, func(field schema.Field, fieldValue interface{}) (interface{}, bool, error) {
+				bz, ok := fieldValue.([]byte)
+				if !ok || len(bz) <= opts.Threshold {
+					return fieldValue, false, nil
+				}
+
+				for offset := 0; offset < len(bz); offset += chunkSize {
+					end := offset + chunkSize
+					if end > len(bz) {
+						end = len(bz)
+					}
+					err := listener.OnBytesChunk(BytesChunkData{
+						ModuleName:  data.ModuleName,
+						TypeName:    update.TypeName,
+						UpdateIndex: i,
+						FieldName:   field.Name,
+						Size:        len(bz),
+						Offset:      offset,
+						Chunk:       bz[offset:end:end],
+					})
+					if err != nil {
+						return nil, false, err
+					}
+				}
+				return nil, true, nil
+			})
+			if err != nil {
+				return err
+			}
+			if value == nil {
+				continue
+			}
+
+			if updates == nil {
+				updates = make([]schema.ObjectUpdate, len(data.Updates))
+				copy(updates, data.Updates)
+			}
+			updates[i].Value = value
+		}
+
+		if updates != nil {
+			data.Updates = updates
+		}
+		return listener.OnObjectUpdate(data)
+	}
+
+	return res, nil
+}
+
+// ReassembleBytes returns a listener which reassembles the Bytes values delivered in chunks by ChunkBytes and
+// restores them in the object updates passed to listener.OnObjectUpdate, so that listeners which don't stream
+// large values, for instance to a blob store, don't have to handle OnBytesChunk. Each value is allocated once
+// with its total size. It is an error for the chunks of a value to be incomplete when the ObjectUpdateData
+// packet they belong to is received or for chunks to be left over at Commit.
+func ReassembleBytes(listener Listener) Listener {
+	type valueRef struct {
+		updateIndex int
+		fieldName   string
+	}
+
+	type value struct {
+		bytes    []byte
+		received int
+	}
+
+	objectTypes := map[string]map[string]schema.ObjectType{}
+	var pendingModule string
+	pending := map[valueRef]*value{}
+
+	res := listener
+	res.InitializeModuleData = func(data ModuleInitializationData) error {
+		objectTypes[data.ModuleName] = bytesObjectTypes(data.Schema)
+		if listener.InitializeModuleData == nil {
+			return nil
+		}
+		return listener.InitializeModuleData(data)
+	}
+
+	res.OnBytesChunk = func(data BytesChunkData) error {
+		if len(pending) != 0 && data.ModuleName != pendingModule {
+			return fmt.Errorf("received chunk of module %s while the chunks of module %s are pending", data.ModuleName, pendingModule)
+		}
+		pendingModule = data.ModuleName
+
+		ref := valueRef{updateIndex: data.UpdateIndex, fieldName: data.FieldName}
+		v, ok := pending[ref]
+		if !ok {
+			if data.Size < 0 {
+				return fmt.Errorf("invalid size %d of chunked value of field %s of object type %s in module %s", data.Size, data.FieldName, data.TypeName, data.ModuleName)
+			}
+			v = &value{bytes: make([]byte, data.Size)}
+			pending[ref] = v
+		}
+		if data.Offset < 0 || data.Offset+len(data.Chunk) > len(v.bytes) {
+			return fmt.Errorf("chunk at offset %d exceeds the size %d of the value of field %s of object type %s in module %s", data.Offset, len(v.bytes), data.FieldName, data.TypeName, data.ModuleName)
+		}
+		copy(v.bytes[data.Offset:], data.Chunk)
+		v.received += len(data.Chunk)
+		return nil
+	}
+
+	if listener.OnObjectUpdate != nil {
+		res.OnObjectUpdate = func(data ObjectUpdateData) error {
+			if len(pending) == 0 {
+				return listener.OnObjectUpdate(data)
+			}
+			if data.ModuleName != pendingModule {
+				return fmt.Errorf("received object updates of module %s while the chunks of module %s are pending", data.ModuleName, pendingModule)
+			}
+
+			updates := make([]schema.ObjectUpdate, len(data.Updates))
+			copy(updates, data.Updates)
+			for ref, v := range pending {
+				if ref.updateIndex < 0 || ref.updateIndex >= len(updates) {
+					return fmt.Errorf("chunked value of field %s refers to update %d of %d in module %s", ref.fieldName, ref.updateIndex, len(updates), data.ModuleName)
+				}
+				update := updates[ref.updateIndex]
+				if v.received != len(v.bytes) {
+					return fmt.Errorf("incomplete chunked value of field %s of object type %s in module %s: received %d of %d bytes", ref.fieldName, update.TypeName, data.ModuleName, v.received, len(v.bytes))
+				}
+
+				objectType, ok := objectTypes[data.ModuleName][update.TypeName]
+				if !ok {
+					return fmt.Errorf("can't reassemble chunked value of object type %s in module %s which was not initialized", update.TypeName, data.ModuleName)
+				}
+
+				bz := v.bytes
+				value, err := mapBytesValues(objectType, update.Value, func(field schema.Field, fieldValue interface{}) (interface{}, bool, error) {
+					if field.Name != ref.fieldName {
+						return fieldValue, false, nil
+					}
+					return bz, true, nil
+				})
+				if err != nil {
+					return fmt.Errorf("can't reassemble chunked value of object type %s in module %s: %v", update.TypeName, data.ModuleName, err) //nolint:errorlint // false positive due to using go1.12
+				}
+				if value == nil {
+					return fmt.Errorf("chunked value refers to unknown field %s of object type %s in module %s", ref.fieldName, update.TypeName, data.ModuleName)
+				}
+				updates[ref.updateIndex].Value = value
+			}
+
+			pending = map[valueRef]*value{}
+			data.Updates = updates
+			return listener.OnObjectUpdate(data)
+		}
+	}
+
+	res.Commit = func(data CommitData) error {
+		if len(pending) != 0 {
+			return fmt.Errorf("%d chunked values of module %s were not followed by their object updates", len(pending), pendingModule)
+		}
+		if listener.Commit == nil {
+			return nil
+		}
+		return listener.Commit(data)
+	}
+
+	return res
+}
+
+// bytesObjectTypes returns the object types of the module schema which have Bytes value fields.
+func bytesObjectTypes(moduleSchema schema.ModuleSchema) map[string]schema.ObjectType {
+	res := map[string]schema.ObjectType{}
+	moduleSchema.ObjectTypes(func(objectType schema.ObjectType) bool {
+		for _, field := range objectType.ValueFields {
+			if field.Kind == schema.BytesKind {
+				res[objectType.Name] = objectType
+				break
+			}
+		}
+		return true
+	})
+	return res
+}
+
+// mapBytesValues calls f with the Bytes value fields of an object update value, which can be a single value,
+// a slice of values or a schema.ValueUpdates, and returns a copy of the value with the fields replaced by the
+// values returned by f, or nil if f didn't change any field.
+func mapBytesValues(objectType schema.ObjectType, value interface{}, f func(schema.Field, interface{}) (interface{}, bool, error)) (interface{}, error) {
+	if valueUpdates, ok := value.(schema.ValueUpdates); ok {
+		res := schema.MapValueUpdates{}
+		changed := false
+		var fErr error
+		err := valueUpdates.Iterate(func(fieldName string, fieldValue interface{}) bool {
+			if field, ok := lookupField(objectType.ValueFields, fieldName); ok && field.Kind == schema.BytesKind {
+				var fieldChanged bool
+				fieldValue, fieldChanged, fErr = f(field, fieldValue)
+				if fErr != nil {
+					return false
+				}
+				changed = changed || fieldChanged
+			}
+			res[fieldName] = fieldValue
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		if fErr != nil {
+			return nil, fErr
+		}
+		if !changed {
+			return nil, nil
+		}
+		return res, nil
+	}
+
+	if len(objectType.ValueFields) == 1 {
+		field := objectType.ValueFields[0]
+		if field.Kind != schema.BytesKind {
+			return nil, nil
+		}
+		res, changed, err := f(field, value)
+		if err != nil || !changed {
+			return nil, err
+		}
+		if res == nil {
+			// a nil interface can't be distinguished from an unchanged value
+			return []byte(nil), nil
+		}
+		return res, nil
+	}
+
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected slice of values for value fields, got %T", value)
+	}
+	if len(values) != len(objectType.ValueFields) {
+		return nil, fmt.Errorf("expected %d value fields, got %d values", len(objectType.ValueFields), len(values))
+	}
+
+	var res []interface{}
+	for i, field := range objectType.ValueFields {
+		if field.Kind != schema.BytesKind {
+			continue
+		}
+		fieldValue, changed, err := f(field, values[i])
+		if err != nil {
+			return nil, err
+		}
+		if !changed {
+			continue
+		}
+		if res == nil {
+			res = make([]interface{}, len(values))
+			copy(res, values)
+		}
+		res[i] = fieldValue
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res, nil
+}
@@ -0,0 +1,109 @@
+package appdata
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cosmossdk.io/schema"
+)
+
+func TestChunkBytes(t *testing.T) {
+	modSchema, err := schema.NewModuleSchema([]schema.ObjectType{
+		{
+			Name:      "code",
+			KeyFields: []schema.Field{{Name: "id", Kind: schema.Uint64Kind}},
+			ValueFields: []schema.Field{
+				{Name: "creator", Kind: schema.StringKind},
+				{Name: "wasm", Kind: schema.BytesKind},
+			},
+		},
+		{
+			Name:        "blobs",
+			KeyFields:   []schema.Field{{Name: "hash", Kind: schema.BytesKind}},
+			ValueFields: []schema.Field{{Name: "data", Kind: schema.BytesKind}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wasm := bytes.Repeat([]byte{1, 2, 3}, 10)
+	blob := bytes.Repeat([]byte{4}, 9)
+	updates := ObjectUpdateData{ModuleName: "wasm", Updates: []schema.ObjectUpdate{
+		{TypeName: "code", Key: uint64(1), Value: []interface{}{"alice", wasm}},
+		{TypeName: "code", Key: uint64(2), Value: []interface{}{"bob", []byte{1}}},
+		{TypeName: "code", Key: uint64(3), Value: schema.MapValueUpdates{"wasm": wasm}},
+		{TypeName: "blobs", Key: bytes.Repeat([]byte{5}, 20), Value: blob},
+		{TypeName: "blobs", Key: []byte{6}, Delete: true},
+	}}
+
+	var chunked []Packet
+	recorder := recordingListener(&chunked)
+	recorder.OnBytesChunk = func(data BytesChunkData) error {
+		chunked = append(chunked, data)
+		return nil
+	}
+	listener, err := ChunkBytes(recorder, ChunkOptions{Threshold: 8, ChunkSize: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	packets := []Packet{ModuleInitializationData{ModuleName: "wasm", Schema: modSchema}, updates, CommitData{}}
+	for _, p := range packets {
+		if err := listener.SendPacket(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the initialization, 8 chunks for each wasm value, 3 for the blob, the object updates and the commit
+	if len(chunked) != 1+8+8+3+1+1 {
+		t.Fatalf("unexpected packets: %v", chunked)
+	}
+	first := chunked[1].(BytesChunkData)
+	if first.UpdateIndex != 0 || first.FieldName != "wasm" || first.Size != 30 || first.Offset != 0 || !bytes.Equal(first.Chunk, wasm[:4]) {
+		t.Fatalf("unexpected chunk %+v", first)
+	}
+	last := chunked[19].(BytesChunkData)
+	if last.UpdateIndex != 3 || last.FieldName != "data" || last.Offset != 8 || !bytes.Equal(last.Chunk, blob[8:]) {
+		t.Fatalf("unexpected chunk %+v", last)
+	}
+	sent := chunked[20].(ObjectUpdateData).Updates
+	if sent[0].Value.([]interface{})[1] != nil || sent[2].Value.(schema.MapValueUpdates)["wasm"] != nil || sent[3].Value.([]byte) != nil {
+		t.Fatalf("expected the chunked values to be nil, got %v", sent)
+	}
+	if !bytes.Equal(sent[1].Value.([]interface{})[1].([]byte), []byte{1}) || len(sent[3].Key.([]byte)) != 20 {
+		t.Fatalf("expected small values and keys not to be chunked, got %v", sent)
+	}
+	if updates.Updates[0].Value.([]interface{})[1] == nil {
+		t.Fatalf("expected the original updates not to be modified")
+	}
+
+	// the values are restored by ReassembleBytes
+	var reassembled []Packet
+	reassembler := ReassembleBytes(recordingListener(&reassembled))
+	for _, p := range chunked {
+		if err := reassembler.SendPacket(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(reassembled) != 3 {
+		t.Fatalf("unexpected packets: %v", reassembled)
+	}
+	if received := reassembled[1].(ObjectUpdateData); !reflect.DeepEqual(received, updates) {
+		t.Fatalf("expected %v, got %v", updates, received)
+	}
+
+	// incomplete values are detected
+	reassembler = ReassembleBytes(recordingListener(&reassembled))
+	for _, p := range chunked[:3] {
+		if err := reassembler.SendPacket(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = reassembler.SendPacket(chunked[20])
+	if err == nil || !strings.Contains(err.Error(), "received 8 of 30 bytes") {
+		t.Fatalf("expected incomplete value error, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package appdata
 
 import (
 	"encoding/json"
+	"time"
 
 	"cosmossdk.io/schema"
 )
@@ -14,6 +15,12 @@ type ModuleInitializationData struct {
 
 	// Schema is the schema of the module.
 	Schema schema.ModuleSchema
+
+	// SchemaChanged is true if the module has already been initialized and is initialized again because its
+	// schema changed at runtime, for instance in an upgrade. Listeners which cache module schemas should then
+	// replace the schema of the module, for instance by creating the tables of new object types, rather than
+	// reject a module which is initialized twice.
+	SchemaChanged bool
 }
 
 // StartBlockData represents the data that is passed to a listener when a block is started.
@@ -27,8 +34,92 @@ type StartBlockData struct {
 	// JSON is the JSON representation of the block header. It should generally be a JSON object.
 	// It may be nil if the source does not provide it.
 	HeaderJSON ToJSON
+
+	// Backfill indicates that the block is delivered to catch up a listener which is behind rather than live,
+	// so that listeners can optimize for throughput, for instance by writing in bulk.
+	Backfill bool
+
+	// Header returns the structured fields of the block header. It may be nil if the source does not provide it.
+	Header ToBlockHeader
+
+	// LastCommit returns the votes of the validators for the previous block, from which indexers can compute
+	// the uptime of validators. It may be nil if the source does not provide it.
+	LastCommit ToLastCommit
+
+	// ValidatorUpdates returns the updates of the validator set which result from executing the block. It may
+	// be nil if the source does not provide them.
+	ValidatorUpdates ToValidatorUpdates
+}
+
+// BlockHeader contains the structured fields of a block header. Fields which the source does not know are
+// empty.
+type BlockHeader struct {
+	// ChainID is the ID of the chain.
+	ChainID string
+
+	// Height is the height of the block.
+	Height uint64
+
+	// Time is the time of the block.
+	Time time.Time
+
+	// Hash is the hash of the block.
+	Hash []byte
+
+	// AppHash is the app hash of the state after the previous block.
+	AppHash []byte
+
+	// NextValidatorsHash is the hash of the validator set of the next block.
+	NextValidatorsHash []byte
+
+	// ProposerAddress is the consensus address of the validator which proposed the block.
+	ProposerAddress []byte
+}
+
+// LastCommit contains the votes of the validators for the previous block.
+type LastCommit struct {
+	// Round is the consensus round in which the previous block was committed.
+	Round int32
+
+	// Votes are the votes of the validators of the validator set of the previous block.
+	Votes []Vote
+}
+
+// Vote is the vote of a validator for a block.
+type Vote struct {
+	// ValidatorAddress is the consensus address of the validator.
+	ValidatorAddress []byte
+
+	// Power is the voting power of the validator.
+	Power int64
+
+	// Signed indicates whether the validator signed the block. It is false if the vote of the validator is
+	// absent from the commit.
+	Signed bool
 }
 
+// ValidatorUpdate is an update of the validator set.
+type ValidatorUpdate struct {
+	// PubKeyType is the type of the consensus public key of the validator, such as "ed25519".
+	PubKeyType string
+
+	// PubKey is the consensus public key of the validator.
+	PubKey []byte
+
+	// Power is the new voting power of the validator. Zero means that the validator is removed from the
+	// validator set.
+	Power int64
+}
+
+// ToBlockHeader is a function that lazily returns the structured fields of a block header.
+type ToBlockHeader = func() (BlockHeader, error)
+
+// ToLastCommit is a function that lazily returns the votes for the previous block.
+type ToLastCommit = func() (LastCommit, error)
+
+// ToValidatorUpdates is a function that lazily returns the validator set updates of a block.
+type ToValidatorUpdates = func() ([]ValidatorUpdate, error)
+
 // TxData represents the raw transaction data that is passed to a listener.
 type TxData struct {
 	// TxIndex is the index of the transaction in the block.
@@ -39,8 +130,66 @@ type TxData struct {
 
 	// JSON is the JSON representation of the transaction. It should generally be a JSON object.
 	JSON ToJSON
+
+	// Decoded returns the decoded transaction. It may be nil if the source cannot decode transactions,
+	// in which case TxDecodingListener can be used to decode Bytes with a TxDecoder.
+	Decoded ToDecodedTx
+
+	// Result is the result of executing the transaction. It may be nil if the source does not provide it.
+	Result *TxResult
+}
+
+// DecodedTx contains the structured fields of a decoded transaction which are commonly needed by indexers.
+type DecodedTx struct {
+	// MsgTypeURLs are the type URLs of the messages in the transaction in order.
+	MsgTypeURLs []string
+
+	// Signers are the addresses of the signers of the transaction.
+	Signers [][]byte
+
+	// Fee is the fee paid by the transaction.
+	Fee []Coin
+
+	// FeePayer is the address of the account which paid the fee. It may be nil if the fee payer is not known.
+	FeePayer []byte
+
+	// GasLimit is the gas limit of the transaction.
+	GasLimit uint64
+
+	// Memo is the memo of the transaction.
+	Memo string
+}
+
+// Coin is an amount of a denomination.
+type Coin struct {
+	// Denom is the denomination of the coin.
+	Denom string
+
+	// Amount is the amount of the coin as an integer string.
+	Amount string
+}
+
+// TxResult is the result of executing a transaction.
+type TxResult struct {
+	// Code is the result code of the transaction. Zero indicates success.
+	Code uint32
+
+	// Codespace is the namespace of Code. It is empty if the transaction succeeded.
+	Codespace string
+
+	// GasWanted is the amount of gas requested by the transaction.
+	GasWanted int64
+
+	// GasUsed is the amount of gas consumed by the transaction.
+	GasUsed int64
+
+	// Log is the raw log of the transaction. It may be empty if the source does not provide it.
+	Log string
 }
 
+// ToDecodedTx is a function that lazily returns a decoded transaction.
+type ToDecodedTx = func() (DecodedTx, error)
+
 // EventData represents event data that is passed to a listener.
 type EventData struct {
 	// TxIndex is the index of the transaction in the block to which this event is associated.
@@ -62,8 +211,29 @@ type EventData struct {
 
 	// Data is the JSON representation of the event data. It should generally be a JSON object.
 	Data ToJSON
+
+	// ModuleName is the name of the module which emitted the event. It may be empty if the source does not
+	// know which module emitted the event. If it is set and the module's schema has an EventType whose name
+	// is Type, the attributes of the event should conform to that event type.
+	ModuleName string
+
+	// Attributes returns the typed attributes of the event. It may be nil if the source does not provide them.
+	Attributes ToEventAttributes
 }
 
+// EventAttribute is a typed event attribute.
+type EventAttribute struct {
+	// Key is the key of the attribute, which corresponds to the name of a field in the event type.
+	Key string
+
+	// Value is the value of the attribute. If the event has an EventType, it must be valid for the field
+	// of the event type named Key, otherwise it should be a string.
+	Value interface{}
+}
+
+// ToEventAttributes is a function that lazily returns the typed attributes of an event.
+type ToEventAttributes = func() ([]EventAttribute, error)
+
 // ToBytes is a function that lazily returns the raw byte representation of data.
 type ToBytes = func() ([]byte, error)
 
@@ -91,6 +261,11 @@ type ObjectUpdateData struct {
 
 	// Updates are the object updates.
 	Updates []schema.ObjectUpdate
+
+	// SourceKey is the key of the kv-pair in the store of the module from which the updates were decoded, so
+	// that a Merkle proof of the objects can be fetched from the app later. It is set by the decoding
+	// middleware and is nil if the updates were not decoded from a single kv-pair.
+	SourceKey []byte
 }
 
 // CommitData represents commit data. It is empty for now, but fields could be added later.
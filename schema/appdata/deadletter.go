@@ -0,0 +1,311 @@
+package appdata
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"cosmossdk.io/schema"
+)
+
+// DeadLetter is an object update which a listener persistently failed to apply, together with the context
+// needed to inspect it and re-drive it later.
+type DeadLetter struct {
+	// ModuleName is the name of the module of the update.
+	ModuleName string
+
+	// Schema is the schema of the module as it was initialized when the update failed. It is empty if the
+	// module was not initialized through the listener.
+	Schema schema.ModuleSchema
+
+	// Update is the object update which failed. ValueUpdates are converted to schema.MapValueUpdates.
+	Update schema.ObjectUpdate
+
+	// Height is the height of the block in which the update failed.
+	Height uint64
+
+	// Attempts is the number of times the listener was called with the update.
+	Attempts int
+
+	// Error is the error returned by the listener on the last attempt.
+	Error string
+
+	// Time is the time at which the update was dead-lettered.
+	Time time.Time
+}
+
+// DeadLetterStore stores dead-lettered object updates, for instance in a file or a database table.
+type DeadLetterStore interface {
+	// PutDeadLetter stores the dead letter. If it returns an error, the update is considered lost and the
+	// error is returned to the caller of the listener so that the pipeline halts.
+	PutDeadLetter(DeadLetter) error
+}
+
+// DeadLetterOptions are the options for DeadLetterListener.
+type DeadLetterOptions struct {
+	// Store is the store which dead-lettered updates are written to. It is required.
+	Store DeadLetterStore
+
+	// MaxAttempts is the number of times an update is passed to the listener before it is dead-lettered.
+	// If it is zero or negative, updates are attempted once.
+	MaxAttempts int
+
+	// OnDeadLetter is called after an update has been written to the store. It may be nil.
+	OnDeadLetter func(DeadLetter)
+}
+
+// DeadLetterListener returns a listener which routes object updates that listener persistently fails to
+// apply to a dead-letter store instead of returning the error, so that a single bad update does not halt
+// the whole pipeline. Object updates are retried up to MaxAttempts times. If a batch of updates still
+// fails, each of its updates is retried on its own, again up to MaxAttempts times, so that only the updates
+// which actually fail are dead-lettered. Listeners should therefore apply object updates idempotently.
+// All other packets, and errors of the store itself, are passed through unchanged.
+func DeadLetterListener(listener Listener, opts DeadLetterOptions) Listener {
+	if listener.OnObjectUpdate == nil || opts.Store == nil {
+		return listener
+	}
+
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var height uint64
+	schemas := map[string]schema.ModuleSchema{}
+
+	initializeModuleData := listener.InitializeModuleData
+	listener.InitializeModuleData = func(data ModuleInitializationData) error {
+		schemas[data.ModuleName] = data.Schema
+		if initializeModuleData == nil {
+			return nil
+		}
+		return initializeModuleData(data)
+	}
+
+	startBlock := listener.StartBlock
+	listener.StartBlock = func(data StartBlockData) error {
+		height = data.Height
+		if startBlock == nil {
+			return nil
+		}
+		return startBlock(data)
+	}
+
+	onObjectUpdate := listener.OnObjectUpdate
+	try := func(data ObjectUpdateData) error {
+		var err error
+		for attempt := 0; attempt < maxAttempts; attempt++ {
+			if err = onObjectUpdate(data); err == nil {
+				return nil
+			}
+		}
+		return err
+	}
+
+	listener.OnObjectUpdate = func(data ObjectUpdateData) error {
+		err := try(data)
+		if err == nil {
+			return nil
+		}
+
+		for _, update := range data.Updates {
+			if len(data.Updates) > 1 {
+				// isolate the updates of the batch which fail on their own
+				err = try(ObjectUpdateData{ModuleName: data.ModuleName, Updates: []schema.ObjectUpdate{update}, SourceKey: data.SourceKey})
+				if err == nil {
+					continue
+				}
+			}
+
+			update, convErr := snapshotObjectUpdate(update)
+			if convErr != nil {
+				return convErr
+			}
+
+			letter := DeadLetter{
+				ModuleName: data.ModuleName,
+				Schema:     schemas[data.ModuleName],
+				Update:     update,
+				Height:     height,
+				Attempts:   maxAttempts,
+				Error:      err.Error(),
+				Time:       time.Now().UTC(),
+			}
+			if putErr := opts.Store.PutDeadLetter(letter); putErr != nil {
+				return fmt.Errorf("failed to dead-letter update of %s in module %s: %v, original error: %v", update.TypeName, data.ModuleName, putErr, err) //nolint:errorlint // false positive due to using go1.12
+			}
+			if opts.OnDeadLetter != nil {
+				opts.OnDeadLetter(letter)
+			}
+		}
+		return nil
+	}
+
+	return listener
+}
+
+// deadLetterEntry is the gob representation of a DeadLetter.
+type deadLetterEntry struct {
+	ModuleName string
+	Schema     []byte
+	Update     journalObjectUpdate
+	Height     uint64
+	Attempts   int
+	Error      string
+	Time       time.Time
+}
+
+// DeadLetterWriter returns a DeadLetterStore which appends dead letters to w, such as a file opened for
+// appending, using the same checksummed record format as the journal. If w has a Sync() error method,
+// such as *os.File, it is called after each dead letter is written so that no dead letter is lost.
+func DeadLetterWriter(w io.Writer) DeadLetterStore {
+	return &deadLetterWriter{w: w}
+}
+
+type deadLetterWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (d *deadLetterWriter) PutDeadLetter(letter DeadLetter) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := WriteDeadLetter(d.w, letter); err != nil {
+		return err
+	}
+
+	if syncer, ok := d.w.(interface{ Sync() error }); ok {
+		if err := syncer.Sync(); err != nil {
+			return fmt.Errorf("failed to sync dead letters: %v", err) //nolint:errorlint // false positive due to using go1.12
+		}
+	}
+	return nil
+}
+
+// WriteDeadLetter appends a single dead letter to w.
+func WriteDeadLetter(w io.Writer, letter DeadLetter) error {
+	var schemaBz []byte
+	if hasTypes(letter.Schema) {
+		var err error
+		schemaBz, err = letter.Schema.MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("failed to marshal schema of module %q: %v", letter.ModuleName, err) //nolint:errorlint // false positive due to using go1.12
+		}
+	}
+
+	update, err := toJournalObjectUpdate(letter.Update)
+	if err != nil {
+		return fmt.Errorf("invalid update of %s in module %s: %v", letter.Update.TypeName, letter.ModuleName, err) //nolint:errorlint // false positive due to using go1.12
+	}
+
+	record, err := encodeJournalRecord(deadLetterEntry{
+		ModuleName: letter.ModuleName,
+		Schema:     schemaBz,
+		Update:     update,
+		Height:     letter.Height,
+		Attempts:   letter.Attempts,
+		Error:      letter.Error,
+		Time:       letter.Time,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode dead letter for %s in module %s: %v", letter.Update.TypeName, letter.ModuleName, err) //nolint:errorlint // false positive due to using go1.12
+	}
+
+	// write the record with a single call so that concurrent appenders do not interleave records
+	_, err = w.Write(record)
+	return err
+}
+
+// DeadLetterReader reads dead letters written by DeadLetterWriter or WriteDeadLetter.
+type DeadLetterReader struct {
+	r      io.Reader
+	offset int64
+}
+
+// NewDeadLetterReader returns a DeadLetterReader which reads dead letters from r.
+func NewDeadLetterReader(r io.Reader) *DeadLetterReader {
+	return &DeadLetterReader{r: r}
+}
+
+// Next returns the next dead letter or io.EOF if the end has been reached. A CorruptJournalError is returned
+// if the record is corrupt.
+func (d *DeadLetterReader) Next() (DeadLetter, error) {
+	var entry deadLetterEntry
+	size, err := readJournalRecord(d.r, d.offset, &entry)
+	if err != nil {
+		return DeadLetter{}, err
+	}
+
+	update, err := entry.Update.toObjectUpdate()
+	if err != nil {
+		return DeadLetter{}, CorruptJournalError{Offset: d.offset, Reason: err.Error()}
+	}
+
+	letter := DeadLetter{
+		ModuleName: entry.ModuleName,
+		Update:     update,
+		Height:     entry.Height,
+		Attempts:   entry.Attempts,
+		Error:      entry.Error,
+		Time:       entry.Time,
+	}
+	if len(entry.Schema) != 0 {
+		if err := letter.Schema.UnmarshalJSON(entry.Schema); err != nil {
+			return DeadLetter{}, CorruptJournalError{Offset: d.offset, Reason: fmt.Sprintf("invalid schema for module %q: %v", entry.ModuleName, err)}
+		}
+	}
+
+	d.offset += size
+	return letter, nil
+}
+
+// RedriveDeadLetters reads all the dead letters from r and sends them to listener in order, for instance
+// after the cause of the failures has been fixed. Each module is initialized with its schema from the dead
+// letters before its first update, each update is sent as its own ObjectUpdateData and Commit is sent after
+// the last update. Updates are sent outside of any block, so StartBlock is not called. The number of updates
+// which were applied is returned together with the first error, if any, so that a partial re-drive can be
+// resumed by skipping that many dead letters.
+func RedriveDeadLetters(r io.Reader, listener Listener) (int, error) {
+	reader := NewDeadLetterReader(r)
+	initialized := map[string]bool{}
+	n := 0
+	for {
+		letter, err := reader.Next()
+		if err == io.EOF { //nolint:errorlint // false positive due to using go1.12
+			break
+		}
+		if err != nil {
+			return n, err
+		}
+
+		if !initialized[letter.ModuleName] && hasTypes(letter.Schema) {
+			initialized[letter.ModuleName] = true
+			if err := listener.SendPacket(ModuleInitializationData{ModuleName: letter.ModuleName, Schema: letter.Schema}); err != nil {
+				return n, err
+			}
+		}
+
+		if err := listener.SendPacket(ObjectUpdateData{ModuleName: letter.ModuleName, Updates: []schema.ObjectUpdate{letter.Update}}); err != nil {
+			return n, fmt.Errorf("failed to re-drive update of %s in module %s from height %d: %v", letter.Update.TypeName, letter.ModuleName, letter.Height, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		n++
+	}
+
+	if n == 0 {
+		return 0, nil
+	}
+	return n, listener.SendPacket(CommitData{})
+}
+
+// hasTypes reports whether the module schema defines any type, i.e. whether it is not the empty schema of a
+// module which was not initialized.
+func hasTypes(moduleSchema schema.ModuleSchema) bool {
+	res := false
+	moduleSchema.Types(func(schema.Type) bool {
+		res = true
+		return false
+	})
+	return res
+}
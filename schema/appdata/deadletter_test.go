@@ -0,0 +1,198 @@
+package appdata
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cosmossdk.io/schema"
+)
+
+type memDeadLetterStore struct {
+	letters []DeadLetter
+}
+
+func (m *memDeadLetterStore) PutDeadLetter(letter DeadLetter) error {
+	m.letters = append(m.letters, letter)
+	return nil
+}
+
+func TestDeadLetterListener(t *testing.T) {
+	moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{
+		{
+			Name:        "balances",
+			KeyFields:   []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+			ValueFields: []schema.Field{{Name: "amount", Kind: schema.IntegerStringKind}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the listener fails twice on "flaky" and always on "bad"
+	calls := map[string]int{}
+	var applied []string
+	listener := Listener{
+		OnObjectUpdate: func(data ObjectUpdateData) error {
+			for _, update := range data.Updates {
+				key := update.Key.(string)
+				calls[key]++
+				if key == "bad" || (key == "flaky" && calls[key] <= 2) {
+					return errors.New("cannot apply " + key)
+				}
+			}
+			for _, update := range data.Updates {
+				applied = append(applied, update.Key.(string))
+			}
+			return nil
+		},
+	}
+
+	store := &memDeadLetterStore{}
+	var notified []DeadLetter
+	dl := DeadLetterListener(listener, DeadLetterOptions{
+		Store:        store,
+		MaxAttempts:  3,
+		OnDeadLetter: func(letter DeadLetter) { notified = append(notified, letter) },
+	})
+
+	if err := dl.SendPacket(ModuleInitializationData{ModuleName: "bank", Schema: moduleSchema}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dl.SendPacket(StartBlockData{Height: 7}); err != nil {
+		t.Fatal(err)
+	}
+
+	updates := []schema.ObjectUpdate{
+		{TypeName: "balances", Key: "good", Value: "1"},
+		{TypeName: "balances", Key: "bad", Value: "2"},
+	}
+	if err := dl.SendPacket(ObjectUpdateData{ModuleName: "bank", Updates: updates}); err != nil {
+		t.Fatalf("expected the failing update to be dead-lettered, got %v", err)
+	}
+	if err := dl.SendPacket(ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{{TypeName: "balances", Key: "flaky", Value: "3"}}}); err != nil {
+		t.Fatalf("expected the flaky update to succeed on retry, got %v", err)
+	}
+
+	if !reflect.DeepEqual(applied, []string{"good", "flaky"}) {
+		t.Fatalf("unexpected applied updates %v", applied)
+	}
+	if calls["bad"] != 6 {
+		t.Fatalf("expected the bad update to be attempted 3 times in the batch and 3 times alone, got %d", calls["bad"])
+	}
+
+	if len(store.letters) != 1 || len(notified) != 1 {
+		t.Fatalf("expected one dead letter, got %d stored and %d notified", len(store.letters), len(notified))
+	}
+	letter := store.letters[0]
+	if letter.ModuleName != "bank" || letter.Height != 7 || letter.Attempts != 3 || letter.Error != "cannot apply bad" {
+		t.Fatalf("unexpected dead letter %+v", letter)
+	}
+	if !reflect.DeepEqual(letter.Update, updates[1]) {
+		t.Fatalf("unexpected dead-lettered update %+v", letter.Update)
+	}
+	if letter.Schema.Fingerprint() != moduleSchema.Fingerprint() {
+		t.Fatal("expected the dead letter to contain the module schema")
+	}
+}
+
+func TestDeadLetterListener_storeError(t *testing.T) {
+	dl := DeadLetterListener(Listener{
+		OnObjectUpdate: func(ObjectUpdateData) error { return errors.New("cannot apply") },
+	}, DeadLetterOptions{Store: failingDeadLetterStore{}})
+
+	err := dl.SendPacket(ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{{TypeName: "balances", Key: "k"}}})
+	if err == nil || !strings.Contains(err.Error(), "cannot apply") || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("expected the store and listener errors, got %v", err)
+	}
+}
+
+type failingDeadLetterStore struct{}
+
+func (failingDeadLetterStore) PutDeadLetter(DeadLetter) error { return errors.New("disk full") }
+
+func TestRedriveDeadLetters(t *testing.T) {
+	moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{
+		{
+			Name:        "balances",
+			KeyFields:   []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+			ValueFields: []schema.Field{{Name: "amount", Kind: schema.IntegerStringKind}, {Name: "memo", Kind: schema.StringKind}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	store := DeadLetterWriter(&buf)
+	letters := []DeadLetter{
+		{ModuleName: "bank", Schema: moduleSchema, Height: 3, Attempts: 1, Error: "e1", Update: schema.ObjectUpdate{TypeName: "balances", Key: "a", Value: []interface{}{"1", "x"}}},
+		{ModuleName: "bank", Schema: moduleSchema, Height: 4, Attempts: 2, Error: "e2", Update: schema.ObjectUpdate{
+			TypeName: "balances",
+			Key:      "b",
+			Value:    schema.MapValueUpdates{"amount": "2"},
+		}},
+		{ModuleName: "bank", Schema: moduleSchema, Height: 4, Attempts: 2, Error: "e3", Update: schema.ObjectUpdate{TypeName: "balances", Key: "c", Delete: true}},
+	}
+	for _, letter := range letters {
+		if err := store.PutDeadLetter(letter); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reader := NewDeadLetterReader(bytes.NewReader(buf.Bytes()))
+	for i, expected := range letters {
+		letter, err := reader.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if letter.Schema.Fingerprint() != moduleSchema.Fingerprint() {
+			t.Fatalf("dead letter %d: schema mismatch", i)
+		}
+		letter.Schema, expected.Schema = schema.ModuleSchema{}, schema.ModuleSchema{}
+		if !reflect.DeepEqual(letter, expected) {
+			t.Fatalf("dead letter %d: expected %+v, got %+v", i, expected, letter)
+		}
+	}
+
+	var packets []Packet
+	record := func(p Packet) error {
+		packets = append(packets, p)
+		return nil
+	}
+	n, err := RedriveDeadLetters(bytes.NewReader(buf.Bytes()), Listener{
+		InitializeModuleData: func(data ModuleInitializationData) error { return record(data) },
+		OnObjectUpdate:       func(data ObjectUpdateData) error { return record(data) },
+		Commit:               func(data CommitData) error { return record(data) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 re-driven updates, got %d", n)
+	}
+	if len(packets) != 5 {
+		t.Fatalf("expected module initialization, 3 updates and commit, got %d packets", len(packets))
+	}
+	if _, ok := packets[0].(ModuleInitializationData); !ok {
+		t.Fatalf("expected module initialization first, got %T", packets[0])
+	}
+	if _, ok := packets[4].(CommitData); !ok {
+		t.Fatalf("expected commit last, got %T", packets[4])
+	}
+
+	// a re-drive stops at the first failure and reports how many updates were applied
+	n, err = RedriveDeadLetters(bytes.NewReader(buf.Bytes()), Listener{
+		OnObjectUpdate: func(data ObjectUpdateData) error {
+			if data.Updates[0].Key == "b" {
+				return errors.New("still failing")
+			}
+			return nil
+		},
+	})
+	if err == nil || n != 1 {
+		t.Fatalf("expected the re-drive to stop after 1 update, got %d, %v", n, err)
+	}
+}
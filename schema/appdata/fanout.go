@@ -0,0 +1,101 @@
+package appdata
+
+import (
+	"fmt"
+	"time"
+
+	"cosmossdk.io/schema/logutil"
+)
+
+// FanOutOptions are options for FanOut.
+type FanOutOptions struct {
+	// ContinueOnError isolates the listeners from each other's failures. If it is false, which is the
+	// default, dispatching stops at the first listener which returns an error and the error is returned
+	// to the caller. If it is true, errors are logged with Logger, the remaining listeners are still
+	// called and no error is returned to the caller.
+	ContinueOnError bool
+
+	// Logger is used to log listener errors when ContinueOnError is true. If it is nil, errors are not logged.
+	Logger logutil.Logger
+
+	// OnLatency, if set, is called after each callback of a listener returns with the index of the listener
+	// in the list passed to FanOut, the packet it was called with and how long it took, so that per-listener
+	// latency can be reported as a metric.
+	OnLatency func(listenerIndex int, packet Packet, latency time.Duration)
+}
+
+// FanOut returns a listener which dispatches each callback to all of the listeners in order. A callback of
+// the returned listener is nil if it is nil in all of the listeners and listeners whose callback is nil
+// are skipped. How errors returned by the listeners are handled is configured with opts.
+func FanOut(opts FanOutOptions, listeners ...Listener) Listener {
+	if opts.Logger == nil {
+		opts.Logger = logutil.NoopLogger{}
+	}
+
+	f := fanOut{opts: opts, listeners: listeners}
+	res := Listener{}
+
+	if indexes := f.indexes(func(l Listener) bool { return l.InitializeModuleData != nil }); len(indexes) != 0 {
+		res.InitializeModuleData = func(data ModuleInitializationData) error { return f.dispatch(indexes, data) }
+	}
+	if indexes := f.indexes(func(l Listener) bool { return l.StartBlock != nil }); len(indexes) != 0 {
+		res.StartBlock = func(data StartBlockData) error { return f.dispatch(indexes, data) }
+	}
+	if indexes := f.indexes(func(l Listener) bool { return l.OnTx != nil }); len(indexes) != 0 {
+		res.OnTx = func(data TxData) error { return f.dispatch(indexes, data) }
+	}
+	if indexes := f.indexes(func(l Listener) bool { return l.OnEvent != nil }); len(indexes) != 0 {
+		res.OnEvent = func(data EventData) error { return f.dispatch(indexes, data) }
+	}
+	if indexes := f.indexes(func(l Listener) bool { return l.OnKVPair != nil }); len(indexes) != 0 {
+		res.OnKVPair = func(data KVPairData) error { return f.dispatch(indexes, data) }
+	}
+	if indexes := f.indexes(func(l Listener) bool { return l.OnObjectUpdate != nil }); len(indexes) != 0 {
+		res.OnObjectUpdate = func(data ObjectUpdateData) error { return f.dispatch(indexes, data) }
+	}
+	if indexes := f.indexes(func(l Listener) bool { return l.Commit != nil }); len(indexes) != 0 {
+		res.Commit = func(data CommitData) error { return f.dispatch(indexes, data) }
+	}
+	if indexes := f.indexes(func(l Listener) bool { return l.OnSimulation != nil }); len(indexes) != 0 {
+		res.OnSimulation = func(data SimulationData) error { return f.dispatch(indexes, data) }
+	}
+	if indexes := f.indexes(func(l Listener) bool { return l.OnBytesChunk != nil }); len(indexes) != 0 {
+		res.OnBytesChunk = func(data BytesChunkData) error { return f.dispatch(indexes, data) }
+	}
+
+	return res
+}
+
+type fanOut struct {
+	opts      FanOutOptions
+	listeners []Listener
+}
+
+// indexes returns the indexes of the listeners for which has returns true.
+func (f fanOut) indexes(has func(Listener) bool) []int {
+	var res []int
+	for i, listener := range f.listeners {
+		if has(listener) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+func (f fanOut) dispatch(indexes []int, packet Packet) error {
+	for _, i := range indexes {
+		start := time.Now()
+		err := f.listeners[i].SendPacket(packet)
+		if f.opts.OnLatency != nil {
+			f.opts.OnLatency(i, packet, time.Since(start))
+		}
+
+		if err != nil {
+			if !f.opts.ContinueOnError {
+				return fmt.Errorf("listener %d: %v", i, err) //nolint:errorlint // false positive due to using go1.12
+			}
+			f.opts.Logger.Error("listener failed, continuing with the remaining listeners", "listener", i, "error", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package appdata
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFanOut(t *testing.T) {
+	var calls []string
+	listener := func(name string, err error) Listener {
+		return Listener{
+			StartBlock: func(data StartBlockData) error {
+				calls = append(calls, fmt.Sprintf("%s:%d", name, data.Height))
+				return err
+			},
+		}
+	}
+	testErr := errors.New("test error")
+
+	t.Run("nil callbacks", func(t *testing.T) {
+		res := FanOut(FanOutOptions{}, listener("a", nil), Listener{Commit: func(CommitData) error { return nil }})
+		if res.StartBlock == nil || res.Commit == nil {
+			t.Fatalf("expected callbacks of any listener to be set")
+		}
+		if res.OnTx != nil || res.OnKVPair != nil {
+			t.Fatalf("expected callbacks of no listener to be nil")
+		}
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		calls = nil
+		res := FanOut(FanOutOptions{}, listener("a", nil), listener("b", testErr), listener("c", nil))
+		err := res.StartBlock(StartBlockData{Height: 1})
+		if err == nil || !strings.Contains(err.Error(), "listener 1: test error") {
+			t.Fatalf("expected listener error, got: %v", err)
+		}
+		if !reflect.DeepEqual(calls, []string{"a:1", "b:1"}) {
+			t.Fatalf("unexpected calls: %v", calls)
+		}
+	})
+
+	t.Run("continue on error", func(t *testing.T) {
+		calls = nil
+		var latencies []int
+		res := FanOut(FanOutOptions{
+			ContinueOnError: true,
+			OnLatency: func(listenerIndex int, packet Packet, latency time.Duration) {
+				if _, ok := packet.(StartBlockData); !ok {
+					t.Errorf("unexpected packet %T", packet)
+				}
+				latencies = append(latencies, listenerIndex)
+			},
+		}, listener("a", nil), listener("b", testErr), Listener{}, listener("c", nil))
+		if err := res.StartBlock(StartBlockData{Height: 2}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(calls, []string{"a:2", "b:2", "c:2"}) {
+			t.Fatalf("unexpected calls: %v", calls)
+		}
+		if !reflect.DeepEqual(latencies, []int{0, 1, 3}) {
+			t.Fatalf("unexpected latency reports: %v", latencies)
+		}
+	})
+}
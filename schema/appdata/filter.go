@@ -0,0 +1,171 @@
+package appdata
+
+import (
+	"fmt"
+
+	"cosmossdk.io/schema"
+)
+
+// FilterOptions are options for Filter. Empty options do not filter anything.
+type FilterOptions struct {
+	// Modules, if non-empty, is the list of modules whose data is forwarded. InitializeModuleData,
+	// OnKVPair and OnObjectUpdate data for other modules is dropped.
+	Modules []string
+
+	// ExcludeModules is a list of modules whose InitializeModuleData, OnKVPair and OnObjectUpdate data is
+	// dropped. It is applied after Modules.
+	ExcludeModules []string
+
+	// ObjectTypes, if non-empty, maps module names to the names of the object types of the module whose
+	// updates are forwarded. Updates of other object types of these modules are dropped and the schemas
+	// passed to InitializeModuleData only contain these object types. Modules which are not in the map are
+	// not restricted.
+	ObjectTypes map[string][]string
+
+	// EventTypes, if non-empty, is the list of event types which are forwarded to OnEvent. Events of
+	// other types are dropped.
+	EventTypes []string
+}
+
+// Filter returns a listener which only forwards the data selected by opts to listener, so that an indexer
+// which is only interested in some modules, object types or events does not need to process the rest.
+// When used together with decoding.Middleware, Filter should wrap the middleware so that key-value pairs of
+// filtered modules are dropped before they are decoded. Callbacks which are nil in listener are also nil in
+// the returned listener.
+func Filter(listener Listener, opts FilterOptions) Listener {
+	modules := stringSet(opts.Modules)
+	excludedModules := stringSet(opts.ExcludeModules)
+	eventTypes := stringSet(opts.EventTypes)
+	objectTypes := make(map[string]map[string]bool, len(opts.ObjectTypes))
+	for moduleName, typeNames := range opts.ObjectTypes {
+		objectTypes[moduleName] = stringSet(typeNames)
+	}
+
+	includeModule := func(moduleName string) bool {
+		return (modules == nil || modules[moduleName]) && !excludedModules[moduleName]
+	}
+
+	res := listener
+
+	if listener.InitializeModuleData != nil {
+		res.InitializeModuleData = func(data ModuleInitializationData) error {
+			if !includeModule(data.ModuleName) {
+				return nil
+			}
+
+			if typeNames, ok := objectTypes[data.ModuleName]; ok {
+				moduleSchema, err := filterModuleSchema(data.Schema, typeNames)
+				if err != nil {
+					return fmt.Errorf("can't filter schema of module %q: %v", data.ModuleName, err) //nolint:errorlint // false positive due to using go1.12
+				}
+				data.Schema = moduleSchema
+			}
+
+			return listener.InitializeModuleData(data)
+		}
+	}
+
+	if listener.OnEvent != nil && eventTypes != nil {
+		res.OnEvent = func(data EventData) error {
+			if !eventTypes[data.Type] {
+				return nil
+			}
+			return listener.OnEvent(data)
+		}
+	}
+
+	if listener.OnKVPair != nil && (modules != nil || excludedModules != nil) {
+		res.OnKVPair = func(data KVPairData) error {
+			var updates []ModuleKVPairUpdate
+			for _, update := range data.Updates {
+				if includeModule(update.ModuleName) {
+					updates = append(updates, update)
+				}
+			}
+
+			if len(updates) == 0 {
+				return nil
+			}
+			return listener.OnKVPair(KVPairData{Updates: updates})
+		}
+	}
+
+	if listener.OnObjectUpdate != nil {
+		res.OnObjectUpdate = func(data ObjectUpdateData) error {
+			if !includeModule(data.ModuleName) {
+				return nil
+			}
+
+			typeNames, ok := objectTypes[data.ModuleName]
+			if !ok {
+				return listener.OnObjectUpdate(data)
+			}
+
+			var updates []schema.ObjectUpdate
+			for _, update := range data.Updates {
+				if typeNames[update.TypeName] {
+					updates = append(updates, update)
+				}
+			}
+
+			if len(updates) == 0 {
+				return nil
+			}
+			return listener.OnObjectUpdate(ObjectUpdateData{ModuleName: data.ModuleName, Updates: updates, SourceKey: data.SourceKey})
+		}
+	}
+
+	return res
+}
+
+// filterModuleSchema returns a module schema which only contains the object types with the given names
+// along with the enum and struct types they reference and the reserved type names of the module schema.
+func filterModuleSchema(moduleSchema schema.ModuleSchema, typeNames map[string]bool) (schema.ModuleSchema, error) {
+	var objectTypes []schema.ObjectType
+	for typeName := range typeNames {
+		typ, found := moduleSchema.LookupType(typeName)
+		objectType, ok := typ.(schema.ObjectType)
+		if !found || !ok {
+			return schema.ModuleSchema{}, fmt.Errorf("object type %q not found", typeName)
+		}
+		objectTypes = append(objectTypes, objectType)
+	}
+
+	res, err := schema.NewModuleSchema(objectTypes)
+	if err != nil {
+		return schema.ModuleSchema{}, err
+	}
+
+	var eventTypes []schema.EventType
+	moduleSchema.EventTypes(func(eventType schema.EventType) bool {
+		eventTypes = append(eventTypes, eventType)
+		return true
+	})
+	if len(eventTypes) != 0 {
+		res, err = res.WithEventTypes(eventTypes...)
+		if err != nil {
+			return schema.ModuleSchema{}, err
+		}
+	}
+
+	if reserved := moduleSchema.ReservedTypeNames(); len(reserved) != 0 {
+		if res, err = res.WithReservedTypeNames(reserved...); err != nil {
+			return schema.ModuleSchema{}, err
+		}
+	}
+
+	return res.WithVersion(moduleSchema.Version()), nil
+}
+
+// stringSet returns a set of the values or nil if values is empty.
+func stringSet(values []string) map[string]bool {
+	if len(values) == 0 {
+		return nil
+	}
+
+	res := make(map[string]bool, len(values))
+	for _, value := range values {
+		res[value] = true
+	}
+	return res
+}
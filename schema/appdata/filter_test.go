@@ -0,0 +1,111 @@
+package appdata
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"cosmossdk.io/schema"
+)
+
+func TestFilter(t *testing.T) {
+	bankSchema, err := schema.NewModuleSchema([]schema.ObjectType{
+		{Name: "balances", KeyFields: []schema.Field{{Name: "address", Kind: schema.AddressKind}}},
+		{Name: "supply", KeyFields: []schema.Field{{Name: "denom", Kind: schema.StringKind}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var received []Packet
+	listener := Filter(recordingListener(&received), FilterOptions{
+		Modules:     []string{"bank", "staking"},
+		ObjectTypes: map[string][]string{"bank": {"balances"}},
+		EventTypes:  []string{"transfer"},
+	})
+
+	packets := []Packet{
+		ModuleInitializationData{ModuleName: "bank", Schema: bankSchema},
+		ModuleInitializationData{ModuleName: "gov", Schema: bankSchema},
+		EventData{Type: "transfer"},
+		EventData{Type: "vote"},
+		KVPairData{Updates: []ModuleKVPairUpdate{
+			{ModuleName: "bank", Update: schema.KVPairUpdate{Key: []byte("a")}},
+			{ModuleName: "gov", Update: schema.KVPairUpdate{Key: []byte("b")}},
+		}},
+		KVPairData{Updates: []ModuleKVPairUpdate{{ModuleName: "gov", Update: schema.KVPairUpdate{Key: []byte("c")}}}},
+		ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{
+			{TypeName: "balances", Key: []byte{1}},
+			{TypeName: "supply", Key: "uatom"},
+		}},
+		ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{{TypeName: "supply", Key: "uatom"}}},
+		ObjectUpdateData{ModuleName: "staking", Updates: []schema.ObjectUpdate{{TypeName: "validators", Key: "val"}}},
+		ObjectUpdateData{ModuleName: "gov", Updates: []schema.ObjectUpdate{{TypeName: "proposals", Key: uint64(1)}}},
+		CommitData{},
+	}
+	for _, p := range packets {
+		if err := listener.SendPacket(p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(received) != 6 {
+		t.Fatalf("expected 6 packets, got %d: %v", len(received), received)
+	}
+
+	initData := received[0].(ModuleInitializationData)
+	var typeNames []string
+	initData.Schema.ObjectTypes(func(objectType schema.ObjectType) bool {
+		typeNames = append(typeNames, objectType.Name)
+		return true
+	})
+	if initData.ModuleName != "bank" || !reflect.DeepEqual(typeNames, []string{"balances"}) {
+		t.Fatalf("expected bank schema with only balances, got %s %v", initData.ModuleName, typeNames)
+	}
+
+	expected := []Packet{
+		EventData{Type: "transfer"},
+		KVPairData{Updates: []ModuleKVPairUpdate{{ModuleName: "bank", Update: schema.KVPairUpdate{Key: []byte("a")}}}},
+		ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{{TypeName: "balances", Key: []byte{1}}}},
+		ObjectUpdateData{ModuleName: "staking", Updates: []schema.ObjectUpdate{{TypeName: "validators", Key: "val"}}},
+		CommitData{},
+	}
+	if !reflect.DeepEqual(received[1:], expected) {
+		t.Fatalf("expected %v, got %v", expected, received[1:])
+	}
+
+	t.Run("unknown object type", func(t *testing.T) {
+		listener := Filter(recordingListener(&received), FilterOptions{
+			ObjectTypes: map[string][]string{"bank": {"params"}},
+		})
+		err := listener.InitializeModuleData(ModuleInitializationData{ModuleName: "bank", Schema: bankSchema})
+		if err == nil || !strings.Contains(err.Error(), "object type \"params\" not found") {
+			t.Fatalf("expected object type not found error, got: %v", err)
+		}
+	})
+
+	t.Run("exclude modules", func(t *testing.T) {
+		var received []Packet
+		listener := Filter(recordingListener(&received), FilterOptions{ExcludeModules: []string{"gov"}})
+		for _, p := range packets[:6] {
+			if err := listener.SendPacket(p); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		expected := []Packet{
+			packets[0], packets[2], packets[3],
+			KVPairData{Updates: []ModuleKVPairUpdate{{ModuleName: "bank", Update: schema.KVPairUpdate{Key: []byte("a")}}}},
+		}
+		if !reflect.DeepEqual(received, expected) {
+			t.Fatalf("expected %v, got %v", expected, received)
+		}
+	})
+
+	t.Run("nil callbacks", func(t *testing.T) {
+		listener := Filter(Listener{}, FilterOptions{Modules: []string{"bank"}, EventTypes: []string{"transfer"}})
+		if listener.OnKVPair != nil || listener.OnEvent != nil || listener.OnObjectUpdate != nil {
+			t.Fatalf("expected nil callbacks to stay nil")
+		}
+	})
+}
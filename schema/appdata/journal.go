@@ -0,0 +1,638 @@
+package appdata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"cosmossdk.io/schema"
+)
+
+// The journal is an append-only sequence of records, each consisting of a 4 byte big-endian payload length,
+// a 4 byte big-endian CRC-32 (IEEE) checksum of the payload and the payload, which is a self-contained gob
+// encoding of a journalEntry. Object update keys and values and event attribute values are stored in the
+// binary encoding of schema.EncodeValue rather than as gob interface values. Because every record is
+// self-contained, journals can be appended to by separate processes and truncated at any record boundary.
+
+const journalRecordHeaderSize = 8
+
+// CorruptJournalError is returned by JournalReader when a record is truncated, fails its checksum or
+// cannot be decoded. A journal whose last record was only partially written, for instance because the
+// process crashed, can be repaired by truncating it to Offset.
+type CorruptJournalError struct {
+	// Offset is the byte offset of the start of the corrupt record in the journal.
+	Offset int64
+
+	// Reason describes why the record is corrupt.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e CorruptJournalError) Error() string {
+	return fmt.Sprintf("corrupt journal record at offset %d: %s", e.Offset, e.Reason)
+}
+
+// journalEntry is the gob representation of a packet. Exactly one field is set.
+type journalEntry struct {
+	ModuleInitialization *journalModuleInitialization
+	StartBlock           *journalStartBlock
+	Tx                   *journalTx
+	Event                *journalEvent
+	KVPair               *KVPairData
+	ObjectUpdate         *journalObjectUpdateData
+	Commit               bool
+}
+
+type journalModuleInitialization struct {
+	ModuleName    string
+	Schema        []byte
+	SchemaChanged bool
+}
+
+type journalStartBlock struct {
+	Height           uint64
+	HeaderBytes      *journalBytes
+	HeaderJSON       *journalBytes
+	Header           *journalBlockHeader
+	LastCommit       *journalLastCommit
+	ValidatorUpdates *journalValidatorUpdates
+}
+
+// journalBlockHeader stores the result of a ToBlockHeader function. A nil *journalBlockHeader means that the
+// function was nil.
+type journalBlockHeader struct {
+	// Set is always true so that gob does not omit empty values.
+	Set    bool
+	Header BlockHeader
+}
+
+// journalLastCommit stores the result of a ToLastCommit function. A nil *journalLastCommit means that the
+// function was nil.
+type journalLastCommit struct {
+	// Set is always true so that gob does not omit empty values.
+	Set        bool
+	LastCommit LastCommit
+}
+
+// journalValidatorUpdates stores the result of a ToValidatorUpdates function. A nil *journalValidatorUpdates
+// means that the function was nil.
+type journalValidatorUpdates struct {
+	// Set is always true so that gob does not omit empty values.
+	Set     bool
+	Updates []ValidatorUpdate
+}
+
+type journalTx struct {
+	TxIndex int32
+	Bytes   *journalBytes
+	JSON    *journalBytes
+	Decoded *journalDecodedTx
+	Result  *TxResult
+}
+
+// journalDecodedTx stores the result of a ToDecodedTx function. A nil *journalDecodedTx means that the
+// function was nil.
+type journalDecodedTx struct {
+	// Set is always true so that gob does not omit empty values.
+	Set bool
+	Tx  DecodedTx
+}
+
+type journalEvent struct {
+	TxIndex    int32
+	MsgIndex   uint32
+	EventIndex uint32
+	Type       string
+	Data       *journalBytes
+	ModuleName string
+	Attributes *journalAttributes
+}
+
+// journalAttributes stores the result of a ToEventAttributes function. A nil *journalAttributes means that
+// the function was nil.
+type journalAttributes struct {
+	// Set is always true so that gob does not omit empty values.
+	Set        bool
+	Attributes []journalEventAttribute
+}
+
+// journalEventAttribute is an EventAttribute whose value is encoded with schema.EncodeValue.
+type journalEventAttribute struct {
+	Key   string
+	Value []byte
+}
+
+type journalObjectUpdateData struct {
+	ModuleName string
+	Updates    []journalObjectUpdate
+	SourceKey  []byte
+}
+
+// journalObjectUpdate is a schema.ObjectUpdate whose key and value are encoded with schema.EncodeValue.
+// If ValueUpdates is set, the value is a schema.MapValueUpdates whose values are encoded separately.
+type journalObjectUpdate struct {
+	TypeName     string
+	Key          []byte
+	Value        []byte
+	ValueUpdates *journalValueUpdates
+	Delete       bool
+}
+
+type journalValueUpdates struct {
+	// Set is always true so that gob does not omit empty values.
+	Set    bool
+	Values map[string][]byte
+}
+
+// journalBytes stores the result of a ToBytes or ToJSON function. A nil *journalBytes means that the
+// function was nil.
+type journalBytes struct {
+	// Set is always true so that gob does not omit empty values.
+	Set   bool
+	Bytes []byte
+}
+
+// JournalListener returns a listener which writes every packet it receives to w in the journal format
+// before forwarding it to listener, so that the packets can later be replayed with ReplayJournal, for
+// instance to rebuild a downstream database without re-syncing the chain. listener may be empty to only
+// write the journal. All callbacks of the returned listener are set.
+//
+// Lazy ToBytes and ToJSON functions are evaluated when the packet is written and ValueUpdates in object
+// updates are replayed as schema.MapValueUpdates. Values are replayed as decoded by schema.DecodeValue, so
+// times are replayed in UTC and nil slices and maps as empty ones. If w has a Sync() error method, such as
+// *os.File, it is called before Commit is forwarded so that the journal is durable before the listener
+// commits.
+func JournalListener(w io.Writer, listener Listener) Listener {
+	write := func(p Packet) error {
+		if err := WriteJournalPacket(w, p); err != nil {
+			return err
+		}
+		return listener.SendPacket(p)
+	}
+
+	return Listener{
+		InitializeModuleData: func(data ModuleInitializationData) error { return write(data) },
+		StartBlock:           func(data StartBlockData) error { return write(data) },
+		OnTx:                 func(data TxData) error { return write(data) },
+		OnEvent:              func(data EventData) error { return write(data) },
+		OnKVPair:             func(data KVPairData) error { return write(data) },
+		OnObjectUpdate:       func(data ObjectUpdateData) error { return write(data) },
+		// simulations are not committed data so they are forwarded without being journaled
+		OnSimulation: func(data SimulationData) error { return listener.SendPacket(data) },
+		Commit: func(data CommitData) error {
+			if err := WriteJournalPacket(w, data); err != nil {
+				return err
+			}
+
+			if syncer, ok := w.(interface{ Sync() error }); ok {
+				if err := syncer.Sync(); err != nil {
+					return fmt.Errorf("failed to sync journal: %v", err) //nolint:errorlint // false positive due to using go1.12
+				}
+			}
+
+			return listener.SendPacket(data)
+		},
+	}
+}
+
+// WriteJournalPacket appends a single packet to w in the journal format.
+func WriteJournalPacket(w io.Writer, p Packet) error {
+	entry, err := toJournalEntry(p)
+	if err != nil {
+		return err
+	}
+
+	record, err := encodeJournalRecord(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal entry for %T: %v", p, err) //nolint:errorlint // false positive due to using go1.12
+	}
+
+	// write the record with a single call so that concurrent appenders do not interleave records
+	_, err = w.Write(record)
+	return err
+}
+
+// encodeJournalRecord returns the journal record whose payload is the gob encoding of v.
+func encodeJournalRecord(v interface{}) ([]byte, error) {
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(v); err != nil {
+		return nil, err
+	}
+
+	record := make([]byte, journalRecordHeaderSize, journalRecordHeaderSize+payload.Len())
+	binary.BigEndian.PutUint32(record[0:4], uint32(payload.Len()))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload.Bytes()))
+	return append(record, payload.Bytes()...), nil
+}
+
+// JournalReader reads packets from a journal written by JournalListener or WriteJournalPacket.
+type JournalReader struct {
+	r      io.Reader
+	offset int64
+}
+
+// NewJournalReader returns a JournalReader which reads packets from r.
+func NewJournalReader(r io.Reader) *JournalReader {
+	return &JournalReader{r: r}
+}
+
+// Next returns the next packet in the journal or io.EOF if the end of the journal has been reached.
+// A CorruptJournalError is returned if the record is corrupt.
+func (j *JournalReader) Next() (Packet, error) {
+	var entry journalEntry
+	size, err := readJournalRecord(j.r, j.offset, &entry)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := fromJournalEntry(entry)
+	if err != nil {
+		return nil, CorruptJournalError{Offset: j.offset, Reason: err.Error()}
+	}
+
+	j.offset += size
+	return p, nil
+}
+
+// readJournalRecord reads the journal record at offset from r, decodes its payload into v and returns the
+// size of the record. io.EOF is returned at the end of the journal.
+func readJournalRecord(r io.Reader, offset int64, v interface{}) (int64, error) {
+	var header [journalRecordHeaderSize]byte
+	n, err := io.ReadFull(r, header[:])
+	if err == io.EOF { //nolint:errorlint // false positive due to using go1.12
+		return 0, io.EOF
+	}
+	if err == io.ErrUnexpectedEOF { //nolint:errorlint // false positive due to using go1.12
+		return 0, CorruptJournalError{Offset: offset, Reason: fmt.Sprintf("truncated record header of %d bytes", n)}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(header[0:4]))
+	n, err = io.ReadFull(r, payload)
+	if err == io.EOF || err == io.ErrUnexpectedEOF { //nolint:errorlint // false positive due to using go1.12
+		return 0, CorruptJournalError{Offset: offset, Reason: fmt.Sprintf("truncated record payload of %d/%d bytes", n, len(payload))}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+		return 0, CorruptJournalError{Offset: offset, Reason: "checksum mismatch"}
+	}
+
+	if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(v); err != nil {
+		return 0, CorruptJournalError{Offset: offset, Reason: err.Error()}
+	}
+
+	return int64(journalRecordHeaderSize + len(payload)), nil
+}
+
+// ReplayJournal reads all the packets in the journal from r and sends them to listener in order.
+func ReplayJournal(r io.Reader, listener Listener) error {
+	reader := NewJournalReader(r)
+	for {
+		p, err := reader.Next()
+		if err == io.EOF { //nolint:errorlint // false positive due to using go1.12
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := listener.SendPacket(p); err != nil {
+			return err
+		}
+	}
+}
+
+func toJournalEntry(p Packet) (journalEntry, error) {
+	switch data := p.(type) {
+	case ModuleInitializationData:
+		bz, err := data.Schema.MarshalJSON()
+		if err != nil {
+			return journalEntry{}, fmt.Errorf("failed to marshal schema of module %q: %v", data.ModuleName, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		return journalEntry{ModuleInitialization: &journalModuleInitialization{
+			ModuleName:    data.ModuleName,
+			Schema:        bz,
+			SchemaChanged: data.SchemaChanged,
+		}}, nil
+	case StartBlockData:
+		headerBytes, err := evalToBytes(data.HeaderBytes)
+		if err != nil {
+			return journalEntry{}, err
+		}
+		headerJSON, err := evalToJSON(data.HeaderJSON)
+		if err != nil {
+			return journalEntry{}, err
+		}
+		entry := &journalStartBlock{Height: data.Height, HeaderBytes: headerBytes, HeaderJSON: headerJSON}
+		if data.Header != nil {
+			header, err := data.Header()
+			if err != nil {
+				return journalEntry{}, err
+			}
+			entry.Header = &journalBlockHeader{Set: true, Header: header}
+		}
+		if data.LastCommit != nil {
+			lastCommit, err := data.LastCommit()
+			if err != nil {
+				return journalEntry{}, err
+			}
+			entry.LastCommit = &journalLastCommit{Set: true, LastCommit: lastCommit}
+		}
+		if data.ValidatorUpdates != nil {
+			updates, err := data.ValidatorUpdates()
+			if err != nil {
+				return journalEntry{}, err
+			}
+			entry.ValidatorUpdates = &journalValidatorUpdates{Set: true, Updates: updates}
+		}
+		return journalEntry{StartBlock: entry}, nil
+	case TxData:
+		bz, err := evalToBytes(data.Bytes)
+		if err != nil {
+			return journalEntry{}, err
+		}
+		js, err := evalToJSON(data.JSON)
+		if err != nil {
+			return journalEntry{}, err
+		}
+		var decoded *journalDecodedTx
+		if data.Decoded != nil {
+			tx, err := data.Decoded()
+			if err != nil {
+				return journalEntry{}, err
+			}
+			decoded = &journalDecodedTx{Set: true, Tx: tx}
+		}
+		return journalEntry{Tx: &journalTx{TxIndex: data.TxIndex, Bytes: bz, JSON: js, Decoded: decoded, Result: data.Result}}, nil
+	case EventData:
+		js, err := evalToJSON(data.Data)
+		if err != nil {
+			return journalEntry{}, err
+		}
+		var attrs *journalAttributes
+		if data.Attributes != nil {
+			res, err := data.Attributes()
+			if err != nil {
+				return journalEntry{}, err
+			}
+			attrs = &journalAttributes{Set: true, Attributes: make([]journalEventAttribute, len(res))}
+			for i, attr := range res {
+				value, err := schema.EncodeValue(attr.Value)
+				if err != nil {
+					return journalEntry{}, fmt.Errorf("invalid value of attribute %q of event %q: %v", attr.Key, data.Type, err) //nolint:errorlint // false positive due to using go1.12
+				}
+				attrs.Attributes[i] = journalEventAttribute{Key: attr.Key, Value: value}
+			}
+		}
+		return journalEntry{Event: &journalEvent{
+			TxIndex:    data.TxIndex,
+			MsgIndex:   data.MsgIndex,
+			EventIndex: data.EventIndex,
+			Type:       data.Type,
+			Data:       js,
+			ModuleName: data.ModuleName,
+			Attributes: attrs,
+		}}, nil
+	case KVPairData:
+		return journalEntry{KVPair: &data}, nil
+	case ObjectUpdateData:
+		updates := make([]journalObjectUpdate, len(data.Updates))
+		for i, update := range data.Updates {
+			var err error
+			updates[i], err = toJournalObjectUpdate(update)
+			if err != nil {
+				return journalEntry{}, fmt.Errorf("invalid update of %s in module %s: %v", update.TypeName, data.ModuleName, err) //nolint:errorlint // false positive due to using go1.12
+			}
+		}
+		return journalEntry{ObjectUpdate: &journalObjectUpdateData{ModuleName: data.ModuleName, Updates: updates, SourceKey: data.SourceKey}}, nil
+	case CommitData:
+		return journalEntry{Commit: true}, nil
+	default:
+		return journalEntry{}, fmt.Errorf("unsupported packet type %T", p)
+	}
+}
+
+func fromJournalEntry(entry journalEntry) (Packet, error) {
+	switch {
+	case entry.ModuleInitialization != nil:
+		var moduleSchema schema.ModuleSchema
+		if err := moduleSchema.UnmarshalJSON(entry.ModuleInitialization.Schema); err != nil {
+			return nil, fmt.Errorf("invalid schema for module %q: %v", entry.ModuleInitialization.ModuleName, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		return ModuleInitializationData{
+			ModuleName:    entry.ModuleInitialization.ModuleName,
+			Schema:        moduleSchema,
+			SchemaChanged: entry.ModuleInitialization.SchemaChanged,
+		}, nil
+	case entry.StartBlock != nil:
+		return StartBlockData{
+			Height:           entry.StartBlock.Height,
+			HeaderBytes:      entry.StartBlock.HeaderBytes.toBytes(),
+			HeaderJSON:       entry.StartBlock.HeaderJSON.toJSON(),
+			Header:           entry.StartBlock.Header.toBlockHeader(),
+			LastCommit:       entry.StartBlock.LastCommit.toLastCommit(),
+			ValidatorUpdates: entry.StartBlock.ValidatorUpdates.toValidatorUpdates(),
+		}, nil
+	case entry.Tx != nil:
+		return TxData{
+			TxIndex: entry.Tx.TxIndex,
+			Bytes:   entry.Tx.Bytes.toBytes(),
+			JSON:    entry.Tx.JSON.toJSON(),
+			Decoded: entry.Tx.Decoded.toDecodedTx(),
+			Result:  entry.Tx.Result,
+		}, nil
+	case entry.Event != nil:
+		attrs, err := entry.Event.Attributes.toEventAttributes()
+		if err != nil {
+			return nil, err
+		}
+		return EventData{
+			TxIndex:    entry.Event.TxIndex,
+			MsgIndex:   entry.Event.MsgIndex,
+			EventIndex: entry.Event.EventIndex,
+			Type:       entry.Event.Type,
+			Data:       entry.Event.Data.toJSON(),
+			ModuleName: entry.Event.ModuleName,
+			Attributes: attrs,
+		}, nil
+	case entry.KVPair != nil:
+		return *entry.KVPair, nil
+	case entry.ObjectUpdate != nil:
+		updates := make([]schema.ObjectUpdate, len(entry.ObjectUpdate.Updates))
+		for i, update := range entry.ObjectUpdate.Updates {
+			var err error
+			if updates[i], err = update.toObjectUpdate(); err != nil {
+				return nil, err
+			}
+		}
+		return ObjectUpdateData{ModuleName: entry.ObjectUpdate.ModuleName, Updates: updates, SourceKey: entry.ObjectUpdate.SourceKey}, nil
+	case entry.Commit:
+		return CommitData{}, nil
+	default:
+		return nil, errors.New("empty journal entry")
+	}
+}
+
+// snapshotObjectUpdate converts ValueUpdates in the value of update to schema.MapValueUpdates so that the
+// update can be stored and replayed.
+func snapshotObjectUpdate(update schema.ObjectUpdate) (schema.ObjectUpdate, error) {
+	if valueUpdates, ok := update.Value.(schema.ValueUpdates); ok {
+		values := schema.MapValueUpdates{}
+		if err := values.Merge(valueUpdates); err != nil {
+			return schema.ObjectUpdate{}, err
+		}
+		update.Value = values
+	}
+	return update, nil
+}
+
+// toJournalObjectUpdate encodes the key and value of update with schema.EncodeValue. ValueUpdates are
+// converted to schema.MapValueUpdates first.
+func toJournalObjectUpdate(update schema.ObjectUpdate) (journalObjectUpdate, error) {
+	update, err := snapshotObjectUpdate(update)
+	if err != nil {
+		return journalObjectUpdate{}, err
+	}
+
+	res := journalObjectUpdate{TypeName: update.TypeName, Delete: update.Delete}
+	if res.Key, err = schema.EncodeValue(update.Key); err != nil {
+		return journalObjectUpdate{}, fmt.Errorf("invalid key: %v", err) //nolint:errorlint // false positive due to using go1.12
+	}
+
+	values, ok := update.Value.(schema.MapValueUpdates)
+	if !ok {
+		if res.Value, err = schema.EncodeValue(update.Value); err != nil {
+			return journalObjectUpdate{}, fmt.Errorf("invalid value: %v", err) //nolint:errorlint // false positive due to using go1.12
+		}
+		return res, nil
+	}
+
+	res.ValueUpdates = &journalValueUpdates{Set: true, Values: make(map[string][]byte, len(values))}
+	for name, value := range values {
+		if res.ValueUpdates.Values[name], err = schema.EncodeValue(value); err != nil {
+			return journalObjectUpdate{}, fmt.Errorf("invalid value of field %q: %v", name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+	}
+	return res, nil
+}
+
+// toObjectUpdate decodes the key and value of the update.
+func (u journalObjectUpdate) toObjectUpdate() (schema.ObjectUpdate, error) {
+	res := schema.ObjectUpdate{TypeName: u.TypeName, Delete: u.Delete}
+
+	var err error
+	if res.Key, err = schema.DecodeValue(u.Key); err != nil {
+		return schema.ObjectUpdate{}, fmt.Errorf("invalid key of update of %s: %v", u.TypeName, err) //nolint:errorlint // false positive due to using go1.12
+	}
+
+	if u.ValueUpdates == nil {
+		if res.Value, err = schema.DecodeValue(u.Value); err != nil {
+			return schema.ObjectUpdate{}, fmt.Errorf("invalid value of update of %s: %v", u.TypeName, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		return res, nil
+	}
+
+	values := make(schema.MapValueUpdates, len(u.ValueUpdates.Values))
+	for name, bz := range u.ValueUpdates.Values {
+		if values[name], err = schema.DecodeValue(bz); err != nil {
+			return schema.ObjectUpdate{}, fmt.Errorf("invalid value of field %q of update of %s: %v", name, u.TypeName, err) //nolint:errorlint // false positive due to using go1.12
+		}
+	}
+	res.Value = values
+	return res, nil
+}
+
+func evalToBytes(f ToBytes) (*journalBytes, error) {
+	if f == nil {
+		return nil, nil
+	}
+	bz, err := f()
+	if err != nil {
+		return nil, err
+	}
+	return &journalBytes{Set: true, Bytes: bz}, nil
+}
+
+func evalToJSON(f ToJSON) (*journalBytes, error) {
+	if f == nil {
+		return nil, nil
+	}
+	bz, err := f()
+	if err != nil {
+		return nil, err
+	}
+	return &journalBytes{Set: true, Bytes: bz}, nil
+}
+
+func (b *journalBytes) toBytes() ToBytes {
+	if b == nil {
+		return nil
+	}
+	bz := b.Bytes
+	return func() ([]byte, error) { return bz, nil }
+}
+
+func (b *journalBytes) toJSON() ToJSON {
+	if b == nil {
+		return nil
+	}
+	bz := json.RawMessage(b.Bytes)
+	return func() (json.RawMessage, error) { return bz, nil }
+}
+
+func (h *journalBlockHeader) toBlockHeader() ToBlockHeader {
+	if h == nil {
+		return nil
+	}
+	header := h.Header
+	return func() (BlockHeader, error) { return header, nil }
+}
+
+func (c *journalLastCommit) toLastCommit() ToLastCommit {
+	if c == nil {
+		return nil
+	}
+	lastCommit := c.LastCommit
+	return func() (LastCommit, error) { return lastCommit, nil }
+}
+
+func (u *journalValidatorUpdates) toValidatorUpdates() ToValidatorUpdates {
+	if u == nil {
+		return nil
+	}
+	updates := u.Updates
+	return func() ([]ValidatorUpdate, error) { return updates, nil }
+}
+
+func (a *journalAttributes) toEventAttributes() (ToEventAttributes, error) {
+	if a == nil {
+		return nil, nil
+	}
+	attrs := make([]EventAttribute, len(a.Attributes))
+	for i, attr := range a.Attributes {
+		value, err := schema.DecodeValue(attr.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of attribute %q: %v", attr.Key, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		attrs[i] = EventAttribute{Key: attr.Key, Value: value}
+	}
+	return func() ([]EventAttribute, error) { return attrs, nil }, nil
+}
+
+func (d *journalDecodedTx) toDecodedTx() ToDecodedTx {
+	if d == nil {
+		return nil
+	}
+	tx := d.Tx
+	return func() (DecodedTx, error) { return tx, nil }
+}
@@ -0,0 +1,252 @@
+package appdata
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"cosmossdk.io/schema"
+)
+
+func TestJournal(t *testing.T) {
+	moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{
+		{
+			Name:      "balances",
+			KeyFields: []schema.Field{{Name: "address", Kind: schema.AddressKind}, {Name: "denom", Kind: schema.StringKind}},
+			ValueFields: []schema.Field{
+				{Name: "amount", Kind: schema.IntegerStringKind},
+				{Name: "updated", Kind: schema.TimeKind, Nullable: true},
+				{Name: "tags", Kind: schema.ListKind, ElementKind: schema.StringKind},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	packets := []Packet{
+		ModuleInitializationData{ModuleName: "bank", Schema: moduleSchema, SchemaChanged: true},
+		StartBlockData{
+			Height:     10,
+			HeaderJSON: func() (json.RawMessage, error) { return json.RawMessage(`{"height":10}`), nil },
+			Header: func() (BlockHeader, error) {
+				return BlockHeader{ChainID: "test", Height: 10, Time: updated, Hash: []byte{1}, ProposerAddress: []byte{2}}, nil
+			},
+			LastCommit: func() (LastCommit, error) {
+				return LastCommit{Round: 1, Votes: []Vote{{ValidatorAddress: []byte{2}, Power: 10, Signed: true}, {ValidatorAddress: []byte{3}, Power: 5}}}, nil
+			},
+			ValidatorUpdates: func() ([]ValidatorUpdate, error) {
+				return []ValidatorUpdate{{PubKeyType: "ed25519", PubKey: []byte{4}, Power: 0}}, nil
+			},
+		},
+		TxData{TxIndex: 1, Bytes: func() ([]byte, error) { return []byte{1, 2, 3}, nil }},
+		TxData{
+			TxIndex: 2,
+			Bytes:   func() ([]byte, error) { return []byte{4, 5, 6}, nil },
+			Decoded: func() (DecodedTx, error) {
+				return DecodedTx{
+					MsgTypeURLs: []string{"/cosmos.bank.v1beta1.MsgSend"},
+					Signers:     [][]byte{{0xaa}},
+					Fee:         []Coin{{Denom: "uatom", Amount: "10"}},
+					GasLimit:    200000,
+					Memo:        "memo",
+				}, nil
+			},
+			Result: &TxResult{Code: 5, Codespace: "sdk", GasWanted: 200000, GasUsed: 50000},
+		},
+		EventData{
+			TxIndex:    -1,
+			EventIndex: 2,
+			Type:       "transfer",
+			Data:       func() (json.RawMessage, error) { return json.RawMessage(`{}`), nil },
+			ModuleName: "bank",
+			Attributes: func() ([]EventAttribute, error) {
+				return []EventAttribute{{Key: "amount", Value: "100"}, {Key: "height", Value: uint64(10)}}, nil
+			},
+		},
+		KVPairData{Updates: []ModuleKVPairUpdate{{ModuleName: "bank", Update: schema.KVPairUpdate{Key: []byte("k"), Value: []byte("v")}}}},
+		ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{
+			{
+				TypeName: "balances",
+				Key:      []interface{}{[]byte{0xaa}, "uatom"},
+				Value:    []interface{}{"100", updated, []interface{}{"a", "b"}},
+			},
+			{
+				TypeName: "balances",
+				Key:      []interface{}{[]byte{0xbb}, "uatom"},
+				Value:    []interface{}{"1", nil, []interface{}{}},
+			},
+			{
+				TypeName: "balances",
+				Key:      []interface{}{[]byte{0xcc}, "uatom"},
+				Value:    schema.MapValueUpdates{"amount": "5"},
+			},
+			{
+				TypeName: "balances",
+				Key:      []interface{}{[]byte{0xdd}, "uatom"},
+				Delete:   true,
+			},
+		}},
+		CommitData{},
+	}
+
+	var buf bytes.Buffer
+	var forwarded []Packet
+	listener := JournalListener(&buf, recordingListener(&forwarded))
+	for _, p := range packets {
+		if err := listener.SendPacket(p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(forwarded) != len(packets) {
+		t.Fatalf("expected %d packets to be forwarded, got %d", len(packets), len(forwarded))
+	}
+
+	var replayed []Packet
+	if err := ReplayJournal(bytes.NewReader(buf.Bytes()), recordingListener(&replayed)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(replayed) != len(packets) {
+		t.Fatalf("expected %d packets to be replayed, got %d", len(packets), len(replayed))
+	}
+
+	for i := range packets {
+		expected := normalizePacket(t, packets[i])
+		actual := normalizePacket(t, replayed[i])
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("packet %d: expected %#v, got %#v", i, expected, actual)
+		}
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+		truncated := buf.Bytes()[:buf.Len()-3]
+		err := ReplayJournal(bytes.NewReader(truncated), Listener{})
+		corruptErr, ok := err.(CorruptJournalError) //nolint:errorlint // false positive due to using go1.12
+		if !ok {
+			t.Fatalf("expected CorruptJournalError, got: %v", err)
+		}
+
+		// truncating to the offset of the corrupt record repairs the journal
+		var count int
+		err = ReplayJournal(bytes.NewReader(truncated[:corruptErr.Offset]), Listener{
+			StartBlock: func(StartBlockData) error { count++; return nil },
+			Commit:     func(CommitData) error { count++; return nil },
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if count != 1 {
+			t.Fatalf("expected the start block packet to be replayed without commit, got %d packets", count)
+		}
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		corrupted := append([]byte(nil), buf.Bytes()...)
+		corrupted[journalRecordHeaderSize+1] ^= 0xff
+		err := ReplayJournal(bytes.NewReader(corrupted), Listener{})
+		corruptErr, ok := err.(CorruptJournalError) //nolint:errorlint // false positive due to using go1.12
+		if !ok || corruptErr.Offset != 0 || corruptErr.Reason != "checksum mismatch" {
+			t.Fatalf("expected checksum mismatch at offset 0, got: %v", err)
+		}
+	})
+}
+
+func recordingListener(packets *[]Packet) Listener {
+	record := func(p Packet) error {
+		*packets = append(*packets, p)
+		return nil
+	}
+	return Listener{
+		InitializeModuleData: func(data ModuleInitializationData) error { return record(data) },
+		StartBlock:           func(data StartBlockData) error { return record(data) },
+		OnTx:                 func(data TxData) error { return record(data) },
+		OnEvent:              func(data EventData) error { return record(data) },
+		OnKVPair:             func(data KVPairData) error { return record(data) },
+		OnObjectUpdate:       func(data ObjectUpdateData) error { return record(data) },
+		Commit:               func(data CommitData) error { return record(data) },
+	}
+}
+
+// normalizePacket evaluates lazy functions and marshals schemas so that packets can be compared.
+func normalizePacket(t *testing.T, p Packet) interface{} {
+	t.Helper()
+	eval := func(f func() ([]byte, error)) []byte {
+		if f == nil {
+			return nil
+		}
+		bz, err := f()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bz
+	}
+	evalJSON := func(f ToJSON) []byte {
+		if f == nil {
+			return nil
+		}
+		return eval(func() ([]byte, error) { return f() })
+	}
+
+	switch data := p.(type) {
+	case ModuleInitializationData:
+		bz, err := data.Schema.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []interface{}{data.ModuleName, string(bz), data.SchemaChanged}
+	case StartBlockData:
+		var header *BlockHeader
+		if data.Header != nil {
+			res, err := data.Header()
+			if err != nil {
+				t.Fatal(err)
+			}
+			header = &res
+		}
+		var lastCommit *LastCommit
+		if data.LastCommit != nil {
+			res, err := data.LastCommit()
+			if err != nil {
+				t.Fatal(err)
+			}
+			lastCommit = &res
+		}
+		var validatorUpdates []ValidatorUpdate
+		if data.ValidatorUpdates != nil {
+			var err error
+			if validatorUpdates, err = data.ValidatorUpdates(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return []interface{}{data.Height, eval(data.HeaderBytes), evalJSON(data.HeaderJSON), header, lastCommit, validatorUpdates}
+	case TxData:
+		var decoded *DecodedTx
+		if data.Decoded != nil {
+			tx, err := data.Decoded()
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded = &tx
+		}
+		return []interface{}{data.TxIndex, eval(data.Bytes), evalJSON(data.JSON), decoded, data.Result}
+	case EventData:
+		var attrs []EventAttribute
+		if data.Attributes != nil {
+			var err error
+			attrs, err = data.Attributes()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		data.Data = nil
+		data.Attributes = nil
+		return []interface{}{data, evalJSON(p.(EventData).Data), attrs}
+	default:
+		return p
+	}
+}
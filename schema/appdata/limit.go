@@ -0,0 +1,201 @@
+package appdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// SizeCapPolicy specifies what a listener returned by Limit does with values which exceed their size cap.
+type SizeCapPolicy int
+
+const (
+	// CapTruncate truncates oversized values and appends TruncationMarker to them.
+	CapTruncate SizeCapPolicy = iota
+
+	// CapDrop drops oversized values.
+	CapDrop
+)
+
+// TruncationMarker is appended to the values truncated with CapTruncate.
+const TruncationMarker = "...[truncated]"
+
+// String returns a string representation of the size cap policy.
+func (p SizeCapPolicy) String() string {
+	switch p {
+	case CapTruncate:
+		return "truncate"
+	case CapDrop:
+		return "drop"
+	default:
+		return fmt.Sprintf("invalid(%d)", p)
+	}
+}
+
+// LimitOptions are options for Limit. Zero values disable the corresponding limits.
+type LimitOptions struct {
+	// MaxEventAttributeSize is the maximum size in bytes of the string, bytes and JSON values of event
+	// attributes.
+	MaxEventAttributeSize int
+
+	// MaxEventDataSize is the maximum size in bytes of the JSON data of events. Since JSON can't be truncated,
+	// oversized JSON data is replaced with null regardless of the policy.
+	MaxEventDataSize int
+
+	// MaxTxLogSize is the maximum size in bytes of the raw logs of transaction results, including the results of
+	// simulations.
+	MaxTxLogSize int
+
+	// MaxEventsPerBlock is the maximum number of events forwarded per block. Further events of the block are
+	// dropped.
+	MaxEventsPerBlock int
+
+	// Policy specifies whether oversized event attributes and transaction logs are truncated or dropped. A
+	// dropped event attribute is omitted from the attributes of the event and a dropped log is empty.
+	Policy SizeCapPolicy
+}
+
+// Limit returns a listener which caps the size of event attributes and transaction logs and the number of
+// events per block before forwarding them to listener, so that a single huge event, for instance one emitted
+// by a smart contract, can't blow up downstream databases. Callbacks which are nil in listener are also nil in
+// the returned listener, except for StartBlock which is needed to count the events per block.
+func Limit(listener Listener, opts LimitOptions) (Listener, error) {
+	if opts.MaxEventAttributeSize < 0 || opts.MaxEventDataSize < 0 || opts.MaxTxLogSize < 0 || opts.MaxEventsPerBlock < 0 {
+		return Listener{}, fmt.Errorf("limits must not be negative")
+	}
+	if opts.Policy != CapTruncate && opts.Policy != CapDrop {
+		return Listener{}, fmt.Errorf("invalid size cap policy %s", opts.Policy)
+	}
+
+	res := listener
+
+	if listener.OnEvent != nil && (opts.MaxEventAttributeSize != 0 || opts.MaxEventDataSize != 0 || opts.MaxEventsPerBlock != 0) {
+		// events is the number of events forwarded in the current block
+		events := 0
+
+		if listener.StartBlock != nil {
+			res.StartBlock = func(data StartBlockData) error {
+				events = 0
+				return listener.StartBlock(data)
+			}
+		} else {
+			res.StartBlock = func(StartBlockData) error {
+				events = 0
+				return nil
+			}
+		}
+
+		res.OnEvent = func(data EventData) error {
+			if opts.MaxEventsPerBlock != 0 {
+				if events >= opts.MaxEventsPerBlock {
+					return nil
+				}
+				events++
+			}
+
+			if opts.MaxEventDataSize != 0 && data.Data != nil {
+				toJSON := data.Data
+				data.Data = func() (json.RawMessage, error) {
+					bz, err := toJSON()
+					if err != nil || len(bz) <= opts.MaxEventDataSize {
+						return bz, err
+					}
+					return json.RawMessage("null"), nil
+				}
+			}
+
+			if opts.MaxEventAttributeSize != 0 && data.Attributes != nil {
+				toAttributes := data.Attributes
+				data.Attributes = func() ([]EventAttribute, error) {
+					attrs, err := toAttributes()
+					if err != nil {
+						return nil, err
+					}
+					return capEventAttributes(attrs, opts.MaxEventAttributeSize, opts.Policy), nil
+				}
+			}
+
+			return listener.OnEvent(data)
+		}
+	}
+
+	capLog := func(log string) string {
+		if len(log) <= opts.MaxTxLogSize {
+			return log
+		}
+		if opts.Policy == CapDrop {
+			return ""
+		}
+		return truncateString(log, opts.MaxTxLogSize)
+	}
+
+	if listener.OnTx != nil && opts.MaxTxLogSize != 0 {
+		res.OnTx = func(data TxData) error {
+			if data.Result != nil && len(data.Result.Log) > opts.MaxTxLogSize {
+				result := *data.Result
+				result.Log = capLog(result.Log)
+				data.Result = &result
+			}
+			return listener.OnTx(data)
+		}
+	}
+
+	if listener.OnSimulation != nil && opts.MaxTxLogSize != 0 {
+		res.OnSimulation = func(data SimulationData) error {
+			data.Result.Log = capLog(data.Result.Log)
+			return listener.OnSimulation(data)
+		}
+	}
+
+	return res, nil
+}
+
+// capEventAttributes returns the attributes with the values which exceed maxSize truncated or dropped.
+func capEventAttributes(attrs []EventAttribute, maxSize int, policy SizeCapPolicy) []EventAttribute {
+	res := make([]EventAttribute, 0, len(attrs))
+	for _, attr := range attrs {
+		switch value := attr.Value.(type) {
+		case string:
+			if len(value) > maxSize {
+				if policy == CapDrop {
+					continue
+				}
+				attr.Value = truncateString(value, maxSize)
+			}
+		case json.RawMessage:
+			if len(value) > maxSize {
+				if policy == CapDrop {
+					continue
+				}
+				// truncated JSON is invalid so the value is replaced with the marker as a JSON string
+				attr.Value = json.RawMessage(`"` + TruncationMarker + `"`)
+			}
+		case []byte:
+			if len(value) > maxSize {
+				if policy == CapDrop {
+					continue
+				}
+				n := maxSize - len(TruncationMarker)
+				if n < 0 {
+					n = 0
+				}
+				attr.Value = append(value[:n:n], TruncationMarker...)
+			}
+		}
+		res = append(res, attr)
+	}
+	return res
+}
+
+// truncateString truncates s at a rune boundary so that it has at most maxSize bytes including the appended
+// TruncationMarker, unless maxSize is smaller than the marker.
+func truncateString(s string, maxSize int) string {
+	n := maxSize - len(TruncationMarker)
+	if n < 0 {
+		n = 0
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + TruncationMarker
+}
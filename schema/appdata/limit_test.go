@@ -0,0 +1,122 @@
+package appdata
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLimit(t *testing.T) {
+	event := EventData{
+		Type: "wasm",
+		Data: func() (json.RawMessage, error) {
+			return json.RawMessage(`{"payload":"` + strings.Repeat("x", 100) + `"}`), nil
+		},
+		Attributes: func() ([]EventAttribute, error) {
+			return []EventAttribute{
+				{Key: "small", Value: "abc"},
+				{Key: "large", Value: strings.Repeat("é", 20)},
+				{Key: "json", Value: json.RawMessage(`"` + strings.Repeat("y", 40) + `"`)},
+				{Key: "bytes", Value: make([]byte, 40)},
+				{Key: "number", Value: uint64(1)},
+			}, nil
+		},
+	}
+	tx := TxData{Result: &TxResult{Code: 1, Log: strings.Repeat("l", 100)}}
+
+	tt := []struct {
+		name     string
+		policy   SizeCapPolicy
+		attrs    []EventAttribute
+		expected string
+	}{
+		{
+			name:   "truncate",
+			policy: CapTruncate,
+			attrs: []EventAttribute{
+				{Key: "small", Value: "abc"},
+				// the 32 byte cap leaves 18 bytes for the two byte runes before the marker
+				{Key: "large", Value: strings.Repeat("é", 9) + TruncationMarker},
+				{Key: "json", Value: json.RawMessage(`"` + TruncationMarker + `"`)},
+				{Key: "bytes", Value: append(make([]byte, 18), TruncationMarker...)},
+				{Key: "number", Value: uint64(1)},
+			},
+			expected: strings.Repeat("l", 50-len(TruncationMarker)) + TruncationMarker,
+		},
+		{
+			name:   "drop",
+			policy: CapDrop,
+			attrs: []EventAttribute{
+				{Key: "small", Value: "abc"},
+				{Key: "number", Value: uint64(1)},
+			},
+			expected: "",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var received []Packet
+			listener, err := Limit(recordingListener(&received), LimitOptions{
+				MaxEventAttributeSize: 32,
+				MaxEventDataSize:      64,
+				MaxTxLogSize:          50,
+				MaxEventsPerBlock:     2,
+				Policy:                tc.policy,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			packets := []Packet{StartBlockData{Height: 1}, event, event, event, tx, StartBlockData{Height: 2}, event}
+			for _, p := range packets {
+				if err := listener.SendPacket(p); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			// the third event of the first block is dropped
+			var types []string
+			for _, p := range received {
+				types = append(types, PacketType(p))
+			}
+			expectedTypes := []string{"start_block", "event", "event", "tx", "start_block", "event"}
+			if !reflect.DeepEqual(types, expectedTypes) {
+				t.Fatalf("expected packets %v, got %v", expectedTypes, types)
+			}
+
+			capped := received[1].(EventData)
+			data, err := capped.Data()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != "null" {
+				t.Fatalf("expected oversized event data to be replaced with null, got %s", data)
+			}
+			attrs, err := capped.Attributes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(attrs, tc.attrs) {
+				t.Fatalf("expected attributes %v, got %v", tc.attrs, attrs)
+			}
+
+			result := received[3].(TxData).Result
+			if result.Log != tc.expected || result.Code != 1 {
+				t.Fatalf("expected log %q, got %v", tc.expected, result)
+			}
+			if len(tx.Result.Log) != 100 {
+				t.Fatalf("expected the input transaction to be unchanged")
+			}
+		})
+	}
+}
+
+func TestLimit_invalid(t *testing.T) {
+	if _, err := Limit(Listener{}, LimitOptions{MaxTxLogSize: -1}); err == nil {
+		t.Fatalf("expected an error for a negative limit")
+	}
+	if _, err := Limit(Listener{}, LimitOptions{Policy: SizeCapPolicy(5)}); err == nil {
+		t.Fatalf("expected an error for an invalid policy")
+	}
+}
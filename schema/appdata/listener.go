@@ -6,6 +6,11 @@ package appdata
 // understand which methods will or will not be called. For instance, most blockchains will not do logical
 // decoding of data out of the box, so the InitializeModuleData and OnObjectUpdate methods will not be called.
 // These methods will only be called when listening logical decoding is setup.
+//
+// Sources call the methods of a listener one at a time and deliver the updates of each object, identified by
+// its module name, object type name and key, in the order in which they happened, within and across blocks,
+// even when decoding is parallelized. Listeners which require a weaker or an enforced ordering can be
+// wrapped with OrderedListener.
 type Listener struct {
 	// InitializeModuleData should be called whenever the blockchain process starts OR whenever
 	// logical decoding of a module is initiated. An indexer listening to this event
@@ -33,9 +38,19 @@ type Listener struct {
 	// is also passed to OnKVPair. Module names must conform to the NameFormat regular expression.
 	OnObjectUpdate func(ObjectUpdateData) error
 
+	// OnBytesChunk is called with a chunk of a large Bytes value of an object update when the value is delivered
+	// in chunks by ChunkBytes. The chunks of the values of an ObjectUpdateData packet are delivered before it and
+	// the values themselves are nil in the packet. Listeners can reassemble the values with ReassembleBytes.
+	OnBytesChunk func(BytesChunkData) error
+
 	// Commit is called when state is committed, usually at the end of a block. Any
 	// indexers should commit their data when this is called and return an error if
 	// they are unable to commit. Data sources MUST call Commit when data is committed,
 	// otherwise it should be assumed that indexers have not persisted their state.
 	Commit func(CommitData) error
+
+	// OnSimulation is called with the outcome of checking or simulating a transaction. It is a separate
+	// channel which is not part of the stream of committed data: it may be called at any time outside of
+	// blocks, the data it receives is never committed and it is not followed by Commit.
+	OnSimulation func(SimulationData) error
 }
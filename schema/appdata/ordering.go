@@ -0,0 +1,200 @@
+package appdata
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"cosmossdk.io/schema"
+)
+
+// Ordering is the guarantee which a listener requires about the order in which it receives object updates.
+// Whatever the ordering, the updates of the same object, identified by its module name, object type name and
+// key, are always delivered in the order in which they happened, within and across blocks.
+type Ordering int
+
+const (
+	// GlobalOrdering delivers every packet one at a time in the order in which the source emitted it, so that
+	// listeners also observe the relative order of updates of different objects and of updates and events.
+	// It is the default.
+	GlobalOrdering Ordering = iota
+
+	// PerKeyOrdering only guarantees the order of the updates of the same object. Updates of different objects
+	// may be delivered concurrently and in any order, but all the object updates which precede any other
+	// packet, such as an event or a Commit, are delivered before that packet.
+	PerKeyOrdering
+)
+
+// String returns the name of the ordering.
+func (o Ordering) String() string {
+	switch o {
+	case GlobalOrdering:
+		return "global"
+	case PerKeyOrdering:
+		return "per_key"
+	default:
+		return fmt.Sprintf("Ordering(%d)", int(o))
+	}
+}
+
+// OrderingOptions are the options for OrderedListener.
+type OrderingOptions struct {
+	// Ordering is the ordering which the listener requires.
+	Ordering Ordering
+
+	// Workers is the number of goroutines which deliver object updates concurrently with PerKeyOrdering.
+	// If it is zero or one, object updates are delivered sequentially. It is ignored with GlobalOrdering.
+	Workers int
+}
+
+// OrderedListener returns a listener which enforces the ordering configured in opts when forwarding packets
+// to listener. With GlobalOrdering, callbacks are serialized so that listener is never called concurrently
+// even if the source is. With PerKeyOrdering and more than one worker, object updates are sharded by module
+// name, object type name and key, buffered and delivered by the workers when the next packet other than an
+// object update is received, before it is forwarded. Errors returned by listener for buffered updates are
+// returned by the callback of that packet. The OnObjectUpdate callback of listener must then be safe to
+// call concurrently for different objects. Callbacks which are nil in listener are also nil in the returned
+// listener, except for Commit which is set to flush the buffered object updates with PerKeyOrdering.
+func OrderedListener(listener Listener, opts OrderingOptions) Listener {
+	o := &orderedListener{target: listener}
+	if opts.Ordering == PerKeyOrdering && opts.Workers > 1 && listener.OnObjectUpdate != nil {
+		o.pending = make([][]ObjectUpdateData, opts.Workers)
+	}
+
+	res := Listener{}
+	if listener.InitializeModuleData != nil {
+		res.InitializeModuleData = func(data ModuleInitializationData) error { return o.forward(data) }
+	}
+	if listener.StartBlock != nil {
+		res.StartBlock = func(data StartBlockData) error { return o.forward(data) }
+	}
+	if listener.OnTx != nil {
+		res.OnTx = func(data TxData) error { return o.forward(data) }
+	}
+	if listener.OnEvent != nil {
+		res.OnEvent = func(data EventData) error { return o.forward(data) }
+	}
+	if listener.OnKVPair != nil {
+		res.OnKVPair = func(data KVPairData) error { return o.forward(data) }
+	}
+	if listener.OnObjectUpdate != nil {
+		res.OnObjectUpdate = o.onObjectUpdate
+	}
+	if listener.Commit != nil {
+		res.Commit = func(data CommitData) error { return o.forward(data) }
+	}
+	if listener.OnSimulation != nil {
+		res.OnSimulation = func(data SimulationData) error { return o.forward(data) }
+	}
+	if listener.OnBytesChunk != nil {
+		res.OnBytesChunk = func(data BytesChunkData) error { return o.forward(data) }
+	}
+
+	// the object updates buffered before the end of the stream have to be flushed even if listener has no
+	// Commit callback
+	if res.Commit == nil && o.pending != nil {
+		res.Commit = func(CommitData) error { return o.forward(nil) }
+	}
+
+	return res
+}
+
+type orderedListener struct {
+	mu     sync.Mutex
+	target Listener
+
+	// pending holds the object updates buffered for each worker with PerKeyOrdering. It is nil if object
+	// updates are delivered sequentially.
+	pending [][]ObjectUpdateData
+}
+
+// forward flushes the buffered object updates and then forwards the packet, unless it is nil.
+func (o *orderedListener) forward(packet Packet) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err := o.flush(); err != nil {
+		return err
+	}
+
+	if packet == nil {
+		return nil
+	}
+	return o.target.SendPacket(packet)
+}
+
+func (o *orderedListener) onObjectUpdate(data ObjectUpdateData) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.pending == nil {
+		return o.target.OnObjectUpdate(data)
+	}
+
+	shards := make([][]schema.ObjectUpdate, len(o.pending))
+	for _, update := range data.Updates {
+		shard, err := o.shard(data.ModuleName, update)
+		if err != nil {
+			return err
+		}
+		shards[shard] = append(shards[shard], update)
+	}
+
+	for i, updates := range shards {
+		if len(updates) != 0 {
+			o.pending[i] = append(o.pending[i], ObjectUpdateData{ModuleName: data.ModuleName, Updates: updates, SourceKey: data.SourceKey})
+		}
+	}
+	return nil
+}
+
+// shard returns the worker which delivers the updates of the object which update belongs to.
+func (o *orderedListener) shard(moduleName string, update schema.ObjectUpdate) (int, error) {
+	key, err := schema.EncodeValue(update.Key)
+	if err != nil {
+		return 0, fmt.Errorf("invalid key of update of %s in module %s: %v", update.TypeName, moduleName, err) //nolint:errorlint // false positive due to using go1.12
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(moduleName))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(update.TypeName))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write(key)
+	return int(h.Sum32() % uint32(len(o.pending))), nil
+}
+
+// flush delivers the buffered object updates on the workers and waits for them. It returns the error of the
+// first worker which failed. Each worker stops at its first error.
+func (o *orderedListener) flush() error {
+	if o.pending == nil {
+		return nil
+	}
+
+	errs := make([]error, len(o.pending))
+	var wg sync.WaitGroup
+	for i, batches := range o.pending {
+		if len(batches) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, batches []ObjectUpdateData) {
+			defer wg.Done()
+			for _, batch := range batches {
+				if errs[i] = o.target.OnObjectUpdate(batch); errs[i] != nil {
+					return
+				}
+			}
+		}(i, batches)
+		o.pending[i] = nil
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package appdata
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"cosmossdk.io/schema"
+)
+
+func TestOrderedListener_PerKey(t *testing.T) {
+	var mu sync.Mutex
+	var committed []uint64
+	received := map[string][]int32{}
+	delivered := 0
+	listener := OrderedListener(Listener{
+		OnObjectUpdate: func(data ObjectUpdateData) error {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, update := range data.Updates {
+				id := fmt.Sprintf("%s/%s/%v", data.ModuleName, update.TypeName, update.Key)
+				received[id] = append(received[id], update.Value.(int32))
+				delivered++
+			}
+			return nil
+		},
+		Commit: func(CommitData) error {
+			mu.Lock()
+			defer mu.Unlock()
+			committed = append(committed, uint64(delivered))
+			return nil
+		},
+	}, OrderingOptions{Ordering: PerKeyOrdering, Workers: 4})
+
+	var seq int32
+	for block := 0; block < 3; block++ {
+		for i := 0; i < 10; i++ {
+			var updates []schema.ObjectUpdate
+			for key := 0; key < 20; key++ {
+				seq++
+				updates = append(updates, schema.ObjectUpdate{TypeName: "obj", Key: []interface{}{"k", uint32(key)}, Value: seq})
+			}
+			for _, module := range []string{"a", "b"} {
+				if err := listener.OnObjectUpdate(ObjectUpdateData{ModuleName: module, Updates: updates}); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+		}
+		if err := listener.Commit(CommitData{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// all the updates of a block are delivered before its commit
+	if fmt.Sprint(committed) != "[400 800 1200]" {
+		t.Fatalf("unexpected number of updates delivered before each commit: %v", committed)
+	}
+
+	// the updates of each object are delivered in order across blocks
+	if len(received) != 40 {
+		t.Fatalf("expected updates of 40 objects, got %d", len(received))
+	}
+	for id, values := range received {
+		for i := 1; i < len(values); i++ {
+			if values[i] <= values[i-1] {
+				t.Fatalf("updates of %s delivered out of order: %v", id, values)
+			}
+		}
+	}
+}
+
+func TestOrderedListener_Barrier(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	record := func(call string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, call)
+		return nil
+	}
+	listener := OrderedListener(Listener{
+		OnEvent:        func(data EventData) error { return record(data.Type) },
+		OnObjectUpdate: func(data ObjectUpdateData) error { return record(data.ModuleName) },
+	}, OrderingOptions{Ordering: PerKeyOrdering, Workers: 2})
+
+	if listener.Commit == nil {
+		t.Fatalf("expected Commit to be set to flush updates")
+	}
+	if listener.StartBlock != nil {
+		t.Fatalf("expected StartBlock to be nil")
+	}
+
+	update := ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{{TypeName: "balances", Key: "a"}}}
+	if err := listener.OnObjectUpdate(update); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := listener.OnEvent(EventData{Type: "transfer"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := listener.OnObjectUpdate(update); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("expected the second update to be buffered, got calls %v", calls)
+	}
+	if err := listener.Commit(CommitData{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(calls, ",") != "bank,transfer,bank" {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+}
+
+func TestOrderedListener_Error(t *testing.T) {
+	testErr := errors.New("test error")
+	listener := OrderedListener(Listener{
+		OnObjectUpdate: func(ObjectUpdateData) error { return testErr },
+		Commit:         func(CommitData) error { t.Fatalf("unexpected commit"); return nil },
+	}, OrderingOptions{Ordering: PerKeyOrdering, Workers: 2})
+
+	err := listener.OnObjectUpdate(ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{{TypeName: "balances", Key: "a"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := listener.Commit(CommitData{}); err != testErr { //nolint:errorlint // false positive due to using go1.12
+		t.Fatalf("expected the error of the buffered update, got: %v", err)
+	}
+
+	err = listener.OnObjectUpdate(ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{{TypeName: "balances", Key: struct{}{}}}})
+	if err == nil || !strings.Contains(err.Error(), "invalid key of update of balances in module bank") {
+		t.Fatalf("expected invalid key error, got: %v", err)
+	}
+}
+
+func TestOrderedListener_Global(t *testing.T) {
+	var inFlight, concurrent, count int32
+	listener := OrderedListener(Listener{
+		OnObjectUpdate: func(ObjectUpdateData) error {
+			if atomic.AddInt32(&inFlight, 1) > 1 {
+				atomic.StoreInt32(&concurrent, 1)
+			}
+			runtime.Gosched()
+			atomic.AddInt32(&inFlight, -1)
+			atomic.AddInt32(&count, 1)
+			return nil
+		},
+	}, OrderingOptions{Workers: 4})
+
+	if listener.Commit != nil {
+		t.Fatalf("expected Commit to be nil with global ordering")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = listener.OnObjectUpdate(ObjectUpdateData{ModuleName: "bank"})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if count != 800 || concurrent != 0 {
+		t.Fatalf("expected 800 serialized calls, got %d calls, concurrent: %v", count, concurrent != 0)
+	}
+}
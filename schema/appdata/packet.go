@@ -53,9 +53,43 @@ func (o ObjectUpdateData) apply(l *Listener) error {
 	return l.OnObjectUpdate(o)
 }
 
+func (c BytesChunkData) apply(l *Listener) error {
+	if l.OnBytesChunk == nil {
+		return nil
+	}
+	return l.OnBytesChunk(c)
+}
+
 func (c CommitData) apply(l *Listener) error {
 	if l.Commit == nil {
 		return nil
 	}
 	return l.Commit(c)
 }
+
+// PacketType returns a short snake case name for the type of the packet, such as "start_block" or
+// "object_update", which can be used to label metrics and logs.
+func PacketType(p Packet) string {
+	switch p.(type) {
+	case ModuleInitializationData:
+		return "module_initialization"
+	case StartBlockData:
+		return "start_block"
+	case TxData:
+		return "tx"
+	case EventData:
+		return "event"
+	case KVPairData:
+		return "kv_pair"
+	case ObjectUpdateData:
+		return "object_update"
+	case BytesChunkData:
+		return "bytes_chunk"
+	case CommitData:
+		return "commit"
+	case SimulationData:
+		return "simulation"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,224 @@
+package appdata
+
+import (
+	"fmt"
+	"io"
+)
+
+// StatefulListener is an extension interface for listeners which persist the data they receive, such as
+// indexers writing to a database, and therefore need to resume from where they left off after a restart.
+type StatefulListener interface {
+	// LastCommittedHeight returns the height of the last block which was committed by the listener. It
+	// should return 0 if the listener has not committed any block yet and -1 if it does not persist data
+	// and does not need to be resumed.
+	LastCommittedHeight() (int64, error)
+}
+
+// ResumableListener pairs a listener with the StatefulListener which reports its last committed height.
+type ResumableListener struct {
+	// Listener is the listener to resume.
+	Listener Listener
+
+	// State reports the last committed height of Listener. If it is nil, Listener is assumed not to
+	// persist data and is returned by Resume as is.
+	State StatefulListener
+}
+
+// CatchUpSource delivers the data of already committed blocks to listeners which have fallen behind.
+type CatchUpSource interface {
+	// CatchUp sends all the packets of the blocks with heights greater than fromHeight and less than or
+	// equal to toHeight to listener, including their Commit packets, so that the listener's last committed
+	// height is toHeight when it returns.
+	CatchUp(fromHeight, toHeight int64, listener Listener) error
+}
+
+// ResumeOptions are the options for Resume.
+type ResumeOptions struct {
+	// Height is the height of the last block committed by the source which will deliver data to the
+	// resumed listeners.
+	Height int64
+
+	// Source is used to deliver the blocks which listeners have missed. If it is nil, Resume returns an
+	// error if any listener is behind Height.
+	Source CatchUpSource
+}
+
+// Resume brings each listener up to date with the source on startup and returns the listeners which the
+// source should deliver new data to in the same order.
+//
+// The last committed height of each listener is compared with opts.Height. Listeners which are behind are
+// caught up using opts.Source and an error is returned if a listener is ahead of the source, because the
+// source cannot deliver data consistent with what the listener has committed. The returned listeners skip
+// blocks which the listener has already committed, so that a block is never applied twice, and return an
+// error if a block is missing from the stream after the last committed height.
+func Resume(opts ResumeOptions, listeners ...ResumableListener) ([]Listener, error) {
+	res := make([]Listener, len(listeners))
+	for i, l := range listeners {
+		if l.State == nil {
+			res[i] = l.Listener
+			continue
+		}
+
+		height, err := l.State.LastCommittedHeight()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the last committed height of listener %d: %v", i, err) //nolint:errorlint // false positive due to using go1.12
+		}
+
+		if height < 0 {
+			res[i] = l.Listener
+			continue
+		}
+
+		if height > opts.Height {
+			return nil, fmt.Errorf("listener %d has committed height %d which is ahead of the source at height %d", i, height, opts.Height)
+		}
+
+		if height < opts.Height {
+			if opts.Source == nil {
+				return nil, fmt.Errorf("listener %d has committed height %d which is behind the source at height %d and no catch-up source was provided", i, height, opts.Height)
+			}
+
+			if err := opts.Source.CatchUp(height, opts.Height, l.Listener); err != nil {
+				return nil, fmt.Errorf("failed to catch up listener %d from height %d to %d: %v", i, height, opts.Height, err) //nolint:errorlint // false positive due to using go1.12
+			}
+
+			height, err = l.State.LastCommittedHeight()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the last committed height of listener %d: %v", i, err) //nolint:errorlint // false positive due to using go1.12
+			}
+
+			if height != opts.Height {
+				return nil, fmt.Errorf("listener %d has committed height %d after catching up to height %d", i, height, opts.Height)
+			}
+		}
+
+		res[i] = skipCommittedBlocks(l.Listener, height)
+	}
+
+	return res, nil
+}
+
+// skipCommittedBlocks returns a listener which drops the packets of blocks with heights less than or equal
+// to lastCommitted and returns an error if a block after lastCommitted is missing. Packets which are not
+// sent within a block, such as module initialization, are always forwarded.
+func skipCommittedBlocks(listener Listener, lastCommitted int64) Listener {
+	skipping := false
+	current := int64(-1)
+	forward := func(p Packet) error {
+		if skipping {
+			return nil
+		}
+		return listener.SendPacket(p)
+	}
+
+	return Listener{
+		InitializeModuleData: func(data ModuleInitializationData) error { return forward(data) },
+		StartBlock: func(data StartBlockData) error {
+			height := int64(data.Height)
+			if height <= lastCommitted {
+				skipping = true
+				return nil
+			}
+
+			// a listener which has not committed anything can start at any height, for instance when a
+			// chain has an initial height greater than 1
+			if lastCommitted > 0 && height != lastCommitted+1 {
+				return fmt.Errorf("missed blocks: expected block %d after last committed height %d, got %d", lastCommitted+1, lastCommitted, height)
+			}
+
+			skipping = false
+			current = height
+			return listener.SendPacket(data)
+		},
+		OnTx:           func(data TxData) error { return forward(data) },
+		OnEvent:        func(data EventData) error { return forward(data) },
+		OnKVPair:       func(data KVPairData) error { return forward(data) },
+		OnObjectUpdate: func(data ObjectUpdateData) error { return forward(data) },
+		// simulations are not part of any block
+		OnSimulation: listener.OnSimulation,
+		Commit: func(data CommitData) error {
+			if skipping {
+				skipping = false
+				return nil
+			}
+
+			if err := listener.SendPacket(data); err != nil {
+				return err
+			}
+
+			if current >= 0 {
+				lastCommitted = current
+				current = -1
+			}
+			return nil
+		},
+	}
+}
+
+// JournalCatchUpSource returns a CatchUpSource which delivers missed blocks from a journal written by
+// JournalListener. open is called to read the journal from the beginning each time a listener needs to
+// catch up. Module initialization packets are always delivered so that listeners are initialized with the
+// latest module schemas. An error is returned if the journal does not contain all the requested blocks.
+func JournalCatchUpSource(open func() (io.ReadCloser, error)) CatchUpSource {
+	return journalCatchUpSource{open: open}
+}
+
+type journalCatchUpSource struct {
+	open func() (io.ReadCloser, error)
+}
+
+func (j journalCatchUpSource) CatchUp(fromHeight, toHeight int64, listener Listener) error {
+	r, err := j.open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	reader := NewJournalReader(r)
+	inRange := false
+	next := fromHeight + 1
+	// if the listener has not committed anything, the first block in the journal can have any height
+	started := fromHeight > 0
+	for next <= toHeight {
+		p, err := reader.Next()
+		if err == io.EOF { //nolint:errorlint // false positive due to using go1.12
+			return fmt.Errorf("journal ends before block %d", next)
+		}
+		if err != nil {
+			return err
+		}
+
+		switch data := p.(type) {
+		case ModuleInitializationData:
+			if err := listener.SendPacket(data); err != nil {
+				return err
+			}
+			continue
+		case StartBlockData:
+			height := int64(data.Height)
+			if height > next && started {
+				return fmt.Errorf("journal is missing block %d", next)
+			}
+			inRange = height >= next && height <= toHeight
+			if inRange {
+				next = height
+			}
+		}
+
+		if !inRange {
+			continue
+		}
+
+		if err := listener.SendPacket(p); err != nil {
+			return err
+		}
+
+		if _, ok := p.(CommitData); ok {
+			inRange = false
+			started = true
+			next++
+		}
+	}
+
+	return nil
+}
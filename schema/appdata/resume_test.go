@@ -0,0 +1,133 @@
+package appdata
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testStatefulListener records the blocks it commits and reports the height of the last one.
+type testStatefulListener struct {
+	committed []uint64
+	current   uint64
+	txs       int
+}
+
+func (l *testStatefulListener) LastCommittedHeight() (int64, error) {
+	if len(l.committed) == 0 {
+		return 0, nil
+	}
+	return int64(l.committed[len(l.committed)-1]), nil
+}
+
+func (l *testStatefulListener) listener() Listener {
+	return Listener{
+		StartBlock: func(data StartBlockData) error {
+			l.current = data.Height
+			return nil
+		},
+		OnTx: func(TxData) error {
+			l.txs++
+			return nil
+		},
+		Commit: func(CommitData) error {
+			l.committed = append(l.committed, l.current)
+			return nil
+		},
+	}
+}
+
+func sendBlock(t *testing.T, listener Listener, height uint64) error {
+	t.Helper()
+	for _, p := range []Packet{StartBlockData{Height: height}, TxData{}, CommitData{}} {
+		if err := listener.SendPacket(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestResume(t *testing.T) {
+	var journal bytes.Buffer
+	writer := JournalListener(&journal, Listener{})
+	for height := uint64(1); height <= 5; height++ {
+		if err := sendBlock(t, writer, height); err != nil {
+			t.Fatal(err)
+		}
+	}
+	source := JournalCatchUpSource(func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(journal.Bytes())), nil
+	})
+
+	behind := &testStatefulListener{committed: []uint64{1, 2}}
+	upToDate := &testStatefulListener{committed: []uint64{5}}
+	fresh := &testStatefulListener{}
+	stateless := &testStatefulListener{}
+
+	listeners, err := Resume(ResumeOptions{Height: 5, Source: source},
+		ResumableListener{Listener: behind.listener(), State: behind},
+		ResumableListener{Listener: upToDate.listener(), State: upToDate},
+		ResumableListener{Listener: fresh.listener(), State: fresh},
+		ResumableListener{Listener: stateless.listener()},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(behind.committed, []uint64{1, 2, 3, 4, 5}) || behind.txs != 3 {
+		t.Fatalf("expected blocks 3 to 5 to be caught up, got %v with %d txs", behind.committed, behind.txs)
+	}
+	if !reflect.DeepEqual(upToDate.committed, []uint64{5}) {
+		t.Fatalf("expected no blocks to be caught up, got %v", upToDate.committed)
+	}
+	if !reflect.DeepEqual(fresh.committed, []uint64{1, 2, 3, 4, 5}) {
+		t.Fatalf("expected all blocks to be caught up, got %v", fresh.committed)
+	}
+
+	// the source restarts from an earlier block so block 5 must not be applied twice
+	for _, listener := range listeners {
+		for _, height := range []uint64{5, 6} {
+			if err := sendBlock(t, listener, height); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+	if !reflect.DeepEqual(behind.committed, []uint64{1, 2, 3, 4, 5, 6}) || behind.txs != 4 {
+		t.Fatalf("expected block 5 to be skipped, got %v with %d txs", behind.committed, behind.txs)
+	}
+	if !reflect.DeepEqual(stateless.committed, []uint64{5, 6}) {
+		t.Fatalf("expected a listener without state to receive all blocks, got %v", stateless.committed)
+	}
+
+	err = sendBlock(t, listeners[0], 8)
+	if err == nil || !strings.Contains(err.Error(), "missed blocks: expected block 7") {
+		t.Fatalf("expected missed blocks error, got: %v", err)
+	}
+
+	t.Run("ahead of source", func(t *testing.T) {
+		ahead := &testStatefulListener{committed: []uint64{6}}
+		_, err := Resume(ResumeOptions{Height: 5, Source: source}, ResumableListener{Listener: ahead.listener(), State: ahead})
+		if err == nil || !strings.Contains(err.Error(), "ahead of the source") {
+			t.Fatalf("expected ahead of source error, got: %v", err)
+		}
+	})
+
+	t.Run("no catch-up source", func(t *testing.T) {
+		behind := &testStatefulListener{committed: []uint64{1}}
+		_, err := Resume(ResumeOptions{Height: 5}, ResumableListener{Listener: behind.listener(), State: behind})
+		if err == nil || !strings.Contains(err.Error(), "no catch-up source was provided") {
+			t.Fatalf("expected no catch-up source error, got: %v", err)
+		}
+	})
+
+	t.Run("journal missing blocks", func(t *testing.T) {
+		behind := &testStatefulListener{committed: []uint64{1}}
+		_, err := Resume(ResumeOptions{Height: 7, Source: source}, ResumableListener{Listener: behind.listener(), State: behind})
+		if err == nil || !strings.Contains(err.Error(), "journal ends before block 6") {
+			t.Fatalf("expected journal ends error, got: %v", err)
+		}
+	})
+}
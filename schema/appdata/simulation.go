@@ -0,0 +1,62 @@
+package appdata
+
+import "fmt"
+
+// SimulationMode is the mode in which a transaction was executed without being committed.
+type SimulationMode int
+
+const (
+	// CheckTxMode indicates that the transaction was checked before being added to the mempool.
+	CheckTxMode SimulationMode = iota
+
+	// ReCheckTxMode indicates that the transaction was checked again after a block was committed to decide
+	// whether it stays in the mempool.
+	ReCheckTxMode
+
+	// SimulateMode indicates that the transaction was simulated, usually to estimate its gas.
+	SimulateMode
+)
+
+// String returns a string representation of the simulation mode.
+func (m SimulationMode) String() string {
+	switch m {
+	case CheckTxMode:
+		return "check_tx"
+	case ReCheckTxMode:
+		return "recheck_tx"
+	case SimulateMode:
+		return "simulate"
+	default:
+		return fmt.Sprintf("invalid(%d)", m)
+	}
+}
+
+// SimulationData is the outcome of checking or simulating a transaction. Its data is not committed state and
+// it is delivered on a separate channel, Listener.OnSimulation, so that it can't be confused with committed
+// transactions. It can be used for mempool analytics such as tracking gas estimates and rejected
+// transactions.
+type SimulationData struct {
+	// Mode is the mode in which the transaction was executed.
+	Mode SimulationMode
+
+	// Height is the height of the last committed block on top of whose state the transaction was executed.
+	Height uint64
+
+	// Bytes is the raw byte representation of the transaction. It may be nil if the source does not provide
+	// it.
+	Bytes ToBytes
+
+	// Decoded returns the decoded transaction. It may be nil if the source cannot decode transactions.
+	Decoded ToDecodedTx
+
+	// Result is the result of executing the transaction. A non-zero Code indicates that the transaction
+	// failed, in which case Log describes the failure.
+	Result TxResult
+}
+
+func (s SimulationData) apply(l *Listener) error {
+	if l.OnSimulation == nil {
+		return nil
+	}
+	return l.OnSimulation(s)
+}
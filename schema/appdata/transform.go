@@ -0,0 +1,407 @@
+package appdata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"cosmossdk.io/schema"
+)
+
+// TransformAction is the transformation which is applied to the values of a field, such as redacting or hashing
+// sensitive data before it leaves the node.
+type TransformAction string
+
+const (
+	// RedactAction replaces the values of the field with null if the field is nullable, or with an empty value
+	// for String, Bytes and JSON fields.
+	RedactAction TransformAction = "redact"
+
+	// HashAction replaces the values of the field with their SHA-256 hash, hex encoded for String fields and
+	// raw for Bytes and Address fields, so that equal values can still be correlated without being revealed.
+	HashAction TransformAction = "hash"
+)
+
+// FieldTransform declares the transformation of a field of an object type or event type of a module.
+type FieldTransform struct {
+	// ModuleName is the name of the module.
+	ModuleName string
+
+	// TypeName is the name of the object type or event type in the schema of the module.
+	TypeName string
+
+	// FieldName is the name of the field. Only value fields of object types can be transformed because
+	// transforming key fields would change the identity of objects.
+	FieldName string
+
+	// Action is the transformation applied to the values of the field.
+	Action TransformAction
+}
+
+// TransformOptions are options for TransformFields.
+type TransformOptions struct {
+	// Fields are the fields to transform.
+	Fields []FieldTransform
+
+	// TxMemo, if set, is the transformation applied to the memo of decoded transactions, including simulated
+	// ones. The raw bytes and JSON of transactions are dropped since they contain the memo.
+	TxMemo TransformAction
+}
+
+// TransformFields returns a listener which applies the transformations declared in opts to the data before it
+// is forwarded to listener, so that operators can redact or hash sensitive fields before they are delivered to
+// external indexers. The fields are validated against the module schemas passed to InitializeModuleData.
+//
+// Since the raw key-value pairs of modules with transformed fields contain the untransformed values, OnKVPair
+// data of these modules is dropped. Likewise, the JSON data of events with transformed attributes is dropped and
+// only their typed attributes are forwarded. Callbacks which are nil in listener are also nil in the returned
+// listener, except for InitializeModuleData which is needed to resolve the transformed fields.
+func TransformFields(listener Listener, opts TransformOptions) (Listener, error) {
+	// transforms maps module names to type names to field names to actions
+	transforms := map[string]map[string]map[string]TransformAction{}
+	for _, t := range opts.Fields {
+		if err := t.Action.validate(); err != nil {
+			return Listener{}, fmt.Errorf("invalid transform of field %s.%s.%s: %v", t.ModuleName, t.TypeName, t.FieldName, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		types, ok := transforms[t.ModuleName]
+		if !ok {
+			types = map[string]map[string]TransformAction{}
+			transforms[t.ModuleName] = types
+		}
+		fields, ok := types[t.TypeName]
+		if !ok {
+			fields = map[string]TransformAction{}
+			types[t.TypeName] = fields
+		}
+		fields[t.FieldName] = t.Action
+	}
+	if opts.TxMemo != "" {
+		if err := opts.TxMemo.validate(); err != nil {
+			return Listener{}, fmt.Errorf("invalid transform of transaction memos: %v", err) //nolint:errorlint // false positive due to using go1.12
+		}
+	}
+
+	if len(transforms) == 0 && opts.TxMemo == "" {
+		return listener, nil
+	}
+
+	// objectTypes and eventTypes hold the types with transformed fields of the initialized modules
+	objectTypes := map[string]map[string]schema.ObjectType{}
+	eventTypes := map[string]map[string]schema.EventType{}
+
+	res := listener
+
+	if len(transforms) != 0 && (listener.InitializeModuleData != nil || listener.OnObjectUpdate != nil || listener.OnEvent != nil) {
+		res.InitializeModuleData = func(data ModuleInitializationData) error {
+			for typeName, fields := range transforms[data.ModuleName] {
+				if err := initTransformedType(data, typeName, fields, objectTypes, eventTypes); err != nil {
+					return err
+				}
+			}
+
+			if listener.InitializeModuleData == nil {
+				return nil
+			}
+			return listener.InitializeModuleData(data)
+		}
+	}
+
+	if listener.OnKVPair != nil && len(transforms) != 0 {
+		res.OnKVPair = func(data KVPairData) error {
+			var updates []ModuleKVPairUpdate
+			for _, update := range data.Updates {
+				if _, ok := transforms[update.ModuleName]; !ok {
+					updates = append(updates, update)
+				}
+			}
+
+			if len(updates) == 0 {
+				return nil
+			}
+			return listener.OnKVPair(KVPairData{Updates: updates})
+		}
+	}
+
+	if listener.OnObjectUpdate != nil && len(transforms) != 0 {
+		res.OnObjectUpdate = func(data ObjectUpdateData) error {
+			types, ok := transforms[data.ModuleName]
+			if !ok {
+				return listener.OnObjectUpdate(data)
+			}
+
+			updates := make([]schema.ObjectUpdate, len(data.Updates))
+			for i, update := range data.Updates {
+				fields, ok := types[update.TypeName]
+				if ok && !update.Delete {
+					objectType, ok := objectTypes[data.ModuleName][update.TypeName]
+					if !ok {
+						return fmt.Errorf("can't transform fields of object type %s in module %s which was not initialized", update.TypeName, data.ModuleName)
+					}
+
+					value, err := transformObjectValue(objectType, fields, update.Value)
+					if err != nil {
+						return fmt.Errorf("can't transform fields of object type %s in module %s: %v", update.TypeName, data.ModuleName, err) //nolint:errorlint // false positive due to using go1.12
+					}
+					update.Value = value
+				}
+				updates[i] = update
+			}
+
+			data.Updates = updates
+			return listener.OnObjectUpdate(data)
+		}
+	}
+
+	if listener.OnEvent != nil && len(transforms) != 0 {
+		res.OnEvent = func(data EventData) error {
+			fields, ok := transforms[data.ModuleName][data.Type]
+			if !ok || data.ModuleName == "" {
+				return listener.OnEvent(data)
+			}
+
+			eventType, ok := eventTypes[data.ModuleName][data.Type]
+			if !ok {
+				return fmt.Errorf("can't transform attributes of event type %s in module %s which was not initialized", data.Type, data.ModuleName)
+			}
+
+			data.Data = nil
+			if data.Attributes != nil {
+				toAttributes := data.Attributes
+				data.Attributes = func() ([]EventAttribute, error) {
+					attrs, err := toAttributes()
+					if err != nil {
+						return nil, err
+					}
+					return transformEventAttributes(eventType, fields, attrs)
+				}
+			}
+			return listener.OnEvent(data)
+		}
+	}
+
+	if listener.OnTx != nil && opts.TxMemo != "" {
+		res.OnTx = func(data TxData) error {
+			data.Bytes = nil
+			data.JSON = nil
+			data.Decoded = transformTxMemo(data.Decoded, opts.TxMemo)
+			return listener.OnTx(data)
+		}
+	}
+
+	if listener.OnSimulation != nil && opts.TxMemo != "" {
+		res.OnSimulation = func(data SimulationData) error {
+			data.Bytes = nil
+			data.Decoded = transformTxMemo(data.Decoded, opts.TxMemo)
+			return listener.OnSimulation(data)
+		}
+	}
+
+	return res, nil
+}
+
+// transformTxMemo returns a function which applies action to the memo of the transaction returned by
+// toDecoded.
+func transformTxMemo(toDecoded ToDecodedTx, action TransformAction) ToDecodedTx {
+	if toDecoded == nil {
+		return nil
+	}
+	memoField := schema.Field{Name: "memo", Kind: schema.StringKind}
+	return func() (DecodedTx, error) {
+		tx, err := toDecoded()
+		if err != nil {
+			return DecodedTx{}, err
+		}
+		memo, err := action.apply(memoField, tx.Memo)
+		if err != nil {
+			return DecodedTx{}, err
+		}
+		tx.Memo = memo.(string)
+		return tx, nil
+	}
+}
+
+// initTransformedType validates the transformed fields of the named type in the module schema and records the
+// type in objectTypes or eventTypes.
+func initTransformedType(data ModuleInitializationData, typeName string, fields map[string]TransformAction, objectTypes map[string]map[string]schema.ObjectType, eventTypes map[string]map[string]schema.EventType) error {
+	typ, found := data.Schema.LookupType(typeName)
+	if !found {
+		return fmt.Errorf("can't transform fields of type %s which is not in the schema of module %s", typeName, data.ModuleName)
+	}
+
+	var typeFields []schema.Field
+	switch typ := typ.(type) {
+	case schema.ObjectType:
+		for _, field := range typ.KeyFields {
+			if _, ok := fields[field.Name]; ok {
+				return fmt.Errorf("can't transform key field %s of object type %s in module %s", field.Name, typeName, data.ModuleName)
+			}
+		}
+		typeFields = typ.ValueFields
+		if objectTypes[data.ModuleName] == nil {
+			objectTypes[data.ModuleName] = map[string]schema.ObjectType{}
+		}
+		objectTypes[data.ModuleName][typeName] = typ
+	case schema.EventType:
+		typeFields = typ.Fields
+		if eventTypes[data.ModuleName] == nil {
+			eventTypes[data.ModuleName] = map[string]schema.EventType{}
+		}
+		eventTypes[data.ModuleName][typeName] = typ
+	default:
+		return fmt.Errorf("can't transform fields of type %s in module %s which is not an object or event type", typeName, data.ModuleName)
+	}
+
+	for fieldName, action := range fields {
+		field, ok := lookupField(typeFields, fieldName)
+		if !ok {
+			return fmt.Errorf("can't transform unknown field %s of type %s in module %s", fieldName, typeName, data.ModuleName)
+		}
+		if err := action.check(field); err != nil {
+			return fmt.Errorf("can't transform field %s of type %s in module %s: %v", fieldName, typeName, data.ModuleName, err) //nolint:errorlint // false positive due to using go1.12
+		}
+	}
+	return nil
+}
+
+// transformObjectValue returns a copy of the value of an object update with the transformed fields replaced.
+// The value must be encoded as described in schema.ObjectUpdate.Value.
+func transformObjectValue(objectType schema.ObjectType, fields map[string]TransformAction, value interface{}) (interface{}, error) {
+	if valueUpdates, ok := value.(schema.ValueUpdates); ok {
+		res := schema.MapValueUpdates{}
+		var applyErr error
+		err := valueUpdates.Iterate(func(fieldName string, fieldValue interface{}) bool {
+			if action, ok := fields[fieldName]; ok {
+				field, _ := lookupField(objectType.ValueFields, fieldName)
+				fieldValue, applyErr = action.apply(field, fieldValue)
+				if applyErr != nil {
+					return false
+				}
+			}
+			res[fieldName] = fieldValue
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		if applyErr != nil {
+			return nil, applyErr
+		}
+		return res, nil
+	}
+
+	if len(objectType.ValueFields) == 1 {
+		return fields[objectType.ValueFields[0].Name].apply(objectType.ValueFields[0], value)
+	}
+
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected slice of values for value fields, got %T", value)
+	}
+	if len(values) != len(objectType.ValueFields) {
+		return nil, fmt.Errorf("expected %d value fields, got %d values", len(objectType.ValueFields), len(values))
+	}
+
+	res := make([]interface{}, len(values))
+	copy(res, values)
+	for i, field := range objectType.ValueFields {
+		action, ok := fields[field.Name]
+		if !ok {
+			continue
+		}
+		var err error
+		if res[i], err = action.apply(field, res[i]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// transformEventAttributes returns a copy of the attributes of an event with the transformed attributes replaced.
+func transformEventAttributes(eventType schema.EventType, fields map[string]TransformAction, attrs []EventAttribute) ([]EventAttribute, error) {
+	res := make([]EventAttribute, len(attrs))
+	for i, attr := range attrs {
+		if action, ok := fields[attr.Key]; ok {
+			field, _ := lookupField(eventType.Fields, attr.Key)
+			value, err := action.apply(field, attr.Value)
+			if err != nil {
+				return nil, fmt.Errorf("can't transform attribute %s of event type %s: %v", attr.Key, eventType.Name, err) //nolint:errorlint // false positive due to using go1.12
+			}
+			attr.Value = value
+		}
+		res[i] = attr
+	}
+	return res, nil
+}
+
+func lookupField(fields []schema.Field, name string) (schema.Field, bool) {
+	for _, field := range fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return schema.Field{}, false
+}
+
+func (a TransformAction) validate() error {
+	switch a {
+	case RedactAction, HashAction:
+		return nil
+	default:
+		return fmt.Errorf("unknown transform action %q", a)
+	}
+}
+
+// check returns an error if the action can't be applied to the values of field.
+func (a TransformAction) check(field schema.Field) error {
+	switch a {
+	case RedactAction:
+		switch field.Kind {
+		case schema.StringKind, schema.BytesKind, schema.JSONKind:
+			return nil
+		}
+		if field.Nullable {
+			return nil
+		}
+		return fmt.Errorf("can't redact non-nullable field of kind %s", field.Kind)
+	case HashAction:
+		switch field.Kind {
+		case schema.StringKind, schema.BytesKind, schema.AddressKind:
+			return nil
+		}
+		return fmt.Errorf("can't hash field of kind %s", field.Kind)
+	}
+	return a.validate()
+}
+
+// apply returns the transformed value of field. Nil values and the values of fields without an action are
+// returned as is.
+func (a TransformAction) apply(field schema.Field, value interface{}) (interface{}, error) {
+	if value == nil || a == "" {
+		return value, nil
+	}
+
+	switch a {
+	case RedactAction:
+		switch {
+		case field.Nullable:
+			return nil, nil
+		case field.Kind == schema.StringKind:
+			return "", nil
+		case field.Kind == schema.BytesKind:
+			return []byte{}, nil
+		case field.Kind == schema.JSONKind:
+			return json.RawMessage("null"), nil
+		}
+	case HashAction:
+		switch v := value.(type) {
+		case string:
+			hash := sha256.Sum256([]byte(v))
+			return hex.EncodeToString(hash[:]), nil
+		case []byte:
+			hash := sha256.Sum256(v)
+			return hash[:], nil
+		}
+	}
+	return nil, fmt.Errorf("can't %s value of type %T of field %s with kind %s", a, value, field.Name, field.Kind)
+}
@@ -0,0 +1,198 @@
+package appdata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cosmossdk.io/schema"
+)
+
+func TestTransformFields(t *testing.T) {
+	modSchema, err := schema.NewModuleSchema([]schema.ObjectType{
+		{
+			Name:      "accounts",
+			KeyFields: []schema.Field{{Name: "address", Kind: schema.AddressKind}},
+			ValueFields: []schema.Field{
+				{Name: "name", Kind: schema.StringKind},
+				{Name: "note", Kind: schema.StringKind, Nullable: true},
+				{Name: "balance", Kind: schema.Uint64Kind},
+			},
+		},
+		{
+			Name:        "notes",
+			KeyFields:   []schema.Field{{Name: "id", Kind: schema.Uint64Kind}},
+			ValueFields: []schema.Field{{Name: "text", Kind: schema.StringKind}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	modSchema, err = modSchema.WithEventTypes(schema.EventType{
+		Name:   "send",
+		Fields: []schema.Field{{Name: "memo", Kind: schema.StringKind}, {Name: "amount", Kind: schema.Uint64Kind}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var received []Packet
+	listener, err := TransformFields(recordingListener(&received), TransformOptions{
+		Fields: []FieldTransform{
+			{ModuleName: "mod", TypeName: "accounts", FieldName: "name", Action: HashAction},
+			{ModuleName: "mod", TypeName: "accounts", FieldName: "note", Action: RedactAction},
+			{ModuleName: "mod", TypeName: "notes", FieldName: "text", Action: RedactAction},
+			{ModuleName: "mod", TypeName: "send", FieldName: "memo", Action: HashAction},
+		},
+		TxMemo: RedactAction,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := func(s string) string {
+		bz := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(bz[:])
+	}
+
+	packets := []Packet{
+		ModuleInitializationData{ModuleName: "mod", Schema: modSchema},
+		KVPairData{Updates: []ModuleKVPairUpdate{
+			{ModuleName: "mod", Update: schema.KVPairUpdate{Key: []byte("a")}},
+			{ModuleName: "other", Update: schema.KVPairUpdate{Key: []byte("b")}},
+		}},
+		ObjectUpdateData{ModuleName: "mod", Updates: []schema.ObjectUpdate{
+			{TypeName: "accounts", Key: []byte{1}, Value: []interface{}{"alice", "secret", uint64(10)}},
+			{TypeName: "accounts", Key: []byte{2}, Value: schema.MapValueUpdates{"name": "bob", "balance": uint64(5)}},
+			{TypeName: "accounts", Key: []byte{3}, Delete: true},
+			{TypeName: "notes", Key: uint64(1), Value: "hello"},
+		}},
+		EventData{ModuleName: "mod", Type: "send", Data: func() (json.RawMessage, error) {
+			return json.RawMessage(`{"memo":"hi"}`), nil
+		}, Attributes: func() ([]EventAttribute, error) {
+			return []EventAttribute{{Key: "memo", Value: "hi"}, {Key: "amount", Value: uint64(3)}}, nil
+		}},
+		TxData{TxIndex: 0, Bytes: func() ([]byte, error) { return []byte("tx"), nil }, Decoded: func() (DecodedTx, error) {
+			return DecodedTx{Memo: "private", GasLimit: 100}, nil
+		}},
+	}
+	for _, p := range packets {
+		if err := listener.SendPacket(p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(received) != 5 {
+		t.Fatalf("expected 5 packets, got %d: %v", len(received), received)
+	}
+
+	kvData := received[1].(KVPairData)
+	if len(kvData.Updates) != 1 || kvData.Updates[0].ModuleName != "other" {
+		t.Fatalf("expected only the kv-pairs of module other, got %v", kvData.Updates)
+	}
+
+	updates := received[2].(ObjectUpdateData).Updates
+	if !reflect.DeepEqual(updates[0].Value, []interface{}{hash("alice"), nil, uint64(10)}) {
+		t.Fatalf("unexpected value %v", updates[0].Value)
+	}
+	if !reflect.DeepEqual(updates[1].Value, schema.MapValueUpdates{"name": hash("bob"), "balance": uint64(5)}) {
+		t.Fatalf("unexpected value %v", updates[1].Value)
+	}
+	if !updates[2].Delete {
+		t.Fatalf("expected delete to be forwarded as is, got %v", updates[2])
+	}
+	if updates[3].Value != "" {
+		t.Fatalf("expected redacted value, got %v", updates[3].Value)
+	}
+	// the input must not be modified
+	if packets[2].(ObjectUpdateData).Updates[0].Value.([]interface{})[0] != "alice" {
+		t.Fatalf("expected the input update to be unchanged")
+	}
+
+	event := received[3].(EventData)
+	if event.Data != nil {
+		t.Fatalf("expected the JSON data of the event to be dropped")
+	}
+	attrs, err := event.Attributes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(attrs, []EventAttribute{{Key: "memo", Value: hash("hi")}, {Key: "amount", Value: uint64(3)}}) {
+		t.Fatalf("unexpected attributes %v", attrs)
+	}
+
+	tx := received[4].(TxData)
+	if tx.Bytes != nil {
+		t.Fatalf("expected the raw bytes of the transaction to be dropped")
+	}
+	decoded, err := tx.Decoded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Memo != "" || decoded.GasLimit != 100 {
+		t.Fatalf("unexpected decoded transaction %v", decoded)
+	}
+}
+
+func TestTransformFields_errors(t *testing.T) {
+	modSchema, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:        "accounts",
+		KeyFields:   []schema.Field{{Name: "address", Kind: schema.AddressKind}},
+		ValueFields: []schema.Field{{Name: "balance", Kind: schema.Uint64Kind}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		name      string
+		transform FieldTransform
+		errMsg    string
+	}{
+		{
+			name:      "unknown action",
+			transform: FieldTransform{ModuleName: "mod", TypeName: "accounts", FieldName: "balance", Action: "encrypt"},
+			errMsg:    "unknown transform action",
+		},
+		{
+			name:      "unknown type",
+			transform: FieldTransform{ModuleName: "mod", TypeName: "foo", FieldName: "balance", Action: RedactAction},
+			errMsg:    "not in the schema",
+		},
+		{
+			name:      "unknown field",
+			transform: FieldTransform{ModuleName: "mod", TypeName: "accounts", FieldName: "foo", Action: RedactAction},
+			errMsg:    "unknown field",
+		},
+		{
+			name:      "key field",
+			transform: FieldTransform{ModuleName: "mod", TypeName: "accounts", FieldName: "address", Action: HashAction},
+			errMsg:    "key field",
+		},
+		{
+			name:      "redact non-nullable integer",
+			transform: FieldTransform{ModuleName: "mod", TypeName: "accounts", FieldName: "balance", Action: RedactAction},
+			errMsg:    "can't redact non-nullable field of kind uint64",
+		},
+		{
+			name:      "hash integer",
+			transform: FieldTransform{ModuleName: "mod", TypeName: "accounts", FieldName: "balance", Action: HashAction},
+			errMsg:    "can't hash field of kind uint64",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var received []Packet
+			listener, err := TransformFields(recordingListener(&received), TransformOptions{Fields: []FieldTransform{tc.transform}})
+			if err == nil {
+				err = listener.SendPacket(ModuleInitializationData{ModuleName: "mod", Schema: modSchema})
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Fatalf("expected error containing %q, got %v", tc.errMsg, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package appdata
+
+// TxDecoder decodes the raw bytes of a transaction.
+type TxDecoder = func(txBytes []byte) (DecodedTx, error)
+
+// TxDecodingListener returns a listener which sets TxData.Decoded using decoder for transactions which have
+// Bytes but which were not decoded by the source before forwarding them to listener. The transaction is
+// only decoded if listener calls Decoded, so that listeners which only use the raw bytes do not pay the
+// cost of decoding. All other callbacks are forwarded as is.
+func TxDecodingListener(listener Listener, decoder TxDecoder) Listener {
+	if listener.OnTx == nil {
+		return listener
+	}
+
+	onTx := listener.OnTx
+	listener.OnTx = func(data TxData) error {
+		if data.Decoded == nil && data.Bytes != nil {
+			toBytes := data.Bytes
+			data.Decoded = func() (DecodedTx, error) {
+				bz, err := toBytes()
+				if err != nil {
+					return DecodedTx{}, err
+				}
+				return decoder(bz)
+			}
+		}
+		return onTx(data)
+	}
+	return listener
+}
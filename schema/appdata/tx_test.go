@@ -0,0 +1,63 @@
+package appdata
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTxDecodingListener(t *testing.T) {
+	decodeErr := errors.New("decode error")
+	decoder := func(txBytes []byte) (DecodedTx, error) {
+		if len(txBytes) == 0 {
+			return DecodedTx{}, decodeErr
+		}
+		return DecodedTx{MsgTypeURLs: []string{string(txBytes)}}, nil
+	}
+
+	var received []TxData
+	listener := TxDecodingListener(Listener{
+		OnTx: func(data TxData) error {
+			received = append(received, data)
+			return nil
+		},
+	}, decoder)
+
+	predecoded := DecodedTx{Memo: "source"}
+	txs := []TxData{
+		{TxIndex: 0, Bytes: func() ([]byte, error) { return []byte("/cosmos.bank.v1beta1.MsgSend"), nil }},
+		{TxIndex: 1, Bytes: func() ([]byte, error) { return nil, nil }},
+		{TxIndex: 2, Bytes: func() ([]byte, error) { return []byte("x"), nil }, Decoded: func() (DecodedTx, error) { return predecoded, nil }},
+		{TxIndex: 3},
+	}
+	for _, tx := range txs {
+		if err := listener.OnTx(tx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	decoded, err := received[0].Decoded()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.MsgTypeURLs, []string{"/cosmos.bank.v1beta1.MsgSend"}) {
+		t.Fatalf("unexpected decoded tx: %v", decoded)
+	}
+
+	if _, err := received[1].Decoded(); err != decodeErr { //nolint:errorlint // false positive due to using go1.12
+		t.Fatalf("expected decode error, got: %v", err)
+	}
+
+	decoded, err = received[2].Decoded()
+	if err != nil || decoded.Memo != "source" {
+		t.Fatalf("expected the source's decoded tx, got: %v %v", decoded, err)
+	}
+
+	if received[3].Decoded != nil {
+		t.Fatalf("expected a tx without bytes not to be decoded")
+	}
+
+	if TxDecodingListener(Listener{}, decoder).OnTx != nil {
+		t.Fatalf("expected nil OnTx to stay nil")
+	}
+}
@@ -0,0 +1,204 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CompatibleWith returns an error if this module schema is not an append-only evolution of the older module schema.
+// It is stricter than SchemaDiff.IsCompatible and enforces the following rules:
+//   - object, enum, struct, oneof and event types cannot be removed
+//   - reserved type names and reserved field names cannot be unreserved
+//   - key fields cannot be changed in any way
+//   - unique constraints cannot be added to existing object types
+//   - existing value, struct and event fields cannot be removed, reordered, change their kind or referenced
+//     type, restrict their decimal precision or scale, make their time resolution coarser or go from
+//     nullable to non-nullable, and neither can their list elements or map values
+//   - new value, struct and event fields must be nullable or have a default value and must be appended after
+//     all existing fields
+//   - oneof cases cannot be removed and existing cases cannot change in the same ways as existing fields, but
+//     new cases can be added anywhere
+//   - enum values cannot be removed or reordered, new enum values must be appended after all existing values
+//     and the numeric values of existing enum values cannot change
+//
+// All violations are reported in the returned error.
+func (s ModuleSchema) CompatibleWith(older ModuleSchema) error {
+	diff := DiffModuleSchemas(older, s)
+	if diff.Empty() {
+		return nil
+	}
+
+	var errs []string
+
+	for _, objType := range diff.RemovedObjectTypes {
+		errs = append(errs, fmt.Sprintf("object type %q was removed", objType.Name))
+	}
+
+	for _, name := range diff.RemovedReservedTypeNames {
+		errs = append(errs, fmt.Sprintf("reserved type name %q was unreserved", name))
+	}
+
+	for _, objDiff := range diff.ChangedObjectTypes {
+		if !objDiff.KeyFieldsDiff.Empty() {
+			errs = append(errs, fmt.Sprintf("key fields of object type %q changed", objDiff.Name))
+		}
+
+		for _, name := range objDiff.RemovedReservedFieldNames {
+			errs = append(errs, fmt.Sprintf("reserved field name %q of object type %q was unreserved", name, objDiff.Name))
+		}
+
+		for _, constraint := range objDiff.AddedUniqueConstraints {
+			errs = append(errs, fmt.Sprintf("unique constraint (%s) was added to object type %q",
+				uniqueConstraintKey(constraint), objDiff.Name))
+		}
+
+		oldType, _ := older.LookupType(objDiff.Name)
+		newType, _ := s.LookupType(objDiff.Name)
+		errs = append(errs, appendOnlyFieldErrors(
+			fmt.Sprintf("object type %q", objDiff.Name),
+			oldType.(ObjectType).ValueFields,
+			newType.(ObjectType).ValueFields,
+			objDiff.ValueFieldsDiff,
+		)...)
+	}
+
+	for _, structType := range diff.RemovedStructTypes {
+		errs = append(errs, fmt.Sprintf("struct type %q was removed", structType.Name))
+	}
+
+	for _, structDiff := range diff.ChangedStructTypes {
+		oldType, _ := older.LookupType(structDiff.Name)
+		newType, _ := s.LookupType(structDiff.Name)
+		errs = append(errs, appendOnlyFieldErrors(
+			fmt.Sprintf("struct type %q", structDiff.Name),
+			oldType.(StructType).Fields,
+			newType.(StructType).Fields,
+			structDiff.FieldsDiff,
+		)...)
+	}
+
+	for _, oneOfType := range diff.RemovedOneOfTypes {
+		errs = append(errs, fmt.Sprintf("oneof type %q was removed", oneOfType.Name))
+	}
+
+	for _, oneOfDiff := range diff.ChangedOneOfTypes {
+		for _, c := range oneOfDiff.CasesDiff.Removed {
+			errs = append(errs, fmt.Sprintf("case %q of oneof type %q was removed", c.Name, oneOfDiff.Name))
+		}
+
+		for _, caseDiff := range oneOfDiff.CasesDiff.Changed {
+			if !caseDiff.IsCompatible() {
+				errs = append(errs, fmt.Sprintf("case %q of oneof type %q changed incompatibly", caseDiff.Name, oneOfDiff.Name))
+			}
+		}
+	}
+
+	for _, eventType := range diff.RemovedEventTypes {
+		errs = append(errs, fmt.Sprintf("event type %q was removed", eventType.Name))
+	}
+
+	for _, eventDiff := range diff.ChangedEventTypes {
+		oldType, _ := older.LookupType(eventDiff.Name)
+		newType, _ := s.LookupType(eventDiff.Name)
+		errs = append(errs, appendOnlyFieldErrors(
+			fmt.Sprintf("event type %q", eventDiff.Name),
+			oldType.(EventType).Fields,
+			newType.(EventType).Fields,
+			eventDiff.FieldsDiff,
+		)...)
+	}
+
+	for _, enumType := range diff.RemovedEnumTypes {
+		errs = append(errs, fmt.Sprintf("enum type %q was removed", enumType.Name))
+	}
+
+	for _, enumDiff := range diff.ChangedEnumTypes {
+		for _, value := range enumDiff.RemovedValues {
+			errs = append(errs, fmt.Sprintf("value %q of enum type %q was removed", value, enumDiff.Name))
+		}
+
+		for _, value := range enumDiff.ChangedNumericValues {
+			errs = append(errs, fmt.Sprintf("numeric value of %q of enum type %q changed", value, enumDiff.Name))
+		}
+
+		if len(enumDiff.RemovedValues) != 0 {
+			continue
+		}
+
+		oldType, _ := older.LookupType(enumDiff.Name)
+		newType, _ := s.LookupType(enumDiff.Name)
+		oldValues, newValues := oldType.(EnumType).Values, newType.(EnumType).Values
+		for i, value := range oldValues {
+			if newValues[i] != value {
+				errs = append(errs, fmt.Sprintf("values of enum type %q must only be appended", enumDiff.Name))
+				break
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errors.New("incompatible schema changes: " + strings.Join(errs, "; "))
+}
+
+// appendOnlyFieldErrors returns the append-only rule violations for a list of non-key fields.
+func appendOnlyFieldErrors(typeDesc string, oldFields, newFields []Field, diff FieldsDiff) []string {
+	var errs []string
+
+	for _, field := range diff.Removed {
+		errs = append(errs, fmt.Sprintf("field %q of %s was removed", field.Name, typeDesc))
+	}
+
+	for _, fieldDiff := range diff.Changed {
+		if fieldDiff.KindChanged() {
+			errs = append(errs, fmt.Sprintf("kind of field %q of %s changed from %s to %s",
+				fieldDiff.Name, typeDesc, fieldDiff.OldField.Kind, fieldDiff.NewField.Kind))
+		}
+
+		if fieldDiff.ReferencedTypeChanged() {
+			errs = append(errs, fmt.Sprintf("referenced type of field %q of %s changed", fieldDiff.Name, typeDesc))
+		}
+
+		if !fieldDiff.DecimalConstraintsRelaxed() {
+			errs = append(errs, fmt.Sprintf("precision and scale of field %q of %s were restricted", fieldDiff.Name, typeDesc))
+		}
+
+		if !fieldDiff.TimeResolutionRelaxed() {
+			errs = append(errs, fmt.Sprintf("time resolution of field %q of %s changed from %s to %s",
+				fieldDiff.Name, typeDesc, fieldDiff.OldField.TimeResolution, fieldDiff.NewField.TimeResolution))
+		}
+
+		if !fieldDiff.ConstraintsRelaxed() {
+			errs = append(errs, fmt.Sprintf("constraints of field %q of %s were restricted", fieldDiff.Name, typeDesc))
+		}
+
+		if fieldDiff.NullableChanged() && !fieldDiff.NewField.Nullable {
+			errs = append(errs, fmt.Sprintf("field %q of %s is no longer nullable", fieldDiff.Name, typeDesc))
+		}
+
+		if fieldDiff.NullableElementsChanged() && !fieldDiff.NewField.NullableElements {
+			errs = append(errs, fmt.Sprintf("elements of field %q of %s are no longer nullable", fieldDiff.Name, typeDesc))
+		}
+	}
+
+	for _, field := range diff.Added {
+		if !field.Nullable && field.Default == nil {
+			errs = append(errs, fmt.Sprintf("new field %q of %s must be nullable or have a default value", field.Name, typeDesc))
+		}
+	}
+
+	if len(diff.Removed) == 0 {
+		// existing fields must be a prefix of the new fields
+		for i, field := range oldFields {
+			if newFields[i].Name != field.Name {
+				errs = append(errs, fmt.Sprintf("fields of %s must only be appended", typeDesc))
+				break
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,332 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestModuleSchema_CompatibleWith(t *testing.T) {
+	baseObject := ObjectType{
+		Name:        "object1",
+		KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+		ValueFields: []Field{{Name: "value1", Kind: Int32Kind}, {Name: "value2", Kind: StringKind}},
+	}
+	enumObject := func(values ...string) ObjectType {
+		return ObjectType{
+			Name:        "object2",
+			KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+			ValueFields: []Field{{Name: "value1", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: values}}},
+		}
+	}
+
+	oneOfObject := func(cases ...Field) ObjectType {
+		return ObjectType{
+			Name:        "object3",
+			KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+			ValueFields: []Field{{Name: "value1", Kind: OneOfKind, OneOfType: OneOfType{Name: "oneof1", Cases: cases}}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		older       []ObjectType
+		newer       []ObjectType
+		errContains []string
+	}{
+		{
+			name:  "identical",
+			older: []ObjectType{baseObject},
+			newer: []ObjectType{baseObject},
+		},
+		{
+			name:  "object type added",
+			older: []ObjectType{baseObject},
+			newer: []ObjectType{baseObject, enumObject("a")},
+		},
+		{
+			name:        "object type removed",
+			older:       []ObjectType{baseObject, enumObject("a")},
+			newer:       []ObjectType{baseObject},
+			errContains: []string{"object type \"object2\" was removed", "enum type \"enum1\" was removed"},
+		},
+		{
+			name:  "nullable field appended",
+			older: []ObjectType{baseObject},
+			newer: []ObjectType{{
+				Name:      "object1",
+				KeyFields: []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{
+					{Name: "value1", Kind: Int32Kind},
+					{Name: "value2", Kind: StringKind},
+					{Name: "value3", Kind: BoolKind, Nullable: true},
+				},
+			}},
+		},
+		{
+			name:  "nullable field inserted",
+			older: []ObjectType{baseObject},
+			newer: []ObjectType{{
+				Name:      "object1",
+				KeyFields: []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{
+					{Name: "value1", Kind: Int32Kind},
+					{Name: "value3", Kind: BoolKind, Nullable: true},
+					{Name: "value2", Kind: StringKind},
+				},
+			}},
+			errContains: []string{"fields of object type \"object1\" must only be appended"},
+		},
+		{
+			name:  "non-nullable field appended",
+			older: []ObjectType{baseObject},
+			newer: []ObjectType{{
+				Name:      "object1",
+				KeyFields: []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{
+					{Name: "value1", Kind: Int32Kind},
+					{Name: "value2", Kind: StringKind},
+					{Name: "value3", Kind: BoolKind},
+				},
+			}},
+			errContains: []string{"new field \"value3\" of object type \"object1\" must be nullable"},
+		},
+		{
+			name:  "non-nullable field with default appended",
+			older: []ObjectType{baseObject},
+			newer: []ObjectType{{
+				Name:      "object1",
+				KeyFields: []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{
+					{Name: "value1", Kind: Int32Kind},
+					{Name: "value2", Kind: StringKind},
+					{Name: "value3", Kind: BoolKind, Default: true},
+				},
+			}},
+		},
+		{
+			name:  "field removed and kind changed",
+			older: []ObjectType{baseObject},
+			newer: []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: Int64Kind}},
+			}},
+			errContains: []string{
+				"field \"value2\" of object type \"object1\" was removed",
+				"kind of field \"value1\" of object type \"object1\" changed from int32 to int64",
+			},
+		},
+		{
+			name: "field no longer nullable",
+			older: []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: Int32Kind, Nullable: true}},
+			}},
+			newer: []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: Int32Kind}},
+			}},
+			errContains: []string{"field \"value1\" of object type \"object1\" is no longer nullable"},
+		},
+		{
+			name: "decimal constraints restricted",
+			older: []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: DecimalStringKind}},
+			}},
+			newer: []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: DecimalStringKind, Precision: 36, Scale: 18}},
+			}},
+			errContains: []string{"precision and scale of field \"value1\" of object type \"object1\" were restricted"},
+		},
+		{
+			name: "time resolution coarser",
+			older: []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: TimeKind, TimeResolution: TimeResolutionMillis}},
+			}},
+			newer: []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: TimeKind, TimeResolution: TimeResolutionSeconds}},
+			}},
+			errContains: []string{"time resolution of field \"value1\" of object type \"object1\" changed from millis to seconds"},
+		},
+		{
+			name: "constraints widened",
+			older: []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: Int64Kind, Constraints: FieldConstraints{Min: "0", Max: "100"}}},
+			}},
+			newer: []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: Int64Kind, Constraints: FieldConstraints{Min: "-100"}}},
+			}},
+		},
+		{
+			name: "constraints narrowed",
+			older: []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: StringKind, Constraints: FieldConstraints{MaxLength: 64}}},
+			}},
+			newer: []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: StringKind, Constraints: FieldConstraints{MaxLength: 32}}},
+			}},
+			errContains: []string{"constraints of field \"value1\" of object type \"object1\" were restricted"},
+		},
+		{
+			name:  "reserved field name added",
+			older: []ObjectType{baseObject},
+			newer: []ObjectType{{
+				Name:               "object1",
+				KeyFields:          baseObject.KeyFields,
+				ValueFields:        baseObject.ValueFields,
+				ReservedFieldNames: []string{"value3"},
+			}},
+		},
+		{
+			name: "reserved field name unreserved",
+			older: []ObjectType{{
+				Name:               "object1",
+				KeyFields:          baseObject.KeyFields,
+				ValueFields:        baseObject.ValueFields,
+				ReservedFieldNames: []string{"value3"},
+			}},
+			newer:       []ObjectType{baseObject},
+			errContains: []string{"reserved field name \"value3\" of object type \"object1\" was unreserved"},
+		},
+		{
+			name:  "unique constraint added",
+			older: []ObjectType{baseObject},
+			newer: []ObjectType{{
+				Name:              "object1",
+				KeyFields:         baseObject.KeyFields,
+				ValueFields:       baseObject.ValueFields,
+				UniqueConstraints: [][]string{{"value1", "value2"}},
+			}},
+			errContains: []string{"unique constraint (value1, value2) was added to object type \"object1\""},
+		},
+		{
+			name: "unique constraint removed",
+			older: []ObjectType{{
+				Name:              "object1",
+				KeyFields:         baseObject.KeyFields,
+				ValueFields:       baseObject.ValueFields,
+				UniqueConstraints: [][]string{{"value1"}},
+			}},
+			newer: []ObjectType{baseObject},
+		},
+		{
+			name:  "key field changed",
+			older: []ObjectType{baseObject},
+			newer: []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: BytesKind}},
+				ValueFields: baseObject.ValueFields,
+			}},
+			errContains: []string{"key fields of object type \"object1\" changed"},
+		},
+		{
+			name:  "oneof case inserted",
+			older: []ObjectType{oneOfObject(Field{Name: "a", Kind: StringKind})},
+			newer: []ObjectType{oneOfObject(Field{Name: "b", Kind: Int32Kind}, Field{Name: "a", Kind: StringKind})},
+		},
+		{
+			name:        "oneof case removed and changed",
+			older:       []ObjectType{oneOfObject(Field{Name: "a", Kind: StringKind}, Field{Name: "b", Kind: Int32Kind})},
+			newer:       []ObjectType{oneOfObject(Field{Name: "b", Kind: Int64Kind})},
+			errContains: []string{"case \"a\" of oneof type \"oneof1\" was removed", "case \"b\" of oneof type \"oneof1\" changed incompatibly"},
+		},
+		{
+			name:  "enum value appended",
+			older: []ObjectType{enumObject("a", "b")},
+			newer: []ObjectType{enumObject("a", "b", "c")},
+		},
+		{
+			name:        "enum value inserted",
+			older:       []ObjectType{enumObject("a", "b")},
+			newer:       []ObjectType{enumObject("a", "c", "b")},
+			errContains: []string{"values of enum type \"enum1\" must only be appended"},
+		},
+		{
+			name:  "enum numeric value changed",
+			older: []ObjectType{enumObject("a", "b")},
+			newer: []ObjectType{{
+				Name:      "object2",
+				KeyFields: []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{
+					Name:     "value1",
+					Kind:     EnumKind,
+					EnumType: EnumType{Name: "enum1", Values: []string{"a", "b"}, NumericValues: []int32{0, 2}},
+				}},
+			}},
+			errContains: []string{"numeric value of \"b\" of enum type \"enum1\" changed"},
+		},
+		{
+			name:        "enum value removed",
+			older:       []ObjectType{enumObject("a", "b")},
+			newer:       []ObjectType{enumObject("a")},
+			errContains: []string{"value \"b\" of enum type \"enum1\" was removed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			older := requireModuleSchema(t, tt.older)
+			newer := requireModuleSchema(t, tt.newer)
+			err := newer.CompatibleWith(older)
+			if len(tt.errContains) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+
+			for _, errContains := range tt.errContains {
+				if !strings.Contains(err.Error(), errContains) {
+					t.Errorf("expected error to contain %q, got: %v", errContains, err)
+				}
+			}
+		})
+	}
+
+	t.Run("event types", func(t *testing.T) {
+		burn := EventType{Name: "burn", Fields: []Field{{Name: "amount", Kind: IntegerStringKind}}}
+		older := requireEventTypes(t, requireModuleSchema(t, []ObjectType{baseObject}), burn, testTransferEvent)
+		newer := requireEventTypes(t, requireModuleSchema(t, []ObjectType{baseObject}),
+			EventType{Name: "burn", Fields: []Field{{Name: "amount", Kind: IntegerStringKind}, {Name: "burner", Kind: AddressKind}}})
+		err := newer.CompatibleWith(older)
+		for _, errContains := range []string{
+			"event type \"transfer\" was removed",
+			"new field \"burner\" of event type \"burn\" must be nullable or have a default value",
+		} {
+			if err == nil || !strings.Contains(err.Error(), errContains) {
+				t.Errorf("expected error to contain %q, got: %v", errContains, err)
+			}
+		}
+	})
+
+	t.Run("reserved type name unreserved", func(t *testing.T) {
+		older := requireReservedTypeNames(t, requireModuleSchema(t, []ObjectType{baseObject}), "old1", "old2")
+		newer := requireReservedTypeNames(t, requireModuleSchema(t, []ObjectType{baseObject}), "old2", "old3")
+		err := newer.CompatibleWith(older)
+		if err == nil || !strings.Contains(err.Error(), "reserved type name \"old1\" was unreserved") {
+			t.Fatalf("expected unreserved type name error, got: %v", err)
+		}
+	})
+}
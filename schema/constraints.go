@@ -0,0 +1,255 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+)
+
+// FieldConstraints are optional constraints on the values of a field in addition to those implied by its kind.
+// Like Precision and Scale, they apply to the elements of list fields and the values of map fields. Indexers
+// can use them to generate CHECK constraints and APIs can use them to validate inputs. The zero value has no
+// constraints.
+type FieldConstraints struct {
+	// Min is the inclusive lower bound of the values of integer, IntegerStringKind, DecimalStringKind and float
+	// fields. It must match DecimalFormat, or IntegerFormat for integer and IntegerStringKind fields. If it is
+	// empty, the values have no lower bound.
+	Min string `json:"min,omitempty"`
+
+	// Max is the inclusive upper bound of the values of the same kinds as Min and has the same format. If it is
+	// empty, the values have no upper bound.
+	Max string `json:"max,omitempty"`
+
+	// MaxLength is the maximum number of characters, i.e. unicode code points, of the values of StringKind
+	// fields. Zero means that the length is unlimited.
+	MaxLength uint32 `json:"max_length,omitempty"`
+
+	// Pattern is a regular expression in the syntax of the go regexp package which the values of StringKind
+	// fields must match. As in JSON schema, it isn't anchored, so it should start with ^ and end with $ to match
+	// whole values.
+	Pattern string `json:"pattern,omitempty"`
+
+	// Size is the exact number of bytes of the values of BytesKind and AddressKind fields, e.g. 20 for the
+	// account addresses of most chains. Zero means that values can have any size.
+	Size uint32 `json:"size,omitempty"`
+}
+
+// IsZero returns true if there are no constraints.
+func (c FieldConstraints) IsZero() bool {
+	return c == FieldConstraints{}
+}
+
+// validate validates the constraints of fields, list elements or map values of the given kind.
+func (c FieldConstraints) validate(kind Kind) error {
+	if c.Min != "" || c.Max != "" {
+		if !isNumericKind(kind) {
+			return fmt.Errorf("min and max are not valid for kind %s", kind)
+		}
+
+		lower, err := c.bound(kind, c.Min)
+		if err != nil {
+			return fmt.Errorf("invalid min: %v", err) //nolint:errorlint // false positive due to using go1.12
+		}
+
+		upper, err := c.bound(kind, c.Max)
+		if err != nil {
+			return fmt.Errorf("invalid max: %v", err) //nolint:errorlint // false positive due to using go1.12
+		}
+
+		if lower != nil && upper != nil && lower.Cmp(upper) > 0 {
+			return fmt.Errorf("min %s is greater than max %s", c.Min, c.Max)
+		}
+	}
+
+	if (c.MaxLength != 0 || c.Pattern != "") && kind != StringKind {
+		return fmt.Errorf("max length and pattern are not valid for kind %s", kind)
+	}
+
+	if c.Pattern != "" {
+		if _, err := compilePattern(c.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %v", err) //nolint:errorlint // false positive due to using go1.12
+		}
+	}
+
+	if c.Size != 0 && kind != BytesKind && kind != AddressKind {
+		return fmt.Errorf("size is not valid for kind %s", kind)
+	}
+
+	return nil
+}
+
+// bound parses a min or max bound, returning nil if it is empty.
+func (c FieldConstraints) bound(kind Kind, bound string) (*big.Rat, error) {
+	if bound == "" {
+		return nil, nil
+	}
+
+	if kind == Float32Kind || kind == Float64Kind || kind == DecimalStringKind {
+		if !decimalRegex.MatchString(bound) {
+			return nil, fmt.Errorf("expected decimal number, got %s", bound)
+		}
+	} else if !integerRegex.MatchString(bound) {
+		return nil, fmt.Errorf("expected base10 integer, got %s", bound)
+	}
+
+	res, ok := new(big.Rat).SetString(bound)
+	if !ok {
+		return nil, fmt.Errorf("invalid number %s", bound)
+	}
+	return res, nil
+}
+
+// validateValue validates that a value of the given kind, whose go type has already been validated, satisfies
+// the constraints. The constraints are assumed to be valid for the kind.
+func (c FieldConstraints) validateValue(kind Kind, value interface{}) error {
+	switch {
+	case c.Min != "" || c.Max != "":
+		return c.validateRange(kind, value)
+	case kind == StringKind:
+		str := value.(string)
+		if c.MaxLength != 0 && utf8.RuneCountInString(str) > int(c.MaxLength) {
+			return fmt.Errorf("string %q is longer than the maximum length of %d", str, c.MaxLength)
+		}
+		if c.Pattern != "" {
+			re, err := compilePattern(c.Pattern)
+			if err != nil {
+				return err
+			}
+			if !re.MatchString(str) {
+				return fmt.Errorf("string %q does not match pattern %s", str, c.Pattern)
+			}
+		}
+	case kind == BytesKind || kind == AddressKind:
+		if bz := value.([]byte); c.Size != 0 && len(bz) != int(c.Size) {
+			return fmt.Errorf("expected %d bytes, got %d", c.Size, len(bz))
+		}
+	}
+	return nil
+}
+
+func (c FieldConstraints) validateRange(kind Kind, value interface{}) error {
+	var num *big.Rat
+	switch v := value.(type) {
+	case int8:
+		num = new(big.Rat).SetInt64(int64(v))
+	case int16:
+		num = new(big.Rat).SetInt64(int64(v))
+	case int32:
+		num = new(big.Rat).SetInt64(int64(v))
+	case int64:
+		num = new(big.Rat).SetInt64(v)
+	case uint8:
+		num = new(big.Rat).SetInt64(int64(v))
+	case uint16:
+		num = new(big.Rat).SetInt64(int64(v))
+	case uint32:
+		num = new(big.Rat).SetInt64(int64(v))
+	case uint64:
+		num = new(big.Rat).SetInt(new(big.Int).SetUint64(v))
+	case float32:
+		return c.validateFloat(float64(v))
+	case float64:
+		return c.validateFloat(v)
+	case string:
+		// the format is checked before parsing so that huge exponents can't be used to allocate huge numbers
+		var err error
+		if num, err = c.bound(kind, v); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unexpected numeric value of type %T", value)
+	}
+
+	if lower, _ := c.bound(kind, c.Min); lower != nil && num.Cmp(lower) < 0 {
+		return fmt.Errorf("value %v is less than the minimum of %s", value, c.Min)
+	}
+	if upper, _ := c.bound(kind, c.Max); upper != nil && num.Cmp(upper) > 0 {
+		return fmt.Errorf("value %v is greater than the maximum of %s", value, c.Max)
+	}
+	return nil
+}
+
+// validateFloat validates the range of a float value. NaN never satisfies a bound and infinities only
+// satisfy the bound on the other side.
+func (c FieldConstraints) validateFloat(f float64) error {
+	switch {
+	case math.IsNaN(f):
+		return errors.New("NaN is not within the bounds of the field")
+	case math.IsInf(f, 1):
+		if c.Max != "" {
+			return fmt.Errorf("value %v is greater than the maximum of %s", f, c.Max)
+		}
+		return nil
+	case math.IsInf(f, -1):
+		if c.Min != "" {
+			return fmt.Errorf("value %v is less than the minimum of %s", f, c.Min)
+		}
+		return nil
+	}
+
+	num := new(big.Rat).SetFloat64(f)
+	if lower, _ := c.bound(Float64Kind, c.Min); lower != nil && num.Cmp(lower) < 0 {
+		return fmt.Errorf("value %v is less than the minimum of %s", f, c.Min)
+	}
+	if upper, _ := c.bound(Float64Kind, c.Max); upper != nil && num.Cmp(upper) > 0 {
+		return fmt.Errorf("value %v is greater than the maximum of %s", f, c.Max)
+	}
+	return nil
+}
+
+// relaxed returns true if every value which satisfies the old constraints also satisfies c, assuming both are
+// valid for the same kind.
+func (c FieldConstraints) relaxed(old FieldConstraints) bool {
+	if c.Min != "" {
+		newMin, _ := c.bound(DecimalStringKind, c.Min)
+		oldMin, _ := old.bound(DecimalStringKind, old.Min)
+		if oldMin == nil || newMin.Cmp(oldMin) > 0 {
+			return false
+		}
+	}
+
+	if c.Max != "" {
+		newMax, _ := c.bound(DecimalStringKind, c.Max)
+		oldMax, _ := old.bound(DecimalStringKind, old.Max)
+		if oldMax == nil || newMax.Cmp(oldMax) < 0 {
+			return false
+		}
+	}
+
+	if c.MaxLength != 0 && (old.MaxLength == 0 || c.MaxLength < old.MaxLength) {
+		return false
+	}
+
+	return (c.Pattern == "" || c.Pattern == old.Pattern) && (c.Size == 0 || c.Size == old.Size)
+}
+
+func isNumericKind(kind Kind) bool {
+	switch kind {
+	case Int8Kind, Uint8Kind, Int16Kind, Uint16Kind, Int32Kind, Uint32Kind, Int64Kind, Uint64Kind,
+		IntegerStringKind, DecimalStringKind, Float32Kind, Float64Kind:
+		return true
+	default:
+		return false
+	}
+}
+
+// patterns caches the compiled regular expressions of FieldConstraints.Pattern because values are validated
+// far more often than schemas are created.
+var patterns sync.Map
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
@@ -8,7 +8,7 @@ type HasModuleCodec interface {
 	ModuleCodec() (ModuleCodec, error)
 }
 
-// ModuleCodec is a struct that contains the schema and a KVDecoder for a module.
+// ModuleCodec is a struct that contains the schema, a KVDecoder and optionally a KVEncoder for a module.
 type ModuleCodec struct {
 	// Schema is the schema for the module. It is required.
 	Schema ModuleSchema
@@ -16,6 +16,12 @@ type ModuleCodec struct {
 	// KVDecoder is a function that decodes a key-value pair into an ObjectUpdate.
 	// If it is nil, the module doesn't support state decoding directly.
 	KVDecoder KVDecoder
+
+	// KVEncoder is a function that encodes an ObjectUpdate into the key-value pairs which
+	// represent it in the module's state. It is the inverse of KVDecoder and allows state to
+	// be initialized from object updates, for instance at genesis. If it is nil, the module
+	// doesn't support state encoding directly.
+	KVEncoder KVEncoder
 }
 
 // KVDecoder is a function that decodes a key-value pair into one or more ObjectUpdate's.
@@ -26,6 +32,14 @@ type ModuleCodec struct {
 // were decodable to aid debugging.
 type KVDecoder = func(KVPairUpdate) ([]ObjectUpdate, error)
 
+// KVEncoder is a function that encodes an ObjectUpdate into one or more key-value pair updates.
+// Decoding the returned key-value pairs with the module's KVDecoder should yield the update again,
+// except that object updates which are derived from others, such as those of secondary indexes,
+// may be encoded as no key-value pairs. The error result should be non-nil if the update is not
+// valid for the module or cannot be encoded, for instance because it only contains some of the
+// value fields of an object.
+type KVEncoder = func(ObjectUpdate) ([]KVPairUpdate, error)
+
 // KVPairUpdate represents a key-value pair set or delete.
 type KVPairUpdate struct {
 	// Key is the key of the key-value pair.
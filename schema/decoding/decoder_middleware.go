@@ -0,0 +1,85 @@
+package decoding
+
+import (
+	"cosmossdk.io/schema"
+)
+
+// DecoderMiddleware wraps the KVDecoder of a module so that the object updates it returns can be transformed
+// before they reach listeners, for instance to enrich, convert or redact values. The returned decoder will
+// usually call decoder and then transform its result. Transformed updates must still conform to the module's
+// schema. DecoderMiddleware is never called for modules without a KVDecoder.
+type DecoderMiddleware = func(moduleName string, decoder schema.KVDecoder) schema.KVDecoder
+
+// ChainDecoderMiddleware composes multiple middleware into one. The first middleware wraps the module's
+// decoder, the second middleware wraps the first one and so on, so that transformations of the decoded
+// updates run in the order the middleware are passed in.
+func ChainDecoderMiddleware(middleware ...DecoderMiddleware) DecoderMiddleware {
+	return func(moduleName string, decoder schema.KVDecoder) schema.KVDecoder {
+		for _, m := range middleware {
+			decoder = m(moduleName, decoder)
+		}
+		return decoder
+	}
+}
+
+// TransformUpdates returns a DecoderMiddleware which passes all the non-empty results of the module's decoder
+// to transform. It is a helper for the common case of middleware which only transform decoded updates.
+func TransformUpdates(transform func(moduleName string, updates []schema.ObjectUpdate) ([]schema.ObjectUpdate, error)) DecoderMiddleware {
+	return func(moduleName string, decoder schema.KVDecoder) schema.KVDecoder {
+		return func(update schema.KVPairUpdate) ([]schema.ObjectUpdate, error) {
+			updates, err := decoder(update)
+			if err != nil || len(updates) == 0 {
+				return updates, err
+			}
+			return transform(moduleName, updates)
+		}
+	}
+}
+
+// ForModules returns a DecoderMiddleware which only applies middleware to the named modules.
+func ForModules(middleware DecoderMiddleware, moduleNames ...string) DecoderMiddleware {
+	modules := make(map[string]bool, len(moduleNames))
+	for _, moduleName := range moduleNames {
+		modules[moduleName] = true
+	}
+
+	return func(moduleName string, decoder schema.KVDecoder) schema.KVDecoder {
+		if !modules[moduleName] {
+			return decoder
+		}
+		return middleware(moduleName, decoder)
+	}
+}
+
+// ResolverWithMiddleware returns a DecoderResolver which applies the middleware to the KVDecoder of every
+// module codec returned by resolver. Because both Middleware and Sync use a resolver, the transformations
+// are applied consistently to live updates and catch-up syncs.
+func ResolverWithMiddleware(resolver DecoderResolver, middleware ...DecoderMiddleware) DecoderResolver {
+	return middlewareResolver{resolver: resolver, middleware: ChainDecoderMiddleware(middleware...)}
+}
+
+type middlewareResolver struct {
+	resolver   DecoderResolver
+	middleware DecoderMiddleware
+}
+
+func (m middlewareResolver) IterateAll(f func(moduleName string, cdc schema.ModuleCodec) error) error {
+	return m.resolver.IterateAll(func(moduleName string, cdc schema.ModuleCodec) error {
+		return f(moduleName, m.wrap(moduleName, cdc))
+	})
+}
+
+func (m middlewareResolver) LookupDecoder(moduleName string) (schema.ModuleCodec, bool, error) {
+	cdc, found, err := m.resolver.LookupDecoder(moduleName)
+	if err != nil || !found {
+		return cdc, found, err
+	}
+	return m.wrap(moduleName, cdc), true, nil
+}
+
+func (m middlewareResolver) wrap(moduleName string, cdc schema.ModuleCodec) schema.ModuleCodec {
+	if cdc.KVDecoder != nil {
+		cdc.KVDecoder = m.middleware(moduleName, cdc.KVDecoder)
+	}
+	return cdc
+}
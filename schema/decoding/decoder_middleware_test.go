@@ -0,0 +1,87 @@
+package decoding
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"cosmossdk.io/schema"
+)
+
+func TestResolverWithMiddleware(t *testing.T) {
+	transformValues := func(f func(uint64) uint64) DecoderMiddleware {
+		return TransformUpdates(func(_ string, updates []schema.ObjectUpdate) ([]schema.ObjectUpdate, error) {
+			res := make([]schema.ObjectUpdate, len(updates))
+			for i, update := range updates {
+				update.Value = f(update.Value.(uint64))
+				res[i] = update
+			}
+			return res, nil
+		})
+	}
+	double := transformValues(func(x uint64) uint64 { return x * 2 })
+	increment := transformValues(func(x uint64) uint64 { return x + 1 })
+
+	tl := newTestFixture(t)
+	tl.bankMod.Mint("bob", "foo", 100)
+	tl.oneMod.SetValue("abc")
+
+	// double runs before increment and the one module is not transformed
+	resolver := ResolverWithMiddleware(tl.resolver, ForModules(double, "bank"), ForModules(increment, "bank"))
+	if err := Sync(tl.Listener, tl.multiStore, resolver, SyncOptions{}); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	expected := []schema.ObjectUpdate{
+		{TypeName: "balances", Key: []interface{}{"bob", "foo"}, Value: uint64(201)},
+		{TypeName: "supply", Key: []interface{}{"foo"}, Value: uint64(201)},
+	}
+	if !reflect.DeepEqual(tl.bankUpdates, expected) {
+		t.Fatalf("expected %v, got %v", expected, tl.bankUpdates)
+	}
+	if !reflect.DeepEqual(tl.oneValueUpdates, []schema.ObjectUpdate{{TypeName: "item", Value: "abc"}}) {
+		t.Fatalf("expected the one module not to be transformed, got %v", tl.oneValueUpdates)
+	}
+
+	// the same transformations are applied to live updates
+	tl.bankUpdates = nil
+	listener, err := Middleware(tl.Listener, resolver, MiddlewareOptions{})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	tl.setListener(listener)
+	tl.bankMod.Mint("alice", "foo", 10)
+
+	expected = []schema.ObjectUpdate{
+		{TypeName: "supply", Key: []interface{}{"foo"}, Value: uint64(221)},
+		{TypeName: "balances", Key: []interface{}{"alice", "foo"}, Value: uint64(21)},
+	}
+	if !reflect.DeepEqual(tl.bankUpdates, expected) {
+		t.Fatalf("expected %v, got %v", expected, tl.bankUpdates)
+	}
+
+	t.Run("error", func(t *testing.T) {
+		transformErr := errors.New("transform error")
+		failing := TransformUpdates(func(string, []schema.ObjectUpdate) ([]schema.ObjectUpdate, error) {
+			return nil, transformErr
+		})
+		cdc, found, err := ResolverWithMiddleware(tl.resolver, failing).LookupDecoder("bank")
+		if err != nil || !found {
+			t.Fatalf("expected bank decoder, got found=%v err=%v", found, err)
+		}
+		_, err = cdc.KVDecoder(schema.KVPairUpdate{Key: supplyKey("foo"), Value: []byte("1")})
+		if err != transformErr { //nolint:errorlint // false positive due to using go1.12
+			t.Fatalf("expected transform error, got: %v", err)
+		}
+	})
+
+	t.Run("no decoder", func(t *testing.T) {
+		cdc, found, err := ResolverWithMiddleware(testResolver, double).LookupDecoder("modA")
+		if err != nil || !found {
+			t.Fatalf("expected modA codec, got found=%v err=%v", found, err)
+		}
+		if cdc.KVDecoder != nil {
+			t.Fatalf("expected nil decoder to stay nil")
+		}
+	})
+}
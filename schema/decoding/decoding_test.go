@@ -1,12 +1,14 @@
 package decoding
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"sort"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"cosmossdk.io/schema"
 	"cosmossdk.io/schema/appdata"
@@ -92,6 +94,111 @@ func TestMiddleware_filtered(t *testing.T) {
 	}
 }
 
+func TestMiddleware_decodeLatency(t *testing.T) {
+	tl := newTestFixture(t)
+	decoded := map[string]int{}
+	listener, err := Middleware(tl.Listener, tl.resolver, MiddlewareOptions{
+		OnDecodeLatency: func(moduleName string, latency time.Duration) {
+			if latency < 0 {
+				t.Fatalf("unexpected negative latency %v", latency)
+			}
+			decoded[moduleName]++
+		},
+	})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	tl.setListener(listener)
+
+	tl.bankMod.Mint("bob", "foo", 100)
+	tl.oneMod.SetValue("abc")
+
+	// minting sets the supply and the balance
+	expected := map[string]int{"bank": 2, "one": 1}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Fatalf("expected %v, got %v", expected, decoded)
+	}
+}
+
+func TestMiddleware_parallel(t *testing.T) {
+	// record the kv-updates of some state changes so that they can be decoded as a single batch
+	tl := newTestFixture(t)
+	var kvUpdates []appdata.ModuleKVPairUpdate
+	tl.setListener(appdata.Listener{
+		OnKVPair: func(data appdata.KVPairData) error {
+			kvUpdates = append(kvUpdates, data.Updates...)
+			return nil
+		},
+	})
+	for i := 0; i < 20; i++ {
+		tl.bankMod.Mint("bob", "foo", 100)
+		tl.oneMod.SetValue(strconv.Itoa(i))
+		if err := tl.bankMod.Send("bob", "alice", "foo", 50); err != nil {
+			t.Fatal("unexpected error", err)
+		}
+	}
+	kvUpdates = append(kvUpdates, appdata.ModuleKVPairUpdate{ModuleName: "unknown", Update: schema.KVPairUpdate{Key: []byte("a")}})
+
+	decode := func(workers int) ([]appdata.ObjectUpdateData, []string) {
+		var updates []appdata.ObjectUpdateData
+		var initialized []string
+		listener, err := Middleware(appdata.Listener{
+			InitializeModuleData: func(data appdata.ModuleInitializationData) error {
+				initialized = append(initialized, data.ModuleName)
+				return nil
+			},
+			OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+				updates = append(updates, data)
+				return nil
+			},
+		}, tl.resolver, MiddlewareOptions{DecodeWorkers: workers})
+		if err != nil {
+			t.Fatal("unexpected error", err)
+		}
+
+		if err := listener.OnKVPair(appdata.KVPairData{Updates: kvUpdates}); err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		return updates, initialized
+	}
+
+	expectedUpdates, expectedInitialized := decode(0)
+	if len(expectedUpdates) != 100 {
+		t.Fatalf("expected 100 updates, got %d", len(expectedUpdates))
+	}
+
+	updates, initialized := decode(4)
+	if !reflect.DeepEqual(updates, expectedUpdates) {
+		t.Fatalf("expected parallel decoding to deliver the same updates as sequential decoding")
+	}
+	if !reflect.DeepEqual(initialized, expectedInitialized) {
+		t.Fatalf("expected modules %v to be initialized, got %v", expectedInitialized, initialized)
+	}
+
+	t.Run("decode error", func(t *testing.T) {
+		var updates []appdata.ObjectUpdateData
+		listener, err := Middleware(appdata.Listener{
+			OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+				updates = append(updates, data)
+				return nil
+			},
+		}, tl.resolver, MiddlewareOptions{DecodeWorkers: 4})
+		if err != nil {
+			t.Fatal("unexpected error", err)
+		}
+
+		invalid := appdata.ModuleKVPairUpdate{ModuleName: "bank", Update: schema.KVPairUpdate{Key: supplyKey("foo"), Value: []byte("abc")}}
+		err = listener.OnKVPair(appdata.KVPairData{Updates: []appdata.ModuleKVPairUpdate{kvUpdates[0], kvUpdates[1], invalid, kvUpdates[2]}})
+		if err == nil {
+			t.Fatal("expected decode error")
+		}
+
+		if !reflect.DeepEqual(updates, expectedUpdates[:2]) {
+			t.Fatalf("expected the updates before the invalid kv-update to be delivered, got %v", updates)
+		}
+	})
+}
+
 func TestSync(t *testing.T) {
 	tl := newTestFixture(t)
 	tl.bankMod.Mint("bob", "foo", 100)
@@ -165,6 +272,112 @@ func TestSync_filtered(t *testing.T) {
 	}
 }
 
+func TestSync_resume(t *testing.T) {
+	tl := newTestFixture(t)
+	tl.bankMod.Mint("bob", "foo", 100)
+	err := tl.bankMod.Send("bob", "alice", "foo", 50)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	tl.oneMod.SetValue("def")
+
+	// interrupt the sync after two key-value pairs
+	interrupted := errors.New("interrupted")
+	var checkpoint SyncCheckpoint
+	err = Sync(tl.Listener, tl.multiStore, tl.resolver, SyncOptions{
+		ProgressInterval: 1,
+		OnProgress: func(progress SyncProgress) error {
+			checkpoint = progress.Checkpoint
+			if progress.KVPairs == 2 {
+				return interrupted
+			}
+			return nil
+		},
+	})
+	if err != interrupted { //nolint:errorlint // false positive due to using go1.12
+		t.Fatalf("expected interrupted error, got: %v", err)
+	}
+
+	if checkpoint.ModuleName != "bank" || len(checkpoint.CompletedModules) != 0 {
+		t.Fatalf("unexpected checkpoint %v", checkpoint)
+	}
+
+	var progresses []SyncProgress
+	err = Sync(tl.Listener, tl.multiStore, tl.resolver, SyncOptions{
+		ResumeFrom: &checkpoint,
+		OnProgress: func(progress SyncProgress) error {
+			progresses = append(progresses, progress)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	// the updates of both syncs together are the same as the updates of a single sync
+	expected := []schema.ObjectUpdate{
+		{TypeName: "balances", Key: []interface{}{"alice", "foo"}, Value: uint64(50)},
+		{TypeName: "balances", Key: []interface{}{"bob", "foo"}, Value: uint64(50)},
+		{TypeName: "supply", Key: []interface{}{"foo"}, Value: uint64(100)},
+	}
+	if !reflect.DeepEqual(tl.bankUpdates, expected) {
+		t.Fatalf("expected %v, got %v", expected, tl.bankUpdates)
+	}
+
+	expectedProgresses := []SyncProgress{
+		{Checkpoint: SyncCheckpoint{CompletedModules: []string{"bank"}}, KVPairs: 1, ObjectUpdates: 1},
+		{Checkpoint: SyncCheckpoint{CompletedModules: []string{"bank", "one"}}, KVPairs: 2, ObjectUpdates: 2},
+	}
+	if !reflect.DeepEqual(progresses, expectedProgresses) {
+		t.Fatalf("expected progress %v, got %v", expectedProgresses, progresses)
+	}
+
+	// a completed sync does not send any update when resumed
+	tl.bankUpdates = nil
+	tl.oneValueUpdates = nil
+	err = Sync(tl.Listener, tl.multiStore, tl.resolver, SyncOptions{ResumeFrom: &progresses[1].Checkpoint})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if len(tl.bankUpdates) != 0 || len(tl.oneValueUpdates) != 0 {
+		t.Fatalf("expected no updates, got %v %v", tl.bankUpdates, tl.oneValueUpdates)
+	}
+}
+
+func TestSyncCatchUpSource(t *testing.T) {
+	tl := newTestFixture(t)
+	tl.bankMod.Mint("bob", "foo", 100)
+
+	var heights []uint64
+	commits := 0
+	tl.Listener.StartBlock = func(data appdata.StartBlockData) error {
+		heights = append(heights, data.Height)
+		return nil
+	}
+	tl.Listener.Commit = func(appdata.CommitData) error {
+		commits++
+		return nil
+	}
+
+	source := SyncCatchUpSource(tl.multiStore, tl.resolver, SyncOptions{})
+	if err := source.CatchUp(0, 10, tl.Listener); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	if !reflect.DeepEqual(heights, []uint64{10}) || commits != 1 {
+		t.Fatalf("expected state to be delivered in block 10 and committed, got blocks %v and %d commits", heights, commits)
+	}
+
+	if len(tl.bankUpdates) != 2 {
+		t.Fatalf("expected 2 bank updates, got %v", tl.bankUpdates)
+	}
+
+	err := source.CatchUp(5, 10, tl.Listener)
+	if err == nil || !strings.Contains(err.Error(), "cannot catch up from height 5") {
+		t.Fatalf("expected error when catching up from a committed height, got: %v", err)
+	}
+}
+
 type testFixture struct {
 	appdata.Listener
 	bankUpdates     []schema.ObjectUpdate
@@ -1,16 +1,74 @@
 package decoding
 
 import (
+	"sync"
+	"time"
+
 	"cosmossdk.io/schema"
 	"cosmossdk.io/schema/appdata"
 )
 
 type MiddlewareOptions struct {
 	ModuleFilter func(moduleName string) bool
+
+	// DecodeWorkers is the number of goroutines which decode the kv-updates of different modules concurrently.
+	// The kv-updates of each module are always decoded in order by a single worker and the decoded object
+	// updates are delivered to the target in the same order as the kv-updates they were decoded from, so
+	// listeners observe the same stream of updates as with sequential decoding. If it is zero or one, kv-updates
+	// are decoded sequentially. Module KVDecoders must be safe to call concurrently for different modules.
+	DecodeWorkers int
+
+	// Tolerant enables tolerant decoding mode in which key-value pairs which a module's KVDecoder fails to decode
+	// are emitted as RawKVUpdateType updates instead of returning an error, so that indexers do not halt on
+	// unknown store prefixes, for instance after an upgrade. RawKVUpdateType is added to the schema of each module.
+	Tolerant bool
+
+	// Stats counts the key-value pairs which could not be decoded in tolerant mode. It is optional.
+	Stats *DecodingStats
+
+	// OnDecodeLatency, if set, is called with the name of the module and the time its KVDecoder took every
+	// time a key-value pair is decoded, so that decoding latency can be reported as a metric. It must be safe
+	// to call concurrently if DecodeWorkers is greater than one.
+	OnDecodeLatency func(moduleName string, latency time.Duration)
+
+	// Invalidator, if set, is used to signal that the schemas of modules changed at runtime, see
+	// SchemaInvalidator.
+	Invalidator *SchemaInvalidator
+}
+
+// SchemaInvalidator signals to Middleware that the schemas of modules changed at runtime, for instance in an
+// upgrade handler, so that their codecs are looked up again with the resolver and that the modules are
+// initialized again with SchemaChanged set. This lets long-lived listeners, such as remote indexers, learn
+// about new object types without a restart. The zero value is ready to use.
+type SchemaInvalidator struct {
+	mu      sync.Mutex
+	modules []string
+}
+
+// Invalidate marks the schemas of the modules as changed. The modules which have already been initialized are
+// initialized again right after the next block is started, even if their schema didn't actually change. It
+// is safe to call concurrently with the delivery of packets.
+func (i *SchemaInvalidator) Invalidate(moduleNames ...string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.modules = append(i.modules, moduleNames...)
+}
+
+// take returns and clears the invalidated modules.
+func (i *SchemaInvalidator) take() []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	res := i.modules
+	i.modules = nil
+	return res
 }
 
 // Middleware decodes raw data passed to the listener as kv-updates into decoded object updates. Module initialization
-// is done lazily as modules are encountered in the kv-update stream.
+// is done lazily as modules are encountered in the kv-update stream. If resolver is a HeightDecoderResolver, such as
+// a VersionRegistry, kv-updates are decoded with the codecs which were active at the height of the current block and
+// modules are initialized again with the schema of the new version when the version of their codec changes. Modules
+// can also be initialized again with opts.Invalidator. Modules which are initialized again are marked with
+// ModuleInitializationData.SchemaChanged.
 func Middleware(target appdata.Listener, resolver DecoderResolver, opts MiddlewareOptions) (appdata.Listener, error) {
 	initializeModuleData := target.InitializeModuleData
 	onObjectUpdate := target.OnObjectUpdate
@@ -24,50 +82,152 @@ func Middleware(target appdata.Listener, resolver DecoderResolver, opts Middlewa
 
 	moduleCodecs := map[string]*schema.ModuleCodec{}
 
-	target.OnKVPair = func(data appdata.KVPairData) error {
-		// first forward kv pair updates
-		if onKVPair != nil {
-			err := onKVPair(data)
+	// initialized records the modules which have been initialized, so that the modules which are initialized
+	// again are marked with SchemaChanged
+	initialized := map[string]bool{}
+
+	// with a HeightDecoderResolver, codecs are looked up at the height of the current block and the schema
+	// versions of the codecs in moduleCodecs are tracked so that modules can be re-initialized on upgrades
+	heightResolver, _ := resolver.(HeightDecoderResolver)
+	moduleVersions := map[string]uint64{}
+	var height uint64
+	haveHeight := false
+	lookupDecoder := func(moduleName string) (schema.ModuleCodec, bool, error) {
+		if heightResolver != nil && haveHeight {
+			return heightResolver.LookupDecoderAt(moduleName, height)
+		}
+		return resolver.LookupDecoder(moduleName)
+	}
+
+	// lookupCodec returns the codec of a module, initializing the module the first time it is encountered, or nil
+	// if the module can't be decoded
+	lookupCodec := func(moduleName string) (*schema.ModuleCodec, error) {
+		// look for an existing codec
+		pcdc, ok := moduleCodecs[moduleName]
+		if ok {
+			return pcdc, nil
+		}
+
+		if opts.ModuleFilter != nil && !opts.ModuleFilter(moduleName) {
+			// we don't care about this module so store nil and continue
+			moduleCodecs[moduleName] = nil
+			return nil, nil
+		}
+
+		// look for a new codec
+		cdc, found, err := lookupDecoder(moduleName)
+		if err != nil {
+			return nil, err
+		}
+
+		if !found {
+			// store nil to indicate we've seen this module and don't have a codec
+			// and keep processing the kv updates
+			moduleCodecs[moduleName] = nil
+			return nil, nil
+		}
+
+		moduleVersions[moduleName] = cdc.Schema.Version()
+
+		if opts.Tolerant {
+			cdc, err = tolerantCodec(moduleName, cdc, opts.Stats)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 
-		for _, kvUpdate := range data.Updates {
-			// look for an existing codec
-			pcdc, ok := moduleCodecs[kvUpdate.ModuleName]
-			if !ok {
-				if opts.ModuleFilter != nil && !opts.ModuleFilter(kvUpdate.ModuleName) {
-					// we don't care about this module so store nil and continue
-					moduleCodecs[kvUpdate.ModuleName] = nil
-					continue
+		if opts.OnDecodeLatency != nil && cdc.KVDecoder != nil {
+			cdc.KVDecoder = timedDecoder(moduleName, cdc.KVDecoder, opts.OnDecodeLatency)
+		}
+
+		pcdc = &cdc
+		moduleCodecs[moduleName] = pcdc
+
+		if initializeModuleData != nil {
+			err = initializeModuleData(appdata.ModuleInitializationData{
+				ModuleName:    moduleName,
+				Schema:        cdc.Schema,
+				SchemaChanged: initialized[moduleName],
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		initialized[moduleName] = true
+
+		return pcdc, nil
+	}
+
+	if heightResolver != nil || opts.Invalidator != nil {
+		startBlock := target.StartBlock
+		target.StartBlock = func(data appdata.StartBlockData) error {
+			if heightResolver != nil {
+				height, haveHeight = data.Height, true
+
+				// forget the codecs of the modules which were added, removed or upgraded at this height so that
+				// they are looked up and initialized again when they are next encountered
+				for moduleName, pcdc := range moduleCodecs {
+					if opts.ModuleFilter != nil && !opts.ModuleFilter(moduleName) {
+						continue
+					}
+
+					cdc, found, err := heightResolver.LookupDecoderAt(moduleName, height)
+					if err != nil {
+						return err
+					}
+
+					if found != (pcdc != nil) || (found && cdc.Schema.Version() != moduleVersions[moduleName]) {
+						delete(moduleCodecs, moduleName)
+					}
 				}
+			}
 
-				// look for a new codec
-				cdc, found, err := resolver.LookupDecoder(kvUpdate.ModuleName)
-				if err != nil {
+			if startBlock != nil {
+				if err := startBlock(data); err != nil {
 					return err
 				}
+			}
 
-				if !found {
-					// store nil to indicate we've seen this module and don't have a codec
-					// and keep processing the kv updates
-					moduleCodecs[kvUpdate.ModuleName] = nil
+			if opts.Invalidator == nil {
+				return nil
+			}
+
+			// forget the codecs of the invalidated modules and initialize the modules which have already been
+			// initialized again right away, so that listeners learn about the new schema before its updates
+			for _, moduleName := range opts.Invalidator.take() {
+				pcdc, ok := moduleCodecs[moduleName]
+				if !ok {
+					continue
+				}
+				delete(moduleCodecs, moduleName)
+				if pcdc == nil {
 					continue
 				}
+				if _, err := lookupCodec(moduleName); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	target.OnKVPair = func(data appdata.KVPairData) error {
+		// first forward kv pair updates
+		if onKVPair != nil {
+			err := onKVPair(data)
+			if err != nil {
+				return err
+			}
+		}
 
-				pcdc = &cdc
-				moduleCodecs[kvUpdate.ModuleName] = pcdc
+		if opts.DecodeWorkers > 1 {
+			return decodeParallel(data.Updates, lookupCodec, onObjectUpdate, opts.DecodeWorkers)
+		}
 
-				if initializeModuleData != nil {
-					err = initializeModuleData(appdata.ModuleInitializationData{
-						ModuleName: kvUpdate.ModuleName,
-						Schema:     cdc.Schema,
-					})
-					if err != nil {
-						return err
-					}
-				}
+		for _, kvUpdate := range data.Updates {
+			pcdc, err := lookupCodec(kvUpdate.ModuleName)
+			if err != nil {
+				return err
 			}
 
 			if pcdc == nil {
@@ -90,9 +250,10 @@ func Middleware(target appdata.Listener, resolver DecoderResolver, opts Middlewa
 				continue
 			}
 
-			err = target.OnObjectUpdate(appdata.ObjectUpdateData{
+			err = onObjectUpdate(appdata.ObjectUpdateData{
 				ModuleName: kvUpdate.ModuleName,
 				Updates:    updates,
+				SourceKey:  kvUpdate.Update.Key,
 			})
 			if err != nil {
 				return err
@@ -104,3 +265,106 @@ func Middleware(target appdata.Listener, resolver DecoderResolver, opts Middlewa
 
 	return target, nil
 }
+
+// timedDecoder wraps the KVDecoder of a module so that the time it takes to decode each key-value pair is
+// reported to onLatency.
+func timedDecoder(moduleName string, decoder schema.KVDecoder, onLatency func(string, time.Duration)) schema.KVDecoder {
+	return func(update schema.KVPairUpdate) ([]schema.ObjectUpdate, error) {
+		start := time.Now()
+		res, err := decoder(update)
+		onLatency(moduleName, time.Since(start))
+		return res, err
+	}
+}
+
+// decodeParallel decodes the kv-updates of each module on a pool of workers and then delivers the decoded
+// updates to onObjectUpdate in the order of kvUpdates. All modules are looked up, and initialized if necessary,
+// before any kv-update is decoded. If decoding fails, the error of the first kv-update in order which failed to
+// decode is returned after the updates decoded before it have been delivered.
+func decodeParallel(
+	kvUpdates []appdata.ModuleKVPairUpdate,
+	lookupCodec func(moduleName string) (*schema.ModuleCodec, error),
+	onObjectUpdate func(appdata.ObjectUpdateData) error,
+	workers int,
+) error {
+	type moduleBatch struct {
+		decoder schema.KVDecoder
+		indexes []int
+	}
+
+	// group the kv-updates by module preserving their order within each module
+	var batches []*moduleBatch
+	batchByModule := map[string]*moduleBatch{}
+	for i, kvUpdate := range kvUpdates {
+		pcdc, err := lookupCodec(kvUpdate.ModuleName)
+		if err != nil {
+			return err
+		}
+
+		if pcdc == nil || onObjectUpdate == nil || pcdc.KVDecoder == nil {
+			continue
+		}
+
+		batch, ok := batchByModule[kvUpdate.ModuleName]
+		if !ok {
+			batch = &moduleBatch{decoder: pcdc.KVDecoder}
+			batchByModule[kvUpdate.ModuleName] = batch
+			batches = append(batches, batch)
+		}
+		batch.indexes = append(batch.indexes, i)
+	}
+
+	if len(batches) == 0 {
+		return nil
+	}
+
+	decoded := make([][]schema.ObjectUpdate, len(kvUpdates))
+	errs := make([]error, len(kvUpdates))
+
+	batchCh := make(chan *moduleBatch)
+	var wg sync.WaitGroup
+	if workers > len(batches) {
+		workers = len(batches)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batchCh {
+				for _, i := range batch.indexes {
+					decoded[i], errs[i] = batch.decoder(kvUpdates[i].Update)
+					if errs[i] != nil {
+						// later updates of the module can't be delivered anyway
+						break
+					}
+				}
+			}
+		}()
+	}
+	for _, batch := range batches {
+		batchCh <- batch
+	}
+	close(batchCh)
+	wg.Wait()
+
+	for i, kvUpdate := range kvUpdates {
+		if errs[i] != nil {
+			return errs[i]
+		}
+
+		if len(decoded[i]) == 0 {
+			continue
+		}
+
+		err := onObjectUpdate(appdata.ObjectUpdateData{
+			ModuleName: kvUpdate.ModuleName,
+			Updates:    decoded[i],
+			SourceKey:  kvUpdate.Update.Key,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package decoding
 
 import (
+	"bytes"
+	"fmt"
+
 	"cosmossdk.io/schema"
 	"cosmossdk.io/schema/appdata"
 )
@@ -12,12 +15,70 @@ type SyncSource interface {
 	IterateAllKVPairs(moduleName string, fn func(key, value []byte) error) error
 }
 
+// SyncSourceFrom is an optional extension of SyncSource for sources which can start iterating a module's
+// key-value pairs at a given key, which allows resumed syncs to skip the keys which were already synced.
+type SyncSourceFrom interface {
+	SyncSource
+
+	// IterateKVPairsFrom iterates over all key-value pairs for a given module whose keys are greater than
+	// or equal to startKey in ascending key order.
+	IterateKVPairsFrom(moduleName string, startKey []byte, fn func(key, value []byte) error) error
+}
+
 // SyncOptions are the options for Sync.
 type SyncOptions struct {
 	ModuleFilter func(moduleName string) bool
+
+	// OnProgress is called with the progress of the sync every ProgressInterval key-value pairs and whenever
+	// the state of a module has been completely synced. The checkpoint of the progress can be persisted to
+	// resume the sync later with ResumeFrom, but only once the listener has persisted all the updates sent
+	// before OnProgress was called. If OnProgress returns an error, the sync is aborted with that error.
+	OnProgress func(SyncProgress) error
+
+	// ProgressInterval is the number of key-value pairs after which OnProgress is called. If it is zero,
+	// OnProgress is only called when the state of a module has been completely synced.
+	ProgressInterval uint64
+
+	// ResumeFrom is a checkpoint reported by OnProgress during a previous sync which was interrupted. If it
+	// is set, the modules which were completely synced and the keys which were already synced are skipped.
+	// InitializeModuleData is still called for all modules. Resuming requires that the source iterates keys in
+	// ascending order and that the state has not changed since the checkpoint was reported.
+	ResumeFrom *SyncCheckpoint
+
+	// Tolerant enables tolerant decoding mode, see MiddlewareOptions.Tolerant.
+	Tolerant bool
+
+	// Stats counts the key-value pairs which could not be decoded in tolerant mode. It is optional.
+	Stats *DecodingStats
+}
+
+// SyncCheckpoint describes which part of the state has been synced.
+type SyncCheckpoint struct {
+	// CompletedModules are the names of the modules whose state has been completely synced.
+	CompletedModules []string
+
+	// ModuleName is the name of the module whose state is being synced. It is empty if no module
+	// is being synced.
+	ModuleName string
+
+	// LastKey is the last key of ModuleName which has been synced.
+	LastKey []byte
+}
+
+// SyncProgress reports the progress of a sync.
+type SyncProgress struct {
+	// Checkpoint describes the state which has been synced so far.
+	Checkpoint SyncCheckpoint
+
+	// KVPairs is the number of key-value pairs which have been synced since Sync was called.
+	KVPairs uint64
+
+	// ObjectUpdates is the number of object updates which have been sent to the listener since Sync was called.
+	ObjectUpdates uint64
 }
 
 // Sync synchronizes existing state from the sync source to the listener using the resolver to decode data.
+// Sync can report its progress and be resumed after an interruption, see SyncOptions.
 func Sync(listener appdata.Listener, source SyncSource, resolver DecoderResolver, opts SyncOptions) error {
 	initializeModuleData := listener.InitializeModuleData
 	onObjectUpdate := listener.OnObjectUpdate
@@ -27,12 +88,44 @@ func Sync(listener appdata.Listener, source SyncSource, resolver DecoderResolver
 		return nil
 	}
 
+	var progress SyncProgress
+	completed := map[string]bool{}
+	var resumeModule string
+	var resumeKey []byte
+	if opts.ResumeFrom != nil {
+		for _, moduleName := range opts.ResumeFrom.CompletedModules {
+			completed[moduleName] = true
+		}
+		progress.Checkpoint.CompletedModules = append(progress.Checkpoint.CompletedModules, opts.ResumeFrom.CompletedModules...)
+		resumeModule = opts.ResumeFrom.ModuleName
+		resumeKey = opts.ResumeFrom.LastKey
+	}
+
+	reportProgress := func() error {
+		if opts.OnProgress == nil {
+			return nil
+		}
+
+		res := progress
+		res.Checkpoint.CompletedModules = append([]string(nil), progress.Checkpoint.CompletedModules...)
+		res.Checkpoint.LastKey = append([]byte(nil), progress.Checkpoint.LastKey...)
+		return opts.OnProgress(res)
+	}
+
 	return resolver.IterateAll(func(moduleName string, cdc schema.ModuleCodec) error {
 		if opts.ModuleFilter != nil && !opts.ModuleFilter(moduleName) {
 			// ignore this module
 			return nil
 		}
 
+		if opts.Tolerant {
+			var err error
+			cdc, err = tolerantCodec(moduleName, cdc, opts.Stats)
+			if err != nil {
+				return err
+			}
+		}
+
 		if initializeModuleData != nil {
 			err := initializeModuleData(appdata.ModuleInitializationData{
 				ModuleName: moduleName,
@@ -43,21 +136,98 @@ func Sync(listener appdata.Listener, source SyncSource, resolver DecoderResolver
 			}
 		}
 
-		if onObjectUpdate == nil || cdc.KVDecoder == nil {
+		if onObjectUpdate == nil || cdc.KVDecoder == nil || completed[moduleName] {
 			return nil
 		}
 
-		return source.IterateAllKVPairs(moduleName, func(key, value []byte) error {
+		var lastKey []byte
+		if moduleName == resumeModule {
+			lastKey = resumeKey
+		}
+
+		progress.Checkpoint.ModuleName = moduleName
+		progress.Checkpoint.LastKey = lastKey
+		err := iterateKVPairsAfter(source, moduleName, lastKey, func(key, value []byte) error {
 			updates, err := cdc.KVDecoder(schema.KVPairUpdate{Key: key, Value: value})
 			if err != nil {
 				return err
 			}
 
-			if len(updates) == 0 {
-				return nil
+			if len(updates) != 0 {
+				err = onObjectUpdate(appdata.ObjectUpdateData{ModuleName: moduleName, Updates: updates, SourceKey: key})
+				if err != nil {
+					return err
+				}
+			}
+
+			progress.Checkpoint.LastKey = key
+			progress.KVPairs++
+			progress.ObjectUpdates += uint64(len(updates))
+			if opts.ProgressInterval != 0 && progress.KVPairs%opts.ProgressInterval == 0 {
+				return reportProgress()
 			}
 
-			return onObjectUpdate(appdata.ObjectUpdateData{ModuleName: moduleName, Updates: updates})
+			return nil
 		})
+		if err != nil {
+			return err
+		}
+
+		progress.Checkpoint.CompletedModules = append(progress.Checkpoint.CompletedModules, moduleName)
+		progress.Checkpoint.ModuleName = ""
+		progress.Checkpoint.LastKey = nil
+		return reportProgress()
 	})
 }
+
+// iterateKVPairsAfter iterates over the key-value pairs of a module whose keys are greater than lastKey,
+// or all of them if lastKey is nil.
+func iterateKVPairsAfter(source SyncSource, moduleName string, lastKey []byte, fn func(key, value []byte) error) error {
+	if lastKey == nil {
+		return source.IterateAllKVPairs(moduleName, fn)
+	}
+
+	skip := func(key, value []byte) error {
+		if bytes.Compare(key, lastKey) <= 0 {
+			return nil
+		}
+		return fn(key, value)
+	}
+
+	if sourceFrom, ok := source.(SyncSourceFrom); ok {
+		return sourceFrom.IterateKVPairsFrom(moduleName, lastKey, skip)
+	}
+
+	return source.IterateAllKVPairs(moduleName, skip)
+}
+
+// SyncCatchUpSource returns an appdata.CatchUpSource which catches up listeners that have not committed any
+// block yet by reloading the existing state from source with Sync. The state is delivered within a single
+// block at the catch-up height, which is then committed, so source must reflect the state at that height.
+// Historical blocks cannot be delivered, so an error is returned for listeners which have already committed
+// a block.
+func SyncCatchUpSource(source SyncSource, resolver DecoderResolver, opts SyncOptions) appdata.CatchUpSource {
+	return syncCatchUpSource{source: source, resolver: resolver, opts: opts}
+}
+
+type syncCatchUpSource struct {
+	source   SyncSource
+	resolver DecoderResolver
+	opts     SyncOptions
+}
+
+func (s syncCatchUpSource) CatchUp(fromHeight, toHeight int64, listener appdata.Listener) error {
+	if fromHeight != 0 {
+		return fmt.Errorf("cannot catch up from height %d by reloading state, only listeners without committed data can be caught up", fromHeight)
+	}
+
+	if err := listener.SendPacket(appdata.StartBlockData{Height: uint64(toHeight)}); err != nil {
+		return err
+	}
+
+	if err := Sync(listener, s.source, s.resolver, s.opts); err != nil {
+		return err
+	}
+
+	return listener.SendPacket(appdata.CommitData{})
+}
@@ -0,0 +1,155 @@
+package decoding
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"cosmossdk.io/schema"
+)
+
+// RawKVUpdateTypeName is the name of the object type which is added to the schema of every module in tolerant
+// decoding mode. Key-value pairs which a module's KVDecoder cannot decode are emitted as updates of this object
+// type with the key of the pair as the object key and the value of the pair and the reason it could not be
+// decoded as the object value.
+const RawKVUpdateTypeName = "raw_kv_update"
+
+// RawKVUpdateType is the object type which is added to the schema of every module in tolerant decoding mode.
+var RawKVUpdateType = schema.ObjectType{
+	Name:      RawKVUpdateTypeName,
+	KeyFields: []schema.Field{{Name: "key", Kind: schema.BytesKind}},
+	ValueFields: []schema.Field{
+		{Name: "value", Kind: schema.BytesKind},
+		{Name: "reason", Kind: schema.StringKind},
+	},
+	Description: "key-value pairs which could not be decoded",
+}
+
+// DecodingStats counts the key-value pairs which could not be decoded in tolerant decoding mode.
+// It is safe for concurrent use.
+type DecodingStats struct {
+	mu          sync.Mutex
+	undecodable map[string]uint64
+}
+
+// UndecodableKVPairs returns the number of key-value pairs of the module which could not be decoded.
+func (s *DecodingStats) UndecodableKVPairs(moduleName string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.undecodable[moduleName]
+}
+
+// TotalUndecodableKVPairs returns the number of key-value pairs of all modules which could not be decoded.
+func (s *DecodingStats) TotalUndecodableKVPairs() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var total uint64
+	for _, n := range s.undecodable {
+		total += n
+	}
+	return total
+}
+
+// ModulesWithUndecodableKVPairs returns the sorted names of the modules which had key-value pairs which could
+// not be decoded.
+func (s *DecodingStats) ModulesWithUndecodableKVPairs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]string, 0, len(s.undecodable))
+	for moduleName := range s.undecodable {
+		res = append(res, moduleName)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func (s *DecodingStats) addUndecodable(moduleName string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.undecodable == nil {
+		s.undecodable = map[string]uint64{}
+	}
+	s.undecodable[moduleName]++
+}
+
+// tolerantCodec returns a copy of the codec whose schema includes RawKVUpdateType and whose KVDecoder emits a
+// RawKVUpdateType update instead of returning an error or panicking when a key-value pair can't be decoded.
+func tolerantCodec(moduleName string, cdc schema.ModuleCodec, stats *DecodingStats) (schema.ModuleCodec, error) {
+	moduleSchema, err := withRawKVUpdateType(cdc.Schema)
+	if err != nil {
+		return schema.ModuleCodec{}, fmt.Errorf("can't use tolerant decoding for module %q: %v", moduleName, err) //nolint:errorlint // false positive due to using go1.12
+	}
+	cdc.Schema = moduleSchema
+
+	decoder := cdc.KVDecoder
+	if decoder == nil {
+		return cdc, nil
+	}
+
+	cdc.KVDecoder = func(update schema.KVPairUpdate) (updates []schema.ObjectUpdate, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+
+			if err != nil {
+				stats.addUndecodable(moduleName)
+				updates = []schema.ObjectUpdate{rawKVUpdate(update, err)}
+				err = nil
+			}
+		}()
+
+		return decoder(update)
+	}
+	return cdc, nil
+}
+
+func rawKVUpdate(update schema.KVPairUpdate, err error) schema.ObjectUpdate {
+	if update.Delete {
+		return schema.ObjectUpdate{TypeName: RawKVUpdateTypeName, Key: update.Key, Delete: true}
+	}
+
+	return schema.ObjectUpdate{
+		TypeName: RawKVUpdateTypeName,
+		Key:      update.Key,
+		Value:    []interface{}{update.Value, err.Error()},
+	}
+}
+
+// withRawKVUpdateType returns a copy of the module schema which includes RawKVUpdateType.
+func withRawKVUpdateType(moduleSchema schema.ModuleSchema) (schema.ModuleSchema, error) {
+	objectTypes := []schema.ObjectType{RawKVUpdateType}
+	moduleSchema.ObjectTypes(func(objectType schema.ObjectType) bool {
+		objectTypes = append(objectTypes, objectType)
+		return true
+	})
+
+	res, err := schema.NewModuleSchema(objectTypes)
+	if err != nil {
+		return schema.ModuleSchema{}, err
+	}
+
+	var eventTypes []schema.EventType
+	moduleSchema.EventTypes(func(eventType schema.EventType) bool {
+		eventTypes = append(eventTypes, eventType)
+		return true
+	})
+	if len(eventTypes) != 0 {
+		res, err = res.WithEventTypes(eventTypes...)
+		if err != nil {
+			return schema.ModuleSchema{}, err
+		}
+	}
+
+	if reserved := moduleSchema.ReservedTypeNames(); len(reserved) != 0 {
+		if res, err = res.WithReservedTypeNames(reserved...); err != nil {
+			return schema.ModuleSchema{}, err
+		}
+	}
+
+	return res.WithVersion(moduleSchema.Version()), nil
+}
@@ -0,0 +1,89 @@
+package decoding
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+)
+
+func TestMiddleware_tolerant(t *testing.T) {
+	tl := newTestFixture(t)
+	panicking := func(moduleName string, decoder schema.KVDecoder) schema.KVDecoder {
+		return func(update schema.KVPairUpdate) ([]schema.ObjectUpdate, error) {
+			if string(update.Key) == "panic" {
+				panic("unexpected key")
+			}
+			return decoder(update)
+		}
+	}
+	resolver := ResolverWithMiddleware(tl.resolver, ForModules(panicking, "bank"))
+
+	var schemas []schema.ModuleSchema
+	var updates []schema.ObjectUpdate
+	stats := &DecodingStats{}
+	listener, err := Middleware(appdata.Listener{
+		InitializeModuleData: func(data appdata.ModuleInitializationData) error {
+			schemas = append(schemas, data.Schema)
+			return nil
+		},
+		OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+			updates = append(updates, data.Updates...)
+			return nil
+		},
+	}, resolver, MiddlewareOptions{Tolerant: true, Stats: stats})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	err = listener.OnKVPair(appdata.KVPairData{Updates: []appdata.ModuleKVPairUpdate{
+		{ModuleName: "bank", Update: schema.KVPairUpdate{Key: supplyKey("foo"), Value: []byte("abc")}},
+		{ModuleName: "bank", Update: schema.KVPairUpdate{Key: supplyKey("foo"), Value: []byte("10")}},
+		{ModuleName: "bank", Update: schema.KVPairUpdate{Key: []byte("panic"), Value: []byte("x")}},
+		{ModuleName: "bank", Update: schema.KVPairUpdate{Key: []byte("panic"), Delete: true}},
+	}})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	if len(schemas) != 1 {
+		t.Fatalf("expected bank to be initialized once, got %d", len(schemas))
+	}
+	typ, found := schemas[0].LookupType(RawKVUpdateTypeName)
+	if !found || !reflect.DeepEqual(typ, RawKVUpdateType) {
+		t.Fatalf("expected the bank schema to include the raw kv update type")
+	}
+	if _, found := schemas[0].LookupType("balances"); !found {
+		t.Fatalf("expected the bank schema to include its own types")
+	}
+
+	if len(updates) != 4 {
+		t.Fatalf("expected 4 updates, got %v", updates)
+	}
+	raw := updates[0]
+	if raw.TypeName != RawKVUpdateTypeName || !reflect.DeepEqual(raw.Key, supplyKey("foo")) {
+		t.Fatalf("unexpected raw kv update %v", raw)
+	}
+	values := raw.Value.([]interface{})
+	if !reflect.DeepEqual(values[0], []byte("abc")) || !strings.Contains(values[1].(string), "invalid syntax") {
+		t.Fatalf("unexpected raw kv update value %v", values)
+	}
+	if !reflect.DeepEqual(updates[1], schema.ObjectUpdate{TypeName: "supply", Key: []interface{}{"foo"}, Value: uint64(10)}) {
+		t.Fatalf("expected valid updates to be decoded, got %v", updates[1])
+	}
+	if !strings.Contains(updates[2].Value.([]interface{})[1].(string), "panic: unexpected key") {
+		t.Fatalf("expected panics to be reported, got %v", updates[2])
+	}
+	if !updates[3].Delete || updates[3].TypeName != RawKVUpdateTypeName {
+		t.Fatalf("expected a raw kv delete, got %v", updates[3])
+	}
+
+	if stats.UndecodableKVPairs("bank") != 3 || stats.TotalUndecodableKVPairs() != 3 {
+		t.Fatalf("expected 3 undecodable kv pairs, got %d", stats.TotalUndecodableKVPairs())
+	}
+	if !reflect.DeepEqual(stats.ModulesWithUndecodableKVPairs(), []string{"bank"}) {
+		t.Fatalf("unexpected modules %v", stats.ModulesWithUndecodableKVPairs())
+	}
+}
@@ -0,0 +1,120 @@
+package decoding
+
+import (
+	"fmt"
+	"sort"
+
+	"cosmossdk.io/schema"
+)
+
+// HeightDecoderResolver is a DecoderResolver which can also look up the decoder of a module which was active
+// at a given block height, so that the blocks of a chain which upgraded its modules can be decoded with the
+// codecs which match the state they wrote. Middleware uses LookupDecoderAt with the height of the current
+// block when its resolver implements this interface.
+type HeightDecoderResolver interface {
+	DecoderResolver
+
+	// LookupDecoderAt looks up the decoder of a module which was active at the given block height. It
+	// returns false if the module did not exist at that height.
+	LookupDecoderAt(moduleName string, height uint64) (decoder schema.ModuleCodec, found bool, err error)
+}
+
+// VersionRegistry holds multiple versions of the codecs of modules, each of which is active from the height
+// at which it was registered until the height of the next version, for instance the height of a chain
+// upgrade. It implements HeightDecoderResolver, where IterateAll and LookupDecoder return the latest version
+// of each module. It is not safe for concurrent use while versions are registered.
+type VersionRegistry struct {
+	modules map[string][]moduleVersion
+}
+
+// moduleVersion is a version of the codec of a module which is active from startHeight.
+type moduleVersion struct {
+	startHeight uint64
+	codec       schema.ModuleCodec
+}
+
+// NewVersionRegistry returns an empty VersionRegistry.
+func NewVersionRegistry() *VersionRegistry {
+	return &VersionRegistry{modules: map[string][]moduleVersion{}}
+}
+
+// Register registers a version of the codec of a module which is active from startHeight. Versions can be
+// registered in any order but each version of a module must have a different start height and the versions
+// of the schemas, see schema.ModuleSchema.Version, must increase with their start heights, so that the
+// version of the schema identifies the codec.
+func (r *VersionRegistry) Register(moduleName string, startHeight uint64, cdc schema.ModuleCodec) error {
+	if !schema.ValidateName(moduleName) {
+		return fmt.Errorf("invalid module name %q", moduleName)
+	}
+
+	versions := r.modules[moduleName]
+	i := sort.Search(len(versions), func(i int) bool { return versions[i].startHeight >= startHeight })
+	if i < len(versions) && versions[i].startHeight == startHeight {
+		return fmt.Errorf("a version of module %q is already registered at height %d", moduleName, startHeight)
+	}
+
+	version := cdc.Schema.Version()
+	if i > 0 && versions[i-1].codec.Schema.Version() >= version {
+		return fmt.Errorf("schema version %d of module %q at height %d must be greater than version %d at height %d",
+			version, moduleName, startHeight, versions[i-1].codec.Schema.Version(), versions[i-1].startHeight)
+	}
+	if i < len(versions) && versions[i].codec.Schema.Version() <= version {
+		return fmt.Errorf("schema version %d of module %q at height %d must be less than version %d at height %d",
+			version, moduleName, startHeight, versions[i].codec.Schema.Version(), versions[i].startHeight)
+	}
+
+	versions = append(versions, moduleVersion{})
+	copy(versions[i+1:], versions[i:])
+	versions[i] = moduleVersion{startHeight: startHeight, codec: cdc}
+	r.modules[moduleName] = versions
+	return nil
+}
+
+// StartHeights returns the heights from which the registered versions of a module are active in increasing
+// order.
+func (r *VersionRegistry) StartHeights(moduleName string) []uint64 {
+	versions := r.modules[moduleName]
+	res := make([]uint64, len(versions))
+	for i, v := range versions {
+		res[i] = v.startHeight
+	}
+	return res
+}
+
+// IterateAll implements DecoderResolver by iterating over the latest version of each module in the order of
+// module names.
+func (r *VersionRegistry) IterateAll(f func(moduleName string, cdc schema.ModuleCodec) error) error {
+	names := make([]string, 0, len(r.modules))
+	for name := range r.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		versions := r.modules[name]
+		if err := f(name, versions[len(versions)-1].codec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LookupDecoder implements DecoderResolver by returning the latest version of the codec of a module.
+func (r *VersionRegistry) LookupDecoder(moduleName string) (schema.ModuleCodec, bool, error) {
+	versions := r.modules[moduleName]
+	if len(versions) == 0 {
+		return schema.ModuleCodec{}, false, nil
+	}
+	return versions[len(versions)-1].codec, true, nil
+}
+
+// LookupDecoderAt implements HeightDecoderResolver by returning the version of the codec of a module which
+// was active at height, which is the version with the greatest start height which is not greater than height.
+func (r *VersionRegistry) LookupDecoderAt(moduleName string, height uint64) (schema.ModuleCodec, bool, error) {
+	versions := r.modules[moduleName]
+	i := sort.Search(len(versions), func(i int) bool { return versions[i].startHeight > height })
+	if i == 0 {
+		return schema.ModuleCodec{}, false, nil
+	}
+	return versions[i-1].codec, true, nil
+}
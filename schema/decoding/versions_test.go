@@ -0,0 +1,216 @@
+package decoding
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+)
+
+// versionedCodec returns a codec whose schema has the given version and which decodes every kv-pair into an
+// update of an object type named after the version.
+func versionedCodec(t *testing.T, version uint64) schema.ModuleCodec {
+	t.Helper()
+	typeName := fmt.Sprintf("v%d", version)
+	modSchema, err := schema.NewModuleSchema([]schema.ObjectType{{Name: typeName, KeyFields: []schema.Field{{Name: "key", Kind: schema.BytesKind}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema.ModuleCodec{
+		Schema: modSchema.WithVersion(version),
+		KVDecoder: func(update schema.KVPairUpdate) ([]schema.ObjectUpdate, error) {
+			return []schema.ObjectUpdate{{TypeName: typeName, Key: update.Key}}, nil
+		},
+	}
+}
+
+func TestVersionRegistry(t *testing.T) {
+	registry := NewVersionRegistry()
+	for _, v := range []struct {
+		height  uint64
+		version uint64
+	}{{100, 2}, {1, 1}, {200, 3}} {
+		if err := registry.Register("bank", v.height, versionedCodec(t, v.version)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if heights := registry.StartHeights("bank"); !reflect.DeepEqual(heights, []uint64{1, 100, 200}) {
+		t.Fatalf("unexpected start heights %v", heights)
+	}
+
+	for height, expected := range map[uint64]uint64{1: 1, 99: 1, 100: 2, 199: 2, 200: 3, 1000: 3} {
+		cdc, found, err := registry.LookupDecoderAt("bank", height)
+		if err != nil || !found {
+			t.Fatalf("expected codec at height %d, got found %v and error %v", height, found, err)
+		}
+		if cdc.Schema.Version() != expected {
+			t.Fatalf("expected version %d at height %d, got %d", expected, height, cdc.Schema.Version())
+		}
+	}
+
+	if _, found, _ := registry.LookupDecoderAt("bank", 0); found {
+		t.Fatalf("expected no codec before the first version")
+	}
+	if _, found, _ := registry.LookupDecoderAt("staking", 100); found {
+		t.Fatalf("expected no codec for unknown module")
+	}
+
+	cdc, found, err := registry.LookupDecoder("bank")
+	if err != nil || !found || cdc.Schema.Version() != 3 {
+		t.Fatalf("expected latest version, got %v, %v, %v", cdc.Schema.Version(), found, err)
+	}
+
+	var modules []string
+	err = registry.IterateAll(func(moduleName string, cdc schema.ModuleCodec) error {
+		modules = append(modules, fmt.Sprintf("%s@%d", moduleName, cdc.Schema.Version()))
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(modules, []string{"bank@3"}) {
+		t.Fatalf("unexpected modules %v, error %v", modules, err)
+	}
+
+	tests := []struct {
+		name        string
+		moduleName  string
+		height      uint64
+		version     uint64
+		errContains string
+	}{
+		{"invalid module name", "1bank", 1, 1, "invalid module name"},
+		{"duplicate height", "bank", 100, 4, "already registered at height 100"},
+		{"version not greater than previous", "bank", 150, 2, "must be greater than version 2 at height 100"},
+		{"version not less than next", "bank", 150, 3, "must be less than version 3 at height 200"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := registry.Register(tt.moduleName, tt.height, versionedCodec(t, tt.version))
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error containing %q, got: %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+func TestMiddleware_versions(t *testing.T) {
+	registry := NewVersionRegistry()
+	if err := registry.Register("bank", 1, versionedCodec(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register("bank", 3, versionedCodec(t, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register("mint", 3, versionedCodec(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls []string
+	listener, err := Middleware(appdata.Listener{
+		InitializeModuleData: func(data appdata.ModuleInitializationData) error {
+			call := fmt.Sprintf("init %s@%d", data.ModuleName, data.Schema.Version())
+			if data.SchemaChanged {
+				call += " changed"
+			}
+			calls = append(calls, call)
+			return nil
+		},
+		OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+			calls = append(calls, fmt.Sprintf("%s %s", data.ModuleName, data.Updates[0].TypeName))
+			return nil
+		},
+	}, registry, MiddlewareOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for height := uint64(1); height <= 4; height++ {
+		if err := listener.StartBlock(appdata.StartBlockData{Height: height}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err := listener.OnKVPair(appdata.KVPairData{Updates: []appdata.ModuleKVPairUpdate{
+			{ModuleName: "bank", Update: schema.KVPairUpdate{Key: []byte{1}}},
+			{ModuleName: "mint", Update: schema.KVPairUpdate{Key: []byte{2}}},
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// bank is re-initialized with version 2 at the upgrade height and mint only exists from then on
+	expected := []string{
+		"init bank@1", "bank v1",
+		"bank v1",
+		"init bank@2 changed", "bank v2", "init mint@1", "mint v1",
+		"bank v2", "mint v1",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("expected calls %v, got %v", expected, calls)
+	}
+}
+
+// mutableCodecModule is a module whose codec can change at runtime.
+type mutableCodecModule struct {
+	cdc schema.ModuleCodec
+}
+
+func (m *mutableCodecModule) ModuleCodec() (schema.ModuleCodec, error) {
+	return m.cdc, nil
+}
+
+func TestMiddleware_invalidator(t *testing.T) {
+	bank := &mutableCodecModule{cdc: versionedCodec(t, 1)}
+	resolver := ModuleSetDecoderResolver(map[string]interface{}{
+		"bank": bank,
+		"mint": &mutableCodecModule{cdc: versionedCodec(t, 1)},
+	})
+
+	var calls []string
+	invalidator := &SchemaInvalidator{}
+	listener, err := Middleware(appdata.Listener{
+		StartBlock: func(data appdata.StartBlockData) error {
+			calls = append(calls, fmt.Sprintf("block %d", data.Height))
+			return nil
+		},
+		InitializeModuleData: func(data appdata.ModuleInitializationData) error {
+			calls = append(calls, fmt.Sprintf("init %s@%d changed=%t", data.ModuleName, data.Schema.Version(), data.SchemaChanged))
+			return nil
+		},
+		OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+			calls = append(calls, fmt.Sprintf("%s %s", data.ModuleName, data.Updates[0].TypeName))
+			return nil
+		},
+	}, resolver, MiddlewareOptions{Invalidator: invalidator})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for height := uint64(1); height <= 3; height++ {
+		if height == 2 {
+			// the schema of bank changes at runtime, mint is invalidated before it is encountered
+			bank.cdc = versionedCodec(t, 2)
+			invalidator.Invalidate("bank", "mint")
+		}
+		if err := listener.StartBlock(appdata.StartBlockData{Height: height}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		updates := []appdata.ModuleKVPairUpdate{{ModuleName: "bank", Update: schema.KVPairUpdate{Key: []byte{1}}}}
+		if height == 3 {
+			updates = append(updates, appdata.ModuleKVPairUpdate{ModuleName: "mint", Update: schema.KVPairUpdate{Key: []byte{2}}})
+		}
+		if err := listener.OnKVPair(appdata.KVPairData{Updates: updates}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := []string{
+		"block 1", "init bank@1 changed=false", "bank v1",
+		"block 2", "init bank@2 changed=true", "bank v2",
+		"block 3", "bank v2", "init mint@1 changed=false", "mint v1",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("expected calls %v, got %v", expected, calls)
+	}
+}
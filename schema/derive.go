@@ -0,0 +1,283 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DeriveOption is an option for DeriveObjectType.
+type DeriveOption func(*deriveOptions)
+
+type deriveOptions struct {
+	name      string
+	enumTypes map[string]EnumType
+}
+
+// WithObjectTypeName sets the name of the derived object type. By default, the snake case name of the
+// go struct type is used.
+func WithObjectTypeName(name string) DeriveOption {
+	return func(o *deriveOptions) {
+		o.name = name
+	}
+}
+
+// WithEnumType registers an enum type which can be bound to string fields using the enum struct tag option.
+func WithEnumType(enumType EnumType) DeriveOption {
+	return func(o *deriveOptions) {
+		o.enumTypes[enumType.Name] = enumType
+	}
+}
+
+// DeriveObjectType derives an ObjectType from a go struct using reflection. value must be a struct or
+// a pointer to a struct, which may be nil, ex. DeriveObjectType((*Balance)(nil)). Every exported field
+// of the struct becomes a field of the object type in declaration order, and its kind is derived from
+// its go type as follows:
+//   - string, []byte, bool, the sized integer types, float32, float64, time.Time and time.Duration map to
+//     the kinds whose go types are described in the Kind documentation
+//   - json.RawMessage maps to JSONKind
+//   - pointers map to nullable fields of the kind of the type they point to
+//   - structs map to StructKind with a struct type derived in the same way as the object type
+//   - slices map to ListKind and maps with string keys map to MapKind
+//
+// int, uint and uintptr fields are not supported because their size is platform dependent.
+//
+// Fields can be customized with the schema struct tag, which is a comma separated list where the first
+// item is the field name and the remaining items are options, ex. `schema:"owner,key"`. If the name is
+// empty, the snake case name of the go field is used and if it is "-", the go field is skipped. The
+// following options are supported:
+//   - key: the field is a key field, otherwise it is a value field
+//   - nullable: the field is nullable even if its go type is not a pointer
+//   - enum=<name>: the field is an enum field of the enum type registered with WithEnumType, the go type
+//     must be a string
+//   - kind=<kind>: the field has the kind with the given name as returned by Kind.String, which overrides
+//     the derived kind, ex. kind=decimal for a string field or kind=bech32address for a []byte field
+//
+// The derived object type is validated before it is returned.
+func DeriveObjectType(value interface{}, opts ...DeriveOption) (ObjectType, error) {
+	options := &deriveOptions{enumTypes: map[string]EnumType{}}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	typ := reflect.TypeOf(value)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return ObjectType{}, fmt.Errorf("expected a struct or a pointer to a struct, got %T", value)
+	}
+
+	objectType := ObjectType{Name: options.name}
+	if objectType.Name == "" {
+		objectType.Name = toSnakeCase(typ.Name())
+	}
+
+	err := deriveStructFields(typ, options, func(field Field, key bool) {
+		if key {
+			objectType.KeyFields = append(objectType.KeyFields, field)
+		} else {
+			objectType.ValueFields = append(objectType.ValueFields, field)
+		}
+	})
+	if err != nil {
+		return ObjectType{}, err
+	}
+
+	if err := objectType.Validate(); err != nil {
+		return ObjectType{}, fmt.Errorf("invalid derived object type %q: %v", objectType.Name, err) //nolint:errorlint // false positive due to using go1.12
+	}
+
+	return objectType, nil
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	durationType   = reflect.TypeOf(time.Duration(0))
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// deriveStructFields derives a field for each exported field of the go struct type and passes it to f
+// along with whether it was tagged as a key field.
+func deriveStructFields(typ reflect.Type, options *deriveOptions, f func(field Field, key bool)) error {
+	for i := 0; i < typ.NumField(); i++ {
+		structField := typ.Field(i)
+		if structField.PkgPath != "" {
+			// unexported field
+			continue
+		}
+
+		tag := strings.Split(structField.Tag.Get("schema"), ",")
+		if tag[0] == "-" {
+			continue
+		}
+
+		field := Field{Name: tag[0]}
+		if field.Name == "" {
+			field.Name = toSnakeCase(structField.Name)
+		}
+
+		goType := structField.Type
+		if goType.Kind() == reflect.Ptr {
+			field.Nullable = true
+			goType = goType.Elem()
+		}
+
+		if err := deriveFieldKind(&field, goType, options); err != nil {
+			return fmt.Errorf("field %s.%s: %v", typ.Name(), structField.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+
+		key := false
+		for _, opt := range tag[1:] {
+			switch {
+			case opt == "key":
+				key = true
+			case opt == "nullable":
+				field.Nullable = true
+			case strings.HasPrefix(opt, "enum="):
+				if goType.Kind() != reflect.String {
+					return fmt.Errorf("field %s.%s: enum fields must be strings", typ.Name(), structField.Name)
+				}
+
+				enumName := strings.TrimPrefix(opt, "enum=")
+				enumType, ok := options.enumTypes[enumName]
+				if !ok {
+					return fmt.Errorf("field %s.%s: unknown enum type %q", typ.Name(), structField.Name, enumName)
+				}
+
+				field.Kind = EnumKind
+				field.EnumType = enumType
+			case strings.HasPrefix(opt, "kind="):
+				var kind Kind
+				kindName := strings.TrimPrefix(opt, "kind=")
+				if err := kind.UnmarshalJSON([]byte(fmt.Sprintf("%q", kindName))); err != nil {
+					return fmt.Errorf("field %s.%s: %v", typ.Name(), structField.Name, err) //nolint:errorlint // false positive due to using go1.12
+				}
+				field.Kind = kind
+			default:
+				return fmt.Errorf("field %s.%s: unknown schema tag option %q", typ.Name(), structField.Name, opt)
+			}
+		}
+
+		f(field, key)
+	}
+
+	return nil
+}
+
+// deriveFieldKind sets the kind of the field and any element, key, value or struct types based on the go type.
+func deriveFieldKind(field *Field, goType reflect.Type, options *deriveOptions) error {
+	switch goType {
+	case timeType:
+		field.Kind = TimeKind
+		return nil
+	case durationType:
+		field.Kind = DurationKind
+		return nil
+	case rawMessageType:
+		field.Kind = JSONKind
+		return nil
+	}
+
+	// byte slices including named types such as addresses
+	if goType.Kind() == reflect.Slice && goType.Elem().Kind() == reflect.Uint8 {
+		field.Kind = BytesKind
+		return nil
+	}
+
+	switch goType.Kind() {
+	case reflect.Struct:
+		structType := StructType{Name: toSnakeCase(goType.Name())}
+		err := deriveStructFields(goType, options, func(nestedField Field, key bool) {
+			structType.Fields = append(structType.Fields, nestedField)
+		})
+		if err != nil {
+			return err
+		}
+		field.Kind = StructKind
+		field.StructType = structType
+		return nil
+	case reflect.Slice:
+		elem, err := deriveNestedKind(field, goType.Elem(), options)
+		if err != nil {
+			return err
+		}
+		field.Kind = ListKind
+		field.ElementKind = elem
+		return nil
+	case reflect.Map:
+		if goType.Key().Kind() != reflect.String {
+			return fmt.Errorf("map keys must be strings, got %s", goType.Key())
+		}
+		value, err := deriveNestedKind(field, goType.Elem(), options)
+		if err != nil {
+			return err
+		}
+		field.Kind = MapKind
+		field.KeyKind = StringKind
+		field.ValueKind = value
+		return nil
+	}
+
+	kind, ok := scalarKinds[goType.Kind()]
+	if !ok {
+		return fmt.Errorf("unsupported go type %s", goType)
+	}
+	field.Kind = kind
+	return nil
+}
+
+// deriveNestedKind derives the kind of a list element or map value. Struct types are set on field.
+func deriveNestedKind(field *Field, goType reflect.Type, options *deriveOptions) (Kind, error) {
+	if goType.Kind() == reflect.Ptr {
+		field.NullableElements = true
+		goType = goType.Elem()
+	}
+
+	nested := Field{}
+	if err := deriveFieldKind(&nested, goType, options); err != nil {
+		return InvalidKind, err
+	}
+
+	if nested.Kind == ListKind || nested.Kind == MapKind {
+		return InvalidKind, fmt.Errorf("nested lists and maps are not supported, got %s", goType)
+	}
+
+	field.StructType = nested.StructType
+	return nested.Kind, nil
+}
+
+var scalarKinds = map[reflect.Kind]Kind{
+	reflect.String:  StringKind,
+	reflect.Bool:    BoolKind,
+	reflect.Int8:    Int8Kind,
+	reflect.Uint8:   Uint8Kind,
+	reflect.Int16:   Int16Kind,
+	reflect.Uint16:  Uint16Kind,
+	reflect.Int32:   Int32Kind,
+	reflect.Uint32:  Uint32Kind,
+	reflect.Int64:   Int64Kind,
+	reflect.Uint64:  Uint64Kind,
+	reflect.Float32: Float32Kind,
+	reflect.Float64: Float64Kind,
+}
+
+// toSnakeCase converts a go identifier such as ConsensusPubKey or HTTPServer to snake case.
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
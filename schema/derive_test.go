@@ -0,0 +1,211 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testAddress []byte
+
+type deriveBalance struct {
+	Address testAddress `schema:",key,kind=bech32address"`
+	Denom   string      `schema:",key"`
+	Amount  string      `schema:",kind=integer"`
+	Status  string      `schema:",enum=status"`
+	Memo    *string
+	Ignored string `schema:"-"`
+	private string //nolint:unused // tests that unexported fields are skipped
+}
+
+type deriveAllTypes struct {
+	ID          uint64 `schema:"id,key"`
+	Str         string
+	Bytes       []byte
+	Int8        int8
+	Uint8       uint8
+	Int16       int16
+	Uint16      uint16
+	Int32       int32
+	Uint32      uint32
+	Int64       int64
+	Float32     float32
+	Float64     float64
+	Bool        bool
+	Time        time.Time
+	Duration    time.Duration
+	JSON        json.RawMessage
+	Point       testPoint
+	Points      []*testPoint
+	Labels      map[string]string
+	HTTPAddress string `schema:",nullable"`
+}
+
+type testPoint struct {
+	X int32
+	Y int32
+}
+
+func TestDeriveObjectType(t *testing.T) {
+	statusEnum := EnumType{Name: "status", Values: []string{"active", "inactive"}}
+
+	objectType, err := DeriveObjectType((*deriveBalance)(nil), WithEnumType(statusEnum))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := ObjectType{
+		Name: "derive_balance",
+		KeyFields: []Field{
+			{Name: "address", Kind: AddressKind},
+			{Name: "denom", Kind: StringKind},
+		},
+		ValueFields: []Field{
+			{Name: "amount", Kind: IntegerStringKind},
+			{Name: "status", Kind: EnumKind, EnumType: statusEnum},
+			{Name: "memo", Kind: StringKind, Nullable: true},
+		},
+	}
+	if !reflect.DeepEqual(objectType, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, objectType)
+	}
+
+	objectType, err = DeriveObjectType(deriveAllTypes{}, WithObjectTypeName("all_types"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	point := StructType{Name: "test_point", Fields: []Field{{Name: "x", Kind: Int32Kind}, {Name: "y", Kind: Int32Kind}}}
+	expected = ObjectType{
+		Name:      "all_types",
+		KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+		ValueFields: []Field{
+			{Name: "str", Kind: StringKind},
+			{Name: "bytes", Kind: BytesKind},
+			{Name: "int8", Kind: Int8Kind},
+			{Name: "uint8", Kind: Uint8Kind},
+			{Name: "int16", Kind: Int16Kind},
+			{Name: "uint16", Kind: Uint16Kind},
+			{Name: "int32", Kind: Int32Kind},
+			{Name: "uint32", Kind: Uint32Kind},
+			{Name: "int64", Kind: Int64Kind},
+			{Name: "float32", Kind: Float32Kind},
+			{Name: "float64", Kind: Float64Kind},
+			{Name: "bool", Kind: BoolKind},
+			{Name: "time", Kind: TimeKind},
+			{Name: "duration", Kind: DurationKind},
+			{Name: "json", Kind: JSONKind},
+			{Name: "point", Kind: StructKind, StructType: point},
+			{Name: "points", Kind: ListKind, ElementKind: StructKind, StructType: point, NullableElements: true},
+			{Name: "labels", Kind: MapKind, KeyKind: StringKind, ValueKind: StringKind},
+			{Name: "http_address", Kind: StringKind, Nullable: true},
+		},
+	}
+	if !reflect.DeepEqual(objectType, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, objectType)
+	}
+}
+
+func TestDeriveObjectType_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       interface{}
+		errContains string
+	}{
+		{
+			name:        "not a struct",
+			value:       "abc",
+			errContains: "expected a struct or a pointer to a struct, got string",
+		},
+		{
+			name:        "nil",
+			value:       nil,
+			errContains: "expected a struct or a pointer to a struct",
+		},
+		{
+			name: "unsupported type",
+			value: struct {
+				ID int `schema:",key"`
+			}{},
+			errContains: "unsupported go type int",
+		},
+		{
+			name: "unknown enum",
+			value: struct {
+				Status string `schema:",enum=status"`
+			}{},
+			errContains: "unknown enum type \"status\"",
+		},
+		{
+			name: "enum on non-string",
+			value: struct {
+				Status int32 `schema:",enum=status"`
+			}{},
+			errContains: "enum fields must be strings",
+		},
+		{
+			name: "unknown kind",
+			value: struct {
+				Amount string `schema:",kind=money"`
+			}{},
+			errContains: "unknown kind \"money\"",
+		},
+		{
+			name: "unknown option",
+			value: struct {
+				Amount string `schema:",primary"`
+			}{},
+			errContains: "unknown schema tag option \"primary\"",
+		},
+		{
+			name: "nested list",
+			value: struct {
+				Matrix [][]int32
+			}{},
+			errContains: "nested lists and maps are not supported",
+		},
+		{
+			name: "non-string map key",
+			value: struct {
+				Counts map[int32]int32
+			}{},
+			errContains: "map keys must be strings",
+		},
+		{
+			name: "invalid derived object type",
+			value: struct {
+				ID *uint64 `schema:",key"`
+			}{},
+			errContains: "key field \"id\" cannot be nullable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DeriveObjectType(tt.value, WithObjectTypeName("test"))
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error to contain %q, got: %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"Foo":             "foo",
+		"FooBar":          "foo_bar",
+		"ID":              "id",
+		"HTTPServer":      "http_server",
+		"ConsensusPubKey": "consensus_pub_key",
+		"Field2Name":      "field2_name",
+		"already_snake":   "already_snake",
+	}
+
+	for name, expected := range tests {
+		if got := toSnakeCase(name); got != expected {
+			t.Errorf("expected toSnakeCase(%q) to be %q, got %q", name, expected, got)
+		}
+	}
+}
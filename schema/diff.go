@@ -0,0 +1,687 @@
+package schema
+
+import "reflect"
+
+// SchemaDiff represents the difference between two versions of a module schema.
+type SchemaDiff struct {
+	// AddedObjectTypes is a list of object types that were added.
+	AddedObjectTypes []ObjectType
+
+	// ChangedObjectTypes is a list of object types that were changed.
+	ChangedObjectTypes []ObjectTypeDiff
+
+	// RemovedObjectTypes is a list of object types that were removed.
+	RemovedObjectTypes []ObjectType
+
+	// AddedEnumTypes is a list of enum types that were added.
+	AddedEnumTypes []EnumType
+
+	// ChangedEnumTypes is a list of enum types that were changed.
+	ChangedEnumTypes []EnumTypeDiff
+
+	// RemovedEnumTypes is a list of enum types that were removed.
+	RemovedEnumTypes []EnumType
+
+	// AddedStructTypes is a list of struct types that were added.
+	AddedStructTypes []StructType
+
+	// ChangedStructTypes is a list of struct types that were changed.
+	ChangedStructTypes []StructTypeDiff
+
+	// RemovedStructTypes is a list of struct types that were removed.
+	RemovedStructTypes []StructType
+
+	// AddedOneOfTypes is a list of oneof types that were added.
+	AddedOneOfTypes []OneOfType
+
+	// ChangedOneOfTypes is a list of oneof types that were changed.
+	ChangedOneOfTypes []OneOfTypeDiff
+
+	// RemovedOneOfTypes is a list of oneof types that were removed.
+	RemovedOneOfTypes []OneOfType
+
+	// AddedEventTypes is a list of event types that were added.
+	AddedEventTypes []EventType
+
+	// ChangedEventTypes is a list of event types that were changed.
+	ChangedEventTypes []EventTypeDiff
+
+	// RemovedEventTypes is a list of event types that were removed.
+	RemovedEventTypes []EventType
+
+	// AddedReservedTypeNames is a list of type names that were reserved.
+	AddedReservedTypeNames []string
+
+	// RemovedReservedTypeNames is a list of type names that are no longer reserved.
+	RemovedReservedTypeNames []string
+}
+
+// ObjectTypeDiff represents the difference between two versions of an object type.
+type ObjectTypeDiff struct {
+	// Name is the name of the object type.
+	Name string
+
+	// KeyFieldsDiff is the difference between the key fields of the object type.
+	KeyFieldsDiff FieldsDiff
+
+	// ValueFieldsDiff is the difference between the value fields of the object type.
+	ValueFieldsDiff FieldsDiff
+
+	// RetainDeletionsChanged indicates that the RetainDeletions flag of the object type changed.
+	RetainDeletionsChanged bool
+
+	// RetentionChanged indicates that the retention policy of the object type changed.
+	RetentionChanged bool
+
+	// AddedUniqueConstraints is a list of unique constraints that were added.
+	AddedUniqueConstraints [][]string
+
+	// RemovedUniqueConstraints is a list of unique constraints that were removed.
+	RemovedUniqueConstraints [][]string
+
+	// IndexesChanged indicates that the secondary indexes of the object type changed.
+	IndexesChanged bool
+
+	// DocumentationChanged indicates that the description or metadata of the object type changed.
+	DocumentationChanged bool
+
+	// AddedReservedFieldNames is a list of field names that were reserved.
+	AddedReservedFieldNames []string
+
+	// RemovedReservedFieldNames is a list of field names that are no longer reserved.
+	RemovedReservedFieldNames []string
+}
+
+// StructTypeDiff represents the difference between two versions of a struct type.
+type StructTypeDiff struct {
+	// Name is the name of the struct type.
+	Name string
+
+	// FieldsDiff is the difference between the fields of the struct type.
+	FieldsDiff FieldsDiff
+}
+
+// OneOfTypeDiff represents the difference between two versions of a oneof type.
+type OneOfTypeDiff struct {
+	// Name is the name of the oneof type.
+	Name string
+
+	// CasesDiff is the difference between the cases of the oneof type.
+	CasesDiff FieldsDiff
+}
+
+// EventTypeDiff represents the difference between two versions of an event type.
+type EventTypeDiff struct {
+	// Name is the name of the event type.
+	Name string
+
+	// FieldsDiff is the difference between the fields of the event type.
+	FieldsDiff FieldsDiff
+
+	// DescriptionChanged indicates that the description of the event type changed.
+	DescriptionChanged bool
+}
+
+// FieldsDiff represents the difference between two lists of fields. Fields are matched by name.
+type FieldsDiff struct {
+	// Added is a list of fields that were added.
+	Added []Field
+
+	// Changed is a list of fields that were changed.
+	Changed []FieldDiff
+
+	// Removed is a list of fields that were removed.
+	Removed []Field
+
+	// OrderChanged indicates that the relative order of the fields present in both lists changed.
+	OrderChanged bool
+}
+
+// FieldDiff represents the difference between two versions of a field with the same name.
+type FieldDiff struct {
+	// Name is the name of the field.
+	Name string
+
+	// OldField is the old version of the field.
+	OldField Field
+
+	// NewField is the new version of the field.
+	NewField Field
+}
+
+// EnumTypeDiff represents the difference between two versions of an enum type.
+type EnumTypeDiff struct {
+	// Name is the name of the enum type.
+	Name string
+
+	// AddedValues is a list of values that were added.
+	AddedValues []string
+
+	// RemovedValues is a list of values that were removed.
+	RemovedValues []string
+
+	// ChangedNumericValues is a list of values which exist in both versions but whose numeric value changed.
+	ChangedNumericValues []string
+
+	// DocumentationChanged indicates that the description or metadata of the enum type changed.
+	DocumentationChanged bool
+}
+
+// DiffModuleSchemas compares two versions of a module schema and returns the difference between them.
+// Changes to enum, struct and oneof type definitions are reported as changed enum, struct and oneof
+// types rather than as changes to the fields which reference them.
+func DiffModuleSchemas(oldSchema, newSchema ModuleSchema) SchemaDiff {
+	diff := SchemaDiff{}
+
+	oldSchema.Types(func(oldType Type) bool {
+		newType, ok := newSchema.LookupType(oldType.TypeName())
+		switch oldType := oldType.(type) {
+		case ObjectType:
+			newObjType, isObj := newType.(ObjectType)
+			if !ok || !isObj {
+				diff.RemovedObjectTypes = append(diff.RemovedObjectTypes, oldType)
+			} else if objDiff := diffObjectTypes(oldType, newObjType); !objDiff.Empty() {
+				diff.ChangedObjectTypes = append(diff.ChangedObjectTypes, objDiff)
+			}
+		case EnumType:
+			newEnumType, isEnum := newType.(EnumType)
+			if !ok || !isEnum {
+				diff.RemovedEnumTypes = append(diff.RemovedEnumTypes, oldType)
+			} else if enumDiff := diffEnumTypes(oldType, newEnumType); !enumDiff.Empty() {
+				diff.ChangedEnumTypes = append(diff.ChangedEnumTypes, enumDiff)
+			}
+		case StructType:
+			newStructType, isStruct := newType.(StructType)
+			if !ok || !isStruct {
+				diff.RemovedStructTypes = append(diff.RemovedStructTypes, oldType)
+			} else if structDiff := diffStructTypes(oldType, newStructType); !structDiff.Empty() {
+				diff.ChangedStructTypes = append(diff.ChangedStructTypes, structDiff)
+			}
+		case OneOfType:
+			newOneOfType, isOneOf := newType.(OneOfType)
+			if !ok || !isOneOf {
+				diff.RemovedOneOfTypes = append(diff.RemovedOneOfTypes, oldType)
+			} else if oneOfDiff := diffOneOfTypes(oldType, newOneOfType); !oneOfDiff.Empty() {
+				diff.ChangedOneOfTypes = append(diff.ChangedOneOfTypes, oneOfDiff)
+			}
+		case EventType:
+			newEventType, isEvent := newType.(EventType)
+			if !ok || !isEvent {
+				diff.RemovedEventTypes = append(diff.RemovedEventTypes, oldType)
+			} else if eventDiff := diffEventTypes(oldType, newEventType); !eventDiff.Empty() {
+				diff.ChangedEventTypes = append(diff.ChangedEventTypes, eventDiff)
+			}
+		}
+		return true
+	})
+
+	newSchema.Types(func(newType Type) bool {
+		oldType, ok := oldSchema.LookupType(newType.TypeName())
+		switch newType := newType.(type) {
+		case ObjectType:
+			if _, isObj := oldType.(ObjectType); !ok || !isObj {
+				diff.AddedObjectTypes = append(diff.AddedObjectTypes, newType)
+			}
+		case EnumType:
+			if _, isEnum := oldType.(EnumType); !ok || !isEnum {
+				diff.AddedEnumTypes = append(diff.AddedEnumTypes, newType)
+			}
+		case StructType:
+			if _, isStruct := oldType.(StructType); !ok || !isStruct {
+				diff.AddedStructTypes = append(diff.AddedStructTypes, newType)
+			}
+		case OneOfType:
+			if _, isOneOf := oldType.(OneOfType); !ok || !isOneOf {
+				diff.AddedOneOfTypes = append(diff.AddedOneOfTypes, newType)
+			}
+		case EventType:
+			if _, isEvent := oldType.(EventType); !ok || !isEvent {
+				diff.AddedEventTypes = append(diff.AddedEventTypes, newType)
+			}
+		}
+		return true
+	})
+
+	diff.AddedReservedTypeNames, diff.RemovedReservedTypeNames = diffNames(oldSchema.reservedTypeNames, newSchema.reservedTypeNames)
+
+	return diff
+}
+
+func diffObjectTypes(oldObjType, newObjType ObjectType) ObjectTypeDiff {
+	diff := ObjectTypeDiff{
+		Name:                   oldObjType.Name,
+		KeyFieldsDiff:          diffFields(oldObjType.KeyFields, newObjType.KeyFields),
+		ValueFieldsDiff:        diffFields(oldObjType.ValueFields, newObjType.ValueFields),
+		RetainDeletionsChanged: oldObjType.RetainDeletions != newObjType.RetainDeletions,
+		RetentionChanged:       !reflect.DeepEqual(oldObjType.Retention, newObjType.Retention),
+		IndexesChanged:         !reflect.DeepEqual(oldObjType.Indexes, newObjType.Indexes),
+		DocumentationChanged: documentationChanged(oldObjType.Description, newObjType.Description,
+			oldObjType.Metadata, newObjType.Metadata),
+	}
+
+	oldConstraints := map[string]bool{}
+	for _, constraint := range oldObjType.UniqueConstraints {
+		oldConstraints[uniqueConstraintKey(constraint)] = true
+	}
+
+	newConstraints := map[string]bool{}
+	for _, constraint := range newObjType.UniqueConstraints {
+		key := uniqueConstraintKey(constraint)
+		newConstraints[key] = true
+		if !oldConstraints[key] {
+			diff.AddedUniqueConstraints = append(diff.AddedUniqueConstraints, constraint)
+		}
+	}
+
+	for _, constraint := range oldObjType.UniqueConstraints {
+		if !newConstraints[uniqueConstraintKey(constraint)] {
+			diff.RemovedUniqueConstraints = append(diff.RemovedUniqueConstraints, constraint)
+		}
+	}
+
+	diff.AddedReservedFieldNames, diff.RemovedReservedFieldNames = diffNames(oldObjType.ReservedFieldNames, newObjType.ReservedFieldNames)
+
+	return diff
+}
+
+// diffNames returns the names which are only in newNames and the names which are only in oldNames.
+func diffNames(oldNames, newNames []string) (added, removed []string) {
+	oldSet := map[string]bool{}
+	for _, name := range oldNames {
+		oldSet[name] = true
+	}
+
+	newSet := map[string]bool{}
+	for _, name := range newNames {
+		newSet[name] = true
+		if !oldSet[name] {
+			added = append(added, name)
+		}
+	}
+
+	for _, name := range oldNames {
+		if !newSet[name] {
+			removed = append(removed, name)
+		}
+	}
+
+	return added, removed
+}
+
+func diffStructTypes(oldStructType, newStructType StructType) StructTypeDiff {
+	return StructTypeDiff{
+		Name:       oldStructType.Name,
+		FieldsDiff: diffFields(oldStructType.Fields, newStructType.Fields),
+	}
+}
+
+func diffOneOfTypes(oldOneOfType, newOneOfType OneOfType) OneOfTypeDiff {
+	return OneOfTypeDiff{
+		Name:      oldOneOfType.Name,
+		CasesDiff: diffFields(oldOneOfType.Cases, newOneOfType.Cases),
+	}
+}
+
+func diffEventTypes(oldEventType, newEventType EventType) EventTypeDiff {
+	return EventTypeDiff{
+		Name:               oldEventType.Name,
+		FieldsDiff:         diffFields(oldEventType.Fields, newEventType.Fields),
+		DescriptionChanged: oldEventType.Description != newEventType.Description,
+	}
+}
+
+func diffEnumTypes(oldEnum, newEnum EnumType) EnumTypeDiff {
+	diff := EnumTypeDiff{
+		Name:                 oldEnum.Name,
+		DocumentationChanged: documentationChanged(oldEnum.Description, newEnum.Description, oldEnum.Metadata, newEnum.Metadata),
+	}
+
+	oldValues := map[string]bool{}
+	for _, value := range oldEnum.Values {
+		oldValues[value] = true
+	}
+
+	newValues := map[string]bool{}
+	for _, value := range newEnum.Values {
+		newValues[value] = true
+		if !oldValues[value] {
+			diff.AddedValues = append(diff.AddedValues, value)
+		}
+	}
+
+	for _, value := range oldEnum.Values {
+		if !newValues[value] {
+			diff.RemovedValues = append(diff.RemovedValues, value)
+			continue
+		}
+
+		oldNum, _ := oldEnum.NumericValue(value)
+		newNum, _ := newEnum.NumericValue(value)
+		if oldNum != newNum {
+			diff.ChangedNumericValues = append(diff.ChangedNumericValues, value)
+		}
+	}
+
+	return diff
+}
+
+func diffFields(oldFields, newFields []Field) FieldsDiff {
+	diff := FieldsDiff{}
+
+	oldFieldMap := map[string]Field{}
+	for _, field := range oldFields {
+		oldFieldMap[field.Name] = field
+	}
+
+	newFieldMap := map[string]Field{}
+	var newOrder []string
+	for _, newField := range newFields {
+		newFieldMap[newField.Name] = newField
+
+		oldField, ok := oldFieldMap[newField.Name]
+		if !ok {
+			diff.Added = append(diff.Added, newField)
+			continue
+		}
+
+		newOrder = append(newOrder, newField.Name)
+		if fieldDiff := (FieldDiff{Name: newField.Name, OldField: oldField, NewField: newField}); !fieldDiff.Empty() {
+			diff.Changed = append(diff.Changed, fieldDiff)
+		}
+	}
+
+	var oldOrder []string
+	for _, oldField := range oldFields {
+		if _, ok := newFieldMap[oldField.Name]; !ok {
+			diff.Removed = append(diff.Removed, oldField)
+			continue
+		}
+
+		oldOrder = append(oldOrder, oldField.Name)
+	}
+
+	for i := range oldOrder {
+		if oldOrder[i] != newOrder[i] {
+			diff.OrderChanged = true
+			break
+		}
+	}
+
+	return diff
+}
+
+// Empty returns true if the module schemas are identical.
+func (d SchemaDiff) Empty() bool {
+	return len(d.AddedObjectTypes) == 0 && len(d.ChangedObjectTypes) == 0 && len(d.RemovedObjectTypes) == 0 &&
+		len(d.AddedEnumTypes) == 0 && len(d.ChangedEnumTypes) == 0 && len(d.RemovedEnumTypes) == 0 &&
+		len(d.AddedStructTypes) == 0 && len(d.ChangedStructTypes) == 0 && len(d.RemovedStructTypes) == 0 &&
+		len(d.AddedOneOfTypes) == 0 && len(d.ChangedOneOfTypes) == 0 && len(d.RemovedOneOfTypes) == 0 &&
+		len(d.AddedEventTypes) == 0 && len(d.ChangedEventTypes) == 0 && len(d.RemovedEventTypes) == 0 &&
+		len(d.AddedReservedTypeNames) == 0 && len(d.RemovedReservedTypeNames) == 0
+}
+
+// IsCompatible returns true if all the changes are backwards-compatible, meaning that data indexed
+// with the old schema is still valid under the new schema. Adding types is compatible whereas
+// removing types is not. See the IsCompatible methods of ObjectTypeDiff, StructTypeDiff, OneOfTypeDiff,
+// EnumTypeDiff and EventTypeDiff for the rules which apply to changed types.
+func (d SchemaDiff) IsCompatible() bool {
+	if len(d.RemovedObjectTypes) != 0 || len(d.RemovedEnumTypes) != 0 || len(d.RemovedStructTypes) != 0 ||
+		len(d.RemovedOneOfTypes) != 0 || len(d.RemovedEventTypes) != 0 {
+		return false
+	}
+
+	for _, objDiff := range d.ChangedObjectTypes {
+		if !objDiff.IsCompatible() {
+			return false
+		}
+	}
+
+	for _, enumDiff := range d.ChangedEnumTypes {
+		if !enumDiff.IsCompatible() {
+			return false
+		}
+	}
+
+	for _, structDiff := range d.ChangedStructTypes {
+		if !structDiff.IsCompatible() {
+			return false
+		}
+	}
+
+	for _, oneOfDiff := range d.ChangedOneOfTypes {
+		if !oneOfDiff.IsCompatible() {
+			return false
+		}
+	}
+
+	for _, eventDiff := range d.ChangedEventTypes {
+		if !eventDiff.IsCompatible() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Empty returns true if the object types are identical.
+func (o ObjectTypeDiff) Empty() bool {
+	return o.KeyFieldsDiff.Empty() && o.ValueFieldsDiff.Empty() && !o.RetainDeletionsChanged &&
+		!o.RetentionChanged && len(o.AddedUniqueConstraints) == 0 && len(o.RemovedUniqueConstraints) == 0 &&
+		!o.IndexesChanged && !o.DocumentationChanged && len(o.AddedReservedFieldNames) == 0 &&
+		len(o.RemovedReservedFieldNames) == 0
+}
+
+// IsCompatible returns true if the changes to the object type are backwards-compatible. Any change to the key
+// fields is breaking whereas value fields changes are compatible as long as FieldsDiff.IsCompatible is true.
+// Changing RetainDeletions, the retention policy, indexes, documentation or reserved field names and removing
+// unique constraints is always compatible, but adding unique constraints is breaking because existing objects
+// may violate them.
+func (o ObjectTypeDiff) IsCompatible() bool {
+	return o.KeyFieldsDiff.Empty() && o.ValueFieldsDiff.IsCompatible() && len(o.AddedUniqueConstraints) == 0
+}
+
+// Empty returns true if the struct types are identical.
+func (s StructTypeDiff) Empty() bool {
+	return s.FieldsDiff.Empty()
+}
+
+// IsCompatible returns true if the changes to the struct type are backwards-compatible as defined by
+// FieldsDiff.IsCompatible.
+func (s StructTypeDiff) IsCompatible() bool {
+	return s.FieldsDiff.IsCompatible()
+}
+
+// Empty returns true if the oneof types are identical.
+func (o OneOfTypeDiff) Empty() bool {
+	return o.CasesDiff.Empty()
+}
+
+// IsCompatible returns true if the changes to the oneof type are backwards-compatible, meaning that no
+// cases were removed and changed cases are compatible as defined by FieldDiff.IsCompatible. Unlike fields,
+// cases can be added and reordered freely because existing values only ever use one of the existing cases.
+func (o OneOfTypeDiff) IsCompatible() bool {
+	if len(o.CasesDiff.Removed) != 0 {
+		return false
+	}
+
+	for _, caseDiff := range o.CasesDiff.Changed {
+		if !caseDiff.IsCompatible() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Empty returns true if the event types are identical.
+func (e EventTypeDiff) Empty() bool {
+	return e.FieldsDiff.Empty() && !e.DescriptionChanged
+}
+
+// IsCompatible returns true if the changes to the event type are backwards-compatible as defined by
+// FieldsDiff.IsCompatible.
+func (e EventTypeDiff) IsCompatible() bool {
+	return e.FieldsDiff.IsCompatible()
+}
+
+// Empty returns true if the field lists are identical.
+func (d FieldsDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0 && !d.OrderChanged
+}
+
+// IsCompatible returns true if the changes to the fields are backwards-compatible. Fields cannot be
+// removed, added fields must be nullable or have a default value and changed fields must be compatible
+// as defined by FieldDiff.IsCompatible. Reordering fields is compatible.
+func (d FieldsDiff) IsCompatible() bool {
+	if len(d.Removed) != 0 {
+		return false
+	}
+
+	for _, field := range d.Added {
+		if !field.Nullable && field.Default == nil {
+			return false
+		}
+	}
+
+	for _, fieldDiff := range d.Changed {
+		if !fieldDiff.IsCompatible() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Empty returns true if the field definitions are identical, not counting the definitions
+// of any referenced enum, struct or oneof types.
+func (d FieldDiff) Empty() bool {
+	return !d.KindChanged() && !d.NullableChanged() && !d.ReferencedTypeChanged() && !d.DecimalConstraintsChanged() &&
+		!d.TimeResolutionChanged() && !d.ConstraintsChanged() && !d.AddressPrefixChanged() &&
+		!d.NullableElementsChanged() && !d.DefaultChanged() &&
+		!d.DocumentationChanged()
+}
+
+// KindChanged returns true if the field's kind or any of its element, key or value kinds changed.
+func (d FieldDiff) KindChanged() bool {
+	return d.OldField.Kind != d.NewField.Kind ||
+		d.OldField.ElementKind != d.NewField.ElementKind ||
+		d.OldField.KeyKind != d.NewField.KeyKind ||
+		d.OldField.ValueKind != d.NewField.ValueKind
+}
+
+// NullableChanged returns true if the field's nullability changed.
+func (d FieldDiff) NullableChanged() bool {
+	return d.OldField.Nullable != d.NewField.Nullable
+}
+
+// ReferencedTypeChanged returns true if the field now references an enum, struct or oneof type with a different name.
+func (d FieldDiff) ReferencedTypeChanged() bool {
+	return d.OldField.EnumType.Name != d.NewField.EnumType.Name ||
+		d.OldField.StructType.Name != d.NewField.StructType.Name ||
+		d.OldField.OneOfType.Name != d.NewField.OneOfType.Name
+}
+
+// DocumentationChanged returns true if the field's description or metadata changed. Documentation changes
+// are always compatible.
+func (d FieldDiff) DocumentationChanged() bool {
+	return documentationChanged(d.OldField.Description, d.NewField.Description, d.OldField.Metadata, d.NewField.Metadata)
+}
+
+// NullableElementsChanged returns true if the nullability of the field's list elements or map values changed.
+func (d FieldDiff) NullableElementsChanged() bool {
+	return d.OldField.NullableElements != d.NewField.NullableElements
+}
+
+// DefaultChanged returns true if the field's default value changed. Changing the default value is
+// always compatible because it only affects objects created after the change.
+func (d FieldDiff) DefaultChanged() bool {
+	return !reflect.DeepEqual(d.OldField.Default, d.NewField.Default)
+}
+
+// DecimalConstraintsChanged returns true if the field's decimal precision or scale changed.
+func (d FieldDiff) DecimalConstraintsChanged() bool {
+	return d.OldField.Precision != d.NewField.Precision || d.OldField.Scale != d.NewField.Scale
+}
+
+// DecimalConstraintsRelaxed returns true if every value which was valid under the old precision and
+// scale is still valid under the new ones, meaning that neither the number of digits allowed before
+// nor after the decimal point decreased.
+func (d FieldDiff) DecimalConstraintsRelaxed() bool {
+	if d.NewField.Precision == 0 {
+		return true
+	}
+
+	if d.OldField.Precision == 0 {
+		return false
+	}
+
+	return d.NewField.Scale >= d.OldField.Scale &&
+		d.NewField.Precision-d.NewField.Scale >= d.OldField.Precision-d.OldField.Scale
+}
+
+// TimeResolutionChanged returns true if the field's time resolution changed.
+func (d FieldDiff) TimeResolutionChanged() bool {
+	return d.OldField.TimeResolution != d.NewField.TimeResolution
+}
+
+// TimeResolutionRelaxed returns true if every value which was valid under the old time resolution is
+// still valid under the new one, meaning that the resolution did not become coarser.
+func (d FieldDiff) TimeResolutionRelaxed() bool {
+	return d.NewField.TimeResolution.Unit() <= d.OldField.TimeResolution.Unit()
+}
+
+// ConstraintsChanged returns true if the field's value constraints changed.
+func (d FieldDiff) ConstraintsChanged() bool {
+	return d.OldField.Constraints != d.NewField.Constraints
+}
+
+// ConstraintsRelaxed returns true if every value which satisfied the old value constraints still satisfies
+// the new ones, meaning that bounds and maximum lengths were only widened or removed and patterns and sizes
+// were unchanged or removed.
+func (d FieldDiff) ConstraintsRelaxed() bool {
+	return d.NewField.Constraints.relaxed(d.OldField.Constraints)
+}
+
+// AddressPrefixChanged returns true if the field's address prefix changed. Changing the address prefix is
+// always compatible because it only affects how addresses are rendered.
+func (d FieldDiff) AddressPrefixChanged() bool {
+	return d.OldField.AddressPrefix != d.NewField.AddressPrefix
+}
+
+// IsCompatible returns true if the changes to the field are backwards-compatible, meaning that the
+// kind and referenced types have not changed, decimal constraints, time resolutions and value constraints
+// have only been relaxed and neither the field nor its list elements or map values have gone from nullable to non-nullable.
+func (d FieldDiff) IsCompatible() bool {
+	return !d.KindChanged() && !d.ReferencedTypeChanged() && d.DecimalConstraintsRelaxed() &&
+		d.TimeResolutionRelaxed() && d.ConstraintsRelaxed() &&
+		(!d.NullableChanged() || d.NewField.Nullable) &&
+		(!d.NullableElementsChanged() || d.NewField.NullableElements)
+}
+
+// Empty returns true if the enum types are identical.
+func (e EnumTypeDiff) Empty() bool {
+	return len(e.AddedValues) == 0 && len(e.RemovedValues) == 0 && len(e.ChangedNumericValues) == 0 &&
+		!e.DocumentationChanged
+}
+
+// IsCompatible returns true if the changes to the enum type are backwards-compatible, meaning that
+// values were only added and the numeric values of existing values did not change.
+func (e EnumTypeDiff) IsCompatible() bool {
+	return len(e.RemovedValues) == 0 && len(e.ChangedNumericValues) == 0
+}
+
+// documentationChanged returns true if either the description or the metadata changed. Nil and empty
+// metadata are considered equal.
+func documentationChanged(oldDescription, newDescription string, oldMetadata, newMetadata map[string]string) bool {
+	if oldDescription != newDescription || len(oldMetadata) != len(newMetadata) {
+		return true
+	}
+
+	for key, value := range oldMetadata {
+		if newValue, ok := newMetadata[key]; !ok || newValue != value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,596 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffModuleSchemas(t *testing.T) {
+	tests := []struct {
+		name         string
+		oldSchema    ModuleSchema
+		newSchema    ModuleSchema
+		diff         SchemaDiff
+		isCompatible bool
+	}{
+		{
+			name:         "no change",
+			oldSchema:    requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}),
+			newSchema:    requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}),
+			diff:         SchemaDiff{},
+			isCompatible: true,
+		},
+		{
+			name:      "object type added",
+			oldSchema: requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}),
+			newSchema: requireModuleSchema(t, []ObjectType{
+				{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}},
+				{Name: "object2", KeyFields: []Field{{Name: "key1", Kind: StringKind}}},
+			}),
+			diff: SchemaDiff{
+				AddedObjectTypes: []ObjectType{{Name: "object2", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}},
+			},
+			isCompatible: true,
+		},
+		{
+			name: "object type removed",
+			oldSchema: requireModuleSchema(t, []ObjectType{
+				{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}},
+				{Name: "object2", KeyFields: []Field{{Name: "key1", Kind: StringKind}}},
+			}),
+			newSchema: requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}),
+			diff: SchemaDiff{
+				RemovedObjectTypes: []ObjectType{{Name: "object2", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}},
+			},
+			isCompatible: false,
+		},
+		{
+			name:      "nullable value field added",
+			oldSchema: requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: Int32Kind, Nullable: true}},
+			}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{
+					{
+						Name: "object1",
+						ValueFieldsDiff: FieldsDiff{
+							Added: []Field{{Name: "value1", Kind: Int32Kind, Nullable: true}},
+						},
+					},
+				},
+			},
+			isCompatible: true,
+		},
+		{
+			name:      "non-nullable value field added",
+			oldSchema: requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: Int32Kind}},
+			}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{
+					{
+						Name: "object1",
+						ValueFieldsDiff: FieldsDiff{
+							Added: []Field{{Name: "value1", Kind: Int32Kind}},
+						},
+					},
+				},
+			},
+			isCompatible: false,
+		},
+		{
+			name: "value field removed",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: Int32Kind}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{
+					{
+						Name: "object1",
+						ValueFieldsDiff: FieldsDiff{
+							Removed: []Field{{Name: "value1", Kind: Int32Kind}},
+						},
+					},
+				},
+			},
+			isCompatible: false,
+		},
+		{
+			name: "value field kind changed",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: Int32Kind}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: Int64Kind}},
+			}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{
+					{
+						Name: "object1",
+						ValueFieldsDiff: FieldsDiff{
+							Changed: []FieldDiff{
+								{
+									Name:     "value1",
+									OldField: Field{Name: "value1", Kind: Int32Kind},
+									NewField: Field{Name: "value1", Kind: Int64Kind},
+								},
+							},
+						},
+					},
+				},
+			},
+			isCompatible: false,
+		},
+		{
+			name: "value field made nullable",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: Int32Kind}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: Int32Kind, Nullable: true}},
+			}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{
+					{
+						Name: "object1",
+						ValueFieldsDiff: FieldsDiff{
+							Changed: []FieldDiff{
+								{
+									Name:     "value1",
+									OldField: Field{Name: "value1", Kind: Int32Kind},
+									NewField: Field{Name: "value1", Kind: Int32Kind, Nullable: true},
+								},
+							},
+						},
+					},
+				},
+			},
+			isCompatible: true,
+		},
+		{
+			name: "value fields reordered",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: Int32Kind}, {Name: "value2", Kind: Int32Kind}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value2", Kind: Int32Kind}, {Name: "value1", Kind: Int32Kind}},
+			}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{
+					{
+						Name:            "object1",
+						ValueFieldsDiff: FieldsDiff{OrderChanged: true},
+					},
+				},
+			},
+			isCompatible: true,
+		},
+		{
+			name: "key fields reordered",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:      "object1",
+				KeyFields: []Field{{Name: "key1", Kind: StringKind}, {Name: "key2", Kind: StringKind}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:      "object1",
+				KeyFields: []Field{{Name: "key2", Kind: StringKind}, {Name: "key1", Kind: StringKind}},
+			}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{
+					{
+						Name:          "object1",
+						KeyFieldsDiff: FieldsDiff{OrderChanged: true},
+					},
+				},
+			},
+			isCompatible: false,
+		},
+		{
+			name:      "retain deletions changed",
+			oldSchema: requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}, RetainDeletions: true}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{{Name: "object1", RetainDeletionsChanged: true}},
+			},
+			isCompatible: true,
+		},
+		{
+			name:      "retention policy changed",
+			oldSchema: requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}, Retention: &RetentionPolicy{Mode: RetentionKeepLatest}}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{{Name: "object1", RetentionChanged: true}},
+			},
+			isCompatible: true,
+		},
+		{
+			name:      "reserved field names changed",
+			oldSchema: requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}, ReservedFieldNames: []string{"old1", "old2"}}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}, ReservedFieldNames: []string{"old2", "old3"}}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{{
+					Name:                      "object1",
+					AddedReservedFieldNames:   []string{"old3"},
+					RemovedReservedFieldNames: []string{"old1"},
+				}},
+			},
+			isCompatible: true,
+		},
+		{
+			name:      "reserved type names changed",
+			oldSchema: requireReservedTypeNames(t, requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}), "old1"),
+			newSchema: requireReservedTypeNames(t, requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}), "old2"),
+			diff: SchemaDiff{
+				AddedReservedTypeNames:   []string{"old2"},
+				RemovedReservedTypeNames: []string{"old1"},
+			},
+			isCompatible: true,
+		},
+		{
+			name:      "event type added",
+			oldSchema: requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}),
+			newSchema: requireEventTypes(t, requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}), testTransferEvent),
+			diff: SchemaDiff{
+				AddedEventTypes: []EventType{testTransferEvent},
+			},
+			isCompatible: true,
+		},
+		{
+			name:      "event type removed",
+			oldSchema: requireEventTypes(t, requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}), testTransferEvent),
+			newSchema: requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}),
+			diff: SchemaDiff{
+				RemovedEventTypes: []EventType{testTransferEvent},
+			},
+			isCompatible: false,
+		},
+		{
+			name: "event field added",
+			oldSchema: requireEventTypes(t, requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}),
+				EventType{Name: "burn", Fields: []Field{{Name: "amount", Kind: IntegerStringKind}}}),
+			newSchema: requireEventTypes(t, requireModuleSchema(t, []ObjectType{{Name: "object1", KeyFields: []Field{{Name: "key1", Kind: StringKind}}}}),
+				EventType{Name: "burn", Fields: []Field{{Name: "amount", Kind: IntegerStringKind}, {Name: "burner", Kind: AddressKind, Nullable: true}}}),
+			diff: SchemaDiff{
+				ChangedEventTypes: []EventTypeDiff{{
+					Name:       "burn",
+					FieldsDiff: FieldsDiff{Added: []Field{{Name: "burner", Kind: AddressKind, Nullable: true}}},
+				}},
+			},
+			isCompatible: true,
+		},
+		{
+			name: "documentation changed",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a"}}}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:      "object1",
+				KeyFields: []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{
+					Name:        "value1",
+					Kind:        EnumKind,
+					EnumType:    EnumType{Name: "enum1", Values: []string{"a"}, Metadata: map[string]string{"foo": "bar"}},
+					Description: "value one",
+				}},
+				Description: "object one",
+			}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{{
+					Name: "object1",
+					ValueFieldsDiff: FieldsDiff{
+						Changed: []FieldDiff{{
+							Name:     "value1",
+							OldField: Field{Name: "value1", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a"}}},
+							NewField: Field{
+								Name:        "value1",
+								Kind:        EnumKind,
+								EnumType:    EnumType{Name: "enum1", Values: []string{"a"}, Metadata: map[string]string{"foo": "bar"}},
+								Description: "value one",
+							},
+						}},
+					},
+					DocumentationChanged: true,
+				}},
+				ChangedEnumTypes: []EnumTypeDiff{{Name: "enum1", DocumentationChanged: true}},
+			},
+			isCompatible: true,
+		},
+		{
+			name: "index added",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: StringKind}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: StringKind}},
+				Indexes:     []IndexDescriptor{{Name: "by_value1", Fields: []IndexField{{Name: "value1"}}}},
+			}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{{Name: "object1", IndexesChanged: true}},
+			},
+			isCompatible: true,
+		},
+		{
+			name: "unique constraints changed",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:              "object1",
+				KeyFields:         []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields:       []Field{{Name: "value1", Kind: StringKind}, {Name: "value2", Kind: StringKind}},
+				UniqueConstraints: [][]string{{"value1"}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:              "object1",
+				KeyFields:         []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields:       []Field{{Name: "value1", Kind: StringKind}, {Name: "value2", Kind: StringKind}},
+				UniqueConstraints: [][]string{{"value1", "value2"}},
+			}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{{
+					Name:                     "object1",
+					AddedUniqueConstraints:   [][]string{{"value1", "value2"}},
+					RemovedUniqueConstraints: [][]string{{"value1"}},
+				}},
+			},
+			isCompatible: false,
+		},
+		{
+			name: "enum value added",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:      "object1",
+				KeyFields: []Field{{Name: "key1", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a", "b"}}}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:      "object1",
+				KeyFields: []Field{{Name: "key1", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a", "b", "c"}}}},
+			}}),
+			diff: SchemaDiff{
+				ChangedEnumTypes: []EnumTypeDiff{{Name: "enum1", AddedValues: []string{"c"}}},
+			},
+			isCompatible: true,
+		},
+		{
+			name: "enum value removed",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:      "object1",
+				KeyFields: []Field{{Name: "key1", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a", "b", "c"}}}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:      "object1",
+				KeyFields: []Field{{Name: "key1", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a", "b"}}}},
+			}}),
+			diff: SchemaDiff{
+				ChangedEnumTypes: []EnumTypeDiff{{Name: "enum1", RemovedValues: []string{"c"}}},
+			},
+			isCompatible: false,
+		},
+		{
+			name: "enum numeric value changed",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:      "object1",
+				KeyFields: []Field{{Name: "key1", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a", "b"}}}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:      "object1",
+				KeyFields: []Field{{Name: "key1", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a", "b"}, NumericValues: []int32{0, 2}}}},
+			}}),
+			diff: SchemaDiff{
+				ChangedEnumTypes: []EnumTypeDiff{{Name: "enum1", ChangedNumericValues: []string{"b"}}},
+			},
+			isCompatible: false,
+		},
+		{
+			name: "enum type replaced",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a"}}}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: EnumKind, EnumType: EnumType{Name: "enum2", Values: []string{"a"}}}},
+			}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{
+					{
+						Name: "object1",
+						ValueFieldsDiff: FieldsDiff{
+							Changed: []FieldDiff{
+								{
+									Name:     "value1",
+									OldField: Field{Name: "value1", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a"}}},
+									NewField: Field{Name: "value1", Kind: EnumKind, EnumType: EnumType{Name: "enum2", Values: []string{"a"}}},
+								},
+							},
+						},
+					},
+				},
+				AddedEnumTypes:   []EnumType{{Name: "enum2", Values: []string{"a"}}},
+				RemovedEnumTypes: []EnumType{{Name: "enum1", Values: []string{"a"}}},
+			},
+			isCompatible: false,
+		},
+		{
+			name: "struct field added",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: StructKind, StructType: testPointStruct}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:      "object1",
+				KeyFields: []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{
+					{
+						Name: "value1",
+						Kind: StructKind,
+						StructType: StructType{
+							Name:   "point",
+							Fields: append(append([]Field{}, testPointStruct.Fields...), Field{Name: "z", Kind: Int32Kind, Nullable: true}),
+						},
+					},
+				},
+			}}),
+			diff: SchemaDiff{
+				ChangedStructTypes: []StructTypeDiff{
+					{
+						Name:       "point",
+						FieldsDiff: FieldsDiff{Added: []Field{{Name: "z", Kind: Int32Kind, Nullable: true}}},
+					},
+				},
+			},
+			isCompatible: true,
+		},
+		{
+			name: "decimal scale increased",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: DecimalStringKind, Precision: 10, Scale: 2}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: DecimalStringKind, Precision: 12, Scale: 4}},
+			}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{
+					{
+						Name: "object1",
+						ValueFieldsDiff: FieldsDiff{
+							Changed: []FieldDiff{
+								{
+									Name:     "value1",
+									OldField: Field{Name: "value1", Kind: DecimalStringKind, Precision: 10, Scale: 2},
+									NewField: Field{Name: "value1", Kind: DecimalStringKind, Precision: 12, Scale: 4},
+								},
+							},
+						},
+					},
+				},
+			},
+			isCompatible: true,
+		},
+		{
+			name: "decimal scale increased without precision",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: DecimalStringKind, Precision: 10, Scale: 2}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: DecimalStringKind, Precision: 10, Scale: 4}},
+			}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{
+					{
+						Name: "object1",
+						ValueFieldsDiff: FieldsDiff{
+							Changed: []FieldDiff{
+								{
+									Name:     "value1",
+									OldField: Field{Name: "value1", Kind: DecimalStringKind, Precision: 10, Scale: 2},
+									NewField: Field{Name: "value1", Kind: DecimalStringKind, Precision: 10, Scale: 4},
+								},
+							},
+						},
+					},
+				},
+			},
+			isCompatible: false,
+		},
+		{
+			name: "time resolution finer",
+			oldSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: TimeKind, TimeResolution: TimeResolutionSeconds}},
+			}}),
+			newSchema: requireModuleSchema(t, []ObjectType{{
+				Name:        "object1",
+				KeyFields:   []Field{{Name: "key1", Kind: StringKind}},
+				ValueFields: []Field{{Name: "value1", Kind: TimeKind}},
+			}}),
+			diff: SchemaDiff{
+				ChangedObjectTypes: []ObjectTypeDiff{
+					{
+						Name: "object1",
+						ValueFieldsDiff: FieldsDiff{
+							Changed: []FieldDiff{
+								{
+									Name:     "value1",
+									OldField: Field{Name: "value1", Kind: TimeKind, TimeResolution: TimeResolutionSeconds},
+									NewField: Field{Name: "value1", Kind: TimeKind},
+								},
+							},
+						},
+					},
+				},
+			},
+			isCompatible: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffModuleSchemas(tt.oldSchema, tt.newSchema)
+			if !reflect.DeepEqual(got, tt.diff) {
+				t.Errorf("DiffModuleSchemas() = %v, want %v", got, tt.diff)
+			}
+
+			if got.Empty() != reflect.DeepEqual(tt.diff, SchemaDiff{}) {
+				t.Errorf("expected Empty() to be %t", !got.Empty())
+			}
+
+			if got.IsCompatible() != tt.isCompatible {
+				t.Errorf("expected IsCompatible() to be %t", tt.isCompatible)
+			}
+		})
+	}
+}
+
+func requireReservedTypeNames(t *testing.T, moduleSchema ModuleSchema, names ...string) ModuleSchema {
+	t.Helper()
+	res, err := moduleSchema.WithReservedTypeNames(names...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func requireEventTypes(t *testing.T, moduleSchema ModuleSchema, eventTypes ...EventType) ModuleSchema {
+	t.Helper()
+	res, err := moduleSchema.WithEventTypes(eventTypes...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
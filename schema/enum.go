@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 )
@@ -11,11 +12,25 @@ type EnumType struct {
 	// Its name must be unique between all enum types and object types in the module.
 	// The same enum, however, can be used in multiple object types and fields as long as the
 	// definition is identical each time
-	Name string
+	Name string `json:"name"`
 
 	// Values is a list of distinct, non-empty values that are part of the enum type.
 	// Each value must conform to the NameFormat regular expression.
-	Values []string
+	Values []string `json:"values"`
+
+	// NumericValues is an optional list of numeric values for the enum values which can be used to map
+	// protobuf enums losslessly. If set, it must have the same length as Values, with NumericValues[i]
+	// being the number of Values[i], and the numbers must be unique. One of the numbers must be 0, which is
+	// the default value of protobuf enums. If it is not set, the numeric value of each enum value is its
+	// index in Values, so the first value is the default.
+	NumericValues []int32 `json:"numeric_values,omitempty"`
+
+	// Description is an optional human-readable description of the enum type.
+	Description string `json:"description,omitempty"`
+
+	// Metadata is an optional set of key-value pairs which tools can use to attach additional
+	// information to the enum type. Keys cannot be empty.
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // TypeName implements the Type interface.
@@ -45,6 +60,44 @@ func (e EnumType) Validate() error {
 		}
 		seen[v] = true
 	}
+
+	if len(e.NumericValues) != 0 {
+		if len(e.NumericValues) != len(e.Values) {
+			return fmt.Errorf("enum %s has %d values but %d numeric values", e.Name, len(e.Values), len(e.NumericValues))
+		}
+
+		seenNumeric := make(map[int32]bool, len(e.NumericValues))
+		for i, n := range e.NumericValues {
+			if seenNumeric[n] {
+				return fmt.Errorf("duplicate numeric value %d for enum value %q in enum %s", n, e.Values[i], e.Name)
+			}
+			seenNumeric[n] = true
+		}
+		if !seenNumeric[0] {
+			return fmt.Errorf("enum %s has no value with the numeric value 0, which is the default value", e.Name)
+		}
+	}
+
+	if err := validateMetadata(e.Metadata); err != nil {
+		return fmt.Errorf("invalid metadata for enum %s: %v", e.Name, err) //nolint:errorlint // false positive due to using go1.12
+	}
+
+	return nil
+}
+
+// UnmarshalJSON unmarshals and validates the enum definition.
+func (e *EnumType) UnmarshalJSON(data []byte) error {
+	type enumTypeJSON EnumType
+	var res enumTypeJSON
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+
+	if err := EnumType(res).Validate(); err != nil {
+		return err
+	}
+
+	*e = EnumType(res)
 	return nil
 }
 
@@ -57,3 +110,35 @@ func (e EnumType) ValidateValue(value string) error {
 	}
 	return fmt.Errorf("value %q is not a valid enum value for %s", value, e.Name)
 }
+
+// NumericValue returns the numeric value of the enum value with the provided name and false if the
+// name is not a valid value of the enum.
+func (e EnumType) NumericValue(name string) (int32, bool) {
+	for i, v := range e.Values {
+		if v == name {
+			if len(e.NumericValues) == 0 {
+				return int32(i), true
+			}
+			return e.NumericValues[i], true
+		}
+	}
+	return 0, false
+}
+
+// ValueForNumber returns the name of the enum value with the provided numeric value and false if
+// no enum value has that number.
+func (e EnumType) ValueForNumber(n int32) (string, bool) {
+	if len(e.NumericValues) == 0 {
+		if n < 0 || int(n) >= len(e.Values) {
+			return "", false
+		}
+		return e.Values[n], true
+	}
+
+	for i, v := range e.NumericValues {
+		if v == n {
+			return e.Values[i], true
+		}
+	}
+	return "", false
+}
@@ -19,6 +19,61 @@ func TestEnumDefinition_Validate(t *testing.T) {
 			},
 			errContains: "",
 		},
+		{
+			name: "valid enum with documentation",
+			enum: EnumType{
+				Name:        "test",
+				Values:      []string{"a", "b", "c"},
+				Description: "a test enum",
+				Metadata:    map[string]string{"source": "test.proto"},
+			},
+			errContains: "",
+		},
+		{
+			name: "valid numeric values",
+			enum: EnumType{
+				Name:          "test",
+				Values:        []string{"unspecified", "yes", "no"},
+				NumericValues: []int32{0, 1, 3},
+			},
+			errContains: "",
+		},
+		{
+			name: "numeric values length mismatch",
+			enum: EnumType{
+				Name:          "test",
+				Values:        []string{"a", "b", "c"},
+				NumericValues: []int32{0, 1},
+			},
+			errContains: "enum test has 3 values but 2 numeric values",
+		},
+		{
+			name: "duplicate numeric value",
+			enum: EnumType{
+				Name:          "test",
+				Values:        []string{"a", "b", "c"},
+				NumericValues: []int32{0, 1, 1},
+			},
+			errContains: "duplicate numeric value 1 for enum value \"c\" in enum test",
+		},
+		{
+			name: "no default numeric value",
+			enum: EnumType{
+				Name:          "test",
+				Values:        []string{"yes", "no"},
+				NumericValues: []int32{1, 2},
+			},
+			errContains: "enum test has no value with the numeric value 0",
+		},
+		{
+			name: "empty metadata key",
+			enum: EnumType{
+				Name:     "test",
+				Values:   []string{"a", "b", "c"},
+				Metadata: map[string]string{"": "x"},
+			},
+			errContains: "invalid metadata for enum test: metadata keys cannot be empty",
+		},
 		{
 			name: "empty name",
 			enum: EnumType{
@@ -104,3 +159,57 @@ func TestEnumDefinition_ValidateValue(t *testing.T) {
 		})
 	}
 }
+
+func TestEnumType_NumericValues(t *testing.T) {
+	tests := []struct {
+		name string
+		enum EnumType
+		// values maps each enum value to its expected number
+		values map[string]int32
+		// invalidNumbers are numbers which should not map to any value
+		invalidNumbers []int32
+	}{
+		{
+			name:           "implicit numeric values",
+			enum:           EnumType{Name: "test", Values: []string{"a", "b", "c"}},
+			values:         map[string]int32{"a": 0, "b": 1, "c": 2},
+			invalidNumbers: []int32{-1, 3},
+		},
+		{
+			name: "explicit numeric values",
+			enum: EnumType{
+				Name:          "test",
+				Values:        []string{"unspecified", "yes", "no"},
+				NumericValues: []int32{0, 1, -3},
+			},
+			values:         map[string]int32{"unspecified": 0, "yes": 1, "no": -3},
+			invalidNumbers: []int32{2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for value, number := range tt.values {
+				n, ok := tt.enum.NumericValue(value)
+				if !ok || n != number {
+					t.Errorf("expected NumericValue(%q) to be %d, got %d, %t", value, number, n, ok)
+				}
+
+				v, ok := tt.enum.ValueForNumber(number)
+				if !ok || v != value {
+					t.Errorf("expected ValueForNumber(%d) to be %q, got %q, %t", number, value, v, ok)
+				}
+			}
+
+			if _, ok := tt.enum.NumericValue("foo"); ok {
+				t.Errorf("expected NumericValue(\"foo\") to fail")
+			}
+
+			for _, number := range tt.invalidNumbers {
+				if _, ok := tt.enum.ValueForNumber(number); ok {
+					t.Errorf("expected ValueForNumber(%d) to fail", number)
+				}
+			}
+		})
+	}
+}
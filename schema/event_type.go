@@ -0,0 +1,94 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// EventType describes the typed attributes of an event type emitted by a module so that indexers do not
+// need to parse attribute values from strings. Event types are registered in a module schema with
+// ModuleSchema.WithEventTypes.
+type EventType struct {
+	// Name is the name of the event type. It must conform to the NameFormat regular expression and be
+	// unique amongst all the types in the module schema. It usually corresponds to the event's type string.
+	Name string `json:"name"`
+
+	// Fields are the attributes of the event type. Field names correspond to attribute keys and must be
+	// unique within the event type. Attributes which are not always emitted should be nullable.
+	Fields []Field `json:"fields"`
+
+	// Description is an optional human-readable description of the event type.
+	Description string `json:"description,omitempty"`
+}
+
+// TypeName implements the Type interface.
+func (e EventType) TypeName() string {
+	return e.Name
+}
+
+func (EventType) isType() {}
+
+// Validate validates the event type.
+func (e EventType) Validate() error {
+	return e.validate(map[string]Type{})
+}
+
+// validate validates the event type and adds any enum and struct types referenced by its fields to types.
+func (e EventType) validate(types map[string]Type) error {
+	if !ValidateName(e.Name) {
+		return fmt.Errorf("invalid event type name %q", e.Name)
+	}
+
+	if len(e.Fields) == 0 {
+		return fmt.Errorf("event type %q has no fields", e.Name)
+	}
+
+	fieldNames := map[string]bool{}
+	for _, field := range e.Fields {
+		if err := field.Validate(); err != nil {
+			return fmt.Errorf("invalid field %q in event type %q: %v", field.Name, e.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+
+		if fieldNames[field.Name] {
+			return fmt.Errorf("duplicate field name %q in event type %q", field.Name, e.Name)
+		}
+		fieldNames[field.Name] = true
+
+		if err := addFieldTypes(types, field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalJSON unmarshals and validates the event type.
+func (e *EventType) UnmarshalJSON(data []byte) error {
+	type eventTypeJSON EventType
+	var res eventTypeJSON
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+
+	if err := EventType(res).Validate(); err != nil {
+		return err
+	}
+
+	*e = EventType(res)
+	return nil
+}
+
+// ValidateAttribute validates that the attribute with the given key is a field of the event type and that
+// the value is valid for the field.
+func (e EventType) ValidateAttribute(key string, value interface{}) error {
+	for _, field := range e.Fields {
+		if field.Name == key {
+			if err := field.ValidateValue(value); err != nil {
+				return fmt.Errorf("invalid attribute %q of event type %q: %v", key, e.Name, err) //nolint:errorlint // false positive due to using go1.12
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown attribute %q of event type %q", key, e.Name)
+}
@@ -0,0 +1,128 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var testTransferEvent = EventType{
+	Name: "transfer",
+	Fields: []Field{
+		{Name: "sender", Kind: AddressKind},
+		{Name: "recipient", Kind: AddressKind},
+		{Name: "amount", Kind: IntegerStringKind},
+		{Name: "memo", Kind: StringKind, Nullable: true},
+	},
+}
+
+func TestEventType_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		eventType   EventType
+		errContains string
+	}{
+		{
+			name:      "valid event type",
+			eventType: testTransferEvent,
+		},
+		{
+			name:        "invalid name",
+			eventType:   EventType{Name: "1transfer", Fields: testTransferEvent.Fields},
+			errContains: "invalid event type name \"1transfer\"",
+		},
+		{
+			name:        "no fields",
+			eventType:   EventType{Name: "transfer"},
+			errContains: "event type \"transfer\" has no fields",
+		},
+		{
+			name: "invalid field",
+			eventType: EventType{Name: "transfer", Fields: []Field{
+				{Name: "amount", Kind: ListKind},
+			}},
+			errContains: "invalid field \"amount\" in event type \"transfer\"",
+		},
+		{
+			name: "duplicate field",
+			eventType: EventType{Name: "transfer", Fields: []Field{
+				{Name: "amount", Kind: StringKind},
+				{Name: "amount", Kind: StringKind},
+			}},
+			errContains: "duplicate field name \"amount\" in event type \"transfer\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.eventType.Validate()
+			if tt.errContains == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error to contain %q, got: %v", tt.errContains, err)
+			}
+
+			bz, err := json.Marshal(tt.eventType)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var decoded EventType
+			err = json.Unmarshal(bz, &decoded)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else if err == nil {
+				t.Fatalf("expected unmarshal error")
+			}
+		})
+	}
+}
+
+func TestEventType_ValidateAttribute(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		value       interface{}
+		errContains string
+	}{
+		{
+			name:  "valid attribute",
+			key:   "amount",
+			value: "100",
+		},
+		{
+			name:  "null nullable attribute",
+			key:   "memo",
+			value: nil,
+		},
+		{
+			name:        "invalid value",
+			key:         "amount",
+			value:       int64(100),
+			errContains: "invalid attribute \"amount\" of event type \"transfer\"",
+		},
+		{
+			name:        "unknown attribute",
+			key:         "fee",
+			value:       "1",
+			errContains: "unknown attribute \"fee\" of event type \"transfer\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testTransferEvent.ValidateAttribute(tt.key, tt.value)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error to contain %q, got: %v", tt.errContains, err)
+			}
+		})
+	}
+}
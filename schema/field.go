@@ -1,6 +1,12 @@
 package schema
 
-import "fmt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
 
 // Field represents a field in an object type.
 type Field struct {
@@ -10,43 +16,343 @@ type Field struct {
 	// Kind is the basic type of the field.
 	Kind Kind
 
+	// ElementKind is the kind of the elements of the list and is only valid when Kind is ListKind.
+	// It cannot itself be ListKind or MapKind. If ElementKind is EnumKind, StructKind or OneOfKind, the
+	// EnumType, StructType or OneOfType field must be set to the definition of the element type.
+	ElementKind Kind
+
+	// KeyKind is the kind of the keys of the map and is only valid when Kind is MapKind.
+	// See Kind.IsValidMapKeyKind for the kinds which can be used as map keys.
+	KeyKind Kind
+
+	// ValueKind is the kind of the values of the map and is only valid when Kind is MapKind.
+	// It cannot itself be ListKind or MapKind. If ValueKind is EnumKind, StructKind or OneOfKind, the
+	// EnumType, StructType or OneOfType field must be set to the definition of the value type.
+	ValueKind Kind
+
 	// Nullable indicates whether null values are accepted for the field. Key fields CANNOT be nullable.
 	Nullable bool
 
+	// NullableElements indicates whether null values are accepted for the elements of a ListKind field
+	// or the values of a MapKind field. It is only valid for list and map fields.
+	NullableElements bool
+
+	// Default is the value which indexers should use for the field when an object is created from an
+	// ObjectUpdate whose Value is a ValueUpdates that omits the field. If set, it must be a valid value
+	// for the field. Defaults are not supported for key fields or fields of kind StructKind, ListKind,
+	// MapKind or OneOfKind. See FillObjectValueDefaults.
+	Default interface{}
+
+	// Precision is the maximum total number of significant digits of values of a DecimalStringKind
+	// field (or list elements or map values of DecimalStringKind). Zero means that the precision
+	// is unconstrained and values may use the full range allowed by DecimalFormat. It is only valid
+	// for decimal fields and may be at most MaxDecimalPrecision.
+	Precision uint32
+
+	// Scale is the maximum number of digits after the decimal point of values of a DecimalStringKind
+	// field. It can only be set when Precision is set and cannot be greater than Precision. Values
+	// can therefore have at most Precision - Scale digits before the decimal point.
+	Scale uint32
+
+	// TimeResolution is the resolution of the values of a TimeKind field (or list elements or map
+	// values of TimeKind). Values which are more precise than the resolution are invalid. It is only
+	// valid for time fields and defaults to TimeResolutionNanos.
+	TimeResolution TimeResolution
+
+	// Constraints are optional constraints on the values of the field (or list elements or map values),
+	// such as bounds for numeric kinds, a maximum length and pattern for strings and a fixed size for
+	// bytes. See FieldConstraints.
+	Constraints FieldConstraints
+
+	// AddressPrefix is the bech32 human-readable part, such as "cosmos" or "cosmosvaloper", of the values of
+	// an AddressKind field (or list elements or map values of AddressKind). It declares how the addresses
+	// are rendered, so that indexers of several chains can use the correct prefix for each field, and
+	// Bech32AddressCodec rejects strings with a different prefix. See Field.AddressCodec. It is only valid for
+	// address fields and is optional.
+	AddressPrefix string
+
 	// EnumType is the definition of the enum type and is only valid when Kind is EnumKind.
 	// The same enum types can be reused in the same module schema, but they always must contain
 	// the same values for the same enum name. This possibly introduces some duplication of
 	// definitions but makes it easier to reason about correctness and validation in isolation.
 	EnumType EnumType
+
+	// StructType is the definition of the struct type and is only valid when Kind is StructKind.
+	// Like enum types, the same struct types can be reused in the same module schema, but they
+	// always must have the same definition for the same struct name.
+	StructType StructType
+
+	// OneOfType is the definition of the oneof type and is only valid when Kind is OneOfKind.
+	// Like struct types, the same oneof types can be reused in the same module schema, but they
+	// always must have the same definition for the same oneof name.
+	OneOfType OneOfType
+
+	// Description is an optional human-readable description of the field for use in indexer UIs
+	// and generated API documentation.
+	Description string
+
+	// Metadata is an optional set of key-value pairs which tools can use to attach additional
+	// information to the field. Keys cannot be empty.
+	Metadata map[string]string
 }
 
 // Validate validates the field.
 func (c Field) Validate() error {
+	return c.validate(nil)
+}
+
+// validate validates the field where parents is the list of struct types which contain
+// this field and is used for cycle detection.
+func (c Field) validate(parents []string) error {
 	// valid name
 	if !ValidateName(c.Name) {
 		return fmt.Errorf("invalid field name %q", c.Name)
 	}
 
+	if err := validateMetadata(c.Metadata); err != nil {
+		return fmt.Errorf("invalid metadata for field %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
+	}
+
 	// valid kind
 	if err := c.Kind.Validate(); err != nil {
 		return fmt.Errorf("invalid field kind for %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
 	}
 
+	// element kind only valid with ListKind
+	if c.Kind == ListKind {
+		if err := c.ElementKind.Validate(); err != nil {
+			return fmt.Errorf("invalid element kind for %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+
+		if c.ElementKind == ListKind || c.ElementKind == MapKind {
+			return fmt.Errorf("nested lists and maps are not supported for field %q", c.Name)
+		}
+	} else if c.ElementKind != InvalidKind {
+		return fmt.Errorf("element kind is only valid for field %q with type ListKind", c.Name)
+	}
+
+	// key and value kinds only valid with MapKind
+	if c.Kind == MapKind {
+		if !c.KeyKind.IsValidMapKeyKind() {
+			return fmt.Errorf("invalid map key kind %s for %q", c.KeyKind, c.Name)
+		}
+
+		if err := c.ValueKind.Validate(); err != nil {
+			return fmt.Errorf("invalid map value kind for %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+
+		if c.ValueKind == ListKind || c.ValueKind == MapKind {
+			return fmt.Errorf("nested lists and maps are not supported for field %q", c.Name)
+		}
+	} else if c.KeyKind != InvalidKind || c.ValueKind != InvalidKind {
+		return fmt.Errorf("key and value kinds are only valid for field %q with type MapKind", c.Name)
+	}
+
+	if c.NullableElements && c.Kind != ListKind && c.Kind != MapKind {
+		return fmt.Errorf("nullable elements are only valid for field %q with type ListKind or MapKind", c.Name)
+	}
+
+	kind := c.typeKind()
+
+	// precision and scale only valid with DecimalStringKind
+	if kind == DecimalStringKind {
+		if c.Precision > MaxDecimalPrecision {
+			return fmt.Errorf("precision %d for field %q exceeds the maximum of %d", c.Precision, c.Name, MaxDecimalPrecision)
+		}
+
+		if c.Scale > c.Precision {
+			return fmt.Errorf("scale %d for field %q cannot be greater than precision %d", c.Scale, c.Name, c.Precision)
+		}
+	} else if c.Precision != 0 || c.Scale != 0 {
+		return fmt.Errorf("precision and scale are only valid for field %q with type DecimalStringKind", c.Name)
+	}
+
+	// time resolution only valid with TimeKind
+	if kind == TimeKind {
+		if err := c.TimeResolution.Validate(); err != nil {
+			return fmt.Errorf("invalid time resolution for field %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+	} else if c.TimeResolution != TimeResolutionNanos {
+		return fmt.Errorf("time resolution is only valid for field %q with type TimeKind", c.Name)
+	}
+
+	// address prefix only valid with AddressKind
+	if kind == AddressKind {
+		if c.AddressPrefix != "" {
+			if err := validateAddressPrefix(c.AddressPrefix); err != nil {
+				return fmt.Errorf("invalid address prefix for field %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
+			}
+		}
+	} else if c.AddressPrefix != "" {
+		return fmt.Errorf("address prefix is only valid for field %q with type AddressKind", c.Name)
+	}
+
+	if err := c.Constraints.validate(kind); err != nil {
+		return fmt.Errorf("invalid constraints for field %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
+	}
+
 	// enum definition only valid with EnumKind
-	if c.Kind == EnumKind {
+	if kind == EnumKind {
 		if err := c.EnumType.Validate(); err != nil {
 			return fmt.Errorf("invalid enum definition for field %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
 		}
-	} else if c.Kind != EnumKind && (c.EnumType.Name != "" || c.EnumType.Values != nil) {
+	} else if c.EnumType.Name != "" || c.EnumType.Values != nil {
 		return fmt.Errorf("enum definition is only valid for field %q with type EnumKind", c.Name)
 	}
 
+	// struct definition only valid with StructKind
+	if kind == StructKind {
+		if err := c.StructType.validate(parents); err != nil {
+			return fmt.Errorf("invalid struct definition for field %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+	} else if c.StructType.Name != "" || c.StructType.Fields != nil {
+		return fmt.Errorf("struct definition is only valid for field %q with type StructKind", c.Name)
+	}
+
+	// oneof definition only valid with OneOfKind
+	if kind == OneOfKind {
+		if err := c.OneOfType.validate(parents); err != nil {
+			return fmt.Errorf("invalid oneof definition for field %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+	} else if c.OneOfType.Name != "" || c.OneOfType.Cases != nil {
+		return fmt.Errorf("oneof definition is only valid for field %q with type OneOfKind", c.Name)
+	}
+
+	if c.Default != nil {
+		if c.Kind == StructKind || c.Kind == ListKind || c.Kind == MapKind || c.Kind == OneOfKind {
+			return fmt.Errorf("default values are not supported for field %q of kind %s", c.Name, c.Kind)
+		}
+
+		if err := c.ValidateValue(c.Default); err != nil {
+			return fmt.Errorf("invalid default value for field %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+	}
+
+	return nil
+}
+
+// fieldJSON is the JSON representation of a Field which omits unset kinds and type definitions.
+type fieldJSON struct {
+	Name             string            `json:"name"`
+	Kind             Kind              `json:"kind"`
+	ElementKind      Kind              `json:"element_kind,omitempty"`
+	KeyKind          Kind              `json:"key_kind,omitempty"`
+	ValueKind        Kind              `json:"value_kind,omitempty"`
+	Nullable         bool              `json:"nullable,omitempty"`
+	NullableElements bool              `json:"nullable_elements,omitempty"`
+	Default          json.RawMessage   `json:"default,omitempty"`
+	Precision        uint32            `json:"precision,omitempty"`
+	Scale            uint32            `json:"scale,omitempty"`
+	TimeResolution   TimeResolution    `json:"time_resolution,omitempty"`
+	Constraints      *FieldConstraints `json:"constraints,omitempty"`
+	AddressPrefix    string            `json:"address_prefix,omitempty"`
+	EnumType         *EnumType         `json:"enum_type,omitempty"`
+	StructType       *StructType       `json:"struct_type,omitempty"`
+	OneOfType        *OneOfType        `json:"oneof_type,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// MarshalJSON marshals the field to JSON, omitting any kinds or type definitions which are not set.
+func (c Field) MarshalJSON() ([]byte, error) {
+	res := fieldJSON{
+		Name:             c.Name,
+		Kind:             c.Kind,
+		ElementKind:      c.ElementKind,
+		KeyKind:          c.KeyKind,
+		ValueKind:        c.ValueKind,
+		Nullable:         c.Nullable,
+		NullableElements: c.NullableElements,
+		Precision:        c.Precision,
+		Scale:            c.Scale,
+		TimeResolution:   c.TimeResolution,
+		AddressPrefix:    c.AddressPrefix,
+		Description:      c.Description,
+		Metadata:         c.Metadata,
+	}
+
+	if c.Default != nil {
+		bz, err := json.Marshal(c.Default)
+		if err != nil {
+			return nil, fmt.Errorf("can't marshal default value for field %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		res.Default = bz
+	}
+
+	if !c.Constraints.IsZero() {
+		res.Constraints = &c.Constraints
+	}
+
+	switch c.typeKind() {
+	case EnumKind:
+		res.EnumType = &c.EnumType
+	case StructKind:
+		res.StructType = &c.StructType
+	case OneOfKind:
+		res.OneOfType = &c.OneOfType
+	}
+
+	return json.Marshal(res)
+}
+
+// UnmarshalJSON unmarshals and validates the field.
+func (c *Field) UnmarshalJSON(data []byte) error {
+	var res fieldJSON
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+
+	field := Field{
+		Name:             res.Name,
+		Kind:             res.Kind,
+		ElementKind:      res.ElementKind,
+		KeyKind:          res.KeyKind,
+		ValueKind:        res.ValueKind,
+		Nullable:         res.Nullable,
+		NullableElements: res.NullableElements,
+		Precision:        res.Precision,
+		Scale:            res.Scale,
+		TimeResolution:   res.TimeResolution,
+		AddressPrefix:    res.AddressPrefix,
+		Description:      res.Description,
+		Metadata:         res.Metadata,
+	}
+	if res.Default != nil {
+		def, err := decodeJSONValue(res.Kind, res.Default)
+		if err != nil {
+			return fmt.Errorf("invalid default value for field %q: %v", res.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		field.Default = def
+	}
+	if res.Constraints != nil {
+		field.Constraints = *res.Constraints
+	}
+	if res.EnumType != nil {
+		field.EnumType = *res.EnumType
+	}
+	if res.StructType != nil {
+		field.StructType = *res.StructType
+	}
+	if res.OneOfType != nil {
+		field.OneOfType = *res.OneOfType
+	}
+
+	if err := field.Validate(); err != nil {
+		return err
+	}
+
+	*c = field
 	return nil
 }
 
-// ValidateValue validates that the value conforms to the field's kind and nullability.
-// Unlike Kind.ValidateValue, it also checks that the value conforms to the EnumType
-// if the field is an EnumKind.
+// ValidateValue validates that the value conforms to the field's kind and nullability. Unlike
+// Kind.ValidateValue, it also checks that:
+//   - EnumKind, StructKind and OneOfKind values conform to the EnumType, StructType or OneOfType,
+//   - DecimalStringKind values fit within the Precision and Scale,
+//   - TimeKind values conform to the TimeResolution,
+//   - values, or the elements and map values of ListKind and MapKind fields, satisfy the Constraints,
+//   - the elements of a ListKind value and the values of a MapKind value pass Kind.ValidateValue for the
+//     ElementKind or ValueKind and the checks above, and map keys pass Kind.ValidateValue for the KeyKind.
 func (c Field) ValidateValue(value interface{}) error {
 	if value == nil {
 		if !c.Nullable {
@@ -59,9 +365,162 @@ func (c Field) ValidateValue(value interface{}) error {
 		return fmt.Errorf("invalid value for field %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
 	}
 
-	if c.Kind == EnumKind {
+	if c.Kind != ListKind && c.Kind != MapKind {
+		if err := c.Constraints.validateValue(c.Kind, value); err != nil {
+			return fmt.Errorf("invalid value for field %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+	}
+
+	switch c.Kind {
+	case EnumKind:
 		return c.EnumType.ValidateValue(value.(string))
+	case StructKind:
+		return c.StructType.ValidateValue(value.([]interface{}))
+	case OneOfKind:
+		return c.OneOfType.ValidateValue(value.(OneOfValue))
+	case DecimalStringKind:
+		if err := c.validateDecimalValue(value.(string)); err != nil {
+			return fmt.Errorf("invalid value for field %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+	case TimeKind:
+		if err := c.TimeResolution.ValidateTime(value.(time.Time)); err != nil {
+			return fmt.Errorf("invalid value for field %q: %v", c.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+	case ListKind:
+		for i, elem := range value.([]interface{}) {
+			if err := c.validateNestedValue(c.ElementKind, elem); err != nil {
+				return fmt.Errorf("invalid element %d for field %q: %v", i, c.Name, err) //nolint:errorlint // false positive due to using go1.12
+			}
+		}
+	case MapKind:
+		for k, v := range value.(map[interface{}]interface{}) {
+			if err := c.KeyKind.ValidateValue(k); err != nil {
+				return fmt.Errorf("invalid map key %v for field %q: %v", k, c.Name, err) //nolint:errorlint // false positive due to using go1.12
+			}
+
+			if err := c.validateNestedValue(c.ValueKind, v); err != nil {
+				return fmt.Errorf("invalid map value for key %v for field %q: %v", k, c.Name, err) //nolint:errorlint // false positive due to using go1.12
+			}
+		}
 	}
 
 	return nil
 }
+
+// typeKind returns the kind which the field's EnumType, StructType or OneOfType describes. This is the
+// element kind for lists, the value kind for maps and the field kind otherwise.
+func (c Field) typeKind() Kind {
+	switch c.Kind {
+	case ListKind:
+		return c.ElementKind
+	case MapKind:
+		return c.ValueKind
+	default:
+		return c.Kind
+	}
+}
+
+// validateNestedValue validates that the value is a valid list element or map value of the given kind.
+func (c Field) validateNestedValue(kind Kind, value interface{}) error {
+	if value == nil {
+		if !c.NullableElements {
+			return errors.New("list elements and map values cannot be null")
+		}
+		return nil
+	}
+
+	err := kind.ValidateValue(value)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Constraints.validateValue(kind, value); err != nil {
+		return err
+	}
+
+	switch kind {
+	case EnumKind:
+		return c.EnumType.ValidateValue(value.(string))
+	case StructKind:
+		return c.StructType.ValidateValue(value.([]interface{}))
+	case OneOfKind:
+		return c.OneOfType.ValidateValue(value.(OneOfValue))
+	case DecimalStringKind:
+		return c.validateDecimalValue(value.(string))
+	case TimeKind:
+		return c.TimeResolution.ValidateTime(value.(time.Time))
+	}
+
+	return nil
+}
+
+// validateDecimalValue checks that the decimal value fits within the field's Precision and Scale.
+func (c Field) validateDecimalValue(value string) error {
+	if c.Precision == 0 {
+		return nil
+	}
+
+	if !decimalRegex.MatchString(value) {
+		return fmt.Errorf("expected decimal number, got %s", value)
+	}
+
+	intDigits, fracDigits := decimalDigits(value)
+	if fracDigits > int(c.Scale) {
+		return fmt.Errorf("decimal %s has %d digits after the decimal point, scale is %d", value, fracDigits, c.Scale)
+	}
+
+	if intDigits > int(c.Precision-c.Scale) {
+		return fmt.Errorf("decimal %s has %d digits before the decimal point, at most %d are allowed with precision %d and scale %d",
+			value, intDigits, c.Precision-c.Scale, c.Precision, c.Scale)
+	}
+
+	return nil
+}
+
+// decodeJSONValue decodes a JSON value produced by json.Marshal into the go type used for values of the
+// kind. It is only used for default values and therefore does not support struct, list, map or oneof kinds.
+func decodeJSONValue(kind Kind, data json.RawMessage) (interface{}, error) {
+	var ptr interface{}
+	switch kind {
+	case StringKind, IntegerStringKind, DecimalStringKind, EnumKind:
+		ptr = new(string)
+	case BytesKind, AddressKind:
+		ptr = new([]byte)
+	case Int8Kind:
+		ptr = new(int8)
+	case Uint8Kind:
+		ptr = new(uint8)
+	case Int16Kind:
+		ptr = new(int16)
+	case Uint16Kind:
+		ptr = new(uint16)
+	case Int32Kind:
+		ptr = new(int32)
+	case Uint32Kind:
+		ptr = new(uint32)
+	case Int64Kind:
+		ptr = new(int64)
+	case Uint64Kind:
+		ptr = new(uint64)
+	case BoolKind:
+		ptr = new(bool)
+	case TimeKind:
+		ptr = new(time.Time)
+	case DurationKind:
+		ptr = new(time.Duration)
+	case Float32Kind:
+		ptr = new(float32)
+	case Float64Kind:
+		ptr = new(float64)
+	case JSONKind:
+		ptr = new(json.RawMessage)
+	default:
+		return nil, fmt.Errorf("can't decode JSON value of kind %s", kind)
+	}
+
+	if err := json.Unmarshal(data, ptr); err != nil {
+		return nil, err
+	}
+
+	return reflect.ValueOf(ptr).Elem().Interface(), nil
+}
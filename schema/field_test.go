@@ -1,8 +1,12 @@
 package schema
 
 import (
+	"encoding/json"
+	"math"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestField_Validate(t *testing.T) {
@@ -52,6 +56,86 @@ func TestField_Validate(t *testing.T) {
 			},
 			errContains: "enum definition is only valid for field \"field1\" with type EnumKind",
 		},
+		{
+			name: "valid struct",
+			field: Field{
+				Name:       "field1",
+				Kind:       StructKind,
+				StructType: testPointStruct,
+			},
+			errContains: "",
+		},
+		{
+			name: "invalid struct definition",
+			field: Field{
+				Name: "field1",
+				Kind: StructKind,
+			},
+			errContains: "invalid struct definition",
+		},
+		{
+			name: "struct definition with non-StructKind",
+			field: Field{
+				Name:       "field1",
+				Kind:       StringKind,
+				StructType: testPointStruct,
+			},
+			errContains: "struct definition is only valid for field \"field1\" with type StructKind",
+		},
+		{
+			name: "valid list",
+			field: Field{
+				Name:        "field1",
+				Kind:        ListKind,
+				ElementKind: StringKind,
+			},
+			errContains: "",
+		},
+		{
+			name: "valid list of enums",
+			field: Field{
+				Name:        "field1",
+				Kind:        ListKind,
+				ElementKind: EnumKind,
+				EnumType:    EnumType{Name: "enum", Values: []string{"a", "b"}},
+			},
+			errContains: "",
+		},
+		{
+			name: "list without element kind",
+			field: Field{
+				Name: "field1",
+				Kind: ListKind,
+			},
+			errContains: "invalid element kind",
+		},
+		{
+			name: "nested list",
+			field: Field{
+				Name:        "field1",
+				Kind:        ListKind,
+				ElementKind: ListKind,
+			},
+			errContains: "nested lists and maps are not supported",
+		},
+		{
+			name: "element kind with non-ListKind",
+			field: Field{
+				Name:        "field1",
+				Kind:        StringKind,
+				ElementKind: StringKind,
+			},
+			errContains: "element kind is only valid for field \"field1\" with type ListKind",
+		},
+		{
+			name: "list of enums without enum definition",
+			field: Field{
+				Name:        "field1",
+				Kind:        ListKind,
+				ElementKind: EnumKind,
+			},
+			errContains: "invalid enum definition",
+		},
 		{
 			name: "valid enum",
 			field: Field{
@@ -60,6 +144,224 @@ func TestField_Validate(t *testing.T) {
 				EnumType: EnumType{Name: "enum", Values: []string{"a", "b"}},
 			},
 		},
+		{
+			name: "valid decimal precision and scale",
+			field: Field{
+				Name:      "field1",
+				Kind:      DecimalStringKind,
+				Precision: 36,
+				Scale:     18,
+			},
+		},
+		{
+			name: "valid list of decimals with precision",
+			field: Field{
+				Name:        "field1",
+				Kind:        ListKind,
+				ElementKind: DecimalStringKind,
+				Precision:   10,
+			},
+		},
+		{
+			name: "decimal precision too large",
+			field: Field{
+				Name:      "field1",
+				Kind:      DecimalStringKind,
+				Precision: MaxDecimalPrecision + 1,
+			},
+			errContains: "exceeds the maximum",
+		},
+		{
+			name: "decimal scale greater than precision",
+			field: Field{
+				Name:      "field1",
+				Kind:      DecimalStringKind,
+				Precision: 10,
+				Scale:     11,
+			},
+			errContains: "cannot be greater than precision",
+		},
+		{
+			name: "decimal scale without precision",
+			field: Field{
+				Name:  "field1",
+				Kind:  DecimalStringKind,
+				Scale: 2,
+			},
+			errContains: "cannot be greater than precision",
+		},
+		{
+			name: "precision on non-decimal field",
+			field: Field{
+				Name:      "field1",
+				Kind:      IntegerStringKind,
+				Precision: 10,
+			},
+			errContains: "precision and scale are only valid for field \"field1\" with type DecimalStringKind",
+		},
+		{
+			name: "valid time resolution",
+			field: Field{
+				Name:           "field1",
+				Kind:           TimeKind,
+				TimeResolution: TimeResolutionSeconds,
+			},
+		},
+		{
+			name: "invalid time resolution",
+			field: Field{
+				Name:           "field1",
+				Kind:           TimeKind,
+				TimeResolution: TimeResolution(5),
+			},
+			errContains: "invalid time resolution for field \"field1\": invalid time resolution: 5",
+		},
+		{
+			name: "time resolution on non-time field",
+			field: Field{
+				Name:           "field1",
+				Kind:           Int64Kind,
+				TimeResolution: TimeResolutionMillis,
+			},
+			errContains: "time resolution is only valid for field \"field1\" with type TimeKind",
+		},
+		{
+			name: "valid list with nullable elements",
+			field: Field{
+				Name:             "field1",
+				Kind:             ListKind,
+				ElementKind:      StringKind,
+				NullableElements: true,
+			},
+		},
+		{
+			name: "nullable elements on non-list field",
+			field: Field{
+				Name:             "field1",
+				Kind:             StringKind,
+				NullableElements: true,
+			},
+			errContains: "nullable elements are only valid for field \"field1\" with type ListKind or MapKind",
+		},
+		{
+			name: "empty metadata key",
+			field: Field{
+				Name:     "field1",
+				Kind:     StringKind,
+				Metadata: map[string]string{"": "x"},
+			},
+			errContains: "invalid metadata for field \"field1\"",
+		},
+		{
+			name: "valid default",
+			field: Field{
+				Name:    "field1",
+				Kind:    Int64Kind,
+				Default: int64(10),
+			},
+		},
+		{
+			name: "valid enum default",
+			field: Field{
+				Name:     "field1",
+				Kind:     EnumKind,
+				EnumType: EnumType{Name: "enum", Values: []string{"a", "b"}},
+				Default:  "b",
+			},
+		},
+		{
+			name: "default with wrong type",
+			field: Field{
+				Name:    "field1",
+				Kind:    Int64Kind,
+				Default: int32(10),
+			},
+			errContains: "invalid default value for field \"field1\"",
+		},
+		{
+			name: "default not in enum",
+			field: Field{
+				Name:     "field1",
+				Kind:     EnumKind,
+				EnumType: EnumType{Name: "enum", Values: []string{"a", "b"}},
+				Default:  "c",
+			},
+			errContains: "invalid default value for field \"field1\"",
+		},
+		{
+			name: "default for list field",
+			field: Field{
+				Name:        "field1",
+				Kind:        ListKind,
+				ElementKind: StringKind,
+				Default:     []interface{}{"a"},
+			},
+			errContains: "default values are not supported for field \"field1\" of kind list",
+		},
+		{
+			name: "valid constraints",
+			field: Field{
+				Name:        "field1",
+				Kind:        ListKind,
+				ElementKind: DecimalStringKind,
+				Constraints: FieldConstraints{Min: "-1.5", Max: "1e3"},
+			},
+		},
+		{
+			name: "min greater than max",
+			field: Field{
+				Name:        "field1",
+				Kind:        Int32Kind,
+				Constraints: FieldConstraints{Min: "10", Max: "1"},
+			},
+			errContains: "invalid constraints for field \"field1\": min 10 is greater than max 1",
+		},
+		{
+			name: "decimal min for integer field",
+			field: Field{
+				Name:        "field1",
+				Kind:        Uint64Kind,
+				Constraints: FieldConstraints{Min: "0.5"},
+			},
+			errContains: "invalid min: expected base10 integer",
+		},
+		{
+			name: "max length for bytes field",
+			field: Field{
+				Name:        "field1",
+				Kind:        BytesKind,
+				Constraints: FieldConstraints{MaxLength: 10},
+			},
+			errContains: "max length and pattern are not valid for kind bytes",
+		},
+		{
+			name: "invalid pattern",
+			field: Field{
+				Name:        "field1",
+				Kind:        StringKind,
+				Constraints: FieldConstraints{Pattern: "[a-"},
+			},
+			errContains: "invalid pattern",
+		},
+		{
+			name: "size for string field",
+			field: Field{
+				Name:        "field1",
+				Kind:        StringKind,
+				Constraints: FieldConstraints{Size: 20},
+			},
+			errContains: "size is not valid for kind string",
+		},
+		{
+			name: "default violates constraints",
+			field: Field{
+				Name:        "field1",
+				Kind:        Int64Kind,
+				Default:     int64(-1),
+				Constraints: FieldConstraints{Min: "0"},
+			},
+			errContains: "invalid default value for field \"field1\"",
+		},
 	}
 
 	for _, tt := range tests {
@@ -145,6 +447,364 @@ func TestField_ValidateValue(t *testing.T) {
 			value:       "c",
 			errContains: "not a valid enum value",
 		},
+		{
+			name: "valid struct",
+			field: Field{
+				Name:       "field1",
+				Kind:       StructKind,
+				StructType: testPointStruct,
+			},
+			value:       []interface{}{int32(1), int32(2)},
+			errContains: "",
+		},
+		{
+			name: "invalid struct",
+			field: Field{
+				Name:       "field1",
+				Kind:       StructKind,
+				StructType: testPointStruct,
+			},
+			value:       []interface{}{int32(1)},
+			errContains: "expected 2 values for struct \"point\"",
+		},
+		{
+			name: "valid list",
+			field: Field{
+				Name:        "field1",
+				Kind:        ListKind,
+				ElementKind: StringKind,
+			},
+			value:       []interface{}{"a", "b"},
+			errContains: "",
+		},
+		{
+			name: "empty list",
+			field: Field{
+				Name:        "field1",
+				Kind:        ListKind,
+				ElementKind: StringKind,
+			},
+			value:       []interface{}{},
+			errContains: "",
+		},
+		{
+			name: "list with invalid element",
+			field: Field{
+				Name:        "field1",
+				Kind:        ListKind,
+				ElementKind: StringKind,
+			},
+			value:       []interface{}{"a", 1},
+			errContains: "invalid element 1 for field \"field1\"",
+		},
+		{
+			name: "list with invalid integer string element",
+			field: Field{
+				Name:        "field1",
+				Kind:        ListKind,
+				ElementKind: IntegerStringKind,
+			},
+			value:       []interface{}{"1", "1.5"},
+			errContains: "invalid element 1 for field \"field1\": expected base10 integer",
+		},
+		{
+			name: "list with null element",
+			field: Field{
+				Name:        "field1",
+				Kind:        ListKind,
+				ElementKind: StringKind,
+			},
+			value:       []interface{}{nil},
+			errContains: "list elements and map values cannot be null",
+		},
+		{
+			name: "list with invalid enum element",
+			field: Field{
+				Name:        "field1",
+				Kind:        ListKind,
+				ElementKind: EnumKind,
+				EnumType:    EnumType{Name: "enum", Values: []string{"a", "b"}},
+			},
+			value:       []interface{}{"a", "c"},
+			errContains: "not a valid enum value",
+		},
+		{
+			name: "list of structs",
+			field: Field{
+				Name:        "field1",
+				Kind:        ListKind,
+				ElementKind: StructKind,
+				StructType:  testPointStruct,
+			},
+			value:       []interface{}{[]interface{}{int32(1), int32(2)}, []interface{}{int32(3), int32(4)}},
+			errContains: "",
+		},
+		{
+			name: "valid map",
+			field: Field{
+				Name:      "field1",
+				Kind:      MapKind,
+				KeyKind:   StringKind,
+				ValueKind: IntegerStringKind,
+			},
+			value:       map[interface{}]interface{}{"uatom": "100", "stake": "2"},
+			errContains: "",
+		},
+		{
+			name: "map with invalid key",
+			field: Field{
+				Name:      "field1",
+				Kind:      MapKind,
+				KeyKind:   StringKind,
+				ValueKind: IntegerStringKind,
+			},
+			value:       map[interface{}]interface{}{1: "100"},
+			errContains: "invalid map key 1 for field \"field1\"",
+		},
+		{
+			name: "map with invalid value",
+			field: Field{
+				Name:      "field1",
+				Kind:      MapKind,
+				KeyKind:   StringKind,
+				ValueKind: IntegerStringKind,
+			},
+			value:       map[interface{}]interface{}{"uatom": 100},
+			errContains: "invalid map value for key uatom for field \"field1\"",
+		},
+		{
+			name: "map with null value",
+			field: Field{
+				Name:      "field1",
+				Kind:      MapKind,
+				KeyKind:   StringKind,
+				ValueKind: IntegerStringKind,
+			},
+			value:       map[interface{}]interface{}{"uatom": nil},
+			errContains: "map values cannot be null",
+		},
+		{
+			name: "unconstrained decimal",
+			field: Field{
+				Name: "field1",
+				Kind: DecimalStringKind,
+			},
+			value:       "123456789012345678901234567890.123456789012345678901234567890",
+			errContains: "",
+		},
+		{
+			name: "decimal within precision and scale",
+			field: Field{
+				Name:      "field1",
+				Kind:      DecimalStringKind,
+				Precision: 5,
+				Scale:     2,
+			},
+			value:       "-123.45",
+			errContains: "",
+		},
+		{
+			name: "decimal with insignificant zeros",
+			field: Field{
+				Name:      "field1",
+				Kind:      DecimalStringKind,
+				Precision: 5,
+				Scale:     2,
+			},
+			value:       "000123.4500",
+			errContains: "",
+		},
+		{
+			name: "decimal with too many fractional digits",
+			field: Field{
+				Name:      "field1",
+				Kind:      DecimalStringKind,
+				Precision: 5,
+				Scale:     2,
+			},
+			value:       "1.234",
+			errContains: "3 digits after the decimal point, scale is 2",
+		},
+		{
+			name: "decimal with too many integer digits",
+			field: Field{
+				Name:      "field1",
+				Kind:      DecimalStringKind,
+				Precision: 5,
+				Scale:     2,
+			},
+			value:       "1234",
+			errContains: "4 digits before the decimal point, at most 3 are allowed",
+		},
+		{
+			name: "decimal with exponent within precision",
+			field: Field{
+				Name:      "field1",
+				Kind:      DecimalStringKind,
+				Precision: 5,
+				Scale:     2,
+			},
+			value:       "1.23E2",
+			errContains: "",
+		},
+		{
+			name: "decimal with exponent exceeding scale",
+			field: Field{
+				Name:      "field1",
+				Kind:      DecimalStringKind,
+				Precision: 5,
+				Scale:     2,
+			},
+			value:       "1.5e-2",
+			errContains: "3 digits after the decimal point",
+		},
+		{
+			name: "invalid decimal with precision",
+			field: Field{
+				Name:      "field1",
+				Kind:      DecimalStringKind,
+				Precision: 5,
+			},
+			value:       "abc",
+			errContains: "expected decimal number",
+		},
+		{
+			name: "list of decimals exceeding precision",
+			field: Field{
+				Name:        "field1",
+				Kind:        ListKind,
+				ElementKind: DecimalStringKind,
+				Precision:   3,
+			},
+			value:       []interface{}{"100", "1000"},
+			errContains: "invalid element 1",
+		},
+		{
+			name:  "time with nanosecond resolution",
+			field: Field{Name: "field1", Kind: TimeKind},
+			value: time.Unix(1, 1),
+		},
+		{
+			name:  "time with millisecond resolution",
+			field: Field{Name: "field1", Kind: TimeKind, TimeResolution: TimeResolutionMillis},
+			value: time.Unix(1, int64(5*time.Millisecond)),
+		},
+		{
+			name:        "time exceeding millisecond resolution",
+			field:       Field{Name: "field1", Kind: TimeKind, TimeResolution: TimeResolutionMillis},
+			value:       time.Unix(1, int64(5*time.Microsecond)),
+			errContains: "is more precise than the time resolution millis",
+		},
+		{
+			name:        "list of times exceeding second resolution",
+			field:       Field{Name: "field1", Kind: ListKind, ElementKind: TimeKind, TimeResolution: TimeResolutionSeconds},
+			value:       []interface{}{time.Unix(1, 0), time.Unix(2, 1)},
+			errContains: "invalid element 1",
+		},
+		{
+			name: "list with nullable elements",
+			field: Field{
+				Name:             "field1",
+				Kind:             ListKind,
+				ElementKind:      StringKind,
+				NullableElements: true,
+			},
+			value:       []interface{}{"a", nil, "b"},
+			errContains: "",
+		},
+		{
+			name: "map with nullable values",
+			field: Field{
+				Name:             "field1",
+				Kind:             MapKind,
+				KeyKind:          StringKind,
+				ValueKind:        IntegerStringKind,
+				NullableElements: true,
+			},
+			value:       map[interface{}]interface{}{"uatom": nil},
+			errContains: "",
+		},
+		{
+			name: "duration",
+			field: Field{
+				Name: "field1",
+				Kind: DurationKind,
+			},
+			value:       time.Hour * 24 * 21,
+			errContains: "",
+		},
+		{
+			name:        "uint64 within bounds",
+			field:       Field{Name: "field1", Kind: Uint64Kind, Constraints: FieldConstraints{Min: "1", Max: "18446744073709551615"}},
+			value:       uint64(18446744073709551615),
+			errContains: "",
+		},
+		{
+			name:        "int8 below min",
+			field:       Field{Name: "field1", Kind: Int8Kind, Constraints: FieldConstraints{Min: "0"}},
+			value:       int8(-1),
+			errContains: "value -1 is less than the minimum of 0",
+		},
+		{
+			name:        "decimal above max",
+			field:       Field{Name: "field1", Kind: DecimalStringKind, Constraints: FieldConstraints{Max: "1.5"}},
+			value:       "1.50001",
+			errContains: "value 1.50001 is greater than the maximum of 1.5",
+		},
+		{
+			name:        "decimal with exponent within bounds",
+			field:       Field{Name: "field1", Kind: DecimalStringKind, Constraints: FieldConstraints{Min: "0", Max: "1000"}},
+			value:       "1e3",
+			errContains: "",
+		},
+		{
+			name:        "float infinity above max",
+			field:       Field{Name: "field1", Kind: Float64Kind, Constraints: FieldConstraints{Max: "1"}},
+			value:       math.Inf(1),
+			errContains: "greater than the maximum",
+		},
+		{
+			name:        "float infinity without max",
+			field:       Field{Name: "field1", Kind: Float64Kind, Constraints: FieldConstraints{Min: "1"}},
+			value:       math.Inf(1),
+			errContains: "",
+		},
+		{
+			name:        "string too long",
+			field:       Field{Name: "field1", Kind: StringKind, Constraints: FieldConstraints{MaxLength: 3}},
+			value:       "abcd",
+			errContains: "longer than the maximum length of 3",
+		},
+		{
+			name:        "multi-byte string within max length",
+			field:       Field{Name: "field1", Kind: StringKind, Constraints: FieldConstraints{MaxLength: 3}},
+			value:       "äöü",
+			errContains: "",
+		},
+		{
+			name:        "string not matching pattern",
+			field:       Field{Name: "field1", Kind: StringKind, Constraints: FieldConstraints{Pattern: "^[a-z]+$"}},
+			value:       "uAtom",
+			errContains: "does not match pattern ^[a-z]+$",
+		},
+		{
+			name:        "bytes of wrong size",
+			field:       Field{Name: "field1", Kind: BytesKind, Constraints: FieldConstraints{Size: 32}},
+			value:       []byte{1, 2, 3},
+			errContains: "expected 32 bytes, got 3",
+		},
+		{
+			name: "map value above max",
+			field: Field{
+				Name:        "field1",
+				Kind:        MapKind,
+				KeyKind:     StringKind,
+				ValueKind:   IntegerStringKind,
+				Constraints: FieldConstraints{Max: "100"},
+			},
+			value:       map[interface{}]interface{}{"uatom": "101"},
+			errContains: "invalid map value for key uatom for field \"field1\": value 101 is greater than the maximum of 100",
+		},
 	}
 
 	for _, tt := range tests {
@@ -164,3 +824,102 @@ func TestField_ValidateValue(t *testing.T) {
 		})
 	}
 }
+
+func TestField_JSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		field Field
+		json  string
+	}{
+		{
+			name:  "string",
+			field: Field{Name: "field1", Kind: StringKind},
+			json:  `{"name":"field1","kind":"string"}`,
+		},
+		{
+			name:  "int64 default",
+			field: Field{Name: "field1", Kind: Int64Kind, Default: int64(10)},
+			json:  `{"name":"field1","kind":"int64","default":10}`,
+		},
+		{
+			name:  "bytes default",
+			field: Field{Name: "field1", Kind: BytesKind, Default: []byte{1, 2, 3}},
+			json:  `{"name":"field1","kind":"bytes","default":"AQID"}`,
+		},
+		{
+			name:  "time default",
+			field: Field{Name: "field1", Kind: TimeKind, Default: time.Unix(0, 0).UTC()},
+			json:  `{"name":"field1","kind":"time","default":"1970-01-01T00:00:00Z"}`,
+		},
+		{
+			name:  "duration default",
+			field: Field{Name: "field1", Kind: DurationKind, Nullable: true, Default: time.Second},
+			json:  `{"name":"field1","kind":"duration","nullable":true,"default":1000000000}`,
+		},
+		{
+			name:  "list with nullable elements",
+			field: Field{Name: "field1", Kind: ListKind, ElementKind: StringKind, NullableElements: true},
+			json:  `{"name":"field1","kind":"list","element_kind":"string","nullable_elements":true}`,
+		},
+		{
+			name: "documentation",
+			field: Field{
+				Name:        "field1",
+				Kind:        StringKind,
+				Description: "the first field",
+				Metadata:    map[string]string{"unit": "uatom"},
+			},
+			json: `{"name":"field1","kind":"string","description":"the first field","metadata":{"unit":"uatom"}}`,
+		},
+		{
+			name:  "decimal precision",
+			field: Field{Name: "field1", Kind: DecimalStringKind, Precision: 10, Scale: 2, Default: "1.5"},
+			json:  `{"name":"field1","kind":"decimal","default":"1.5","precision":10,"scale":2}`,
+		},
+		{
+			name:  "time resolution",
+			field: Field{Name: "field1", Kind: TimeKind, TimeResolution: TimeResolutionMillis},
+			json:  `{"name":"field1","kind":"time","time_resolution":"millis"}`,
+		},
+		{
+			name:  "constraints",
+			field: Field{Name: "field1", Kind: StringKind, Constraints: FieldConstraints{MaxLength: 64, Pattern: "^[a-z]+$"}},
+			json:  `{"name":"field1","kind":"string","constraints":{"max_length":64,"pattern":"^[a-z]+$"}}`,
+		},
+		{
+			name:  "address prefix",
+			field: Field{Name: "field1", Kind: AddressKind, AddressPrefix: "cosmos", Constraints: FieldConstraints{Size: 20}},
+			json:  `{"name":"field1","kind":"bech32address","constraints":{"size":20},"address_prefix":"cosmos"}`,
+		},
+		{
+			name: "oneof",
+			field: Field{Name: "field1", Kind: OneOfKind, Nullable: true, OneOfType: OneOfType{
+				Name:  "oneof1",
+				Cases: []Field{{Name: "a", Kind: StringKind}, {Name: "b", Kind: Int32Kind}},
+			}},
+			json: `{"name":"field1","kind":"oneof","nullable":true,"oneof_type":{"name":"oneof1","cases":[{"name":"a","kind":"string"},{"name":"b","kind":"int32"}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bz, err := json.Marshal(tt.field)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(bz) != tt.json {
+				t.Fatalf("expected %s, got %s", tt.json, bz)
+			}
+
+			var field Field
+			if err := json.Unmarshal(bz, &field); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(field, tt.field) {
+				t.Fatalf("expected %v, got %v", tt.field, field)
+			}
+		})
+	}
+}
@@ -45,6 +45,46 @@ func ValidateObjectValue(valueFields []Field, value interface{}) error {
 	return nil
 }
 
+// FillObjectValueDefaults converts the Value of an ObjectUpdate which creates a new object into a value
+// which sets every value field. If value is a ValueUpdates, fields which it omits are set to their
+// Default or to nil if they are nullable and have no default. An error is returned if a non-nullable
+// field without a default is omitted. Values which are not ValueUpdates are returned unchanged. The
+// result is a single value if there is exactly one value field and a slice of values otherwise, as
+// described in ObjectUpdate.Value. The values themselves are not validated.
+func FillObjectValueDefaults(valueFields []Field, value interface{}) (interface{}, error) {
+	valueUpdates, ok := value.(ValueUpdates)
+	if !ok {
+		return value, nil
+	}
+
+	values := map[string]interface{}{}
+	err := valueUpdates.Iterate(func(fieldname string, value interface{}) bool {
+		values[fieldname] = value
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]interface{}, len(valueFields))
+	for i, field := range valueFields {
+		v, ok := values[field.Name]
+		if !ok {
+			if field.Default == nil && !field.Nullable {
+				return nil, fmt.Errorf("missing value for non-nullable field %q without a default", field.Name)
+			}
+			v = field.Default
+		}
+		res[i] = v
+	}
+
+	if len(res) == 1 {
+		return res[0], nil
+	}
+
+	return res, nil
+}
+
 func validateFieldsValue(fields []Field, value interface{}) error {
 	if len(fields) == 0 {
 		return nil
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -141,3 +142,78 @@ func TestValidateForValueFields(t *testing.T) {
 		})
 	}
 }
+
+func TestFillObjectValueDefaults(t *testing.T) {
+	valueFields := []Field{
+		{Name: "field1", Kind: StringKind, Default: "abc"},
+		{Name: "field2", Kind: Int32Kind, Nullable: true},
+		{Name: "field3", Kind: BoolKind},
+	}
+
+	tests := []struct {
+		name        string
+		valueFields []Field
+		value       interface{}
+		expected    interface{}
+		errContains string
+	}{
+		{
+			name:        "full value unchanged",
+			valueFields: valueFields,
+			value:       []interface{}{"x", int32(1), true},
+			expected:    []interface{}{"x", int32(1), true},
+		},
+		{
+			name:        "value updates with all fields",
+			valueFields: valueFields,
+			value: MapValueUpdates(map[string]interface{}{
+				"field1": "x",
+				"field2": int32(1),
+				"field3": true,
+			}),
+			expected: []interface{}{"x", int32(1), true},
+		},
+		{
+			name:        "value updates with defaults",
+			valueFields: valueFields,
+			value: MapValueUpdates(map[string]interface{}{
+				"field3": false,
+			}),
+			expected: []interface{}{"abc", nil, false},
+		},
+		{
+			name:        "missing non-nullable field",
+			valueFields: valueFields,
+			value: MapValueUpdates(map[string]interface{}{
+				"field1": "x",
+			}),
+			errContains: "missing value for non-nullable field \"field3\"",
+		},
+		{
+			name:        "single value field",
+			valueFields: valueFields[:1],
+			value:       MapValueUpdates(map[string]interface{}{}),
+			expected:    "abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := FillObjectValueDefaults(tt.valueFields, tt.value)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error to contain %q, got: %v", tt.errContains, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(res, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package schema
+
+import "fmt"
+
+// IndexDescriptor describes a secondary index on the value fields of an object type which indexers
+// may create to speed up queries. Indexes do not affect the validity of object updates.
+type IndexDescriptor struct {
+	// Name is the name of the index. It must conform to the NameFormat regular expression and be unique
+	// amongst the indexes of the object type.
+	Name string `json:"name"`
+
+	// Fields are the value fields which make up the index in order. Single field indexes have one field
+	// and composite indexes have several. Each field can only appear once in an index and list and map
+	// fields cannot be indexed.
+	Fields []IndexField `json:"fields"`
+}
+
+// IndexField describes a field in an index.
+type IndexField struct {
+	// Name is the name of the value field.
+	Name string `json:"name"`
+
+	// Descending indicates that the field should be sorted in descending rather than ascending order.
+	Descending bool `json:"descending,omitempty"`
+}
+
+// validate validates the index against the value fields of the object type.
+func (i IndexDescriptor) validate(valueFields map[string]Field) error {
+	if !ValidateName(i.Name) {
+		return fmt.Errorf("invalid index name %q", i.Name)
+	}
+
+	if len(i.Fields) == 0 {
+		return fmt.Errorf("index %q must have at least one field", i.Name)
+	}
+
+	indexFields := map[string]bool{}
+	for _, indexField := range i.Fields {
+		field, ok := valueFields[indexField.Name]
+		if !ok {
+			return fmt.Errorf("index %q references unknown value field %q", i.Name, indexField.Name)
+		}
+
+		if field.Kind == ListKind || field.Kind == MapKind {
+			return fmt.Errorf("index %q cannot include list or map field %q", i.Name, indexField.Name)
+		}
+
+		if field.Kind == OneOfKind {
+			return fmt.Errorf("index %q cannot include oneof field %q", i.Name, indexField.Name)
+		}
+
+		if indexFields[indexField.Name] {
+			return fmt.Errorf("index %q references field %q more than once", i.Name, indexField.Name)
+		}
+		indexFields[indexField.Name] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package indexer
+
+import (
+	"fmt"
+
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/decoding"
+)
+
+// backfillTarget wraps the listener of a target so that, when the first block is delivered, the blocks which
+// the target is missing are backfilled before it switches to live mode, and blocks before the target's start
+// height or which it has already persisted are dropped. An error is returned if the target is missing blocks
+// which can't be backfilled, so that it never silently indexes partial data. Each module is only initialized
+// once even if it is initialized again during live mode.
+func (m *Manager) backfillTarget(name string, cfg Config, lastPersisted int64, listener appdata.Listener, opts ManagerOptions) appdata.Listener {
+	initialized := map[string]bool{}
+	if listener.InitializeModuleData != nil {
+		initializeModuleData := listener.InitializeModuleData
+		listener.InitializeModuleData = func(data appdata.ModuleInitializationData) error {
+			// modules are initialized once, both by backfills and live blocks, unless their schema changed
+			if initialized[data.ModuleName] && !data.SchemaChanged {
+				return nil
+			}
+			initialized[data.ModuleName] = true
+			return initializeModuleData(data)
+		}
+	}
+
+	// prepare backfills the blocks up to and including height and returns the first block height which should
+	// be delivered in live mode
+	prepare := func(height int64) (uint64, error) {
+		if lastPersisted < 0 {
+			// the target does not persist data so it can just start at its start height
+			return uint64(cfg.StartHeight), nil
+		}
+
+		from := lastPersisted
+		if cfg.StartHeight-1 > from {
+			from = cfg.StartHeight - 1
+		}
+
+		if from >= height {
+			return uint64(from + 1), nil
+		}
+
+		var source appdata.CatchUpSource
+		switch {
+		case cfg.Backfill:
+			if opts.BackfillSource == nil {
+				return 0, fmt.Errorf("indexer target %q has backfill enabled but no backfill source is available", name)
+			}
+			source = opts.BackfillSource
+		case from == 0 && opts.SyncSource != nil && opts.Resolver != nil:
+			// an empty target can start from the current state instead of replaying history
+			source = decoding.SyncCatchUpSource(opts.SyncSource, opts.Resolver, decoding.SyncOptions{})
+		default:
+			return 0, fmt.Errorf("indexer target %q has data up to height %d but the chain is at height %d, enable backfill or set start_height to a later height", name, from, height)
+		}
+
+		target := listener
+		if target.StartBlock != nil {
+			startBlock := target.StartBlock
+			target.StartBlock = func(data appdata.StartBlockData) error {
+				data.Backfill = true
+				return startBlock(data)
+			}
+		}
+		if opts.Resolver != nil {
+			var err error
+			target, err = decoding.Middleware(listener, opts.Resolver, decoding.MiddlewareOptions{
+				OnDecodeLatency: opts.Metrics.OnDecodeLatency,
+			})
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		m.logger.Info("backfilling indexer target", "target", name, "from", from+1, "to", height)
+		if err := source.CatchUp(from, height, target); err != nil {
+			return 0, fmt.Errorf("failed to backfill indexer target %q from height %d to %d: %v", name, from+1, height, err) //nolint:errorlint // false positive due to using go1.12
+		}
+
+		return uint64(height + 1), nil
+	}
+
+	started := false
+	var minHeight uint64
+	skipping := false
+	forward := func(p appdata.Packet) error {
+		if skipping {
+			return nil
+		}
+		return listener.SendPacket(p)
+	}
+
+	res := appdata.Listener{
+		StartBlock: func(data appdata.StartBlockData) error {
+			if !started {
+				var err error
+				minHeight, err = prepare(int64(data.Height) - 1)
+				if err != nil {
+					return err
+				}
+				started = true
+			}
+
+			skipping = data.Height < minHeight
+			return forward(data)
+		},
+		Commit: func(data appdata.CommitData) error {
+			err := forward(data)
+			skipping = false
+			return err
+		},
+	}
+	if listener.InitializeModuleData != nil {
+		// module initialization is never skipped
+		res.InitializeModuleData = listener.InitializeModuleData
+	}
+	if listener.OnTx != nil {
+		res.OnTx = func(data appdata.TxData) error { return forward(data) }
+	}
+	if listener.OnEvent != nil {
+		res.OnEvent = func(data appdata.EventData) error { return forward(data) }
+	}
+	if listener.OnKVPair != nil {
+		res.OnKVPair = func(data appdata.KVPairData) error { return forward(data) }
+	}
+	if listener.OnObjectUpdate != nil {
+		res.OnObjectUpdate = func(data appdata.ObjectUpdateData) error { return forward(data) }
+	}
+	// simulations are not part of any block and are never skipped
+	res.OnSimulation = listener.OnSimulation
+	return res
+}
+
+// Backfill replays the blocks from fromHeight to toHeight, inclusive, from source to the targets, for instance
+// from a journal while the node is stopped. As for live blocks, the blocks which a target has already persisted
+// or which are before its start height are skipped, and an error is returned if a target is missing blocks
+// before fromHeight which can't be backfilled.
+func (m *Manager) Backfill(fromHeight, toHeight int64, source appdata.CatchUpSource) error {
+	if fromHeight < 1 || toHeight < fromHeight {
+		return fmt.Errorf("invalid backfill range from height %d to %d", fromHeight, toHeight)
+	}
+
+	listener := m.listener
+	startBlock := listener.StartBlock
+	listener.StartBlock = func(data appdata.StartBlockData) error {
+		data.Backfill = true
+		return startBlock(data)
+	}
+
+	m.logger.Info("backfilling indexer targets", "from", fromHeight, "to", toHeight)
+	if err := source.CatchUp(fromHeight-1, toHeight, listener); err != nil {
+		return fmt.Errorf("failed to backfill indexer targets from height %d to %d: %v", fromHeight, toHeight, err) //nolint:errorlint // false positive due to using go1.12
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package indexer
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cosmossdk.io/schema/appdata"
+)
+
+type backfillTestIndexer struct {
+	committed  []uint64
+	backfilled []uint64
+	height     uint64
+	backfill   bool
+}
+
+var backfillTestIndexers = map[string]*backfillTestIndexer{}
+
+func init() {
+	Register("backfill_test", func(params InitParams) (InitResult, error) {
+		i := &backfillTestIndexer{}
+		backfillTestIndexers[params.Config.Config["name"].(string)] = i
+		return InitResult{
+			Listener: appdata.Listener{
+				StartBlock: func(data appdata.StartBlockData) error {
+					i.height = data.Height
+					i.backfill = data.Backfill
+					return nil
+				},
+				Commit: func(appdata.CommitData) error {
+					i.committed = append(i.committed, i.height)
+					if i.backfill {
+						i.backfilled = append(i.backfilled, i.height)
+					}
+					return nil
+				},
+			},
+			LastBlockPersisted: int64(params.Config.Config["last_persisted"].(float64)),
+		}, nil
+	})
+}
+
+func TestManager_backfill(t *testing.T) {
+	var journal bytes.Buffer
+	writer := appdata.JournalListener(&journal, appdata.Listener{})
+	for height := uint64(1); height <= 4; height++ {
+		if err := writer.StartBlock(appdata.StartBlockData{Height: height}); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Commit(appdata.CommitData{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	source := appdata.JournalCatchUpSource(func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(journal.Bytes())), nil
+	})
+
+	target := func(name string, lastPersisted int, extra map[string]interface{}) map[string]interface{} {
+		cfg := map[string]interface{}{
+			"type":   "backfill_test",
+			"config": map[string]interface{}{"name": name, "last_persisted": lastPersisted},
+		}
+		for k, v := range extra {
+			cfg[k] = v
+		}
+		return cfg
+	}
+
+	manager, err := StartManager(ManagerOptions{
+		Config: map[string]interface{}{
+			"target": map[string]interface{}{
+				"behind":      target("behind", 2, map[string]interface{}{"backfill": true}),
+				"fresh":       target("fresh", 0, map[string]interface{}{"backfill": true, "start_height": 3}),
+				"late":        target("late", 0, map[string]interface{}{"start_height": 7}),
+				"stateless":   target("stateless", -1, map[string]interface{}{"start_height": 6}),
+				"up_to_date":  target("up_to_date", 4, nil),
+				"ahead_start": target("ahead_start", 5, map[string]interface{}{"start_height": 2}),
+			},
+		},
+		BackfillSource: source,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	listener := manager.Listener()
+	for height := uint64(5); height <= 7; height++ {
+		if err := listener.StartBlock(appdata.StartBlockData{Height: height}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := listener.Commit(appdata.CommitData{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := map[string][]uint64{
+		"behind":      {3, 4, 5, 6, 7},
+		"fresh":       {3, 4, 5, 6, 7},
+		"late":        {7},
+		"stateless":   {6, 7},
+		"up_to_date":  {5, 6, 7},
+		"ahead_start": {6, 7},
+	}
+	for name, heights := range expected {
+		if got := backfillTestIndexers[name].committed; !reflect.DeepEqual(got, heights) {
+			t.Errorf("expected target %q to commit %v, got %v", name, heights, got)
+		}
+	}
+
+	expectedBackfilled := map[string][]uint64{
+		"behind": {3, 4},
+		"fresh":  {3, 4},
+	}
+	for name := range expected {
+		if got := backfillTestIndexers[name].backfilled; !reflect.DeepEqual(got, expectedBackfilled[name]) {
+			t.Errorf("expected target %q to backfill %v, got %v", name, expectedBackfilled[name], got)
+		}
+	}
+
+	t.Run("missing blocks without backfill", func(t *testing.T) {
+		manager, err := StartManager(ManagerOptions{
+			Config: map[string]interface{}{
+				"target": map[string]interface{}{
+					"partial": target("partial", 2, nil),
+				},
+			},
+			BackfillSource: source,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = manager.Listener().StartBlock(appdata.StartBlockData{Height: 5})
+		if err == nil || !strings.Contains(err.Error(), "enable backfill or set start_height") {
+			t.Fatalf("expected missing blocks error, got: %v", err)
+		}
+	})
+
+	t.Run("manual backfill", func(t *testing.T) {
+		manager, err := StartManager(ManagerOptions{
+			Config: map[string]interface{}{
+				"target": map[string]interface{}{
+					"manual":         target("manual", 1, nil),
+					"manual_partial": target("manual_partial", 3, nil),
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := manager.Backfill(2, 4, source); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := backfillTestIndexers["manual"].backfilled; !reflect.DeepEqual(got, []uint64{2, 3, 4}) {
+			t.Errorf("expected blocks 2 to 4 to be backfilled, got %v", got)
+		}
+		if got := backfillTestIndexers["manual_partial"].backfilled; !reflect.DeepEqual(got, []uint64{4}) {
+			t.Errorf("expected only block 4 to be backfilled, got %v", got)
+		}
+
+		if err := manager.Backfill(4, 3, source); err == nil {
+			t.Fatal("expected an invalid range error")
+		}
+	})
+
+	t.Run("backfill without source", func(t *testing.T) {
+		manager, err := StartManager(ManagerOptions{
+			Config: map[string]interface{}{
+				"target": map[string]interface{}{
+					"no_source": target("no_source", 2, map[string]interface{}{"backfill": true}),
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = manager.Listener().StartBlock(appdata.StartBlockData{Height: 5})
+		if err == nil || !strings.Contains(err.Error(), "no backfill source is available") {
+			t.Fatalf("expected no backfill source error, got: %v", err)
+		}
+	})
+}
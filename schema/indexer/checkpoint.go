@@ -0,0 +1,49 @@
+package indexer
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/decoding"
+)
+
+// Checkpoint identifies the state which a node restored from a snapshot, for instance with state sync,
+// corresponds to, so that attached indexers can tell where the restored state picks up.
+type Checkpoint struct {
+	// Height is the height of the last block included in the restored state.
+	Height int64 `json:"height"`
+
+	// SchemaFingerprints maps the name of each module which has a module codec to the hex encoded
+	// fingerprint of its schema, as returned by schema.ModuleSchema.Fingerprint, at Height.
+	SchemaFingerprints map[string]string `json:"schema_fingerprints"`
+}
+
+// NewCheckpoint returns the checkpoint of the state at height for the modules resolved by resolver.
+func NewCheckpoint(height int64, resolver decoding.DecoderResolver) (Checkpoint, error) {
+	res := Checkpoint{Height: height, SchemaFingerprints: map[string]string{}}
+	if resolver == nil {
+		return res, nil
+	}
+
+	err := resolver.IterateAll(func(moduleName string, cdc schema.ModuleCodec) error {
+		fingerprint := cdc.Schema.Fingerprint()
+		res.SchemaFingerprints[moduleName] = hex.EncodeToString(fingerprint[:])
+		return nil
+	})
+	if err != nil {
+		return Checkpoint{}, fmt.Errorf("failed to compute schema fingerprints: %v", err) //nolint:errorlint // false positive due to using go1.12
+	}
+	return res, nil
+}
+
+// SchemaMatches reports whether the schema of moduleName matches its schema in the checkpoint. It returns
+// false if the module is not part of the checkpoint.
+func (c Checkpoint) SchemaMatches(moduleName string, moduleSchema schema.ModuleSchema) bool {
+	fingerprint, ok := c.SchemaFingerprints[moduleName]
+	if !ok {
+		return false
+	}
+	actual := moduleSchema.Fingerprint()
+	return fingerprint == hex.EncodeToString(actual[:])
+}
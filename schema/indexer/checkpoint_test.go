@@ -0,0 +1,92 @@
+package indexer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/decoding"
+)
+
+type checkpointTestModule struct {
+	schema schema.ModuleSchema
+}
+
+func (m checkpointTestModule) ModuleCodec() (schema.ModuleCodec, error) {
+	return schema.ModuleCodec{Schema: m.schema}, nil
+}
+
+func TestManager_Restore(t *testing.T) {
+	bankSchema, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:      "balances",
+		KeyFields: []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resolver := decoding.ModuleSetDecoderResolver(map[string]interface{}{
+		"bank": checkpointTestModule{schema: bankSchema},
+		"auth": struct{}{},
+	})
+
+	var restored []Checkpoint
+	failRestore := false
+	Register("checkpoint_test", func(params InitParams) (InitResult, error) {
+		return InitResult{
+			OnRestore: func(checkpoint Checkpoint) error {
+				if failRestore {
+					return context.Canceled
+				}
+				restored = append(restored, checkpoint)
+				return nil
+			},
+		}, nil
+	})
+
+	manager, err := StartManager(ManagerOptions{
+		Config:   ManagerConfig{Target: map[string]Config{"restored": {Type: "checkpoint_test"}}},
+		Resolver: resolver,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	checkpoint, err := manager.Checkpoint(10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checkpoint.Height != 10 || len(checkpoint.SchemaFingerprints) != 1 || !checkpoint.SchemaMatches("bank", bankSchema) {
+		t.Fatalf("unexpected checkpoint: %+v", checkpoint)
+	}
+	if checkpoint.SchemaMatches("bank", schema.ModuleSchema{}) || checkpoint.SchemaMatches("auth", bankSchema) {
+		t.Fatalf("expected schema mismatches")
+	}
+
+	if err := manager.Restore(checkpoint); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(restored) != 1 || restored[0].Height != 10 {
+		t.Fatalf("expected the target to be notified of the restored checkpoint, got %v", restored)
+	}
+	// the target has not persisted any block so it lags behind the restored height
+	if health := manager.Health(); health[0].Lag != 10 || health[0].LastError != nil {
+		t.Fatalf("unexpected health after restore: %+v", health[0])
+	}
+
+	failRestore = true
+	err = manager.Restore(checkpoint)
+	if err == nil || !strings.Contains(err.Error(), "indexer target \"restored\" failed to restore checkpoint at height 10") {
+		t.Fatalf("expected restore error, got: %v", err)
+	}
+	if health := manager.Health(); health[0].LastError == nil {
+		t.Fatalf("expected the restore error to be recorded")
+	}
+
+	if err := manager.Stop(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := manager.Restore(checkpoint); err != ErrManagerStopped { //nolint:errorlint // false positive due to using go1.12
+		t.Fatalf("expected ErrManagerStopped, got: %v", err)
+	}
+}
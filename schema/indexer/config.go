@@ -0,0 +1,196 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"cosmossdk.io/schema/appdata"
+)
+
+// ParseManagerConfig parses the configuration of the indexer manager, usually the indexer section of app.toml,
+// and validates it with ManagerConfig.Validate. config may be a ManagerConfig, a pointer to one or any value
+// which can be marshaled to JSON, such as the map read from app.toml, in which case unknown options are
+// rejected so that misspelled options are not silently ignored.
+//
+// References of the form ${NAME} in the string values of the indexer specific options of the targets are
+// replaced with the value of the environment variable NAME, so that credentials such as database passwords
+// don't have to be stored in app.toml. It is an error for a referenced variable not to be set.
+func ParseManagerConfig(config interface{}) (ManagerConfig, error) {
+	var res ManagerConfig
+	switch cfg := config.(type) {
+	case nil:
+	case ManagerConfig:
+		res = cfg
+	case *ManagerConfig:
+		res = *cfg
+	default:
+		bz, err := json.Marshal(config)
+		if err != nil {
+			return ManagerConfig{}, fmt.Errorf("invalid indexer config: %v", err) //nolint:errorlint // false positive due to using go1.12
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(bz))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&res); err != nil {
+			return ManagerConfig{}, fmt.Errorf("invalid indexer config: %v", err) //nolint:errorlint // false positive due to using go1.12
+		}
+	}
+
+	targets := make(map[string]Config, len(res.Target))
+	for name, target := range res.Target {
+		options, err := expandEnv(target.Config)
+		if err != nil {
+			return ManagerConfig{}, fmt.Errorf("invalid indexer config: target %q: %v", name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		if options != nil {
+			target.Config = options.(map[string]interface{})
+		}
+		targets[name] = target
+	}
+	res.Target = targets
+
+	if err := res.Validate(); err != nil {
+		return ManagerConfig{}, err
+	}
+	return res, nil
+}
+
+// envRef matches the references to environment variables in indexer specific options.
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv returns a copy of value, which is decoded from JSON, with the references to environment variables
+// in its strings replaced with their values.
+func expandEnv(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case string:
+		var err error
+		res := envRef.ReplaceAllStringFunc(value, func(ref string) string {
+			name := envRef.FindStringSubmatch(ref)[1]
+			env, ok := os.LookupEnv(name)
+			if !ok && err == nil {
+				err = fmt.Errorf("environment variable %s is not set", name)
+			}
+			return env
+		})
+		return res, err
+	case map[string]interface{}:
+		if value == nil {
+			return nil, nil
+		}
+		res := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			expanded, err := expandEnv(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", k, err) //nolint:errorlint // false positive due to using go1.12
+			}
+			res[k] = expanded
+		}
+		return res, nil
+	case []interface{}:
+		res := make([]interface{}, len(value))
+		for i, v := range value {
+			expanded, err := expandEnv(v)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %v", i, err) //nolint:errorlint // false positive due to using go1.12
+			}
+			res[i] = expanded
+		}
+		return res, nil
+	default:
+		return value, nil
+	}
+}
+
+// Validate checks the configuration of the manager and of each target, including that the indexer type of
+// each target is registered, and returns an error which describes every problem found.
+func (c ManagerConfig) Validate() error {
+	var errs []string
+	if len(c.IncludeModules) != 0 && len(c.ExcludeModules) != 0 {
+		errs = append(errs, "only one of include_modules or exclude_modules can be specified")
+	}
+
+	names := make([]string, 0, len(c.Target))
+	for name := range c.Target {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if name == "" {
+			errs = append(errs, "target names must not be empty")
+			continue
+		}
+		for _, err := range c.Target[name].validate() {
+			errs = append(errs, fmt.Sprintf("target %q: %s", name, err))
+		}
+	}
+
+	if len(errs) != 0 {
+		return fmt.Errorf("invalid indexer config: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// validate returns the problems found in the configuration of a target.
+func (c Config) validate() []string {
+	var errs []string
+	if c.Type == "" {
+		errs = append(errs, "type is required")
+	} else if _, ok := indexerRegistry[c.Type]; !ok {
+		errs = append(errs, fmt.Sprintf("indexer type %q not found, registered types are %s", c.Type, strings.Join(RegisteredTypes(), ", ")))
+	}
+
+	if len(c.IncludeModules) != 0 && len(c.ExcludeModules) != 0 {
+		errs = append(errs, "only one of include_modules or exclude_modules can be specified")
+	}
+
+	if c.StartHeight < 0 {
+		errs = append(errs, "start_height must not be negative")
+	}
+
+	if c.BufferSize < 0 {
+		errs = append(errs, "buffer_size must not be negative")
+	}
+
+	if c.DeadLetter != nil && c.DeadLetter.MaxAttempts < 0 {
+		errs = append(errs, "dead_letter.max_attempts must not be negative")
+	}
+
+	if c.Limits != nil {
+		if c.Limits.MaxEventAttributeSize < 0 || c.Limits.MaxEventDataSize < 0 || c.Limits.MaxTxLogSize < 0 || c.Limits.MaxEventsPerBlock < 0 {
+			errs = append(errs, "limits must not be negative")
+		}
+		if c.Limits.Overflow != "" && c.Limits.Overflow != "truncate" && c.Limits.Overflow != "drop" {
+			errs = append(errs, fmt.Sprintf("invalid limits.overflow %q, expected truncate or drop", c.Limits.Overflow))
+		}
+	}
+
+	if c.Chunking != nil && (c.Chunking.Threshold < 0 || c.Chunking.ChunkSize < 0) {
+		errs = append(errs, "chunking.threshold and chunking.chunk_size must not be negative")
+	}
+
+	if c.Transforms != nil {
+		if c.Transforms.TxMemo != "" && !validTransformAction(c.Transforms.TxMemo) {
+			errs = append(errs, fmt.Sprintf("transforms.tx_memo: unknown transform action %q, expected redact or hash", c.Transforms.TxMemo))
+		}
+		for i, field := range c.Transforms.Fields {
+			if field.Module == "" || field.Type == "" || field.Field == "" {
+				errs = append(errs, fmt.Sprintf("transforms.fields[%d]: module, type and field are required", i))
+			}
+			if !validTransformAction(field.Action) {
+				errs = append(errs, fmt.Sprintf("transforms.fields[%d]: unknown transform action %q, expected redact or hash", i, field.Action))
+			}
+		}
+	}
+
+	return errs
+}
+
+func validTransformAction(action string) bool {
+	return action == string(appdata.RedactAction) || action == string(appdata.HashAction)
+}
@@ -0,0 +1,111 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/logutil"
+)
+
+// deadLetterTarget wraps the listener of a target so that the object updates which it persistently fails to
+// apply are routed to its dead-letter store, if dead-lettering is configured for the target.
+func (m *Manager) deadLetterTarget(ctx context.Context, t *target, cfg Config, res InitResult) (appdata.Listener, error) {
+	if cfg.DeadLetter == nil {
+		return res.Listener, nil
+	}
+
+	store := res.DeadLetterStore
+	if cfg.DeadLetter.File != "" {
+		file, err := os.OpenFile(cfg.DeadLetter.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return appdata.Listener{}, fmt.Errorf("failed to open dead-letter file: %v", err) //nolint:errorlint // false positive due to using go1.12
+		}
+
+		// the file is closed once the manager is stopped, at which point no more packets are delivered
+		m.done.Add(1)
+		go func() {
+			defer m.done.Done()
+			<-ctx.Done()
+			if err := file.Close(); err != nil {
+				m.logger.Error("failed to close dead-letter file", "target", t.name, "error", err)
+			}
+		}()
+
+		store = appdata.DeadLetterWriter(file)
+	}
+	if store == nil {
+		return appdata.Listener{}, fmt.Errorf("dead_letter is enabled but no file is set and indexer type %q does not provide a dead-letter store", cfg.Type)
+	}
+
+	return appdata.DeadLetterListener(res.Listener, appdata.DeadLetterOptions{
+		Store:       store,
+		MaxAttempts: cfg.DeadLetter.MaxAttempts,
+		OnDeadLetter: func(letter appdata.DeadLetter) {
+			m.healthMu.Lock()
+			t.deadLetters++
+			m.healthMu.Unlock()
+			m.logger.Warn("dead-lettered object update", "target", t.name, "module", letter.ModuleName,
+				"type", letter.Update.TypeName, "height", letter.Height, "error", letter.Error)
+		},
+	}), nil
+}
+
+// RedriveOptions are the options for RedriveDeadLetters.
+type RedriveOptions struct {
+	// Config is the config of the indexer target which the dead letters are re-driven to. Its dead_letter
+	// option is ignored so that updates which still fail are reported instead of being dead-lettered again.
+	Config Config
+
+	// DeadLetters is the source of the dead letters, as written by appdata.DeadLetterWriter.
+	DeadLetters io.Reader
+
+	// Logger is the logger passed to the indexer. It is optional.
+	Logger logutil.Logger
+
+	// Context is the parent of the context passed to the indexer. If it is omitted, context.Background
+	// will be used.
+	Context context.Context
+}
+
+// RedriveDeadLetters initializes the indexer target described by opts.Config on its own, outside of a
+// Manager, sends the dead letters to it with appdata.RedriveDeadLetters and shuts it down. It returns the
+// number of updates which were applied and the first error, if any.
+func RedriveDeadLetters(opts RedriveOptions) (int, error) {
+	initFunc, ok := indexerRegistry[opts.Config.Type]
+	if !ok {
+		return 0, fmt.Errorf("indexer type %q not found", opts.Config.Type)
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = logutil.NoopLogger{}
+	}
+
+	parentCtx := opts.Context
+	if parentCtx == nil {
+		parentCtx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parentCtx)
+
+	done := &sync.WaitGroup{}
+	defer func() {
+		cancel()
+		done.Wait()
+	}()
+
+	res, err := initFunc(InitParams{
+		Config:        opts.Config,
+		Context:       ctx,
+		Logger:        logger,
+		DoneWaitGroup: done,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return appdata.RedriveDeadLetters(opts.DeadLetters, filterTarget(res.Listener, opts.Config))
+}
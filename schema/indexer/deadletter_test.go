@@ -0,0 +1,121 @@
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+)
+
+type deadLetterTestIndexer struct {
+	applied []string
+	// fixed makes the indexer apply every update
+	fixed bool
+}
+
+func (i *deadLetterTestIndexer) init(InitParams) (InitResult, error) {
+	return InitResult{
+		Listener: appdata.Listener{
+			OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+				for _, update := range data.Updates {
+					if update.Key == "bad" && !i.fixed {
+						return errors.New("cannot apply bad")
+					}
+				}
+				for _, update := range data.Updates {
+					i.applied = append(i.applied, update.Key.(string))
+				}
+				return nil
+			},
+		},
+		LastBlockPersisted: -1,
+	}, nil
+}
+
+func TestManager_DeadLetter(t *testing.T) {
+	idx := &deadLetterTestIndexer{}
+	Register("dead_letter_test", idx.init)
+
+	file := filepath.Join(t.TempDir(), "dead_letters")
+	targetCfg := map[string]interface{}{
+		"type": "dead_letter_test",
+		"dead_letter": map[string]interface{}{
+			"file":         file,
+			"max_attempts": 2,
+		},
+	}
+	manager, err := StartManager(ManagerOptions{
+		Config: map[string]interface{}{"target": map[string]interface{}{"dl": targetCfg}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener := manager.Listener()
+	if err := listener.SendPacket(appdata.StartBlockData{Height: 1}); err != nil {
+		t.Fatal(err)
+	}
+	err = listener.SendPacket(appdata.ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{
+		{TypeName: "balances", Key: "good"},
+		{TypeName: "balances", Key: "bad"},
+	}})
+	if err != nil {
+		t.Fatalf("expected the failing update to be dead-lettered, got %v", err)
+	}
+	if err := listener.SendPacket(appdata.CommitData{}); err != nil {
+		t.Fatal(err)
+	}
+
+	health := manager.Health()
+	if health[0].DeadLetters != 1 || health[0].LastError != nil {
+		t.Fatalf("expected one dead letter and no error, got %+v", health[0])
+	}
+
+	if err := manager.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	letter, err := appdata.NewDeadLetterReader(bytes.NewReader(bz)).Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if letter.ModuleName != "bank" || letter.Update.Key != "bad" || letter.Height != 1 || letter.Attempts != 2 {
+		t.Fatalf("unexpected dead letter %+v", letter)
+	}
+
+	// re-drive the dead letter once the indexer has been fixed
+	idx.fixed = true
+	idx.applied = nil
+	var cfg Config
+	cfg.Type = "dead_letter_test"
+	n, err := RedriveDeadLetters(RedriveOptions{Config: cfg, DeadLetters: bytes.NewReader(bz)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || len(idx.applied) != 1 || idx.applied[0] != "bad" {
+		t.Fatalf("expected the dead letter to be re-driven, got %d: %v", n, idx.applied)
+	}
+}
+
+func TestManager_DeadLetterStore(t *testing.T) {
+	Register("dead_letter_test_no_store", (&deadLetterTestIndexer{}).init)
+
+	_, err := StartManager(ManagerOptions{
+		Config: map[string]interface{}{"target": map[string]interface{}{"dl": map[string]interface{}{
+			"type":        "dead_letter_test_no_store",
+			"dead_letter": map[string]interface{}{},
+		}}},
+	})
+	if err == nil {
+		t.Fatal("expected an error when neither a file nor an indexer store is available")
+	}
+}
@@ -2,9 +2,11 @@ package indexer
 
 import (
 	"context"
+	"sync"
 
 	"cosmossdk.io/schema/appdata"
 	"cosmossdk.io/schema/logutil"
+	"cosmossdk.io/schema/view"
 )
 
 // Config species the configuration passed to an indexer initialization function.
@@ -36,6 +38,10 @@ type Config struct {
 	// the header data.
 	ExcludeBlockHeaders bool `json:"exclude_block_headers"`
 
+	// IncludeSimulations specifies that the indexer will receive the outcomes of checked and simulated
+	// transactions on the separate OnSimulation callback, for instance for mempool analytics.
+	IncludeSimulations bool `json:"include_simulations"`
+
 	// IncludeModules specifies a list of modules whose state the indexer will
 	// receive state updates for.
 	// Only one of include or exclude modules should be specified.
@@ -45,6 +51,105 @@ type Config struct {
 	// receive state updates for.
 	// Only one of include or exclude modules should be specified.
 	ExcludeModules []string `json:"exclude_modules"`
+
+	// StartHeight specifies the first block height which the indexer will receive. Blocks before it are
+	// neither delivered nor backfilled.
+	StartHeight int64 `json:"start_height"`
+
+	// BufferSize is the number of packets which are queued for the indexer, so that it processes them on a
+	// separate goroutine and only blocks the node once the queue is full. Errors returned by the indexer are then
+	// reported when the next packet is sent. If it is zero, packets are delivered synchronously.
+	BufferSize int `json:"buffer_size"`
+
+	// Backfill specifies that the blocks which the indexer has missed, from the block after its last persisted
+	// block or from StartHeight, should be replayed from the manager's backfill source before the indexer
+	// switches to live mode.
+	Backfill bool `json:"backfill"`
+
+	// DeadLetter configures a dead-letter store for the object updates which the indexer persistently fails to
+	// apply. If it is nil, such failures halt the pipeline.
+	DeadLetter *DeadLetterConfig `json:"dead_letter"`
+
+	// Transforms declares fields which are redacted or hashed before they are delivered to the indexer, so that
+	// sensitive data such as memos doesn't leave the node. If it is nil, the data is delivered as is.
+	Transforms *TransformConfig `json:"transforms"`
+
+	// Limits caps the size of event attributes and transaction logs and the number of events per block which are
+	// delivered to the indexer. If it is nil, nothing is capped.
+	Limits *LimitsConfig `json:"limits"`
+
+	// Chunking configures the delivery of large Bytes values in chunks to indexers which handle
+	// appdata.Listener.OnBytesChunk, such as the remote indexer. If it is nil, values are delivered whole.
+	Chunking *ChunkingConfig `json:"chunking"`
+}
+
+// ChunkingConfig configures the delivery of large Bytes values in chunks. See appdata.ChunkBytes.
+type ChunkingConfig struct {
+	// Threshold is the size in bytes above which Bytes values are delivered in chunks.
+	Threshold int `json:"threshold"`
+
+	// ChunkSize is the maximum size in bytes of each chunk. If it is zero, Threshold is used.
+	ChunkSize int `json:"chunk_size"`
+}
+
+// LimitsConfig configures the size caps and rate limits of the data delivered to an indexer. Zero values disable
+// the corresponding limits. See appdata.Limit.
+type LimitsConfig struct {
+	// MaxEventAttributeSize is the maximum size in bytes of the values of event attributes.
+	MaxEventAttributeSize int `json:"max_event_attribute_size"`
+
+	// MaxEventDataSize is the maximum size in bytes of the JSON data of events. Larger JSON data is replaced
+	// with null.
+	MaxEventDataSize int `json:"max_event_data_size"`
+
+	// MaxTxLogSize is the maximum size in bytes of the raw logs of transactions.
+	MaxTxLogSize int `json:"max_tx_log_size"`
+
+	// MaxEventsPerBlock is the maximum number of events delivered per block.
+	MaxEventsPerBlock int `json:"max_events_per_block"`
+
+	// Overflow is either "truncate", the default, to truncate oversized values with a marker or "drop" to
+	// drop them.
+	Overflow string `json:"overflow"`
+}
+
+// TransformConfig configures the redaction or hashing of fields before they are delivered to an indexer. See
+// appdata.TransformFields.
+type TransformConfig struct {
+	// Fields are the fields of object types and event types to transform.
+	Fields []FieldTransformConfig `json:"fields"`
+
+	// TxMemo is the action, "redact" or "hash", applied to the memo of transactions. If it is set, the raw
+	// bytes and JSON of transactions are not delivered.
+	TxMemo string `json:"tx_memo"`
+}
+
+// FieldTransformConfig declares the transformation of a value field of an object type or a field of an event
+// type.
+type FieldTransformConfig struct {
+	// Module is the name of the module.
+	Module string `json:"module"`
+
+	// Type is the name of the object type or event type in the schema of the module.
+	Type string `json:"type"`
+
+	// Field is the name of the field.
+	Field string `json:"field"`
+
+	// Action is the transformation applied to the values of the field, either "redact" or "hash".
+	Action string `json:"action"`
+}
+
+// DeadLetterConfig configures how object updates which an indexer persistently fails to apply are routed to a
+// dead-letter store instead of halting the pipeline. See appdata.DeadLetterListener.
+type DeadLetterConfig struct {
+	// File is the path of the file which dead letters are appended to. If it is empty, the store returned by
+	// the indexer in InitResult.DeadLetterStore, such as a database table, is used.
+	File string `json:"file"`
+
+	// MaxAttempts is the number of times an update is passed to the indexer before it is dead-lettered. If
+	// it is zero, updates are attempted once.
+	MaxAttempts int `json:"max_attempts"`
 }
 
 type InitFunc = func(InitParams) (InitResult, error)
@@ -60,6 +165,11 @@ type InitParams struct {
 
 	// Logger is a logger the indexer can use to write log messages.
 	Logger logutil.Logger
+
+	// DoneWaitGroup is a wait group which indexers that run goroutines or hold resources, such as database
+	// connections, should increment and mark as done once they have shut down after Context is done, so that
+	// the manager can wait for them during a graceful shutdown. It may be nil.
+	DoneWaitGroup *sync.WaitGroup
 }
 
 // InitResult is the indexer initialization result and includes the indexer's listener implementation.
@@ -75,4 +185,30 @@ type InitResult struct {
 	// will attempt to perform a catch-up sync of state. Historical events will not be replayed, but an accurate
 	// representation of the current state at the height at which indexing began can be reproduced.
 	LastBlockPersisted int64
+
+	// View is a view of the data which the indexer has persisted, if it supports querying it. It may be nil.
+	View view.AppData
+
+	// OnRestore is called when the node's state has been restored from a snapshot, for instance with state
+	// sync, with the checkpoint of the restored state. Indexers can use it to verify that they can continue
+	// from the restored height with the restored module schemas, or to record where their data resumes.
+	// It may be nil.
+	OnRestore func(Checkpoint) error
+
+	// CheckMigration is called by Manager.CheckSchemaUpgrade ahead of a chain upgrade with each module schema
+	// which will change in the upgrade and should return an error if the indexer can't migrate its data to the
+	// next schema, for instance because a column type would change. If it is nil, the next schema must be
+	// compatible with the current one according to schema.ModuleSchema.CompatibleWith.
+	CheckMigration func(SchemaMigration) error
+
+	// DeadLetterStore is the store, for instance a table in the indexer's database, which dead-lettered
+	// object updates are written to if dead-lettering is enabled without a file. It may be nil.
+	DeadLetterStore appdata.DeadLetterStore
+
+	// Ordering is the ordering of object updates which the indexer requires, see appdata.OrderedListener.
+	// By default, the indexer receives all packets one at a time in order. Indexers which only require the
+	// updates of each object to be in order can request appdata.PerKeyOrdering with several workers to
+	// apply the updates of different objects concurrently. The dead-letter store must then be safe for
+	// concurrent use.
+	Ordering appdata.OrderingOptions
 }
@@ -2,7 +2,13 @@ package indexer
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
 
+	"cosmossdk.io/schema"
 	"cosmossdk.io/schema/appdata"
 	"cosmossdk.io/schema/decoding"
 	"cosmossdk.io/schema/logutil"
@@ -14,7 +20,8 @@ type ManagerOptions struct {
 	// and match the json structure of ManagerConfig. The manager will attempt to convert it to ManagerConfig.
 	Config interface{}
 
-	// Resolver is the decoder resolver that will be used to decode the data. It is required.
+	// Resolver is the decoder resolver that will be used to decode the data. If it is nil, indexers will not
+	// receive decoded data.
 	Resolver decoding.DecoderResolver
 
 	// SyncSource is a representation of the current state of key-value data to be used in a catch-up sync.
@@ -22,6 +29,19 @@ type ManagerOptions struct {
 	// it is omitted, indexers will only be able to start indexing state from genesis.
 	SyncSource decoding.SyncSource
 
+	// BackfillSource is the source of historical blocks, such as a journal, an archive node or historical store
+	// versions, which is used to replay the blocks missed by targets which have backfill enabled. It is optional
+	// but if it is omitted, targets with backfill enabled will fail to start on a chain which is ahead of them.
+	BackfillSource appdata.CatchUpSource
+
+	// StorePaths, if set, records the store path of every decoded object update so that the objects served by
+	// the targets can be proven against the app hash. It is optional.
+	StorePaths *StorePathIndex
+
+	// Metrics are the callbacks through which the manager reports metrics of the indexing pipeline. They
+	// are optional.
+	Metrics Metrics
+
 	// Logger is the logger that indexers can use to write logs. It is optional.
 	Logger logutil.Logger
 
@@ -31,14 +51,594 @@ type ManagerOptions struct {
 	Context context.Context
 }
 
+// Metrics are callbacks through which the manager reports metrics of the indexing pipeline, so that apps can
+// export them with their telemetry system. All callbacks are optional and must be safe for concurrent use.
+type Metrics struct {
+	// OnPacket is called with each packet delivered to the manager before it is dispatched to the targets.
+	OnPacket func(appdata.Packet)
+
+	// OnDecodeLatency is called with the time taken to decode each key-value pair of a module.
+	OnDecodeLatency func(moduleName string, latency time.Duration)
+
+	// OnCommitLatency is called with the time taken by a target to commit a block.
+	OnCommitLatency func(target string, latency time.Duration)
+
+	// OnCommittedHeight is called with the height of the last block committed by a target every time it
+	// commits a block.
+	OnCommittedHeight func(target string, height int64)
+}
+
 // ManagerConfig is the configuration of the indexer manager and contains the configuration for each indexer target.
 type ManagerConfig struct {
 	// Target is a map of named indexer targets to their configuration.
-	Target map[string]Config
+	Target map[string]Config `json:"target"`
+
+	// IncludeModules, if non-empty, is the list of modules whose state changes are streamed to all targets.
+	// Only one of include or exclude modules should be specified.
+	IncludeModules []string `json:"include_modules"`
+
+	// ExcludeModules is a list of modules whose state changes are not streamed to any target.
+	// Only one of include or exclude modules should be specified.
+	ExcludeModules []string `json:"exclude_modules"`
+}
+
+// IncludesModule reports whether the state changes of moduleName are streamed to the targets according to
+// the IncludeModules and ExcludeModules options.
+func (c ManagerConfig) IncludesModule(moduleName string) bool {
+	if len(c.IncludeModules) != 0 && !containsString(c.IncludeModules, moduleName) {
+		return false
+	}
+	return !containsString(c.ExcludeModules, moduleName)
+}
+
+func containsString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrManagerStopped is returned by the listener of a Manager after Stop has been called.
+var ErrManagerStopped = errors.New("indexer manager stopped")
+
+// Manager manages the lifecycle of the configured indexer targets. It starts them, delivers app data to them,
+// tracks their health and shuts them down gracefully.
+type Manager struct {
+	logger   logutil.Logger
+	metrics  Metrics
+	config   ManagerConfig
+	resolver decoding.DecoderResolver
+	schemas  *decoding.SchemaInvalidator
+	cancel   context.CancelFunc
+	done     *sync.WaitGroup
+	targets  []*target
+	listener appdata.Listener
+
+	// buffers are the queues of the targets with a buffer size, which are drained before the targets are shut down
+	bufferCtx    context.Context
+	bufferCancel context.CancelFunc
+	buffers      *sync.WaitGroup
+
+	// mu is held while a packet is delivered so that Stop can wait for in-flight deliveries
+	mu      sync.Mutex
+	stopped bool
+
+	healthMu   sync.Mutex
+	headHeight uint64
+}
+
+// TargetHealth describes the health of an indexer target.
+type TargetHealth struct {
+	// Name is the name of the target in ManagerConfig.
+	Name string
+
+	// Type is the indexer type of the target.
+	Type string
+
+	// LastCommittedHeight is the height of the last block committed by the target. It is initially the
+	// LastBlockPersisted reported by the indexer and -1 if the indexer does not persist data.
+	LastCommittedHeight int64
+
+	// Lag is the number of blocks the target is behind the latest block delivered to the manager.
+	// It is always zero for indexers which do not persist data.
+	Lag uint64
+
+	// BlocksProcessed is the number of blocks committed by the target since the manager was started.
+	BlocksProcessed uint64
+
+	// LastError is the last error returned by the target or nil if it has not returned any error.
+	LastError error
+
+	// LastErrorTime is the time at which LastError was returned.
+	LastErrorTime time.Time
+
+	// DeadLetters is the number of object updates which were routed to the target's dead-letter store since
+	// the manager was started.
+	DeadLetters uint64
+}
+
+type target struct {
+	name      string
+	typ       string
+	cfg       Config
+	onRestore func(Checkpoint) error
+	committed int64
+	current   int64
+	processed uint64
+	lastErr   error
+	lastErrAt time.Time
+
+	deadLetters    uint64
+	checkMigration func(SchemaMigration) error
 }
 
 // StartManager starts the indexer manager with the given options. The state machine should write all relevant app data to
-// the returned listener.
-func StartManager(opts ManagerOptions) (appdata.Listener, error) {
-	panic("TODO: this will be implemented in a follow-up PR, this function is just a stub to demonstrate the API")
+// the listener returned by Manager.Listener.
+func StartManager(opts ManagerOptions) (*Manager, error) {
+	logger := opts.Logger
+	if logger == nil {
+		logger = logutil.NoopLogger{}
+	}
+
+	parentCtx := opts.Context
+	if parentCtx == nil {
+		parentCtx = context.Background()
+	}
+
+	cfg, err := ParseManagerConfig(opts.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(parentCtx)
+	bufferCtx, bufferCancel := context.WithCancel(ctx)
+	m := &Manager{
+		logger:       logger,
+		metrics:      opts.Metrics,
+		config:       cfg,
+		resolver:     opts.Resolver,
+		schemas:      &decoding.SchemaInvalidator{},
+		cancel:       cancel,
+		done:         &sync.WaitGroup{},
+		bufferCtx:    bufferCtx,
+		bufferCancel: bufferCancel,
+		buffers:      &sync.WaitGroup{},
+	}
+
+	names := make([]string, 0, len(cfg.Target))
+	for name := range cfg.Target {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	listeners := make([]appdata.Listener, 0, len(names))
+	for _, name := range names {
+		targetCfg := cfg.Target[name]
+		listener, err := m.startTarget(ctx, name, targetCfg, opts)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to start indexer target %q: %v", name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		listeners = append(listeners, listener)
+	}
+
+	if opts.StorePaths != nil {
+		listeners = append(listeners, opts.StorePaths.Listener())
+	}
+
+	listener := appdata.FanOut(appdata.FanOutOptions{}, listeners...)
+	if opts.Resolver != nil {
+		listener, err = decoding.Middleware(listener, opts.Resolver, decoding.MiddlewareOptions{
+			OnDecodeLatency: opts.Metrics.OnDecodeLatency,
+			Invalidator:     m.schemas,
+		})
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+	listener = appdata.Filter(listener, appdata.FilterOptions{
+		Modules:        cfg.IncludeModules,
+		ExcludeModules: cfg.ExcludeModules,
+	})
+	m.listener = m.guard(listener)
+
+	return m, nil
+}
+
+func (m *Manager) startTarget(ctx context.Context, name string, cfg Config, opts ManagerOptions) (appdata.Listener, error) {
+	initFunc, ok := indexerRegistry[cfg.Type]
+	if !ok {
+		return appdata.Listener{}, fmt.Errorf("indexer type %q not found", cfg.Type)
+	}
+
+	res, err := initFunc(InitParams{
+		Config:        cfg,
+		Context:       ctx,
+		Logger:        m.logger,
+		DoneWaitGroup: m.done,
+	})
+	if err != nil {
+		return appdata.Listener{}, err
+	}
+
+	t := &target{
+		name:           name,
+		typ:            cfg.Type,
+		cfg:            cfg,
+		onRestore:      res.OnRestore,
+		checkMigration: res.CheckMigration,
+		committed:      res.LastBlockPersisted,
+		current:        -1,
+	}
+	m.targets = append(m.targets, t)
+	m.logger.Info("started indexer target", "target", name, "type", cfg.Type, "last_block_persisted", res.LastBlockPersisted)
+
+	if cfg.Chunking != nil {
+		res.Listener, err = appdata.ChunkBytes(res.Listener, appdata.ChunkOptions{
+			Threshold: cfg.Chunking.Threshold,
+			ChunkSize: cfg.Chunking.ChunkSize,
+		})
+		if err != nil {
+			return appdata.Listener{}, err
+		}
+	}
+
+	listener, err := m.deadLetterTarget(ctx, t, cfg, res)
+	if err != nil {
+		return appdata.Listener{}, err
+	}
+	if cfg.BufferSize > 0 {
+		listener = appdata.AsyncListenerWithBackpressure(appdata.AsyncListenerOptions{
+			Context:       m.bufferCtx,
+			BufferSize:    cfg.BufferSize,
+			DoneWaitGroup: m.buffers,
+			DrainOnDone:   true,
+		}, listener)
+	}
+	listener = appdata.OrderedListener(listener, res.Ordering)
+
+	listener, err = limitTarget(listener, cfg)
+	if err != nil {
+		return appdata.Listener{}, err
+	}
+
+	listener, err = transformTarget(listener, cfg)
+	if err != nil {
+		return appdata.Listener{}, err
+	}
+
+	listener = m.trackHealth(t, filterTarget(listener, cfg))
+	return m.backfillTarget(name, cfg, res.LastBlockPersisted, listener, opts), nil
+}
+
+// filterTarget applies the common filtering options of the target config to the indexer's listener.
+func filterTarget(listener appdata.Listener, cfg Config) appdata.Listener {
+	if cfg.ExcludeState {
+		listener.InitializeModuleData = nil
+		listener.OnKVPair = nil
+		listener.OnObjectUpdate = nil
+	}
+
+	if cfg.ExcludeEvents {
+		listener.OnEvent = nil
+	}
+
+	if cfg.ExcludeTxs {
+		listener.OnTx = nil
+	}
+
+	if !cfg.IncludeSimulations {
+		listener.OnSimulation = nil
+	}
+
+	if cfg.ExcludeBlockHeaders && listener.StartBlock != nil {
+		startBlock := listener.StartBlock
+		listener.StartBlock = func(data appdata.StartBlockData) error {
+			data.HeaderBytes = nil
+			data.HeaderJSON = nil
+			data.Header = nil
+			return startBlock(data)
+		}
+	}
+
+	return appdata.Filter(listener, appdata.FilterOptions{
+		Modules:        cfg.IncludeModules,
+		ExcludeModules: cfg.ExcludeModules,
+	})
+}
+
+// limitTarget applies the size caps and rate limits of the target config to the indexer's listener.
+func limitTarget(listener appdata.Listener, cfg Config) (appdata.Listener, error) {
+	if cfg.Limits == nil {
+		return listener, nil
+	}
+
+	opts := appdata.LimitOptions{
+		MaxEventAttributeSize: cfg.Limits.MaxEventAttributeSize,
+		MaxEventDataSize:      cfg.Limits.MaxEventDataSize,
+		MaxTxLogSize:          cfg.Limits.MaxTxLogSize,
+		MaxEventsPerBlock:     cfg.Limits.MaxEventsPerBlock,
+	}
+	switch cfg.Limits.Overflow {
+	case "", "truncate":
+		opts.Policy = appdata.CapTruncate
+	case "drop":
+		opts.Policy = appdata.CapDrop
+	default:
+		return appdata.Listener{}, fmt.Errorf("invalid limits.overflow %q, expected truncate or drop", cfg.Limits.Overflow)
+	}
+	return appdata.Limit(listener, opts)
+}
+
+// transformTarget applies the field transformations of the target config to the indexer's listener.
+func transformTarget(listener appdata.Listener, cfg Config) (appdata.Listener, error) {
+	if cfg.Transforms == nil {
+		return listener, nil
+	}
+
+	opts := appdata.TransformOptions{TxMemo: appdata.TransformAction(cfg.Transforms.TxMemo)}
+	for _, field := range cfg.Transforms.Fields {
+		opts.Fields = append(opts.Fields, appdata.FieldTransform{
+			ModuleName: field.Module,
+			TypeName:   field.Type,
+			FieldName:  field.Field,
+			Action:     appdata.TransformAction(field.Action),
+		})
+	}
+	return appdata.TransformFields(listener, opts)
+}
+
+// trackHealth wraps the listener of a target so that its commits and errors are recorded.
+func (m *Manager) trackHealth(t *target, listener appdata.Listener) appdata.Listener {
+	record := func(err error) error {
+		if err != nil {
+			m.healthMu.Lock()
+			t.lastErr = err
+			t.lastErrAt = time.Now()
+			m.healthMu.Unlock()
+			m.logger.Error("indexer target error", "target", t.name, "error", err)
+		}
+		return err
+	}
+
+	wrap := func(p appdata.Packet) error {
+		return record(listener.SendPacket(p))
+	}
+
+	res := appdata.Listener{
+		StartBlock: func(data appdata.StartBlockData) error {
+			m.healthMu.Lock()
+			t.current = int64(data.Height)
+			m.healthMu.Unlock()
+			return wrap(data)
+		},
+		Commit: func(data appdata.CommitData) error {
+			start := time.Now()
+			if err := wrap(data); err != nil {
+				return err
+			}
+			if m.metrics.OnCommitLatency != nil {
+				m.metrics.OnCommitLatency(t.name, time.Since(start))
+			}
+
+			m.healthMu.Lock()
+			if t.current >= 0 && t.committed >= 0 {
+				t.committed = t.current
+			}
+			t.current = -1
+			t.processed++
+			committed := t.committed
+			m.healthMu.Unlock()
+
+			if m.metrics.OnCommittedHeight != nil {
+				m.metrics.OnCommittedHeight(t.name, committed)
+			}
+			return nil
+		},
+	}
+	if listener.InitializeModuleData != nil {
+		res.InitializeModuleData = func(data appdata.ModuleInitializationData) error { return wrap(data) }
+	}
+	if listener.OnTx != nil {
+		res.OnTx = func(data appdata.TxData) error { return wrap(data) }
+	}
+	if listener.OnEvent != nil {
+		res.OnEvent = func(data appdata.EventData) error { return wrap(data) }
+	}
+	if listener.OnKVPair != nil {
+		res.OnKVPair = func(data appdata.KVPairData) error { return wrap(data) }
+	}
+	if listener.OnObjectUpdate != nil {
+		res.OnObjectUpdate = func(data appdata.ObjectUpdateData) error { return wrap(data) }
+	}
+	if listener.OnSimulation != nil {
+		res.OnSimulation = func(data appdata.SimulationData) error { return wrap(data) }
+	}
+	return res
+}
+
+// guard wraps the listener so that deliveries are serialized with Stop and rejected after it, and so that the
+// height of the latest block is recorded.
+func (m *Manager) guard(listener appdata.Listener) appdata.Listener {
+	deliver := func(p appdata.Packet) error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.stopped {
+			return ErrManagerStopped
+		}
+		if m.metrics.OnPacket != nil {
+			m.metrics.OnPacket(p)
+		}
+		return listener.SendPacket(p)
+	}
+
+	res := appdata.Listener{
+		StartBlock: func(data appdata.StartBlockData) error {
+			m.healthMu.Lock()
+			m.headHeight = data.Height
+			m.healthMu.Unlock()
+			return deliver(data)
+		},
+	}
+	if listener.InitializeModuleData != nil {
+		res.InitializeModuleData = func(data appdata.ModuleInitializationData) error { return deliver(data) }
+	}
+	if listener.OnTx != nil {
+		res.OnTx = func(data appdata.TxData) error { return deliver(data) }
+	}
+	if listener.OnEvent != nil {
+		res.OnEvent = func(data appdata.EventData) error { return deliver(data) }
+	}
+	if listener.OnKVPair != nil {
+		res.OnKVPair = func(data appdata.KVPairData) error { return deliver(data) }
+	}
+	if listener.OnObjectUpdate != nil {
+		res.OnObjectUpdate = func(data appdata.ObjectUpdateData) error { return deliver(data) }
+	}
+	if listener.Commit != nil {
+		res.Commit = func(data appdata.CommitData) error { return deliver(data) }
+	}
+	if listener.OnSimulation != nil {
+		res.OnSimulation = func(data appdata.SimulationData) error { return deliver(data) }
+	}
+	return res
+}
+
+// Listener returns the listener which the state machine should write all app data to.
+func (m *Manager) Listener() appdata.Listener {
+	return m.listener
+}
+
+// IncludesModule reports whether the state changes of moduleName are streamed to the targets, so that the
+// app can avoid collecting the state changes of modules which are disabled in the manager's configuration.
+func (m *Manager) IncludesModule(moduleName string) bool {
+	return m.config.IncludesModule(moduleName)
+}
+
+// InvalidateSchemas signals that the schemas of the modules changed at runtime, for instance in an upgrade
+// handler, so that the modules which have already been initialized are initialized again with their current
+// schema and ModuleInitializationData.SchemaChanged set right after the next block is started. This lets
+// long-lived targets, such as remote indexers, learn about new object types without a restart.
+func (m *Manager) InvalidateSchemas(moduleNames ...string) {
+	m.schemas.Invalidate(moduleNames...)
+}
+
+// Health returns the health of each indexer target sorted by target name.
+func (m *Manager) Health() []TargetHealth {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+
+	res := make([]TargetHealth, len(m.targets))
+	for i, t := range m.targets {
+		var lag uint64
+		if t.committed >= 0 && int64(m.headHeight) > t.committed {
+			lag = m.headHeight - uint64(t.committed)
+		}
+
+		res[i] = TargetHealth{
+			Name:                t.name,
+			Type:                t.typ,
+			LastCommittedHeight: t.committed,
+			Lag:                 lag,
+			BlocksProcessed:     t.processed,
+			LastError:           t.lastErr,
+			LastErrorTime:       t.lastErrAt,
+			DeadLetters:         t.deadLetters,
+		}
+	}
+	return res
+}
+
+// Checkpoint returns the checkpoint of the state at height, with the schema fingerprints of the modules
+// resolved by the manager's decoder resolver. It is used to embed the checkpoint in state snapshots.
+func (m *Manager) Checkpoint(height int64) (Checkpoint, error) {
+	return NewCheckpoint(height, m.resolver)
+}
+
+// Restore notifies the indexer targets that the node's state has been restored from a snapshot which
+// corresponds to checkpoint, by calling the OnRestore callback of each target which has one. Modules whose
+// current schema doesn't match their schema in the checkpoint are logged, as indexers will receive data for
+// the current schema from the restored height on.
+func (m *Manager) Restore(checkpoint Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopped {
+		return ErrManagerStopped
+	}
+
+	if m.resolver != nil {
+		err := m.resolver.IterateAll(func(moduleName string, cdc schema.ModuleCodec) error {
+			if !checkpoint.SchemaMatches(moduleName, cdc.Schema) {
+				m.logger.Warn("module schema differs from the restored snapshot", "module", moduleName, "height", checkpoint.Height)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	m.healthMu.Lock()
+	if checkpoint.Height > 0 {
+		m.headHeight = uint64(checkpoint.Height)
+	}
+	m.healthMu.Unlock()
+
+	m.logger.Info("restored indexer checkpoint", "height", checkpoint.Height, "modules", len(checkpoint.SchemaFingerprints))
+	for _, t := range m.targets {
+		if t.onRestore == nil {
+			continue
+		}
+		if err := t.onRestore(checkpoint); err != nil {
+			m.healthMu.Lock()
+			t.lastErr = err
+			t.lastErrAt = time.Now()
+			m.healthMu.Unlock()
+			return fmt.Errorf("indexer target %q failed to restore checkpoint at height %d: %v", t.name, checkpoint.Height, err) //nolint:errorlint // false positive due to using go1.12
+		}
+	}
+	return nil
+}
+
+// Stop shuts down the indexer targets gracefully. It waits for the packet which is being delivered, if any, so
+// that no packet is delivered partially, rejects further packets with ErrManagerStopped, waits until the
+// packets buffered for targets with a buffer size have been delivered, cancels the context passed to the
+// indexers and waits until they have marked InitParams.DoneWaitGroup as done, for instance after flushing
+// in-flight batches and closing their connections. If ctx is done first, its error is returned.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	m.stopped = true
+	m.mu.Unlock()
+
+	m.bufferCancel()
+	if err := waitContext(ctx, m.buffers); err != nil {
+		m.cancel()
+		return err
+	}
+
+	m.cancel()
+	if err := waitContext(ctx, m.done); err != nil {
+		return err
+	}
+	m.logger.Info("stopped indexer targets")
+	return nil
+}
+
+// waitContext waits for wg or until ctx is done, in which case its error is returned.
+func waitContext(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
@@ -0,0 +1,420 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+)
+
+type testIndexer struct {
+	txs      int
+	kvPairs  []string
+	commits  int
+	failAt   uint64
+	height   uint64
+	shutdown bool
+}
+
+func (i *testIndexer) init(params InitParams) (InitResult, error) {
+	params.DoneWaitGroup.Add(1)
+	go func() {
+		defer params.DoneWaitGroup.Done()
+		<-params.Context.Done()
+		// simulate flushing in-flight data
+		time.Sleep(10 * time.Millisecond)
+		i.shutdown = true
+	}()
+
+	return InitResult{
+		Listener: appdata.Listener{
+			StartBlock: func(data appdata.StartBlockData) error {
+				i.height = data.Height
+				return nil
+			},
+			OnTx: func(appdata.TxData) error {
+				i.txs++
+				return nil
+			},
+			OnKVPair: func(data appdata.KVPairData) error {
+				for _, update := range data.Updates {
+					i.kvPairs = append(i.kvPairs, update.ModuleName)
+				}
+				return nil
+			},
+			Commit: func(appdata.CommitData) error {
+				if i.failAt != 0 && i.height >= i.failAt {
+					return errors.New("commit failed")
+				}
+				i.commits++
+				return nil
+			},
+		},
+		LastBlockPersisted: 1,
+	}, nil
+}
+
+func TestManager(t *testing.T) {
+	filtered := &testIndexer{}
+	failing := &testIndexer{failAt: 3}
+	Register("manager_test_filtered", filtered.init)
+	Register("manager_test_failing", failing.init)
+
+	manager, err := StartManager(ManagerOptions{
+		Config: map[string]interface{}{
+			"target": map[string]interface{}{
+				"filtered": map[string]interface{}{
+					"type":            "manager_test_filtered",
+					"exclude_txs":     true,
+					"include_modules": []interface{}{"bank"},
+				},
+				"failing": map[string]interface{}{
+					"type": "manager_test_failing",
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	listener := manager.Listener()
+	sendBlock := func(height uint64) error {
+		packets := []appdata.Packet{
+			appdata.StartBlockData{Height: height},
+			appdata.TxData{},
+			appdata.KVPairData{Updates: []appdata.ModuleKVPairUpdate{{ModuleName: "bank"}, {ModuleName: "gov"}}},
+			appdata.CommitData{},
+		}
+		for _, p := range packets {
+			if err := listener.SendPacket(p); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := sendBlock(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filtered.txs != 0 || len(filtered.kvPairs) != 1 || filtered.kvPairs[0] != "bank" {
+		t.Fatalf("expected txs and gov kv pairs to be filtered, got %d txs and %v", filtered.txs, filtered.kvPairs)
+	}
+	if failing.txs != 1 || len(failing.kvPairs) != 2 {
+		t.Fatalf("expected unfiltered data, got %d txs and %v", failing.txs, failing.kvPairs)
+	}
+
+	if err := sendBlock(3); err == nil {
+		t.Fatalf("expected commit error")
+	}
+
+	health := manager.Health()
+	if len(health) != 2 || health[0].Name != "failing" || health[1].Name != "filtered" {
+		t.Fatalf("expected health of both targets sorted by name, got %v", health)
+	}
+	if h := health[0]; h.LastCommittedHeight != 2 || h.Lag != 1 || h.BlocksProcessed != 1 || h.LastError == nil || h.LastErrorTime.IsZero() {
+		t.Fatalf("unexpected health of the failing target: %+v", h)
+	}
+	// the failing target is called first so the filtered one did not receive the commit of block 3
+	if h := health[1]; h.Type != "manager_test_filtered" || h.LastCommittedHeight != 2 || h.BlocksProcessed != 1 || h.LastError != nil {
+		t.Fatalf("unexpected health of the filtered target: %+v", h)
+	}
+
+	if err := manager.Stop(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !filtered.shutdown || !failing.shutdown {
+		t.Fatalf("expected indexers to be shut down")
+	}
+	if err := listener.Commit(appdata.CommitData{}); err != ErrManagerStopped { //nolint:errorlint // false positive due to using go1.12
+		t.Fatalf("expected ErrManagerStopped, got: %v", err)
+	}
+
+	t.Run("unknown indexer type", func(t *testing.T) {
+		_, err := StartManager(ManagerOptions{
+			Config: ManagerConfig{Target: map[string]Config{"foo": {Type: "unknown"}}},
+		})
+		if err == nil || !strings.Contains(err.Error(), "indexer type \"unknown\" not found") {
+			t.Fatalf("expected unknown indexer type error, got: %v", err)
+		}
+	})
+
+	t.Run("include and exclude modules", func(t *testing.T) {
+		_, err := StartManager(ManagerOptions{
+			Config: ManagerConfig{Target: map[string]Config{"foo": {
+				Type:           "manager_test_filtered",
+				IncludeModules: []string{"bank"},
+				ExcludeModules: []string{"gov"},
+			}}},
+		})
+		if err == nil || !strings.Contains(err.Error(), "only one of include_modules or exclude_modules") {
+			t.Fatalf("expected include and exclude modules error, got: %v", err)
+		}
+
+		_, err = StartManager(ManagerOptions{
+			Config: ManagerConfig{IncludeModules: []string{"bank"}, ExcludeModules: []string{"gov"}},
+		})
+		if err == nil || !strings.Contains(err.Error(), "only one of include_modules or exclude_modules") {
+			t.Fatalf("expected include and exclude modules error, got: %v", err)
+		}
+	})
+
+	t.Run("module configuration applies to all targets", func(t *testing.T) {
+		excluded := &testIndexer{}
+		Register("manager_test_excluded", excluded.init)
+
+		manager, err := StartManager(ManagerOptions{
+			Config: map[string]interface{}{
+				"exclude_modules": []interface{}{"gov"},
+				"target": map[string]interface{}{
+					"excluded": map[string]interface{}{"type": "manager_test_excluded"},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !manager.IncludesModule("bank") || manager.IncludesModule("gov") {
+			t.Fatalf("expected only gov to be excluded")
+		}
+
+		err = manager.Listener().SendPacket(appdata.KVPairData{Updates: []appdata.ModuleKVPairUpdate{{ModuleName: "bank"}, {ModuleName: "gov"}}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(excluded.kvPairs) != 1 || excluded.kvPairs[0] != "bank" {
+			t.Fatalf("expected gov kv pairs to be filtered, got %v", excluded.kvPairs)
+		}
+		if err := manager.Stop(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestManager_Metrics(t *testing.T) {
+	indexer := &testIndexer{}
+	Register("manager_test_metrics", indexer.init)
+
+	packets := map[string]int{}
+	commitLatencies := 0
+	heights := map[string]int64{}
+	manager, err := StartManager(ManagerOptions{
+		Config: ManagerConfig{Target: map[string]Config{"metrics": {Type: "manager_test_metrics"}}},
+		Metrics: Metrics{
+			OnPacket: func(p appdata.Packet) { packets[appdata.PacketType(p)]++ },
+			OnCommitLatency: func(target string, _ time.Duration) {
+				if target != "metrics" {
+					t.Fatalf("unexpected target %q", target)
+				}
+				commitLatencies++
+			},
+			OnCommittedHeight: func(target string, height int64) { heights[target] = height },
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	listener := manager.Listener()
+	for _, p := range []appdata.Packet{appdata.StartBlockData{Height: 2}, appdata.TxData{}, appdata.TxData{}, appdata.CommitData{}} {
+		if err := listener.SendPacket(p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := map[string]int{"start_block": 1, "tx": 2, "commit": 1}
+	if !reflect.DeepEqual(packets, expected) {
+		t.Fatalf("expected packets %v, got %v", expected, packets)
+	}
+	if commitLatencies != 1 || heights["metrics"] != 2 {
+		t.Fatalf("expected one commit at height 2, got %d commits and heights %v", commitLatencies, heights)
+	}
+
+	if err := manager.Stop(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestManager_PerKeyOrdering(t *testing.T) {
+	var mu sync.Mutex
+	applied := 0
+	Register("manager_test_per_key", func(InitParams) (InitResult, error) {
+		return InitResult{
+			Listener: appdata.Listener{
+				OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+					mu.Lock()
+					defer mu.Unlock()
+					applied += len(data.Updates)
+					return nil
+				},
+				Commit: func(appdata.CommitData) error {
+					if applied != 3 {
+						return fmt.Errorf("expected all updates to be applied before commit, got %d", applied)
+					}
+					return nil
+				},
+			},
+			LastBlockPersisted: -1,
+			Ordering:           appdata.OrderingOptions{Ordering: appdata.PerKeyOrdering, Workers: 2},
+		}, nil
+	})
+
+	manager, err := StartManager(ManagerOptions{
+		Config: ManagerConfig{Target: map[string]Config{"per_key": {Type: "manager_test_per_key"}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updates := appdata.ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{
+		{TypeName: "balances", Key: "a"},
+		{TypeName: "balances", Key: "b"},
+		{TypeName: "balances", Key: "a"},
+	}}
+	for _, p := range []appdata.Packet{appdata.StartBlockData{Height: 1}, updates, appdata.CommitData{}} {
+		if err := manager.Listener().SendPacket(p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := manager.Stop(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestManager_Transforms(t *testing.T) {
+	var memos []string
+	Register("manager_test_transforms", func(InitParams) (InitResult, error) {
+		return InitResult{
+			Listener: appdata.Listener{
+				OnTx: func(data appdata.TxData) error {
+					if data.Bytes != nil {
+						return errors.New("expected the raw bytes of the transaction to be dropped")
+					}
+					tx, err := data.Decoded()
+					if err != nil {
+						return err
+					}
+					memos = append(memos, tx.Memo)
+					return nil
+				},
+			},
+			LastBlockPersisted: -1,
+		}, nil
+	})
+
+	manager, err := StartManager(ManagerOptions{
+		Config: map[string]interface{}{
+			"target": map[string]interface{}{
+				"transforms": map[string]interface{}{
+					"type":       "manager_test_transforms",
+					"transforms": map[string]interface{}{"tx_memo": "redact"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tx := appdata.TxData{
+		Bytes:   func() ([]byte, error) { return []byte("tx"), nil },
+		Decoded: func() (appdata.DecodedTx, error) { return appdata.DecodedTx{Memo: "secret"}, nil },
+	}
+	for _, p := range []appdata.Packet{appdata.StartBlockData{Height: 1}, tx, appdata.CommitData{}} {
+		if err := manager.Listener().SendPacket(p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if !reflect.DeepEqual(memos, []string{""}) {
+		t.Fatalf("expected a redacted memo, got %v", memos)
+	}
+
+	if err := manager.Stop(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = StartManager(ManagerOptions{
+		Config: ManagerConfig{Target: map[string]Config{"invalid": {
+			Type:       "manager_test_transforms",
+			Transforms: &TransformConfig{TxMemo: "encrypt"},
+		}}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "unknown transform action") {
+		t.Fatalf("expected an unknown transform action error, got %v", err)
+	}
+}
+
+func TestManager_Limits(t *testing.T) {
+	Register("manager_test_limits", func(InitParams) (InitResult, error) {
+		return InitResult{LastBlockPersisted: -1}, nil
+	})
+
+	manager, err := StartManager(ManagerOptions{
+		Config: ManagerConfig{Target: map[string]Config{"limits": {
+			Type:   "manager_test_limits",
+			Limits: &LimitsConfig{MaxEventAttributeSize: 1024, Overflow: "truncate"},
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := manager.Stop(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = StartManager(ManagerOptions{
+		Config: ManagerConfig{Target: map[string]Config{"invalid": {
+			Type:   "manager_test_limits",
+			Limits: &LimitsConfig{MaxEventAttributeSize: 1024, Overflow: "compress"},
+		}}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid limits.overflow") {
+		t.Fatalf("expected an invalid overflow error, got %v", err)
+	}
+}
+
+func TestManager_Simulations(t *testing.T) {
+	simulations := map[string]int{}
+	Register("manager_test_simulations", func(params InitParams) (InitResult, error) {
+		name := params.Config.Config["name"].(string)
+		return InitResult{
+			Listener: appdata.Listener{
+				OnSimulation: func(appdata.SimulationData) error {
+					simulations[name]++
+					return nil
+				},
+			},
+			LastBlockPersisted: -1,
+		}, nil
+	})
+
+	manager, err := StartManager(ManagerOptions{
+		Config: ManagerConfig{Target: map[string]Config{
+			"included": {Type: "manager_test_simulations", IncludeSimulations: true, Config: map[string]interface{}{"name": "included"}},
+			"excluded": {Type: "manager_test_simulations", Config: map[string]interface{}{"name": "excluded"}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// simulations are delivered outside of blocks
+	if err := manager.Listener().SendPacket(appdata.SimulationData{Mode: appdata.CheckTxMode}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(simulations, map[string]int{"included": 1}) {
+		t.Fatalf("expected only the target which includes simulations to receive them, got %v", simulations)
+	}
+
+	if err := manager.Stop(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+// Package memory provides an in-memory indexer target which implements view.AppData and keeps snapshots of
+// the app state at the most recent committed heights. Each object stores a version per height at which it was
+// written, so snapshots share the objects which didn't change and writing a block only costs as much as its
+// updates. It has no external dependencies and is useful for tests and for serving queries of the latest
+// blocks.
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cosmossdk.io/schema/indexer"
+)
+
+// IndexerType is the type of the in-memory indexer target in the indexer config.
+const IndexerType = "memory"
+
+func init() {
+	indexer.Register(IndexerType, Init)
+}
+
+// Options are the options of an in-memory app data store, which are also the indexer specific config of an
+// in-memory indexer target.
+type Options struct {
+	// MaxHistory is the number of the most recent committed heights whose snapshots can be read with ReadAt,
+	// including the latest one. The versions of objects which are only visible at older heights are pruned.
+	// If it is zero, only the latest height is retained.
+	MaxHistory int `json:"max_history"`
+}
+
+// Init initializes an in-memory indexer target from the indexer config. Since the data isn't persisted, the
+// target starts from an empty state.
+func Init(params indexer.InitParams) (indexer.InitResult, error) {
+	var opts Options
+	if params.Config.Config != nil {
+		bz, err := json.Marshal(params.Config.Config)
+		if err != nil {
+			return indexer.InitResult{}, fmt.Errorf("invalid memory indexer config: %v", err) //nolint:errorlint // false positive due to using go1.12
+		}
+		if err := json.Unmarshal(bz, &opts); err != nil {
+			return indexer.InitResult{}, fmt.Errorf("invalid memory indexer config: %v", err) //nolint:errorlint // false positive due to using go1.12
+		}
+	}
+
+	data, err := New(opts)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+	return indexer.InitResult{Listener: data.Listener(), View: data}, nil
+}
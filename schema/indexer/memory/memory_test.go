@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/schema/view"
+)
+
+var testSchema = func() schema.ModuleSchema {
+	modSchema, err := schema.NewModuleSchema([]schema.ObjectType{
+		{
+			Name:      "balances",
+			KeyFields: []schema.Field{{Name: "address", Kind: schema.StringKind}},
+			ValueFields: []schema.Field{
+				{Name: "amount", Kind: schema.Uint64Kind},
+				{Name: "locked", Kind: schema.Uint64Kind},
+			},
+		},
+		{
+			Name:            "proposals",
+			KeyFields:       []schema.Field{{Name: "id", Kind: schema.Uint64Kind}},
+			ValueFields:     []schema.Field{{Name: "title", Kind: schema.StringKind}},
+			RetainDeletions: true,
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return modSchema
+}()
+
+func sendBlock(t *testing.T, listener appdata.Listener, height uint64, updates ...schema.ObjectUpdate) {
+	t.Helper()
+	packets := []appdata.Packet{
+		appdata.StartBlockData{Height: height},
+		appdata.ObjectUpdateData{ModuleName: "bank", Updates: updates},
+		appdata.CommitData{},
+	}
+	for _, p := range packets {
+		if err := listener.SendPacket(p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func objects(t *testing.T, data view.AppData, typeName string) []schema.ObjectUpdate {
+	t.Helper()
+	mod, err := data.AppState().GetModule("bank")
+	if err != nil || mod == nil {
+		t.Fatalf("expected module bank, got %v", err)
+	}
+	coll, err := mod.GetObjectCollection(typeName)
+	if err != nil || coll == nil {
+		t.Fatalf("expected collection %s, got %v", typeName, err)
+	}
+
+	var res []schema.ObjectUpdate
+	coll.AllState(func(update schema.ObjectUpdate, err error) bool {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		res = append(res, update)
+		return true
+	})
+	n, err := coll.Len()
+	if err != nil || n != len(res) {
+		t.Fatalf("expected length %d, got %d, %v", len(res), n, err)
+	}
+	return res
+}
+
+func TestAppData(t *testing.T) {
+	data, err := New(Options{MaxHistory: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener := data.Listener()
+	if err := listener.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "bank", Schema: testSchema}); err != nil {
+		t.Fatal(err)
+	}
+
+	sendBlock(t, listener, 1,
+		schema.ObjectUpdate{TypeName: "balances", Key: "alice", Value: []interface{}{uint64(10), uint64(0)}},
+		schema.ObjectUpdate{TypeName: "balances", Key: "bob", Value: []interface{}{uint64(5), uint64(0)}},
+		schema.ObjectUpdate{TypeName: "proposals", Key: uint64(1), Value: "upgrade"},
+	)
+	sendBlock(t, listener, 2,
+		schema.ObjectUpdate{TypeName: "balances", Key: "alice", Value: schema.MapValueUpdates{"amount": uint64(7)}},
+		schema.ObjectUpdate{TypeName: "balances", Key: "bob", Delete: true},
+		schema.ObjectUpdate{TypeName: "proposals", Key: uint64(1), Delete: true},
+	)
+
+	// the updates of a block which isn't committed are not visible
+	if err := listener.StartBlock(appdata.StartBlockData{Height: 3}); err != nil {
+		t.Fatal(err)
+	}
+	err = listener.OnObjectUpdate(appdata.ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{
+		{TypeName: "balances", Key: "carol", Value: []interface{}{uint64(1), uint64(0)}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest := []schema.ObjectUpdate{{TypeName: "balances", Key: "alice", Value: []interface{}{uint64(7), uint64(0)}}}
+	if got := objects(t, data, "balances"); !reflect.DeepEqual(got, latest) {
+		t.Fatalf("expected %v, got %v", latest, got)
+	}
+	deleted := []schema.ObjectUpdate{{TypeName: "proposals", Key: uint64(1), Value: "upgrade", Delete: true}}
+	if got := objects(t, data, "proposals"); !reflect.DeepEqual(got, deleted) {
+		t.Fatalf("expected the deletion to be retained, got %v", got)
+	}
+
+	atHeight1, err := data.ReadAt(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blockNum, _ := atHeight1.BlockNum(); blockNum != 1 {
+		t.Fatalf("expected block 1, got %d", blockNum)
+	}
+	if got := objects(t, atHeight1, "balances"); len(got) != 2 || got[0].Value.([]interface{})[0] != uint64(10) {
+		t.Fatalf("unexpected balances at height 1: %v", got)
+	}
+
+	// the block is delivered again, which discards the uncommitted updates, and committed
+	sendBlock(t, listener, 3, schema.ObjectUpdate{TypeName: "balances", Key: "dave", Value: []interface{}{uint64(2), uint64(0)}})
+	if got := objects(t, data, "balances"); len(got) != 2 || got[0].Key != "alice" || got[1].Key != "dave" {
+		t.Fatalf("unexpected balances at height 3: %v", got)
+	}
+
+	// height 1 is pruned and views of it return errors
+	if heights := data.RetainedHeights(); !reflect.DeepEqual(heights, []uint64{2, 3}) {
+		t.Fatalf("unexpected retained heights %v", heights)
+	}
+	if _, err := data.ReadAt(1); err == nil || !strings.Contains(err.Error(), "height 1 was pruned") {
+		t.Fatalf("expected pruned error, got %v", err)
+	}
+	if _, err := atHeight1.AppState().GetModule("bank"); err == nil {
+		t.Fatalf("expected pruned error")
+	}
+	if _, err := data.ReadAt(4); err == nil || !strings.Contains(err.Error(), "latest committed height is 3") {
+		t.Fatalf("expected uncommitted error, got %v", err)
+	}
+
+	atHeight2, err := data.ReadAt(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := objects(t, atHeight2, "balances"); !reflect.DeepEqual(got, latest) {
+		t.Fatalf("expected %v at height 2, got %v", latest, got)
+	}
+
+	// bob was deleted at height 2 which is now the oldest height, so its versions are gone
+	coll := data.modules["bank"].collections["balances"]
+	if _, ok := coll.objects[keyString(coll.objectType, "bob")]; ok {
+		t.Fatalf("expected the deleted object to be pruned")
+	}
+	if versions := coll.objects[keyString(coll.objectType, "alice")].versions; len(versions) != 1 || versions[0].height != 2 {
+		t.Fatalf("expected only the version of height 2 to be retained, got %v", versions)
+	}
+
+	if err := listener.StartBlock(appdata.StartBlockData{Height: 3}); err == nil {
+		t.Fatalf("expected an error for a committed block")
+	}
+}
+
+func TestInit(t *testing.T) {
+	res, err := Init(indexer.InitParams{Config: indexer.Config{Type: IndexerType, Config: map[string]interface{}{"max_history": 10}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.View.(*AppData).maxHistory != 10 {
+		t.Fatalf("expected the max history to be configured")
+	}
+
+	if _, err := Init(indexer.InitParams{Config: indexer.Config{Config: map[string]interface{}{"max_history": -1}}}); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
@@ -0,0 +1,331 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/view"
+)
+
+// AppData is an in-memory store of app data which is written by its listener and read at the latest committed
+// height through view.AppData or at a retained height with ReadAt. It is safe to read while it is being written.
+// The updates of a block are only visible once the block is committed.
+type AppData struct {
+	maxHistory int
+
+	mu      sync.RWMutex
+	modules map[string]*module
+
+	// heights are the retained committed heights in ascending order, the last one being the latest
+	heights []uint64
+
+	// height is the height of the block which is being written, if started is true
+	height  uint64
+	started bool
+
+	// changed holds for each retained or uncommitted height the objects which got a version at that height
+	changed map[uint64][]*object
+}
+
+var _ view.AppData = &AppData{}
+
+// New creates an empty in-memory store of app data.
+func New(opts Options) (*AppData, error) {
+	if opts.MaxHistory < 0 {
+		return nil, fmt.Errorf("max history must not be negative")
+	}
+	maxHistory := opts.MaxHistory
+	if maxHistory == 0 {
+		maxHistory = 1
+	}
+	return &AppData{
+		maxHistory: maxHistory,
+		modules:    map[string]*module{},
+		changed:    map[uint64][]*object{},
+	}, nil
+}
+
+type module struct {
+	name        string
+	schema      schema.ModuleSchema
+	collections map[string]*collection
+}
+
+type collection struct {
+	objectType schema.ObjectType
+	objects    map[string]*object
+
+	// keys are the sorted key strings of objects
+	keys []string
+}
+
+// object holds the versions of an object in ascending order of height.
+type object struct {
+	coll     *collection
+	key      string
+	versions []version
+}
+
+type version struct {
+	height uint64
+
+	// update is the state of the object, with the latest value and Delete set if it is a retained deletion
+	update schema.ObjectUpdate
+
+	// removed is true if the object was deleted and deletions aren't retained
+	removed bool
+}
+
+// Listener returns the listener which writes app data to the store. Module initialization, object updates and
+// commits are stored, other data is ignored.
+func (a *AppData) Listener() appdata.Listener {
+	return appdata.Listener{
+		InitializeModuleData: a.initializeModule,
+		StartBlock:           a.startBlock,
+		OnObjectUpdate:       a.onObjectUpdate,
+		Commit:               a.commit,
+	}
+}
+
+func (a *AppData) initializeModule(data appdata.ModuleInitializationData) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	mod, ok := a.modules[data.ModuleName]
+	if ok && !data.SchemaChanged {
+		return fmt.Errorf("module %s already initialized", data.ModuleName)
+	}
+	if !ok {
+		mod = &module{name: data.ModuleName, collections: map[string]*collection{}}
+		a.modules[data.ModuleName] = mod
+	}
+
+	// collections of object types which were removed from the schema are kept for the snapshots they are in
+	mod.schema = data.Schema
+	data.Schema.ObjectTypes(func(objectType schema.ObjectType) bool {
+		if coll, ok := mod.collections[objectType.Name]; ok {
+			coll.objectType = objectType
+		} else {
+			mod.collections[objectType.Name] = &collection{objectType: objectType, objects: map[string]*object{}}
+		}
+		return true
+	})
+	return nil
+}
+
+func (a *AppData) startBlock(data appdata.StartBlockData) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.started {
+		// the block which was being written wasn't committed, for instance because it is delivered again
+		a.rollback()
+	}
+	if latest, ok := a.latest(); ok && data.Height <= latest {
+		return fmt.Errorf("block %d was already committed, the latest committed block is %d", data.Height, latest)
+	}
+	a.height = data.Height
+	a.started = true
+	return nil
+}
+
+func (a *AppData) onObjectUpdate(data appdata.ObjectUpdateData) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	mod, ok := a.modules[data.ModuleName]
+	if !ok {
+		return fmt.Errorf("module %s isn't initialized", data.ModuleName)
+	}
+	a.start()
+
+	for _, update := range data.Updates {
+		coll, ok := mod.collections[update.TypeName]
+		if !ok {
+			return fmt.Errorf("unknown object type %s in module %s", update.TypeName, data.ModuleName)
+		}
+		if err := coll.objectType.ValidateObjectUpdate(update); err != nil {
+			return fmt.Errorf("invalid update of object type %s in module %s: %v", update.TypeName, data.ModuleName, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		if err := a.apply(coll, update); err != nil {
+			return fmt.Errorf("can't apply update of object type %s in module %s: %v", update.TypeName, data.ModuleName, err) //nolint:errorlint // false positive due to using go1.12
+		}
+	}
+	return nil
+}
+
+func (a *AppData) commit(appdata.CommitData) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.start()
+	a.heights = append(a.heights, a.height)
+	a.started = false
+
+	for len(a.heights) > a.maxHistory {
+		pruned := a.heights[0]
+		a.heights = a.heights[1:]
+		oldest := a.heights[0]
+		for _, obj := range a.changed[pruned] {
+			obj.prune(oldest)
+		}
+		delete(a.changed, pruned)
+	}
+	return nil
+}
+
+// start starts a block after the latest committed block if StartBlock wasn't called.
+func (a *AppData) start() {
+	if a.started {
+		return
+	}
+	latest, _ := a.latest()
+	a.height = latest + 1
+	a.started = true
+}
+
+// latest returns the latest committed height and false if no block has been committed.
+func (a *AppData) latest() (uint64, bool) {
+	if len(a.heights) == 0 {
+		return 0, false
+	}
+	return a.heights[len(a.heights)-1], true
+}
+
+// rollback removes the versions written by the block which is being written.
+func (a *AppData) rollback() {
+	for _, obj := range a.changed[a.height] {
+		obj.versions = obj.versions[:len(obj.versions)-1]
+		if len(obj.versions) == 0 {
+			obj.coll.remove(obj)
+		}
+	}
+	delete(a.changed, a.height)
+	a.started = false
+}
+
+// apply writes a version of the object of the update at the height of the current block.
+func (a *AppData) apply(coll *collection, update schema.ObjectUpdate) error {
+	key := keyString(coll.objectType, update.Key)
+	obj, exists := coll.objects[key]
+	var current version
+	if exists {
+		current = obj.versions[len(obj.versions)-1]
+	}
+	live := exists && !current.removed && !current.update.Delete
+
+	next := version{height: a.height}
+	switch valueUpdates, ok := update.Value.(schema.ValueUpdates); {
+	case update.Delete:
+		if !live {
+			return nil
+		}
+		if coll.objectType.RetainDeletions {
+			next.update = current.update
+			next.update.Delete = true
+		} else {
+			next.removed = true
+		}
+	case ok:
+		if !live {
+			return fmt.Errorf("can't apply partial update to object %v which doesn't exist", update.Key)
+		}
+		value, err := mergeValueUpdates(coll.objectType, current.update.Value, valueUpdates)
+		if err != nil {
+			return err
+		}
+		next.update = schema.ObjectUpdate{TypeName: update.TypeName, Key: current.update.Key, Value: value}
+	default:
+		next.update = schema.ObjectUpdate{TypeName: update.TypeName, Key: update.Key, Value: update.Value}
+	}
+
+	if !exists {
+		obj = &object{coll: coll, key: key}
+		coll.insert(obj)
+	}
+	if exists && current.height == a.height {
+		obj.versions[len(obj.versions)-1] = next
+		return nil
+	}
+	obj.versions = append(obj.versions, next)
+	a.changed[a.height] = append(a.changed[a.height], obj)
+	return nil
+}
+
+// mergeValueUpdates returns the value of the value fields of an object after applying value updates to them.
+func mergeValueUpdates(objectType schema.ObjectType, value interface{}, valueUpdates schema.ValueUpdates) (interface{}, error) {
+	var values []interface{}
+	if len(objectType.ValueFields) == 1 {
+		values = []interface{}{value}
+	} else {
+		values = append([]interface{}{}, value.([]interface{})...)
+	}
+
+	err := valueUpdates.Iterate(func(name string, fieldValue interface{}) bool {
+		for i, field := range objectType.ValueFields {
+			if field.Name == name {
+				values[i] = fieldValue
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) == 1 {
+		return values[0], nil
+	}
+	return values, nil
+}
+
+// at returns the version of the object which is visible at height and false if there is none.
+func (o *object) at(height uint64) (version, bool) {
+	i := sort.Search(len(o.versions), func(i int) bool { return o.versions[i].height > height })
+	if i == 0 || o.versions[i-1].removed {
+		return version{}, false
+	}
+	return o.versions[i-1], true
+}
+
+// prune removes the versions which are not visible at oldest or any later height, and the object itself if it
+// isn't visible at any retained height.
+func (o *object) prune(oldest uint64) {
+	i := sort.Search(len(o.versions), func(i int) bool { return o.versions[i].height > oldest })
+	if i > 1 {
+		o.versions = append(o.versions[:0], o.versions[i-1:]...)
+	}
+	if len(o.versions) == 1 && o.versions[0].removed && o.versions[0].height <= oldest {
+		o.versions = nil
+		o.coll.remove(o)
+	}
+}
+
+func (c *collection) insert(obj *object) {
+	c.objects[obj.key] = obj
+	i := sort.SearchStrings(c.keys, obj.key)
+	c.keys = append(c.keys, "")
+	copy(c.keys[i+1:], c.keys[i:])
+	c.keys[i] = obj.key
+}
+
+func (c *collection) remove(obj *object) {
+	if c.objects[obj.key] != obj {
+		return
+	}
+	delete(c.objects, obj.key)
+	i := sort.SearchStrings(c.keys, obj.key)
+	c.keys = append(c.keys[:i], c.keys[i+1:]...)
+}
+
+// keyString returns a string which identifies a key of an object of the object type. Keys never contain maps
+// or floats and the keys of singletons are ignored.
+func keyString(objectType schema.ObjectType, key interface{}) string {
+	if len(objectType.KeyFields) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%#v", key)
+}
@@ -0,0 +1,260 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/view"
+)
+
+// BlockNum implements view.AppData and returns the latest committed height.
+func (a *AppData) BlockNum() (uint64, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	latest, _ := a.latest()
+	return latest, nil
+}
+
+// AppState implements view.AppData and returns the app state at the latest committed height at the time each
+// of its methods is called.
+func (a *AppData) AppState() view.AppState {
+	return appState{data: a, latest: true}
+}
+
+// ReadAt returns a view of the app data as of the given committed height, which must be one of the retained
+// heights. The view returns an error once the height has been pruned. The state at a height which wasn't
+// committed, but lies between retained heights, is the state of the previous committed height.
+func (a *AppData) ReadAt(height uint64) (view.AppData, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if err := a.checkHeight(height); err != nil {
+		return nil, err
+	}
+	return snapshot{appState{data: a, height: height}}, nil
+}
+
+// RetainedHeights returns the committed heights which can be read with ReadAt, in ascending order.
+func (a *AppData) RetainedHeights() []uint64 {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return append([]uint64(nil), a.heights...)
+}
+
+// checkHeight returns an error if the state at height can't be read.
+func (a *AppData) checkHeight(height uint64) error {
+	latest, ok := a.latest()
+	if !ok {
+		if height != 0 {
+			return fmt.Errorf("height %d isn't committed, no block has been committed", height)
+		}
+		return nil
+	}
+	if height > latest {
+		return fmt.Errorf("height %d isn't committed, the latest committed height is %d", height, latest)
+	}
+	if height < a.heights[0] {
+		return fmt.Errorf("height %d was pruned, the oldest retained height is %d", height, a.heights[0])
+	}
+	return nil
+}
+
+// snapshot is a view.AppData at a fixed height.
+type snapshot struct {
+	state appState
+}
+
+func (s snapshot) BlockNum() (uint64, error) {
+	return s.state.height, nil
+}
+
+func (s snapshot) AppState() view.AppState {
+	return s.state
+}
+
+// appState reads the state at a fixed height, or at the latest committed height if latest is true.
+type appState struct {
+	data   *AppData
+	height uint64
+	latest bool
+}
+
+// read calls f with the height to read while holding the read lock.
+func (s appState) read(f func(height uint64) error) error {
+	s.data.mu.RLock()
+	defer s.data.mu.RUnlock()
+
+	if s.latest {
+		height, _ := s.data.latest()
+		return f(height)
+	}
+	if err := s.data.checkHeight(s.height); err != nil {
+		return err
+	}
+	return f(s.height)
+}
+
+func (s appState) GetModule(moduleName string) (view.ModuleState, error) {
+	var res view.ModuleState
+	err := s.read(func(uint64) error {
+		if mod, ok := s.data.modules[moduleName]; ok {
+			res = moduleState{state: s, mod: mod, schema: mod.schema}
+		}
+		return nil
+	})
+	return res, err
+}
+
+func (s appState) Modules(f func(view.ModuleState, error) bool) {
+	var mods []view.ModuleState
+	err := s.read(func(uint64) error {
+		for _, mod := range s.data.modules {
+			mods = append(mods, moduleState{state: s, mod: mod, schema: mod.schema})
+		}
+		return nil
+	})
+	if err != nil {
+		f(nil, err)
+		return
+	}
+
+	sort.Slice(mods, func(i, j int) bool { return mods[i].ModuleName() < mods[j].ModuleName() })
+	for _, mod := range mods {
+		if !f(mod, nil) {
+			return
+		}
+	}
+}
+
+func (s appState) NumModules() (int, error) {
+	var res int
+	err := s.read(func(uint64) error {
+		res = len(s.data.modules)
+		return nil
+	})
+	return res, err
+}
+
+type moduleState struct {
+	state  appState
+	mod    *module
+	schema schema.ModuleSchema
+}
+
+func (m moduleState) ModuleName() string {
+	return m.mod.name
+}
+
+func (m moduleState) ModuleSchema() schema.ModuleSchema {
+	return m.schema
+}
+
+func (m moduleState) GetObjectCollection(objectType string) (view.ObjectCollection, error) {
+	var res view.ObjectCollection
+	err := m.state.read(func(uint64) error {
+		if coll, ok := m.mod.collections[objectType]; ok {
+			res = objectCollection{state: m.state, coll: coll, objectType: coll.objectType}
+		}
+		return nil
+	})
+	return res, err
+}
+
+func (m moduleState) ObjectCollections(f func(view.ObjectCollection, error) bool) {
+	var colls []view.ObjectCollection
+	err := m.state.read(func(uint64) error {
+		for _, coll := range m.mod.collections {
+			colls = append(colls, objectCollection{state: m.state, coll: coll, objectType: coll.objectType})
+		}
+		return nil
+	})
+	if err != nil {
+		f(nil, err)
+		return
+	}
+
+	sort.Slice(colls, func(i, j int) bool { return colls[i].ObjectType().Name < colls[j].ObjectType().Name })
+	for _, coll := range colls {
+		if !f(coll, nil) {
+			return
+		}
+	}
+}
+
+func (m moduleState) NumObjectCollections() (int, error) {
+	var res int
+	err := m.state.read(func(uint64) error {
+		res = len(m.mod.collections)
+		return nil
+	})
+	return res, err
+}
+
+type objectCollection struct {
+	state      appState
+	coll       *collection
+	objectType schema.ObjectType
+}
+
+func (c objectCollection) ObjectType() schema.ObjectType {
+	return c.objectType
+}
+
+func (c objectCollection) GetObject(key interface{}) (update schema.ObjectUpdate, found bool, err error) {
+	err = c.state.read(func(height uint64) error {
+		obj, ok := c.coll.objects[keyString(c.coll.objectType, key)]
+		if !ok {
+			return nil
+		}
+		var v version
+		if v, found = obj.at(height); found {
+			update = v.update
+		}
+		return nil
+	})
+	return update, found, err
+}
+
+// AllState iterates over the objects in the order of their key strings. The objects are read before f is
+// called, so f may call other methods of the view.
+func (c objectCollection) AllState(f func(schema.ObjectUpdate, error) bool) {
+	var updates []schema.ObjectUpdate
+	err := c.state.read(func(height uint64) error {
+		for _, key := range c.coll.keys {
+			if v, ok := c.coll.objects[key].at(height); ok {
+				updates = append(updates, v.update)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		f(schema.ObjectUpdate{}, err)
+		return
+	}
+
+	for _, update := range updates {
+		if !f(update, nil) {
+			return
+		}
+	}
+}
+
+func (c objectCollection) Len() (int, error) {
+	var res int
+	err := c.state.read(func(height uint64) error {
+		for _, obj := range c.coll.objects {
+			if _, ok := obj.at(height); ok {
+				res++
+			}
+		}
+		return nil
+	})
+	return res, err
+}
+
+func (c objectCollection) List(filter view.FieldFilter, order view.OrderBy, page view.Pagination) (view.ListResult, error) {
+	return view.ListObjects(c, filter, order, page)
+}
@@ -1,6 +1,9 @@
 package indexer
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 // Register registers an indexer type with the given initialization function.
 func Register(indexerType string, initFunc InitFunc) {
@@ -11,4 +14,14 @@ func Register(indexerType string, initFunc InitFunc) {
 	indexerRegistry[indexerType] = initFunc
 }
 
+// RegisteredTypes returns the sorted names of the registered indexer types.
+func RegisteredTypes() []string {
+	res := make([]string, 0, len(indexerRegistry))
+	for indexerType := range indexerRegistry {
+		res = append(res, indexerType)
+	}
+	sort.Strings(res)
+	return res
+}
+
 var indexerRegistry = map[string]InitFunc{}
@@ -15,6 +15,14 @@ func TestRegister(t *testing.T) {
 		t.Fatalf("expected not to find indexer")
 	}
 
+	found := false
+	for _, indexerType := range RegisteredTypes() {
+		found = found || indexerType == "test"
+	}
+	if !found {
+		t.Fatalf("expected the indexer type to be listed")
+	}
+
 	defer func() {
 		if r := recover(); r == nil {
 			t.Fatalf("expected to panic")
@@ -0,0 +1,88 @@
+package indexer
+
+import (
+	"fmt"
+	"sync"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+)
+
+// StorePath is the location in the state of the app of the kv-pair from which an object was decoded. It is
+// the path needed to fetch a Merkle proof of the object from the app.
+type StorePath struct {
+	// StoreKey is the name of the store of the module, which is the module name of the kv-pair.
+	StoreKey string
+
+	// Key is the key of the kv-pair in the store.
+	Key []byte
+
+	// Height is the height of the block in which the object was last updated.
+	Height uint64
+}
+
+// StorePathIndex records the store path of the latest update of each object delivered to its listener, so
+// that the objects served by indexers can later be proven against the app hash. The index is held in memory
+// and only covers the objects updated since it was created. It is safe for concurrent use.
+type StorePathIndex struct {
+	mu     sync.RWMutex
+	height uint64
+	paths  map[string]StorePath
+}
+
+// NewStorePathIndex returns an empty StorePathIndex.
+func NewStorePathIndex() *StorePathIndex {
+	return &StorePathIndex{paths: map[string]StorePath{}}
+}
+
+// Listener returns the listener through which the index records the store paths of object updates. Only the
+// object updates which have a SourceKey, i.e. which were decoded by the decoding middleware, are recorded.
+func (s *StorePathIndex) Listener() appdata.Listener {
+	return appdata.Listener{
+		StartBlock: func(data appdata.StartBlockData) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.height = data.Height
+			return nil
+		},
+		OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+			if data.SourceKey == nil {
+				return nil
+			}
+
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			for _, update := range data.Updates {
+				id, err := storePathID(data.ModuleName, update.TypeName, update.Key)
+				if err != nil {
+					return err
+				}
+				s.paths[id] = StorePath{StoreKey: data.ModuleName, Key: data.SourceKey, Height: s.height}
+			}
+			return nil
+		},
+	}
+}
+
+// Lookup returns the store path of the latest update of the object of type typeName in module moduleName
+// with the given key. It returns false if no update of the object was recorded.
+func (s *StorePathIndex) Lookup(moduleName, typeName string, key interface{}) (StorePath, bool, error) {
+	id, err := storePathID(moduleName, typeName, key)
+	if err != nil {
+		return StorePath{}, false, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	path, ok := s.paths[id]
+	return path, ok, nil
+}
+
+// storePathID returns the string which identifies an object in the index.
+func storePathID(moduleName, typeName string, key interface{}) (string, error) {
+	bz, err := schema.EncodeValue(key)
+	if err != nil {
+		return "", fmt.Errorf("invalid key of %s in module %s: %v", typeName, moduleName, err) //nolint:errorlint // false positive due to using go1.12
+	}
+	return moduleName + "\x00" + typeName + "\x00" + string(bz), nil
+}
@@ -0,0 +1,86 @@
+package indexer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/decoding"
+)
+
+type storePathsModule struct{}
+
+func (storePathsModule) ModuleCodec() (schema.ModuleCodec, error) {
+	modSchema, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:      "balances",
+		KeyFields: []schema.Field{{Name: "address", Kind: schema.StringKind}},
+	}})
+	if err != nil {
+		return schema.ModuleCodec{}, err
+	}
+	return schema.ModuleCodec{
+		Schema: modSchema,
+		KVDecoder: func(update schema.KVPairUpdate) ([]schema.ObjectUpdate, error) {
+			// the key of the kv-pair is a one byte prefix followed by the address
+			return []schema.ObjectUpdate{{TypeName: "balances", Key: string(update.Key[1:]), Delete: update.Delete}}, nil
+		},
+	}, nil
+}
+
+func TestStorePathIndex(t *testing.T) {
+	Register("store_paths_test", func(InitParams) (InitResult, error) {
+		return InitResult{
+			Listener:           appdata.Listener{OnObjectUpdate: func(appdata.ObjectUpdateData) error { return nil }},
+			LastBlockPersisted: -1,
+		}, nil
+	})
+
+	paths := NewStorePathIndex()
+	manager, err := StartManager(ManagerOptions{
+		Config:     ManagerConfig{Target: map[string]Config{"store_paths": {Type: "store_paths_test"}}},
+		Resolver:   decoding.ModuleSetDecoderResolver(map[string]interface{}{"bank": storePathsModule{}}),
+		StorePaths: paths,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for height, address := range []string{"alice", "bob", "alice"} {
+		packets := []appdata.Packet{
+			appdata.StartBlockData{Height: uint64(height + 1)},
+			appdata.KVPairData{Updates: []appdata.ModuleKVPairUpdate{
+				{ModuleName: "bank", Update: schema.KVPairUpdate{Key: append([]byte{2}, address...), Value: []byte{1}}},
+			}},
+			// updates which were not decoded from a kv-pair are not recorded
+			appdata.ObjectUpdateData{ModuleName: "bank", Updates: []schema.ObjectUpdate{{TypeName: "balances", Key: "carol"}}},
+			appdata.CommitData{},
+		}
+		for _, p := range packets {
+			if err := manager.Listener().SendPacket(p); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+
+	path, found, err := paths.Lookup("bank", "balances", "alice")
+	if err != nil || !found {
+		t.Fatalf("expected store path of alice, got found %v and error %v", found, err)
+	}
+	expected := StorePath{StoreKey: "bank", Key: []byte("\x02alice"), Height: 3}
+	if !reflect.DeepEqual(path, expected) {
+		t.Fatalf("expected store path %+v, got %+v", expected, path)
+	}
+
+	if _, found, _ := paths.Lookup("bank", "balances", "carol"); found {
+		t.Fatalf("expected no store path of carol")
+	}
+	if _, _, err := paths.Lookup("bank", "balances", struct{}{}); err == nil {
+		t.Fatalf("expected invalid key error")
+	}
+
+	if err := manager.Stop(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package indexer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cosmossdk.io/schema"
+)
+
+// SchemaMigration describes the change of a module's schema during a chain upgrade.
+type SchemaMigration struct {
+	// PlanName is the name of the upgrade plan.
+	PlanName string
+
+	// ModuleName is the name of the module.
+	ModuleName string
+
+	// From is the schema of the module before the upgrade.
+	From schema.ModuleSchema
+
+	// To is the schema which the module will have after the upgrade.
+	To schema.ModuleSchema
+}
+
+// CheckSchemaUpgrade validates, ahead of the height of the upgrade plan planName, that the indexer targets
+// can migrate to the next-version module schemas in next, so that an upgrade which would break indexing can
+// be halted before it happens. The current schema of each module is looked up with the manager's decoder
+// resolver. For each module whose schema changes and whose state is delivered to a target, the target's
+// InitResult.CheckMigration callback is called if it has one, otherwise the next schema must be compatible
+// with the current one according to schema.ModuleSchema.CompatibleWith. Modules which don't exist yet are
+// initialized from scratch after the upgrade and are not checked. The returned error lists every target and
+// module which can't be migrated.
+func (m *Manager) CheckSchemaUpgrade(planName string, next map[string]schema.ModuleSchema) error {
+	if m.resolver == nil {
+		return nil
+	}
+
+	moduleNames := make([]string, 0, len(next))
+	for moduleName := range next {
+		moduleNames = append(moduleNames, moduleName)
+	}
+	sort.Strings(moduleNames)
+
+	var failures []string
+	for _, moduleName := range moduleNames {
+		if !m.config.IncludesModule(moduleName) {
+			continue
+		}
+
+		cdc, found, err := m.resolver.LookupDecoder(moduleName)
+		if err != nil {
+			return fmt.Errorf("failed to look up the schema of module %s: %v", moduleName, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		if !found {
+			continue
+		}
+
+		migration := SchemaMigration{PlanName: planName, ModuleName: moduleName, From: cdc.Schema, To: next[moduleName]}
+		if migration.From.Fingerprint() == migration.To.Fingerprint() {
+			continue
+		}
+
+		for _, t := range m.targets {
+			if !t.receivesState(moduleName) {
+				continue
+			}
+
+			var err error
+			if t.checkMigration != nil {
+				err = t.checkMigration(migration)
+			} else {
+				err = migration.To.CompatibleWith(migration.From)
+			}
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("target %q can't migrate module %s: %v", t.name, moduleName, err))
+			}
+		}
+	}
+
+	if len(failures) != 0 {
+		return fmt.Errorf("indexer schema check for upgrade %q failed: %s", planName, strings.Join(failures, "; "))
+	}
+	m.logger.Info("indexer targets can migrate to the upgraded module schemas", "plan", planName, "modules", len(moduleNames))
+	return nil
+}
+
+// receivesState reports whether the state changes of moduleName are delivered to the target.
+func (t *target) receivesState(moduleName string) bool {
+	if t.cfg.ExcludeState {
+		return false
+	}
+	if len(t.cfg.IncludeModules) != 0 && !containsString(t.cfg.IncludeModules, moduleName) {
+		return false
+	}
+	return !containsString(t.cfg.ExcludeModules, moduleName)
+}
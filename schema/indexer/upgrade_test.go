@@ -0,0 +1,148 @@
+package indexer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/decoding"
+)
+
+func TestManager_CheckSchemaUpgrade(t *testing.T) {
+	newSchema := func(valueFields ...schema.Field) schema.ModuleSchema {
+		modSchema, err := schema.NewModuleSchema([]schema.ObjectType{{
+			Name:        "balances",
+			KeyFields:   []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+			ValueFields: valueFields,
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return modSchema
+	}
+	amount := schema.Field{Name: "amount", Kind: schema.Uint64Kind}
+	current := newSchema(amount)
+	appended := newSchema(amount, schema.Field{Name: "locked", Kind: schema.Uint64Kind, Nullable: true})
+	changed := newSchema(schema.Field{Name: "amount", Kind: schema.StringKind})
+
+	resolver := decoding.ModuleSetDecoderResolver(map[string]interface{}{
+		"bank":    checkpointTestModule{schema: current},
+		"staking": checkpointTestModule{schema: current},
+	})
+
+	var migrations []SchemaMigration
+	Register("upgrade_test_checked", func(InitParams) (InitResult, error) {
+		return InitResult{
+			CheckMigration: func(migration SchemaMigration) error {
+				migrations = append(migrations, migration)
+				if migration.ModuleName == "staking" {
+					return fmt.Errorf("can't alter column")
+				}
+				return nil
+			},
+		}, nil
+	})
+	Register("upgrade_test_default", func(InitParams) (InitResult, error) {
+		return InitResult{}, nil
+	})
+
+	manager, err := StartManager(ManagerOptions{
+		Config: ManagerConfig{Target: map[string]Config{
+			"checked": {Type: "upgrade_test_checked"},
+			"default": {Type: "upgrade_test_default", ExcludeModules: []string{"staking"}},
+			"events":  {Type: "upgrade_test_default", ExcludeState: true},
+		}},
+		Resolver: resolver,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// unchanged schemas and new modules are not checked
+	if err := manager.CheckSchemaUpgrade("v2", map[string]schema.ModuleSchema{"bank": current, "gov": changed}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(migrations) != 0 {
+		t.Fatalf("expected no migrations to be checked, got %v", migrations)
+	}
+
+	if err := manager.CheckSchemaUpgrade("v2", map[string]schema.ModuleSchema{"bank": appended}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(migrations) != 1 || migrations[0].PlanName != "v2" || migrations[0].ModuleName != "bank" ||
+		migrations[0].From.Fingerprint() != current.Fingerprint() || migrations[0].To.Fingerprint() != appended.Fingerprint() {
+		t.Fatalf("unexpected migrations: %v", migrations)
+	}
+
+	err = manager.CheckSchemaUpgrade("v3", map[string]schema.ModuleSchema{"bank": changed, "staking": appended})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	// the default target is checked with CompatibleWith, it doesn't receive the state of staking and the
+	// events target doesn't receive state at all
+	for _, expected := range []string{
+		`target "default" can't migrate module bank`,
+		`target "checked" can't migrate module staking: can't alter column`,
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error containing %q, got: %v", expected, err)
+		}
+	}
+	if strings.Contains(err.Error(), `"events"`) || strings.Contains(err.Error(), `target "default" can't migrate module staking`) {
+		t.Fatalf("unexpected failures in error: %v", err)
+	}
+}
+
+func TestManager_InvalidateSchemas(t *testing.T) {
+	bank := &checkpointTestModule{}
+	var initialized []appdata.ModuleInitializationData
+	Register("upgrade_test_invalidate", func(InitParams) (InitResult, error) {
+		return InitResult{Listener: appdata.Listener{
+			InitializeModuleData: func(data appdata.ModuleInitializationData) error {
+				initialized = append(initialized, data)
+				return nil
+			},
+		}}, nil
+	})
+
+	manager, err := StartManager(ManagerOptions{
+		Config:   ManagerConfig{Target: map[string]Config{"remote": {Type: "upgrade_test_invalidate"}}},
+		Resolver: decoding.ModuleSetDecoderResolver(map[string]interface{}{"bank": bank}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	packets := []appdata.Packet{
+		appdata.StartBlockData{Height: 1},
+		appdata.KVPairData{Updates: []appdata.ModuleKVPairUpdate{{ModuleName: "bank"}}},
+		appdata.CommitData{},
+	}
+	for _, p := range packets {
+		if err := manager.Listener().SendPacket(p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// the module is initialized again at the start of the next block
+	bank.schema, err = schema.NewModuleSchema([]schema.ObjectType{{
+		Name:      "balances",
+		KeyFields: []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	manager.InvalidateSchemas("bank")
+	if err := manager.Listener().SendPacket(appdata.StartBlockData{Height: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(initialized) != 2 || initialized[0].SchemaChanged || !initialized[1].SchemaChanged {
+		t.Fatalf("expected bank to be initialized again, got %v", initialized)
+	}
+	if _, ok := initialized[1].Schema.LookupType("balances"); !ok {
+		t.Fatalf("expected the new schema to be delivered")
+	}
+}
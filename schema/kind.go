@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 )
@@ -74,7 +76,9 @@ const (
 
 	// AddressKind represents an account address and must be of type []byte. Addresses usually have a
 	// human-readable rendering, such as bech32, and tooling should provide a way for apps to define a
-	// string encoder for friendly user-facing display.
+	// string encoder for friendly user-facing display. Kind.NormalizeValue can be used with an AddressCodec
+	// to convert string addresses to their canonical []byte representation. Fields can declare the bech32
+	// prefix of their addresses with Field.AddressPrefix.
 	AddressKind
 
 	// EnumKind is an enum type and values of this type must be of the go type string.
@@ -85,10 +89,33 @@ const (
 	// JSONKind is a JSON type and values of this type should be of go type json.RawMessage and represent
 	// valid JSON.
 	JSONKind
+
+	// StructKind is a nested struct type and values of this type must be of the go type []interface{}
+	// with one value for each field in the struct definition, in the same order as the fields.
+	// Fields of this type are expected to set the StructType field in the field definition to the
+	// struct definition.
+	StructKind
+
+	// ListKind is a list type and values of this type must be of the go type []interface{} where each
+	// element is a valid value of the field's ElementKind. Fields of this type are expected to set
+	// the ElementKind field in the field definition.
+	ListKind
+
+	// MapKind is a map type and values of this type must be of the go type map[interface{}]interface{}
+	// where each key is a valid value of the field's KeyKind and each value is a valid value of the
+	// field's ValueKind. Fields of this type are expected to set the KeyKind and ValueKind fields in
+	// the field definition.
+	MapKind
+
+	// OneOfKind is a union type and values of this type must be of the go type OneOfValue whose Case
+	// is the name of one of the cases of the field's OneOfType and whose Value is a valid value of
+	// that case. Fields of this type are expected to set the OneOfType field in the field definition
+	// to the oneof definition.
+	OneOfKind
 )
 
 // MAX_VALID_KIND is the maximum valid kind value.
-const MAX_VALID_KIND = JSONKind
+const MAX_VALID_KIND = OneOfKind
 
 const (
 	// IntegerFormat is a regex that describes the format integer number strings must match. It specifies
@@ -100,6 +127,9 @@ const (
 	// exponent of up to 2 digits. These restrictions ensure that the decimal can be accurately represented
 	// by a wide variety of implementations.
 	DecimalFormat = `^-?[0-9]{1,50}(\.[0-9]{1,50})?([eE][-+]?[0-9]{1,2})?$`
+
+	// MaxDecimalPrecision is the maximum precision which can be declared for DecimalStringKind fields.
+	MaxDecimalPrecision = 100
 )
 
 // Validate returns an errContains if the kind is invalid.
@@ -107,7 +137,7 @@ func (t Kind) Validate() error {
 	if t <= InvalidKind {
 		return fmt.Errorf("unknown type: %d", t)
 	}
-	if t > JSONKind {
+	if t > MAX_VALID_KIND {
 		return fmt.Errorf("invalid type: %d", t)
 	}
 	return nil
@@ -156,11 +186,44 @@ func (t Kind) String() string {
 		return "enum"
 	case JSONKind:
 		return "json"
+	case StructKind:
+		return "struct"
+	case ListKind:
+		return "list"
+	case MapKind:
+		return "map"
+	case OneOfKind:
+		return "oneof"
 	default:
 		return fmt.Sprintf("invalid(%d)", t)
 	}
 }
 
+// MarshalJSON marshals the kind as a JSON string using the name returned by Kind.String.
+func (t Kind) MarshalJSON() ([]byte, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON unmarshals the kind from a JSON string using the names returned by Kind.String.
+func (t *Kind) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	for kind := InvalidKind + 1; kind <= MAX_VALID_KIND; kind++ {
+		if kind.String() == name {
+			*t = kind
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown kind %q", name)
+}
+
 // ValidateValueType returns an errContains if the value does not conform to the expected go type.
 // Some fields may accept nil values, however, this method does not have any notion of
 // nullability. This method only validates that the go type of the value is correct for the kind
@@ -269,6 +332,26 @@ func (t Kind) ValidateValueType(value interface{}) error {
 		if !ok {
 			return fmt.Errorf("expected json.RawMessage, got %T", value)
 		}
+	case StructKind:
+		_, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected []interface{}, got %T", value)
+		}
+	case ListKind:
+		_, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected []interface{}, got %T", value)
+		}
+	case MapKind:
+		_, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return fmt.Errorf("expected map[interface{}]interface{}, got %T", value)
+		}
+	case OneOfKind:
+		_, ok := value.(OneOfValue)
+		if !ok {
+			return fmt.Errorf("expected OneOfValue, got %T", value)
+		}
 	default:
 		return fmt.Errorf("invalid type: %d", t)
 	}
@@ -277,7 +360,8 @@ func (t Kind) ValidateValueType(value interface{}) error {
 
 // ValidateValue returns an errContains if the value does not conform to the expected go type and format.
 // It is more thorough, but slower, than Kind.ValidateValueType and validates that Integer, Decimal and JSON
-// values are formatted correctly. It cannot validate enum values because Kind's do not have enum schemas.
+// values are formatted correctly. It cannot validate enum, struct, list, map or oneof values because Kind's do not
+// have enum, struct, element, map entry or oneof case schemas.
 func (t Kind) ValidateValue(value interface{}) error {
 	err := t.ValidateValueType(value)
 	if err != nil {
@@ -315,6 +399,63 @@ func (t Kind) ValidateValue(value interface{}) error {
 	return nil
 }
 
+// decimalDigits returns the number of significant digits before and after the decimal point
+// of a decimal string which matches DecimalFormat, taking any exponent into account. Leading
+// zeros before and trailing zeros after the decimal point are not counted.
+func decimalDigits(value string) (intDigits, fracDigits int) {
+	value = strings.TrimPrefix(value, "-")
+
+	exp := 0
+	if i := strings.IndexAny(value, "eE"); i >= 0 {
+		exp, _ = strconv.Atoi(value[i+1:])
+		value = value[:i]
+	}
+
+	digits := value
+	point := len(value)
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		digits = value[:i] + value[i+1:]
+		point = i
+	}
+	point += exp
+
+	for len(digits) > 0 && digits[0] == '0' {
+		digits = digits[1:]
+		point--
+	}
+
+	for len(digits) > 0 && len(digits) > point && digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+	}
+
+	if len(digits) == 0 {
+		return 0, 0
+	}
+
+	if point > 0 {
+		intDigits = point
+	}
+
+	if len(digits) > point {
+		fracDigits = len(digits) - point
+	}
+
+	return intDigits, fracDigits
+}
+
+// IsValidMapKeyKind returns true if the kind can be used as the KeyKind of a MapKind field.
+// Only kinds whose go types are comparable and have a single canonical representation
+// can be used as map keys.
+func (t Kind) IsValidMapKeyKind() bool {
+	switch t {
+	case StringKind, Int8Kind, Uint8Kind, Int16Kind, Uint16Kind, Int32Kind, Uint32Kind,
+		Int64Kind, Uint64Kind, IntegerStringKind, BoolKind:
+		return true
+	default:
+		return false
+	}
+}
+
 var (
 	integerRegex = regexp.MustCompile(IntegerFormat)
 	decimalRegex = regexp.MustCompile(DecimalFormat)
@@ -76,6 +76,12 @@ func TestKind_ValidateValueType(t *testing.T) {
 		{kind: Float64Kind, value: float32(1.0), valid: false},
 		{kind: JSONKind, value: json.RawMessage("{}"), valid: true},
 		{kind: JSONKind, value: "hello", valid: false},
+		{kind: StructKind, value: []interface{}{int32(1), "abc"}, valid: true},
+		{kind: StructKind, value: "hello", valid: false},
+		{kind: ListKind, value: []interface{}{"a", "b"}, valid: true},
+		{kind: ListKind, value: []string{"a", "b"}, valid: false},
+		{kind: MapKind, value: map[interface{}]interface{}{"a": 1}, valid: true},
+		{kind: MapKind, value: map[string]interface{}{"a": 1}, valid: false},
 		{kind: InvalidKind, value: "hello", valid: false},
 	}
 
@@ -214,6 +220,9 @@ func TestKind_String(t *testing.T) {
 		{JSONKind, "json"},
 		{EnumKind, "enum"},
 		{AddressKind, "bech32address"},
+		{StructKind, "struct"},
+		{ListKind, "list"},
+		{MapKind, "map"},
 		{InvalidKind, "invalid(0)"},
 	}
 	for i, tt := range tests {
@@ -225,6 +234,59 @@ func TestKind_String(t *testing.T) {
 	}
 }
 
+func TestKind_JSON(t *testing.T) {
+	for kind := InvalidKind + 1; kind <= MAX_VALID_KIND; kind++ {
+		bz, err := json.Marshal(kind)
+		if err != nil {
+			t.Fatalf("unexpected error marshaling kind %s: %v", kind, err)
+		}
+
+		if string(bz) != fmt.Sprintf("%q", kind.String()) {
+			t.Errorf("expected kind %s to marshal to its name, got %s", kind, bz)
+		}
+
+		var decoded Kind
+		if err := json.Unmarshal(bz, &decoded); err != nil {
+			t.Fatalf("unexpected error unmarshaling kind %s: %v", kind, err)
+		}
+
+		if decoded != kind {
+			t.Errorf("expected %s, got %s", kind, decoded)
+		}
+	}
+
+	if _, err := json.Marshal(InvalidKind); err == nil {
+		t.Errorf("expected error marshaling invalid kind")
+	}
+
+	var decoded Kind
+	if err := json.Unmarshal([]byte(`"foo"`), &decoded); err == nil {
+		t.Errorf("expected error unmarshaling unknown kind")
+	}
+}
+
+func TestKind_IsValidMapKeyKind(t *testing.T) {
+	validKeyKinds := map[Kind]bool{
+		StringKind:        true,
+		Int8Kind:          true,
+		Uint8Kind:         true,
+		Int16Kind:         true,
+		Uint16Kind:        true,
+		Int32Kind:         true,
+		Uint32Kind:        true,
+		Int64Kind:         true,
+		Uint64Kind:        true,
+		IntegerStringKind: true,
+		BoolKind:          true,
+	}
+
+	for kind := InvalidKind; kind <= MAX_VALID_KIND; kind++ {
+		if got := kind.IsValidMapKeyKind(); got != validKeyKinds[kind] {
+			t.Errorf("expected IsValidMapKeyKind() for kind %s to be %t, got %t", kind, validKeyKinds[kind], got)
+		}
+	}
+}
+
 func TestKindForGoValue(t *testing.T) {
 	tests := []struct {
 		value interface{}
@@ -0,0 +1,13 @@
+package schema
+
+import "errors"
+
+// validateMetadata checks that the metadata of an object type, field or enum type has no empty keys.
+func validateMetadata(metadata map[string]string) error {
+	for key := range metadata {
+		if key == "" {
+			return errors.New("metadata keys cannot be empty")
+		}
+	}
+	return nil
+}
@@ -1,13 +1,18 @@
 package schema
 
 import (
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"sort"
 )
 
 // ModuleSchema represents the logical schema of a module for purposes of indexing and querying.
 type ModuleSchema struct {
-	types map[string]Type
+	types             map[string]Type
+	reservedTypeNames []string
+	version           uint64
 }
 
 // NewModuleSchema constructs a new ModuleSchema and validates it. Any module schema returned without an error
@@ -21,7 +26,7 @@ func NewModuleSchema(objectTypes []ObjectType) (ModuleSchema, error) {
 
 	res := ModuleSchema{types: types}
 
-	// validate adds all enum types to the type map
+	// validate adds all enum and struct types to the type map
 	err := res.Validate()
 	if err != nil {
 		return ModuleSchema{}, err
@@ -30,6 +35,157 @@ func NewModuleSchema(objectTypes []ObjectType) (ModuleSchema, error) {
 	return res, nil
 }
 
+// WithReservedTypeNames returns a copy of the module schema whose reserved type names are replaced with names
+// and validates it. Reserved type names cannot be used by any object, enum or struct type in the module schema,
+// similar to reserved names in protobuf. When a type is removed, its name should be reserved so that future
+// versions of the schema do not accidentally reuse it with a different meaning. Reserved names must conform
+// to the NameFormat regular expression and be unique.
+func (s ModuleSchema) WithReservedTypeNames(names ...string) (ModuleSchema, error) {
+	reserved := append([]string(nil), names...)
+	sort.Strings(reserved)
+	res := ModuleSchema{types: s.types, reservedTypeNames: reserved, version: s.version}
+	if err := res.Validate(); err != nil {
+		return ModuleSchema{}, err
+	}
+	return res, nil
+}
+
+// ReservedTypeNames returns the reserved type names of the module schema in sorted order.
+func (s ModuleSchema) ReservedTypeNames() []string {
+	return append([]string(nil), s.reservedTypeNames...)
+}
+
+// WithVersion returns a copy of the module schema with the given version. Modules should increase the
+// version of their schema whenever it changes in a chain upgrade, so that the schema, and the codec which
+// decodes state written with it, can be told apart from the schemas of other heights. Version 0 means that
+// the schema is not versioned.
+func (s ModuleSchema) WithVersion(version uint64) ModuleSchema {
+	s.version = version
+	return s
+}
+
+// Version returns the version of the module schema, or 0 if it is not versioned.
+func (s ModuleSchema) Version() uint64 {
+	return s.version
+}
+
+// WithEventTypes returns a copy of the module schema whose event types are replaced with eventTypes and
+// validates it. Event type names must be unique amongst all the types in the module schema and enum and
+// struct types referenced by event types must be compatible with those referenced by object types in the
+// same way as they must be between object types.
+func (s ModuleSchema) WithEventTypes(eventTypes ...EventType) (ModuleSchema, error) {
+	types := map[string]Type{}
+	s.ObjectTypes(func(objectType ObjectType) bool {
+		types[objectType.Name] = objectType
+		return true
+	})
+
+	for _, eventType := range eventTypes {
+		if _, ok := types[eventType.Name]; ok {
+			return ModuleSchema{}, fmt.Errorf("duplicate type name %q", eventType.Name)
+		}
+		types[eventType.Name] = eventType
+	}
+
+	res := ModuleSchema{types: types, reservedTypeNames: s.reservedTypeNames, version: s.version}
+	if err := res.Validate(); err != nil {
+		return ModuleSchema{}, err
+	}
+	return res, nil
+}
+
+// MergeModuleSchemas returns a new module schema which contains the union of the object, enum, struct and
+// event types and the reserved type names of a and b. This allows a module schema to be assembled from
+// fragments which are defined independently. Object and event types which are defined in both schemas must
+// have identical definitions and enum and struct types which are referenced in both schemas must be
+// compatible in the same way as they must be within a single module schema. If only one of the schemas is
+// versioned, the merged schema has its version. An error is returned if there are conflicting definitions or
+// versions or the merged schema is otherwise invalid.
+func MergeModuleSchemas(a, b ModuleSchema) (ModuleSchema, error) {
+	version := a.version
+	if b.version != 0 {
+		if version != 0 && version != b.version {
+			return ModuleSchema{}, fmt.Errorf("cannot merge module schemas: conflicting versions %d and %d", a.version, b.version)
+		}
+		version = b.version
+	}
+
+	objectTypes := a.ObjectTypesSorted()
+	var err error
+	b.ObjectTypes(func(objectType ObjectType) bool {
+		existing, ok := a.types[objectType.Name]
+		if !ok {
+			objectTypes = append(objectTypes, objectType)
+			return true
+		}
+
+		if !reflect.DeepEqual(existing, objectType) {
+			err = fmt.Errorf("cannot merge module schemas: object type %q has conflicting definitions", objectType.Name)
+			return false
+		}
+
+		return true
+	})
+	if err != nil {
+		return ModuleSchema{}, err
+	}
+
+	eventTypes := a.EventTypesSorted()
+	b.EventTypes(func(eventType EventType) bool {
+		existing, ok := a.types[eventType.Name]
+		if !ok {
+			eventTypes = append(eventTypes, eventType)
+			return true
+		}
+
+		if !reflect.DeepEqual(existing, eventType) {
+			err = fmt.Errorf("cannot merge module schemas: event type %q has conflicting definitions", eventType.Name)
+			return false
+		}
+
+		return true
+	})
+	if err != nil {
+		return ModuleSchema{}, err
+	}
+
+	var reserved []string
+	seenReserved := map[string]bool{}
+	for _, name := range append(a.ReservedTypeNames(), b.reservedTypeNames...) {
+		if !seenReserved[name] {
+			reserved = append(reserved, name)
+			seenReserved[name] = true
+		}
+	}
+
+	res, err := NewModuleSchema(objectTypes)
+	if err == nil {
+		res, err = res.WithEventTypes(eventTypes...)
+	}
+	if err == nil {
+		res, err = res.WithReservedTypeNames(reserved...)
+	}
+	if err != nil {
+		return ModuleSchema{}, fmt.Errorf("cannot merge module schemas: %v", err) //nolint:errorlint // false positive due to using go1.12
+	}
+
+	return res.WithVersion(version), nil
+}
+
+// addFieldTypes adds any enum, struct or oneof types referenced by the field to the type map.
+func addFieldTypes(types map[string]Type, field Field) error {
+	switch field.typeKind() {
+	case EnumKind:
+		return addEnumType(types, field)
+	case StructKind:
+		return addStructType(types, field)
+	case OneOfKind:
+		return addOneOfType(types, field)
+	default:
+		return nil
+	}
+}
+
 func addEnumType(types map[string]Type, field Field) error {
 	enumDef := field.EnumType
 	if enumDef.Name == "" {
@@ -63,24 +219,111 @@ func addEnumType(types map[string]Type, field Field) error {
 		}
 	}
 
+	for _, value := range enumDef.Values {
+		existingNum, _ := existingEnum.NumericValue(value)
+		num, _ := enumDef.NumericValue(value)
+		if existingNum != num {
+			return fmt.Errorf("enum %q has different numeric values in different fields", enumDef.Name)
+		}
+	}
+
+	return nil
+}
+
+func addStructType(types map[string]Type, field Field) error {
+	structDef := field.StructType
+	if structDef.Name == "" {
+		return nil
+	}
+
+	existing, ok := types[structDef.Name]
+	if ok {
+		existingStruct, ok := existing.(StructType)
+		if !ok {
+			return fmt.Errorf("struct %q already exists as a different non-struct type", structDef.Name)
+		}
+
+		if !reflect.DeepEqual(existingStruct, structDef) {
+			return fmt.Errorf("struct %q has different definitions in different fields", structDef.Name)
+		}
+
+		return nil
+	}
+
+	types[structDef.Name] = structDef
+
+	// nested enum and struct types also get added to the type map
+	for _, nestedField := range structDef.Fields {
+		err := addFieldTypes(types, nestedField)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func addOneOfType(types map[string]Type, field Field) error {
+	oneOfDef := field.OneOfType
+	if oneOfDef.Name == "" {
+		return nil
+	}
+
+	existing, ok := types[oneOfDef.Name]
+	if ok {
+		existingOneOf, ok := existing.(OneOfType)
+		if !ok {
+			return fmt.Errorf("oneof %q already exists as a different non-oneof type", oneOfDef.Name)
+		}
+
+		if !reflect.DeepEqual(existingOneOf, oneOfDef) {
+			return fmt.Errorf("oneof %q has different definitions in different fields", oneOfDef.Name)
+		}
+
+		return nil
+	}
+
+	types[oneOfDef.Name] = oneOfDef
+
+	// enum and struct types referenced by cases also get added to the type map
+	for _, c := range oneOfDef.Cases {
+		err := addFieldTypes(types, c)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // Validate validates the module schema.
 func (s ModuleSchema) Validate() error {
 	for _, typ := range s.types {
-		objTyp, ok := typ.(ObjectType)
-		if !ok {
-			continue
+		// all enum, struct and oneof types get added to the type map when we call ObjectType.validate
+		// or EventType.validate
+		var err error
+		switch typ := typ.(type) {
+		case ObjectType:
+			err = typ.validate(s.types)
+		case EventType:
+			err = typ.validate(s.types)
 		}
-
-		// all enum types get added to the type map when we call ObjectType.validate
-		err := objTyp.validate(s.types)
 		if err != nil {
 			return err
 		}
 	}
 
+	reserved, err := validateReservedNames(s.reservedTypeNames)
+	if err != nil {
+		return fmt.Errorf("invalid reserved type names: %v", err) //nolint:errorlint // false positive due to using go1.12
+	}
+
+	for name := range reserved {
+		if _, ok := s.types[name]; ok {
+			return fmt.Errorf("type name %q is reserved", name)
+		}
+	}
+
 	return nil
 }
 
@@ -99,6 +342,91 @@ func (s ModuleSchema) ValidateObjectUpdate(update ObjectUpdate) error {
 	return objTyp.ValidateObjectUpdate(update)
 }
 
+// ValidateUpdateBatch validates all the updates in the batch against the module schema. Validation
+// does not stop at the first invalid update. If any updates are invalid, an ObjectUpdateBatchError is
+// returned which describes each invalid update and its position in the batch.
+func (s ModuleSchema) ValidateUpdateBatch(batch ObjectUpdateBatch) error {
+	var errs []ObjectUpdateError
+	for i, update := range batch {
+		if err := s.ValidateObjectUpdate(update); err != nil {
+			errs = append(errs, ObjectUpdateError{Index: i, TypeName: update.TypeName, Err: err})
+		}
+	}
+
+	if len(errs) != 0 {
+		return ObjectUpdateBatchError{Errors: errs}
+	}
+
+	return nil
+}
+
+// moduleSchemaJSON is the JSON representation of a ModuleSchema. Enum, struct and oneof types are not included
+// because they are defined inline in the fields which reference them.
+type moduleSchemaJSON struct {
+	ObjectTypes       []ObjectType `json:"object_types"`
+	EventTypes        []EventType  `json:"event_types,omitempty"`
+	ReservedTypeNames []string     `json:"reserved_type_names,omitempty"`
+	Version           uint64       `json:"version,omitempty"`
+}
+
+// MarshalJSON marshals the module schema to JSON. The encoding is canonical: object types are sorted by name,
+// fields retain their declared order and the same module schema always produces the same bytes.
+func (s ModuleSchema) MarshalJSON() ([]byte, error) {
+	res := moduleSchemaJSON{ObjectTypes: []ObjectType{}, ReservedTypeNames: s.reservedTypeNames, Version: s.version}
+	s.ObjectTypes(func(objectType ObjectType) bool {
+		res.ObjectTypes = append(res.ObjectTypes, objectType)
+		return true
+	})
+	s.EventTypes(func(eventType EventType) bool {
+		res.EventTypes = append(res.EventTypes, eventType)
+		return true
+	})
+	return json.Marshal(res)
+}
+
+// Fingerprint returns the SHA-256 hash of the canonical JSON encoding of the module schema produced by
+// MarshalJSON. Two module schemas have the same fingerprint if and only if they have the same types and
+// version, so indexers can use it to detect schema changes between restarts. Fingerprint panics if the module
+// schema cannot be marshaled to JSON, which is only possible if it contains default values that JSON cannot
+// represent such as NaN floats.
+func (s ModuleSchema) Fingerprint() [32]byte {
+	bz, err := s.MarshalJSON()
+	if err != nil {
+		panic(fmt.Errorf("can't compute module schema fingerprint: %v", err)) //nolint:errorlint // false positive due to using go1.12
+	}
+	return sha256.Sum256(bz)
+}
+
+// UnmarshalJSON unmarshals the module schema from JSON and validates it with NewModuleSchema.
+func (s *ModuleSchema) UnmarshalJSON(data []byte) error {
+	var res moduleSchemaJSON
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+
+	moduleSchema, err := NewModuleSchema(res.ObjectTypes)
+	if err != nil {
+		return err
+	}
+
+	if len(res.EventTypes) != 0 {
+		moduleSchema, err = moduleSchema.WithEventTypes(res.EventTypes...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(res.ReservedTypeNames) != 0 {
+		moduleSchema, err = moduleSchema.WithReservedTypeNames(res.ReservedTypeNames...)
+		if err != nil {
+			return err
+		}
+	}
+
+	*s = moduleSchema.WithVersion(res.Version)
+	return nil
+}
+
 // LookupType looks up a type by name in the module schema.
 func (s ModuleSchema) LookupType(name string) (Type, bool) {
 	typ, ok := s.types[name]
@@ -141,3 +469,93 @@ func (s ModuleSchema) EnumTypes(f func(EnumType) bool) {
 		return true
 	})
 }
+
+// StructTypes iterators over all the struct types in the schema in alphabetical order.
+func (s ModuleSchema) StructTypes(f func(StructType) bool) {
+	s.Types(func(t Type) bool {
+		structType, ok := t.(StructType)
+		if ok {
+			return f(structType)
+		}
+		return true
+	})
+}
+
+// OneOfTypes iterators over all the oneof types in the schema in alphabetical order.
+func (s ModuleSchema) OneOfTypes(f func(OneOfType) bool) {
+	s.Types(func(t Type) bool {
+		oneOfType, ok := t.(OneOfType)
+		if ok {
+			return f(oneOfType)
+		}
+		return true
+	})
+}
+
+// EventTypes iterators over all the event types in the schema in alphabetical order.
+func (s ModuleSchema) EventTypes(f func(EventType) bool) {
+	s.Types(func(t Type) bool {
+		eventType, ok := t.(EventType)
+		if ok {
+			return f(eventType)
+		}
+		return true
+	})
+}
+
+// AllTypes returns an iterator over all the types in the module schema in sorted order by name. The returned
+// function has the same underlying type as iter.Seq[Type], so it can be used with range-over-func in go 1.23+,
+// e.g. `for typ := range moduleSchema.AllTypes()`, while this package remains compatible with go 1.12.
+func (s ModuleSchema) AllTypes() func(yield func(Type) bool) {
+	return s.Types
+}
+
+// ObjectTypesSorted returns the object types in the module schema in sorted order by name.
+func (s ModuleSchema) ObjectTypesSorted() []ObjectType {
+	var res []ObjectType
+	s.ObjectTypes(func(objectType ObjectType) bool {
+		res = append(res, objectType)
+		return true
+	})
+	return res
+}
+
+// EnumTypesSorted returns the enum types in the module schema in sorted order by name.
+func (s ModuleSchema) EnumTypesSorted() []EnumType {
+	var res []EnumType
+	s.EnumTypes(func(enumType EnumType) bool {
+		res = append(res, enumType)
+		return true
+	})
+	return res
+}
+
+// StructTypesSorted returns the struct types in the module schema in sorted order by name.
+func (s ModuleSchema) StructTypesSorted() []StructType {
+	var res []StructType
+	s.StructTypes(func(structType StructType) bool {
+		res = append(res, structType)
+		return true
+	})
+	return res
+}
+
+// OneOfTypesSorted returns the oneof types in the module schema in sorted order by name.
+func (s ModuleSchema) OneOfTypesSorted() []OneOfType {
+	var res []OneOfType
+	s.OneOfTypes(func(oneOfType OneOfType) bool {
+		res = append(res, oneOfType)
+		return true
+	})
+	return res
+}
+
+// EventTypesSorted returns the event types in the module schema in sorted order by name.
+func (s ModuleSchema) EventTypesSorted() []EventType {
+	var res []EventType
+	s.EventTypes(func(eventType EventType) bool {
+		res = append(res, eventType)
+		return true
+	})
+	return res
+}
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"encoding/json"
 	"reflect"
 	"strings"
 	"testing"
@@ -103,6 +104,32 @@ func TestModuleSchema_Validate(t *testing.T) {
 			},
 			errContains: "different values",
 		},
+		{
+			name: "enum with different numeric values",
+			objectTypes: []ObjectType{
+				{
+					Name: "object1",
+					KeyFields: []Field{
+						{
+							Name:     "k",
+							Kind:     EnumKind,
+							EnumType: EnumType{Name: "enum1", Values: []string{"a", "b"}},
+						},
+					},
+				},
+				{
+					Name: "object2",
+					KeyFields: []Field{
+						{
+							Name:     "k",
+							Kind:     EnumKind,
+							EnumType: EnumType{Name: "enum1", Values: []string{"a", "b"}, NumericValues: []int32{0, 5}},
+						},
+					},
+				},
+			},
+			errContains: "enum \"enum1\" has different numeric values in different fields",
+		},
 		{
 			name: "same enum",
 			objectTypes: []ObjectType{{
@@ -151,6 +178,95 @@ func TestModuleSchema_Validate(t *testing.T) {
 			},
 			errContains: "enum \"type1\" already exists as a different non-enum type",
 		},
+		{
+			name: "same struct",
+			objectTypes: []ObjectType{
+				{
+					Name:        "object1",
+					KeyFields:   []Field{{Name: "k", Kind: StringKind}},
+					ValueFields: []Field{{Name: "v", Kind: StructKind, StructType: testPointStruct}},
+				},
+				{
+					Name:        "object2",
+					KeyFields:   []Field{{Name: "k", Kind: StringKind}},
+					ValueFields: []Field{{Name: "v", Kind: StructKind, StructType: testPointStruct}},
+				},
+			},
+		},
+		{
+			name: "same struct with different fields",
+			objectTypes: []ObjectType{
+				{
+					Name:        "object1",
+					KeyFields:   []Field{{Name: "k", Kind: StringKind}},
+					ValueFields: []Field{{Name: "v", Kind: StructKind, StructType: testPointStruct}},
+				},
+				{
+					Name:      "object2",
+					KeyFields: []Field{{Name: "k", Kind: StringKind}},
+					ValueFields: []Field{
+						{
+							Name: "v",
+							Kind: StructKind,
+							StructType: StructType{
+								Name:   "point",
+								Fields: []Field{{Name: "x", Kind: Int64Kind}},
+							},
+						},
+					},
+				},
+			},
+			errContains: "struct \"point\" has different definitions in different fields",
+		},
+		{
+			name: "struct name collides with object type",
+			objectTypes: []ObjectType{
+				{
+					Name: "point",
+					ValueFields: []Field{
+						{Name: "v", Kind: StructKind, StructType: testPointStruct},
+					},
+				},
+			},
+			errContains: "struct \"point\" already exists as a different non-struct type",
+		},
+		{
+			name: "nested enum collides with struct",
+			objectTypes: []ObjectType{
+				{
+					Name: "object1",
+					ValueFields: []Field{
+						{Name: "v", Kind: StructKind, StructType: testPointStruct},
+						{
+							Name: "e",
+							Kind: StructKind,
+							StructType: StructType{
+								Name: "wrapper",
+								Fields: []Field{
+									{Name: "e", Kind: EnumKind, EnumType: EnumType{Name: "point", Values: []string{"a"}}},
+								},
+							},
+						},
+					},
+				},
+			},
+			errContains: "enum \"point\" already exists as a different non-enum type",
+		},
+		{
+			name: "list of enums conflicts with enum",
+			objectTypes: []ObjectType{
+				{
+					Name: "object1",
+					KeyFields: []Field{
+						{Name: "k", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a", "b"}}},
+					},
+					ValueFields: []Field{
+						{Name: "v", Kind: ListKind, ElementKind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a", "c"}}},
+					},
+				},
+			},
+			errContains: "enum \"enum1\" has different values in different fields",
+		},
 	}
 
 	for _, tt := range tests {
@@ -258,6 +374,468 @@ func TestModuleSchema_ValidateObjectUpdate(t *testing.T) {
 	}
 }
 
+func TestModuleSchema_ValidateUpdateBatch(t *testing.T) {
+	moduleSchema := requireModuleSchema(t, []ObjectType{
+		{
+			Name:        "object1",
+			KeyFields:   []Field{{Name: "key", Kind: StringKind}},
+			ValueFields: []Field{{Name: "value", Kind: Int32Kind}},
+		},
+	})
+
+	err := moduleSchema.ValidateUpdateBatch(ObjectUpdateBatch{
+		{TypeName: "object1", Key: "a", Value: int32(1)},
+		{TypeName: "object1", Key: "b", Delete: true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = moduleSchema.ValidateUpdateBatch(ObjectUpdateBatch{
+		{TypeName: "object1", Key: 1, Value: int32(1)},
+		{TypeName: "object1", Key: "b", Value: int32(2)},
+		{TypeName: "object2", Key: "c"},
+	})
+	batchErr, ok := err.(ObjectUpdateBatchError)
+	if !ok {
+		t.Fatalf("expected ObjectUpdateBatchError, got: %v", err)
+	}
+
+	if len(batchErr.Errors) != 2 {
+		t.Fatalf("expected 2 errors, got: %v", batchErr.Errors)
+	}
+
+	if batchErr.Errors[0].Index != 0 || batchErr.Errors[0].TypeName != "object1" {
+		t.Errorf("unexpected first error: %v", batchErr.Errors[0])
+	}
+
+	if batchErr.Errors[1].Index != 2 || batchErr.Errors[1].TypeName != "object2" {
+		t.Errorf("unexpected second error: %v", batchErr.Errors[1])
+	}
+
+	expected := "2 invalid object update(s): update 0 (object1): "
+	if !strings.HasPrefix(err.Error(), expected) {
+		t.Errorf("expected error to start with %q, got: %v", expected, err)
+	}
+
+	if !strings.Contains(err.Error(), "update 2 (object2): object type \"object2\" not found in module schema") {
+		t.Errorf("expected error to describe update 2, got: %v", err)
+	}
+}
+
+func TestModuleSchema_WithReservedTypeNames(t *testing.T) {
+	moduleSchema := requireModuleSchema(t, []ObjectType{
+		{
+			Name:      "object1",
+			KeyFields: []Field{{Name: "key", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a"}}}},
+		},
+	})
+
+	reserved, err := moduleSchema.WithReservedTypeNames("old_object", "old_enum")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(reserved.ReservedTypeNames(), []string{"old_enum", "old_object"}) {
+		t.Fatalf("unexpected reserved type names: %v", reserved.ReservedTypeNames())
+	}
+
+	if len(moduleSchema.ReservedTypeNames()) != 0 {
+		t.Fatalf("expected original module schema to be unchanged")
+	}
+
+	bz, err := json.Marshal(reserved)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded ModuleSchema
+	if err := json.Unmarshal(bz, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.ReservedTypeNames(), reserved.ReservedTypeNames()) {
+		t.Fatalf("expected reserved type names to survive a JSON round trip, got: %v", decoded.ReservedTypeNames())
+	}
+
+	tests := []struct {
+		name        string
+		names       []string
+		errContains string
+	}{
+		{
+			name:        "reserved object type name",
+			names:       []string{"object1"},
+			errContains: "type name \"object1\" is reserved",
+		},
+		{
+			name:        "reserved enum type name",
+			names:       []string{"enum1"},
+			errContains: "type name \"enum1\" is reserved",
+		},
+		{
+			name:        "invalid reserved type name",
+			names:       []string{"1abc"},
+			errContains: "invalid reserved type names: invalid reserved name \"1abc\"",
+		},
+		{
+			name:        "duplicate reserved type name",
+			names:       []string{"abc", "abc"},
+			errContains: "duplicate reserved name \"abc\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := moduleSchema.WithReservedTypeNames(tt.names...)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error to contain %q, got: %v", tt.errContains, err)
+			}
+		})
+	}
+
+	err = json.Unmarshal([]byte(`{"object_types":[{"name":"object1","key_fields":[{"name":"key","kind":"string"}]}],"reserved_type_names":["object1"]}`), &decoded)
+	if err == nil || !strings.Contains(err.Error(), "type name \"object1\" is reserved") {
+		t.Fatalf("expected reserved type name error, got: %v", err)
+	}
+}
+
+func TestModuleSchema_WithVersion(t *testing.T) {
+	moduleSchema := requireModuleSchema(t, []ObjectType{
+		{Name: "object1", KeyFields: []Field{{Name: "key", Kind: StringKind}}},
+	})
+
+	v2 := moduleSchema.WithVersion(2)
+	if v2.Version() != 2 || moduleSchema.Version() != 0 {
+		t.Fatalf("expected version 2 and original module schema to be unchanged, got %d and %d", v2.Version(), moduleSchema.Version())
+	}
+	if v2.Fingerprint() == moduleSchema.Fingerprint() {
+		t.Fatalf("expected different fingerprints for different versions")
+	}
+
+	// the version is kept by the other With methods
+	reserved, err := v2.WithReservedTypeNames("old_object")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reserved.Version() != 2 {
+		t.Fatalf("expected version 2, got %d", reserved.Version())
+	}
+
+	bz, err := json.Marshal(reserved)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(bz), `"version":2`) {
+		t.Fatalf("expected version in JSON, got %s", bz)
+	}
+	var decoded ModuleSchema
+	if err := json.Unmarshal(bz, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, reserved) {
+		t.Fatalf("expected %v, got %v", reserved, decoded)
+	}
+
+	merged, err := MergeModuleSchemas(moduleSchema, v2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if merged.Version() != 2 {
+		t.Fatalf("expected merged version 2, got %d", merged.Version())
+	}
+	_, err = MergeModuleSchemas(moduleSchema.WithVersion(1), v2)
+	if err == nil || !strings.Contains(err.Error(), "conflicting versions 1 and 2") {
+		t.Fatalf("expected conflicting versions error, got: %v", err)
+	}
+}
+
+func TestModuleSchema_WithEventTypes(t *testing.T) {
+	statusEnum := EnumType{Name: "status", Values: []string{"active", "inactive"}}
+	moduleSchema := requireModuleSchema(t, []ObjectType{
+		{
+			Name:        "accounts",
+			KeyFields:   []Field{{Name: "address", Kind: AddressKind}},
+			ValueFields: []Field{{Name: "status", Kind: EnumKind, EnumType: statusEnum}},
+		},
+	})
+
+	statusChanged := EventType{
+		Name:   "status_changed",
+		Fields: []Field{{Name: "status", Kind: EnumKind, EnumType: statusEnum}},
+	}
+	withEvents, err := moduleSchema.WithEventTypes(testTransferEvent, statusChanged)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var eventNames []string
+	withEvents.EventTypes(func(eventType EventType) bool {
+		eventNames = append(eventNames, eventType.Name)
+		return true
+	})
+	if !reflect.DeepEqual(eventNames, []string{"status_changed", "transfer"}) {
+		t.Fatalf("unexpected event types: %v", eventNames)
+	}
+
+	if _, ok := moduleSchema.LookupType("transfer"); ok {
+		t.Fatalf("expected original module schema to be unchanged")
+	}
+
+	bz, err := json.Marshal(withEvents)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded ModuleSchema
+	if err := json.Unmarshal(bz, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !DiffModuleSchemas(withEvents, decoded).Empty() {
+		t.Fatalf("expected event types to survive a JSON round trip")
+	}
+
+	replaced, err := withEvents.WithEventTypes(statusChanged)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := replaced.LookupType("transfer"); ok {
+		t.Fatalf("expected event types to be replaced")
+	}
+
+	tests := []struct {
+		name        string
+		eventTypes  []EventType
+		errContains string
+	}{
+		{
+			name:        "conflicts with object type",
+			eventTypes:  []EventType{{Name: "accounts", Fields: []Field{{Name: "a", Kind: StringKind}}}},
+			errContains: "duplicate type name \"accounts\"",
+		},
+		{
+			name:        "duplicate event type",
+			eventTypes:  []EventType{testTransferEvent, testTransferEvent},
+			errContains: "duplicate type name \"transfer\"",
+		},
+		{
+			name:        "conflicts with enum type",
+			eventTypes:  []EventType{{Name: "status", Fields: []Field{{Name: "a", Kind: StringKind}}}},
+			errContains: "enum \"status\" already exists as a different non-enum type",
+		},
+		{
+			name: "incompatible enum type",
+			eventTypes: []EventType{{
+				Name:   "status_changed",
+				Fields: []Field{{Name: "status", Kind: EnumKind, EnumType: EnumType{Name: "status", Values: []string{"active"}}}},
+			}},
+			errContains: "enum \"status\" has different number of values in different fields",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := moduleSchema.WithEventTypes(tt.eventTypes...)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error to contain %q, got: %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+func TestModuleSchema_Fingerprint(t *testing.T) {
+	objectType1 := ObjectType{
+		Name:        "object1",
+		KeyFields:   []Field{{Name: "key", Kind: StringKind}},
+		ValueFields: []Field{{Name: "value", Kind: Int32Kind, Metadata: map[string]string{"a": "1", "b": "2"}}},
+	}
+	objectType2 := ObjectType{
+		Name:      "object2",
+		KeyFields: []Field{{Name: "key", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a", "b"}}}},
+	}
+
+	schema1 := requireModuleSchema(t, []ObjectType{objectType1, objectType2})
+	// the order in which object types are declared doesn't matter
+	schema2 := requireModuleSchema(t, []ObjectType{objectType2, objectType1})
+	if schema1.Fingerprint() != schema2.Fingerprint() {
+		t.Fatalf("expected equal fingerprints")
+	}
+
+	// a schema decoded from JSON has the same fingerprint
+	bz, err := json.Marshal(schema1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded ModuleSchema
+	if err := json.Unmarshal(bz, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if schema1.Fingerprint() != decoded.Fingerprint() {
+		t.Fatalf("expected equal fingerprints after JSON round trip")
+	}
+
+	objectType1.ValueFields = append(objectType1.ValueFields, Field{Name: "value2", Kind: StringKind, Nullable: true})
+	schema3 := requireModuleSchema(t, []ObjectType{objectType1, objectType2})
+	if schema1.Fingerprint() == schema3.Fingerprint() {
+		t.Fatalf("expected different fingerprints")
+	}
+
+	if (ModuleSchema{}).Fingerprint() == schema1.Fingerprint() {
+		t.Fatalf("expected different fingerprints for an empty schema")
+	}
+}
+
+func TestMergeModuleSchemas(t *testing.T) {
+	enum1 := EnumType{Name: "enum1", Values: []string{"a", "b"}}
+	object1 := ObjectType{
+		Name:        "object1",
+		KeyFields:   []Field{{Name: "key", Kind: StringKind}},
+		ValueFields: []Field{{Name: "value", Kind: EnumKind, EnumType: enum1}},
+	}
+	object2 := ObjectType{
+		Name:      "object2",
+		KeyFields: []Field{{Name: "key", Kind: EnumKind, EnumType: enum1}},
+	}
+	object3 := ObjectType{
+		Name:      "object3",
+		KeyFields: []Field{{Name: "key", Kind: Int32Kind}},
+	}
+
+	tests := []struct {
+		name        string
+		a, b        ModuleSchema
+		expected    []string
+		errContains string
+	}{
+		{
+			name:     "disjoint",
+			a:        requireModuleSchema(t, []ObjectType{object1}),
+			b:        requireModuleSchema(t, []ObjectType{object3}),
+			expected: []string{"enum1", "object1", "object3"},
+		},
+		{
+			name:     "shared enum and object type",
+			a:        requireModuleSchema(t, []ObjectType{object1, object3}),
+			b:        requireModuleSchema(t, []ObjectType{object2, object3}),
+			expected: []string{"enum1", "object1", "object2", "object3"},
+		},
+		{
+			name: "conflicting object types",
+			a:    requireModuleSchema(t, []ObjectType{object3}),
+			b: requireModuleSchema(t, []ObjectType{{
+				Name:      "object3",
+				KeyFields: []Field{{Name: "key", Kind: Int64Kind}},
+			}}),
+			errContains: "cannot merge module schemas: object type \"object3\" has conflicting definitions",
+		},
+		{
+			name: "conflicting enum types",
+			a:    requireModuleSchema(t, []ObjectType{object1}),
+			b: requireModuleSchema(t, []ObjectType{{
+				Name:      "object2",
+				KeyFields: []Field{{Name: "key", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a", "c"}}}},
+			}}),
+			errContains: "cannot merge module schemas: enum \"enum1\" has different values in different fields",
+		},
+		{
+			name: "object type conflicts with enum type",
+			a:    requireModuleSchema(t, []ObjectType{object1}),
+			b: requireModuleSchema(t, []ObjectType{{
+				Name:      "enum1",
+				KeyFields: []Field{{Name: "key", Kind: Int32Kind}},
+			}}),
+			errContains: "cannot merge module schemas",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := MergeModuleSchemas(tt.a, tt.b)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error to contain %q, got: %v", tt.errContains, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var typeNames []string
+			merged.Types(func(typ Type) bool {
+				typeNames = append(typeNames, typ.TypeName())
+				return true
+			})
+
+			if !reflect.DeepEqual(typeNames, tt.expected) {
+				t.Fatalf("expected types %v, got %v", tt.expected, typeNames)
+			}
+		})
+	}
+
+	t.Run("event types", func(t *testing.T) {
+		a, err := requireModuleSchema(t, []ObjectType{object1}).WithEventTypes(testTransferEvent)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b, err := requireModuleSchema(t, []ObjectType{object3}).WithEventTypes(testTransferEvent,
+			EventType{Name: "burn", Fields: []Field{{Name: "amount", Kind: IntegerStringKind}}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		merged, err := MergeModuleSchemas(a, b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var eventNames []string
+		merged.EventTypes(func(eventType EventType) bool {
+			eventNames = append(eventNames, eventType.Name)
+			return true
+		})
+		if !reflect.DeepEqual(eventNames, []string{"burn", "transfer"}) {
+			t.Fatalf("unexpected event types: %v", eventNames)
+		}
+
+		conflicting, err := requireModuleSchema(t, []ObjectType{object3}).WithEventTypes(
+			EventType{Name: "transfer", Fields: []Field{{Name: "amount", Kind: StringKind}}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = MergeModuleSchemas(a, conflicting)
+		if err == nil || !strings.Contains(err.Error(), "event type \"transfer\" has conflicting definitions") {
+			t.Fatalf("expected conflicting event type error, got: %v", err)
+		}
+	})
+
+	t.Run("reserved type names", func(t *testing.T) {
+		a, err := requireModuleSchema(t, []ObjectType{object1}).WithReservedTypeNames("old1", "old2")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b, err := requireModuleSchema(t, []ObjectType{object3}).WithReservedTypeNames("old2", "old3")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		merged, err := MergeModuleSchemas(a, b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(merged.ReservedTypeNames(), []string{"old1", "old2", "old3"}) {
+			t.Fatalf("unexpected reserved type names: %v", merged.ReservedTypeNames())
+		}
+
+		reservesObject3, err := requireModuleSchema(t, []ObjectType{object1}).WithReservedTypeNames("object3")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = MergeModuleSchemas(reservesObject3, b)
+		if err == nil || !strings.Contains(err.Error(), "type name \"object3\" is reserved") {
+			t.Fatalf("expected reserved type name error, got: %v", err)
+		}
+	})
+}
+
 func requireModuleSchema(t *testing.T, objectTypes []ObjectType) ModuleSchema {
 	t.Helper()
 	moduleSchema, err := NewModuleSchema(objectTypes)
@@ -406,3 +984,249 @@ func TestModuleSchema_EnumTypes(t *testing.T) {
 		t.Fatalf("expected %v, got %v", expected, typeNames)
 	}
 }
+
+func TestModuleSchema_StructTypes(t *testing.T) {
+	moduleSchema, err := NewModuleSchema([]ObjectType{
+		{
+			Name: "object1",
+			ValueFields: []Field{
+				{
+					Name: "line",
+					Kind: StructKind,
+					StructType: StructType{
+						Name: "line",
+						Fields: []Field{
+							{Name: "start", Kind: StructKind, StructType: testPointStruct},
+							{Name: "end", Kind: StructKind, StructType: testPointStruct},
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var typeNames []string
+	moduleSchema.StructTypes(func(typ StructType) bool {
+		typeNames = append(typeNames, typ.Name)
+		return true
+	})
+
+	expected := []string{"line", "point"}
+	if !reflect.DeepEqual(typeNames, expected) {
+		t.Fatalf("expected %v, got %v", expected, typeNames)
+	}
+}
+
+func TestModuleSchema_JSON(t *testing.T) {
+	moduleSchema := requireModuleSchema(t, []ObjectType{
+		{
+			Name: "object2",
+			KeyFields: []Field{
+				{Name: "key1", Kind: EnumKind, EnumType: EnumType{Name: "enum1", Values: []string{"a", "b"}}},
+			},
+			ValueFields: []Field{
+				{Name: "point", Kind: StructKind, StructType: testPointStruct, Nullable: true},
+				{Name: "tags", Kind: ListKind, ElementKind: StringKind},
+				{Name: "balances", Kind: MapKind, KeyKind: StringKind, ValueKind: IntegerStringKind},
+			},
+			RetainDeletions: true,
+		},
+		{
+			Name:      "object1",
+			KeyFields: []Field{{Name: "key1", Kind: AddressKind}},
+		},
+	})
+
+	bz, err := json.Marshal(moduleSchema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{"object_types":[` +
+		`{"name":"object1","key_fields":[{"name":"key1","kind":"bech32address"}]},` +
+		`{"name":"object2","key_fields":[{"name":"key1","kind":"enum","enum_type":{"name":"enum1","values":["a","b"]}}],` +
+		`"value_fields":[{"name":"point","kind":"struct","nullable":true,"struct_type":{"name":"point","fields":[{"name":"x","kind":"int32"},{"name":"y","kind":"int32"}]}},` +
+		`{"name":"tags","kind":"list","element_kind":"string"},` +
+		`{"name":"balances","kind":"map","key_kind":"string","value_kind":"integer"}],"retain_deletions":true}]}`
+	if string(bz) != expected {
+		t.Fatalf("expected %s, got %s", expected, bz)
+	}
+
+	var decoded ModuleSchema
+	if err := json.Unmarshal(bz, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded, moduleSchema) {
+		t.Fatalf("expected %v, got %v", moduleSchema, decoded)
+	}
+
+	bz2, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(bz2) != string(bz) {
+		t.Fatalf("expected canonical encoding %s, got %s", bz, bz2)
+	}
+}
+
+func TestModuleSchema_UnmarshalJSON_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		json        string
+		errContains string
+	}{
+		{
+			name:        "unknown kind",
+			json:        `{"object_types":[{"name":"object1","key_fields":[{"name":"key1","kind":"foo"}]}]}`,
+			errContains: "unknown kind \"foo\"",
+		},
+		{
+			name:        "invalid field name",
+			json:        `{"object_types":[{"name":"object1","key_fields":[{"name":"","kind":"string"}]}]}`,
+			errContains: "invalid field name",
+		},
+		{
+			name:        "invalid enum",
+			json:        `{"object_types":[{"name":"object1","key_fields":[{"name":"key1","kind":"enum","enum_type":{"name":"enum1","values":[]}}]}]}`,
+			errContains: "enum definition values cannot be empty",
+		},
+		{
+			name:        "nullable key field",
+			json:        `{"object_types":[{"name":"object1","key_fields":[{"name":"key1","kind":"string","nullable":true}]}]}`,
+			errContains: "key field \"key1\" cannot be nullable",
+		},
+		{
+			name: "conflicting enums",
+			json: `{"object_types":[` +
+				`{"name":"object1","key_fields":[{"name":"key1","kind":"enum","enum_type":{"name":"enum1","values":["a"]}}]},` +
+				`{"name":"object2","key_fields":[{"name":"key1","kind":"enum","enum_type":{"name":"enum1","values":["b"]}}]}]}`,
+			errContains: "enum \"enum1\" has different values in different fields",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var moduleSchema ModuleSchema
+			err := json.Unmarshal([]byte(tt.json), &moduleSchema)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error to contain %q, got: %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+func TestModuleSchema_OneOfTypes(t *testing.T) {
+	moduleSchema, err := NewModuleSchema([]ObjectType{
+		{
+			Name: "object1",
+			ValueFields: []Field{
+				{Name: "content", Kind: OneOfKind, OneOfType: testContentOneOf},
+				{Name: "history", Kind: ListKind, ElementKind: OneOfKind, OneOfType: testContentOneOf},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var typeNames []string
+	moduleSchema.OneOfTypes(func(typ OneOfType) bool {
+		typeNames = append(typeNames, typ.Name)
+		return true
+	})
+
+	expected := []string{"content"}
+	if !reflect.DeepEqual(typeNames, expected) {
+		t.Fatalf("expected %v, got %v", expected, typeNames)
+	}
+
+	// struct types referenced by cases are added to the module schema
+	if _, ok := moduleSchema.LookupType("point"); !ok {
+		t.Fatalf("expected struct type point to be in the module schema")
+	}
+
+	_, err = NewModuleSchema([]ObjectType{
+		{
+			Name:        "object1",
+			ValueFields: []Field{{Name: "content", Kind: OneOfKind, OneOfType: testContentOneOf}},
+		},
+		{
+			Name: "object2",
+			ValueFields: []Field{{Name: "content", Kind: OneOfKind, OneOfType: OneOfType{
+				Name:  "content",
+				Cases: []Field{{Name: "text", Kind: StringKind}},
+			}}},
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "oneof \"content\" has different definitions in different fields") {
+		t.Fatalf("expected conflicting oneof definitions error, got: %v", err)
+	}
+}
+
+func TestModuleSchema_AllTypes(t *testing.T) {
+	moduleSchema := exampleSchema(t)
+
+	var typeNames []string
+	moduleSchema.AllTypes()(func(typ Type) bool {
+		typeNames = append(typeNames, typ.TypeName())
+		return len(typeNames) < 3
+	})
+
+	expected := []string{"enum1", "enum2", "object1"}
+	if !reflect.DeepEqual(typeNames, expected) {
+		t.Fatalf("expected %v, got %v", expected, typeNames)
+	}
+}
+
+func TestModuleSchema_TypesSorted(t *testing.T) {
+	moduleSchema, err := exampleSchema(t).WithEventTypes(
+		EventType{Name: "event2", Fields: []Field{{Name: "field1", Kind: StringKind}}},
+		EventType{Name: "event1", Fields: []Field{{Name: "content", Kind: OneOfKind, OneOfType: testContentOneOf}}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var objectTypeNames []string
+	for _, typ := range moduleSchema.ObjectTypesSorted() {
+		objectTypeNames = append(objectTypeNames, typ.Name)
+	}
+	if expected := []string{"object1", "object2"}; !reflect.DeepEqual(objectTypeNames, expected) {
+		t.Fatalf("expected %v, got %v", expected, objectTypeNames)
+	}
+
+	var enumTypeNames []string
+	for _, typ := range moduleSchema.EnumTypesSorted() {
+		enumTypeNames = append(enumTypeNames, typ.Name)
+	}
+	if expected := []string{"enum1", "enum2"}; !reflect.DeepEqual(enumTypeNames, expected) {
+		t.Fatalf("expected %v, got %v", expected, enumTypeNames)
+	}
+
+	var eventTypeNames []string
+	for _, typ := range moduleSchema.EventTypesSorted() {
+		eventTypeNames = append(eventTypeNames, typ.Name)
+	}
+	if expected := []string{"event1", "event2"}; !reflect.DeepEqual(eventTypeNames, expected) {
+		t.Fatalf("expected %v, got %v", expected, eventTypeNames)
+	}
+
+	oneOfTypes := moduleSchema.OneOfTypesSorted()
+	if len(oneOfTypes) != 1 || oneOfTypes[0].Name != "content" {
+		t.Fatalf("expected oneof type content, got %v", oneOfTypes)
+	}
+
+	structTypes := moduleSchema.StructTypesSorted()
+	if len(structTypes) != 1 || structTypes[0].Name != "point" {
+		t.Fatalf("expected struct type point, got %v", structTypes)
+	}
+
+	if typs := (ModuleSchema{}).ObjectTypesSorted(); len(typs) != 0 {
+		t.Fatalf("expected no object types, got %v", typs)
+	}
+}
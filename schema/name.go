@@ -1,6 +1,9 @@
 package schema
 
-import "regexp"
+import (
+	"fmt"
+	"regexp"
+)
 
 // NameFormat is the regular expression that a name must match.
 // A name must start with a letter or underscore and can only contain letters, numbers, and underscores.
@@ -13,3 +16,19 @@ var nameRegex = regexp.MustCompile(NameFormat)
 func ValidateName(name string) bool {
 	return nameRegex.MatchString(name)
 }
+
+// validateReservedNames checks that the reserved names are valid and unique and returns them as a set.
+func validateReservedNames(names []string) (map[string]bool, error) {
+	res := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !ValidateName(name) {
+			return nil, fmt.Errorf("invalid reserved name %q", name)
+		}
+
+		if res[name] {
+			return nil, fmt.Errorf("duplicate reserved name %q", name)
+		}
+		res[name] = true
+	}
+	return res, nil
+}
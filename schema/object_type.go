@@ -1,30 +1,65 @@
 package schema
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
 
 // ObjectType describes an object type a module schema.
 type ObjectType struct {
 	// Name is the name of the object type. It must be unique within the module schema amongst all object and enum
 	// types and conform to the NameFormat regular expression.
-	Name string
+	Name string `json:"name"`
 
 	// KeyFields is a list of fields that make up the primary key of the object.
 	// It can be empty in which case indexers should assume that this object is
 	// a singleton and only has one value. Field names must be unique within the
-	// object between both key and value fields. Key fields CANNOT be nullable.
-	KeyFields []Field
+	// object between both key and value fields. Key fields CANNOT be nullable,
+	// CANNOT be lists or maps and CANNOT have default values.
+	KeyFields []Field `json:"key_fields,omitempty"`
 
 	// ValueFields is a list of fields that are not part of the primary key of the object.
 	// It can be empty in the case where all fields are part of the primary key.
 	// Field names must be unique within the object between both key and value fields.
-	ValueFields []Field
+	ValueFields []Field `json:"value_fields,omitempty"`
 
 	// RetainDeletions is a flag that indicates whether the indexer should retain
 	// deleted rows in the database and flag them as deleted rather than actually
 	// deleting the row. For many types of data in state, the data is deleted even
 	// though it is still valid in order to save space. Indexers will want to have
 	// the option of retaining such data and distinguishing from other "true" deletions.
-	RetainDeletions bool
+	// Object types which retain deletions require their full history to be kept and
+	// cannot have a Retention policy which prunes history.
+	RetainDeletions bool `json:"retain_deletions,omitempty"`
+
+	// Retention is an optional hint to indexers about how much history of the object type they need
+	// to keep when pruning historical data. If it is nil, the full history should be kept.
+	Retention *RetentionPolicy `json:"retention,omitempty"`
+
+	// UniqueConstraints is a list of sets of value field names whose combined values must be unique
+	// amongst all objects of this type, in addition to the primary key. Each constraint must name at
+	// least one value field, cannot name the same field twice and cannot include list or map fields.
+	// Indexers may use these to create unique indexes and to detect module bugs early.
+	UniqueConstraints [][]string `json:"unique_constraints,omitempty"`
+
+	// Indexes is a list of secondary indexes on the value fields of the object which indexers
+	// should create to improve query performance. See IndexDescriptor for details.
+	Indexes []IndexDescriptor `json:"indexes,omitempty"`
+
+	// Description is an optional human-readable description of the object type for use in indexer UIs
+	// and generated API documentation.
+	Description string `json:"description,omitempty"`
+
+	// Metadata is an optional set of key-value pairs which tools can use to attach additional
+	// information to the object type. Keys cannot be empty.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// ReservedFieldNames is a list of field names which cannot be used by the key or value fields of the
+	// object type, similar to reserved field names in protobuf. When a field is removed, its name should
+	// be reserved so that future versions of the schema do not accidentally reuse it with a different
+	// meaning. Reserved names must conform to the NameFormat regular expression and be unique.
+	ReservedFieldNames []string `json:"reserved_field_names,omitempty"`
 }
 
 // TypeName implements the Type interface.
@@ -46,6 +81,15 @@ func (o ObjectType) validate(types map[string]Type) error {
 		return fmt.Errorf("invalid object type name %q", o.Name)
 	}
 
+	if err := validateMetadata(o.Metadata); err != nil {
+		return fmt.Errorf("invalid metadata for object type %q: %v", o.Name, err) //nolint:errorlint // false positive due to using go1.12
+	}
+
+	reservedNames, err := validateReservedNames(o.ReservedFieldNames)
+	if err != nil {
+		return fmt.Errorf("invalid reserved field names for object type %q: %v", o.Name, err) //nolint:errorlint // false positive due to using go1.12
+	}
+
 	fieldNames := map[string]bool{}
 
 	for _, field := range o.KeyFields {
@@ -57,12 +101,28 @@ func (o ObjectType) validate(types map[string]Type) error {
 			return fmt.Errorf("key field %q cannot be nullable", field.Name)
 		}
 
+		if field.Kind == ListKind || field.Kind == MapKind {
+			return fmt.Errorf("key field %q cannot be a list or map", field.Name)
+		}
+
+		if field.Kind == OneOfKind {
+			return fmt.Errorf("key field %q cannot be a oneof", field.Name)
+		}
+
+		if field.Default != nil {
+			return fmt.Errorf("key field %q cannot have a default value", field.Name)
+		}
+
 		if fieldNames[field.Name] {
 			return fmt.Errorf("duplicate field name %q", field.Name)
 		}
 		fieldNames[field.Name] = true
 
-		err := addEnumType(types, field)
+		if reservedNames[field.Name] {
+			return fmt.Errorf("field name %q is reserved in object type %q", field.Name, o.Name)
+		}
+
+		err := addFieldTypes(types, field)
 		if err != nil {
 			return err
 		}
@@ -78,7 +138,11 @@ func (o ObjectType) validate(types map[string]Type) error {
 		}
 		fieldNames[field.Name] = true
 
-		err := addEnumType(types, field)
+		if reservedNames[field.Name] {
+			return fmt.Errorf("field name %q is reserved in object type %q", field.Name, o.Name)
+		}
+
+		err := addFieldTypes(types, field)
 		if err != nil {
 			return err
 		}
@@ -88,6 +152,97 @@ func (o ObjectType) validate(types map[string]Type) error {
 		return fmt.Errorf("object type %q has no key or value fields", o.Name)
 	}
 
+	if o.Retention != nil {
+		if err := o.Retention.Validate(); err != nil {
+			return fmt.Errorf("invalid retention policy for object type %q: %v", o.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+
+		if o.RetainDeletions && o.Retention.Prunes() {
+			return fmt.Errorf("object type %q retains deletions and cannot have retention mode %s", o.Name, o.Retention.Mode)
+		}
+	}
+
+	valueFields := map[string]Field{}
+	for _, field := range o.ValueFields {
+		valueFields[field.Name] = field
+	}
+
+	if err := o.validateUniqueConstraints(valueFields); err != nil {
+		return err
+	}
+
+	indexNames := map[string]bool{}
+	for _, index := range o.Indexes {
+		if err := index.validate(valueFields); err != nil {
+			return fmt.Errorf("invalid index in object type %q: %v", o.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+
+		if indexNames[index.Name] {
+			return fmt.Errorf("duplicate index name %q in object type %q", index.Name, o.Name)
+		}
+		indexNames[index.Name] = true
+	}
+
+	return nil
+}
+
+// validateUniqueConstraints checks that every unique constraint names a valid set of value fields.
+func (o ObjectType) validateUniqueConstraints(valueFields map[string]Field) error {
+	constraints := map[string]bool{}
+	for _, constraint := range o.UniqueConstraints {
+		if len(constraint) == 0 {
+			return fmt.Errorf("unique constraint in object type %q cannot be empty", o.Name)
+		}
+
+		constraintFields := map[string]bool{}
+		for _, name := range constraint {
+			field, ok := valueFields[name]
+			if !ok {
+				return fmt.Errorf("unique constraint in object type %q references unknown value field %q", o.Name, name)
+			}
+
+			if field.Kind == ListKind || field.Kind == MapKind {
+				return fmt.Errorf("unique constraint in object type %q cannot include list or map field %q", o.Name, name)
+			}
+
+			if field.Kind == OneOfKind {
+				return fmt.Errorf("unique constraint in object type %q cannot include oneof field %q", o.Name, name)
+			}
+
+			if constraintFields[name] {
+				return fmt.Errorf("unique constraint in object type %q references field %q more than once", o.Name, name)
+			}
+			constraintFields[name] = true
+		}
+
+		key := uniqueConstraintKey(constraint)
+		if constraints[key] {
+			return fmt.Errorf("duplicate unique constraint (%s) in object type %q", key, o.Name)
+		}
+		constraints[key] = true
+	}
+
+	return nil
+}
+
+// uniqueConstraintKey returns a string which identifies the unique constraint for comparisons.
+func uniqueConstraintKey(constraint []string) string {
+	return strings.Join(constraint, ", ")
+}
+
+// UnmarshalJSON unmarshals and validates the object type.
+func (o *ObjectType) UnmarshalJSON(data []byte) error {
+	type objectTypeJSON ObjectType
+	var res objectTypeJSON
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+
+	if err := ObjectType(res).Validate(); err != nil {
+		return err
+	}
+
+	*o = ObjectType(res)
 	return nil
 }
 
@@ -112,6 +112,20 @@ func TestObjectType_Validate(t *testing.T) {
 			objectType:  ObjectType{Name: "object0"},
 			errContains: "has no key or value fields",
 		},
+		{
+			name: "list key field",
+			objectType: ObjectType{
+				Name: "object1",
+				KeyFields: []Field{
+					{
+						Name:        "field1",
+						Kind:        ListKind,
+						ElementKind: StringKind,
+					},
+				},
+			},
+			errContains: "key field \"field1\" cannot be a list or map",
+		},
 		{
 			name: "duplicate field",
 			objectType: ObjectType{
@@ -162,6 +176,313 @@ func TestObjectType_Validate(t *testing.T) {
 			},
 			errContains: "key field \"field1\" cannot be nullable",
 		},
+		{
+			name: "key field with default",
+			objectType: ObjectType{
+				Name: "objectDefaultKey",
+				KeyFields: []Field{
+					{
+						Name:    "field1",
+						Kind:    StringKind,
+						Default: "abc",
+					},
+				},
+			},
+			errContains: "key field \"field1\" cannot have a default value",
+		},
+		{
+			name: "valid unique constraints",
+			objectType: ObjectType{
+				Name:      "objectUnique",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				ValueFields: []Field{
+					{Name: "owner", Kind: AddressKind},
+					{Name: "name", Kind: StringKind},
+					{Name: "tags", Kind: ListKind, ElementKind: StringKind},
+				},
+				UniqueConstraints: [][]string{{"name"}, {"owner", "name"}},
+			},
+		},
+		{
+			name: "empty unique constraint",
+			objectType: ObjectType{
+				Name:      "objectUnique",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				ValueFields: []Field{
+					{Name: "owner", Kind: AddressKind},
+					{Name: "name", Kind: StringKind},
+					{Name: "tags", Kind: ListKind, ElementKind: StringKind},
+				},
+				UniqueConstraints: [][]string{{}},
+			},
+			errContains: "unique constraint in object type \"objectUnique\" cannot be empty",
+		},
+		{
+			name: "unique constraint with unknown field",
+			objectType: ObjectType{
+				Name:      "objectUnique",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				ValueFields: []Field{
+					{Name: "owner", Kind: AddressKind},
+					{Name: "name", Kind: StringKind},
+					{Name: "tags", Kind: ListKind, ElementKind: StringKind},
+				},
+				UniqueConstraints: [][]string{{"owner", "foo"}},
+			},
+			errContains: "references unknown value field \"foo\"",
+		},
+		{
+			name: "unique constraint with key field",
+			objectType: ObjectType{
+				Name:      "objectUnique",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				ValueFields: []Field{
+					{Name: "owner", Kind: AddressKind},
+					{Name: "name", Kind: StringKind},
+					{Name: "tags", Kind: ListKind, ElementKind: StringKind},
+				},
+				UniqueConstraints: [][]string{{"id"}},
+			},
+			errContains: "references unknown value field \"id\"",
+		},
+		{
+			name: "unique constraint with list field",
+			objectType: ObjectType{
+				Name:      "objectUnique",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				ValueFields: []Field{
+					{Name: "owner", Kind: AddressKind},
+					{Name: "name", Kind: StringKind},
+					{Name: "tags", Kind: ListKind, ElementKind: StringKind},
+				},
+				UniqueConstraints: [][]string{{"tags"}},
+			},
+			errContains: "cannot include list or map field \"tags\"",
+		},
+		{
+			name: "unique constraint with repeated field",
+			objectType: ObjectType{
+				Name:      "objectUnique",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				ValueFields: []Field{
+					{Name: "owner", Kind: AddressKind},
+					{Name: "name", Kind: StringKind},
+					{Name: "tags", Kind: ListKind, ElementKind: StringKind},
+				},
+				UniqueConstraints: [][]string{{"name", "name"}},
+			},
+			errContains: "references field \"name\" more than once",
+		},
+		{
+			name: "duplicate unique constraint",
+			objectType: ObjectType{
+				Name:      "objectUnique",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				ValueFields: []Field{
+					{Name: "owner", Kind: AddressKind},
+					{Name: "name", Kind: StringKind},
+					{Name: "tags", Kind: ListKind, ElementKind: StringKind},
+				},
+				UniqueConstraints: [][]string{{"owner", "name"}, {"owner", "name"}},
+			},
+			errContains: "duplicate unique constraint (owner, name)",
+		},
+		{
+			name: "valid indexes",
+			objectType: ObjectType{
+				Name:      "objectIndexed",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				ValueFields: []Field{
+					{Name: "owner", Kind: AddressKind},
+					{Name: "height", Kind: Int64Kind},
+					{Name: "tags", Kind: ListKind, ElementKind: StringKind},
+				},
+				Indexes: []IndexDescriptor{{Name: "by_owner", Fields: []IndexField{{Name: "owner"}}}, {Name: "by_owner_height", Fields: []IndexField{{Name: "owner"}, {Name: "height", Descending: true}}}},
+			},
+		},
+		{
+			name: "invalid index name",
+			objectType: ObjectType{
+				Name:      "objectIndexed",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				ValueFields: []Field{
+					{Name: "owner", Kind: AddressKind},
+					{Name: "height", Kind: Int64Kind},
+					{Name: "tags", Kind: ListKind, ElementKind: StringKind},
+				},
+				Indexes: []IndexDescriptor{{Name: "by owner", Fields: []IndexField{{Name: "owner"}}}},
+			},
+			errContains: "invalid index name \"by owner\"",
+		},
+		{
+			name: "index without fields",
+			objectType: ObjectType{
+				Name:      "objectIndexed",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				ValueFields: []Field{
+					{Name: "owner", Kind: AddressKind},
+					{Name: "height", Kind: Int64Kind},
+					{Name: "tags", Kind: ListKind, ElementKind: StringKind},
+				},
+				Indexes: []IndexDescriptor{{Name: "by_owner"}},
+			},
+			errContains: "index \"by_owner\" must have at least one field",
+		},
+		{
+			name: "index with unknown field",
+			objectType: ObjectType{
+				Name:      "objectIndexed",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				ValueFields: []Field{
+					{Name: "owner", Kind: AddressKind},
+					{Name: "height", Kind: Int64Kind},
+					{Name: "tags", Kind: ListKind, ElementKind: StringKind},
+				},
+				Indexes: []IndexDescriptor{{Name: "by_foo", Fields: []IndexField{{Name: "foo"}}}},
+			},
+			errContains: "index \"by_foo\" references unknown value field \"foo\"",
+		},
+		{
+			name: "index with list field",
+			objectType: ObjectType{
+				Name:      "objectIndexed",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				ValueFields: []Field{
+					{Name: "owner", Kind: AddressKind},
+					{Name: "height", Kind: Int64Kind},
+					{Name: "tags", Kind: ListKind, ElementKind: StringKind},
+				},
+				Indexes: []IndexDescriptor{{Name: "by_tags", Fields: []IndexField{{Name: "tags"}}}},
+			},
+			errContains: "cannot include list or map field \"tags\"",
+		},
+		{
+			name: "index with repeated field",
+			objectType: ObjectType{
+				Name:      "objectIndexed",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				ValueFields: []Field{
+					{Name: "owner", Kind: AddressKind},
+					{Name: "height", Kind: Int64Kind},
+					{Name: "tags", Kind: ListKind, ElementKind: StringKind},
+				},
+				Indexes: []IndexDescriptor{{Name: "by_owner", Fields: []IndexField{{Name: "owner"}, {Name: "owner", Descending: true}}}},
+			},
+			errContains: "references field \"owner\" more than once",
+		},
+		{
+			name: "duplicate index name",
+			objectType: ObjectType{
+				Name:      "objectIndexed",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				ValueFields: []Field{
+					{Name: "owner", Kind: AddressKind},
+					{Name: "height", Kind: Int64Kind},
+					{Name: "tags", Kind: ListKind, ElementKind: StringKind},
+				},
+				Indexes: []IndexDescriptor{{Name: "by_owner", Fields: []IndexField{{Name: "owner"}}}, {Name: "by_owner", Fields: []IndexField{{Name: "height"}}}},
+			},
+			errContains: "duplicate index name \"by_owner\"",
+		},
+		{
+			name: "valid retention policy",
+			objectType: ObjectType{
+				Name:      "objectRetention",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				Retention: &RetentionPolicy{Mode: RetentionKeepBlocks, Blocks: 100},
+			},
+		},
+		{
+			name: "invalid retention policy",
+			objectType: ObjectType{
+				Name:      "objectRetention",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				Retention: &RetentionPolicy{Mode: RetentionKeepBlocks},
+			},
+			errContains: "invalid retention policy for object type \"objectRetention\": retention mode keep_blocks requires a number of blocks",
+		},
+		{
+			name: "retention keep forever with retain deletions",
+			objectType: ObjectType{
+				Name:            "objectRetention",
+				KeyFields:       []Field{{Name: "id", Kind: Uint64Kind}},
+				RetainDeletions: true,
+				Retention:       &RetentionPolicy{Mode: RetentionKeepForever},
+			},
+		},
+		{
+			name: "pruning retention with retain deletions",
+			objectType: ObjectType{
+				Name:            "objectRetention",
+				KeyFields:       []Field{{Name: "id", Kind: Uint64Kind}},
+				RetainDeletions: true,
+				Retention:       &RetentionPolicy{Mode: RetentionKeepLatest},
+			},
+			errContains: "object type \"objectRetention\" retains deletions and cannot have retention mode keep_latest",
+		},
+		{
+			name: "valid reserved field names",
+			objectType: ObjectType{
+				Name:               "objectReserved",
+				KeyFields:          []Field{{Name: "id", Kind: Uint64Kind}},
+				ReservedFieldNames: []string{"old_field", "other_field"},
+			},
+		},
+		{
+			name: "reserved key field name",
+			objectType: ObjectType{
+				Name:               "objectReserved",
+				KeyFields:          []Field{{Name: "id", Kind: Uint64Kind}},
+				ReservedFieldNames: []string{"id"},
+			},
+			errContains: "field name \"id\" is reserved in object type \"objectReserved\"",
+		},
+		{
+			name: "reserved value field name",
+			objectType: ObjectType{
+				Name:               "objectReserved",
+				KeyFields:          []Field{{Name: "id", Kind: Uint64Kind}},
+				ValueFields:        []Field{{Name: "old_field", Kind: StringKind}},
+				ReservedFieldNames: []string{"old_field"},
+			},
+			errContains: "field name \"old_field\" is reserved in object type \"objectReserved\"",
+		},
+		{
+			name: "invalid reserved field name",
+			objectType: ObjectType{
+				Name:               "objectReserved",
+				KeyFields:          []Field{{Name: "id", Kind: Uint64Kind}},
+				ReservedFieldNames: []string{"old-field"},
+			},
+			errContains: "invalid reserved field names for object type \"objectReserved\": invalid reserved name \"old-field\"",
+		},
+		{
+			name: "duplicate reserved field name",
+			objectType: ObjectType{
+				Name:               "objectReserved",
+				KeyFields:          []Field{{Name: "id", Kind: Uint64Kind}},
+				ReservedFieldNames: []string{"old_field", "old_field"},
+			},
+			errContains: "duplicate reserved name \"old_field\"",
+		},
+		{
+			name: "object type with documentation",
+			objectType: ObjectType{
+				Name:        "objectDocs",
+				KeyFields:   []Field{{Name: "id", Kind: Uint64Kind, Description: "the object id"}},
+				Description: "an object with documentation",
+				Metadata:    map[string]string{"source": "state.proto"},
+			},
+		},
+		{
+			name: "object type with empty metadata key",
+			objectType: ObjectType{
+				Name:      "objectDocs",
+				KeyFields: []Field{{Name: "id", Kind: Uint64Kind}},
+				Metadata:  map[string]string{"": "x"},
+			},
+			errContains: "invalid metadata for object type \"objectDocs\"",
+		},
 		{
 			name: "duplicate incompatible enum",
 			objectType: ObjectType{
@@ -1,6 +1,11 @@
 package schema
 
-import "sort"
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
 
 // ObjectUpdate represents an update operation on an object in a module's state.
 type ObjectUpdate struct {
@@ -29,6 +34,138 @@ type ObjectUpdate struct {
 	Delete bool
 }
 
+// ErrFieldNotUpdated is returned by ObjectUpdate.ValueFieldValue when the Value of the update is a
+// ValueUpdates which omits the requested field, meaning that the field is unchanged.
+var ErrFieldNotUpdated = errors.New("field not updated")
+
+// KeyFieldValue returns the value of the named key field in the update's Key, resolving the single value
+// or slice encoding of keys described in ObjectUpdate.Key. The object type must be the type of the update.
+func (u ObjectUpdate) KeyFieldValue(objectType ObjectType, name string) (interface{}, error) {
+	if objectType.Name != u.TypeName {
+		return nil, fmt.Errorf("object update has type %q, got object type %q", u.TypeName, objectType.Name)
+	}
+
+	return positionalFieldValue(objectType.KeyFields, u.Key, name, "key")
+}
+
+// ValueFieldValue returns the value of the named value field in the update's Value, resolving the single
+// value, slice or ValueUpdates encoding of values described in ObjectUpdate.Value. If the Value is a
+// ValueUpdates which omits the field, ErrFieldNotUpdated is returned. The object type must be the type of
+// the update and the update must not be a delete.
+func (u ObjectUpdate) ValueFieldValue(objectType ObjectType, name string) (interface{}, error) {
+	if objectType.Name != u.TypeName {
+		return nil, fmt.Errorf("object update has type %q, got object type %q", u.TypeName, objectType.Name)
+	}
+
+	if u.Delete {
+		return nil, fmt.Errorf("object update for %q is a delete and has no value", u.TypeName)
+	}
+
+	valueUpdates, ok := u.Value.(ValueUpdates)
+	if !ok {
+		return positionalFieldValue(objectType.ValueFields, u.Value, name, "value")
+	}
+
+	found := false
+	for _, field := range objectType.ValueFields {
+		if field.Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown value field %q", name)
+	}
+
+	var res interface{}
+	updated := false
+	err := valueUpdates.Iterate(func(fieldName string, value interface{}) bool {
+		if fieldName == name {
+			res = value
+			updated = true
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !updated {
+		return nil, ErrFieldNotUpdated
+	}
+
+	return res, nil
+}
+
+// positionalFieldValue returns the value of the named field in a single value or slice of values
+// encoded as described in ObjectUpdate.Key.
+func positionalFieldValue(fields []Field, value interface{}, name, fieldType string) (interface{}, error) {
+	for i, field := range fields {
+		if field.Name != name {
+			continue
+		}
+
+		if len(fields) == 1 {
+			return value, nil
+		}
+
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected slice of values for %s fields, got %T", fieldType, value)
+		}
+
+		if len(values) != len(fields) {
+			return nil, fmt.Errorf("expected %d %s fields, got %d values", len(fields), fieldType, len(values))
+		}
+
+		return values[i], nil
+	}
+
+	return nil, fmt.Errorf("unknown %s field %q", fieldType, name)
+}
+
+// ObjectUpdateBatch groups the object updates for a single module which occurred in the same block
+// or transaction. Updates are applied in order.
+type ObjectUpdateBatch []ObjectUpdate
+
+// ObjectUpdateError describes why a single object update in an ObjectUpdateBatch is invalid.
+type ObjectUpdateError struct {
+	// Index is the position of the invalid update in the batch.
+	Index int
+
+	// TypeName is the object type name of the invalid update.
+	TypeName string
+
+	// Err is the validation error for the update.
+	Err error
+}
+
+// Error implements the error interface.
+func (e ObjectUpdateError) Error() string {
+	return fmt.Sprintf("update %d (%s): %v", e.Index, e.TypeName, e.Err)
+}
+
+// Unwrap returns the underlying validation error.
+func (e ObjectUpdateError) Unwrap() error {
+	return e.Err
+}
+
+// ObjectUpdateBatchError is returned when one or more updates in an ObjectUpdateBatch are invalid.
+// It contains an error for each invalid update in the order they appear in the batch.
+type ObjectUpdateBatchError struct {
+	Errors []ObjectUpdateError
+}
+
+// Error implements the error interface.
+func (e ObjectUpdateBatchError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d invalid object update(s): %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
 // ValueUpdates is an interface that represents the value fields of an object update. fields that
 // were not updated may be excluded from the update. Consumers should be aware that implementations
 // may not filter out fields that were unchanged. However, if a field is omitted from the update
@@ -59,3 +196,14 @@ func (m MapValueUpdates) Iterate(fn func(col string, value interface{}) bool) er
 	}
 	return nil
 }
+
+// Merge sets the values of all the fields in other on m, overwriting the values of any fields
+// which are already present. It can be used to accumulate several partial updates for the same
+// object into a single update. An error is returned if other could not be iterated over, in
+// which case m may have been partially updated.
+func (m MapValueUpdates) Merge(other ValueUpdates) error {
+	return other.Iterate(func(col string, value interface{}) bool {
+		m[col] = value
+		return true
+	})
+}
@@ -1,6 +1,10 @@
 package schema
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+)
 
 func TestMapValueUpdates_Iterate(t *testing.T) {
 	updates := MapValueUpdates(map[string]interface{}{
@@ -50,3 +54,199 @@ func TestMapValueUpdates_Iterate(t *testing.T) {
 		t.Errorf("expected a=abc, got: %v", got)
 	}
 }
+
+func TestMapValueUpdates_Merge(t *testing.T) {
+	updates := MapValueUpdates{
+		"a": "abc",
+		"b": 123,
+	}
+
+	err := updates.Merge(MapValueUpdates{
+		"b": 456,
+		"c": nil,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(updates) != 3 {
+		t.Fatalf("expected 3 updates, got: %v", updates)
+	}
+
+	if updates["a"] != "abc" {
+		t.Errorf("expected a=abc, got: %v", updates)
+	}
+
+	// later updates overwrite earlier ones
+	if updates["b"] != 456 {
+		t.Errorf("expected b=456, got: %v", updates)
+	}
+
+	// nil values are merged as well
+	if v, ok := updates["c"]; !ok || v != nil {
+		t.Errorf("expected c=nil, got: %v", updates)
+	}
+
+	var fields []string
+	err = updates.Iterate(func(col string, _ interface{}) bool {
+		fields = append(fields, col)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(fields, ",") != "a,b,c" {
+		t.Errorf("expected fields in sorted order, got: %v", fields)
+	}
+
+	err = updates.Merge(errValueUpdates{})
+	if err == nil || err.Error() != "iterate error" {
+		t.Errorf("expected iterate error, got: %v", err)
+	}
+}
+
+type errValueUpdates struct{}
+
+func (errValueUpdates) Iterate(func(col string, value interface{}) bool) error {
+	return errors.New("iterate error")
+}
+
+func TestObjectUpdate_FieldValue(t *testing.T) {
+	objectType := ObjectType{
+		Name:      "object1",
+		KeyFields: []Field{{Name: "k1", Kind: StringKind}, {Name: "k2", Kind: Int32Kind}},
+		ValueFields: []Field{
+			{Name: "v1", Kind: StringKind},
+			{Name: "v2", Kind: Int32Kind, Nullable: true},
+		},
+	}
+	singleFieldType := ObjectType{
+		Name:        "object2",
+		KeyFields:   []Field{{Name: "k", Kind: StringKind}},
+		ValueFields: []Field{{Name: "v", Kind: StringKind}},
+	}
+
+	tests := []struct {
+		name        string
+		objectType  ObjectType
+		update      ObjectUpdate
+		key         bool
+		field       string
+		expected    interface{}
+		errContains string
+	}{
+		{
+			name:       "single key field",
+			objectType: singleFieldType,
+			update:     ObjectUpdate{TypeName: "object2", Key: "abc", Value: "def"},
+			key:        true,
+			field:      "k",
+			expected:   "abc",
+		},
+		{
+			name:       "single value field",
+			objectType: singleFieldType,
+			update:     ObjectUpdate{TypeName: "object2", Key: "abc", Value: "def"},
+			field:      "v",
+			expected:   "def",
+		},
+		{
+			name:       "multiple key fields",
+			objectType: objectType,
+			update:     ObjectUpdate{TypeName: "object1", Key: []interface{}{"abc", int32(1)}},
+			key:        true,
+			field:      "k2",
+			expected:   int32(1),
+		},
+		{
+			name:       "multiple value fields",
+			objectType: objectType,
+			update:     ObjectUpdate{TypeName: "object1", Value: []interface{}{"abc", int32(1)}},
+			field:      "v1",
+			expected:   "abc",
+		},
+		{
+			name:       "value updates",
+			objectType: objectType,
+			update:     ObjectUpdate{TypeName: "object1", Value: MapValueUpdates{"v2": int32(2)}},
+			field:      "v2",
+			expected:   int32(2),
+		},
+		{
+			name:        "value updates omit field",
+			objectType:  objectType,
+			update:      ObjectUpdate{TypeName: "object1", Value: MapValueUpdates{"v2": int32(2)}},
+			field:       "v1",
+			errContains: "field not updated",
+		},
+		{
+			name:        "unknown key field",
+			objectType:  objectType,
+			update:      ObjectUpdate{TypeName: "object1", Key: []interface{}{"abc", int32(1)}},
+			key:         true,
+			field:       "v1",
+			errContains: "unknown key field \"v1\"",
+		},
+		{
+			name:        "unknown value field in value updates",
+			objectType:  objectType,
+			update:      ObjectUpdate{TypeName: "object1", Value: MapValueUpdates{}},
+			field:       "k1",
+			errContains: "unknown value field \"k1\"",
+		},
+		{
+			name:        "key is not a slice",
+			objectType:  objectType,
+			update:      ObjectUpdate{TypeName: "object1", Key: "abc"},
+			key:         true,
+			field:       "k1",
+			errContains: "expected slice of values for key fields",
+		},
+		{
+			name:        "wrong number of values",
+			objectType:  objectType,
+			update:      ObjectUpdate{TypeName: "object1", Value: []interface{}{"abc"}},
+			field:       "v1",
+			errContains: "expected 2 value fields, got 1 values",
+		},
+		{
+			name:        "delete",
+			objectType:  objectType,
+			update:      ObjectUpdate{TypeName: "object1", Key: []interface{}{"abc", int32(1)}, Delete: true},
+			field:       "v1",
+			errContains: "is a delete",
+		},
+		{
+			name:        "wrong object type",
+			objectType:  singleFieldType,
+			update:      ObjectUpdate{TypeName: "object1", Key: "abc"},
+			key:         true,
+			field:       "k",
+			errContains: "object update has type \"object1\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			var err error
+			if tt.key {
+				value, err = tt.update.KeyFieldValue(tt.objectType, tt.field)
+			} else {
+				value, err = tt.update.ValueFieldValue(tt.objectType, tt.field)
+			}
+
+			if tt.errContains == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if value != tt.expected {
+					t.Fatalf("expected %v, got %v", tt.expected, value)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error to contain %q, got: %v", tt.errContains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// OneOfType represents the definition of a union type which can be used to represent values
+// in fields of kind OneOfKind which are exactly one of several declared cases, such as protobuf
+// oneof fields or interface values which are packed in an Any.
+type OneOfType struct {
+	// Name is the name of the oneof type. It must conform to the NameFormat regular expression.
+	// Its name must be unique between all oneof, struct, enum and object types in the module.
+	// The same oneof type, however, can be used in multiple object types and fields as long as
+	// the definition is identical each time.
+	Name string `json:"name"`
+
+	// Cases is the list of cases of the oneof type. It must not be empty and case names must be
+	// unique within the oneof type. Each case is described by a field whose name is the case name
+	// which is used as the discriminator of OneOfValue's and whose kind and type definitions describe
+	// the value of the case. Cases cannot be of kind OneOfKind, nullable or have default values.
+	Cases []Field `json:"cases"`
+}
+
+// OneOfValue is the go type of values of fields of kind OneOfKind.
+type OneOfValue struct {
+	// Case is the name of the case which is set and is used as the discriminator of the value.
+	Case string
+
+	// Value is the value of the case which must be valid for the case's field definition.
+	Value interface{}
+}
+
+// TypeName implements the Type interface.
+func (o OneOfType) TypeName() string {
+	return o.Name
+}
+
+func (OneOfType) isType() {}
+
+// Validate validates the oneof definition and all nested struct and oneof definitions.
+func (o OneOfType) Validate() error {
+	return o.validate(nil)
+}
+
+// validate validates the oneof definition where parents is the list of names of the struct types
+// which contain this oneof type and is used for cycle detection.
+func (o OneOfType) validate(parents []string) error {
+	if !ValidateName(o.Name) {
+		return fmt.Errorf("invalid oneof definition name %q", o.Name)
+	}
+
+	if len(o.Cases) == 0 {
+		return fmt.Errorf("oneof definition %q cases cannot be empty", o.Name)
+	}
+
+	caseNames := map[string]bool{}
+	for _, c := range o.Cases {
+		if err := c.validate(parents); err != nil {
+			return fmt.Errorf("invalid case %q in oneof %q: %v", c.Name, o.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+
+		if c.typeKind() == OneOfKind {
+			return fmt.Errorf("case %q in oneof %q cannot be a nested oneof", c.Name, o.Name)
+		}
+
+		if c.Nullable {
+			return fmt.Errorf("case %q in oneof %q cannot be nullable", c.Name, o.Name)
+		}
+
+		if c.Default != nil {
+			return fmt.Errorf("case %q in oneof %q cannot have a default value", c.Name, o.Name)
+		}
+
+		if caseNames[c.Name] {
+			return fmt.Errorf("duplicate case name %q in oneof %q", c.Name, o.Name)
+		}
+		caseNames[c.Name] = true
+	}
+
+	return nil
+}
+
+// UnmarshalJSON unmarshals and validates the oneof definition.
+func (o *OneOfType) UnmarshalJSON(data []byte) error {
+	type oneOfTypeJSON OneOfType
+	var res oneOfTypeJSON
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+
+	if err := OneOfType(res).Validate(); err != nil {
+		return err
+	}
+
+	*o = OneOfType(res)
+	return nil
+}
+
+// LookupCase returns the field definition of the case with the given name.
+func (o OneOfType) LookupCase(name string) (Field, bool) {
+	for _, c := range o.Cases {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Field{}, false
+}
+
+// ValidateValue validates that the value is a valid oneof value, meaning that its case is one of
+// the declared cases of the oneof type and that its value is valid for that case.
+func (o OneOfType) ValidateValue(value OneOfValue) error {
+	c, ok := o.LookupCase(value.Case)
+	if !ok {
+		return fmt.Errorf("unknown case %q for oneof %q", value.Case, o.Name)
+	}
+
+	if err := c.ValidateValue(value.Value); err != nil {
+		return fmt.Errorf("invalid value for oneof %q: %v", o.Name, err) //nolint:errorlint // false positive due to using go1.12
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+var testContentOneOf = OneOfType{
+	Name: "content",
+	Cases: []Field{
+		{Name: "text", Kind: StringKind},
+		{Name: "point", Kind: StructKind, StructType: testPointStruct},
+		{Name: "recipient", Kind: AddressKind},
+	},
+}
+
+func TestOneOfType_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		oneOfType   OneOfType
+		errContains string
+	}{
+		{
+			name:        "valid oneof",
+			oneOfType:   testContentOneOf,
+			errContains: "",
+		},
+		{
+			name:        "empty name",
+			oneOfType:   OneOfType{Cases: []Field{{Name: "x", Kind: Int32Kind}}},
+			errContains: "invalid oneof definition name",
+		},
+		{
+			name:        "empty cases",
+			oneOfType:   OneOfType{Name: "content"},
+			errContains: "cases cannot be empty",
+		},
+		{
+			name: "invalid case",
+			oneOfType: OneOfType{
+				Name:  "content",
+				Cases: []Field{{Name: "x", Kind: InvalidKind}},
+			},
+			errContains: "invalid case \"x\" in oneof \"content\"",
+		},
+		{
+			name: "duplicate case",
+			oneOfType: OneOfType{
+				Name: "content",
+				Cases: []Field{
+					{Name: "x", Kind: Int32Kind},
+					{Name: "x", Kind: StringKind},
+				},
+			},
+			errContains: "duplicate case name \"x\" in oneof \"content\"",
+		},
+		{
+			name: "nullable case",
+			oneOfType: OneOfType{
+				Name:  "content",
+				Cases: []Field{{Name: "x", Kind: Int32Kind, Nullable: true}},
+			},
+			errContains: "case \"x\" in oneof \"content\" cannot be nullable",
+		},
+		{
+			name: "case with default",
+			oneOfType: OneOfType{
+				Name:  "content",
+				Cases: []Field{{Name: "x", Kind: Int32Kind, Default: int32(1)}},
+			},
+			errContains: "case \"x\" in oneof \"content\" cannot have a default value",
+		},
+		{
+			name: "nested oneof",
+			oneOfType: OneOfType{
+				Name:  "outer",
+				Cases: []Field{{Name: "inner", Kind: OneOfKind, OneOfType: testContentOneOf}},
+			},
+			errContains: "case \"inner\" in oneof \"outer\" cannot be a nested oneof",
+		},
+		{
+			name: "cycle through struct",
+			oneOfType: OneOfType{
+				Name: "content",
+				Cases: []Field{
+					{
+						Name: "wrapper",
+						Kind: StructKind,
+						StructType: StructType{
+							Name: "wrapper",
+							Fields: []Field{
+								{
+									Name: "content",
+									Kind: OneOfKind,
+									OneOfType: OneOfType{
+										Name: "content",
+										Cases: []Field{{
+											Name:       "wrapper",
+											Kind:       StructKind,
+											StructType: StructType{Name: "wrapper", Fields: []Field{{Name: "x", Kind: Int32Kind}}},
+										}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			errContains: "struct \"wrapper\" cannot contain itself",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.oneOfType.Validate()
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("expected valid oneof definition to pass validation, got: %v", err)
+				}
+			} else {
+				if err == nil {
+					t.Errorf("expected invalid oneof definition to fail validation, got nil error")
+				} else if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("expected error to contain %s, got: %v", tt.errContains, err)
+				}
+			}
+		})
+	}
+}
+
+func TestOneOfType_ValidateValue(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       OneOfValue
+		errContains string
+	}{
+		{
+			name:        "valid scalar case",
+			value:       OneOfValue{Case: "text", Value: "hello"},
+			errContains: "",
+		},
+		{
+			name:        "valid struct case",
+			value:       OneOfValue{Case: "point", Value: []interface{}{int32(1), int32(2)}},
+			errContains: "",
+		},
+		{
+			name:        "unknown case",
+			value:       OneOfValue{Case: "image", Value: "hello"},
+			errContains: "unknown case \"image\" for oneof \"content\"",
+		},
+		{
+			name:        "empty case",
+			value:       OneOfValue{Value: "hello"},
+			errContains: "unknown case \"\" for oneof \"content\"",
+		},
+		{
+			name:        "value of another case",
+			value:       OneOfValue{Case: "text", Value: []interface{}{int32(1), int32(2)}},
+			errContains: "invalid value for field \"text\"",
+		},
+		{
+			name:        "null value",
+			value:       OneOfValue{Case: "recipient"},
+			errContains: "field \"recipient\" cannot be null",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testContentOneOf.ValidateValue(tt.value)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("expected valid oneof value to pass validation, got: %v", err)
+				}
+			} else {
+				if err == nil {
+					t.Errorf("expected invalid oneof value to fail validation, got nil error")
+				} else if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("expected error to contain %s, got: %v", tt.errContains, err)
+				}
+			}
+		})
+	}
+}
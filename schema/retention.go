@@ -0,0 +1,104 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RetentionMode specifies how much history of an object type indexers need to keep.
+type RetentionMode int
+
+const (
+	// RetentionKeepForever indicates that indexers should keep the full history of the object type.
+	// This is the default if no retention policy is specified.
+	RetentionKeepForever RetentionMode = iota
+
+	// RetentionKeepLatest indicates that indexers only need to keep the latest version of each object
+	// and may prune all historical versions.
+	RetentionKeepLatest
+
+	// RetentionKeepBlocks indicates that indexers only need to keep historical versions of objects
+	// from the last RetentionPolicy.Blocks blocks and may prune older versions. The latest version
+	// of each object is always kept.
+	RetentionKeepBlocks
+)
+
+// RetentionPolicy is a hint to indexers about how much history of an object type they need to keep
+// when pruning historical data. Indexers which do not store history can ignore it.
+type RetentionPolicy struct {
+	// Mode is the retention mode.
+	Mode RetentionMode `json:"mode"`
+
+	// Blocks is the number of blocks of history to keep. It must be greater than zero when Mode
+	// is RetentionKeepBlocks and zero otherwise.
+	Blocks uint64 `json:"blocks,omitempty"`
+}
+
+// Validate validates the retention policy.
+func (r RetentionPolicy) Validate() error {
+	if err := r.Mode.Validate(); err != nil {
+		return err
+	}
+
+	if r.Mode == RetentionKeepBlocks && r.Blocks == 0 {
+		return fmt.Errorf("retention mode %s requires a number of blocks", r.Mode)
+	}
+
+	if r.Mode != RetentionKeepBlocks && r.Blocks != 0 {
+		return fmt.Errorf("retention mode %s cannot specify a number of blocks", r.Mode)
+	}
+
+	return nil
+}
+
+// Prunes returns true if the retention policy allows indexers to prune any historical data.
+func (r RetentionPolicy) Prunes() bool {
+	return r.Mode != RetentionKeepForever
+}
+
+// Validate returns an error if the retention mode is invalid.
+func (m RetentionMode) Validate() error {
+	if m < RetentionKeepForever || m > RetentionKeepBlocks {
+		return fmt.Errorf("invalid retention mode: %d", m)
+	}
+	return nil
+}
+
+// String returns a string representation of the retention mode.
+func (m RetentionMode) String() string {
+	switch m {
+	case RetentionKeepForever:
+		return "keep_forever"
+	case RetentionKeepLatest:
+		return "keep_latest"
+	case RetentionKeepBlocks:
+		return "keep_blocks"
+	default:
+		return fmt.Sprintf("invalid(%d)", m)
+	}
+}
+
+// MarshalJSON marshals the retention mode as a JSON string using the name returned by RetentionMode.String.
+func (m RetentionMode) MarshalJSON() ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON unmarshals the retention mode from a JSON string using the names returned by RetentionMode.String.
+func (m *RetentionMode) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	for mode := RetentionKeepForever; mode <= RetentionKeepBlocks; mode++ {
+		if mode.String() == name {
+			*m = mode
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown retention mode %q", name)
+}
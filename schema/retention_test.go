@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRetentionPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      RetentionPolicy
+		errContains string
+	}{
+		{
+			name:   "keep forever",
+			policy: RetentionPolicy{Mode: RetentionKeepForever},
+		},
+		{
+			name:   "keep latest",
+			policy: RetentionPolicy{Mode: RetentionKeepLatest},
+		},
+		{
+			name:   "keep blocks",
+			policy: RetentionPolicy{Mode: RetentionKeepBlocks, Blocks: 10},
+		},
+		{
+			name:        "keep blocks without blocks",
+			policy:      RetentionPolicy{Mode: RetentionKeepBlocks},
+			errContains: "retention mode keep_blocks requires a number of blocks",
+		},
+		{
+			name:        "keep latest with blocks",
+			policy:      RetentionPolicy{Mode: RetentionKeepLatest, Blocks: 10},
+			errContains: "retention mode keep_latest cannot specify a number of blocks",
+		},
+		{
+			name:        "invalid mode",
+			policy:      RetentionPolicy{Mode: RetentionMode(10)},
+			errContains: "invalid retention mode: 10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.errContains == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error to contain %q, got: %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+func TestRetentionPolicy_JSON(t *testing.T) {
+	policy := RetentionPolicy{Mode: RetentionKeepBlocks, Blocks: 100}
+	bz, err := json.Marshal(policy)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{"mode":"keep_blocks","blocks":100}`
+	if string(bz) != expected {
+		t.Fatalf("expected %s, got %s", expected, bz)
+	}
+
+	var decoded RetentionPolicy
+	if err := json.Unmarshal(bz, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded != policy {
+		t.Fatalf("expected %v, got %v", policy, decoded)
+	}
+
+	err = json.Unmarshal([]byte(`{"mode":"keep_some"}`), &decoded)
+	if err == nil || !strings.Contains(err.Error(), "unknown retention mode \"keep_some\"") {
+		t.Fatalf("expected unknown retention mode error, got: %v", err)
+	}
+}
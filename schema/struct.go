@@ -0,0 +1,102 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// StructType represents the definition of a struct type which can be used to represent
+// nested data in fields of kind StructKind.
+type StructType struct {
+	// Name is the name of the struct type. It must conform to the NameFormat regular expression.
+	// Its name must be unique between all struct, enum and object types in the module.
+	// The same struct, however, can be used in multiple object types and fields as long as the
+	// definition is identical each time.
+	Name string `json:"name"`
+
+	// Fields is the list of fields in the struct. It must not be empty and field names must be
+	// unique within the struct. Fields may themselves be of kind StructKind, but a struct type
+	// cannot directly or indirectly contain itself.
+	Fields []Field `json:"fields"`
+}
+
+// TypeName implements the Type interface.
+func (s StructType) TypeName() string {
+	return s.Name
+}
+
+func (StructType) isType() {}
+
+// Validate validates the struct definition and all nested struct definitions.
+func (s StructType) Validate() error {
+	return s.validate(nil)
+}
+
+// validate validates the struct definition where parents is the list of names of the struct types
+// which contain this struct type and is used for cycle detection.
+func (s StructType) validate(parents []string) error {
+	if !ValidateName(s.Name) {
+		return fmt.Errorf("invalid struct definition name %q", s.Name)
+	}
+
+	for _, parent := range parents {
+		if parent == s.Name {
+			return fmt.Errorf("struct %q cannot contain itself: %s -> %s", s.Name, strings.Join(parents, " -> "), s.Name)
+		}
+	}
+
+	if len(s.Fields) == 0 {
+		return fmt.Errorf("struct definition %q fields cannot be empty", s.Name)
+	}
+
+	path := make([]string, len(parents), len(parents)+1)
+	copy(path, parents)
+	path = append(path, s.Name)
+
+	fieldNames := map[string]bool{}
+	for _, field := range s.Fields {
+		if err := field.validate(path); err != nil {
+			return fmt.Errorf("invalid field %q in struct %q: %v", field.Name, s.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+
+		if fieldNames[field.Name] {
+			return fmt.Errorf("duplicate field name %q in struct %q", field.Name, s.Name)
+		}
+		fieldNames[field.Name] = true
+	}
+
+	return nil
+}
+
+// UnmarshalJSON unmarshals and validates the struct definition.
+func (s *StructType) UnmarshalJSON(data []byte) error {
+	type structTypeJSON StructType
+	var res structTypeJSON
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+
+	if err := StructType(res).Validate(); err != nil {
+		return err
+	}
+
+	*s = StructType(res)
+	return nil
+}
+
+// ValidateValue validates that the value is a valid struct value, meaning that it has one value
+// for each field in the struct and that each value is valid for its field.
+func (s StructType) ValidateValue(value []interface{}) error {
+	if len(value) != len(s.Fields) {
+		return fmt.Errorf("expected %d values for struct %q, got %d", len(s.Fields), s.Name, len(value))
+	}
+
+	for i, field := range s.Fields {
+		if err := field.ValidateValue(value[i]); err != nil {
+			return fmt.Errorf("invalid value for struct %q: %v", s.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+	}
+
+	return nil
+}
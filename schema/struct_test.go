@@ -0,0 +1,159 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+var testPointStruct = StructType{
+	Name: "point",
+	Fields: []Field{
+		{Name: "x", Kind: Int32Kind},
+		{Name: "y", Kind: Int32Kind},
+	},
+}
+
+func TestStructType_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		structType  StructType
+		errContains string
+	}{
+		{
+			name:        "valid struct",
+			structType:  testPointStruct,
+			errContains: "",
+		},
+		{
+			name: "valid nested struct",
+			structType: StructType{
+				Name: "line",
+				Fields: []Field{
+					{Name: "start", Kind: StructKind, StructType: testPointStruct},
+					{Name: "end", Kind: StructKind, StructType: testPointStruct},
+				},
+			},
+			errContains: "",
+		},
+		{
+			name: "empty name",
+			structType: StructType{
+				Fields: []Field{{Name: "x", Kind: Int32Kind}},
+			},
+			errContains: "invalid struct definition name",
+		},
+		{
+			name:        "empty fields",
+			structType:  StructType{Name: "point"},
+			errContains: "fields cannot be empty",
+		},
+		{
+			name: "invalid field",
+			structType: StructType{
+				Name:   "point",
+				Fields: []Field{{Name: "x", Kind: InvalidKind}},
+			},
+			errContains: "invalid field \"x\" in struct \"point\"",
+		},
+		{
+			name: "duplicate field",
+			structType: StructType{
+				Name: "point",
+				Fields: []Field{
+					{Name: "x", Kind: Int32Kind},
+					{Name: "x", Kind: Int64Kind},
+				},
+			},
+			errContains: "duplicate field name \"x\" in struct \"point\"",
+		},
+		{
+			name: "cycle",
+			structType: StructType{
+				Name: "a",
+				Fields: []Field{
+					{
+						Name: "b",
+						Kind: StructKind,
+						StructType: StructType{
+							Name: "b",
+							Fields: []Field{
+								{
+									Name: "a",
+									Kind: StructKind,
+									StructType: StructType{
+										Name:   "a",
+										Fields: []Field{{Name: "x", Kind: Int32Kind}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			errContains: "struct \"a\" cannot contain itself: a -> b -> a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.structType.Validate()
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("expected valid struct definition to pass validation, got: %v", err)
+				}
+			} else {
+				if err == nil {
+					t.Errorf("expected invalid struct definition to fail validation, got nil error")
+				} else if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("expected error to contain %s, got: %v", tt.errContains, err)
+				}
+			}
+		})
+	}
+}
+
+func TestStructType_ValidateValue(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       []interface{}
+		errContains string
+	}{
+		{
+			name:        "valid value",
+			value:       []interface{}{int32(1), int32(2)},
+			errContains: "",
+		},
+		{
+			name:        "too few values",
+			value:       []interface{}{int32(1)},
+			errContains: "expected 2 values for struct \"point\", got 1",
+		},
+		{
+			name:        "invalid value type",
+			value:       []interface{}{int32(1), "abc"},
+			errContains: "invalid value for field \"y\"",
+		},
+		{
+			name:        "null value",
+			value:       []interface{}{int32(1), nil},
+			errContains: "field \"y\" cannot be null",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testPointStruct.ValidateValue(tt.value)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("expected valid struct value to pass validation, got: %v", err)
+				}
+			} else {
+				if err == nil {
+					t.Errorf("expected invalid struct value to fail validation, got nil error")
+				} else if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("expected error to contain %s, got: %v", tt.errContains, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package appdatasim
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"cosmossdk.io/schema/appdata"
+)
+
+// BenchmarkResult reports the throughput and per-block latency of a listener processing the blocks of a
+// workload.
+type BenchmarkResult struct {
+	// Blocks, Updates and Events are the numbers of blocks, object updates and events processed.
+	Blocks, Updates, Events int
+
+	// Duration is the total time spent delivering blocks, excluding module initialization.
+	Duration time.Duration
+
+	// P50, P99 and Max are percentiles of the time spent delivering a single block.
+	P50, P99, Max time.Duration
+}
+
+// UpdatesPerSecond returns the number of object updates processed per second.
+func (r BenchmarkResult) UpdatesPerSecond() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Updates) / r.Duration.Seconds()
+}
+
+// BlocksPerSecond returns the number of blocks processed per second.
+func (r BenchmarkResult) BlocksPerSecond() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Blocks) / r.Duration.Seconds()
+}
+
+// String returns a human-readable summary of the result.
+func (r BenchmarkResult) String() string {
+	return fmt.Sprintf("%d blocks, %d updates, %d events in %s (%.0f updates/s, p50 %s, p99 %s, max %s)",
+		r.Blocks, r.Updates, r.Events, r.Duration, r.UpdatesPerSecond(), r.P50, r.P99, r.Max)
+}
+
+// Run initializes the modules of the workload in the listener and delivers the blocks to it, measuring the
+// time spent in the listener for each block. Blocks must have been generated by Blocks.
+func (w Workload) Run(listener appdata.Listener, blocks []BlockData) (BenchmarkResult, error) {
+	for _, name := range sortedKeys(w.Options.AppSchema) {
+		err := listener.SendPacket(appdata.ModuleInitializationData{ModuleName: name, Schema: w.Options.AppSchema[name]})
+		if err != nil {
+			return BenchmarkResult{}, err
+		}
+	}
+
+	res := BenchmarkResult{Blocks: len(blocks)}
+	latencies := make([]time.Duration, len(blocks))
+	for i, block := range blocks {
+		for _, packet := range block {
+			switch data := packet.(type) {
+			case appdata.ObjectUpdateData:
+				res.Updates += len(data.Updates)
+			case appdata.EventData:
+				res.Events++
+			}
+		}
+
+		start := time.Now()
+		for _, packet := range block {
+			if err := listener.SendPacket(packet); err != nil {
+				return BenchmarkResult{}, fmt.Errorf("block %d: %w", i+1, err)
+			}
+		}
+		latencies[i] = time.Since(start)
+		res.Duration += latencies[i]
+	}
+
+	if len(latencies) != 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		res.P50 = latencies[len(latencies)*50/100]
+		res.P99 = latencies[len(latencies)*99/100]
+		res.Max = latencies[len(latencies)-1]
+	}
+	return res, nil
+}
+
+// BenchmarkWorkload benchmarks the listeners returned by newListener with numBlocks blocks of the workload.
+// The blocks are generated once before the timer starts and every iteration delivers them to a new listener.
+// Besides the default metrics it reports updates/s, blocks/s and the p50 and p99 latencies per block.
+func BenchmarkWorkload(b *testing.B, w Workload, numBlocks int, newListener func() appdata.Listener) {
+	b.Helper()
+	blocks, err := w.Blocks(numBlocks, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var total BenchmarkResult
+	var p50, p99 time.Duration
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		listener := newListener()
+		b.StartTimer()
+
+		res, err := w.Run(listener, blocks)
+		if err != nil {
+			b.Fatal(err)
+		}
+		total.Blocks += res.Blocks
+		total.Updates += res.Updates
+		total.Duration += res.Duration
+		p50 += res.P50
+		p99 += res.P99
+	}
+
+	b.ReportMetric(total.UpdatesPerSecond(), "updates/s")
+	b.ReportMetric(total.BlocksPerSecond(), "blocks/s")
+	b.ReportMetric(float64(p50.Microseconds())/float64(b.N), "p50-us/block")
+	b.ReportMetric(float64(p99.Microseconds())/float64(b.N), "p99-us/block")
+}
@@ -0,0 +1,110 @@
+package appdatasim
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/view"
+)
+
+// DiffAppData compares the app data of a listener with the expected app data, such as the state of a
+// Simulator, and returns a description of the differences or an empty string if they are equal.
+func DiffAppData(expected, actual view.AppData) string {
+	res := &strings.Builder{}
+
+	expectedBlock, err := expected.BlockNum()
+	if err != nil {
+		return fmt.Sprintf("failed to get expected block number: %v\n", err)
+	}
+	actualBlock, err := actual.BlockNum()
+	if err != nil {
+		return fmt.Sprintf("failed to get actual block number: %v\n", err)
+	}
+	if expectedBlock != actualBlock {
+		fmt.Fprintf(res, "block number: expected %d, got %d\n", expectedBlock, actualBlock)
+	}
+
+	expected.AppState().Modules(func(expectedMod view.ModuleState, err error) bool {
+		if err != nil {
+			fmt.Fprintf(res, "failed to get expected module: %v\n", err)
+			return true
+		}
+		actualMod, err := actual.AppState().GetModule(expectedMod.ModuleName())
+		if err != nil {
+			fmt.Fprintf(res, "module %s: %v\n", expectedMod.ModuleName(), err)
+			return true
+		}
+		if actualMod == nil {
+			fmt.Fprintf(res, "module %s: not found\n", expectedMod.ModuleName())
+			return true
+		}
+		res.WriteString(DiffModuleStates(expectedMod, actualMod))
+		return true
+	})
+	return res.String()
+}
+
+// DiffModuleStates compares the state of a module of a listener with the expected state and returns a
+// description of the differences or an empty string if they are equal.
+func DiffModuleStates(expected, actual view.ModuleState) string {
+	res := &strings.Builder{}
+	expected.ObjectCollections(func(expectedColl view.ObjectCollection, err error) bool {
+		if err != nil {
+			fmt.Fprintf(res, "module %s: failed to get expected collection: %v\n", expected.ModuleName(), err)
+			return true
+		}
+		typeName := expectedColl.ObjectType().Name
+		actualColl, err := actual.GetObjectCollection(typeName)
+		if err != nil {
+			fmt.Fprintf(res, "object type %s/%s: %v\n", expected.ModuleName(), typeName, err)
+			return true
+		}
+		if actualColl == nil {
+			fmt.Fprintf(res, "object type %s/%s: not found\n", expected.ModuleName(), typeName)
+			return true
+		}
+		diffCollections(res, expected.ModuleName(), expectedColl, actualColl)
+		return true
+	})
+	return res.String()
+}
+
+func diffCollections(res *strings.Builder, moduleName string, expected, actual view.ObjectCollection) {
+	objectType := expected.ObjectType()
+	prefix := fmt.Sprintf("object type %s/%s", moduleName, objectType.Name)
+
+	expectedLen, err := expected.Len()
+	if err != nil {
+		fmt.Fprintf(res, "%s: failed to get expected length: %v\n", prefix, err)
+		return
+	}
+	actualLen, err := actual.Len()
+	if err != nil {
+		fmt.Fprintf(res, "%s: failed to get actual length: %v\n", prefix, err)
+		return
+	}
+	if expectedLen != actualLen {
+		fmt.Fprintf(res, "%s: expected %d objects, got %d\n", prefix, expectedLen, actualLen)
+	}
+
+	expected.AllState(func(expectedObj schema.ObjectUpdate, err error) bool {
+		if err != nil {
+			fmt.Fprintf(res, "%s: failed to get expected object: %v\n", prefix, err)
+			return true
+		}
+		actualObj, found, err := actual.GetObject(expectedObj.Key)
+		switch {
+		case err != nil:
+			fmt.Fprintf(res, "%s: object %v: %v\n", prefix, expectedObj.Key, err)
+		case !found:
+			fmt.Fprintf(res, "%s: object %v: not found\n", prefix, expectedObj.Key)
+		case expectedObj.Delete != actualObj.Delete:
+			fmt.Fprintf(res, "%s: object %v: expected deleted %t, got %t\n", prefix, expectedObj.Key, expectedObj.Delete, actualObj.Delete)
+		case !reflect.DeepEqual(expectedObj.Value, actualObj.Value):
+			fmt.Fprintf(res, "%s: object %v: expected value %v, got %v\n", prefix, expectedObj.Key, expectedObj.Value, actualObj.Value)
+		}
+		return true
+	})
+}
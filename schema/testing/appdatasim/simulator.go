@@ -0,0 +1,391 @@
+// Package appdatasim simulates the app data which a node sends to listeners, such as indexers, so that they
+// can be tested against generated module schemas and blocks of correlated object updates. The simulator keeps
+// the expected app state, which can be compared with the state of a listener with DiffAppData, and can inject
+// failures to test how listeners recover from errors and crashes.
+package appdatasim
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"pgregory.net/rapid"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	schematesting "cosmossdk.io/schema/testing"
+	"cosmossdk.io/schema/view"
+)
+
+// Options are the options for creating a simulator.
+type Options struct {
+	// AppSchema are the schemas of the modules of the simulated app by module name.
+	AppSchema map[string]schema.ModuleSchema
+
+	// Listener is the listener which receives the simulated app data.
+	Listener appdata.Listener
+
+	// StateGen are the options for generating object updates.
+	StateGen schematesting.Options
+
+	// MinUpdatesPerBlock and MaxUpdatesPerBlock bound the number of object updates in a block. The maximum
+	// defaults to 20.
+	MinUpdatesPerBlock, MaxUpdatesPerBlock int
+
+	// MaxEventsPerBlock is the maximum number of events in a block. Events are only generated for the event
+	// types of the module schemas.
+	MaxEventsPerBlock int
+
+	// ModuleWeights are the relative probabilities that an update or event belongs to each module. If it is
+	// nil, all modules have the same weight, otherwise modules without a weight don't get any updates or
+	// events.
+	ModuleWeights map[string]int
+
+	// Failures configures the injection of failures, which requires Restart.
+	Failures FailureOptions
+
+	// Restart simulates a restart of the listener's process after a failure. It must return a new instance
+	// of the listener which only has the data it persisted before the failure and reports its last committed
+	// height. The simulator then resumes the listener with appdata.Resume, catching it up with the blocks it
+	// has missed.
+	Restart func() (appdata.ResumableListener, error)
+}
+
+// FailureOptions configure the failures which the simulator injects while processing blocks. Failures are
+// drawn from a random source seeded with Seed, so that the same failures are injected for the same blocks.
+type FailureOptions struct {
+	// ListenerErrorPercent is the probability in percent that the delivery of a block fails with
+	// ErrInjectedFailure before one of its packets reaches the listener. The listener is then restarted and
+	// the block is delivered again.
+	ListenerErrorPercent int
+
+	// CrashPercent is the probability in percent that the process crashes after the listener has committed a
+	// block but before the commit was acknowledged. The listener is then restarted and the block is delivered
+	// again, which the listener must not apply twice.
+	CrashPercent int
+
+	// Seed is the seed of the random source of failures.
+	Seed int64
+}
+
+// FailureStats counts the failures which the simulator injected.
+type FailureStats struct {
+	// ListenerErrors is the number of injected listener errors.
+	ListenerErrors int
+
+	// Crashes is the number of injected crashes between commit and acknowledgment.
+	Crashes int
+
+	// Restarts is the number of restarts of the listener.
+	Restarts int
+}
+
+// ErrInjectedFailure is the error which the simulator injects in the delivery of blocks.
+var ErrInjectedFailure = errors.New("injected failure")
+
+// BlockData are the packets of a block, which starts with appdata.StartBlockData and ends with
+// appdata.CommitData.
+type BlockData = []appdata.Packet
+
+// Simulator generates blocks of object updates for the modules of an app, delivers them to a listener and
+// keeps the expected app state. It implements view.AppData.
+type Simulator struct {
+	options  Options
+	listener appdata.Listener
+	state    *appState
+	blockNum uint64
+
+	// blocks are the blocks which were committed, which are used to catch up restarted listeners
+	blocks []BlockData
+
+	rand  *rand.Rand
+	stats FailureStats
+}
+
+var _ view.AppData = &Simulator{}
+
+// NewSimulator creates a simulator and initializes the modules of the listener.
+func NewSimulator(options Options) (*Simulator, error) {
+	if options.MaxUpdatesPerBlock == 0 {
+		options.MaxUpdatesPerBlock = 20
+	}
+	if options.MaxUpdatesPerBlock < options.MinUpdatesPerBlock {
+		options.MaxUpdatesPerBlock = options.MinUpdatesPerBlock
+	}
+	if (options.Failures.ListenerErrorPercent > 0 || options.Failures.CrashPercent > 0) && options.Restart == nil {
+		return nil, errors.New("failure injection requires a Restart function")
+	}
+
+	s := &Simulator{
+		options:  options,
+		listener: options.Listener,
+		state:    newAppState(),
+		rand:     rand.New(rand.NewSource(options.Failures.Seed)), //nolint:gosec // failures don't need a secure random source
+	}
+	for _, name := range sortedKeys(options.AppSchema) {
+		s.state.initializeModule(name, options.AppSchema[name])
+	}
+	if err := s.initializeModules(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Simulator) initializeModules() error {
+	for _, name := range sortedKeys(s.options.AppSchema) {
+		err := s.listener.SendPacket(appdata.ModuleInitializationData{ModuleName: name, Schema: s.options.AppSchema[name]})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BlockNum implements view.AppData and returns the height of the last committed block.
+func (s *Simulator) BlockNum() (uint64, error) {
+	return s.blockNum, nil
+}
+
+// AppState implements view.AppData and returns the expected app state after the last committed block.
+func (s *Simulator) AppState() view.AppState {
+	return s.state
+}
+
+// FailureStats returns the failures which have been injected so far.
+func (s *Simulator) FailureStats() FailureStats {
+	return s.stats
+}
+
+// BlockDataGen generates the next block, which contains object updates of the current app state: objects are
+// inserted, updated with all or some of their value fields and deleted. It is followed by events of the event
+// types of the modules. It must be drawn again after each block was processed.
+func (s *Simulator) BlockDataGen() *rapid.Generator[BlockData] {
+	opts := s.options.StateGen
+	updateModules := s.weightedModules(func(schema.ModuleSchema) bool { return true })
+	eventModules := s.weightedModules(func(modSchema schema.ModuleSchema) bool {
+		hasEvents := false
+		modSchema.EventTypes(func(schema.EventType) bool {
+			hasEvents = true
+			return false
+		})
+		return hasEvents
+	})
+	return rapid.Custom(func(t *rapid.T) BlockData {
+		block := BlockData{appdata.StartBlockData{Height: s.blockNum + 1}}
+
+		// updates are generated against a copy of the state so that they stay consistent within the block
+		state := s.state.clone()
+		numUpdates := rapid.IntRange(s.options.MinUpdatesPerBlock, s.options.MaxUpdatesPerBlock).Draw(t, "numUpdates")
+		for i := 0; i < numUpdates && len(updateModules) != 0; i++ {
+			mod := state.modules[rapid.SampledFrom(updateModules).Draw(t, "module")]
+			typeNames := sortedKeys(mod.collections)
+			if len(typeNames) == 0 {
+				continue
+			}
+			coll := mod.collections[rapid.SampledFrom(typeNames).Draw(t, "objectType")]
+			update := drawObjectUpdate(t, coll, opts)
+			if err := coll.apply(update); err != nil {
+				t.Fatalf("generated invalid update: %v", err)
+			}
+			block = append(block, appdata.ObjectUpdateData{ModuleName: mod.name, Updates: []schema.ObjectUpdate{update}})
+		}
+
+		if s.options.MaxEventsPerBlock != 0 && len(eventModules) != 0 {
+			numEvents := rapid.IntRange(0, s.options.MaxEventsPerBlock).Draw(t, "numEvents")
+			for i := 0; i < numEvents; i++ {
+				moduleName := rapid.SampledFrom(eventModules).Draw(t, "eventModule")
+				block = append(block, drawEvent(t, moduleName, s.options.AppSchema[moduleName], uint32(i), opts))
+			}
+		}
+		return append(block, appdata.CommitData{})
+	})
+}
+
+// weightedModules returns the names of the modules accepted by include, each repeated by its weight, in
+// alphabetical order so that sampling from them is seed-stable.
+func (s *Simulator) weightedModules(include func(schema.ModuleSchema) bool) []string {
+	var res []string
+	for _, name := range sortedKeys(s.options.AppSchema) {
+		if !include(s.options.AppSchema[name]) {
+			continue
+		}
+		weight := 1
+		if s.options.ModuleWeights != nil {
+			weight = s.options.ModuleWeights[name]
+		}
+		for i := 0; i < weight; i++ {
+			res = append(res, name)
+		}
+	}
+	return res
+}
+
+// drawEvent draws an end block event of one of the event types of the module with typed attributes.
+func drawEvent(t *rapid.T, moduleName string, modSchema schema.ModuleSchema, index uint32, opts schematesting.Options) appdata.EventData {
+	var eventTypes []schema.EventType
+	modSchema.EventTypes(func(eventType schema.EventType) bool {
+		eventTypes = append(eventTypes, eventType)
+		return true
+	})
+	eventType := rapid.SampledFrom(eventTypes).Draw(t, "eventType")
+
+	attrs := make([]appdata.EventAttribute, len(eventType.Fields))
+	for i, field := range eventType.Fields {
+		attrs[i] = appdata.EventAttribute{Key: field.Name, Value: schematesting.FieldValueGen(field, opts).Draw(t, field.Name)}
+	}
+	return appdata.EventData{
+		TxIndex:    -2,
+		EventIndex: index,
+		Type:       eventType.Name,
+		ModuleName: moduleName,
+		Attributes: func() ([]appdata.EventAttribute, error) { return attrs, nil },
+	}
+}
+
+// drawObjectUpdate draws an update of an object of the collection, which inserts a new object or updates or
+// deletes an existing one.
+func drawObjectUpdate(t *rapid.T, coll *objectCollection, opts schematesting.Options) schema.ObjectUpdate {
+	objectType := coll.objectType
+	update := schema.ObjectUpdate{TypeName: objectType.Name}
+	keys := coll.keys()
+	if len(objectType.KeyFields) == 0 {
+		if len(keys) == 0 || len(objectType.ValueFields) == 0 {
+			update.Value = schematesting.ValueGen(objectType, opts).Draw(t, "value")
+		} else {
+			update.Value = schematesting.UpdateValueGen(objectType, opts).Draw(t, "value")
+		}
+		return update
+	}
+
+	op := 0
+	if len(keys) != 0 {
+		op = rapid.IntRange(0, 5).Draw(t, "op")
+	}
+	switch {
+	case op < 3:
+		update.Key = schematesting.KeyGen(objectType, opts).Draw(t, "key")
+		update.Value = schematesting.ValueGen(objectType, opts).Draw(t, "value")
+	case op < 5 && len(objectType.ValueFields) != 0:
+		update.Key = rapid.SampledFrom(keys).Draw(t, "existingKey")
+		update.Value = schematesting.UpdateValueGen(objectType, opts).Draw(t, "value")
+	default:
+		update.Key = rapid.SampledFrom(keys).Draw(t, "existingKey")
+		update.Delete = true
+	}
+	return update
+}
+
+// ProcessBlockData delivers a block to the listener and applies it to the expected app state. Injected
+// failures are recovered from by restarting the listener and delivering the block again, so an error is only
+// returned if the listener fails by itself or can't be restarted.
+func (s *Simulator) ProcessBlockData(block BlockData) error {
+	failAt := -1
+	if s.drawPercent(s.options.Failures.ListenerErrorPercent) {
+		failAt = s.rand.Intn(len(block))
+	}
+
+	for {
+		err := s.deliver(block, failAt)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, ErrInjectedFailure) {
+			return err
+		}
+
+		s.stats.ListenerErrors++
+		failAt = -1
+		if err := s.restart(); err != nil {
+			return err
+		}
+	}
+
+	for _, packet := range block {
+		if data, ok := packet.(appdata.ObjectUpdateData); ok {
+			if err := s.state.apply(data.ModuleName, data.Updates); err != nil {
+				return err
+			}
+		}
+	}
+	s.blockNum = blockHeight(block)
+	s.blocks = append(s.blocks, block)
+
+	if !s.drawPercent(s.options.Failures.CrashPercent) {
+		return nil
+	}
+
+	// the block is committed by the node and the listener, but the listener's acknowledgment was lost in the
+	// crash, so the block is delivered again after the restart
+	s.stats.Crashes++
+	if err := s.restart(); err != nil {
+		return err
+	}
+	return s.deliver(block, -1)
+}
+
+// deliver sends the packets of a block to the listener, failing with ErrInjectedFailure instead of sending
+// the packet at index failAt.
+func (s *Simulator) deliver(block BlockData, failAt int) error {
+	for i, packet := range block {
+		if i == failAt {
+			return ErrInjectedFailure
+		}
+		if err := s.listener.SendPacket(packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restart restarts the listener, initializes its modules and resumes it at the last committed height.
+func (s *Simulator) restart() error {
+	s.stats.Restarts++
+	listener, err := s.options.Restart()
+	if err != nil {
+		return fmt.Errorf("failed to restart listener: %w", err)
+	}
+
+	// modules are initialized when the process starts, before the listener is caught up
+	s.listener = listener.Listener
+	if err := s.initializeModules(); err != nil {
+		return err
+	}
+
+	listeners, err := appdata.Resume(appdata.ResumeOptions{Height: int64(s.blockNum), Source: s}, listener)
+	if err != nil {
+		return err
+	}
+	s.listener = listeners[0]
+	return nil
+}
+
+// CatchUp implements appdata.CatchUpSource by delivering the committed blocks.
+func (s *Simulator) CatchUp(fromHeight, toHeight int64, listener appdata.Listener) error {
+	for _, block := range s.blocks {
+		height := int64(blockHeight(block))
+		if height <= fromHeight || height > toHeight {
+			continue
+		}
+		for _, packet := range block {
+			if start, ok := packet.(appdata.StartBlockData); ok {
+				start.Backfill = true
+				packet = start
+			}
+			if err := listener.SendPacket(packet); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Simulator) drawPercent(p int) bool {
+	return p > 0 && s.rand.Intn(100) < p
+}
+
+func blockHeight(block BlockData) uint64 {
+	if start, ok := block[0].(appdata.StartBlockData); ok {
+		return start.Height
+	}
+	return 0
+}
@@ -0,0 +1,161 @@
+package appdatasim
+
+import (
+	"testing"
+
+	"pgregory.net/rapid"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	schematesting "cosmossdk.io/schema/testing"
+	"cosmossdk.io/schema/view"
+)
+
+// testIndexer is an indexer which persists the updates of a block atomically when it is committed and loses
+// the updates of uncommitted blocks when it is restarted.
+type testIndexer struct {
+	// persisted state which survives restarts
+	state    *appState
+	height   uint64
+	restarts int
+
+	// in-memory state of the current process
+	pendingHeight  uint64
+	pendingUpdates []appdata.ObjectUpdateData
+}
+
+func (i *testIndexer) BlockNum() (uint64, error) { return i.height, nil }
+
+func (i *testIndexer) AppState() view.AppState { return i.state }
+
+func (i *testIndexer) LastCommittedHeight() (int64, error) { return int64(i.height), nil }
+
+func (i *testIndexer) listener() appdata.Listener {
+	return appdata.Listener{
+		InitializeModuleData: func(data appdata.ModuleInitializationData) error {
+			i.state.initializeModule(data.ModuleName, data.Schema)
+			return nil
+		},
+		StartBlock: func(data appdata.StartBlockData) error {
+			i.pendingHeight = data.Height
+			i.pendingUpdates = nil
+			return nil
+		},
+		OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+			i.pendingUpdates = append(i.pendingUpdates, data)
+			return nil
+		},
+		Commit: func(appdata.CommitData) error {
+			for _, data := range i.pendingUpdates {
+				if err := i.state.apply(data.ModuleName, data.Updates); err != nil {
+					return err
+				}
+			}
+			i.height = i.pendingHeight
+			i.pendingUpdates = nil
+			return nil
+		},
+	}
+}
+
+func (i *testIndexer) restart() (appdata.ResumableListener, error) {
+	i.restarts++
+	i.pendingUpdates = nil
+	return appdata.ResumableListener{Listener: i.listener(), State: i}, nil
+}
+
+func appSchemaGen() *rapid.Generator[map[string]schema.ModuleSchema] {
+	return rapid.Custom(func(t *rapid.T) map[string]schema.ModuleSchema {
+		return map[string]schema.ModuleSchema{
+			"bank":    schematesting.ModuleSchemaGen(schematesting.Options{}).Draw(t, "bank"),
+			"staking": schematesting.ModuleSchemaGen(schematesting.Options{}).Draw(t, "staking"),
+		}
+	})
+}
+
+func TestSimulator(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		indexer := &testIndexer{state: newAppState()}
+		sim, err := NewSimulator(Options{AppSchema: appSchemaGen().Draw(t, "appSchema"), Listener: indexer.listener()})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 10; i++ {
+			if err := sim.ProcessBlockData(sim.BlockDataGen().Draw(t, "block")); err != nil {
+				t.Fatal(err)
+			}
+			if diff := DiffAppData(sim, indexer); diff != "" {
+				t.Fatalf("indexer state differs after block %d:\n%s", i+1, diff)
+			}
+		}
+	})
+}
+
+func TestSimulator_failures(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		indexer := &testIndexer{state: newAppState()}
+		sim, err := NewSimulator(Options{
+			AppSchema: appSchemaGen().Draw(t, "appSchema"),
+			Listener:  indexer.listener(),
+			Failures: FailureOptions{
+				ListenerErrorPercent: 30,
+				CrashPercent:         30,
+				Seed:                 rapid.Int64().Draw(t, "seed"),
+			},
+			Restart: indexer.restart,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 20; i++ {
+			if err := sim.ProcessBlockData(sim.BlockDataGen().Draw(t, "block")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if diff := DiffAppData(sim, indexer); diff != "" {
+			t.Fatalf("indexer state differs after recovering from %+v:\n%s", sim.FailureStats(), diff)
+		}
+
+		stats := sim.FailureStats()
+		if stats.ListenerErrors+stats.Crashes != stats.Restarts || stats.Restarts != indexer.restarts {
+			t.Fatalf("unexpected failure stats %+v with %d restarts", stats, indexer.restarts)
+		}
+	})
+}
+
+func TestSimulator_lostCommit(t *testing.T) {
+	// an indexer which loses committed blocks when it restarts is caught up with the committed blocks
+	indexer := &testIndexer{state: newAppState()}
+	appSchema := appSchemaGen().Example(1)
+	sim, err := NewSimulator(Options{
+		AppSchema: appSchema,
+		Listener:  indexer.listener(),
+		Failures:  FailureOptions{CrashPercent: 100},
+		Restart: func() (appdata.ResumableListener, error) {
+			indexer.state = newAppState()
+			indexer.height = 0
+			return indexer.restart()
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if err := sim.ProcessBlockData(sim.BlockDataGen().Example(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if diff := DiffAppData(sim, indexer); diff != "" {
+		t.Fatalf("indexer state differs:\n%s", diff)
+	}
+}
+
+func TestNewSimulator_requiresRestart(t *testing.T) {
+	_, err := NewSimulator(Options{Failures: FailureOptions{CrashPercent: 10}})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
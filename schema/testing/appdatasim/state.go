@@ -0,0 +1,238 @@
+package appdatasim
+
+import (
+	"fmt"
+	"sort"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/view"
+)
+
+// appState is an in-memory app state which applies object updates and implements view.AppState.
+type appState struct {
+	modules map[string]*moduleState
+}
+
+func newAppState() *appState {
+	return &appState{modules: map[string]*moduleState{}}
+}
+
+// initializeModule adds the module to the state if it doesn't exist yet.
+func (a *appState) initializeModule(moduleName string, modSchema schema.ModuleSchema) {
+	if _, ok := a.modules[moduleName]; ok {
+		return
+	}
+
+	mod := &moduleState{name: moduleName, schema: modSchema, collections: map[string]*objectCollection{}}
+	modSchema.ObjectTypes(func(objectType schema.ObjectType) bool {
+		mod.collections[objectType.Name] = &objectCollection{objectType: objectType, objects: map[string]schema.ObjectUpdate{}}
+		return true
+	})
+	a.modules[moduleName] = mod
+}
+
+// apply applies the updates of a module.
+func (a *appState) apply(moduleName string, updates []schema.ObjectUpdate) error {
+	mod, ok := a.modules[moduleName]
+	if !ok {
+		return fmt.Errorf("module %q isn't initialized", moduleName)
+	}
+	for _, update := range updates {
+		coll, ok := mod.collections[update.TypeName]
+		if !ok {
+			return fmt.Errorf("unknown object type %q in module %q", update.TypeName, moduleName)
+		}
+		if err := coll.apply(update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clone returns a deep copy of the state, except for the values of objects which are never mutated.
+func (a *appState) clone() *appState {
+	res := newAppState()
+	for name, mod := range a.modules {
+		modCopy := &moduleState{name: name, schema: mod.schema, collections: map[string]*objectCollection{}}
+		for typeName, coll := range mod.collections {
+			objects := make(map[string]schema.ObjectUpdate, len(coll.objects))
+			for k, v := range coll.objects {
+				objects[k] = v
+			}
+			modCopy.collections[typeName] = &objectCollection{objectType: coll.objectType, objects: objects}
+		}
+		res.modules[name] = modCopy
+	}
+	return res
+}
+
+func (a *appState) GetModule(moduleName string) (view.ModuleState, error) {
+	mod, ok := a.modules[moduleName]
+	if !ok {
+		return nil, nil
+	}
+	return mod, nil
+}
+
+func (a *appState) Modules(f func(view.ModuleState, error) bool) {
+	for _, name := range sortedKeys(a.modules) {
+		if !f(a.modules[name], nil) {
+			return
+		}
+	}
+}
+
+func (a *appState) NumModules() (int, error) {
+	return len(a.modules), nil
+}
+
+type moduleState struct {
+	name        string
+	schema      schema.ModuleSchema
+	collections map[string]*objectCollection
+}
+
+func (m *moduleState) ModuleName() string {
+	return m.name
+}
+
+func (m *moduleState) ModuleSchema() schema.ModuleSchema {
+	return m.schema
+}
+
+func (m *moduleState) GetObjectCollection(objectType string) (view.ObjectCollection, error) {
+	coll, ok := m.collections[objectType]
+	if !ok {
+		return nil, nil
+	}
+	return coll, nil
+}
+
+func (m *moduleState) ObjectCollections(f func(view.ObjectCollection, error) bool) {
+	for _, name := range sortedKeys(m.collections) {
+		if !f(m.collections[name], nil) {
+			return
+		}
+	}
+}
+
+func (m *moduleState) NumObjectCollections() (int, error) {
+	return len(m.collections), nil
+}
+
+// objectCollection stores the objects of an object type by the string of their key, see keyString.
+type objectCollection struct {
+	objectType schema.ObjectType
+	objects    map[string]schema.ObjectUpdate
+}
+
+func (c *objectCollection) apply(update schema.ObjectUpdate) error {
+	if err := c.objectType.ValidateObjectUpdate(update); err != nil {
+		return err
+	}
+
+	key := keyString(c.objectType, update.Key)
+	existing, exists := c.objects[key]
+	if update.Delete {
+		switch {
+		case !exists:
+		case c.objectType.RetainDeletions:
+			existing.Delete = true
+			c.objects[key] = existing
+		default:
+			delete(c.objects, key)
+		}
+		return nil
+	}
+
+	valueUpdates, ok := update.Value.(schema.ValueUpdates)
+	if !ok {
+		c.objects[key] = schema.ObjectUpdate{TypeName: update.TypeName, Key: update.Key, Value: update.Value}
+		return nil
+	}
+
+	if !exists || existing.Delete {
+		return fmt.Errorf("can't apply partial update to object %v of type %q which doesn't exist", update.Key, update.TypeName)
+	}
+	values := c.valueSlice(existing.Value)
+	err := valueUpdates.Iterate(func(name string, value interface{}) bool {
+		for i, field := range c.objectType.ValueFields {
+			if field.Name == name {
+				values[i] = value
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if len(values) == 1 {
+		existing.Value = values[0]
+	} else {
+		existing.Value = values
+	}
+	c.objects[key] = existing
+	return nil
+}
+
+// valueSlice returns a copy of the values of the value fields of an object.
+func (c *objectCollection) valueSlice(value interface{}) []interface{} {
+	if len(c.objectType.ValueFields) == 1 {
+		return []interface{}{value}
+	}
+	return append([]interface{}{}, value.([]interface{})...)
+}
+
+// keys returns the keys of the objects in the order of their key strings, excluding retained deletions.
+func (c *objectCollection) keys() []interface{} {
+	var res []interface{}
+	for _, k := range sortedKeys(c.objects) {
+		if obj := c.objects[k]; !obj.Delete {
+			res = append(res, obj.Key)
+		}
+	}
+	return res
+}
+
+func (c *objectCollection) ObjectType() schema.ObjectType {
+	return c.objectType
+}
+
+func (c *objectCollection) GetObject(key interface{}) (schema.ObjectUpdate, bool, error) {
+	obj, ok := c.objects[keyString(c.objectType, key)]
+	return obj, ok, nil
+}
+
+func (c *objectCollection) AllState(f func(schema.ObjectUpdate, error) bool) {
+	for _, k := range sortedKeys(c.objects) {
+		if !f(c.objects[k], nil) {
+			return
+		}
+	}
+}
+
+func (c *objectCollection) Len() (int, error) {
+	return len(c.objects), nil
+}
+
+func (c *objectCollection) List(filter view.FieldFilter, order view.OrderBy, page view.Pagination) (view.ListResult, error) {
+	return view.ListObjects(c, filter, order, page)
+}
+
+// keyString returns a string which identifies a key of an object of the object type. Keys never contain maps
+// or floats and the keys of singletons are ignored.
+func keyString(objectType schema.ObjectType, key interface{}) string {
+	if len(objectType.KeyFields) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%#v", key)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
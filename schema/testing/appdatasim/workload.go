@@ -0,0 +1,233 @@
+package appdatasim
+
+import (
+	"fmt"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	schematesting "cosmossdk.io/schema/testing"
+)
+
+// Workload is a deterministic profile of the app data of a simulated app, which can be used to benchmark
+// listeners reproducibly. All workloads share the module schemas of WorkloadAppSchema and differ in the
+// distribution of updates and events between modules and in the sizes of values.
+type Workload struct {
+	// Name is the name of the workload.
+	Name string
+
+	// Options are the options of the simulator which generates the blocks of the workload. Listener, Failures
+	// and Restart are ignored.
+	Options Options
+}
+
+// BankHeavyWorkload returns a workload where most updates are balance and supply changes of the bank module.
+func BankHeavyWorkload() Workload {
+	return Workload{Name: "bank-heavy", Options: Options{
+		AppSchema:          WorkloadAppSchema(),
+		MinUpdatesPerBlock: 50,
+		MaxUpdatesPerBlock: 150,
+		MaxEventsPerBlock:  20,
+		ModuleWeights:      map[string]int{"bank": 8, "staking": 1},
+	}}
+}
+
+// StakingHeavyWorkload returns a workload where most updates are validator, delegation and unbonding changes
+// of the staking module.
+func StakingHeavyWorkload() Workload {
+	return Workload{Name: "staking-heavy", Options: Options{
+		AppSchema:          WorkloadAppSchema(),
+		MinUpdatesPerBlock: 50,
+		MaxUpdatesPerBlock: 150,
+		MaxEventsPerBlock:  20,
+		ModuleWeights:      map[string]int{"bank": 1, "staking": 8},
+	}}
+}
+
+// EventHeavyWorkload returns a workload with few updates and many typed events per block.
+func EventHeavyWorkload() Workload {
+	return Workload{Name: "event-heavy", Options: Options{
+		AppSchema:          WorkloadAppSchema(),
+		MaxUpdatesPerBlock: 10,
+		MaxEventsPerBlock:  500,
+		ModuleWeights:      map[string]int{"bank": 1, "staking": 1},
+	}}
+}
+
+// LargeValueWorkload returns a workload of blobs of up to 64 KiB stored in the blob module.
+func LargeValueWorkload() Workload {
+	return Workload{Name: "large-values", Options: Options{
+		AppSchema:          WorkloadAppSchema(),
+		StateGen:           schematesting.Options{MaxBytesLen: 64 << 10},
+		MinUpdatesPerBlock: 5,
+		MaxUpdatesPerBlock: 20,
+		ModuleWeights:      map[string]int{"blob": 1},
+	}}
+}
+
+// Workloads returns all built-in workloads.
+func Workloads() []Workload {
+	return []Workload{BankHeavyWorkload(), StakingHeavyWorkload(), EventHeavyWorkload(), LargeValueWorkload()}
+}
+
+// Blocks generates the blocks of the workload. The same number of blocks and seed always generate the same
+// blocks.
+func (w Workload) Blocks(numBlocks, seed int) ([]BlockData, error) {
+	opts := w.Options
+	opts.Listener = appdata.Listener{}
+	opts.Failures = FailureOptions{}
+	opts.Restart = nil
+	sim, err := NewSimulator(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make([]BlockData, numBlocks)
+	for i := range blocks {
+		blocks[i] = sim.BlockDataGen().Example(seed + i)
+		if err := sim.ProcessBlockData(blocks[i]); err != nil {
+			return nil, fmt.Errorf("failed to process block %d of workload %s: %w", i+1, w.Name, err)
+		}
+	}
+	return blocks, nil
+}
+
+// WorkloadAppSchema returns the module schemas of the built-in workloads, which model the state of the bank
+// and staking modules and a module storing large blobs.
+func WorkloadAppSchema() map[string]schema.ModuleSchema {
+	return map[string]schema.ModuleSchema{
+		"bank":    mustModuleSchema(bankObjectTypes, bankEventTypes),
+		"staking": mustModuleSchema(stakingObjectTypes, stakingEventTypes),
+		"blob":    mustModuleSchema(blobObjectTypes, nil),
+	}
+}
+
+func mustModuleSchema(objectTypes []schema.ObjectType, eventTypes []schema.EventType) schema.ModuleSchema {
+	modSchema, err := schema.NewModuleSchema(objectTypes)
+	if err != nil {
+		panic(err)
+	}
+	if len(eventTypes) != 0 {
+		modSchema, err = modSchema.WithEventTypes(eventTypes...)
+		if err != nil {
+			panic(err)
+		}
+	}
+	return modSchema
+}
+
+var bankObjectTypes = []schema.ObjectType{
+	{
+		Name: "balances",
+		KeyFields: []schema.Field{
+			{Name: "address", Kind: schema.AddressKind},
+			{Name: "denom", Kind: schema.StringKind},
+		},
+		ValueFields: []schema.Field{{Name: "amount", Kind: schema.IntegerStringKind}},
+	},
+	{
+		Name:        "supply",
+		KeyFields:   []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+		ValueFields: []schema.Field{{Name: "amount", Kind: schema.IntegerStringKind}},
+	},
+	{
+		Name: "params",
+		ValueFields: []schema.Field{
+			{Name: "default_send_enabled", Kind: schema.BoolKind},
+			{Name: "send_enabled_denoms", Kind: schema.ListKind, ElementKind: schema.StringKind},
+		},
+	},
+}
+
+var bankEventTypes = []schema.EventType{
+	{
+		Name: "transfer",
+		Fields: []schema.Field{
+			{Name: "sender", Kind: schema.AddressKind},
+			{Name: "recipient", Kind: schema.AddressKind},
+			{Name: "amount", Kind: schema.IntegerStringKind},
+			{Name: "denom", Kind: schema.StringKind},
+		},
+	},
+	{
+		Name: "coin_spent",
+		Fields: []schema.Field{
+			{Name: "spender", Kind: schema.AddressKind},
+			{Name: "amount", Kind: schema.IntegerStringKind},
+		},
+	},
+}
+
+var bondStatus = schema.EnumType{Name: "bond_status", Values: []string{"unbonded", "unbonding", "bonded"}}
+
+var stakingObjectTypes = []schema.ObjectType{
+	{
+		Name:      "validators",
+		KeyFields: []schema.Field{{Name: "operator", Kind: schema.AddressKind}},
+		ValueFields: []schema.Field{
+			{Name: "tokens", Kind: schema.IntegerStringKind},
+			{Name: "delegator_shares", Kind: schema.DecimalStringKind},
+			{Name: "status", Kind: schema.EnumKind, EnumType: bondStatus},
+			{Name: "jailed", Kind: schema.BoolKind},
+			{Name: "commission_rate", Kind: schema.DecimalStringKind},
+			{Name: "description", Kind: schema.StructKind, StructType: schema.StructType{
+				Name: "validator_description",
+				Fields: []schema.Field{
+					{Name: "moniker", Kind: schema.StringKind},
+					{Name: "website", Kind: schema.StringKind, Nullable: true},
+				},
+			}},
+		},
+		RetainDeletions: true,
+	},
+	{
+		Name: "delegations",
+		KeyFields: []schema.Field{
+			{Name: "delegator", Kind: schema.AddressKind},
+			{Name: "validator", Kind: schema.AddressKind},
+		},
+		ValueFields: []schema.Field{{Name: "shares", Kind: schema.DecimalStringKind}},
+	},
+	{
+		Name: "unbonding_delegations",
+		KeyFields: []schema.Field{
+			{Name: "delegator", Kind: schema.AddressKind},
+			{Name: "validator", Kind: schema.AddressKind},
+			{Name: "creation_height", Kind: schema.Int64Kind},
+		},
+		ValueFields: []schema.Field{
+			{Name: "completion_time", Kind: schema.TimeKind},
+			{Name: "balance", Kind: schema.IntegerStringKind},
+		},
+	},
+}
+
+var stakingEventTypes = []schema.EventType{
+	{
+		Name: "delegate",
+		Fields: []schema.Field{
+			{Name: "validator", Kind: schema.AddressKind},
+			{Name: "delegator", Kind: schema.AddressKind},
+			{Name: "amount", Kind: schema.IntegerStringKind},
+			{Name: "new_shares", Kind: schema.DecimalStringKind},
+		},
+	},
+	{
+		Name: "unbond",
+		Fields: []schema.Field{
+			{Name: "validator", Kind: schema.AddressKind},
+			{Name: "amount", Kind: schema.IntegerStringKind},
+			{Name: "completion_time", Kind: schema.TimeKind},
+		},
+	},
+}
+
+var blobObjectTypes = []schema.ObjectType{
+	{
+		Name:      "blobs",
+		KeyFields: []schema.Field{{Name: "id", Kind: schema.Uint64Kind}},
+		ValueFields: []schema.Field{
+			{Name: "content_type", Kind: schema.StringKind},
+			{Name: "data", Kind: schema.BytesKind},
+		},
+	},
+}
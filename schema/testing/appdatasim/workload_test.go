@@ -0,0 +1,68 @@
+package appdatasim
+
+import (
+	"reflect"
+	"testing"
+
+	"cosmossdk.io/schema/appdata"
+)
+
+// evaluatedBlock replaces the attribute callbacks of events with their values so that blocks can be compared.
+func evaluatedBlock(t *testing.T, block BlockData) []interface{} {
+	t.Helper()
+	res := make([]interface{}, len(block))
+	for i, packet := range block {
+		data, ok := packet.(appdata.EventData)
+		if !ok {
+			res[i] = packet
+			continue
+		}
+		attrs, err := data.Attributes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data.Attributes = nil
+		res[i] = []interface{}{data, attrs}
+	}
+	return res
+}
+
+func TestWorkload_Blocks(t *testing.T) {
+	for _, w := range Workloads() {
+		t.Run(w.Name, func(t *testing.T) {
+			blocks1, err := w.Blocks(3, 7)
+			if err != nil {
+				t.Fatal(err)
+			}
+			blocks2, err := w.Blocks(3, 7)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := range blocks1 {
+				if !reflect.DeepEqual(evaluatedBlock(t, blocks1[i]), evaluatedBlock(t, blocks2[i])) {
+					t.Fatalf("block %d differs between runs with the same seed", i+1)
+				}
+			}
+
+			indexer := &testIndexer{state: newAppState()}
+			res, err := w.Run(indexer.listener(), blocks1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Blocks != 3 || res.Updates+res.Events == 0 {
+				t.Fatalf("unexpected result %s", res)
+			}
+		})
+	}
+}
+
+func BenchmarkWorkloads(b *testing.B) {
+	for _, w := range Workloads() {
+		b.Run(w.Name, func(b *testing.B) {
+			BenchmarkWorkload(b, w, 20, func() appdata.Listener {
+				return (&testIndexer{state: newAppState()}).listener()
+			})
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package schematesting
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"pgregory.net/rapid"
+
+	"cosmossdk.io/schema"
+)
+
+// NameGen generates valid lowercase names without underscores. Generated type names are derived from them by
+// joining them with underscores, so that they can't collide with generated names.
+var NameGen = rapid.StringMatching(`^[a-z][a-z0-9]{0,11}$`)
+
+// keyKind returns true if the kind is generated for key fields. Besides lists and maps, which are invalid key
+// kinds, floats, JSON, structs and oneofs are excluded because they don't have a canonical representation
+// which indexers can use as primary keys.
+func keyKind(kind schema.Kind) bool {
+	switch kind {
+	case schema.Float32Kind, schema.Float64Kind, schema.JSONKind, schema.StructKind, schema.ListKind, schema.MapKind,
+		schema.OneOfKind:
+		return false
+	default:
+		return true
+	}
+}
+
+// kindGen generates kinds accepted by allowed with the weights of the options, falling back to StringKind
+// if no kind with a weight is allowed.
+func (o Options) kindGen(allowed func(schema.Kind) bool) *rapid.Generator[schema.Kind] {
+	kinds := o.weightedKinds(allowed)
+	if len(kinds) == 0 {
+		return rapid.Just(schema.StringKind)
+	}
+	return rapid.SampledFrom(kinds)
+}
+
+// FieldGen generates valid value fields. The names of the enum and struct types of the fields are prefixed
+// with typeName.
+func FieldGen(typeName string, opts Options) *rapid.Generator[schema.Field] {
+	opts = opts.withDefaults()
+	return rapid.Custom(func(t *rapid.T) schema.Field {
+		name := NameGen.Draw(t, "name")
+		return opts.drawField(t, typeName, name, false, 0)
+	})
+}
+
+// KeyFieldGen generates valid key fields. The names of the enum types of the fields are prefixed with
+// typeName.
+func KeyFieldGen(typeName string, opts Options) *rapid.Generator[schema.Field] {
+	opts = opts.withDefaults()
+	return rapid.Custom(func(t *rapid.T) schema.Field {
+		name := NameGen.Draw(t, "name")
+		return opts.drawField(t, typeName, name, true, 0)
+	})
+}
+
+// drawField draws a field of the type typeName. depth is the number of structs and oneofs which contain the field.
+func (o Options) drawField(t *rapid.T, typeName, name string, key bool, depth int) schema.Field {
+	allowed := func(kind schema.Kind) bool {
+		switch {
+		case key:
+			return keyKind(kind)
+		case depth > 0:
+			// structs and oneofs only contain scalar fields to keep generated schemas small
+			return kind != schema.StructKind && kind != schema.ListKind && kind != schema.MapKind && kind != schema.OneOfKind
+		default:
+			return true
+		}
+	}
+
+	field := schema.Field{Name: name, Kind: o.kindGen(allowed).Draw(t, "kind")}
+	elemTypeName := typeName + "_" + name
+	elemAllowed := func(kind schema.Kind) bool {
+		return kind != schema.ListKind && kind != schema.MapKind &&
+			(depth == 0 || (kind != schema.StructKind && kind != schema.OneOfKind))
+	}
+
+	var elemKind schema.Kind
+	switch field.Kind {
+	case schema.ListKind:
+		field.ElementKind = o.kindGen(elemAllowed).Draw(t, "elementKind")
+		field.NullableElements = o.drawPercent(t, o.NullablePercent, "nullableElements")
+		elemKind = field.ElementKind
+	case schema.MapKind:
+		field.KeyKind = o.kindGen(schema.Kind.IsValidMapKeyKind).Draw(t, "keyKind")
+		field.ValueKind = o.kindGen(elemAllowed).Draw(t, "valueKind")
+		field.NullableElements = o.drawPercent(t, o.NullablePercent, "nullableElements")
+		elemKind = field.ValueKind
+	default:
+		elemKind = field.Kind
+	}
+
+	switch elemKind {
+	case schema.EnumKind:
+		field.EnumType = o.drawEnumType(t, elemTypeName)
+	case schema.StructKind:
+		field.StructType = o.drawStructType(t, elemTypeName, depth+1)
+	case schema.OneOfKind:
+		field.OneOfType = o.drawOneOfType(t, elemTypeName, depth+1)
+	}
+
+	if !key {
+		field.Nullable = o.drawPercent(t, o.NullablePercent, "nullable")
+	}
+	return field
+}
+
+// EnumTypeGen generates valid enum types with the given name.
+func EnumTypeGen(name string, opts Options) *rapid.Generator[schema.EnumType] {
+	opts = opts.withDefaults()
+	return rapid.Custom(func(t *rapid.T) schema.EnumType {
+		return opts.drawEnumType(t, name)
+	})
+}
+
+func (o Options) drawEnumType(t *rapid.T, name string) schema.EnumType {
+	values := rapid.SliceOfNDistinct(NameGen, o.MinEnumValues, o.MaxEnumValues, rapid.ID[string]).Draw(t, "enumValues")
+	return schema.EnumType{Name: name, Values: values}
+}
+
+func (o Options) drawStructType(t *rapid.T, name string, depth int) schema.StructType {
+	names := rapid.SliceOfNDistinct(NameGen, 1, o.MaxStructFields, rapid.ID[string]).Draw(t, "structFields")
+	fields := make([]schema.Field, len(names))
+	for i, fieldName := range names {
+		fields[i] = o.drawField(t, name, fieldName, false, depth)
+	}
+	return schema.StructType{Name: name, Fields: fields}
+}
+
+func (o Options) drawOneOfType(t *rapid.T, name string, depth int) schema.OneOfType {
+	names := rapid.SliceOfNDistinct(NameGen, 1, o.MaxStructFields, rapid.ID[string]).Draw(t, "oneOfCases")
+	cases := make([]schema.Field, len(names))
+	for i, caseName := range names {
+		// cases are never nullable, the oneof field itself is
+		cases[i] = o.drawField(t, name, caseName, false, depth)
+		cases[i].Nullable = false
+	}
+	return schema.OneOfType{Name: name, Cases: cases}
+}
+
+// drawPercent draws true with the probability p in percent.
+func (o Options) drawPercent(t *rapid.T, p int, label string) bool {
+	return rapid.IntRange(0, 99).Draw(t, label) < percent(p)
+}
+
+// drawBytes draws a bytes value of up to MaxBytesLen bytes.
+func (o Options) drawBytes(t *rapid.T) []byte {
+	n := rapid.IntRange(0, o.MaxBytesLen).Draw(t, "bytesLen")
+	if n <= 32 {
+		return rapid.SliceOfN(rapid.Byte(), n, n).Draw(t, "bytes")
+	}
+	bz := make([]byte, n)
+	rand.New(rand.NewSource(rapid.Int64().Draw(t, "bytesSeed"))).Read(bz) //nolint:gosec // test data doesn't need a secure random source
+	return bz
+}
+
+// FieldValueGen generates valid values for the field, including null values for nullable fields.
+func FieldValueGen(field schema.Field, opts Options) *rapid.Generator[interface{}] {
+	opts = opts.withDefaults()
+	return rapid.Custom(func(t *rapid.T) interface{} {
+		return opts.drawFieldValue(t, field)
+	})
+}
+
+func (o Options) drawFieldValue(t *rapid.T, field schema.Field) interface{} {
+	if field.Nullable && o.drawPercent(t, o.NullablePercent, "null") {
+		return nil
+	}
+
+	switch field.Kind {
+	case schema.ListKind:
+		n := rapid.IntRange(0, o.MaxListLen).Draw(t, "listLen")
+		list := make([]interface{}, n)
+		for i := range list {
+			list[i] = o.drawElementValue(t, field, field.ElementKind)
+		}
+		return list
+	case schema.MapKind:
+		n := rapid.IntRange(0, o.MaxListLen).Draw(t, "mapLen")
+		m := make(map[interface{}]interface{}, n)
+		for i := 0; i < n; i++ {
+			m[o.drawKindValue(t, field, field.KeyKind)] = o.drawElementValue(t, field, field.ValueKind)
+		}
+		return m
+	default:
+		return o.drawKindValue(t, field, field.Kind)
+	}
+}
+
+func (o Options) drawElementValue(t *rapid.T, field schema.Field, kind schema.Kind) interface{} {
+	if field.NullableElements && o.drawPercent(t, o.NullablePercent, "nullElement") {
+		return nil
+	}
+	return o.drawKindValue(t, field, kind)
+}
+
+// drawKindValue draws a value of the kind, which is the kind of the field or of its elements, keys or values.
+func (o Options) drawKindValue(t *rapid.T, field schema.Field, kind schema.Kind) interface{} {
+	switch kind {
+	case schema.StringKind:
+		return strings.ReplaceAll(rapid.StringN(0, 16, -1).Draw(t, "string"), "\x00", "")
+	case schema.BytesKind:
+		return o.drawBytes(t)
+	case schema.Int8Kind:
+		return rapid.Int8().Draw(t, "int8")
+	case schema.Uint8Kind:
+		return rapid.Uint8().Draw(t, "uint8")
+	case schema.Int16Kind:
+		return rapid.Int16().Draw(t, "int16")
+	case schema.Uint16Kind:
+		return rapid.Uint16().Draw(t, "uint16")
+	case schema.Int32Kind:
+		return rapid.Int32().Draw(t, "int32")
+	case schema.Uint32Kind:
+		return rapid.Uint32().Draw(t, "uint32")
+	case schema.Int64Kind:
+		return rapid.Int64().Draw(t, "int64")
+	case schema.Uint64Kind:
+		return rapid.Uint64().Draw(t, "uint64")
+	case schema.IntegerStringKind:
+		return rapid.StringMatching(`^-?[1-9][0-9]{0,30}$|^0$`).Draw(t, "integer")
+	case schema.DecimalStringKind:
+		return rapid.StringMatching(`^-?(0|[1-9][0-9]{0,15})(\.[0-9]{0,15}[1-9])?$`).Draw(t, "decimal")
+	case schema.BoolKind:
+		return rapid.Bool().Draw(t, "bool")
+	case schema.TimeKind:
+		// times are truncated to microseconds, which all indexers can store losslessly
+		micros := rapid.Int64Range(-1<<50, 1<<50).Draw(t, "time")
+		return time.UnixMicro(micros).UTC()
+	case schema.DurationKind:
+		return time.Duration(rapid.Int64().Draw(t, "duration"))
+	case schema.Float32Kind:
+		return rapid.Float32Range(-math.MaxFloat32, math.MaxFloat32).Draw(t, "float32")
+	case schema.Float64Kind:
+		return rapid.Float64Range(-math.MaxFloat64, math.MaxFloat64).Draw(t, "float64")
+	case schema.AddressKind:
+		return rapid.SliceOfN(rapid.Byte(), 20, 32).Draw(t, "address")
+	case schema.EnumKind:
+		return rapid.SampledFrom(field.EnumType.Values).Draw(t, "enum")
+	case schema.JSONKind:
+		obj := rapid.MapOfN(NameGen, rapid.Int32(), 0, 3).Draw(t, "json")
+		bz, err := json.Marshal(obj)
+		if err != nil {
+			t.Fatalf("can't marshal JSON value: %v", err)
+		}
+		return json.RawMessage(bz)
+	case schema.StructKind:
+		values := make([]interface{}, len(field.StructType.Fields))
+		for i, structField := range field.StructType.Fields {
+			values[i] = o.drawFieldValue(t, structField)
+		}
+		return values
+	case schema.OneOfKind:
+		c := rapid.SampledFrom(field.OneOfType.Cases).Draw(t, "oneOfCase")
+		return schema.OneOfValue{Case: c.Name, Value: o.drawFieldValue(t, c)}
+	default:
+		panic(fmt.Sprintf("unexpected kind %s", kind))
+	}
+}
@@ -0,0 +1,127 @@
+package schematesting
+
+import (
+	"reflect"
+	"testing"
+
+	"pgregory.net/rapid"
+
+	"cosmossdk.io/schema"
+)
+
+func TestModuleSchemaGen(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		modSchema := ModuleSchemaGen(Options{}).Draw(t, "schema")
+		if err := modSchema.Validate(); err != nil {
+			t.Fatalf("invalid schema: %v", err)
+		}
+	})
+}
+
+func TestOptions(t *testing.T) {
+	opts := Options{
+		MinObjectTypes: 2,
+		MaxObjectTypes: 2,
+		MaxKeyFields:   1,
+		MinValueFields: 3,
+		MaxValueFields: 3,
+		KindWeights:    map[schema.Kind]int{schema.EnumKind: 1, schema.Int32Kind: 0},
+		MinEnumValues:  5,
+		MaxEnumValues:  5,
+	}
+	rapid.Check(t, func(t *rapid.T) {
+		modSchema := ModuleSchemaGen(opts).Draw(t, "schema")
+		numObjectTypes := 0
+		modSchema.ObjectTypes(func(objectType schema.ObjectType) bool {
+			numObjectTypes++
+			if len(objectType.KeyFields) > 1 || len(objectType.ValueFields) != 3 {
+				t.Fatalf("unexpected number of fields in %+v", objectType)
+			}
+			for _, field := range append(objectType.KeyFields, objectType.ValueFields...) {
+				if field.Kind != schema.EnumKind || len(field.EnumType.Values) != 5 {
+					t.Fatalf("unexpected field %+v", field)
+				}
+			}
+			return true
+		})
+		if numObjectTypes != 2 {
+			t.Fatalf("expected 2 object types, got %d", numObjectTypes)
+		}
+	})
+}
+
+func TestUpdateSequenceGen(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		modSchema := ModuleSchemaGen(Options{}).Draw(t, "schema")
+		updates := UpdateSequenceGen(modSchema, Options{}).Draw(t, "updates")
+
+		// the updates of each object form an insert, update and delete chain
+		type state struct{ inserted, deleted bool }
+		states := map[string]*state{}
+		for _, update := range updates {
+			if err := modSchema.ValidateObjectUpdate(update); err != nil {
+				t.Fatalf("invalid update %+v: %v", update, err)
+			}
+
+			id := update.TypeName + "/" + keyString(update.Key)
+			s := states[id]
+			switch {
+			case s == nil:
+				if update.Delete {
+					t.Fatalf("object %s deleted before insert", id)
+				}
+				if _, ok := update.Value.(schema.ValueUpdates); ok {
+					t.Fatalf("object %s inserted with partial value", id)
+				}
+				states[id] = &state{inserted: true}
+			case s.deleted:
+				t.Fatalf("object %s updated after delete", id)
+			case update.Delete:
+				s.deleted = true
+			}
+		}
+	})
+}
+
+func TestSeedStable(t *testing.T) {
+	gen := rapid.Custom(func(t *rapid.T) []schema.ObjectUpdate {
+		modSchema := ModuleSchemaGen(Options{}).Draw(t, "schema")
+		return UpdateSequenceGen(modSchema, Options{}).Draw(t, "updates")
+	})
+	for seed := 0; seed < 10; seed++ {
+		if a, b := gen.Example(seed), gen.Example(seed); !reflect.DeepEqual(a, b) {
+			t.Fatalf("seed %d generated different values:\n%#v\n%#v", seed, a, b)
+		}
+	}
+}
+
+func TestValueCodec(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		objectType := ObjectTypeGen(Options{}).Draw(t, "objectType")
+		value := ValueGen(objectType, Options{}).Draw(t, "value")
+
+		bz, err := schema.EncodeValue(value)
+		if err != nil {
+			t.Fatalf("can't encode %#v: %v", value, err)
+		}
+		decoded, err := schema.DecodeValue(bz)
+		if err != nil {
+			t.Fatalf("can't decode %#v: %v", value, err)
+		}
+		if !reflect.DeepEqual(value, decoded) {
+			t.Fatalf("expected %#v, got %#v", value, decoded)
+		}
+
+		jsonBz, err := schema.EncodeValueJSON(value)
+		if err != nil {
+			t.Fatalf("can't encode %#v to JSON: %v", value, err)
+		}
+		decoded, err = schema.DecodeValueJSON(jsonBz)
+		if err != nil {
+			t.Fatalf("can't decode %s: %v", jsonBz, err)
+		}
+		if !reflect.DeepEqual(value, decoded) {
+			t.Fatalf("expected %#v, got %#v", value, decoded)
+		}
+	})
+}
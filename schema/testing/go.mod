@@ -0,0 +1,12 @@
+module cosmossdk.io/schema/testing
+
+// NOTE: unlike cosmossdk.io/schema, this module is only used in tests and can use newer
+// go versions and dependencies such as rapid for property-based testing.
+go 1.23
+
+require (
+	cosmossdk.io/schema v0.1.1
+	pgregory.net/rapid v1.1.0
+)
+
+replace cosmossdk.io/schema => ./..
@@ -0,0 +1,228 @@
+package schematesting
+
+import (
+	"fmt"
+
+	"pgregory.net/rapid"
+
+	"cosmossdk.io/schema"
+)
+
+// ObjectTypeGen generates valid object types with the numbers of fields and the field kinds of the options.
+func ObjectTypeGen(opts Options) *rapid.Generator[schema.ObjectType] {
+	opts = opts.withDefaults()
+	return rapid.Custom(func(t *rapid.T) schema.ObjectType {
+		return opts.drawObjectType(t)
+	})
+}
+
+func (o Options) drawObjectType(t *rapid.T) schema.ObjectType {
+	name := NameGen.Draw(t, "objectTypeName")
+	numKeys := rapid.IntRange(0, o.MaxKeyFields).Draw(t, "numKeyFields")
+	minValues := o.MinValueFields
+	if numKeys == 0 && minValues == 0 {
+		// object types need at least one field
+		minValues = 1
+	}
+	numValues := rapid.IntRange(minValues, max(o.MaxValueFields, minValues)).Draw(t, "numValueFields")
+	names := rapid.SliceOfNDistinct(NameGen, numKeys+numValues, numKeys+numValues, rapid.ID[string]).Draw(t, "fieldNames")
+
+	objectType := schema.ObjectType{
+		Name:            name,
+		RetainDeletions: rapid.Bool().Draw(t, "retainDeletions"),
+	}
+	for _, fieldName := range names[:numKeys] {
+		objectType.KeyFields = append(objectType.KeyFields, o.drawField(t, name, fieldName, true, 0))
+	}
+	for _, fieldName := range names[numKeys:] {
+		objectType.ValueFields = append(objectType.ValueFields, o.drawField(t, name, fieldName, false, 0))
+	}
+	return objectType
+}
+
+// ModuleSchemaGen generates valid module schemas with the numbers of object types of the options.
+func ModuleSchemaGen(opts Options) *rapid.Generator[schema.ModuleSchema] {
+	opts = opts.withDefaults()
+	return rapid.Custom(func(t *rapid.T) schema.ModuleSchema {
+		objectTypes := rapid.SliceOfNDistinct(
+			rapid.Custom(opts.drawObjectType),
+			opts.MinObjectTypes, opts.MaxObjectTypes,
+			func(objectType schema.ObjectType) string { return objectType.Name },
+		).Draw(t, "objectTypes")
+
+		modSchema, err := schema.NewModuleSchema(objectTypes)
+		if err != nil {
+			t.Fatalf("generated invalid module schema: %v", err)
+		}
+		return modSchema
+	})
+}
+
+// KeyGen generates keys of the object type in the representation of schema.ObjectUpdate.Key, which are nil
+// for singletons.
+func KeyGen(objectType schema.ObjectType, opts Options) *rapid.Generator[interface{}] {
+	return opts.withDefaults().fieldsValueGen(objectType.KeyFields)
+}
+
+// ValueGen generates values of all the value fields of the object type in the representation of
+// schema.ObjectUpdate.Value, which are nil for object types without value fields.
+func ValueGen(objectType schema.ObjectType, opts Options) *rapid.Generator[interface{}] {
+	return opts.withDefaults().fieldsValueGen(objectType.ValueFields)
+}
+
+func (o Options) fieldsValueGen(fields []schema.Field) *rapid.Generator[interface{}] {
+	if len(fields) == 0 {
+		// custom generators must draw data
+		return rapid.Just[interface{}](nil)
+	}
+	return rapid.Custom(func(t *rapid.T) interface{} {
+		return o.drawFieldsValue(t, fields)
+	})
+}
+
+// drawFieldsValue draws values for fields, returning nil for no fields, the value for a single field and a
+// slice of values for more fields.
+func (o Options) drawFieldsValue(t *rapid.T, fields []schema.Field) interface{} {
+	switch len(fields) {
+	case 0:
+		return nil
+	case 1:
+		return o.drawFieldValue(t, fields[0])
+	default:
+		values := make([]interface{}, len(fields))
+		for i, field := range fields {
+			values[i] = o.drawFieldValue(t, field)
+		}
+		return values
+	}
+}
+
+// ObjectInsertGen generates updates which insert objects of the object type with all their value fields.
+func ObjectInsertGen(objectType schema.ObjectType, opts Options) *rapid.Generator[schema.ObjectUpdate] {
+	opts = opts.withDefaults()
+	return rapid.Custom(func(t *rapid.T) schema.ObjectUpdate {
+		return schema.ObjectUpdate{
+			TypeName: objectType.Name,
+			Key:      opts.drawFieldsValue(t, objectType.KeyFields),
+			Value:    opts.drawFieldsValue(t, objectType.ValueFields),
+		}
+	})
+}
+
+// ObjectUpdateSequenceGen generates sequences of correlated updates of objects of the object type. Each object
+// is first inserted with all its value fields, then updated up to MaxUpdatesPerKey times and finally deleted
+// with the probability DeletePercent, which is never the case for singletons. Updates are either complete or
+// partial schema.MapValueUpdates. The update chains of the objects are interleaved, but the updates of each
+// object stay in order, so that indexers can be checked against the expected state at any point of the
+// sequence.
+func ObjectUpdateSequenceGen(objectType schema.ObjectType, opts Options) *rapid.Generator[[]schema.ObjectUpdate] {
+	opts = opts.withDefaults()
+	return rapid.Custom(func(t *rapid.T) []schema.ObjectUpdate {
+		return interleave(t, opts.drawUpdateChains(t, objectType))
+	})
+}
+
+// UpdateSequenceGen generates sequences of correlated updates of the objects of all object types of the module
+// schema, following the rules of ObjectUpdateSequenceGen.
+func UpdateSequenceGen(modSchema schema.ModuleSchema, opts Options) *rapid.Generator[[]schema.ObjectUpdate] {
+	opts = opts.withDefaults()
+	return rapid.Custom(func(t *rapid.T) []schema.ObjectUpdate {
+		var chains [][]schema.ObjectUpdate
+		modSchema.ObjectTypes(func(objectType schema.ObjectType) bool {
+			chains = append(chains, opts.drawUpdateChains(t, objectType)...)
+			return true
+		})
+		return interleave(t, chains)
+	})
+}
+
+// drawUpdateChains draws the update chains of distinct objects of the object type.
+func (o Options) drawUpdateChains(t *rapid.T, objectType schema.ObjectType) [][]schema.ObjectUpdate {
+	singleton := len(objectType.KeyFields) == 0
+	var keys []interface{}
+	if singleton {
+		keys = []interface{}{nil}
+	} else {
+		keys = rapid.SliceOfNDistinct(KeyGen(objectType, o), 0, o.MaxKeys, keyString).Draw(t, "keys")
+	}
+
+	chains := make([][]schema.ObjectUpdate, len(keys))
+	for i, key := range keys {
+		chain := []schema.ObjectUpdate{{
+			TypeName: objectType.Name,
+			Key:      key,
+			Value:    o.drawFieldsValue(t, objectType.ValueFields),
+		}}
+
+		if len(objectType.ValueFields) != 0 {
+			numUpdates := rapid.IntRange(0, o.MaxUpdatesPerKey).Draw(t, "numUpdates")
+			for j := 0; j < numUpdates; j++ {
+				chain = append(chain, schema.ObjectUpdate{
+					TypeName: objectType.Name,
+					Key:      key,
+					Value:    o.drawUpdateValue(t, objectType),
+				})
+			}
+		}
+
+		if !singleton && o.drawPercent(t, o.DeletePercent, "delete") {
+			chain = append(chain, schema.ObjectUpdate{TypeName: objectType.Name, Key: key, Delete: true})
+		}
+		chains[i] = chain
+	}
+	return chains
+}
+
+// UpdateValueGen generates values of updates of existing objects of the object type, which contain all value
+// fields or, with the probability PartialUpdatePercent, some of them as schema.MapValueUpdates. The object
+// type must have value fields.
+func UpdateValueGen(objectType schema.ObjectType, opts Options) *rapid.Generator[interface{}] {
+	opts = opts.withDefaults()
+	return rapid.Custom(func(t *rapid.T) interface{} {
+		return opts.drawUpdateValue(t, objectType)
+	})
+}
+
+// drawUpdateValue draws the value of an update of an existing object, which is partial with the probability
+// PartialUpdatePercent.
+func (o Options) drawUpdateValue(t *rapid.T, objectType schema.ObjectType) interface{} {
+	if !o.drawPercent(t, o.PartialUpdatePercent, "partial") {
+		return o.drawFieldsValue(t, objectType.ValueFields)
+	}
+
+	fields := rapid.SliceOfNDistinct(
+		rapid.SampledFrom(objectType.ValueFields), 1, len(objectType.ValueFields),
+		func(field schema.Field) string { return field.Name },
+	).Draw(t, "partialFields")
+	updates := schema.MapValueUpdates{}
+	for _, field := range fields {
+		updates[field.Name] = o.drawFieldValue(t, field)
+	}
+	return updates
+}
+
+// interleave merges the chains into a single sequence in a random order which keeps the order of each chain.
+func interleave(t *rapid.T, chains [][]schema.ObjectUpdate) []schema.ObjectUpdate {
+	var res []schema.ObjectUpdate
+	var active [][]schema.ObjectUpdate
+	for _, chain := range chains {
+		if len(chain) != 0 {
+			active = append(active, chain)
+		}
+	}
+	for len(active) != 0 {
+		i := rapid.IntRange(0, len(active)-1).Draw(t, "chain")
+		res = append(res, active[i][0])
+		active[i] = active[i][1:]
+		if len(active[i]) == 0 {
+			active = append(active[:i], active[i+1:]...)
+		}
+	}
+	return res
+}
+
+// keyString returns a string which identifies a key generated by KeyGen, whose values never contain maps or
+// floats.
+func keyString(key interface{}) string {
+	return fmt.Sprintf("%#v", key)
+}
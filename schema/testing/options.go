@@ -0,0 +1,137 @@
+// Package schematesting provides rapid generators of module schemas, object types and object updates for
+// property-based testing of indexers and other consumers of cosmossdk.io/schema.
+//
+// Generation is seed-stable: the same seed passed to rapid.Generator.Example with the same Options always
+// produces the same value, so that failures found by fuzzing can be reproduced from their seed.
+package schematesting
+
+import (
+	"sort"
+
+	"cosmossdk.io/schema"
+)
+
+// Options configure the distributions of the generators. The zero value of each option selects its default.
+type Options struct {
+	// MinObjectTypes and MaxObjectTypes bound the number of object types in module schemas. They default to
+	// 1 and 5.
+	MinObjectTypes, MaxObjectTypes int
+
+	// MaxKeyFields is the maximum number of key fields of object types. It defaults to 3. Object types
+	// without key fields are singletons.
+	MaxKeyFields int
+
+	// MinValueFields and MaxValueFields bound the number of value fields of object types. They default to
+	// 0 and 6.
+	MinValueFields, MaxValueFields int
+
+	// MaxStructFields is the maximum number of fields of struct types and cases of oneof types. It defaults to 4.
+	MaxStructFields int
+
+	// KindWeights are the relative probabilities of each field kind. Kinds which are missing or have a zero
+	// weight are never generated. Defaults to a weight of 1 for every kind. Kinds which aren't valid at a
+	// position, such as lists in key fields or nested lists, are excluded there.
+	KindWeights map[schema.Kind]int
+
+	// MinEnumValues and MaxEnumValues bound the number of values of enum types. They default to 1 and 8.
+	MinEnumValues, MaxEnumValues int
+
+	// NullablePercent is the probability in percent that a value field is nullable and that a nullable
+	// field gets a null value. It defaults to 25 and can be disabled with a negative value.
+	NullablePercent int
+
+	// MaxListLen is the maximum number of elements of lists and entries of maps. It defaults to 4.
+	MaxListLen int
+
+	// MaxBytesLen is the maximum length of bytes values. It defaults to 16. Values longer than 32 bytes are
+	// filled from a drawn seed rather than drawn byte by byte so that large values can be generated quickly.
+	MaxBytesLen int
+
+	// MaxKeys is the maximum number of distinct objects in update sequences. It defaults to 8.
+	MaxKeys int
+
+	// MaxUpdatesPerKey is the maximum number of updates of an object between its insert and its optional
+	// delete in update sequences. It defaults to 3.
+	MaxUpdatesPerKey int
+
+	// DeletePercent is the probability in percent that an object is deleted at the end of its update chain.
+	// It defaults to 30 and can be disabled with a negative value.
+	DeletePercent int
+
+	// PartialUpdatePercent is the probability in percent that an update only contains some value fields as
+	// schema.MapValueUpdates. It defaults to 30 and can be disabled with a negative value.
+	PartialUpdatePercent int
+}
+
+// DefaultOptions returns the default options.
+func DefaultOptions() Options {
+	return Options{}.withDefaults()
+}
+
+func (o Options) withDefaults() Options {
+	setDefault(&o.MinObjectTypes, 1)
+	setDefault(&o.MaxObjectTypes, 5)
+	setDefault(&o.MaxKeyFields, 3)
+	setDefault(&o.MaxValueFields, 6)
+	setDefault(&o.MaxStructFields, 4)
+	setDefault(&o.MinEnumValues, 1)
+	setDefault(&o.MaxEnumValues, 8)
+	setDefault(&o.NullablePercent, 25)
+	setDefault(&o.MaxListLen, 4)
+	setDefault(&o.MaxBytesLen, 16)
+	setDefault(&o.MaxKeys, 8)
+	setDefault(&o.MaxUpdatesPerKey, 3)
+	setDefault(&o.DeletePercent, 30)
+	setDefault(&o.PartialUpdatePercent, 30)
+	if o.KindWeights == nil {
+		o.KindWeights = map[schema.Kind]int{}
+		for kind := schema.StringKind; kind <= schema.MAX_VALID_KIND; kind++ {
+			o.KindWeights[kind] = 1
+		}
+	}
+	if o.MaxObjectTypes < o.MinObjectTypes {
+		o.MaxObjectTypes = o.MinObjectTypes
+	}
+	if o.MaxValueFields < o.MinValueFields {
+		o.MaxValueFields = o.MinValueFields
+	}
+	if o.MaxEnumValues < o.MinEnumValues {
+		o.MaxEnumValues = o.MinEnumValues
+	}
+	return o
+}
+
+func setDefault(v *int, def int) {
+	if *v == 0 {
+		*v = def
+	}
+}
+
+// percent returns the probability in percent of an option, treating negative values as zero.
+func percent(p int) int {
+	if p < 0 {
+		return 0
+	}
+	return p
+}
+
+// weightedKinds returns the kinds accepted by allowed, each repeated by its weight, in kind order so that
+// sampling from them is seed-stable.
+func (o Options) weightedKinds(allowed func(schema.Kind) bool) []schema.Kind {
+	kinds := make([]schema.Kind, 0, len(o.KindWeights))
+	for kind := range o.KindWeights {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+
+	var res []schema.Kind
+	for _, kind := range kinds {
+		if !allowed(kind) {
+			continue
+		}
+		for i := 0; i < o.KindWeights[kind]; i++ {
+			res = append(res, kind)
+		}
+	}
+	return res
+}
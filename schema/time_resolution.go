@@ -0,0 +1,89 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TimeResolution is the resolution of the values of a TimeKind field. Indexers can use it to choose a
+// column type which can store values without loss of precision.
+type TimeResolution int
+
+const (
+	// TimeResolutionNanos indicates that time values can have nanosecond precision. This is the default.
+	TimeResolutionNanos TimeResolution = iota
+
+	// TimeResolutionMillis indicates that time values are whole milliseconds.
+	TimeResolutionMillis
+
+	// TimeResolutionSeconds indicates that time values are whole seconds.
+	TimeResolutionSeconds
+)
+
+// Validate returns an error if the time resolution is invalid.
+func (r TimeResolution) Validate() error {
+	if r < TimeResolutionNanos || r > TimeResolutionSeconds {
+		return fmt.Errorf("invalid time resolution: %d", r)
+	}
+	return nil
+}
+
+// Unit returns the smallest duration which can be represented at the time resolution.
+func (r TimeResolution) Unit() time.Duration {
+	switch r {
+	case TimeResolutionMillis:
+		return time.Millisecond
+	case TimeResolutionSeconds:
+		return time.Second
+	default:
+		return time.Nanosecond
+	}
+}
+
+// ValidateTime returns an error if the time value is more precise than the time resolution.
+func (r TimeResolution) ValidateTime(t time.Time) error {
+	if unit := r.Unit(); t.Nanosecond()%int(unit) != 0 {
+		return fmt.Errorf("time %s is more precise than the time resolution %s", t.Format(time.RFC3339Nano), r)
+	}
+	return nil
+}
+
+// String returns a string representation of the time resolution.
+func (r TimeResolution) String() string {
+	switch r {
+	case TimeResolutionNanos:
+		return "nanos"
+	case TimeResolutionMillis:
+		return "millis"
+	case TimeResolutionSeconds:
+		return "seconds"
+	default:
+		return fmt.Sprintf("invalid(%d)", r)
+	}
+}
+
+// MarshalJSON marshals the time resolution as a JSON string using the name returned by TimeResolution.String.
+func (r TimeResolution) MarshalJSON() ([]byte, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON unmarshals the time resolution from a JSON string using the names returned by TimeResolution.String.
+func (r *TimeResolution) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	for res := TimeResolutionNanos; res <= TimeResolutionSeconds; res++ {
+		if res.String() == name {
+			*r = res
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown time resolution %q", name)
+}
@@ -1,7 +1,7 @@
 package schema
 
 // Type is an interface that all types in the schema implement.
-// Currently these are ObjectType and EnumType.
+// Currently these are ObjectType, EnumType, StructType, OneOfType and EventType.
 type Type interface {
 	// TypeName returns the type's name.
 	TypeName() string
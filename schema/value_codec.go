@@ -0,0 +1,677 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// The value encodings are self-describing: every value is tagged with the kind of its go type so that it can be
+// decoded without the schema. Because kinds which share a go type, such as StringKind, IntegerStringKind,
+// DecimalStringKind and EnumKind, are tagged with the simplest of them, see KindForGoValue, and structs are
+// encoded as lists, decoding always produces values with the same go types which were encoded.
+//
+// In the binary encoding, every value starts with its tag, which is the numeric value of its kind or 0 for
+// null, followed by:
+//   - strings, bytes and JSON: the uvarint length followed by the bytes
+//   - int8, uint8 and bool: a single byte
+//   - int16, int32, int64 and durations: the zig-zag varint of the value
+//   - uint16, uint32 and uint64: the uvarint of the value
+//   - float32 and float64: the 4 or 8 byte big-endian IEEE 754 bits
+//   - times: the varint of the unix seconds followed by the uvarint of the nanoseconds
+//   - lists: the uvarint length followed by the elements
+//   - maps: the uvarint length followed by each key and its value, sorted by the encoding of the keys
+//   - oneofs: the case as a string without tag followed by the value
+//
+// In the JSON encoding, null values are encoded as null and all other values as an object with a single
+// property whose name is the name of the kind, as returned by Kind.String, and whose value is:
+//   - strings and oneof cases: a JSON string
+//   - bytes: a base64 encoded string
+//   - int8, uint8, int16, uint16, int32, uint32 and float values: a JSON number except for infinite and NaN
+//     floats, which are encoded as the strings "+Inf", "-Inf" and "NaN"
+//   - int64, uint64 and durations: a base10 string, so that they don't lose precision in JavaScript
+//   - bools: a JSON boolean
+//   - times: an RFC 3339 string in UTC with nanosecond precision
+//   - JSON: a JSON string containing the JSON value, so that it is preserved byte for byte
+//   - lists: an array of the elements
+//   - maps: an array of [key, value] arrays, sorted by the binary encoding of the keys
+//   - oneofs: an object with the properties "case" and "value"
+//
+// Both encodings are canonical: the same value is always encoded to the same bytes. Times are decoded in UTC
+// and nil slices and maps are decoded as empty ones.
+
+// EncodeValue returns the binary encoding of a value, which must be nil, have one of the go types which
+// Kind.ValidateValueType accepts or be a list of such values as used for multi-field keys and values.
+func EncodeValue(value interface{}) ([]byte, error) {
+	return appendValue(nil, value)
+}
+
+// DecodeValue decodes a value encoded with EncodeValue.
+func DecodeValue(bz []byte) (interface{}, error) {
+	d := &valueDecoder{buf: bz}
+	value, err := d.decode()
+	if err != nil {
+		return nil, fmt.Errorf("invalid encoded value at offset %d: %v", d.pos, err) //nolint:errorlint // false positive due to using go1.12
+	}
+	if d.pos != len(bz) {
+		return nil, fmt.Errorf("invalid encoded value: %d trailing bytes", len(bz)-d.pos)
+	}
+	return value, nil
+}
+
+// EncodeValueJSON returns the JSON encoding of a value, which must be nil, have one of the go types which
+// Kind.ValidateValueType accepts or be a list of such values as used for multi-field keys and values.
+func EncodeValueJSON(value interface{}) (json.RawMessage, error) {
+	v, err := toValueJSON(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// DecodeValueJSON decodes a value encoded with EncodeValueJSON.
+func DecodeValueJSON(bz json.RawMessage) (interface{}, error) {
+	return fromValueJSON(bz)
+}
+
+// valueKind returns the kind whose tag is used to encode the go type of value. It returns InvalidKind for
+// null values and an error for unsupported go types.
+func valueKind(value interface{}) (Kind, error) {
+	switch value.(type) {
+	case nil:
+		return InvalidKind, nil
+	case []interface{}:
+		return ListKind, nil
+	case map[interface{}]interface{}:
+		return MapKind, nil
+	case OneOfValue:
+		return OneOfKind, nil
+	}
+
+	kind := KindForGoValue(value)
+	if kind == InvalidKind {
+		return InvalidKind, fmt.Errorf("unsupported value type %T", value)
+	}
+	return kind, nil
+}
+
+func appendValue(buf []byte, value interface{}) ([]byte, error) {
+	kind, err := valueKind(value)
+	if err != nil {
+		return nil, err
+	}
+	buf = append(buf, byte(kind))
+
+	switch v := value.(type) {
+	case nil:
+	case string:
+		buf = appendBytes(buf, []byte(v))
+	case []byte:
+		buf = appendBytes(buf, v)
+	case json.RawMessage:
+		buf = appendBytes(buf, v)
+	case int8:
+		buf = append(buf, byte(v))
+	case uint8:
+		buf = append(buf, v)
+	case bool:
+		if v {
+			buf = append(buf, 1)
+		} else {
+			buf = append(buf, 0)
+		}
+	case int16:
+		buf = appendVarint(buf, int64(v))
+	case int32:
+		buf = appendVarint(buf, int64(v))
+	case int64:
+		buf = appendVarint(buf, v)
+	case time.Duration:
+		buf = appendVarint(buf, int64(v))
+	case uint16:
+		buf = appendUvarint(buf, uint64(v))
+	case uint32:
+		buf = appendUvarint(buf, uint64(v))
+	case uint64:
+		buf = appendUvarint(buf, v)
+	case float32:
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], math.Float32bits(v))
+		buf = append(buf, b[:]...)
+	case float64:
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], math.Float64bits(v))
+		buf = append(buf, b[:]...)
+	case time.Time:
+		buf = appendVarint(buf, v.Unix())
+		buf = appendUvarint(buf, uint64(v.Nanosecond()))
+	case []interface{}:
+		buf = appendUvarint(buf, uint64(len(v)))
+		for _, elem := range v {
+			if buf, err = appendValue(buf, elem); err != nil {
+				return nil, err
+			}
+		}
+	case map[interface{}]interface{}:
+		entries, err := sortedMapEntries(v)
+		if err != nil {
+			return nil, err
+		}
+		buf = appendUvarint(buf, uint64(len(entries)))
+		for _, entry := range entries {
+			buf = append(buf, entry.key...)
+			if buf, err = appendValue(buf, entry.value); err != nil {
+				return nil, err
+			}
+		}
+	case OneOfValue:
+		buf = appendBytes(buf, []byte(v.Case))
+		if buf, err = appendValue(buf, v.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf, nil
+}
+
+func appendBytes(buf, bz []byte) []byte {
+	buf = appendUvarint(buf, uint64(len(bz)))
+	return append(buf, bz...)
+}
+
+func appendVarint(buf []byte, x int64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(b[:], x)
+	return append(buf, b[:n]...)
+}
+
+func appendUvarint(buf []byte, x uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], x)
+	return append(buf, b[:n]...)
+}
+
+// mapEntry is a map entry whose key has been encoded.
+type mapEntry struct {
+	key   []byte
+	value interface{}
+}
+
+// sortedMapEntries returns the entries of the map sorted by the binary encoding of their keys.
+func sortedMapEntries(m map[interface{}]interface{}) ([]mapEntry, error) {
+	entries := make([]mapEntry, 0, len(m))
+	for k, v := range m {
+		if !isMapKeyKind(KindForGoValue(k)) {
+			return nil, fmt.Errorf("unsupported map key type %T", k)
+		}
+		key, err := appendValue(nil, k)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, mapEntry{key: key, value: v})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+	return entries, nil
+}
+
+// isMapKeyKind returns true if values tagged with the kind can be used as map keys, meaning that their go
+// types are comparable.
+func isMapKeyKind(kind Kind) bool {
+	switch kind {
+	case InvalidKind, BytesKind, JSONKind, ListKind, MapKind, OneOfKind:
+		return false
+	default:
+		return true
+	}
+}
+
+// valueDecoder decodes binary encoded values from buf starting at pos.
+type valueDecoder struct {
+	buf []byte
+	pos int
+}
+
+func (d *valueDecoder) decode() (interface{}, error) {
+	tag, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	kind := Kind(tag)
+	switch kind {
+	case InvalidKind:
+		return nil, nil
+	case StringKind:
+		bz, err := d.readBytes()
+		return string(bz), err
+	case BytesKind:
+		return d.readBytes()
+	case JSONKind:
+		bz, err := d.readBytes()
+		return json.RawMessage(bz), err
+	case Int8Kind:
+		b, err := d.readByte()
+		return int8(b), err
+	case Uint8Kind:
+		return d.readByte()
+	case BoolKind:
+		b, err := d.readByte()
+		if err == nil && b > 1 {
+			err = fmt.Errorf("invalid bool %d", b)
+		}
+		return b == 1, err
+	case Int16Kind:
+		x, err := d.readVarint(math.MinInt16, math.MaxInt16)
+		return int16(x), err
+	case Int32Kind:
+		x, err := d.readVarint(math.MinInt32, math.MaxInt32)
+		return int32(x), err
+	case Int64Kind:
+		return d.readVarint(math.MinInt64, math.MaxInt64)
+	case DurationKind:
+		x, err := d.readVarint(math.MinInt64, math.MaxInt64)
+		return time.Duration(x), err
+	case Uint16Kind:
+		x, err := d.readUvarint(math.MaxUint16)
+		return uint16(x), err
+	case Uint32Kind:
+		x, err := d.readUvarint(math.MaxUint32)
+		return uint32(x), err
+	case Uint64Kind:
+		return d.readUvarint(math.MaxUint64)
+	case Float32Kind:
+		bz, err := d.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(bz)), nil
+	case Float64Kind:
+		bz, err := d.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(bz)), nil
+	case TimeKind:
+		secs, err := d.readVarint(math.MinInt64, math.MaxInt64)
+		if err != nil {
+			return nil, err
+		}
+		nanos, err := d.readUvarint(999999999)
+		if err != nil {
+			return nil, err
+		}
+		return time.Unix(secs, int64(nanos)).UTC(), nil
+	case ListKind:
+		n, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		list := make([]interface{}, n)
+		for i := range list {
+			if list[i], err = d.decode(); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case MapKind:
+		n, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[interface{}]interface{}, n)
+		for i := 0; i < n; i++ {
+			if d.pos < len(d.buf) && !isMapKeyKind(Kind(d.buf[d.pos])) {
+				return nil, fmt.Errorf("unsupported map key kind %s", Kind(d.buf[d.pos]))
+			}
+			key, err := d.decode()
+			if err != nil {
+				return nil, err
+			}
+			if m[key], err = d.decode(); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case OneOfKind:
+		c, err := d.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		return OneOfValue{Case: string(c), Value: value}, nil
+	default:
+		return nil, fmt.Errorf("unknown value tag %d", tag)
+	}
+}
+
+func (d *valueDecoder) read(n int) ([]byte, error) {
+	if n > len(d.buf)-d.pos {
+		return nil, errors.New("unexpected end of input")
+	}
+	bz := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return bz, nil
+}
+
+func (d *valueDecoder) readByte() (byte, error) {
+	bz, err := d.read(1)
+	if err != nil {
+		return 0, err
+	}
+	return bz[0], nil
+}
+
+func (d *valueDecoder) readBytes() ([]byte, error) {
+	n, err := d.readLength()
+	if err != nil {
+		return nil, err
+	}
+	bz, err := d.read(n)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{}, bz...), nil
+}
+
+// readLength reads a uvarint length which cannot be greater than the number of remaining bytes, as every
+// element takes at least one byte.
+func (d *valueDecoder) readLength() (int, error) {
+	n, err := d.readUvarint(uint64(len(d.buf) - d.pos))
+	return int(n), err
+}
+
+func (d *valueDecoder) readVarint(min, max int64) (int64, error) {
+	x, n := binary.Varint(d.buf[d.pos:])
+	if n <= 0 {
+		return 0, errors.New("invalid varint")
+	}
+	if x < min || x > max {
+		return 0, fmt.Errorf("varint %d out of range", x)
+	}
+	d.pos += n
+	return x, nil
+}
+
+func (d *valueDecoder) readUvarint(max uint64) (uint64, error) {
+	x, n := binary.Uvarint(d.buf[d.pos:])
+	if n <= 0 {
+		return 0, errors.New("invalid uvarint")
+	}
+	if x > max {
+		return 0, fmt.Errorf("uvarint %d out of range", x)
+	}
+	d.pos += n
+	return x, nil
+}
+
+// oneOfValueJSON is the JSON representation of the payload of a oneof value.
+type oneOfValueJSON struct {
+	Case  string          `json:"case"`
+	Value json.RawMessage `json:"value"`
+}
+
+// toValueJSON converts a value to a value which json.Marshal encodes in the JSON value encoding.
+func toValueJSON(value interface{}) (interface{}, error) {
+	kind, err := valueKind(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload interface{}
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case int64:
+		payload = strconv.FormatInt(v, 10)
+	case uint64:
+		payload = strconv.FormatUint(v, 10)
+	case time.Duration:
+		payload = strconv.FormatInt(int64(v), 10)
+	case []byte:
+		// nil bytes are encoded like empty bytes rather than as null
+		payload = append([]byte{}, v...)
+	case float32:
+		payload = floatJSON(float64(v), 32)
+	case float64:
+		payload = floatJSON(v, 64)
+	case time.Time:
+		if year := v.UTC().Year(); year < 0 || year > 9999 {
+			return nil, fmt.Errorf("time %s is outside of the range of RFC 3339", v)
+		}
+		payload = v.UTC().Format(time.RFC3339Nano)
+	case json.RawMessage:
+		payload = string(v)
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, elem := range v {
+			if list[i], err = toValueJSON(elem); err != nil {
+				return nil, err
+			}
+		}
+		payload = list
+	case map[interface{}]interface{}:
+		entries, err := sortedMapEntries(v)
+		if err != nil {
+			return nil, err
+		}
+		pairs := make([][2]interface{}, len(entries))
+		for i, entry := range entries {
+			key, err := DecodeValue(entry.key)
+			if err != nil {
+				return nil, err
+			}
+			if pairs[i][0], err = toValueJSON(key); err != nil {
+				return nil, err
+			}
+			if pairs[i][1], err = toValueJSON(entry.value); err != nil {
+				return nil, err
+			}
+		}
+		payload = pairs
+	case OneOfValue:
+		inner, err := toValueJSON(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		bz, err := json.Marshal(inner)
+		if err != nil {
+			return nil, err
+		}
+		payload = oneOfValueJSON{Case: v.Case, Value: bz}
+	default:
+		// strings, bytes, bools and small integers are encoded as is
+		payload = v
+	}
+
+	return map[string]interface{}{kind.String(): payload}, nil
+}
+
+// floatJSON returns a float as a json.Number or as a string if it is infinite or NaN.
+func floatJSON(f float64, bitSize int) interface{} {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	default:
+		return json.Number(strconv.FormatFloat(f, 'g', -1, bitSize))
+	}
+}
+
+func fromValueJSON(bz json.RawMessage) (interface{}, error) {
+	if string(bytes.TrimSpace(bz)) == "null" {
+		return nil, nil
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(bz, &obj); err != nil {
+		return nil, fmt.Errorf("invalid JSON value: %v", err) //nolint:errorlint // false positive due to using go1.12
+	}
+	if len(obj) != 1 {
+		return nil, fmt.Errorf("expected JSON value object with a single kind, got %d properties", len(obj))
+	}
+
+	for name, payload := range obj {
+		var kind Kind
+		if err := kind.UnmarshalJSON([]byte(strconv.Quote(name))); err != nil {
+			return nil, err
+		}
+		value, err := fromValueJSONPayload(kind, payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON %s value: %v", kind, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		return value, nil
+	}
+	return nil, nil
+}
+
+func fromValueJSONPayload(kind Kind, payload json.RawMessage) (interface{}, error) {
+	var ptr interface{}
+	switch kind {
+	case StringKind:
+		ptr = new(string)
+	case BytesKind:
+		ptr = new([]byte)
+	case Int8Kind:
+		ptr = new(int8)
+	case Uint8Kind:
+		ptr = new(uint8)
+	case Int16Kind:
+		ptr = new(int16)
+	case Uint16Kind:
+		ptr = new(uint16)
+	case Int32Kind:
+		ptr = new(int32)
+	case Uint32Kind:
+		ptr = new(uint32)
+	case BoolKind:
+		ptr = new(bool)
+	case Int64Kind, DurationKind:
+		var s string
+		if err := json.Unmarshal(payload, &s); err != nil {
+			return nil, err
+		}
+		x, err := strconv.ParseInt(s, 10, 64)
+		if kind == DurationKind {
+			return time.Duration(x), err
+		}
+		return x, err
+	case Uint64Kind:
+		var s string
+		if err := json.Unmarshal(payload, &s); err != nil {
+			return nil, err
+		}
+		return strconv.ParseUint(s, 10, 64)
+	case Float32Kind, Float64Kind:
+		bitSize := 64
+		if kind == Float32Kind {
+			bitSize = 32
+		}
+		var s string
+		if err := json.Unmarshal(payload, &s); err != nil {
+			// finite floats are JSON numbers
+			s = string(payload)
+		} else if s != "NaN" && s != "+Inf" && s != "-Inf" {
+			return nil, fmt.Errorf("invalid float %q", s)
+		}
+		f, err := strconv.ParseFloat(s, bitSize)
+		if kind == Float32Kind {
+			return float32(f), err
+		}
+		return f, err
+	case TimeKind:
+		var s string
+		if err := json.Unmarshal(payload, &s); err != nil {
+			return nil, err
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		return t.UTC(), err
+	case JSONKind:
+		var s string
+		if err := json.Unmarshal(payload, &s); err != nil {
+			return nil, err
+		}
+		return json.RawMessage(s), nil
+	case ListKind:
+		var elems []json.RawMessage
+		if err := json.Unmarshal(payload, &elems); err != nil {
+			return nil, err
+		}
+		list := make([]interface{}, len(elems))
+		for i, elem := range elems {
+			var err error
+			if list[i], err = fromValueJSON(elem); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case MapKind:
+		var pairs [][2]json.RawMessage
+		if err := json.Unmarshal(payload, &pairs); err != nil {
+			return nil, err
+		}
+		m := make(map[interface{}]interface{}, len(pairs))
+		for _, pair := range pairs {
+			key, err := fromValueJSON(pair[0])
+			if err != nil {
+				return nil, err
+			}
+			if !isMapKeyKind(KindForGoValue(key)) {
+				return nil, fmt.Errorf("unsupported map key type %T", key)
+			}
+			if m[key], err = fromValueJSON(pair[1]); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case OneOfKind:
+		var v oneOfValueJSON
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return nil, err
+		}
+		value, err := fromValueJSON(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return OneOfValue{Case: v.Case, Value: value}, nil
+	default:
+		return nil, fmt.Errorf("unsupported kind %s", kind)
+	}
+
+	if err := json.Unmarshal(payload, ptr); err != nil {
+		return nil, err
+	}
+	switch p := ptr.(type) {
+	case *string:
+		return *p, nil
+	case *[]byte:
+		return *p, nil
+	case *int8:
+		return *p, nil
+	case *uint8:
+		return *p, nil
+	case *int16:
+		return *p, nil
+	case *uint16:
+		return *p, nil
+	case *int32:
+		return *p, nil
+	case *uint32:
+		return *p, nil
+	default:
+		return *(p.(*bool)), nil
+	}
+}
@@ -0,0 +1,187 @@
+package schema
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testCodecValues = []struct {
+	name  string
+	value interface{}
+	json  string
+}{
+	{"null", nil, `null`},
+	{"string", "hello", `{"string":"hello"}`},
+	{"bytes", []byte{1, 2, 3}, `{"bytes":"AQID"}`},
+	{"int8", int8(-8), `{"int8":-8}`},
+	{"uint8", uint8(8), `{"uint8":8}`},
+	{"int16", int16(-16), `{"int16":-16}`},
+	{"uint16", uint16(16), `{"uint16":16}`},
+	{"int32", int32(math.MinInt32), `{"int32":-2147483648}`},
+	{"uint32", uint32(math.MaxUint32), `{"uint32":4294967295}`},
+	{"int64", int64(math.MinInt64), `{"int64":"-9223372036854775808"}`},
+	{"uint64", uint64(math.MaxUint64), `{"uint64":"18446744073709551615"}`},
+	{"bool", true, `{"bool":true}`},
+	{"float32", float32(1.5), `{"float32":1.5}`},
+	{"float64", 0.1, `{"float64":0.1}`},
+	{"float64 inf", math.Inf(-1), `{"float64":"-Inf"}`},
+	{"time", time.Date(2024, 7, 1, 12, 30, 0, 123456789, time.UTC), `{"time":"2024-07-01T12:30:00.123456789Z"}`},
+	{"time before epoch", time.Date(1969, 12, 31, 23, 59, 59, 5, time.UTC), `{"time":"1969-12-31T23:59:59.000000005Z"}`},
+	{"duration", -time.Hour, `{"duration":"-3600000000000"}`},
+	{"json", json.RawMessage(`{"a": [1, 2]}`), `{"json":"{\"a\": [1, 2]}"}`},
+	{"list", []interface{}{"a", nil, []interface{}{int32(1)}}, `{"list":[{"string":"a"},null,{"list":[{"int32":1}]}]}`},
+	{"empty list", []interface{}{}, `{"list":[]}`},
+	{
+		"map",
+		map[interface{}]interface{}{"b": uint64(2), "a": nil},
+		`{"map":[[{"string":"a"},null],[{"string":"b"},{"uint64":"2"}]]}`,
+	},
+	{
+		"oneof",
+		OneOfValue{Case: "point", Value: []interface{}{int32(1), int32(2)}},
+		`{"oneof":{"case":"point","value":{"list":[{"int32":1},{"int32":2}]}}}`,
+	},
+}
+
+func TestEncodeValue(t *testing.T) {
+	for _, tt := range testCodecValues {
+		t.Run(tt.name, func(t *testing.T) {
+			bz, err := EncodeValue(tt.value)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			decoded, err := DecodeValue(bz)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tt.value, decoded) {
+				t.Fatalf("expected %#v, got %#v", tt.value, decoded)
+			}
+			if kind, _ := valueKind(tt.value); bz[0] != byte(kind) {
+				t.Fatalf("expected tag %d, got %d", kind, bz[0])
+			}
+		})
+	}
+}
+
+func TestEncodeValueJSON(t *testing.T) {
+	for _, tt := range testCodecValues {
+		t.Run(tt.name, func(t *testing.T) {
+			bz, err := EncodeValueJSON(tt.value)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(bz) != tt.json {
+				t.Fatalf("expected %s, got %s", tt.json, bz)
+			}
+			decoded, err := DecodeValueJSON(bz)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tt.value, decoded) {
+				t.Fatalf("expected %#v, got %#v", tt.value, decoded)
+			}
+		})
+	}
+}
+
+func TestEncodeValue_Canonical(t *testing.T) {
+	m := map[interface{}]interface{}{}
+	for i := int32(0); i < 100; i++ {
+		m[i] = i
+	}
+	expected, err := EncodeValue(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedJSON, err := EncodeValueJSON(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		bz, _ := EncodeValue(m)
+		if string(bz) != string(expected) {
+			t.Fatalf("map encoding isn't deterministic")
+		}
+		bz, _ = EncodeValueJSON(m)
+		if string(bz) != string(expectedJSON) {
+			t.Fatalf("map JSON encoding isn't deterministic")
+		}
+	}
+}
+
+func TestEncodeValue_Errors(t *testing.T) {
+	invalid := []interface{}{
+		struct{}{},
+		[]string{"a"},
+		[]interface{}{int(1)},
+		map[interface{}]interface{}{[2]byte{}: "a"},
+		OneOfValue{Case: "a", Value: uint(1)},
+	}
+	for _, value := range invalid {
+		if _, err := EncodeValue(value); err == nil {
+			t.Errorf("expected error encoding %#v", value)
+		}
+		if _, err := EncodeValueJSON(value); err == nil {
+			t.Errorf("expected JSON error encoding %#v", value)
+		}
+	}
+
+	if _, err := EncodeValueJSON(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Errorf("expected JSON error encoding a time after year 9999")
+	}
+}
+
+func TestDecodeValue_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		bz   []byte
+		err  string
+	}{
+		{"empty", nil, "unexpected end of input"},
+		{"unknown tag", []byte{100}, "unknown value tag 100"},
+		{"trailing bytes", []byte{byte(BoolKind), 1, 0}, "1 trailing bytes"},
+		{"invalid bool", []byte{byte(BoolKind), 2}, "invalid bool 2"},
+		{"truncated string", []byte{byte(StringKind), 5, 'a'}, "out of range"},
+		{"int16 out of range", append([]byte{byte(Int16Kind)}, appendVarint(nil, math.MaxInt16+1)...), "out of range"},
+		{"truncated float", []byte{byte(Float64Kind), 0, 0}, "unexpected end of input"},
+		{"unhashable map key", []byte{byte(MapKind), 1, byte(BytesKind), 0, 0}, "unsupported map key kind bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeValue(tt.bz)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestDecodeValueJSON_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		err  string
+	}{
+		{"not an object", `"a"`, "invalid JSON value"},
+		{"multiple kinds", `{"string":"a","bool":true}`, "single kind"},
+		{"unknown kind", `{"foo":1}`, "unknown kind"},
+		{"int8 out of range", `{"int8":128}`, "invalid JSON int8 value"},
+		{"int64 as number", `{"int64":1}`, "invalid JSON int64 value"},
+		{"invalid float", `{"float64":"1"}`, "invalid float"},
+		{"invalid time", `{"time":"yesterday"}`, "invalid JSON time value"},
+		{"unhashable map key", `{"map":[[{"list":[]},null]]}`, "unsupported map key type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeValueJSON(json.RawMessage(tt.json))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
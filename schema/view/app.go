@@ -0,0 +1,28 @@
+// Package view defines interfaces for viewing the data stored in an indexer target or an app.
+package view
+
+// AppData is an interface which indexer data targets can implement to allow their app data including
+// state, blocks, transactions and events to be queried. An app's state and event store can also implement
+// this interface to provide an authoritative source of data for comparing with indexed data.
+type AppData interface {
+	// BlockNum indicates the last block that was persisted. It should be 0 if the target has no data
+	// stored yet.
+	BlockNum() (uint64, error)
+
+	// AppState returns the app state. If the view doesn't persist app state, nil should be returned.
+	AppState() AppState
+}
+
+// AppState defines an interface for things that represent application state in schema format.
+type AppState interface {
+	// GetModule returns the module state for the given module name. If the module does not exist, nil and no error
+	// should be returned.
+	GetModule(moduleName string) (ModuleState, error)
+
+	// Modules iterates over all the module state instances in the app. If there is an error getting a module state,
+	// modState may be nil and err will be non-nil.
+	Modules(f func(modState ModuleState, err error) bool)
+
+	// NumModules returns the number of modules in the app.
+	NumModules() (int, error)
+}
@@ -0,0 +1,284 @@
+package view
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/decoding"
+)
+
+// DiffReport describes the differences between the state of an indexer and the state of the node it indexes.
+type DiffReport struct {
+	// Missing are the objects in the node state which are missing in the indexer or which the indexer marks as
+	// deleted.
+	Missing []ObjectDiff
+
+	// Extra are the objects in the indexer which aren't in the node state. Deleted objects retained by the
+	// indexer aren't reported.
+	Extra []ObjectDiff
+
+	// Mismatched are the objects whose value in the indexer differs from their value in the node state.
+	Mismatched []ObjectDiff
+}
+
+// Empty returns true if the report doesn't contain any difference.
+func (r DiffReport) Empty() bool {
+	return len(r.Missing) == 0 && len(r.Extra) == 0 && len(r.Mismatched) == 0
+}
+
+// ObjectDiff describes an object which differs between the indexer and the node state.
+type ObjectDiff struct {
+	// ModuleName is the name of the module of the object.
+	ModuleName string
+
+	// TypeName is the name of the object type of the object.
+	TypeName string
+
+	// Key is the key of the object.
+	Key interface{}
+
+	// NodeValue is the value of the object in the node state. It is nil for extra objects.
+	NodeValue interface{}
+
+	// IndexedValue is the value of the object in the indexer. It is nil for missing objects.
+	IndexedValue interface{}
+
+	// Fields are the names of the value fields which differ for mismatched objects.
+	Fields []string
+}
+
+// Compare compares the state of the indexer in data with the current node state, which is re-decoded from the
+// key-value pairs of source with the module codecs of resolver. Only the modules which have a KVDecoder are
+// compared and the decoded node state of each module is held in memory while it is being compared, so source
+// must not change during the comparison.
+func Compare(data AppData, source decoding.SyncSource, resolver decoding.DecoderResolver) (DiffReport, error) {
+	var report DiffReport
+	state := data.AppState()
+	if state == nil {
+		return report, fmt.Errorf("app state isn't available")
+	}
+
+	err := resolver.IterateAll(func(moduleName string, cdc schema.ModuleCodec) error {
+		if cdc.KVDecoder == nil {
+			return nil
+		}
+
+		nodeState := newModuleObjects()
+		err := decoding.Sync(appdata.Listener{
+			OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+				return nodeState.apply(cdc.Schema, data.Updates)
+			},
+		}, source, singleModuleResolver{moduleName, cdc}, decoding.SyncOptions{})
+		if err != nil {
+			return err
+		}
+
+		modState, err := state.GetModule(moduleName)
+		if err != nil {
+			return err
+		}
+		return report.compareModule(moduleName, cdc.Schema, nodeState, modState)
+	})
+	return report, err
+}
+
+func (r *DiffReport) compareModule(moduleName string, modSchema schema.ModuleSchema, nodeState *moduleObjects, modState ModuleState) error {
+	var err error
+	modSchema.ObjectTypes(func(objectType schema.ObjectType) bool {
+		var coll ObjectCollection
+		if modState != nil {
+			coll, err = modState.GetObjectCollection(objectType.Name)
+			if err != nil {
+				return false
+			}
+		}
+		err = r.compareCollection(moduleName, objectType, nodeState.objects[objectType.Name], coll)
+		return err == nil
+	})
+	return err
+}
+
+func (r *DiffReport) compareCollection(moduleName string, objectType schema.ObjectType, nodeObjects *objectSet, coll ObjectCollection) error {
+	if nodeObjects == nil {
+		nodeObjects = &objectSet{byKey: map[string]int{}}
+	}
+
+	for _, update := range nodeObjects.updates {
+		if update.TypeName == "" {
+			// deleted while decoding
+			continue
+		}
+		diff := ObjectDiff{ModuleName: moduleName, TypeName: objectType.Name, Key: update.Key, NodeValue: update.Value}
+		if coll == nil {
+			r.Missing = append(r.Missing, diff)
+			continue
+		}
+
+		indexed, found, err := coll.GetObject(update.Key)
+		if err != nil {
+			return err
+		}
+		if !found || indexed.Delete {
+			r.Missing = append(r.Missing, diff)
+			continue
+		}
+
+		fields, err := mismatchedFields(objectType, update, indexed)
+		if err != nil {
+			return err
+		}
+		if len(fields) != 0 {
+			diff.IndexedValue = indexed.Value
+			diff.Fields = fields
+			r.Mismatched = append(r.Mismatched, diff)
+		}
+	}
+
+	if coll == nil {
+		return nil
+	}
+	var err error
+	coll.AllState(func(indexed schema.ObjectUpdate, iterErr error) bool {
+		if iterErr != nil {
+			err = iterErr
+			return false
+		}
+		if indexed.Delete {
+			return true
+		}
+
+		var key string
+		key, err = objectKey(objectType, indexed.Key)
+		if err != nil {
+			return false
+		}
+		if _, ok := nodeObjects.byKey[key]; !ok {
+			r.Extra = append(r.Extra, ObjectDiff{
+				ModuleName:   moduleName,
+				TypeName:     objectType.Name,
+				Key:          indexed.Key,
+				IndexedValue: indexed.Value,
+			})
+		}
+		return true
+	})
+	return err
+}
+
+// mismatchedFields returns the names of the value fields which differ between the node and indexed objects.
+func mismatchedFields(objectType schema.ObjectType, node, indexed schema.ObjectUpdate) ([]string, error) {
+	var fields []string
+	for _, field := range objectType.ValueFields {
+		x, err := FieldValue(objectType, node, field.Name)
+		if err != nil {
+			return nil, err
+		}
+		y, err := FieldValue(objectType, indexed, field.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !valuesEqual(field, x, y) {
+			fields = append(fields, field.Name)
+		}
+	}
+	return fields, nil
+}
+
+func valuesEqual(field schema.Field, x, y interface{}) bool {
+	switch field.Kind {
+	case schema.ListKind, schema.MapKind:
+		return reflect.DeepEqual(x, y)
+	default:
+		return CompareValues(field, x, y) == 0
+	}
+}
+
+// moduleObjects are the objects of a module by object type name.
+type moduleObjects struct {
+	objects map[string]*objectSet
+}
+
+// objectSet are the objects of an object type in the order in which they were first decoded, with their
+// index by encoded key.
+type objectSet struct {
+	updates []schema.ObjectUpdate
+	byKey   map[string]int
+}
+
+func newModuleObjects() *moduleObjects {
+	return &moduleObjects{objects: map[string]*objectSet{}}
+}
+
+func (m *moduleObjects) apply(modSchema schema.ModuleSchema, updates []schema.ObjectUpdate) error {
+	for _, update := range updates {
+		typ, ok := modSchema.LookupType(update.TypeName)
+		if !ok {
+			return fmt.Errorf("unknown object type %q", update.TypeName)
+		}
+		objectType, ok := typ.(schema.ObjectType)
+		if !ok {
+			return fmt.Errorf("type %q isn't an object type", update.TypeName)
+		}
+		if _, ok := update.Value.(schema.ValueUpdates); ok {
+			return fmt.Errorf("can't compare partial updates of object type %q", update.TypeName)
+		}
+
+		key, err := objectKey(objectType, update.Key)
+		if err != nil {
+			return err
+		}
+
+		set := m.objects[update.TypeName]
+		if set == nil {
+			set = &objectSet{byKey: map[string]int{}}
+			m.objects[update.TypeName] = set
+		}
+		if i, ok := set.byKey[key]; ok {
+			if update.Delete {
+				// keep the order of the remaining objects and leave a tombstone which is skipped when comparing
+				set.updates[i] = schema.ObjectUpdate{}
+				delete(set.byKey, key)
+				continue
+			}
+			set.updates[i] = update
+			continue
+		}
+		if !update.Delete {
+			set.byKey[key] = len(set.updates)
+			set.updates = append(set.updates, update)
+		}
+	}
+	return nil
+}
+
+// objectKey encodes the key of an object so that it can be used as a map key. Singletons have an empty key.
+func objectKey(objectType schema.ObjectType, key interface{}) (string, error) {
+	if len(objectType.KeyFields) == 0 {
+		return "", nil
+	}
+	bz, err := json.Marshal(key)
+	if err != nil {
+		return "", fmt.Errorf("can't encode key of object type %q: %v", objectType.Name, err) //nolint:errorlint // false positive due to using go1.12
+	}
+	return string(bz), nil
+}
+
+// singleModuleResolver resolves the codec of a single module.
+type singleModuleResolver struct {
+	moduleName string
+	cdc        schema.ModuleCodec
+}
+
+func (r singleModuleResolver) IterateAll(f func(string, schema.ModuleCodec) error) error {
+	return f(r.moduleName, r.cdc)
+}
+
+func (r singleModuleResolver) LookupDecoder(moduleName string) (schema.ModuleCodec, bool, error) {
+	if moduleName != r.moduleName {
+		return schema.ModuleCodec{}, false, nil
+	}
+	return r.cdc, true, nil
+}
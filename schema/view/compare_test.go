@@ -0,0 +1,191 @@
+package view
+
+import (
+	"reflect"
+	"testing"
+
+	"cosmossdk.io/schema"
+)
+
+type testKVSource map[string][][2][]byte
+
+func (s testKVSource) IterateAllKVPairs(moduleName string, fn func(key, value []byte) error) error {
+	for _, kv := range s[moduleName] {
+		if err := fn(kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type testResolver map[string]schema.ModuleCodec
+
+func (r testResolver) IterateAll(f func(string, schema.ModuleCodec) error) error {
+	for name, cdc := range r {
+		if err := f(name, cdc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r testResolver) LookupDecoder(moduleName string) (schema.ModuleCodec, bool, error) {
+	cdc, ok := r[moduleName]
+	return cdc, ok, nil
+}
+
+// testBankCodec decodes balances whose key is an address byte followed by the denom and whose value is the
+// amount, deleting the balance if the amount is empty.
+func testBankCodec(t *testing.T) schema.ModuleCodec {
+	t.Helper()
+	modSchema, err := schema.NewModuleSchema([]schema.ObjectType{testBalanceType})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema.ModuleCodec{
+		Schema: modSchema,
+		KVDecoder: func(update schema.KVPairUpdate) ([]schema.ObjectUpdate, error) {
+			key := []interface{}{update.Key[:1], string(update.Key[1:])}
+			if len(update.Value) == 0 {
+				return []schema.ObjectUpdate{{TypeName: "balances", Key: key, Delete: true}}, nil
+			}
+			return []schema.ObjectUpdate{{TypeName: "balances", Key: key, Value: []interface{}{string(update.Value), nil}}}, nil
+		},
+	}
+}
+
+type testIndexedState map[string]testMapCollection
+
+func (s testIndexedState) BlockNum() (uint64, error) { return 1, nil }
+
+func (s testIndexedState) AppState() AppState { return s }
+
+func (s testIndexedState) GetModule(name string) (ModuleState, error) {
+	coll, ok := s[name]
+	if !ok {
+		return nil, nil
+	}
+	return testModuleState{name: name, coll: coll}, nil
+}
+
+func (s testIndexedState) Modules(f func(ModuleState, error) bool) {
+	for name, coll := range s {
+		if !f(testModuleState{name: name, coll: coll}, nil) {
+			return
+		}
+	}
+}
+
+func (s testIndexedState) NumModules() (int, error) { return len(s), nil }
+
+type testModuleState struct {
+	name string
+	coll testMapCollection
+}
+
+func (m testModuleState) ModuleName() string { return m.name }
+
+func (m testModuleState) ModuleSchema() schema.ModuleSchema {
+	modSchema, _ := schema.NewModuleSchema([]schema.ObjectType{testBalanceType})
+	return modSchema
+}
+
+func (m testModuleState) GetObjectCollection(name string) (ObjectCollection, error) {
+	if name != testBalanceType.Name {
+		return nil, nil
+	}
+	return m.coll, nil
+}
+
+func (m testModuleState) ObjectCollections(f func(ObjectCollection, error) bool) { f(m.coll, nil) }
+
+func (m testModuleState) NumObjectCollections() (int, error) { return 1, nil }
+
+// testMapCollection is a collection of balances which supports GetObject.
+type testMapCollection struct {
+	testCollection
+}
+
+func (c testMapCollection) GetObject(key interface{}) (schema.ObjectUpdate, bool, error) {
+	for _, obj := range c.objects {
+		if reflect.DeepEqual(obj.Key, key) {
+			return obj, true, nil
+		}
+	}
+	return schema.ObjectUpdate{}, false, nil
+}
+
+func TestCompare(t *testing.T) {
+	source := testKVSource{"bank": {
+		{[]byte("\x01atom"), []byte("10")},
+		{[]byte("\x01stake"), []byte("20")},
+		{[]byte("\x02atom"), []byte("30")},
+		{[]byte("\x03atom"), []byte("40")},
+		{[]byte("\x03atom"), nil},
+		{[]byte("\x04atom"), []byte("50")},
+	}}
+	resolver := testResolver{"bank": testBankCodec(t)}
+
+	deletedLive := balance(4, "atom", "50", nil)
+	deletedLive.Delete = true
+	deletedGone := balance(6, "atom", "70", nil)
+	deletedGone.Delete = true
+	indexed := testIndexedState{"bank": testMapCollection{testCollection{objects: []schema.ObjectUpdate{
+		balance(1, "atom", "10", nil),
+		balance(1, "stake", "21", nil),
+		balance(5, "atom", "60", nil),
+		deletedLive,
+		deletedGone,
+	}}}}
+
+	report, err := Compare(indexed, source, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := DiffReport{
+		Missing: []ObjectDiff{
+			{ModuleName: "bank", TypeName: "balances", Key: []interface{}{[]byte{2}, "atom"}, NodeValue: []interface{}{"30", nil}},
+			{ModuleName: "bank", TypeName: "balances", Key: []interface{}{[]byte{4}, "atom"}, NodeValue: []interface{}{"50", nil}},
+		},
+		Extra: []ObjectDiff{
+			{ModuleName: "bank", TypeName: "balances", Key: []interface{}{[]byte{5}, "atom"}, IndexedValue: []interface{}{"60", nil}},
+		},
+		Mismatched: []ObjectDiff{
+			{
+				ModuleName: "bank", TypeName: "balances", Key: []interface{}{[]byte{1}, "stake"},
+				NodeValue: []interface{}{"20", nil}, IndexedValue: []interface{}{"21", nil}, Fields: []string{"amount"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Fatalf("expected report %+v, got %+v", expected, report)
+	}
+	if report.Empty() {
+		t.Fatal("expected report not to be empty")
+	}
+
+	t.Run("module not indexed", func(t *testing.T) {
+		report, err := Compare(testIndexedState{}, source, resolver)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Missing) != 4 || len(report.Extra) != 0 || len(report.Mismatched) != 0 {
+			t.Fatalf("expected 4 missing objects, got %+v", report)
+		}
+	})
+
+	t.Run("consistent", func(t *testing.T) {
+		source := testKVSource{"bank": {{[]byte("\x01atom"), []byte("10")}}}
+		indexed := testIndexedState{"bank": testMapCollection{testCollection{objects: []schema.ObjectUpdate{
+			balance(1, "atom", "10", nil),
+		}}}}
+		report, err := Compare(indexed, source, resolver)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.Empty() {
+			t.Fatalf("expected empty report, got %+v", report)
+		}
+	})
+}
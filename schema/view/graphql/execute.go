@@ -0,0 +1,497 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"cosmossdk.io/schema/view"
+)
+
+// Request is a GraphQL request, which has the JSON encoding of the body of GraphQL HTTP POST requests.
+type Request struct {
+	// Query is the GraphQL document.
+	Query string `json:"query"`
+
+	// OperationName is the name of the operation of the document to execute, which is required if the
+	// document has multiple operations.
+	OperationName string `json:"operationName,omitempty"`
+
+	// Variables are the values of the operation's variables.
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response is the result of a GraphQL request as described by the GraphQL specification.
+type Response struct {
+	// Data is the result of the operation as JSON. It is absent if the request failed before execution and
+	// null if a non-nullable root field couldn't be resolved.
+	Data json.RawMessage `json:"data,omitempty"`
+
+	// Errors are the errors which occurred while validating or executing the request.
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+// Error is an error of a GraphQL response.
+type Error struct {
+	Message   string        `json:"message"`
+	Locations []Location    `json:"locations,omitempty"`
+	Path      []interface{} `json:"path,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Location is a position in a GraphQL document.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// execContext is the state of the execution of a request.
+type execContext struct {
+	ctx       context.Context
+	schema    *Schema
+	data      view.AppData
+	variables map[string]interface{}
+	fragments map[string]*fragmentDef
+	errors    []*Error
+}
+
+// path is the path of a field in the response, with the response keys of fields and the indexes of list elements.
+type path struct {
+	parent *path
+	key    interface{}
+}
+
+func (p *path) with(key interface{}) *path {
+	return &path{parent: p, key: key}
+}
+
+func (p *path) elems() []interface{} {
+	var elems []interface{}
+	for ; p != nil; p = p.parent {
+		elems = append([]interface{}{p.key}, elems...)
+	}
+	return elems
+}
+
+func (ec *execContext) addError(err error, loc Location, p *path) {
+	ec.errors = append(ec.errors, &Error{Message: err.Error(), Locations: []Location{loc}, Path: p.elems()})
+}
+
+// Execute executes a query against the app data. Mutations and subscriptions aren't supported. Errors which
+// occur while resolving fields are reported in the response together with the partial data.
+func (s *Schema) Execute(ctx context.Context, data view.AppData, req Request) *Response {
+	doc, err := parseDocument(req.Query)
+	if err != nil {
+		return errorResponse(err)
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if errs := s.validate(doc, op); len(errs) != 0 {
+		return &Response{Errors: errs}
+	}
+
+	variables, err := s.coerceVariables(op, req.Variables)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	ec := &execContext{ctx: ctx, schema: s, data: data, variables: variables, fragments: doc.fragments}
+	res, failed := ec.executeSelectionSet(s.query, nil, op.selections, nil)
+	resp := &Response{Errors: ec.errors}
+	if failed {
+		resp.Data = json.RawMessage("null")
+		return resp
+	}
+	if resp.Data, err = json.Marshal(res); err != nil {
+		return errorResponse(err)
+	}
+	return resp
+}
+
+func errorResponse(err error) *Response {
+	gqlErr, ok := err.(*Error) //nolint:errorlint // false positive due to using go1.12
+	if !ok {
+		gqlErr = &Error{Message: err.Error()}
+	}
+	return &Response{Errors: []*Error{gqlErr}}
+}
+
+func selectOperation(doc *document, name string) (*operation, error) {
+	var op *operation
+	if name == "" {
+		if len(doc.operations) != 1 {
+			return nil, &Error{Message: "operationName is required for documents with multiple operations"}
+		}
+		op = doc.operations[0]
+	} else {
+		for _, o := range doc.operations {
+			if o.name == name {
+				op = o
+			}
+		}
+		if op == nil {
+			return nil, &Error{Message: fmt.Sprintf("unknown operation %q", name)}
+		}
+	}
+
+	if op.typ != "query" {
+		return nil, &Error{Message: fmt.Sprintf("%s operations aren't supported", op.typ), Locations: []Location{op.loc}}
+	}
+	return op, nil
+}
+
+// coerceVariables validates the variable values of a request against the types of the operation's variables and
+// applies their default values.
+func (s *Schema) coerceVariables(op *operation, values map[string]interface{}) (map[string]interface{}, error) {
+	// values are normalized to the types decoded by json.Decoder.UseNumber, so that requests created in go code
+	// are handled like those decoded from JSON
+	if len(values) != 0 {
+		bz, err := json.Marshal(values)
+		if err != nil {
+			return nil, &Error{Message: fmt.Sprintf("invalid variables: %v", err)}
+		}
+		dec := json.NewDecoder(bytes.NewReader(bz))
+		dec.UseNumber()
+		values = nil
+		if err := dec.Decode(&values); err != nil {
+			return nil, &Error{Message: fmt.Sprintf("invalid variables: %v", err)}
+		}
+	}
+
+	res := map[string]interface{}{}
+	for _, def := range op.varDefs {
+		typ := s.inputType(def.typ)
+		value, present := values[def.name]
+		if !present && def.defaultValue != nil {
+			value, present = def.defaultValue.inputValue(nil)
+		}
+		if !present {
+			if def.typ.nonNull {
+				return nil, &Error{Message: fmt.Sprintf("variable $%s of required type %s was not provided", def.name, def.typ), Locations: []Location{def.loc}}
+			}
+			continue
+		}
+
+		if _, err := coerceInput(typ, value); err != nil {
+			return nil, &Error{Message: fmt.Sprintf("variable $%s got invalid value: %v", def.name, err), Locations: []Location{def.loc}} //nolint:errorlint // false positive due to using go1.12
+		}
+		// the uncoerced value is kept because arguments are coerced after variables are substituted
+		res[def.name] = value
+	}
+	return res, nil
+}
+
+// inputType returns the type of a type reference, which was validated to refer to an input type.
+func (s *Schema) inputType(ref *typeRef) *gqlType {
+	var t *gqlType
+	if ref.elem != nil {
+		t = listOf(s.inputType(ref.elem))
+	} else {
+		t = s.types[ref.name]
+	}
+	if ref.nonNull {
+		t = nonNull(t)
+	}
+	return t
+}
+
+// coerceInput coerces an input value, as decoded from JSON, to an input type.
+func coerceInput(t *gqlType, value interface{}) (interface{}, error) {
+	if t.kind == nonNullKind {
+		if value == nil {
+			return nil, fmt.Errorf("expected non-null value of type %s", t)
+		}
+		return coerceInput(t.ofType, value)
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	switch t.kind {
+	case listKind:
+		values, ok := value.([]interface{})
+		if !ok {
+			// single values are coerced to lists with one element
+			values = []interface{}{value}
+		}
+		list := make([]interface{}, len(values))
+		for i, v := range values {
+			var err error
+			if list[i], err = coerceInput(t.ofType, v); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case inputObjectKind:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected object of type %s, got %s", t.name, describeInput(value))
+		}
+		res := map[string]interface{}{}
+		for _, f := range t.inputFields {
+			v, ok := obj[f.name]
+			if !ok {
+				if f.typ.kind == nonNullKind {
+					return nil, fmt.Errorf("field %s.%s of required type %s was not provided", t.name, f.name, f.typ)
+				}
+				continue
+			}
+			cv, err := coerceInput(f.typ, v)
+			if err != nil {
+				return nil, fmt.Errorf("field %s.%s: %v", t.name, f.name, err) //nolint:errorlint // false positive due to using go1.12
+			}
+			res[f.name] = cv
+		}
+		for name := range obj {
+			if inputField(t, name) == nil {
+				return nil, fmt.Errorf("unknown field %q of type %s", name, t.name)
+			}
+		}
+		return res, nil
+	default:
+		return t.parse(value)
+	}
+}
+
+func inputField(t *gqlType, name string) *inputValueDef {
+	for _, f := range t.inputFields {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// coerceArguments coerces the arguments of a field. Arguments which aren't provided are absent from the result.
+func (ec *execContext) coerceArguments(defs []*inputValueDef, args []*argument) (map[string]interface{}, error) {
+	res := map[string]interface{}{}
+	for _, def := range defs {
+		var arg *argument
+		for _, a := range args {
+			if a.name == def.name {
+				arg = a
+			}
+		}
+
+		var value interface{}
+		present := false
+		if arg != nil {
+			value, present = arg.value.inputValue(ec.variables)
+		}
+		if !present {
+			if def.typ.kind == nonNullKind {
+				return nil, fmt.Errorf("argument %q of required type %s was not provided", def.name, def.typ)
+			}
+			continue
+		}
+
+		v, err := coerceInput(def.typ, value)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q has invalid value: %v", def.name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		res[def.name] = v
+	}
+	return res, nil
+}
+
+// orderedObject is an object of the response whose keys are marshaled in the order of the selection set.
+type orderedObject struct {
+	keys   []string
+	values []interface{}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// collectedField is a response key with the field nodes which are merged into it.
+type collectedField struct {
+	key   string
+	nodes []*fieldNode
+}
+
+// collectFields collects the fields of a selection set, including those of fragments, which aren't skipped.
+func (ec *execContext) collectFields(t *gqlType, selections []selection, fields []*collectedField, visited map[string]bool) []*collectedField {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *fieldNode:
+			if !ec.included(sel.directives) {
+				continue
+			}
+			key := sel.responseKey()
+			merged := false
+			for _, f := range fields {
+				if f.key == key {
+					f.nodes = append(f.nodes, sel)
+					merged = true
+				}
+			}
+			if !merged {
+				fields = append(fields, &collectedField{key: key, nodes: []*fieldNode{sel}})
+			}
+		case *inlineFragment:
+			if !ec.included(sel.directives) || (sel.typeCondition != "" && sel.typeCondition != t.name) {
+				continue
+			}
+			fields = ec.collectFields(t, sel.selections, fields, visited)
+		case *fragmentSpread:
+			if visited[sel.name] || !ec.included(sel.directives) {
+				continue
+			}
+			visited[sel.name] = true
+			frag := ec.fragments[sel.name]
+			if frag.typeCondition != t.name {
+				continue
+			}
+			fields = ec.collectFields(t, frag.selections, fields, visited)
+		}
+	}
+	return fields
+}
+
+// included evaluates the @skip and @include directives.
+func (ec *execContext) included(directives []*directive) bool {
+	for _, d := range directives {
+		if d.name != "skip" && d.name != "include" {
+			continue
+		}
+		var cond bool
+		for _, arg := range d.args {
+			if arg.name == "if" {
+				v, _ := arg.value.inputValue(ec.variables)
+				cond, _ = v.(bool)
+			}
+		}
+		if cond == (d.name == "skip") {
+			return false
+		}
+	}
+	return true
+}
+
+// executeSelectionSet resolves the fields of a selection set on an object. If a non-nullable field is null, the
+// error is recorded and failed is true, so that the null propagates to the parent field.
+func (ec *execContext) executeSelectionSet(t *gqlType, source interface{}, selections []selection, p *path) (res *orderedObject, failed bool) {
+	res = &orderedObject{}
+	for _, f := range ec.collectFields(t, selections, nil, map[string]bool{}) {
+		node := f.nodes[0]
+		fieldPath := p.with(f.key)
+
+		var value interface{}
+		if node.name == "__typename" {
+			value = t.name
+		} else {
+			fd := ec.schema.lookupField(t, node.name)
+			value = ec.executeField(fd, source, f.nodes, fieldPath)
+			if value == nil && fd.typ.kind == nonNullKind {
+				return nil, true
+			}
+		}
+
+		res.keys = append(res.keys, f.key)
+		res.values = append(res.values, value)
+	}
+	return res, false
+}
+
+// executeField resolves and completes the value of a field. It returns nil if the field is null or an error
+// was recorded.
+func (ec *execContext) executeField(fd *fieldDef, source interface{}, nodes []*fieldNode, p *path) interface{} {
+	node := nodes[0]
+	if err := ec.ctx.Err(); err != nil {
+		ec.addError(err, node.loc, p)
+		return nil
+	}
+
+	args, err := ec.coerceArguments(fd.args, node.args)
+	if err != nil {
+		ec.addError(err, node.loc, p)
+		return nil
+	}
+	value, err := fd.resolve(ec, source, args)
+	if err != nil {
+		ec.addError(err, node.loc, p)
+		return nil
+	}
+
+	res, _ := ec.completeValue(fd.typ, nodes, value, p)
+	return res
+}
+
+// completeValue converts a resolved value to its response value according to its type. It returns true for
+// errored if an error was recorded for the value or one of its non-nullable descendants, which made it null.
+func (ec *execContext) completeValue(t *gqlType, nodes []*fieldNode, value interface{}, p *path) (res interface{}, errored bool) {
+	if t.kind == nonNullKind {
+		res, errored = ec.completeValue(t.ofType, nodes, value, p)
+		if res == nil && !errored {
+			ec.addError(fmt.Errorf("non-nullable field %s returned null", nodes[0].name), nodes[0].loc, p)
+			errored = true
+		}
+		return res, errored
+	}
+	if value == nil {
+		return nil, false
+	}
+
+	switch t.kind {
+	case listKind:
+		values, ok := value.([]interface{})
+		if !ok {
+			ec.addError(fmt.Errorf("expected list value for field %s, got %T", nodes[0].name, value), nodes[0].loc, p)
+			return nil, true
+		}
+		list := make([]interface{}, len(values))
+		for i, v := range values {
+			elem, errored := ec.completeValue(t.ofType, nodes, v, p.with(i))
+			if elem == nil && t.ofType.kind == nonNullKind {
+				return nil, errored
+			}
+			list[i] = elem
+		}
+		return list, false
+	case objectKind:
+		var selections []selection
+		for _, node := range nodes {
+			selections = append(selections, node.selections...)
+		}
+		obj, failed := ec.executeSelectionSet(t, value, selections, p)
+		if failed {
+			return nil, true
+		}
+		return obj, false
+	default:
+		v, err := t.serialize(value)
+		if err != nil {
+			ec.addError(fmt.Errorf("can't serialize value of type %s: %v", t.name, err), nodes[0].loc, p) //nolint:errorlint // false positive due to using go1.12
+			return nil, true
+		}
+		return v, false
+	}
+}
@@ -0,0 +1,529 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/view"
+)
+
+var testStatusEnum = schema.EnumType{Name: "status", Values: []string{"active", "frozen"}}
+
+var testCoinStruct = schema.StructType{
+	Name: "coin",
+	Fields: []schema.Field{
+		{Name: "denom", Kind: schema.StringKind},
+		{Name: "amount", Kind: schema.IntegerStringKind},
+	},
+}
+
+var testBankSchema = mustModuleSchema(
+	schema.ObjectType{
+		Name:        "balances",
+		Description: "Balances of accounts.",
+		KeyFields: []schema.Field{
+			{Name: "address", Kind: schema.AddressKind},
+			{Name: "denom", Kind: schema.StringKind},
+		},
+		ValueFields: []schema.Field{
+			{Name: "amount", Kind: schema.IntegerStringKind},
+			{Name: "memo", Kind: schema.StringKind, Nullable: true},
+		},
+	},
+	schema.ObjectType{
+		Name: "accounts",
+		KeyFields: []schema.Field{
+			{Name: "id", Kind: schema.Uint64Kind},
+		},
+		ValueFields: []schema.Field{
+			{Name: "status", Kind: schema.EnumKind, EnumType: testStatusEnum},
+			{Name: "created", Kind: schema.TimeKind},
+			{Name: "locked", Kind: schema.ListKind, ElementKind: schema.StructKind, StructType: testCoinStruct, Nullable: true},
+		},
+		RetainDeletions: true,
+	},
+	schema.ObjectType{
+		Name: "params",
+		ValueFields: []schema.Field{
+			{Name: "send_enabled", Kind: schema.BoolKind},
+			{Name: "max_memo", Kind: schema.Int32Kind},
+		},
+	},
+)
+
+func mustModuleSchema(objectTypes ...schema.ObjectType) schema.ModuleSchema {
+	s, err := schema.NewModuleSchema(objectTypes)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+type testAppData struct {
+	modules map[string]*testModuleState
+}
+
+func (a testAppData) BlockNum() (uint64, error) { return 42, nil }
+
+func (a testAppData) AppState() view.AppState { return a }
+
+func (a testAppData) GetModule(name string) (view.ModuleState, error) {
+	if m, ok := a.modules[name]; ok {
+		return m, nil
+	}
+	return nil, nil
+}
+
+func (a testAppData) Modules(f func(view.ModuleState, error) bool) {
+	for _, m := range a.modules {
+		if !f(m, nil) {
+			return
+		}
+	}
+}
+
+func (a testAppData) NumModules() (int, error) { return len(a.modules), nil }
+
+type testModuleState struct {
+	name        string
+	schema      schema.ModuleSchema
+	collections map[string]*testCollection
+
+	// provable are the names of the collections which can prove their objects
+	provable map[string]bool
+}
+
+func (m *testModuleState) ModuleName() string { return m.name }
+
+func (m *testModuleState) ModuleSchema() schema.ModuleSchema { return m.schema }
+
+func (m *testModuleState) GetObjectCollection(name string) (view.ObjectCollection, error) {
+	if c, ok := m.collections[name]; ok {
+		if m.provable[name] {
+			return provableCollection{c}, nil
+		}
+		return c, nil
+	}
+	return nil, nil
+}
+
+func (m *testModuleState) ObjectCollections(f func(view.ObjectCollection, error) bool) {
+	for _, c := range m.collections {
+		if !f(c, nil) {
+			return
+		}
+	}
+}
+
+func (m *testModuleState) NumObjectCollections() (int, error) { return len(m.collections), nil }
+
+type testCollection struct {
+	objectType schema.ObjectType
+	objects    []schema.ObjectUpdate
+	err        error
+}
+
+func (c *testCollection) ObjectType() schema.ObjectType { return c.objectType }
+
+func (c *testCollection) GetObject(key interface{}) (schema.ObjectUpdate, bool, error) {
+	if c.err != nil {
+		return schema.ObjectUpdate{}, false, c.err
+	}
+	for _, obj := range c.objects {
+		if reflect.DeepEqual(obj.Key, key) {
+			return obj, true, nil
+		}
+	}
+	return schema.ObjectUpdate{}, false, nil
+}
+
+func (c *testCollection) AllState(f func(schema.ObjectUpdate, error) bool) {
+	if c.err != nil {
+		f(schema.ObjectUpdate{}, c.err)
+		return
+	}
+	for _, obj := range c.objects {
+		if !f(obj, nil) {
+			return
+		}
+	}
+}
+
+func (c *testCollection) Len() (int, error) { return len(c.objects), nil }
+
+func (c *testCollection) List(filter view.FieldFilter, order view.OrderBy, page view.Pagination) (view.ListResult, error) {
+	return view.ListObjects(c, filter, order, page)
+}
+
+func newTestAppData() testAppData {
+	objectTypes := map[string]schema.ObjectType{}
+	testBankSchema.ObjectTypes(func(t schema.ObjectType) bool {
+		objectTypes[t.Name] = t
+		return true
+	})
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return testAppData{modules: map[string]*testModuleState{
+		"bank": {
+			name:   "bank",
+			schema: testBankSchema,
+			collections: map[string]*testCollection{
+				"balances": {objectType: objectTypes["balances"], objects: []schema.ObjectUpdate{
+					{TypeName: "balances", Key: []interface{}{[]byte{1}, "stake"}, Value: []interface{}{"100", nil}},
+					{TypeName: "balances", Key: []interface{}{[]byte{1}, "atom"}, Value: []interface{}{"7", "hi"}},
+					{TypeName: "balances", Key: []interface{}{[]byte{2}, "stake"}, Value: []interface{}{"-3", nil}},
+				}},
+				"accounts": {objectType: objectTypes["accounts"], objects: []schema.ObjectUpdate{
+					{TypeName: "accounts", Key: uint64(1), Value: []interface{}{"active", created, []interface{}{
+						[]interface{}{"stake", "10"},
+					}}},
+					{TypeName: "accounts", Key: uint64(2), Value: []interface{}{"frozen", created.Add(time.Hour), nil}, Delete: true},
+				}},
+				"params": {objectType: objectTypes["params"], err: fmt.Errorf("store is closed")},
+			},
+		},
+	}}
+}
+
+func newTestSchema(t *testing.T) *Schema {
+	t.Helper()
+	s, err := NewSchema(map[string]schema.ModuleSchema{"bank": testBankSchema}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestExecute(t *testing.T) {
+	s := newTestSchema(t)
+	data := newTestAppData()
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		expected  string
+	}{
+		{
+			name:     "get by key",
+			query:    `{ block_num bank { balances(address: "0x01", denom: "atom") { address denom amount memo } } }`,
+			expected: `{"data":{"block_num":"42","bank":{"balances":{"address":"0x01","denom":"atom","amount":"7","memo":"hi"}}}}`,
+		},
+		{
+			name:     "get missing object",
+			query:    `{ bank { balances(address: "0x03", denom: "atom") { amount } } }`,
+			expected: `{"data":{"bank":{"balances":null}}}`,
+		},
+		{
+			name:     "enum, time, struct list and deleted",
+			query:    `{ bank { a: accounts(id: "1") { status created locked { denom amount } _deleted } b: accounts(id: 2) { status locked { denom } _deleted } } }`,
+			expected: `{"data":{"bank":{"a":{"status":"active","created":"2024-05-01T12:00:00Z","locked":[{"denom":"stake","amount":"10"}],"_deleted":false},"b":{"status":"frozen","locked":null,"_deleted":true}}}}`,
+		},
+		{
+			name:     "list with filter and order",
+			query:    `{ bank { balances_list(filter: {denom: {eq: "stake"}}, order_by: [{field: amount, desc: true}]) { objects { address amount } next_cursor } } }`,
+			expected: `{"data":{"bank":{"balances_list":{"objects":[{"address":"0x01","amount":"100"},{"address":"0x02","amount":"-3"}],"next_cursor":null}}}}`,
+		},
+		{
+			name:      "list with variables",
+			query:     `query List($memo: Boolean!, $denoms: [String!]) { bank { balances_list(filter: {memo: {is_null: $memo}, denom: {in: $denoms}}) { objects { denom } } } }`,
+			variables: map[string]interface{}{"memo": true, "denoms": []string{"stake", "atom"}},
+			expected:  `{"data":{"bank":{"balances_list":{"objects":[{"denom":"stake"},{"denom":"stake"}]}}}}`,
+		},
+		{
+			name:     "list page",
+			query:    `{ bank { balances_list(limit: 1) { objects { denom } next_cursor } } }`,
+			expected: `{"data":{"bank":{"balances_list":{"objects":[{"denom":"atom"}],"next_cursor":"WyJBUT09IiwiYXRvbSJd"}}}}`,
+		},
+		{
+			name:     "list after cursor",
+			query:    `{ bank { balances_list(limit: 1, cursor: "WyJBUT09IiwiYXRvbSJd") { objects { denom } } } }`,
+			expected: `{"data":{"bank":{"balances_list":{"objects":[{"denom":"stake"}]}}}}`,
+		},
+		{
+			name: "fragments and directives",
+			query: `query($skip: Boolean = true) {
+				__typename
+				bank { ...Balance @skip(if: $skip) ... on bank_query { accounts(id: 1) { status @include(if: false) _deleted } } }
+			}
+			fragment Balance on bank_query { balances(address: "0x02", denom: "stake") { amount } }`,
+			expected: `{"data":{"__typename":"Query","bank":{"accounts":{"_deleted":false}}}}`,
+		},
+		{
+			name:     "unknown module state",
+			query:    `{ gov: bank { __typename } }`,
+			expected: `{"data":{"gov":{"__typename":"bank_query"}}}`,
+		},
+		{
+			name:     "resolver error",
+			query:    `{ bank { params { send_enabled } } }`,
+			expected: `{"data":{"bank":{"params":null}},"errors":[{"message":"store is closed","locations":[{"line":1,"column":10}],"path":["bank","params"]}]}`,
+		},
+		{
+			name:     "error propagates to nullable parent",
+			query:    `{ bank { balances_list(order_by: [{field: amount}], limit: -1) { objects { denom } } } }`,
+			expected: `{"data":{"bank":null},"errors":[{"message":"limit must be positive, got -1","locations":[{"line":1,"column":10}],"path":["bank","balances_list"]}]}`,
+		},
+		{
+			name:     "invalid argument",
+			query:    `{ bank { accounts(id: "x") { status } } }`,
+			expected: `{"data":{"bank":{"accounts":null}},"errors":[{"message":"argument \"id\" has invalid value: strconv.ParseUint: parsing \"x\": invalid syntax","locations":[{"line":1,"column":10}],"path":["bank","accounts"]}]}`,
+		},
+		{
+			name:     "validation errors",
+			query:    `{ bank { balances(denom: "atom") { foo } params } }`,
+			expected: `{"errors":[{"message":"field balances argument \"address\" of type Address! is required, but it was not provided","locations":[{"line":1,"column":10}]},{"message":"cannot query field \"foo\" on type bank_balances","locations":[{"line":1,"column":36}]},{"message":"field \"params\" of type bank_params must have a selection of subfields","locations":[{"line":1,"column":42}]}]}`,
+		},
+		{
+			name:     "syntax error",
+			query:    "{\n  bank {",
+			expected: `{"errors":[{"message":"syntax error: unexpected end of document","locations":[{"line":2,"column":9}]}]}`,
+		},
+		{
+			name:     "mutation",
+			query:    `mutation { bank }`,
+			expected: `{"errors":[{"message":"mutation operations aren't supported","locations":[{"line":1,"column":1}]}]}`,
+		},
+		{
+			name:     "introspection",
+			query:    `{ __type(name: "bank_status") { kind name enumValues { name } } __schema { queryType { name } } }`,
+			expected: `{"data":{"__type":{"kind":"ENUM","name":"bank_status","enumValues":[{"name":"active"},{"name":"frozen"}]},"__schema":{"queryType":{"name":"Query"}}}}`,
+		},
+		{
+			name:     "introspection of wrapped types",
+			query:    `{ __type(name: "bank_balances_page") { fields { name type { kind ofType { kind ofType { kind ofType { name } } } } } } }`,
+			expected: `{"data":{"__type":{"fields":[{"name":"objects","type":{"kind":"NON_NULL","ofType":{"kind":"LIST","ofType":{"kind":"NON_NULL","ofType":{"name":"bank_balances"}}}}},{"name":"next_cursor","type":{"kind":"SCALAR","ofType":null}}]}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := s.Execute(context.Background(), data, Request{Query: tt.query, Variables: tt.variables})
+			bz, err := json.Marshal(resp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(bz) != tt.expected {
+				t.Fatalf("expected:\n%s\ngot:\n%s", tt.expected, bz)
+			}
+		})
+	}
+}
+
+// provableCollection is a testCollection whose objects can be proven.
+type provableCollection struct {
+	*testCollection
+}
+
+func (c provableCollection) ProveObject(key interface{}) (view.ObjectProof, error) {
+	return view.ObjectProof{
+		Height:   42,
+		StoreKey: "bank",
+		Key:      []byte(fmt.Sprint(key)),
+		Ops:      []view.ProofOp{{Type: "ics23:iavl", Key: []byte{1}, Data: []byte{2}}},
+	}, nil
+}
+
+func TestExecute_proofs(t *testing.T) {
+	s, err := NewSchema(map[string]schema.ModuleSchema{"bank": testBankSchema}, Options{Proofs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := newTestAppData()
+	data.modules["bank"].provable = map[string]bool{"accounts": true}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "get",
+			query:    `{ bank { accounts(id: 1) { status _proof { height app_hash store_key key value ops { type key data } } } } }`,
+			expected: `{"data":{"bank":{"accounts":{"status":"active","_proof":{"height":"42","app_hash":null,"store_key":"bank","key":"MQ==","value":null,"ops":[{"type":"ics23:iavl","key":"AQ==","data":"Ag=="}]}}}}}`,
+		},
+		{
+			name:     "list",
+			query:    `{ bank { accounts_list { objects { _proof { key } } } } }`,
+			expected: `{"data":{"bank":{"accounts_list":{"objects":[{"_proof":{"key":"MQ=="}},{"_proof":{"key":"Mg=="}}]}}}}`,
+		},
+		{
+			name:     "collection which can't be proven",
+			query:    `{ bank { balances(address: "0x01", denom: "atom") { amount _proof { key } } } }`,
+			expected: `{"data":{"bank":{"balances":{"amount":"7","_proof":null}}},"errors":[{"message":"object type bank/balances can't be proven","locations":[{"line":1,"column":60}],"path":["bank","balances","_proof"]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bz, err := json.Marshal(s.Execute(context.Background(), data, Request{Query: tt.query}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(bz) != tt.expected {
+				t.Fatalf("expected:\n%s\ngot:\n%s", tt.expected, bz)
+			}
+		})
+	}
+
+	// the _proof field only exists in verifiable mode
+	resp := newTestSchema(t).Execute(context.Background(), data, Request{Query: `{ bank { accounts(id: 1) { _proof { key } } } }`})
+	if len(resp.Errors) == 0 {
+		t.Fatalf("expected a validation error without proofs")
+	}
+}
+
+func TestNewSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		modules     map[string]schema.ModuleSchema
+		errContains string
+	}{
+		{
+			name:        "invalid module name",
+			modules:     map[string]schema.ModuleSchema{"x/bank": testBankSchema},
+			errContains: `invalid module name "x/bank"`,
+		},
+		{
+			name: "type name conflict",
+			modules: map[string]schema.ModuleSchema{
+				"a":   mustModuleSchema(schema.ObjectType{Name: "b_c", KeyFields: []schema.Field{{Name: "id", Kind: schema.StringKind}}}),
+				"a_b": mustModuleSchema(schema.ObjectType{Name: "c", KeyFields: []schema.Field{{Name: "id", Kind: schema.StringKind}}}),
+			},
+			errContains: `duplicate GraphQL type name "a_b_c"`,
+		},
+		{
+			name: "query field conflict",
+			modules: map[string]schema.ModuleSchema{
+				"a": mustModuleSchema(
+					schema.ObjectType{Name: "b", KeyFields: []schema.Field{{Name: "id", Kind: schema.StringKind}}},
+					schema.ObjectType{Name: "b_list", ValueFields: []schema.Field{{Name: "id", Kind: schema.StringKind}}},
+				),
+			},
+			errContains: `duplicate field "b_list" in GraphQL type a_query`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSchema(tt.modules, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+func ExampleSchema_SDL() {
+	modSchema := mustModuleSchema(schema.ObjectType{
+		Name:        "votes",
+		Description: "Votes on proposals.",
+		KeyFields: []schema.Field{
+			{Name: "proposal", Kind: schema.Uint64Kind},
+			{Name: "voter", Kind: schema.AddressKind},
+		},
+		ValueFields: []schema.Field{
+			{Name: "option", Kind: schema.EnumKind, EnumType: schema.EnumType{Name: "vote_option", Values: []string{"yes", "no"}}},
+			{Name: "weights", Kind: schema.MapKind, KeyKind: schema.StringKind, ValueKind: schema.DecimalStringKind, Nullable: true},
+		},
+	})
+	s, err := NewSchema(map[string]schema.ModuleSchema{"gov": modSchema}, Options{})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Print(s.SDL())
+	// Output:
+	// type Query {
+	//   """The last block which was persisted."""
+	//   block_num: Uint64!
+	//   """The state of the gov module, or null if it isn't indexed."""
+	//   gov: gov_query
+	// }
+	//
+	// """An address in the string format of the chain."""
+	// scalar Address
+	//
+	// """Compares fields of type Address. Null fields only match is_null conditions."""
+	// input Address_comparison {
+	//   eq: Address
+	//   ne: Address
+	//   in: [Address!]
+	//   is_null: Boolean
+	// }
+	//
+	// """An arbitrary JSON value."""
+	// scalar JSON
+	//
+	// """An unsigned 64-bit integer, serialized as a string."""
+	// scalar Uint64
+	//
+	// """Compares fields of type Uint64. Null fields only match is_null conditions."""
+	// input Uint64_comparison {
+	//   eq: Uint64
+	//   ne: Uint64
+	//   lt: Uint64
+	//   lte: Uint64
+	//   gt: Uint64
+	//   gte: Uint64
+	//   in: [Uint64!]
+	//   is_null: Boolean
+	// }
+	//
+	// type gov_query {
+	//   """Gets the votes object with the key, or null if it doesn't exist."""
+	//   votes(proposal: Uint64!, voter: Address!): gov_votes
+	//   """Lists the votes objects which match the filter in the order, which always ends with the key fields."""
+	//   votes_list(filter: gov_votes_filter, order_by: [gov_votes_order!], limit: Int, cursor: String): gov_votes_page!
+	// }
+	//
+	// enum gov_vote_option {
+	//   yes
+	//   no
+	// }
+	//
+	// """Compares fields of type gov_vote_option. Null fields only match is_null conditions."""
+	// input gov_vote_option_comparison {
+	//   eq: gov_vote_option
+	//   ne: gov_vote_option
+	//   in: [gov_vote_option!]
+	//   is_null: Boolean
+	// }
+	//
+	// """Votes on proposals."""
+	// type gov_votes {
+	//   proposal: Uint64!
+	//   voter: Address!
+	//   option: gov_vote_option!
+	//   weights: JSON
+	// }
+	//
+	// """Filters votes objects by the conditions on their fields, which must all be satisfied."""
+	// input gov_votes_filter {
+	//   proposal: Uint64_comparison
+	//   voter: Address_comparison
+	//   option: gov_vote_option_comparison
+	// }
+	//
+	// """Orders votes objects by a field."""
+	// input gov_votes_order {
+	//   field: gov_votes_order_field!
+	//   """Orders in descending instead of ascending order."""
+	//   desc: Boolean
+	// }
+	//
+	// enum gov_votes_order_field {
+	//   proposal
+	// }
+	//
+	// """A page of votes objects."""
+	// type gov_votes_page {
+	//   objects: [gov_votes!]!
+	//   """The cursor of the next page, or null if this is the last page."""
+	//   next_cursor: String
+	// }
+}
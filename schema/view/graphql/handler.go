@@ -0,0 +1,75 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"cosmossdk.io/schema/view"
+)
+
+// maxRequestSize is the maximum size of the body of POST requests.
+const maxRequestSize = 1 << 20
+
+// Handler returns an HTTP handler which executes GraphQL requests against the app data. POST requests have a
+// JSON encoded Request as body and GET requests have the query, operationName and variables as URL query
+// parameters. GET requests without a query return the schema in the GraphQL schema definition language.
+func (s *Schema) Handler(data view.AppData) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		switch r.Method {
+		case http.MethodGet:
+			query := r.URL.Query()
+			req.Query = query.Get("query")
+			if req.Query == "" {
+				w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+				_ = s.WriteSDL(w)
+				return
+			}
+			req.OperationName = query.Get("operationName")
+			if vars := query.Get("variables"); vars != "" {
+				if err := decodeJSON([]byte(vars), &req.Variables); err != nil {
+					writeResponse(w, http.StatusBadRequest, errorResponse(fmt.Errorf("invalid variables: %v", err))) //nolint:errorlint // false positive due to using go1.12
+					return
+				}
+			}
+		case http.MethodPost:
+			body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+			if err != nil {
+				writeResponse(w, http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			if err := decodeJSON(body, &req); err != nil {
+				writeResponse(w, http.StatusBadRequest, errorResponse(fmt.Errorf("invalid request body: %v", err))) //nolint:errorlint // false positive due to using go1.12
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		resp := s.Execute(r.Context(), data, req)
+		status := http.StatusOK
+		if resp.Data == nil {
+			// the request failed before execution
+			status = http.StatusBadRequest
+		}
+		writeResponse(w, status, resp)
+	})
+}
+
+// decodeJSON decodes JSON with numbers as json.Number, as expected by the coercion of input values.
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func writeResponse(w http.ResponseWriter, status int, resp *Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
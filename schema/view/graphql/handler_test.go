@@ -0,0 +1,81 @@
+package graphql
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	handler := newTestSchema(t).Handler(newTestAppData())
+
+	tests := []struct {
+		name         string
+		method       string
+		target       string
+		body         string
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			name:         "post",
+			method:       http.MethodPost,
+			target:       "/",
+			body:         `{"query":"query Get($id: Uint64!) { bank { accounts(id: $id) { status } } }","variables":{"id":1}}`,
+			expectStatus: http.StatusOK,
+			expectBody:   `{"data":{"bank":{"accounts":{"status":"active"}}}}`,
+		},
+		{
+			name:         "get",
+			method:       http.MethodGet,
+			target:       "/?" + url.Values{"query": {"query A { block_num } query B { __typename }"}, "operationName": {"B"}}.Encode(),
+			expectStatus: http.StatusOK,
+			expectBody:   `{"data":{"__typename":"Query"}}`,
+		},
+		{
+			name:         "invalid body",
+			method:       http.MethodPost,
+			target:       "/",
+			body:         `{"query":`,
+			expectStatus: http.StatusBadRequest,
+			expectBody:   `{"errors":[{"message":"invalid request body: unexpected EOF"}]}`,
+		},
+		{
+			name:         "validation error",
+			method:       http.MethodPost,
+			target:       "/",
+			body:         `{"query":"{ foo }"}`,
+			expectStatus: http.StatusBadRequest,
+			expectBody:   `{"errors":[{"message":"cannot query field \"foo\" on type Query","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			name:         "sdl",
+			method:       http.MethodGet,
+			target:       "/",
+			expectStatus: http.StatusOK,
+			expectBody:   "type Query {",
+		},
+		{
+			name:         "method not allowed",
+			method:       http.MethodPut,
+			target:       "/",
+			expectStatus: http.StatusMethodNotAllowed,
+			expectBody:   "method not allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if rec.Code != tt.expectStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectStatus, rec.Code)
+			}
+			if !strings.HasPrefix(rec.Body.String(), tt.expectBody) {
+				t.Fatalf("expected body starting with %q, got %q", tt.expectBody, rec.Body.String())
+			}
+		})
+	}
+}
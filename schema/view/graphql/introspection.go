@@ -0,0 +1,236 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+)
+
+// directiveDef is a directive supported by the schema.
+type directiveDef struct {
+	name        string
+	description string
+	locations   []string
+	args        []*inputValueDef
+}
+
+// addIntrospection adds the introspection types and the __schema and __type fields of the Query type.
+func (s *Schema) addIntrospection() error {
+	str := s.scalars["String"]
+	boolean := s.scalars["Boolean"]
+
+	typeKindEnum := introspectionEnum("__TypeKind", "SCALAR", "OBJECT", "INTERFACE", "UNION", "ENUM", "INPUT_OBJECT", "LIST", "NON_NULL")
+	locationEnum := introspectionEnum("__DirectiveLocation", "QUERY", "MUTATION", "SUBSCRIPTION", "FIELD",
+		"FRAGMENT_DEFINITION", "FRAGMENT_SPREAD", "INLINE_FRAGMENT", "VARIABLE_DEFINITION", "SCHEMA", "SCALAR", "OBJECT",
+		"FIELD_DEFINITION", "ARGUMENT_DEFINITION", "INTERFACE", "UNION", "ENUM", "ENUM_VALUE", "INPUT_OBJECT",
+		"INPUT_FIELD_DEFINITION")
+	schemaType := &gqlType{kind: objectKind, name: "__Schema"}
+	typeType := &gqlType{kind: objectKind, name: "__Type"}
+	fieldType := &gqlType{kind: objectKind, name: "__Field"}
+	inputValueType := &gqlType{kind: objectKind, name: "__InputValue"}
+	enumValueType := &gqlType{kind: objectKind, name: "__EnumValue"}
+	directiveType := &gqlType{kind: objectKind, name: "__Directive"}
+	for _, t := range []*gqlType{typeKindEnum, locationEnum, schemaType, typeType, fieldType, inputValueType, enumValueType, directiveType} {
+		if err := s.register(t); err != nil {
+			return err
+		}
+	}
+
+	includeDeprecated := []*inputValueDef{{name: "includeDeprecated", typ: boolean}}
+	notDeprecated := []*fieldDef{
+		field("isDeprecated", nonNull(boolean), func(interface{}) interface{} { return false }),
+		field("deprecationReason", str, func(interface{}) interface{} { return nil }),
+	}
+	directives := []*directiveDef{
+		{
+			name:        "include",
+			description: "Directs the executor to include this field or fragment only when the `if` argument is true.",
+			locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+			args:        []*inputValueDef{{name: "if", description: "Included when true.", typ: nonNull(boolean)}},
+		},
+		{
+			name:        "skip",
+			description: "Directs the executor to skip this field or fragment when the `if` argument is true.",
+			locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+			args:        []*inputValueDef{{name: "if", description: "Skipped when true.", typ: nonNull(boolean)}},
+		},
+	}
+
+	schemaType.fields = []*fieldDef{
+		field("description", str, func(interface{}) interface{} { return nil }),
+		field("types", nonNull(listOf(nonNull(typeType))), func(interface{}) interface{} {
+			names := make([]string, 0, len(s.types))
+			for name := range s.types {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			types := make([]interface{}, len(names))
+			for i, name := range names {
+				types[i] = s.types[name]
+			}
+			return types
+		}),
+		field("queryType", nonNull(typeType), func(interface{}) interface{} { return s.query }),
+		field("mutationType", typeType, func(interface{}) interface{} { return nil }),
+		field("subscriptionType", typeType, func(interface{}) interface{} { return nil }),
+		field("directives", nonNull(listOf(nonNull(directiveType))), func(interface{}) interface{} {
+			res := make([]interface{}, len(directives))
+			for i, d := range directives {
+				res[i] = d
+			}
+			return res
+		}),
+	}
+
+	typeType.fields = []*fieldDef{
+		field("kind", nonNull(typeKindEnum), func(source interface{}) interface{} { return string(source.(*gqlType).kind) }),
+		field("name", str, func(source interface{}) interface{} { return optionalString(source.(*gqlType).name) }),
+		field("description", str, func(source interface{}) interface{} { return optionalString(source.(*gqlType).description) }),
+		field("specifiedByURL", str, func(interface{}) interface{} { return nil }),
+		withArgs(field("fields", listOf(nonNull(fieldType)), func(source interface{}) interface{} {
+			t := source.(*gqlType)
+			if t.kind != objectKind {
+				return nil
+			}
+			res := make([]interface{}, len(t.fields))
+			for i, f := range t.fields {
+				res[i] = f
+			}
+			return res
+		}), includeDeprecated),
+		field("interfaces", listOf(nonNull(typeType)), func(source interface{}) interface{} {
+			if source.(*gqlType).kind != objectKind {
+				return nil
+			}
+			return []interface{}{}
+		}),
+		field("possibleTypes", listOf(nonNull(typeType)), func(interface{}) interface{} { return nil }),
+		withArgs(field("enumValues", listOf(nonNull(enumValueType)), func(source interface{}) interface{} {
+			t := source.(*gqlType)
+			if t.kind != enumKind {
+				return nil
+			}
+			res := make([]interface{}, len(t.enumValues))
+			for i, v := range t.enumValues {
+				res[i] = v
+			}
+			return res
+		}), includeDeprecated),
+		withArgs(field("inputFields", listOf(nonNull(inputValueType)), func(source interface{}) interface{} {
+			t := source.(*gqlType)
+			if t.kind != inputObjectKind {
+				return nil
+			}
+			return inputValues(t.inputFields)
+		}), includeDeprecated),
+		field("ofType", typeType, func(source interface{}) interface{} {
+			if t := source.(*gqlType).ofType; t != nil {
+				return t
+			}
+			return nil
+		}),
+		field("isOneOf", boolean, func(source interface{}) interface{} {
+			if source.(*gqlType).kind != inputObjectKind {
+				return nil
+			}
+			return false
+		}),
+	}
+
+	fieldType.fields = append([]*fieldDef{
+		field("name", nonNull(str), func(source interface{}) interface{} { return source.(*fieldDef).name }),
+		field("description", str, func(source interface{}) interface{} { return optionalString(source.(*fieldDef).description) }),
+		withArgs(field("args", nonNull(listOf(nonNull(inputValueType))), func(source interface{}) interface{} {
+			return inputValues(source.(*fieldDef).args)
+		}), includeDeprecated),
+		field("type", nonNull(typeType), func(source interface{}) interface{} { return source.(*fieldDef).typ }),
+	}, notDeprecated...)
+
+	inputValueType.fields = append([]*fieldDef{
+		field("name", nonNull(str), func(source interface{}) interface{} { return source.(*inputValueDef).name }),
+		field("description", str, func(source interface{}) interface{} { return optionalString(source.(*inputValueDef).description) }),
+		field("type", nonNull(typeType), func(source interface{}) interface{} { return source.(*inputValueDef).typ }),
+		field("defaultValue", str, func(interface{}) interface{} { return nil }),
+	}, notDeprecated...)
+
+	enumValueType.fields = append([]*fieldDef{
+		field("name", nonNull(str), func(source interface{}) interface{} { return source }),
+		field("description", str, func(interface{}) interface{} { return nil }),
+	}, notDeprecated...)
+
+	directiveType.fields = []*fieldDef{
+		field("name", nonNull(str), func(source interface{}) interface{} { return source.(*directiveDef).name }),
+		field("description", str, func(source interface{}) interface{} { return optionalString(source.(*directiveDef).description) }),
+		field("isRepeatable", nonNull(boolean), func(interface{}) interface{} { return false }),
+		field("locations", nonNull(listOf(nonNull(locationEnum))), func(source interface{}) interface{} {
+			locations := source.(*directiveDef).locations
+			res := make([]interface{}, len(locations))
+			for i, l := range locations {
+				res[i] = l
+			}
+			return res
+		}),
+		withArgs(field("args", nonNull(listOf(nonNull(inputValueType))), func(source interface{}) interface{} {
+			return inputValues(source.(*directiveDef).args)
+		}), includeDeprecated),
+	}
+
+	s.introspection = []*fieldDef{
+		field("__schema", nonNull(schemaType), func(interface{}) interface{} { return s }),
+		{
+			name: "__type",
+			args: []*inputValueDef{{name: "name", typ: nonNull(str)}},
+			typ:  typeType,
+			resolve: func(_ *execContext, _ interface{}, args map[string]interface{}) (interface{}, error) {
+				if t, ok := s.types[args["name"].(string)]; ok {
+					return t, nil
+				}
+				return nil, nil
+			},
+		},
+	}
+	return nil
+}
+
+// field returns a field without arguments whose value is resolved from the source by resolve.
+func field(name string, typ *gqlType, resolve func(source interface{}) interface{}) *fieldDef {
+	return &fieldDef{
+		name: name,
+		typ:  typ,
+		resolve: func(_ *execContext, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			return resolve(source), nil
+		},
+	}
+}
+
+func withArgs(f *fieldDef, args []*inputValueDef) *fieldDef {
+	f.args = args
+	return f
+}
+
+func introspectionEnum(name string, values ...string) *gqlType {
+	t := &gqlType{kind: enumKind, name: name, enumValues: values}
+	t.serialize = func(v interface{}) (interface{}, error) {
+		str, ok := v.(string)
+		if !ok || !containsString(values, str) {
+			return nil, fmt.Errorf("invalid %s value %v", name, v)
+		}
+		return str, nil
+	}
+	t.parse = t.serialize
+	return t
+}
+
+func inputValues(defs []*inputValueDef) []interface{} {
+	res := make([]interface{}, len(defs))
+	for i, d := range defs {
+		res[i] = d
+	}
+	return res
+}
+
+func optionalString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
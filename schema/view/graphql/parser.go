@@ -0,0 +1,589 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// document is a parsed executable GraphQL document.
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragmentDef
+}
+
+// operation is an operation definition of a document.
+type operation struct {
+	// typ is query, mutation or subscription.
+	typ        string
+	name       string
+	varDefs    []*varDef
+	directives []*directive
+	selections []selection
+	loc        Location
+}
+
+type varDef struct {
+	name         string
+	typ          *typeRef
+	defaultValue *valueNode
+	loc          Location
+}
+
+// typeRef is a type reference in a variable definition.
+type typeRef struct {
+	name    string
+	elem    *typeRef
+	nonNull bool
+}
+
+func (t *typeRef) String() string {
+	s := t.name
+	if t.elem != nil {
+		s = "[" + t.elem.String() + "]"
+	}
+	if t.nonNull {
+		s += "!"
+	}
+	return s
+}
+
+// selection is a *fieldNode, *fragmentSpread or *inlineFragment.
+type selection interface{}
+
+type fieldNode struct {
+	alias      string
+	name       string
+	args       []*argument
+	directives []*directive
+	selections []selection
+	loc        Location
+}
+
+// responseKey returns the key of the field in the response.
+func (f *fieldNode) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+type fragmentSpread struct {
+	name       string
+	directives []*directive
+	loc        Location
+}
+
+type inlineFragment struct {
+	typeCondition string
+	directives    []*directive
+	selections    []selection
+	loc           Location
+}
+
+type fragmentDef struct {
+	name          string
+	typeCondition string
+	directives    []*directive
+	selections    []selection
+	loc           Location
+}
+
+type argument struct {
+	name  string
+	value *valueNode
+	loc   Location
+}
+
+type directive struct {
+	name string
+	args []*argument
+	loc  Location
+}
+
+// valueKind is the kind of a value literal.
+type valueKind int
+
+const (
+	variableValue valueKind = iota
+	intValue
+	floatValue
+	stringValue
+	booleanValue
+	nullValue
+	enumValue
+	listValue
+	objectValue
+)
+
+// valueNode is a value literal of a document.
+type valueNode struct {
+	kind valueKind
+
+	// raw is the name of variables and enum values, the digits of numbers and the unescaped value of strings
+	raw    string
+	list   []*valueNode
+	fields []*objectField
+	loc    Location
+}
+
+type objectField struct {
+	name  string
+	value *valueNode
+}
+
+// inputValue converts the literal to a value as decoded from JSON with json.Decoder.UseNumber, with enum
+// values as strings. Variables are replaced by their values and absent variables are reported as not present.
+func (v *valueNode) inputValue(variables map[string]interface{}) (value interface{}, present bool) {
+	switch v.kind {
+	case variableValue:
+		value, present = variables[v.raw]
+		return value, present
+	case intValue, floatValue:
+		return json.Number(v.raw), true
+	case stringValue, enumValue:
+		return v.raw, true
+	case booleanValue:
+		return v.raw == "true", true
+	case listValue:
+		list := make([]interface{}, len(v.list))
+		for i, elem := range v.list {
+			list[i], _ = elem.inputValue(variables)
+		}
+		return list, true
+	case objectValue:
+		obj := make(map[string]interface{}, len(v.fields))
+		for _, f := range v.fields {
+			if fv, ok := f.value.inputValue(variables); ok {
+				obj[f.name] = fv
+			}
+		}
+		return obj, true
+	default:
+		return nil, true
+	}
+}
+
+// parser is a recursive descent parser of executable GraphQL documents.
+type parser struct {
+	lexer *lexer
+	tok   token
+}
+
+// parseDocument parses an executable document, which can only contain operation and fragment definitions.
+func parseDocument(src string) (*document, error) {
+	p := &parser{lexer: newLexer(src)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &document{fragments: map[string]*fragmentDef{}}
+	for p.tok.kind != eofToken {
+		switch {
+		case p.peek("{"):
+			op := &operation{typ: "query", loc: p.tok.loc}
+			var err error
+			if op.selections, err = p.parseSelectionSet(); err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.peekName("query") || p.peekName("mutation") || p.peekName("subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.peekName("fragment"):
+			frag, err := p.parseFragmentDef()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.fragments[frag.name]; ok {
+				return nil, &Error{Message: fmt.Sprintf("there can be only one fragment named %q", frag.name), Locations: []Location{frag.loc}}
+			}
+			doc.fragments[frag.name] = frag
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(doc.operations) == 0 {
+		return nil, &Error{Message: "document doesn't contain any operation"}
+	}
+	return doc, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) peek(punctuator string) bool {
+	return p.tok.kind == punctuatorToken && p.tok.value == punctuator
+}
+
+func (p *parser) peekName(name string) bool {
+	return p.tok.kind == nameToken && p.tok.value == name
+}
+
+// skip advances if the current token is the punctuator and returns whether it was.
+func (p *parser) skip(punctuator string) (bool, error) {
+	if !p.peek(punctuator) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) expect(punctuator string) error {
+	if !p.peek(punctuator) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != nameToken {
+		return "", p.unexpected()
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) unexpected() error {
+	desc := fmt.Sprintf("%q", p.tok.value)
+	if p.tok.kind == eofToken {
+		desc = "end of document"
+	}
+	return &Error{Message: fmt.Sprintf("syntax error: unexpected %s", desc), Locations: []Location{p.tok.loc}}
+}
+
+func (p *parser) parseOperation() (*operation, error) {
+	op := &operation{typ: p.tok.value, loc: p.tok.loc}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if p.tok.kind == nameToken {
+		if op.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek("(") {
+		if op.varDefs, err = p.parseVarDefs(); err != nil {
+			return nil, err
+		}
+	}
+	if op.directives, err = p.parseDirectives(false); err != nil {
+		return nil, err
+	}
+	if op.selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (p *parser) parseVarDefs() ([]*varDef, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var defs []*varDef
+	for {
+		if ok, err := p.skip(")"); ok || err != nil {
+			return defs, err
+		}
+
+		def := &varDef{loc: p.tok.loc}
+		if err := p.expect("$"); err != nil {
+			return nil, err
+		}
+		var err error
+		if def.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if def.typ, err = p.parseTypeRef(); err != nil {
+			return nil, err
+		}
+		if ok, err := p.skip("="); err != nil {
+			return nil, err
+		} else if ok {
+			if def.defaultValue, err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := p.parseDirectives(true); err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+	}
+}
+
+func (p *parser) parseTypeRef() (*typeRef, error) {
+	t := &typeRef{}
+	if ok, err := p.skip("["); err != nil {
+		return nil, err
+	} else if ok {
+		if t.elem, err = p.parseTypeRef(); err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	} else if t.name, err = p.expectName(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	t.nonNull, err = p.skip("!")
+	return t, err
+}
+
+func (p *parser) parseDirectives(isConst bool) ([]*directive, error) {
+	var directives []*directive
+	for p.peek("@") {
+		d := &directive{loc: p.tok.loc}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		if d.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+		if d.args, err = p.parseArguments(isConst); err != nil {
+			return nil, err
+		}
+		directives = append(directives, d)
+	}
+	return directives, nil
+}
+
+func (p *parser) parseArguments(isConst bool) ([]*argument, error) {
+	if !p.peek("(") {
+		return nil, nil
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var args []*argument
+	for {
+		if ok, err := p.skip(")"); ok || err != nil {
+			return args, err
+		}
+
+		arg := &argument{loc: p.tok.loc}
+		var err error
+		if arg.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if arg.value, err = p.parseValue(isConst); err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+}
+
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var selections []selection
+	for {
+		if ok, err := p.skip("}"); err != nil {
+			return nil, err
+		} else if ok {
+			if len(selections) == 0 {
+				return nil, &Error{Message: "syntax error: empty selection set", Locations: []Location{p.tok.loc}}
+			}
+			return selections, nil
+		}
+
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, sel)
+	}
+}
+
+func (p *parser) parseSelection() (selection, error) {
+	loc := p.tok.loc
+	if ok, err := p.skip("..."); err != nil {
+		return nil, err
+	} else if ok {
+		return p.parseFragment(loc)
+	}
+
+	f := &fieldNode{loc: loc}
+	var err error
+	if f.name, err = p.expectName(); err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip(":"); err != nil {
+		return nil, err
+	} else if ok {
+		f.alias = f.name
+		if f.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+	if f.args, err = p.parseArguments(false); err != nil {
+		return nil, err
+	}
+	if f.directives, err = p.parseDirectives(false); err != nil {
+		return nil, err
+	}
+	if p.peek("{") {
+		if f.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// parseFragment parses a fragment spread or inline fragment after the "...".
+func (p *parser) parseFragment(loc Location) (selection, error) {
+	if p.tok.kind == nameToken && p.tok.value != "on" {
+		spread := &fragmentSpread{name: p.tok.value, loc: loc}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		spread.directives, err = p.parseDirectives(false)
+		return spread, err
+	}
+
+	frag := &inlineFragment{loc: loc}
+	if p.peekName("on") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		if frag.typeCondition, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if frag.directives, err = p.parseDirectives(false); err != nil {
+		return nil, err
+	}
+	frag.selections, err = p.parseSelectionSet()
+	return frag, err
+}
+
+func (p *parser) parseFragmentDef() (*fragmentDef, error) {
+	frag := &fragmentDef{loc: p.tok.loc}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if frag.name, err = p.expectName(); err != nil {
+		return nil, err
+	}
+	if frag.name == "on" {
+		return nil, &Error{Message: "syntax error: fragment can't be named \"on\"", Locations: []Location{frag.loc}}
+	}
+	if !p.peekName("on") {
+		return nil, p.unexpected()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if frag.typeCondition, err = p.expectName(); err != nil {
+		return nil, err
+	}
+	if frag.directives, err = p.parseDirectives(false); err != nil {
+		return nil, err
+	}
+	frag.selections, err = p.parseSelectionSet()
+	return frag, err
+}
+
+// parseValue parses a value literal, which can't contain variables if isConst is true.
+func (p *parser) parseValue(isConst bool) (*valueNode, error) {
+	v := &valueNode{loc: p.tok.loc, raw: p.tok.value}
+	switch p.tok.kind {
+	case intToken:
+		v.kind = intValue
+	case floatToken:
+		v.kind = floatValue
+	case stringToken:
+		v.kind = stringValue
+	case nameToken:
+		switch p.tok.value {
+		case "true", "false":
+			v.kind = booleanValue
+		case "null":
+			v.kind = nullValue
+		default:
+			v.kind = enumValue
+		}
+	case punctuatorToken:
+		switch p.tok.value {
+		case "$":
+			if isConst {
+				return nil, p.unexpected()
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			v.kind = variableValue
+			var err error
+			v.raw, err = p.expectName()
+			return v, err
+		case "[":
+			v.kind = listValue
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			for {
+				if ok, err := p.skip("]"); ok || err != nil {
+					return v, err
+				}
+				elem, err := p.parseValue(isConst)
+				if err != nil {
+					return nil, err
+				}
+				v.list = append(v.list, elem)
+			}
+		case "{":
+			v.kind = objectValue
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			for {
+				if ok, err := p.skip("}"); ok || err != nil {
+					return v, err
+				}
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				fv, err := p.parseValue(isConst)
+				if err != nil {
+					return nil, err
+				}
+				v.fields = append(v.fields, &objectField{name: name, value: fv})
+			}
+		default:
+			return nil, p.unexpected()
+		}
+	default:
+		return nil, p.unexpected()
+	}
+	return v, p.advance()
+}
@@ -0,0 +1,85 @@
+package graphql
+
+import "cosmossdk.io/schema/view"
+
+// addProofTypes registers the ObjectProof and ProofOp types of the verifiable mode.
+func (s *Schema) addProofTypes() error {
+	bytesValue := func(bz []byte) interface{} {
+		if bz == nil {
+			return nil
+		}
+		return bz
+	}
+	proofField := func(name, description string, typ *gqlType, value func(view.ObjectProof) interface{}) *fieldDef {
+		return &fieldDef{
+			name:        name,
+			description: description,
+			typ:         typ,
+			resolve: func(_ *execContext, source interface{}, _ map[string]interface{}) (interface{}, error) {
+				return value(source.(view.ObjectProof)), nil
+			},
+		}
+	}
+	opField := func(name, description string, value func(view.ProofOp) []byte) *fieldDef {
+		return &fieldDef{
+			name:        name,
+			description: description,
+			typ:         nonNull(s.scalars["Bytes"]),
+			resolve: func(_ *execContext, source interface{}, _ map[string]interface{}) (interface{}, error) {
+				return value(source.(view.ProofOp)), nil
+			},
+		}
+	}
+
+	op := &gqlType{
+		kind:        objectKind,
+		name:        "ProofOp",
+		description: "A commitment proof operation.",
+		fields: []*fieldDef{
+			{
+				name:        "type",
+				description: "The type of the proof operation.",
+				typ:         nonNull(s.scalars["String"]),
+				resolve: func(_ *execContext, source interface{}, _ map[string]interface{}) (interface{}, error) {
+					return source.(view.ProofOp).Type, nil
+				},
+			},
+			opField("key", "The key which the operation proves.", func(op view.ProofOp) []byte { return op.Key }),
+			opField("data", "The encoded proof.", func(op view.ProofOp) []byte { return op.Data }),
+		},
+	}
+	if err := s.register(op); err != nil {
+		return err
+	}
+
+	s.proof = &gqlType{
+		kind:        objectKind,
+		name:        "ObjectProof",
+		description: "The proof of the state of an object, which can be verified against the app hash of the block at height + 1.",
+		fields: []*fieldDef{
+			proofField("height", "The height of the proven state.", nonNull(s.scalars["Uint64"]), func(p view.ObjectProof) interface{} {
+				return p.Height
+			}),
+			proofField("app_hash", "The app hash which the proof commits to, or null if the backend doesn't know it.", s.scalars["Bytes"], func(p view.ObjectProof) interface{} {
+				return bytesValue(p.AppHash)
+			}),
+			proofField("store_key", "The store which holds the kv-pair of the object.", nonNull(s.scalars["String"]), func(p view.ObjectProof) interface{} {
+				return p.StoreKey
+			}),
+			proofField("key", "The key of the kv-pair of the object.", nonNull(s.scalars["Bytes"]), func(p view.ObjectProof) interface{} {
+				return p.Key
+			}),
+			proofField("value", "The value of the kv-pair, or null for a proof of absence.", s.scalars["Bytes"], func(p view.ObjectProof) interface{} {
+				return bytesValue(p.Value)
+			}),
+			proofField("ops", "The commitment proof operations which chain the kv-pair to the app hash.", nonNull(listOf(nonNull(op))), func(p view.ObjectProof) interface{} {
+				ops := make([]interface{}, len(p.Ops))
+				for i, op := range p.Ops {
+					ops[i] = op
+				}
+				return ops
+			}),
+		},
+	}
+	return s.register(s.proof)
+}
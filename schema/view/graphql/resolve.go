@@ -0,0 +1,150 @@
+package graphql
+
+import (
+	"fmt"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/view"
+)
+
+// objectSource is the value from which the fields of an object type are resolved.
+type objectSource struct {
+	objectType schema.ObjectType
+	update     schema.ObjectUpdate
+
+	// coll is the collection of the object, which proves it
+	coll view.ObjectCollection
+}
+
+// structSource is the value from which the fields of a struct type are resolved.
+type structSource struct {
+	values []interface{}
+}
+
+// pageSource is the value from which the fields of a page type are resolved.
+type pageSource struct {
+	objectType schema.ObjectType
+	result     view.ListResult
+	coll       view.ObjectCollection
+}
+
+// outputValue converts the value of a field to the value which is completed by its GraphQL type.
+func outputValue(field schema.Field, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	switch field.Kind {
+	case schema.StructKind:
+		values, _ := value.([]interface{})
+		return structSource{values: values}
+	case schema.ListKind:
+		values, _ := value.([]interface{})
+		elemField := schema.Field{Name: field.Name, Kind: field.ElementKind, StructType: field.StructType}
+		list := make([]interface{}, len(values))
+		for i, v := range values {
+			list[i] = outputValue(elemField, v)
+		}
+		return list
+	default:
+		return value
+	}
+}
+
+// keyValue returns the key of an object from the key field arguments as described by schema.ObjectUpdate.
+func keyValue(objectType schema.ObjectType, args map[string]interface{}) (interface{}, error) {
+	values := make([]interface{}, len(objectType.KeyFields))
+	for i, field := range objectType.KeyFields {
+		v, err := kindValue(field.Kind, args[field.Name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %q: %v", field.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		values[i] = v
+	}
+	if len(values) == 1 {
+		return values[0], nil
+	}
+	return values, nil
+}
+
+// comparisonOps maps the fields of comparison input types to filter operators.
+var comparisonOps = []struct {
+	name string
+	op   view.FilterOp
+}{
+	{"eq", view.FilterEq},
+	{"ne", view.FilterNotEq},
+	{"lt", view.FilterLt},
+	{"lte", view.FilterLtEq},
+	{"gt", view.FilterGt},
+	{"gte", view.FilterGtEq},
+	{"in", view.FilterIn},
+}
+
+// listArgs converts the arguments of a list field to the arguments of view.ObjectCollection.List.
+func listArgs(objectType schema.ObjectType, args map[string]interface{}) (view.FieldFilter, view.OrderBy, view.Pagination, error) {
+	var filter view.FieldFilter
+	if filterArg, ok := args["filter"].(map[string]interface{}); ok {
+		fields := append(append([]schema.Field{}, objectType.KeyFields...), objectType.ValueFields...)
+		for _, field := range fields {
+			comparison, ok := filterArg[field.Name].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, c := range comparisonOps {
+				arg, ok := comparison[c.name]
+				if !ok {
+					continue
+				}
+				if arg == nil {
+					return nil, nil, view.Pagination{}, fmt.Errorf("%s condition on field %q can't be null, use is_null", c.name, field.Name)
+				}
+				value, err := conditionValue(field.Kind, arg)
+				if err != nil {
+					return nil, nil, view.Pagination{}, fmt.Errorf("invalid %s condition on field %q: %v", c.name, field.Name, err) //nolint:errorlint // false positive due to using go1.12
+				}
+				filter = append(filter, view.FieldCondition{Field: field.Name, Op: c.op, Value: value})
+			}
+			if isNull, ok := comparison["is_null"].(bool); ok {
+				op := view.FilterEq
+				if !isNull {
+					op = view.FilterNotEq
+				}
+				filter = append(filter, view.FieldCondition{Field: field.Name, Op: op})
+			}
+		}
+	}
+
+	var order view.OrderBy
+	orderArg, _ := args["order_by"].([]interface{})
+	for _, o := range orderArg {
+		fieldOrder := o.(map[string]interface{})
+		desc, _ := fieldOrder["desc"].(bool)
+		order = append(order, view.FieldOrder{Field: fieldOrder["field"].(string), Descending: desc})
+	}
+
+	var page view.Pagination
+	if limit, ok := args["limit"].(int64); ok {
+		if limit <= 0 {
+			return nil, nil, view.Pagination{}, fmt.Errorf("limit must be positive, got %d", limit)
+		}
+		page.Limit = int(limit)
+	}
+	page.Cursor, _ = args["cursor"].(string)
+	return filter, order, page, nil
+}
+
+func conditionValue(kind schema.Kind, arg interface{}) (interface{}, error) {
+	values, ok := arg.([]interface{})
+	if !ok {
+		return kindValue(kind, arg)
+	}
+	res := make([]interface{}, len(values))
+	for i, v := range values {
+		var err error
+		if res[i], err = kindValue(kind, v); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,334 @@
+package graphql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+
+	"cosmossdk.io/schema"
+)
+
+// scalars are the scalar types of a schema by name. Int, Float, String and Boolean are the built-in GraphQL
+// scalars, the others are custom scalars for kinds which can't be represented by them.
+type scalars map[string]*gqlType
+
+func newScalars(addressCodec schema.AddressCodec) scalars {
+	s := scalars{}
+	add := func(name, description string, serialize func(interface{}) (interface{}, error), parse func(interface{}) (interface{}, error)) {
+		s[name] = &gqlType{kind: scalarKind, name: name, description: description, serialize: serialize, parse: parse}
+	}
+
+	add("String", "", serializeAs(reflect.String), func(v interface{}) (interface{}, error) {
+		str, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %s", describeInput(v))
+		}
+		return str, nil
+	})
+	add("Boolean", "", serializeAs(reflect.Bool), func(v interface{}) (interface{}, error) {
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected boolean, got %s", describeInput(v))
+		}
+		return b, nil
+	})
+	add("Int", "", serializeInt, func(v interface{}) (interface{}, error) {
+		n, err := parseInt(v, false)
+		if err != nil {
+			return nil, err
+		}
+		if n < math.MinInt32 || n > math.MaxInt32 {
+			return nil, fmt.Errorf("%d is out of the range of Int", n)
+		}
+		return n, nil
+	})
+	add("Float", "", serializeFloat, func(v interface{}) (interface{}, error) {
+		num, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("expected number, got %s", describeInput(v))
+		}
+		return num.Float64()
+	})
+	add("Uint32", "An unsigned 32-bit integer.", serializeInt, func(v interface{}) (interface{}, error) {
+		n, err := parseInt(v, false)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 || n > math.MaxUint32 {
+			return nil, fmt.Errorf("%d is out of the range of Uint32", n)
+		}
+		return uint32(n), nil
+	})
+	add("Int64", "A signed 64-bit integer, serialized as a string.", serializeString, func(v interface{}) (interface{}, error) {
+		return parseInt(v, true)
+	})
+	add("Uint64", "An unsigned 64-bit integer, serialized as a string.", serializeString, func(v interface{}) (interface{}, error) {
+		str, err := numberString(v)
+		if err != nil {
+			return nil, err
+		}
+		return strconv.ParseUint(str, 10, 64)
+	})
+	add("BigInt", "An arbitrary precision integer, serialized as a string.", serializeString, func(v interface{}) (interface{}, error) {
+		str, err := numberString(v)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := new(big.Int).SetString(str, 10); !ok {
+			return nil, fmt.Errorf("invalid integer %q", str)
+		}
+		return str, nil
+	})
+	add("Decimal", "An arbitrary precision decimal number, serialized as a string.", serializeString, func(v interface{}) (interface{}, error) {
+		str, err := numberString(v)
+		if err != nil {
+			return nil, err
+		}
+		return str, schema.DecimalStringKind.ValidateValue(str)
+	})
+	add("Time", "A timestamp in RFC 3339 format with up to nanosecond precision.", func(v interface{}) (interface{}, error) {
+		t, ok := v.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("expected time.Time, got %T", v)
+		}
+		return t.UTC().Format(time.RFC3339Nano), nil
+	}, func(v interface{}) (interface{}, error) {
+		str, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected RFC 3339 string, got %s", describeInput(v))
+		}
+		return time.Parse(time.RFC3339Nano, str)
+	})
+	add("Duration", "A duration in nanoseconds, serialized as a string.", func(v interface{}) (interface{}, error) {
+		d, ok := v.(time.Duration)
+		if !ok {
+			return nil, fmt.Errorf("expected time.Duration, got %T", v)
+		}
+		return strconv.FormatInt(int64(d), 10), nil
+	}, func(v interface{}) (interface{}, error) {
+		n, err := parseInt(v, true)
+		return time.Duration(n), err
+	})
+	add("Bytes", "Bytes encoded in base64.", func(v interface{}) (interface{}, error) {
+		bz, ok := v.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("expected []byte, got %T", v)
+		}
+		return base64.StdEncoding.EncodeToString(bz), nil
+	}, func(v interface{}) (interface{}, error) {
+		str, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected base64 string, got %s", describeInput(v))
+		}
+		return base64.StdEncoding.DecodeString(str)
+	})
+	add("Address", "An address in the string format of the chain.", func(v interface{}) (interface{}, error) {
+		bz, ok := v.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("expected []byte, got %T", v)
+		}
+		return addressCodec.BytesToString(bz)
+	}, func(v interface{}) (interface{}, error) {
+		str, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected address string, got %s", describeInput(v))
+		}
+		return addressCodec.StringToBytes(str)
+	})
+	add("JSON", "An arbitrary JSON value.", func(v interface{}) (interface{}, error) {
+		if raw, ok := v.(json.RawMessage); ok {
+			return raw, nil
+		}
+		return jsonCompatible(v), nil
+	}, func(v interface{}) (interface{}, error) {
+		bz, err := json.Marshal(v)
+		return json.RawMessage(bz), err
+	})
+
+	return s
+}
+
+// typeForKind returns the scalar type of the values of a kind, or nil for enum, struct, list and map kinds.
+func (s scalars) typeForKind(kind schema.Kind) *gqlType {
+	switch kind {
+	case schema.StringKind:
+		return s["String"]
+	case schema.BytesKind:
+		return s["Bytes"]
+	case schema.Int8Kind, schema.Uint8Kind, schema.Int16Kind, schema.Uint16Kind, schema.Int32Kind:
+		return s["Int"]
+	case schema.Uint32Kind:
+		return s["Uint32"]
+	case schema.Int64Kind:
+		return s["Int64"]
+	case schema.Uint64Kind:
+		return s["Uint64"]
+	case schema.IntegerStringKind:
+		return s["BigInt"]
+	case schema.DecimalStringKind:
+		return s["Decimal"]
+	case schema.BoolKind:
+		return s["Boolean"]
+	case schema.TimeKind:
+		return s["Time"]
+	case schema.DurationKind:
+		return s["Duration"]
+	case schema.Float32Kind, schema.Float64Kind:
+		return s["Float"]
+	case schema.AddressKind:
+		return s["Address"]
+	case schema.JSONKind, schema.MapKind:
+		return s["JSON"]
+	default:
+		return nil
+	}
+}
+
+// kindValue converts a value parsed by the scalar of a kind to the go type of the kind.
+func kindValue(kind schema.Kind, value interface{}) (interface{}, error) {
+	n, isInt := value.(int64)
+	f, isFloat := value.(float64)
+	switch {
+	case kind == schema.Int8Kind && isInt && n >= math.MinInt8 && n <= math.MaxInt8:
+		return int8(n), nil
+	case kind == schema.Uint8Kind && isInt && n >= 0 && n <= math.MaxUint8:
+		return uint8(n), nil
+	case kind == schema.Int16Kind && isInt && n >= math.MinInt16 && n <= math.MaxInt16:
+		return int16(n), nil
+	case kind == schema.Uint16Kind && isInt && n >= 0 && n <= math.MaxUint16:
+		return uint16(n), nil
+	case kind == schema.Int32Kind && isInt:
+		return int32(n), nil
+	case kind == schema.Float32Kind && isFloat:
+		return float32(f), nil
+	case kind == schema.Int8Kind, kind == schema.Uint8Kind, kind == schema.Int16Kind, kind == schema.Uint16Kind:
+		return nil, fmt.Errorf("%v is out of the range of %s", value, kind)
+	default:
+		return value, nil
+	}
+}
+
+func serializeAs(kind reflect.Kind) func(interface{}) (interface{}, error) {
+	return func(v interface{}) (interface{}, error) {
+		if reflect.ValueOf(v).Kind() != kind {
+			return nil, fmt.Errorf("expected %s, got %T", kind, v)
+		}
+		return v, nil
+	}
+}
+
+func serializeInt(v interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return rv.Uint(), nil
+	default:
+		return nil, fmt.Errorf("expected integer, got %T", v)
+	}
+}
+
+func serializeFloat(v interface{}) (interface{}, error) {
+	var f float64
+	switch x := v.(type) {
+	case float32:
+		f = float64(x)
+	case float64:
+		f = x
+	default:
+		return nil, fmt.Errorf("expected float, got %T", v)
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("%v can't be represented as Float", f)
+	}
+	return f, nil
+}
+
+// serializeString serializes 64-bit and arbitrary precision numbers as strings so that clients don't lose
+// precision.
+func serializeString(v interface{}) (interface{}, error) {
+	switch n := v.(type) {
+	case int64:
+		return strconv.FormatInt(n, 10), nil
+	case uint64:
+		return strconv.FormatUint(n, 10), nil
+	case string:
+		return n, nil
+	default:
+		return nil, fmt.Errorf("expected number, got %T", v)
+	}
+}
+
+// parseInt parses an integer input value, which can be a string if allowString is true.
+func parseInt(v interface{}, allowString bool) (int64, error) {
+	var str string
+	switch n := v.(type) {
+	case json.Number:
+		str = n.String()
+	case string:
+		if !allowString {
+			return 0, fmt.Errorf("expected integer, got %s", describeInput(v))
+		}
+		str = n
+	default:
+		return 0, fmt.Errorf("expected integer, got %s", describeInput(v))
+	}
+	return strconv.ParseInt(str, 10, 64)
+}
+
+// numberString returns the string of a number input value, which can be a number or string.
+func numberString(v interface{}) (string, error) {
+	switch n := v.(type) {
+	case json.Number:
+		return n.String(), nil
+	case string:
+		return n, nil
+	default:
+		return "", fmt.Errorf("expected number or string, got %s", describeInput(v))
+	}
+}
+
+func describeInput(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// jsonCompatible converts map values, which have interface{} keys, to values which can be marshaled as JSON.
+func jsonCompatible(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, v := range x {
+			m[fmt.Sprint(k)] = jsonCompatible(v)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(x))
+		for i, v := range x {
+			l[i] = jsonCompatible(v)
+		}
+		return l
+	default:
+		return v
+	}
+}
@@ -0,0 +1,637 @@
+// Package graphql generates a GraphQL schema from module schemas and serves GraphQL queries against any
+// view.AppData, such as an indexer target, without dependencies on a GraphQL library.
+package graphql
+
+import (
+	"fmt"
+	"sort"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/view"
+)
+
+// Options are the options for generating a Schema.
+type Options struct {
+	// AddressCodec is the codec which formats and parses the values of address fields. It defaults to
+	// schema.HexAddressCodec.
+	AddressCodec schema.AddressCodec
+
+	// Proofs enables the verifiable mode in which object types have a _proof field which resolves the proof
+	// of the object, so that clients can verify it against the app hash. It requires object collections
+	// which implement view.ObjectProver.
+	Proofs bool
+}
+
+// Schema is a GraphQL schema generated from module schemas, which can execute queries against any
+// view.AppData. It is safe for concurrent use.
+//
+// The Query type has a block_num field and a field for each module, whose type has these fields for each
+// object type of the module:
+//   - <object>(<key fields>): the object with the key, or null if it doesn't exist; singletons have no arguments
+//   - <object>_list(filter, order_by, limit, cursor): a page of the objects which match the filter in the
+//     order as returned by view.ObjectCollection.List, which isn't generated for singletons
+//
+// GraphQL types are named <module>_<type> for the object, enum and struct types of modules. 64-bit and
+// arbitrary precision numbers, times, durations, bytes and addresses are represented by custom scalars which
+// are serialized as strings and map fields by the JSON scalar. In verifiable mode, object types also have a
+// _proof field of type ObjectProof, see view.ObjectProof.
+type Schema struct {
+	query   *gqlType
+	types   map[string]*gqlType
+	scalars scalars
+
+	// proof is the ObjectProof type in verifiable mode, or nil
+	proof *gqlType
+
+	// introspection are the __schema and __type fields of the Query type
+	introspection []*fieldDef
+}
+
+// NewSchema generates the GraphQL schema of the modules, which are keyed by module name.
+func NewSchema(modules map[string]schema.ModuleSchema, options Options) (*Schema, error) {
+	addressCodec := options.AddressCodec
+	if addressCodec == nil {
+		addressCodec = schema.HexAddressCodec{}
+	}
+
+	s := &Schema{
+		types:   map[string]*gqlType{},
+		scalars: newScalars(addressCodec),
+	}
+	s.query = &gqlType{kind: objectKind, name: "Query"}
+	if err := s.register(s.query); err != nil {
+		return nil, err
+	}
+	s.query.fields = append(s.query.fields, &fieldDef{
+		name:        "block_num",
+		description: "The last block which was persisted.",
+		typ:         nonNull(s.scalars["Uint64"]),
+		resolve: func(ctx *execContext, _ interface{}, _ map[string]interface{}) (interface{}, error) {
+			return ctx.data.BlockNum()
+		},
+	})
+
+	if options.Proofs {
+		if err := s.addProofTypes(); err != nil {
+			return nil, err
+		}
+	}
+
+	moduleNames := make([]string, 0, len(modules))
+	for name := range modules {
+		moduleNames = append(moduleNames, name)
+	}
+	sort.Strings(moduleNames)
+	for _, name := range moduleNames {
+		if !schema.ValidateName(name) {
+			return nil, fmt.Errorf("invalid module name %q", name)
+		}
+		b := &moduleBuilder{schema: s, moduleName: name, moduleSchema: modules[name], types: map[string]*gqlType{}}
+		if err := b.build(); err != nil {
+			return nil, fmt.Errorf("module %s: %v", name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+	}
+
+	for _, name := range []string{"String", "Boolean", "Int", "Float"} {
+		if err := s.register(s.scalars[name]); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range s.types {
+		for _, f := range t.fields {
+			if err := s.registerScalars(f.typ); err != nil {
+				return nil, err
+			}
+		}
+		for _, f := range t.inputFields {
+			if err := s.registerScalars(f.typ); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.addIntrospection(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// register adds a named type to the schema.
+func (s *Schema) register(t *gqlType) error {
+	if _, ok := s.types[t.name]; ok {
+		return fmt.Errorf("duplicate GraphQL type name %q", t.name)
+	}
+	s.types[t.name] = t
+	return nil
+}
+
+// registerScalars registers the custom scalar of a type reference if it is used.
+func (s *Schema) registerScalars(t *gqlType) error {
+	named := t.namedType()
+	if named.kind != scalarKind {
+		return nil
+	}
+	if _, ok := s.types[named.name]; ok {
+		return nil
+	}
+	return s.register(named)
+}
+
+// lookupField returns the field of an object type including the introspection fields of the Query type.
+func (s *Schema) lookupField(t *gqlType, name string) *fieldDef {
+	if f := t.field(name); f != nil {
+		return f
+	}
+	if t == s.query {
+		for _, f := range s.introspection {
+			if f.name == name {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// ModuleSchemas returns the schemas of the modules of the app state, which can be used to generate a Schema
+// for the app state.
+func ModuleSchemas(state view.AppState) (map[string]schema.ModuleSchema, error) {
+	modules := map[string]schema.ModuleSchema{}
+	var err error
+	state.Modules(func(modState view.ModuleState, modErr error) bool {
+		if modErr != nil {
+			err = modErr
+			return false
+		}
+		modules[modState.ModuleName()] = modState.ModuleSchema()
+		return true
+	})
+	return modules, err
+}
+
+// moduleBuilder generates the types and query fields of a module.
+type moduleBuilder struct {
+	schema       *Schema
+	moduleName   string
+	moduleSchema schema.ModuleSchema
+
+	// types are the generated types by module type name
+	types map[string]*gqlType
+}
+
+func (b *moduleBuilder) typeName(name string) string {
+	return fmt.Sprintf("%s_%s", b.moduleName, name)
+}
+
+func (b *moduleBuilder) build() error {
+	// enum and struct types are created before their fields are generated, so that fields can refer to them
+	var err error
+	b.moduleSchema.EnumTypes(func(enumType schema.EnumType) bool {
+		t := &gqlType{
+			kind:        enumKind,
+			name:        b.typeName(enumType.Name),
+			description: enumType.Description,
+			enumValues:  enumType.Values,
+		}
+		t.serialize = func(v interface{}) (interface{}, error) {
+			str, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected string, got %T", v)
+			}
+			return str, enumType.ValidateValue(str)
+		}
+		t.parse = func(v interface{}) (interface{}, error) {
+			str, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected %s value, got %s", t.name, describeInput(v))
+			}
+			return str, enumType.ValidateValue(str)
+		}
+		b.types[enumType.Name] = t
+		err = b.schema.register(t)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var structTypes []schema.StructType
+	b.moduleSchema.StructTypes(func(structType schema.StructType) bool {
+		t := &gqlType{kind: objectKind, name: b.typeName(structType.Name)}
+		b.types[structType.Name] = t
+		structTypes = append(structTypes, structType)
+		err = b.schema.register(t)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, structType := range structTypes {
+		structType := structType
+		t := b.types[structType.Name]
+		for i, field := range structType.Fields {
+			i := i
+			fd, err := b.fieldDef(field, func(source interface{}) (interface{}, error) {
+				values := source.(structSource).values
+				if i >= len(values) {
+					return nil, fmt.Errorf("expected %d values for struct %s, got %d", len(structType.Fields), structType.Name, len(values))
+				}
+				return values[i], nil
+			})
+			if err != nil {
+				return err
+			}
+			t.fields = append(t.fields, fd)
+		}
+	}
+
+	moduleQuery := &gqlType{kind: objectKind, name: b.typeName("query")}
+	if err := b.schema.register(moduleQuery); err != nil {
+		return err
+	}
+	b.moduleSchema.ObjectTypes(func(objectType schema.ObjectType) bool {
+		var fields []*fieldDef
+		fields, err = b.objectQueryFields(objectType)
+		moduleQuery.fields = append(moduleQuery.fields, fields...)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := checkUniqueFields(moduleQuery); err != nil {
+		return err
+	}
+
+	moduleName := b.moduleName
+	b.schema.query.fields = append(b.schema.query.fields, &fieldDef{
+		name:        moduleName,
+		description: fmt.Sprintf("The state of the %s module, or null if it isn't indexed.", moduleName),
+		typ:         moduleQuery,
+		resolve: func(ctx *execContext, _ interface{}, _ map[string]interface{}) (interface{}, error) {
+			state := ctx.data.AppState()
+			if state == nil {
+				return nil, fmt.Errorf("app state isn't available")
+			}
+			modState, err := state.GetModule(moduleName)
+			if err != nil || modState == nil {
+				return nil, err
+			}
+			return modState, nil
+		},
+	})
+	return checkUniqueFields(b.schema.query)
+}
+
+// objectQueryFields generates the object type and the fields of the module query type which query it.
+func (b *moduleBuilder) objectQueryFields(objectType schema.ObjectType) ([]*fieldDef, error) {
+	t := &gqlType{kind: objectKind, name: b.typeName(objectType.Name), description: objectType.Description}
+	if err := b.schema.register(t); err != nil {
+		return nil, err
+	}
+	b.types[objectType.Name] = t
+
+	fields := append(append([]schema.Field{}, objectType.KeyFields...), objectType.ValueFields...)
+	for _, field := range fields {
+		name := field.Name
+		fd, err := b.fieldDef(field, func(source interface{}) (interface{}, error) {
+			src := source.(objectSource)
+			return view.FieldValue(src.objectType, src.update, name)
+		})
+		if err != nil {
+			return nil, err
+		}
+		t.fields = append(t.fields, fd)
+	}
+	if objectType.RetainDeletions {
+		t.fields = append(t.fields, &fieldDef{
+			name:        "_deleted",
+			description: "Whether the object was deleted.",
+			typ:         nonNull(b.schema.scalars["Boolean"]),
+			resolve: func(_ *execContext, source interface{}, _ map[string]interface{}) (interface{}, error) {
+				return source.(objectSource).update.Delete, nil
+			},
+		})
+	}
+	if b.schema.proof != nil {
+		t.fields = append(t.fields, &fieldDef{
+			name:        "_proof",
+			description: "The proof of the object, which can be verified against the app hash.",
+			typ:         b.schema.proof,
+			resolve: func(_ *execContext, source interface{}, _ map[string]interface{}) (interface{}, error) {
+				src := source.(objectSource)
+				prover, ok := src.coll.(view.ObjectProver)
+				if !ok {
+					return nil, fmt.Errorf("object type %s/%s can't be proven", b.moduleName, src.objectType.Name)
+				}
+				return prover.ProveObject(src.update.Key)
+			},
+		})
+	}
+	if err := checkUniqueFields(t); err != nil {
+		return nil, err
+	}
+
+	getField := &fieldDef{
+		name:        objectType.Name,
+		description: fmt.Sprintf("Gets the %s object with the key, or null if it doesn't exist.", objectType.Name),
+		typ:         t,
+		resolve: func(ctx *execContext, source interface{}, args map[string]interface{}) (interface{}, error) {
+			coll, err := source.(view.ModuleState).GetObjectCollection(objectType.Name)
+			if err != nil || coll == nil {
+				return nil, err
+			}
+			key, err := keyValue(objectType, args)
+			if err != nil {
+				return nil, err
+			}
+			update, found, err := coll.GetObject(key)
+			if err != nil || !found {
+				return nil, err
+			}
+			return objectSource{objectType: objectType, update: update, coll: coll}, nil
+		},
+	}
+	if len(objectType.KeyFields) == 0 {
+		getField.description = fmt.Sprintf("Gets the %s singleton, or null if it isn't set.", objectType.Name)
+		return []*fieldDef{getField}, nil
+	}
+	for _, field := range objectType.KeyFields {
+		typ, err := b.valueType(field, field.Kind)
+		if err != nil {
+			return nil, err
+		}
+		getField.args = append(getField.args, &inputValueDef{name: field.Name, description: field.Description, typ: nonNull(typ)})
+	}
+
+	listField, err := b.listField(objectType, t)
+	if err != nil {
+		return nil, err
+	}
+	return []*fieldDef{getField, listField}, nil
+}
+
+// listField generates the <object>_list field with its filter, order and page types.
+func (b *moduleBuilder) listField(objectType schema.ObjectType, objType *gqlType) (*fieldDef, error) {
+	page := &gqlType{
+		kind:        objectKind,
+		name:        b.typeName(objectType.Name + "_page"),
+		description: fmt.Sprintf("A page of %s objects.", objectType.Name),
+		fields: []*fieldDef{
+			{
+				name: "objects",
+				typ:  nonNull(listOf(nonNull(objType))),
+				resolve: func(_ *execContext, source interface{}, _ map[string]interface{}) (interface{}, error) {
+					res := source.(pageSource)
+					objects := make([]interface{}, len(res.result.Objects))
+					for i, update := range res.result.Objects {
+						objects[i] = objectSource{objectType: res.objectType, update: update, coll: res.coll}
+					}
+					return objects, nil
+				},
+			},
+			{
+				name:        "next_cursor",
+				description: "The cursor of the next page, or null if this is the last page.",
+				typ:         b.schema.scalars["String"],
+				resolve: func(_ *execContext, source interface{}, _ map[string]interface{}) (interface{}, error) {
+					cursor := source.(pageSource).result.NextCursor
+					if cursor == "" {
+						return nil, nil
+					}
+					return cursor, nil
+				},
+			},
+		},
+	}
+	if err := b.schema.register(page); err != nil {
+		return nil, err
+	}
+
+	filter := &gqlType{
+		kind:        inputObjectKind,
+		name:        b.typeName(objectType.Name + "_filter"),
+		description: fmt.Sprintf("Filters %s objects by the conditions on their fields, which must all be satisfied.", objectType.Name),
+	}
+	var orderFields []string
+	fields := append(append([]schema.Field{}, objectType.KeyFields...), objectType.ValueFields...)
+	for _, field := range fields {
+		comparison, err := b.comparisonType(field)
+		if err != nil {
+			return nil, err
+		}
+		if comparison != nil {
+			filter.inputFields = append(filter.inputFields, &inputValueDef{name: field.Name, typ: comparison})
+		}
+		if isOrderable(field) {
+			orderFields = append(orderFields, field.Name)
+		}
+	}
+
+	listField := &fieldDef{
+		name:        objectType.Name + "_list",
+		description: fmt.Sprintf("Lists the %s objects which match the filter in the order, which always ends with the key fields.", objectType.Name),
+		typ:         nonNull(page),
+		resolve: func(ctx *execContext, source interface{}, args map[string]interface{}) (interface{}, error) {
+			coll, err := source.(view.ModuleState).GetObjectCollection(objectType.Name)
+			if err != nil {
+				return nil, err
+			}
+			if coll == nil {
+				return pageSource{objectType: objectType}, nil
+			}
+			filter, order, page, err := listArgs(objectType, args)
+			if err != nil {
+				return nil, err
+			}
+			res, err := coll.List(filter, order, page)
+			if err != nil {
+				return nil, err
+			}
+			return pageSource{objectType: objectType, result: res, coll: coll}, nil
+		},
+	}
+	if len(filter.inputFields) != 0 {
+		if err := b.schema.register(filter); err != nil {
+			return nil, err
+		}
+		listField.args = append(listField.args, &inputValueDef{name: "filter", typ: filter})
+	}
+	if len(orderFields) != 0 {
+		orderField := &gqlType{kind: enumKind, name: b.typeName(objectType.Name + "_order_field"), enumValues: orderFields}
+		orderField.serialize = func(v interface{}) (interface{}, error) { return v, nil }
+		orderField.parse = func(v interface{}) (interface{}, error) {
+			str, ok := v.(string)
+			if !ok || !containsString(orderFields, str) {
+				return nil, fmt.Errorf("expected %s value, got %v", orderField.name, v)
+			}
+			return str, nil
+		}
+		order := &gqlType{
+			kind:        inputObjectKind,
+			name:        b.typeName(objectType.Name + "_order"),
+			description: fmt.Sprintf("Orders %s objects by a field.", objectType.Name),
+			inputFields: []*inputValueDef{
+				{name: "field", typ: nonNull(orderField)},
+				{name: "desc", description: "Orders in descending instead of ascending order.", typ: b.schema.scalars["Boolean"]},
+			},
+		}
+		if err := b.schema.register(orderField); err != nil {
+			return nil, err
+		}
+		if err := b.schema.register(order); err != nil {
+			return nil, err
+		}
+		listField.args = append(listField.args, &inputValueDef{name: "order_by", typ: listOf(nonNull(order))})
+	}
+	listField.args = append(listField.args,
+		&inputValueDef{
+			name:        "limit",
+			description: fmt.Sprintf("The maximum number of objects of the page, which defaults to %d.", view.DefaultPageLimit),
+			typ:         b.schema.scalars["Int"],
+		},
+		&inputValueDef{name: "cursor", description: "The next_cursor of the previous page.", typ: b.schema.scalars["String"]},
+	)
+	return listField, nil
+}
+
+// comparisonType returns the comparison input type of a field, or nil if the field can't be filtered.
+func (b *moduleBuilder) comparisonType(field schema.Field) (*gqlType, error) {
+	switch field.Kind {
+	case schema.JSONKind, schema.StructKind, schema.ListKind, schema.MapKind:
+		return nil, nil
+	}
+
+	valueType, err := b.valueType(field, field.Kind)
+	if err != nil {
+		return nil, err
+	}
+	name := valueType.name + "_comparison"
+	if t, ok := b.schema.types[name]; ok {
+		return t, nil
+	}
+
+	boolType := b.schema.scalars["Boolean"]
+	t := &gqlType{
+		kind:        inputObjectKind,
+		name:        name,
+		description: fmt.Sprintf("Compares fields of type %s. Null fields only match is_null conditions.", valueType.name),
+		inputFields: []*inputValueDef{
+			{name: "eq", typ: valueType},
+			{name: "ne", typ: valueType},
+		},
+	}
+	if isOrderedKind(field.Kind) {
+		t.inputFields = append(t.inputFields,
+			&inputValueDef{name: "lt", typ: valueType},
+			&inputValueDef{name: "lte", typ: valueType},
+			&inputValueDef{name: "gt", typ: valueType},
+			&inputValueDef{name: "gte", typ: valueType},
+		)
+	}
+	t.inputFields = append(t.inputFields,
+		&inputValueDef{name: "in", typ: listOf(nonNull(valueType))},
+		&inputValueDef{name: "is_null", typ: boolType},
+	)
+	return t, b.schema.register(t)
+}
+
+// fieldDef generates the field of an object or struct type for a schema field whose value is returned by value.
+func (b *moduleBuilder) fieldDef(field schema.Field, value func(source interface{}) (interface{}, error)) (*fieldDef, error) {
+	typ, err := b.fieldType(field)
+	if err != nil {
+		return nil, err
+	}
+	return &fieldDef{
+		name:        field.Name,
+		description: field.Description,
+		typ:         typ,
+		resolve: func(_ *execContext, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			v, err := value(source)
+			if err != nil {
+				return nil, err
+			}
+			return outputValue(field, v), nil
+		},
+	}, nil
+}
+
+// fieldType returns the output type of a field.
+func (b *moduleBuilder) fieldType(field schema.Field) (*gqlType, error) {
+	var typ *gqlType
+	if field.Kind == schema.ListKind {
+		elemType, err := b.valueType(field, field.ElementKind)
+		if err != nil {
+			return nil, err
+		}
+		if !field.NullableElements {
+			elemType = nonNull(elemType)
+		}
+		typ = listOf(elemType)
+	} else {
+		var err error
+		typ, err = b.valueType(field, field.Kind)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !field.Nullable {
+		typ = nonNull(typ)
+	}
+	return typ, nil
+}
+
+// valueType returns the named type of the values of a kind of a field.
+func (b *moduleBuilder) valueType(field schema.Field, kind schema.Kind) (*gqlType, error) {
+	var name string
+	switch kind {
+	case schema.EnumKind:
+		name = field.EnumType.Name
+	case schema.StructKind:
+		name = field.StructType.Name
+	default:
+		if t := b.schema.scalars.typeForKind(kind); t != nil {
+			return t, nil
+		}
+		return nil, fmt.Errorf("field %q has unsupported kind %s", field.Name, kind)
+	}
+
+	t, ok := b.types[name]
+	if !ok {
+		return nil, fmt.Errorf("field %q refers to unknown type %q", field.Name, name)
+	}
+	return t, nil
+}
+
+func checkUniqueFields(t *gqlType) error {
+	names := map[string]bool{}
+	for _, f := range t.fields {
+		if names[f.name] {
+			return fmt.Errorf("duplicate field %q in GraphQL type %s", f.name, t.name)
+		}
+		names[f.name] = true
+	}
+	return nil
+}
+
+// isOrderable returns true if objects can be ordered by the field, following the rules of view.ListPlan.
+func isOrderable(field schema.Field) bool {
+	return !field.Nullable && isOrderedKind(field.Kind)
+}
+
+func isOrderedKind(kind schema.Kind) bool {
+	switch kind {
+	case schema.AddressKind, schema.EnumKind, schema.JSONKind, schema.StructKind, schema.ListKind, schema.MapKind:
+		return false
+	default:
+		return true
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package graphql
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// builtinScalars are the scalars defined by the GraphQL specification, which aren't printed in the SDL.
+var builtinScalars = map[string]bool{"String": true, "Boolean": true, "Int": true, "Float": true}
+
+// SDL returns the schema in the GraphQL schema definition language.
+func (s *Schema) SDL() string {
+	buf := new(strings.Builder)
+	_ = s.WriteSDL(buf)
+	return buf.String()
+}
+
+// WriteSDL writes the schema in the GraphQL schema definition language. The Query type is written first,
+// followed by the other types in alphabetical order.
+func (s *Schema) WriteSDL(writer io.Writer) error {
+	names := make([]string, 0, len(s.types))
+	for name, t := range s.types {
+		if t == s.query || builtinScalars[name] || strings.HasPrefix(name, "__") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range append([]string{s.query.name}, names...) {
+		if i > 0 {
+			if _, err := fmt.Fprint(writer, "\n"); err != nil {
+				return err
+			}
+		}
+		if err := writeTypeSDL(writer, s.types[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTypeSDL(writer io.Writer, t *gqlType) error {
+	if err := writeDescription(writer, t.description, ""); err != nil {
+		return err
+	}
+
+	var err error
+	switch t.kind {
+	case scalarKind:
+		_, err = fmt.Fprintf(writer, "scalar %s\n", t.name)
+	case enumKind:
+		_, err = fmt.Fprintf(writer, "enum %s {\n  %s\n}\n", t.name, strings.Join(t.enumValues, "\n  "))
+	case inputObjectKind:
+		if _, err = fmt.Fprintf(writer, "input %s {\n", t.name); err != nil {
+			return err
+		}
+		for _, f := range t.inputFields {
+			if err = writeDescription(writer, f.description, "  "); err != nil {
+				return err
+			}
+			if _, err = fmt.Fprintf(writer, "  %s: %s\n", f.name, f.typ); err != nil {
+				return err
+			}
+		}
+		_, err = fmt.Fprint(writer, "}\n")
+	case objectKind:
+		if _, err = fmt.Fprintf(writer, "type %s {\n", t.name); err != nil {
+			return err
+		}
+		for _, f := range t.fields {
+			if err = writeDescription(writer, f.description, "  "); err != nil {
+				return err
+			}
+			var args []string
+			for _, arg := range f.args {
+				args = append(args, fmt.Sprintf("%s: %s", arg.name, arg.typ))
+			}
+			argList := ""
+			if len(args) != 0 {
+				argList = "(" + strings.Join(args, ", ") + ")"
+			}
+			if _, err = fmt.Fprintf(writer, "  %s%s: %s\n", f.name, argList, f.typ); err != nil {
+				return err
+			}
+		}
+		_, err = fmt.Fprint(writer, "}\n")
+	}
+	return err
+}
+
+func writeDescription(writer io.Writer, description, indent string) error {
+	if description == "" {
+		return nil
+	}
+	description = strings.Replace(description, `"""`, `\"""`, -1)
+	if !strings.Contains(description, "\n") {
+		_, err := fmt.Fprintf(writer, "%s\"\"\"%s\"\"\"\n", indent, description)
+		return err
+	}
+	lines := strings.Split(description, "\n")
+	_, err := fmt.Fprintf(writer, "%s\"\"\"\n%s%s\n%s\"\"\"\n", indent, indent, strings.Join(lines, "\n"+indent), indent)
+	return err
+}
@@ -0,0 +1,94 @@
+package graphql
+
+// typeKind is the kind of a GraphQL type as reported by introspection.
+type typeKind string
+
+const (
+	scalarKind      typeKind = "SCALAR"
+	objectKind      typeKind = "OBJECT"
+	enumKind        typeKind = "ENUM"
+	inputObjectKind typeKind = "INPUT_OBJECT"
+	listKind        typeKind = "LIST"
+	nonNullKind     typeKind = "NON_NULL"
+)
+
+// gqlType is a GraphQL type. Named types are registered in the schema, list and non-null types wrap ofType.
+type gqlType struct {
+	kind        typeKind
+	name        string
+	description string
+
+	// fields are the fields of object types
+	fields []*fieldDef
+
+	// inputFields are the fields of input object types
+	inputFields []*inputValueDef
+
+	// enumValues are the values of enum types
+	enumValues []string
+
+	// ofType is the wrapped type of list and non-null types
+	ofType *gqlType
+
+	// serialize converts a resolved value of a scalar or enum type to its JSON representation
+	serialize func(value interface{}) (interface{}, error)
+
+	// parse converts an input value of a scalar or enum type, which is a string, json.Number, bool or nil as
+	// decoded from JSON or a literal, to the resolver's value
+	parse func(value interface{}) (interface{}, error)
+}
+
+// fieldDef is a field of an object type.
+type fieldDef struct {
+	name        string
+	description string
+	args        []*inputValueDef
+	typ         *gqlType
+
+	// resolve resolves the value of the field from the value of the parent object and the coerced arguments
+	resolve func(ctx *execContext, source interface{}, args map[string]interface{}) (interface{}, error)
+}
+
+// inputValueDef is an argument of a field or a field of an input object type.
+type inputValueDef struct {
+	name        string
+	description string
+	typ         *gqlType
+}
+
+func listOf(t *gqlType) *gqlType {
+	return &gqlType{kind: listKind, ofType: t}
+}
+
+func nonNull(t *gqlType) *gqlType {
+	return &gqlType{kind: nonNullKind, ofType: t}
+}
+
+// namedType returns the named type of a possibly wrapped type.
+func (t *gqlType) namedType() *gqlType {
+	for t.ofType != nil {
+		t = t.ofType
+	}
+	return t
+}
+
+// String returns the type reference in GraphQL syntax, such as "[String!]!".
+func (t *gqlType) String() string {
+	switch t.kind {
+	case listKind:
+		return "[" + t.ofType.String() + "]"
+	case nonNullKind:
+		return t.ofType.String() + "!"
+	default:
+		return t.name
+	}
+}
+
+func (t *gqlType) field(name string) *fieldDef {
+	for _, f := range t.fields {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
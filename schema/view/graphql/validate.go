@@ -0,0 +1,149 @@
+package graphql
+
+import "fmt"
+
+// validator validates an operation and the fragments it uses against the schema.
+type validator struct {
+	schema *Schema
+	doc    *document
+	errors []*Error
+}
+
+// validate validates the selections, directives and variable definitions of an operation. Argument values are
+// validated when they are coerced during execution.
+func (s *Schema) validate(doc *document, op *operation) []*Error {
+	v := &validator{schema: s, doc: doc}
+
+	varNames := map[string]bool{}
+	for _, def := range op.varDefs {
+		if varNames[def.name] {
+			v.addError(def.loc, "there can be only one variable named $%s", def.name)
+		}
+		varNames[def.name] = true
+		if !v.isInputType(def.typ) {
+			v.addError(def.loc, "variable $%s can't be of non-input type %s", def.name, def.typ)
+		}
+	}
+
+	v.validateDirectives(op.directives)
+	v.validateSelections(s.query, op.selections, map[string]bool{})
+	return v.errors
+}
+
+func (v *validator) addError(loc Location, format string, args ...interface{}) {
+	v.errors = append(v.errors, &Error{Message: fmt.Sprintf(format, args...), Locations: []Location{loc}})
+}
+
+func (v *validator) isInputType(ref *typeRef) bool {
+	if ref.elem != nil {
+		return v.isInputType(ref.elem)
+	}
+	t, ok := v.schema.types[ref.name]
+	return ok && (t.kind == scalarKind || t.kind == enumKind || t.kind == inputObjectKind)
+}
+
+// validateSelections validates a selection set on an object type. spreads are the fragments which are being
+// spread, to detect cycles.
+func (v *validator) validateSelections(t *gqlType, selections []selection, spreads map[string]bool) {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *fieldNode:
+			v.validateDirectives(sel.directives)
+			v.validateField(t, sel, spreads)
+		case *inlineFragment:
+			v.validateDirectives(sel.directives)
+			if sel.typeCondition != "" && !v.validTypeCondition(t, sel.typeCondition, sel.loc) {
+				continue
+			}
+			v.validateSelections(t, sel.selections, spreads)
+		case *fragmentSpread:
+			v.validateDirectives(sel.directives)
+			frag, ok := v.doc.fragments[sel.name]
+			if !ok {
+				v.addError(sel.loc, "unknown fragment %q", sel.name)
+				continue
+			}
+			if spreads[sel.name] {
+				v.addError(sel.loc, "cannot spread fragment %q within itself", sel.name)
+				continue
+			}
+			if !v.validTypeCondition(t, frag.typeCondition, sel.loc) {
+				continue
+			}
+			spreads[sel.name] = true
+			v.validateSelections(t, frag.selections, spreads)
+			delete(spreads, sel.name)
+		}
+	}
+}
+
+// validTypeCondition checks that a fragment with the type condition can be spread on the object type. As the
+// schema has no interfaces or unions, the type condition must be the object type itself.
+func (v *validator) validTypeCondition(t *gqlType, typeCondition string, loc Location) bool {
+	if _, ok := v.schema.types[typeCondition]; !ok {
+		v.addError(loc, "unknown type %q", typeCondition)
+		return false
+	}
+	if typeCondition != t.name {
+		v.addError(loc, "fragment on %s can never be spread on type %s", typeCondition, t.name)
+		return false
+	}
+	return true
+}
+
+func (v *validator) validateField(t *gqlType, node *fieldNode, spreads map[string]bool) {
+	if node.name == "__typename" {
+		if node.selections != nil {
+			v.addError(node.loc, "field __typename of type String! must not have a selection")
+		}
+		return
+	}
+
+	fd := v.schema.lookupField(t, node.name)
+	if fd == nil {
+		v.addError(node.loc, "cannot query field %q on type %s", node.name, t.name)
+		return
+	}
+
+	names := map[string]bool{}
+	for _, arg := range node.args {
+		if names[arg.name] {
+			v.addError(arg.loc, "there can be only one argument named %q", arg.name)
+		}
+		names[arg.name] = true
+		found := false
+		for _, def := range fd.args {
+			found = found || def.name == arg.name
+		}
+		if !found {
+			v.addError(arg.loc, "unknown argument %q on field %s.%s", arg.name, t.name, fd.name)
+		}
+	}
+	for _, def := range fd.args {
+		if def.typ.kind == nonNullKind && !names[def.name] {
+			v.addError(node.loc, "field %s argument %q of type %s is required, but it was not provided", fd.name, def.name, def.typ)
+		}
+	}
+
+	named := fd.typ.namedType()
+	switch {
+	case named.kind == objectKind && node.selections == nil:
+		v.addError(node.loc, "field %q of type %s must have a selection of subfields", node.name, fd.typ)
+	case named.kind == objectKind:
+		v.validateSelections(named, node.selections, spreads)
+	case node.selections != nil:
+		v.addError(node.loc, "field %q must not have a selection since type %s has no subfields", node.name, fd.typ)
+	}
+}
+
+func (v *validator) validateDirectives(directives []*directive) {
+	for _, d := range directives {
+		if d.name != "skip" && d.name != "include" {
+			v.addError(d.loc, "unknown directive @%s", d.name)
+			continue
+		}
+		if len(d.args) != 1 || d.args[0].name != "if" {
+			v.addError(d.loc, "directive @%s requires exactly one argument \"if\"", d.name)
+		}
+	}
+}
@@ -0,0 +1,628 @@
+package view
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"cosmossdk.io/schema"
+)
+
+// FilterOp is the operator of a FieldCondition.
+type FilterOp int
+
+const (
+	// FilterEq matches objects whose field equals the value. A nil value matches null fields.
+	FilterEq FilterOp = iota
+
+	// FilterNotEq matches objects whose field doesn't equal the value. A nil value matches non-null fields.
+	FilterNotEq
+
+	// FilterLt matches objects whose field is less than the value.
+	FilterLt
+
+	// FilterLtEq matches objects whose field is less than or equal to the value.
+	FilterLtEq
+
+	// FilterGt matches objects whose field is greater than the value.
+	FilterGt
+
+	// FilterGtEq matches objects whose field is greater than or equal to the value.
+	FilterGtEq
+
+	// FilterIn matches objects whose field equals one of the values in a []interface{} value.
+	FilterIn
+)
+
+// String returns the name of the operator.
+func (op FilterOp) String() string {
+	switch op {
+	case FilterEq:
+		return "eq"
+	case FilterNotEq:
+		return "not_eq"
+	case FilterLt:
+		return "lt"
+	case FilterLtEq:
+		return "lt_eq"
+	case FilterGt:
+		return "gt"
+	case FilterGtEq:
+		return "gt_eq"
+	case FilterIn:
+		return "in"
+	default:
+		return fmt.Sprintf("FilterOp(%d)", int(op))
+	}
+}
+
+// FieldCondition is a condition on a key or value field of an object type.
+type FieldCondition struct {
+	// Field is the name of the field.
+	Field string
+
+	// Op is the operator which compares the field with Value.
+	Op FilterOp
+
+	// Value is the value which the field is compared with, of the go type of the field's kind, or a
+	// []interface{} of such values for FilterIn. Null fields only match FilterEq and FilterNotEq conditions
+	// with a nil value.
+	Value interface{}
+}
+
+// FieldFilter is a filter which matches objects which satisfy all of its conditions. An empty filter matches
+// all objects.
+type FieldFilter []FieldCondition
+
+// FieldOrder orders objects by a field.
+type FieldOrder struct {
+	// Field is the name of the key or value field.
+	Field string
+
+	// Descending orders objects by the field in descending instead of ascending order.
+	Descending bool
+}
+
+// OrderBy orders objects by fields in order of precedence. Objects are always ordered by their key fields in
+// ascending order after the fields of OrderBy, so that the order is total and can be paginated.
+type OrderBy []FieldOrder
+
+// Pagination selects a page of the objects of a list.
+type Pagination struct {
+	// Limit is the maximum number of objects in the page. It defaults to DefaultPageLimit if it is zero.
+	Limit int
+
+	// Cursor is the NextCursor of the previous page, or empty for the first page. Cursors are opaque and only
+	// valid for the same filter and order.
+	Cursor string
+}
+
+// DefaultPageLimit is the default value of Pagination.Limit.
+const DefaultPageLimit = 100
+
+// ListResult is a page of objects returned by ObjectCollection.List.
+type ListResult struct {
+	// Objects are the objects of the page.
+	Objects []schema.ObjectUpdate
+
+	// NextCursor is the cursor of the next page, or empty if this is the last page.
+	NextCursor string
+}
+
+// ListPlan is a validated list request for an object type, which backends use to implement
+// ObjectCollection.List consistently. It is created with PlanList.
+//
+// Filtering and ordering follow these rules, independent of the backend:
+//   - conditions can't be applied to JSON, struct, list or map fields
+//   - range conditions and OrderBy only support string, bytes, integer, integer and decimal string, bool, time,
+//     duration and float fields; strings and bytes are compared bytewise and integer and decimal strings
+//     numerically
+//   - OrderBy fields must not be nullable
+//
+// Key fields of other kinds are only used to make the order total, in a backend specific but stable order.
+type ListPlan struct {
+	// Conditions are the conditions of the filter.
+	Conditions []Condition
+
+	// Sort are the fields by which objects are ordered: the OrderBy fields followed by the remaining key fields.
+	Sort []SortField
+
+	// Limit is the maximum number of objects of the page.
+	Limit int
+
+	// After are the values of the Sort fields of the last object of the previous page, or nil for the first
+	// page. The page contains the objects which are ordered after them.
+	After []interface{}
+}
+
+// Condition is a FieldCondition with its field.
+type Condition struct {
+	Field schema.Field
+	Op    FilterOp
+	Value interface{}
+}
+
+// SortField is a field by which objects are ordered.
+type SortField struct {
+	Field      schema.Field
+	Descending bool
+}
+
+// PlanList validates the arguments of ObjectCollection.List for an object type.
+func PlanList(objectType schema.ObjectType, filter FieldFilter, order OrderBy, page Pagination) (ListPlan, error) {
+	fields := map[string]schema.Field{}
+	for _, field := range objectType.KeyFields {
+		fields[field.Name] = field
+	}
+	for _, field := range objectType.ValueFields {
+		fields[field.Name] = field
+	}
+
+	var plan ListPlan
+	for _, cond := range filter {
+		field, ok := fields[cond.Field]
+		if !ok {
+			return ListPlan{}, fmt.Errorf("unknown field %q in filter", cond.Field)
+		}
+		if err := validateCondition(field, cond); err != nil {
+			return ListPlan{}, fmt.Errorf("invalid %s condition on field %q: %v", cond.Op, cond.Field, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		plan.Conditions = append(plan.Conditions, Condition{Field: field, Op: cond.Op, Value: cond.Value})
+	}
+
+	sorted := map[string]bool{}
+	for _, o := range order {
+		field, ok := fields[o.Field]
+		if !ok {
+			return ListPlan{}, fmt.Errorf("unknown field %q in order", o.Field)
+		}
+		if sorted[o.Field] {
+			return ListPlan{}, fmt.Errorf("duplicate field %q in order", o.Field)
+		}
+		if !orderedKind(field.Kind) {
+			return ListPlan{}, fmt.Errorf("can't order by field %q of kind %s", o.Field, field.Kind)
+		}
+		if field.Nullable {
+			return ListPlan{}, fmt.Errorf("can't order by nullable field %q", o.Field)
+		}
+		sorted[o.Field] = true
+		plan.Sort = append(plan.Sort, SortField{Field: field, Descending: o.Descending})
+	}
+	for _, field := range objectType.KeyFields {
+		if !sorted[field.Name] {
+			plan.Sort = append(plan.Sort, SortField{Field: field})
+		}
+	}
+
+	switch {
+	case page.Limit < 0:
+		return ListPlan{}, fmt.Errorf("invalid page limit %d", page.Limit)
+	case page.Limit == 0:
+		plan.Limit = DefaultPageLimit
+	default:
+		plan.Limit = page.Limit
+	}
+
+	if page.Cursor != "" {
+		after, err := plan.decodeCursor(page.Cursor)
+		if err != nil {
+			return ListPlan{}, fmt.Errorf("invalid cursor: %v", err) //nolint:errorlint // false positive due to using go1.12
+		}
+		plan.After = after
+	}
+
+	return plan, nil
+}
+
+func validateCondition(field schema.Field, cond FieldCondition) error {
+	switch field.Kind {
+	case schema.JSONKind, schema.StructKind, schema.ListKind, schema.MapKind:
+		return fmt.Errorf("can't filter fields of kind %s", field.Kind)
+	}
+
+	switch cond.Op {
+	case FilterEq, FilterNotEq:
+		if cond.Value == nil {
+			if !field.Nullable {
+				return fmt.Errorf("field is not nullable")
+			}
+			return nil
+		}
+	case FilterLt, FilterLtEq, FilterGt, FilterGtEq:
+		if !orderedKind(field.Kind) {
+			return fmt.Errorf("can't compare fields of kind %s", field.Kind)
+		}
+	case FilterIn:
+		values, ok := cond.Value.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected []interface{} value, got %T", cond.Value)
+		}
+		for _, v := range values {
+			if err := nonNullField(field).ValidateValue(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown operator")
+	}
+
+	return nonNullField(field).ValidateValue(cond.Value)
+}
+
+func nonNullField(field schema.Field) schema.Field {
+	field.Nullable = false
+	return field
+}
+
+// orderedKind returns true if fields of the kind can be ordered and compared with range conditions.
+func orderedKind(kind schema.Kind) bool {
+	switch kind {
+	case schema.AddressKind, schema.EnumKind, schema.JSONKind, schema.StructKind, schema.ListKind, schema.MapKind:
+		return false
+	default:
+		return kind.Validate() == nil
+	}
+}
+
+// Matches returns true if the object satisfies the conditions of the plan.
+func (p ListPlan) Matches(objectType schema.ObjectType, update schema.ObjectUpdate) (bool, error) {
+	for _, cond := range p.Conditions {
+		value, err := FieldValue(objectType, update, cond.Field.Name)
+		if err != nil {
+			return false, err
+		}
+		if !matches(cond, value) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func matches(cond Condition, value interface{}) bool {
+	switch cond.Op {
+	case FilterEq:
+		if cond.Value == nil || value == nil {
+			return cond.Value == nil && value == nil
+		}
+		return CompareValues(cond.Field, value, cond.Value) == 0
+	case FilterNotEq:
+		if cond.Value == nil {
+			return value != nil
+		}
+		// like in SQL, null fields are neither equal nor not equal to any value
+		return value != nil && CompareValues(cond.Field, value, cond.Value) != 0
+	case FilterIn:
+		if value == nil {
+			return false
+		}
+		for _, v := range cond.Value.([]interface{}) {
+			if CompareValues(cond.Field, value, v) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	if value == nil {
+		return false
+	}
+	c := CompareValues(cond.Field, value, cond.Value)
+	switch cond.Op {
+	case FilterLt:
+		return c < 0
+	case FilterLtEq:
+		return c <= 0
+	case FilterGt:
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+// SortValues returns the values of the Sort fields of an object.
+func (p ListPlan) SortValues(objectType schema.ObjectType, update schema.ObjectUpdate) ([]interface{}, error) {
+	values := make([]interface{}, len(p.Sort))
+	for i, s := range p.Sort {
+		var err error
+		if values[i], err = FieldValue(objectType, update, s.Field.Name); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// CompareSortValues compares the values of the Sort fields of two objects in the order of the plan.
+func (p ListPlan) CompareSortValues(a, b []interface{}) int {
+	for i, s := range p.Sort {
+		c := CompareValues(s.Field, a[i], b[i])
+		if s.Descending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// Cursor returns the cursor of the page which follows an object with the sort values.
+func (p ListPlan) Cursor(sortValues []interface{}) (string, error) {
+	bz, err := json.Marshal(sortValues)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bz), nil
+}
+
+func (p ListPlan) decodeCursor(cursor string) ([]interface{}, error) {
+	bz, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(bz, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw) != len(p.Sort) {
+		return nil, fmt.Errorf("expected %d values, got %d", len(p.Sort), len(raw))
+	}
+
+	values := make([]interface{}, len(raw))
+	for i, s := range p.Sort {
+		if values[i], err = decodeValue(s.Field, raw[i]); err != nil {
+			return nil, err
+		}
+		if err := s.Field.ValidateValue(values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// decodeValue decodes a value of a sort field encoded with json.Marshal.
+func decodeValue(field schema.Field, data json.RawMessage) (interface{}, error) {
+	if field.Kind == schema.StructKind {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		if len(raw) != len(field.StructType.Fields) {
+			return nil, fmt.Errorf("expected %d values for field %q, got %d", len(field.StructType.Fields), field.Name, len(raw))
+		}
+		values := make([]interface{}, len(raw))
+		for i, structField := range field.StructType.Fields {
+			var err error
+			if values[i], err = decodeValue(structField, raw[i]); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+
+	var ptr interface{}
+	switch field.Kind {
+	case schema.StringKind, schema.IntegerStringKind, schema.DecimalStringKind, schema.EnumKind:
+		ptr = new(string)
+	case schema.BytesKind, schema.AddressKind:
+		ptr = new([]byte)
+	case schema.Int8Kind:
+		ptr = new(int8)
+	case schema.Uint8Kind:
+		ptr = new(uint8)
+	case schema.Int16Kind:
+		ptr = new(int16)
+	case schema.Uint16Kind:
+		ptr = new(uint16)
+	case schema.Int32Kind:
+		ptr = new(int32)
+	case schema.Uint32Kind:
+		ptr = new(uint32)
+	case schema.Int64Kind:
+		ptr = new(int64)
+	case schema.Uint64Kind:
+		ptr = new(uint64)
+	case schema.BoolKind:
+		ptr = new(bool)
+	case schema.TimeKind:
+		ptr = new(time.Time)
+	case schema.DurationKind:
+		ptr = new(time.Duration)
+	case schema.Float32Kind:
+		ptr = new(float32)
+	case schema.Float64Kind:
+		ptr = new(float64)
+	case schema.JSONKind:
+		ptr = new(json.RawMessage)
+	default:
+		return nil, fmt.Errorf("can't decode value of kind %s", field.Kind)
+	}
+
+	if err := json.Unmarshal(data, ptr); err != nil {
+		return nil, err
+	}
+	return reflect.ValueOf(ptr).Elem().Interface(), nil
+}
+
+// FieldValue returns the value of a key or value field of an object. The value of the object must not be
+// a schema.ValueUpdates.
+func FieldValue(objectType schema.ObjectType, update schema.ObjectUpdate, name string) (interface{}, error) {
+	for i, field := range objectType.KeyFields {
+		if field.Name == name {
+			return fieldAt(objectType.KeyFields, update.Key, i)
+		}
+	}
+	for i, field := range objectType.ValueFields {
+		if field.Name == name {
+			return fieldAt(objectType.ValueFields, update.Value, i)
+		}
+	}
+	return nil, fmt.Errorf("unknown field %q", name)
+}
+
+func fieldAt(fields []schema.Field, value interface{}, i int) (interface{}, error) {
+	if len(fields) == 1 {
+		return value, nil
+	}
+	values, ok := value.([]interface{})
+	if !ok || len(values) != len(fields) {
+		return nil, fmt.Errorf("expected slice of %d values, got %T", len(fields), value)
+	}
+	return values[i], nil
+}
+
+// CompareValues compares two non-nil values of a field, returning -1, 0 or 1. Strings, bytes, addresses and
+// JSON are compared bytewise, integer and decimal strings numerically, false is less than true and structs
+// are compared field by field. Nil values are less than all other values.
+func CompareValues(field schema.Field, a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	switch field.Kind {
+	case schema.StringKind, schema.EnumKind:
+		return strings.Compare(a.(string), b.(string))
+	case schema.BytesKind, schema.AddressKind:
+		return bytes.Compare(a.([]byte), b.([]byte))
+	case schema.JSONKind:
+		return bytes.Compare(a.(json.RawMessage), b.(json.RawMessage))
+	case schema.IntegerStringKind:
+		x, _ := new(big.Int).SetString(a.(string), 10)
+		y, _ := new(big.Int).SetString(b.(string), 10)
+		if x == nil || y == nil {
+			return strings.Compare(a.(string), b.(string))
+		}
+		return x.Cmp(y)
+	case schema.DecimalStringKind:
+		x, _ := new(big.Rat).SetString(a.(string))
+		y, _ := new(big.Rat).SetString(b.(string))
+		if x == nil || y == nil {
+			return strings.Compare(a.(string), b.(string))
+		}
+		return x.Cmp(y)
+	case schema.BoolKind:
+		x, y := a.(bool), b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		default:
+			return 1
+		}
+	case schema.TimeKind:
+		x, y := a.(time.Time), b.(time.Time)
+		switch {
+		case x.Before(y):
+			return -1
+		case x.After(y):
+			return 1
+		default:
+			return 0
+		}
+	case schema.Uint64Kind:
+		return compareOrdered(a.(uint64) < b.(uint64), a.(uint64) > b.(uint64))
+	case schema.Float32Kind:
+		return compareOrdered(a.(float32) < b.(float32), a.(float32) > b.(float32))
+	case schema.Float64Kind:
+		return compareOrdered(a.(float64) < b.(float64), a.(float64) > b.(float64))
+	case schema.StructKind:
+		x, y := a.([]interface{}), b.([]interface{})
+		for i, structField := range field.StructType.Fields {
+			if c := CompareValues(structField, x[i], y[i]); c != 0 {
+				return c
+			}
+		}
+		return 0
+	default:
+		// signed and unsigned integers which fit into int64 and durations
+		x, y := reflect.ValueOf(a), reflect.ValueOf(b)
+		if x.Kind() >= reflect.Uint && x.Kind() <= reflect.Uint64 {
+			return compareOrdered(x.Uint() < y.Uint(), x.Uint() > y.Uint())
+		}
+		return compareOrdered(x.Int() < y.Int(), x.Int() > y.Int())
+	}
+}
+
+func compareOrdered(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ListObjects implements ObjectCollection.List for collections which can't filter and order objects natively
+// by iterating over all objects of the collection with AllState.
+func ListObjects(coll ObjectCollection, filter FieldFilter, order OrderBy, page Pagination) (ListResult, error) {
+	objectType := coll.ObjectType()
+	plan, err := PlanList(objectType, filter, order, page)
+	if err != nil {
+		return ListResult{}, err
+	}
+
+	type entry struct {
+		update     schema.ObjectUpdate
+		sortValues []interface{}
+	}
+	var entries []entry
+	var iterErr error
+	coll.AllState(func(update schema.ObjectUpdate, err error) bool {
+		if err != nil {
+			iterErr = err
+			return false
+		}
+
+		ok, err := plan.Matches(objectType, update)
+		if err != nil || !ok {
+			iterErr = err
+			return err == nil
+		}
+
+		sortValues, err := plan.SortValues(objectType, update)
+		if err != nil {
+			iterErr = err
+			return false
+		}
+		if plan.After != nil && plan.CompareSortValues(sortValues, plan.After) <= 0 {
+			return true
+		}
+		entries = append(entries, entry{update: update, sortValues: sortValues})
+		return true
+	})
+	if iterErr != nil {
+		return ListResult{}, iterErr
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return plan.CompareSortValues(entries[i].sortValues, entries[j].sortValues) < 0
+	})
+
+	var res ListResult
+	if len(entries) > plan.Limit {
+		entries = entries[:plan.Limit]
+		if res.NextCursor, err = plan.Cursor(entries[len(entries)-1].sortValues); err != nil {
+			return ListResult{}, err
+		}
+	}
+	res.Objects = make([]schema.ObjectUpdate, len(entries))
+	for i, e := range entries {
+		res.Objects[i] = e.update
+	}
+	return res, nil
+}
@@ -0,0 +1,236 @@
+package view
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"cosmossdk.io/schema"
+)
+
+var testBalanceType = schema.ObjectType{
+	Name: "balances",
+	KeyFields: []schema.Field{
+		{Name: "address", Kind: schema.AddressKind},
+		{Name: "denom", Kind: schema.StringKind},
+	},
+	ValueFields: []schema.Field{
+		{Name: "amount", Kind: schema.IntegerStringKind},
+		{Name: "memo", Kind: schema.StringKind, Nullable: true},
+	},
+}
+
+type testCollection struct {
+	objects []schema.ObjectUpdate
+}
+
+func (c testCollection) ObjectType() schema.ObjectType { return testBalanceType }
+
+func (c testCollection) GetObject(interface{}) (schema.ObjectUpdate, bool, error) {
+	panic("not implemented")
+}
+
+func (c testCollection) AllState(f func(schema.ObjectUpdate, error) bool) {
+	for _, obj := range c.objects {
+		if !f(obj, nil) {
+			return
+		}
+	}
+}
+
+func (c testCollection) Len() (int, error) { return len(c.objects), nil }
+
+func (c testCollection) List(filter FieldFilter, order OrderBy, page Pagination) (ListResult, error) {
+	return ListObjects(c, filter, order, page)
+}
+
+func balance(addr byte, denom, amount string, memo interface{}) schema.ObjectUpdate {
+	return schema.ObjectUpdate{
+		TypeName: "balances",
+		Key:      []interface{}{[]byte{addr}, denom},
+		Value:    []interface{}{amount, memo},
+	}
+}
+
+var testBalances = testCollection{objects: []schema.ObjectUpdate{
+	balance(2, "stake", "100", nil),
+	balance(1, "stake", "9", "a"),
+	balance(1, "atom", "1000", nil),
+	balance(3, "atom", "100", "b"),
+	balance(2, "atom", "-5", nil),
+}}
+
+func TestListObjects(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   FieldFilter
+		order    OrderBy
+		expected []schema.ObjectUpdate
+	}{
+		{
+			name: "key order",
+			expected: []schema.ObjectUpdate{
+				balance(1, "atom", "1000", nil),
+				balance(1, "stake", "9", "a"),
+				balance(2, "atom", "-5", nil),
+				balance(2, "stake", "100", nil),
+				balance(3, "atom", "100", "b"),
+			},
+		},
+		{
+			name:   "numeric order of integer strings",
+			order:  OrderBy{{Field: "amount", Descending: true}},
+			filter: FieldFilter{{Field: "amount", Op: FilterGtEq, Value: "9"}},
+			expected: []schema.ObjectUpdate{
+				balance(1, "atom", "1000", nil),
+				balance(2, "stake", "100", nil),
+				balance(3, "atom", "100", "b"),
+				balance(1, "stake", "9", "a"),
+			},
+		},
+		{
+			name:   "null and in conditions",
+			filter: FieldFilter{{Field: "memo", Op: FilterEq}, {Field: "denom", Op: FilterIn, Value: []interface{}{"atom", "osmo"}}},
+			expected: []schema.ObjectUpdate{
+				balance(1, "atom", "1000", nil),
+				balance(2, "atom", "-5", nil),
+			},
+		},
+		{
+			name:   "not null",
+			filter: FieldFilter{{Field: "memo", Op: FilterNotEq}, {Field: "memo", Op: FilterNotEq, Value: "b"}},
+			expected: []schema.ObjectUpdate{
+				balance(1, "stake", "9", "a"),
+			},
+		},
+		{
+			name:   "null is not unequal",
+			filter: FieldFilter{{Field: "memo", Op: FilterNotEq, Value: "a"}},
+			expected: []schema.ObjectUpdate{
+				balance(3, "atom", "100", "b"),
+			},
+		},
+		{
+			name:   "range conditions don't match null",
+			filter: FieldFilter{{Field: "memo", Op: FilterLt, Value: "z"}},
+			expected: []schema.ObjectUpdate{
+				balance(1, "stake", "9", "a"),
+				balance(3, "atom", "100", "b"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// every page size must return the same objects
+			for limit := 1; limit <= len(testBalances.objects)+1; limit++ {
+				var objects []schema.ObjectUpdate
+				page := Pagination{Limit: limit}
+				for {
+					res, err := testBalances.List(tt.filter, tt.order, page)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(res.Objects) > limit {
+						t.Fatalf("expected at most %d objects, got %d", limit, len(res.Objects))
+					}
+					objects = append(objects, res.Objects...)
+					if res.NextCursor == "" {
+						break
+					}
+					page.Cursor = res.NextCursor
+				}
+				if !reflect.DeepEqual(objects, tt.expected) {
+					t.Fatalf("limit %d: expected %v, got %v", limit, tt.expected, objects)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanList_invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      FieldFilter
+		order       OrderBy
+		page        Pagination
+		errContains string
+	}{
+		{
+			name:        "unknown filter field",
+			filter:      FieldFilter{{Field: "foo", Value: "bar"}},
+			errContains: `unknown field "foo" in filter`,
+		},
+		{
+			name:        "wrong value type",
+			filter:      FieldFilter{{Field: "amount", Op: FilterGt, Value: 1}},
+			errContains: `invalid gt condition on field "amount"`,
+		},
+		{
+			name:        "range condition on address",
+			filter:      FieldFilter{{Field: "address", Op: FilterLt, Value: []byte{1}}},
+			errContains: "can't compare fields of kind bech32address",
+		},
+		{
+			name:        "null value of non-nullable field",
+			filter:      FieldFilter{{Field: "denom", Op: FilterEq}},
+			errContains: "field is not nullable",
+		},
+		{
+			name:        "in without slice",
+			filter:      FieldFilter{{Field: "denom", Op: FilterIn, Value: "atom"}},
+			errContains: "expected []interface{} value",
+		},
+		{
+			name:        "order by nullable field",
+			order:       OrderBy{{Field: "memo"}},
+			errContains: `can't order by nullable field "memo"`,
+		},
+		{
+			name:        "order by address",
+			order:       OrderBy{{Field: "address"}},
+			errContains: `can't order by field "address" of kind bech32address`,
+		},
+		{
+			name:        "duplicate order field",
+			order:       OrderBy{{Field: "amount"}, {Field: "amount", Descending: true}},
+			errContains: `duplicate field "amount" in order`,
+		},
+		{
+			name:        "negative limit",
+			page:        Pagination{Limit: -1},
+			errContains: "invalid page limit -1",
+		},
+		{
+			name:        "invalid cursor",
+			page:        Pagination{Cursor: "!"},
+			errContains: "invalid cursor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := PlanList(testBalanceType, tt.filter, tt.order, tt.page)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error containing %q, got: %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+func TestPlanList_cursorMismatch(t *testing.T) {
+	plan, err := PlanList(testBalanceType, nil, OrderBy{{Field: "amount"}}, Pagination{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cursor, err := plan.Cursor([]interface{}{"100", []byte{1}, "atom"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// cursors are only valid for the same order
+	_, err = PlanList(testBalanceType, nil, nil, Pagination{Cursor: cursor})
+	if err == nil || !strings.Contains(err.Error(), "expected 2 values, got 3") {
+		t.Fatalf("expected cursor error, got: %v", err)
+	}
+}
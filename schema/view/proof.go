@@ -0,0 +1,48 @@
+package view
+
+// ObjectProof is a commitment proof of the state of an object, which lets clients treat a backend such as an
+// indexer as an untrusted cache by verifying the objects it serves against the app hash of the chain, for
+// instance one obtained from a light client.
+type ObjectProof struct {
+	// Height is the height of the proven state. Its app hash is included in the header of the block at
+	// Height+1.
+	Height uint64
+
+	// AppHash is the app hash which the proof commits to, if the backend knows it. Clients must not trust
+	// it without checking it against a block header.
+	AppHash []byte
+
+	// StoreKey is the name of the store which holds the kv-pair from which the object was decoded.
+	StoreKey string
+
+	// Key is the key of the kv-pair in the store.
+	Key []byte
+
+	// Value is the value of the kv-pair at Height, or nil if the proof is a proof of absence.
+	Value []byte
+
+	// Ops are the commitment proof operations, such as the ICS23 proofs of the kv-pair in its store and of
+	// the store in the app state, which chain the kv-pair to the app hash.
+	Ops []ProofOp
+}
+
+// ProofOp is a commitment proof operation.
+type ProofOp struct {
+	// Type is the type of the proof, such as "ics23:iavl" or "ics23:simple".
+	Type string
+
+	// Key is the key which the operation proves.
+	Key []byte
+
+	// Data is the encoded proof.
+	Data []byte
+}
+
+// ObjectProver is implemented by the object collections of backends which can prove the state of their
+// objects. The REST and GraphQL layers attach proofs to their responses in verifiable mode when object
+// collections implement it.
+type ObjectProver interface {
+	// ProveObject returns the proof of the state of the object with the key at the height of the data of the
+	// collection, so that the proof matches the object returned by GetObject.
+	ProveObject(key interface{}) (ObjectProof, error)
+}
@@ -0,0 +1,137 @@
+// Package rest generates REST routes and an OpenAPI document from module schemas and serves them against any
+// view.AppData, such as an indexer target.
+package rest
+
+import (
+	"fmt"
+	"sort"
+
+	"cosmossdk.io/schema"
+)
+
+// Options are the options for generating an API.
+type Options struct {
+	// AddressCodec is the codec which formats and parses the values of address fields. It defaults to
+	// schema.HexAddressCodec.
+	AddressCodec schema.AddressCodec
+
+	// Title is the title of the OpenAPI document. It defaults to "App State".
+	Title string
+
+	// Version is the version of the OpenAPI document. It defaults to "1.0.0".
+	Version string
+
+	// Proofs enables the verifiable mode in which clients can add the prove=true query parameter to object
+	// requests to receive the proof of each returned object in its _proof property, so that they can verify
+	// the objects against the app hash. It requires object collections which implement view.ObjectProver.
+	Proofs bool
+}
+
+// API is a REST API generated from module schemas. It has these routes for each object type:
+//   - GET /{module}/{object}: a page of the objects which match the filter in the query parameters, or the
+//     object of singletons
+//   - GET /{module}/{object}/{key fields...}: the object with the key, with one path segment per key field
+//
+// Objects can be filtered and ordered by their key fields and the value fields of the object type's indexes,
+// so that backends can serve list requests efficiently. If Options.Proofs is set, both routes accept the prove
+// query parameter, see view.ObjectProof. The OpenAPI document describing the routes is served
+// at GET /openapi.json.
+type API struct {
+	addressCodec schema.AddressCodec
+	options      Options
+
+	// objects are the routes of the object types by module and object type name
+	objects map[string]map[string]*objectRoute
+
+	// moduleNames are the names of the modules in alphabetical order
+	moduleNames []string
+}
+
+// objectRoute are the routes of an object type.
+type objectRoute struct {
+	moduleName string
+	objectType schema.ObjectType
+
+	// filterFields are the fields which can be used to filter and order objects in the order of the object type
+	filterFields []schema.Field
+}
+
+// NewAPI generates the REST API of the modules, which are keyed by module name.
+func NewAPI(modules map[string]schema.ModuleSchema, options Options) (*API, error) {
+	a := &API{
+		addressCodec: options.AddressCodec,
+		options:      options,
+		objects:      map[string]map[string]*objectRoute{},
+	}
+	if a.addressCodec == nil {
+		a.addressCodec = schema.HexAddressCodec{}
+	}
+	if a.options.Title == "" {
+		a.options.Title = "App State"
+	}
+	if a.options.Version == "" {
+		a.options.Version = "1.0.0"
+	}
+
+	for name, modSchema := range modules {
+		if !schema.ValidateName(name) {
+			return nil, fmt.Errorf("invalid module name %q", name)
+		}
+		a.moduleNames = append(a.moduleNames, name)
+		routes := map[string]*objectRoute{}
+		modSchema.ObjectTypes(func(objectType schema.ObjectType) bool {
+			routes[objectType.Name] = newObjectRoute(name, objectType)
+			return true
+		})
+		a.objects[name] = routes
+	}
+	sort.Strings(a.moduleNames)
+	return a, nil
+}
+
+func newObjectRoute(moduleName string, objectType schema.ObjectType) *objectRoute {
+	indexed := map[string]bool{}
+	for _, index := range objectType.Indexes {
+		for _, field := range index.Fields {
+			indexed[field.Name] = true
+		}
+	}
+
+	route := &objectRoute{moduleName: moduleName, objectType: objectType}
+	for _, field := range objectType.KeyFields {
+		if filterableKind(field.Kind) {
+			route.filterFields = append(route.filterFields, field)
+		}
+	}
+	for _, field := range objectType.ValueFields {
+		if indexed[field.Name] && filterableKind(field.Kind) {
+			route.filterFields = append(route.filterFields, field)
+		}
+	}
+	return route
+}
+
+func (r *objectRoute) filterField(name string) (schema.Field, bool) {
+	for _, field := range r.filterFields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return schema.Field{}, false
+}
+
+// filterableKind returns true if fields of the kind can be used in filters, following the rules of view.ListPlan.
+func filterableKind(kind schema.Kind) bool {
+	switch kind {
+	case schema.JSONKind, schema.StructKind, schema.ListKind, schema.MapKind:
+		return false
+	default:
+		return true
+	}
+}
+
+// orderedKind returns true if fields of the kind can be compared with range conditions and ordered, following
+// the rules of view.ListPlan.
+func orderedKind(kind schema.Kind) bool {
+	return filterableKind(kind) && kind != schema.AddressKind && kind != schema.EnumKind
+}
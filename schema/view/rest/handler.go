@@ -0,0 +1,399 @@
+package rest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/view"
+)
+
+// filterOps maps the operator suffixes of filter query parameters to filter operators.
+var filterOps = map[string]view.FilterOp{
+	"eq":  view.FilterEq,
+	"ne":  view.FilterNotEq,
+	"lt":  view.FilterLt,
+	"lte": view.FilterLtEq,
+	"gt":  view.FilterGt,
+	"gte": view.FilterGtEq,
+	"in":  view.FilterIn,
+}
+
+// httpError is an error with the HTTP status of the response.
+type httpError struct {
+	status int
+	msg    string
+}
+
+func (e httpError) Error() string {
+	return e.msg
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return httpError{status: http.StatusBadRequest, msg: fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...interface{}) error {
+	return httpError{status: http.StatusNotFound, msg: fmt.Sprintf(format, args...)}
+}
+
+// Handler returns an HTTP handler which serves the routes of the API against the app data.
+func (a *API) Handler(data view.AppData) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			writeJSON(w, http.StatusMethodNotAllowed, errorBody("method not allowed"))
+			return
+		}
+
+		res, err := a.serve(data, r)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if httpErr, ok := err.(httpError); ok { //nolint:errorlint // false positive due to using go1.12
+				status = httpErr.status
+			}
+			writeJSON(w, status, errorBody(err.Error()))
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	})
+}
+
+func (a *API) serve(data view.AppData, r *http.Request) (interface{}, error) {
+	var segments []string
+	for _, s := range strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/") {
+		segment, err := url.PathUnescape(s)
+		if err != nil {
+			return nil, badRequest("invalid path: %v", err)
+		}
+		segments = append(segments, segment)
+	}
+
+	if len(segments) == 1 && segments[0] == "openapi.json" {
+		return a.OpenAPI(), nil
+	}
+	if len(segments) < 2 {
+		return nil, notFound("unknown route %s", r.URL.Path)
+	}
+
+	route, ok := a.objects[segments[0]][segments[1]]
+	if !ok {
+		return nil, notFound("unknown object type %s/%s", segments[0], segments[1])
+	}
+	keySegments := segments[2:]
+	if len(keySegments) != 0 && len(keySegments) != len(route.objectType.KeyFields) {
+		return nil, notFound("expected %d key segments for %s/%s, got %d",
+			len(route.objectType.KeyFields), route.moduleName, route.objectType.Name, len(keySegments))
+	}
+
+	prove, err := a.proveParam(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	coll, err := objectCollection(data, route)
+	if err != nil {
+		return nil, err
+	}
+
+	var prover view.ObjectProver
+	if prove {
+		var ok bool
+		if prover, ok = coll.(view.ObjectProver); !ok {
+			return nil, httpError{status: http.StatusNotImplemented, msg: fmt.Sprintf("object type %s/%s can't be proven", route.moduleName, route.objectType.Name)}
+		}
+	}
+
+	if len(keySegments) == 0 && len(route.objectType.KeyFields) != 0 {
+		return a.list(route, coll, prover, r.URL.Query())
+	}
+	return a.get(route, coll, prover, keySegments)
+}
+
+// proveParam parses the prove query parameter, which is only accepted in verifiable mode.
+func (a *API) proveParam(query url.Values) (bool, error) {
+	values, ok := query["prove"]
+	if !ok || !a.options.Proofs {
+		return false, nil
+	}
+	prove, err := strconv.ParseBool(values[0])
+	if err != nil {
+		return false, badRequest("invalid prove parameter %q", values[0])
+	}
+	return prove, nil
+}
+
+func objectCollection(data view.AppData, route *objectRoute) (view.ObjectCollection, error) {
+	state := data.AppState()
+	if state == nil {
+		return nil, httpError{status: http.StatusServiceUnavailable, msg: "app state isn't available"}
+	}
+	modState, err := state.GetModule(route.moduleName)
+	if err != nil {
+		return nil, err
+	}
+	if modState == nil {
+		return nil, notFound("module %s isn't indexed", route.moduleName)
+	}
+	coll, err := modState.GetObjectCollection(route.objectType.Name)
+	if err != nil {
+		return nil, err
+	}
+	if coll == nil {
+		return nil, notFound("object type %s/%s isn't indexed", route.moduleName, route.objectType.Name)
+	}
+	return coll, nil
+}
+
+// get returns the object with the key in the key segments, or the singleton if there are none. If prover is
+// not nil, the proof of the object is attached.
+func (a *API) get(route *objectRoute, coll view.ObjectCollection, prover view.ObjectProver, keySegments []string) (interface{}, error) {
+	keyFields := route.objectType.KeyFields
+	values := make([]interface{}, len(keyFields))
+	for i, field := range keyFields {
+		v, err := a.parseValue(field, keySegments[i])
+		if err != nil {
+			return nil, badRequest("invalid key field %q: %v", field.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		values[i] = v
+	}
+
+	var key interface{}
+	switch len(values) {
+	case 0:
+	case 1:
+		key = values[0]
+	default:
+		key = values
+	}
+	update, found, err := coll.GetObject(key)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, notFound("object not found")
+	}
+	return a.provenObjectJSON(route.objectType, update, prover)
+}
+
+// list returns a page of the objects which match the filter in the query parameters. If prover is not nil, the
+// proof of each object is attached.
+func (a *API) list(route *objectRoute, coll view.ObjectCollection, prover view.ObjectProver, query url.Values) (interface{}, error) {
+	filter, order, page, err := a.listParams(route, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// the request is validated first so that invalid requests can be distinguished from backend errors
+	if _, err := view.PlanList(route.objectType, filter, order, page); err != nil {
+		return nil, badRequest("%v", err)
+	}
+	res, err := coll.List(filter, order, page)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]interface{}, len(res.Objects))
+	for i, update := range res.Objects {
+		if objects[i], err = a.provenObjectJSON(route.objectType, update, prover); err != nil {
+			return nil, err
+		}
+	}
+	body := &orderedObject{}
+	body.add("objects", objects)
+	if res.NextCursor != "" {
+		body.add("next_cursor", res.NextCursor)
+	} else {
+		body.add("next_cursor", nil)
+	}
+	return body, nil
+}
+
+// listParams parses the query parameters of list requests:
+//   - <field>=<value> and <field>.<op>=<value> add conditions, where op is one of eq, ne, lt, lte, gt, gte and
+//     in; in conditions can be repeated for each value
+//   - <field>.is_null=<bool> adds a condition which matches null or non-null fields
+//   - order_by=<field>,-<field> orders objects by fields, in descending order if prefixed by -
+//   - limit and cursor select the page
+func (a *API) listParams(route *objectRoute, query url.Values) (view.FieldFilter, view.OrderBy, view.Pagination, error) {
+	var filter view.FieldFilter
+	var order view.OrderBy
+	var page view.Pagination
+
+	params := make([]string, 0, len(query))
+	for param := range query {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+	for _, param := range params {
+		values := query[param]
+		switch param {
+		case "limit":
+			limit, err := strconv.Atoi(values[0])
+			if err != nil || limit <= 0 {
+				return nil, nil, page, badRequest("invalid limit %q", values[0])
+			}
+			page.Limit = limit
+			continue
+		case "cursor":
+			page.Cursor = values[0]
+			continue
+		case "prove":
+			if a.options.Proofs {
+				continue
+			}
+		case "order_by":
+			for _, name := range strings.Split(values[0], ",") {
+				desc := strings.HasPrefix(name, "-")
+				name = strings.TrimPrefix(name, "-")
+				if _, ok := route.filterField(name); !ok {
+					return nil, nil, page, badRequest("can't order by field %q which isn't a key or indexed field", name)
+				}
+				order = append(order, view.FieldOrder{Field: name, Descending: desc})
+			}
+			continue
+		}
+
+		name, opName := param, "eq"
+		if i := strings.LastIndexByte(param, '.'); i >= 0 {
+			name, opName = param[:i], param[i+1:]
+		}
+		field, ok := route.filterField(name)
+		if !ok {
+			return nil, nil, page, badRequest("unknown query parameter %q, only key and indexed fields can be filtered", param)
+		}
+
+		cond, err := a.condition(field, opName, values)
+		if err != nil {
+			return nil, nil, page, badRequest("invalid query parameter %q: %v", param, err) //nolint:errorlint // false positive due to using go1.12
+		}
+		filter = append(filter, cond)
+	}
+	return filter, order, page, nil
+}
+
+func (a *API) condition(field schema.Field, opName string, values []string) (view.FieldCondition, error) {
+	cond := view.FieldCondition{Field: field.Name}
+	if opName == "is_null" {
+		isNull, err := strconv.ParseBool(values[0])
+		if err != nil {
+			return cond, err
+		}
+		if !isNull {
+			cond.Op = view.FilterNotEq
+		}
+		return cond, nil
+	}
+
+	op, ok := filterOps[opName]
+	if !ok {
+		return cond, fmt.Errorf("unknown operator %q", opName)
+	}
+	cond.Op = op
+	if op == view.FilterIn {
+		in := make([]interface{}, len(values))
+		for i, str := range values {
+			v, err := a.parseValue(field, str)
+			if err != nil {
+				return cond, err
+			}
+			in[i] = v
+		}
+		cond.Value = in
+		return cond, nil
+	}
+
+	if len(values) != 1 {
+		return cond, fmt.Errorf("expected one value, use %s.in to match several values", field.Name)
+	}
+	v, err := a.parseValue(field, values[0])
+	cond.Value = v
+	return cond, err
+}
+
+// objectJSON converts an object to its JSON representation, which has the key and value fields as properties
+// and a _deleted property for object types which retain deletions.
+func (a *API) objectJSON(objectType schema.ObjectType, update schema.ObjectUpdate) (*orderedObject, error) {
+	fields := append(append([]schema.Field{}, objectType.KeyFields...), objectType.ValueFields...)
+	values := make([]interface{}, len(fields))
+	for i, field := range fields {
+		v, err := view.FieldValue(objectType, update, field.Name)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+
+	var extra *orderedObject
+	if objectType.RetainDeletions {
+		extra = &orderedObject{}
+		extra.add("_deleted", update.Delete)
+	}
+	return a.jsonObject(fields, values, extra)
+}
+
+// provenObjectJSON converts an object to its JSON representation and, if prover is not nil, attaches the proof
+// of the object in the _proof property.
+func (a *API) provenObjectJSON(objectType schema.ObjectType, update schema.ObjectUpdate, prover view.ObjectProver) (*orderedObject, error) {
+	obj, err := a.objectJSON(objectType, update)
+	if err != nil || prover == nil {
+		return obj, err
+	}
+
+	proof, err := prover.ProveObject(update.Key)
+	if err != nil {
+		return nil, err
+	}
+	obj.add("_proof", proofJSON(proof))
+	return obj, nil
+}
+
+// proofJSON converts a proof to its JSON representation, in which bytes are encoded in base64 and the height
+// is a string like other 64-bit integers.
+func proofJSON(proof view.ObjectProof) *orderedObject {
+	bytesJSON := func(bz []byte) interface{} {
+		if bz == nil {
+			return nil
+		}
+		return base64.StdEncoding.EncodeToString(bz)
+	}
+
+	ops := make([]interface{}, len(proof.Ops))
+	for i, op := range proof.Ops {
+		opObj := &orderedObject{}
+		opObj.add("type", op.Type)
+		opObj.add("key", bytesJSON(op.Key))
+		opObj.add("data", bytesJSON(op.Data))
+		ops[i] = opObj
+	}
+
+	obj := &orderedObject{}
+	obj.add("height", strconv.FormatUint(proof.Height, 10))
+	obj.add("app_hash", bytesJSON(proof.AppHash))
+	obj.add("store_key", proof.StoreKey)
+	obj.add("key", bytesJSON(proof.Key))
+	obj.add("value", bytesJSON(proof.Value))
+	obj.add("ops", ops)
+	return obj
+}
+
+func errorBody(msg string) interface{} {
+	return map[string]string{"error": msg}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	bz, err := json.Marshal(body)
+	if err != nil {
+		status = http.StatusInternalServerError
+		bz, _ = json.Marshal(errorBody(err.Error()))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(bz)
+}
@@ -0,0 +1,377 @@
+package rest
+
+import (
+	"fmt"
+	"strings"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/view"
+)
+
+// OpenAPI returns the OpenAPI 3.0 document of the API, which can be marshaled as JSON. Component schemas are
+// named <module>.<type> for the object, enum and struct types of modules.
+func (a *API) OpenAPI() map[string]interface{} {
+	paths := map[string]interface{}{
+		"/openapi.json": map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": "openapi",
+				"summary":     "Returns this OpenAPI document.",
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{"description": "The OpenAPI document."},
+				},
+			},
+		},
+	}
+	schemas := map[string]interface{}{
+		"Error": map[string]interface{}{
+			"type":       "object",
+			"required":   []string{"error"},
+			"properties": map[string]interface{}{"error": map[string]interface{}{"type": "string"}},
+		},
+	}
+
+	if a.options.Proofs {
+		addProofSchemas(schemas)
+	}
+
+	for _, moduleName := range a.moduleNames {
+		routes := a.objects[moduleName]
+		for _, route := range routes {
+			a.addObjectPaths(paths, schemas, route)
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   a.options.Title,
+			"version": a.options.Version,
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+}
+
+func (a *API) addObjectPaths(paths, schemas map[string]interface{}, route *objectRoute) {
+	objectType := route.objectType
+	name := componentName(route.moduleName, objectType.Name)
+	basePath := fmt.Sprintf("/%s/%s", route.moduleName, objectType.Name)
+
+	fields := append(append([]schema.Field{}, objectType.KeyFields...), objectType.ValueFields...)
+	properties := map[string]interface{}{}
+	var required []string
+	for _, field := range fields {
+		properties[field.Name] = a.fieldSchema(schemas, route.moduleName, field)
+		required = append(required, field.Name)
+	}
+	if objectType.RetainDeletions {
+		properties["_deleted"] = map[string]interface{}{"type": "boolean", "description": "Whether the object was deleted."}
+		required = append(required, "_deleted")
+	}
+	if a.options.Proofs {
+		properties["_proof"] = ref("ObjectProof")
+	}
+	objectSchema := map[string]interface{}{"type": "object", "properties": properties, "required": required}
+	if objectType.Description != "" {
+		objectSchema["description"] = objectType.Description
+	}
+	schemas[name] = objectSchema
+
+	errorResponses := func(responses map[string]interface{}) map[string]interface{} {
+		responses["default"] = map[string]interface{}{
+			"description": "An error.",
+			"content":     jsonContent(ref("Error")),
+		}
+		return responses
+	}
+
+	if len(objectType.KeyFields) == 0 {
+		get := map[string]interface{}{
+			"operationId": fmt.Sprintf("get_%s_%s", route.moduleName, objectType.Name),
+			"summary":     fmt.Sprintf("Returns the %s singleton.", objectType.Name),
+			"responses": errorResponses(map[string]interface{}{
+				"200": map[string]interface{}{"description": fmt.Sprintf("The %s singleton.", objectType.Name), "content": jsonContent(ref(name))},
+			}),
+		}
+		if a.options.Proofs {
+			get["parameters"] = a.proveParams(nil)
+		}
+		paths[basePath] = map[string]interface{}{"get": get}
+		return
+	}
+
+	keyPath := basePath
+	var keyParams []interface{}
+	for _, field := range objectType.KeyFields {
+		keyPath += fmt.Sprintf("/{%s}", field.Name)
+		keyParams = append(keyParams, map[string]interface{}{
+			"name":     field.Name,
+			"in":       "path",
+			"required": true,
+			"schema":   a.paramSchema(schemas, route.moduleName, field),
+		})
+	}
+	paths[keyPath] = map[string]interface{}{
+		"get": map[string]interface{}{
+			"operationId": fmt.Sprintf("get_%s_%s", route.moduleName, objectType.Name),
+			"summary":     fmt.Sprintf("Returns the %s object with the key.", objectType.Name),
+			"parameters":  a.proveParams(keyParams),
+			"responses": errorResponses(map[string]interface{}{
+				"200": map[string]interface{}{"description": fmt.Sprintf("The %s object.", objectType.Name), "content": jsonContent(ref(name))},
+			}),
+		},
+	}
+
+	pageName := name + "_page"
+	schemas[pageName] = map[string]interface{}{
+		"type":     "object",
+		"required": []string{"objects", "next_cursor"},
+		"properties": map[string]interface{}{
+			"objects": map[string]interface{}{"type": "array", "items": ref(name)},
+			"next_cursor": map[string]interface{}{
+				"type":        "string",
+				"nullable":    true,
+				"description": "The cursor of the next page, or null if this is the last page.",
+			},
+		},
+	}
+
+	var orderFields []string
+	listParams := []interface{}{}
+	for _, field := range route.filterFields {
+		schema := a.paramSchema(schemas, route.moduleName, field)
+		ops := []string{"eq", "ne", "in"}
+		if orderedKind(field.Kind) {
+			ops = append(ops, "lt", "lte", "gt", "gte")
+			if !field.Nullable {
+				orderFields = append(orderFields, field.Name)
+			}
+		}
+		listParams = append(listParams, map[string]interface{}{
+			"name":        field.Name,
+			"in":          "query",
+			"description": fmt.Sprintf("Matches objects whose %s equals the value.", field.Name),
+			"schema":      schema,
+		})
+		for _, op := range ops {
+			param := map[string]interface{}{
+				"name":        fmt.Sprintf("%s.%s", field.Name, op),
+				"in":          "query",
+				"description": fmt.Sprintf("Matches objects whose %s is %s the value.", field.Name, opDescriptions[op]),
+				"schema":      schema,
+			}
+			if op == "in" {
+				param["description"] = fmt.Sprintf("Matches objects whose %s equals one of the values.", field.Name)
+				param["schema"] = map[string]interface{}{"type": "array", "items": schema}
+				param["explode"] = true
+			}
+			listParams = append(listParams, param)
+		}
+		if field.Nullable {
+			listParams = append(listParams, map[string]interface{}{
+				"name":        field.Name + ".is_null",
+				"in":          "query",
+				"description": fmt.Sprintf("Matches objects whose %s is null if true or not null if false.", field.Name),
+				"schema":      map[string]interface{}{"type": "boolean"},
+			})
+		}
+	}
+	if len(orderFields) != 0 {
+		listParams = append(listParams, map[string]interface{}{
+			"name": "order_by",
+			"in":   "query",
+			"description": fmt.Sprintf("Comma separated fields by which objects are ordered before their key fields, in descending order if prefixed by -. One of %s.",
+				strings.Join(orderFields, ", ")),
+			"schema": map[string]interface{}{"type": "string"},
+		})
+	}
+	listParams = append(listParams,
+		map[string]interface{}{
+			"name":        "limit",
+			"in":          "query",
+			"description": fmt.Sprintf("The maximum number of objects of the page, which defaults to %d.", view.DefaultPageLimit),
+			"schema":      map[string]interface{}{"type": "integer", "minimum": 1},
+		},
+		map[string]interface{}{
+			"name":        "cursor",
+			"in":          "query",
+			"description": "The next_cursor of the previous page.",
+			"schema":      map[string]interface{}{"type": "string"},
+		},
+	)
+	paths[basePath] = map[string]interface{}{
+		"get": map[string]interface{}{
+			"operationId": fmt.Sprintf("list_%s_%s", route.moduleName, objectType.Name),
+			"summary":     fmt.Sprintf("Lists the %s objects which match the filter.", objectType.Name),
+			"parameters":  a.proveParams(listParams),
+			"responses": errorResponses(map[string]interface{}{
+				"200": map[string]interface{}{"description": fmt.Sprintf("A page of %s objects.", objectType.Name), "content": jsonContent(ref(pageName))},
+			}),
+		},
+	}
+}
+
+var opDescriptions = map[string]string{
+	"eq":  "equal to",
+	"ne":  "not equal to",
+	"lt":  "less than",
+	"lte": "less than or equal to",
+	"gt":  "greater than",
+	"gte": "greater than or equal to",
+}
+
+// paramSchema returns the schema of a field's value in path and query parameters, which are never null.
+func (a *API) paramSchema(schemas map[string]interface{}, moduleName string, field schema.Field) map[string]interface{} {
+	field.Nullable = false
+	return a.fieldSchema(schemas, moduleName, field)
+}
+
+// fieldSchema returns the schema of the JSON representation of a field's values and adds the schemas of its
+// enum and struct types to schemas.
+func (a *API) fieldSchema(schemas map[string]interface{}, moduleName string, field schema.Field) map[string]interface{} {
+	var s map[string]interface{}
+	switch field.Kind {
+	case schema.EnumKind:
+		name := componentName(moduleName, field.EnumType.Name)
+		enumSchema := map[string]interface{}{"type": "string", "enum": field.EnumType.Values}
+		if field.EnumType.Description != "" {
+			enumSchema["description"] = field.EnumType.Description
+		}
+		schemas[name] = enumSchema
+		s = ref(name)
+	case schema.StructKind:
+		name := componentName(moduleName, field.StructType.Name)
+		properties := map[string]interface{}{}
+		var required []string
+		for _, f := range field.StructType.Fields {
+			properties[f.Name] = a.fieldSchema(schemas, moduleName, f)
+			required = append(required, f.Name)
+		}
+		schemas[name] = map[string]interface{}{"type": "object", "properties": properties, "required": required}
+		s = ref(name)
+	case schema.ListKind:
+		elemField := elementField(field, field.ElementKind)
+		elemField.Nullable = field.NullableElements
+		s = map[string]interface{}{"type": "array", "items": a.fieldSchema(schemas, moduleName, elemField)}
+	case schema.MapKind:
+		valueField := elementField(field, field.ValueKind)
+		valueField.Nullable = field.NullableElements
+		s = map[string]interface{}{"type": "object", "additionalProperties": a.fieldSchema(schemas, moduleName, valueField)}
+	default:
+		s = kindSchema(field.Kind)
+	}
+
+	if field.Description != "" {
+		s = withProperty(s, "description", field.Description)
+	}
+	if field.Nullable {
+		s = withProperty(s, "nullable", true)
+	}
+	return s
+}
+
+// withProperty adds a property to a schema. References are wrapped in allOf because OpenAPI 3.0 ignores the
+// siblings of $ref.
+func withProperty(s map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	if _, ok := s["$ref"]; ok {
+		s = map[string]interface{}{"allOf": []interface{}{s}}
+	}
+	s[key] = value
+	return s
+}
+
+func kindSchema(kind schema.Kind) map[string]interface{} {
+	switch kind {
+	case schema.StringKind:
+		return map[string]interface{}{"type": "string"}
+	case schema.BytesKind:
+		return map[string]interface{}{"type": "string", "format": "byte"}
+	case schema.Int8Kind, schema.Int16Kind, schema.Int32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case schema.Uint8Kind, schema.Uint16Kind:
+		return map[string]interface{}{"type": "integer", "format": "int32", "minimum": 0}
+	case schema.Uint32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int64", "minimum": 0}
+	case schema.Int64Kind:
+		return map[string]interface{}{"type": "string", "format": "int64", "pattern": `^-?[0-9]+$`}
+	case schema.Uint64Kind:
+		return map[string]interface{}{"type": "string", "format": "uint64", "pattern": `^[0-9]+$`}
+	case schema.IntegerStringKind:
+		return map[string]interface{}{"type": "string", "pattern": schema.IntegerFormat}
+	case schema.DecimalStringKind:
+		return map[string]interface{}{"type": "string", "pattern": schema.DecimalFormat}
+	case schema.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case schema.TimeKind:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case schema.DurationKind:
+		return map[string]interface{}{"type": "string", "pattern": `^-?[0-9]+$`, "description": "A duration in nanoseconds."}
+	case schema.Float32Kind:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case schema.Float64Kind:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case schema.AddressKind:
+		return map[string]interface{}{"type": "string", "description": "An address in the string format of the chain."}
+	default:
+		// JSON values can be of any type
+		return map[string]interface{}{}
+	}
+}
+
+func componentName(moduleName, typeName string) string {
+	return fmt.Sprintf("%s.%s", moduleName, typeName)
+}
+
+func ref(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+func jsonContent(s map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"application/json": map[string]interface{}{"schema": s}}
+}
+
+// proveParams appends the prove query parameter to the parameters of an object route in verifiable mode.
+func (a *API) proveParams(params []interface{}) []interface{} {
+	if !a.options.Proofs {
+		return params
+	}
+	return append(params, map[string]interface{}{
+		"name":        "prove",
+		"in":          "query",
+		"description": "Attaches the proof of each returned object in its _proof property.",
+		"schema":      map[string]interface{}{"type": "boolean"},
+	})
+}
+
+// addProofSchemas adds the component schemas of view.ObjectProof.
+func addProofSchemas(schemas map[string]interface{}) {
+	bytesSchema := func(description string) map[string]interface{} {
+		return map[string]interface{}{"type": "string", "format": "byte", "description": description}
+	}
+	schemas["ProofOp"] = map[string]interface{}{
+		"type":     "object",
+		"required": []string{"type", "key", "data"},
+		"properties": map[string]interface{}{
+			"type": map[string]interface{}{"type": "string", "description": "The type of the proof operation."},
+			"key":  bytesSchema("The key which the operation proves."),
+			"data": bytesSchema("The encoded proof."),
+		},
+	}
+	valueSchema := bytesSchema("The value of the kv-pair, or null for a proof of absence.")
+	valueSchema["nullable"] = true
+	appHashSchema := bytesSchema("The app hash which the proof commits to, or null if the backend doesn't know it.")
+	appHashSchema["nullable"] = true
+	schemas["ObjectProof"] = map[string]interface{}{
+		"type":        "object",
+		"description": "The proof of the state of an object, which can be verified against the app hash of the block at height + 1.",
+		"required":    []string{"height", "app_hash", "store_key", "key", "value", "ops"},
+		"properties": map[string]interface{}{
+			"height":    map[string]interface{}{"type": "string", "description": "The height of the proven state."},
+			"app_hash":  appHashSchema,
+			"store_key": map[string]interface{}{"type": "string", "description": "The store which holds the kv-pair of the object."},
+			"key":       bytesSchema("The key of the kv-pair of the object."),
+			"value":     valueSchema,
+			"ops":       map[string]interface{}{"type": "array", "items": ref("ProofOp")},
+		},
+	}
+}
@@ -0,0 +1,414 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/view"
+)
+
+var testBalanceType = schema.ObjectType{
+	Name: "balances",
+	KeyFields: []schema.Field{
+		{Name: "address", Kind: schema.AddressKind},
+		{Name: "denom", Kind: schema.StringKind},
+	},
+	ValueFields: []schema.Field{
+		{Name: "amount", Kind: schema.Int64Kind},
+		{Name: "memo", Kind: schema.StringKind, Nullable: true},
+		{Name: "note", Kind: schema.StringKind, Nullable: true},
+	},
+	Indexes: []schema.IndexDescriptor{
+		{Name: "by_amount", Fields: []schema.IndexField{{Name: "amount"}}},
+		{Name: "by_memo", Fields: []schema.IndexField{{Name: "memo"}}},
+	},
+	RetainDeletions: true,
+}
+
+var testParamsType = schema.ObjectType{
+	Name: "params",
+	ValueFields: []schema.Field{
+		{Name: "limits", Kind: schema.MapKind, KeyKind: schema.StringKind, ValueKind: schema.Uint64Kind},
+	},
+}
+
+var testModuleSchema, _ = schema.NewModuleSchema([]schema.ObjectType{testBalanceType, testParamsType})
+
+type testAppData map[string]*testCollection
+
+func (d testAppData) BlockNum() (uint64, error) { return 1, nil }
+
+func (d testAppData) AppState() view.AppState { return d }
+
+func (d testAppData) GetModule(name string) (view.ModuleState, error) {
+	if name != "bank" {
+		return nil, nil
+	}
+	return d, nil
+}
+
+func (d testAppData) Modules(f func(view.ModuleState, error) bool) { f(d, nil) }
+
+func (d testAppData) NumModules() (int, error) { return 1, nil }
+
+func (d testAppData) ModuleName() string { return "bank" }
+
+func (d testAppData) ModuleSchema() schema.ModuleSchema { return testModuleSchema }
+
+func (d testAppData) GetObjectCollection(name string) (view.ObjectCollection, error) {
+	if c, ok := d[name]; ok {
+		return c, nil
+	}
+	return nil, nil
+}
+
+func (d testAppData) ObjectCollections(f func(view.ObjectCollection, error) bool) {
+	for _, c := range d {
+		if !f(c, nil) {
+			return
+		}
+	}
+}
+
+func (d testAppData) NumObjectCollections() (int, error) { return len(d), nil }
+
+type testCollection struct {
+	objectType schema.ObjectType
+	objects    []schema.ObjectUpdate
+	err        error
+}
+
+func (c *testCollection) ObjectType() schema.ObjectType { return c.objectType }
+
+func (c *testCollection) GetObject(key interface{}) (schema.ObjectUpdate, bool, error) {
+	for _, obj := range c.objects {
+		if reflect.DeepEqual(obj.Key, key) {
+			return obj, true, nil
+		}
+	}
+	return schema.ObjectUpdate{}, false, c.err
+}
+
+func (c *testCollection) AllState(f func(schema.ObjectUpdate, error) bool) {
+	if c.err != nil {
+		f(schema.ObjectUpdate{}, c.err)
+		return
+	}
+	for _, obj := range c.objects {
+		if !f(obj, nil) {
+			return
+		}
+	}
+}
+
+func (c *testCollection) Len() (int, error) { return len(c.objects), nil }
+
+func (c *testCollection) List(filter view.FieldFilter, order view.OrderBy, page view.Pagination) (view.ListResult, error) {
+	return view.ListObjects(c, filter, order, page)
+}
+
+var testData = testAppData{
+	"balances": {objectType: testBalanceType, objects: []schema.ObjectUpdate{
+		{TypeName: "balances", Key: []interface{}{[]byte{1}, "stake"}, Value: []interface{}{int64(100), nil, nil}},
+		{TypeName: "balances", Key: []interface{}{[]byte{1}, "atom"}, Value: []interface{}{int64(7), "hi", "x"}},
+		{TypeName: "balances", Key: []interface{}{[]byte{2}, "stake"}, Value: []interface{}{int64(-3), nil, nil}, Delete: true},
+	}},
+	"params": {objectType: testParamsType, objects: []schema.ObjectUpdate{
+		{TypeName: "params", Value: map[interface{}]interface{}{"b": uint64(2), "a": uint64(1)}},
+	}},
+}
+
+func TestHandler(t *testing.T) {
+	api, err := NewAPI(map[string]schema.ModuleSchema{"bank": testModuleSchema}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := api.Handler(testData)
+
+	tests := []struct {
+		name         string
+		target       string
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			name:         "get",
+			target:       "/bank/balances/0x01/atom",
+			expectStatus: http.StatusOK,
+			expectBody:   `{"address":"0x01","denom":"atom","amount":"7","memo":"hi","note":"x","_deleted":false}`,
+		},
+		{
+			name:         "get escaped segment",
+			target:       "/bank/balances/0x01/%61tom",
+			expectStatus: http.StatusOK,
+			expectBody:   `{"address":"0x01","denom":"atom","amount":"7","memo":"hi","note":"x","_deleted":false}`,
+		},
+		{
+			name:         "get missing",
+			target:       "/bank/balances/0x03/atom",
+			expectStatus: http.StatusNotFound,
+			expectBody:   `{"error":"object not found"}`,
+		},
+		{
+			name:         "get invalid key",
+			target:       "/bank/balances/zz/atom",
+			expectStatus: http.StatusBadRequest,
+			expectBody:   `{"error":"invalid key field \"address\": encoding/hex: invalid byte: U+007A 'z'"}`,
+		},
+		{
+			name:         "singleton",
+			target:       "/bank/params",
+			expectStatus: http.StatusOK,
+			expectBody:   `{"limits":{"a":"1","b":"2"}}`,
+		},
+		{
+			name:         "list",
+			target:       "/bank/balances?order_by=-amount",
+			expectStatus: http.StatusOK,
+			expectBody:   `{"objects":[{"address":"0x01","denom":"stake","amount":"100","memo":null,"note":null,"_deleted":false},{"address":"0x01","denom":"atom","amount":"7","memo":"hi","note":"x","_deleted":false},{"address":"0x02","denom":"stake","amount":"-3","memo":null,"note":null,"_deleted":true}],"next_cursor":null}`,
+		},
+		{
+			name:         "list with filters and limit",
+			target:       "/bank/balances?amount.gte=-10&memo.is_null=true&denom.in=stake&denom.in=atom&limit=1",
+			expectStatus: http.StatusOK,
+			expectBody:   `{"objects":[{"address":"0x01","denom":"stake","amount":"100","memo":null,"note":null,"_deleted":false}],"next_cursor":"WyJBUT09Iiwic3Rha2UiXQ"}`,
+		},
+		{
+			name:         "list after cursor",
+			target:       "/bank/balances?amount.gte=-10&memo.is_null=true&limit=1&cursor=WyJBUT09Iiwic3Rha2UiXQ",
+			expectStatus: http.StatusOK,
+			expectBody:   `{"objects":[{"address":"0x02","denom":"stake","amount":"-3","memo":null,"note":null,"_deleted":true}],"next_cursor":null}`,
+		},
+		{
+			name:         "filter on unindexed field",
+			target:       "/bank/balances?note=x",
+			expectStatus: http.StatusBadRequest,
+			expectBody:   `{"error":"unknown query parameter \"note\", only key and indexed fields can be filtered"}`,
+		},
+		{
+			name:         "invalid filter",
+			target:       "/bank/balances?address.lt=0x01",
+			expectStatus: http.StatusBadRequest,
+			expectBody:   `{"error":"invalid lt condition on field \"address\": can't compare fields of kind bech32address"}`,
+		},
+		{
+			name:         "order by nullable field",
+			target:       "/bank/balances?order_by=memo",
+			expectStatus: http.StatusBadRequest,
+			expectBody:   `{"error":"can't order by nullable field \"memo\""}`,
+		},
+		{
+			name:         "unknown object type",
+			target:       "/bank/supply",
+			expectStatus: http.StatusNotFound,
+			expectBody:   `{"error":"unknown object type bank/supply"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rec.Code != tt.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectStatus, rec.Code, rec.Body)
+			}
+			if rec.Body.String() != tt.expectBody {
+				t.Fatalf("expected body:\n%s\ngot:\n%s", tt.expectBody, rec.Body)
+			}
+		})
+	}
+}
+
+func TestHandlerBackendError(t *testing.T) {
+	api, err := NewAPI(map[string]schema.ModuleSchema{"bank": testModuleSchema}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := testAppData{"balances": {objectType: testBalanceType, err: errors.New("connection closed")}}
+
+	rec := httptest.NewRecorder()
+	api.Handler(data).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/bank/balances", nil))
+	if rec.Code != http.StatusInternalServerError || rec.Body.String() != `{"error":"connection closed"}` {
+		t.Fatalf("unexpected response %d: %s", rec.Code, rec.Body)
+	}
+}
+
+// provableCollection is a testCollection whose objects can be proven.
+type provableCollection struct {
+	*testCollection
+}
+
+func (c provableCollection) ProveObject(key interface{}) (view.ObjectProof, error) {
+	denom := key.([]interface{})[1].(string)
+	return view.ObjectProof{
+		Height:   1,
+		StoreKey: "bank",
+		Key:      []byte(denom),
+		Value:    []byte{1},
+		Ops:      []view.ProofOp{{Type: "ics23:iavl", Key: []byte(denom), Data: []byte{2}}},
+	}, nil
+}
+
+func TestHandlerProofs(t *testing.T) {
+	api, err := NewAPI(map[string]schema.ModuleSchema{"bank": testModuleSchema}, Options{Proofs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	balances := testData["balances"]
+	data := testAppData{
+		"balances": {objectType: testBalanceType, objects: balances.objects[:1]},
+		"params":   testData["params"],
+	}
+	handler := api.Handler(provableAppData{data})
+
+	tests := []struct {
+		name         string
+		target       string
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			name:         "get",
+			target:       "/bank/balances/0x01/stake?prove=true",
+			expectStatus: http.StatusOK,
+			expectBody:   `{"address":"0x01","denom":"stake","amount":"100","memo":null,"note":null,"_deleted":false,"_proof":{"height":"1","app_hash":null,"store_key":"bank","key":"c3Rha2U=","value":"AQ==","ops":[{"type":"ics23:iavl","key":"c3Rha2U=","data":"Ag=="}]}}`,
+		},
+		{
+			name:         "get without proof",
+			target:       "/bank/balances/0x01/stake?prove=false",
+			expectStatus: http.StatusOK,
+			expectBody:   `{"address":"0x01","denom":"stake","amount":"100","memo":null,"note":null,"_deleted":false}`,
+		},
+		{
+			name:         "list",
+			target:       "/bank/balances?prove=1&denom=stake",
+			expectStatus: http.StatusOK,
+			expectBody:   `{"objects":[{"address":"0x01","denom":"stake","amount":"100","memo":null,"note":null,"_deleted":false,"_proof":{"height":"1","app_hash":null,"store_key":"bank","key":"c3Rha2U=","value":"AQ==","ops":[{"type":"ics23:iavl","key":"c3Rha2U=","data":"Ag=="}]}}],"next_cursor":null}`,
+		},
+		{
+			name:         "invalid prove parameter",
+			target:       "/bank/balances?prove=maybe",
+			expectStatus: http.StatusBadRequest,
+			expectBody:   `{"error":"invalid prove parameter \"maybe\""}`,
+		},
+		{
+			name:         "collection which can't be proven",
+			target:       "/bank/params?prove=true",
+			expectStatus: http.StatusNotImplemented,
+			expectBody:   `{"error":"object type bank/params can't be proven"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rec.Code != tt.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectStatus, rec.Code, rec.Body)
+			}
+			if rec.Body.String() != tt.expectBody {
+				t.Fatalf("expected body:\n%s\ngot:\n%s", tt.expectBody, rec.Body)
+			}
+		})
+	}
+
+	// the prove parameter is rejected like other unknown parameters if proofs are disabled
+	api, err = NewAPI(map[string]schema.ModuleSchema{"bank": testModuleSchema}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	api.Handler(provableAppData{data}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/bank/balances?prove=true", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusBadRequest, rec.Code, rec.Body)
+	}
+}
+
+// provableAppData serves the balances of testAppData from a provableCollection.
+type provableAppData struct {
+	testAppData
+}
+
+func (d provableAppData) AppState() view.AppState { return d }
+
+func (d provableAppData) GetModule(name string) (view.ModuleState, error) {
+	if name != "bank" {
+		return nil, nil
+	}
+	return d, nil
+}
+
+func (d provableAppData) GetObjectCollection(name string) (view.ObjectCollection, error) {
+	if name == "balances" {
+		return provableCollection{d.testAppData[name]}, nil
+	}
+	return d.testAppData.GetObjectCollection(name)
+}
+
+func TestOpenAPI(t *testing.T) {
+	api, err := NewAPI(map[string]schema.ModuleSchema{"bank": testModuleSchema}, Options{Title: "Bank"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	api.Handler(testData).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	var doc struct {
+		Info  map[string]string `json:"info"`
+		Paths map[string]struct {
+			Get struct {
+				OperationID string `json:"operationId"`
+				Parameters  []struct {
+					Name string `json:"name"`
+				} `json:"parameters"`
+			} `json:"get"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.Info["title"] != "Bank" {
+		t.Fatalf("unexpected info %v", doc.Info)
+	}
+	operations := map[string]string{}
+	for path, item := range doc.Paths {
+		operations[path] = item.Get.OperationID
+	}
+	expectedOperations := map[string]string{
+		"/openapi.json":                    "openapi",
+		"/bank/balances":                   "list_bank_balances",
+		"/bank/balances/{address}/{denom}": "get_bank_balances",
+		"/bank/params":                     "get_bank_params",
+	}
+	if !reflect.DeepEqual(operations, expectedOperations) {
+		t.Fatalf("expected operations %v, got %v", expectedOperations, operations)
+	}
+
+	var params []string
+	for _, p := range doc.Paths["/bank/balances"].Get.Parameters {
+		params = append(params, p.Name)
+	}
+	expectedParams := []string{
+		"address", "address.eq", "address.ne", "address.in",
+		"denom", "denom.eq", "denom.ne", "denom.in", "denom.lt", "denom.lte", "denom.gt", "denom.gte",
+		"amount", "amount.eq", "amount.ne", "amount.in", "amount.lt", "amount.lte", "amount.gt", "amount.gte",
+		"memo", "memo.eq", "memo.ne", "memo.in", "memo.lt", "memo.lte", "memo.gt", "memo.gte", "memo.is_null",
+		"order_by", "limit", "cursor",
+	}
+	if !reflect.DeepEqual(params, expectedParams) {
+		t.Fatalf("expected parameters %v, got %v", expectedParams, params)
+	}
+
+	if got := string(doc.Components.Schemas["bank.balances"]); got != `{"properties":{"_deleted":{"description":"Whether the object was deleted.","type":"boolean"},"address":{"description":"An address in the string format of the chain.","type":"string"},"amount":{"format":"int64","pattern":"^-?[0-9]+$","type":"string"},"denom":{"type":"string"},"memo":{"nullable":true,"type":"string"},"note":{"nullable":true,"type":"string"}},"required":["address","denom","amount","memo","note","_deleted"],"type":"object"}` {
+		t.Fatalf("unexpected balances schema %s", got)
+	}
+}
@@ -0,0 +1,261 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"cosmossdk.io/schema"
+)
+
+// parseValue parses the string representation of a value of a field, as used in URL paths and query
+// parameters, to the go type of the field's kind.
+func (a *API) parseValue(field schema.Field, str string) (interface{}, error) {
+	var value interface{}
+	var err error
+	switch field.Kind {
+	case schema.StringKind, schema.IntegerStringKind, schema.DecimalStringKind, schema.EnumKind:
+		value = str
+	case schema.BytesKind:
+		value, err = base64.StdEncoding.DecodeString(str)
+	case schema.Int8Kind, schema.Int16Kind, schema.Int32Kind, schema.Int64Kind:
+		var n int64
+		n, err = strconv.ParseInt(str, 10, kindBits(field.Kind))
+		value = intValue(field.Kind, n)
+	case schema.Uint8Kind, schema.Uint16Kind, schema.Uint32Kind, schema.Uint64Kind:
+		var n uint64
+		n, err = strconv.ParseUint(str, 10, kindBits(field.Kind))
+		value = uintValue(field.Kind, n)
+	case schema.BoolKind:
+		value, err = strconv.ParseBool(str)
+	case schema.TimeKind:
+		value, err = time.Parse(time.RFC3339Nano, str)
+	case schema.DurationKind:
+		var n int64
+		n, err = strconv.ParseInt(str, 10, 64)
+		value = time.Duration(n)
+	case schema.Float32Kind:
+		var f float64
+		f, err = strconv.ParseFloat(str, 32)
+		value = float32(f)
+	case schema.Float64Kind:
+		value, err = strconv.ParseFloat(str, 64)
+	case schema.AddressKind:
+		value, err = field.AddressCodec(a.addressCodec).StringToBytes(str)
+	default:
+		return nil, fmt.Errorf("values of kind %s can't be used in URLs", field.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	field.Nullable = false
+	if err := field.ValidateValue(value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func kindBits(kind schema.Kind) int {
+	switch kind {
+	case schema.Int8Kind, schema.Uint8Kind:
+		return 8
+	case schema.Int16Kind, schema.Uint16Kind:
+		return 16
+	case schema.Int32Kind, schema.Uint32Kind:
+		return 32
+	default:
+		return 64
+	}
+}
+
+func intValue(kind schema.Kind, n int64) interface{} {
+	switch kind {
+	case schema.Int8Kind:
+		return int8(n)
+	case schema.Int16Kind:
+		return int16(n)
+	case schema.Int32Kind:
+		return int32(n)
+	default:
+		return n
+	}
+}
+
+func uintValue(kind schema.Kind, n uint64) interface{} {
+	switch kind {
+	case schema.Uint8Kind:
+		return uint8(n)
+	case schema.Uint16Kind:
+		return uint16(n)
+	case schema.Uint32Kind:
+		return uint32(n)
+	default:
+		return n
+	}
+}
+
+// jsonValue converts the value of a field to the value of its JSON representation: 64-bit integers and
+// durations in nanoseconds are strings so that clients don't lose precision, bytes are base64 encoded, times
+// are RFC 3339 strings, addresses are formatted with the address codec and structs are objects.
+func (a *API) jsonValue(field schema.Field, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch field.Kind {
+	case schema.Int64Kind:
+		n, ok := value.(int64)
+		if !ok {
+			return nil, fmt.Errorf("expected int64 for field %q, got %T", field.Name, value)
+		}
+		return strconv.FormatInt(n, 10), nil
+	case schema.Uint64Kind:
+		n, ok := value.(uint64)
+		if !ok {
+			return nil, fmt.Errorf("expected uint64 for field %q, got %T", field.Name, value)
+		}
+		return strconv.FormatUint(n, 10), nil
+	case schema.DurationKind:
+		d, ok := value.(time.Duration)
+		if !ok {
+			return nil, fmt.Errorf("expected time.Duration for field %q, got %T", field.Name, value)
+		}
+		return strconv.FormatInt(int64(d), 10), nil
+	case schema.TimeKind:
+		t, ok := value.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("expected time.Time for field %q, got %T", field.Name, value)
+		}
+		return t.UTC().Format(time.RFC3339Nano), nil
+	case schema.AddressKind:
+		bz, ok := value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("expected []byte for field %q, got %T", field.Name, value)
+		}
+		return field.AddressCodec(a.addressCodec).BytesToString(bz)
+	case schema.StructKind:
+		values, ok := value.([]interface{})
+		if !ok || len(values) != len(field.StructType.Fields) {
+			return nil, fmt.Errorf("expected %d values for field %q", len(field.StructType.Fields), field.Name)
+		}
+		return a.jsonObject(field.StructType.Fields, values, nil)
+	case schema.ListKind:
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected []interface{} for field %q, got %T", field.Name, value)
+		}
+		elemField := elementField(field, field.ElementKind)
+		list := make([]interface{}, len(values))
+		for i, v := range values {
+			var err error
+			if list[i], err = a.jsonValue(elemField, v); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case schema.MapKind:
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected map[interface{}]interface{} for field %q, got %T", field.Name, value)
+		}
+		keyField := elementField(field, field.KeyKind)
+		valueField := elementField(field, field.ValueKind)
+		obj := &orderedObject{}
+		for k, v := range m {
+			jk, err := a.jsonValue(keyField, k)
+			if err != nil {
+				return nil, err
+			}
+			jv, err := a.jsonValue(valueField, v)
+			if err != nil {
+				return nil, err
+			}
+			obj.add(fmt.Sprint(jk), jv)
+		}
+		obj.sort()
+		return obj, nil
+	default:
+		// the remaining kinds are represented by their go values
+		return value, nil
+	}
+}
+
+// jsonObject converts the values of fields to a JSON object with the fields in order. extra are added as
+// additional keys.
+func (a *API) jsonObject(fields []schema.Field, values []interface{}, extra *orderedObject) (*orderedObject, error) {
+	obj := &orderedObject{}
+	for i, field := range fields {
+		v, err := a.jsonValue(field, values[i])
+		if err != nil {
+			return nil, err
+		}
+		obj.add(field.Name, v)
+	}
+	if extra != nil {
+		obj.keys = append(obj.keys, extra.keys...)
+		obj.values = append(obj.values, extra.values...)
+	}
+	return obj, nil
+}
+
+// elementField returns a field which describes the elements of a list field or the keys or values of a map field.
+func elementField(field schema.Field, kind schema.Kind) schema.Field {
+	elem := schema.Field{Name: field.Name, Kind: kind, EnumType: field.EnumType, StructType: field.StructType}
+	if kind == schema.AddressKind {
+		elem.AddressPrefix = field.AddressPrefix
+	}
+	return elem
+}
+
+// orderedObject is a JSON object whose keys are marshaled in order.
+type orderedObject struct {
+	keys   []string
+	values []interface{}
+}
+
+func (o *orderedObject) add(key string, value interface{}) {
+	o.keys = append(o.keys, key)
+	o.values = append(o.values, value)
+}
+
+func (o *orderedObject) sort() {
+	sort.Sort(byKey{o})
+}
+
+type byKey struct{ *orderedObject }
+
+func (b byKey) Len() int           { return len(b.keys) }
+func (b byKey) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKey) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.values[i], b.values[j] = b.values[j], b.values[i]
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,463 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+
+	"github.com/cosmos/cosmos-sdk/server/types"
+)
+
+const (
+	flagIndexerModule  = "module"
+	flagIndexerTarget  = "target"
+	flagIndexerFrom    = "from"
+	flagIndexerTo      = "to"
+	flagIndexerJournal = "journal"
+)
+
+// IndexerCmd returns the command group to operate the built-in indexer which is configured in the indexer
+// section of app.toml. Except for listing the configured targets and dead letters, the commands open the
+// application's database and start the indexer targets, so the node must be stopped while they run.
+func IndexerCmd[T types.Application](appCreator types.AppCreator[T]) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "indexer",
+		Short: "Built-in indexer subcommands",
+	}
+
+	cmd.AddCommand(
+		IndexerStatusCmd(appCreator),
+		IndexerBackfillCmd(appCreator),
+		IndexerVerifyCmd(appCreator),
+		IndexerReplayJournalCmd(appCreator),
+		IndexerTargetsCmd(),
+		DeadLettersCmd(),
+	)
+	return cmd
+}
+
+// indexerTargetStatus is the status of an indexer target as printed by the indexer commands.
+type indexerTargetStatus struct {
+	Name                string `json:"name"`
+	Type                string `json:"type"`
+	LastCommittedHeight int64  `json:"last_committed_height"`
+	Lag                 int64  `json:"lag"`
+	DeadLetters         uint64 `json:"dead_letters"`
+	Error               string `json:"error,omitempty"`
+}
+
+// IndexerStatusCmd returns a command which reports the height which each indexer target has indexed up to
+// and how far it is behind the application state.
+func IndexerStatusCmd[T types.Application](appCreator types.AppCreator[T]) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the indexed height of each indexer target compared to the application state",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return withIndexerManager(cmd, appCreator, func(app T, manager *indexer.Manager) error {
+				return printJSON(cmd, indexerStatus(app, manager))
+			})
+		},
+	}
+}
+
+// IndexerBackfillCmd returns a command which replays a range of blocks from a journal to the indexer targets.
+func IndexerBackfillCmd[T types.Application](appCreator types.AppCreator[T]) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backfill --from [height] --to [height] --journal [file]",
+		Short: "Replay a range of blocks from a journal to the indexer targets",
+		Long: `Replay the blocks from --from to --to, inclusive, from a journal written with appdata.JournalListener to
+the indexer targets. Blocks which a target has already indexed are skipped and the command fails if a target
+is missing blocks before --from.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			from, err := cmd.Flags().GetInt64(flagIndexerFrom)
+			if err != nil {
+				return err
+			}
+			to, err := cmd.Flags().GetInt64(flagIndexerTo)
+			if err != nil {
+				return err
+			}
+			journal, err := cmd.Flags().GetString(flagIndexerJournal)
+			if err != nil {
+				return err
+			}
+
+			return withIndexerManager(cmd, appCreator, func(app T, manager *indexer.Manager) error {
+				if to == 0 {
+					to = app.CommitMultiStore().LastCommitID().Version
+				}
+
+				source := appdata.JournalCatchUpSource(func() (io.ReadCloser, error) { return os.Open(journal) })
+				if err := manager.Backfill(from, to, source); err != nil {
+					return err
+				}
+
+				cmd.Printf("backfilled blocks %d to %d\n", from, to)
+				return printJSON(cmd, indexerStatus(app, manager))
+			})
+		},
+	}
+
+	cmd.Flags().Int64(flagIndexerFrom, 1, "First block height to backfill")
+	cmd.Flags().Int64(flagIndexerTo, 0, "Last block height to backfill, defaults to the height of the application state")
+	cmd.Flags().String(flagIndexerJournal, "", "Path of the journal to read the blocks from")
+	_ = cmd.MarkFlagRequired(flagIndexerJournal)
+	return cmd
+}
+
+// IndexerVerifyCmd returns a command which verifies that the indexer targets are consistent with the
+// application state and, optionally, that a journal is intact.
+func IndexerVerifyCmd[T types.Application](appCreator types.AppCreator[T]) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify that the indexer targets and journal are consistent with the application state",
+		Long: `Verify that every indexer target configured in app.toml starts, that no target has indexed blocks beyond
+the height of the application state, which happens when the state was rolled back or belongs to another
+chain, and, if --journal is set, that the journal has no corrupt records and no missing blocks.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			journal, err := cmd.Flags().GetString(flagIndexerJournal)
+			if err != nil {
+				return err
+			}
+
+			return withIndexerManager(cmd, appCreator, func(app T, manager *indexer.Manager) error {
+				statuses := indexerStatus(app, manager)
+				var errs []error
+				for i, status := range statuses {
+					if status.Lag < 0 {
+						statuses[i].Error = fmt.Sprintf("target is %d blocks ahead of the application state", -status.Lag)
+						errs = append(errs, fmt.Errorf("indexer target %q: %s", status.Name, statuses[i].Error))
+					}
+				}
+				if err := printJSON(cmd, statuses); err != nil {
+					return err
+				}
+
+				if journal != "" {
+					first, last, err := verifyJournal(journal)
+					if err != nil {
+						errs = append(errs, fmt.Errorf("journal %s: %w", journal, err))
+					} else {
+						cmd.Printf("journal %s contains blocks %d to %d\n", journal, first, last)
+					}
+				}
+
+				return errors.Join(errs...)
+			})
+		},
+	}
+
+	cmd.Flags().String(flagIndexerJournal, "", "Path of a journal to verify")
+	return cmd
+}
+
+// IndexerReplayJournalCmd returns a command which replays a whole journal to the indexer targets.
+func IndexerReplayJournalCmd[T types.Application](appCreator types.AppCreator[T]) *cobra.Command {
+	return &cobra.Command{
+		Use:   "replay-journal [file]",
+		Short: "Replay all the blocks in a journal to the indexer targets",
+		Long: `Replay all the packets in a journal written with appdata.JournalListener to the indexer targets, for
+instance to rebuild a target's database without re-syncing the chain. Blocks which a target has already
+indexed are skipped.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withIndexerManager(cmd, appCreator, func(app T, manager *indexer.Manager) error {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+
+				if err := appdata.ReplayJournal(file, manager.Listener()); err != nil {
+					return err
+				}
+				return printJSON(cmd, indexerStatus(app, manager))
+			})
+		},
+	}
+}
+
+// indexerTargetConfig is the configuration of an indexer target as printed by the targets list command.
+type indexerTargetConfig struct {
+	Name string `json:"name"`
+	indexer.Config
+}
+
+// IndexerTargetsCmd returns the commands to inspect the indexer targets configured in app.toml.
+func IndexerTargetsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "targets",
+		Short: "Indexer target subcommands",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the indexer targets configured in app.toml",
+		Long: `List the indexer targets configured in app.toml with their common options. The indexer section is
+validated first, as with config validate-indexer, and indexer specific options are omitted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := parseIndexerConfig(GetServerContextFromCmd(cmd))
+			if err != nil {
+				return err
+			}
+
+			names := make([]string, 0, len(cfg.Target))
+			for name := range cfg.Target {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			targets := make([]indexerTargetConfig, 0, len(names))
+			for _, name := range names {
+				targetCfg := cfg.Target[name]
+				targetCfg.Config = nil
+				targets = append(targets, indexerTargetConfig{Name: name, Config: targetCfg})
+			}
+			return printJSON(cmd, targets)
+		},
+	})
+	return cmd
+}
+
+// ValidateIndexerConfigCmd returns a command which validates the indexer section of app.toml without starting
+// the indexer targets.
+func ValidateIndexerConfigCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate-indexer",
+		Short: "Validate the indexer section of app.toml",
+		Long: `Validate the indexer section of app.toml with the same rules that apply when the node starts. Unknown
+options, indexer types which are not available in this binary, invalid common options of the targets and
+environment variables referenced with ${NAME} which are not set are all reported. Indexer specific options
+are only validated by the indexers when they start.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := parseIndexerConfig(GetServerContextFromCmd(cmd))
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("indexer config is valid with %d target(s)\n", len(cfg.Target))
+			return nil
+		},
+	}
+}
+
+// withIndexerManager creates the application, whose built-in indexer is started from the indexer section of
+// app.toml, calls f with its indexer manager and closes the application, which stops the indexer targets.
+func withIndexerManager[T types.Application](cmd *cobra.Command, appCreator types.AppCreator[T], f func(app T, manager *indexer.Manager) error) (err error) {
+	serverCtx := GetServerContextFromCmd(cmd)
+	if serverCtx.Viper.Get("indexer") == nil {
+		return errors.New("the indexer is not configured in app.toml")
+	}
+
+	db, err := OpenDB(serverCtx.Config.RootDir, GetAppDBBackend(serverCtx.Viper))
+	if err != nil {
+		return err
+	}
+
+	app := appCreator(serverCtx.Logger, db, nil, serverCtx.Viper)
+	defer func() {
+		if closeErr := app.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	provider, ok := any(app).(interface{ IndexerManager() *indexer.Manager })
+	if !ok || provider.IndexerManager() == nil {
+		return errors.New("the application does not enable the built-in indexer")
+	}
+	return f(app, provider.IndexerManager())
+}
+
+// indexerStatus returns the status of the indexer targets relative to the height of the application state.
+func indexerStatus[T types.Application](app T, manager *indexer.Manager) []indexerTargetStatus {
+	height := app.CommitMultiStore().LastCommitID().Version
+	health := manager.Health()
+	res := make([]indexerTargetStatus, len(health))
+	for i, h := range health {
+		res[i] = indexerTargetStatus{
+			Name:                h.Name,
+			Type:                h.Type,
+			LastCommittedHeight: h.LastCommittedHeight,
+			DeadLetters:         h.DeadLetters,
+		}
+		if h.LastCommittedHeight >= 0 {
+			res[i].Lag = height - h.LastCommittedHeight
+		}
+		if h.LastError != nil {
+			res[i].Error = h.LastError.Error()
+		}
+	}
+	return res
+}
+
+// verifyJournal reads all the records of a journal and returns the heights of its first and last block. An
+// error is returned if a record is corrupt or if blocks are missing.
+func verifyJournal(path string) (first, last uint64, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	reader := appdata.NewJournalReader(file)
+	for {
+		p, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return first, last, nil
+		}
+		if err != nil {
+			return first, last, err
+		}
+
+		data, ok := p.(appdata.StartBlockData)
+		if !ok {
+			continue
+		}
+		if last != 0 && data.Height != last+1 {
+			return first, last, fmt.Errorf("expected block %d after block %d but found block %d", last+1, last, data.Height)
+		}
+		if first == 0 {
+			first = data.Height
+		}
+		last = data.Height
+	}
+}
+
+func printJSON(cmd *cobra.Command, v any) error {
+	bz, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	cmd.Println(string(bz))
+	return nil
+}
+
+// DeadLettersCmd returns the commands to inspect and re-drive the object updates which indexer targets have
+// routed to their dead-letter file.
+func DeadLettersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dead-letters",
+		Short: "Inspect and re-drive dead-lettered object updates",
+	}
+
+	cmd.AddCommand(listDeadLettersCmd(), redriveDeadLettersCmd())
+	return cmd
+}
+
+func listDeadLettersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list [file]",
+		Short: "List the object updates in a dead-letter file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			moduleName, err := cmd.Flags().GetString(flagIndexerModule)
+			if err != nil {
+				return err
+			}
+
+			file, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			reader := appdata.NewDeadLetterReader(file)
+			for {
+				letter, err := reader.Next()
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+
+				if moduleName != "" && letter.ModuleName != moduleName {
+					continue
+				}
+
+				cmd.Printf("height=%d module=%s type=%s key=%v delete=%t attempts=%d time=%s error=%q\n",
+					letter.Height, letter.ModuleName, letter.Update.TypeName, letter.Update.Key, letter.Update.Delete,
+					letter.Attempts, letter.Time.Format(time.RFC3339), letter.Error)
+			}
+		},
+	}
+
+	cmd.Flags().String(flagIndexerModule, "", "Only list the updates of this module")
+	return cmd
+}
+
+func redriveDeadLettersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "redrive [file]",
+		Short: "Re-drive the object updates in a dead-letter file to an indexer target",
+		Long: `Re-drive the object updates in a dead-letter file to an indexer target configured in app.toml,
+for instance after the cause of the failures has been fixed. The updates are applied in order and the
+command stops at the first update which still fails. The node should be stopped while re-driving updates.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			serverCtx := GetServerContextFromCmd(cmd)
+
+			targetName, err := cmd.Flags().GetString(flagIndexerTarget)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := parseIndexerConfig(serverCtx)
+			if err != nil {
+				return err
+			}
+
+			targetCfg, ok := cfg.Target[targetName]
+			if !ok {
+				return fmt.Errorf("indexer target %q is not configured", targetName)
+			}
+
+			file, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			n, err := indexer.RedriveDeadLetters(indexer.RedriveOptions{
+				Config:      targetCfg,
+				DeadLetters: file,
+				Logger:      serverCtx.Logger.With("module", "indexer"),
+				Context:     cmd.Context(),
+			})
+			if err != nil {
+				return fmt.Errorf("re-drove %d updates before failing: %w", n, err)
+			}
+
+			cmd.Printf("re-drove %d updates to indexer target %q\n", n, targetName)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagIndexerTarget, "", "Name of the indexer target to re-drive the updates to")
+	_ = cmd.MarkFlagRequired(flagIndexerTarget)
+	return cmd
+}
+
+// parseIndexerConfig parses and validates the indexer section of app.toml with indexer.ParseManagerConfig, which
+// also expands the references to environment variables in the indexer specific options.
+func parseIndexerConfig(serverCtx *Context) (indexer.ManagerConfig, error) {
+	opts := serverCtx.Viper.Get("indexer")
+	if opts == nil {
+		return indexer.ManagerConfig{}, errors.New("the indexer is not configured in app.toml")
+	}
+	return indexer.ParseManagerConfig(opts)
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+)
+
+func TestParseIndexerConfig(t *testing.T) {
+	serverCtx := NewDefaultContext()
+	_, err := parseIndexerConfig(serverCtx)
+	require.Error(t, err)
+
+	t.Setenv("INDEXER_TEST_PASSWORD", "secret")
+	serverCtx.Viper.Set("indexer", map[string]interface{}{
+		"target": map[string]interface{}{
+			"pg": map[string]interface{}{
+				"type":         "server_parse_config_test",
+				"start_height": int64(5),
+				"dead_letter":  map[string]interface{}{"file": "dl", "max_attempts": int64(3)},
+				"config":       map[string]interface{}{"password": "${INDEXER_TEST_PASSWORD}"},
+			},
+		},
+	})
+	indexer.Register("server_parse_config_test", func(indexer.InitParams) (indexer.InitResult, error) {
+		return indexer.InitResult{}, nil
+	})
+
+	cfg, err := parseIndexerConfig(serverCtx)
+	require.NoError(t, err)
+	require.Equal(t, "server_parse_config_test", cfg.Target["pg"].Type)
+	require.Equal(t, int64(5), cfg.Target["pg"].StartHeight)
+	require.Equal(t, 3, cfg.Target["pg"].DeadLetter.MaxAttempts)
+	require.Equal(t, "secret", cfg.Target["pg"].Config["password"])
+}
+
+func TestValidateIndexerConfigCmd(t *testing.T) {
+	serverCtx := NewDefaultContext()
+	cmd := ValidateIndexerConfigCmd()
+	cmd.SetArgs([]string{})
+	cmd.SetContext(context.WithValue(context.Background(), ServerContextKey, serverCtx))
+	require.ErrorContains(t, cmd.Execute(), "the indexer is not configured in app.toml")
+
+	serverCtx.Viper.Set("indexer", map[string]interface{}{
+		"target": map[string]interface{}{
+			"pg": map[string]interface{}{"type": "postgres", "start_hieght": int64(5)},
+		},
+	})
+	require.ErrorContains(t, cmd.Execute(), `unknown field "start_hieght"`)
+
+	serverCtx.Viper.Set("indexer", map[string]interface{}{
+		"target": map[string]interface{}{
+			"pg": map[string]interface{}{"type": "postgres", "start_height": int64(-1)},
+		},
+	})
+	err := cmd.Execute()
+	require.ErrorContains(t, err, `target "pg": indexer type "postgres" not found`)
+	require.ErrorContains(t, err, `target "pg": start_height must not be negative`)
+}
+
+func TestVerifyJournal(t *testing.T) {
+	writeJournal := func(heights ...uint64) string {
+		path := filepath.Join(t.TempDir(), "journal")
+		file, err := os.Create(path)
+		require.NoError(t, err)
+		defer file.Close()
+
+		listener := appdata.JournalListener(file, appdata.Listener{})
+		for _, height := range heights {
+			require.NoError(t, listener.StartBlock(appdata.StartBlockData{Height: height}))
+			require.NoError(t, listener.Commit(appdata.CommitData{}))
+		}
+		return path
+	}
+
+	first, last, err := verifyJournal(writeJournal(3, 4, 5))
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), first)
+	require.Equal(t, uint64(5), last)
+
+	_, _, err = verifyJournal(writeJournal(3, 5))
+	require.ErrorContains(t, err, "expected block 4")
+
+	path := writeJournal(3, 4)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(path, info.Size()-1))
+	_, _, err = verifyJournal(path)
+	require.ErrorAs(t, err, &appdata.CorruptJournalError{})
+}
+
+func TestRedriveDeadLettersCmd(t *testing.T) {
+	var (
+		password string
+		updates  []schema.ObjectUpdate
+	)
+	indexer.Register("server_redrive_test", func(params indexer.InitParams) (indexer.InitResult, error) {
+		password, _ = params.Config.Config["password"].(string)
+		return indexer.InitResult{Listener: appdata.Listener{
+			OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+				if password != "secret" {
+					return errors.New("authentication failed")
+				}
+				updates = append(updates, data.Updates...)
+				return nil
+			},
+		}}, nil
+	})
+
+	moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:      "balances",
+		KeyFields: []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+	}})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "dead-letters")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	update := schema.ObjectUpdate{TypeName: "balances", Key: "atom"}
+	require.NoError(t, appdata.DeadLetterWriter(file).PutDeadLetter(appdata.DeadLetter{
+		ModuleName: "bank",
+		Schema:     moduleSchema,
+		Update:     update,
+		Height:     3,
+		Attempts:   1,
+		Error:      "connection refused",
+	}))
+	require.NoError(t, file.Close())
+
+	t.Setenv("INDEXER_TEST_PASSWORD", "secret")
+	serverCtx := NewDefaultContext()
+	serverCtx.Viper.Set("indexer", map[string]interface{}{
+		"target": map[string]interface{}{
+			"pg": map[string]interface{}{
+				"type":   "server_redrive_test",
+				"config": map[string]interface{}{"password": "${INDEXER_TEST_PASSWORD}"},
+			},
+		},
+	})
+
+	cmd := DeadLettersCmd()
+	cmd.SetArgs([]string{"redrive", path, "--" + flagIndexerTarget, "pg"})
+	cmd.SetContext(context.WithValue(context.Background(), ServerContextKey, serverCtx))
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, "secret", password)
+	require.Equal(t, []schema.ObjectUpdate{update}, updates)
+}
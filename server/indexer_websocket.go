@@ -0,0 +1,625 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/schema/logutil"
+)
+
+// WebSocketIndexerType is the indexer type of the targets which serve a WebSocket endpoint on which clients, such
+// as explorer frontends, receive the object updates of committed blocks without polling.
+const WebSocketIndexerType = "websocket"
+
+func init() {
+	indexer.Register(WebSocketIndexerType, InitWebSocketIndexer)
+}
+
+// WebSocketIndexerConfig is the indexer specific config of a websocket indexer target.
+type WebSocketIndexerConfig struct {
+	// ListenAddress is the address on which the WebSocket endpoint is served. It defaults to
+	// DefaultWebSocketListenAddress.
+	ListenAddress string `json:"listen_address"`
+
+	// Path is the HTTP path of the WebSocket endpoint. It defaults to DefaultWebSocketPath.
+	Path string `json:"path"`
+
+	// MaxSubscriptions is the maximum number of subscriptions of a connection. It defaults to
+	// DefaultWebSocketMaxSubscriptions.
+	MaxSubscriptions int `json:"max_subscriptions"`
+
+	// BufferSize is the number of messages which are buffered for each connection. Connections which fall
+	// further behind are closed so that slow clients don't hold back the node. It defaults to
+	// DefaultWebSocketBufferSize.
+	BufferSize int `json:"buffer_size"`
+
+	// AllowedOrigins are the origins from which browsers may connect. "*" allows all origins. If it is empty,
+	// only requests without an Origin header or from the same host are accepted.
+	AllowedOrigins []string `json:"allowed_origins"`
+}
+
+const (
+	// DefaultWebSocketListenAddress is the default value of WebSocketIndexerConfig.ListenAddress.
+	DefaultWebSocketListenAddress = "localhost:9494"
+
+	// DefaultWebSocketPath is the default value of WebSocketIndexerConfig.Path.
+	DefaultWebSocketPath = "/object_updates"
+
+	// DefaultWebSocketMaxSubscriptions is the default value of WebSocketIndexerConfig.MaxSubscriptions.
+	DefaultWebSocketMaxSubscriptions = 32
+
+	// DefaultWebSocketBufferSize is the default value of WebSocketIndexerConfig.BufferSize.
+	DefaultWebSocketBufferSize = 64
+)
+
+// InitWebSocketIndexer initializes a websocket indexer target. It is registered as the indexer type
+// "websocket".
+func InitWebSocketIndexer(params indexer.InitParams) (indexer.InitResult, error) {
+	cfg, err := decodeWebSocketIndexerConfig(params.Config.Config)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	ctx := params.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = logutil.NoopLogger{}
+	}
+
+	ln, err := net.Listen("tcp", cfg.ListenAddress)
+	if err != nil {
+		return indexer.InitResult{}, fmt.Errorf("failed to listen on %s: %w", cfg.ListenAddress, err)
+	}
+
+	ws := newWebSocketIndexer(cfg, logger)
+	mux := http.NewServeMux()
+	mux.Handle(cfg.Path, ws)
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("object update websocket server failed", "error", err)
+		}
+	}()
+	logger.Info("serving object update websocket", "address", ln.Addr().String(), "path", cfg.Path)
+
+	if params.DoneWaitGroup != nil {
+		params.DoneWaitGroup.Add(1)
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error("failed to shut down object update websocket server", "error", err)
+		}
+		// hijacked WebSocket connections are not closed by Shutdown
+		ws.close()
+		if params.DoneWaitGroup != nil {
+			params.DoneWaitGroup.Done()
+		}
+	}()
+
+	return indexer.InitResult{
+		Listener:           ws.listener(),
+		LastBlockPersisted: -1,
+	}, nil
+}
+
+func decodeWebSocketIndexerConfig(rawConfig map[string]interface{}) (WebSocketIndexerConfig, error) {
+	bz, err := json.Marshal(rawConfig)
+	if err != nil {
+		return WebSocketIndexerConfig{}, fmt.Errorf("invalid websocket indexer config: %w", err)
+	}
+
+	var cfg WebSocketIndexerConfig
+	if err := json.Unmarshal(bz, &cfg); err != nil {
+		return WebSocketIndexerConfig{}, fmt.Errorf("invalid websocket indexer config: %w", err)
+	}
+
+	if cfg.ListenAddress == "" {
+		cfg.ListenAddress = DefaultWebSocketListenAddress
+	}
+	if cfg.Path == "" {
+		cfg.Path = DefaultWebSocketPath
+	}
+	if cfg.MaxSubscriptions == 0 {
+		cfg.MaxSubscriptions = DefaultWebSocketMaxSubscriptions
+	}
+	if cfg.BufferSize == 0 {
+		cfg.BufferSize = DefaultWebSocketBufferSize
+	}
+	if cfg.MaxSubscriptions < 0 || cfg.BufferSize < 0 {
+		return WebSocketIndexerConfig{}, fmt.Errorf("websocket indexer max_subscriptions and buffer_size must not be negative")
+	}
+
+	return cfg, nil
+}
+
+// WebSocketSubscription selects object updates by module, object type and key prefix. Empty fields match
+// everything.
+type WebSocketSubscription struct {
+	// Module is the name of the module.
+	Module string `json:"module,omitempty"`
+
+	// Type is the name of the object type.
+	Type string `json:"type,omitempty"`
+
+	// KeyPrefix are the values of the first key fields, in the encoding of schema.EncodeValueJSON, which the key
+	// of an object must start with.
+	KeyPrefix []json.RawMessage `json:"key_prefix,omitempty"`
+}
+
+// WebSocketControlMessage is a message which clients send to change their subscriptions.
+type WebSocketControlMessage struct {
+	// Subscribe are the subscriptions to add.
+	Subscribe []WebSocketSubscription `json:"subscribe,omitempty"`
+
+	// Unsubscribe are the subscriptions to remove.
+	Unsubscribe []WebSocketSubscription `json:"unsubscribe,omitempty"`
+}
+
+// WebSocketControlResponse is the response to a WebSocketControlMessage, and to the subscription of the query
+// parameters when a connection is opened.
+type WebSocketControlResponse struct {
+	// Subscriptions are the subscriptions of the connection.
+	Subscriptions []WebSocketSubscription `json:"subscriptions"`
+
+	// Error is the reason why the control message was rejected, in which case the subscriptions are unchanged.
+	Error string `json:"error,omitempty"`
+}
+
+// WebSocketMessage is a message sent to clients with the object updates of a committed block which match their
+// subscriptions.
+type WebSocketMessage struct {
+	// Height is the height of the block.
+	Height uint64 `json:"height"`
+
+	// Updates are the matching object updates of the block, in order.
+	Updates []WebSocketObjectUpdate `json:"updates"`
+}
+
+// WebSocketObjectUpdate is an object update in which the fields are named after the object type's schema and
+// their values are encoded with schema.EncodeValueJSON, which tags each value with its kind.
+type WebSocketObjectUpdate struct {
+	// Module is the name of the module of the object.
+	Module string `json:"module"`
+
+	// Type is the name of the object type.
+	Type string `json:"type"`
+
+	// Key are the key fields of the object.
+	Key map[string]json.RawMessage `json:"key"`
+
+	// Value are the value fields of the object, or only the updated fields if Partial is true. It is empty for
+	// deletes.
+	Value map[string]json.RawMessage `json:"value,omitempty"`
+
+	// Partial is true if only some value fields were updated.
+	Partial bool `json:"partial,omitempty"`
+
+	// Delete is true if the object was deleted.
+	Delete bool `json:"delete,omitempty"`
+}
+
+// webSocketUpdate is an object update of the current block with the encoded values of its key fields, which
+// are matched against key prefixes.
+type webSocketUpdate struct {
+	update    WebSocketObjectUpdate
+	keyFields [][]byte
+}
+
+// webSocketSubscription is a parsed subscription with its key prefix encoded with schema.EncodeValue.
+type webSocketSubscription struct {
+	WebSocketSubscription
+	keyPrefix [][]byte
+}
+
+// id identifies equal subscriptions.
+func (s webSocketSubscription) id() string {
+	return s.Module + "\x00" + s.Type + "\x00" + string(bytes.Join(s.keyPrefix, []byte{0}))
+}
+
+func (s webSocketSubscription) matches(update webSocketUpdate) bool {
+	if s.Module != "" && s.Module != update.update.Module {
+		return false
+	}
+	if s.Type != "" && s.Type != update.update.Type {
+		return false
+	}
+	if len(s.keyPrefix) > len(update.keyFields) {
+		return false
+	}
+	for i, value := range s.keyPrefix {
+		if !bytes.Equal(value, update.keyFields[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseWebSocketSubscription(sub WebSocketSubscription) (webSocketSubscription, error) {
+	res := webSocketSubscription{WebSocketSubscription: sub}
+	for _, bz := range sub.KeyPrefix {
+		value, err := schema.DecodeValueJSON(bz)
+		if err != nil {
+			return res, fmt.Errorf("invalid key prefix value %s: %w", bz, err)
+		}
+		encoded, err := schema.EncodeValue(value)
+		if err != nil {
+			return res, fmt.Errorf("invalid key prefix value %s: %w", bz, err)
+		}
+		res.keyPrefix = append(res.keyPrefix, encoded)
+	}
+	return res, nil
+}
+
+// webSocketIndexer tracks the clients and the object updates of the current block. Packets are delivered to
+// the listener sequentially, so only the clients are guarded by mu.
+type webSocketIndexer struct {
+	cfg      WebSocketIndexerConfig
+	logger   logutil.Logger
+	upgrader websocket.Upgrader
+
+	schemas map[string]schema.ModuleSchema
+	height  uint64
+	pending []webSocketUpdate
+
+	mu      sync.Mutex
+	closed  bool
+	clients map[*webSocketClient]bool
+}
+
+func newWebSocketIndexer(cfg WebSocketIndexerConfig, logger logutil.Logger) *webSocketIndexer {
+	ws := &webSocketIndexer{
+		cfg:     cfg,
+		logger:  logger,
+		schemas: map[string]schema.ModuleSchema{},
+		clients: map[*webSocketClient]bool{},
+	}
+	ws.upgrader.CheckOrigin = ws.checkOrigin
+	return ws
+}
+
+func (ws *webSocketIndexer) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range ws.cfg.AllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(origin, "https://"), "http://") == r.Host
+}
+
+func (ws *webSocketIndexer) listener() appdata.Listener {
+	return appdata.Listener{
+		InitializeModuleData: func(data appdata.ModuleInitializationData) error {
+			ws.schemas[data.ModuleName] = data.Schema
+			return nil
+		},
+		StartBlock: func(data appdata.StartBlockData) error {
+			ws.height = data.Height
+			ws.pending = nil
+			return nil
+		},
+		OnObjectUpdate: ws.onObjectUpdate,
+		Commit: func(appdata.CommitData) error {
+			ws.publish()
+			ws.pending = nil
+			return nil
+		},
+	}
+}
+
+func (ws *webSocketIndexer) onObjectUpdate(data appdata.ObjectUpdateData) error {
+	if !ws.hasClients() {
+		return nil
+	}
+
+	moduleSchema, ok := ws.schemas[data.ModuleName]
+	if !ok {
+		return nil
+	}
+
+	for _, update := range data.Updates {
+		typ, ok := moduleSchema.LookupType(update.TypeName)
+		if !ok {
+			return fmt.Errorf("unknown object type %q in module %q", update.TypeName, data.ModuleName)
+		}
+		objectType, ok := typ.(schema.ObjectType)
+		if !ok {
+			return fmt.Errorf("type %q in module %q is not an object type", update.TypeName, data.ModuleName)
+		}
+
+		encoded, err := encodeWebSocketUpdate(data.ModuleName, objectType, update)
+		if err != nil {
+			return fmt.Errorf("failed to encode %q update in module %q: %w", update.TypeName, data.ModuleName, err)
+		}
+		ws.pending = append(ws.pending, encoded)
+	}
+	return nil
+}
+
+// encodeWebSocketUpdate names the fields of an object update and encodes their values.
+func encodeWebSocketUpdate(module string, objectType schema.ObjectType, update schema.ObjectUpdate) (webSocketUpdate, error) {
+	res := webSocketUpdate{update: WebSocketObjectUpdate{
+		Module: module,
+		Type:   update.TypeName,
+		Key:    make(map[string]json.RawMessage, len(objectType.KeyFields)),
+		Delete: update.Delete,
+	}}
+
+	for _, field := range objectType.KeyFields {
+		value, err := update.KeyFieldValue(objectType, field.Name)
+		if err != nil {
+			return res, err
+		}
+		if res.update.Key[field.Name], err = schema.EncodeValueJSON(value); err != nil {
+			return res, err
+		}
+		encoded, err := schema.EncodeValue(value)
+		if err != nil {
+			return res, err
+		}
+		res.keyFields = append(res.keyFields, encoded)
+	}
+
+	if update.Delete {
+		return res, nil
+	}
+
+	res.update.Value = make(map[string]json.RawMessage, len(objectType.ValueFields))
+	if valueUpdates, ok := update.Value.(schema.ValueUpdates); ok {
+		res.update.Partial = true
+		var encodeErr error
+		err := valueUpdates.Iterate(func(name string, value interface{}) bool {
+			res.update.Value[name], encodeErr = schema.EncodeValueJSON(value)
+			return encodeErr == nil
+		})
+		if err == nil {
+			err = encodeErr
+		}
+		return res, err
+	}
+
+	for _, field := range objectType.ValueFields {
+		value, err := update.ValueFieldValue(objectType, field.Name)
+		if err != nil {
+			return res, err
+		}
+		if res.update.Value[field.Name], err = schema.EncodeValueJSON(value); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// publish sends the updates of the current block to each client which has a subscription matching any of them.
+func (ws *webSocketIndexer) publish() {
+	if len(ws.pending) == 0 {
+		return
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	for client := range ws.clients {
+		msg := WebSocketMessage{Height: ws.height}
+		for _, update := range ws.pending {
+			for _, sub := range client.subscriptions {
+				if sub.matches(update) {
+					msg.Updates = append(msg.Updates, update.update)
+					break
+				}
+			}
+		}
+		if len(msg.Updates) == 0 {
+			continue
+		}
+
+		bz, err := json.Marshal(msg)
+		if err != nil {
+			ws.logger.Error("failed to encode object update websocket message", "error", err)
+			continue
+		}
+		select {
+		case client.send <- bz:
+		default:
+			ws.logger.Warn("closing slow object update websocket client", "remote", client.remote)
+			ws.removeLocked(client)
+		}
+	}
+}
+
+func (ws *webSocketIndexer) hasClients() bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return len(ws.clients) != 0
+}
+
+func (ws *webSocketIndexer) close() {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.closed = true
+	for client := range ws.clients {
+		ws.removeLocked(client)
+	}
+}
+
+func (ws *webSocketIndexer) remove(client *webSocketClient) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.removeLocked(client)
+}
+
+func (ws *webSocketIndexer) removeLocked(client *webSocketClient) {
+	if !ws.clients[client] {
+		return
+	}
+	delete(ws.clients, client)
+	close(client.send)
+}
+
+// webSocketWriteTimeout is the time after which writing a message to a client fails.
+const webSocketWriteTimeout = 10 * time.Second
+
+// webSocketClient is a WebSocket connection. Its subscriptions are guarded by the mutex of the indexer.
+type webSocketClient struct {
+	conn          *websocket.Conn
+	remote        string
+	send          chan []byte
+	subscriptions []webSocketSubscription
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection and serves the subscriptions. The module and type
+// query parameters, if one of them is set, are subscribed to when the connection is opened.
+func (ws *webSocketIndexer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var initial WebSocketControlMessage
+	query := r.URL.Query()
+	if query.Get("module") != "" || query.Get("type") != "" {
+		initial.Subscribe = []WebSocketSubscription{{Module: query.Get("module"), Type: query.Get("type")}}
+	}
+
+	client := &webSocketClient{remote: r.RemoteAddr, send: make(chan []byte, ws.cfg.BufferSize)}
+	if err := ws.update(client, initial); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conn, err := ws.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has already replied with an error
+		return
+	}
+	client.conn = conn
+
+	ws.mu.Lock()
+	if ws.closed {
+		ws.mu.Unlock()
+		_ = conn.Close()
+		return
+	}
+	ws.clients[client] = true
+	ws.mu.Unlock()
+
+	ws.respond(client, nil)
+	go ws.write(client)
+	ws.read(client)
+}
+
+// read handles the control messages of the client until the connection is closed.
+func (ws *webSocketIndexer) read(client *webSocketClient) {
+	defer ws.remove(client)
+
+	for {
+		_, bz, err := client.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg WebSocketControlMessage
+		if err := json.Unmarshal(bz, &msg); err != nil {
+			ws.respond(client, fmt.Errorf("invalid control message: %w", err))
+			continue
+		}
+		ws.respond(client, ws.update(client, msg))
+	}
+}
+
+// write sends the messages of the client until its channel is closed.
+func (ws *webSocketIndexer) write(client *webSocketClient) {
+	defer client.conn.Close()
+
+	for bz := range client.send {
+		_ = client.conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+		if err := client.conn.WriteMessage(websocket.TextMessage, bz); err != nil {
+			ws.remove(client)
+			return
+		}
+	}
+	_ = client.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(webSocketWriteTimeout))
+}
+
+// update applies a control message to the subscriptions of the client. Either all subscriptions are applied
+// or none.
+func (ws *webSocketIndexer) update(client *webSocketClient, msg WebSocketControlMessage) error {
+	subscribe := make([]webSocketSubscription, 0, len(msg.Subscribe))
+	for _, sub := range msg.Subscribe {
+		parsed, err := parseWebSocketSubscription(sub)
+		if err != nil {
+			return err
+		}
+		subscribe = append(subscribe, parsed)
+	}
+	unsubscribe := make(map[string]bool, len(msg.Unsubscribe))
+	for _, sub := range msg.Unsubscribe {
+		parsed, err := parseWebSocketSubscription(sub)
+		if err != nil {
+			return err
+		}
+		unsubscribe[parsed.id()] = true
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	var res []webSocketSubscription
+	seen := map[string]bool{}
+	for _, sub := range append(append([]webSocketSubscription{}, client.subscriptions...), subscribe...) {
+		if id := sub.id(); !seen[id] && !unsubscribe[id] {
+			seen[id] = true
+			res = append(res, sub)
+		}
+	}
+	if len(res) > ws.cfg.MaxSubscriptions {
+		return fmt.Errorf("cannot have more than %d subscriptions", ws.cfg.MaxSubscriptions)
+	}
+
+	client.subscriptions = res
+	return nil
+}
+
+// respond queues the response to a control message for the client.
+func (ws *webSocketIndexer) respond(client *webSocketClient, err error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if !ws.clients[client] {
+		return
+	}
+
+	res := WebSocketControlResponse{Subscriptions: make([]WebSocketSubscription, len(client.subscriptions))}
+	for i, sub := range client.subscriptions {
+		res.Subscriptions[i] = sub.WebSocketSubscription
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	bz, err := json.Marshal(res)
+	if err != nil {
+		return
+	}
+	select {
+	case client.send <- bz:
+	default:
+		ws.removeLocked(client)
+	}
+}
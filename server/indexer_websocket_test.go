@@ -0,0 +1,148 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/logutil"
+)
+
+func testWebSocketIndexer(t *testing.T, cfg map[string]interface{}) (appdata.Listener, *httptest.Server) {
+	t.Helper()
+	c, err := decodeWebSocketIndexerConfig(cfg)
+	require.NoError(t, err)
+	ws := newWebSocketIndexer(c, logutil.NoopLogger{})
+	srv := httptest.NewServer(ws)
+	t.Cleanup(func() {
+		ws.close()
+		srv.Close()
+	})
+
+	moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{
+		{
+			Name:        "balances",
+			KeyFields:   []schema.Field{{Name: "address", Kind: schema.AddressKind}, {Name: "denom", Kind: schema.StringKind}},
+			ValueFields: []schema.Field{{Name: "amount", Kind: schema.IntegerStringKind}},
+		},
+		{
+			Name:        "supply",
+			KeyFields:   []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+			ValueFields: []schema.Field{{Name: "amount", Kind: schema.IntegerStringKind}, {Name: "holders", Kind: schema.Uint64Kind}},
+		},
+	})
+	require.NoError(t, err)
+	listener := ws.listener()
+	require.NoError(t, listener.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "bank", Schema: moduleSchema}))
+	return listener, srv
+}
+
+func dialWebSocket(t *testing.T, srv *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/?"+query, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func readWebSocketJSON(t *testing.T, conn *websocket.Conn, v interface{}) {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, bz, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(bz, v))
+}
+
+func sendWebSocketBlock(t *testing.T, listener appdata.Listener, height uint64, updates ...schema.ObjectUpdate) {
+	t.Helper()
+	require.NoError(t, listener.StartBlock(appdata.StartBlockData{Height: height}))
+	require.NoError(t, listener.OnObjectUpdate(appdata.ObjectUpdateData{ModuleName: "bank", Updates: updates}))
+	require.NoError(t, listener.Commit(appdata.CommitData{}))
+}
+
+func TestWebSocketIndexer(t *testing.T) {
+	listener, srv := testWebSocketIndexer(t, nil)
+	alice := []byte{0x01, 0x02}
+
+	supplyConn := dialWebSocket(t, srv, "module=bank&type=supply")
+	var res WebSocketControlResponse
+	readWebSocketJSON(t, supplyConn, &res)
+	require.Equal(t, []WebSocketSubscription{{Module: "bank", Type: "supply"}}, res.Subscriptions)
+
+	aliceConn := dialWebSocket(t, srv, "")
+	readWebSocketJSON(t, aliceConn, &res)
+	require.Empty(t, res.Subscriptions)
+	aliceKey, err := schema.EncodeValueJSON(alice)
+	require.NoError(t, err)
+	sub := WebSocketSubscription{Module: "bank", Type: "balances", KeyPrefix: []json.RawMessage{aliceKey}}
+	require.NoError(t, aliceConn.WriteJSON(WebSocketControlMessage{Subscribe: []WebSocketSubscription{sub}}))
+	readWebSocketJSON(t, aliceConn, &res)
+	require.Empty(t, res.Error)
+	require.Len(t, res.Subscriptions, 1)
+
+	sendWebSocketBlock(t, listener, 5,
+		schema.ObjectUpdate{TypeName: "balances", Key: []interface{}{alice, "uatom"}, Value: "10"},
+		schema.ObjectUpdate{TypeName: "balances", Key: []interface{}{[]byte{0x03}, "uatom"}, Value: "20"},
+		schema.ObjectUpdate{TypeName: "supply", Key: "uatom", Value: schema.MapValueUpdates{"amount": "30"}},
+	)
+
+	var msg WebSocketMessage
+	readWebSocketJSON(t, aliceConn, &msg)
+	require.Equal(t, uint64(5), msg.Height)
+	require.Len(t, msg.Updates, 1)
+	require.Equal(t, "balances", msg.Updates[0].Type)
+	require.JSONEq(t, string(aliceKey), string(msg.Updates[0].Key["address"]))
+	require.JSONEq(t, `{"string":"uatom"}`, string(msg.Updates[0].Key["denom"]))
+	require.JSONEq(t, `{"string":"10"}`, string(msg.Updates[0].Value["amount"]))
+
+	msg = WebSocketMessage{}
+	readWebSocketJSON(t, supplyConn, &msg)
+	require.Len(t, msg.Updates, 1)
+	require.True(t, msg.Updates[0].Partial)
+	require.Len(t, msg.Updates[0].Value, 1)
+
+	// deletes are delivered without a value and unsubscribed clients don't receive updates
+	require.NoError(t, supplyConn.WriteJSON(WebSocketControlMessage{Unsubscribe: []WebSocketSubscription{{Module: "bank", Type: "supply"}}}))
+	readWebSocketJSON(t, supplyConn, &res)
+	require.Empty(t, res.Subscriptions)
+	sendWebSocketBlock(t, listener, 6,
+		schema.ObjectUpdate{TypeName: "supply", Key: "uatom", Delete: true},
+		schema.ObjectUpdate{TypeName: "balances", Key: []interface{}{alice, "uatom"}, Delete: true},
+	)
+	msg = WebSocketMessage{}
+	readWebSocketJSON(t, aliceConn, &msg)
+	require.Equal(t, uint64(6), msg.Height)
+	require.Len(t, msg.Updates, 1)
+	require.True(t, msg.Updates[0].Delete)
+	require.Empty(t, msg.Updates[0].Value)
+
+	require.NoError(t, supplyConn.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+	_, _, err = supplyConn.ReadMessage()
+	require.Error(t, err)
+}
+
+func TestWebSocketIndexer_invalidSubscriptions(t *testing.T) {
+	_, srv := testWebSocketIndexer(t, map[string]interface{}{"max_subscriptions": 1})
+	conn := dialWebSocket(t, srv, "module=bank")
+	var res WebSocketControlResponse
+	readWebSocketJSON(t, conn, &res)
+
+	require.NoError(t, conn.WriteJSON(WebSocketControlMessage{Subscribe: []WebSocketSubscription{{Module: "staking"}}}))
+	readWebSocketJSON(t, conn, &res)
+	require.Contains(t, res.Error, "cannot have more than 1 subscriptions")
+	require.Equal(t, []WebSocketSubscription{{Module: "bank"}}, res.Subscriptions)
+
+	require.NoError(t, conn.WriteJSON(WebSocketControlMessage{Subscribe: []WebSocketSubscription{{KeyPrefix: []json.RawMessage{json.RawMessage(`{"unknown":1}`)}}}}))
+	readWebSocketJSON(t, conn, &res)
+	require.Contains(t, res.Error, "invalid key prefix value")
+
+	_, err := decodeWebSocketIndexerConfig(map[string]interface{}{"buffer_size": -1})
+	require.Error(t, err)
+}
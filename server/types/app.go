@@ -10,6 +10,7 @@ import (
 	"github.com/cosmos/gogoproto/grpc"
 
 	"cosmossdk.io/log"
+	"cosmossdk.io/schema/appdata"
 	"cosmossdk.io/store/snapshots"
 	storetypes "cosmossdk.io/store/types"
 
@@ -78,6 +79,10 @@ type (
 		Height int64
 		// ConsensusParams are the exported consensus params for ABCI.
 		ConsensusParams cmtproto.ConsensusParams
+		// ExportObjectUpdates streams the exported state to the listener as InitializeModuleData and
+		// ObjectUpdate packets decoded with the module schemas. It is nil if the app doesn't support
+		// exporting its state in the schema format.
+		ExportObjectUpdates func(listener appdata.Listener) error
 	}
 
 	// AppExporter is a function that dumps all app state to
@@ -18,6 +18,7 @@ import (
 	"cosmossdk.io/core/store"
 	"cosmossdk.io/core/transaction"
 	errorsmod "cosmossdk.io/errors"
+	"cosmossdk.io/schema/appdata"
 	"cosmossdk.io/server/v2/appmanager"
 	"cosmossdk.io/server/v2/cometbft/client/grpc/cmtservice"
 	"cosmossdk.io/server/v2/cometbft/handlers"
@@ -39,6 +40,7 @@ type Consensus[T transaction.Tx] struct {
 	txCodec            transaction.Codec[T]
 	store              types.Store
 	streaming          streaming.Manager
+	appData            *streaming.AppDataListener
 	snapshotManager    *snapshots.Manager
 	mempool            mempool.Mempool[T]
 	grpcQueryDecoders  map[string]func(requestBytes []byte) (gogoproto.Message, error) // legacy support for gRPC
@@ -103,6 +105,12 @@ func (c *Consensus[T]) SetStreamingManager(sm streaming.Manager) {
 	c.streaming = sm
 }
 
+// SetAppDataListener sets an app data listener, such as the listener of the indexer manager, which receives
+// the changeset of every committed block. Errors returned by the listener halt the node.
+func (c *Consensus[T]) SetAppDataListener(listener appdata.Listener) {
+	c.appData = streaming.NewAppDataListener(listener)
+}
+
 // RegisterSnapshotExtensions registers the given extensions with the consensus module's snapshot manager.
 // It allows additional snapshotter implementations to be used for creating and restoring snapshots.
 func (c *Consensus[T]) RegisterSnapshotExtensions(extensions ...snapshots.ExtensionSnapshotter) error {
@@ -469,11 +477,18 @@ func (c *Consensus[T]) FinalizeBlock(
 	if err != nil {
 		return nil, err
 	}
-	appHash, err := c.store.Commit(&store.Changeset{Changes: stateChanges})
+	changeset := &store.Changeset{Changes: stateChanges}
+	appHash, err := c.store.Commit(changeset)
 	if err != nil {
 		return nil, fmt.Errorf("unable to commit the changeset: %w", err)
 	}
 
+	if c.appData != nil {
+		if err := c.appData.ListenCommit(uint64(req.Height), changeset); err != nil {
+			return nil, fmt.Errorf("app data listener failed at height %d: %w", req.Height, err)
+		}
+	}
+
 	var events []event.Event
 	events = append(events, resp.PreBlockEvents...)
 	events = append(events, resp.BeginBlockEvents...)
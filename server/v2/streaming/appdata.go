@@ -0,0 +1,91 @@
+package streaming
+
+import (
+	"fmt"
+
+	"cosmossdk.io/core/store"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/decoding"
+)
+
+// ChangesetToKVPairData converts a store/v2 changeset into an app data KVPairData packet. The module name of
+// each update is the store key (actor) of its state changes and removals are converted into deletions. The
+// order of the state changes in the changeset is preserved.
+func ChangesetToKVPairData(cs *store.Changeset) appdata.KVPairData {
+	updates := make([]appdata.ModuleKVPairUpdate, 0, cs.Size())
+	for _, changes := range cs.Changes {
+		moduleName := string(changes.Actor)
+		for _, pair := range changes.StateChanges {
+			updates = append(updates, appdata.ModuleKVPairUpdate{
+				ModuleName: moduleName,
+				Update: schema.KVPairUpdate{
+					Key:    pair.Key,
+					Value:  pair.Value,
+					Delete: pair.Remove,
+				},
+			})
+		}
+	}
+	return appdata.KVPairData{Updates: updates}
+}
+
+// ChangesetToObjectUpdateData decodes a store/v2 changeset into app data ObjectUpdateData packets, one per
+// store key, using the module codecs found by resolver. The state changes of store keys without a codec or
+// whose codec has no KVDecoder are skipped.
+func ChangesetToObjectUpdateData(cs *store.Changeset, resolver decoding.DecoderResolver) ([]appdata.ObjectUpdateData, error) {
+	var res []appdata.ObjectUpdateData
+	for _, changes := range cs.Changes {
+		moduleName := string(changes.Actor)
+		cdc, found, err := resolver.LookupDecoder(moduleName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up decoder for module %s: %w", moduleName, err)
+		}
+		if !found || cdc.KVDecoder == nil {
+			continue
+		}
+
+		var updates []schema.ObjectUpdate
+		for _, pair := range changes.StateChanges {
+			decoded, err := cdc.KVDecoder(schema.KVPairUpdate{Key: pair.Key, Value: pair.Value, Delete: pair.Remove})
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode state change of module %s: %w", moduleName, err)
+			}
+			updates = append(updates, decoded...)
+		}
+
+		if len(updates) != 0 {
+			res = append(res, appdata.ObjectUpdateData{ModuleName: moduleName, Updates: updates})
+		}
+	}
+	return res, nil
+}
+
+// AppDataListener streams the changesets committed by a store/v2 root store to an app data listener, such as
+// the listener of the indexer manager, without going through the plugin Listener interface. Wrap the listener
+// with decoding.Middleware to also receive ObjectUpdateData for the modules which have a codec.
+type AppDataListener struct {
+	listener appdata.Listener
+}
+
+// NewAppDataListener returns an AppDataListener which forwards committed changesets to listener.
+func NewAppDataListener(listener appdata.Listener) *AppDataListener {
+	return &AppDataListener{listener: listener}
+}
+
+// ListenCommit must be called once the changeset of the block at height has been committed to the store. It
+// sends a StartBlockData, the changeset as a single KVPairData and a CommitData to the listener, so that
+// listeners only ever receive the state changes of committed blocks.
+func (l *AppDataListener) ListenCommit(height uint64, cs *store.Changeset) error {
+	if err := l.listener.SendPacket(appdata.StartBlockData{Height: height}); err != nil {
+		return err
+	}
+
+	if l.listener.OnKVPair != nil && cs != nil && cs.Size() != 0 {
+		if err := l.listener.OnKVPair(ChangesetToKVPairData(cs)); err != nil {
+			return err
+		}
+	}
+
+	return l.listener.SendPacket(appdata.CommitData{})
+}
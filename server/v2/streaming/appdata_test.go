@@ -0,0 +1,87 @@
+package streaming
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/core/store"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/decoding"
+)
+
+func testChangeset() *store.Changeset {
+	cs := store.NewChangeset()
+	cs.Add([]byte("bank"), []byte("a"), []byte("1"), false)
+	cs.Add([]byte("staking"), []byte("b"), []byte("2"), false)
+	cs.Add([]byte("bank"), []byte("c"), nil, true)
+	return cs
+}
+
+func TestChangesetToKVPairData(t *testing.T) {
+	data := ChangesetToKVPairData(testChangeset())
+	require.Equal(t, []appdata.ModuleKVPairUpdate{
+		{ModuleName: "bank", Update: schema.KVPairUpdate{Key: []byte("a"), Value: []byte("1")}},
+		{ModuleName: "bank", Update: schema.KVPairUpdate{Key: []byte("c"), Delete: true}},
+		{ModuleName: "staking", Update: schema.KVPairUpdate{Key: []byte("b"), Value: []byte("2")}},
+	}, data.Updates)
+}
+
+func TestChangesetToObjectUpdateData(t *testing.T) {
+	resolver := decoding.ModuleSetDecoderResolver(map[string]interface{}{"bank": testModule{}})
+	data, err := ChangesetToObjectUpdateData(testChangeset(), resolver)
+	require.NoError(t, err)
+	require.Equal(t, []appdata.ObjectUpdateData{{
+		ModuleName: "bank",
+		Updates: []schema.ObjectUpdate{
+			{TypeName: "balances", Key: "a", Value: "1"},
+			{TypeName: "balances", Key: "c", Delete: true},
+		},
+	}}, data)
+}
+
+func TestAppDataListener(t *testing.T) {
+	var calls []string
+	listener := NewAppDataListener(appdata.Listener{
+		StartBlock: func(data appdata.StartBlockData) error {
+			calls = append(calls, fmt.Sprintf("start %d", data.Height))
+			return nil
+		},
+		OnKVPair: func(data appdata.KVPairData) error {
+			calls = append(calls, fmt.Sprintf("kv %d", len(data.Updates)))
+			return nil
+		},
+		Commit: func(appdata.CommitData) error {
+			calls = append(calls, "commit")
+			return nil
+		},
+	})
+
+	require.NoError(t, listener.ListenCommit(1, testChangeset()))
+	require.NoError(t, listener.ListenCommit(2, store.NewChangeset()))
+	require.Equal(t, []string{"start 1", "kv 3", "commit", "start 2", "commit"}, calls)
+}
+
+type testModule struct{}
+
+func (testModule) ModuleCodec() (schema.ModuleCodec, error) {
+	modSchema, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:        "balances",
+		KeyFields:   []schema.Field{{Name: "address", Kind: schema.StringKind}},
+		ValueFields: []schema.Field{{Name: "amount", Kind: schema.StringKind}},
+	}})
+	if err != nil {
+		return schema.ModuleCodec{}, err
+	}
+	return schema.ModuleCodec{
+		Schema: modSchema,
+		KVDecoder: func(update schema.KVPairUpdate) ([]schema.ObjectUpdate, error) {
+			if update.Delete {
+				return []schema.ObjectUpdate{{TypeName: "balances", Key: string(update.Key), Delete: true}}, nil
+			}
+			return []schema.ObjectUpdate{{TypeName: "balances", Key: string(update.Key), Value: string(update.Value)}}, nil
+		},
+	}, nil
+}
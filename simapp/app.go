@@ -264,9 +264,13 @@ func NewSimApp(
 		accounts.StoreKey, epochstypes.StoreKey,
 	)
 
-	// register streaming services
-	if err := bApp.RegisterStreamingServices(appOpts, keys); err != nil {
-		panic(err)
+	// register legacy streaming services if we don't have the built-in indexer enabled, the indexer is
+	// enabled once the module manager has been created
+	indexerOpts := appOpts.Get("indexer")
+	if indexerOpts == nil {
+		if err := bApp.RegisterStreamingServices(appOpts, keys); err != nil {
+			panic(err)
+		}
 	}
 
 	app := &SimApp{
@@ -460,6 +464,20 @@ func NewSimApp(
 		epochstypes.ModuleName:         epochs.NewAppModule(appCodec, app.EpochsKeeper),
 	})
 
+	if indexerOpts != nil {
+		// if we have indexer options in app.toml, then enable the built-in indexer framework
+		moduleSet := map[string]any{}
+		for modName, mod := range app.ModuleManager.Modules {
+			moduleSet[modName] = mod
+		}
+		if err := app.EnableIndexer(indexerOpts, keys, moduleSet); err != nil {
+			panic(err)
+		}
+
+		// check the next-version module schemas of upgrades against the indexer targets, only logging failures
+		app.UpgradeKeeper.SetSchemaUpgradeChecker(app.IndexerManager(), false)
+	}
+
 	app.ModuleManager.RegisterLegacyAminoCodec(legacyAmino)
 	app.ModuleManager.RegisterInterfaces(interfaceRegistry)
 
@@ -548,8 +566,13 @@ func NewSimApp(
 
 	// register custom snapshot extensions (if any)
 	if manager := app.SnapshotManager(); manager != nil {
+		moduleSet := map[string]any{}
+		for modName, mod := range app.ModuleManager.Modules {
+			moduleSet[modName] = mod
+		}
 		err := manager.RegisterExtensions(
 			unorderedtx.NewSnapshotter(app.UnorderedTxManager),
+			baseapp.NewIndexerCheckpointSnapshotter(app.BaseApp, moduleSet),
 		)
 		if err != nil {
 			panic(fmt.Errorf("failed to register snapshot extension: %w", err))
@@ -255,6 +255,9 @@ func NewSimApp(
 		if err != nil {
 			panic(err)
 		}
+
+		// check the next-version module schemas of upgrades against the indexer targets, only logging failures
+		app.UpgradeKeeper.SetSchemaUpgradeChecker(app.IndexerManager(), false)
 	} else {
 		// register legacy streaming services if we don't have the built-in indexer enabled
 		if err := app.RegisterStreamingServices(appOpts, app.kvStoreKeys()); err != nil {
@@ -303,8 +306,13 @@ func NewSimApp(
 
 	// register custom snapshot extensions (if any)
 	if manager := app.SnapshotManager(); manager != nil {
+		moduleSet := map[string]any{}
+		for modName, mod := range app.ModuleManager.Modules {
+			moduleSet[modName] = mod
+		}
 		err := manager.RegisterExtensions(
 			unorderedtx.NewSnapshotter(app.UnorderedTxManager),
+			baseapp.NewIndexerCheckpointSnapshotter(app.BaseApp, moduleSet),
 		)
 		if err != nil {
 			panic(fmt.Errorf("failed to register snapshot extension: %w", err))
@@ -8,6 +8,7 @@ import (
 	cmtproto "github.com/cometbft/cometbft/api/cometbft/types/v1"
 
 	"cosmossdk.io/collections"
+	"cosmossdk.io/schema/appdata"
 	storetypes "cosmossdk.io/store/types"
 	slashingtypes "cosmossdk.io/x/slashing/types"
 	"cosmossdk.io/x/staking"
@@ -41,12 +42,22 @@ func (app *SimApp) ExportAppStateAndValidators(forZeroHeight bool, jailAllowedAd
 		return servertypes.ExportedApp{}, err
 	}
 
+	keys := make(map[string]*storetypes.KVStoreKey)
+	for _, key := range app.GetStoreKeys() {
+		if kvStoreKey, ok := key.(*storetypes.KVStoreKey); ok {
+			keys[kvStoreKey.Name()] = kvStoreKey
+		}
+	}
+
 	validators, err := staking.WriteValidators(ctx, app.StakingKeeper)
 	return servertypes.ExportedApp{
 		AppState:        appState,
 		Validators:      validators,
 		Height:          height,
 		ConsensusParams: app.BaseApp.GetConsensusParams(ctx),
+		ExportObjectUpdates: func(listener appdata.Listener) error {
+			return app.ModuleManager.ExportObjectUpdates(ctx, keys, listener, modulesToExport)
+		},
 	}, err
 }
 
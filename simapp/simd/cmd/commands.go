@@ -40,7 +40,7 @@ func initRootCmd(
 		genutilcli.InitCmd(moduleManager),
 		NewTestnetCmd(moduleManager),
 		debug.Cmd(),
-		confixcmd.ConfigCommand(),
+		configCommand(),
 		pruning.Cmd(newApp),
 		snapshot.Cmd(newApp),
 	)
@@ -50,6 +50,7 @@ func initRootCmd(
 	// add keybase, auxiliary RPC, query, genesis, and tx child commands
 	rootCmd.AddCommand(
 		server.StatusCommand(),
+		server.IndexerCmd(newApp),
 		genesisCommand(moduleManager, appExport),
 		queryCommand(),
 		txCommand(),
@@ -67,6 +68,13 @@ func genesisCommand(moduleManager *module.Manager, appExport servertypes.AppExpo
 	return cmd
 }
 
+// configCommand builds the `simd config` command, which adds the validation of the indexer config to the confix commands
+func configCommand() *cobra.Command {
+	cmd := confixcmd.ConfigCommand()
+	cmd.AddCommand(server.ValidateIndexerConfigCmd())
+	return cmd
+}
+
 func queryCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:                        "query",
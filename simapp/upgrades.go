@@ -22,6 +22,10 @@ import (
 // v0.50.x to v0.51.x.
 const UpgradeName = "v050-to-v051"
 
+// NextUpgradeName defines the on-chain name of the upgrade following UpgradeName, which this binary doesn't
+// handle. The modules present the schemas they will have after it with upgradetypes.HasUpgradeSchema.
+const NextUpgradeName = countertypes.CountUpgradeName
+
 func (app SimApp) RegisterUpgradeHandlers() {
 	app.UpgradeKeeper.SetUpgradeHandler(
 		UpgradeName,
@@ -36,6 +40,14 @@ func (app SimApp) RegisterUpgradeHandlers() {
 		},
 	)
 
+	// this binary has no handler for the upgrade following UpgradeName, so it presents the schemas which the
+	// modules will have after it to the schema checker, which validates them ahead of the upgrade height
+	modules := make(map[string]interface{}, len(app.ModuleManager.Modules))
+	for name, mod := range app.ModuleManager.Modules {
+		modules[name] = mod
+	}
+	app.UpgradeKeeper.SetUpgradeSchemaHandler(NextUpgradeName, upgradetypes.ModuleUpgradeSchemas(modules))
+
 	upgradeInfo, err := app.UpgradeKeeper.ReadUpgradeInfoFromDisk()
 	if err != nil {
 		panic(err)
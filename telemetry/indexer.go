@@ -0,0 +1,91 @@
+package telemetry
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-metrics"
+
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+)
+
+// Indexer metric key and label constants
+const (
+	MetricKeyIndexer       = "indexer"
+	MetricLabelNameTarget  = "target"
+	MetricLabelNamePacket  = "packet_type"
+	MetricLabelNameJournal = "journal"
+	MetricLabelNameQueue   = "queue"
+)
+
+// IndexerMetrics returns the callbacks which report the metrics of the indexer manager with the telemetry
+// sinks of the node, such as its Prometheus endpoint:
+//
+//   - indexer_packets: the number of packets delivered to the manager per packet type
+//   - indexer_decode_latency: the time taken to decode key-value pairs per module, in milliseconds
+//   - indexer_commit_latency: the time taken by each target to commit a block, in milliseconds
+//   - indexer_last_indexed_height: the height of the last block committed by each target
+func IndexerMetrics() indexer.Metrics {
+	return indexer.Metrics{
+		OnPacket: func(p appdata.Packet) {
+			IncrCounterWithLabels([]string{MetricKeyIndexer, "packets"}, 1, []metrics.Label{NewLabel(MetricLabelNamePacket, appdata.PacketType(p))})
+		},
+		OnDecodeLatency: func(moduleName string, latency time.Duration) {
+			addLatencySample([]string{MetricKeyIndexer, "decode_latency"}, latency, NewLabel(MetricLabelNameModule, moduleName))
+		},
+		OnCommitLatency: func(target string, latency time.Duration) {
+			addLatencySample([]string{MetricKeyIndexer, "commit_latency"}, latency, NewLabel(MetricLabelNameTarget, target))
+		},
+		OnCommittedHeight: func(target string, height int64) {
+			SetGaugeWithLabels([]string{MetricKeyIndexer, "last_indexed_height"}, float32(height), []metrics.Label{NewLabel(MetricLabelNameTarget, target)})
+		},
+	}
+}
+
+// IndexerQueueDepth returns a callback for appdata.AsyncListenerOptions.OnQueueDepth which reports the depth
+// of the named queue as the indexer_queue_depth gauge.
+func IndexerQueueDepth(name string) func(depth int) {
+	return func(depth int) {
+		SetGaugeWithLabels([]string{MetricKeyIndexer, "queue_depth"}, float32(depth), []metrics.Label{NewLabel(MetricLabelNameQueue, name)})
+	}
+}
+
+// IndexerJournalWriter wraps the writer of an appdata journal so that the number of bytes written to the named
+// journal is reported as the indexer_journal_size gauge. size is the initial size of the journal, for instance
+// the size of the file which is appended to.
+func IndexerJournalWriter(w io.Writer, name string, size int64) io.Writer {
+	return &journalWriter{w: w, name: name, size: size}
+}
+
+type journalWriter struct {
+	w    io.Writer
+	name string
+	size int64
+}
+
+func (j *journalWriter) Write(p []byte) (int, error) {
+	n, err := j.w.Write(p)
+	size := atomic.AddInt64(&j.size, int64(n))
+	SetGaugeWithLabels([]string{MetricKeyIndexer, "journal_size"}, float32(size), []metrics.Label{NewLabel(MetricLabelNameJournal, j.name)})
+	return n, err
+}
+
+// Sync syncs the wrapped writer if it has a Sync() error method, so that appdata.JournalListener still syncs
+// the journal before commits.
+func (j *journalWriter) Sync() error {
+	if syncer, ok := j.w.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+	return nil
+}
+
+// addLatencySample emits a latency sample in milliseconds with the label and global labels (if any).
+func addLatencySample(keys []string, latency time.Duration, label metrics.Label) {
+	if !IsTelemetryEnabled() {
+		return
+	}
+
+	metrics.AddSampleWithLabels(keys, float32(latency.Nanoseconds())/float32(time.Millisecond), append([]metrics.Label{label}, globalLabels...))
+}
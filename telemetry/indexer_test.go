@@ -0,0 +1,51 @@
+package telemetry
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-metrics"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/schema/appdata"
+)
+
+func TestIndexerMetrics(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	sink := metrics.NewInmemSink(time.Minute, time.Minute)
+	cfg := metrics.DefaultConfig("test")
+	cfg.EnableHostname = false
+	cfg.EnableRuntimeMetrics = false
+	_, err := metrics.NewGlobal(cfg, sink)
+	require.NoError(t, err)
+	initTelemetry(true)
+	defer initTelemetry(false)
+
+	m := IndexerMetrics()
+	m.OnPacket(appdata.CommitData{})
+	m.OnPacket(appdata.CommitData{})
+	m.OnDecodeLatency("bank", 2*time.Millisecond)
+	m.OnCommitLatency("postgres", time.Millisecond)
+	m.OnCommittedHeight("postgres", 10)
+	IndexerQueueDepth("postgres")(3)
+
+	var buf bytes.Buffer
+	w := IndexerJournalWriter(&buf, "main", 5)
+	_, err = w.Write([]byte("abc"))
+	require.NoError(t, err)
+	require.Equal(t, "abc", buf.String())
+
+	data := sink.Data()
+	require.NotEmpty(t, data)
+	interval := data[len(data)-1]
+
+	require.Equal(t, 2, interval.Counters["test.indexer.packets;packet_type=commit"].Count)
+	require.Equal(t, float64(2), interval.Samples["test.indexer.decode_latency;module=bank"].Sum)
+	require.Equal(t, float64(1), interval.Samples["test.indexer.commit_latency;target=postgres"].Sum)
+	require.Equal(t, float32(10), interval.Gauges["test.indexer.last_indexed_height;target=postgres"].Value)
+	require.Equal(t, float32(3), interval.Gauges["test.indexer.queue_depth;queue=postgres"].Value)
+	require.Equal(t, float32(8), interval.Gauges["test.indexer.journal_size;journal=main"].Value)
+}
@@ -0,0 +1,38 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corestore "cosmossdk.io/core/store"
+	"cosmossdk.io/schema"
+)
+
+// DecodeModuleState decodes every kv-pair of a module's store with the KVDecoder of the module codec and
+// returns the decoded object updates grouped by type name. The test fails if a kv-pair can't be decoded, if
+// an object update isn't valid according to the module schema or if it doesn't encode back to the same
+// kv-pair with the KVEncoder, so that module tests only need to assert on the decoded values.
+func DecodeModuleState(t *testing.T, cdc schema.ModuleCodec, store corestore.KVStore) map[string][]schema.ObjectUpdate {
+	t.Helper()
+
+	iter, err := store.Iterator(nil, nil)
+	require.NoError(t, err)
+	defer iter.Close()
+
+	decoded := map[string][]schema.ObjectUpdate{}
+	for ; iter.Valid(); iter.Next() {
+		updates, err := cdc.KVDecoder(schema.KVPairUpdate{Key: iter.Key(), Value: iter.Value()})
+		require.NoError(t, err)
+		for _, update := range updates {
+			require.NoError(t, cdc.Schema.ValidateObjectUpdate(update))
+			decoded[update.TypeName] = append(decoded[update.TypeName], update)
+
+			pairs, err := cdc.KVEncoder(update)
+			require.NoError(t, err)
+			require.Equal(t, []schema.KVPairUpdate{{Key: iter.Key(), Value: iter.Value()}}, pairs, update.TypeName)
+		}
+	}
+	require.NoError(t, iter.Error())
+	return decoded
+}
@@ -20,15 +20,22 @@ var StoreKey = "Counter"
 type Keeper struct {
 	appmodule.Environment
 
+	Schema     collections.Schema
 	CountStore collections.Item[int64]
 }
 
 func NewKeeper(env appmodule.Environment) Keeper {
 	sb := collections.NewSchemaBuilder(env.KVStoreService)
-	return Keeper{
+	k := Keeper{
 		Environment: env,
 		CountStore:  collections.NewItem(sb, collections.NewPrefix(0), "count", collections.Int64Value),
 	}
+	schema, err := sb.Build()
+	if err != nil {
+		panic(err)
+	}
+	k.Schema = schema
+	return k
 }
 
 // Querier
@@ -3,8 +3,10 @@ package counter
 import (
 	"google.golang.org/grpc"
 
+	"cosmossdk.io/collections"
 	"cosmossdk.io/core/appmodule"
 	"cosmossdk.io/core/registry"
+	"cosmossdk.io/schema"
 
 	"github.com/cosmos/cosmos-sdk/testutil/x/counter/keeper"
 	"github.com/cosmos/cosmos-sdk/testutil/x/counter/types"
@@ -13,6 +15,8 @@ import (
 var (
 	_ appmodule.AppModule             = AppModule{}
 	_ appmodule.HasRegisterInterfaces = AppModule{}
+
+	_ schema.HasModuleCodec = AppModule{}
 )
 
 // AppModule implements an application module
@@ -48,3 +52,36 @@ func (AppModule) Name() string { return types.ModuleName }
 func (AppModule) RegisterInterfaces(registrar registry.InterfaceRegistrar) {
 	types.RegisterInterfaces(registrar)
 }
+
+// ModuleCodec implements schema.HasModuleCodec. The count is exported as the single object of the count type.
+func (am AppModule) ModuleCodec() (schema.ModuleCodec, error) {
+	return am.keeper.Schema.ModuleCodec(collections.IndexingOptions{})
+}
+
+// UpgradeSchema returns the schema which the module will have after the upgrade planName. In the upgrade
+// types.CountUpgradeName the value of the count becomes an uint64, so that binaries which don't handle that
+// upgrade yet can check ahead of its height whether the attached indexers can migrate the count. The schema
+// doesn't change in any other upgrade.
+func (am AppModule) UpgradeSchema(planName string) (schema.ModuleSchema, bool, error) {
+	if planName != types.CountUpgradeName {
+		return schema.ModuleSchema{}, false, nil
+	}
+
+	cdc, err := am.ModuleCodec()
+	if err != nil {
+		return schema.ModuleSchema{}, false, err
+	}
+
+	var objectTypes []schema.ObjectType
+	for _, objectType := range cdc.Schema.ObjectTypesSorted() {
+		if objectType.Name == "count" {
+			objectType.ValueFields = []schema.Field{{Name: "value", Kind: schema.Uint64Kind}}
+		}
+		objectTypes = append(objectTypes, objectType)
+	}
+	next, err := schema.NewModuleSchema(objectTypes)
+	if err != nil {
+		return schema.ModuleSchema{}, false, err
+	}
+	return next.WithVersion(cdc.Schema.Version() + 1), true, nil
+}
@@ -7,3 +7,7 @@ const (
 	// StoreKey defines the module's store key.
 	StoreKey = ModuleName
 )
+
+// CountUpgradeName is the name of the upgrade plan in which the count becomes unsigned. It matches the upgrade
+// following the one of SimApp, so that SimApp checks the new schema of the module ahead of that upgrade.
+const CountUpgradeName = "v051-to-v052"
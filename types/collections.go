@@ -8,6 +8,7 @@ import (
 	"cosmossdk.io/collections"
 	collcodec "cosmossdk.io/collections/codec"
 	"cosmossdk.io/math"
+	"cosmossdk.io/schema"
 )
 
 var (
@@ -115,6 +116,23 @@ func (a genericAddressKey[T]) SizeNonTerminal(key T) int {
 	return collections.BytesKey.SizeNonTerminal(key)
 }
 
+// SchemaCodec implements the collcodec.HasSchemaCodec interface, addresses are exported as a schema.AddressKind field.
+func (a genericAddressKey[T]) SchemaCodec() (collcodec.SchemaCodec[T], error) {
+	return collcodec.SchemaCodec[T]{
+		Fields: []schema.Field{{Kind: schema.AddressKind}},
+		ToSchemaType: func(key T) (interface{}, error) {
+			return []byte(key), nil
+		},
+		FromSchemaType: func(value interface{}) (T, error) {
+			bz, ok := value.([]byte)
+			if !ok {
+				return nil, fmt.Errorf("%w: expected []byte, got %T", collcodec.ErrEncoding, value)
+			}
+			return T(bz), nil
+		},
+	}, nil
+}
+
 // Deprecated: lengthPrefixedAddressKey is a special key codec used to retain state backwards compatibility
 // when a generic address key (be: AccAddress, ValAddress, ConsAddress), is used as an index key.
 // More docs can be found in the LengthPrefixedAddressKey function.
@@ -134,6 +152,11 @@ func (g lengthPrefixedAddressKey[T]) Size(key T) int { return g.SizeNonTerminal(
 
 func (g lengthPrefixedAddressKey[T]) KeyType() string { return "index_key/" + g.KeyCodec.KeyType() }
 
+// SchemaCodec implements the collcodec.HasSchemaCodec interface by describing keys like the wrapped key codec.
+func (g lengthPrefixedAddressKey[T]) SchemaCodec() (collcodec.SchemaCodec[T], error) {
+	return collcodec.KeySchemaCodec(g.KeyCodec)
+}
+
 // Deprecated: LengthPrefixedAddressKey implements an SDK backwards compatible indexing key encoder
 // for addresses.
 // The status quo in the SDK is that address keys are length prefixed even when they're the
@@ -171,6 +194,11 @@ func (g lengthPrefixedBytesKey) KeyType() string {
 	return "index_key/" + g.KeyCodec.KeyType()
 }
 
+// SchemaCodec implements the collcodec.HasSchemaCodec interface by describing keys like the wrapped key codec.
+func (g lengthPrefixedBytesKey) SchemaCodec() (collcodec.SchemaCodec[[]byte], error) {
+	return collcodec.KeySchemaCodec(g.KeyCodec)
+}
+
 // Collection Codecs
 
 type intValueCodec struct{}
@@ -209,6 +237,28 @@ func (i intValueCodec) ValueType() string {
 	return Int
 }
 
+// SchemaCodec implements the collcodec.HasSchemaCodec interface, integers are exported as a
+// schema.IntegerStringKind field.
+func (i intValueCodec) SchemaCodec() (collcodec.SchemaCodec[math.Int], error) {
+	return collcodec.SchemaCodec[math.Int]{
+		Fields: []schema.Field{{Kind: schema.IntegerStringKind}},
+		ToSchemaType: func(value math.Int) (interface{}, error) {
+			return value.String(), nil
+		},
+		FromSchemaType: func(value interface{}) (math.Int, error) {
+			s, ok := value.(string)
+			if !ok {
+				return math.Int{}, fmt.Errorf("%w: expected string, got %T", collcodec.ErrEncoding, value)
+			}
+			v, ok := math.NewIntFromString(s)
+			if !ok {
+				return math.Int{}, fmt.Errorf("%w: invalid integer %q", collcodec.ErrEncoding, s)
+			}
+			return v, nil
+		},
+	}, nil
+}
+
 type uintValueCodec struct{}
 
 func (i uintValueCodec) Encode(value math.Uint) ([]byte, error) {
@@ -245,6 +295,28 @@ func (i uintValueCodec) ValueType() string {
 	return Uint
 }
 
+// SchemaCodec implements the collcodec.HasSchemaCodec interface, integers are exported as a
+// schema.IntegerStringKind field.
+func (i uintValueCodec) SchemaCodec() (collcodec.SchemaCodec[math.Uint], error) {
+	return collcodec.SchemaCodec[math.Uint]{
+		Fields: []schema.Field{{Kind: schema.IntegerStringKind}},
+		ToSchemaType: func(value math.Uint) (interface{}, error) {
+			return value.String(), nil
+		},
+		FromSchemaType: func(value interface{}) (math.Uint, error) {
+			s, ok := value.(string)
+			if !ok {
+				return math.Uint{}, fmt.Errorf("%w: expected string, got %T", collcodec.ErrEncoding, value)
+			}
+			v, err := math.ParseUint(s)
+			if err != nil {
+				return math.Uint{}, fmt.Errorf("%w: %w", collcodec.ErrEncoding, err)
+			}
+			return v, nil
+		},
+	}, nil
+}
+
 type timeKeyCodec struct{}
 
 func (timeKeyCodec) Encode(buffer []byte, key time.Time) (int, error) {
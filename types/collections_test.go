@@ -7,7 +7,10 @@ import (
 	"github.com/stretchr/testify/require"
 	"pgregory.net/rapid"
 
+	collcodec "cosmossdk.io/collections/codec"
 	"cosmossdk.io/collections/colltest"
+	"cosmossdk.io/math"
+	"cosmossdk.io/schema"
 )
 
 func TestCollectionsCorrectness(t *testing.T) {
@@ -46,3 +49,33 @@ func TestLEUint64Key(t *testing.T) {
 		require.ErrorContains(t, err, "invalid buffer size")
 	})
 }
+
+func TestCollectionsSchemaCodecs(t *testing.T) {
+	addrCodec, err := collcodec.KeySchemaCodec(LengthPrefixedAddressKey(AccAddressKey))
+	require.NoError(t, err)
+	require.Equal(t, schema.AddressKind, addrCodec.Fields[0].Kind)
+	value, err := addrCodec.ToSchemaType(AccAddress{0x1, 0x2})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1, 0x2}, value)
+	addr, err := addrCodec.FromSchemaType(value)
+	require.NoError(t, err)
+	require.Equal(t, AccAddress{0x1, 0x2}, addr)
+
+	intCodec, err := collcodec.ValueSchemaCodec(IntValue)
+	require.NoError(t, err)
+	require.Equal(t, schema.IntegerStringKind, intCodec.Fields[0].Kind)
+	value, err = intCodec.ToSchemaType(math.NewInt(-100))
+	require.NoError(t, err)
+	require.Equal(t, "-100", value)
+	i, err := intCodec.FromSchemaType(value)
+	require.NoError(t, err)
+	require.Equal(t, math.NewInt(-100), i)
+	_, err = intCodec.FromSchemaType("1.5")
+	require.ErrorIs(t, err, collcodec.ErrEncoding)
+
+	uintCodec, err := collcodec.ValueSchemaCodec(UintValue)
+	require.NoError(t, err)
+	u, err := uintCodec.FromSchemaType("100")
+	require.NoError(t, err)
+	require.Equal(t, math.NewUint(100), u)
+}
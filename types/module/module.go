@@ -37,6 +37,9 @@ import (
 	"cosmossdk.io/core/legacy"
 	"cosmossdk.io/core/registry"
 	errorsmod "cosmossdk.io/errors"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/decoding"
 	storetypes "cosmossdk.io/store/types"
 
 	"github.com/cosmos/cosmos-sdk/client"
@@ -552,6 +555,124 @@ func (m *Manager) ExportGenesisForModules(ctx sdk.Context, modulesToExport []str
 	return genesisData, nil
 }
 
+// ExportObjectUpdates exports the state of the modules in modulesToExport, or of all modules if it is empty,
+// as a stream of app data packets so that indexers can bootstrap from an exported snapshot. For each module
+// which implements schema.HasModuleCodec, the listener receives an InitializeModuleData packet with the
+// module's schema followed by ObjectUpdate packets decoded from the module's store in ctx. Stores are
+// looked up in keys by module name and modules without a store are only initialized.
+func (m *Manager) ExportObjectUpdates(ctx sdk.Context, keys map[string]*storetypes.KVStoreKey, listener appdata.Listener, modulesToExport []string) error {
+	if err := m.checkModulesExists(modulesToExport); err != nil {
+		return err
+	}
+
+	export := map[string]bool{}
+	for _, moduleName := range modulesToExport {
+		export[moduleName] = true
+	}
+
+	moduleSet := make(map[string]interface{}, len(m.Modules))
+	for moduleName, mod := range m.Modules {
+		moduleSet[moduleName] = mod
+	}
+
+	return decoding.Sync(listener, storeSyncSource{ctx: ctx, keys: keys}, decoding.ModuleSetDecoderResolver(moduleSet), decoding.SyncOptions{
+		ModuleFilter: func(moduleName string) bool {
+			return len(export) == 0 || export[moduleName]
+		},
+	})
+}
+
+// ImportObjectUpdates returns a listener which initializes the state of modules from a stream of app data
+// packets, such as the one produced by ExportObjectUpdates, so that state can be imported through the
+// modules' logical schema rather than raw key-value pairs. For each InitializeModuleData packet, the module
+// must implement schema.HasModuleCodec with a KVEncoder and its schema must be compatible with the schema
+// in the packet. ObjectUpdate packets are validated, encoded and written to the module's store in ctx,
+// which is looked up in keys by module name.
+func (m *Manager) ImportObjectUpdates(ctx sdk.Context, keys map[string]*storetypes.KVStoreKey) appdata.Listener {
+	codecs := map[string]schema.ModuleCodec{}
+	return appdata.Listener{
+		InitializeModuleData: func(data appdata.ModuleInitializationData) error {
+			mod, ok := m.Modules[data.ModuleName]
+			if !ok {
+				return fmt.Errorf("module %s does not exist", data.ModuleName)
+			}
+			if _, ok := keys[data.ModuleName]; !ok {
+				return fmt.Errorf("module %s does not have a store", data.ModuleName)
+			}
+
+			hasCodec, ok := mod.(schema.HasModuleCodec)
+			if !ok {
+				return fmt.Errorf("module %s does not have a module codec", data.ModuleName)
+			}
+			cdc, err := hasCodec.ModuleCodec()
+			if err != nil {
+				return fmt.Errorf("failed to get module codec for module %s: %w", data.ModuleName, err)
+			}
+			if cdc.KVEncoder == nil {
+				return fmt.Errorf("module %s does not have a kv encoder", data.ModuleName)
+			}
+			if err := cdc.Schema.CompatibleWith(data.Schema); err != nil {
+				return fmt.Errorf("schema of module %s is not compatible with the imported schema: %w", data.ModuleName, err)
+			}
+
+			codecs[data.ModuleName] = cdc
+			return nil
+		},
+		OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+			cdc, ok := codecs[data.ModuleName]
+			if !ok {
+				return fmt.Errorf("module %s was not initialized", data.ModuleName)
+			}
+
+			store := ctx.KVStore(keys[data.ModuleName])
+			for _, update := range data.Updates {
+				if err := cdc.Schema.ValidateObjectUpdate(update); err != nil {
+					return fmt.Errorf("invalid object update for module %s: %w", data.ModuleName, err)
+				}
+
+				pairs, err := cdc.KVEncoder(update)
+				if err != nil {
+					return fmt.Errorf("failed to encode object update for module %s: %w", data.ModuleName, err)
+				}
+				for _, pair := range pairs {
+					if pair.Delete {
+						store.Delete(pair.Key)
+					} else {
+						store.Set(pair.Key, pair.Value)
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// storeSyncSource iterates over the key-value pairs of module stores in a context.
+type storeSyncSource struct {
+	ctx  sdk.Context
+	keys map[string]*storetypes.KVStoreKey
+}
+
+func (s storeSyncSource) IterateAllKVPairs(moduleName string, fn func(key, value []byte) error) error {
+	return s.IterateKVPairsFrom(moduleName, nil, fn)
+}
+
+func (s storeSyncSource) IterateKVPairsFrom(moduleName string, startKey []byte, fn func(key, value []byte) error) error {
+	key, ok := s.keys[moduleName]
+	if !ok {
+		return nil
+	}
+
+	it := s.ctx.KVStore(key).Iterator(startKey, nil)
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		if err := fn(it.Key(), it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
 // checkModulesExists verifies that all modules in the list exist in the app
 func (m *Manager) checkModulesExists(moduleName []string) error {
 	for _, name := range moduleName {
@@ -15,10 +15,14 @@ import (
 
 	"cosmossdk.io/core/appmodule"
 	coretesting "cosmossdk.io/core/testing"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	storetypes "cosmossdk.io/store/types"
 	authtypes "cosmossdk.io/x/auth/types"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
 	"github.com/cosmos/cosmos-sdk/testutil/mock"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/module"
@@ -519,6 +523,132 @@ func TestManager_Precommit(t *testing.T) {
 	require.EqualError(t, err, "some error")
 }
 
+// schemaAppModule stores balances as denom -> amount and decodes them with its module codec.
+type schemaAppModule struct{}
+
+func (schemaAppModule) IsOnePerModuleType() {}
+func (schemaAppModule) IsAppModule()        {}
+
+func (schemaAppModule) ModuleCodec() (schema.ModuleCodec, error) {
+	moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:        "balances",
+		KeyFields:   []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+		ValueFields: []schema.Field{{Name: "amount", Kind: schema.StringKind}},
+	}})
+	return schema.ModuleCodec{
+		Schema: moduleSchema,
+		KVDecoder: func(update schema.KVPairUpdate) ([]schema.ObjectUpdate, error) {
+			return []schema.ObjectUpdate{{TypeName: "balances", Key: string(update.Key), Value: string(update.Value)}}, nil
+		},
+		KVEncoder: func(update schema.ObjectUpdate) ([]schema.KVPairUpdate, error) {
+			if update.Delete {
+				return []schema.KVPairUpdate{{Key: []byte(update.Key.(string)), Delete: true}}, nil
+			}
+			return []schema.KVPairUpdate{{Key: []byte(update.Key.(string)), Value: []byte(update.Value.(string))}}, nil
+		},
+	}, err
+}
+
+func TestManager_ExportObjectUpdates(t *testing.T) {
+	key := storetypes.NewKVStoreKey("bank")
+	ctx := testutil.DefaultContext(key, storetypes.NewTransientStoreKey("transient_test"))
+	ctx.KVStore(key).Set([]byte("atom"), []byte("10"))
+	ctx.KVStore(key).Set([]byte("osmo"), []byte("5"))
+
+	mm := module.NewManagerFromMap(map[string]appmodule.AppModule{
+		"bank": schemaAppModule{},
+		"auth": MockCoreAppModule{},
+	})
+
+	var modules []string
+	var updates []schema.ObjectUpdate
+	listener := appdata.Listener{
+		InitializeModuleData: func(data appdata.ModuleInitializationData) error {
+			modules = append(modules, data.ModuleName)
+			return nil
+		},
+		OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+			require.Equal(t, "bank", data.ModuleName)
+			updates = append(updates, data.Updates...)
+			return nil
+		},
+	}
+
+	err := mm.ExportObjectUpdates(ctx, map[string]*storetypes.KVStoreKey{"bank": key}, listener, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"bank"}, modules)
+	require.Equal(t, []schema.ObjectUpdate{
+		{TypeName: "balances", Key: "atom", Value: "10"},
+		{TypeName: "balances", Key: "osmo", Value: "5"},
+	}, updates)
+
+	modules, updates = nil, nil
+	err = mm.ExportObjectUpdates(ctx, map[string]*storetypes.KVStoreKey{"bank": key}, listener, []string{"auth"})
+	require.NoError(t, err)
+	require.Empty(t, modules)
+	require.Empty(t, updates)
+
+	err = mm.ExportObjectUpdates(ctx, nil, listener, []string{"staking"})
+	require.EqualError(t, err, "module staking does not exist")
+}
+
+func TestManager_ImportObjectUpdates(t *testing.T) {
+	exportKey := storetypes.NewKVStoreKey("bank")
+	exportCtx := testutil.DefaultContext(exportKey, storetypes.NewTransientStoreKey("transient_test"))
+	exportCtx.KVStore(exportKey).Set([]byte("atom"), []byte("10"))
+	exportCtx.KVStore(exportKey).Set([]byte("osmo"), []byte("5"))
+
+	mm := module.NewManagerFromMap(map[string]appmodule.AppModule{
+		"bank": schemaAppModule{},
+		"auth": MockCoreAppModule{},
+	})
+
+	key := storetypes.NewKVStoreKey("bank")
+	ctx := testutil.DefaultContext(key, storetypes.NewTransientStoreKey("transient_test"))
+	keys := map[string]*storetypes.KVStoreKey{"bank": key}
+	listener := mm.ImportObjectUpdates(ctx, keys)
+
+	err := mm.ExportObjectUpdates(exportCtx, map[string]*storetypes.KVStoreKey{"bank": exportKey}, listener, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("10"), ctx.KVStore(key).Get([]byte("atom")))
+	require.Equal(t, []byte("5"), ctx.KVStore(key).Get([]byte("osmo")))
+
+	err = listener.OnObjectUpdate(appdata.ObjectUpdateData{
+		ModuleName: "bank",
+		Updates:    []schema.ObjectUpdate{{TypeName: "balances", Key: "atom", Delete: true}},
+	})
+	require.NoError(t, err)
+	require.False(t, ctx.KVStore(key).Has([]byte("atom")))
+
+	err = listener.OnObjectUpdate(appdata.ObjectUpdateData{
+		ModuleName: "bank",
+		Updates:    []schema.ObjectUpdate{{TypeName: "balances", Key: "atom", Value: uint64(10)}},
+	})
+	require.ErrorContains(t, err, "invalid object update for module bank")
+
+	err = listener.OnObjectUpdate(appdata.ObjectUpdateData{ModuleName: "auth"})
+	require.EqualError(t, err, "module auth was not initialized")
+
+	err = listener.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "auth"})
+	require.EqualError(t, err, "module auth does not have a store")
+
+	keys["auth"] = storetypes.NewKVStoreKey("auth")
+	err = listener.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "auth"})
+	require.EqualError(t, err, "module auth does not have a module codec")
+
+	err = listener.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "staking"})
+	require.EqualError(t, err, "module staking does not exist")
+
+	incompatible, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:        "balances",
+		KeyFields:   []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+		ValueFields: []schema.Field{{Name: "amount", Kind: schema.Uint64Kind}},
+	}})
+	require.NoError(t, err)
+	err = listener.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "bank", Schema: incompatible})
+	require.ErrorContains(t, err, "schema of module bank is not compatible with the imported schema")
+}
+
 // MockCoreAppModule allows us to test functions like DefaultGenesis
 type MockCoreAppModule struct{}
 
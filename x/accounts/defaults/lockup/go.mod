@@ -159,6 +159,7 @@ replace (
 	cosmossdk.io/core => ../../../../core
 	cosmossdk.io/core/testing => ../../../../core/testing
 	cosmossdk.io/log => ../../../../log
+	cosmossdk.io/schema => ../../../../schema
 	cosmossdk.io/x/accounts => ../../.
 	cosmossdk.io/x/auth => ../../../auth
 	cosmossdk.io/x/bank => ../../../bank
@@ -0,0 +1,213 @@
+package keeper
+
+import (
+	"fmt"
+
+	collcodec "cosmossdk.io/collections/codec"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/x/auth/types"
+	vestingtypes "cosmossdk.io/x/auth/vesting/types"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/schemavalue"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// The codec in this file describes accounts with structured schema fields, so that indexers can query
+// the address, public key, number and sequence of every account, the name and permissions of module
+// accounts and the schedule of vesting accounts.
+
+// Vesting kinds are the values of VestingKindEnum, one for each vesting account type.
+const (
+	VestingKindContinuous      = "continuous"
+	VestingKindDelayed         = "delayed"
+	VestingKindPeriodic        = "periodic"
+	VestingKindPermanentLocked = "permanent_locked"
+)
+
+// VestingKindEnum is the schema enum type of the kind of the vesting schedule of an account.
+var VestingKindEnum = schema.EnumType{
+	Name:   "vesting_kind",
+	Values: []string{VestingKindContinuous, VestingKindDelayed, VestingKindPeriodic, VestingKindPermanentLocked},
+}
+
+var (
+	periodStruct = schema.StructType{
+		Name: "vesting_period",
+		Fields: []schema.Field{
+			{Name: "length", Kind: schema.Int64Kind},
+			{Name: "amount", Kind: schema.ListKind, ElementKind: schema.StructKind, StructType: schemavalue.CoinStruct},
+		},
+	}
+
+	// vestingScheduleStruct holds the fields of all vesting account types, the start time and periods
+	// are only set for the kinds which have them.
+	vestingScheduleStruct = schema.StructType{
+		Name: "vesting_schedule",
+		Fields: []schema.Field{
+			{Name: "kind", Kind: schema.EnumKind, EnumType: VestingKindEnum},
+			{Name: "original_vesting", Kind: schema.ListKind, ElementKind: schema.StructKind, StructType: schemavalue.CoinStruct},
+			{Name: "delegated_free", Kind: schema.ListKind, ElementKind: schema.StructKind, StructType: schemavalue.CoinStruct},
+			{Name: "delegated_vesting", Kind: schema.ListKind, ElementKind: schema.StructKind, StructType: schemavalue.CoinStruct},
+			{Name: "start_time", Kind: schema.Int64Kind},
+			{Name: "end_time", Kind: schema.Int64Kind},
+			{Name: "periods", Kind: schema.ListKind, ElementKind: schema.StructKind, StructType: periodStruct},
+		},
+	}
+)
+
+// accountValue describes the accounts of every type with the same fields. The type_url field tells
+// the type of the account, module_name and permissions are only set for module accounts and
+// vesting only for vesting accounts. Accounts of other types, such as those registered by
+// applications, keep their number and sequence and are otherwise exported as encoded.
+func accountValue(cdc codec.BinaryCodec) collcodec.ValueCodec[sdk.AccountI] {
+	valueCodec := codec.CollInterfaceValue[sdk.AccountI](cdc)
+	return collcodec.ValueCodecWithSchema(valueCodec, collcodec.SchemaCodec[sdk.AccountI]{
+		Fields: []schema.Field{
+			{Name: "type_url", Kind: schema.StringKind},
+			{Name: "address", Kind: schema.StringKind},
+			{Name: "pub_key", Kind: schema.StructKind, StructType: schemavalue.AnyStruct, Nullable: true},
+			{Name: "account_number", Kind: schema.Uint64Kind},
+			{Name: "sequence", Kind: schema.Uint64Kind},
+			{Name: "module_name", Kind: schema.StringKind, Nullable: true},
+			{Name: "permissions", Kind: schema.ListKind, ElementKind: schema.StringKind},
+			{Name: "vesting", Kind: schema.StructKind, StructType: vestingScheduleStruct, Nullable: true},
+			{Name: "encoded", Kind: schema.StructKind, StructType: schemavalue.AnyStruct, Nullable: true},
+		},
+		ToSchemaType: func(acc sdk.AccountI) (interface{}, error) {
+			var (
+				base        *types.BaseAccount
+				moduleName  interface{}
+				permissions = []interface{}{}
+				vesting     interface{}
+				encoded     interface{}
+			)
+			switch acc := acc.(type) {
+			case *types.BaseAccount:
+				base = acc
+			case *types.ModuleAccount:
+				base, moduleName = acc.BaseAccount, acc.Name
+				for _, p := range acc.Permissions {
+					permissions = append(permissions, p)
+				}
+			case *vestingtypes.ContinuousVestingAccount:
+				base, vesting = acc.BaseAccount, vestingSchedule(VestingKindContinuous, acc.BaseVestingAccount, acc.StartTime, nil)
+			case *vestingtypes.DelayedVestingAccount:
+				base, vesting = acc.BaseAccount, vestingSchedule(VestingKindDelayed, acc.BaseVestingAccount, 0, nil)
+			case *vestingtypes.PeriodicVestingAccount:
+				base, vesting = acc.BaseAccount, vestingSchedule(VestingKindPeriodic, acc.BaseVestingAccount, acc.StartTime, acc.VestingPeriods)
+			case *vestingtypes.PermanentLockedAccount:
+				base, vesting = acc.BaseAccount, vestingSchedule(VestingKindPermanentLocked, acc.BaseVestingAccount, 0, nil)
+			default:
+				a, err := codectypes.NewAnyWithValue(acc)
+				if err != nil {
+					return nil, err
+				}
+				encoded = schemavalue.AnyValue(a)
+				base = &types.BaseAccount{AccountNumber: acc.GetAccountNumber(), Sequence: acc.GetSequence()}
+			}
+			if base == nil {
+				base = &types.BaseAccount{}
+			}
+			return []interface{}{
+				sdk.MsgTypeURL(acc),
+				base.Address,
+				schemavalue.AnyValue(base.PubKey),
+				base.AccountNumber,
+				base.Sequence,
+				moduleName,
+				permissions,
+				vesting,
+				encoded,
+			}, nil
+		},
+		FromSchemaType: func(value interface{}) (sdk.AccountI, error) {
+			r, err := schemavalue.NewReader(value, 9)
+			if err != nil {
+				return nil, err
+			}
+			typeURL := r.ReadString()
+			base := &types.BaseAccount{Address: r.ReadString(), PubKey: r.ReadAny(), AccountNumber: r.ReadUint64(), Sequence: r.ReadUint64()}
+			name := r.Next()
+			moduleName, ok := name.(string)
+			if name != nil && !ok {
+				return nil, fmt.Errorf("%w: expected string module name, got %T", collcodec.ErrEncoding, name)
+			}
+			var permissions []string
+			for l := r.ReadList(); l.More(); {
+				permissions = append(permissions, l.ReadString())
+			}
+			var (
+				kind        string
+				baseVesting *vestingtypes.BaseVestingAccount
+				startTime   int64
+				periods     []vestingtypes.Period
+			)
+			if v := r.ReadNullableStruct(len(vestingScheduleStruct.Fields)); v != nil {
+				kind = VestingKindEnum.Values[v.ReadEnum(VestingKindEnum)]
+				baseVesting = &vestingtypes.BaseVestingAccount{BaseAccount: base, OriginalVesting: v.ReadCoins(), DelegatedFree: v.ReadCoins(), DelegatedVesting: v.ReadCoins()}
+				startTime = v.ReadInt64()
+				baseVesting.EndTime = v.ReadInt64()
+				for l := v.ReadList(); l.More(); {
+					p := l.ReadStruct(len(periodStruct.Fields))
+					periods = append(periods, vestingtypes.Period{Length: p.ReadInt64(), Amount: p.ReadCoins()})
+				}
+			}
+			encoded := r.ReadAny()
+			if err := r.Err(); err != nil {
+				return nil, err
+			}
+
+			if encoded == nil {
+				var acc sdk.AccountI
+				switch {
+				case baseVesting != nil && kind == VestingKindContinuous:
+					acc = &vestingtypes.ContinuousVestingAccount{BaseVestingAccount: baseVesting, StartTime: startTime}
+				case baseVesting != nil && kind == VestingKindDelayed:
+					acc = &vestingtypes.DelayedVestingAccount{BaseVestingAccount: baseVesting}
+				case baseVesting != nil && kind == VestingKindPeriodic:
+					acc = &vestingtypes.PeriodicVestingAccount{BaseVestingAccount: baseVesting, StartTime: startTime, VestingPeriods: periods}
+				case baseVesting != nil:
+					acc = &vestingtypes.PermanentLockedAccount{BaseVestingAccount: baseVesting}
+				case typeURL == sdk.MsgTypeURL(&types.ModuleAccount{}):
+					acc = &types.ModuleAccount{BaseAccount: base, Name: moduleName, Permissions: permissions}
+				default:
+					acc = base
+				}
+				if sdk.MsgTypeURL(acc) != typeURL {
+					return nil, fmt.Errorf("%w: account of type %s has fields of %s", collcodec.ErrEncoding, typeURL, sdk.MsgTypeURL(acc))
+				}
+				if encoded, err = codectypes.NewAnyWithValue(acc); err != nil {
+					return nil, err
+				}
+			}
+			// decode the account from its encoding, so that its public key is unpacked like the accounts
+			// read from state
+			bz, err := encoded.Marshal()
+			if err != nil {
+				return nil, err
+			}
+			return valueCodec.Decode(bz)
+		},
+	})
+}
+
+func vestingSchedule(kind string, acc *vestingtypes.BaseVestingAccount, startTime int64, periods []vestingtypes.Period) []interface{} {
+	if acc == nil {
+		acc = &vestingtypes.BaseVestingAccount{}
+	}
+	ps := make([]interface{}, len(periods))
+	for i, p := range periods {
+		ps[i] = []interface{}{p.Length, schemavalue.CoinsValue(p.Amount)}
+	}
+	return []interface{}{
+		kind,
+		schemavalue.CoinsValue(acc.OriginalVesting),
+		schemavalue.CoinsValue(acc.DelegatedFree),
+		schemavalue.CoinsValue(acc.DelegatedVesting),
+		startTime,
+		acc.EndTime,
+		ps,
+	}
+}
@@ -0,0 +1,81 @@
+package keeper_test
+
+import (
+	"cosmossdk.io/collections"
+	collcodec "cosmossdk.io/collections/codec"
+	"cosmossdk.io/x/auth/keeper"
+	"cosmossdk.io/x/auth/types"
+	vestingtypes "cosmossdk.io/x/auth/vesting/types"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func (suite *KeeperTestSuite) TestModuleCodec() {
+	require := suite.Require()
+	ctx, ak := suite.ctx, suite.accountKeeper
+	vestingtypes.RegisterInterfaces(suite.encCfg.InterfaceRegistry)
+
+	coins := sdk.NewCoins(sdk.NewInt64Coin("stake", 100))
+	newBase := func(name string) *types.BaseAccount {
+		return ak.NewAccountWithAddress(ctx, sdk.AccAddress(name)).(*types.BaseAccount)
+	}
+
+	base := newBase("base")
+	require.NoError(base.SetPubKey(ed25519.GenPrivKey().PubKey()))
+	require.NoError(base.SetSequence(5))
+	continuous, err := vestingtypes.NewContinuousVestingAccount(newBase("continuous"), coins, 10, 20)
+	require.NoError(err)
+	delayed, err := vestingtypes.NewDelayedVestingAccount(newBase("delayed"), coins, 20)
+	require.NoError(err)
+	periodic, err := vestingtypes.NewPeriodicVestingAccount(newBase("periodic"), coins, 10, vestingtypes.Periods{{Length: 10, Amount: coins}})
+	require.NoError(err)
+	locked, err := vestingtypes.NewPermanentLockedAccount(newBase("locked"), coins)
+	require.NoError(err)
+	for _, acc := range []sdk.AccountI{base, continuous, delayed, periodic, locked} {
+		ak.SetAccount(ctx, acc)
+	}
+	ak.GetModuleAccount(ctx, multiPerm)
+
+	cdc, err := ak.Schema.ModuleCodec(collections.IndexingOptions{})
+	require.NoError(err)
+
+	// every kv-pair of the module decodes to valid object updates which encode back to the same kv-pair
+	accounts := map[string][]interface{}{}
+	for _, update := range testutil.DecodeModuleState(suite.T(), cdc, ak.KVStoreService.OpenKVStore(ctx))["accounts"] {
+		accounts[string(update.Key.([]byte))] = update.Value.([]interface{})
+	}
+	require.Len(accounts, 6)
+
+	value := accounts["base"]
+	require.Equal(sdk.MsgTypeURL(base), value[0])
+	require.Equal(base.Address, value[1])
+	require.NotNil(value[2])
+	require.Equal(uint64(5), value[4])
+	require.Nil(value[7])
+
+	value = accounts[string(types.NewModuleAddress(multiPerm))]
+	require.Equal(multiPerm, value[5])
+	require.Equal([]interface{}{"burner", "minter", "staking"}, value[6])
+
+	for name, kind := range map[string]string{
+		"continuous": keeper.VestingKindContinuous,
+		"delayed":    keeper.VestingKindDelayed,
+		"periodic":   keeper.VestingKindPeriodic,
+		"locked":     keeper.VestingKindPermanentLocked,
+	} {
+		vesting := accounts[name][7].([]interface{})
+		require.Equal(kind, vesting[0], name)
+		require.Equal([]interface{}{[]interface{}{"stake", "100"}}, vesting[1], name)
+	}
+	periods := accounts["periodic"][7].([]interface{})[6]
+	require.Equal([]interface{}{[]interface{}{int64(10), []interface{}{[]interface{}{"stake", "100"}}}}, periods)
+
+	// decoded accounts have their public key unpacked like the accounts read from state
+	schemaCodec, err := collcodec.ValueSchemaCodec(ak.Accounts.ValueCodec())
+	require.NoError(err)
+	decoded, err := schemaCodec.FromSchemaType(accounts["base"])
+	require.NoError(err)
+	require.Equal(base.GetPubKey(), decoded.GetPubKey())
+}
@@ -8,11 +8,13 @@ import (
 	gwruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"google.golang.org/grpc"
 
+	"cosmossdk.io/collections"
 	"cosmossdk.io/core/appmodule"
 	appmodulev2 "cosmossdk.io/core/appmodule/v2"
 	"cosmossdk.io/core/legacy"
 	"cosmossdk.io/core/registry"
 	"cosmossdk.io/core/transaction"
+	"cosmossdk.io/schema"
 	"cosmossdk.io/x/auth/ante"
 	"cosmossdk.io/x/auth/keeper"
 	"cosmossdk.io/x/auth/simulation"
@@ -38,6 +40,8 @@ var (
 	_ appmodulev2.AppModule     = AppModule{}
 	_ appmodule.HasServices     = AppModule{}
 	_ appmodulev2.HasMigrations = AppModule{}
+
+	_ schema.HasModuleCodec = AppModule{}
 )
 
 // AppModule implements an application module for the auth module.
@@ -117,6 +121,12 @@ func (am AppModule) RegisterMigrations(mr appmodule.MigrationRegistrar) error {
 	return nil
 }
 
+// ModuleCodec implements schema.HasModuleCodec. Accounts of every type are exported with the same
+// structured fields, with the schedule of vesting accounts as a struct of the vesting_kind enum.
+func (am AppModule) ModuleCodec() (schema.ModuleCodec, error) {
+	return am.accountKeeper.Schema.ModuleCodec(collections.IndexingOptions{})
+}
+
 // DefaultGenesis returns default genesis state as raw bytes for the auth module.
 func (am AppModule) DefaultGenesis() json.RawMessage {
 	return am.cdc.MustMarshalJSON(types.DefaultGenesisState())
@@ -0,0 +1,193 @@
+package keeper
+
+import (
+	"bytes"
+	"fmt"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/x/authz"
+
+	"github.com/cosmos/cosmos-sdk/codec/schemavalue"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Object types of the module codec of authz.
+const (
+	GrantsObjectType     = "grants"
+	GrantQueueObjectType = "grant_queue"
+)
+
+// ModuleCodec returns the schema.ModuleCodec of the module. As the module doesn't store its state
+// with collections, its key-value pairs are decoded and encoded by hand here:
+//
+//   - grants are keyed by granter, grantee and msg type URL, and have the authorization as an any
+//     struct and the optional expiration,
+//   - grant_queue entries are keyed by expiration, granter and grantee, and have the msg type URLs
+//     of the grants which expire then.
+func (k Keeper) ModuleCodec() (schema.ModuleCodec, error) {
+	moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{
+		{
+			Name: GrantsObjectType,
+			KeyFields: []schema.Field{
+				{Name: "granter", Kind: schema.AddressKind},
+				{Name: "grantee", Kind: schema.AddressKind},
+				{Name: "msg_type_url", Kind: schema.StringKind},
+			},
+			ValueFields: []schema.Field{
+				{Name: "authorization", Kind: schema.StructKind, StructType: schemavalue.AnyStruct, Nullable: true},
+				{Name: "expiration", Kind: schema.TimeKind, Nullable: true},
+			},
+		},
+		{
+			Name: GrantQueueObjectType,
+			KeyFields: []schema.Field{
+				{Name: "expiration", Kind: schema.TimeKind},
+				{Name: "granter", Kind: schema.AddressKind},
+				{Name: "grantee", Kind: schema.AddressKind},
+			},
+			ValueFields: []schema.Field{
+				{Name: "msg_type_urls", Kind: schema.ListKind, ElementKind: schema.StringKind},
+			},
+		},
+	})
+	if err != nil {
+		return schema.ModuleCodec{}, err
+	}
+	return schema.ModuleCodec{
+		Schema:    moduleSchema,
+		KVDecoder: k.decodeKV,
+		KVEncoder: k.encodeObjectUpdate,
+	}, nil
+}
+
+func (k Keeper) decodeKV(update schema.KVPairUpdate) ([]schema.ObjectUpdate, error) {
+	var (
+		typeName string
+		key      []interface{}
+		value    interface{}
+	)
+	switch {
+	case bytes.HasPrefix(update.Key, GrantKey):
+		addrs, rest, err := parseLengthPrefixedAddresses(update.Key[len(GrantKey):], 2)
+		if err != nil {
+			return nil, err
+		}
+		typeName, key = GrantsObjectType, []interface{}{addrs[0], addrs[1], string(rest)}
+		if !update.Delete {
+			var grant authz.Grant
+			if err := k.cdc.Unmarshal(update.Value, &grant); err != nil {
+				return nil, err
+			}
+			value = []interface{}{schemavalue.AnyValue(grant.Authorization), schemavalue.TimeValue(grant.Expiration)}
+		}
+	case bytes.HasPrefix(update.Key, GrantQueuePrefix):
+		rest := update.Key[len(GrantQueuePrefix):]
+		if len(rest) < lenTime {
+			return nil, fmt.Errorf("invalid grant queue key %X", update.Key)
+		}
+		exp, err := sdk.ParseTimeBytes(rest[:lenTime])
+		if err != nil {
+			return nil, err
+		}
+		addrs, rest, err := parseLengthPrefixedAddresses(rest[lenTime:], 2)
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) != 0 {
+			return nil, fmt.Errorf("invalid grant queue key %X", update.Key)
+		}
+		typeName, key = GrantQueueObjectType, []interface{}{exp, addrs[0], addrs[1]}
+		if !update.Delete {
+			var item authz.GrantQueueItem
+			if err := k.cdc.Unmarshal(update.Value, &item); err != nil {
+				return nil, err
+			}
+			urls := make([]interface{}, len(item.MsgTypeUrls))
+			for i, url := range item.MsgTypeUrls {
+				urls[i] = url
+			}
+			value = urls
+		}
+	default:
+		return nil, nil
+	}
+	return []schema.ObjectUpdate{{TypeName: typeName, Key: key, Value: value, Delete: update.Delete}}, nil
+}
+
+func (k Keeper) encodeObjectUpdate(update schema.ObjectUpdate) ([]schema.KVPairUpdate, error) {
+	var (
+		key   []byte
+		value []byte
+	)
+	switch update.TypeName {
+	case GrantsObjectType:
+		r, err := schemavalue.NewReader(update.Key, 3)
+		if err != nil {
+			return nil, err
+		}
+		granter, grantee, msgType := r.ReadBytes(), r.ReadBytes(), r.ReadString()
+		if err := r.Err(); err != nil {
+			return nil, err
+		}
+		key = grantStoreKey(grantee, granter, msgType)
+		if !update.Delete {
+			r, err := schemavalue.NewReader(update.Value, 2)
+			if err != nil {
+				return nil, err
+			}
+			grant := authz.Grant{Authorization: r.ReadAny(), Expiration: r.ReadNullableTime()}
+			if err := r.Err(); err != nil {
+				return nil, err
+			}
+			if value, err = k.cdc.Marshal(&grant); err != nil {
+				return nil, err
+			}
+		}
+	case GrantQueueObjectType:
+		r, err := schemavalue.NewReader(update.Key, 3)
+		if err != nil {
+			return nil, err
+		}
+		exp, granter, grantee := r.ReadTime(), r.ReadBytes(), r.ReadBytes()
+		if err := r.Err(); err != nil {
+			return nil, err
+		}
+		key = GrantQueueKey(exp, granter, grantee)
+		if !update.Delete {
+			urls, ok := update.Value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected a list of msg type URLs, got %T", update.Value)
+			}
+			r, err := schemavalue.NewReader(urls, len(urls))
+			if err != nil {
+				return nil, err
+			}
+			var item authz.GrantQueueItem
+			for r.More() {
+				item.MsgTypeUrls = append(item.MsgTypeUrls, r.ReadString())
+			}
+			if err := r.Err(); err != nil {
+				return nil, err
+			}
+			if value, err = k.cdc.Marshal(&item); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown object type %q", update.TypeName)
+	}
+	return []schema.KVPairUpdate{{Key: key, Value: value, Delete: update.Delete}}, nil
+}
+
+// parseLengthPrefixedAddresses parses n length prefixed addresses from the start of bz and returns
+// them with the remaining bytes.
+func parseLengthPrefixedAddresses(bz []byte, n int) ([][]byte, []byte, error) {
+	addrs := make([][]byte, n)
+	for i := range addrs {
+		if len(bz) == 0 || len(bz) < 1+int(bz[0]) {
+			return nil, nil, fmt.Errorf("invalid length prefixed address %X", bz)
+		}
+		addrs[i], bz = bz[1:1+int(bz[0])], bz[1+int(bz[0]):]
+	}
+	return addrs, bz, nil
+}
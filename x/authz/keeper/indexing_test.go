@@ -0,0 +1,50 @@
+package keeper_test
+
+import (
+	"cosmossdk.io/schema"
+	"cosmossdk.io/x/authz"
+	authzkeeper "cosmossdk.io/x/authz/keeper"
+	banktypes "cosmossdk.io/x/bank/types"
+
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func (s *TestSuite) TestModuleCodec() {
+	ctx, addrs := s.ctx, s.addrs
+	require := s.Require()
+
+	expire := ctx.HeaderInfo().Time.AddDate(1, 0, 0)
+	require.NoError(s.authzKeeper.SaveGrant(ctx, addrs[1], addrs[0], &banktypes.SendAuthorization{SpendLimit: coins100}, &expire))
+	require.NoError(s.authzKeeper.SaveGrant(ctx, addrs[2], addrs[0], authz.NewGenericAuthorization(bankSendAuthMsgType), nil))
+
+	cdc, err := s.authzKeeper.ModuleCodec()
+	require.NoError(err)
+
+	// every kv-pair of the module decodes to valid object updates which encode back to the same kv-pair
+	decoded := testutil.DecodeModuleState(s.T(), cdc, s.authzKeeper.KVStoreService.OpenKVStore(ctx))
+
+	grants := decoded[authzkeeper.GrantsObjectType]
+	require.Len(grants, 2)
+	require.Equal([]interface{}{[]byte(addrs[0]), []byte(addrs[1]), sdk.MsgTypeURL(&banktypes.MsgSend{})}, grants[0].Key)
+	require.Equal(expire, grants[0].Value.([]interface{})[1])
+	require.Nil(grants[1].Value.([]interface{})[1])
+
+	queue := decoded[authzkeeper.GrantQueueObjectType]
+	require.Len(queue, 1)
+	require.Equal([]interface{}{expire, []byte(addrs[0]), []byte(addrs[1])}, queue[0].Key)
+	require.Equal([]interface{}{sdk.MsgTypeURL(&banktypes.MsgSend{})}, queue[0].Value)
+
+	// deletions are decoded and encoded without values
+	updates, err := cdc.KVDecoder(schema.KVPairUpdate{Key: authzkeeper.GrantQueueKey(expire, addrs[0], addrs[1]), Delete: true})
+	require.NoError(err)
+	require.Equal([]schema.ObjectUpdate{{TypeName: authzkeeper.GrantQueueObjectType, Key: queue[0].Key, Delete: true}}, updates)
+	pairs, err := cdc.KVEncoder(updates[0])
+	require.NoError(err)
+	require.Equal([]schema.KVPairUpdate{{Key: authzkeeper.GrantQueueKey(expire, addrs[0], addrs[1]), Delete: true}}, pairs)
+
+	// unknown keys are ignored
+	updates, err = cdc.KVDecoder(schema.KVPairUpdate{Key: []byte{0xff}})
+	require.NoError(err)
+	require.Empty(updates)
+}
@@ -13,6 +13,7 @@ import (
 	"cosmossdk.io/core/legacy"
 	"cosmossdk.io/core/registry"
 	"cosmossdk.io/errors"
+	"cosmossdk.io/schema"
 	"cosmossdk.io/x/authz"
 	"cosmossdk.io/x/authz/client/cli"
 	"cosmossdk.io/x/authz/keeper"
@@ -38,6 +39,8 @@ var (
 	_ appmodule.HasBeginBlocker       = AppModule{}
 	_ appmodule.HasServices           = AppModule{}
 	_ appmodule.HasMigrations         = AppModule{}
+
+	_ schema.HasModuleCodec = AppModule{}
 )
 
 // AppModule implements the sdk.AppModule interface
@@ -115,6 +118,12 @@ func (AppModule) GetTxCmd() *cobra.Command {
 	return cli.GetTxCmd()
 }
 
+// ModuleCodec implements schema.HasModuleCodec. Grants are exported with their granter, grantee,
+// msg type URL, authorization and expiration.
+func (am AppModule) ModuleCodec() (schema.ModuleCodec, error) {
+	return am.keeper.ModuleCodec()
+}
+
 // DefaultGenesis returns default genesis state as raw bytes for the authz module.
 func (am AppModule) DefaultGenesis() json.RawMessage {
 	return am.cdc.MustMarshalJSON(authz.DefaultGenesisState())
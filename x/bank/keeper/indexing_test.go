@@ -0,0 +1,76 @@
+package keeper_test
+
+import (
+	"encoding/json"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/x/bank"
+	"cosmossdk.io/x/bank/keeper"
+	banktypes "cosmossdk.io/x/bank/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func (suite *KeeperTestSuite) TestModuleCodec() {
+	require := suite.Require()
+	cdc, err := suite.bankKeeper.ModuleCodec()
+	require.NoError(err)
+
+	objectType := func(name string) schema.ObjectType {
+		typ, ok := cdc.Schema.LookupType(name)
+		require.True(ok, name)
+		return typ.(schema.ObjectType)
+	}
+	require.Equal([]schema.Field{{Name: "address", Kind: schema.AddressKind}, {Name: "denom", Kind: schema.StringKind}}, objectType("balances").KeyFields)
+	require.Equal([]schema.Field{{Name: "amount", Kind: schema.IntegerStringKind}}, objectType("balances").ValueFields)
+	require.Equal([]schema.Field{{Name: "denom", Kind: schema.StringKind}, {Name: "address", Kind: schema.AddressKind}}, objectType("address_by_denom_index").KeyFields)
+	require.Equal([]schema.Field{{Name: "amount", Kind: schema.IntegerStringKind}}, objectType("supply").ValueFields)
+	require.Equal([]schema.Field{{Name: "metadata", Kind: schema.JSONKind}}, objectType("denom_metadata").ValueFields)
+	require.Equal([]schema.Field{{Name: "enabled", Kind: schema.BoolKind}}, objectType("send_enabled").ValueFields)
+
+	balance := schema.ObjectUpdate{TypeName: "balances", Key: []interface{}{[]byte(accAddrs[0]), fooDenom}, Value: "100"}
+	pairs, err := cdc.KVEncoder(balance)
+	require.NoError(err)
+	require.Len(pairs, 1)
+	updates, err := cdc.KVDecoder(pairs[0])
+	require.NoError(err)
+	require.Equal([]schema.ObjectUpdate{balance}, updates)
+
+	// balances which are still stored as coins are decoded as well
+	coin := sdk.NewInt64Coin(fooDenom, 100)
+	legacy, err := coin.Marshal()
+	require.NoError(err)
+	updates, err = cdc.KVDecoder(schema.KVPairUpdate{Key: pairs[0].Key, Value: legacy})
+	require.NoError(err)
+	require.Equal([]schema.ObjectUpdate{balance}, updates)
+
+	metadata := banktypes.Metadata{Base: fooDenom, Display: fooDenom, DenomUnits: []*banktypes.DenomUnit{{Denom: fooDenom}}}
+	value, err := suite.encCfg.Codec.Marshal(&metadata)
+	require.NoError(err)
+	key, err := collections.EncodeKeyWithPrefix(banktypes.DenomMetadataPrefix, collections.StringKey, fooDenom)
+	require.NoError(err)
+	updates, err = cdc.KVDecoder(schema.KVPairUpdate{Key: key, Value: value})
+	require.NoError(err)
+	require.Len(updates, 1)
+	require.Equal(fooDenom, updates[0].Key)
+	var decoded map[string]interface{}
+	require.NoError(json.Unmarshal(updates[0].Value.(json.RawMessage), &decoded))
+	require.Equal(fooDenom, decoded["base"])
+}
+
+// wrappedKeeper wraps the bank keeper like apps which decorate it do.
+type wrappedKeeper struct {
+	keeper.Keeper
+}
+
+func (suite *KeeperTestSuite) TestAppModuleCodec_wrappedKeeper() {
+	require := suite.Require()
+	expected, err := suite.bankKeeper.ModuleCodec()
+	require.NoError(err)
+
+	am := bank.NewAppModule(suite.encCfg.Codec, wrappedKeeper{suite.bankKeeper}, suite.authKeeper)
+	cdc, err := am.ModuleCodec()
+	require.NoError(err)
+	require.Equal(expected.Schema.Fingerprint(), cdc.Schema.Fingerprint())
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 
 	"cosmossdk.io/collections"
+	collcodec "cosmossdk.io/collections/codec"
 	"cosmossdk.io/collections/indexes"
 	"cosmossdk.io/core/appmodule"
 	errorsmod "cosmossdk.io/errors"
 	"cosmossdk.io/math"
+	"cosmossdk.io/schema"
 	"cosmossdk.io/x/bank/types"
 
 	"github.com/cosmos/cosmos-sdk/codec"
@@ -35,12 +37,19 @@ type ViewKeeper interface {
 	IterateAllBalances(ctx context.Context, cb func(address sdk.AccAddress, coin sdk.Coin) (stop bool))
 }
 
+// addressKey and denomKey are the key codecs of addresses and denoms, named so that the fields of
+// the module's object types are exported to indexers with meaningful names.
+var (
+	addressKey = collcodec.NamedKeyCodec(sdk.AccAddressKey, "address")
+	denomKey   = collcodec.NamedKeyCodec(collections.StringKey, "denom")
+)
+
 func newBalancesIndexes(sb *collections.SchemaBuilder) BalancesIndexes {
 	return BalancesIndexes{
 		Denom: indexes.NewReversePair[math.Int](
 			sb, types.DenomAddressPrefix, "address_by_denom_index",
-			collections.PairKeyCodec(sdk.LengthPrefixedAddressKey(sdk.AccAddressKey), collections.StringKey), //nolint:staticcheck // Note: refer to the LengthPrefixedAddressKey docs to understand why we do this.
-			indexes.WithReversePairUncheckedValue(),                                                          // denom to address indexes were stored as Key: Join(denom, address) Value: []byte{0}, this will migrate the value to []byte{} in a lazy way.
+			collections.PairKeyCodec(sdk.LengthPrefixedAddressKey(addressKey), denomKey), //nolint:staticcheck // Note: refer to the LengthPrefixedAddressKey docs to understand why we do this.
+			indexes.WithReversePairUncheckedValue(),                                      // denom to address indexes were stored as Key: Join(denom, address) Value: []byte{0}, this will migrate the value to []byte{} in a lazy way.
 		),
 	}
 }
@@ -75,21 +84,27 @@ func NewBaseViewKeeper(env appmodule.Environment, cdc codec.BinaryCodec, ak type
 		Environment:   env,
 		cdc:           cdc,
 		ak:            ak,
-		Supply:        collections.NewMap(sb, types.SupplyKey, "supply", collections.StringKey, sdk.IntValue),
-		DenomMetadata: collections.NewMap(sb, types.DenomMetadataPrefix, "denom_metadata", collections.StringKey, codec.CollValue[types.Metadata](cdc)),
-		SendEnabled:   collections.NewMap(sb, types.SendEnabledPrefix, "send_enabled", collections.StringKey, codec.BoolValue), // NOTE: we use a bool value which uses protobuf to retain state backwards compat
-		Balances:      collections.NewIndexedMap(sb, types.BalancesPrefix, "balances", collections.PairKeyCodec(sdk.AccAddressKey, collections.StringKey), types.BalanceValueCodec, newBalancesIndexes(sb)),
-		Params:        collections.NewItem(sb, types.ParamsKey, "params", codec.CollValue[types.Params](cdc)),
+		Supply:        collections.NewMap(sb, types.SupplyKey, "supply", denomKey, collcodec.NamedValueCodec(sdk.IntValue, "amount")),
+		DenomMetadata: collections.NewMap(sb, types.DenomMetadataPrefix, "denom_metadata", denomKey, collcodec.NamedValueCodec(codec.CollValue[types.Metadata](cdc), "metadata")),
+		SendEnabled:   collections.NewMap(sb, types.SendEnabledPrefix, "send_enabled", denomKey, collcodec.NamedValueCodec(codec.BoolValue, "enabled")), // NOTE: we use a bool value which uses protobuf to retain state backwards compat
+		Balances:      collections.NewIndexedMap(sb, types.BalancesPrefix, "balances", collections.PairKeyCodec(addressKey, denomKey), collcodec.NamedValueCodec(types.BalanceValueCodec, "amount"), newBalancesIndexes(sb)),
+		Params:        collections.NewItem(sb, types.ParamsKey, "params", collcodec.NamedValueCodec(codec.CollValue[types.Params](cdc), "params")),
 	}
 
-	schema, err := sb.Build()
+	collSchema, err := sb.Build()
 	if err != nil {
 		panic(err)
 	}
-	k.Schema = schema
+	k.Schema = collSchema
 	return k
 }
 
+// ModuleCodec returns the codec which exports the collections of the bank module to indexers as object types
+// named after the collections.
+func (k BaseViewKeeper) ModuleCodec() (schema.ModuleCodec, error) {
+	return k.Schema.ModuleCodec(collections.IndexingOptions{})
+}
+
 // HasBalance returns whether or not an account has at least amt balance.
 func (k BaseViewKeeper) HasBalance(ctx context.Context, addr sdk.AccAddress, amt sdk.Coin) bool {
 	return k.GetBalance(ctx, addr, amt.Denom).IsGTE(amt)
@@ -12,6 +12,7 @@ import (
 	"cosmossdk.io/core/appmodule"
 	"cosmossdk.io/core/legacy"
 	"cosmossdk.io/core/registry"
+	"cosmossdk.io/schema"
 	"cosmossdk.io/x/bank/client/cli"
 	"cosmossdk.io/x/bank/keeper"
 	"cosmossdk.io/x/bank/simulation"
@@ -38,6 +39,8 @@ var (
 	_ appmodule.HasMigrations         = AppModule{}
 	_ appmodule.HasGenesis            = AppModule{}
 	_ appmodule.HasRegisterInterfaces = AppModule{}
+
+	_ schema.HasModuleCodec = AppModule{}
 )
 
 // AppModule implements an application module for the bank module.
@@ -117,6 +120,12 @@ func (am AppModule) RegisterInvariants(ir sdk.InvariantRegistry) {
 	keeper.RegisterInvariants(ir, am.keeper)
 }
 
+// ModuleCodec implements schema.HasModuleCodec. Balances, supply, denom metadata, send enabled
+// flags and params are exported to indexers as object types named after their collections.
+func (am AppModule) ModuleCodec() (schema.ModuleCodec, error) {
+	return am.keeper.ModuleCodec()
+}
+
 // DefaultGenesis returns default genesis state as raw bytes for the bank module.
 func (am AppModule) DefaultGenesis() json.RawMessage {
 	return am.cdc.MustMarshalJSON(types.DefaultGenesisState())
@@ -0,0 +1,169 @@
+package keeper
+
+import (
+	collcodec "cosmossdk.io/collections/codec"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/x/distribution/types"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/schemavalue"
+)
+
+// The codecs in this file describe the rewards, commission and starting info of the module with
+// structured schema fields, with amounts as decimal strings, so that reward analytics can be built
+// from indexed state rather than from per block queries of the node.
+
+// decCoinsField is a list field of DecCoinStruct elements.
+func decCoinsField(name string) schema.Field {
+	return schema.Field{Name: name, Kind: schema.ListKind, ElementKind: schema.StructKind, StructType: schemavalue.DecCoinStruct}
+}
+
+func feePoolValue(cdc codec.BinaryCodec) collcodec.ValueCodec[types.FeePool] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[types.FeePool](cdc), collcodec.SchemaCodec[types.FeePool]{
+		Fields: []schema.Field{decCoinsField("community_pool"), decCoinsField("decimal_pool")},
+		ToSchemaType: func(p types.FeePool) (interface{}, error) {
+			communityPool, err := schemavalue.FormatDecCoins(p.CommunityPool) //nolint:staticcheck // the deprecated community pool is still stored
+			if err != nil {
+				return nil, err
+			}
+			decimalPool, err := schemavalue.FormatDecCoins(p.DecimalPool)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{communityPool, decimalPool}, nil
+		},
+		FromSchemaType: func(value interface{}) (types.FeePool, error) {
+			r, err := schemavalue.NewReader(value, 2)
+			if err != nil {
+				return types.FeePool{}, err
+			}
+			p := types.FeePool{CommunityPool: r.ReadDecCoins(), DecimalPool: r.ReadDecCoins()} //nolint:staticcheck // the deprecated community pool is still stored
+			return p, r.Err()
+		},
+	})
+}
+
+func validatorCurrentRewardsValue(cdc codec.BinaryCodec) collcodec.ValueCodec[types.ValidatorCurrentRewards] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[types.ValidatorCurrentRewards](cdc), collcodec.SchemaCodec[types.ValidatorCurrentRewards]{
+		Fields: []schema.Field{decCoinsField("rewards"), {Name: "period", Kind: schema.Uint64Kind}},
+		ToSchemaType: func(r types.ValidatorCurrentRewards) (interface{}, error) {
+			rewards, err := schemavalue.FormatDecCoins(r.Rewards)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{rewards, r.Period}, nil
+		},
+		FromSchemaType: func(value interface{}) (types.ValidatorCurrentRewards, error) {
+			r, err := schemavalue.NewReader(value, 2)
+			if err != nil {
+				return types.ValidatorCurrentRewards{}, err
+			}
+			rewards := types.ValidatorCurrentRewards{Rewards: r.ReadDecCoins(), Period: r.ReadUint64()}
+			return rewards, r.Err()
+		},
+	})
+}
+
+func delegatorStartingInfoValue(cdc codec.BinaryCodec) collcodec.ValueCodec[types.DelegatorStartingInfo] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[types.DelegatorStartingInfo](cdc), collcodec.SchemaCodec[types.DelegatorStartingInfo]{
+		Fields: []schema.Field{
+			{Name: "previous_period", Kind: schema.Uint64Kind},
+			{Name: "stake", Kind: schema.DecimalStringKind},
+			{Name: "height", Kind: schema.Uint64Kind},
+		},
+		ToSchemaType: func(info types.DelegatorStartingInfo) (interface{}, error) {
+			stake, err := schemavalue.FormatDec(info.Stake)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{info.PreviousPeriod, stake, info.Height}, nil
+		},
+		FromSchemaType: func(value interface{}) (types.DelegatorStartingInfo, error) {
+			r, err := schemavalue.NewReader(value, 3)
+			if err != nil {
+				return types.DelegatorStartingInfo{}, err
+			}
+			info := types.DelegatorStartingInfo{PreviousPeriod: r.ReadUint64(), Stake: r.ReadDec(), Height: r.ReadUint64()}
+			return info, r.Err()
+		},
+	})
+}
+
+func validatorAccumulatedCommissionValue(cdc codec.BinaryCodec) collcodec.ValueCodec[types.ValidatorAccumulatedCommission] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[types.ValidatorAccumulatedCommission](cdc), collcodec.SchemaCodec[types.ValidatorAccumulatedCommission]{
+		Fields: []schema.Field{decCoinsField("commission")},
+		ToSchemaType: func(c types.ValidatorAccumulatedCommission) (interface{}, error) {
+			return schemavalue.FormatDecCoins(c.Commission)
+		},
+		FromSchemaType: func(value interface{}) (types.ValidatorAccumulatedCommission, error) {
+			r, err := schemavalue.NewReader([]interface{}{value}, 1)
+			if err != nil {
+				return types.ValidatorAccumulatedCommission{}, err
+			}
+			c := types.ValidatorAccumulatedCommission{Commission: r.ReadDecCoins()}
+			return c, r.Err()
+		},
+	})
+}
+
+func validatorOutstandingRewardsValue(cdc codec.BinaryCodec) collcodec.ValueCodec[types.ValidatorOutstandingRewards] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[types.ValidatorOutstandingRewards](cdc), collcodec.SchemaCodec[types.ValidatorOutstandingRewards]{
+		Fields: []schema.Field{decCoinsField("rewards")},
+		ToSchemaType: func(r types.ValidatorOutstandingRewards) (interface{}, error) {
+			return schemavalue.FormatDecCoins(r.Rewards)
+		},
+		FromSchemaType: func(value interface{}) (types.ValidatorOutstandingRewards, error) {
+			r, err := schemavalue.NewReader([]interface{}{value}, 1)
+			if err != nil {
+				return types.ValidatorOutstandingRewards{}, err
+			}
+			rewards := types.ValidatorOutstandingRewards{Rewards: r.ReadDecCoins()}
+			return rewards, r.Err()
+		},
+	})
+}
+
+func validatorHistoricalRewardsValue(cdc codec.BinaryCodec) collcodec.ValueCodec[types.ValidatorHistoricalRewards] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[types.ValidatorHistoricalRewards](cdc), collcodec.SchemaCodec[types.ValidatorHistoricalRewards]{
+		Fields: []schema.Field{decCoinsField("cumulative_reward_ratio"), {Name: "reference_count", Kind: schema.Uint32Kind}},
+		ToSchemaType: func(r types.ValidatorHistoricalRewards) (interface{}, error) {
+			ratio, err := schemavalue.FormatDecCoins(r.CumulativeRewardRatio)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{ratio, r.ReferenceCount}, nil
+		},
+		FromSchemaType: func(value interface{}) (types.ValidatorHistoricalRewards, error) {
+			r, err := schemavalue.NewReader(value, 2)
+			if err != nil {
+				return types.ValidatorHistoricalRewards{}, err
+			}
+			rewards := types.ValidatorHistoricalRewards{CumulativeRewardRatio: r.ReadDecCoins(), ReferenceCount: r.ReadUint32()}
+			return rewards, r.Err()
+		},
+	})
+}
+
+func validatorSlashEventValue(cdc codec.BinaryCodec) collcodec.ValueCodec[types.ValidatorSlashEvent] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[types.ValidatorSlashEvent](cdc), collcodec.SchemaCodec[types.ValidatorSlashEvent]{
+		Fields: []schema.Field{
+			{Name: "validator_period", Kind: schema.Uint64Kind},
+			{Name: "fraction", Kind: schema.DecimalStringKind},
+		},
+		ToSchemaType: func(e types.ValidatorSlashEvent) (interface{}, error) {
+			fraction, err := schemavalue.FormatDec(e.Fraction)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{e.ValidatorPeriod, fraction}, nil
+		},
+		FromSchemaType: func(value interface{}) (types.ValidatorSlashEvent, error) {
+			r, err := schemavalue.NewReader(value, 2)
+			if err != nil {
+				return types.ValidatorSlashEvent{}, err
+			}
+			e := types.ValidatorSlashEvent{ValidatorPeriod: r.ReadUint64(), Fraction: r.ReadDec()}
+			return e, r.Err()
+		},
+	})
+}
@@ -0,0 +1,46 @@
+package keeper_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/math"
+	"cosmossdk.io/x/distribution/types"
+
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestModuleCodec(t *testing.T) {
+	ctx, addrs, distrKeeper, _ := initFixture(t)
+	valAddr := sdk.ValAddress(addrs[0])
+	rewards := sdk.DecCoins{sdk.NewDecCoinFromDec("stake", math.LegacyNewDecWithPrec(15, 1))}
+
+	require.NoError(t, distrKeeper.FeePool.Set(ctx, types.FeePool{DecimalPool: rewards}))
+	require.NoError(t, distrKeeper.DelegatorsWithdrawAddress.Set(ctx, addrs[0], addrs[1]))
+	require.NoError(t, distrKeeper.ValidatorCurrentRewards.Set(ctx, valAddr, types.NewValidatorCurrentRewards(rewards, 2)))
+	require.NoError(t, distrKeeper.ValidatorOutstandingRewards.Set(ctx, valAddr, types.ValidatorOutstandingRewards{Rewards: rewards}))
+	require.NoError(t, distrKeeper.ValidatorsAccumulatedCommission.Set(ctx, valAddr, types.ValidatorAccumulatedCommission{Commission: rewards}))
+	require.NoError(t, distrKeeper.ValidatorHistoricalRewards.Set(ctx, collections.Join(valAddr, uint64(1)), types.NewValidatorHistoricalRewards(rewards, 3)))
+	require.NoError(t, distrKeeper.DelegatorStartingInfo.Set(ctx, collections.Join(valAddr, addrs[1]), types.NewDelegatorStartingInfo(1, math.LegacyNewDec(10), 5)))
+	require.NoError(t, distrKeeper.ValidatorSlashEvents.Set(ctx, collections.Join3(valAddr, uint64(5), uint64(1)), types.NewValidatorSlashEvent(1, math.LegacyNewDecWithPrec(1, 2))))
+
+	cdc, err := distrKeeper.Schema.ModuleCodec(collections.IndexingOptions{})
+	require.NoError(t, err)
+
+	// every kv-pair of the module decodes to valid object updates which encode back to the same kv-pair
+	decoded := testutil.DecodeModuleState(t, cdc, distrKeeper.KVStoreService.OpenKVStore(ctx))
+
+	decCoins := []interface{}{[]interface{}{"stake", "1.500000000000000000"}}
+	require.Equal(t, []interface{}{[]interface{}{}, decCoins}, decoded["fee_pool"][0].Value)
+	require.Equal(t, []byte(addrs[1]), decoded["delegators_withdraw_address"][0].Value)
+	require.Equal(t, []interface{}{decCoins, uint64(2)}, decoded["validators_current_rewards"][0].Value)
+	require.Equal(t, decCoins, decoded["validator_outstanding_rewards"][0].Value)
+	require.Equal(t, decCoins, decoded["validators_accumulated_commission"][0].Value)
+	require.Equal(t, []interface{}{decCoins, uint32(3)}, decoded["validator_historical_rewards"][0].Value)
+	require.Equal(t, []interface{}{[]byte(valAddr), []byte(addrs[1])}, decoded["delegators_starting_info"][0].Key)
+	require.Equal(t, []interface{}{uint64(1), "10.000000000000000000", uint64(5)}, decoded["delegators_starting_info"][0].Value)
+	require.Equal(t, []interface{}{uint64(1), "0.010000000000000000"}, decoded["validator_slash_events"][0].Value)
+}
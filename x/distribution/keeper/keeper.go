@@ -84,57 +84,57 @@ func NewKeeper(
 		poolKeeper:       pk,
 		feeCollectorName: feeCollectorName,
 		authority:        authority,
-		Params:           collections.NewItem(sb, types.ParamsKey, "params", codec.CollValue[types.Params](cdc)),
-		FeePool:          collections.NewItem(sb, types.FeePoolKey, "fee_pool", codec.CollValue[types.FeePool](cdc)),
+		Params:           collections.NewItem(sb, types.ParamsKey, "params", collcodec.NamedValueCodec(codec.CollValue[types.Params](cdc), "params")),
+		FeePool:          collections.NewItem(sb, types.FeePoolKey, "fee_pool", feePoolValue(cdc)),
 		DelegatorsWithdrawAddress: collections.NewMap(
 			sb,
 			types.DelegatorWithdrawAddrPrefix,
 			"delegators_withdraw_address",
-			sdk.LengthPrefixedAddressKey(sdk.AccAddressKey), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
-			collcodec.KeyToValueCodec(sdk.AccAddressKey),
+			collcodec.NamedKeyCodec(sdk.LengthPrefixedAddressKey(sdk.AccAddressKey), "delegator"), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
+			collcodec.NamedValueCodec(collcodec.KeyToValueCodec(sdk.AccAddressKey), "withdraw_address"),
 		),
 		ValidatorCurrentRewards: collections.NewMap(
 			sb,
 			types.ValidatorCurrentRewardsPrefix,
 			"validators_current_rewards",
-			sdk.LengthPrefixedAddressKey(sdk.ValAddressKey), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
-			codec.CollValue[types.ValidatorCurrentRewards](cdc),
+			collcodec.NamedKeyCodec(sdk.LengthPrefixedAddressKey(sdk.ValAddressKey), "validator"), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
+			validatorCurrentRewardsValue(cdc),
 		),
 		DelegatorStartingInfo: collections.NewMap(
 			sb,
 			types.DelegatorStartingInfoPrefix,
 			"delegators_starting_info",
-			collections.PairKeyCodec(sdk.ValAddressKey, sdk.LengthPrefixedAddressKey(sdk.AccAddressKey)), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
-			codec.CollValue[types.DelegatorStartingInfo](cdc),
+			collcodec.NamedKeyCodec(collections.PairKeyCodec(sdk.ValAddressKey, sdk.LengthPrefixedAddressKey(sdk.AccAddressKey)), "validator", "delegator"), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
+			delegatorStartingInfoValue(cdc),
 		),
 		ValidatorsAccumulatedCommission: collections.NewMap(
 			sb,
 			types.ValidatorAccumulatedCommissionPrefix,
 			"validators_accumulated_commission",
-			sdk.LengthPrefixedAddressKey(sdk.ValAddressKey), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
-			codec.CollValue[types.ValidatorAccumulatedCommission](cdc),
+			collcodec.NamedKeyCodec(sdk.LengthPrefixedAddressKey(sdk.ValAddressKey), "validator"), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
+			validatorAccumulatedCommissionValue(cdc),
 		),
 		ValidatorOutstandingRewards: collections.NewMap(
 			sb,
 			types.ValidatorOutstandingRewardsPrefix,
 			"validator_outstanding_rewards",
-			sdk.LengthPrefixedAddressKey(sdk.ValAddressKey), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
-			codec.CollValue[types.ValidatorOutstandingRewards](cdc),
+			collcodec.NamedKeyCodec(sdk.LengthPrefixedAddressKey(sdk.ValAddressKey), "validator"), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
+			validatorOutstandingRewardsValue(cdc),
 		),
 
 		ValidatorHistoricalRewards: collections.NewMap(
 			sb,
 			types.ValidatorHistoricalRewardsPrefix,
 			"validator_historical_rewards",
-			collections.PairKeyCodec(sdk.LengthPrefixedAddressKey(sdk.ValAddressKey), sdk.LEUint64Key), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
-			codec.CollValue[types.ValidatorHistoricalRewards](cdc),
+			collcodec.NamedKeyCodec(collections.PairKeyCodec(sdk.LengthPrefixedAddressKey(sdk.ValAddressKey), sdk.LEUint64Key), "validator", "period"), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
+			validatorHistoricalRewardsValue(cdc),
 		),
 		ValidatorSlashEvents: collections.NewMap(
 			sb,
 			types.ValidatorSlashEventPrefix,
 			"validator_slash_events",
-			collections.TripleKeyCodec(sdk.LengthPrefixedAddressKey(sdk.ValAddressKey), collections.Uint64Key, collections.Uint64Key), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
-			codec.CollValue[types.ValidatorSlashEvent](cdc),
+			collcodec.NamedKeyCodec(collections.TripleKeyCodec(sdk.LengthPrefixedAddressKey(sdk.ValAddressKey), collections.Uint64Key, collections.Uint64Key), "validator", "height", "period"), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
+			validatorSlashEventValue(cdc),
 		),
 	}
 
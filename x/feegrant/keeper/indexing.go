@@ -0,0 +1,50 @@
+package keeper
+
+import (
+	collcodec "cosmossdk.io/collections/codec"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/x/feegrant"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/schemavalue"
+)
+
+// grantValue describes fee allowance grants by their allowance, so that the active allowances of an
+// address can be listed by indexers. The granter and grantee are the key of the grant and are restored
+// from it when encoding grants. The allowance is kept as an any struct since applications can register
+// their own allowance types.
+func grantValue(cdc codec.BinaryCodec, ak feegrant.AccountKeeper) collcodec.ValueCodec[feegrant.Grant] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[feegrant.Grant](cdc), collcodec.SchemaCodec[feegrant.Grant]{
+		Fields: []schema.Field{
+			{Name: "allowance", Kind: schema.StructKind, StructType: schemavalue.AnyStruct, Nullable: true},
+		},
+		ToSchemaType: func(g feegrant.Grant) (interface{}, error) {
+			return schemavalue.AnyValue(g.Allowance), nil
+		},
+		FromKeyedSchemaType: func(key, value interface{}) (feegrant.Grant, error) {
+			k, err := schemavalue.NewReader(key, 2)
+			if err != nil {
+				return feegrant.Grant{}, err
+			}
+			grantee, granter := k.ReadBytes(), k.ReadBytes()
+			if err := k.Err(); err != nil {
+				return feegrant.Grant{}, err
+			}
+
+			r, err := schemavalue.NewReader([]interface{}{value}, 1)
+			if err != nil {
+				return feegrant.Grant{}, err
+			}
+			g := feegrant.Grant{Allowance: r.ReadAny()}
+			if err := r.Err(); err != nil {
+				return feegrant.Grant{}, err
+			}
+
+			if g.Granter, err = ak.AddressCodec().BytesToString(granter); err != nil {
+				return feegrant.Grant{}, err
+			}
+			g.Grantee, err = ak.AddressCodec().BytesToString(grantee)
+			return g, err
+		},
+	})
+}
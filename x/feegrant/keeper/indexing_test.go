@@ -0,0 +1,38 @@
+package keeper_test
+
+import (
+	"cosmossdk.io/collections"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/x/feegrant"
+
+	"github.com/cosmos/cosmos-sdk/codec/schemavalue"
+	"github.com/cosmos/cosmos-sdk/testutil"
+)
+
+func (suite *KeeperTestSuite) TestModuleCodec() {
+	require := suite.Require()
+	ctx := suite.ctx
+	exp := ctx.HeaderInfo().Time.AddDate(1, 0, 0)
+	require.NoError(suite.feegrantKeeper.GrantAllowance(ctx, suite.addrs[0], suite.addrs[1], &feegrant.BasicAllowance{SpendLimit: suite.atom, Expiration: &exp}))
+
+	cdc, err := suite.feegrantKeeper.Schema.ModuleCodec(collections.IndexingOptions{})
+	require.NoError(err)
+
+	// every kv-pair of the module decodes to valid object updates which encode back to the same kv-pair
+	decoded := testutil.DecodeModuleState(suite.T(), cdc, suite.feegrantKeeper.KVStoreService.OpenKVStore(ctx))
+
+	allowance := decoded["allowances"][0]
+	require.Equal([]interface{}{[]byte(suite.addrs[1]), []byte(suite.addrs[0])}, allowance.Key)
+	require.Equal("/cosmos.feegrant.v1beta1.BasicAllowance", allowance.Value.([]interface{})[0])
+
+	require.Equal([]interface{}{exp, []byte(suite.addrs[1]), []byte(suite.addrs[0])}, decoded["allowances_queue"][0].Key)
+	require.Equal(true, decoded["allowances_queue"][0].Value)
+
+	// the granter and grantee of grants are only part of the key
+	allowances, ok := cdc.Schema.LookupType("allowances")
+	require.True(ok)
+	require.Equal([]schema.Field{{Name: "allowance", Kind: schema.StructKind, StructType: schemavalue.AnyStruct, Nullable: true}}, allowances.(schema.ObjectType).ValueFields)
+	queue, ok := cdc.Schema.LookupType("allowances_queue")
+	require.True(ok)
+	require.Equal([]schema.Field{{Name: "queued", Kind: schema.BoolKind}}, queue.(schema.ObjectType).ValueFields)
+}
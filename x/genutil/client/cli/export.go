@@ -1,16 +1,24 @@
 package cli
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	indexerv1 "cosmossdk.io/api/cosmos/indexer/v1"
+	"cosmossdk.io/schema/appdata"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/codec/schemaproto"
 	"github.com/cosmos/cosmos-sdk/server"
 	servertypes "github.com/cosmos/cosmos-sdk/server/types"
 	"github.com/cosmos/cosmos-sdk/version"
@@ -23,9 +31,21 @@ const (
 	flagForZeroHeight    = "for-zero-height"
 	flagJailAllowedAddrs = "jail-allowed-addrs"
 	flagModulesToExport  = "modules-to-export"
+	flagFormat           = "format"
+
+	// FormatGenesis exports the state as a genesis file with module-specific genesis JSON.
+	FormatGenesis = "genesis"
+	// FormatJSON exports the state as newline-delimited JSON encoded cosmos.indexer.v1.Packet messages.
+	FormatJSON = "json"
+	// FormatProto exports the state as varint length-delimited protobuf encoded cosmos.indexer.v1.Packet messages.
+	FormatProto = "proto"
+
+	// maxPacketLineSize is the maximum size of a JSON encoded packet read by ReadPackets.
+	maxPacketLineSize = 64 << 20
 )
 
-// ExportCmd dumps app state to JSON.
+// ExportCmd dumps app state to JSON. With the json or proto format, the state is instead exported as a stream
+// of schema packets which indexers can bootstrap from.
 func ExportCmd(appExporter servertypes.AppExporter) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "export",
@@ -36,6 +56,11 @@ func ExportCmd(appExporter servertypes.AppExporter) *cobra.Command {
 			viper := client.GetViperFromCmd(cmd)
 			logger := client.GetLoggerFromCmd(cmd)
 
+			format, _ := cmd.Flags().GetString(flagFormat)
+			if format != FormatGenesis && format != FormatJSON && format != FormatProto {
+				return fmt.Errorf("unknown export format %q, expected one of %s, %s or %s", format, FormatGenesis, FormatJSON, FormatProto)
+			}
+
 			if _, err := os.Stat(config.GenesisFile()); os.IsNotExist(err) {
 				return err
 			}
@@ -85,6 +110,28 @@ func ExportCmd(appExporter servertypes.AppExporter) *cobra.Command {
 				return fmt.Errorf("error exporting state: %w", err)
 			}
 
+			if format != FormatGenesis {
+				if exported.ExportObjectUpdates == nil {
+					return fmt.Errorf("the app does not support exporting state in the %s format", format)
+				}
+
+				out := cmd.OutOrStdout()
+				if outputDocument != "" {
+					f, err := os.Create(outputDocument)
+					if err != nil {
+						return err
+					}
+					defer f.Close()
+					out = f
+				}
+
+				w := bufio.NewWriter(out)
+				if err := exported.ExportObjectUpdates(packetWriter(w, format)); err != nil {
+					return fmt.Errorf("error exporting object updates: %w", err)
+				}
+				return w.Flush()
+			}
+
 			appGenesis, err := genutiltypes.AppGenesisFromFile(config.GenesisFile())
 			if err != nil {
 				return err
@@ -122,6 +169,83 @@ func ExportCmd(appExporter servertypes.AppExporter) *cobra.Command {
 	cmd.Flags().StringSlice(flagJailAllowedAddrs, []string{}, "Comma-separated list of operator addresses of jailed validators to unjail")
 	cmd.Flags().StringSlice(flagModulesToExport, []string{}, "Comma-separated list of modules to export. If empty, will export all modules")
 	cmd.Flags().String(flags.FlagOutputDocument, "", "Exported state is written to the given file instead of STDOUT")
+	cmd.Flags().String(flagFormat, FormatGenesis, fmt.Sprintf("Export format: %s for a genesis file, or %s or %s for a stream of schema module initialization and object update packets which indexers can bootstrap from", FormatGenesis, FormatJSON, FormatProto))
 
 	return cmd
 }
+
+// packetWriter returns a listener which writes the module initialization and object update packets it
+// receives to w in the JSON or proto format.
+func packetWriter(w io.Writer, format string) appdata.Listener {
+	write := func(packet appdata.Packet) error {
+		p, err := schemaproto.PacketToProto(packet)
+		if err != nil {
+			return err
+		}
+
+		if format == FormatProto {
+			_, err = protodelim.MarshalTo(w, p)
+			return err
+		}
+
+		bz, err := protojson.Marshal(p)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(bz, '\n'))
+		return err
+	}
+
+	return appdata.Listener{
+		InitializeModuleData: func(data appdata.ModuleInitializationData) error { return write(data) },
+		OnObjectUpdate:       func(data appdata.ObjectUpdateData) error { return write(data) },
+	}
+}
+
+// ReadPackets reads the packets written by the export command in the JSON or proto format from r and sends
+// them to listener, for instance to initialize module state with module.Manager.ImportObjectUpdates.
+func ReadPackets(r io.Reader, format string, listener appdata.Listener) error {
+	send := func(p *indexerv1.Packet) error {
+		packet, err := schemaproto.PacketFromProto(p)
+		if err != nil {
+			return err
+		}
+		return listener.SendPacket(packet)
+	}
+
+	switch format {
+	case FormatProto:
+		br := bufio.NewReader(r)
+		for {
+			p := &indexerv1.Packet{}
+			err := protodelim.UnmarshalFrom(br, p)
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := send(p); err != nil {
+				return err
+			}
+		}
+	case FormatJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, maxPacketLineSize)
+		for scanner.Scan() {
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+			p := &indexerv1.Packet{}
+			if err := protojson.Unmarshal(scanner.Bytes(), p); err != nil {
+				return err
+			}
+			if err := send(p); err != nil {
+				return err
+			}
+		}
+		return scanner.Err()
+	default:
+		return fmt.Errorf("unknown packet format %q, expected %s or %s", format, FormatJSON, FormatProto)
+	}
+}
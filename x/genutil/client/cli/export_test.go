@@ -1,12 +1,14 @@
 package cli_test
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,9 +18,14 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
 
+	indexerv1 "cosmossdk.io/api/cosmos/indexer/v1"
 	corectx "cosmossdk.io/core/context"
 	"cosmossdk.io/log"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/server/types"
@@ -321,6 +328,105 @@ func TestExportCLI(t *testing.T) {
 		require.ErrorIs(t, res.Err, e.Err)
 	})
 
+	t.Run("exports object updates with --format", func(t *testing.T) {
+		t.Parallel()
+
+		moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{{
+			Name:        "balances",
+			KeyFields:   []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+			ValueFields: []schema.Field{{Name: "amount", Kind: schema.Uint64Kind}},
+		}})
+		require.NoError(t, err)
+
+		e := new(mockExporter)
+		e.SetDefaultExportApp()
+		e.ExportApp.ExportObjectUpdates = func(listener appdata.Listener) error {
+			if err := listener.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "bank", Schema: moduleSchema}); err != nil {
+				return err
+			}
+			return listener.OnObjectUpdate(appdata.ObjectUpdateData{
+				ModuleName: "bank",
+				Updates:    []schema.ObjectUpdate{{TypeName: "balances", Key: "foo", Value: uint64(10)}},
+			})
+		}
+
+		run := func(format string) cmdtest.RunResult {
+			sys := NewExportSystem(t, e.Export)
+			_ = sys.MustRun(t, "init", "some_moniker")
+			return sys.MustRun(t, "export", "--format", format)
+		}
+
+		checkPackets := func(packets []*indexerv1.Packet) {
+			require.Len(t, packets, 2)
+			require.Equal(t, "bank", packets[0].GetModuleInitialization().ModuleName)
+			updates := packets[1].GetObjectUpdates()
+			require.Equal(t, "bank", updates.ModuleName)
+			require.Equal(t, "foo", updates.Updates[0].Key.GetStringValue())
+			require.Equal(t, uint64(10), updates.Updates[0].GetObjectValue().GetUint64Value())
+		}
+
+		res := run(cli.FormatJSON)
+		var packets []*indexerv1.Packet
+		for _, line := range strings.Split(strings.TrimSpace(res.Stdout.String()), "\n") {
+			packet := &indexerv1.Packet{}
+			require.NoError(t, protojson.Unmarshal([]byte(line), packet))
+			packets = append(packets, packet)
+		}
+		checkPackets(packets)
+
+		res = run(cli.FormatProto)
+		packets = nil
+		r := bufio.NewReader(&res.Stdout)
+		for {
+			packet := &indexerv1.Packet{}
+			err := protodelim.UnmarshalFrom(r, packet)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			packets = append(packets, packet)
+		}
+		checkPackets(packets)
+
+		// the exported packets can be read back
+		for _, format := range []string{cli.FormatJSON, cli.FormatProto} {
+			res = run(format)
+			var modules []string
+			var updates []schema.ObjectUpdate
+			err := cli.ReadPackets(&res.Stdout, format, appdata.Listener{
+				InitializeModuleData: func(data appdata.ModuleInitializationData) error {
+					modules = append(modules, data.ModuleName)
+					return nil
+				},
+				OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+					updates = append(updates, data.Updates...)
+					return nil
+				},
+			})
+			require.NoError(t, err)
+			require.Equal(t, []string{"bank"}, modules)
+			require.Equal(t, []schema.ObjectUpdate{{TypeName: "balances", Key: "foo", Value: uint64(10)}}, updates)
+		}
+
+		require.ErrorContains(t, cli.ReadPackets(strings.NewReader(""), cli.FormatGenesis, appdata.Listener{}), "unknown packet format")
+	})
+
+	t.Run("rejects --format if the app does not export object updates", func(t *testing.T) {
+		t.Parallel()
+
+		e := new(mockExporter)
+		e.SetDefaultExportApp()
+
+		sys := NewExportSystem(t, e.Export)
+		_ = sys.MustRun(t, "init", "some_moniker")
+
+		res := sys.Run("export", "--format", "yaml")
+		require.ErrorContains(t, res.Err, "unknown export format")
+
+		res = sys.Run("export", "--format", cli.FormatJSON)
+		require.ErrorContains(t, res.Err, "the app does not support exporting state in the json format")
+	})
+
 	t.Run("rejects positional arguments", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,196 @@
+package keeper
+
+import (
+	govv1 "cosmossdk.io/api/cosmos/gov/v1"
+	collcodec "cosmossdk.io/collections/codec"
+	"cosmossdk.io/schema"
+	v1 "cosmossdk.io/x/gov/types/v1"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/schemavalue"
+)
+
+// The codecs in this file describe the proposals, votes and deposits of the module with structured
+// schema fields, so that explorers can follow governance through indexers instead of reconstructing
+// it from events. The values keep every field of their protobuf messages so that the object updates
+// can be encoded back to the same state.
+
+var (
+	// ProposalStatusEnum is the schema enum type of v1.ProposalStatus.
+	ProposalStatusEnum = schemavalue.EnumType("proposal_status", govv1.ProposalStatus(0).Descriptor())
+
+	// ProposalTypeEnum is the schema enum type of v1.ProposalType.
+	ProposalTypeEnum = schemavalue.EnumType("proposal_type", govv1.ProposalType(0).Descriptor())
+
+	// VoteOptionEnum is the schema enum type of v1.VoteOption.
+	VoteOptionEnum = schemavalue.EnumType("vote_option", govv1.VoteOption(0).Descriptor())
+)
+
+var (
+	tallyResultStruct = schema.StructType{
+		Name: "tally_result",
+		Fields: []schema.Field{
+			// the counts are kept as the strings they are stored as, since the counts of the deprecated
+			// yes, abstain, no and no with veto options may be empty
+			{Name: "yes_count", Kind: schema.StringKind},
+			{Name: "abstain_count", Kind: schema.StringKind},
+			{Name: "no_count", Kind: schema.StringKind},
+			{Name: "no_with_veto_count", Kind: schema.StringKind},
+			{Name: "option_one_count", Kind: schema.StringKind},
+			{Name: "option_two_count", Kind: schema.StringKind},
+			{Name: "option_three_count", Kind: schema.StringKind},
+			{Name: "option_four_count", Kind: schema.StringKind},
+			{Name: "spam_count", Kind: schema.StringKind},
+		},
+	}
+
+	weightedVoteOptionStruct = schema.StructType{
+		Name: "weighted_vote_option",
+		Fields: []schema.Field{
+			{Name: "option", Kind: schema.EnumKind, EnumType: VoteOptionEnum},
+			{Name: "weight", Kind: schema.DecimalStringKind},
+		},
+	}
+)
+
+func proposalValue(cdc codec.BinaryCodec) collcodec.ValueCodec[v1.Proposal] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[v1.Proposal](cdc), collcodec.SchemaCodec[v1.Proposal]{
+		Fields: []schema.Field{
+			{Name: "id", Kind: schema.Uint64Kind},
+			{Name: "messages", Kind: schema.ListKind, ElementKind: schema.StructKind, StructType: schemavalue.AnyStruct},
+			{Name: "status", Kind: schema.EnumKind, EnumType: ProposalStatusEnum},
+			{Name: "final_tally_result", Kind: schema.StructKind, StructType: tallyResultStruct, Nullable: true},
+			{Name: "submit_time", Kind: schema.TimeKind, Nullable: true},
+			{Name: "deposit_end_time", Kind: schema.TimeKind, Nullable: true},
+			{Name: "total_deposit", Kind: schema.ListKind, ElementKind: schema.StructKind, StructType: schemavalue.CoinStruct},
+			{Name: "voting_start_time", Kind: schema.TimeKind, Nullable: true},
+			{Name: "voting_end_time", Kind: schema.TimeKind, Nullable: true},
+			{Name: "metadata", Kind: schema.StringKind},
+			{Name: "title", Kind: schema.StringKind},
+			{Name: "summary", Kind: schema.StringKind},
+			{Name: "proposer", Kind: schema.StringKind},
+			{Name: "expedited", Kind: schema.BoolKind},
+			{Name: "failed_reason", Kind: schema.StringKind},
+			{Name: "proposal_type", Kind: schema.EnumKind, EnumType: ProposalTypeEnum},
+		},
+		ToSchemaType: func(p v1.Proposal) (interface{}, error) {
+			var tally interface{}
+			if t := p.FinalTallyResult; t != nil {
+				tally = []interface{}{
+					t.YesCount, t.AbstainCount, t.NoCount, t.NoWithVetoCount, //nolint:staticcheck // the deprecated counts are still stored
+					t.OptionOneCount, t.OptionTwoCount, t.OptionThreeCount, t.OptionFourCount, t.SpamCount,
+				}
+			}
+			return []interface{}{
+				p.Id,
+				schemavalue.AnysValue(p.Messages),
+				p.Status.String(),
+				tally,
+				schemavalue.TimeValue(p.SubmitTime),
+				schemavalue.TimeValue(p.DepositEndTime),
+				schemavalue.CoinsValue(p.TotalDeposit),
+				schemavalue.TimeValue(p.VotingStartTime),
+				schemavalue.TimeValue(p.VotingEndTime),
+				p.Metadata,
+				p.Title,
+				p.Summary,
+				p.Proposer,
+				p.Expedited, //nolint:staticcheck // the deprecated flag is still stored
+				p.FailedReason,
+				p.ProposalType.String(),
+			}, nil
+		},
+		FromSchemaType: func(value interface{}) (v1.Proposal, error) {
+			r, err := schemavalue.NewReader(value, 16)
+			if err != nil {
+				return v1.Proposal{}, err
+			}
+			p := v1.Proposal{
+				Id:       r.ReadUint64(),
+				Messages: r.ReadAnys(),
+				Status:   v1.ProposalStatus(r.ReadEnum(ProposalStatusEnum)),
+			}
+			if t := r.ReadNullableStruct(len(tallyResultStruct.Fields)); t != nil {
+				p.FinalTallyResult = &v1.TallyResult{
+					YesCount:         t.ReadString(),
+					AbstainCount:     t.ReadString(),
+					NoCount:          t.ReadString(),
+					NoWithVetoCount:  t.ReadString(),
+					OptionOneCount:   t.ReadString(),
+					OptionTwoCount:   t.ReadString(),
+					OptionThreeCount: t.ReadString(),
+					OptionFourCount:  t.ReadString(),
+					SpamCount:        t.ReadString(),
+				}
+			}
+			p.SubmitTime = r.ReadNullableTime()
+			p.DepositEndTime = r.ReadNullableTime()
+			p.TotalDeposit = r.ReadCoins()
+			p.VotingStartTime = r.ReadNullableTime()
+			p.VotingEndTime = r.ReadNullableTime()
+			p.Metadata = r.ReadString()
+			p.Title = r.ReadString()
+			p.Summary = r.ReadString()
+			p.Proposer = r.ReadString()
+			p.Expedited = r.ReadBool() //nolint:staticcheck // the deprecated flag is still stored
+			p.FailedReason = r.ReadString()
+			p.ProposalType = v1.ProposalType(r.ReadEnum(ProposalTypeEnum))
+			return p, r.Err()
+		},
+	})
+}
+
+func voteValue(cdc codec.BinaryCodec) collcodec.ValueCodec[v1.Vote] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[v1.Vote](cdc), collcodec.SchemaCodec[v1.Vote]{
+		Fields: []schema.Field{
+			{Name: "proposal_id", Kind: schema.Uint64Kind},
+			{Name: "voter", Kind: schema.StringKind},
+			{Name: "options", Kind: schema.ListKind, ElementKind: schema.StructKind, StructType: weightedVoteOptionStruct},
+			{Name: "metadata", Kind: schema.StringKind},
+		},
+		ToSchemaType: func(v v1.Vote) (interface{}, error) {
+			options := make([]interface{}, len(v.Options))
+			for i, option := range v.Options {
+				options[i] = []interface{}{option.GetOption().String(), option.GetWeight()}
+			}
+			return []interface{}{v.ProposalId, v.Voter, options, v.Metadata}, nil
+		},
+		FromSchemaType: func(value interface{}) (v1.Vote, error) {
+			r, err := schemavalue.NewReader(value, 4)
+			if err != nil {
+				return v1.Vote{}, err
+			}
+			v := v1.Vote{ProposalId: r.ReadUint64(), Voter: r.ReadString()}
+			for options := r.ReadList(); options.More(); {
+				o := options.ReadStruct(len(weightedVoteOptionStruct.Fields))
+				v.Options = append(v.Options, &v1.WeightedVoteOption{
+					Option: v1.VoteOption(o.ReadEnum(VoteOptionEnum)),
+					Weight: o.ReadString(),
+				})
+			}
+			v.Metadata = r.ReadString()
+			return v, r.Err()
+		},
+	})
+}
+
+func depositValue(cdc codec.BinaryCodec) collcodec.ValueCodec[v1.Deposit] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[v1.Deposit](cdc), collcodec.SchemaCodec[v1.Deposit]{
+		Fields: []schema.Field{
+			{Name: "proposal_id", Kind: schema.Uint64Kind},
+			{Name: "depositor", Kind: schema.StringKind},
+			{Name: "amount", Kind: schema.ListKind, ElementKind: schema.StructKind, StructType: schemavalue.CoinStruct},
+		},
+		ToSchemaType: func(d v1.Deposit) (interface{}, error) {
+			return []interface{}{d.ProposalId, d.Depositor, schemavalue.CoinsValue(d.Amount)}, nil
+		},
+		FromSchemaType: func(value interface{}) (v1.Deposit, error) {
+			r, err := schemavalue.NewReader(value, 3)
+			if err != nil {
+				return v1.Deposit{}, err
+			}
+			d := v1.Deposit{ProposalId: r.ReadUint64(), Depositor: r.ReadString(), Amount: r.ReadCoins()}
+			return d, r.Err()
+		},
+	})
+}
@@ -0,0 +1,72 @@
+package keeper_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/collections"
+	sdkmath "cosmossdk.io/math"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/x/gov/keeper"
+	v1 "cosmossdk.io/x/gov/types/v1"
+
+	"github.com/cosmos/cosmos-sdk/codec/address"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestModuleCodec(t *testing.T) {
+	govKeeper, mocks, _, ctx := setupGovKeeper(t)
+	authKeeper, bankKeeper, stakingKeeper := mocks.acctKeeper, mocks.bankKeeper, mocks.stakingKeeper
+	addrs := simtestutil.AddTestAddrsIncremental(bankKeeper, stakingKeeper, ctx, 2, sdkmath.NewInt(10000000))
+	authKeeper.EXPECT().AddressCodec().Return(address.NewBech32Codec("cosmos")).AnyTimes()
+	addr0Str, err := authKeeper.AddressCodec().BytesToString(addrs[0])
+	require.NoError(t, err)
+
+	proposal, err := govKeeper.SubmitProposal(ctx, TestProposal, "", "title", "summary", addrs[0], v1.ProposalType_PROPOSAL_TYPE_STANDARD)
+	require.NoError(t, err)
+	require.NoError(t, govKeeper.Params.Set(ctx, v1.DefaultParams()))
+	require.NoError(t, govKeeper.Deposits.Set(ctx, collections.Join(proposal.Id, addrs[0]), v1.NewDeposit(proposal.Id, addr0Str, sdk.NewCoins(sdk.NewInt64Coin("stake", 10)))))
+	require.NoError(t, govKeeper.Votes.Set(ctx, collections.Join(proposal.Id, addrs[0]), v1.NewVote(proposal.Id, addr0Str, v1.WeightedVoteOptions{
+		v1.NewWeightedVoteOption(v1.OptionYes, sdkmath.LegacyNewDecWithPrec(60, 2)),
+		v1.NewWeightedVoteOption(v1.OptionNo, sdkmath.LegacyNewDecWithPrec(40, 2)),
+	}, "metadata")))
+
+	cdc, err := govKeeper.Schema.ModuleCodec(collections.IndexingOptions{})
+	require.NoError(t, err)
+
+	// every kv-pair of the module decodes to valid object updates which encode back to the same kv-pair
+	decoded := testutil.DecodeModuleState(t, cdc, govKeeper.KVStoreService.OpenKVStore(ctx))
+
+	require.Len(t, decoded["proposals"], 1)
+	require.Equal(t, proposal.Id, decoded["proposals"][0].Key)
+	value := decoded["proposals"][0].Value.([]interface{})
+	require.Equal(t, "PROPOSAL_STATUS_DEPOSIT_PERIOD", value[2])
+	require.Equal(t, "title", value[10])
+	require.Equal(t, "PROPOSAL_TYPE_STANDARD", value[15])
+
+	require.Len(t, decoded["votes"], 1)
+	require.Equal(t, []interface{}{proposal.Id, []byte(addrs[0])}, decoded["votes"][0].Key)
+	options := decoded["votes"][0].Value.([]interface{})[2]
+	require.Equal(t, []interface{}{
+		[]interface{}{"VOTE_OPTION_YES", "0.600000000000000000"},
+		[]interface{}{"VOTE_OPTION_NO", "0.400000000000000000"},
+	}, options)
+
+	require.Len(t, decoded["deposits"], 1)
+	require.Equal(t, []interface{}{proposal.Id, addr0Str, []interface{}{[]interface{}{"stake", "10"}}}, decoded["deposits"][0].Value)
+
+	require.Len(t, decoded["params"], 1)
+
+	for name, enum := range map[string]schema.EnumType{
+		"proposal_status": keeper.ProposalStatusEnum,
+		"proposal_type":   keeper.ProposalTypeEnum,
+		"vote_option":     keeper.VoteOptionEnum,
+	} {
+		typ, ok := cdc.Schema.LookupType(name)
+		require.True(t, ok, name)
+		require.Equal(t, enum, typ)
+	}
+}
@@ -0,0 +1,497 @@
+package keeper
+
+import (
+	"fmt"
+
+	groupv1 "cosmossdk.io/api/cosmos/group/v1"
+	"cosmossdk.io/core/address"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/x/group"
+	"cosmossdk.io/x/group/internal/orm"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/schemavalue"
+)
+
+// Object types of the module codec of group.
+const (
+	GroupsObjectType        = "groups"
+	GroupMembersObjectType  = "group_members"
+	GroupPoliciesObjectType = "group_policies"
+	ProposalsObjectType     = "proposals"
+	VotesObjectType         = "votes"
+)
+
+var (
+	// ProposalStatusEnum is the schema enum type of group.ProposalStatus.
+	ProposalStatusEnum = schemavalue.EnumType("proposal_status", groupv1.ProposalStatus(0).Descriptor())
+
+	// ProposalExecutorResultEnum is the schema enum type of group.ProposalExecutorResult.
+	ProposalExecutorResultEnum = schemavalue.EnumType("proposal_executor_result", groupv1.ProposalExecutorResult(0).Descriptor())
+
+	// VoteOptionEnum is the schema enum type of group.VoteOption.
+	VoteOptionEnum = schemavalue.EnumType("vote_option", groupv1.VoteOption(0).Descriptor())
+)
+
+var tallyResultStruct = schema.StructType{
+	Name: "tally_result",
+	Fields: []schema.Field{
+		{Name: "yes_count", Kind: schema.DecimalStringKind},
+		{Name: "abstain_count", Kind: schema.DecimalStringKind},
+		{Name: "no_count", Kind: schema.DecimalStringKind},
+		{Name: "no_with_veto_count", Kind: schema.DecimalStringKind},
+	},
+}
+
+// tableCodec converts the rows of an orm table to the object updates of an object type. Rows are
+// stored under the two bytes prefix of their table followed by their primary key, see orm. The ids
+// and addresses which rows repeat from their primary key are only exported as key fields, and are
+// restored from the key when encoding rows.
+type tableCodec struct {
+	prefix      byte
+	objectType  schema.ObjectType
+	decodeKey   func(key []byte) (interface{}, error)
+	encodeKey   func(key interface{}) ([]byte, error)
+	decodeValue func(cdc codec.Codec, bz []byte) (interface{}, error)
+	encodeValue func(cdc codec.Codec, ac address.Codec, key, value interface{}) ([]byte, error)
+}
+
+// tableCodecs returns the codecs of the tables of the module. The indexes and sequences of the
+// tables are derived from their rows and aren't exported.
+func tableCodecs() []tableCodec {
+	return []tableCodec{
+		{
+			prefix: GroupTablePrefix,
+			objectType: schema.ObjectType{
+				Name:      GroupsObjectType,
+				KeyFields: []schema.Field{{Name: "group", Kind: schema.Uint64Kind}},
+				ValueFields: []schema.Field{
+					{Name: "admin", Kind: schema.StringKind},
+					{Name: "metadata", Kind: schema.StringKind},
+					{Name: "version", Kind: schema.Uint64Kind},
+					{Name: "total_weight", Kind: schema.DecimalStringKind},
+					{Name: "created_at", Kind: schema.TimeKind},
+				},
+			},
+			decodeKey: decodeUint64Key,
+			encodeKey: encodeUint64Key,
+			decodeValue: func(cdc codec.Codec, bz []byte) (interface{}, error) {
+				var g group.GroupInfo
+				if err := cdc.Unmarshal(bz, &g); err != nil {
+					return nil, err
+				}
+				return []interface{}{g.Admin, g.Metadata, g.Version, g.TotalWeight, g.CreatedAt}, nil
+			},
+			encodeValue: func(cdc codec.Codec, _ address.Codec, key, value interface{}) ([]byte, error) {
+				id, err := readUint64Key(key)
+				if err != nil {
+					return nil, err
+				}
+				r, err := schemavalue.NewReader(value, 5)
+				if err != nil {
+					return nil, err
+				}
+				g := group.GroupInfo{
+					Id:          id,
+					Admin:       r.ReadString(),
+					Metadata:    r.ReadString(),
+					Version:     r.ReadUint64(),
+					TotalWeight: r.ReadString(),
+					CreatedAt:   r.ReadTime(),
+				}
+				if err := r.Err(); err != nil {
+					return nil, err
+				}
+				return cdc.Marshal(&g)
+			},
+		},
+		{
+			prefix: GroupMemberTablePrefix,
+			objectType: schema.ObjectType{
+				Name: GroupMembersObjectType,
+				KeyFields: []schema.Field{
+					{Name: "group", Kind: schema.Uint64Kind},
+					{Name: "account", Kind: schema.AddressKind},
+				},
+				ValueFields: []schema.Field{
+					{Name: "weight", Kind: schema.DecimalStringKind},
+					{Name: "metadata", Kind: schema.StringKind},
+					{Name: "added_at", Kind: schema.TimeKind},
+				},
+			},
+			decodeKey: decodeUint64AddressKey,
+			encodeKey: encodeUint64AddressKey,
+			decodeValue: func(cdc codec.Codec, bz []byte) (interface{}, error) {
+				var m group.GroupMember
+				if err := cdc.Unmarshal(bz, &m); err != nil {
+					return nil, err
+				}
+				if m.Member == nil {
+					return nil, fmt.Errorf("group member of group %d without member", m.GroupId)
+				}
+				return []interface{}{m.Member.Weight, m.Member.Metadata, m.Member.AddedAt}, nil
+			},
+			encodeValue: func(cdc codec.Codec, ac address.Codec, key, value interface{}) ([]byte, error) {
+				groupID, member, err := readUint64AddressKey(ac, key)
+				if err != nil {
+					return nil, err
+				}
+				r, err := schemavalue.NewReader(value, 3)
+				if err != nil {
+					return nil, err
+				}
+				m := group.GroupMember{
+					GroupId: groupID,
+					Member: &group.Member{
+						Address:  member,
+						Weight:   r.ReadString(),
+						Metadata: r.ReadString(),
+						AddedAt:  r.ReadTime(),
+					},
+				}
+				if err := r.Err(); err != nil {
+					return nil, err
+				}
+				return cdc.Marshal(&m)
+			},
+		},
+		{
+			prefix: GroupPolicyTablePrefix,
+			objectType: schema.ObjectType{
+				Name:      GroupPoliciesObjectType,
+				KeyFields: []schema.Field{{Name: "group_policy", Kind: schema.AddressKind}},
+				ValueFields: []schema.Field{
+					{Name: "group_id", Kind: schema.Uint64Kind},
+					{Name: "admin", Kind: schema.StringKind},
+					{Name: "metadata", Kind: schema.StringKind},
+					{Name: "version", Kind: schema.Uint64Kind},
+					{Name: "decision_policy", Kind: schema.StructKind, StructType: schemavalue.AnyStruct, Nullable: true},
+					{Name: "created_at", Kind: schema.TimeKind},
+				},
+			},
+			decodeKey: decodeAddressKey,
+			encodeKey: encodeAddressKey,
+			decodeValue: func(cdc codec.Codec, bz []byte) (interface{}, error) {
+				var p group.GroupPolicyInfo
+				if err := cdc.Unmarshal(bz, &p); err != nil {
+					return nil, err
+				}
+				return []interface{}{p.GroupId, p.Admin, p.Metadata, p.Version, schemavalue.AnyValue(p.DecisionPolicy), p.CreatedAt}, nil
+			},
+			encodeValue: func(cdc codec.Codec, ac address.Codec, key, value interface{}) ([]byte, error) {
+				policy, err := readAddressKey(ac, key)
+				if err != nil {
+					return nil, err
+				}
+				r, err := schemavalue.NewReader(value, 6)
+				if err != nil {
+					return nil, err
+				}
+				p := group.GroupPolicyInfo{
+					Address:        policy,
+					GroupId:        r.ReadUint64(),
+					Admin:          r.ReadString(),
+					Metadata:       r.ReadString(),
+					Version:        r.ReadUint64(),
+					DecisionPolicy: r.ReadAny(),
+					CreatedAt:      r.ReadTime(),
+				}
+				if err := r.Err(); err != nil {
+					return nil, err
+				}
+				return cdc.Marshal(&p)
+			},
+		},
+		{
+			prefix: ProposalTablePrefix,
+			objectType: schema.ObjectType{
+				Name:      ProposalsObjectType,
+				KeyFields: []schema.Field{{Name: "proposal", Kind: schema.Uint64Kind}},
+				ValueFields: []schema.Field{
+					{Name: "group_policy_address", Kind: schema.StringKind},
+					{Name: "metadata", Kind: schema.StringKind},
+					{Name: "proposers", Kind: schema.ListKind, ElementKind: schema.StringKind},
+					{Name: "submit_time", Kind: schema.TimeKind},
+					{Name: "group_version", Kind: schema.Uint64Kind},
+					{Name: "group_policy_version", Kind: schema.Uint64Kind},
+					{Name: "status", Kind: schema.EnumKind, EnumType: ProposalStatusEnum},
+					{Name: "final_tally_result", Kind: schema.StructKind, StructType: tallyResultStruct},
+					{Name: "voting_period_end", Kind: schema.TimeKind},
+					{Name: "executor_result", Kind: schema.EnumKind, EnumType: ProposalExecutorResultEnum},
+					{Name: "messages", Kind: schema.ListKind, ElementKind: schema.StructKind, StructType: schemavalue.AnyStruct},
+					{Name: "title", Kind: schema.StringKind},
+					{Name: "summary", Kind: schema.StringKind},
+				},
+			},
+			decodeKey: decodeUint64Key,
+			encodeKey: encodeUint64Key,
+			decodeValue: func(cdc codec.Codec, bz []byte) (interface{}, error) {
+				var p group.Proposal
+				if err := cdc.Unmarshal(bz, &p); err != nil {
+					return nil, err
+				}
+				proposers := make([]interface{}, len(p.Proposers))
+				for i, proposer := range p.Proposers {
+					proposers[i] = proposer
+				}
+				t := p.FinalTallyResult
+				return []interface{}{
+					p.GroupPolicyAddress,
+					p.Metadata,
+					proposers,
+					p.SubmitTime,
+					p.GroupVersion,
+					p.GroupPolicyVersion,
+					p.Status.String(),
+					[]interface{}{t.YesCount, t.AbstainCount, t.NoCount, t.NoWithVetoCount},
+					p.VotingPeriodEnd,
+					p.ExecutorResult.String(),
+					schemavalue.AnysValue(p.Messages),
+					p.Title,
+					p.Summary,
+				}, nil
+			},
+			encodeValue: func(cdc codec.Codec, _ address.Codec, key, value interface{}) ([]byte, error) {
+				id, err := readUint64Key(key)
+				if err != nil {
+					return nil, err
+				}
+				r, err := schemavalue.NewReader(value, 13)
+				if err != nil {
+					return nil, err
+				}
+				p := group.Proposal{
+					Id:                 id,
+					GroupPolicyAddress: r.ReadString(),
+					Metadata:           r.ReadString(),
+				}
+				for l := r.ReadList(); l.More(); {
+					p.Proposers = append(p.Proposers, l.ReadString())
+				}
+				p.SubmitTime = r.ReadTime()
+				p.GroupVersion = r.ReadUint64()
+				p.GroupPolicyVersion = r.ReadUint64()
+				p.Status = group.ProposalStatus(r.ReadEnum(ProposalStatusEnum))
+				t := r.ReadStruct(len(tallyResultStruct.Fields))
+				p.FinalTallyResult = group.TallyResult{
+					YesCount:        t.ReadString(),
+					AbstainCount:    t.ReadString(),
+					NoCount:         t.ReadString(),
+					NoWithVetoCount: t.ReadString(),
+				}
+				p.VotingPeriodEnd = r.ReadTime()
+				p.ExecutorResult = group.ProposalExecutorResult(r.ReadEnum(ProposalExecutorResultEnum))
+				p.Messages = r.ReadAnys()
+				p.Title = r.ReadString()
+				p.Summary = r.ReadString()
+				if err := r.Err(); err != nil {
+					return nil, err
+				}
+				return cdc.Marshal(&p)
+			},
+		},
+		{
+			prefix: VoteTablePrefix,
+			objectType: schema.ObjectType{
+				Name: VotesObjectType,
+				KeyFields: []schema.Field{
+					{Name: "proposal", Kind: schema.Uint64Kind},
+					{Name: "account", Kind: schema.AddressKind},
+				},
+				ValueFields: []schema.Field{
+					{Name: "option", Kind: schema.EnumKind, EnumType: VoteOptionEnum},
+					{Name: "metadata", Kind: schema.StringKind},
+					{Name: "submit_time", Kind: schema.TimeKind},
+				},
+			},
+			decodeKey: decodeUint64AddressKey,
+			encodeKey: encodeUint64AddressKey,
+			decodeValue: func(cdc codec.Codec, bz []byte) (interface{}, error) {
+				var v group.Vote
+				if err := cdc.Unmarshal(bz, &v); err != nil {
+					return nil, err
+				}
+				return []interface{}{v.Option.String(), v.Metadata, v.SubmitTime}, nil
+			},
+			encodeValue: func(cdc codec.Codec, ac address.Codec, key, value interface{}) ([]byte, error) {
+				proposalID, voter, err := readUint64AddressKey(ac, key)
+				if err != nil {
+					return nil, err
+				}
+				r, err := schemavalue.NewReader(value, 3)
+				if err != nil {
+					return nil, err
+				}
+				v := group.Vote{
+					ProposalId: proposalID,
+					Voter:      voter,
+					Option:     group.VoteOption(r.ReadEnum(VoteOptionEnum)),
+					Metadata:   r.ReadString(),
+					SubmitTime: r.ReadTime(),
+				}
+				if err := r.Err(); err != nil {
+					return nil, err
+				}
+				return cdc.Marshal(&v)
+			},
+		},
+	}
+}
+
+// ModuleCodec returns the schema.ModuleCodec of the module. As the module stores its state with
+// orm tables rather than collections, the rows of its groups, group members, group policies,
+// proposals and votes tables are decoded and encoded by hand here.
+func (k Keeper) ModuleCodec() (schema.ModuleCodec, error) {
+	tables := tableCodecs()
+	objectTypes := make([]schema.ObjectType, len(tables))
+	byPrefix := make(map[byte]tableCodec, len(tables))
+	byName := make(map[string]tableCodec, len(tables))
+	for i, t := range tables {
+		objectTypes[i] = t.objectType
+		byPrefix[t.prefix] = t
+		byName[t.objectType.Name] = t
+	}
+	moduleSchema, err := schema.NewModuleSchema(objectTypes)
+	if err != nil {
+		return schema.ModuleCodec{}, err
+	}
+
+	return schema.ModuleCodec{
+		Schema: moduleSchema,
+		KVDecoder: func(update schema.KVPairUpdate) ([]schema.ObjectUpdate, error) {
+			if len(update.Key) < 2 || update.Key[1] != 0 {
+				return nil, nil
+			}
+			t, ok := byPrefix[update.Key[0]]
+			if !ok {
+				return nil, nil
+			}
+			key, err := t.decodeKey(update.Key[2:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s key %X: %w", t.objectType.Name, update.Key, err)
+			}
+			objectUpdate := schema.ObjectUpdate{TypeName: t.objectType.Name, Key: key, Delete: update.Delete}
+			if !update.Delete {
+				if objectUpdate.Value, err = t.decodeValue(k.cdc, update.Value); err != nil {
+					return nil, err
+				}
+			}
+			return []schema.ObjectUpdate{objectUpdate}, nil
+		},
+		KVEncoder: func(update schema.ObjectUpdate) ([]schema.KVPairUpdate, error) {
+			t, ok := byName[update.TypeName]
+			if !ok {
+				return nil, fmt.Errorf("unknown object type %q", update.TypeName)
+			}
+			key, err := t.encodeKey(update.Key)
+			if err != nil {
+				return nil, err
+			}
+			pair := schema.KVPairUpdate{Key: append([]byte{t.prefix, 0}, key...), Delete: update.Delete}
+			if !update.Delete {
+				if pair.Value, err = t.encodeValue(k.cdc, k.accKeeper.AddressCodec(), update.Key, update.Value); err != nil {
+					return nil, err
+				}
+			}
+			return []schema.KVPairUpdate{pair}, nil
+		},
+	}, nil
+}
+
+// decodeUint64Key decodes the primary key of the rows of orm.AutoUInt64Table tables.
+func decodeUint64Key(key []byte) (interface{}, error) {
+	if len(key) != orm.EncodedSeqLength {
+		return nil, fmt.Errorf("expected %d bytes, got %d", orm.EncodedSeqLength, len(key))
+	}
+	return orm.DecodeSequence(key), nil
+}
+
+func encodeUint64Key(key interface{}) ([]byte, error) {
+	id, err := readUint64Key(key)
+	if err != nil {
+		return nil, err
+	}
+	return orm.EncodeSequence(id), nil
+}
+
+// readUint64Key reads the id of a primary key of a single id.
+func readUint64Key(key interface{}) (uint64, error) {
+	r, err := schemavalue.NewReader([]interface{}{key}, 1)
+	if err != nil {
+		return 0, err
+	}
+	id := r.ReadUint64()
+	return id, r.Err()
+}
+
+// decodeAddressKey decodes a primary key of a single address, which is length prefixed.
+func decodeAddressKey(key []byte) (interface{}, error) {
+	if len(key) == 0 || len(key) != 1+int(key[0]) {
+		return nil, fmt.Errorf("invalid length prefixed address")
+	}
+	return key[1:], nil
+}
+
+// readAddressKey reads the address of a primary key of a single address as a string.
+func readAddressKey(ac address.Codec, key interface{}) (string, error) {
+	r, err := schemavalue.NewReader([]interface{}{key}, 1)
+	if err != nil {
+		return "", err
+	}
+	addr := r.ReadBytes()
+	if err := r.Err(); err != nil {
+		return "", err
+	}
+	return ac.BytesToString(addr)
+}
+
+func encodeAddressKey(key interface{}) ([]byte, error) {
+	r, err := schemavalue.NewReader([]interface{}{key}, 1)
+	if err != nil {
+		return nil, err
+	}
+	addr := r.ReadBytes()
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	if len(addr) > orm.MaxBytesLen {
+		return nil, fmt.Errorf("address of %d bytes is too long", len(addr))
+	}
+	return orm.AddLengthPrefix(addr), nil
+}
+
+// decodeUint64AddressKey decodes a primary key of an id followed by an address, which as the last
+// part of the key isn't length prefixed.
+func decodeUint64AddressKey(key []byte) (interface{}, error) {
+	if len(key) < orm.EncodedSeqLength {
+		return nil, fmt.Errorf("expected at least %d bytes, got %d", orm.EncodedSeqLength, len(key))
+	}
+	return []interface{}{orm.DecodeSequence(key[:orm.EncodedSeqLength]), key[orm.EncodedSeqLength:]}, nil
+}
+
+func encodeUint64AddressKey(key interface{}) ([]byte, error) {
+	r, err := schemavalue.NewReader(key, 2)
+	if err != nil {
+		return nil, err
+	}
+	id, addr := r.ReadUint64(), r.ReadBytes()
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	return append(orm.EncodeSequence(id), addr...), nil
+}
+
+// readUint64AddressKey reads the id and the address of a primary key of an id followed by an
+// address, with the address as a string.
+func readUint64AddressKey(ac address.Codec, key interface{}) (uint64, string, error) {
+	r, err := schemavalue.NewReader(key, 2)
+	if err != nil {
+		return 0, "", err
+	}
+	id, addr := r.ReadUint64(), r.ReadBytes()
+	if err := r.Err(); err != nil {
+		return 0, "", err
+	}
+	addrStr, err := ac.BytesToString(addr)
+	return id, addrStr, err
+}
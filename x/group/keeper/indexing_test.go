@@ -0,0 +1,74 @@
+package keeper_test
+
+import (
+	"cosmossdk.io/schema"
+	"cosmossdk.io/x/group"
+	"cosmossdk.io/x/group/keeper"
+
+	"github.com/cosmos/cosmos-sdk/testutil"
+)
+
+func (s *TestSuite) TestModuleCodec() {
+	require := s.Require()
+
+	proposalRes, err := s.groupKeeper.SubmitProposal(s.ctx, &group.MsgSubmitProposal{
+		GroupPolicyAddress: s.groupPolicyStrAddr,
+		Proposers:          []string{s.addrsStr[4]},
+		Title:              "title",
+	})
+	require.NoError(err)
+	_, err = s.groupKeeper.Vote(s.ctx, &group.MsgVote{
+		ProposalId: proposalRes.ProposalId,
+		Voter:      s.addrsStr[1],
+		Option:     group.VOTE_OPTION_NO,
+	})
+	require.NoError(err)
+
+	cdc, err := s.groupKeeper.ModuleCodec()
+	require.NoError(err)
+
+	// every row of the module decodes to valid object updates which encode back to the same kv-pair
+	decoded := testutil.DecodeModuleState(s.T(), cdc, s.groupKeeper.KVStoreService.OpenKVStore(s.ctx))
+
+	groups := decoded[keeper.GroupsObjectType]
+	require.Len(groups, 1)
+	require.Equal(s.groupID, groups[0].Key)
+	require.Equal("3", groups[0].Value.([]interface{})[3])
+
+	members := decoded[keeper.GroupMembersObjectType]
+	require.Len(members, 2)
+	require.Equal(s.groupID, members[0].Key.([]interface{})[0])
+	require.Len(members[0].Value, 3)
+
+	policies := decoded[keeper.GroupPoliciesObjectType]
+	require.Len(policies, 1)
+	require.Equal([]byte(s.groupPolicyAddr), policies[0].Key)
+	require.Equal("/cosmos.group.v1.ThresholdDecisionPolicy", policies[0].Value.([]interface{})[4].([]interface{})[0])
+
+	proposals := decoded[keeper.ProposalsObjectType]
+	require.Len(proposals, 1)
+	proposal := proposals[0].Value.([]interface{})
+	require.Equal([]interface{}{s.addrsStr[4]}, proposal[2])
+	require.Equal("PROPOSAL_STATUS_SUBMITTED", proposal[6])
+	require.Equal("title", proposal[11])
+
+	votes := decoded[keeper.VotesObjectType]
+	require.Len(votes, 1)
+	require.Equal([]interface{}{proposalRes.ProposalId, []byte(s.addrs[1])}, votes[0].Key)
+	require.Equal("VOTE_OPTION_NO", votes[0].Value.([]interface{})[0])
+
+	// deletions are encoded and decoded without values
+	deletion := schema.ObjectUpdate{TypeName: keeper.VotesObjectType, Key: votes[0].Key, Delete: true}
+	pairs, err := cdc.KVEncoder(deletion)
+	require.NoError(err)
+	require.Len(pairs, 1)
+	require.True(pairs[0].Delete)
+	updates, err := cdc.KVDecoder(pairs[0])
+	require.NoError(err)
+	require.Equal([]schema.ObjectUpdate{deletion}, updates)
+
+	// index and sequence keys are ignored
+	updates, err = cdc.KVDecoder(schema.KVPairUpdate{Key: []byte{keeper.GroupTableSeqPrefix}})
+	require.NoError(err)
+	require.Empty(updates)
+}
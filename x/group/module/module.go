@@ -12,6 +12,7 @@ import (
 	"cosmossdk.io/core/appmodule"
 	"cosmossdk.io/core/legacy"
 	"cosmossdk.io/core/registry"
+	"cosmossdk.io/schema"
 	"cosmossdk.io/x/group"
 	"cosmossdk.io/x/group/client/cli"
 	"cosmossdk.io/x/group/keeper"
@@ -40,6 +41,8 @@ var (
 	_ appmodule.HasMigrations         = AppModule{}
 	_ appmodule.HasRegisterInterfaces = AppModule{}
 	_ appmodule.HasGenesis            = AppModule{}
+
+	_ schema.HasModuleCodec = AppModule{}
 )
 
 type AppModule struct {
@@ -124,6 +127,12 @@ func (am AppModule) EndBlock(ctx context.Context) error {
 	return am.keeper.EndBlocker(ctx)
 }
 
+// ModuleCodec implements schema.HasModuleCodec. The rows of the groups, group members, group
+// policies, proposals and votes tables are exported with structured fields.
+func (am AppModule) ModuleCodec() (schema.ModuleCodec, error) {
+	return am.keeper.ModuleCodec()
+}
+
 // DefaultGenesis returns default genesis state as raw bytes for the group module.
 func (am AppModule) DefaultGenesis() json.RawMessage {
 	return am.cdc.MustMarshalJSON(group.NewGenesisState())
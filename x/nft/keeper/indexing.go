@@ -0,0 +1,258 @@
+package keeper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/x/nft"
+
+	"github.com/cosmos/cosmos-sdk/codec/schemavalue"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Object types of the module codec of nft.
+const (
+	ClassesObjectType          = "classes"
+	NFTsObjectType             = "nfts"
+	OwnersObjectType           = "owners"
+	ClassTotalSupplyObjectType = "class_total_supply"
+)
+
+// ModuleCodec returns the schema.ModuleCodec of the module. As the module doesn't store its state
+// with collections, its key-value pairs are decoded and encoded by hand here:
+//
+//   - classes are keyed by class id and have the other fields of the class, with its data as an any struct,
+//   - nfts are keyed by class id and nft id and have the other fields of the nft,
+//   - owners are keyed by class id and nft id and have the address of the owner of the nft,
+//   - class_total_supply entries are keyed by class id and have the number of nfts of the class.
+//
+// The index of the nfts of a class by owner is derived from the owners and isn't exported.
+func (k Keeper) ModuleCodec() (schema.ModuleCodec, error) {
+	moduleSchema, err := schema.NewModuleSchema([]schema.ObjectType{
+		{
+			Name:      ClassesObjectType,
+			KeyFields: []schema.Field{{Name: "class", Kind: schema.StringKind}},
+			ValueFields: []schema.Field{
+				{Name: "name", Kind: schema.StringKind},
+				{Name: "symbol", Kind: schema.StringKind},
+				{Name: "description", Kind: schema.StringKind},
+				{Name: "uri", Kind: schema.StringKind},
+				{Name: "uri_hash", Kind: schema.StringKind},
+				{Name: "data", Kind: schema.StructKind, StructType: schemavalue.AnyStruct, Nullable: true},
+			},
+		},
+		{
+			Name: NFTsObjectType,
+			KeyFields: []schema.Field{
+				{Name: "class", Kind: schema.StringKind},
+				{Name: "nft", Kind: schema.StringKind},
+			},
+			ValueFields: []schema.Field{
+				{Name: "uri", Kind: schema.StringKind},
+				{Name: "uri_hash", Kind: schema.StringKind},
+				{Name: "data", Kind: schema.StructKind, StructType: schemavalue.AnyStruct, Nullable: true},
+			},
+		},
+		{
+			Name: OwnersObjectType,
+			KeyFields: []schema.Field{
+				{Name: "class", Kind: schema.StringKind},
+				{Name: "nft", Kind: schema.StringKind},
+			},
+			ValueFields: []schema.Field{{Name: "owner", Kind: schema.AddressKind}},
+		},
+		{
+			Name:        ClassTotalSupplyObjectType,
+			KeyFields:   []schema.Field{{Name: "class", Kind: schema.StringKind}},
+			ValueFields: []schema.Field{{Name: "total_supply", Kind: schema.Uint64Kind}},
+		},
+	})
+	if err != nil {
+		return schema.ModuleCodec{}, err
+	}
+	return schema.ModuleCodec{
+		Schema:    moduleSchema,
+		KVDecoder: k.decodeKV,
+		KVEncoder: k.encodeObjectUpdate,
+	}, nil
+}
+
+func (k Keeper) decodeKV(update schema.KVPairUpdate) ([]schema.ObjectUpdate, error) {
+	if len(update.Key) == 0 {
+		return nil, nil
+	}
+	var (
+		typeName string
+		key      interface{}
+		value    interface{}
+	)
+	prefix, rest := update.Key[:1], update.Key[1:]
+	switch {
+	case bytes.Equal(prefix, ClassKey):
+		typeName, key = ClassesObjectType, string(rest)
+		if !update.Delete {
+			var class nft.Class
+			if err := k.cdc.Unmarshal(update.Value, &class); err != nil {
+				return nil, err
+			}
+			value = []interface{}{class.Name, class.Symbol, class.Description, class.Uri, class.UriHash, schemavalue.AnyValue(class.Data)}
+		}
+	case bytes.Equal(prefix, NFTKey):
+		classID, nftID, err := splitClassAndNFT(rest)
+		if err != nil {
+			return nil, err
+		}
+		typeName, key = NFTsObjectType, []interface{}{classID, nftID}
+		if !update.Delete {
+			var token nft.NFT
+			if err := k.cdc.Unmarshal(update.Value, &token); err != nil {
+				return nil, err
+			}
+			value = []interface{}{token.Uri, token.UriHash, schemavalue.AnyValue(token.Data)}
+		}
+	case bytes.Equal(prefix, OwnerKey):
+		classID, nftID, err := splitClassAndNFT(rest)
+		if err != nil {
+			return nil, err
+		}
+		typeName, key = OwnersObjectType, []interface{}{classID, nftID}
+		if !update.Delete {
+			value = update.Value
+		}
+	case bytes.Equal(prefix, ClassTotalSupply):
+		typeName, key = ClassTotalSupplyObjectType, string(rest)
+		if !update.Delete {
+			if len(update.Value) != 8 {
+				return nil, fmt.Errorf("invalid total supply of class %s: expected 8 bytes, got %d", rest, len(update.Value))
+			}
+			value = binary.BigEndian.Uint64(update.Value)
+		}
+	default:
+		return nil, nil
+	}
+	return []schema.ObjectUpdate{{TypeName: typeName, Key: key, Value: value, Delete: update.Delete}}, nil
+}
+
+func (k Keeper) encodeObjectUpdate(update schema.ObjectUpdate) ([]schema.KVPairUpdate, error) {
+	var (
+		key   []byte
+		value []byte
+	)
+	switch update.TypeName {
+	case ClassesObjectType:
+		r, err := schemavalue.NewReader([]interface{}{update.Key}, 1)
+		if err != nil {
+			return nil, err
+		}
+		classID := r.ReadString()
+		if err := r.Err(); err != nil {
+			return nil, err
+		}
+		key = classStoreKey(classID)
+		if !update.Delete {
+			r, err := schemavalue.NewReader(update.Value, 6)
+			if err != nil {
+				return nil, err
+			}
+			class := nft.Class{
+				Id:          classID,
+				Name:        r.ReadString(),
+				Symbol:      r.ReadString(),
+				Description: r.ReadString(),
+				Uri:         r.ReadString(),
+				UriHash:     r.ReadString(),
+				Data:        r.ReadAny(),
+			}
+			if err := r.Err(); err != nil {
+				return nil, err
+			}
+			if value, err = k.cdc.Marshal(&class); err != nil {
+				return nil, err
+			}
+		}
+	case NFTsObjectType:
+		r, err := schemavalue.NewReader(update.Key, 2)
+		if err != nil {
+			return nil, err
+		}
+		classID, nftID := r.ReadString(), r.ReadString()
+		if err := r.Err(); err != nil {
+			return nil, err
+		}
+		key = append(nftStoreKey(classID), nftID...)
+		if !update.Delete {
+			r, err := schemavalue.NewReader(update.Value, 3)
+			if err != nil {
+				return nil, err
+			}
+			token := nft.NFT{
+				ClassId: classID,
+				Id:      nftID,
+				Uri:     r.ReadString(),
+				UriHash: r.ReadString(),
+				Data:    r.ReadAny(),
+			}
+			if err := r.Err(); err != nil {
+				return nil, err
+			}
+			if value, err = k.cdc.Marshal(&token); err != nil {
+				return nil, err
+			}
+		}
+	case OwnersObjectType:
+		r, err := schemavalue.NewReader(update.Key, 2)
+		if err != nil {
+			return nil, err
+		}
+		classID, nftID := r.ReadString(), r.ReadString()
+		if err := r.Err(); err != nil {
+			return nil, err
+		}
+		key = ownerStoreKey(classID, nftID)
+		if !update.Delete {
+			r, err := schemavalue.NewReader([]interface{}{update.Value}, 1)
+			if err != nil {
+				return nil, err
+			}
+			value = r.ReadBytes()
+			if err := r.Err(); err != nil {
+				return nil, err
+			}
+		}
+	case ClassTotalSupplyObjectType:
+		r, err := schemavalue.NewReader([]interface{}{update.Key}, 1)
+		if err != nil {
+			return nil, err
+		}
+		classID := r.ReadString()
+		if err := r.Err(); err != nil {
+			return nil, err
+		}
+		key = classTotalSupply(classID)
+		if !update.Delete {
+			r, err := schemavalue.NewReader([]interface{}{update.Value}, 1)
+			if err != nil {
+				return nil, err
+			}
+			supply := r.ReadUint64()
+			if err := r.Err(); err != nil {
+				return nil, err
+			}
+			value = sdk.Uint64ToBigEndian(supply)
+		}
+	default:
+		return nil, fmt.Errorf("unknown object type %q", update.TypeName)
+	}
+	return []schema.KVPairUpdate{{Key: key, Value: value, Delete: update.Delete}}, nil
+}
+
+// splitClassAndNFT splits the <classID><Delimiter><nftID> suffix of nft and owner keys.
+func splitClassAndNFT(bz []byte) (classID, nftID string, err error) {
+	i := bytes.Index(bz, Delimiter)
+	if i < 0 {
+		return "", "", fmt.Errorf("invalid nft key %X: missing delimiter", bz)
+	}
+	return string(bz[:i]), string(bz[i+len(Delimiter):]), nil
+}
@@ -0,0 +1,41 @@
+package keeper_test
+
+import (
+	"cosmossdk.io/schema"
+	"cosmossdk.io/x/nft"
+	"cosmossdk.io/x/nft/keeper"
+
+	"github.com/cosmos/cosmos-sdk/testutil"
+)
+
+func (s *TestSuite) TestModuleCodec() {
+	require := s.Require()
+
+	require.NoError(s.nftKeeper.SaveClass(s.ctx, nft.Class{Id: testClassID, Name: "kitty", Symbol: "kitty"}))
+	require.NoError(s.nftKeeper.Mint(s.ctx, nft.NFT{ClassId: testClassID, Id: testID, Uri: "kitty.com"}, s.addrs[1]))
+
+	cdc, err := s.nftKeeper.ModuleCodec()
+	require.NoError(err)
+
+	// every kv-pair of the module decodes to valid object updates which encode back to the same kv-pair
+	decoded := testutil.DecodeModuleState(s.T(), cdc, s.nftKeeper.KVStoreService.OpenKVStore(s.ctx))
+	require.Len(decoded, 4)
+
+	require.Equal(testClassID, decoded[keeper.ClassesObjectType][0].Key)
+	require.Equal([]interface{}{"kitty", "kitty", "", "", "", nil}, decoded[keeper.ClassesObjectType][0].Value)
+	require.Equal([]interface{}{testClassID, testID}, decoded[keeper.NFTsObjectType][0].Key)
+	require.Equal([]interface{}{"kitty.com", "", nil}, decoded[keeper.NFTsObjectType][0].Value)
+	require.Equal([]interface{}{testClassID, testID}, decoded[keeper.OwnersObjectType][0].Key)
+	require.Equal([]byte(s.addrs[1]), decoded[keeper.OwnersObjectType][0].Value)
+	require.Equal(uint64(1), decoded[keeper.ClassTotalSupplyObjectType][0].Value)
+
+	// burning an nft deletes it and its owner
+	for _, typeName := range []string{keeper.NFTsObjectType, keeper.OwnersObjectType} {
+		deletion := schema.ObjectUpdate{TypeName: typeName, Key: []interface{}{testClassID, testID}, Delete: true}
+		pairs, err := cdc.KVEncoder(deletion)
+		require.NoError(err)
+		updates, err := cdc.KVDecoder(pairs[0])
+		require.NoError(err)
+		require.Equal([]schema.ObjectUpdate{deletion}, updates)
+	}
+}
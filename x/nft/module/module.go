@@ -9,6 +9,7 @@ import (
 
 	"cosmossdk.io/core/appmodule"
 	"cosmossdk.io/core/registry"
+	"cosmossdk.io/schema"
 	"cosmossdk.io/errors"
 	"cosmossdk.io/x/nft"
 	"cosmossdk.io/x/nft/keeper"
@@ -28,6 +29,8 @@ var (
 	_ appmodule.AppModule             = AppModule{}
 	_ appmodule.HasGenesis            = AppModule{}
 	_ appmodule.HasRegisterInterfaces = AppModule{}
+
+	_ schema.HasModuleCodec = AppModule{}
 )
 
 const ConsensusVersion = 1
@@ -82,6 +85,12 @@ func (AppModule) RegisterGRPCGatewayRoutes(clientCtx sdkclient.Context, mux *gwr
 	}
 }
 
+// ModuleCodec implements schema.HasModuleCodec. Classes, nfts, their owners and the total supply of
+// classes are exported with structured fields.
+func (am AppModule) ModuleCodec() (schema.ModuleCodec, error) {
+	return am.keeper.ModuleCodec()
+}
+
 // DefaultGenesis returns default genesis state as raw bytes for the nft module.
 func (am AppModule) DefaultGenesis() json.RawMessage {
 	return am.cdc.MustMarshalJSON(nft.DefaultGenesisState())
@@ -0,0 +1,113 @@
+package keeper
+
+import (
+	"fmt"
+
+	"github.com/bits-and-blooms/bitset"
+
+	"cosmossdk.io/collections"
+	collcodec "cosmossdk.io/collections/codec"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/x/slashing/types"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/schemavalue"
+)
+
+// The codecs in this file describe the signing info and the missed block bitmap of validators with
+// structured schema fields, so that uptime can be tracked from indexed state.
+
+func validatorSigningInfoValue(cdc codec.BinaryCodec, sk types.StakingKeeper) collcodec.ValueCodec[types.ValidatorSigningInfo] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[types.ValidatorSigningInfo](cdc), collcodec.SchemaCodec[types.ValidatorSigningInfo]{
+		Fields: []schema.Field{
+			{Name: "start_height", Kind: schema.Int64Kind},
+			{Name: "index_offset", Kind: schema.Int64Kind},
+			{Name: "jailed_until", Kind: schema.TimeKind},
+			{Name: "tombstoned", Kind: schema.BoolKind},
+			{Name: "missed_blocks_counter", Kind: schema.Int64Kind},
+		},
+		ToSchemaType: func(info types.ValidatorSigningInfo) (interface{}, error) {
+			return []interface{}{
+				info.StartHeight,
+				info.IndexOffset, //nolint:staticcheck // the deprecated index offset is still stored
+				info.JailedUntil,
+				info.Tombstoned,
+				info.MissedBlocksCounter,
+			}, nil
+		},
+		// the consensus address of the validator is the key of its signing info
+		FromKeyedSchemaType: func(key, value interface{}) (types.ValidatorSigningInfo, error) {
+			k, err := schemavalue.NewReader([]interface{}{key}, 1)
+			if err != nil {
+				return types.ValidatorSigningInfo{}, err
+			}
+			consAddr := k.ReadBytes()
+			if err := k.Err(); err != nil {
+				return types.ValidatorSigningInfo{}, err
+			}
+
+			r, err := schemavalue.NewReader(value, 5)
+			if err != nil {
+				return types.ValidatorSigningInfo{}, err
+			}
+			info := types.ValidatorSigningInfo{
+				StartHeight:         r.ReadInt64(),
+				IndexOffset:         r.ReadInt64(),
+				JailedUntil:         r.ReadTime(),
+				Tombstoned:          r.ReadBool(),
+				MissedBlocksCounter: r.ReadInt64(),
+			}
+			if err := r.Err(); err != nil {
+				return types.ValidatorSigningInfo{}, err
+			}
+
+			info.Address, err = sk.ConsensusAddressCodec().BytesToString(consAddr)
+			return info, err
+		},
+	})
+}
+
+// missedBlockBitmapChunkValue describes a chunk of the missed block bitmap compactly by the
+// length of the chunk in bits and the positions in the chunk of the blocks which were missed, rather
+// than by its binary encoding.
+func missedBlockBitmapChunkValue() collcodec.ValueCodec[[]byte] {
+	return collcodec.ValueCodecWithSchema(collections.BytesValue, collcodec.SchemaCodec[[]byte]{
+		Fields: []schema.Field{
+			{Name: "length", Kind: schema.Uint32Kind},
+			{Name: "missed", Kind: schema.ListKind, ElementKind: schema.Uint32Kind},
+		},
+		ToSchemaType: func(chunk []byte) (interface{}, error) {
+			bs := new(bitset.BitSet)
+			if err := bs.UnmarshalBinary(chunk); err != nil {
+				return nil, fmt.Errorf("%w: invalid missed block bitmap chunk: %w", collcodec.ErrEncoding, err)
+			}
+			missed := make([]interface{}, 0, bs.Count())
+			for i, ok := bs.NextSet(0); ok; i, ok = bs.NextSet(i + 1) {
+				missed = append(missed, uint32(i))
+			}
+			return []interface{}{uint32(bs.Len()), missed}, nil
+		},
+		FromSchemaType: func(value interface{}) ([]byte, error) {
+			r, err := schemavalue.NewReader(value, 2)
+			if err != nil {
+				return nil, err
+			}
+			length := r.ReadUint32()
+			if length > types.MissedBlockBitmapChunkSize {
+				return nil, fmt.Errorf("%w: chunk of %d blocks exceeds the chunk size", collcodec.ErrEncoding, length)
+			}
+			bs := bitset.New(uint(length))
+			for l := r.ReadList(); l.More(); {
+				i := uint(l.ReadUint32())
+				if i >= bs.Len() {
+					return nil, fmt.Errorf("%w: missed block %d out of the chunk of %d blocks", collcodec.ErrEncoding, i, bs.Len())
+				}
+				bs.Set(i)
+			}
+			if err := r.Err(); err != nil {
+				return nil, err
+			}
+			return bs.MarshalBinary()
+		},
+	})
+}
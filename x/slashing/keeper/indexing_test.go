@@ -0,0 +1,44 @@
+package keeper_test
+
+import (
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"cosmossdk.io/collections"
+	slashingtypes "cosmossdk.io/x/slashing/types"
+
+	"github.com/cosmos/cosmos-sdk/testutil"
+)
+
+func (s *KeeperTestSuite) TestModuleCodec() {
+	ctx, keeper := s.ctx, s.slashingKeeper
+	require := s.Require()
+
+	consStr, err := s.stakingKeeper.ConsensusAddressCodec().BytesToString(consAddr)
+	require.NoError(err)
+	jailedUntil := time.Unix(2, 0).UTC()
+	require.NoError(keeper.ValidatorSigningInfo.Set(ctx, consAddr, slashingtypes.NewValidatorSigningInfo(consStr, 3, jailedUntil, false, 2)))
+
+	s.stakingKeeper.EXPECT().ValidatorIdentifier(gomock.Any(), consAddr).Return(consAddr, nil).AnyTimes()
+	require.NoError(keeper.SetMissedBlockBitmapValue(ctx, consAddr, 3, true))
+	require.NoError(keeper.SetMissedBlockBitmapValue(ctx, consAddr, 1030, true))
+
+	cdc, err := keeper.Schema.ModuleCodec(collections.IndexingOptions{})
+	require.NoError(err)
+
+	// every kv-pair of the module decodes to valid object updates which encode back to the same kv-pair
+	decoded := testutil.DecodeModuleState(s.T(), cdc, keeper.KVStoreService.OpenKVStore(ctx))
+
+	signingInfo := decoded["validator_signing_info"]
+	require.Len(signingInfo, 1)
+	require.Equal([]byte(consAddr), signingInfo[0].Key)
+	require.Equal([]interface{}{int64(3), int64(0), jailedUntil, false, int64(2)}, signingInfo[0].Value)
+
+	bitmap := decoded["validator_missed_block_bitmap"]
+	require.Len(bitmap, 2)
+	require.Equal([]interface{}{[]byte(consAddr), uint64(0)}, bitmap[0].Key)
+	require.Equal([]interface{}{uint32(slashingtypes.MissedBlockBitmapChunkSize), []interface{}{uint32(3)}}, bitmap[0].Value)
+	require.Equal([]interface{}{[]byte(consAddr), uint64(1)}, bitmap[1].Key)
+	require.Equal([]interface{}{uint32(slashingtypes.MissedBlockBitmapChunkSize), []interface{}{uint32(6)}}, bitmap[1].Value)
+}
@@ -6,12 +6,14 @@ import (
 
 	st "cosmossdk.io/api/cosmos/staking/v1beta1"
 	"cosmossdk.io/collections"
+	collcodec "cosmossdk.io/collections/codec"
 	"cosmossdk.io/core/appmodule"
 	"cosmossdk.io/core/event"
 	sdkmath "cosmossdk.io/math"
 	"cosmossdk.io/x/slashing/types"
 
 	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/schemavalue"
 	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
@@ -47,13 +49,13 @@ func NewKeeper(environment appmodule.Environment, cdc codec.BinaryCodec, legacyA
 		legacyAmino: legacyAmino,
 		sk:          sk,
 		authority:   authority,
-		Params:      collections.NewItem(sb, types.ParamsKey, "params", codec.CollValue[types.Params](cdc)),
+		Params:      collections.NewItem(sb, types.ParamsKey, "params", collcodec.NamedValueCodec(codec.CollValue[types.Params](cdc), "params")),
 		ValidatorSigningInfo: collections.NewMap(
 			sb,
 			types.ValidatorSigningInfoKeyPrefix,
 			"validator_signing_info",
-			sdk.LengthPrefixedAddressKey(sdk.ConsAddressKey), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
-			codec.CollValue[types.ValidatorSigningInfo](cdc),
+			collcodec.NamedKeyCodec(sdk.LengthPrefixedAddressKey(sdk.ConsAddressKey), "address"), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
+			validatorSigningInfoValue(cdc, sk),
 		),
 		AddrPubkeyRelation: collections.NewMap(
 			sb,
@@ -66,8 +68,8 @@ func NewKeeper(environment appmodule.Environment, cdc codec.BinaryCodec, legacyA
 			sb,
 			types.ValidatorMissedBlockBitmapKeyPrefix,
 			"validator_missed_block_bitmap",
-			collections.PairKeyCodec(sdk.LengthPrefixedBytesKey, collections.Uint64Key),
-			collections.BytesValue,
+			collcodec.NamedKeyCodec(collections.PairKeyCodec(schemavalue.AddressBytesKey(sdk.LengthPrefixedBytesKey, "address"), collections.Uint64Key), "address", "chunk"),
+			missedBlockBitmapChunkValue(),
 		),
 	}
 
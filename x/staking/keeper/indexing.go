@@ -0,0 +1,273 @@
+package keeper
+
+import (
+	stakingv1beta1 "cosmossdk.io/api/cosmos/staking/v1beta1"
+	collcodec "cosmossdk.io/collections/codec"
+	"cosmossdk.io/math"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/x/staking/types"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/schemavalue"
+)
+
+// The codecs in this file describe the validators, delegations, unbonding delegations and redelegations
+// of the module with structured schema fields, so that they are exported to indexers as queryable
+// columns rather than as JSON. The values keep every field of their protobuf messages so that the
+// object updates can be encoded back to the same state.
+
+// BondStatusEnum is the schema enum type of types.BondStatus, whose values are the names of the
+// protobuf enum values.
+var BondStatusEnum = schemavalue.EnumType("bond_status", stakingv1beta1.BondStatus(0).Descriptor())
+
+var (
+	descriptionStruct = schema.StructType{
+		Name: "description",
+		Fields: []schema.Field{
+			{Name: "moniker", Kind: schema.StringKind},
+			{Name: "identity", Kind: schema.StringKind},
+			{Name: "website", Kind: schema.StringKind},
+			{Name: "security_contact", Kind: schema.StringKind},
+			{Name: "details", Kind: schema.StringKind},
+		},
+	}
+
+	commissionStruct = schema.StructType{
+		Name: "commission",
+		Fields: []schema.Field{
+			{Name: "rate", Kind: schema.DecimalStringKind},
+			{Name: "max_rate", Kind: schema.DecimalStringKind},
+			{Name: "max_change_rate", Kind: schema.DecimalStringKind},
+			{Name: "update_time", Kind: schema.TimeKind},
+		},
+	}
+
+	unbondingDelegationEntryStruct = schema.StructType{
+		Name: "unbonding_delegation_entry",
+		Fields: []schema.Field{
+			{Name: "creation_height", Kind: schema.Int64Kind},
+			{Name: "completion_time", Kind: schema.TimeKind},
+			{Name: "initial_balance", Kind: schema.IntegerStringKind},
+			{Name: "balance", Kind: schema.IntegerStringKind},
+			{Name: "unbonding_id", Kind: schema.Uint64Kind},
+			{Name: "unbonding_on_hold_ref_count", Kind: schema.Int64Kind},
+		},
+	}
+
+	redelegationEntryStruct = schema.StructType{
+		Name: "redelegation_entry",
+		Fields: []schema.Field{
+			{Name: "creation_height", Kind: schema.Int64Kind},
+			{Name: "completion_time", Kind: schema.TimeKind},
+			{Name: "initial_balance", Kind: schema.IntegerStringKind},
+			{Name: "shares_dst", Kind: schema.DecimalStringKind},
+			{Name: "unbonding_id", Kind: schema.Uint64Kind},
+			{Name: "unbonding_on_hold_ref_count", Kind: schema.Int64Kind},
+		},
+	}
+)
+
+func validatorValue(cdc codec.BinaryCodec) collcodec.ValueCodec[types.Validator] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[types.Validator](cdc), collcodec.SchemaCodec[types.Validator]{
+		Fields: []schema.Field{
+			{Name: "operator_address", Kind: schema.StringKind},
+			{Name: "consensus_pubkey", Kind: schema.StructKind, StructType: schemavalue.AnyStruct, Nullable: true},
+			{Name: "jailed", Kind: schema.BoolKind},
+			{Name: "status", Kind: schema.EnumKind, EnumType: BondStatusEnum},
+			{Name: "tokens", Kind: schema.IntegerStringKind},
+			{Name: "delegator_shares", Kind: schema.DecimalStringKind},
+			{Name: "description", Kind: schema.StructKind, StructType: descriptionStruct},
+			{Name: "unbonding_height", Kind: schema.Int64Kind},
+			{Name: "unbonding_time", Kind: schema.TimeKind},
+			{Name: "commission", Kind: schema.StructKind, StructType: commissionStruct},
+			{Name: "min_self_delegation", Kind: schema.IntegerStringKind},
+			{Name: "unbonding_on_hold_ref_count", Kind: schema.Int64Kind},
+			{Name: "unbonding_ids", Kind: schema.ListKind, ElementKind: schema.Uint64Kind},
+		},
+		ToSchemaType: func(v types.Validator) (interface{}, error) {
+			unbondingIDs := make([]interface{}, len(v.UnbondingIds))
+			for i, id := range v.UnbondingIds {
+				unbondingIDs[i] = id
+			}
+			tokens, err := schemavalue.FormatInt(v.Tokens)
+			if err != nil {
+				return nil, err
+			}
+			shares, err := schemavalue.FormatDec(v.DelegatorShares)
+			if err != nil {
+				return nil, err
+			}
+			commission := make([]interface{}, 0, 4)
+			for _, rate := range []math.LegacyDec{v.Commission.Rate, v.Commission.MaxRate, v.Commission.MaxChangeRate} {
+				s, err := schemavalue.FormatDec(rate)
+				if err != nil {
+					return nil, err
+				}
+				commission = append(commission, s)
+			}
+			commission = append(commission, v.Commission.UpdateTime)
+			minSelfDelegation, err := schemavalue.FormatInt(v.MinSelfDelegation)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{
+				v.OperatorAddress,
+				schemavalue.AnyValue(v.ConsensusPubkey),
+				v.Jailed,
+				v.Status.String(),
+				tokens,
+				shares,
+				[]interface{}{v.Description.Moniker, v.Description.Identity, v.Description.Website, v.Description.SecurityContact, v.Description.Details},
+				v.UnbondingHeight,
+				v.UnbondingTime,
+				commission,
+				minSelfDelegation,
+				v.UnbondingOnHoldRefCount,
+				unbondingIDs,
+			}, nil
+		},
+		FromSchemaType: func(value interface{}) (types.Validator, error) {
+			r, err := schemavalue.NewReader(value, 13)
+			if err != nil {
+				return types.Validator{}, err
+			}
+			v := types.Validator{
+				OperatorAddress: r.ReadString(),
+				ConsensusPubkey: r.ReadAny(),
+				Jailed:          r.ReadBool(),
+				Status:          types.BondStatus(r.ReadEnum(BondStatusEnum)),
+				Tokens:          r.ReadInt(),
+				DelegatorShares: r.ReadDec(),
+			}
+			desc := r.ReadStruct(5)
+			v.Description = types.Description{Moniker: desc.ReadString(), Identity: desc.ReadString(), Website: desc.ReadString(), SecurityContact: desc.ReadString(), Details: desc.ReadString()}
+			v.UnbondingHeight = r.ReadInt64()
+			v.UnbondingTime = r.ReadTime()
+			comm := r.ReadStruct(4)
+			v.Commission = types.Commission{
+				CommissionRates: types.CommissionRates{Rate: comm.ReadDec(), MaxRate: comm.ReadDec(), MaxChangeRate: comm.ReadDec()},
+				UpdateTime:      comm.ReadTime(),
+			}
+			v.MinSelfDelegation = r.ReadInt()
+			v.UnbondingOnHoldRefCount = r.ReadInt64()
+			for ids := r.ReadList(); ids.More(); {
+				v.UnbondingIds = append(v.UnbondingIds, ids.ReadUint64())
+			}
+			return v, r.Err()
+		},
+	})
+}
+
+func delegationValue(cdc codec.BinaryCodec) collcodec.ValueCodec[types.Delegation] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[types.Delegation](cdc), collcodec.SchemaCodec[types.Delegation]{
+		Fields: []schema.Field{
+			{Name: "delegator_address", Kind: schema.StringKind},
+			{Name: "validator_address", Kind: schema.StringKind},
+			{Name: "shares", Kind: schema.DecimalStringKind},
+		},
+		ToSchemaType: func(d types.Delegation) (interface{}, error) {
+			shares, err := schemavalue.FormatDec(d.Shares)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{d.DelegatorAddress, d.ValidatorAddress, shares}, nil
+		},
+		FromSchemaType: func(value interface{}) (types.Delegation, error) {
+			r, err := schemavalue.NewReader(value, 3)
+			if err != nil {
+				return types.Delegation{}, err
+			}
+			d := types.Delegation{DelegatorAddress: r.ReadString(), ValidatorAddress: r.ReadString(), Shares: r.ReadDec()}
+			return d, r.Err()
+		},
+	})
+}
+
+func unbondingDelegationValue(cdc codec.BinaryCodec) collcodec.ValueCodec[types.UnbondingDelegation] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[types.UnbondingDelegation](cdc), collcodec.SchemaCodec[types.UnbondingDelegation]{
+		Fields: []schema.Field{
+			{Name: "delegator_address", Kind: schema.StringKind},
+			{Name: "validator_address", Kind: schema.StringKind},
+			{Name: "entries", Kind: schema.ListKind, ElementKind: schema.StructKind, StructType: unbondingDelegationEntryStruct},
+		},
+		ToSchemaType: func(ubd types.UnbondingDelegation) (interface{}, error) {
+			entries := make([]interface{}, len(ubd.Entries))
+			for i, e := range ubd.Entries {
+				initialBalance, err := schemavalue.FormatInt(e.InitialBalance)
+				if err != nil {
+					return nil, err
+				}
+				balance, err := schemavalue.FormatInt(e.Balance)
+				if err != nil {
+					return nil, err
+				}
+				entries[i] = []interface{}{e.CreationHeight, e.CompletionTime, initialBalance, balance, e.UnbondingId, e.UnbondingOnHoldRefCount}
+			}
+			return []interface{}{ubd.DelegatorAddress, ubd.ValidatorAddress, entries}, nil
+		},
+		FromSchemaType: func(value interface{}) (types.UnbondingDelegation, error) {
+			r, err := schemavalue.NewReader(value, 3)
+			if err != nil {
+				return types.UnbondingDelegation{}, err
+			}
+			ubd := types.UnbondingDelegation{DelegatorAddress: r.ReadString(), ValidatorAddress: r.ReadString()}
+			for entries := r.ReadList(); entries.More(); {
+				e := entries.ReadStruct(6)
+				ubd.Entries = append(ubd.Entries, types.UnbondingDelegationEntry{
+					CreationHeight:          e.ReadInt64(),
+					CompletionTime:          e.ReadTime(),
+					InitialBalance:          e.ReadInt(),
+					Balance:                 e.ReadInt(),
+					UnbondingId:             e.ReadUint64(),
+					UnbondingOnHoldRefCount: e.ReadInt64(),
+				})
+			}
+			return ubd, r.Err()
+		},
+	})
+}
+
+func redelegationValue(cdc codec.BinaryCodec) collcodec.ValueCodec[types.Redelegation] {
+	return collcodec.ValueCodecWithSchema(codec.CollValue[types.Redelegation](cdc), collcodec.SchemaCodec[types.Redelegation]{
+		Fields: []schema.Field{
+			{Name: "delegator_address", Kind: schema.StringKind},
+			{Name: "validator_src_address", Kind: schema.StringKind},
+			{Name: "validator_dst_address", Kind: schema.StringKind},
+			{Name: "entries", Kind: schema.ListKind, ElementKind: schema.StructKind, StructType: redelegationEntryStruct},
+		},
+		ToSchemaType: func(red types.Redelegation) (interface{}, error) {
+			entries := make([]interface{}, len(red.Entries))
+			for i, e := range red.Entries {
+				initialBalance, err := schemavalue.FormatInt(e.InitialBalance)
+				if err != nil {
+					return nil, err
+				}
+				sharesDst, err := schemavalue.FormatDec(e.SharesDst)
+				if err != nil {
+					return nil, err
+				}
+				entries[i] = []interface{}{e.CreationHeight, e.CompletionTime, initialBalance, sharesDst, e.UnbondingId, e.UnbondingOnHoldRefCount}
+			}
+			return []interface{}{red.DelegatorAddress, red.ValidatorSrcAddress, red.ValidatorDstAddress, entries}, nil
+		},
+		FromSchemaType: func(value interface{}) (types.Redelegation, error) {
+			r, err := schemavalue.NewReader(value, 4)
+			if err != nil {
+				return types.Redelegation{}, err
+			}
+			red := types.Redelegation{DelegatorAddress: r.ReadString(), ValidatorSrcAddress: r.ReadString(), ValidatorDstAddress: r.ReadString()}
+			for entries := r.ReadList(); entries.More(); {
+				e := entries.ReadStruct(6)
+				red.Entries = append(red.Entries, types.RedelegationEntry{
+					CreationHeight:          e.ReadInt64(),
+					CompletionTime:          e.ReadTime(),
+					InitialBalance:          e.ReadInt(),
+					SharesDst:               e.ReadDec(),
+					UnbondingId:             e.ReadUint64(),
+					UnbondingOnHoldRefCount: e.ReadInt64(),
+				})
+			}
+			return red, r.Err()
+		},
+	})
+}
@@ -0,0 +1,83 @@
+package keeper_test
+
+import (
+	"strings"
+	"time"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/math"
+	"cosmossdk.io/schema"
+	stakingkeeper "cosmossdk.io/x/staking/keeper"
+	"cosmossdk.io/x/staking/testutil"
+	"cosmossdk.io/x/staking/types"
+
+	sdktestutil "github.com/cosmos/cosmos-sdk/testutil"
+)
+
+func (s *KeeperTestSuite) TestModuleCodec() {
+	require := s.Require()
+	stakingKeeper, ctx := s.stakingKeeper, s.ctx
+	delAddrs, valAddrs := createValAddrs(3)
+	valAc, delAc := stakingKeeper.ValidatorAddressCodec(), s.accountKeeper.AddressCodec()
+
+	validator := testutil.NewValidator(s.T(), valAddrs[0], PKs[0])
+	validator, shares := validator.AddTokensFromDel(math.NewInt(100))
+	require.NoError(stakingKeeper.SetValidator(ctx, validator))
+	delegator, err := delAc.BytesToString(delAddrs[0])
+	require.NoError(err)
+	require.NoError(stakingKeeper.SetDelegation(ctx, types.NewDelegation(delegator, validator.OperatorAddress, shares)))
+	completion := time.Unix(100, 0).UTC()
+	require.NoError(stakingKeeper.SetUnbondingDelegation(ctx, types.NewUnbondingDelegation(delAddrs[0], valAddrs[0], 10, completion, math.NewInt(5), 1, valAc, delAc)))
+	require.NoError(stakingKeeper.SetRedelegation(ctx, types.NewRedelegation(delAddrs[0], valAddrs[0], valAddrs[1], 10, completion, math.NewInt(5), math.LegacyNewDec(5), 2, valAc, delAc)))
+
+	cdc, err := stakingKeeper.Schema.ModuleCodec(collections.IndexingOptions{})
+	require.NoError(err)
+
+	// every kv-pair of the module decodes to valid object updates which encode back to the same kv-pair
+	decoded := sdktestutil.DecodeModuleState(s.T(), cdc, stakingKeeper.KVStoreService.OpenKVStore(ctx))
+
+	require.Len(decoded["validators"], 1)
+	require.Equal([]byte(valAddrs[0]), decoded["validators"][0].Key)
+	value := decoded["validators"][0].Value.([]interface{})
+	require.Equal(validator.OperatorAddress, value[0])
+	require.Equal("BOND_STATUS_UNBONDED", value[3])
+	require.Equal("100", value[4])
+
+	require.Len(decoded["delegations"], 1)
+	require.Equal([]interface{}{[]byte(delAddrs[0]), []byte(valAddrs[0])}, decoded["delegations"][0].Key)
+
+	require.Len(decoded["unbonding_delegation"], 1)
+	entries := decoded["unbonding_delegation"][0].Value.([]interface{})[2].([]interface{})
+	require.Equal([]interface{}{int64(10), completion, "5", "5", uint64(1), int64(0)}, entries[0])
+
+	require.Len(decoded["redelegations"], 1)
+	require.Equal([]interface{}{[]byte(delAddrs[0]), []byte(valAddrs[0]), []byte(valAddrs[1])}, decoded["redelegations"][0].Key)
+
+	typ, ok := cdc.Schema.LookupType("bond_status")
+	require.True(ok)
+	require.Equal(stakingkeeper.BondStatusEnum, typ)
+}
+
+func (s *KeeperTestSuite) TestModuleCodecOutOfRangeDecimal() {
+	require := s.Require()
+	stakingKeeper, ctx := s.stakingKeeper, s.ctx
+	delAddrs, valAddrs := createValAddrs(1)
+	delegator, err := s.accountKeeper.AddressCodec().BytesToString(delAddrs[0])
+	require.NoError(err)
+	validator, err := stakingKeeper.ValidatorAddressCodec().BytesToString(valAddrs[0])
+	require.NoError(err)
+
+	// decimal strings have at most 50 digits before the decimal point
+	shares, err := math.LegacyNewDecFromStr("1" + strings.Repeat("0", 50))
+	require.NoError(err)
+	require.NoError(stakingKeeper.SetDelegation(ctx, types.NewDelegation(delegator, validator, shares)))
+
+	cdc, err := stakingKeeper.Schema.ModuleCodec(collections.IndexingOptions{})
+	require.NoError(err)
+
+	iter := ctx.KVStore(s.key).Iterator(nil, nil)
+	defer iter.Close()
+	require.True(iter.Valid())
+	_, err = cdc.KVDecoder(schema.KVPairUpdate{Key: iter.Key(), Value: iter.Value()})
+	require.ErrorContains(err, "can't be represented as a decimal string")
+}
@@ -16,6 +16,7 @@ import (
 	"cosmossdk.io/x/staking/types"
 
 	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/schemavalue"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -157,10 +158,10 @@ func NewKeeper(
 		Delegations: collections.NewMap(
 			sb, types.DelegationKey, "delegations",
 			collections.PairKeyCodec(
-				sdk.LengthPrefixedAddressKey(sdk.AccAddressKey), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
-				sdk.LengthPrefixedAddressKey(sdk.ValAddressKey), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
+				collcodec.NamedKeyCodec(sdk.LengthPrefixedAddressKey(sdk.AccAddressKey), "delegator"), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
+				collcodec.NamedKeyCodec(sdk.LengthPrefixedAddressKey(sdk.ValAddressKey), "validator"), //nolint: staticcheck // sdk.LengthPrefixedAddressKey is needed to retain state compatibility
 			),
-			codec.CollValue[types.Delegation](cdc),
+			delegationValue(cdc),
 		),
 		DelegationsByValidator: collections.NewMap(
 			sb, types.DelegationByValIndexKey,
@@ -181,11 +182,11 @@ func NewKeeper(
 			sb, types.RedelegationKey,
 			"redelegations",
 			collections.TripleKeyCodec(
-				collections.BytesKey,
-				collections.BytesKey,
-				sdk.LengthPrefixedBytesKey, // sdk.LengthPrefixedBytesKey is needed to retain state compatibility
+				schemavalue.AddressBytesKey(collections.BytesKey, "delegator"),
+				schemavalue.AddressBytesKey(collections.BytesKey, "validator_src"),
+				schemavalue.AddressBytesKey(sdk.LengthPrefixedBytesKey, "validator_dst"), // sdk.LengthPrefixedBytesKey is needed to retain state compatibility
 			),
-			codec.CollValue[types.Redelegation](cdc),
+			redelegationValue(cdc),
 		),
 		UnbondingIndex: collections.NewMap(sb, types.UnbondingIndexKey, "unbonding_index", collections.Uint64Key, collections.BytesValue),
 		UnbondingDelegationByValIndex: collections.NewMap(
@@ -220,15 +221,15 @@ func NewKeeper(
 			collections.BytesValue,
 		),
 		RedelegationQueue: collections.NewMap(sb, types.RedelegationQueueKey, "redelegation_queue", sdk.TimeKey, codec.CollValue[types.DVVTriplets](cdc)),
-		Validators:        collections.NewMap(sb, types.ValidatorsKey, "validators", sdk.LengthPrefixedBytesKey, codec.CollValue[types.Validator](cdc)), // sdk.LengthPrefixedBytesKey is needed to retain state compatibility
+		Validators:        collections.NewMap(sb, types.ValidatorsKey, "validators", schemavalue.AddressBytesKey(sdk.LengthPrefixedBytesKey, "operator"), validatorValue(cdc)), // sdk.LengthPrefixedBytesKey is needed to retain state compatibility
 		UnbondingDelegations: collections.NewMap(
 			sb, types.UnbondingDelegationKey,
 			"unbonding_delegation",
 			collections.PairKeyCodec(
-				collections.BytesKey,
-				sdk.LengthPrefixedBytesKey, // sdk.LengthPrefixedBytesKey is needed to retain state compatibility
+				schemavalue.AddressBytesKey(collections.BytesKey, "delegator"),
+				schemavalue.AddressBytesKey(sdk.LengthPrefixedBytesKey, "validator"), // sdk.LengthPrefixedBytesKey is needed to retain state compatibility
 			),
-			codec.CollValue[types.UnbondingDelegation](cdc),
+			unbondingDelegationValue(cdc),
 		),
 		// key format is: 67 | length(timestamp Bytes) | timestamp | height
 		// Note: We use 3 keys here because we prefixed time bytes with its length previously and to retain state compatibility we remain to use the same
@@ -78,6 +78,21 @@ func (k Keeper) PreBlocker(ctx context.Context) error {
 
 		// Prepare shutdown if we don't have an upgrade handler for this upgrade name (meaning this software is out of date)
 		if !k.HasHandler(plan.Name) {
+			// If the next-version module schemas can't be migrated to, either halt without writing the upgrade info
+			// to disk, so that the binary is not switched, or only log the failure, which is the default since a
+			// node-local indexer problem must not keep the node from upgrading
+			if err := k.CheckUpgradeSchemas(ctx, plan); err != nil {
+				if !k.schemaChecker.halt {
+					k.Logger.Error(fmt.Sprintf("UPGRADE \"%s\" schema check failed at %s: %v", plan.Name, plan.DueAt(), err))
+				} else {
+					haltMsg := fmt.Sprintf("UPGRADE \"%s\" HALTED at %s: %v", plan.Name, plan.DueAt(), err)
+					k.Logger.Error(haltMsg)
+
+					// Returning an error will end up in a panic
+					return errors.New(haltMsg)
+				}
+			}
+
 			// Write the upgrade info to disk. The UpgradeStoreLoader uses this info to perform or skip
 			// store migrations.
 			err := k.DumpUpgradeInfoToDisk(blockHeight, plan)
@@ -109,6 +124,18 @@ func (k Keeper) PreBlocker(ctx context.Context) error {
 		// Returning an error will end up in a panic
 		return errors.New(downgradeMsg)
 	}
+
+	// check the next-version module schemas once ahead of the upgrade height, so that operators have time to
+	// fix the indexers which can't migrate before the upgrade
+	if _, checked := k.schemaChecks[plan.Name]; !checked {
+		err := k.CheckUpgradeSchemas(ctx, plan)
+		k.schemaChecks[plan.Name] = err
+		if err != nil && k.schemaChecker.halt {
+			k.Logger.Error(fmt.Sprintf("UPGRADE \"%s\" WILL BE HALTED at %s unless the schema check passes: %v", plan.Name, plan.DueAt(), err))
+		} else if err != nil {
+			k.Logger.Error(fmt.Sprintf("UPGRADE \"%s\" schema check failed ahead of %s: %v", plan.Name, plan.DueAt(), err))
+		}
+	}
 	return nil
 }
 
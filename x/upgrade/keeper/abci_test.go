@@ -13,6 +13,9 @@ import (
 	"cosmossdk.io/core/appmodule"
 	"cosmossdk.io/core/header"
 	coretesting "cosmossdk.io/core/testing"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/decoding"
+	"cosmossdk.io/schema/indexer"
 	storetypes "cosmossdk.io/store/types"
 	authtypes "cosmossdk.io/x/auth/types"
 	"cosmossdk.io/x/upgrade"
@@ -24,6 +27,9 @@ import (
 	codectestutil "github.com/cosmos/cosmos-sdk/codec/testutil"
 	"github.com/cosmos/cosmos-sdk/runtime"
 	"github.com/cosmos/cosmos-sdk/testutil"
+	"github.com/cosmos/cosmos-sdk/testutil/x/counter"
+	counterkeeper "github.com/cosmos/cosmos-sdk/testutil/x/counter/keeper"
+	countertypes "github.com/cosmos/cosmos-sdk/testutil/x/counter/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	moduletestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
@@ -369,6 +375,125 @@ func TestUpgradeWithoutSkip(t *testing.T) {
 	s.VerifyDone(t, s.ctx, "test")
 }
 
+type schemaUpgradeChecker struct {
+	checked []map[string]schema.ModuleSchema
+	err     error
+}
+
+func (c *schemaUpgradeChecker) CheckSchemaUpgrade(_ string, next map[string]schema.ModuleSchema) error {
+	c.checked = append(c.checked, next)
+	return c.err
+}
+
+func TestSchemaUpgradeCheck(t *testing.T) {
+	s := setupTest(t, 10, map[int64]bool{})
+	err := s.keeper.ScheduleUpgrade(s.ctx, types.Plan{Name: "test", Height: s.ctx.HeaderInfo().Height + 2})
+	require.NoError(t, err)
+
+	bankSchema, err := schema.NewModuleSchema([]schema.ObjectType{{
+		Name:      "balances",
+		KeyFields: []schema.Field{{Name: "denom", Kind: schema.StringKind}},
+	}})
+	require.NoError(t, err)
+
+	checker := &schemaUpgradeChecker{err: errors.New("target \"postgres\" can't migrate module bank")}
+	s.keeper.SetSchemaUpgradeChecker(checker, true)
+	s.keeper.SetUpgradeSchemaHandler("test", func(ctx context.Context, plan types.Plan) (map[string]schema.ModuleSchema, error) {
+		return map[string]schema.ModuleSchema{"bank": bankSchema}, nil
+	})
+
+	t.Log("Verify that the schemas are checked once ahead of the upgrade height without halting")
+	aheadCtx := s.ctx.WithHeaderInfo(header.Info{Height: s.ctx.HeaderInfo().Height + 1, Time: time.Now()})
+	require.NoError(t, s.preModule.PreBlock(aheadCtx))
+	require.NoError(t, s.preModule.PreBlock(aheadCtx))
+	require.Len(t, checker.checked, 1)
+	require.Equal(t, map[string]schema.ModuleSchema{"bank": bankSchema}, checker.checked[0])
+
+	t.Log("Verify that the upgrade is halted without writing the upgrade info if the check fails")
+	upgradeCtx := s.ctx.WithHeaderInfo(header.Info{Height: s.ctx.HeaderInfo().Height + 2, Time: time.Now()})
+	err = s.preModule.PreBlock(upgradeCtx)
+	require.ErrorContains(t, err, "UPGRADE \"test\" HALTED at height: 12: target \"postgres\" can't migrate module bank")
+	upgradeInfo, err := s.keeper.ReadUpgradeInfoFromDisk()
+	require.NoError(t, err)
+	require.Empty(t, upgradeInfo.Name)
+
+	t.Log("Verify that the upgrade proceeds once the check passes")
+	checker.err = nil
+	err = s.preModule.PreBlock(upgradeCtx)
+	require.ErrorContains(t, err, "UPGRADE \"test\" NEEDED at height: 12: ")
+	upgradeInfo, err = s.keeper.ReadUpgradeInfoFromDisk()
+	require.NoError(t, err)
+	require.Equal(t, "test", upgradeInfo.Name)
+}
+
+func TestSchemaUpgradeCheckWithoutHalt(t *testing.T) {
+	s := setupTest(t, 10, map[int64]bool{})
+	err := s.keeper.ScheduleUpgrade(s.ctx, types.Plan{Name: "test", Height: s.ctx.HeaderInfo().Height + 1})
+	require.NoError(t, err)
+
+	checker := &schemaUpgradeChecker{err: errors.New("target \"postgres\" can't migrate module bank")}
+	s.keeper.SetSchemaUpgradeChecker(checker, false)
+	s.keeper.SetUpgradeSchemaHandler("test", func(ctx context.Context, plan types.Plan) (map[string]schema.ModuleSchema, error) {
+		return map[string]schema.ModuleSchema{}, nil
+	})
+
+	t.Log("Verify that a failing check is only logged and the upgrade info is written by default")
+	upgradeCtx := s.ctx.WithHeaderInfo(header.Info{Height: s.ctx.HeaderInfo().Height + 1, Time: time.Now()})
+	err = s.preModule.PreBlock(upgradeCtx)
+	require.ErrorContains(t, err, "UPGRADE \"test\" NEEDED at height: 11: ")
+	require.Len(t, checker.checked, 1)
+	upgradeInfo, err := s.keeper.ReadUpgradeInfoFromDisk()
+	require.NoError(t, err)
+	require.Equal(t, "test", upgradeInfo.Name)
+}
+
+func TestSchemaUpgradeCheckWithIndexer(t *testing.T) {
+	s := setupTest(t, 10, map[int64]bool{})
+	plan := types.Plan{Name: countertypes.CountUpgradeName, Height: s.ctx.HeaderInfo().Height + 1}
+	require.NoError(t, s.keeper.ScheduleUpgrade(s.ctx, plan))
+
+	// the counter module presents the schema it will have after the upgrade, in which its count becomes unsigned
+	counterEnv := runtime.NewEnvironment(runtime.NewKVStoreService(storetypes.NewKVStoreKey(countertypes.StoreKey)), coretesting.NewNopLogger())
+	modules := map[string]interface{}{countertypes.ModuleName: counter.NewAppModule(counterkeeper.NewKeeper(counterEnv))}
+
+	var migrations []indexer.SchemaMigration
+	indexer.Register("upgrade_keeper_test", func(indexer.InitParams) (indexer.InitResult, error) {
+		return indexer.InitResult{CheckMigration: func(migration indexer.SchemaMigration) error {
+			migrations = append(migrations, migration)
+			return errors.New("can't change the type of column value")
+		}}, nil
+	})
+	manager, err := indexer.StartManager(indexer.ManagerOptions{
+		Config:   indexer.ManagerConfig{Target: map[string]indexer.Config{"postgres": {Type: "upgrade_keeper_test"}}},
+		Resolver: decoding.ModuleSetDecoderResolver(modules),
+	})
+	require.NoError(t, err)
+
+	// this binary has no handler for the upgrade, so the schemas are checked before halting for the binary switch
+	require.False(t, s.keeper.HasHandler(plan.Name))
+	s.keeper.SetUpgradeSchemaHandler(plan.Name, types.ModuleUpgradeSchemas(modules))
+	s.keeper.SetSchemaUpgradeChecker(manager, true)
+
+	upgradeCtx := s.ctx.WithHeaderInfo(header.Info{Height: plan.Height, Time: time.Now()})
+	err = s.preModule.PreBlock(upgradeCtx)
+	require.ErrorContains(t, err, "UPGRADE \"v051-to-v052\" HALTED at height: 11")
+	require.ErrorContains(t, err, "target \"postgres\" can't migrate module counter: can't change the type of column value")
+	upgradeInfo, err := s.keeper.ReadUpgradeInfoFromDisk()
+	require.NoError(t, err)
+	require.Empty(t, upgradeInfo.Name)
+
+	require.Len(t, migrations, 1)
+	require.Equal(t, countertypes.ModuleName, migrations[0].ModuleName)
+	countType, ok := migrations[0].To.LookupType("count")
+	require.True(t, ok)
+	require.Equal(t, schema.Uint64Kind, countType.(schema.ObjectType).ValueFields[0].Kind)
+
+	// the counter module's schema doesn't change in other upgrades, so they are not checked
+	schemas, err := types.ModuleUpgradeSchemas(modules)(s.ctx, types.Plan{Name: "other"})
+	require.NoError(t, err)
+	require.Empty(t, schemas)
+}
+
 func TestDumpUpgradeInfoToFile(t *testing.T) {
 	s := setupTest(t, 10, map[int64]bool{})
 	require := require.New(t)
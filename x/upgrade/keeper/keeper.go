@@ -30,14 +30,24 @@ import (
 type Keeper struct {
 	appmodule.Environment
 
-	homePath           string                          // root directory of app config
-	skipUpgradeHeights map[int64]bool                  // map of heights to skip for an upgrade
-	cdc                codec.BinaryCodec               // App-wide binary codec
-	upgradeHandlers    map[string]types.UpgradeHandler // map of plan name to upgrade handler
-	versionModifier    app.VersionModifier             // implements setting the protocol version field on BaseApp
-	downgradeVerified  bool                            // tells if we've already sanity checked that this binary version isn't being used against an old state.
-	authority          string                          // the address capable of executing and canceling an upgrade. Usually the gov module account
-	initVersionMap     appmodule.VersionMap            // the module version map at init genesis
+	homePath           string                                // root directory of app config
+	skipUpgradeHeights map[int64]bool                        // map of heights to skip for an upgrade
+	cdc                codec.BinaryCodec                     // App-wide binary codec
+	upgradeHandlers    map[string]types.UpgradeHandler       // map of plan name to upgrade handler
+	versionModifier    app.VersionModifier                   // implements setting the protocol version field on BaseApp
+	downgradeVerified  bool                                  // tells if we've already sanity checked that this binary version isn't being used against an old state.
+	authority          string                                // the address capable of executing and canceling an upgrade. Usually the gov module account
+	initVersionMap     appmodule.VersionMap                  // the module version map at init genesis
+	schemaHandlers     map[string]types.UpgradeSchemaHandler // map of plan name to the handler presenting the next-version module schemas
+	schemaChecker      *schemaChecker                        // validates the next-version module schemas, usually with the indexer manager
+	schemaChecks       map[string]error                      // map of plan name to the result of the schema check ahead of the upgrade height
+}
+
+// schemaChecker holds the checker set with SetSchemaUpgradeChecker. It is shared by pointer so that, like the
+// upgrade handlers, it can be set on a copy of the keeper.
+type schemaChecker struct {
+	checker types.SchemaUpgradeChecker
+	halt    bool // halt at the upgrade height if the schema check fails instead of only logging it
 }
 
 // NewKeeper constructs an upgrade Keeper which requires the following arguments:
@@ -60,6 +70,9 @@ func NewKeeper(
 		skipUpgradeHeights: skipUpgradeHeights,
 		cdc:                cdc,
 		upgradeHandlers:    map[string]types.UpgradeHandler{},
+		schemaHandlers:     map[string]types.UpgradeSchemaHandler{},
+		schemaChecker:      &schemaChecker{},
+		schemaChecks:       map[string]error{},
 		versionModifier:    vs,
 		authority:          authority,
 	}
@@ -90,6 +103,39 @@ func (k Keeper) SetUpgradeHandler(name string, upgradeHandler types.UpgradeHandl
 	k.upgradeHandlers[name] = upgradeHandler
 }
 
+// SetUpgradeSchemaHandler sets the UpgradeSchemaHandler which presents the next-version module schemas of the
+// upgrade specified by name. It is set in the binary which runs before the upgrade, so that the schema checker
+// set with SetSchemaUpgradeChecker can validate the schemas ahead of the upgrade height. The schemas are only
+// checked while no upgrade handler is set for name, because a binary with the handler applies the upgrade.
+func (k Keeper) SetUpgradeSchemaHandler(name string, handler types.UpgradeSchemaHandler) {
+	k.schemaHandlers[name] = handler
+}
+
+// SetSchemaUpgradeChecker sets the checker, usually the indexer manager, which validates the next-version module
+// schemas of scheduled upgrades. The schemas are checked at the first block after an upgrade is scheduled and again
+// at the upgrade height, and failures are logged. If halt is true, a failure at the upgrade height also halts the
+// node without writing the upgrade info to disk, so that the binary is not switched while an indexer can't migrate
+// its data. Halting is opt-in because it lets a node-local indexer problem keep a validator from upgrading.
+func (k Keeper) SetSchemaUpgradeChecker(checker types.SchemaUpgradeChecker, halt bool) {
+	k.schemaChecker.checker = checker
+	k.schemaChecker.halt = halt
+}
+
+// CheckUpgradeSchemas validates the next-version module schemas of plan with the schema checker. It returns nil
+// if no checker or no UpgradeSchemaHandler for plan is set.
+func (k Keeper) CheckUpgradeSchemas(ctx context.Context, plan types.Plan) error {
+	handler, ok := k.schemaHandlers[plan.Name]
+	if !ok || k.schemaChecker.checker == nil {
+		return nil
+	}
+
+	schemas, err := handler(ctx, plan)
+	if err != nil {
+		return fmt.Errorf("failed to get the module schemas of upgrade %q: %w", plan.Name, err)
+	}
+	return k.schemaChecker.checker.CheckSchemaUpgrade(plan.Name, schemas)
+}
+
 // SetModuleVersionMap saves a given version map to state
 func (k Keeper) SetModuleVersionMap(ctx context.Context, vm appmodule.VersionMap) error {
 	if len(vm) > 0 {
@@ -2,8 +2,10 @@ package types
 
 import (
 	"context"
+	"fmt"
 
 	"cosmossdk.io/core/appmodule"
+	"cosmossdk.io/schema"
 )
 
 // UpgradeHandler specifies the type of function that is called when an upgrade
@@ -25,3 +27,48 @@ import (
 //
 // Please also refer to docs/core/upgrade.md for more information.
 type UpgradeHandler func(ctx context.Context, plan Plan, fromVM appmodule.VersionMap) (appmodule.VersionMap, error)
+
+// UpgradeSchemaHandler returns the module schemas, keyed by module name, which the modules will have after the
+// upgrade specified by plan. It is called ahead of the upgrade height, by the binary which runs before the
+// upgrade, so that the next-version schemas can be validated against the attached indexers. Modules whose
+// schema doesn't change can be omitted.
+type UpgradeSchemaHandler func(ctx context.Context, plan Plan) (map[string]schema.ModuleSchema, error)
+
+// HasUpgradeSchema is implemented by modules which present the schema they will have after an upgrade, so
+// that the binary which runs before the upgrade, and therefore has no upgrade handler for it, can check the
+// schema ahead of the upgrade height. It only depends on the plan name so that modules can implement it
+// without importing this package.
+type HasUpgradeSchema interface {
+	// UpgradeSchema returns the module schema after the upgrade planName. It returns false if the module's
+	// schema doesn't change in the upgrade.
+	UpgradeSchema(planName string) (schema.ModuleSchema, bool, error)
+}
+
+// ModuleUpgradeSchemas returns an UpgradeSchemaHandler which collects the next-version schemas of the modules
+// in modules which implement HasUpgradeSchema.
+func ModuleUpgradeSchemas(modules map[string]interface{}) UpgradeSchemaHandler {
+	return func(_ context.Context, plan Plan) (map[string]schema.ModuleSchema, error) {
+		res := map[string]schema.ModuleSchema{}
+		for moduleName, mod := range modules {
+			hasSchema, ok := mod.(HasUpgradeSchema)
+			if !ok {
+				continue
+			}
+			moduleSchema, changed, err := hasSchema.UpgradeSchema(plan.Name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the upgrade schema of module %s: %w", moduleName, err)
+			}
+			if changed {
+				res[moduleName] = moduleSchema
+			}
+		}
+		return res, nil
+	}
+}
+
+// SchemaUpgradeChecker validates that the next-version module schemas of an upgrade plan can be migrated to.
+// It is implemented by the indexer manager of cosmossdk.io/schema/indexer, which returns an error if one of
+// its targets can't migrate its data.
+type SchemaUpgradeChecker interface {
+	CheckSchemaUpgrade(planName string, next map[string]schema.ModuleSchema) error
+}